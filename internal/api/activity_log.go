@@ -0,0 +1,311 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/stashapp/stash/internal/manager"
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/session"
+	"github.com/stashapp/stash/pkg/txn"
+)
+
+const (
+	activityAuthAPIKey   = "api_key"
+	activityAuthSession  = "session"
+	activityAuthNone     = "none"
+	activityAuthInternal = "internal"
+
+	activityActionStream = "stream"
+
+	// maximum length of the recorded mutation arguments
+	activityMaxDetailsLength = 2048
+	// repeated streams of the same scene by the same client within this
+	// window are recorded once
+	activityStreamWindow = 30 * time.Minute
+	// minimum interval between pruning old entries
+	activityPruneInterval = time.Hour
+
+	activityRedacted = "[redacted]"
+)
+
+// activityIgnoredMutations are mutations which are too frequent to be useful
+// in the activity log.
+var activityIgnoredMutations = map[string]bool{
+	"playbackEventsCreate": true,
+}
+
+// activityActor identifies the client performing a request.
+type activityActor struct {
+	AuthMethod string
+	Credential string
+	RemoteAddr string
+}
+
+// newActivityActor returns the actor of the provided authenticated request.
+func newActivityActor(r *http.Request, userID string) activityActor {
+	ret := activityActor{
+		AuthMethod: activityAuthNone,
+		RemoteAddr: r.RemoteAddr,
+	}
+
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		ret.RemoteAddr = host
+	}
+
+	switch apiKey := session.GetRequestAPIKey(r); {
+	case apiKey != "":
+		ret.AuthMethod = activityAuthAPIKey
+		ret.Credential = apiKeyFingerprint(apiKey)
+	case userID != "":
+		ret.AuthMethod = activityAuthSession
+		ret.Credential = userID
+	}
+
+	return ret
+}
+
+// apiKeyFingerprint returns a short identifier of the API key that can be
+// stored without exposing the key itself.
+func apiKeyFingerprint(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:4])
+}
+
+func setActivityActor(ctx context.Context, actor activityActor) context.Context {
+	return context.WithValue(ctx, activityActorKey, actor)
+}
+
+// getActivityActor returns the actor stored in the context. Requests that do
+// not pass through the authentication handler, such as those made by plugins,
+// are attributed to an internal actor.
+func getActivityActor(ctx context.Context) activityActor {
+	if actor, ok := ctx.Value(activityActorKey).(activityActor); ok {
+		return actor
+	}
+
+	return activityActor{AuthMethod: activityAuthInternal}
+}
+
+type activityRecorder struct {
+	txnManager txn.Manager
+	repository models.ActivityLogWriter
+
+	mutex      sync.Mutex
+	lastStream map[string]time.Time
+	lastPrune  time.Time
+}
+
+func newActivityRecorder(repo manager.Repository) *activityRecorder {
+	return &activityRecorder{
+		txnManager: repo,
+		repository: repo.ActivityLog,
+		lastStream: make(map[string]time.Time),
+	}
+}
+
+func (a *activityRecorder) record(ctx context.Context, entry models.ActivityLogEntry) {
+	c := config.GetInstance()
+	if !c.GetActivityLogEnabled() {
+		return
+	}
+
+	actor := getActivityActor(ctx)
+	entry.CreatedAt = time.Now()
+	entry.AuthMethod = actor.AuthMethod
+	entry.Credential = actor.Credential
+	entry.RemoteAddr = actor.RemoteAddr
+
+	var pruneBefore *time.Time
+	if days := c.GetActivityLogRetentionDays(); days > 0 && a.shouldPrune(entry.CreatedAt) {
+		t := entry.CreatedAt.AddDate(0, 0, -days)
+		pruneBefore = &t
+	}
+
+	// use a context detached from the request so that the entry is recorded
+	// even if the client disconnects
+	if err := txn.WithTxn(context.Background(), a.txnManager, func(ctx context.Context) error {
+		if err := a.repository.Create(ctx, entry); err != nil {
+			return err
+		}
+
+		if pruneBefore != nil {
+			return a.repository.DestroyOlderThan(ctx, *pruneBefore)
+		}
+
+		return nil
+	}); err != nil {
+		logger.Errorf("Error recording activity %s: %v", entry.Action, err)
+	}
+}
+
+func (a *activityRecorder) shouldPrune(now time.Time) bool {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	if now.Sub(a.lastPrune) < activityPruneInterval {
+		return false
+	}
+
+	a.lastPrune = now
+	return true
+}
+
+// shouldRecordStream returns false if the stream was already recorded within
+// activityStreamWindow. Players make many range requests for a single
+// playback, which should only be recorded once.
+func (a *activityRecorder) shouldRecordStream(key string, now time.Time) bool {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	for k, t := range a.lastStream {
+		if now.Sub(t) >= activityStreamWindow {
+			delete(a.lastStream, k)
+		}
+	}
+
+	if _, found := a.lastStream[key]; found {
+		return false
+	}
+
+	a.lastStream[key] = now
+	return true
+}
+
+// StreamMiddleware records streams of the scene in the request context.
+func (a *activityRecorder) StreamMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if s, ok := ctx.Value(sceneKey).(*models.Scene); ok && config.GetInstance().GetActivityLogEnabled() {
+			actor := getActivityActor(ctx)
+			sceneID := strconv.Itoa(s.ID)
+			key := strings.Join([]string{actor.AuthMethod, actor.Credential, actor.RemoteAddr, sceneID}, "|")
+
+			if a.shouldRecordStream(key, time.Now()) {
+				targetType := "scene"
+				a.record(ctx, models.ActivityLogEntry{
+					Action:     activityActionStream,
+					TargetType: &targetType,
+					TargetID:   &sceneID,
+				})
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// FieldMiddleware records successful mutations.
+func (a *activityRecorder) FieldMiddleware(ctx context.Context, next graphql.Resolver) (interface{}, error) {
+	res, err := next(ctx)
+
+	fc := graphql.GetFieldContext(ctx)
+	if err != nil || fc == nil || fc.Object != "Mutation" || activityIgnoredMutations[fc.Field.Name] {
+		return res, err
+	}
+
+	entry := models.ActivityLogEntry{
+		Action: fc.Field.Name,
+	}
+
+	args := redactActivityArgs(fc.Args)
+	if id := activityTargetID(args); id != "" {
+		entry.TargetID = &id
+	}
+
+	if len(args) > 0 {
+		if details, err := json.Marshal(args); err == nil {
+			s := string(details)
+			if len(s) > activityMaxDetailsLength {
+				s = s[:activityMaxDetailsLength]
+			}
+			entry.Details = &s
+		}
+	}
+
+	a.record(ctx, entry)
+
+	return res, err
+}
+
+// redactActivityArgs returns a JSON-compatible copy of the provided mutation
+// arguments with sensitive values such as passwords and API keys replaced.
+func redactActivityArgs(args map[string]interface{}) map[string]interface{} {
+	if len(args) == 0 {
+		return nil
+	}
+
+	// round-trip through JSON to convert input structs into maps
+	data, err := json.Marshal(args)
+	if err != nil {
+		return nil
+	}
+
+	var ret map[string]interface{}
+	if err := json.Unmarshal(data, &ret); err != nil {
+		return nil
+	}
+
+	redactActivityValue(ret)
+	return ret
+}
+
+func redactActivityValue(v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, vv := range v {
+			if isSensitiveActivityKey(k) {
+				if vv != nil {
+					v[k] = activityRedacted
+				}
+				continue
+			}
+			redactActivityValue(vv)
+		}
+	case []interface{}:
+		for _, vv := range v {
+			redactActivityValue(vv)
+		}
+	}
+}
+
+func isSensitiveActivityKey(k string) bool {
+	k = strings.ToLower(strings.ReplaceAll(k, "_", ""))
+	// PINs are matched by suffix to avoid redacting keys such as pinned
+	return strings.Contains(k, "password") || strings.Contains(k, "apikey") || strings.Contains(k, "secret") || strings.Contains(k, "token") || strings.HasSuffix(k, "pin")
+}
+
+// activityTargetID returns the ID of the object targeted by a mutation, taken
+// from the id argument or the id field of the input argument.
+func activityTargetID(args map[string]interface{}) string {
+	toString := func(v interface{}) string {
+		switch v := v.(type) {
+		case string:
+			return v
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64)
+		}
+		return ""
+	}
+
+	if id := toString(args["id"]); id != "" {
+		return id
+	}
+
+	if input, ok := args["input"].(map[string]interface{}); ok {
+		return toString(input["id"])
+	}
+
+	return ""
+}
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactActivityArgs(t *testing.T) {
+	type input struct {
+		ID       string  `json:"id"`
+		Username *string `json:"username"`
+		Password *string `json:"password"`
+	}
+
+	username := "admin"
+	password := "hunter2"
+
+	tests := []struct {
+		name string
+		args map[string]interface{}
+		want map[string]interface{}
+	}{
+		{"nil", nil, nil},
+		{
+			"id",
+			map[string]interface{}{"id": "1"},
+			map[string]interface{}{"id": "1"},
+		},
+		{
+			"struct input",
+			map[string]interface{}{"input": input{ID: "2", Username: &username, Password: &password}},
+			map[string]interface{}{"input": map[string]interface{}{"id": "2", "username": "admin", "password": activityRedacted}},
+		},
+		{
+			"nested",
+			map[string]interface{}{"input": map[string]interface{}{"stash_boxes": []interface{}{map[string]interface{}{"api_key": "abc"}}}},
+			map[string]interface{}{"input": map[string]interface{}{"stash_boxes": []interface{}{map[string]interface{}{"api_key": activityRedacted}}}},
+		},
+		{
+			"null sensitive value",
+			map[string]interface{}{"input": input{ID: "3"}},
+			map[string]interface{}{"input": map[string]interface{}{"id": "3", "username": nil, "password": nil}},
+		},
+		{
+			"pinned not redacted",
+			map[string]interface{}{"input": map[string]interface{}{"pinned": true}},
+			map[string]interface{}{"input": map[string]interface{}{"pinned": true}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, redactActivityArgs(tt.args))
+		})
+	}
+}
+
+func TestRedactActivityArgsPin(t *testing.T) {
+	pin := "1234"
+	name := "kids"
+
+	tests := []struct {
+		name  string
+		input interface{}
+		key   string
+	}{
+		{"configure general", ConfigGeneralInput{SessionLockPin: &pin}, "sessionLockPin"},
+		{"restriction profile create", RestrictionProfileCreateInput{Name: name, Pin: &pin}, "pin"},
+		{"restriction profile update", RestrictionProfileUpdateInput{ID: "1", Name: &name, Pin: &pin}, "pin"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := redactActivityArgs(map[string]interface{}{"input": tt.input})
+			input, _ := got["input"].(map[string]interface{})
+			assert.Equal(t, activityRedacted, input[tt.key])
+		})
+	}
+}
+
+func TestActivityTargetID(t *testing.T) {
+	tests := []struct {
+		name string
+		args map[string]interface{}
+		want string
+	}{
+		{"none", map[string]interface{}{"ids": []interface{}{"1"}}, ""},
+		{"id", map[string]interface{}{"id": "1"}, "1"},
+		{"numeric id", map[string]interface{}{"id": float64(12)}, "12"},
+		{"input id", map[string]interface{}{"input": map[string]interface{}{"id": "3"}}, "3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, activityTargetID(tt.args))
+		})
+	}
+}
+
+func TestNewActivityActor(t *testing.T) {
+	r := httptest.NewRequest("GET", "/graphql", nil)
+	r.RemoteAddr = "192.168.1.2:1234"
+
+	assert.Equal(t, activityActor{AuthMethod: activityAuthNone, RemoteAddr: "192.168.1.2"}, newActivityActor(r, ""))
+	assert.Equal(t, activityActor{AuthMethod: activityAuthSession, Credential: "admin", RemoteAddr: "192.168.1.2"}, newActivityActor(r, "admin"))
+
+	r.Header.Set("ApiKey", "key")
+	actor := newActivityActor(r, "admin")
+	assert.Equal(t, activityAuthAPIKey, actor.AuthMethod)
+	assert.Equal(t, apiKeyFingerprint("key"), actor.Credential)
+	assert.NotContains(t, actor.Credential, "key")
+}
+
+func TestShouldRecordStream(t *testing.T) {
+	a := &activityRecorder{lastStream: make(map[string]time.Time)}
+	now := time.Now()
+
+	assert.True(t, a.shouldRecordStream("a", now))
+	assert.False(t, a.shouldRecordStream("a", now.Add(time.Minute)))
+	assert.True(t, a.shouldRecordStream("b", now.Add(time.Minute)))
+	assert.True(t, a.shouldRecordStream("a", now.Add(activityStreamWindow)))
+}
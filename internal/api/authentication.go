@@ -10,6 +10,7 @@ import (
 	"github.com/stashapp/stash/internal/manager"
 	"github.com/stashapp/stash/internal/manager/config"
 	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/session"
 )
 
@@ -71,6 +72,17 @@ func authenticateHandler() func(http.Handler) http.Handler {
 
 			ctx := r.Context()
 
+			role, err := manager.GetInstance().SessionStore.GetUserRole(ctx, userID)
+			if err != nil {
+				if !errors.Is(err, session.ErrUnauthorized) {
+					logger.Errorf("Error getting role of user %s: %v", userID, err)
+				}
+
+				// treat sessions of deleted users as unauthenticated
+				userID = ""
+				role = models.UserRoleReadOnly
+			}
+
 			if c.HasCredentials() {
 				// authentication is required
 				if userID == "" && !allowUnauthenticated(r) {
@@ -82,25 +94,15 @@ func authenticateHandler() func(http.Handler) http.Handler {
 						return
 					}
 
-					prefix := getProxyPrefix(r.Header)
-
 					// otherwise redirect to the login page
-					returnURL := url.URL{
-						Path:     prefix + r.URL.Path,
-						RawQuery: r.URL.RawQuery,
-					}
-					q := make(url.Values)
-					q.Set(returnURLParam, returnURL.String())
-					u := url.URL{
-						Path:     prefix + "/login",
-						RawQuery: q.Encode(),
-					}
-					http.Redirect(w, r, u.String(), http.StatusFound)
+					redirectToLoginPage(w, r)
 					return
 				}
 			}
 
 			ctx = session.SetCurrentUserID(ctx, userID)
+			ctx = session.SetCurrentUserRole(ctx, role)
+			ctx = setActivityActor(ctx, newActivityActor(r, userID))
 
 			r = r.WithContext(ctx)
 
@@ -109,6 +111,40 @@ func authenticateHandler() func(http.Handler) http.Handler {
 	}
 }
 
+// redirectToLoginPage redirects to the login page, returning to the
+// requested URL after logging in.
+func redirectToLoginPage(w http.ResponseWriter, r *http.Request) {
+	prefix := getProxyPrefix(r.Header)
+
+	returnURL := url.URL{
+		Path:     prefix + r.URL.Path,
+		RawQuery: r.URL.RawQuery,
+	}
+	q := make(url.Values)
+	q.Set(returnURLParam, returnURL.String())
+	u := url.URL{
+		Path:     prefix + loginEndPoint,
+		RawQuery: q.Encode(),
+	}
+	http.Redirect(w, r, u.String(), http.StatusFound)
+}
+
+// mediaAccessHandler restricts access to streaming and download endpoints
+// according to the media access configuration.
+func mediaAccessHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := session.CheckMediaAccess(config.GetInstance(), r); err != nil {
+			if !errors.Is(err, session.ErrMediaAccessDenied) {
+				logger.Errorf("Error checking media access: %v", err)
+			}
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
 func checkSecurityTripwireActivated(c *config.Instance, w http.ResponseWriter) bool {
 	if accessErr := session.CheckExternalAccessTripwire(c); accessErr != nil {
 		w.WriteHeader(http.StatusForbidden)
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/internal/manager"
+	"github.com/stashapp/stash/pkg/performer"
+	"github.com/stashapp/stash/pkg/scene"
+)
+
+// autoBackup backs up the data affected by a destructive bulk operation
+// before it is run. The rows returned by rows are only backed up if the
+// database is too large to be backed up in full.
+func autoBackup(ctx context.Context, operation string, rows manager.AutoBackupRows) error {
+	_, err := manager.GetInstance().AutoBackup(ctx, operation, rows)
+	return err
+}
+
+func (r *mutationResolver) sceneBackupRows(ids []int) manager.AutoBackupRows {
+	return func(ctx context.Context) (interface{}, error) {
+		scenes, err := r.repository.Scene.FindMany(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+
+		var ret []*scene.Snapshot
+		for _, s := range scenes {
+			snapshot, err := scene.TakeSnapshot(ctx, r.repository.Scene, s)
+			if err != nil {
+				return nil, err
+			}
+			if err := snapshot.LoadMarkers(ctx, r.repository.SceneMarker); err != nil {
+				return nil, err
+			}
+			ret = append(ret, snapshot)
+		}
+
+		return ret, nil
+	}
+}
+
+func (r *mutationResolver) performerBackupRows(ids []int) manager.AutoBackupRows {
+	return func(ctx context.Context) (interface{}, error) {
+		performers, err := r.repository.Performer.FindMany(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+
+		var ret []*performer.Snapshot
+		for _, p := range performers {
+			snapshot, err := performer.TakeSnapshot(ctx, r.repository.Performer, p)
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, snapshot)
+		}
+
+		return ret, nil
+	}
+}
+
+func (r *mutationResolver) imageBackupRows(ids []int) manager.AutoBackupRows {
+	return func(ctx context.Context) (interface{}, error) {
+		return r.repository.Image.FindMany(ctx, ids)
+	}
+}
+
+func (r *mutationResolver) galleryBackupRows(ids []int) manager.AutoBackupRows {
+	return func(ctx context.Context) (interface{}, error) {
+		return r.repository.Gallery.FindMany(ctx, ids)
+	}
+}
+
+func (r *mutationResolver) studioBackupRows(ids []int) manager.AutoBackupRows {
+	return func(ctx context.Context) (interface{}, error) {
+		return r.repository.Studio.FindMany(ctx, ids)
+	}
+}
+
+func (r *mutationResolver) movieBackupRows(ids []int) manager.AutoBackupRows {
+	return func(ctx context.Context) (interface{}, error) {
+		return r.repository.Movie.FindMany(ctx, ids)
+	}
+}
+
+func (r *mutationResolver) tagBackupRows(ids []int) manager.AutoBackupRows {
+	return func(ctx context.Context) (interface{}, error) {
+		return r.repository.Tag.FindMany(ctx, ids)
+	}
+}
@@ -0,0 +1,239 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/performer"
+	"github.com/stashapp/stash/pkg/scene"
+	"github.com/stashapp/stash/pkg/sliceutil/intslice"
+)
+
+// operations which may be undone
+const (
+	bulkOperationSceneUpdate     = "bulkSceneUpdate"
+	bulkOperationScenesDestroy   = "scenesDestroy"
+	bulkOperationPerformersMerge = "mergePerformers"
+)
+
+var errNoBulkOperation = errors.New("no bulk operation to undo")
+
+// bulkSceneReverse is the reverse of a bulk operation on scenes, holding the
+// state of the scenes before the operation.
+type bulkSceneReverse struct {
+	Scenes []*scene.Snapshot `json:"scenes"`
+}
+
+func bulkUndoEnabled() bool {
+	return config.GetInstance().GetBulkUndoWindow() > 0
+}
+
+// bulkUndoCutoff returns the time before which bulk operations can no longer
+// be undone.
+func bulkUndoCutoff(now time.Time) time.Time {
+	window := config.GetInstance().GetBulkUndoWindow()
+	return now.Add(-time.Duration(window) * time.Minute)
+}
+
+// stageBulkOperation records the reverse of a bulk operation so that it can
+// be undone, and removes expired operations. It must be called within the
+// transaction of the operation.
+func (r *mutationResolver) stageBulkOperation(ctx context.Context, operation string, reverse interface{}) error {
+	data, err := json.Marshal(reverse)
+	if err != nil {
+		return fmt.Errorf("encoding reverse of %s: %w", operation, err)
+	}
+
+	now := time.Now()
+	qb := r.repository.BulkOperation
+	if err := qb.DestroyOlderThan(ctx, bulkUndoCutoff(now)); err != nil {
+		return err
+	}
+
+	_, err = qb.Create(ctx, models.BulkOperation{
+		Operation: operation,
+		Reverse:   data,
+		CreatedAt: now,
+	})
+	return err
+}
+
+func (r *mutationResolver) UndoBulkOperation(ctx context.Context) (ret *models.BulkOperation, err error) {
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		qb := r.repository.BulkOperation
+
+		ret, err = qb.FindLatest(ctx, bulkUndoCutoff(time.Now()))
+		if err != nil {
+			return err
+		}
+
+		if ret == nil {
+			return errNoBulkOperation
+		}
+
+		if err := r.undoBulkOperation(ctx, ret); err != nil {
+			return fmt.Errorf("undoing %s: %w", ret.Operation, err)
+		}
+
+		return qb.Destroy(ctx, ret.ID)
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) undoBulkOperation(ctx context.Context, op *models.BulkOperation) error {
+	switch op.Operation {
+	case bulkOperationSceneUpdate, bulkOperationScenesDestroy:
+		var reverse bulkSceneReverse
+		if err := json.Unmarshal(op.Reverse, &reverse); err != nil {
+			return fmt.Errorf("decoding reverse operation: %w", err)
+		}
+
+		if op.Operation == bulkOperationSceneUpdate {
+			return r.revertScenes(ctx, reverse.Scenes)
+		}
+		return r.restoreScenes(ctx, reverse.Scenes)
+	case bulkOperationPerformersMerge:
+		var reverse performer.MergeResult
+		if err := json.Unmarshal(op.Reverse, &reverse); err != nil {
+			return fmt.Errorf("decoding reverse operation: %w", err)
+		}
+
+		return r.unmergePerformers(ctx, &reverse)
+	default:
+		return fmt.Errorf("unsupported bulk operation %q", op.Operation)
+	}
+}
+
+// revertScenes reverts the scenes to the state of the snapshots. Scenes
+// which have since been destroyed are ignored.
+func (r *mutationResolver) revertScenes(ctx context.Context, snapshots []*scene.Snapshot) error {
+	qb := r.repository.Scene
+	for _, s := range snapshots {
+		existing, err := qb.Find(ctx, s.Scene.ID)
+		if err != nil {
+			return err
+		}
+
+		if existing == nil {
+			logger.Warnf("Not reverting scene %d: scene no longer exists", s.Scene.ID)
+			continue
+		}
+
+		if _, err := s.Revert(ctx, qb); err != nil {
+			return fmt.Errorf("reverting scene %d: %w", s.Scene.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// restoreScenes recreates the destroyed scenes of the snapshots.
+func (r *mutationResolver) restoreScenes(ctx context.Context, snapshots []*scene.Snapshot) error {
+	for _, s := range snapshots {
+		restored, err := s.Restore(ctx, r.repository.Scene, r.repository.SceneMarker)
+		if err != nil {
+			return fmt.Errorf("restoring scene %d: %w", s.Scene.ID, err)
+		}
+
+		logger.Infof("Restored destroyed scene %d as scene %d", s.Scene.ID, restored.ID)
+	}
+
+	return nil
+}
+
+// unmergePerformers reverts the destination of a performer merge, and
+// recreates the merged source performers, linking them to their original
+// scenes, images and galleries.
+func (r *mutationResolver) unmergePerformers(ctx context.Context, result *performer.MergeResult) error {
+	dest := result.Destination
+	if err := dest.Revert(ctx, r.repository.Performer); err != nil {
+		return fmt.Errorf("reverting performer %d: %w", dest.Performer.ID, err)
+	}
+
+	for _, s := range result.Sources {
+		restored, err := s.Restore(ctx, r.repository.Performer)
+		if err != nil {
+			return fmt.Errorf("restoring performer %d: %w", s.Performer.ID, err)
+		}
+
+		if err := r.linkPerformer(ctx, restored.ID, s, models.RelationshipUpdateModeAdd); err != nil {
+			return err
+		}
+
+		// remove the destination from content which it was only linked to
+		// through the merge
+		merged := &performer.Snapshot{
+			SceneIDs:   intslice.IntExclude(s.SceneIDs, dest.SceneIDs),
+			ImageIDs:   intslice.IntExclude(s.ImageIDs, dest.ImageIDs),
+			GalleryIDs: intslice.IntExclude(s.GalleryIDs, dest.GalleryIDs),
+		}
+		if err := r.linkPerformer(ctx, dest.Performer.ID, merged, models.RelationshipUpdateModeRemove); err != nil {
+			return err
+		}
+
+		logger.Infof("Restored merged performer %d as performer %d", s.Performer.ID, restored.ID)
+	}
+
+	return nil
+}
+
+// linkPerformer adds or removes the performer to or from the scenes, images
+// and galleries of the snapshot.
+func (r *mutationResolver) linkPerformer(ctx context.Context, performerID int, s *performer.Snapshot, mode models.RelationshipUpdateMode) error {
+	ids := &models.UpdateIDs{
+		IDs:  []int{performerID},
+		Mode: mode,
+	}
+
+	for _, id := range s.SceneIDs {
+		partial := models.NewScenePartial()
+		partial.PerformerIDs = ids
+		if _, err := r.repository.Scene.UpdatePartial(ctx, id, partial); err != nil {
+			return fmt.Errorf("updating performers of scene %d: %w", id, err)
+		}
+	}
+
+	for _, id := range s.ImageIDs {
+		partial := models.NewImagePartial()
+		partial.PerformerIDs = ids
+		if _, err := r.repository.Image.UpdatePartial(ctx, id, partial); err != nil {
+			return fmt.Errorf("updating performers of image %d: %w", id, err)
+		}
+	}
+
+	for _, id := range s.GalleryIDs {
+		partial := models.NewGalleryPartial()
+		partial.PerformerIDs = ids
+		if _, err := r.repository.Gallery.UpdatePartial(ctx, id, partial); err != nil {
+			return fmt.Errorf("updating performers of gallery %d: %w", id, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *queryResolver) LastBulkOperation(ctx context.Context) (ret *models.BulkOperation, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.BulkOperation.FindLatest(ctx, bulkUndoCutoff(time.Now()))
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *bulkOperationResolver) ExpiresAt(ctx context.Context, obj *models.BulkOperation) (*time.Time, error) {
+	window := config.GetInstance().GetBulkUndoWindow()
+	ret := obj.CreatedAt.Add(time.Duration(window) * time.Minute)
+	return &ret, nil
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// bulkUpdateErrors collects the objects of a bulk update which could not be
+// updated. These objects are skipped, and the rest of the update is applied.
+// Errors from the database still fail the whole update, since the
+// transaction may already contain a partial update of the object.
+type bulkUpdateErrors struct {
+	typ  string
+	errs []*gqlerror.Error
+}
+
+func newBulkUpdateErrors(typ string) *bulkUpdateErrors {
+	return &bulkUpdateErrors{
+		typ: typ,
+	}
+}
+
+func (e *bulkUpdateErrors) add(id int, err error) {
+	e.errs = append(e.errs, &gqlerror.Error{
+		Message: fmt.Sprintf("%s %d: %v", e.typ, id, err),
+		Extensions: map[string]interface{}{
+			"id": strconv.Itoa(id),
+		},
+	})
+}
+
+func (e *bulkUpdateErrors) notFound(id int) {
+	e.add(id, fmt.Errorf("%s not found", e.typ))
+}
+
+// report adds the collected errors to the response, alongside the updated
+// objects. Each error includes the ID of its object in the id extension.
+func (e *bulkUpdateErrors) report(ctx context.Context) {
+	path := graphql.GetPath(ctx)
+	for _, err := range e.errs {
+		err.Path = path
+		graphql.AddError(ctx, err)
+	}
+}
@@ -0,0 +1,27 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBulkUpdateErrors(t *testing.T) {
+	ctx := graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, nil)
+
+	e := newBulkUpdateErrors("scene")
+	e.notFound(1)
+	e.add(2, errors.New("invalid"))
+	e.report(ctx)
+
+	errs := graphql.GetErrors(ctx)
+	if assert.Len(t, errs, 2) {
+		assert.Equal(t, "scene 1: scene not found", errs[0].Message)
+		assert.Equal(t, "1", errs[0].Extensions["id"])
+		assert.Equal(t, "scene 2: invalid", errs[1].Message)
+		assert.Equal(t, "2", errs[1].Extensions["id"])
+	}
+}
@@ -0,0 +1,557 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/stashapp/stash/internal/manager"
+	"github.com/stashapp/stash/pkg/hash/md5"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/tag"
+	"github.com/stashapp/stash/pkg/txn"
+)
+
+const editHistoryActionRevert = "editHistoryRevert"
+
+// editHistoryMutations maps the mutations recorded in the edit history to the
+// type of object they edit.
+var editHistoryMutations = map[string]models.EditHistoryObjectType{
+	"sceneUpdate":         models.EditHistoryObjectTypeScene,
+	"scenesUpdate":        models.EditHistoryObjectTypeScene,
+	"bulkSceneUpdate":     models.EditHistoryObjectTypeScene,
+	"performerUpdate":     models.EditHistoryObjectTypePerformer,
+	"bulkPerformerUpdate": models.EditHistoryObjectTypePerformer,
+	"tagUpdate":           models.EditHistoryObjectTypeTag,
+	"studioUpdate":        models.EditHistoryObjectTypeStudio,
+}
+
+// editHistoryState is the set of editable fields of an object. The JSON
+// encoding of the state is compared to find the changed fields.
+type editHistoryState interface {
+	apply(ctx context.Context, r manager.Repository, id int) error
+}
+
+// loadEditHistoryState returns the current state of the object, or nil if it
+// does not exist.
+func loadEditHistoryState(ctx context.Context, r manager.Repository, objectType models.EditHistoryObjectType, id int) (editHistoryState, error) {
+	switch objectType {
+	case models.EditHistoryObjectTypeScene:
+		return loadSceneHistoryState(ctx, r, id)
+	case models.EditHistoryObjectTypePerformer:
+		return loadPerformerHistoryState(ctx, r, id)
+	case models.EditHistoryObjectTypeTag:
+		return loadTagHistoryState(ctx, r, id)
+	case models.EditHistoryObjectTypeStudio:
+		return loadStudioHistoryState(ctx, r, id)
+	}
+
+	return nil, fmt.Errorf("unsupported object type %s", objectType)
+}
+
+func dateString(d *models.Date) *string {
+	if d == nil {
+		return nil
+	}
+	s := d.String()
+	return &s
+}
+
+func stringDate(s *string) *models.Date {
+	if s == nil {
+		return nil
+	}
+	d := models.NewDate(*s)
+	return &d
+}
+
+func sortedIDs(ids []int) []int {
+	ret := append([]int{}, ids...)
+	sort.Ints(ret)
+	return ret
+}
+
+func tagIDs(tags []*models.Tag) []int {
+	ret := make([]int, len(tags))
+	for i, t := range tags {
+		ret[i] = t.ID
+	}
+	sort.Ints(ret)
+	return ret
+}
+
+type sceneHistoryState struct {
+	Title        string  `json:"title"`
+	Code         string  `json:"code"`
+	Details      string  `json:"details"`
+	Director     string  `json:"director"`
+	URL          string  `json:"url"`
+	Date         *string `json:"date"`
+	Rating100    *int    `json:"rating100"`
+	Organized    bool    `json:"organized"`
+	StudioID     *int    `json:"studio_id"`
+	PerformerIDs []int   `json:"performer_ids"`
+	TagIDs       []int   `json:"tag_ids"`
+	GalleryIDs   []int   `json:"gallery_ids"`
+}
+
+func loadSceneHistoryState(ctx context.Context, r manager.Repository, id int) (editHistoryState, error) {
+	s, err := r.Scene.Find(ctx, id)
+	if err != nil || s == nil {
+		return nil, err
+	}
+
+	if err := s.LoadPerformerIDs(ctx, r.Scene); err != nil {
+		return nil, err
+	}
+	if err := s.LoadTagIDs(ctx, r.Scene); err != nil {
+		return nil, err
+	}
+	if err := s.LoadGalleryIDs(ctx, r.Scene); err != nil {
+		return nil, err
+	}
+
+	return &sceneHistoryState{
+		Title:        s.Title,
+		Code:         s.Code,
+		Details:      s.Details,
+		Director:     s.Director,
+		URL:          s.URL,
+		Date:         dateString(s.Date),
+		Rating100:    s.Rating,
+		Organized:    s.Organized,
+		StudioID:     s.StudioID,
+		PerformerIDs: sortedIDs(s.PerformerIDs.List()),
+		TagIDs:       sortedIDs(s.TagIDs.List()),
+		GalleryIDs:   sortedIDs(s.GalleryIDs.List()),
+	}, nil
+}
+
+func (s *sceneHistoryState) apply(ctx context.Context, r manager.Repository, id int) error {
+	partial := models.NewScenePartial()
+	partial.Title = models.NewOptionalString(s.Title)
+	partial.Code = models.NewOptionalString(s.Code)
+	partial.Details = models.NewOptionalString(s.Details)
+	partial.Director = models.NewOptionalString(s.Director)
+	partial.URL = models.NewOptionalString(s.URL)
+	partial.Date = models.NewOptionalDatePtr(stringDate(s.Date))
+	partial.Rating = models.NewOptionalIntPtr(s.Rating100)
+	partial.Organized = models.NewOptionalBool(s.Organized)
+	partial.StudioID = models.NewOptionalIntPtr(s.StudioID)
+	partial.PerformerIDs = &models.UpdateIDs{IDs: s.PerformerIDs, Mode: models.RelationshipUpdateModeSet}
+	partial.TagIDs = &models.UpdateIDs{IDs: s.TagIDs, Mode: models.RelationshipUpdateModeSet}
+	partial.GalleryIDs = &models.UpdateIDs{IDs: s.GalleryIDs, Mode: models.RelationshipUpdateModeSet}
+
+	_, err := r.Scene.UpdatePartial(ctx, id, partial)
+	return err
+}
+
+type performerHistoryState struct {
+	Name           string   `json:"name"`
+	Disambiguation string   `json:"disambiguation"`
+	Gender         string   `json:"gender"`
+	URL            string   `json:"url"`
+	Twitter        string   `json:"twitter"`
+	Instagram      string   `json:"instagram"`
+	Birthdate      *string  `json:"birthdate"`
+	DeathDate      *string  `json:"death_date"`
+	Ethnicity      string   `json:"ethnicity"`
+	Country        string   `json:"country"`
+	EyeColor       string   `json:"eye_color"`
+	HairColor      string   `json:"hair_color"`
+	HeightCm       *int     `json:"height_cm"`
+	Weight         *int     `json:"weight"`
+	Measurements   string   `json:"measurements"`
+	FakeTits       string   `json:"fake_tits"`
+	CareerLength   string   `json:"career_length"`
+	Tattoos        string   `json:"tattoos"`
+	Piercings      string   `json:"piercings"`
+	Details        string   `json:"details"`
+	Favorite       bool     `json:"favorite"`
+	Rating100      *int     `json:"rating100"`
+	IgnoreAutoTag  bool     `json:"ignore_auto_tag"`
+	AliasList      []string `json:"alias_list"`
+	TagIDs         []int    `json:"tag_ids"`
+}
+
+func loadPerformerHistoryState(ctx context.Context, r manager.Repository, id int) (editHistoryState, error) {
+	p, err := r.Performer.Find(ctx, id)
+	if err != nil || p == nil {
+		return nil, err
+	}
+
+	if err := p.LoadAliases(ctx, r.Performer); err != nil {
+		return nil, err
+	}
+	if err := p.LoadTagIDs(ctx, r.Performer); err != nil {
+		return nil, err
+	}
+
+	return &performerHistoryState{
+		Name:           p.Name,
+		Disambiguation: p.Disambiguation,
+		Gender:         p.Gender.String(),
+		URL:            p.URL,
+		Twitter:        p.Twitter,
+		Instagram:      p.Instagram,
+		Birthdate:      dateString(p.Birthdate),
+		DeathDate:      dateString(p.DeathDate),
+		Ethnicity:      p.Ethnicity,
+		Country:        p.Country,
+		EyeColor:       p.EyeColor,
+		HairColor:      p.HairColor,
+		HeightCm:       p.Height,
+		Weight:         p.Weight,
+		Measurements:   p.Measurements,
+		FakeTits:       p.FakeTits,
+		CareerLength:   p.CareerLength,
+		Tattoos:        p.Tattoos,
+		Piercings:      p.Piercings,
+		Details:        p.Details,
+		Favorite:       p.Favorite,
+		Rating100:      p.Rating,
+		IgnoreAutoTag:  p.IgnoreAutoTag,
+		AliasList:      p.Aliases.List(),
+		TagIDs:         sortedIDs(p.TagIDs.List()),
+	}, nil
+}
+
+func (s *performerHistoryState) apply(ctx context.Context, r manager.Repository, id int) error {
+	partial := models.NewPerformerPartial()
+	partial.Name = models.NewOptionalString(s.Name)
+	partial.Disambiguation = models.NewOptionalString(s.Disambiguation)
+	partial.Gender = models.NewOptionalString(s.Gender)
+	partial.URL = models.NewOptionalString(s.URL)
+	partial.Twitter = models.NewOptionalString(s.Twitter)
+	partial.Instagram = models.NewOptionalString(s.Instagram)
+	partial.Birthdate = models.NewOptionalDatePtr(stringDate(s.Birthdate))
+	partial.DeathDate = models.NewOptionalDatePtr(stringDate(s.DeathDate))
+	partial.Ethnicity = models.NewOptionalString(s.Ethnicity)
+	partial.Country = models.NewOptionalString(s.Country)
+	partial.EyeColor = models.NewOptionalString(s.EyeColor)
+	partial.HairColor = models.NewOptionalString(s.HairColor)
+	partial.Height = models.NewOptionalIntPtr(s.HeightCm)
+	partial.Weight = models.NewOptionalIntPtr(s.Weight)
+	partial.Measurements = models.NewOptionalString(s.Measurements)
+	partial.FakeTits = models.NewOptionalString(s.FakeTits)
+	partial.CareerLength = models.NewOptionalString(s.CareerLength)
+	partial.Tattoos = models.NewOptionalString(s.Tattoos)
+	partial.Piercings = models.NewOptionalString(s.Piercings)
+	partial.Details = models.NewOptionalString(s.Details)
+	partial.Favorite = models.NewOptionalBool(s.Favorite)
+	partial.Rating = models.NewOptionalIntPtr(s.Rating100)
+	partial.IgnoreAutoTag = models.NewOptionalBool(s.IgnoreAutoTag)
+	partial.Aliases = &models.UpdateStrings{Values: s.AliasList, Mode: models.RelationshipUpdateModeSet}
+	partial.TagIDs = &models.UpdateIDs{IDs: s.TagIDs, Mode: models.RelationshipUpdateModeSet}
+
+	_, err := r.Performer.UpdatePartial(ctx, id, partial)
+	return err
+}
+
+type tagHistoryState struct {
+	Name           string   `json:"name"`
+	Description    string   `json:"description"`
+	Aliases        []string `json:"aliases"`
+	IgnoreAutoTag  bool     `json:"ignore_auto_tag"`
+	ContentWarning bool     `json:"content_warning"`
+	ParentIDs      []int    `json:"parent_ids"`
+	ChildIDs       []int    `json:"child_ids"`
+}
+
+func loadTagHistoryState(ctx context.Context, r manager.Repository, id int) (editHistoryState, error) {
+	qb := r.Tag
+	t, err := qb.Find(ctx, id)
+	if err != nil || t == nil {
+		return nil, err
+	}
+
+	aliases, err := qb.GetAliases(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	parents, err := qb.FindByChildTagID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	children, err := qb.FindByParentTagID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tagHistoryState{
+		Name:           t.Name,
+		Description:    t.Description.String,
+		Aliases:        aliases,
+		IgnoreAutoTag:  t.IgnoreAutoTag,
+		ContentWarning: t.ContentWarning,
+		ParentIDs:      tagIDs(parents),
+		ChildIDs:       tagIDs(children),
+	}, nil
+}
+
+func (s *tagHistoryState) apply(ctx context.Context, r manager.Repository, id int) error {
+	qb := r.Tag
+
+	if err := tag.EnsureTagNameUnique(ctx, id, s.Name, qb); err != nil {
+		return err
+	}
+	if err := tag.EnsureAliasesUnique(ctx, id, s.Aliases, qb); err != nil {
+		return err
+	}
+
+	t, err := qb.Update(ctx, models.TagPartial{
+		ID:             id,
+		Name:           &s.Name,
+		Description:    &sql.NullString{String: s.Description, Valid: s.Description != ""},
+		IgnoreAutoTag:  &s.IgnoreAutoTag,
+		ContentWarning: &s.ContentWarning,
+		UpdatedAt:      &models.SQLiteTimestamp{Timestamp: time.Now()},
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := qb.UpdateAliases(ctx, id, s.Aliases); err != nil {
+		return err
+	}
+	if err := qb.UpdateParentTags(ctx, id, s.ParentIDs); err != nil {
+		return err
+	}
+	if err := qb.UpdateChildTags(ctx, id, s.ChildIDs); err != nil {
+		return err
+	}
+
+	return tag.ValidateHierarchy(ctx, t, s.ParentIDs, s.ChildIDs, qb)
+}
+
+type studioHistoryState struct {
+	Name          string   `json:"name"`
+	URL           string   `json:"url"`
+	Details       string   `json:"details"`
+	ParentID      *int     `json:"parent_id"`
+	Rating100     *int     `json:"rating100"`
+	IgnoreAutoTag bool     `json:"ignore_auto_tag"`
+	Aliases       []string `json:"aliases"`
+}
+
+func nullInt64Ptr(v sql.NullInt64) *int {
+	if !v.Valid {
+		return nil
+	}
+	i := int(v.Int64)
+	return &i
+}
+
+func intPtrNullInt64(v *int) *sql.NullInt64 {
+	if v == nil {
+		return &sql.NullInt64{}
+	}
+	return &sql.NullInt64{Int64: int64(*v), Valid: true}
+}
+
+func loadStudioHistoryState(ctx context.Context, r manager.Repository, id int) (editHistoryState, error) {
+	qb := r.Studio
+	s, err := qb.Find(ctx, id)
+	if err != nil || s == nil {
+		return nil, err
+	}
+
+	aliases, err := qb.GetAliases(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &studioHistoryState{
+		Name:          s.Name.String,
+		URL:           s.URL.String,
+		Details:       s.Details.String,
+		ParentID:      nullInt64Ptr(s.ParentID),
+		Rating100:     nullInt64Ptr(s.Rating),
+		IgnoreAutoTag: s.IgnoreAutoTag,
+		Aliases:       aliases,
+	}, nil
+}
+
+func (s *studioHistoryState) apply(ctx context.Context, r manager.Repository, id int) error {
+	qb := r.Studio
+
+	checksum := md5.FromString(s.Name)
+	partial := models.StudioPartial{
+		ID:            id,
+		Checksum:      &checksum,
+		Name:          &sql.NullString{String: s.Name, Valid: true},
+		URL:           &sql.NullString{String: s.URL, Valid: true},
+		Details:       &sql.NullString{String: s.Details, Valid: true},
+		ParentID:      intPtrNullInt64(s.ParentID),
+		Rating:        intPtrNullInt64(s.Rating100),
+		IgnoreAutoTag: &s.IgnoreAutoTag,
+		UpdatedAt:     &models.SQLiteTimestamp{Timestamp: time.Now()},
+	}
+
+	if err := manager.ValidateModifyStudio(ctx, partial, qb); err != nil {
+		return err
+	}
+
+	if _, err := qb.Update(ctx, partial); err != nil {
+		return err
+	}
+
+	return qb.UpdateAliases(ctx, id, s.Aliases)
+}
+
+// recordEditHistory records the differences between the before and after
+// states of the object. Nothing is recorded if the object was not changed.
+func recordEditHistory(ctx context.Context, r manager.Repository, entry models.EditHistoryEntry, before, after editHistoryState) error {
+	if before == nil || after == nil {
+		return nil
+	}
+
+	changes, err := models.DiffFields(before, after)
+	if err != nil {
+		return err
+	}
+
+	if len(changes) == 0 {
+		return nil
+	}
+
+	actor := getActivityActor(ctx)
+	entry.CreatedAt = time.Now()
+	entry.AuthMethod = actor.AuthMethod
+	entry.Credential = actor.Credential
+	entry.Changes = changes
+
+	_, err = r.EditHistory.Create(ctx, entry)
+	return err
+}
+
+type editHistoryRecorder struct {
+	repository manager.Repository
+}
+
+func newEditHistoryRecorder(repo manager.Repository) *editHistoryRecorder {
+	return &editHistoryRecorder{
+		repository: repo,
+	}
+}
+
+// FieldMiddleware records the fields changed by the mutations in
+// editHistoryMutations. The state of the edited objects is loaded before and
+// after the mutation is resolved.
+func (h *editHistoryRecorder) FieldMiddleware(ctx context.Context, next graphql.Resolver) (interface{}, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || fc.Object != "Mutation" {
+		return next(ctx)
+	}
+
+	objectType, ok := editHistoryMutations[fc.Field.Name]
+	if !ok {
+		return next(ctx)
+	}
+
+	ids := editHistoryTargetIDs(fc.Args)
+	before := make(map[int]editHistoryState)
+	if err := txn.WithReadTxn(ctx, h.repository, func(ctx context.Context) error {
+		for _, id := range ids {
+			s, err := loadEditHistoryState(ctx, h.repository, objectType, id)
+			if err != nil {
+				return err
+			}
+			before[id] = s
+		}
+		return nil
+	}); err != nil {
+		logger.Errorf("Error loading edit history state for %s: %v", fc.Field.Name, err)
+		return next(ctx)
+	}
+
+	res, err := next(ctx)
+	if err != nil {
+		return res, err
+	}
+
+	// use a context detached from the request so that the history is
+	// recorded even if the client disconnects
+	actorCtx := setActivityActor(context.Background(), getActivityActor(ctx))
+	if err := txn.WithTxn(actorCtx, h.repository, func(ctx context.Context) error {
+		for _, id := range ids {
+			after, err := loadEditHistoryState(ctx, h.repository, objectType, id)
+			if err != nil {
+				return err
+			}
+
+			if err := recordEditHistory(ctx, h.repository, models.EditHistoryEntry{
+				ObjectType: objectType,
+				ObjectID:   id,
+				Action:     fc.Field.Name,
+			}, before[id], after); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		logger.Errorf("Error recording edit history for %s: %v", fc.Field.Name, err)
+	}
+
+	return res, err
+}
+
+// editHistoryTargetIDs returns the IDs of the objects edited by a mutation,
+// taken from the id or ids fields of the input argument. The input argument
+// may also be a list of objects with id fields.
+func editHistoryTargetIDs(args map[string]interface{}) []int {
+	// round-trip through JSON to convert input structs into maps
+	data, err := json.Marshal(args["input"])
+	if err != nil {
+		return nil
+	}
+
+	var input interface{}
+	if err := json.Unmarshal(data, &input); err != nil {
+		return nil
+	}
+
+	toID := func(v interface{}) (int, bool) {
+		s, ok := v.(string)
+		if !ok {
+			return 0, false
+		}
+		id, err := strconv.Atoi(s)
+		return id, err == nil
+	}
+
+	var ret []int
+	addObject := func(v interface{}) {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return
+		}
+
+		if id, ok := toID(m["id"]); ok {
+			ret = append(ret, id)
+		}
+		if ids, ok := m["ids"].([]interface{}); ok {
+			for _, v := range ids {
+				if id, ok := toID(v); ok {
+					ret = append(ret, id)
+				}
+			}
+		}
+	}
+
+	if list, ok := input.([]interface{}); ok {
+		for _, v := range list {
+			addObject(v)
+		}
+	} else {
+		addObject(input)
+	}
+
+	return ret
+}
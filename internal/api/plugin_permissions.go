@@ -0,0 +1,84 @@
+package api
+
+import (
+	"context"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/session"
+)
+
+// pluginAdminMutations are the mutations which require the admin permission
+// when run by a plugin. Mutations starting with configure also require it.
+var pluginAdminMutations = map[string]bool{
+	"setup":                    true,
+	"migrate":                  true,
+	"generateAPIKey":           true,
+	"importObjects":            true,
+	"metadataImport":           true,
+	"migrateHashNaming":        true,
+	"metadataCheckConsistency": true,
+	"backupDatabase":           true,
+	"anonymiseDatabase":        true,
+	"reloadScrapers":           true,
+	"runPluginTask":            true,
+	"reloadPlugins":            true,
+	"activateTheme":            true,
+	"enableDLNA":               true,
+	"disableDLNA":              true,
+	"addTempDLNAIP":            true,
+	"removeTempDLNAIP":         true,
+	"stashPathAdd":             true,
+	"stashPathUpdate":          true,
+	"stashPathRemove":          true,
+	"stashPathsReorder":        true,
+}
+
+func requiredPluginPermission(mutation string) session.PluginPermission {
+	if pluginAdminMutations[mutation] || strings.HasPrefix(mutation, "configure") {
+		return session.PluginPermissionAdmin
+	}
+
+	return session.PluginPermissionWrite
+}
+
+// pluginPermissionsMiddleware rejects mutations that the plugin making the
+// request has not been granted permission to run.
+func pluginPermissionsMiddleware(ctx context.Context, next graphql.RootResolver) graphql.Marshaler {
+	fc := graphql.GetRootFieldContext(ctx)
+	if fc == nil || fc.Object != "Mutation" {
+		return next(ctx)
+	}
+
+	required := requiredPluginPermission(fc.Field.Name)
+	if !session.HasPluginPermission(ctx, required) {
+		graphql.AddErrorf(ctx, "plugin %s does not have the %s permission required to run %s", session.GetPluginID(ctx), required, fc.Field.Name)
+		return graphql.Null
+	}
+
+	return next(ctx)
+}
+
+// redactConfigSecrets removes secrets from the configuration, unless the
+// request was made by an administrator or a plugin with the admin permission.
+func redactConfigSecrets(ctx context.Context, c *ConfigResult) {
+	if session.HasPluginPermission(ctx, session.PluginPermissionAdmin) && session.HasUserRole(ctx, models.UserRoleAdmin) {
+		return
+	}
+
+	g := c.General
+	g.APIKey = ""
+	g.Password = ""
+	g.SessionLockPin = ""
+	g.MediaAccessToken = nil
+
+	stashBoxes := make([]*models.StashBox, len(g.StashBoxes))
+	for i, sb := range g.StashBoxes {
+		redacted := *sb
+		redacted.APIKey = ""
+		stashBoxes[i] = &redacted
+	}
+	g.StashBoxes = stashBoxes
+}
@@ -2,7 +2,10 @@ package api
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"reflect"
 	"sort"
 	"strconv"
 
@@ -10,6 +13,7 @@ import (
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/plugin"
+	"github.com/stashapp/stash/pkg/scene"
 	"github.com/stashapp/stash/pkg/scraper"
 	"github.com/stashapp/stash/pkg/txn"
 )
@@ -28,6 +32,7 @@ var (
 
 type hookExecutor interface {
 	ExecutePostHooks(ctx context.Context, id int, hookType plugin.HookTriggerEnum, input interface{}, inputFields []string)
+	ExecutePreHooks(ctx context.Context, id int, hookType plugin.HookTriggerEnum, input interface{}) (map[string]interface{}, error)
 }
 
 type Resolver struct {
@@ -65,6 +70,48 @@ func (r *Resolver) Image() ImageResolver {
 func (r *Resolver) SceneMarker() SceneMarkerResolver {
 	return &sceneMarkerResolver{r}
 }
+func (r *Resolver) WantedScene() WantedSceneResolver {
+	return &wantedSceneResolver{r}
+}
+func (r *Resolver) BulkOperation() BulkOperationResolver {
+	return &bulkOperationResolver{r}
+}
+func (r *Resolver) TrashedScene() TrashedSceneResolver {
+	return &trashedSceneResolver{r}
+}
+func (r *Resolver) SearchResult() SearchResultResolver {
+	return &searchResultResolver{r}
+}
+func (r *Resolver) TagSuggestion() TagSuggestionResolver {
+	return &tagSuggestionResolver{r}
+}
+func (r *Resolver) StashBoxMatchSuggestion() StashBoxMatchSuggestionResolver {
+	return &stashBoxMatchSuggestionResolver{r}
+}
+func (r *Resolver) TagMergePlan() TagMergePlanResolver {
+	return &tagMergePlanResolver{r}
+}
+func (r *Resolver) PlayQueueItem() PlayQueueItemResolver {
+	return &playQueueItemResolver{r}
+}
+func (r *Resolver) SceneFlag() SceneFlagResolver {
+	return &sceneFlagResolver{r}
+}
+func (r *Resolver) CustomFieldValue() CustomFieldValueResolver {
+	return &customFieldValueResolver{r}
+}
+func (r *Resolver) EditHistoryEntry() EditHistoryEntryResolver {
+	return &editHistoryEntryResolver{r}
+}
+func (r *Resolver) EditHistoryFieldChange() EditHistoryFieldChangeResolver {
+	return &editHistoryFieldChangeResolver{r}
+}
+func (r *Resolver) User() UserResolver {
+	return &userResolver{r}
+}
+func (r *Resolver) RestrictionProfile() RestrictionProfileResolver {
+	return &restrictionProfileResolver{r}
+}
 func (r *Resolver) Studio() StudioResolver {
 	return &studioResolver{r}
 }
@@ -77,6 +124,27 @@ func (r *Resolver) Subscription() SubscriptionResolver {
 func (r *Resolver) Tag() TagResolver {
 	return &tagResolver{r}
 }
+func (r *Resolver) ScheduledTask() ScheduledTaskResolver {
+	return &scheduledTaskResolver{r}
+}
+func (r *Resolver) ScrapeRefreshRule() ScrapeRefreshRuleResolver {
+	return &scrapeRefreshRuleResolver{r}
+}
+func (r *Resolver) JobArtifact() JobArtifactResolver {
+	return &jobArtifactResolver{r}
+}
+func (r *Resolver) StashBoxDraft() StashBoxDraftResolver {
+	return &stashBoxDraftResolver{r}
+}
+func (r *Resolver) Diagnostics() DiagnosticsResolver {
+	return &diagnosticsResolver{r}
+}
+func (r *Resolver) StatsResultType() StatsResultTypeResolver {
+	return &statsResultTypeResolver{r}
+}
+func (r *Resolver) PathStats() PathStatsResolver {
+	return &pathStatsResolver{r}
+}
 
 type mutationResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
@@ -86,10 +154,31 @@ type galleryResolver struct{ *Resolver }
 type performerResolver struct{ *Resolver }
 type sceneResolver struct{ *Resolver }
 type sceneMarkerResolver struct{ *Resolver }
+type wantedSceneResolver struct{ *Resolver }
+type sceneFlagResolver struct{ *Resolver }
+type customFieldValueResolver struct{ *Resolver }
+type editHistoryEntryResolver struct{ *Resolver }
+type editHistoryFieldChangeResolver struct{ *Resolver }
+type userResolver struct{ *Resolver }
+type restrictionProfileResolver struct{ *Resolver }
+type bulkOperationResolver struct{ *Resolver }
+type trashedSceneResolver struct{ *Resolver }
+type searchResultResolver struct{ *Resolver }
+type tagSuggestionResolver struct{ *Resolver }
+type stashBoxMatchSuggestionResolver struct{ *Resolver }
+type tagMergePlanResolver struct{ *Resolver }
+type playQueueItemResolver struct{ *Resolver }
 type imageResolver struct{ *Resolver }
 type studioResolver struct{ *Resolver }
 type movieResolver struct{ *Resolver }
 type tagResolver struct{ *Resolver }
+type scheduledTaskResolver struct{ *Resolver }
+type scrapeRefreshRuleResolver struct{ *Resolver }
+type jobArtifactResolver struct{ *Resolver }
+type stashBoxDraftResolver struct{ *Resolver }
+type diagnosticsResolver struct{ *Resolver }
+type statsResultTypeResolver struct{ *Resolver }
+type pathStatsResolver struct{ *Resolver }
 
 func (r *Resolver) withTxn(ctx context.Context, fn func(ctx context.Context) error) error {
 	return txn.WithTxn(ctx, r.txnManager, fn)
@@ -99,6 +188,46 @@ func (r *Resolver) withReadTxn(ctx context.Context, fn func(ctx context.Context)
 	return txn.WithReadTxn(ctx, r.txnManager, fn)
 }
 
+// executePreHooks runs the plugin pre hooks of a mutation, which may veto it
+// or replace its input. input must point to the input of the mutation. The
+// hooks of update mutations are passed the fields of the translator instead,
+// so that they can tell the set fields from the null fields. If a hook
+// replaces the input, input and the fields of the translator are replaced
+// with it.
+func (r *Resolver) executePreHooks(ctx context.Context, id int, hookType plugin.HookTriggerEnum, input interface{}, translator *changesetTranslator) error {
+	hookInput := input
+	if translator != nil {
+		hookInput = translator.inputMap
+	}
+
+	replaced, err := r.hookExecutor.ExecutePreHooks(ctx, id, hookType, hookInput)
+	if err != nil {
+		return err
+	}
+
+	if replaced == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(replaced)
+	if err != nil {
+		return err
+	}
+
+	// clear the input so that removed fields are unset
+	v := reflect.ValueOf(input).Elem()
+	v.Set(reflect.Zero(v.Type()))
+
+	if err := json.Unmarshal(data, input); err != nil {
+		return fmt.Errorf("invalid input returned by %s hook: %w", hookType, err)
+	}
+
+	if translator != nil {
+		translator.inputMap = replaced
+	}
+	return nil
+}
+
 func (r *queryResolver) MarkerWall(ctx context.Context, q *string) (ret []*models.SceneMarker, err error) {
 	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
 		ret, err = r.repository.SceneMarker.Wall(ctx, q)
@@ -143,6 +272,7 @@ func (r *queryResolver) Stats(ctx context.Context) (*StatsResultType, error) {
 		moviesQB := repo.Movie
 		tagsQB := repo.Tag
 		scenesCount, _ := scenesQB.Count(ctx)
+		inboxScenesCount, _ := scene.CountInbox(ctx, scenesQB)
 		scenesSize, _ := scenesQB.Size(ctx)
 		scenesDuration, _ := scenesQB.Duration(ctx)
 		imageCount, _ := imageQB.Count(ctx)
@@ -152,18 +282,29 @@ func (r *queryResolver) Stats(ctx context.Context) (*StatsResultType, error) {
 		studiosCount, _ := studiosQB.Count(ctx)
 		moviesCount, _ := moviesQB.Count(ctx)
 		tagsCount, _ := tagsQB.Count(ctx)
+		playbackSessions, _ := repo.PlaybackEvent.CountSessions(ctx, nil)
+		playbackCompleted, _ := repo.PlaybackEvent.CountCompletedSessions(ctx, nil)
+
+		var playbackCompletionRate float64
+		if playbackSessions > 0 {
+			playbackCompletionRate = float64(playbackCompleted) / float64(playbackSessions)
+		}
 
 		ret = StatsResultType{
-			SceneCount:     scenesCount,
-			ScenesSize:     scenesSize,
-			ScenesDuration: scenesDuration,
-			ImageCount:     imageCount,
-			ImagesSize:     imageSize,
-			GalleryCount:   galleryCount,
-			PerformerCount: performersCount,
-			StudioCount:    studiosCount,
-			MovieCount:     moviesCount,
-			TagCount:       tagsCount,
+			SceneCount:      scenesCount,
+			InboxSceneCount: inboxScenesCount,
+			ScenesSize:      scenesSize,
+			ScenesDuration:  scenesDuration,
+			ImageCount:      imageCount,
+			ImagesSize:      imageSize,
+			GalleryCount:    galleryCount,
+			PerformerCount:  performersCount,
+			StudioCount:     studiosCount,
+			MovieCount:      moviesCount,
+			TagCount:        tagsCount,
+
+			PlaybackSessions:       playbackSessions,
+			PlaybackCompletionRate: playbackCompletionRate,
 		}
 
 		return nil
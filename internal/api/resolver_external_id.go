@@ -0,0 +1,115 @@
+package api
+
+import (
+	"context"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func externalIDsSliceToPtrSlice(v []models.ExternalID) []*models.ExternalID {
+	ret := make([]*models.ExternalID, len(v))
+	for i, vv := range v {
+		c := vv
+		ret[i] = &c
+	}
+
+	return ret
+}
+
+func externalIDPtrSliceToSlice(v []*models.ExternalID) []models.ExternalID {
+	ret := make([]models.ExternalID, len(v))
+	for i, vv := range v {
+		ret[i] = *vv
+	}
+
+	return sanitizeExternalIDs(ret)
+}
+
+// sanitizeExternalIDs trims the input identifiers, ignoring identifiers with
+// an empty source or ID. Only the last identifier of each source is kept.
+func sanitizeExternalIDs(v []models.ExternalID) []models.ExternalID {
+	var ret []models.ExternalID
+	for _, vv := range v {
+		c := models.ExternalID{
+			Source: strings.TrimSpace(vv.Source),
+			ID:     strings.TrimSpace(vv.ID),
+		}
+		if c.Source == "" || c.ID == "" {
+			continue
+		}
+
+		ret = models.MergeExternalIDs(ret, []models.ExternalID{c})
+	}
+
+	return ret
+}
+
+func (r *sceneResolver) ExternalIds(ctx context.Context, obj *models.Scene) (ret []*models.ExternalID, err error) {
+	var externalIDs []models.ExternalID
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		externalIDs, err = r.repository.Scene.GetExternalIDs(ctx, obj.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return externalIDsSliceToPtrSlice(externalIDs), nil
+}
+
+func (r *performerResolver) ExternalIds(ctx context.Context, obj *models.Performer) (ret []*models.ExternalID, err error) {
+	var externalIDs []models.ExternalID
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		externalIDs, err = r.repository.Performer.GetExternalIDs(ctx, obj.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return externalIDsSliceToPtrSlice(externalIDs), nil
+}
+
+func (r *studioResolver) ExternalIds(ctx context.Context, obj *models.Studio) (ret []*models.ExternalID, err error) {
+	var externalIDs []models.ExternalID
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		externalIDs, err = r.repository.Studio.GetExternalIDs(ctx, obj.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return externalIDsSliceToPtrSlice(externalIDs), nil
+}
+
+func (r *queryResolver) FindScenesByExternalID(ctx context.Context, source string, id string) (ret []*models.Scene, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.Scene.FindByExternalID(ctx, source, id)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *queryResolver) FindPerformersByExternalID(ctx context.Context, source string, id string) (ret []*models.Performer, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.Performer.FindByExternalID(ctx, source, id)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *queryResolver) FindStudiosByExternalID(ctx context.Context, source string, id string) (ret []*models.Studio, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.Studio.FindByExternalID(ctx, source, id)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func (r *customFieldValueResolver) Field(ctx context.Context, obj *models.CustomFieldValue) (ret *models.CustomField, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.CustomField.Find(ctx, obj.FieldID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	if ret == nil {
+		return nil, fmt.Errorf("custom field with id %d not found", obj.FieldID)
+	}
+
+	return ret, nil
+}
+
+// customFieldValues returns the custom field values of the object.
+func (r *Resolver) customFieldValues(ctx context.Context, objectType models.CustomFieldObjectType, id int) ([]*models.CustomFieldValue, error) {
+	var values []models.CustomFieldValue
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		var err error
+		values, err = r.repository.CustomField.GetValues(ctx, objectType, id)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	ret := make([]*models.CustomFieldValue, len(values))
+	for i := range values {
+		ret[i] = &values[i]
+	}
+
+	return ret, nil
+}
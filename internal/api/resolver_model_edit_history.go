@@ -0,0 +1,42 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func (r *editHistoryEntryResolver) Changes(ctx context.Context, obj *models.EditHistoryEntry) ([]*models.FieldChange, error) {
+	ret := make([]*models.FieldChange, len(obj.Changes))
+	for i := range obj.Changes {
+		ret[i] = &obj.Changes[i]
+	}
+
+	return ret, nil
+}
+
+func (r *editHistoryFieldChangeResolver) OldValue(ctx context.Context, obj *models.FieldChange) (interface{}, error) {
+	if obj.OldValue == nil {
+		return nil, nil
+	}
+	return obj.OldValue, nil
+}
+
+func (r *editHistoryFieldChangeResolver) NewValue(ctx context.Context, obj *models.FieldChange) (interface{}, error) {
+	if obj.NewValue == nil {
+		return nil, nil
+	}
+	return obj.NewValue, nil
+}
+
+// editHistory returns the edit history of the object, newest first.
+func (r *Resolver) editHistory(ctx context.Context, objectType models.EditHistoryObjectType, id int) (ret []*models.EditHistoryEntry, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.EditHistory.FindByObject(ctx, objectType, id)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
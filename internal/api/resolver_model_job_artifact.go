@@ -0,0 +1,13 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/internal/api/urlbuilders"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func (r *jobArtifactResolver) URL(ctx context.Context, obj *models.JobArtifact) (string, error) {
+	baseURL, _ := ctx.Value(BaseURLCtxKey).(string)
+	return urlbuilders.NewJobArtifactURLBuilder(baseURL, obj).GetURL(), nil
+}
@@ -139,6 +139,17 @@ func (r *performerResolver) StashIds(ctx context.Context, obj *models.Performer)
 	return stashIDsSliceToPtrSlice(obj.StashIDs.List()), nil
 }
 
+func (r *performerResolver) StashBoxDrafts(ctx context.Context, obj *models.Performer) (ret []*models.StashBoxDraft, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.StashBoxDraft.FindByPerformerID(ctx, obj.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
 func (r *performerResolver) Rating(ctx context.Context, obj *models.Performer) (*int, error) {
 	if obj.Rating != nil {
 		rating := models.Rating100To5(*obj.Rating)
@@ -170,6 +181,10 @@ func (r *performerResolver) Movies(ctx context.Context, obj *models.Performer) (
 	return ret, nil
 }
 
+func (r *performerResolver) CustomFields(ctx context.Context, obj *models.Performer) ([]*models.CustomFieldValue, error) {
+	return r.customFieldValues(ctx, models.CustomFieldObjectTypePerformer, obj.ID)
+}
+
 func (r *performerResolver) MovieCount(ctx context.Context, obj *models.Performer) (ret *int, err error) {
 	var res int
 	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
@@ -181,3 +196,7 @@ func (r *performerResolver) MovieCount(ctx context.Context, obj *models.Performe
 
 	return &res, nil
 }
+
+func (r *performerResolver) History(ctx context.Context, obj *models.Performer) ([]*models.EditHistoryEntry, error) {
+	return r.editHistory(ctx, models.EditHistoryObjectTypePerformer, obj.ID)
+}
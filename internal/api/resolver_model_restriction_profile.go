@@ -0,0 +1,40 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/internal/api/loaders"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func (r *restrictionProfileResolver) HasPin(ctx context.Context, obj *models.RestrictionProfile) (bool, error) {
+	return obj.PinHash != nil, nil
+}
+
+func (r *restrictionProfileResolver) Tags(ctx context.Context, obj *models.RestrictionProfile) (ret []*models.Tag, err error) {
+	var tagIDs []int
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		tagIDs, err = r.repository.RestrictionProfile.GetTagIDs(ctx, obj.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	ret, errs = loaders.From(ctx).TagByID.LoadAll(tagIDs)
+	return ret, firstError(errs)
+}
+
+func (r *restrictionProfileResolver) Studios(ctx context.Context, obj *models.RestrictionProfile) (ret []*models.Studio, err error) {
+	var studioIDs []int
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		studioIDs, err = r.repository.RestrictionProfile.GetStudioIDs(ctx, obj.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	ret, errs = loaders.From(ctx).StudioByID.LoadAll(studioIDs)
+	return ret, firstError(errs)
+}
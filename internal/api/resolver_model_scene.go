@@ -9,8 +9,11 @@ import (
 	"github.com/stashapp/stash/internal/api/loaders"
 	"github.com/stashapp/stash/internal/api/urlbuilders"
 	"github.com/stashapp/stash/internal/manager"
+	"github.com/stashapp/stash/internal/manager/config"
 	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/file/video"
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene"
 	"github.com/stashapp/stash/pkg/utils"
 )
 
@@ -103,6 +106,11 @@ func (r *sceneResolver) File(ctx context.Context, obj *models.Scene) (*models.Sc
 	}, nil
 }
 
+func (r *sceneResolver) LinkedOnly(ctx context.Context, obj *models.Scene) (bool, error) {
+	// scenes with files always have a primary file
+	return obj.PrimaryFileID == nil, nil
+}
+
 func (r *sceneResolver) Files(ctx context.Context, obj *models.Scene) ([]*VideoFile, error) {
 	files, err := r.getFiles(ctx, obj)
 	if err != nil {
@@ -186,11 +194,25 @@ func (r *sceneResolver) Paths(ctx context.Context, obj *models.Scene) (*ScenePat
 	webpPath := builder.GetStreamPreviewImageURL()
 	vttPath := builder.GetSpriteVTTURL()
 	spritePath := builder.GetSpriteURL()
+	hoverStripPath := builder.GetHoverStripURL()
+	hoverStripVttPath := builder.GetHoverStripVTTURL()
 	chaptersVttPath := builder.GetChaptersVTTURL()
 	funscriptPath := builder.GetFunscriptURL()
 	captionBasePath := builder.GetCaptionURL()
 	interactiveHeatmap := builder.GetInteractiveHeatmapURL()
 
+	var trailerPath *string
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		trailerFileID, err := r.repository.Scene.GetTrailerFileID(ctx, obj.ID)
+		if err == nil && trailerFileID != nil {
+			p := builder.GetTrailerURL()
+			trailerPath = &p
+		}
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
 	return &ScenePathsType{
 		Screenshot:         &screenshotPath,
 		Preview:            &previewPath,
@@ -199,9 +221,12 @@ func (r *sceneResolver) Paths(ctx context.Context, obj *models.Scene) (*ScenePat
 		Vtt:                &vttPath,
 		ChaptersVtt:        &chaptersVttPath,
 		Sprite:             &spritePath,
+		HoverStrip:         &hoverStripPath,
+		HoverStripVtt:      &hoverStripVttPath,
 		Funscript:          &funscriptPath,
 		InteractiveHeatmap: &interactiveHeatmap,
 		Caption:            &captionBasePath,
+		Trailer:            trailerPath,
 	}, nil
 }
 
@@ -235,6 +260,146 @@ func (r *sceneResolver) Captions(ctx context.Context, obj *models.Scene) (ret []
 	return ret, err
 }
 
+func (r *sceneResolver) Chapters(ctx context.Context, obj *models.Scene) (ret []*SceneChapter, err error) {
+	primaryFile, err := r.getPrimaryFile(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+	if primaryFile == nil {
+		return []*SceneChapter{}, nil
+	}
+
+	var chapters []file.VideoChapter
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		chapters, err = r.repository.File.GetChapters(ctx, primaryFile.Base().ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	baseURL, _ := ctx.Value(BaseURLCtxKey).(string)
+	builder := urlbuilders.NewSceneURLBuilder(baseURL, obj.ID)
+
+	ret = []*SceneChapter{}
+	for i, c := range chapters {
+		ret = append(ret, &SceneChapter{
+			Index:     i,
+			Title:     c.Title,
+			Start:     c.Start,
+			Thumbnail: builder.GetChapterThumbnailURL(i, c.Start),
+		})
+	}
+
+	return ret, nil
+}
+
+func (r *sceneResolver) Subtitles(ctx context.Context, obj *models.Scene) (ret []*SceneSubtitle, err error) {
+	primaryFile, err := r.getPrimaryFile(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+	if primaryFile == nil {
+		return []*SceneSubtitle{}, nil
+	}
+
+	var subtitles []manager.Subtitle
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		subtitles, err = manager.GetSubtitles(ctx, r.repository.File, primaryFile.Base().ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	baseURL, _ := ctx.Value(BaseURLCtxKey).(string)
+	builder := urlbuilders.NewSceneURLBuilder(baseURL, obj.ID)
+
+	ret = []*SceneSubtitle{}
+	for i, s := range subtitles {
+		subtitle := &SceneSubtitle{
+			Index: i,
+			Vtt:   builder.GetSubtitleVTTURL(i),
+		}
+
+		if s.Caption != nil {
+			subtitle.Source = SubtitleSourceSidecar
+			subtitle.LanguageCode = s.Caption.LanguageCode
+			subtitle.Format = s.Caption.CaptionType
+		} else {
+			subtitle.Source = SubtitleSourceEmbedded
+			subtitle.LanguageCode = s.Track.Language
+			if s.Track.Title != "" {
+				subtitle.Title = &s.Track.Title
+			}
+			subtitle.Format = s.Track.Codec
+			subtitle.Default = s.Track.Default
+			subtitle.Forced = s.Track.Forced
+		}
+
+		ret = append(ret, subtitle)
+	}
+
+	return ret, nil
+}
+
+func (r *sceneResolver) InteractiveAxes(ctx context.Context, obj *models.Scene) (ret []*models.FunscriptAxis, err error) {
+	primaryFile, err := r.getPrimaryFile(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+	if primaryFile == nil {
+		return []*models.FunscriptAxis{}, nil
+	}
+
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.File.GetFunscriptAxes(ctx, primaryFile.Base().ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	if ret == nil {
+		ret = []*models.FunscriptAxis{}
+	}
+
+	return ret, nil
+}
+
+func (r *sceneResolver) InteractiveStats(ctx context.Context, obj *models.Scene) (ret *models.FunscriptStats, err error) {
+	primaryFile, err := r.getPrimaryFile(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+	if primaryFile == nil || !primaryFile.Interactive {
+		return nil, nil
+	}
+
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.File.GetFunscriptStats(ctx, primaryFile.Base().ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *sceneResolver) InteractiveHeatmapData(ctx context.Context, obj *models.Scene, segments *int) (*manager.InteractiveHeatmapData, error) {
+	primaryFile, err := r.getPrimaryFile(ctx, obj)
+	if err != nil {
+		return nil, err
+	}
+	if primaryFile == nil || !primaryFile.Interactive {
+		return nil, nil
+	}
+
+	numSegments := config.GetInstance().GetInteractiveHeatmapSegments()
+	if segments != nil {
+		numSegments = *segments
+	}
+
+	return manager.LoadInteractiveHeatmapData(video.FindInteractiveScriptPath(primaryFile.Path), primaryFile.Duration, numSegments)
+}
+
 func (r *sceneResolver) Galleries(ctx context.Context, obj *models.Scene) (ret []*models.Gallery, err error) {
 	if !obj.GalleryIDs.Loaded() {
 		if err := r.withReadTxn(ctx, func(ctx context.Context) error {
@@ -302,6 +467,22 @@ func (r *sceneResolver) Tags(ctx context.Context, obj *models.Scene) (ret []*mod
 	return ret, firstError(errs)
 }
 
+func (r *sceneResolver) InheritedTags(ctx context.Context, obj *models.Scene, depth *int) (ret []*models.Tag, err error) {
+	d := -1
+	if depth != nil {
+		d = *depth
+	}
+
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.Tag.FindInheritedBySceneID(ctx, obj.ID, d)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
 func (r *sceneResolver) Performers(ctx context.Context, obj *models.Scene) (ret []*models.Performer, err error) {
 	if !obj.PerformerIDs.Loaded() {
 		if err := r.withReadTxn(ctx, func(ctx context.Context) error {
@@ -316,6 +497,21 @@ func (r *sceneResolver) Performers(ctx context.Context, obj *models.Scene) (ret
 	return ret, firstError(errs)
 }
 
+func (r *sceneResolver) Flags(ctx context.Context, obj *models.Scene) (ret []*models.SceneFlag, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.SceneFlag.FindBySceneID(ctx, obj.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *sceneResolver) CustomFields(ctx context.Context, obj *models.Scene) ([]*models.CustomFieldValue, error) {
+	return r.customFieldValues(ctx, models.CustomFieldObjectTypeScene, obj.ID)
+}
+
 func stashIDsSliceToPtrSlice(v []models.StashID) []*models.StashID {
 	ret := make([]*models.StashID, len(v))
 	for i, vv := range v {
@@ -336,6 +532,78 @@ func (r *sceneResolver) StashIds(ctx context.Context, obj *models.Scene) (ret []
 	return stashIDsSliceToPtrSlice(obj.StashIDs.List()), nil
 }
 
+func (r *sceneResolver) StashBoxDrafts(ctx context.Context, obj *models.Scene) (ret []*models.StashBoxDraft, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.StashBoxDraft.FindBySceneID(ctx, obj.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *sceneResolver) Versions(ctx context.Context, obj *models.Scene) (ret []*models.Scene, err error) {
+	var ids []int
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ids, err = scene.StackIDs(ctx, r.repository.SceneVersion, obj.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	ret, errs = loaders.From(ctx).SceneByID.LoadAll(ids)
+	return ret, firstError(errs)
+}
+
+func (r *sceneResolver) PrimaryVersion(ctx context.Context, obj *models.Scene) (*models.Scene, error) {
+	versions, err := r.Versions(ctx, obj)
+	if err != nil || len(versions) == 0 {
+		return nil, err
+	}
+
+	return versions[0], nil
+}
+
+func (r *sceneResolver) BestVersion(ctx context.Context, obj *models.Scene) (*models.Scene, error) {
+	versions, err := r.Versions(ctx, obj)
+	if err != nil || len(versions) == 0 {
+		return nil, err
+	}
+
+	for _, v := range versions {
+		if _, err := r.getPrimaryFile(ctx, v); err != nil {
+			return nil, err
+		}
+	}
+
+	return scene.BestVersion(versions), nil
+}
+
+// streamedScene returns the scene whose primary file is streamed when playing
+// the scene: the best version for the primary scene of a stack, otherwise the
+// scene itself. Must be called within a transaction.
+func (r *Resolver) streamedScene(ctx context.Context, s *models.Scene) (*models.Scene, error) {
+	ids, err := scene.StackIDs(ctx, r.repository.SceneVersion, s.ID)
+	if err != nil || len(ids) == 0 || ids[0] != s.ID {
+		return s, err
+	}
+
+	versions, err := r.repository.Scene.FindMany(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range versions {
+		if err := v.LoadPrimaryFile(ctx, r.repository.File); err != nil {
+			return nil, err
+		}
+	}
+
+	return scene.BestVersion(versions), nil
+}
+
 func (r *sceneResolver) Phash(ctx context.Context, obj *models.Scene) (*string, error) {
 	f, err := r.getPrimaryFile(ctx, obj)
 	if err != nil {
@@ -361,6 +629,15 @@ func (r *sceneResolver) Phash(ctx context.Context, obj *models.Scene) (*string,
 }
 
 func (r *sceneResolver) SceneStreams(ctx context.Context, obj *models.Scene) ([]*manager.SceneStreamEndpoint, error) {
+	// the primary scene of a stack plays the best version by default
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		var err error
+		obj, err = r.streamedScene(ctx, obj)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
 	// load the primary file into the scene
 	_, err := r.getPrimaryFile(ctx, obj)
 	if err != nil {
@@ -399,3 +676,7 @@ func (r *sceneResolver) InteractiveSpeed(ctx context.Context, obj *models.Scene)
 
 	return primaryFile.InteractiveSpeed, nil
 }
+
+func (r *sceneResolver) History(ctx context.Context, obj *models.Scene) ([]*models.EditHistoryEntry, error) {
+	return r.editHistory(ctx, models.EditHistoryObjectTypeScene, obj.ID)
+}
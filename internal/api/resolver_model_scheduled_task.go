@@ -0,0 +1,17 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/stashapp/stash/internal/manager"
+	"github.com/stashapp/stash/internal/manager/config"
+)
+
+func (r *scheduledTaskResolver) NextRun(ctx context.Context, obj *config.ScheduledTask) (*time.Time, error) {
+	return manager.NextScheduledRun(obj, time.Now()), nil
+}
+
+func (r *scrapeRefreshRuleResolver) NextRun(ctx context.Context, obj *config.ScrapeRefreshRule) (*time.Time, error) {
+	return manager.NextScrapeRefreshRun(obj, time.Now()), nil
+}
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/internal/api/loaders"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func (r *searchResultResolver) Scene(ctx context.Context, obj *models.SearchResult) (*models.Scene, error) {
+	if obj.Type != models.SearchResultTypeScene {
+		return nil, nil
+	}
+
+	return loaders.From(ctx).SceneByID.Load(obj.ID)
+}
+
+func (r *searchResultResolver) Performer(ctx context.Context, obj *models.SearchResult) (*models.Performer, error) {
+	if obj.Type != models.SearchResultTypePerformer {
+		return nil, nil
+	}
+
+	return loaders.From(ctx).PerformerByID.Load(obj.ID)
+}
+
+func (r *searchResultResolver) Tag(ctx context.Context, obj *models.SearchResult) (*models.Tag, error) {
+	if obj.Type != models.SearchResultTypeTag {
+		return nil, nil
+	}
+
+	return loaders.From(ctx).TagByID.Load(obj.ID)
+}
+
+func (r *searchResultResolver) SceneMarker(ctx context.Context, obj *models.SearchResult) (ret *models.SceneMarker, err error) {
+	if obj.Type != models.SearchResultTypeSceneMarker {
+		return nil, nil
+	}
+
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.SceneMarker.Find(ctx, obj.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,14 @@
+package api
+
+import (
+	"context"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func (r *stashBoxDraftResolver) URL(ctx context.Context, obj *models.StashBoxDraft) (string, error) {
+	// endpoints are the graphql URL of the stash-box instance
+	base := strings.TrimSuffix(obj.Endpoint, "graphql")
+	return base + "drafts/" + obj.DraftID, nil
+}
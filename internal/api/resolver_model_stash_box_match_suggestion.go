@@ -0,0 +1,29 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/stashapp/stash/internal/api/loaders"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scraper"
+)
+
+func (r *stashBoxMatchSuggestionResolver) ID(ctx context.Context, obj *models.StashBoxMatchSuggestion) (string, error) {
+	return strconv.Itoa(obj.ID), nil
+}
+
+func (r *stashBoxMatchSuggestionResolver) Scene(ctx context.Context, obj *models.StashBoxMatchSuggestion) (*models.Scene, error) {
+	return loaders.From(ctx).SceneByID.Load(obj.SceneID)
+}
+
+func (r *stashBoxMatchSuggestionResolver) Match(ctx context.Context, obj *models.StashBoxMatchSuggestion) (*scraper.ScrapedScene, error) {
+	var ret scraper.ScrapedScene
+	if err := json.Unmarshal(obj.Data, &ret); err != nil {
+		return nil, fmt.Errorf("decoding stash-box match suggestion %d: %w", obj.ID, err)
+	}
+
+	return &ret, nil
+}
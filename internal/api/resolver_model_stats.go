@@ -0,0 +1,55 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/stashapp/stash/internal/manager"
+	"github.com/stashapp/stash/internal/manager/config"
+)
+
+func (r *statsResultTypeResolver) pathStats(ctx context.Context) (ret []*manager.PathStats, err error) {
+	var paths []string
+	for _, s := range config.GetInstance().GetStashPaths() {
+		paths = append(paths, s.Path)
+	}
+
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = manager.GetPathStats(ctx, r.repository.File, paths, time.Now())
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *statsResultTypeResolver) Paths(ctx context.Context, obj *StatsResultType) ([]*manager.PathStats, error) {
+	return r.pathStats(ctx)
+}
+
+func (r *statsResultTypeResolver) Volumes(ctx context.Context, obj *StatsResultType) ([]*manager.VolumeStats, error) {
+	paths, err := r.pathStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return manager.GetVolumeStats(paths), nil
+}
+
+func (r *pathStatsResolver) Volume(ctx context.Context, obj *manager.PathStats) (*string, error) {
+	if obj.Volume == nil {
+		return nil, nil
+	}
+
+	return &obj.Volume.ID, nil
+}
+
+func (r *pathStatsResolver) FreeSpace(ctx context.Context, obj *manager.PathStats) (*float64, error) {
+	if obj.Volume == nil {
+		return nil, nil
+	}
+
+	ret := float64(obj.Volume.FreeSpace)
+	return &ret, nil
+}
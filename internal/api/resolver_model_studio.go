@@ -167,6 +167,10 @@ func (r *studioResolver) Movies(ctx context.Context, obj *models.Studio) (ret []
 	return ret, nil
 }
 
+func (r *studioResolver) CustomFields(ctx context.Context, obj *models.Studio) ([]*models.CustomFieldValue, error) {
+	return r.customFieldValues(ctx, models.CustomFieldObjectTypeStudio, obj.ID)
+}
+
 func (r *studioResolver) MovieCount(ctx context.Context, obj *models.Studio) (ret *int, err error) {
 	var res int
 	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
@@ -178,3 +182,7 @@ func (r *studioResolver) MovieCount(ctx context.Context, obj *models.Studio) (re
 
 	return &res, nil
 }
+
+func (r *studioResolver) History(ctx context.Context, obj *models.Studio) ([]*models.EditHistoryEntry, error) {
+	return r.editHistory(ctx, models.EditHistoryObjectTypeStudio, obj.ID)
+}
@@ -8,6 +8,8 @@ import (
 	"github.com/stashapp/stash/pkg/gallery"
 	"github.com/stashapp/stash/pkg/image"
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene"
+	"github.com/stashapp/stash/pkg/utils"
 )
 
 func (r *tagResolver) Description(ctx context.Context, obj *models.Tag) (*string, error) {
@@ -50,9 +52,14 @@ func (r *tagResolver) Aliases(ctx context.Context, obj *models.Tag) (ret []strin
 	return ret, err
 }
 
-func (r *tagResolver) SceneCount(ctx context.Context, obj *models.Tag) (ret *int, err error) {
+func (r *tagResolver) SceneCount(ctx context.Context, obj *models.Tag, includeMarkerTags *bool) (ret *int, err error) {
 	var count int
 	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		if utils.IsTrue(includeMarkerTags) {
+			count, err = scene.CountByTagID(ctx, r.repository.Scene, obj.ID, true)
+			return err
+		}
+
 		count, err = r.repository.Scene.CountByTagID(ctx, obj.ID)
 		return err
 	}); err != nil {
@@ -123,3 +130,7 @@ func (r *tagResolver) CreatedAt(ctx context.Context, obj *models.Tag) (*time.Tim
 func (r *tagResolver) UpdatedAt(ctx context.Context, obj *models.Tag) (*time.Time, error) {
 	return &obj.UpdatedAt.Timestamp, nil
 }
+
+func (r *tagResolver) History(ctx context.Context, obj *models.Tag) ([]*models.EditHistoryEntry, error) {
+	return r.editHistory(ctx, models.EditHistoryObjectTypeTag, obj.ID)
+}
@@ -0,0 +1,21 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/internal/api/loaders"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/tag"
+)
+
+func (r *tagMergePlanResolver) Parents(ctx context.Context, obj *tag.MergePlan) (ret []*models.Tag, err error) {
+	var errs []error
+	ret, errs = loaders.From(ctx).TagByID.LoadAll(obj.ParentIDs)
+	return ret, firstError(errs)
+}
+
+func (r *tagMergePlanResolver) Children(ctx context.Context, obj *tag.MergePlan) (ret []*models.Tag, err error) {
+	var errs []error
+	ret, errs = loaders.From(ctx).TagByID.LoadAll(obj.ChildIDs)
+	return ret, firstError(errs)
+}
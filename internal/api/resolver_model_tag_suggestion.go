@@ -0,0 +1,16 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/internal/api/loaders"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func (r *tagSuggestionResolver) Scene(ctx context.Context, obj *models.TagSuggestion) (*models.Scene, error) {
+	return loaders.From(ctx).SceneByID.Load(obj.SceneID)
+}
+
+func (r *tagSuggestionResolver) Tag(ctx context.Context, obj *models.TagSuggestion) (*models.Tag, error) {
+	return loaders.From(ctx).TagByID.Load(obj.TagID)
+}
@@ -0,0 +1,19 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func (r *trashedSceneResolver) ExpiresAt(ctx context.Context, obj *models.TrashedScene) (*time.Time, error) {
+	days := config.GetInstance().GetTrashRetentionDays()
+	if days <= 0 {
+		return nil, nil
+	}
+
+	ret := obj.CreatedAt.AddDate(0, 0, days)
+	return &ret, nil
+}
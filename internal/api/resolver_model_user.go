@@ -0,0 +1,44 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func (r *userResolver) HasAPIKey(ctx context.Context, obj *models.User) (bool, error) {
+	return obj.APIKeyHash != nil, nil
+}
+
+func (r *userResolver) ExternalIdentities(ctx context.Context, obj *models.User) ([]*models.UserExternalIdentity, error) {
+	var identities []models.UserExternalIdentity
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		var err error
+		identities, err = r.repository.User.GetExternalIdentities(ctx, obj.ID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	ret := make([]*models.UserExternalIdentity, len(identities))
+	for i := range identities {
+		ret[i] = &identities[i]
+	}
+
+	return ret, nil
+}
+
+func (r *userResolver) RestrictionProfile(ctx context.Context, obj *models.User) (ret *models.RestrictionProfile, err error) {
+	if obj.RestrictionProfileID == nil {
+		return nil, nil
+	}
+
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.RestrictionProfile.Find(ctx, *obj.RestrictionProfileID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/internal/api/urlbuilders"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+func (r *wantedSceneResolver) Phash(ctx context.Context, obj *models.WantedScene) (*string, error) {
+	if obj.Phash == nil || *obj.Phash == 0 {
+		return nil, nil
+	}
+
+	hexval := utils.PhashToString(*obj.Phash)
+	return &hexval, nil
+}
+
+func (r *wantedSceneResolver) Scene(ctx context.Context, obj *models.WantedScene) (ret *models.Scene, err error) {
+	if obj.SceneID == nil {
+		return nil, nil
+	}
+
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.Scene.Find(ctx, *obj.SceneID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *wantedSceneResolver) CoverImage(ctx context.Context, obj *models.WantedScene) (*string, error) {
+	// covers are only scraped from URLs
+	if obj.ScrapeStatus == nil || *obj.ScrapeStatus != models.WantedSceneScrapeStatusComplete {
+		return nil, nil
+	}
+
+	baseURL, _ := ctx.Value(BaseURLCtxKey).(string)
+	imagePath := urlbuilders.NewWantedSceneURLBuilder(baseURL, obj).GetCoverImageURL()
+	return &imagePath, nil
+}
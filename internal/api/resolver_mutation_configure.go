@@ -5,12 +5,16 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
+	"strings"
 
+	"github.com/lucasb-eyer/go-colorful"
 	"github.com/stashapp/stash/internal/manager"
 	"github.com/stashapp/stash/internal/manager/config"
 	"github.com/stashapp/stash/pkg/fsutil"
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/session"
+	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
 )
 
 var ErrOverriddenConfig = errors.New("cannot set overridden value")
@@ -30,6 +34,10 @@ func (r *mutationResolver) ConfigureGeneral(ctx context.Context, input ConfigGen
 
 	existingPaths := c.GetStashPaths()
 	if input.Stashes != nil {
+		if err := manager.ValidateStashPathPatterns(input.Stashes); err != nil {
+			return makeConfigGeneralResult(), err
+		}
+
 		for _, s := range input.Stashes {
 			// Only validate existence of new paths
 			isNew := true
@@ -186,6 +194,39 @@ func (r *mutationResolver) ConfigureGeneral(ctx context.Context, input ConfigGen
 		c.Set(config.MaxStreamingTranscodeSize, input.MaxStreamingTranscodeSize.String())
 	}
 
+	if input.TranscodeProfiles != nil || input.StreamTranscodeProfiles != nil || input.PreviewTranscodeProfile != nil {
+		// validate the resulting selection against the resulting profiles
+		transcodeProfiles := c.GetTranscodeProfiles()
+		if input.TranscodeProfiles != nil {
+			transcodeProfiles = input.TranscodeProfiles
+		}
+
+		streamTranscodeProfiles := c.GetStreamTranscodeProfiles()
+		if input.StreamTranscodeProfiles != nil {
+			streamTranscodeProfiles = input.StreamTranscodeProfiles
+		}
+
+		previewTranscodeProfile := c.GetPreviewTranscodeProfile()
+		if input.PreviewTranscodeProfile != nil {
+			previewTranscodeProfile = *input.PreviewTranscodeProfile
+		}
+
+		if err := c.ValidateTranscodeProfiles(transcodeProfiles, streamTranscodeProfiles, previewTranscodeProfile); err != nil {
+			return makeConfigGeneralResult(), err
+		}
+
+		c.Set(config.TranscodeProfiles, transcodeProfiles)
+		c.Set(config.StreamTranscodeProfiles, streamTranscodeProfiles)
+		c.Set(config.PreviewTranscodeProfile, previewTranscodeProfile)
+	}
+
+	if input.HlsVariants != nil {
+		if err := c.ValidateHLSVariants(input.HlsVariants); err != nil {
+			return makeConfigGeneralResult(), err
+		}
+		c.Set(config.HLSVariants, input.HlsVariants)
+	}
+
 	if input.WriteImageThumbnails != nil {
 		c.Set(config.WriteImageThumbnails, *input.WriteImageThumbnails)
 	}
@@ -208,6 +249,28 @@ func (r *mutationResolver) ConfigureGeneral(ctx context.Context, input ConfigGen
 		c.Set(config.MaxSessionAge, *input.MaxSessionAge)
 	}
 
+	if input.SessionLockPin != nil {
+		// as with the password, only set if different from the stored hash
+		if *input.SessionLockPin != c.GetSessionLockPinHash() {
+			c.SetSessionLockPin(*input.SessionLockPin)
+		}
+	}
+
+	if input.SessionLockTimeout != nil {
+		if *input.SessionLockTimeout < 0 {
+			return makeConfigGeneralResult(), errors.New("session lock timeout must not be negative")
+		}
+		c.Set(config.SessionLockTimeout, *input.SessionLockTimeout)
+	}
+
+	if input.QuickHideSafeTags != nil {
+		safeTags, err := stringslice.StringSliceToIntSlice(input.QuickHideSafeTags)
+		if err != nil {
+			return makeConfigGeneralResult(), fmt.Errorf("converting quick hide safe tags: %w", err)
+		}
+		c.Set(config.QuickHideSafeTags, safeTags)
+	}
+
 	if input.LogFile != nil {
 		c.Set(config.LogFile, input.LogFile)
 	}
@@ -280,6 +343,245 @@ func (r *mutationResolver) ConfigureGeneral(ctx context.Context, input ConfigGen
 		c.Set(config.PythonPath, input.PythonPath)
 	}
 
+	if input.WatchLibraryEnabled != nil {
+		c.Set(config.WatchLibraryEnabled, *input.WatchLibraryEnabled)
+	}
+
+	if input.WatchLibraryDebounce != nil {
+		if *input.WatchLibraryDebounce <= 0 {
+			return makeConfigGeneralResult(), errors.New("watch library debounce must be greater than zero")
+		}
+		c.Set(config.WatchLibraryDebounce, *input.WatchLibraryDebounce)
+	}
+
+	if input.ScheduledTasks != nil {
+		if err := manager.ValidateScheduledTasks(input.ScheduledTasks); err != nil {
+			return makeConfigGeneralResult(), err
+		}
+		c.Set(config.ScheduledTasks, input.ScheduledTasks)
+	}
+
+	if input.ScrapeRefreshRules != nil {
+		if err := manager.ValidateScrapeRefreshRules(input.ScrapeRefreshRules, c.GetStashBoxes()); err != nil {
+			return makeConfigGeneralResult(), err
+		}
+		c.Set(config.ScrapeRefreshRules, input.ScrapeRefreshRules)
+	}
+
+	if input.ResumeInterruptedJobs != nil {
+		c.Set(config.ResumeInterruptedJobs, *input.ResumeInterruptedJobs)
+	}
+
+	if input.DownloadHookEnabled != nil {
+		c.Set(config.DownloadHookEnabled, *input.DownloadHookEnabled)
+	}
+
+	if input.DownloadHookAutoTag != nil {
+		c.Set(config.DownloadHookAutoTag, *input.DownloadHookAutoTag)
+	}
+
+	if input.UploadInboxPath != nil {
+		inboxPath := *input.UploadInboxPath
+		if inboxPath != "" {
+			if !filepath.IsAbs(inboxPath) {
+				return makeConfigGeneralResult(), errors.New("upload inbox path must be absolute")
+			}
+
+			inLibrary := false
+			for _, s := range c.GetStashPaths() {
+				if fsutil.IsPathInDir(s.Path, inboxPath) {
+					inLibrary = true
+					break
+				}
+			}
+			if !inLibrary {
+				return makeConfigGeneralResult(), errors.New("upload inbox path must be inside a library path")
+			}
+		}
+		c.Set(config.UploadInboxPath, inboxPath)
+	}
+
+	if input.InboxEnabled != nil {
+		c.Set(config.InboxEnabled, *input.InboxEnabled)
+	}
+
+	if input.ActivityLogEnabled != nil {
+		c.Set(config.ActivityLogEnabled, *input.ActivityLogEnabled)
+	}
+
+	if input.ActivityLogRetentionDays != nil {
+		c.Set(config.ActivityLogRetentionDays, *input.ActivityLogRetentionDays)
+	}
+
+	if input.JobArtifactRetentionDays != nil {
+		if *input.JobArtifactRetentionDays < 0 {
+			return makeConfigGeneralResult(), errors.New("job artifact retention days must not be negative")
+		}
+		c.Set(config.JobArtifactRetentionDays, *input.JobArtifactRetentionDays)
+	}
+
+	if input.TrashPath != nil {
+		trashPath := *input.TrashPath
+		if trashPath != "" {
+			for _, s := range c.GetStashPaths() {
+				if fsutil.IsPathInDir(s.Path, trashPath) {
+					return makeConfigGeneralResult(), fmt.Errorf("trash path must not be inside library path %s", s.Path)
+				}
+			}
+		}
+		c.Set(config.TrashPath, trashPath)
+	}
+
+	if input.TrashRetentionDays != nil {
+		if *input.TrashRetentionDays < 0 {
+			return makeConfigGeneralResult(), errors.New("trash retention days must not be negative")
+		}
+		c.Set(config.TrashRetentionDays, *input.TrashRetentionDays)
+	}
+
+	if input.DeletedFilesPath != nil {
+		deletedFilesPath := *input.DeletedFilesPath
+		if deletedFilesPath != "" {
+			for _, s := range c.GetStashPaths() {
+				if fsutil.IsPathInDir(s.Path, deletedFilesPath) {
+					return makeConfigGeneralResult(), fmt.Errorf("deleted files path must not be inside library path %s", s.Path)
+				}
+			}
+		}
+		c.Set(config.DeletedFilesPath, deletedFilesPath)
+	}
+
+	if input.DeletedFilesPreserveStructure != nil {
+		c.Set(config.DeletedFilesPreserveStructure, *input.DeletedFilesPreserveStructure)
+	}
+
+	if input.DeletedFilesMaxSize != nil {
+		if *input.DeletedFilesMaxSize < 0 {
+			return makeConfigGeneralResult(), errors.New("deleted files max size must not be negative")
+		}
+		c.Set(config.DeletedFilesMaxSize, *input.DeletedFilesMaxSize)
+	}
+
+	if input.StashBoxFingerprintCacheDays != nil {
+		if *input.StashBoxFingerprintCacheDays < 0 {
+			return makeConfigGeneralResult(), errors.New("stash-box fingerprint cache days must not be negative")
+		}
+		c.Set(config.StashBoxFingerprintCacheDays, *input.StashBoxFingerprintCacheDays)
+	}
+
+	if input.RenameTemplate != nil {
+		if err := manager.ValidateRenameTemplate(*input.RenameTemplate); err != nil {
+			return makeConfigGeneralResult(), fmt.Errorf("invalid rename template: %w", err)
+		}
+		c.Set(config.RenameTemplate, *input.RenameTemplate)
+	}
+
+	if input.MediaAllowedSubnets != nil {
+		if _, err := session.ParseSubnets(input.MediaAllowedSubnets); err != nil {
+			return makeConfigGeneralResult(), err
+		}
+		c.Set(config.MediaAllowedSubnets, input.MediaAllowedSubnets)
+	}
+
+	if input.MediaAccessToken != nil {
+		c.Set(config.MediaAccessToken, *input.MediaAccessToken)
+	}
+
+	if input.InteractiveHeatmapRenderAxes != nil {
+		c.Set(config.InteractiveHeatmapRenderAxes, *input.InteractiveHeatmapRenderAxes)
+	}
+
+	if input.InteractiveHeatmapSprite != nil {
+		c.Set(config.InteractiveHeatmapSprite, *input.InteractiveHeatmapSprite)
+	}
+
+	if input.InteractiveHeatmapWidth != nil {
+		if *input.InteractiveHeatmapWidth <= 0 {
+			return makeConfigGeneralResult(), fmt.Errorf("interactive heatmap width must be positive")
+		}
+		c.Set(config.InteractiveHeatmapWidth, *input.InteractiveHeatmapWidth)
+	}
+
+	if input.InteractiveHeatmapHeight != nil {
+		if *input.InteractiveHeatmapHeight <= 0 {
+			return makeConfigGeneralResult(), fmt.Errorf("interactive heatmap height must be positive")
+		}
+		c.Set(config.InteractiveHeatmapHeight, *input.InteractiveHeatmapHeight)
+	}
+
+	if input.InteractiveHeatmapSegments != nil {
+		if *input.InteractiveHeatmapSegments < 2 {
+			return makeConfigGeneralResult(), fmt.Errorf("interactive heatmap segments must be at least 2")
+		}
+		c.Set(config.InteractiveHeatmapSegments, *input.InteractiveHeatmapSegments)
+	}
+
+	if input.InteractiveHeatmapColormap != nil || input.InteractiveHeatmapColormapStops != nil {
+		colormap := c.GetInteractiveHeatmapColormap()
+		if input.InteractiveHeatmapColormap != nil {
+			colormap = *input.InteractiveHeatmapColormap
+		}
+
+		stops := c.GetInteractiveHeatmapColormapStops()
+		if input.InteractiveHeatmapColormapStops != nil {
+			stops = input.InteractiveHeatmapColormapStops
+		}
+
+		if _, err := manager.ParseHeatmapColormap(colormap, stops); err != nil {
+			return makeConfigGeneralResult(), err
+		}
+
+		c.Set(config.InteractiveHeatmapColormap, colormap)
+		c.Set(config.InteractiveHeatmapColormapStops, stops)
+	}
+
+	if input.InteractiveHeatmapBackgroundColor != nil {
+		if _, err := colorful.Hex(*input.InteractiveHeatmapBackgroundColor); err != nil {
+			return makeConfigGeneralResult(), fmt.Errorf("invalid interactive heatmap background color: %w", err)
+		}
+		c.Set(config.InteractiveHeatmapBackgroundColor, *input.InteractiveHeatmapBackgroundColor)
+	}
+
+	if input.BulkUndoWindow != nil {
+		if *input.BulkUndoWindow < 0 {
+			return makeConfigGeneralResult(), errors.New("bulk undo window must not be negative")
+		}
+		c.Set(config.BulkUndoWindow, *input.BulkUndoWindow)
+	}
+
+	if input.AutoBackup != nil {
+		c.Set(config.AutoBackup, *input.AutoBackup)
+	}
+
+	if input.AutoBackupMaxDatabaseSize != nil {
+		if *input.AutoBackupMaxDatabaseSize < 0 {
+			return makeConfigGeneralResult(), errors.New("auto backup max database size must not be negative")
+		}
+		c.Set(config.AutoBackupMaxDatabaseSize, *input.AutoBackupMaxDatabaseSize)
+	}
+
+	if input.LowMemoryMode != nil {
+		c.Set(config.LowMemoryMode, *input.LowMemoryMode)
+	}
+
+	if input.InteractiveMarkerTag != nil {
+		if strings.TrimSpace(*input.InteractiveMarkerTag) == "" {
+			return makeConfigGeneralResult(), errors.New("interactive marker tag must not be empty")
+		}
+		c.Set(config.InteractiveMarkerTag, strings.TrimSpace(*input.InteractiveMarkerTag))
+	}
+
+	if input.ImportChapters != nil {
+		c.Set(config.ImportChapters, *input.ImportChapters)
+	}
+
+	if input.ChapterMarkerTag != nil {
+		if strings.TrimSpace(*input.ChapterMarkerTag) == "" {
+			return makeConfigGeneralResult(), errors.New("chapter marker tag must not be empty")
+		}
+		c.Set(config.ChapterMarkerTag, strings.TrimSpace(*input.ChapterMarkerTag))
+	}
+
 	if err := c.Write(); err != nil {
 		return makeConfigGeneralResult(), err
 	}
@@ -458,6 +760,13 @@ func (r *mutationResolver) ConfigureScraping(ctx context.Context, input ConfigSc
 		c.Set(config.ScraperCertCheck, input.ScraperCertCheck)
 	}
 
+	if input.ScraperCacheDays != nil {
+		if *input.ScraperCacheDays < 0 {
+			return makeConfigScrapingResult(), errors.New("scraper cache days must not be negative")
+		}
+		c.Set(config.ScraperCacheDays, *input.ScraperCacheDays)
+	}
+
 	if refreshScraperCache {
 		manager.GetInstance().RefreshScraperCache()
 	}
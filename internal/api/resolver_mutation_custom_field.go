@@ -0,0 +1,212 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
+)
+
+// ensureCustomFieldNameUnique returns an error if a field of the object type
+// other than the one with the provided id already uses the name. Names are
+// compared case insensitively.
+func ensureCustomFieldNameUnique(ctx context.Context, qb models.CustomFieldReader, objectType models.CustomFieldObjectType, id int, name string) error {
+	existing, err := qb.FindByName(ctx, objectType, name)
+	if err != nil {
+		return err
+	}
+
+	if existing != nil && existing.ID != id {
+		return fmt.Errorf("%s custom field with name %q already exists", strings.ToLower(objectType.String()), existing.Name)
+	}
+
+	return nil
+}
+
+func customFieldName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", errors.New("name must not be empty")
+	}
+
+	return name, nil
+}
+
+// customFieldOptions returns the trimmed, deduplicated options of a field of
+// the type. Only enum fields have options, which are required.
+func customFieldOptions(fieldType models.CustomFieldType, options []string) ([]string, error) {
+	if fieldType != models.CustomFieldTypeEnum {
+		if len(options) > 0 {
+			return nil, fmt.Errorf("options are only supported by %s fields", models.CustomFieldTypeEnum)
+		}
+		return nil, nil
+	}
+
+	var ret []string
+	for _, o := range options {
+		o = strings.TrimSpace(o)
+		if o != "" {
+			ret = stringslice.StrAppendUnique(ret, o)
+		}
+	}
+
+	if len(ret) == 0 {
+		return nil, fmt.Errorf("%s fields require options", models.CustomFieldTypeEnum)
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) CustomFieldCreate(ctx context.Context, input CustomFieldCreateInput) (ret *models.CustomField, err error) {
+	name, err := customFieldName(input.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	options, err := customFieldOptions(input.Type, input.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	newField := models.CustomField{
+		ObjectType: input.ObjectType,
+		Name:       name,
+		Type:       input.Type,
+		Options:    options,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		qb := r.repository.CustomField
+
+		if err := ensureCustomFieldNameUnique(ctx, qb, input.ObjectType, 0, name); err != nil {
+			return err
+		}
+
+		ret, err = qb.Create(ctx, newField)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) CustomFieldUpdate(ctx context.Context, input CustomFieldUpdateInput) (ret *models.CustomField, err error) {
+	id, err := strconv.Atoi(input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		qb := r.repository.CustomField
+
+		existing, err := qb.Find(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if existing == nil {
+			return fmt.Errorf("custom field with id %d not found", id)
+		}
+
+		updated := *existing
+
+		if input.Name != nil {
+			updated.Name, err = customFieldName(*input.Name)
+			if err != nil {
+				return err
+			}
+
+			if err := ensureCustomFieldNameUnique(ctx, qb, existing.ObjectType, id, updated.Name); err != nil {
+				return err
+			}
+		}
+
+		if input.Options != nil {
+			updated.Options, err = customFieldOptions(existing.Type, input.Options)
+			if err != nil {
+				return err
+			}
+		}
+
+		updated.UpdatedAt = time.Now()
+
+		ret, err = qb.Update(ctx, updated)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) CustomFieldDestroy(ctx context.Context, id string) (bool, error) {
+	idInt, err := strconv.Atoi(id)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		return r.repository.CustomField.Destroy(ctx, idInt)
+	}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *mutationResolver) CustomFieldValuesSet(ctx context.Context, input CustomFieldValuesSetInput) (bool, error) {
+	ids, err := stringslice.StringSliceToIntSlice(input.Ids)
+	if err != nil {
+		return false, fmt.Errorf("converting ids: %w", err)
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		qb := r.repository.CustomField
+
+		values := make([]models.CustomFieldValue, len(input.Values))
+		for i, v := range input.Values {
+			fieldID, err := strconv.Atoi(v.FieldID)
+			if err != nil {
+				return fmt.Errorf("converting field id: %w", err)
+			}
+
+			field, err := qb.Find(ctx, fieldID)
+			if err != nil {
+				return err
+			}
+
+			if field == nil || field.ObjectType != input.ObjectType {
+				return fmt.Errorf("%s custom field with id %d not found", strings.ToLower(input.ObjectType.String()), fieldID)
+			}
+
+			values[i].FieldID = fieldID
+			if v.Value != nil {
+				values[i].Value, err = field.ParseValue(v.Value)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, id := range ids {
+			if err := qb.SetValues(ctx, input.ObjectType, id, values); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
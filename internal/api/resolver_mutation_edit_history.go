@@ -0,0 +1,84 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
+)
+
+func (r *mutationResolver) EditHistoryRevert(ctx context.Context, ids []string) (bool, error) {
+	entryIDs, err := stringslice.StringSliceToIntSlice(ids)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		qb := r.repository.EditHistory
+
+		var entries []*models.EditHistoryEntry
+		for _, id := range entryIDs {
+			e, err := qb.Find(ctx, id)
+			if err != nil {
+				return err
+			}
+			if e == nil {
+				return fmt.Errorf("edit history entry with id %d not found", id)
+			}
+			entries = append(entries, e)
+		}
+
+		// revert the newest entries first, so that the oldest values are
+		// restored when multiple entries change the same field
+		sort.Slice(entries, func(i, j int) bool {
+			if entries[i].CreatedAt.Equal(entries[j].CreatedAt) {
+				return entries[i].ID > entries[j].ID
+			}
+			return entries[i].CreatedAt.After(entries[j].CreatedAt)
+		})
+
+		for _, e := range entries {
+			before, err := loadEditHistoryState(ctx, r.repository, e.ObjectType, e.ObjectID)
+			if err != nil {
+				return err
+			}
+			if before == nil {
+				return fmt.Errorf("%s with id %d not found", e.ObjectType, e.ObjectID)
+			}
+
+			after, err := loadEditHistoryState(ctx, r.repository, e.ObjectType, e.ObjectID)
+			if err != nil {
+				return err
+			}
+
+			if err := models.RevertFields(after, e.Changes); err != nil {
+				return fmt.Errorf("reverting edit history entry %d: %w", e.ID, err)
+			}
+
+			if err := after.apply(ctx, r.repository, e.ObjectID); err != nil {
+				return fmt.Errorf("reverting edit history entry %d: %w", e.ID, err)
+			}
+
+			reverted, err := loadEditHistoryState(ctx, r.repository, e.ObjectType, e.ObjectID)
+			if err != nil {
+				return err
+			}
+
+			if err := recordEditHistory(ctx, r.repository, models.EditHistoryEntry{
+				ObjectType: e.ObjectType,
+				ObjectID:   e.ObjectID,
+				Action:     editHistoryActionRevert,
+			}, before, reverted); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -2,9 +2,15 @@ package api
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
 
+	"github.com/stashapp/stash/internal/manager"
 	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/fsutil"
 	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
 )
 
@@ -14,7 +20,7 @@ func (r *mutationResolver) DeleteFiles(ctx context.Context, ids []string) (ret b
 		return false, err
 	}
 
-	fileDeleter := file.NewDeleter()
+	fileDeleter := manager.NewFileDeleter()
 	destroyer := &file.ZipDestroyer{
 		FileDestroyer:   r.repository.File,
 		FolderDestroyer: r.repository.Folder,
@@ -72,3 +78,128 @@ func (r *mutationResolver) DeleteFiles(ctx context.Context, ids []string) (ret b
 
 	return true, nil
 }
+
+func (r *mutationResolver) MoveFiles(ctx context.Context, input MoveFilesInput) (bool, error) {
+	fileIDs, err := stringslice.StringSliceToIntSlice(input.Ids)
+	if err != nil {
+		return false, err
+	}
+
+	var destFolder, renamePattern string
+	if input.DestinationFolder != nil {
+		destFolder = strings.TrimSpace(*input.DestinationFolder)
+		if !filepath.IsAbs(destFolder) {
+			return false, fmt.Errorf("destination folder %q must be an absolute path", destFolder)
+		}
+		destFolder = filepath.Clean(destFolder)
+	}
+	if input.RenamePattern != nil {
+		renamePattern = strings.TrimSpace(*input.RenamePattern)
+	}
+	if destFolder == "" && renamePattern == "" {
+		return false, errors.New("destination folder or rename pattern must be set")
+	}
+
+	mover := file.NewMover(r.repository.File, r.repository.Folder)
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		for _, fileIDInt := range fileIDs {
+			fileID := file.ID(fileIDInt)
+			f, err := r.repository.File.Find(ctx, fileID)
+			if err != nil {
+				return err
+			}
+			if len(f) == 0 {
+				return fmt.Errorf("file with id %d not found", fileIDInt)
+			}
+
+			base := f[0].Base()
+			folder := destFolder
+			if folder == "" {
+				folder = filepath.Dir(base.Path)
+			}
+
+			dest := filepath.Join(folder, base.Basename)
+			if renamePattern != "" {
+				values, err := r.moveFileTokenValues(ctx, base)
+				if err != nil {
+					return err
+				}
+
+				rel, err := file.ExpandRenamePattern(renamePattern, values)
+				if err != nil {
+					return err
+				}
+				dest = filepath.Join(folder, rel) + filepath.Ext(base.Basename)
+			}
+
+			if !isInStashPath(dest) {
+				return fmt.Errorf("destination %q is not in a library path", dest)
+			}
+
+			if err := mover.Move(ctx, f[0], dest); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		mover.Rollback()
+		return false, err
+	}
+
+	mover.Commit()
+
+	return true, nil
+}
+
+// moveFileTokenValues returns the rename pattern token values of the file,
+// using the scene of the file if it has one.
+func (r *mutationResolver) moveFileTokenValues(ctx context.Context, f *file.BaseFile) (map[string]string, error) {
+	basename := strings.TrimSuffix(f.Basename, filepath.Ext(f.Basename))
+	ret := map[string]string{
+		"basename": basename,
+		"title":    basename,
+		"date":     "",
+		"year":     "",
+		"studio":   "",
+	}
+
+	scenes, err := r.repository.Scene.FindByFileID(ctx, f.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(scenes) == 0 {
+		return ret, nil
+	}
+
+	s := scenes[0]
+	if s.Title != "" {
+		ret["title"] = s.Title
+	}
+	if s.Date != nil {
+		ret["date"] = s.Date.String()
+		ret["year"] = strconv.Itoa(s.Date.Year())
+	}
+	if s.StudioID != nil {
+		studio, err := r.repository.Studio.Find(ctx, *s.StudioID)
+		if err != nil {
+			return nil, err
+		}
+		if studio != nil {
+			ret["studio"] = studio.Name.String
+		}
+	}
+
+	return ret, nil
+}
+
+func isInStashPath(p string) bool {
+	for _, s := range manager.GetInstance().Config.GetStashPaths() {
+		if fsutil.IsPathInDir(s.Path, p) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,253 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+
+	"github.com/stashapp/stash/internal/manager"
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/file/video"
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/funscript"
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+// getSceneFunscript returns the primary file of the scene, the path of its
+// interactive script and the contents of the script in the funscript
+// format. CSV scripts are converted to the funscript format.
+func (r *mutationResolver) getSceneFunscript(ctx context.Context, sceneID string) (*file.VideoFile, string, []byte, error) {
+	id, err := strconv.Atoi(sceneID)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	var f *file.VideoFile
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		s, err := r.repository.Scene.Find(ctx, id)
+		if err != nil {
+			return err
+		}
+		if s == nil {
+			return fmt.Errorf("scene with id %d not found", id)
+		}
+
+		if err := s.LoadPrimaryFile(ctx, r.repository.File); err != nil {
+			return err
+		}
+
+		f = s.Files.Primary()
+		return nil
+	}); err != nil {
+		return nil, "", nil, err
+	}
+
+	if f == nil || !f.Interactive {
+		return nil, "", nil, fmt.Errorf("scene %d is not interactive", id)
+	}
+
+	path := video.FindInteractiveScriptPath(f.Path)
+	if manager.IsCSVScriptPath(path) {
+		script, err := manager.ReadInteractiveScript(path)
+		if err != nil {
+			return nil, "", nil, err
+		}
+
+		data, err := script.MarshalFunscript()
+		if err != nil {
+			return nil, "", nil, fmt.Errorf("converting CSV script: %w", err)
+		}
+
+		return f, path, data, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("reading funscript: %w", err)
+	}
+
+	return f, path, data, nil
+}
+
+// getEditableSceneFunscript returns the primary file of the scene and the
+// contents of its funscript. CSV scripts cannot be edited, since editing
+// would lose the details of the CSV format.
+func (r *mutationResolver) getEditableSceneFunscript(ctx context.Context, sceneID string) (*file.VideoFile, []byte, error) {
+	f, path, data, err := r.getSceneFunscript(ctx, sceneID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if manager.IsCSVScriptPath(path) {
+		return nil, nil, fmt.Errorf("CSV scripts cannot be edited: %s", path)
+	}
+
+	return f, data, nil
+}
+
+func funscriptDurationMilli(f *file.VideoFile) int64 {
+	return int64(f.Duration * 1000)
+}
+
+func (r *mutationResolver) ValidateFunscript(ctx context.Context, sceneID string) (*funscript.Report, error) {
+	f, _, data, err := r.getSceneFunscript(ctx, sceneID)
+	if err != nil {
+		return nil, err
+	}
+
+	return funscript.Validate(data, funscriptDurationMilli(f))
+}
+
+func (r *mutationResolver) RepairFunscript(ctx context.Context, sceneID string) (*FunscriptRepairResult, error) {
+	f, _, data, err := r.getSceneFunscript(ctx, sceneID)
+	if err != nil {
+		return nil, err
+	}
+
+	repaired, report, err := funscript.Repair(data, funscriptDurationMilli(f))
+	if err != nil {
+		return nil, err
+	}
+
+	fn := video.GetRepairedFunscriptPath(f.Path)
+	if err := fsutil.WriteFile(fn, repaired); err != nil {
+		return nil, fmt.Errorf("writing repaired funscript: %w", err)
+	}
+
+	return &FunscriptRepairResult{
+		Path:   fn,
+		Report: report,
+	}, nil
+}
+
+// funscriptEdit is the edited contents of a funscript file.
+type funscriptEdit struct {
+	path       string
+	backupPath string
+	original   []byte
+	edited     []byte
+}
+
+// editFunscriptAxes applies fn to the secondary axis scripts of the file
+// that exist, so that they remain in sync with the main funscript.
+func editFunscriptAxes(path string, fn func(data []byte) ([]byte, error)) ([]funscriptEdit, error) {
+	var ret []funscriptEdit
+	for _, axis := range video.FunscriptAxes {
+		axisPath := video.GetFunscriptAxisPath(path, axis)
+		data, err := os.ReadFile(axisPath)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading %s funscript: %w", axis, err)
+		}
+
+		edited, err := fn(data)
+		if err != nil {
+			return nil, fmt.Errorf("editing %s funscript: %w", axis, err)
+		}
+
+		ret = append(ret, funscriptEdit{
+			path:       axisPath,
+			backupPath: axisPath + ".bak",
+			original:   data,
+			edited:     edited,
+		})
+	}
+
+	return ret, nil
+}
+
+// writeFunscriptEdits writes the edited funscripts, after backing up the
+// originals.
+func writeFunscriptEdits(edits []funscriptEdit) error {
+	for _, e := range edits {
+		if err := fsutil.WriteFile(e.backupPath, e.original); err != nil {
+			return fmt.Errorf("writing funscript backup: %w", err)
+		}
+
+		if err := fsutil.WriteFile(e.path, e.edited); err != nil {
+			return fmt.Errorf("writing funscript: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// writeSceneFunscript replaces the funscript of the file and any of the
+// provided secondary axis scripts with the edited data, after backing up the
+// originals, and queues the regeneration of the interactive heatmap.
+func (r *mutationResolver) writeSceneFunscript(ctx context.Context, sceneID string, f *file.VideoFile, original []byte, data []byte, axes []funscriptEdit) error {
+	edits := append([]funscriptEdit{{
+		path:       video.GetFunscriptPath(f.Path),
+		backupPath: video.GetFunscriptBackupPath(f.Path),
+		original:   original,
+		edited:     data,
+	}}, axes...)
+
+	if err := writeFunscriptEdits(edits); err != nil {
+		return err
+	}
+
+	overwrite := true
+	if _, err := manager.GetInstance().Generate(ctx, manager.GenerateMetadataInput{
+		InteractiveHeatmapsSpeeds: &overwrite,
+		SceneIDs:                  []string{sceneID},
+		Overwrite:                 &overwrite,
+	}); err != nil {
+		logger.Warnf("could not regenerate interactive heatmap for scene %s: %v", sceneID, err)
+	}
+
+	return nil
+}
+
+func (r *mutationResolver) ShiftFunscript(ctx context.Context, sceneID string, offset int) (bool, error) {
+	f, data, err := r.getEditableSceneFunscript(ctx, sceneID)
+	if err != nil {
+		return false, err
+	}
+
+	shift := func(data []byte) ([]byte, error) {
+		return funscript.Shift(data, int64(offset))
+	}
+
+	shifted, err := shift(data)
+	if err != nil {
+		return false, err
+	}
+
+	// shift the secondary axes so that they stay in sync
+	axes, err := editFunscriptAxes(f.Path, shift)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.writeSceneFunscript(ctx, sceneID, f, data, shifted, axes); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// ScaleFunscript scales the positions of the main funscript only. The
+// positions of the secondary axes are independent of the main axis.
+func (r *mutationResolver) ScaleFunscript(ctx context.Context, sceneID string, min int, max int) (bool, error) {
+	f, data, err := r.getEditableSceneFunscript(ctx, sceneID)
+	if err != nil {
+		return false, err
+	}
+
+	scaled, err := funscript.Scale(data, min, max)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.writeSceneFunscript(ctx, sceneID, f, data, scaled, nil); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
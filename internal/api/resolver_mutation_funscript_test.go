@@ -0,0 +1,53 @@
+package api
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stashapp/stash/pkg/file/video"
+	"github.com/stashapp/stash/pkg/funscript"
+)
+
+func TestEditFunscriptAxes(t *testing.T) {
+	dir := t.TempDir()
+	videoPath := filepath.Join(dir, "scene.mp4")
+
+	rollPath := video.GetFunscriptAxisPath(videoPath, "roll")
+	roll := []byte(`{"actions":[{"at":100,"pos":10},{"at":200,"pos":90}]}`)
+	if err := os.WriteFile(rollPath, roll, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	shift := func(data []byte) ([]byte, error) {
+		return funscript.Shift(data, 50)
+	}
+
+	edits, err := editFunscriptAxes(videoPath, shift)
+	if !assert.NoError(t, err) || !assert.Len(t, edits, 1) {
+		return
+	}
+
+	want, _ := shift(roll)
+	assert.Equal(t, rollPath, edits[0].path)
+	assert.Equal(t, roll, edits[0].original)
+	assert.Equal(t, want, edits[0].edited)
+
+	if !assert.NoError(t, writeFunscriptEdits(edits)) {
+		return
+	}
+
+	got, _ := os.ReadFile(rollPath)
+	assert.Equal(t, want, got)
+	backup, _ := os.ReadFile(rollPath + ".bak")
+	assert.Equal(t, roll, backup)
+
+	// invalid axis scripts fail the edit
+	if err := os.WriteFile(video.GetFunscriptAxisPath(videoPath, "pitch"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err = editFunscriptAxes(videoPath, shift)
+	assert.Error(t, err)
+}
@@ -63,6 +63,15 @@ func (r *mutationResolver) GalleryCreate(ctx context.Context, input GalleryCreat
 	if input.Details != nil {
 		newGallery.Details = *input.Details
 	}
+	if input.Location != nil {
+		newGallery.Location = *input.Location
+	}
+
+	if err := models.ValidateCoordinates(input.Latitude, input.Longitude); err != nil {
+		return nil, err
+	}
+	newGallery.Latitude = input.Latitude
+	newGallery.Longitude = input.Longitude
 
 	if input.Date != nil {
 		d := models.NewDate(*input.Date)
@@ -199,6 +208,12 @@ func (r *mutationResolver) galleryUpdate(ctx context.Context, input models.Galle
 		return nil, fmt.Errorf("converting studio id: %w", err)
 	}
 	updatedGallery.Organized = translator.optionalBool(input.Organized, "organized")
+	updatedGallery.Location = translator.optionalString(input.Location, "location")
+	updatedGallery.Latitude = translator.optionalFloat64(input.Latitude, "latitude")
+	updatedGallery.Longitude = translator.optionalFloat64(input.Longitude, "longitude")
+	if err := models.ValidateCoordinates(updatedGallery.Latitude.Ptr(), updatedGallery.Longitude.Ptr()); err != nil {
+		return nil, err
+	}
 
 	if input.PrimaryFileID != nil {
 		primaryFileID, err := strconv.Atoi(*input.PrimaryFileID)
@@ -258,6 +273,11 @@ func (r *mutationResolver) galleryUpdate(ctx context.Context, input models.Galle
 }
 
 func (r *mutationResolver) BulkGalleryUpdate(ctx context.Context, input BulkGalleryUpdateInput) ([]*models.Gallery, error) {
+	galleryIDs, err := stringslice.StringSliceToIntSlice(input.Ids)
+	if err != nil {
+		return nil, err
+	}
+
 	// Populate gallery from the input
 	translator := changesetTranslator{
 		inputMap: getUpdateInputMap(ctx),
@@ -269,12 +289,17 @@ func (r *mutationResolver) BulkGalleryUpdate(ctx context.Context, input BulkGall
 	updatedGallery.URL = translator.optionalString(input.URL, "url")
 	updatedGallery.Date = translator.optionalDate(input.Date, "date")
 	updatedGallery.Rating = translator.ratingConversionOptional(input.Rating, input.Rating100)
-	var err error
 	updatedGallery.StudioID, err = translator.optionalIntFromString(input.StudioID, "studio_id")
 	if err != nil {
 		return nil, fmt.Errorf("converting studio id: %w", err)
 	}
 	updatedGallery.Organized = translator.optionalBool(input.Organized, "organized")
+	updatedGallery.Location = translator.optionalString(input.Location, "location")
+	updatedGallery.Latitude = translator.optionalFloat64(input.Latitude, "latitude")
+	updatedGallery.Longitude = translator.optionalFloat64(input.Longitude, "longitude")
+	if err := models.ValidateCoordinates(updatedGallery.Latitude.Ptr(), updatedGallery.Longitude.Ptr()); err != nil {
+		return nil, err
+	}
 
 	if translator.hasField("performer_ids") {
 		updatedGallery.PerformerIDs, err = translateUpdateIDs(input.PerformerIds.Ids, input.PerformerIds.Mode)
@@ -297,14 +322,25 @@ func (r *mutationResolver) BulkGalleryUpdate(ctx context.Context, input BulkGall
 		}
 	}
 
-	ret := []*models.Gallery{}
+	var ret []*models.Gallery
+	var bulkErrs *bulkUpdateErrors
 
 	// Start the transaction and save the galleries
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
 		qb := r.repository.Gallery
+		ret = []*models.Gallery{}
+		bulkErrs = newBulkUpdateErrors("gallery")
+
+		for _, galleryID := range galleryIDs {
+			existing, err := qb.Find(ctx, galleryID)
+			if err != nil {
+				return err
+			}
 
-		for _, galleryIDStr := range input.Ids {
-			galleryID, _ := strconv.Atoi(galleryIDStr)
+			if existing == nil {
+				bulkErrs.notFound(galleryID)
+				continue
+			}
 
 			gallery, err := qb.UpdatePartial(ctx, galleryID, updatedGallery)
 			if err != nil {
@@ -319,8 +355,10 @@ func (r *mutationResolver) BulkGalleryUpdate(ctx context.Context, input BulkGall
 		return nil, err
 	}
 
+	bulkErrs.report(ctx)
+
 	// execute post hooks outside of txn
-	var newRet []*models.Gallery
+	newRet := []*models.Gallery{}
 	for _, gallery := range ret {
 		r.hookExecutor.ExecutePostHooks(ctx, gallery.ID, plugin.GalleryUpdatePost, input, translator.getFields())
 
@@ -345,10 +383,14 @@ func (r *mutationResolver) GalleryDestroy(ctx context.Context, input models.Gall
 		return false, err
 	}
 
+	if err := autoBackup(ctx, "destroying galleries", r.galleryBackupRows(galleryIDs)); err != nil {
+		return false, err
+	}
+
 	var galleries []*models.Gallery
 	var imgsDestroyed []*models.Image
 	fileDeleter := &image.FileDeleter{
-		Deleter: file.NewDeleter(),
+		Deleter: manager.NewFileDeleter(),
 		Paths:   manager.GetInstance().Paths,
 	}
 
@@ -2,11 +2,13 @@ package api
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 
 	"github.com/stashapp/stash/internal/manager"
 	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/gallery"
 	"github.com/stashapp/stash/pkg/image"
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/plugin"
@@ -111,6 +113,13 @@ func (r *mutationResolver) imageUpdate(ctx context.Context, input ImageUpdateInp
 		return nil, fmt.Errorf("converting studio id: %w", err)
 	}
 	updatedImage.Organized = translator.optionalBool(input.Organized, "organized")
+	updatedImage.Hidden = translator.optionalBool(input.Hidden, "hidden")
+	updatedImage.Location = translator.optionalString(input.Location, "location")
+	updatedImage.Latitude = translator.optionalFloat64(input.Latitude, "latitude")
+	updatedImage.Longitude = translator.optionalFloat64(input.Longitude, "longitude")
+	if err := models.ValidateCoordinates(updatedImage.Latitude.Ptr(), updatedImage.Longitude.Ptr()); err != nil {
+		return nil, err
+	}
 
 	if input.PrimaryFileID != nil {
 		primaryFileID, err := strconv.Atoi(*input.PrimaryFileID)
@@ -199,6 +208,13 @@ func (r *mutationResolver) BulkImageUpdate(ctx context.Context, input BulkImageU
 		return nil, fmt.Errorf("converting studio id: %w", err)
 	}
 	updatedImage.Organized = translator.optionalBool(input.Organized, "organized")
+	updatedImage.Hidden = translator.optionalBool(input.Hidden, "hidden")
+	updatedImage.Location = translator.optionalString(input.Location, "location")
+	updatedImage.Latitude = translator.optionalFloat64(input.Latitude, "latitude")
+	updatedImage.Longitude = translator.optionalFloat64(input.Longitude, "longitude")
+	if err := models.ValidateCoordinates(updatedImage.Latitude.Ptr(), updatedImage.Longitude.Ptr()); err != nil {
+		return nil, err
+	}
 
 	if translator.hasField("gallery_ids") {
 		updatedImage.GalleryIDs, err = translateUpdateIDs(input.GalleryIds.Ids, input.GalleryIds.Mode)
@@ -221,9 +237,13 @@ func (r *mutationResolver) BulkImageUpdate(ctx context.Context, input BulkImageU
 		}
 	}
 
+	var bulkErrs *bulkUpdateErrors
+
 	// Start the transaction and save the image marker
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
 		qb := r.repository.Image
+		ret = nil
+		bulkErrs = newBulkUpdateErrors("image")
 
 		for _, imageID := range imageIDs {
 			i, err := r.repository.Image.Find(ctx, imageID)
@@ -232,7 +252,8 @@ func (r *mutationResolver) BulkImageUpdate(ctx context.Context, input BulkImageU
 			}
 
 			if i == nil {
-				return fmt.Errorf("image not found %d", imageID)
+				bulkErrs.notFound(imageID)
+				continue
 			}
 
 			if updatedImage.GalleryIDs != nil {
@@ -242,7 +263,13 @@ func (r *mutationResolver) BulkImageUpdate(ctx context.Context, input BulkImageU
 				}
 
 				if err := r.galleryService.ValidateImageGalleryChange(ctx, i, *updatedImage.GalleryIDs); err != nil {
-					return err
+					var changedErr *gallery.ContentsChangedError
+					if !errors.As(err, &changedErr) {
+						return err
+					}
+
+					bulkErrs.add(imageID, err)
+					continue
 				}
 			}
 
@@ -259,8 +286,10 @@ func (r *mutationResolver) BulkImageUpdate(ctx context.Context, input BulkImageU
 		return nil, err
 	}
 
+	bulkErrs.report(ctx)
+
 	// execute post hooks outside of txn
-	var newRet []*models.Image
+	newRet := []*models.Image{}
 	for _, image := range ret {
 		r.hookExecutor.ExecutePostHooks(ctx, image.ID, plugin.ImageUpdatePost, input, translator.getFields())
 
@@ -283,7 +312,7 @@ func (r *mutationResolver) ImageDestroy(ctx context.Context, input models.ImageD
 
 	var i *models.Image
 	fileDeleter := &image.FileDeleter{
-		Deleter: file.NewDeleter(),
+		Deleter: manager.NewFileDeleter(),
 		Paths:   manager.GetInstance().Paths,
 	}
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
@@ -321,9 +350,13 @@ func (r *mutationResolver) ImagesDestroy(ctx context.Context, input models.Image
 		return false, err
 	}
 
+	if err := autoBackup(ctx, "destroying images", r.imageBackupRows(imageIDs)); err != nil {
+		return false, err
+	}
+
 	var images []*models.Image
 	fileDeleter := &image.FileDeleter{
-		Deleter: file.NewDeleter(),
+		Deleter: manager.NewFileDeleter(),
 		Paths:   manager.GetInstance().Paths,
 	}
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stashapp/stash/internal/manager"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/plugin"
+	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
+)
+
+func (r *mutationResolver) InboxApprove(ctx context.Context, input InboxApproveInput) ([]*models.Scene, error) {
+	sceneIDs, err := stringslice.StringSliceToIntSlice(input.Ids)
+	if err != nil {
+		return nil, err
+	}
+
+	translator := changesetTranslator{
+		inputMap: getUpdateInputMap(ctx),
+	}
+
+	partial := models.NewScenePartial()
+	partial.StudioID, err = translator.optionalIntFromString(input.StudioID, "studio_id")
+	if err != nil {
+		return nil, fmt.Errorf("converting studio id: %w", err)
+	}
+	partial.Organized = translator.optionalBool(input.Organized, "organized")
+
+	if len(input.TagIds) > 0 {
+		partial.TagIDs, err = translateUpdateIDs(input.TagIds, models.RelationshipUpdateModeAdd)
+		if err != nil {
+			return nil, fmt.Errorf("converting tag ids: %w", err)
+		}
+	}
+
+	if len(input.PerformerIds) > 0 {
+		partial.PerformerIDs, err = translateUpdateIDs(input.PerformerIds, models.RelationshipUpdateModeAdd)
+		if err != nil {
+			return nil, fmt.Errorf("converting performer ids: %w", err)
+		}
+	}
+
+	approved, err := manager.GetInstance().ApproveInboxScenes(ctx, sceneIDs, partial, input.AutoTag != nil && *input.AutoTag)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := []*models.Scene{}
+	for _, s := range approved {
+		r.hookExecutor.ExecutePostHooks(ctx, s.ID, plugin.SceneUpdatePost, input, translator.getFields())
+
+		s, err = r.getScene(ctx, s.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		ret = append(ret, s)
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) InboxReject(ctx context.Context, input InboxRejectInput) (bool, error) {
+	sceneIDs, err := stringslice.StringSliceToIntSlice(input.Ids)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		_, err := manager.FindInboxScenes(ctx, r.repository.Scene, sceneIDs)
+		return err
+	}); err != nil {
+		return false, err
+	}
+
+	deleteFile := true
+	if input.DeleteFile != nil {
+		deleteFile = *input.DeleteFile
+	}
+
+	deleteGenerated := true
+	if input.DeleteGenerated != nil {
+		deleteGenerated = *input.DeleteGenerated
+	}
+
+	return r.ScenesDestroy(ctx, models.ScenesDestroyInput{
+		Ids:             input.Ids,
+		DeleteFile:      &deleteFile,
+		DeleteGenerated: &deleteGenerated,
+	})
+}
@@ -5,8 +5,19 @@ import (
 	"strconv"
 
 	"github.com/stashapp/stash/internal/manager"
+	"github.com/stashapp/stash/pkg/job"
 )
 
+// jobIDString returns the string form of an optional job ID.
+func jobIDString(jobID *int) *string {
+	if jobID == nil {
+		return nil
+	}
+
+	ret := strconv.Itoa(*jobID)
+	return &ret
+}
+
 func (r *mutationResolver) StopJob(ctx context.Context, jobID string) (bool, error) {
 	idInt, err := strconv.Atoi(jobID)
 	if err != nil {
@@ -21,3 +32,99 @@ func (r *mutationResolver) StopAllJobs(ctx context.Context) (bool, error) {
 	manager.GetInstance().JobManager.CancelAll()
 	return true, nil
 }
+
+func (r *mutationResolver) SetJobPriority(ctx context.Context, jobID string, priority JobPriority) (bool, error) {
+	idInt, err := strconv.Atoi(jobID)
+	if err != nil {
+		return false, err
+	}
+
+	p := job.PriorityNormal
+	switch priority {
+	case JobPriorityLow:
+		p = job.PriorityLow
+	case JobPriorityHigh:
+		p = job.PriorityHigh
+	}
+
+	if err := manager.GetInstance().JobManager.SetPriority(idInt, p); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *mutationResolver) PauseJob(ctx context.Context, jobID string) (bool, error) {
+	idInt, err := strconv.Atoi(jobID)
+	if err != nil {
+		return false, err
+	}
+
+	if err := manager.GetInstance().JobManager.PauseJob(idInt); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *mutationResolver) SkipJobItem(ctx context.Context, jobID string, itemID string) (bool, error) {
+	idInt, err := strconv.Atoi(jobID)
+	if err != nil {
+		return false, err
+	}
+
+	itemIDInt, err := strconv.Atoi(itemID)
+	if err != nil {
+		return false, err
+	}
+
+	if err := manager.GetInstance().JobManager.SkipJobItem(idInt, itemIDInt); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *mutationResolver) SetJobItemPriority(ctx context.Context, jobID string, itemID string, priority int) (bool, error) {
+	idInt, err := strconv.Atoi(jobID)
+	if err != nil {
+		return false, err
+	}
+
+	itemIDInt, err := strconv.Atoi(itemID)
+	if err != nil {
+		return false, err
+	}
+
+	if err := manager.GetInstance().JobManager.SetJobItemPriority(idInt, itemIDInt, priority); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *mutationResolver) DestroyJobArtifact(ctx context.Context, id string) (bool, error) {
+	idInt, err := strconv.Atoi(id)
+	if err != nil {
+		return false, err
+	}
+
+	if err := manager.GetInstance().DestroyJobArtifact(ctx, idInt); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *mutationResolver) ResumeJob(ctx context.Context, jobID string) (bool, error) {
+	idInt, err := strconv.Atoi(jobID)
+	if err != nil {
+		return false, err
+	}
+
+	if err := manager.GetInstance().JobManager.ResumeJob(idInt); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
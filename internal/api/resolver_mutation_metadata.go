@@ -13,7 +13,9 @@ import (
 	"github.com/stashapp/stash/internal/manager"
 	"github.com/stashapp/stash/internal/manager/config"
 	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/job"
 	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
 )
 
 func (r *mutationResolver) MetadataScan(ctx context.Context, input manager.ScanMetadataInput) (string, error) {
@@ -26,6 +28,16 @@ func (r *mutationResolver) MetadataScan(ctx context.Context, input manager.ScanM
 	return strconv.Itoa(jobID), nil
 }
 
+func (r *mutationResolver) MetadataScanPath(ctx context.Context, input manager.ScanPathInput) (string, error) {
+	jobID, err := manager.GetInstance().ScanPath(ctx, input)
+
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(jobID), nil
+}
+
 func (r *mutationResolver) MetadataImport(ctx context.Context) (string, error) {
 	jobID, err := manager.GetInstance().Import(ctx)
 	if err != nil {
@@ -91,7 +103,7 @@ func (r *mutationResolver) MetadataAutoTag(ctx context.Context, input manager.Au
 
 func (r *mutationResolver) MetadataIdentify(ctx context.Context, input identify.Options) (string, error) {
 	t := manager.CreateIdentifyJob(input)
-	jobID := manager.GetInstance().JobManager.Add(ctx, "Identifying...", t)
+	jobID := manager.GetInstance().JobManager.AddClass(ctx, "Identifying...", t, job.ClassMetadata)
 
 	return strconv.Itoa(jobID), nil
 }
@@ -101,6 +113,161 @@ func (r *mutationResolver) MetadataClean(ctx context.Context, input manager.Clea
 	return strconv.Itoa(jobID), nil
 }
 
+func (r *mutationResolver) MetadataRetention(ctx context.Context, input manager.RetentionMetadataInput) (string, error) {
+	jobID := manager.GetInstance().Retention(ctx, input)
+	return strconv.Itoa(jobID), nil
+}
+
+func (r *mutationResolver) MetadataRename(ctx context.Context, input manager.RenameFilesInput) (string, error) {
+	jobID, err := manager.GetInstance().Rename(ctx, input)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(jobID), nil
+}
+
+func (r *mutationResolver) MetadataUndoRename(ctx context.Context, journalID string) (string, error) {
+	idInt, err := strconv.Atoi(journalID)
+	if err != nil {
+		return "", err
+	}
+
+	jobID, err := manager.GetInstance().UndoRename(ctx, idInt)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(jobID), nil
+}
+
+func (r *mutationResolver) MetadataReencode(ctx context.Context, input manager.ReencodeMetadataInput) (string, error) {
+	jobID, err := manager.GetInstance().Reencode(ctx, input)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(jobID), nil
+}
+
+func (r *mutationResolver) MetadataRemux(ctx context.Context, input manager.RemuxMetadataInput) (string, error) {
+	jobID, err := manager.GetInstance().Remux(ctx, input)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(jobID), nil
+}
+
+func (r *mutationResolver) MetadataEmbed(ctx context.Context, input manager.EmbedMetadataInput) (string, error) {
+	jobID, err := manager.GetInstance().EmbedMetadata(ctx, input)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(jobID), nil
+}
+
+func (r *mutationResolver) MetadataGenerateImagePhashes(ctx context.Context, input manager.GenerateImagePhashesInput) (string, error) {
+	jobID, err := manager.GetInstance().GenerateImagePhashes(ctx, input)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(jobID), nil
+}
+
+func (r *mutationResolver) MetadataGenerateImageThumbnails(ctx context.Context, input manager.GenerateImageThumbnailsInput) (string, error) {
+	jobID, err := manager.GetInstance().GenerateImageThumbnails(ctx, input)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(jobID), nil
+}
+
+func (r *mutationResolver) MetadataProbe(ctx context.Context, input manager.ProbeMetadataInput) (string, error) {
+	jobID, err := manager.GetInstance().Probe(ctx, input)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(jobID), nil
+}
+
+func (r *mutationResolver) MetadataRegenerateHeatmaps(ctx context.Context, input manager.RegenerateHeatmapsInput) (string, error) {
+	jobID, err := manager.GetInstance().RegenerateHeatmaps(ctx, input)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(jobID), nil
+}
+
+func (r *mutationResolver) MetadataPurgeDeletedFiles(ctx context.Context, input manager.PurgeDeletedFilesInput) (string, error) {
+	jobID, err := manager.GetInstance().PurgeDeletedFiles(ctx, input)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(jobID), nil
+}
+
+func (r *mutationResolver) MetadataMatchWanted(ctx context.Context) (string, error) {
+	jobID := manager.GetInstance().MatchWanted(ctx)
+	return strconv.Itoa(jobID), nil
+}
+
+func (r *mutationResolver) MetadataSuggestTags(ctx context.Context, input manager.SuggestTagsInput) (string, error) {
+	jobID, err := manager.GetInstance().SuggestTags(ctx, input)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(jobID), nil
+}
+
+func (r *mutationResolver) MetadataStashBoxSync(ctx context.Context, input manager.StashBoxSyncInput) (string, error) {
+	jobID, err := manager.GetInstance().StashBoxSync(ctx, input)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(jobID), nil
+}
+
+func (r *mutationResolver) MetadataScrapeRefresh(ctx context.Context, name string) (string, error) {
+	rule := config.GetInstance().GetScrapeRefreshRule(name)
+	if rule == nil {
+		return "", fmt.Errorf("%w: scrape refresh rule %s", models.ErrNotFound, name)
+	}
+
+	jobID, err := manager.GetInstance().ScrapeRefresh(ctx, *rule)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(jobID), nil
+}
+
+func (r *mutationResolver) MetadataFindDuplicateTags(ctx context.Context, input manager.FindDuplicateTagsInput) (string, error) {
+	jobID, err := manager.GetInstance().FindDuplicateTags(ctx, input)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(jobID), nil
+}
+
+func (r *mutationResolver) MetadataCheckConsistency(ctx context.Context, input manager.CheckConsistencyInput) (string, error) {
+	jobID, err := manager.GetInstance().CheckConsistency(ctx, input)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(jobID), nil
+}
+
 func (r *mutationResolver) MigrateHashNaming(ctx context.Context) (string, error) {
 	jobID := manager.GetInstance().MigrateHash(ctx)
 	return strconv.Itoa(jobID), nil
@@ -111,50 +278,37 @@ func (r *mutationResolver) BackupDatabase(ctx context.Context, input BackupDatab
 	download := input.Download != nil && *input.Download
 	mgr := manager.GetInstance()
 	database := mgr.Database
-	var backupPath string
-	if download {
-		if err := fsutil.EnsureDir(mgr.Paths.Generated.Downloads); err != nil {
-			return nil, fmt.Errorf("could not create backup directory %v: %w", mgr.Paths.Generated.Downloads, err)
-		}
-		f, err := os.CreateTemp(mgr.Paths.Generated.Downloads, "backup*.sqlite")
-		if err != nil {
-			return nil, err
-		}
-
-		backupPath = f.Name()
-		f.Close()
-	} else {
-		backupDirectoryPath := mgr.Config.GetBackupDirectoryPathOrDefault()
-		if backupDirectoryPath != "" {
-			if err := fsutil.EnsureDir(backupDirectoryPath); err != nil {
-				return nil, fmt.Errorf("could not create backup directory %v: %w", backupDirectoryPath, err)
-			}
-		}
-		backupPath = database.DatabaseBackupPath(backupDirectoryPath)
+	if !download {
+		_, err := mgr.BackupDatabase()
+		return nil, err
 	}
 
-	err := database.Backup(backupPath)
+	if err := fsutil.EnsureDir(mgr.Paths.Generated.Downloads); err != nil {
+		return nil, fmt.Errorf("could not create backup directory %v: %w", mgr.Paths.Generated.Downloads, err)
+	}
+	f, err := os.CreateTemp(mgr.Paths.Generated.Downloads, "backup*.sqlite")
 	if err != nil {
 		return nil, err
 	}
 
-	if download {
-		downloadHash, err := mgr.DownloadStore.RegisterFile(backupPath, "", false)
-		if err != nil {
-			return nil, fmt.Errorf("error registering file for download: %w", err)
-		}
-		logger.Debugf("Generated backup file %s with hash %s", backupPath, downloadHash)
+	backupPath := f.Name()
+	f.Close()
 
-		baseURL, _ := ctx.Value(BaseURLCtxKey).(string)
+	if err := database.Backup(backupPath); err != nil {
+		return nil, err
+	}
 
-		fn := filepath.Base(database.DatabaseBackupPath(""))
-		ret := baseURL + "/downloads/" + downloadHash + "/" + fn
-		return &ret, nil
-	} else {
-		logger.Infof("Successfully backed up database to: %s", backupPath)
+	downloadHash, err := mgr.DownloadStore.RegisterFile(backupPath, "", false)
+	if err != nil {
+		return nil, fmt.Errorf("error registering file for download: %w", err)
 	}
+	logger.Debugf("Generated backup file %s with hash %s", backupPath, downloadHash)
 
-	return nil, nil
+	baseURL, _ := ctx.Value(BaseURLCtxKey).(string)
+
+	fn := filepath.Base(database.DatabaseBackupPath(""))
+	ret := baseURL + "/downloads/" + downloadHash + "/" + fn
+	return &ret, nil
 }
 
 func (r *mutationResolver) AnonymiseDatabase(ctx context.Context, input AnonymiseDatabaseInput) (*string, error) {
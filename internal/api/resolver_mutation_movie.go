@@ -313,6 +313,10 @@ func (r *mutationResolver) MoviesDestroy(ctx context.Context, movieIDs []string)
 		return false, err
 	}
 
+	if err := autoBackup(ctx, "destroying movies", r.movieBackupRows(ids)); err != nil {
+		return false, err
+	}
+
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
 		qb := r.repository.Movie
 		for _, id := range ids {
@@ -35,6 +35,10 @@ func stashIDPtrSliceToSlice(v []*models.StashID) []models.StashID {
 }
 
 func (r *mutationResolver) PerformerCreate(ctx context.Context, input PerformerCreateInput) (*models.Performer, error) {
+	if err := r.executePreHooks(ctx, 0, plugin.PerformerCreatePre, &input, nil); err != nil {
+		return nil, err
+	}
+
 	var imageData []byte
 	var err error
 
@@ -166,6 +170,13 @@ func (r *mutationResolver) PerformerCreate(ctx context.Context, input PerformerC
 			}
 		}
 
+		// Save the external_ids
+		if input.ExternalIds != nil {
+			if err := qb.UpdateExternalIDs(ctx, newPerformer.ID, externalIDPtrSliceToSlice(input.ExternalIds)); err != nil {
+				return err
+			}
+		}
+
 		return nil
 	}); err != nil {
 		return nil, err
@@ -184,6 +195,10 @@ func (r *mutationResolver) PerformerUpdate(ctx context.Context, input PerformerU
 		inputMap: getUpdateInputMap(ctx),
 	}
 
+	if err := r.executePreHooks(ctx, performerID, plugin.PerformerUpdatePre, &input, &translator); err != nil {
+		return nil, err
+	}
+
 	var imageData []byte
 	var err error
 	imageIncluded := translator.hasField("image")
@@ -299,6 +314,13 @@ func (r *mutationResolver) PerformerUpdate(ctx context.Context, input PerformerU
 			}
 		}
 
+		// Save the external_ids
+		if translator.hasField("external_ids") {
+			if err := qb.UpdateExternalIDs(ctx, performerID, externalIDPtrSliceToSlice(input.ExternalIds)); err != nil {
+				return err
+			}
+		}
+
 		return nil
 	}); err != nil {
 		return nil, err
@@ -454,6 +476,10 @@ func (r *mutationResolver) PerformersDestroy(ctx context.Context, performerIDs [
 		return false, err
 	}
 
+	if err := autoBackup(ctx, "destroying performers", r.performerBackupRows(ids)); err != nil {
+		return false, err
+	}
+
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
 		qb := r.repository.Performer
 		for _, id := range ids {
@@ -473,3 +499,40 @@ func (r *mutationResolver) PerformersDestroy(ctx context.Context, performerIDs [
 
 	return true, nil
 }
+
+func (r *mutationResolver) MergePerformers(ctx context.Context, input PerformersMergeInput) (*models.Performer, error) {
+	source, err := stringslice.StringSliceToIntSlice(input.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	destination, err := strconv.Atoi(input.Destination)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := autoBackup(ctx, "merging performers", r.performerBackupRows(append([]int{destination}, source...))); err != nil {
+		return nil, err
+	}
+
+	stage := bulkUndoEnabled()
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		result, err := performer.Merge(ctx, r.repository.Performer, source, destination)
+		if err != nil {
+			return err
+		}
+
+		if stage && len(result.Sources) > 0 {
+			return r.stageBulkOperation(ctx, bulkOperationPerformersMerge, result)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	r.hookExecutor.ExecutePostHooks(ctx, destination, plugin.PerformerMergePost, input, nil)
+
+	return r.getPerformer(ctx, destination)
+}
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene"
+	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
+)
+
+func (r *mutationResolver) PlayQueueAppend(ctx context.Context, queue *string, sceneIds []string) (ret []*models.PlayQueueItem, err error) {
+	ids, err := stringslice.StringSliceToIntSlice(sceneIds)
+	if err != nil {
+		return nil, fmt.Errorf("converting scene ids: %w", err)
+	}
+
+	name := playQueueName(queue)
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		if err := r.repository.PlayQueue.Append(ctx, name, ids); err != nil {
+			return err
+		}
+
+		ret, err = r.repository.PlayQueue.FindByQueue(ctx, name)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) PlayQueueReorder(ctx context.Context, queue *string, itemIds []string) (ret []*models.PlayQueueItem, err error) {
+	ids, err := stringslice.StringSliceToIntSlice(itemIds)
+	if err != nil {
+		return nil, fmt.Errorf("converting item ids: %w", err)
+	}
+
+	name := playQueueName(queue)
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		qb := r.repository.PlayQueue
+		items, err := qb.FindByQueue(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		ordered, err := scene.ReorderPlayQueue(items, ids)
+		if err != nil {
+			return err
+		}
+
+		if err := qb.UpdatePositions(ctx, name, ordered); err != nil {
+			return err
+		}
+
+		ret, err = qb.FindByQueue(ctx, name)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) PlayQueuePop(ctx context.Context, queue *string) (ret *models.PlayQueueItem, err error) {
+	name := playQueueName(queue)
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		qb := r.repository.PlayQueue
+		items, err := qb.FindByQueue(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		if len(items) == 0 {
+			return nil
+		}
+
+		ret = items[0]
+		return qb.Destroy(ctx, name, []int{ret.ID})
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) PlayQueueRemove(ctx context.Context, queue *string, itemIds []string) (ret []*models.PlayQueueItem, err error) {
+	ids, err := stringslice.StringSliceToIntSlice(itemIds)
+	if err != nil {
+		return nil, fmt.Errorf("converting item ids: %w", err)
+	}
+
+	name := playQueueName(queue)
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		if err := r.repository.PlayQueue.Destroy(ctx, name, ids); err != nil {
+			return err
+		}
+
+		ret, err = r.repository.PlayQueue.FindByQueue(ctx, name)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) PlayQueueClear(ctx context.Context, queue *string) (bool, error) {
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		return r.repository.PlayQueue.Clear(ctx, playQueueName(queue))
+	}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
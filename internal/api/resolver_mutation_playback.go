@@ -0,0 +1,82 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const (
+	// maximum number of events recorded by a single request
+	maxPlaybackEvents = 100
+	// maximum length of session IDs and event details
+	maxPlaybackEventValueLength = 255
+)
+
+func truncatePlaybackEventValue(s string) string {
+	if len(s) > maxPlaybackEventValueLength {
+		return s[:maxPlaybackEventValueLength]
+	}
+	return s
+}
+
+func (r *mutationResolver) PlaybackEventsCreate(ctx context.Context, input []*PlaybackEventInput) (int, error) {
+	if len(input) > maxPlaybackEvents {
+		return 0, fmt.Errorf("cannot record more than %d events at once", maxPlaybackEvents)
+	}
+
+	now := time.Now()
+	events := make([]models.PlaybackEvent, len(input))
+	for i, e := range input {
+		sceneID, err := strconv.Atoi(e.SceneID)
+		if err != nil {
+			return 0, fmt.Errorf("converting scene id: %w", err)
+		}
+
+		if e.SessionID == "" {
+			return 0, fmt.Errorf("session id is required")
+		}
+
+		events[i] = models.PlaybackEvent{
+			SceneID:   sceneID,
+			SessionID: truncatePlaybackEventValue(e.SessionID),
+			Type:      e.Type,
+			Position:  e.Position,
+			CreatedAt: now,
+		}
+
+		if e.Details != nil {
+			details := truncatePlaybackEventValue(*e.Details)
+			events[i].Details = &details
+		}
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		sceneIDs := make(map[int]bool)
+		for _, e := range events {
+			if !sceneIDs[e.SceneID] {
+				s, err := r.repository.Scene.Find(ctx, e.SceneID)
+				if err != nil {
+					return err
+				}
+				if s == nil {
+					return fmt.Errorf("scene with id %d not found", e.SceneID)
+				}
+				sceneIDs[e.SceneID] = true
+			}
+
+			if err := r.repository.PlaybackEvent.Create(ctx, e); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+
+	return len(events), nil
+}
@@ -0,0 +1,199 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/session"
+	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
+)
+
+// validateRestrictionProfile returns an error if the name is empty or used
+// by another profile, or if the PIN already unlocks locked sessions.
+func (r *mutationResolver) validateRestrictionProfile(ctx context.Context, id int, name string, pin *string) error {
+	if strings.TrimSpace(name) == "" {
+		return errors.New("name must not be empty")
+	}
+
+	if pin != nil && *pin != "" && config.GetInstance().ValidateSessionLockPin(*pin) {
+		return errors.New("PIN must differ from the session lock PIN")
+	}
+
+	profiles, err := r.repository.RestrictionProfile.All(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range profiles {
+		if p.ID == id {
+			continue
+		}
+
+		if strings.EqualFold(p.Name, name) {
+			return fmt.Errorf("restriction profile %s already exists", name)
+		}
+
+		if pin != nil && *pin != "" && p.PinHash != nil && bcrypt.CompareHashAndPassword([]byte(*p.PinHash), []byte(*pin)) == nil {
+			return fmt.Errorf("PIN is used by restriction profile %s", p.Name)
+		}
+	}
+
+	return nil
+}
+
+// restrictionProfilePinHash returns the hash of the PIN, or nil if the PIN
+// is empty.
+func restrictionProfilePinHash(pin string) (*string, error) {
+	if pin == "" {
+		return nil, nil
+	}
+
+	h, err := session.HashPassword(pin)
+	if err != nil {
+		return nil, err
+	}
+
+	return &h, nil
+}
+
+func (r *mutationResolver) RestrictionProfileCreate(ctx context.Context, input RestrictionProfileCreateInput) (*models.RestrictionProfile, error) {
+	tagIDs, err := stringslice.StringSliceToIntSlice(input.TagIds)
+	if err != nil {
+		return nil, fmt.Errorf("converting tag ids: %w", err)
+	}
+	studioIDs, err := stringslice.StringSliceToIntSlice(input.StudioIds)
+	if err != nil {
+		return nil, fmt.Errorf("converting studio ids: %w", err)
+	}
+
+	var pinHash *string
+	if input.Pin != nil {
+		pinHash, err = restrictionProfilePinHash(*input.Pin)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var ret *models.RestrictionProfile
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		qb := r.repository.RestrictionProfile
+
+		if err := r.validateRestrictionProfile(ctx, 0, input.Name, input.Pin); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		ret, err = qb.Create(ctx, models.RestrictionProfile{
+			Name:      input.Name,
+			PinHash:   pinHash,
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := qb.UpdateTags(ctx, ret.ID, tagIDs); err != nil {
+			return err
+		}
+		return qb.UpdateStudios(ctx, ret.ID, studioIDs)
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) RestrictionProfileUpdate(ctx context.Context, input RestrictionProfileUpdateInput) (*models.RestrictionProfile, error) {
+	id, err := strconv.Atoi(input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var pinHash *string
+	if input.Pin != nil {
+		pinHash, err = restrictionProfilePinHash(*input.Pin)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var ret *models.RestrictionProfile
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		qb := r.repository.RestrictionProfile
+
+		p, err := qb.Find(ctx, id)
+		if err != nil {
+			return err
+		}
+		if p == nil {
+			return fmt.Errorf("restriction profile with id %d not found", id)
+		}
+
+		if input.Name != nil {
+			p.Name = *input.Name
+		}
+		if err := r.validateRestrictionProfile(ctx, id, p.Name, input.Pin); err != nil {
+			return err
+		}
+
+		if input.Pin != nil {
+			p.PinHash = pinHash
+		}
+		p.UpdatedAt = time.Now()
+
+		ret, err = qb.Update(ctx, *p)
+		if err != nil {
+			return err
+		}
+
+		if input.TagIds != nil {
+			tagIDs, err := stringslice.StringSliceToIntSlice(input.TagIds)
+			if err != nil {
+				return fmt.Errorf("converting tag ids: %w", err)
+			}
+			if err := qb.UpdateTags(ctx, id, tagIDs); err != nil {
+				return err
+			}
+		}
+
+		if input.StudioIds != nil {
+			studioIDs, err := stringslice.StringSliceToIntSlice(input.StudioIds)
+			if err != nil {
+				return fmt.Errorf("converting studio ids: %w", err)
+			}
+			if err := qb.UpdateStudios(ctx, id, studioIDs); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) RestrictionProfileDestroy(ctx context.Context, id string) (bool, error) {
+	profileID, err := strconv.Atoi(id)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		return r.repository.RestrictionProfile.Destroy(ctx, profileID)
+	}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
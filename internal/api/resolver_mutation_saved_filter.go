@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/savedfilter"
 )
 
 func (r *mutationResolver) SaveFilter(ctx context.Context, input SaveFilterInput) (ret *models.SavedFilter, err error) {
@@ -14,6 +15,12 @@ func (r *mutationResolver) SaveFilter(ctx context.Context, input SaveFilterInput
 		return nil, errors.New("name must be non-empty")
 	}
 
+	if input.DisplayOptions != nil {
+		if err := input.DisplayOptions.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
 	var id *int
 	if input.ID != nil {
 		idv, err := strconv.Atoi(*input.ID)
@@ -25,9 +32,10 @@ func (r *mutationResolver) SaveFilter(ctx context.Context, input SaveFilterInput
 
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
 		f := models.SavedFilter{
-			Mode:   input.Mode,
-			Name:   input.Name,
-			Filter: input.Filter,
+			Mode:           input.Mode,
+			Name:           input.Name,
+			Filter:         input.Filter,
+			DisplayOptions: input.DisplayOptions,
 		}
 		if id == nil {
 			ret, err = r.repository.SavedFilter.Create(ctx, f)
@@ -58,6 +66,12 @@ func (r *mutationResolver) DestroySavedFilter(ctx context.Context, input Destroy
 }
 
 func (r *mutationResolver) SetDefaultFilter(ctx context.Context, input SetDefaultFilterInput) (bool, error) {
+	if input.DisplayOptions != nil {
+		if err := input.DisplayOptions.Validate(); err != nil {
+			return false, err
+		}
+	}
+
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
 		qb := r.repository.SavedFilter
 
@@ -76,8 +90,9 @@ func (r *mutationResolver) SetDefaultFilter(ctx context.Context, input SetDefaul
 		}
 
 		_, err := qb.SetDefault(ctx, models.SavedFilter{
-			Mode:   input.Mode,
-			Filter: *input.Filter,
+			Mode:           input.Mode,
+			Filter:         *input.Filter,
+			DisplayOptions: input.DisplayOptions,
 		})
 
 		return err
@@ -87,3 +102,73 @@ func (r *mutationResolver) SetDefaultFilter(ctx context.Context, input SetDefaul
 
 	return true, nil
 }
+
+func (r *mutationResolver) ImportSavedFilters(ctx context.Context, input ImportSavedFiltersInput) (*ImportSavedFiltersResult, error) {
+	doc, err := savedfilter.ParseDocument([]byte(input.Data))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range doc.Filters {
+		if strings.TrimSpace(f.Name) == "" {
+			return nil, errors.New("name must be non-empty")
+		}
+	}
+
+	overwrite := input.Overwrite != nil && *input.Overwrite
+	ret := &ImportSavedFiltersResult{
+		Filters: []*models.SavedFilter{},
+		Missing: []string{},
+		Skipped: []string{},
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		qb := r.repository.SavedFilter
+		resolver := savedfilter.Resolver{
+			TagFinder:    r.repository.Tag,
+			StudioFinder: r.repository.Studio,
+		}
+
+		filters, missing, err := resolver.Import(ctx, doc)
+		if err != nil {
+			return err
+		}
+		ret.Missing = append(ret.Missing, missing...)
+
+		for _, f := range filters {
+			existing, err := qb.FindByMode(ctx, f.Mode)
+			if err != nil {
+				return err
+			}
+
+			var saved *models.SavedFilter
+			for _, e := range existing {
+				if e.Name == f.Name {
+					f.ID = e.ID
+					break
+				}
+			}
+
+			switch {
+			case f.ID == 0:
+				saved, err = qb.Create(ctx, f)
+			case overwrite:
+				saved, err = qb.Update(ctx, f)
+			default:
+				ret.Skipped = append(ret.Skipped, f.Name)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			ret.Filters = append(ret.Filters, saved)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
@@ -36,6 +36,10 @@ func (r *mutationResolver) SceneCreate(ctx context.Context, input SceneCreateInp
 		inputMap: getUpdateInputMap(ctx),
 	}
 
+	if err := r.executePreHooks(ctx, 0, plugin.SceneCreatePre, &input, &translator); err != nil {
+		return nil, err
+	}
+
 	performerIDs, err := stringslice.StringSliceToIntSlice(input.PerformerIds)
 	if err != nil {
 		return nil, fmt.Errorf("converting performer ids: %w", err)
@@ -54,6 +58,10 @@ func (r *mutationResolver) SceneCreate(ctx context.Context, input SceneCreateInp
 		return nil, fmt.Errorf("converting movies scenes: %w", err)
 	}
 
+	if err := models.ValidateCoordinates(input.Latitude, input.Longitude); err != nil {
+		return nil, err
+	}
+
 	fileIDsInt, err := stringslice.StringSliceToIntSlice(input.FileIds)
 	if err != nil {
 		return nil, fmt.Errorf("converting file ids: %w", err)
@@ -73,6 +81,9 @@ func (r *mutationResolver) SceneCreate(ctx context.Context, input SceneCreateInp
 		Date:         translator.datePtr(input.Date, "date"),
 		Rating:       translator.ratingConversionInt(input.Rating, input.Rating100),
 		Organized:    translator.bool(input.Organized, "organized"),
+		Location:     translator.string(input.Location, "location"),
+		Latitude:     input.Latitude,
+		Longitude:    input.Longitude,
 		PerformerIDs: models.NewRelatedIDs(performerIDs),
 		TagIDs:       models.NewRelatedIDs(tagIDs),
 		GalleryIDs:   models.NewRelatedIDs(galleryIDs),
@@ -96,7 +107,17 @@ func (r *mutationResolver) SceneCreate(ctx context.Context, input SceneCreateInp
 
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
 		ret, err = r.Resolver.sceneService.Create(ctx, &newScene, fileIDs, coverImageData)
-		return err
+		if err != nil {
+			return err
+		}
+
+		if input.ExternalIds != nil {
+			if err := r.repository.Scene.UpdateExternalIDs(ctx, ret.ID, externalIDPtrSliceToSlice(input.ExternalIds)); err != nil {
+				return fmt.Errorf("setting external ids: %w", err)
+			}
+		}
+
+		return nil
 	}); err != nil {
 		return nil, err
 	}
@@ -105,10 +126,22 @@ func (r *mutationResolver) SceneCreate(ctx context.Context, input SceneCreateInp
 }
 
 func (r *mutationResolver) SceneUpdate(ctx context.Context, input models.SceneUpdateInput) (ret *models.Scene, err error) {
+	sceneID, err := strconv.Atoi(input.ID)
+	if err != nil {
+		return nil, err
+	}
+
 	translator := changesetTranslator{
 		inputMap: getUpdateInputMap(ctx),
 	}
 
+	if err := r.executePreHooks(ctx, sceneID, plugin.SceneUpdatePre, &input, &translator); err != nil {
+		return nil, err
+	}
+
+	// the scene cannot be changed by the hooks
+	input.ID = strconv.Itoa(sceneID)
+
 	// Start the transaction and save the scene
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
 		ret, err = r.sceneUpdate(ctx, input, translator)
@@ -183,6 +216,12 @@ func scenePartialFromInput(input models.SceneUpdateInput, translator changesetTr
 	}
 
 	updatedScene.Organized = translator.optionalBool(input.Organized, "organized")
+	updatedScene.Location = translator.optionalString(input.Location, "location")
+	updatedScene.Latitude = translator.optionalFloat64(input.Latitude, "latitude")
+	updatedScene.Longitude = translator.optionalFloat64(input.Longitude, "longitude")
+	if err := models.ValidateCoordinates(updatedScene.Latitude.Ptr(), updatedScene.Longitude.Ptr()); err != nil {
+		return nil, err
+	}
 
 	if input.PrimaryFileID != nil {
 		primaryFileID, err := strconv.Atoi(*input.PrimaryFileID)
@@ -309,6 +348,12 @@ func (r *mutationResolver) sceneUpdate(ctx context.Context, input models.SceneUp
 		return nil, err
 	}
 
+	if translator.hasField("external_ids") {
+		if err := qb.UpdateExternalIDs(ctx, sceneID, sanitizeExternalIDs(input.ExternalIds)); err != nil {
+			return nil, fmt.Errorf("setting external ids: %w", err)
+		}
+	}
+
 	return s, nil
 }
 
@@ -357,6 +402,12 @@ func (r *mutationResolver) BulkSceneUpdate(ctx context.Context, input BulkSceneU
 	}
 
 	updatedScene.Organized = translator.optionalBool(input.Organized, "organized")
+	updatedScene.Location = translator.optionalString(input.Location, "location")
+	updatedScene.Latitude = translator.optionalFloat64(input.Latitude, "latitude")
+	updatedScene.Longitude = translator.optionalFloat64(input.Longitude, "longitude")
+	if err := models.ValidateCoordinates(updatedScene.Latitude.Ptr(), updatedScene.Longitude.Ptr()); err != nil {
+		return nil, err
+	}
 
 	if translator.hasField("performer_ids") {
 		updatedScene.PerformerIDs, err = translateUpdateIDs(input.PerformerIds.Ids, input.PerformerIds.Mode)
@@ -387,19 +438,48 @@ func (r *mutationResolver) BulkSceneUpdate(ctx context.Context, input BulkSceneU
 		}
 	}
 
-	ret := []*models.Scene{}
+	var ret []*models.Scene
+	var bulkErrs *bulkUpdateErrors
+	stage := bulkUndoEnabled()
 
 	// Start the transaction and save the scene marker
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
 		qb := r.repository.Scene
+		ret = []*models.Scene{}
+		bulkErrs = newBulkUpdateErrors("scene")
 
+		var reverse bulkSceneReverse
 		for _, sceneID := range sceneIDs {
-			scene, err := qb.UpdatePartial(ctx, sceneID, updatedScene)
+			existing, err := qb.Find(ctx, sceneID)
 			if err != nil {
 				return err
 			}
 
-			ret = append(ret, scene)
+			if existing == nil {
+				bulkErrs.notFound(sceneID)
+				continue
+			}
+
+			if stage {
+				snapshot, err := scene.TakeSnapshot(ctx, qb, existing)
+				if err != nil {
+					return err
+				}
+				// the cover is not changed by the update
+				snapshot.Cover = nil
+				reverse.Scenes = append(reverse.Scenes, snapshot)
+			}
+
+			updated, err := qb.UpdatePartial(ctx, sceneID, updatedScene)
+			if err != nil {
+				return err
+			}
+
+			ret = append(ret, updated)
+		}
+
+		if stage && len(reverse.Scenes) > 0 {
+			return r.stageBulkOperation(ctx, bulkOperationSceneUpdate, reverse)
 		}
 
 		return nil
@@ -407,8 +487,10 @@ func (r *mutationResolver) BulkSceneUpdate(ctx context.Context, input BulkSceneU
 		return nil, err
 	}
 
+	bulkErrs.report(ctx)
+
 	// execute post hooks outside of txn
-	var newRet []*models.Scene
+	newRet := []*models.Scene{}
 	for _, scene := range ret {
 		r.hookExecutor.ExecutePostHooks(ctx, scene.ID, plugin.SceneUpdatePost, input, translator.getFields())
 
@@ -433,13 +515,14 @@ func (r *mutationResolver) SceneDestroy(ctx context.Context, input models.SceneD
 
 	var s *models.Scene
 	fileDeleter := &scene.FileDeleter{
-		Deleter:        file.NewDeleter(),
+		Deleter:        manager.NewFileDeleter(),
 		FileNamingAlgo: fileNamingAlgo,
 		Paths:          manager.GetInstance().Paths,
 	}
 
 	deleteGenerated := utils.IsTrue(input.DeleteGenerated)
 	deleteFile := utils.IsTrue(input.DeleteFile)
+	trash := newSceneTrash(r.repository, deleteFile)
 
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
 		qb := r.repository.Scene
@@ -456,14 +539,20 @@ func (r *mutationResolver) SceneDestroy(ctx context.Context, input models.SceneD
 		// kill any running encoders
 		manager.KillRunningStreams(s, fileNamingAlgo)
 
+		if trash != nil {
+			return trash.destroy(ctx, r.sceneService, s, fileDeleter, deleteGenerated)
+		}
+
 		return r.sceneService.Destroy(ctx, s, fileDeleter, deleteGenerated, deleteFile)
 	}); err != nil {
 		fileDeleter.Rollback()
+		trash.rollback()
 		return false, err
 	}
 
 	// perform the post-commit actions
 	fileDeleter.Commit()
+	trash.commit()
 
 	// call post hook after performing the other actions
 	r.hookExecutor.ExecutePostHooks(ctx, s.ID, plugin.SceneDestroyPost, plugin.SceneDestroyInput{
@@ -477,11 +566,20 @@ func (r *mutationResolver) SceneDestroy(ctx context.Context, input models.SceneD
 }
 
 func (r *mutationResolver) ScenesDestroy(ctx context.Context, input models.ScenesDestroyInput) (bool, error) {
+	sceneIDs, err := stringslice.StringSliceToIntSlice(input.Ids)
+	if err != nil {
+		return false, err
+	}
+
+	if err := autoBackup(ctx, "destroying scenes", r.sceneBackupRows(sceneIDs)); err != nil {
+		return false, err
+	}
+
 	var scenes []*models.Scene
 	fileNamingAlgo := manager.GetInstance().Config.GetVideoFileNamingAlgorithm()
 
 	fileDeleter := &scene.FileDeleter{
-		Deleter:        file.NewDeleter(),
+		Deleter:        manager.NewFileDeleter(),
 		FileNamingAlgo: fileNamingAlgo,
 		Paths:          manager.GetInstance().Paths,
 	}
@@ -489,12 +587,16 @@ func (r *mutationResolver) ScenesDestroy(ctx context.Context, input models.Scene
 	deleteGenerated := utils.IsTrue(input.DeleteGenerated)
 	deleteFile := utils.IsTrue(input.DeleteFile)
 
+	// deleted files cannot be restored. Trashed scenes are restored from
+	// the trash instead.
+	stage := bulkUndoEnabled() && !deleteFile
+	trash := newSceneTrash(r.repository, deleteFile)
+
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
 		qb := r.repository.Scene
 
-		for _, id := range input.Ids {
-			sceneID, _ := strconv.Atoi(id)
-
+		var reverse bulkSceneReverse
+		for _, sceneID := range sceneIDs {
 			s, err := qb.Find(ctx, sceneID)
 			if err != nil {
 				return err
@@ -503,22 +605,46 @@ func (r *mutationResolver) ScenesDestroy(ctx context.Context, input models.Scene
 				scenes = append(scenes, s)
 			}
 
+			if stage && s != nil {
+				snapshot, err := scene.TakeSnapshot(ctx, qb, s)
+				if err != nil {
+					return err
+				}
+				if err := snapshot.LoadMarkers(ctx, r.repository.SceneMarker); err != nil {
+					return err
+				}
+				reverse.Scenes = append(reverse.Scenes, snapshot)
+			}
+
 			// kill any running encoders
 			manager.KillRunningStreams(s, fileNamingAlgo)
 
+			if trash != nil {
+				if err := trash.destroy(ctx, r.sceneService, s, fileDeleter, deleteGenerated); err != nil {
+					return err
+				}
+				continue
+			}
+
 			if err := r.sceneService.Destroy(ctx, s, fileDeleter, deleteGenerated, deleteFile); err != nil {
 				return err
 			}
 		}
 
+		if stage {
+			return r.stageBulkOperation(ctx, bulkOperationScenesDestroy, reverse)
+		}
+
 		return nil
 	}); err != nil {
 		fileDeleter.Rollback()
+		trash.rollback()
 		return false, err
 	}
 
 	// perform the post-commit actions
 	fileDeleter.Commit()
+	trash.commit()
 
 	for _, scene := range scenes {
 		// call post hook after performing the other actions
@@ -555,6 +681,89 @@ func (r *mutationResolver) SceneAssignFile(ctx context.Context, input AssignScen
 	return true, nil
 }
 
+func (r *mutationResolver) SceneAttachTrailer(ctx context.Context, input SceneAttachTrailerInput) (*models.Scene, error) {
+	sceneID, err := strconv.Atoi(input.SceneID)
+	if err != nil {
+		return nil, fmt.Errorf("converting scene ID: %w", err)
+	}
+
+	trailerID, err := strconv.Atoi(input.TrailerID)
+	if err != nil {
+		return nil, fmt.Errorf("converting trailer ID: %w", err)
+	}
+
+	if sceneID == trailerID {
+		return nil, errors.New("cannot attach a scene as its own trailer")
+	}
+
+	fileNamingAlgo := manager.GetInstance().Config.GetVideoFileNamingAlgorithm()
+
+	fileDeleter := &scene.FileDeleter{
+		Deleter:        file.NewDeleter(),
+		FileNamingAlgo: fileNamingAlgo,
+		Paths:          manager.GetInstance().Paths,
+	}
+
+	var trailer *models.Scene
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		qb := r.repository.Scene
+		s, err := qb.Find(ctx, sceneID)
+		if err != nil {
+			return err
+		}
+		if s == nil {
+			return fmt.Errorf("scene with id %d not found", sceneID)
+		}
+
+		trailer, err = qb.Find(ctx, trailerID)
+		if err != nil {
+			return err
+		}
+		if trailer == nil {
+			return fmt.Errorf("scene with id %d not found", trailerID)
+		}
+
+		if err := trailer.LoadPrimaryFile(ctx, r.repository.File); err != nil {
+			return err
+		}
+
+		f := trailer.Files.Primary()
+		if f == nil {
+			return fmt.Errorf("scene with id %d has no files", trailerID)
+		}
+
+		if err := qb.UpdateTrailerFile(ctx, sceneID, &f.ID); err != nil {
+			return fmt.Errorf("attaching trailer: %w", err)
+		}
+
+		// kill any running encoders
+		manager.KillRunningStreams(trailer, fileNamingAlgo)
+
+		// the trailer file is kept, only the trailer scene is destroyed
+		const (
+			deleteGenerated = true
+			deleteFile      = false
+		)
+		return r.sceneService.Destroy(ctx, trailer, fileDeleter, deleteGenerated, deleteFile)
+	}); err != nil {
+		fileDeleter.Rollback()
+		return nil, err
+	}
+
+	// perform the post-commit actions
+	fileDeleter.Commit()
+
+	r.hookExecutor.ExecutePostHooks(ctx, trailer.ID, plugin.SceneDestroyPost, plugin.SceneDestroyInput{
+		SceneDestroyInput: models.SceneDestroyInput{ID: input.TrailerID},
+		Checksum:          trailer.Checksum,
+		OSHash:            trailer.OSHash,
+		Path:              trailer.Path,
+	}, nil)
+	r.hookExecutor.ExecutePostHooks(ctx, sceneID, plugin.SceneUpdatePost, input, nil)
+
+	return r.getScene(ctx, sceneID)
+}
+
 func (r *mutationResolver) SceneMerge(ctx context.Context, input SceneMergeInput) (*models.Scene, error) {
 	srcIDs, err := stringslice.StringSliceToIntSlice(input.Source)
 	if err != nil {
@@ -591,6 +800,10 @@ func (r *mutationResolver) SceneMerge(ctx context.Context, input SceneMergeInput
 		}
 	}
 
+	if err := autoBackup(ctx, "merging scenes", r.sceneBackupRows(append([]int{destID}, srcIDs...))); err != nil {
+		return nil, err
+	}
+
 	var ret *models.Scene
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
 		if err := r.Resolver.sceneService.Merge(ctx, srcIDs, destID, *values); err != nil {
@@ -0,0 +1,161 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
+)
+
+// ensureSceneFlagNameUnique returns an error if a flag other than the one
+// with the provided id already uses the name. Names are compared case
+// insensitively.
+func ensureSceneFlagNameUnique(ctx context.Context, qb models.SceneFlagReader, id int, name string) error {
+	existing, err := qb.FindByName(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	if existing != nil && existing.ID != id {
+		return fmt.Errorf("scene flag with name %q already exists", existing.Name)
+	}
+
+	return nil
+}
+
+func sceneFlagName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", errors.New("name must not be empty")
+	}
+
+	return name, nil
+}
+
+func (r *mutationResolver) SceneFlagCreate(ctx context.Context, input SceneFlagCreateInput) (ret *models.SceneFlag, err error) {
+	name, err := sceneFlagName(input.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	newFlag := models.SceneFlag{
+		Name:      name,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		qb := r.repository.SceneFlag
+
+		if err := ensureSceneFlagNameUnique(ctx, qb, 0, name); err != nil {
+			return err
+		}
+
+		ret, err = qb.Create(ctx, newFlag)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) SceneFlagUpdate(ctx context.Context, input SceneFlagUpdateInput) (ret *models.SceneFlag, err error) {
+	id, err := strconv.Atoi(input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := sceneFlagName(input.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		qb := r.repository.SceneFlag
+
+		existing, err := qb.Find(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if existing == nil {
+			return fmt.Errorf("scene flag with id %d not found", id)
+		}
+
+		if err := ensureSceneFlagNameUnique(ctx, qb, id, name); err != nil {
+			return err
+		}
+
+		updated := *existing
+		updated.Name = name
+		updated.UpdatedAt = time.Now()
+
+		ret, err = qb.Update(ctx, updated)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) SceneFlagDestroy(ctx context.Context, id string) (bool, error) {
+	idInt, err := strconv.Atoi(id)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		return r.repository.SceneFlag.Destroy(ctx, idInt)
+	}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// changeSceneFlagScenes calls fn with the flag and scene ids of the input,
+// after checking that the flag exists.
+func (r *mutationResolver) changeSceneFlagScenes(ctx context.Context, input SceneFlagScenesInput, fn func(ctx context.Context, id int, sceneIDs []int) error) (bool, error) {
+	id, err := strconv.Atoi(input.ID)
+	if err != nil {
+		return false, err
+	}
+
+	sceneIDs, err := stringslice.StringSliceToIntSlice(input.SceneIds)
+	if err != nil {
+		return false, fmt.Errorf("converting scene ids: %w", err)
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		existing, err := r.repository.SceneFlag.Find(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if existing == nil {
+			return fmt.Errorf("scene flag with id %d not found", id)
+		}
+
+		return fn(ctx, id, sceneIDs)
+	}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *mutationResolver) SceneFlagAddScenes(ctx context.Context, input SceneFlagScenesInput) (bool, error) {
+	return r.changeSceneFlagScenes(ctx, input, r.repository.SceneFlag.AddScenes)
+}
+
+func (r *mutationResolver) SceneFlagRemoveScenes(ctx context.Context, input SceneFlagScenesInput) (bool, error) {
+	return r.changeSceneFlagScenes(ctx, input, r.repository.SceneFlag.RemoveScenes)
+}
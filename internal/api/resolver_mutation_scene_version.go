@@ -0,0 +1,89 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/stashapp/stash/pkg/scene"
+	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
+)
+
+func (r *mutationResolver) SceneStackVersions(ctx context.Context, input SceneStackVersionsInput) (bool, error) {
+	primaryID, err := strconv.Atoi(input.PrimaryID)
+	if err != nil {
+		return false, fmt.Errorf("converting primary ID: %w", err)
+	}
+
+	sceneIDs, err := stringslice.StringSliceToIntSlice(input.SceneIds)
+	if err != nil {
+		return false, fmt.Errorf("converting scene IDs: %w", err)
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		return scene.StackVersions(ctx, r.repository.SceneVersion, primaryID, sceneIDs)
+	}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *mutationResolver) SceneUnstackVersions(ctx context.Context, ids []string) (bool, error) {
+	sceneIDs, err := stringslice.StringSliceToIntSlice(ids)
+	if err != nil {
+		return false, fmt.Errorf("converting scene IDs: %w", err)
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		return scene.UnstackVersions(ctx, r.repository.SceneVersion, sceneIDs)
+	}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *mutationResolver) SceneSetPrimaryVersion(ctx context.Context, id string) (bool, error) {
+	sceneID, err := strconv.Atoi(id)
+	if err != nil {
+		return false, fmt.Errorf("converting id: %w", err)
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		return scene.SetPrimaryVersion(ctx, r.repository.SceneVersion, sceneID)
+	}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *mutationResolver) SceneStackDuplicates(ctx context.Context, distance *int) (ret int, err error) {
+	dist := 0
+	if distance != nil {
+		dist = *distance
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		groups, err := r.repository.Scene.FindDuplicates(ctx, dist)
+		if err != nil {
+			return err
+		}
+
+		for _, scenes := range groups {
+			for _, s := range scenes {
+				if err := s.LoadPrimaryFile(ctx, r.repository.File); err != nil {
+					return err
+				}
+			}
+		}
+
+		ret, err = scene.StackDuplicates(ctx, r.repository.SceneVersion, groups)
+		return err
+	}); err != nil {
+		return 0, err
+	}
+
+	return ret, nil
+}
@@ -15,3 +15,16 @@ func (r *mutationResolver) ReloadScrapers(ctx context.Context) (bool, error) {
 
 	return true, nil
 }
+
+func (r *mutationResolver) ScraperCachePurge(ctx context.Context, scraperID *string) (int, error) {
+	var ret int
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		var err error
+		ret, err = r.repository.ScraperCache.DestroyByScraperID(ctx, scraperID)
+		return err
+	}); err != nil {
+		return 0, err
+	}
+
+	return ret, nil
+}
@@ -4,9 +4,12 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/stashapp/stash/internal/manager"
 	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/scraper/stashbox"
 )
 
@@ -64,6 +67,14 @@ func (r *mutationResolver) SubmitStashBoxSceneDraft(ctx context.Context, input S
 		return err
 	})
 
+	if err == nil && res != nil {
+		r.recordStashBoxDraft(ctx, models.StashBoxDraft{
+			Endpoint: boxes[input.StashBoxIndex].Endpoint,
+			DraftID:  *res,
+			SceneID:  &id,
+		})
+	}
+
 	return res, err
 }
 
@@ -93,5 +104,27 @@ func (r *mutationResolver) SubmitStashBoxPerformerDraft(ctx context.Context, inp
 		return err
 	})
 
+	if err == nil && res != nil {
+		r.recordStashBoxDraft(ctx, models.StashBoxDraft{
+			Endpoint:    boxes[input.StashBoxIndex].Endpoint,
+			DraftID:     *res,
+			PerformerID: &id,
+		})
+	}
+
 	return res, err
 }
+
+// recordStashBoxDraft stores a submitted draft so that it can be listed on
+// the scene or performer. The draft has already been submitted, so failing
+// to record it is not returned as an error.
+func (r *mutationResolver) recordStashBoxDraft(ctx context.Context, draft models.StashBoxDraft) {
+	draft.CreatedAt = time.Now()
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		_, err := r.repository.StashBoxDraft.Create(ctx, draft)
+		return err
+	}); err != nil {
+		logger.Warnf("error recording stash-box draft %s: %v", draft.DraftID, err)
+	}
+}
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/stashapp/stash/internal/manager"
+)
+
+func (r *mutationResolver) StashBoxMatchSuggestionAccept(ctx context.Context, id string) (bool, error) {
+	idInt, err := strconv.Atoi(id)
+	if err != nil {
+		return false, fmt.Errorf("converting id: %w", err)
+	}
+
+	if err := manager.GetInstance().AcceptStashBoxMatchSuggestion(ctx, idInt); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *mutationResolver) StashBoxMatchSuggestionReject(ctx context.Context, id string) (bool, error) {
+	idInt, err := strconv.Atoi(id)
+	if err != nil {
+		return false, fmt.Errorf("converting id: %w", err)
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		return r.repository.StashBoxSync.RejectSuggestion(ctx, idInt)
+	}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
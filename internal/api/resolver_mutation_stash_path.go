@@ -0,0 +1,49 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/internal/manager"
+	"github.com/stashapp/stash/internal/manager/config"
+)
+
+func (r *mutationResolver) StashPathAdd(ctx context.Context, input manager.StashPathAddInput) (*StashPathAddResult, error) {
+	jobID, err := manager.GetInstance().AddStashPath(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StashPathAddResult{
+		Stashes:   config.GetInstance().GetStashPaths(),
+		ScanJobID: jobIDString(jobID),
+	}, nil
+}
+
+func (r *mutationResolver) StashPathUpdate(ctx context.Context, input config.StashConfigInput) ([]*config.StashConfig, error) {
+	if err := manager.GetInstance().UpdateStashPath(ctx, input); err != nil {
+		return nil, err
+	}
+
+	return config.GetInstance().GetStashPaths(), nil
+}
+
+func (r *mutationResolver) StashPathRemove(ctx context.Context, input manager.StashPathRemoveInput) (*StashPathRemoveResult, error) {
+	ret, err := manager.GetInstance().RemoveStashPath(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StashPathRemoveResult{
+		Stashes:    config.GetInstance().GetStashPaths(),
+		FileCount:  ret.FileCount,
+		CleanJobID: jobIDString(ret.CleanJobID),
+	}, nil
+}
+
+func (r *mutationResolver) StashPathsReorder(ctx context.Context, paths []string) ([]*config.StashConfig, error) {
+	if err := manager.GetInstance().ReorderStashPaths(ctx, paths); err != nil {
+		return nil, err
+	}
+
+	return config.GetInstance().GetStashPaths(), nil
+}
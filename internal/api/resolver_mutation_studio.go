@@ -28,6 +28,10 @@ func (r *mutationResolver) getStudio(ctx context.Context, id int) (ret *models.S
 }
 
 func (r *mutationResolver) StudioCreate(ctx context.Context, input StudioCreateInput) (*models.Studio, error) {
+	if err := r.executePreHooks(ctx, 0, plugin.StudioCreatePre, &input, nil); err != nil {
+		return nil, err
+	}
+
 	// generate checksum from studio name rather than image
 	checksum := md5.FromString(input.Name)
 
@@ -76,6 +80,9 @@ func (r *mutationResolver) StudioCreate(ctx context.Context, input StudioCreateI
 	if input.IgnoreAutoTag != nil {
 		newStudio.IgnoreAutoTag = *input.IgnoreAutoTag
 	}
+	if input.Favorite != nil {
+		newStudio.Favorite = *input.Favorite
+	}
 
 	// Start the transaction and save the studio
 	var s *models.Studio
@@ -103,6 +110,13 @@ func (r *mutationResolver) StudioCreate(ctx context.Context, input StudioCreateI
 			}
 		}
 
+		// Save the external_ids
+		if input.ExternalIds != nil {
+			if err := qb.UpdateExternalIDs(ctx, s.ID, externalIDPtrSliceToSlice(input.ExternalIds)); err != nil {
+				return err
+			}
+		}
+
 		if len(input.Aliases) > 0 {
 			if err := studio.EnsureAliasesUnique(ctx, s.ID, input.Aliases, qb); err != nil {
 				return err
@@ -133,6 +147,10 @@ func (r *mutationResolver) StudioUpdate(ctx context.Context, input StudioUpdateI
 		inputMap: getUpdateInputMap(ctx),
 	}
 
+	if err := r.executePreHooks(ctx, studioID, plugin.StudioUpdatePre, &input, &translator); err != nil {
+		return nil, err
+	}
+
 	updatedStudio := models.StudioPartial{
 		ID:        studioID,
 		UpdatedAt: &models.SQLiteTimestamp{Timestamp: time.Now()},
@@ -159,6 +177,7 @@ func (r *mutationResolver) StudioUpdate(ctx context.Context, input StudioUpdateI
 	updatedStudio.ParentID = translator.nullInt64FromString(input.ParentID, "parent_id")
 	updatedStudio.Rating = translator.ratingConversion(input.Rating, input.Rating100)
 	updatedStudio.IgnoreAutoTag = input.IgnoreAutoTag
+	updatedStudio.Favorite = input.Favorite
 
 	// Start the transaction and save the studio
 	var s *models.Studio
@@ -195,6 +214,13 @@ func (r *mutationResolver) StudioUpdate(ctx context.Context, input StudioUpdateI
 			}
 		}
 
+		// Save the external_ids
+		if translator.hasField("external_ids") {
+			if err := qb.UpdateExternalIDs(ctx, studioID, externalIDPtrSliceToSlice(input.ExternalIds)); err != nil {
+				return err
+			}
+		}
+
 		if translator.hasField("aliases") {
 			if err := studio.EnsureAliasesUnique(ctx, studioID, input.Aliases, qb); err != nil {
 				return err
@@ -237,6 +263,10 @@ func (r *mutationResolver) StudiosDestroy(ctx context.Context, studioIDs []strin
 		return false, err
 	}
 
+	if err := autoBackup(ctx, "destroying studios", r.studioBackupRows(ids)); err != nil {
+		return false, err
+	}
+
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
 		qb := r.repository.Studio
 		for _, id := range ids {
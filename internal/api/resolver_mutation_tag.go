@@ -27,6 +27,10 @@ func (r *mutationResolver) getTag(ctx context.Context, id int) (ret *models.Tag,
 }
 
 func (r *mutationResolver) TagCreate(ctx context.Context, input TagCreateInput) (*models.Tag, error) {
+	if err := r.executePreHooks(ctx, 0, plugin.TagCreatePre, &input, nil); err != nil {
+		return nil, err
+	}
+
 	// Populate a new tag from the input
 	currentTime := time.Now()
 	newTag := models.Tag{
@@ -43,6 +47,14 @@ func (r *mutationResolver) TagCreate(ctx context.Context, input TagCreateInput)
 		newTag.IgnoreAutoTag = *input.IgnoreAutoTag
 	}
 
+	if input.ContentWarning != nil {
+		newTag.ContentWarning = *input.ContentWarning
+	}
+
+	if input.Favorite != nil {
+		newTag.Favorite = *input.Favorite
+	}
+
 	var imageData []byte
 	var err error
 
@@ -145,6 +157,10 @@ func (r *mutationResolver) TagUpdate(ctx context.Context, input TagUpdateInput)
 		inputMap: getUpdateInputMap(ctx),
 	}
 
+	if err := r.executePreHooks(ctx, tagID, plugin.TagUpdatePre, &input, &translator); err != nil {
+		return nil, err
+	}
+
 	imageIncluded := translator.hasField("image")
 	if input.Image != nil {
 		imageData, err = utils.ProcessImageInput(ctx, *input.Image)
@@ -187,9 +203,11 @@ func (r *mutationResolver) TagUpdate(ctx context.Context, input TagUpdateInput)
 		}
 
 		updatedTag := models.TagPartial{
-			ID:            tagID,
-			IgnoreAutoTag: input.IgnoreAutoTag,
-			UpdatedAt:     &models.SQLiteTimestamp{Timestamp: time.Now()},
+			ID:             tagID,
+			IgnoreAutoTag:  input.IgnoreAutoTag,
+			ContentWarning: input.ContentWarning,
+			Favorite:       input.Favorite,
+			UpdatedAt:      &models.SQLiteTimestamp{Timestamp: time.Now()},
 		}
 
 		if input.Name != nil && t.Name != *input.Name {
@@ -282,6 +300,10 @@ func (r *mutationResolver) TagsDestroy(ctx context.Context, tagIDs []string) (bo
 		return false, err
 	}
 
+	if err := autoBackup(ctx, "destroying tags", r.tagBackupRows(ids)); err != nil {
+		return false, err
+	}
+
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
 		qb := r.repository.Tag
 		for _, id := range ids {
@@ -317,44 +339,21 @@ func (r *mutationResolver) TagsMerge(ctx context.Context, input TagsMergeInput)
 		return nil, nil
 	}
 
+	if err := autoBackup(ctx, "merging tags", r.tagBackupRows(append([]int{destination}, source...))); err != nil {
+		return nil, err
+	}
+
 	var t *models.Tag
 	if err := r.withTxn(ctx, func(ctx context.Context) error {
 		qb := r.repository.Tag
 
-		var err error
-		t, err = qb.Find(ctx, destination)
-		if err != nil {
-			return err
-		}
-
-		if t == nil {
-			return fmt.Errorf("Tag with ID %d not found", destination)
-		}
-
-		parents, children, err := tag.MergeHierarchy(ctx, destination, source, qb)
-		if err != nil {
-			return err
-		}
-
-		if err = qb.Merge(ctx, source, destination); err != nil {
-			return err
-		}
-
-		err = qb.UpdateParentTags(ctx, destination, parents)
-		if err != nil {
-			return err
-		}
-		err = qb.UpdateChildTags(ctx, destination, children)
-		if err != nil {
-			return err
-		}
-
-		err = tag.ValidateHierarchy(ctx, t, parents, children, qb)
+		plan, err := tag.Merge(ctx, qb, source, destination)
 		if err != nil {
 			logger.Errorf("Error merging tag: %s", err)
 			return err
 		}
 
+		t = plan.Destination
 		return nil
 	}); err != nil {
 		return nil, err
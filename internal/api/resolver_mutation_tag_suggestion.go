@@ -0,0 +1,112 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/plugin"
+	"github.com/stashapp/stash/pkg/sliceutil/intslice"
+)
+
+type tagSuggestionIDs struct {
+	sceneID int
+	tagID   int
+}
+
+func tagSuggestionInputIDs(input []*TagSuggestionInput) ([]tagSuggestionIDs, error) {
+	ret := make([]tagSuggestionIDs, len(input))
+	for i, v := range input {
+		sceneID, err := strconv.Atoi(v.SceneID)
+		if err != nil {
+			return nil, fmt.Errorf("converting scene id: %w", err)
+		}
+
+		tagID, err := strconv.Atoi(v.TagID)
+		if err != nil {
+			return nil, fmt.Errorf("converting tag id: %w", err)
+		}
+
+		ret[i] = tagSuggestionIDs{sceneID: sceneID, tagID: tagID}
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) TagSuggestionsAccept(ctx context.Context, input []*TagSuggestionInput) (bool, error) {
+	ids, err := tagSuggestionInputIDs(input)
+	if err != nil {
+		return false, err
+	}
+
+	// group the tags by scene so that each scene is updated once
+	var sceneIDs []int
+	sceneTagIDs := make(map[int][]int)
+	for _, v := range ids {
+		if _, found := sceneTagIDs[v.sceneID]; !found {
+			sceneIDs = append(sceneIDs, v.sceneID)
+		}
+		sceneTagIDs[v.sceneID] = append(sceneTagIDs[v.sceneID], v.tagID)
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		for _, sceneID := range sceneIDs {
+			tagIDs := sceneTagIDs[sceneID]
+
+			updatedScene := models.NewScenePartial()
+			updatedScene.TagIDs = &models.UpdateIDs{
+				IDs:  tagIDs,
+				Mode: models.RelationshipUpdateModeAdd,
+			}
+
+			if _, err := r.repository.Scene.UpdatePartial(ctx, sceneID, updatedScene); err != nil {
+				return fmt.Errorf("updating scene %d: %w", sceneID, err)
+			}
+
+			for _, tagID := range tagIDs {
+				if err := r.repository.TagSuggestion.Destroy(ctx, sceneID, tagID); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return false, err
+	}
+
+	for _, sceneID := range sceneIDs {
+		hookInput := BulkSceneUpdateInput{
+			Ids: []string{strconv.Itoa(sceneID)},
+			TagIds: &BulkUpdateIds{
+				Ids:  intslice.IntSliceToStringSlice(sceneTagIDs[sceneID]),
+				Mode: models.RelationshipUpdateModeAdd,
+			},
+		}
+		r.hookExecutor.ExecutePostHooks(ctx, sceneID, plugin.SceneUpdatePost, hookInput, []string{"tag_ids"})
+	}
+
+	return true, nil
+}
+
+func (r *mutationResolver) TagSuggestionsReject(ctx context.Context, input []*TagSuggestionInput) (bool, error) {
+	ids, err := tagSuggestionInputIDs(input)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		for _, v := range ids {
+			if err := r.repository.TagSuggestion.Reject(ctx, v.sceneID, v.tagID); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
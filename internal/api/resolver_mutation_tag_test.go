@@ -44,6 +44,10 @@ type mockHookExecutor struct{}
 func (*mockHookExecutor) ExecutePostHooks(ctx context.Context, id int, hookType plugin.HookTriggerEnum, input interface{}, inputFields []string) {
 }
 
+func (*mockHookExecutor) ExecutePreHooks(ctx context.Context, id int, hookType plugin.HookTriggerEnum, input interface{}) (map[string]interface{}, error) {
+	return nil, nil
+}
+
 func TestTagCreate(t *testing.T) {
 	r := newResolver()
 
@@ -0,0 +1,30 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/theme"
+)
+
+func (r *mutationResolver) ActivateTheme(ctx context.Context, id *string) (bool, error) {
+	c := config.GetInstance()
+
+	var activeTheme string
+	if id != nil && *id != "" {
+		// ensure the theme exists and is valid
+		t, err := theme.LoadTheme(c.GetThemesPath(), *id)
+		if err != nil {
+			return false, fmt.Errorf("loading theme %s: %w", *id, err)
+		}
+		activeTheme = t.ID()
+	}
+
+	c.Set(config.ActiveTheme, activeTheme)
+	if err := c.Write(); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
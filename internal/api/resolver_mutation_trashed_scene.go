@@ -0,0 +1,36 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/internal/manager"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
+)
+
+func (r *mutationResolver) TrashedScenesRestore(ctx context.Context, ids []string) ([]*models.Scene, error) {
+	idInts, err := stringslice.StringSliceToIntSlice(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	return manager.GetInstance().RestoreTrashedScenes(ctx, idInts)
+}
+
+func (r *mutationResolver) TrashedScenesPurge(ctx context.Context, ids []string) (int, error) {
+	var idInts []int
+	if ids != nil {
+		var err error
+		idInts, err = stringslice.StringSliceToIntSlice(ids)
+		if err != nil {
+			return 0, err
+		}
+
+		// purge none rather than all if an empty list is provided
+		if len(idInts) == 0 {
+			return 0, nil
+		}
+	}
+
+	return manager.GetInstance().PurgeTrashedScenes(ctx, idInts)
+}
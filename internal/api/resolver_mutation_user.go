@@ -0,0 +1,352 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stashapp/stash/internal/manager"
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/hash"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/session"
+)
+
+// length in bytes of generated user API keys
+const userAPIKeyLength = 32
+
+var ErrNoCredentials = errors.New("a username and password must be configured before adding users")
+
+// validateUsername returns an error if the username is empty, or is used by
+// the configured administrator or another user.
+func (r *mutationResolver) validateUsername(ctx context.Context, id int, username string) error {
+	if strings.TrimSpace(username) == "" {
+		return errors.New("username must not be empty")
+	}
+
+	if strings.EqualFold(username, config.GetInstance().GetUsername()) {
+		return fmt.Errorf("username %s is used by the administrator", username)
+	}
+
+	existing, err := r.repository.User.FindByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	if existing != nil && existing.ID != id {
+		return fmt.Errorf("username %s is already in use", username)
+	}
+
+	return nil
+}
+
+func (r *mutationResolver) UserCreate(ctx context.Context, input UserCreateInput) (*models.User, error) {
+	if !config.GetInstance().HasCredentials() {
+		return nil, ErrNoCredentials
+	}
+
+	translator := changesetTranslator{
+		inputMap: getUpdateInputMap(ctx),
+	}
+
+	restrictionProfileID, err := translator.intPtrFromString(input.RestrictionProfileID, "restriction_profile_id")
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Password == "" {
+		return nil, errors.New("password must not be empty")
+	}
+
+	passwordHash, err := session.HashPassword(input.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret *models.User
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		if err := r.validateUsername(ctx, 0, input.Username); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		ret, err = r.repository.User.Create(ctx, models.User{
+			Username:     input.Username,
+			PasswordHash: passwordHash,
+			Role:         input.Role,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+
+			RestrictionProfileID: restrictionProfileID,
+		})
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) UserUpdate(ctx context.Context, input UserUpdateInput) (*models.User, error) {
+	id, err := strconv.Atoi(input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var passwordHash string
+	if input.Password != nil {
+		if *input.Password == "" {
+			return nil, errors.New("password must not be empty")
+		}
+
+		passwordHash, err = session.HashPassword(*input.Password)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	translator := changesetTranslator{
+		inputMap: getUpdateInputMap(ctx),
+	}
+
+	restrictionProfileID, err := translator.intPtrFromString(input.RestrictionProfileID, "restriction_profile_id")
+	if err != nil {
+		return nil, err
+	}
+
+	var ret *models.User
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		qb := r.repository.User
+
+		u, err := qb.Find(ctx, id)
+		if err != nil {
+			return err
+		}
+		if u == nil {
+			return fmt.Errorf("user with id %d not found", id)
+		}
+
+		if input.Username != nil {
+			if err := r.validateUsername(ctx, id, *input.Username); err != nil {
+				return err
+			}
+			u.Username = *input.Username
+		}
+		if passwordHash != "" {
+			u.PasswordHash = passwordHash
+		}
+		if input.Role != nil {
+			u.Role = *input.Role
+		}
+		if translator.hasField("restriction_profile_id") {
+			u.RestrictionProfileID = restrictionProfileID
+		}
+		u.UpdatedAt = time.Now()
+
+		ret, err = qb.Update(ctx, *u)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) UserDestroy(ctx context.Context, id string) (bool, error) {
+	userID, err := strconv.Atoi(id)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		return r.repository.User.Destroy(ctx, userID)
+	}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *mutationResolver) UserLinkExternalIdentity(ctx context.Context, input UserLinkExternalIdentityInput) (*models.User, error) {
+	userID, err := strconv.Atoi(input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Subject == "" {
+		return nil, errors.New("subject must not be empty")
+	}
+
+	found := false
+	for _, p := range manager.GetInstance().SessionStore.AuthProviders() {
+		found = found || p.ID() == input.Provider
+	}
+	if !found {
+		return nil, fmt.Errorf("authentication provider %s is not configured", input.Provider)
+	}
+
+	var ret *models.User
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		qb := r.repository.User
+
+		existing, err := qb.FindByExternalIdentity(ctx, input.Provider, input.Subject)
+		if err != nil {
+			return err
+		}
+		if existing != nil && existing.ID != userID {
+			return fmt.Errorf("the account is already linked to user %s", existing.Username)
+		}
+
+		ret, err = qb.Find(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if ret == nil {
+			return fmt.Errorf("user with id %d not found", userID)
+		}
+
+		return qb.LinkExternalIdentity(ctx, userID, models.UserExternalIdentity{
+			Provider: input.Provider,
+			Subject:  input.Subject,
+		})
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) UserUnlinkExternalIdentity(ctx context.Context, input UserUnlinkExternalIdentityInput) (*models.User, error) {
+	userID, err := strconv.Atoi(input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret *models.User
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		qb := r.repository.User
+
+		ret, err = qb.Find(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if ret == nil {
+			return fmt.Errorf("user with id %d not found", userID)
+		}
+
+		return qb.UnlinkExternalIdentity(ctx, userID, input.Provider)
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// currentDatabaseUser returns the user making the request, or an error if
+// the request was not made by a user stored in the database.
+func (r *mutationResolver) currentDatabaseUser(ctx context.Context) (*models.User, error) {
+	userID := session.GetCurrentUserID(ctx)
+	if userID == nil || *userID == "" || *userID == config.GetInstance().GetUsername() {
+		return nil, errors.New("the configured administrator is changed in the settings")
+	}
+
+	u, err := r.repository.User.FindByUsername(ctx, *userID)
+	if err != nil {
+		return nil, err
+	}
+	if u == nil {
+		return nil, fmt.Errorf("user %s not found", *userID)
+	}
+
+	return u, nil
+}
+
+func (r *mutationResolver) UserChangePassword(ctx context.Context, currentPassword string, newPassword string) (bool, error) {
+	if newPassword == "" {
+		return false, errors.New("password must not be empty")
+	}
+
+	var u *models.User
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		var err error
+		u, err = r.currentDatabaseUser(ctx)
+		return err
+	}); err != nil {
+		return false, err
+	}
+
+	if manager.GetInstance().SessionStore.ValidateUserCredentials(ctx, u.Username, currentPassword) == nil {
+		return false, session.ErrInvalidCredentials
+	}
+
+	passwordHash, err := session.HashPassword(newPassword)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		u.PasswordHash = passwordHash
+		u.UpdatedAt = time.Now()
+		_, err := r.repository.User.Update(ctx, *u)
+		return err
+	}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *mutationResolver) UserGenerateAPIKey(ctx context.Context, input UserGenerateAPIKeyInput) (string, error) {
+	var newAPIKey string
+	var apiKeyHash *string
+	if input.Clear == nil || !*input.Clear {
+		var err error
+		newAPIKey, err = hash.GenerateRandomKey(userAPIKeyLength)
+		if err != nil {
+			return "", err
+		}
+
+		h := session.HashAPIKey(newAPIKey)
+		apiKeyHash = &h
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		qb := r.repository.User
+
+		var u *models.User
+		var err error
+		if input.ID == nil {
+			u, err = r.currentDatabaseUser(ctx)
+		} else {
+			var id int
+			id, err = strconv.Atoi(*input.ID)
+			if err != nil {
+				return err
+			}
+
+			u, err = qb.Find(ctx, id)
+			if err == nil && u == nil {
+				err = fmt.Errorf("user with id %d not found", id)
+			}
+		}
+		if err != nil {
+			return err
+		}
+
+		if userID := session.GetCurrentUserID(ctx); (userID == nil || *userID != u.Username) && !session.HasUserRole(ctx, models.UserRoleAdmin) {
+			return fmt.Errorf("the %s role is required to generate API keys of other users", models.UserRoleAdmin)
+		}
+
+		u.APIKeyHash = apiKeyHash
+		u.UpdatedAt = time.Now()
+		_, err = qb.Update(ctx, *u)
+		return err
+	}); err != nil {
+		return "", err
+	}
+
+	return newAPIKey, nil
+}
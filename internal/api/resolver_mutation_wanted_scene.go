@@ -0,0 +1,242 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stashapp/stash/internal/manager"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+func wantedSceneString(value *string) *string {
+	if value == nil {
+		return nil
+	}
+
+	v := strings.TrimSpace(*value)
+	if v == "" {
+		return nil
+	}
+
+	return &v
+}
+
+func wantedScenePhash(value *string) (*int64, error) {
+	v := wantedSceneString(value)
+	if v == nil {
+		return nil, nil
+	}
+
+	phash, err := utils.StringToPhash(*v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid phash %q: %w", *v, err)
+	}
+
+	return &phash, nil
+}
+
+func validateWantedScene(w models.WantedScene) error {
+	if w.Title == nil && w.URL == nil && w.StashID == nil && w.Checksum == nil && w.OSHash == nil && w.Phash == nil {
+		return errors.New("one of title, url, stash_id, checksum, oshash or phash must be set")
+	}
+
+	return nil
+}
+
+func (r *mutationResolver) WantedSceneCreate(ctx context.Context, input WantedSceneCreateInput) (ret *models.WantedScene, err error) {
+	phash, err := wantedScenePhash(input.Phash)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	newWanted := models.WantedScene{
+		Title:            wantedSceneString(input.Title),
+		URL:              wantedSceneString(input.URL),
+		Details:          wantedSceneString(input.Details),
+		Date:             wantedSceneString(input.Date),
+		StashBoxEndpoint: wantedSceneString(input.StashBoxEndpoint),
+		StashID:          wantedSceneString(input.StashID),
+		Checksum:         wantedSceneString(input.Checksum),
+		OSHash:           wantedSceneString(input.Oshash),
+		Phash:            phash,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+
+	if err := validateWantedScene(newWanted); err != nil {
+		return nil, err
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.WantedScene.Create(ctx, newWanted)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) WantedSceneUpdate(ctx context.Context, input WantedSceneUpdateInput) (ret *models.WantedScene, err error) {
+	id, err := strconv.Atoi(input.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	translator := changesetTranslator{
+		inputMap: getUpdateInputMap(ctx),
+	}
+
+	phash, err := wantedScenePhash(input.Phash)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		qb := r.repository.WantedScene
+
+		existing, err := qb.Find(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if existing == nil {
+			return fmt.Errorf("wanted scene with id %d not found", id)
+		}
+
+		updated := *existing
+		if translator.hasField("title") {
+			updated.Title = wantedSceneString(input.Title)
+		}
+		if translator.hasField("url") {
+			updated.URL = wantedSceneString(input.URL)
+		}
+		if translator.hasField("details") {
+			updated.Details = wantedSceneString(input.Details)
+		}
+		if translator.hasField("date") {
+			updated.Date = wantedSceneString(input.Date)
+		}
+		if translator.hasField("stash_box_endpoint") {
+			updated.StashBoxEndpoint = wantedSceneString(input.StashBoxEndpoint)
+		}
+		if translator.hasField("stash_id") {
+			updated.StashID = wantedSceneString(input.StashID)
+		}
+		if translator.hasField("checksum") {
+			updated.Checksum = wantedSceneString(input.Checksum)
+		}
+		if translator.hasField("oshash") {
+			updated.OSHash = wantedSceneString(input.Oshash)
+		}
+		if translator.hasField("phash") {
+			updated.Phash = phash
+		}
+		updated.UpdatedAt = time.Now()
+
+		if err := validateWantedScene(updated); err != nil {
+			return err
+		}
+
+		ret, err = qb.Update(ctx, updated)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *mutationResolver) WantedSceneDestroy(ctx context.Context, id string) (bool, error) {
+	idInt, err := strconv.Atoi(id)
+	if err != nil {
+		return false, err
+	}
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		return r.repository.WantedScene.Destroy(ctx, idInt)
+	}); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *mutationResolver) WantedScenesCreateFromURLs(ctx context.Context, urls []string) (string, error) {
+	now := time.Now()
+	pending := models.WantedSceneScrapeStatusPending
+
+	if err := r.withTxn(ctx, func(ctx context.Context) error {
+		qb := r.repository.WantedScene
+		seen := make(map[string]bool)
+
+		for _, u := range urls {
+			u = strings.TrimSpace(u)
+			if u == "" || seen[u] {
+				continue
+			}
+			seen[u] = true
+
+			existing, err := qb.FindByURL(ctx, u)
+			if err != nil {
+				return err
+			}
+			if len(existing) > 0 {
+				continue
+			}
+
+			url := u
+			if _, err := qb.Create(ctx, models.WantedScene{
+				URL:          &url,
+				ScrapeStatus: &pending,
+				CreatedAt:    now,
+				UpdatedAt:    now,
+			}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return "", err
+	}
+
+	jobID := manager.GetInstance().ScrapeWantedScenes(ctx)
+	return strconv.Itoa(jobID), nil
+}
+
+func (r *mutationResolver) WantedScenesScrape(ctx context.Context, retryFailed *bool) (string, error) {
+	if retryFailed != nil && *retryFailed {
+		if err := r.withTxn(ctx, func(ctx context.Context) error {
+			qb := r.repository.WantedScene
+			failed, err := qb.FindByScrapeStatus(ctx, models.WantedSceneScrapeStatusFailed)
+			if err != nil {
+				return err
+			}
+
+			pending := models.WantedSceneScrapeStatusPending
+			for _, w := range failed {
+				updated := *w
+				updated.ScrapeStatus = &pending
+				updated.ScrapeError = nil
+				updated.UpdatedAt = time.Now()
+				if _, err := qb.Update(ctx, updated); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}); err != nil {
+			return "", err
+		}
+	}
+
+	jobID := manager.GetInstance().ScrapeWantedScenes(ctx)
+	return strconv.Itoa(jobID), nil
+}
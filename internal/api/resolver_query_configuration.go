@@ -10,11 +10,14 @@ import (
 	"github.com/stashapp/stash/pkg/fsutil"
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/scraper/stashbox"
+	"github.com/stashapp/stash/pkg/sliceutil/intslice"
 	"golang.org/x/text/collate"
 )
 
 func (r *queryResolver) Configuration(ctx context.Context) (*ConfigResult, error) {
-	return makeConfigResult(), nil
+	ret := makeConfigResult()
+	redactConfigSecrets(ctx, ret)
+	return ret, nil
 }
 
 func (r *queryResolver) Directory(ctx context.Context, path, locale *string) (*Directory, error) {
@@ -81,48 +84,92 @@ func makeConfigGeneralResult() *ConfigGeneralResult {
 
 	scraperUserAgent := config.GetScraperUserAgent()
 	scraperCDPPath := config.GetScraperCDPPath()
+	mediaAccessToken := config.GetMediaAccessToken()
 
 	return &ConfigGeneralResult{
-		Stashes:                      config.GetStashPaths(),
-		DatabasePath:                 config.GetDatabasePath(),
-		BackupDirectoryPath:          config.GetBackupDirectoryPath(),
-		GeneratedPath:                config.GetGeneratedPath(),
-		MetadataPath:                 config.GetMetadataPath(),
-		ConfigFilePath:               config.GetConfigFile(),
-		ScrapersPath:                 config.GetScrapersPath(),
-		CachePath:                    config.GetCachePath(),
-		CalculateMd5:                 config.IsCalculateMD5(),
-		VideoFileNamingAlgorithm:     config.GetVideoFileNamingAlgorithm(),
-		ParallelTasks:                config.GetParallelTasks(),
-		PreviewAudio:                 config.GetPreviewAudio(),
-		PreviewSegments:              config.GetPreviewSegments(),
-		PreviewSegmentDuration:       config.GetPreviewSegmentDuration(),
-		PreviewExcludeStart:          config.GetPreviewExcludeStart(),
-		PreviewExcludeEnd:            config.GetPreviewExcludeEnd(),
-		PreviewPreset:                config.GetPreviewPreset(),
-		MaxTranscodeSize:             &maxTranscodeSize,
-		MaxStreamingTranscodeSize:    &maxStreamingTranscodeSize,
-		WriteImageThumbnails:         config.IsWriteImageThumbnails(),
-		APIKey:                       config.GetAPIKey(),
-		Username:                     config.GetUsername(),
-		Password:                     config.GetPasswordHash(),
-		MaxSessionAge:                config.GetMaxSessionAge(),
-		LogFile:                      &logFile,
-		LogOut:                       config.GetLogOut(),
-		LogLevel:                     config.GetLogLevel(),
-		LogAccess:                    config.GetLogAccess(),
-		VideoExtensions:              config.GetVideoExtensions(),
-		ImageExtensions:              config.GetImageExtensions(),
-		GalleryExtensions:            config.GetGalleryExtensions(),
-		CreateGalleriesFromFolders:   config.GetCreateGalleriesFromFolders(),
-		Excludes:                     config.GetExcludes(),
-		ImageExcludes:                config.GetImageExcludes(),
-		CustomPerformerImageLocation: &customPerformerImageLocation,
-		ScraperUserAgent:             &scraperUserAgent,
-		ScraperCertCheck:             config.GetScraperCertCheck(),
-		ScraperCDPPath:               &scraperCDPPath,
-		StashBoxes:                   config.GetStashBoxes(),
-		PythonPath:                   config.GetPythonPath(),
+		Stashes:                           config.GetStashPaths(),
+		DatabasePath:                      config.GetDatabasePath(),
+		BackupDirectoryPath:               config.GetBackupDirectoryPath(),
+		GeneratedPath:                     config.GetGeneratedPath(),
+		MetadataPath:                      config.GetMetadataPath(),
+		ConfigFilePath:                    config.GetConfigFile(),
+		ScrapersPath:                      config.GetScrapersPath(),
+		CachePath:                         config.GetCachePath(),
+		CalculateMd5:                      config.IsCalculateMD5(),
+		VideoFileNamingAlgorithm:          config.GetVideoFileNamingAlgorithm(),
+		ParallelTasks:                     config.GetParallelTasks(),
+		PreviewAudio:                      config.GetPreviewAudio(),
+		PreviewSegments:                   config.GetPreviewSegments(),
+		PreviewSegmentDuration:            config.GetPreviewSegmentDuration(),
+		PreviewExcludeStart:               config.GetPreviewExcludeStart(),
+		PreviewExcludeEnd:                 config.GetPreviewExcludeEnd(),
+		PreviewPreset:                     config.GetPreviewPreset(),
+		MaxTranscodeSize:                  &maxTranscodeSize,
+		MaxStreamingTranscodeSize:         &maxStreamingTranscodeSize,
+		TranscodeProfiles:                 config.GetTranscodeProfiles(),
+		StreamTranscodeProfiles:           config.GetStreamTranscodeProfiles(),
+		PreviewTranscodeProfile:           config.GetPreviewTranscodeProfile(),
+		HlsVariants:                       config.GetHLSVariants(),
+		WriteImageThumbnails:              config.IsWriteImageThumbnails(),
+		APIKey:                            config.GetAPIKey(),
+		Username:                          config.GetUsername(),
+		Password:                          config.GetPasswordHash(),
+		MaxSessionAge:                     config.GetMaxSessionAge(),
+		SessionLockPin:                    config.GetSessionLockPinHash(),
+		SessionLockTimeout:                int(config.GetSessionLockTimeout().Minutes()),
+		QuickHideSafeTags:                 intslice.IntSliceToStringSlice(config.GetQuickHideSafeTags()),
+		LogFile:                           &logFile,
+		LogOut:                            config.GetLogOut(),
+		LogLevel:                          config.GetLogLevel(),
+		LogAccess:                         config.GetLogAccess(),
+		VideoExtensions:                   config.GetVideoExtensions(),
+		ImageExtensions:                   config.GetImageExtensions(),
+		GalleryExtensions:                 config.GetGalleryExtensions(),
+		CreateGalleriesFromFolders:        config.GetCreateGalleriesFromFolders(),
+		Excludes:                          config.GetExcludes(),
+		ImageExcludes:                     config.GetImageExcludes(),
+		CustomPerformerImageLocation:      &customPerformerImageLocation,
+		ScraperUserAgent:                  &scraperUserAgent,
+		ScraperCertCheck:                  config.GetScraperCertCheck(),
+		ScraperCDPPath:                    &scraperCDPPath,
+		StashBoxes:                        config.GetStashBoxes(),
+		PythonPath:                        config.GetPythonPath(),
+		WatchLibraryEnabled:               config.GetWatchLibraryEnabled(),
+		WatchLibraryDebounce:              config.GetWatchLibraryDebounce(),
+		ScheduledTasks:                    config.GetScheduledTasks(),
+		ScrapeRefreshRules:                config.GetScrapeRefreshRules(),
+		ResumeInterruptedJobs:             config.GetResumeInterruptedJobs(),
+		DownloadHookEnabled:               config.GetDownloadHookEnabled(),
+		DownloadHookAutoTag:               config.GetDownloadHookAutoTag(),
+		UploadInboxPath:                   config.GetUploadInboxPath(),
+		InboxEnabled:                      config.GetInboxEnabled(),
+		ActivityLogEnabled:                config.GetActivityLogEnabled(),
+		ActivityLogRetentionDays:          config.GetActivityLogRetentionDays(),
+		JobArtifactRetentionDays:          config.GetJobArtifactRetentionDays(),
+		TrashPath:                         config.GetTrashPath(),
+		TrashRetentionDays:                config.GetTrashRetentionDays(),
+		DeletedFilesPath:                  config.GetDeletedFilesPath(),
+		DeletedFilesPreserveStructure:     config.GetDeletedFilesPreserveStructure(),
+		DeletedFilesMaxSize:               config.GetDeletedFilesMaxSize(),
+		StashBoxFingerprintCacheDays:      config.GetStashBoxFingerprintCacheDays(),
+		RenameTemplate:                    config.GetRenameTemplate(),
+		MediaAllowedSubnets:               config.GetMediaAllowedSubnets(),
+		MediaAccessToken:                  &mediaAccessToken,
+		InteractiveHeatmapRenderAxes:      config.GetInteractiveHeatmapRenderAxes(),
+		InteractiveHeatmapSprite:          config.GetInteractiveHeatmapSprite(),
+		InteractiveHeatmapWidth:           config.GetInteractiveHeatmapWidth(),
+		InteractiveHeatmapHeight:          config.GetInteractiveHeatmapHeight(),
+		InteractiveHeatmapSegments:        config.GetInteractiveHeatmapSegments(),
+		InteractiveHeatmapColormap:        config.GetInteractiveHeatmapColormap(),
+		InteractiveHeatmapColormapStops:   config.GetInteractiveHeatmapColormapStops(),
+		InteractiveHeatmapBackgroundColor: config.GetInteractiveHeatmapBackgroundColor(),
+		BulkUndoWindow:                    config.GetBulkUndoWindow(),
+		AutoBackup:                        config.GetAutoBackup(),
+		AutoBackupMaxDatabaseSize:         config.GetAutoBackupMaxDatabaseSize(),
+		LowMemoryMode:                     config.GetLowMemoryMode(),
+		InteractiveMarkerTag:              config.GetInteractiveMarkerTag(),
+		ImportChapters:                    config.GetImportChapters(),
+		ChapterMarkerTag:                  config.GetChapterMarkerTag(),
 	}
 }
 
@@ -207,6 +254,7 @@ func makeConfigScrapingResult() *ConfigScrapingResult {
 		ScraperCertCheck:   config.GetScraperCertCheck(),
 		ScraperCDPPath:     &scraperCDPPath,
 		ExcludeTagPatterns: config.GetScraperExcludeTagPatterns(),
+		ScraperCacheDays:   config.GetScraperCacheDays(),
 	}
 }
 
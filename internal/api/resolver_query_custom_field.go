@@ -0,0 +1,23 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func (r *queryResolver) AllCustomFields(ctx context.Context, objectType *models.CustomFieldObjectType) (ret []*models.CustomField, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		qb := r.repository.CustomField
+		if objectType != nil {
+			ret, err = qb.FindByObjectType(ctx, *objectType)
+		} else {
+			ret, err = qb.All(ctx)
+		}
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
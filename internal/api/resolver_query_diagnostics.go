@@ -0,0 +1,15 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/internal/manager"
+)
+
+func (r *queryResolver) Diagnostics(ctx context.Context) (*manager.Diagnostics, error) {
+	return manager.GetInstance().GetDiagnostics(ctx), nil
+}
+
+func (r *diagnosticsResolver) RemoteAddr(ctx context.Context, obj *manager.Diagnostics) (string, error) {
+	return getActivityActor(ctx).RemoteAddr, nil
+}
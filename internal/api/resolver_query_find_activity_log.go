@@ -0,0 +1,26 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func (r *queryResolver) FindActivityLog(ctx context.Context, activityFilter *models.ActivityLogFilterType, filter *models.FindFilterType) (ret *FindActivityLogResultType, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		entries, count, err := r.repository.ActivityLog.Query(ctx, activityFilter, filter)
+		if err != nil {
+			return err
+		}
+
+		ret = &FindActivityLogResultType{
+			Count:   count,
+			Entries: entries,
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
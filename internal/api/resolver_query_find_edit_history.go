@@ -0,0 +1,26 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func (r *queryResolver) FindEditHistory(ctx context.Context, historyFilter *models.EditHistoryFilterType, filter *models.FindFilterType) (ret *FindEditHistoryResultType, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		entries, count, err := r.repository.EditHistory.Query(ctx, historyFilter, filter)
+		if err != nil {
+			return err
+		}
+
+		ret = &FindEditHistoryResultType{
+			Count:   count,
+			Entries: entries,
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"context"
+	"errors"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const defaultFavoritesLimit = 25
+
+func (r *queryResolver) FindFavorites(ctx context.Context, limit *int) (ret *FindFavoritesResultType, err error) {
+	perPage := defaultFavoritesLimit
+	if limit != nil {
+		perPage = *limit
+	}
+	if perPage < 1 && perPage != models.PerPageAll {
+		return nil, errors.New("limit must be positive or -1")
+	}
+
+	sort := "name"
+	findFilter := &models.FindFilterType{
+		PerPage: &perPage,
+		Sort:    &sort,
+	}
+	favorite := true
+
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret = &FindFavoritesResultType{}
+
+		ret.Performers, _, err = r.repository.Performer.Query(ctx, &models.PerformerFilterType{
+			FilterFavorites: &favorite,
+		}, findFilter)
+		if err != nil {
+			return err
+		}
+
+		ret.Tags, _, err = r.repository.Tag.Query(ctx, &models.TagFilterType{
+			Favorite: &favorite,
+		}, findFilter)
+		if err != nil {
+			return err
+		}
+
+		ret.Studios, _, err = r.repository.Studio.Query(ctx, &models.StudioFilterType{
+			Favorite: &favorite,
+		}, findFilter)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
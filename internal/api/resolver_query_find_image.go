@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"github.com/99designs/gqlgen/graphql"
+	"github.com/stashapp/stash/pkg/image"
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
 )
@@ -84,3 +85,49 @@ func (r *queryResolver) FindImages(ctx context.Context, imageFilter *models.Imag
 
 	return ret, nil
 }
+
+func (r *queryResolver) FindImageBursts(ctx context.Context, galleryID string, distance *int) (ret []*ImageBurst, err error) {
+	id, err := strconv.Atoi(galleryID)
+	if err != nil {
+		return nil, err
+	}
+
+	dist := 4
+	if distance != nil {
+		dist = *distance
+	}
+
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		images, err := r.repository.Image.FindByGalleryID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		// hidden images have already been collapsed
+		var visible []*models.Image
+		for _, i := range images {
+			if i.Hidden {
+				continue
+			}
+
+			if err := i.LoadPrimaryFile(ctx, r.repository.File); err != nil {
+				return err
+			}
+			visible = append(visible, i)
+		}
+
+		for _, b := range image.FindBursts(visible, dist) {
+			ret = append(ret, &ImageBurst{
+				Keep:      b.Keep,
+				Redundant: b.Redundant,
+				Distance:  b.Distance,
+			})
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
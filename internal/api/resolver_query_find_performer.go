@@ -2,9 +2,11 @@ package api
 
 import (
 	"context"
+	"errors"
 	"strconv"
 
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/performer"
 )
 
 func (r *queryResolver) FindPerformer(ctx context.Context, id string) (ret *models.Performer, err error) {
@@ -52,3 +54,49 @@ func (r *queryResolver) AllPerformers(ctx context.Context) (ret []*models.Perfor
 
 	return ret, nil
 }
+
+func (r *queryResolver) FindDuplicatePerformers(ctx context.Context, minSimilarity *float64) (ret [][]*models.Performer, err error) {
+	min := performer.DefaultDuplicateMinSimilarity
+	if minSimilarity != nil {
+		if *minSimilarity <= 0 || *minSimilarity > 1 {
+			return nil, errors.New("min_similarity must be greater than 0 and at most 1")
+		}
+		min = *minSimilarity
+	}
+
+	var candidates []performer.DuplicateCandidate
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		qb := r.repository.Performer
+		performers, err := qb.All(ctx)
+		if err != nil {
+			return err
+		}
+
+		candidates = make([]performer.DuplicateCandidate, len(performers))
+		for i, p := range performers {
+			if err := p.LoadAliases(ctx, qb); err != nil {
+				return err
+			}
+			if err := p.LoadStashIDs(ctx, qb); err != nil {
+				return err
+			}
+
+			candidates[i] = performer.DuplicateCandidate{
+				Performer: p,
+				Aliases:   p.Aliases.List(),
+				StashIDs:  p.StashIDs.List(),
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	ret = performer.FindDuplicates(candidates, min)
+	if ret == nil {
+		ret = [][]*models.Performer{}
+	}
+
+	return ret, nil
+}
@@ -2,9 +2,12 @@ package api
 
 import (
 	"context"
+	"encoding/json"
 	"strconv"
 
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/savedfilter"
+	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
 )
 
 func (r *queryResolver) FindSavedFilter(ctx context.Context, id string) (ret *models.SavedFilter, err error) {
@@ -45,3 +48,49 @@ func (r *queryResolver) FindDefaultFilter(ctx context.Context, mode models.Filte
 	}
 	return ret, err
 }
+
+func (r *queryResolver) ExportSavedFilters(ctx context.Context, ids []string) (string, error) {
+	var doc *savedfilter.Document
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		qb := r.repository.SavedFilter
+
+		var filters []*models.SavedFilter
+		if ids == nil {
+			for _, mode := range models.AllFilterMode {
+				modeFilters, err := qb.FindByMode(ctx, mode)
+				if err != nil {
+					return err
+				}
+				filters = append(filters, modeFilters...)
+			}
+		} else {
+			idInts, err := stringslice.StringSliceToIntSlice(ids)
+			if err != nil {
+				return err
+			}
+
+			filters, err = qb.FindMany(ctx, idInts, false)
+			if err != nil {
+				return err
+			}
+		}
+
+		resolver := savedfilter.Resolver{
+			TagFinder:    r.repository.Tag,
+			StudioFinder: r.repository.Studio,
+		}
+
+		var err error
+		doc, err = resolver.Export(ctx, filters)
+		return err
+	}); err != nil {
+		return "", err
+	}
+
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
@@ -7,6 +7,8 @@ import (
 	"github.com/99designs/gqlgen/graphql"
 	"github.com/stashapp/stash/internal/manager"
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene"
+	"github.com/stashapp/stash/pkg/sliceutil/intslice"
 	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
 )
 
@@ -232,3 +234,99 @@ func (r *queryResolver) FindDuplicateScenes(ctx context.Context, distance *int)
 
 	return ret, nil
 }
+
+func (r *queryResolver) FindDuplicateSceneGroups(ctx context.Context, distance *int) (ret []*DuplicateSceneGroup, err error) {
+	dist := 0
+	if distance != nil {
+		dist = *distance
+	}
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		groups, err := r.repository.Scene.FindDuplicates(ctx, dist)
+		if err != nil {
+			return err
+		}
+
+		for _, scenes := range groups {
+			for _, s := range scenes {
+				if err := s.LoadPrimaryFile(ctx, r.repository.File); err != nil {
+					return err
+				}
+			}
+
+			group := &DuplicateSceneGroup{}
+			for i, c := range scene.CompareDuplicates(scenes) {
+				group.Scenes = append(group.Scenes, &DuplicateSceneCandidate{
+					Scene:             scenes[i],
+					HighestResolution: c.HighestResolution,
+					HighestBitrate:    c.HighestBitrate,
+					LargestSize:       c.LargestSize,
+					LongestDuration:   c.LongestDuration,
+					Recommended:       c.Recommended,
+				})
+			}
+
+			ret = append(ret, group)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (r *queryResolver) FindTrailerMatches(ctx context.Context, input *TrailerMatchInput) (ret []*TrailerMatch, err error) {
+	options := scene.DefaultTrailerMatchOptions()
+	if input != nil {
+		if input.MaxTrailerDuration != nil {
+			options.MaxTrailerDuration = *input.MaxTrailerDuration
+		}
+		if input.MinDurationRatio != nil {
+			options.MinDurationRatio = *input.MinDurationRatio
+		}
+		if input.Distance != nil {
+			options.Distance = *input.Distance
+		}
+	}
+
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		qb := r.repository.Scene
+		hashes, err := qb.FindPrimaryPhashes(ctx)
+		if err != nil {
+			return err
+		}
+
+		matches := scene.MatchTrailers(hashes, options)
+
+		// a full scene may be matched by more than one trailer
+		var ids []int
+		for _, m := range matches {
+			ids = intslice.IntAppendUniques(ids, []int{m.TrailerID, m.SceneID})
+		}
+
+		scenes, err := qb.FindMany(ctx, ids)
+		if err != nil {
+			return err
+		}
+
+		byID := make(map[int]*models.Scene, len(scenes))
+		for _, s := range scenes {
+			byID[s.ID] = s
+		}
+
+		for _, m := range matches {
+			ret = append(ret, &TrailerMatch{
+				Trailer:  byID[m.TrailerID],
+				Scene:    byID[m.SceneID],
+				Distance: m.Distance,
+			})
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
@@ -5,6 +5,8 @@ import (
 	"strconv"
 
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
+	"github.com/stashapp/stash/pkg/tag"
 )
 
 func (r *queryResolver) FindTag(ctx context.Context, id string) (ret *models.Tag, err error) {
@@ -53,3 +55,24 @@ func (r *queryResolver) AllTags(ctx context.Context) (ret []*models.Tag, err err
 
 	return ret, nil
 }
+
+func (r *queryResolver) TagsMergePlan(ctx context.Context, input TagsMergeInput) (ret *tag.MergePlan, err error) {
+	source, err := stringslice.StringSliceToIntSlice(input.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	destination, err := strconv.Atoi(input.Destination)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = tag.PlanMerge(ctx, r.repository.Tag, source, destination)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func (r *queryResolver) FindWantedScene(ctx context.Context, id string) (ret *models.WantedScene, err error) {
+	idInt, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.WantedScene.Find(ctx, idInt)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return ret, err
+}
+
+func (r *queryResolver) AllWantedScenes(ctx context.Context, fulfilled *bool) (ret []*models.WantedScene, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.WantedScene.All(ctx)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	if fulfilled == nil {
+		return ret, nil
+	}
+
+	var filtered []*models.WantedScene
+	for _, w := range ret {
+		if w.IsFulfilled() == *fulfilled {
+			filtered = append(filtered, w)
+		}
+	}
+
+	return filtered, nil
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"strconv"
+	"time"
 
 	"github.com/stashapp/stash/internal/manager"
 	"github.com/stashapp/stash/pkg/job"
@@ -32,6 +33,40 @@ func (r *queryResolver) FindJob(ctx context.Context, input FindJobInput) (*Job,
 	return jobToJobModel(*j), nil
 }
 
+func (r *queryResolver) JobItems(ctx context.Context, jobID string, status []JobItemStatus) ([]*JobItem, error) {
+	idInt, err := strconv.Atoi(jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []job.ItemStatus
+	for _, s := range status {
+		statuses = append(statuses, job.ItemStatus(s))
+	}
+
+	items, err := manager.GetInstance().JobManager.GetJobItems(idInt, statuses...)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]*JobItem, len(items))
+	for i, item := range items {
+		ret[i] = &JobItem{
+			ID:          strconv.Itoa(item.ID),
+			Description: item.Description,
+			Status:      JobItemStatus(item.Status),
+			Priority:    item.Priority,
+			StartTime:   item.StartTime,
+			EndTime:     item.EndTime,
+		}
+		if item.Error != "" {
+			ret[i].Error = &items[i].Error
+		}
+	}
+
+	return ret, nil
+}
+
 func jobToJobModel(j job.Job) *Job {
 	ret := &Job{
 		ID:          strconv.Itoa(j.ID),
@@ -41,11 +76,33 @@ func jobToJobModel(j job.Job) *Job {
 		StartTime:   j.StartTime,
 		EndTime:     j.EndTime,
 		AddTime:     j.AddTime,
+		Priority:    jobPriorityToModel(j.Priority),
+		Class:       JobClass(j.Class),
+
+		EstimatedEndTime: j.EstimatedEndTime(time.Now()),
 	}
 
 	if j.Progress != -1 {
 		ret.Progress = &j.Progress
 	}
 
+	if j.Processed > 0 || j.Total > 0 {
+		ret.Processed = &j.Processed
+	}
+	if j.Total > 0 {
+		ret.Total = &j.Total
+	}
+
 	return ret
 }
+
+func jobPriorityToModel(p job.Priority) JobPriority {
+	switch {
+	case p < job.PriorityNormal:
+		return JobPriorityLow
+	case p > job.PriorityNormal:
+		return JobPriorityHigh
+	default:
+		return JobPriorityNormal
+	}
+}
@@ -4,8 +4,27 @@ import (
 	"context"
 
 	"github.com/stashapp/stash/internal/manager"
+	"github.com/stashapp/stash/pkg/models"
 )
 
 func (r *queryResolver) SystemStatus(ctx context.Context) (*manager.SystemStatus, error) {
 	return manager.GetInstance().GetSystemStatus(), nil
 }
+
+func (r *queryResolver) RenameFilesPreview(ctx context.Context, input manager.RenameFilesInput) ([]*manager.FileRename, error) {
+	return manager.GetInstance().PreviewRenames(ctx, input)
+}
+
+func (r *queryResolver) ContentWarningsUnlocked(ctx context.Context) (bool, error) {
+	return !models.ContentWarningsHidden(ctx), nil
+}
+
+func (r *queryResolver) QuickHideEnabled(ctx context.Context) (bool, error) {
+	_, enabled := models.QuickHideSafeTags(ctx)
+	return enabled, nil
+}
+
+func (r *queryResolver) ContentRestricted(ctx context.Context) (bool, error) {
+	restriction, ok := models.GetContentRestriction(ctx)
+	return ok && !restriction.Empty(), nil
+}
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const (
+	defaultOperationJournalLimit = 100
+	maxOperationJournalLimit     = 1000
+)
+
+func (r *queryResolver) OperationJournal(ctx context.Context, cursor *string, entityTypes []models.OperationJournalEntityType, limit *int) (*OperationJournalResult, error) {
+	after := 0
+	if cursor != nil {
+		var err error
+		after, err = strconv.Atoi(*cursor)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor %q", *cursor)
+		}
+	}
+
+	l := defaultOperationJournalLimit
+	if limit != nil {
+		if *limit <= 0 || *limit > maxOperationJournalLimit {
+			return nil, fmt.Errorf("limit must be between 1 and %d", maxOperationJournalLimit)
+		}
+		l = *limit
+	}
+
+	var entries []*models.OperationJournalEntry
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		var err error
+		// one extra entry to determine if there are more
+		entries, err = r.repository.OperationJournal.FindAfter(ctx, after, entityTypes, l+1)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	hasMore := len(entries) > l
+	if hasMore {
+		entries = entries[:l]
+	}
+
+	if len(entries) > 0 {
+		after = entries[len(entries)-1].ID
+	}
+
+	return &OperationJournalResult{
+		Entries: entries,
+		Cursor:  strconv.Itoa(after),
+		HasMore: hasMore,
+	}, nil
+}
+
+func (r *queryResolver) OperationJournalCursor(ctx context.Context) (string, error) {
+	var ret int
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		var err error
+		ret, err = r.repository.OperationJournal.Latest(ctx)
+		return err
+	}); err != nil {
+		return "", err
+	}
+
+	return strconv.Itoa(ret), nil
+}
@@ -0,0 +1,26 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func playQueueName(queue *string) string {
+	if queue == nil || *queue == "" {
+		return models.DefaultPlayQueue
+	}
+
+	return *queue
+}
+
+func (r *queryResolver) PlayQueue(ctx context.Context, queue *string) (ret []*models.PlayQueueItem, err error) {
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.PlayQueue.FindByQueue(ctx, playQueueName(queue))
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strconv"
 
 	"github.com/stashapp/stash/internal/api/urlbuilders"
@@ -18,6 +19,99 @@ func (r *queryResolver) SceneStreams(ctx context.Context, id *string) ([]*manage
 		idInt, _ := strconv.Atoi(*id)
 		var err error
 		scene, err = r.repository.Scene.Find(ctx, idInt)
+		if err != nil || scene == nil {
+			return err
+		}
+
+		// the primary scene of a stack plays the best version by default
+		scene, err = r.streamedScene(ctx, scene)
+		if err != nil {
+			return err
+		}
+
+		return scene.LoadPrimaryFile(ctx, r.repository.File)
+	}); err != nil {
+		return nil, err
+	}
+
+	if scene == nil {
+		return nil, errors.New("nil scene")
+	}
+
+	baseURL, _ := ctx.Value(BaseURLCtxKey).(string)
+	builder := urlbuilders.NewSceneURLBuilder(baseURL, scene.ID)
+
+	return manager.GetSceneStreamPaths(scene, builder.GetStreamURL(), config.GetInstance().GetMaxStreamingTranscodeSize())
+}
+
+// playbackDropOffRanges is the number of ranges that the scene duration is
+// divided into when reporting drop-off points.
+const playbackDropOffRanges = 20
+
+func (r *queryResolver) ScenePlaybackStats(ctx context.Context, id string) (*ScenePlaybackStats, error) {
+	idInt, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := &ScenePlaybackStats{}
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		scene, err := r.repository.Scene.Find(ctx, idInt)
+		if err != nil {
+			return err
+		}
+		if scene == nil {
+			return fmt.Errorf("scene with id %s not found", id)
+		}
+
+		if err := scene.LoadPrimaryFile(ctx, r.repository.File); err != nil {
+			return err
+		}
+
+		qb := r.repository.PlaybackEvent
+		ret.Sessions, err = qb.CountSessions(ctx, &idInt)
+		if err != nil {
+			return err
+		}
+
+		ret.CompletedSessions, err = qb.CountCompletedSessions(ctx, &idInt)
+		if err != nil {
+			return err
+		}
+
+		positions, err := qb.IncompleteSessionPositions(ctx, idInt)
+		if err != nil {
+			return err
+		}
+
+		var duration float64
+		if f := scene.Files.Primary(); f != nil {
+			duration = f.Duration
+		}
+
+		ret.DropOffs = models.PlaybackDropOffs(positions, duration, playbackDropOffRanges)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if ret.Sessions > 0 {
+		ret.CompletionRate = float64(ret.CompletedSessions) / float64(ret.Sessions)
+	}
+
+	return ret, nil
+}
+
+func (r *queryResolver) ScenePlaybackDecision(ctx context.Context, id string, capabilities manager.ClientCapabilitiesInput) (*manager.PlaybackDecision, error) {
+	idInt, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var scene *models.Scene
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		var err error
+		scene, err = r.repository.Scene.Find(ctx, idInt)
 
 		if scene != nil {
 			err = scene.LoadPrimaryFile(ctx, r.repository.File)
@@ -29,11 +123,12 @@ func (r *queryResolver) SceneStreams(ctx context.Context, id *string) ([]*manage
 	}
 
 	if scene == nil {
-		return nil, errors.New("nil scene")
+		return nil, fmt.Errorf("scene with id %s not found", id)
 	}
 
 	baseURL, _ := ctx.Value(BaseURLCtxKey).(string)
 	builder := urlbuilders.NewSceneURLBuilder(baseURL, scene.ID)
+	builder.APIKey = config.GetInstance().GetAPIKey()
 
-	return manager.GetSceneStreamPaths(scene, builder.GetStreamURL(), config.GetInstance().GetMaxStreamingTranscodeSize())
+	return manager.DecidePlayback(scene, builder.GetStreamURL(), capabilities)
 }
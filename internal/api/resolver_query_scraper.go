@@ -307,6 +307,26 @@ func (r *queryResolver) ScrapeSingleScene(ctx context.Context, source scraper.So
 	return ret, nil
 }
 
+func (r *queryResolver) ScrapeSceneIdentifySources(ctx context.Context, sceneID string, sources []*scraper.Source) ([]*manager.IdentifySourceResult, error) {
+	id, err := strconv.Atoi(sceneID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: sceneID is not an integer: '%s'", ErrInput, sceneID)
+	}
+
+	ret, err := manager.GetInstance().CompareIdentifySources(ctx, id, sources)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, result := range ret {
+		if result.Scene != nil {
+			filterSceneTags([]*scraper.ScrapedScene{result.Scene})
+		}
+	}
+
+	return ret, nil
+}
+
 func (r *queryResolver) ScrapeMultiScenes(ctx context.Context, source scraper.Source, input ScrapeMultiScenesInput) ([][]*scraper.ScrapedScene, error) {
 	if source.ScraperID != nil {
 		return nil, ErrNotImplemented
@@ -0,0 +1,29 @@
+package api
+
+import (
+	"context"
+	"errors"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const defaultSearchLimit = 25
+
+func (r *queryResolver) Search(ctx context.Context, term string, types []models.SearchResultType, limit *int) (ret []*models.SearchResult, err error) {
+	l := defaultSearchLimit
+	if limit != nil {
+		if *limit <= 0 {
+			return nil, errors.New("limit must be positive")
+		}
+		l = *limit
+	}
+
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		ret, err = r.repository.Search.Search(ctx, term, types, l)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
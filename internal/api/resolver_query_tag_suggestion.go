@@ -0,0 +1,36 @@
+package api
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func (r *queryResolver) FindTagSuggestions(ctx context.Context, sceneID *string, filter *models.FindFilterType) (ret *FindTagSuggestionsResultType, err error) {
+	var sceneIDInt *int
+	if sceneID != nil {
+		id, err := strconv.Atoi(*sceneID)
+		if err != nil {
+			return nil, err
+		}
+		sceneIDInt = &id
+	}
+
+	if err := r.withReadTxn(ctx, func(ctx context.Context) error {
+		suggestions, count, err := r.repository.TagSuggestion.Query(ctx, sceneIDInt, filter)
+		if err != nil {
+			return err
+		}
+
+		ret = &FindTagSuggestionsResultType{
+			Count:       count,
+			Suggestions: suggestions,
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,24 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/theme"
+)
+
+func (r *queryResolver) Themes(ctx context.Context) ([]*theme.Theme, error) {
+	c := config.GetInstance()
+	themes, err := theme.LoadThemes(c.GetThemesPath())
+	if err != nil {
+		return nil, err
+	}
+
+	active := c.GetActiveTheme()
+	ret := make([]*theme.Theme, len(themes))
+	for i, t := range themes {
+		ret[i] = t.ToTheme(t.ID() == active)
+	}
+
+	return ret, nil
+}
@@ -2,6 +2,8 @@ package api
 
 import (
 	"context"
+	"fmt"
+	"strconv"
 
 	"github.com/stashapp/stash/internal/manager"
 	"github.com/stashapp/stash/pkg/job"
@@ -38,6 +40,68 @@ func (r *subscriptionResolver) JobsSubscribe(ctx context.Context) (<-chan *JobSt
 	return msg, nil
 }
 
+func isJobDone(j job.Job) bool {
+	return j.Status == job.StatusFinished || j.Status == job.StatusCancelled || j.Status == job.StatusFailed
+}
+
+func (r *subscriptionResolver) JobSubscribe(ctx context.Context, id string) (<-chan *Job, error) {
+	jobID, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// subscribe before getting the current state so that no updates are missed
+	ctx, cancel := context.WithCancel(ctx)
+	jobManager := manager.GetInstance().JobManager
+	subscription := jobManager.Subscribe(ctx)
+
+	current := jobManager.GetJob(jobID)
+	if current == nil {
+		cancel()
+		return nil, fmt.Errorf("job %d not found", jobID)
+	}
+
+	msg := make(chan *Job, 100)
+	msg <- jobToJobModel(*current)
+
+	go func() {
+		// unsubscribe from the manager once the job is done
+		defer cancel()
+		defer close(msg)
+
+		if isJobDone(*current) {
+			return
+		}
+
+		for {
+			select {
+			case j, ok := <-subscription.UpdatedJob:
+				if !ok {
+					return
+				}
+				if j.ID == jobID {
+					msg <- jobToJobModel(j)
+					if isJobDone(j) {
+						return
+					}
+				}
+			case j, ok := <-subscription.RemovedJob:
+				if !ok {
+					return
+				}
+				if j.ID == jobID {
+					msg <- jobToJobModel(j)
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return msg, nil
+}
+
 func (r *subscriptionResolver) ScanCompleteSubscribe(ctx context.Context) (<-chan bool, error) {
 	return manager.GetInstance().ScanSubscribe(ctx), nil
 }
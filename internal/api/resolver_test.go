@@ -0,0 +1,103 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stashapp/stash/pkg/plugin"
+)
+
+// preHookExecutor returns the replaced input or error from pre hooks.
+type preHookExecutor struct {
+	mockHookExecutor
+	replaced map[string]interface{}
+	err      error
+
+	input interface{}
+}
+
+func (e *preHookExecutor) ExecutePreHooks(ctx context.Context, id int, hookType plugin.HookTriggerEnum, input interface{}) (map[string]interface{}, error) {
+	e.input = input
+	return e.replaced, e.err
+}
+
+func TestExecutePreHooks(t *testing.T) {
+	name := "name"
+	description := "description"
+	vetoErr := &plugin.PreHookVetoError{Plugin: "validator", Reason: "invalid name"}
+
+	testCases := []struct {
+		name       string
+		replaced   map[string]interface{}
+		err        error
+		translator *changesetTranslator
+		want       TagUpdateInput
+		wantFields []string
+		wantErr    error
+	}{
+		{
+			"not replaced",
+			nil,
+			nil,
+			&changesetTranslator{inputMap: map[string]interface{}{"id": "1", "name": name}},
+			TagUpdateInput{ID: "1", Name: &name},
+			[]string{"id", "name"},
+			nil,
+		},
+		{
+			"replaced",
+			map[string]interface{}{"id": "1", "description": description},
+			nil,
+			&changesetTranslator{inputMap: map[string]interface{}{"id": "1", "name": name}},
+			TagUpdateInput{ID: "1", Description: &description},
+			[]string{"id", "description"},
+			nil,
+		},
+		{
+			"replaced create",
+			map[string]interface{}{"id": "1", "description": description},
+			nil,
+			nil,
+			TagUpdateInput{ID: "1", Description: &description},
+			nil,
+			nil,
+		},
+		{
+			"vetoed",
+			nil,
+			vetoErr,
+			&changesetTranslator{inputMap: map[string]interface{}{"id": "1", "name": name}},
+			TagUpdateInput{ID: "1", Name: &name},
+			[]string{"id", "name"},
+			vetoErr,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := &preHookExecutor{replaced: tc.replaced, err: tc.err}
+			r := &Resolver{hookExecutor: e}
+
+			input := TagUpdateInput{ID: "1", Name: &name}
+			err := r.executePreHooks(testCtx, 1, plugin.TagUpdatePre, &input, tc.translator)
+			if tc.wantErr != nil {
+				assert.True(t, errors.Is(err, tc.wantErr))
+			} else {
+				assert.Nil(t, err)
+			}
+
+			assert.Equal(t, tc.want, input)
+
+			if tc.translator == nil {
+				// creates pass the input itself
+				assert.Equal(t, &input, e.input)
+				return
+			}
+
+			assert.ElementsMatch(t, tc.wantFields, tc.translator.getFields())
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/stashapp/stash/internal/manager"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/txn"
+)
+
+type JobArtifactFinder interface {
+	Find(ctx context.Context, id int) (*models.JobArtifact, error)
+}
+
+type artifactRoutes struct {
+	txnManager     txn.Manager
+	artifactFinder JobArtifactFinder
+}
+
+func (rs artifactRoutes) Routes() chi.Router {
+	r := chi.NewRouter()
+	r.Use(mediaAccessHandler)
+
+	// the file name is ignored, but allows the browser to name the download
+	r.Get("/{artifactId}/{filename}", rs.File)
+
+	return r
+}
+
+func (rs artifactRoutes) File(w http.ResponseWriter, r *http.Request) {
+	artifactID, err := strconv.Atoi(chi.URLParam(r, "artifactId"))
+	if err != nil {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+
+	var artifact *models.JobArtifact
+	readTxnErr := txn.WithReadTxn(r.Context(), rs.txnManager, func(ctx context.Context) error {
+		artifact, err = rs.artifactFinder.Find(ctx, artifactID)
+		return err
+	})
+	if errors.Is(readTxnErr, context.Canceled) {
+		return
+	}
+	if readTxnErr != nil {
+		logger.Warnf("read transaction error on fetch job artifact: %v", readTxnErr)
+	}
+
+	if artifact == nil {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+
+	if artifact.ContentType != "" {
+		w.Header().Set("Content-Type", artifact.ContentType)
+	}
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": artifact.Name}))
+	http.ServeFile(w, r, manager.GetInstance().JobArtifactPath(artifact))
+}
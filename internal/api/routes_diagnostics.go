@@ -0,0 +1,191 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/stashapp/stash/internal/manager"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const (
+	speedTestDefaultSize = 10 * 1024 * 1024
+	speedTestMaxSize     = 200 * 1024 * 1024
+
+	// size of the random data repeated in download responses
+	speedTestChunkSize = 64 * 1024
+)
+
+var (
+	speedTestChunk     []byte
+	speedTestChunkOnce sync.Once
+)
+
+// getSpeedTestChunk returns random data, so that download speeds are not
+// affected by compression.
+func getSpeedTestChunk() []byte {
+	speedTestChunkOnce.Do(func() {
+		speedTestChunk = make([]byte, speedTestChunkSize)
+		if _, err := rand.Read(speedTestChunk); err != nil {
+			logger.Warnf("error generating speed test data: %v", err)
+		}
+	})
+
+	return speedTestChunk
+}
+
+// diagnosticsRoutes provides endpoints for clients to measure the latency
+// and throughput of their connection to the server.
+type diagnosticsRoutes struct{}
+
+func (rs diagnosticsRoutes) Routes() chi.Router {
+	r := chi.NewRouter()
+	r.Use(noStoreHandler)
+
+	r.Get("/ping", rs.ping)
+	r.Get("/download", rs.download)
+	r.Post("/upload", rs.upload)
+
+	return r
+}
+
+func noStoreHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+		next.ServeHTTP(w, r)
+	})
+}
+
+// speedTestSize returns the size parameter of the request, in bytes.
+func speedTestSize(r *http.Request) (int64, error) {
+	v := r.URL.Query().Get("size")
+	if v == "" {
+		return speedTestDefaultSize, nil
+	}
+
+	ret, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || ret < 0 {
+		return 0, fmt.Errorf("invalid size %q", v)
+	}
+
+	if ret > speedTestMaxSize {
+		ret = speedTestMaxSize
+	}
+
+	return ret, nil
+}
+
+// ping responds immediately, for clients to measure the round trip time.
+func (rs diagnosticsRoutes) ping(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// download writes the number of bytes requested by the size parameter.
+func (rs diagnosticsRoutes) download(w http.ResponseWriter, r *http.Request) {
+	size, err := speedTestSize(r)
+	if err != nil {
+		http.Error(w, "invalid size", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Length", strconv.FormatInt(size, 10))
+
+	chunk := getSpeedTestChunk()
+	for remaining := size; remaining > 0; {
+		n := int64(len(chunk))
+		if remaining < n {
+			n = remaining
+		}
+
+		if _, err := w.Write(chunk[:n]); err != nil {
+			// client disconnected
+			return
+		}
+		remaining -= n
+	}
+}
+
+type speedTestUploadResult struct {
+	Bytes int64 `json:"bytes"`
+	// DurationMs is the time taken by the server to receive the body
+	DurationMs int64 `json:"duration_ms"`
+}
+
+// upload reads and discards the request body, returning the number of
+// bytes received and the time taken to receive them.
+func (rs diagnosticsRoutes) upload(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	n, err := io.Copy(io.Discard, io.LimitReader(r.Body, speedTestMaxSize+1))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if n > speedTestMaxSize {
+		http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(speedTestUploadResult{
+		Bytes:      n,
+		DurationMs: time.Since(start).Milliseconds(),
+	}); err != nil {
+		logger.Warnf("error writing speed test result: %v", err)
+	}
+}
+
+// sceneStreamType returns the type of the scene stream requested by the URL
+// path.
+func sceneStreamType(p string) manager.SceneStreamType {
+	if strings.Contains(p, "/stream_abr") {
+		return manager.SceneStreamTypeHLSAdaptive
+	}
+
+	switch path.Base(p) {
+	case "stream.mkv":
+		return manager.SceneStreamTypeMkv
+	case "stream.webm":
+		return manager.SceneStreamTypeWebm
+	case "stream.mp4":
+		return manager.SceneStreamTypeMp4
+	case "stream.m3u8", "stream.ts":
+		return manager.SceneStreamTypeHLS
+	}
+
+	return manager.SceneStreamTypeDirect
+}
+
+// streamClientHandler records the scene streams requested by each client.
+func streamClientHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s, ok := r.Context().Value(sceneKey).(*models.Scene); ok {
+			c := manager.StreamClient{
+				RemoteAddr:  getActivityActor(r.Context()).RemoteAddr,
+				UserAgent:   r.UserAgent(),
+				SceneID:     s.ID,
+				StreamType:  sceneStreamType(r.URL.Path),
+				LastRequest: time.Now(),
+			}
+
+			if resolution := r.URL.Query().Get("resolution"); resolution != "" && c.StreamType != manager.SceneStreamTypeDirect {
+				c.Resolution = &resolution
+			}
+
+			manager.GetInstance().StreamClients.Record(c)
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
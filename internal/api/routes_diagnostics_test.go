@@ -0,0 +1,57 @@
+package api
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stashapp/stash/internal/manager"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSceneStreamType(t *testing.T) {
+	tests := []struct {
+		path string
+		want manager.SceneStreamType
+	}{
+		{"/scene/1/stream", manager.SceneStreamTypeDirect},
+		{"/scene/1/stream.mkv", manager.SceneStreamTypeMkv},
+		{"/scene/1/stream.webm", manager.SceneStreamTypeWebm},
+		{"/scene/1/stream.mp4", manager.SceneStreamTypeMp4},
+		{"/scene/1/stream.m3u8", manager.SceneStreamTypeHLS},
+		{"/scene/1/stream.ts", manager.SceneStreamTypeHLS},
+		{"/scene/1/stream_abr.m3u8", manager.SceneStreamTypeHLSAdaptive},
+		{"/scene/1/stream_abr/abc/STANDARD/3.ts", manager.SceneStreamTypeHLSAdaptive},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, sceneStreamType(tt.path))
+		})
+	}
+}
+
+func TestSpeedTestSize(t *testing.T) {
+	tests := []struct {
+		query   string
+		want    int64
+		wantErr bool
+	}{
+		{"", speedTestDefaultSize, false},
+		{"?size=1024", 1024, false},
+		{"?size=999999999999", speedTestMaxSize, false},
+		{"?size=-1", 0, true},
+		{"?size=abc", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/diagnostics/download"+tt.query, nil)
+			got, err := speedTestSize(r)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
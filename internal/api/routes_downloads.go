@@ -12,6 +12,7 @@ type downloadsRoutes struct{}
 
 func (rs downloadsRoutes) Routes() chi.Router {
 	r := chi.NewRouter()
+	r.Use(mediaAccessHandler)
 
 	r.Route("/{downloadHash}", func(r chi.Router) {
 		r.Use(downloadCtx)
@@ -0,0 +1,124 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/stashapp/stash/internal/manager"
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+type hooksRoutes struct{}
+
+func (rs hooksRoutes) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.With(editorRoleHandler).Post("/download-complete", rs.downloadComplete)
+
+	return r
+}
+
+// downloadCompletePayload contains the fields accepted by the completed
+// download hook. Field names cover those used by common download clients.
+type downloadCompletePayload struct {
+	Path string `json:"path"`
+	// qBittorrent
+	ContentPath string `json:"content_path"`
+	SavePath    string `json:"save_path"`
+	// Transmission
+	Dir  string `json:"dir"`
+	Name string `json:"name"`
+}
+
+func (p downloadCompletePayload) resolve() string {
+	switch {
+	case p.Path != "":
+		return p.Path
+	case p.ContentPath != "":
+		return p.ContentPath
+	}
+
+	dir := p.Dir
+	if dir == "" {
+		dir = p.SavePath
+	}
+
+	if dir == "" {
+		return ""
+	}
+
+	if p.Name != "" {
+		return filepath.Join(dir, p.Name)
+	}
+
+	return dir
+}
+
+func readDownloadCompletePayload(r *http.Request) (downloadCompletePayload, error) {
+	var ret downloadCompletePayload
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "application/json" {
+		if err := json.NewDecoder(r.Body).Decode(&ret); err != nil && !errors.Is(err, io.EOF) {
+			return ret, fmt.Errorf("decoding request body: %w", err)
+		}
+		return ret, nil
+	}
+
+	// query string or form values
+	if err := r.ParseForm(); err != nil {
+		return ret, err
+	}
+
+	ret.Path = r.Form.Get("path")
+	ret.ContentPath = r.Form.Get("content_path")
+	ret.SavePath = r.Form.Get("save_path")
+	ret.Dir = r.Form.Get("dir")
+	ret.Name = r.Form.Get("name")
+
+	return ret, nil
+}
+
+func (rs hooksRoutes) downloadComplete(w http.ResponseWriter, r *http.Request) {
+	payload, err := readDownloadCompletePayload(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	p := payload.resolve()
+	if p == "" {
+		http.Error(w, "path is required", http.StatusBadRequest)
+		return
+	}
+
+	jobIDs, err := manager.GetInstance().DownloadComplete(r.Context(), p)
+	if err != nil {
+		if errors.Is(err, manager.ErrDownloadHookDisabled) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+
+		logger.Warnf("download hook: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ids := make([]string, len(jobIDs))
+	for i, id := range jobIDs {
+		ids[i] = strconv.Itoa(id)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	if err := json.NewEncoder(w).Encode(map[string][]string{"jobs": ids}); err != nil {
+		logger.Warnf("failed to write download hook response: %v", err)
+	}
+}
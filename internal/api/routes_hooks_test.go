@@ -0,0 +1,95 @@
+package api
+
+import (
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadDownloadCompletePayload(t *testing.T) {
+	tests := []struct {
+		name        string
+		target      string
+		contentType string
+		body        string
+		want        string
+		wantErr     bool
+	}{
+		{
+			"query path",
+			"/download-complete?path=/downloads/a",
+			"",
+			"",
+			"/downloads/a",
+			false,
+		},
+		{
+			"form content path",
+			"/download-complete",
+			"application/x-www-form-urlencoded",
+			"content_path=%2Fdownloads%2Fb.mp4",
+			"/downloads/b.mp4",
+			false,
+		},
+		{
+			"json qbittorrent",
+			"/download-complete",
+			"application/json",
+			`{"save_path": "/downloads", "name": "c"}`,
+			filepath.Join("/downloads", "c"),
+			false,
+		},
+		{
+			"json transmission",
+			"/download-complete",
+			"application/json; charset=utf-8",
+			`{"dir": "/downloads", "name": "d.mkv"}`,
+			filepath.Join("/downloads", "d.mkv"),
+			false,
+		},
+		{
+			"json path precedence",
+			"/download-complete",
+			"application/json",
+			`{"path": "/downloads/e", "dir": "/other", "name": "f"}`,
+			"/downloads/e",
+			false,
+		},
+		{
+			"empty",
+			"/download-complete",
+			"application/json",
+			"",
+			"",
+			false,
+		},
+		{
+			"invalid json",
+			"/download-complete",
+			"application/json",
+			"{",
+			"",
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", tt.target, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				r.Header.Set("Content-Type", tt.contentType)
+			}
+
+			got, err := readDownloadCompletePayload(r)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("readDownloadCompletePayload() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			assert.Equal(t, tt.want, got.resolve())
+		})
+	}
+}
@@ -38,7 +38,7 @@ func (rs imageRoutes) Routes() chi.Router {
 	r.Route("/{imageId}", func(r chi.Router) {
 		r.Use(rs.ImageCtx)
 
-		r.Get("/image", rs.Image)
+		r.With(mediaAccessHandler).Get("/image", rs.Image)
 		r.Get("/thumbnail", rs.Thumbnail)
 	})
 
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/stashapp/stash/internal/locale"
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+type localeRoutes struct {
+	catalog *locale.Catalog
+}
+
+type localesResult struct {
+	Languages []string `json:"languages"`
+	// Language negotiated from the Accept-Language header and the configured
+	// language
+	Language string `json:"language"`
+	Default  string `json:"default"`
+}
+
+func (rs localeRoutes) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", rs.List)
+	r.Get("/{language}", rs.Messages)
+
+	return r
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logger.Warnf("error writing json response: %v", err)
+	}
+}
+
+// List returns the available languages.
+func (rs localeRoutes) List(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, localesResult{
+		Languages: rs.catalog.Languages(),
+		Language:  rs.catalog.ContextLanguage(r.Context(), config.GetInstance().GetLanguage()),
+		Default:   locale.DefaultLanguage,
+	})
+}
+
+// Messages returns the merged messages of the language, including the
+// translation overrides.
+func (rs localeRoutes) Messages(w http.ResponseWriter, r *http.Request) {
+	messages, err := rs.catalog.Messages(chi.URLParam(r, "language"))
+	if errors.Is(err, locale.ErrUnknownLanguage) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		logger.Errorf("error getting locale messages: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, messages)
+}
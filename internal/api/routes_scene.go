@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
+	"path/filepath"
 	"strconv"
 	"strings"
 
@@ -22,12 +24,16 @@ import (
 	"github.com/stashapp/stash/pkg/utils"
 )
 
+// seconds after which clients should retry streams of offline scenes
+const offlineRetryAfter = 30
+
 type SceneFinder interface {
 	manager.SceneCoverGetter
 
 	scene.IDFinder
 	FindByChecksum(ctx context.Context, checksum string) ([]*models.Scene, error)
 	FindByOSHash(ctx context.Context, oshash string) ([]*models.Scene, error)
+	GetTrailerFileID(ctx context.Context, sceneID int) (*file.ID, error)
 }
 
 type SceneMarkerFinder interface {
@@ -37,6 +43,11 @@ type SceneMarkerFinder interface {
 
 type CaptionFinder interface {
 	GetCaptions(ctx context.Context, fileID file.ID) ([]*models.VideoCaption, error)
+	GetSubtitleTracks(ctx context.Context, fileID file.ID) ([]file.VideoSubtitleTrack, error)
+}
+
+type ChapterFinder interface {
+	GetChapters(ctx context.Context, fileID file.ID) ([]file.VideoChapter, error)
 }
 
 type sceneRoutes struct {
@@ -44,8 +55,10 @@ type sceneRoutes struct {
 	sceneFinder       SceneFinder
 	fileFinder        file.Finder
 	captionFinder     CaptionFinder
+	chapterFinder     ChapterFinder
 	sceneMarkerFinder SceneMarkerFinder
 	tagFinder         scene.MarkerTagFinder
+	activity          *activityRecorder
 }
 
 func (rs sceneRoutes) Routes() chi.Router {
@@ -55,12 +68,23 @@ func (rs sceneRoutes) Routes() chi.Router {
 		r.Use(rs.SceneCtx)
 
 		// streaming endpoints
-		r.Get("/stream", rs.StreamDirect)
-		r.Get("/stream.mkv", rs.StreamMKV)
-		r.Get("/stream.webm", rs.StreamWebM)
-		r.Get("/stream.m3u8", rs.StreamHLS)
-		r.Get("/stream.ts", rs.StreamTS)
-		r.Get("/stream.mp4", rs.StreamMp4)
+		r.Group(func(r chi.Router) {
+			r.Use(mediaAccessHandler)
+			r.Use(sceneOnlineHandler)
+			r.Use(rs.activity.StreamMiddleware)
+			r.Use(streamClientHandler)
+
+			r.Get("/stream", rs.StreamDirect)
+			r.Get("/stream.mkv", rs.StreamMKV)
+			r.Get("/stream.webm", rs.StreamWebM)
+			r.Get("/stream.m3u8", rs.StreamHLS)
+			r.Get("/stream.ts", rs.StreamTS)
+			r.Get("/stream.mp4", rs.StreamMp4)
+			r.Get("/stream_abr.m3u8", rs.StreamHLSMaster)
+			r.Get("/stream_abr/{session}/{variant}.m3u8", rs.StreamHLSVariant)
+			r.Get("/stream_abr/{session}/{variant}/{segment}.ts", rs.StreamHLSSegment)
+			r.Get("/trailer", rs.Trailer)
+		})
 
 		r.Get("/screenshot", rs.Screenshot)
 		r.Get("/preview", rs.Preview)
@@ -69,13 +93,17 @@ func (rs sceneRoutes) Routes() chi.Router {
 		r.Get("/funscript", rs.Funscript)
 		r.Get("/interactive_heatmap", rs.InteractiveHeatmap)
 		r.Get("/caption", rs.CaptionLang)
+		r.Get("/subtitle/{subtitleIndex}/vtt", rs.SubtitleVTT)
+		r.Get("/chapter/{chapterIndex}/thumbnail", rs.ChapterThumbnail)
 
-		r.Get("/scene_marker/{sceneMarkerId}/stream", rs.SceneMarkerStream)
+		r.With(mediaAccessHandler).Get("/scene_marker/{sceneMarkerId}/stream", rs.SceneMarkerStream)
 		r.Get("/scene_marker/{sceneMarkerId}/preview", rs.SceneMarkerPreview)
 		r.Get("/scene_marker/{sceneMarkerId}/screenshot", rs.SceneMarkerScreenshot)
 	})
 	r.With(rs.SceneCtx).Get("/{sceneId}_thumbs.vtt", rs.VttThumbs)
 	r.With(rs.SceneCtx).Get("/{sceneId}_sprite.jpg", rs.VttSprite)
+	r.With(rs.SceneCtx).Get("/{sceneId}_hover.vtt", rs.HoverStripVtt)
+	r.With(rs.SceneCtx).Get("/{sceneId}_hover.mp4", rs.HoverStrip)
 
 	return r
 }
@@ -155,6 +183,134 @@ func (rs sceneRoutes) StreamHLS(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// StreamHLSMaster starts an adaptive bitrate HLS session and returns the
+// master playlist of its variant streams.
+func (rs sceneRoutes) StreamHLSMaster(w http.ResponseWriter, r *http.Request) {
+	scene := r.Context().Value(sceneKey).(*models.Scene)
+
+	pf := scene.Files.Primary()
+	if pf == nil {
+		return
+	}
+
+	sessionID, err := manager.GetInstance().HLSStreams.NewSession(scene.ID)
+	if err != nil {
+		logger.Errorf("[stream] error starting HLS session: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	variants := ffmpeg.HLSVariantsForVideo(manager.HLSVariants(config.GetInstance()), pf.Width, pf.Height)
+
+	// relative to the master playlist
+	var str strings.Builder
+	ffmpeg.WriteHLSMasterPlaylist(&str, variants, pf.Width, pf.Height, func(v ffmpeg.HLSVariant) string {
+		return withRawQuery(fmt.Sprintf("stream_abr/%s/%s.m3u8", sessionID, v.Name), r.URL.RawQuery)
+	})
+
+	w.Header().Set("Content-Type", ffmpeg.MimeHLS)
+	if _, err := w.Write([]byte(str.String())); err != nil {
+		logger.Warnf("[stream] error writing HLS playlist: %v", err)
+	}
+}
+
+func (rs sceneRoutes) StreamHLSVariant(w http.ResponseWriter, r *http.Request) {
+	scene := r.Context().Value(sceneKey).(*models.Scene)
+
+	pf := scene.Files.Primary()
+	if pf == nil {
+		return
+	}
+
+	sessionID := chi.URLParam(r, "session")
+	variant, found := findHLSVariant(chi.URLParam(r, "variant"))
+	if !found {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+
+	if err := manager.GetInstance().HLSStreams.Touch(sessionID, scene.ID); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	// relative to the variant playlist
+	var str strings.Builder
+	ffmpeg.WriteHLSVariantPlaylist(&str, pf.Duration, func(index int) string {
+		return withRawQuery(fmt.Sprintf("%s/%d.ts", variant.Name, index), r.URL.RawQuery)
+	})
+
+	w.Header().Set("Content-Type", ffmpeg.MimeHLS)
+	if _, err := w.Write([]byte(str.String())); err != nil {
+		logger.Warnf("[stream] error writing HLS playlist: %v", err)
+	}
+}
+
+func (rs sceneRoutes) StreamHLSSegment(w http.ResponseWriter, r *http.Request) {
+	scene := r.Context().Value(sceneKey).(*models.Scene)
+
+	pf := scene.Files.Primary()
+	if pf == nil {
+		return
+	}
+
+	sessionID := chi.URLParam(r, "session")
+	variant, found := findHLSVariant(chi.URLParam(r, "variant"))
+	index, err := strconv.Atoi(chi.URLParam(r, "segment"))
+	if !found || err != nil || index < 0 || index >= ffmpeg.HLSSegmentCount(pf.Duration) {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+
+	audioCodec := ffmpeg.MissingUnsupported
+	if pf.AudioCodec != "" {
+		audioCodec = ffmpeg.ProbeAudioCodec(pf.AudioCodec)
+	}
+
+	options := ffmpeg.HLSSegmentOptions{
+		Input:       pf.Path,
+		Index:       index,
+		Variant:     variant,
+		VideoWidth:  pf.Width,
+		VideoHeight: pf.Height,
+		VideoOnly:   audioCodec == ffmpeg.MissingUnsupported,
+	}
+
+	fn, err := manager.GetInstance().HLSStreams.Segment(r.Context(), sessionID, scene.ID, options)
+	if errors.Is(err, manager.ErrHLSSessionNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		if !errors.Is(err, context.Canceled) {
+			logger.Errorf("[stream] error transcoding HLS segment: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", ffmpeg.MimeMpegts)
+	http.ServeFile(w, r, fn)
+}
+
+func findHLSVariant(name string) (ffmpeg.HLSVariant, bool) {
+	for _, v := range manager.HLSVariants(config.GetInstance()) {
+		if v.Name == name {
+			return v, true
+		}
+	}
+
+	return ffmpeg.HLSVariant{}, false
+}
+
+func withRawQuery(u string, rawQuery string) string {
+	if rawQuery == "" {
+		return u
+	}
+
+	return u + "?" + rawQuery
+}
+
 func (rs sceneRoutes) StreamTS(w http.ResponseWriter, r *http.Request) {
 	rs.streamTranscode(w, r, ffmpeg.StreamFormatHLS)
 }
@@ -185,6 +341,17 @@ func (rs sceneRoutes) streamTranscode(w http.ResponseWriter, r *http.Request, st
 	width := f.Width
 	height := f.Height
 
+	resolution := config.GetInstance().GetMaxStreamingTranscodeSize()
+	if requestedSize != "" {
+		resolution = models.StreamingResolutionEnum(requestedSize)
+	}
+
+	// use the transcode profile selected for the resolution, if any
+	if profile := config.GetInstance().GetStreamTranscodeProfile(resolution, streamFormat.Container()); profile != nil {
+		logger.Debugf("[stream] using transcode profile %s", profile.Name)
+		streamFormat = profile.StreamFormat()
+	}
+
 	options := ffmpeg.TranscodeStreamOptions{
 		Input:     f.Path,
 		Codec:     streamFormat,
@@ -194,11 +361,33 @@ func (rs sceneRoutes) streamTranscode(w http.ResponseWriter, r *http.Request, st
 		VideoHeight: height,
 
 		StartTime:        ss,
-		MaxTranscodeSize: config.GetInstance().GetMaxStreamingTranscodeSize().GetMaxResolution(),
+		MaxTranscodeSize: resolution.GetMaxResolution(),
 	}
 
-	if requestedSize != "" {
-		options.MaxTranscodeSize = models.StreamingResolutionEnum(requestedSize).GetMaxResolution()
+	// burn in the selected subtitle, if provided
+	if subtitleIndex := r.Form.Get("subtitle"); subtitleIndex != "" {
+		index, err := strconv.Atoi(subtitleIndex)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid subtitle %q", subtitleIndex), http.StatusBadRequest)
+			return
+		}
+
+		subtitle, err := rs.getSubtitle(r.Context(), f.ID, index)
+		if errors.Is(err, context.Canceled) {
+			return
+		}
+		if err != nil {
+			logger.Warnf("[stream] error getting subtitle: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if subtitle == nil {
+			http.Error(w, fmt.Sprintf("subtitle %d not found", index), http.StatusBadRequest)
+			return
+		}
+
+		burnIn := subtitle.BurnIn(f.Path)
+		options.Subtitles = &burnIn
 	}
 
 	encoder := manager.GetInstance().FFMPEG
@@ -334,15 +523,87 @@ func (rs sceneRoutes) ChapterVtt(w http.ResponseWriter, r *http.Request) {
 
 func (rs sceneRoutes) Funscript(w http.ResponseWriter, r *http.Request) {
 	s := r.Context().Value(sceneKey).(*models.Scene)
-	funscript := video.GetFunscriptPath(s.Path)
-	serveFileNoCache(w, r, funscript)
+	scriptPath := video.FindInteractiveScriptPath(s.Path)
+	if !strings.EqualFold(filepath.Ext(scriptPath), video.CSVScriptExtension) {
+		serveFileNoCache(w, r, scriptPath)
+		return
+	}
+
+	// convert CSV scripts for players which only support funscripts
+	script, err := manager.ReadInteractiveScript(scriptPath)
+	if err != nil {
+		logger.Warnf("error reading interactive script %s: %v", scriptPath, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data, err := script.MarshalFunscript()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Add("Cache-Control", "no-cache")
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data)
+}
+
+func (rs sceneRoutes) Trailer(w http.ResponseWriter, r *http.Request) {
+	s := r.Context().Value(sceneKey).(*models.Scene)
+
+	var trailer file.File
+	readTxnErr := txn.WithReadTxn(r.Context(), rs.txnManager, func(ctx context.Context) error {
+		fileID, err := rs.sceneFinder.GetTrailerFileID(ctx, s.ID)
+		if err != nil || fileID == nil {
+			return err
+		}
+
+		files, err := rs.fileFinder.Find(ctx, *fileID)
+		if err != nil {
+			return err
+		}
+
+		if len(files) > 0 {
+			trailer = files[0]
+		}
+		return nil
+	})
+	if errors.Is(readTxnErr, context.Canceled) {
+		return
+	}
+	if readTxnErr != nil {
+		logger.Warnf("read transaction error on fetch trailer: %v", readTxnErr)
+		http.Error(w, readTxnErr.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if trailer == nil {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+
+	http.ServeFile(w, r, trailer.Base().Path)
 }
 
 func (rs sceneRoutes) InteractiveHeatmap(w http.ResponseWriter, r *http.Request) {
 	scene := r.Context().Value(sceneKey).(*models.Scene)
 	w.Header().Set("Content-Type", "image/png")
 	filepath := manager.GetInstance().Paths.Scene.GetInteractiveHeatmapPath(scene.GetHash(config.GetInstance().GetVideoFileNamingAlgorithm()))
-	http.ServeFile(w, r, filepath)
+	if exists, _ := fsutil.FileExists(filepath); exists {
+		http.ServeFile(w, r, filepath)
+		return
+	}
+
+	// render heatmaps which have not been generated yet
+	var b bytes.Buffer
+	if err := manager.WriteInteractiveHeatmap(scene, &b); err != nil {
+		logger.Warnf("error rendering interactive heatmap of scene %d: %v", scene.ID, err)
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Add("Cache-Control", "no-cache")
+	_, _ = w.Write(b.Bytes())
 }
 
 func (rs sceneRoutes) Caption(w http.ResponseWriter, r *http.Request, lang string, ext string) {
@@ -394,6 +655,115 @@ func (rs sceneRoutes) Caption(w http.ResponseWriter, r *http.Request, lang strin
 	}
 }
 
+// getSubtitle returns the subtitle of the file at index, or nil if there is
+// no such subtitle.
+func (rs sceneRoutes) getSubtitle(ctx context.Context, fileID file.ID, index int) (*manager.Subtitle, error) {
+	var subtitles []manager.Subtitle
+	if err := txn.WithReadTxn(ctx, rs.txnManager, func(ctx context.Context) error {
+		var err error
+		subtitles, err = manager.GetSubtitles(ctx, rs.captionFinder, fileID)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	if index < 0 || index >= len(subtitles) {
+		return nil, nil
+	}
+
+	return &subtitles[index], nil
+}
+
+func (rs sceneRoutes) SubtitleVTT(w http.ResponseWriter, r *http.Request) {
+	s := r.Context().Value(sceneKey).(*models.Scene)
+	index, err := strconv.Atoi(chi.URLParam(r, "subtitleIndex"))
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+
+	primaryFile := s.Files.Primary()
+	if primaryFile == nil {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+
+	subtitle, err := rs.getSubtitle(r.Context(), primaryFile.ID, index)
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	if err != nil {
+		logger.Warnf("read transaction error on fetch scene subtitles: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if subtitle == nil {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+
+	var b bytes.Buffer
+	if err := manager.WriteSubtitleVTT(r.Context(), manager.GetInstance().FFMPEG, primaryFile.Path, *subtitle, &b); err != nil {
+		if errors.Is(err, context.Canceled) {
+			return
+		}
+		logger.Warnf("error converting subtitle: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/vtt")
+	w.Header().Add("Cache-Control", "no-cache")
+	_, _ = b.WriteTo(w)
+}
+
+func (rs sceneRoutes) ChapterThumbnail(w http.ResponseWriter, r *http.Request) {
+	s := r.Context().Value(sceneKey).(*models.Scene)
+	index, err := strconv.Atoi(chi.URLParam(r, "chapterIndex"))
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+
+	var chapters []file.VideoChapter
+	readTxnErr := txn.WithReadTxn(r.Context(), rs.txnManager, func(ctx context.Context) error {
+		primaryFile := s.Files.Primary()
+		if primaryFile == nil {
+			return nil
+		}
+
+		var err error
+		chapters, err = rs.chapterFinder.GetChapters(ctx, primaryFile.Base().ID)
+		return err
+	})
+	if errors.Is(readTxnErr, context.Canceled) {
+		return
+	}
+	if readTxnErr != nil {
+		logger.Warnf("read transaction error on fetch scene chapters: %v", readTxnErr)
+		http.Error(w, readTxnErr.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if index < 0 || index >= len(chapters) {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+
+	filepath := manager.GetInstance().Paths.Scene.GetChapterThumbnailPath(s.GetHash(config.GetInstance().GetVideoFileNamingAlgorithm()), chapters[index].Start)
+
+	// If the image doesn't exist, send the placeholder
+	exists, _ := fsutil.FileExists(filepath)
+	if !exists {
+		w.Header().Set("Content-Type", "image/png")
+		w.Header().Set("Cache-Control", "no-store")
+		_, _ = w.Write(utils.PendingGenerateResource)
+		return
+	}
+
+	http.ServeFile(w, r, filepath)
+}
+
 func (rs sceneRoutes) CaptionLang(w http.ResponseWriter, r *http.Request) {
 	// serve caption based on lang query param, if provided
 	if err := r.ParseForm(); err != nil {
@@ -419,6 +789,19 @@ func (rs sceneRoutes) VttSprite(w http.ResponseWriter, r *http.Request) {
 	http.ServeFile(w, r, filepath)
 }
 
+func (rs sceneRoutes) HoverStripVtt(w http.ResponseWriter, r *http.Request) {
+	scene := r.Context().Value(sceneKey).(*models.Scene)
+	w.Header().Set("Content-Type", "text/vtt")
+	filepath := manager.GetInstance().Paths.Scene.GetHoverStripVttPath(scene.GetHash(config.GetInstance().GetVideoFileNamingAlgorithm()))
+	http.ServeFile(w, r, filepath)
+}
+
+func (rs sceneRoutes) HoverStrip(w http.ResponseWriter, r *http.Request) {
+	scene := r.Context().Value(sceneKey).(*models.Scene)
+	filepath := manager.GetInstance().Paths.Scene.GetHoverStripPath(scene.GetHash(config.GetInstance().GetVideoFileNamingAlgorithm()))
+	http.ServeFile(w, r, filepath)
+}
+
 func (rs sceneRoutes) SceneMarkerStream(w http.ResponseWriter, r *http.Request) {
 	scene := r.Context().Value(sceneKey).(*models.Scene)
 	sceneMarkerID, _ := strconv.Atoi(chi.URLParam(r, "sceneMarkerId"))
@@ -568,3 +951,20 @@ func (rs sceneRoutes) SceneCtx(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// sceneOnlineHandler responds with a temporary error if the scene file is on
+// an offline stash path, so that clients retry the stream instead of
+// treating the file as missing.
+func sceneOnlineHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scene := r.Context().Value(sceneKey).(*models.Scene)
+
+		if scene.Path != "" && manager.GetInstance().StashPathMonitor.IsOffline(scene.Path) {
+			w.Header().Set("Retry-After", strconv.Itoa(offlineRetryAfter))
+			http.Error(w, "scene file is offline", http.StatusServiceUnavailable)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/theme"
+)
+
+type themeRoutes struct {
+	config *config.Instance
+}
+
+func (rs themeRoutes) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/asset/{name}", rs.Asset)
+
+	return r
+}
+
+// Asset serves an asset override of the active theme.
+func (rs themeRoutes) Asset(w http.ResponseWriter, r *http.Request) {
+	t := getActiveTheme(rs.config)
+	if t == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	fn := t.AssetPath(chi.URLParam(r, "name"))
+	if fn == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-cache")
+	http.ServeFile(w, r, fn)
+}
+
+// getActiveTheme returns the active theme. Returns nil if no theme is active
+// or if the active theme cannot be loaded.
+func getActiveTheme(c *config.Instance) *theme.Config {
+	id := c.GetActiveTheme()
+	if id == "" {
+		return nil
+	}
+
+	t, err := theme.LoadTheme(c.GetThemesPath(), id)
+	if err != nil {
+		if !errors.Is(err, theme.ErrNotFound) {
+			logger.Errorf("error loading theme %s: %v", id, err)
+		} else {
+			logger.Warnf("active theme %s not found", id)
+		}
+		return nil
+	}
+
+	return t
+}
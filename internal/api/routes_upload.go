@@ -0,0 +1,259 @@
+package api
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi"
+	"github.com/stashapp/stash/internal/manager"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/upload"
+)
+
+// uploadRoutes implements the core, creation and termination parts of the
+// tus resumable upload protocol. Completed uploads are moved to the upload
+// inbox and scanned.
+type uploadRoutes struct{}
+
+const (
+	tusVersion    = "1.0.0"
+	tusExtensions = "creation,creation-with-upload,termination"
+
+	tusOffsetContentType = "application/offset+octet-stream"
+
+	// header containing the id of the scan job queued for a completed upload
+	uploadJobHeader = "Stash-Job-Id"
+)
+
+func (rs uploadRoutes) Routes() chi.Router {
+	r := chi.NewRouter()
+	r.Use(tusHeaders)
+
+	r.Options("/", rs.options)
+
+	r.Group(func(r chi.Router) {
+		r.Use(editorRoleHandler)
+
+		r.Post("/", rs.create)
+
+		r.Route("/{uploadId}", func(r chi.Router) {
+			r.Head("/", rs.head)
+			r.Patch("/", rs.patch)
+			r.Delete("/", rs.delete)
+		})
+	})
+
+	return r
+}
+
+func tusHeaders(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Tus-Resumable", tusVersion)
+		w.Header().Set("Access-Control-Expose-Headers", "Location, Upload-Offset, Upload-Length, Tus-Resumable, "+uploadJobHeader)
+
+		if v := r.Header.Get("Tus-Resumable"); r.Method != http.MethodOptions && v != "" && v != tusVersion {
+			w.Header().Set("Tus-Version", tusVersion)
+			http.Error(w, "unsupported tus version", http.StatusPreconditionFailed)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// uploadErrorStatus returns the http status of an error returned by the
+// upload store.
+func uploadErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, manager.ErrUploadDisabled):
+		return http.StatusForbidden
+	case errors.Is(err, upload.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, upload.ErrOffsetMismatch), errors.Is(err, upload.ErrIncomplete):
+		return http.StatusConflict
+	case errors.Is(err, upload.ErrLocked):
+		return http.StatusLocked
+	case errors.Is(err, upload.ErrTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, upload.ErrInvalidName):
+		return http.StatusBadRequest
+	}
+
+	return http.StatusInternalServerError
+}
+
+func uploadError(w http.ResponseWriter, err error) {
+	status := uploadErrorStatus(err)
+	if status == http.StatusInternalServerError {
+		logger.Errorf("upload: %v", err)
+	}
+
+	http.Error(w, err.Error(), status)
+}
+
+// parseUploadMetadata parses the Upload-Metadata header, which consists of
+// comma-separated pairs of keys and base64 encoded values.
+func parseUploadMetadata(header string) (map[string]string, error) {
+	ret := make(map[string]string)
+	for _, pair := range strings.Split(header, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		key, encoded, _ := strings.Cut(pair, " ")
+		value, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value of metadata %q: %w", key, err)
+		}
+
+		ret[key] = string(value)
+	}
+
+	return ret, nil
+}
+
+func parseUploadOffset(r *http.Request) (int64, error) {
+	v, err := strconv.ParseInt(r.Header.Get("Upload-Offset"), 10, 64)
+	if err != nil || v < 0 {
+		return 0, errors.New("invalid Upload-Offset header")
+	}
+
+	return v, nil
+}
+
+func (rs uploadRoutes) options(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Tus-Version", tusVersion)
+	w.Header().Set("Tus-Extension", tusExtensions)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (rs uploadRoutes) create(w http.ResponseWriter, r *http.Request) {
+	length, err := strconv.ParseInt(r.Header.Get("Upload-Length"), 10, 64)
+	if err != nil || length < 0 {
+		http.Error(w, "invalid Upload-Length header", http.StatusBadRequest)
+		return
+	}
+
+	metadata, err := parseUploadMetadata(r.Header.Get("Upload-Metadata"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	filename := metadata["filename"]
+	if filename == "" {
+		filename = metadata["name"]
+	}
+
+	info, err := manager.GetInstance().CreateUpload(filename, length)
+	if err != nil {
+		uploadError(w, err)
+		return
+	}
+
+	baseURL, _ := r.Context().Value(BaseURLCtxKey).(string)
+	w.Header().Set("Location", baseURL+"/upload/"+info.ID)
+
+	// creation-with-upload: the request may contain the first chunk
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == tusOffsetContentType {
+		rs.write(w, r, info.ID, 0, http.StatusCreated)
+		return
+	}
+
+	rs.writeOffset(w, r, info, http.StatusCreated)
+}
+
+func (rs uploadRoutes) head(w http.ResponseWriter, r *http.Request) {
+	store, err := manager.GetInstance().UploadStore()
+	if err != nil {
+		uploadError(w, err)
+		return
+	}
+
+	info, err := store.Get(chi.URLParam(r, "uploadId"))
+	if err != nil {
+		uploadError(w, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Upload-Offset", strconv.FormatInt(info.Offset, 10))
+	w.Header().Set("Upload-Length", strconv.FormatInt(info.Length, 10))
+	w.WriteHeader(http.StatusOK)
+}
+
+func (rs uploadRoutes) patch(w http.ResponseWriter, r *http.Request) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != tusOffsetContentType {
+		http.Error(w, "Content-Type must be "+tusOffsetContentType, http.StatusUnsupportedMediaType)
+		return
+	}
+
+	offset, err := parseUploadOffset(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rs.write(w, r, chi.URLParam(r, "uploadId"), offset, http.StatusNoContent)
+}
+
+// write appends the request body to the upload, then completes the upload
+// if all bytes have been received.
+func (rs uploadRoutes) write(w http.ResponseWriter, r *http.Request, id string, offset int64, status int) {
+	store, err := manager.GetInstance().UploadStore()
+	if err != nil {
+		uploadError(w, err)
+		return
+	}
+
+	info, err := store.Write(id, offset, r.Body)
+	if err != nil {
+		if info != nil {
+			w.Header().Set("Upload-Offset", strconv.FormatInt(info.Offset, 10))
+		}
+		uploadError(w, err)
+		return
+	}
+
+	rs.writeOffset(w, r, info, status)
+}
+
+// writeOffset writes the offset of the upload, completing the upload first
+// if all bytes have been received.
+func (rs uploadRoutes) writeOffset(w http.ResponseWriter, r *http.Request, info *upload.Info, status int) {
+	if info.Complete() {
+		_, jobID, err := manager.GetInstance().CompleteUpload(r.Context(), info.ID)
+		if err != nil {
+			uploadError(w, err)
+			return
+		}
+
+		w.Header().Set(uploadJobHeader, strconv.Itoa(jobID))
+	}
+
+	w.Header().Set("Upload-Offset", strconv.FormatInt(info.Offset, 10))
+	w.WriteHeader(status)
+}
+
+func (rs uploadRoutes) delete(w http.ResponseWriter, r *http.Request) {
+	store, err := manager.GetInstance().UploadStore()
+	if err != nil {
+		uploadError(w, err)
+		return
+	}
+
+	if err := store.Delete(chi.URLParam(r, "uploadId")); err != nil {
+		uploadError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
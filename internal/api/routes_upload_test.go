@@ -0,0 +1,60 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseUploadMetadata(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			"empty",
+			"",
+			map[string]string{},
+			false,
+		},
+		{
+			"pairs",
+			"filename YS5tcDQ=, filetype dmlkZW8vbXA0",
+			map[string]string{
+				"filename": "a.mp4",
+				"filetype": "video/mp4",
+			},
+			false,
+		},
+		{
+			"key without value",
+			"is_confidential,filename YS5tcDQ=",
+			map[string]string{
+				"is_confidential": "",
+				"filename":        "a.mp4",
+			},
+			false,
+		},
+		{
+			"invalid value",
+			"filename a.mp4",
+			nil,
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseUploadMetadata(tt.header)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/txn"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+type WantedSceneCoverFinder interface {
+	GetCover(ctx context.Context, wantedSceneID int) ([]byte, error)
+}
+
+type wantedRoutes struct {
+	txnManager  txn.Manager
+	coverFinder WantedSceneCoverFinder
+}
+
+func (rs wantedRoutes) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/{wantedSceneId}/cover", rs.Cover)
+
+	return r
+}
+
+func (rs wantedRoutes) Cover(w http.ResponseWriter, r *http.Request) {
+	wantedSceneID, err := strconv.Atoi(chi.URLParam(r, "wantedSceneId"))
+	if err != nil {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+
+	var cover []byte
+	readTxnErr := txn.WithReadTxn(r.Context(), rs.txnManager, func(ctx context.Context) error {
+		cover, _ = rs.coverFinder.GetCover(ctx, wantedSceneID)
+		return nil
+	})
+	if errors.Is(readTxnErr, context.Canceled) {
+		return
+	}
+	if readTxnErr != nil {
+		logger.Warnf("read transaction error on fetch wanted scene cover: %v", readTxnErr)
+	}
+
+	if len(cover) == 0 {
+		http.Error(w, http.StatusText(404), 404)
+		return
+	}
+
+	if err := utils.ServeImage(cover, w, r); err != nil {
+		logger.Warnf("error serving wanted scene cover: %v", err)
+	}
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/internal/manager"
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene"
+)
+
+// sceneTrash moves the files of destroyed scenes to the trash directory
+// instead of deleting them. The methods are no-ops on a nil trash.
+type sceneTrash struct {
+	dir   string
+	mover *file.Mover
+	store models.TrashedSceneWriter
+}
+
+// newSceneTrash returns the trash to destroy scenes with. Returns nil if the
+// files of the scenes are not deleted, or if the trash is disabled.
+func newSceneTrash(repo manager.Repository, deleteFile bool) *sceneTrash {
+	dir := config.GetInstance().GetTrashPath()
+	if !deleteFile || dir == "" {
+		return nil
+	}
+
+	return &sceneTrash{
+		dir:   dir,
+		mover: file.NewMover(repo.File, repo.Folder),
+		store: repo.TrashedScene,
+	}
+}
+
+func (t *sceneTrash) destroy(ctx context.Context, service manager.SceneService, s *models.Scene, fileDeleter *scene.FileDeleter, deleteGenerated bool) error {
+	_, err := service.Trash(ctx, s, fileDeleter, t.mover, t.dir, t.store, deleteGenerated)
+	return err
+}
+
+// rollback moves the trashed files back to their original paths.
+func (t *sceneTrash) rollback() {
+	if t != nil {
+		t.mover.Rollback()
+	}
+}
+
+func (t *sceneTrash) commit() {
+	if t != nil {
+		t.mover.Commit()
+	}
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/go-chi/httplog"
 	"github.com/rs/cors"
 	"github.com/stashapp/stash/internal/api/loaders"
+	"github.com/stashapp/stash/internal/locale"
 	"github.com/stashapp/stash/internal/manager"
 	"github.com/stashapp/stash/internal/manager/config"
 	"github.com/stashapp/stash/pkg/fsutil"
@@ -51,8 +52,17 @@ func Start() error {
 
 	r.Use(middleware.Heartbeat("/healthz"))
 	r.Use(authenticateHandler())
+	r.Use(sessionLockHandler)
 	visitedPluginHandler := manager.GetInstance().SessionStore.VisitedPluginHandler()
 	r.Use(visitedPluginHandler)
+	contentWarningsHandler := manager.GetInstance().SessionStore.ContentWarningsHandler()
+	r.Use(contentWarningsHandler)
+	quickHideHandler := manager.GetInstance().SessionStore.QuickHideHandler()
+	r.Use(quickHideHandler)
+	contentRestrictionHandler := manager.GetInstance().SessionStore.ContentRestrictionHandler()
+	r.Use(contentRestrictionHandler)
+	pluginPermissionsHandler := manager.GetInstance().SessionStore.PluginPermissionsHandler()
+	r.Use(pluginPermissionsHandler)
 
 	r.Use(middleware.Recoverer)
 
@@ -68,6 +78,7 @@ func Start() error {
 	r.Use(middleware.StripSlashes)
 	r.Use(cors.AllowAll().Handler)
 	r.Use(BaseURLMiddleware)
+	r.Use(LocaleMiddleware)
 
 	recoverFunc := func(ctx context.Context, err interface{}) error {
 		logger.Error(err)
@@ -116,17 +127,27 @@ func Start() error {
 		MaxUploadSize: c.GetMaxUploadSize(),
 	})
 
-	gqlSrv.SetQueryCache(gqlLru.New(1000))
+	queryCacheSize := 1000
+	if c.GetLowMemoryMode() {
+		queryCacheSize = 100
+	}
+	gqlSrv.SetQueryCache(gqlLru.New(queryCacheSize))
 	gqlSrv.Use(gqlExtension.Introspection{})
 
+	activity := newActivityRecorder(txnManager)
+	gqlSrv.AroundFields(activity.FieldMiddleware)
+	gqlSrv.AroundFields(newEditHistoryRecorder(txnManager).FieldMiddleware)
+	gqlSrv.AroundRootFields(pluginPermissionsMiddleware)
+	gqlSrv.AroundRootFields(userRoleMiddleware)
+
 	gqlHandlerFunc := func(w http.ResponseWriter, r *http.Request) {
 		gqlSrv.ServeHTTP(w, r)
 	}
 
 	// register GQL handler with plugin cache
-	// chain the visited plugin handler
+	// chain the session handlers applying the plugin cookie state
 	// also requires the dataloader middleware
-	gqlHandler := visitedPluginHandler(dataloaders.Middleware(http.HandlerFunc(gqlHandlerFunc)))
+	gqlHandler := manager.GetInstance().SessionStore.PluginRequestHandler(dataloaders.Middleware(http.HandlerFunc(gqlHandlerFunc)))
 	manager.GetInstance().PluginCache.RegisterGQLHandler(gqlHandler)
 
 	r.HandleFunc("/graphql", gqlHandlerFunc)
@@ -134,7 +155,15 @@ func Start() error {
 
 	// session handlers
 	r.Post(loginEndPoint, handleLogin(loginUIBox))
+	r.Get(loginEndPoint+"/oidc/{provider}", handleExternalLogin)
+	r.Get(loginEndPoint+"/oidc/{provider}/callback", handleExternalLoginCallback(loginUIBox))
 	r.Get("/logout", handleLogout(loginUIBox))
+	r.Post("/contentWarnings/unlock", handleUnlockContentWarnings)
+	r.Post("/contentWarnings/lock", handleLockContentWarnings)
+	r.Post("/sessionLock/lock", handleLockSession)
+	r.Post(sessionLockUnlockEndPoint, handleUnlockSession)
+	r.Post("/quickHide/enable", handleEnableQuickHide)
+	r.Post("/quickHide/disable", handleDisableQuickHide)
 
 	r.Get(loginEndPoint, getLoginHandler(loginUIBox))
 
@@ -147,8 +176,10 @@ func Start() error {
 		sceneFinder:       txnManager.Scene,
 		fileFinder:        txnManager.File,
 		captionFinder:     txnManager.File,
+		chapterFinder:     txnManager.File,
 		sceneMarkerFinder: txnManager.SceneMarker,
 		tagFinder:         txnManager.Tag,
+		activity:          activity,
 	}.Routes())
 	r.Mount("/image", imageRoutes{
 		txnManager:  txnManager,
@@ -167,7 +198,24 @@ func Start() error {
 		txnManager: txnManager,
 		tagFinder:  txnManager.Tag,
 	}.Routes())
+	r.Mount("/wanted", wantedRoutes{
+		txnManager:  txnManager,
+		coverFinder: txnManager.WantedScene,
+	}.Routes())
 	r.Mount("/downloads", downloadsRoutes{}.Routes())
+	r.Mount("/artifacts", artifactRoutes{
+		txnManager:     txnManager,
+		artifactFinder: txnManager.JobArtifact,
+	}.Routes())
+	r.Mount("/hooks", hooksRoutes{}.Routes())
+	r.Mount("/upload", uploadRoutes{}.Routes())
+	r.Mount("/diagnostics", diagnosticsRoutes{}.Routes())
+	r.Mount("/locales", localeRoutes{
+		catalog: manager.GetInstance().Locales,
+	}.Routes())
+	r.Mount("/theme", themeRoutes{
+		config: c,
+	}.Routes())
 
 	r.HandleFunc("/css", cssHandler(c, pluginCache))
 	r.HandleFunc("/javascript", javascriptHandler(c, pluginCache))
@@ -321,13 +369,13 @@ func copyFile(w io.Writer, path string) (time.Time, error) {
 	return info.ModTime(), err
 }
 
-func serveFiles(w http.ResponseWriter, r *http.Request, name string, paths []string) {
-	buffer := bytes.Buffer{}
-
+// copyFiles concatenates the files to the buffer and returns the latest
+// modification time of the files.
+func copyFiles(buffer *bytes.Buffer, paths []string) time.Time {
 	latestModTime := time.Time{}
 
 	for _, path := range paths {
-		modTime, err := copyFile(&buffer, path)
+		modTime, err := copyFile(buffer, path)
 		if err != nil {
 			logger.Errorf("error serving file %s: %v", path, err)
 		} else {
@@ -338,6 +386,16 @@ func serveFiles(w http.ResponseWriter, r *http.Request, name string, paths []str
 		}
 	}
 
+	return latestModTime
+}
+
+func serveFiles(w http.ResponseWriter, r *http.Request, name string, paths []string) {
+	buffer := bytes.Buffer{}
+	latestModTime := copyFiles(&buffer, paths)
+	serveBuffer(w, r, name, latestModTime, &buffer)
+}
+
+func serveBuffer(w http.ResponseWriter, r *http.Request, name string, latestModTime time.Time, buffer *bytes.Buffer) {
 	bufferReader := bytes.NewReader(buffer.Bytes())
 	http.ServeContent(w, r, name, latestModTime, bufferReader)
 }
@@ -354,6 +412,20 @@ func cssHandler(c *config.Instance, pluginCache *plugin.Cache) func(w http.Respo
 			paths = append(paths, p.UI.CSS...)
 		}
 
+		buffer := bytes.Buffer{}
+		latestModTime := copyFiles(&buffer, paths)
+		paths = nil
+
+		// add the active theme after plugins so that it can override them
+		if t := getActiveTheme(c); t != nil {
+			if info, err := os.Stat(t.Path()); err == nil && info.ModTime().After(latestModTime) {
+				latestModTime = info.ModTime()
+			}
+
+			buffer.WriteString(t.CSSVariables())
+			paths = append(paths, t.CSSFiles()...)
+		}
+
 		if c.GetCSSEnabled() {
 			// search for custom.css in current directory, then $HOME/.stash
 			fn := c.GetCSSPath()
@@ -363,7 +435,11 @@ func cssHandler(c *config.Instance, pluginCache *plugin.Cache) func(w http.Respo
 			}
 		}
 
-		serveFiles(w, r, "custom.css", paths)
+		if modTime := copyFiles(&buffer, paths); modTime.After(latestModTime) {
+			latestModTime = modTime
+		}
+
+		serveBuffer(w, r, "custom.css", latestModTime, &buffer)
 	}
 }
 
@@ -517,3 +593,16 @@ func getProxyPrefix(headers http.Header) string {
 
 	return prefix
 }
+
+// LocaleMiddleware stores the Accept-Language header of the request in the
+// context, so that server-generated content can be localised.
+func LocaleMiddleware(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		if acceptLanguage := r.Header.Get("Accept-Language"); acceptLanguage != "" {
+			r = r.WithContext(locale.WithAcceptLanguage(r.Context(), acceptLanguage))
+		}
+
+		next.ServeHTTP(w, r)
+	}
+	return http.HandlerFunc(fn)
+}
@@ -6,9 +6,13 @@ import (
 	"fmt"
 	"html/template"
 	"net/http"
+	"net/url"
+
+	"github.com/go-chi/chi"
 
 	"github.com/stashapp/stash/internal/manager"
 	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/session"
 )
 
@@ -23,9 +27,15 @@ func getLoginPage(loginUIBox embed.FS) []byte {
 	return data
 }
 
+type loginProvider struct {
+	ID   string
+	Name string
+}
+
 type loginTemplateData struct {
-	URL   string
-	Error string
+	URL       string
+	Error     string
+	Providers []loginProvider
 }
 
 func redirectToLogin(loginUIBox embed.FS, w http.ResponseWriter, returnURL string, loginError string) {
@@ -36,7 +46,12 @@ func redirectToLogin(loginUIBox embed.FS, w http.ResponseWriter, returnURL strin
 		return
 	}
 
-	err = templ.Execute(w, loginTemplateData{URL: returnURL, Error: loginError})
+	var providers []loginProvider
+	for _, p := range manager.GetInstance().SessionStore.AuthProviders() {
+		providers = append(providers, loginProvider{ID: p.ID(), Name: p.Name()})
+	}
+
+	err = templ.Execute(w, loginTemplateData{URL: returnURL, Error: loginError, Providers: providers})
 	if err != nil {
 		http.Error(w, fmt.Sprintf("error: %s", err), http.StatusInternalServerError)
 	}
@@ -76,6 +91,61 @@ func handleLogin(loginUIBox embed.FS) http.HandlerFunc {
 	}
 }
 
+// externalLoginRedirectURL returns the URL the authentication provider
+// redirects to after the user logs in.
+func externalLoginRedirectURL(r *http.Request, providerID string) string {
+	baseURL, _ := r.Context().Value(BaseURLCtxKey).(string)
+	return baseURL + loginEndPoint + "/oidc/" + url.PathEscape(providerID) + "/callback"
+}
+
+func handleExternalLogin(w http.ResponseWriter, r *http.Request) {
+	providerID := chi.URLParam(r, "provider")
+
+	u, err := manager.GetInstance().SessionStore.BeginExternalLogin(w, r, providerID, externalLoginRedirectURL(r, providerID), r.URL.Query().Get(returnURLParam))
+	if errors.Is(err, session.ErrUnknownAuthProvider) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	if err != nil {
+		logger.Errorf("Error starting login with %s: %v", providerID, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, u, http.StatusFound)
+}
+
+func handleExternalLoginCallback(loginUIBox embed.FS) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		providerID := chi.URLParam(r, "provider")
+
+		returnURL, err := manager.GetInstance().SessionStore.CompleteExternalLogin(w, r, providerID, externalLoginRedirectURL(r, providerID))
+		if errors.Is(err, session.ErrUnknownAuthProvider) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		if errors.Is(err, session.ErrExternalUserNotLinked) {
+			redirectToLogin(loginUIBox, w, "", "Your account is not linked to a user")
+			return
+		}
+
+		if err != nil {
+			// the details may be sensitive, so only log them
+			logger.Errorf("Error logging in with %s: %v", providerID, err)
+			redirectToLogin(loginUIBox, w, "", "Login failed")
+			return
+		}
+
+		if returnURL == "" {
+			returnURL = "/"
+		}
+
+		http.Redirect(w, r, returnURL, http.StatusFound)
+	}
+}
+
 func handleLogout(loginUIBox embed.FS) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if err := manager.GetInstance().SessionStore.Logout(w, r); err != nil {
@@ -87,3 +157,123 @@ func handleLogout(loginUIBox embed.FS) http.HandlerFunc {
 		getLoginHandler(loginUIBox)(w, r)
 	}
 }
+
+func handleUnlockContentWarnings(w http.ResponseWriter, r *http.Request) {
+	err := manager.GetInstance().SessionStore.UnlockContentWarnings(w, r)
+	if errors.Is(err, session.ErrInvalidCredentials) {
+		http.Error(w, "Password is invalid", http.StatusUnauthorized)
+		return
+	}
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func handleLockContentWarnings(w http.ResponseWriter, r *http.Request) {
+	if err := manager.GetInstance().SessionStore.LockContentWarnings(w, r); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+const sessionLockUnlockEndPoint = "/sessionLock/unlock"
+
+// allowLocked returns true if the request is allowed while the session is
+// locked.
+func allowLocked(r *http.Request) bool {
+	return allowUnauthenticated(r) || r.URL.Path == sessionLockUnlockEndPoint || r.URL.Path == "/logout"
+}
+
+// sessionLockHandler rejects requests made with a locked session. Page
+// requests are redirected to the login page if credentials are set, since
+// logging in again unlocks the session.
+func sessionLockHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := manager.GetInstance().SessionStore.CheckSessionLock(w, r)
+		if errors.Is(err, session.ErrSessionLocked) && !allowLocked(r) {
+			if r.Method == http.MethodGet && r.URL.Path != "/graphql" && config.GetInstance().HasCredentials() {
+				redirectToLoginPage(w, r)
+				return
+			}
+
+			http.Error(w, err.Error(), http.StatusLocked)
+			return
+		}
+
+		if err != nil && !errors.Is(err, session.ErrSessionLocked) {
+			logger.Errorf("Error checking session lock: %v", err)
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func handleLockSession(w http.ResponseWriter, r *http.Request) {
+	err := manager.GetInstance().SessionStore.LockSession(w, r)
+	if errors.Is(err, session.ErrSessionLockDisabled) || errors.Is(err, session.ErrUnauthorized) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func handleUnlockSession(w http.ResponseWriter, r *http.Request) {
+	err := manager.GetInstance().SessionStore.UnlockSession(w, r)
+	if errors.Is(err, session.ErrInvalidPin) {
+		http.Error(w, "PIN is invalid", http.StatusUnauthorized)
+		return
+	}
+
+	if errors.Is(err, session.ErrTooManyPinAttempts) {
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+		return
+	}
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func handleEnableQuickHide(w http.ResponseWriter, r *http.Request) {
+	if err := manager.GetInstance().SessionStore.EnableQuickHide(w, r); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func handleDisableQuickHide(w http.ResponseWriter, r *http.Request) {
+	err := manager.GetInstance().SessionStore.DisableQuickHide(w, r)
+	if errors.Is(err, session.ErrInvalidPin) {
+		http.Error(w, "PIN is invalid", http.StatusUnauthorized)
+		return
+	}
+
+	if errors.Is(err, session.ErrTooManyPinAttempts) {
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+		return
+	}
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
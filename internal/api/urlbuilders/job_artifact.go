@@ -0,0 +1,26 @@
+package urlbuilders
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+type JobArtifactURLBuilder struct {
+	BaseURL       string
+	JobArtifactID string
+	Name          string
+}
+
+func NewJobArtifactURLBuilder(baseURL string, artifact *models.JobArtifact) JobArtifactURLBuilder {
+	return JobArtifactURLBuilder{
+		BaseURL:       baseURL,
+		JobArtifactID: strconv.Itoa(artifact.ID),
+		Name:          artifact.Name,
+	}
+}
+
+func (b JobArtifactURLBuilder) GetURL() string {
+	return b.BaseURL + "/artifacts/" + b.JobArtifactID + "/" + url.PathEscape(b.Name)
+}
@@ -51,6 +51,14 @@ func (b SceneURLBuilder) GetSpriteURL() string {
 	return b.BaseURL + "/scene/" + b.SceneID + "_sprite.jpg"
 }
 
+func (b SceneURLBuilder) GetHoverStripURL() string {
+	return b.BaseURL + "/scene/" + b.SceneID + "_hover.mp4"
+}
+
+func (b SceneURLBuilder) GetHoverStripVTTURL() string {
+	return b.BaseURL + "/scene/" + b.SceneID + "_hover.vtt"
+}
+
 func (b SceneURLBuilder) GetScreenshotURL(updateTime time.Time) string {
 	return b.BaseURL + "/scene/" + b.SceneID + "/screenshot?" + strconv.FormatInt(updateTime.Unix(), 10)
 }
@@ -59,6 +67,16 @@ func (b SceneURLBuilder) GetChaptersVTTURL() string {
 	return b.BaseURL + "/scene/" + b.SceneID + "/vtt/chapter"
 }
 
+// GetChapterThumbnailURL returns the URL of the thumbnail of a chapter. The
+// start time is included so that the URL changes when the chapter is moved.
+func (b SceneURLBuilder) GetChapterThumbnailURL(index int, start float64) string {
+	return b.BaseURL + "/scene/" + b.SceneID + "/chapter/" + strconv.Itoa(index) + "/thumbnail?" + strconv.FormatInt(int64(start*1000), 10)
+}
+
+func (b SceneURLBuilder) GetSubtitleVTTURL(index int) string {
+	return b.BaseURL + "/scene/" + b.SceneID + "/subtitle/" + strconv.Itoa(index) + "/vtt"
+}
+
 func (b SceneURLBuilder) GetSceneMarkerStreamURL(sceneMarkerID int) string {
 	return b.BaseURL + "/scene/" + b.SceneID + "/scene_marker/" + strconv.Itoa(sceneMarkerID) + "/stream"
 }
@@ -75,6 +93,10 @@ func (b SceneURLBuilder) GetFunscriptURL() string {
 	return b.BaseURL + "/scene/" + b.SceneID + "/funscript"
 }
 
+func (b SceneURLBuilder) GetTrailerURL() string {
+	return b.BaseURL + "/scene/" + b.SceneID + "/trailer"
+}
+
 func (b SceneURLBuilder) GetCaptionURL() string {
 	return b.BaseURL + "/scene/" + b.SceneID + "/caption"
 }
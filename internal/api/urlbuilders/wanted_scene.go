@@ -0,0 +1,25 @@
+package urlbuilders
+
+import (
+	"strconv"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+type WantedSceneURLBuilder struct {
+	BaseURL       string
+	WantedSceneID string
+	UpdatedAt     string
+}
+
+func NewWantedSceneURLBuilder(baseURL string, wanted *models.WantedScene) WantedSceneURLBuilder {
+	return WantedSceneURLBuilder{
+		BaseURL:       baseURL,
+		WantedSceneID: strconv.Itoa(wanted.ID),
+		UpdatedAt:     strconv.FormatInt(wanted.UpdatedAt.Unix(), 10),
+	}
+}
+
+func (b WantedSceneURLBuilder) GetCoverImageURL() string {
+	return b.BaseURL + "/wanted/" + b.WantedSceneID + "/cover?" + b.UpdatedAt
+}
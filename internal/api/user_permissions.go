@@ -0,0 +1,94 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/99designs/gqlgen/graphql"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/session"
+)
+
+// userAdminOperations are the queries and mutations which require the admin
+// role, in addition to those requiring the admin permission when run by a
+// plugin.
+var userAdminOperations = map[string]bool{
+	"allUsers":                   true,
+	"userCreate":                 true,
+	"userUpdate":                 true,
+	"userDestroy":                true,
+	"userLinkExternalIdentity":   true,
+	"userUnlinkExternalIdentity": true,
+	"findActivityLog":            true,
+	"allRestrictionProfiles":     true,
+	"restrictionProfileCreate":   true,
+	"restrictionProfileUpdate":   true,
+	"restrictionProfileDestroy":  true,
+	"stashPathAdd":               true,
+	"stashPathUpdate":            true,
+	"stashPathRemove":            true,
+	"stashPathsReorder":          true,
+}
+
+// userReadOnlyMutations are the mutations which users with the read-only
+// role may run. They record playback or only affect the user's own account.
+var userReadOnlyMutations = map[string]bool{
+	"userChangePassword":      true,
+	"userGenerateAPIKey":      true,
+	"sceneSaveActivity":       true,
+	"sceneIncrementPlayCount": true,
+	"playbackEventsCreate":    true,
+	"playQueueAppend":         true,
+	"playQueueReorder":        true,
+	"playQueuePop":            true,
+	"playQueueRemove":         true,
+	"playQueueClear":          true,
+}
+
+func requiredUserRole(object string, field string) models.UserRole {
+	if userAdminOperations[field] {
+		return models.UserRoleAdmin
+	}
+
+	if object != "Mutation" || userReadOnlyMutations[field] {
+		return models.UserRoleReadOnly
+	}
+
+	if requiredPluginPermission(field) == session.PluginPermissionAdmin {
+		return models.UserRoleAdmin
+	}
+
+	return models.UserRoleEditor
+}
+
+// userRoleMiddleware rejects queries and mutations that the role of the
+// current user does not allow.
+func userRoleMiddleware(ctx context.Context, next graphql.RootResolver) graphql.Marshaler {
+	fc := graphql.GetRootFieldContext(ctx)
+	if fc == nil {
+		return next(ctx)
+	}
+
+	required := requiredUserRole(fc.Object, fc.Field.Name)
+	if !session.HasUserRole(ctx, required) {
+		graphql.AddErrorf(ctx, "the %s role is required to run %s", required, fc.Field.Name)
+		return graphql.Null
+	}
+
+	return next(ctx)
+}
+
+// editorRoleHandler rejects requests from users without the editor role. It
+// is used by HTTP endpoints that add files to the library, which require the
+// same role as mutations.
+func editorRoleHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !session.HasUserRole(r.Context(), models.UserRoleEditor) {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
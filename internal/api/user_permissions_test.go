@@ -0,0 +1,136 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/session"
+)
+
+func TestRequiredUserRole(t *testing.T) {
+	tests := []struct {
+		object string
+		field  string
+		want   models.UserRole
+	}{
+		{"Query", "findScenes", models.UserRoleReadOnly},
+		{"Query", "allUsers", models.UserRoleAdmin},
+		{"Query", "findActivityLog", models.UserRoleAdmin},
+		{"Mutation", "sceneUpdate", models.UserRoleEditor},
+		{"Mutation", "sceneSaveActivity", models.UserRoleReadOnly},
+		{"Mutation", "userChangePassword", models.UserRoleReadOnly},
+		{"Mutation", "userCreate", models.UserRoleAdmin},
+		{"Mutation", "configureGeneral", models.UserRoleAdmin},
+		{"Mutation", "metadataImport", models.UserRoleAdmin},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.object+"."+tt.field, func(t *testing.T) {
+			assert.Equal(t, tt.want, requiredUserRole(tt.object, tt.field))
+		})
+	}
+}
+
+func TestUserRoleMiddlewareStashPaths(t *testing.T) {
+	mutations := []string{"stashPathAdd", "stashPathUpdate", "stashPathRemove", "stashPathsReorder"}
+
+	for _, m := range mutations {
+		t.Run(m, func(t *testing.T) {
+			run := func(role models.UserRole) (called bool, errs gqlerror.List) {
+				ctx := graphql.WithResponseContext(context.Background(), graphql.DefaultErrorPresenter, graphql.DefaultRecover)
+				ctx = graphql.WithRootFieldContext(ctx, &graphql.RootFieldContext{
+					Object: "Mutation",
+					Field:  graphql.CollectedField{Field: &ast.Field{Name: m}},
+				})
+				ctx = session.SetCurrentUserRole(ctx, role)
+
+				userRoleMiddleware(ctx, func(ctx context.Context) graphql.Marshaler {
+					called = true
+					return graphql.Null
+				})
+
+				return called, graphql.GetErrors(ctx)
+			}
+
+			called, errs := run(models.UserRoleEditor)
+			assert.False(t, called, "editor should not run %s", m)
+			assert.Len(t, errs, 1)
+
+			called, errs = run(models.UserRoleAdmin)
+			assert.True(t, called, "admin should run %s", m)
+			assert.Empty(t, errs)
+
+			assert.Equal(t, session.PluginPermissionAdmin, requiredPluginPermission(m))
+		})
+	}
+}
+
+func TestEditorRoleHandler(t *testing.T) {
+	routes := []struct {
+		name    string
+		handler http.Handler
+		method  string
+		path    string
+	}{
+		{"upload create", uploadRoutes{}.Routes(), http.MethodPost, "/"},
+		{"upload patch", uploadRoutes{}.Routes(), http.MethodPatch, "/abc"},
+		{"upload delete", uploadRoutes{}.Routes(), http.MethodDelete, "/abc"},
+		{"download complete hook", hooksRoutes{}.Routes(), http.MethodPost, "/download-complete"},
+	}
+
+	for _, tt := range routes {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, tt.path, nil)
+			r = r.WithContext(session.SetCurrentUserRole(r.Context(), models.UserRoleReadOnly))
+			w := httptest.NewRecorder()
+
+			tt.handler.ServeHTTP(w, r)
+
+			assert.Equal(t, http.StatusForbidden, w.Code)
+		})
+	}
+
+	t.Run("editor", func(t *testing.T) {
+		called := false
+		h := editorRoleHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		}))
+
+		r := httptest.NewRequest(http.MethodPost, "/", nil)
+		r = r.WithContext(session.SetCurrentUserRole(r.Context(), models.UserRoleEditor))
+		h.ServeHTTP(httptest.NewRecorder(), r)
+
+		assert.True(t, called)
+	})
+}
+
+func TestRedactConfigSecrets(t *testing.T) {
+	token := "media"
+	newConfig := func() *ConfigResult {
+		return &ConfigResult{General: &ConfigGeneralResult{
+			APIKey:           "apikey",
+			Password:         "password",
+			SessionLockPin:   "1234",
+			MediaAccessToken: &token,
+			StashBoxes:       []*models.StashBox{{Endpoint: "endpoint", APIKey: "stashbox"}},
+		}}
+	}
+
+	c := newConfig()
+	redactConfigSecrets(session.SetCurrentUserRole(context.Background(), models.UserRoleAdmin), c)
+	assert.Equal(t, newConfig(), c)
+
+	c = newConfig()
+	redactConfigSecrets(session.SetCurrentUserRole(context.Background(), models.UserRoleEditor), c)
+	assert.Equal(t, &ConfigGeneralResult{
+		StashBoxes: []*models.StashBox{{Endpoint: "endpoint"}},
+	}, c.General)
+}
@@ -360,7 +360,7 @@ func (me *contentDirectoryService) handleBrowseMetadata(obj object, host string)
 	} else {
 		var scene *models.Scene
 
-		if err := txn.WithReadTxn(context.TODO(), me.txnManager, func(ctx context.Context) error {
+		if err := txn.WithReadTxn(dlnaContext(context.TODO()), me.txnManager, func(ctx context.Context) error {
 			scene, err = me.repository.SceneFinder.Find(ctx, sceneID)
 			if scene != nil {
 				err = scene.LoadPrimaryFile(ctx, me.repository.FileFinder)
@@ -443,7 +443,7 @@ func getRootObjects() []interface{} {
 func (me *contentDirectoryService) getVideos(sceneFilter *models.SceneFilterType, parentID string, host string) []interface{} {
 	var objs []interface{}
 
-	if err := txn.WithReadTxn(context.TODO(), me.txnManager, func(ctx context.Context) error {
+	if err := txn.WithReadTxn(dlnaContext(context.TODO()), me.txnManager, func(ctx context.Context) error {
 		sort := "title"
 		findFilter := &models.FindFilterType{
 			PerPage: &pageSize,
@@ -486,7 +486,7 @@ func (me *contentDirectoryService) getVideos(sceneFilter *models.SceneFilterType
 func (me *contentDirectoryService) getPageVideos(sceneFilter *models.SceneFilterType, parentID string, page int, host string) []interface{} {
 	var objs []interface{}
 
-	if err := txn.WithReadTxn(context.TODO(), me.txnManager, func(ctx context.Context) error {
+	if err := txn.WithReadTxn(dlnaContext(context.TODO()), me.txnManager, func(ctx context.Context) error {
 		pager := scenePager{
 			sceneFilter: sceneFilter,
 			parentID:    parentID,
@@ -527,7 +527,7 @@ func (me *contentDirectoryService) getAllScenes(host string) []interface{} {
 func (me *contentDirectoryService) getStudios() []interface{} {
 	var objs []interface{}
 
-	if err := txn.WithReadTxn(context.TODO(), me.txnManager, func(ctx context.Context) error {
+	if err := txn.WithReadTxn(dlnaContext(context.TODO()), me.txnManager, func(ctx context.Context) error {
 		studios, err := me.repository.StudioFinder.All(ctx)
 		if err != nil {
 			return err
@@ -566,7 +566,7 @@ func (me *contentDirectoryService) getStudioScenes(paths []string, host string)
 func (me *contentDirectoryService) getTags() []interface{} {
 	var objs []interface{}
 
-	if err := txn.WithReadTxn(context.TODO(), me.txnManager, func(ctx context.Context) error {
+	if err := txn.WithReadTxn(dlnaContext(context.TODO()), me.txnManager, func(ctx context.Context) error {
 		tags, err := me.repository.TagFinder.All(ctx)
 		if err != nil {
 			return err
@@ -605,7 +605,7 @@ func (me *contentDirectoryService) getTagScenes(paths []string, host string) []i
 func (me *contentDirectoryService) getPerformers() []interface{} {
 	var objs []interface{}
 
-	if err := txn.WithReadTxn(context.TODO(), me.txnManager, func(ctx context.Context) error {
+	if err := txn.WithReadTxn(dlnaContext(context.TODO()), me.txnManager, func(ctx context.Context) error {
 		performers, err := me.repository.PerformerFinder.All(ctx)
 		if err != nil {
 			return err
@@ -644,7 +644,7 @@ func (me *contentDirectoryService) getPerformerScenes(paths []string, host strin
 func (me *contentDirectoryService) getMovies() []interface{} {
 	var objs []interface{}
 
-	if err := txn.WithReadTxn(context.TODO(), me.txnManager, func(ctx context.Context) error {
+	if err := txn.WithReadTxn(dlnaContext(context.TODO()), me.txnManager, func(ctx context.Context) error {
 		movies, err := me.repository.MovieFinder.All(ctx)
 		if err != nil {
 			return err
@@ -294,6 +294,12 @@ func init() {
 	startTime = time.Now()
 }
 
+// dlnaContext returns a context hiding content warning tagged content, since
+// DLNA clients cannot unlock content warnings.
+func dlnaContext(ctx context.Context) context.Context {
+	return models.HideContentWarnings(ctx)
+}
+
 func xmlMarshalOrPanic(value interface{}) []byte {
 	ret, err := xml.MarshalIndent(value, "", "  ")
 	if err != nil {
@@ -439,7 +445,7 @@ func (me *Server) serveIcon(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var scene *models.Scene
-	err := txn.WithReadTxn(r.Context(), me.txnManager, func(ctx context.Context) error {
+	err := txn.WithReadTxn(dlnaContext(r.Context()), me.txnManager, func(ctx context.Context) error {
 		idInt, err := strconv.Atoi(sceneId)
 		if err != nil {
 			return nil
@@ -579,7 +585,7 @@ func (me *Server) initMux(mux *http.ServeMux) {
 	mux.HandleFunc(resPath, func(w http.ResponseWriter, r *http.Request) {
 		sceneId := r.URL.Query().Get("scene")
 		var scene *models.Scene
-		err := txn.WithReadTxn(r.Context(), me.txnManager, func(ctx context.Context) error {
+		err := txn.WithReadTxn(dlnaContext(r.Context()), me.txnManager, func(ctx context.Context) error {
 			sceneIdInt, err := strconv.Atoi(sceneId)
 			if err != nil {
 				return nil
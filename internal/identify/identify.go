@@ -21,9 +21,12 @@ type SceneUpdatePostHookExecutor interface {
 }
 
 type ScraperSource struct {
-	Name       string
-	Options    *MetadataOptions
-	Scraper    SceneScraper
+	Name    string
+	Options *MetadataOptions
+	Scraper SceneScraper
+	// ID of the scraper, used as the source of external IDs. Empty for
+	// stash-box sources, which set stash IDs instead.
+	ScraperID  string
 	RemoteSite string
 }
 
@@ -37,25 +40,49 @@ type SceneIdentifier struct {
 	Sources                     []ScraperSource
 	ScreenshotSetter            scene.ScreenshotSetter
 	SceneUpdatePostHookExecutor SceneUpdatePostHookExecutor
+
+	// CombineSources uses every source that finds a match, in order, instead
+	// of only the first. Fields set by a source are not replaced by the
+	// sources after it.
+	CombineSources bool
 }
 
-func (t *SceneIdentifier) Identify(ctx context.Context, txnManager txn.Manager, scene *models.Scene) error {
-	result, err := t.scrapeScene(ctx, scene)
-	if err != nil {
-		return err
+// Identify scrapes the scene using the sources and updates it with the
+// results. Returns a report of the changed fields.
+func (t *SceneIdentifier) Identify(ctx context.Context, txnManager txn.Manager, scene *models.Scene) (*SceneReport, error) {
+	results := t.scrapeScene(ctx, scene)
+
+	report := &SceneReport{
+		SceneID: scene.ID,
+		Path:    scene.Path,
 	}
 
-	if result == nil {
+	if len(results) == 0 {
 		logger.Debugf("Unable to identify %s", scene.Path)
-		return nil
+		return report, nil
 	}
 
-	// results were found, modify the scene
-	if err := t.modifyScene(ctx, txnManager, scene, result); err != nil {
-		return fmt.Errorf("error modifying scene: %v", err)
+	// results were found, modify the scene. Fields set by earlier results
+	// take precedence.
+	set := make(map[string]bool)
+	for _, result := range results {
+		report.Sources = append(report.Sources, result.source.Name)
+
+		fields, err := t.modifyScene(ctx, txnManager, scene, result, set)
+		if err != nil {
+			return report, fmt.Errorf("error modifying scene: %v", err)
+		}
+
+		for _, f := range fields {
+			set[f] = true
+			report.Fields = append(report.Fields, FieldChange{
+				Field:  f,
+				Source: result.source.Name,
+			})
+		}
 	}
 
-	return nil
+	return report, nil
 }
 
 type scrapeResult struct {
@@ -63,7 +90,11 @@ type scrapeResult struct {
 	source ScraperSource
 }
 
-func (t *SceneIdentifier) scrapeScene(ctx context.Context, scene *models.Scene) (*scrapeResult, error) {
+// scrapeScene returns the result of the first source that finds a match, or
+// of every source that finds a match if sources are combined.
+func (t *SceneIdentifier) scrapeScene(ctx context.Context, scene *models.Scene) []*scrapeResult {
+	var ret []*scrapeResult
+
 	// iterate through the input sources
 	for _, source := range t.Sources {
 		// scrape using the source
@@ -73,19 +104,22 @@ func (t *SceneIdentifier) scrapeScene(ctx context.Context, scene *models.Scene)
 			continue
 		}
 
-		// if results were found then return
 		if scraped != nil {
-			return &scrapeResult{
+			ret = append(ret, &scrapeResult{
 				result: scraped,
 				source: source,
-			}, nil
+			})
+
+			if !t.CombineSources {
+				break
+			}
 		}
 	}
 
-	return nil, nil
+	return ret
 }
 
-func (t *SceneIdentifier) getSceneUpdater(ctx context.Context, s *models.Scene, result *scrapeResult) (*scene.UpdateSet, error) {
+func (t *SceneIdentifier) getSceneUpdater(ctx context.Context, s *models.Scene, result *scrapeResult, set map[string]bool) (*scene.UpdateSet, error) {
 	ret := &scene.UpdateSet{
 		ID: s.ID,
 	}
@@ -99,6 +133,7 @@ func (t *SceneIdentifier) getSceneUpdater(ctx context.Context, s *models.Scene,
 	}
 
 	fieldOptions := getFieldOptions(options)
+	fieldOptions = withoutSetFields(fieldOptions, set)
 
 	setOrganized := false
 	for _, o := range options {
@@ -180,7 +215,7 @@ func (t *SceneIdentifier) getSceneUpdater(ctx context.Context, s *models.Scene,
 		}
 	}
 
-	if setCoverImage {
+	if setCoverImage && !set[FieldCoverImage] {
 		ret.CoverImage, err = rel.cover(ctx)
 		if err != nil {
 			return nil, err
@@ -190,8 +225,12 @@ func (t *SceneIdentifier) getSceneUpdater(ctx context.Context, s *models.Scene,
 	return ret, nil
 }
 
-func (t *SceneIdentifier) modifyScene(ctx context.Context, txnManager txn.Manager, s *models.Scene, result *scrapeResult) error {
+// modifyScene updates the scene with the result, excluding the fields in set,
+// and returns the names of the changed fields. s is updated to the modified
+// scene.
+func (t *SceneIdentifier) modifyScene(ctx context.Context, txnManager txn.Manager, s *models.Scene, result *scrapeResult, set map[string]bool) ([]string, error) {
 	var updater *scene.UpdateSet
+	var changed []string
 	if err := txn.WithTxn(ctx, txnManager, func(ctx context.Context) error {
 		// load scene relationships
 		if err := s.LoadPerformerIDs(ctx, t.SceneReaderUpdater); err != nil {
@@ -205,21 +244,35 @@ func (t *SceneIdentifier) modifyScene(ctx context.Context, txnManager txn.Manage
 		}
 
 		var err error
-		updater, err = t.getSceneUpdater(ctx, s, result)
+		updater, err = t.getSceneUpdater(ctx, s, result, set)
 		if err != nil {
 			return err
 		}
 
+		externalIDSet, err := t.setExternalID(ctx, s, result)
+		if err != nil {
+			return fmt.Errorf("error setting external id: %w", err)
+		}
+		if externalIDSet {
+			changed = append(changed, FieldExternalIDs)
+		}
+
 		// don't update anything if nothing was set
 		if updater.IsEmpty() {
 			logger.Debugf("Nothing to set for %s", s.Path)
 			return nil
 		}
 
-		if _, err := updater.Update(ctx, t.SceneReaderUpdater, t.ScreenshotSetter); err != nil {
+		updated, err := updater.Update(ctx, t.SceneReaderUpdater, t.ScreenshotSetter)
+		if err != nil {
 			return fmt.Errorf("error updating scene: %w", err)
 		}
 
+		changed = append(changed, updatedFields(updater.UpdateInput())...)
+		if updated != nil {
+			*s = *updated
+		}
+
 		as := ""
 		title := updater.Partial.Title
 		if title.Ptr() != nil {
@@ -229,7 +282,7 @@ func (t *SceneIdentifier) modifyScene(ctx context.Context, txnManager txn.Manage
 
 		return nil
 	}); err != nil {
-		return err
+		return nil, err
 	}
 
 	// fire post-update hooks
@@ -239,7 +292,37 @@ func (t *SceneIdentifier) modifyScene(ctx context.Context, txnManager txn.Manage
 		t.SceneUpdatePostHookExecutor.ExecuteSceneUpdatePostHooks(ctx, updateInput, fields)
 	}
 
-	return nil
+	return changed, nil
+}
+
+// setExternalID records the remote site ID returned by a scraper source as an
+// external ID of the scene. Returns true if the external ID was added.
+func (t *SceneIdentifier) setExternalID(ctx context.Context, s *models.Scene, result *scrapeResult) (bool, error) {
+	remoteSiteID := result.result.RemoteSiteID
+	if result.source.ScraperID == "" || remoteSiteID == nil || *remoteSiteID == "" {
+		return false, nil
+	}
+
+	existing, err := t.SceneReaderUpdater.GetExternalIDs(ctx, s.ID)
+	if err != nil {
+		return false, err
+	}
+
+	toSet := models.ExternalID{
+		Source: result.source.ScraperID,
+		ID:     *remoteSiteID,
+	}
+	for _, v := range existing {
+		if v == toSet {
+			return false, nil
+		}
+	}
+
+	if err := t.SceneReaderUpdater.UpdateExternalIDs(ctx, s.ID, models.MergeExternalIDs(existing, []models.ExternalID{toSet})); err != nil {
+		return false, err
+	}
+
+	return true, nil
 }
 
 func getFieldOptions(options []MetadataOptions) map[string]*FieldOptions {
@@ -299,17 +382,32 @@ func getScenePartial(scene *models.Scene, scraped *scraper.ScrapedScene, fieldOp
 	return partial
 }
 
-func shouldSetSingleValueField(strategy *FieldOptions, hasExistingValue bool) bool {
+func getFieldStrategy(strategy *FieldOptions) FieldStrategy {
 	// if unset then default to MERGE
-	fs := FieldStrategyMerge
-
 	if strategy != nil && strategy.Strategy.IsValid() {
-		fs = strategy.Strategy
+		return strategy.Strategy
 	}
 
+	return FieldStrategyMerge
+}
+
+func shouldSetSingleValueField(strategy *FieldOptions, hasExistingValue bool) bool {
+	fs := getFieldStrategy(strategy)
+
 	if fs == FieldStrategyIgnore {
 		return false
 	}
 
 	return !hasExistingValue || fs == FieldStrategyOverwrite
 }
+
+func shouldSetMultiValueField(strategy *FieldOptions, hasExistingValue bool) bool {
+	switch getFieldStrategy(strategy) {
+	case FieldStrategyIgnore:
+		return false
+	case FieldStrategySkipIfSet:
+		return !hasExistingValue
+	}
+
+	return true
+}
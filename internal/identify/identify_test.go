@@ -133,13 +133,120 @@ func TestSceneIdentifier_Identify(t *testing.T) {
 				TagIDs:       models.NewRelatedIDs([]int{}),
 				StashIDs:     models.NewRelatedStashIDs([]models.StashID{}),
 			}
-			if err := identifier.Identify(testCtx, &mocks.TxnManager{}, scene); (err != nil) != tt.wantErr {
+			if _, err := identifier.Identify(testCtx, &mocks.TxnManager{}, scene); (err != nil) != tt.wantErr {
 				t.Errorf("SceneIdentifier.Identify() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
 
+func TestSceneIdentifier_IdentifyCombineSources(t *testing.T) {
+	const sceneID = 1
+
+	var (
+		title1   = "title1"
+		title2   = "title2"
+		details2 = "details2"
+		code3    = "code3"
+	)
+
+	sources := []ScraperSource{
+		{
+			Name: "first",
+			Scraper: mockSceneScraper{
+				results: map[int]*scraper.ScrapedScene{
+					sceneID: {Title: &title1},
+				},
+			},
+		},
+		{
+			Name:    "missing",
+			Scraper: mockSceneScraper{},
+		},
+		{
+			Name: "second",
+			Scraper: mockSceneScraper{
+				results: map[int]*scraper.ScrapedScene{
+					sceneID: {Title: &title2, Details: &details2},
+				},
+			},
+			Options: &MetadataOptions{
+				FieldOptions: []*FieldOptions{
+					{Field: "title", Strategy: FieldStrategyOverwrite},
+				},
+			},
+		},
+		{
+			Name: "third",
+			Scraper: mockSceneScraper{
+				results: map[int]*scraper.ScrapedScene{
+					sceneID: {Code: &code3},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		combineSources bool
+		wantSources    []string
+		wantFields     []FieldChange
+	}{
+		{
+			"first match",
+			false,
+			[]string{"first"},
+			[]FieldChange{
+				{Field: "title", Source: "first"},
+			},
+		},
+		{
+			"combined",
+			true,
+			[]string{"first", "second", "third"},
+			[]FieldChange{
+				{Field: "title", Source: "first"},
+				{Field: "details", Source: "second"},
+				{Field: "code", Source: "third"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSceneReaderWriter := &mocks.SceneReaderWriter{}
+			mockSceneReaderWriter.On("UpdatePartial", mock.Anything, sceneID, mock.Anything).Return(nil, nil)
+
+			identifier := SceneIdentifier{
+				SceneReaderUpdater:          mockSceneReaderWriter,
+				DefaultOptions:              &MetadataOptions{},
+				Sources:                     sources,
+				SceneUpdatePostHookExecutor: mockHookExecutor{},
+				CombineSources:              tt.combineSources,
+			}
+
+			scene := &models.Scene{
+				ID:           sceneID,
+				PerformerIDs: models.NewRelatedIDs([]int{}),
+				TagIDs:       models.NewRelatedIDs([]int{}),
+				StashIDs:     models.NewRelatedStashIDs([]models.StashID{}),
+			}
+			got, err := identifier.Identify(testCtx, &mocks.TxnManager{}, scene)
+			if err != nil {
+				t.Errorf("SceneIdentifier.Identify() error = %v", err)
+				return
+			}
+
+			if !reflect.DeepEqual(got.Sources, tt.wantSources) {
+				t.Errorf("SceneIdentifier.Identify() sources = %v, want %v", got.Sources, tt.wantSources)
+			}
+			if !reflect.DeepEqual(got.Fields, tt.wantFields) {
+				t.Errorf("SceneIdentifier.Identify() fields = %v, want %v", got.Fields, tt.wantFields)
+			}
+		})
+	}
+}
+
 func TestSceneIdentifier_modifyScene(t *testing.T) {
 	repo := models.Repository{
 		TxnManager: &mocks.TxnManager{},
@@ -172,13 +279,67 @@ func TestSceneIdentifier_modifyScene(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := tr.modifyScene(testCtx, repo, tt.args.scene, tt.args.result); (err != nil) != tt.wantErr {
+			if _, err := tr.modifyScene(testCtx, repo, tt.args.scene, tt.args.result, nil); (err != nil) != tt.wantErr {
 				t.Errorf("SceneIdentifier.modifyScene() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
 
+func TestSceneIdentifier_setExternalID(t *testing.T) {
+	const (
+		sceneID        = 1
+		existingID     = 2
+		scraperID      = "scraper"
+		otherScraperID = "other"
+		remoteSiteID   = "remoteSiteID"
+	)
+
+	remoteID := remoteSiteID
+	otherID := "otherID"
+
+	mockSceneReaderWriter := &mocks.SceneReaderWriter{}
+	mockSceneReaderWriter.On("GetExternalIDs", testCtx, sceneID).Return([]models.ExternalID{
+		{Source: otherScraperID, ID: otherID},
+	}, nil)
+	mockSceneReaderWriter.On("GetExternalIDs", testCtx, existingID).Return([]models.ExternalID{
+		{Source: scraperID, ID: remoteSiteID},
+	}, nil)
+	mockSceneReaderWriter.On("UpdateExternalIDs", testCtx, sceneID, []models.ExternalID{
+		{Source: otherScraperID, ID: otherID},
+		{Source: scraperID, ID: remoteSiteID},
+	}).Return(nil).Once()
+
+	tr := &SceneIdentifier{
+		SceneReaderUpdater: mockSceneReaderWriter,
+	}
+
+	tests := []struct {
+		name         string
+		sceneID      int
+		scraperID    string
+		remoteSiteID *string
+	}{
+		{"set", sceneID, scraperID, &remoteID},
+		{"already set", existingID, scraperID, &remoteID},
+		{"stash-box source", sceneID, "", &remoteID},
+		{"no remote site id", sceneID, scraperID, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &scrapeResult{
+				source: ScraperSource{ScraperID: tt.scraperID},
+				result: &scraper.ScrapedScene{RemoteSiteID: tt.remoteSiteID},
+			}
+			if _, err := tr.setExternalID(testCtx, &models.Scene{ID: tt.sceneID}, result); err != nil {
+				t.Errorf("SceneIdentifier.setExternalID() error = %v", err)
+			}
+		})
+	}
+
+	mockSceneReaderWriter.AssertExpectations(t)
+}
+
 func Test_getFieldOptions(t *testing.T) {
 	const (
 		inFirst  = "inFirst"
@@ -466,6 +627,26 @@ func Test_shouldSetSingleValueField(t *testing.T) {
 			},
 			true,
 		},
+		{
+			"skip if set existing",
+			args{
+				&FieldOptions{
+					Strategy: FieldStrategySkipIfSet,
+				},
+				true,
+			},
+			false,
+		},
+		{
+			"skip if set absent",
+			args{
+				&FieldOptions{
+					Strategy: FieldStrategySkipIfSet,
+				},
+				false,
+			},
+			true,
+		},
 		{
 			"nil (merge) existing",
 			args{
@@ -511,3 +692,26 @@ func Test_shouldSetSingleValueField(t *testing.T) {
 		})
 	}
 }
+
+func Test_shouldSetMultiValueField(t *testing.T) {
+	tests := []struct {
+		name             string
+		strategy         *FieldOptions
+		hasExistingValue bool
+		want             bool
+	}{
+		{"ignore", &FieldOptions{Strategy: FieldStrategyIgnore}, false, false},
+		{"merge existing", &FieldOptions{Strategy: FieldStrategyMerge}, true, true},
+		{"overwrite existing", &FieldOptions{Strategy: FieldStrategyOverwrite}, true, true},
+		{"skip if set existing", &FieldOptions{Strategy: FieldStrategySkipIfSet}, true, false},
+		{"skip if set absent", &FieldOptions{Strategy: FieldStrategySkipIfSet}, false, true},
+		{"nil (merge) existing", nil, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := shouldSetMultiValueField(tt.strategy, tt.hasExistingValue); got != tt.want {
+				t.Errorf("shouldSetMultiValueField() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"io"
 	"strconv"
 
+	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/scraper"
 )
 
@@ -23,6 +24,12 @@ type Options struct {
 	SceneIDs []string `json:"sceneIDs"`
 	// paths of scenes to identify - ignored if scene ids are set
 	Paths []string `json:"paths"`
+	// scenes matching the filter are identified - ignored if scene ids or
+	// paths are set
+	SceneFilter *models.SceneFilterType `json:"sceneFilter"`
+	// Use every source that finds a match, in order, instead of only the
+	// first. Fields set by a source are not replaced by the sources after it.
+	CombineSources *bool `json:"combineSources"`
 }
 
 type MetadataOptions struct {
@@ -54,17 +61,22 @@ const (
 	//   For multi-value fields, any existing values are removed and replaced with the
 	//   scraped values.
 	FieldStrategyOverwrite FieldStrategy = "OVERWRITE"
+	// Only sets the value if the field is not already set.
+	//   For multi-value fields, values are only set if there are no existing
+	//   values.
+	FieldStrategySkipIfSet FieldStrategy = "SKIP_IF_SET"
 )
 
 var AllFieldStrategy = []FieldStrategy{
 	FieldStrategyIgnore,
 	FieldStrategyMerge,
 	FieldStrategyOverwrite,
+	FieldStrategySkipIfSet,
 }
 
 func (e FieldStrategy) IsValid() bool {
 	switch e {
-	case FieldStrategyIgnore, FieldStrategyMerge, FieldStrategyOverwrite:
+	case FieldStrategyIgnore, FieldStrategyMerge, FieldStrategyOverwrite, FieldStrategySkipIfSet:
 		return true
 	}
 	return false
@@ -0,0 +1,102 @@
+package identify
+
+import (
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+const (
+	FieldCoverImage  = "cover_image"
+	FieldExternalIDs = "external_ids"
+)
+
+// multiValueFields are the fields which values from several sources are
+// merged into.
+var multiValueFields = map[string]bool{
+	"performers": true,
+	"tags":       true,
+	"stash_ids":  true,
+}
+
+// updateInputFields maps the fields of the scene update input to the field
+// names used by the field options.
+var updateInputFields = map[string]string{
+	"studio_id":     "studio",
+	"performer_ids": "performers",
+	"tag_ids":       "tags",
+}
+
+// SceneReport records the fields of a scene changed by identify, and the
+// source that set each field.
+type SceneReport struct {
+	SceneID int    `json:"scene_id"`
+	Path    string `json:"path"`
+	// Sources are the names of the sources that found a match, in order
+	Sources []string      `json:"sources"`
+	Fields  []FieldChange `json:"fields"`
+}
+
+type FieldChange struct {
+	Field  string `json:"field"`
+	Source string `json:"source"`
+}
+
+// updatedFields returns the names of the fields set by input.
+func updatedFields(input models.SceneUpdateInput) []string {
+	var ret []string
+	for _, f := range utils.NotNilFields(input, "json") {
+		if f == "id" {
+			continue
+		}
+
+		if mapped, found := updateInputFields[f]; found {
+			f = mapped
+		}
+		ret = append(ret, f)
+	}
+
+	return ret
+}
+
+// withoutSetFields returns fieldOptions changed so that the fields in set are
+// not replaced. Values may still be added to multi-value fields.
+func withoutSetFields(fieldOptions map[string]*FieldOptions, set map[string]bool) map[string]*FieldOptions {
+	if len(set) == 0 {
+		return fieldOptions
+	}
+
+	ret := make(map[string]*FieldOptions, len(fieldOptions))
+	for k, v := range fieldOptions {
+		ret[k] = v
+	}
+
+	for field := range set {
+		o := ret[field]
+		strategy := getFieldStrategy(o)
+		if strategy == FieldStrategyIgnore {
+			continue
+		}
+
+		var createMissing *bool
+		if o != nil {
+			createMissing = o.CreateMissing
+		}
+
+		switch {
+		case multiValueFields[field]:
+			if strategy == FieldStrategyOverwrite {
+				strategy = FieldStrategyMerge
+			}
+		default:
+			strategy = FieldStrategyIgnore
+		}
+
+		ret[field] = &FieldOptions{
+			Field:         field,
+			Strategy:      strategy,
+			CreateMissing: createMissing,
+		}
+	}
+
+	return ret
+}
@@ -0,0 +1,105 @@
+package identify
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+func Test_updatedFields(t *testing.T) {
+	title := "title"
+	studioID := "1"
+
+	got := updatedFields(models.SceneUpdateInput{
+		ID:           "1",
+		Title:        &title,
+		StudioID:     &studioID,
+		PerformerIds: []string{"2"},
+	})
+
+	want := []string{"title", "studio", "performers"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("updatedFields() = %v, want %v", got, want)
+	}
+}
+
+func Test_withoutSetFields(t *testing.T) {
+	createMissing := true
+
+	fieldOptions := map[string]*FieldOptions{
+		"title": {
+			Field:    "title",
+			Strategy: FieldStrategyOverwrite,
+		},
+		"tags": {
+			Field:         "tags",
+			Strategy:      FieldStrategyOverwrite,
+			CreateMissing: &createMissing,
+		},
+		"performers": {
+			Field:    "performers",
+			Strategy: FieldStrategyIgnore,
+		},
+		"studio": {
+			Field:    "studio",
+			Strategy: FieldStrategyOverwrite,
+		},
+	}
+
+	tests := []struct {
+		name string
+		set  map[string]bool
+		want map[string]*FieldOptions
+	}{
+		{
+			"none set",
+			nil,
+			fieldOptions,
+		},
+		{
+			"fields set",
+			map[string]bool{
+				"title":      true,
+				"details":    true,
+				"tags":       true,
+				"performers": true,
+				"stash_ids":  true,
+			},
+			map[string]*FieldOptions{
+				"title": {
+					Field:    "title",
+					Strategy: FieldStrategyIgnore,
+				},
+				"details": {
+					Field:    "details",
+					Strategy: FieldStrategyIgnore,
+				},
+				"tags": {
+					Field:         "tags",
+					Strategy:      FieldStrategyMerge,
+					CreateMissing: &createMissing,
+				},
+				"performers": {
+					Field:    "performers",
+					Strategy: FieldStrategyIgnore,
+				},
+				"stash_ids": {
+					Field:    "stash_ids",
+					Strategy: FieldStrategyMerge,
+				},
+				"studio": {
+					Field:    "studio",
+					Strategy: FieldStrategyOverwrite,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := withoutSetFields(fieldOptions, tt.set); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("withoutSetFields() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -21,6 +21,8 @@ type SceneReaderUpdater interface {
 	models.PerformerIDLoader
 	models.TagIDLoader
 	models.StashIDLoader
+	models.ExternalIDLoader
+	models.ExternalIDUpdater
 }
 
 type TagCreator interface {
@@ -76,6 +78,10 @@ func (g sceneRelationships) performers(ctx context.Context, ignoreMale bool) ([]
 		return nil, nil
 	}
 
+	if !shouldSetMultiValueField(fieldStrategy, len(g.scene.PerformerIDs.List()) > 0) {
+		return nil, nil
+	}
+
 	createMissing := fieldStrategy != nil && utils.IsTrue(fieldStrategy.CreateMissing)
 	strategy := FieldStrategyMerge
 	if fieldStrategy != nil {
@@ -125,6 +131,10 @@ func (g sceneRelationships) tags(ctx context.Context) ([]int, error) {
 		return nil, nil
 	}
 
+	if !shouldSetMultiValueField(fieldStrategy, len(target.TagIDs.List()) > 0) {
+		return nil, nil
+	}
+
 	createMissing := fieldStrategy != nil && utils.IsTrue(fieldStrategy.CreateMissing)
 	strategy := FieldStrategyMerge
 	if fieldStrategy != nil {
@@ -183,6 +193,10 @@ func (g sceneRelationships) stashIDs(ctx context.Context) ([]models.StashID, err
 		return nil, nil
 	}
 
+	if !shouldSetMultiValueField(fieldStrategy, hasStashIDForEndpoint(target.StashIDs.List(), endpoint)) {
+		return nil, nil
+	}
+
 	strategy := FieldStrategyMerge
 	if fieldStrategy != nil {
 		strategy = fieldStrategy.Strategy
@@ -191,7 +205,8 @@ func (g sceneRelationships) stashIDs(ctx context.Context) ([]models.StashID, err
 	var stashIDs []models.StashID
 	originalStashIDs := target.StashIDs.List()
 
-	if strategy == FieldStrategyMerge {
+	// stash ids of other endpoints are kept when skipping if set
+	if strategy == FieldStrategyMerge || strategy == FieldStrategySkipIfSet {
 		// add to existing
 		// make a copy so we don't modify the original
 		stashIDs = append(stashIDs, originalStashIDs...)
@@ -224,6 +239,19 @@ func (g sceneRelationships) stashIDs(ctx context.Context) ([]models.StashID, err
 	return stashIDs, nil
 }
 
+// hasStashIDForEndpoint returns true if stashIDs includes a stash ID for the
+// stash-box endpoint. Stash IDs are only skipped if set for the same
+// stash-box, as each stash-box has its own ID for the scene.
+func hasStashIDForEndpoint(stashIDs []models.StashID, endpoint string) bool {
+	for _, s := range stashIDs {
+		if s.Endpoint == endpoint {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (g sceneRelationships) cover(ctx context.Context) ([]byte, error) {
 	scraped := g.result.result.Image
 
@@ -660,6 +660,37 @@ func Test_sceneRelationships_stashIDs(t *testing.T) {
 			nil,
 			false,
 		},
+		{
+			"skip if set existing",
+			sceneWithStashIDs,
+			&FieldOptions{
+				Strategy: FieldStrategySkipIfSet,
+			},
+			existingEndpoint,
+			&newRemoteSiteID,
+			nil,
+			false,
+		},
+		{
+			"skip if set add",
+			sceneWithStashIDs,
+			&FieldOptions{
+				Strategy: FieldStrategySkipIfSet,
+			},
+			newEndpoint,
+			&newRemoteSiteID,
+			[]models.StashID{
+				{
+					StashID:  remoteSiteID,
+					Endpoint: existingEndpoint,
+				},
+				{
+					StashID:  newRemoteSiteID,
+					Endpoint: newEndpoint,
+				},
+			},
+			false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
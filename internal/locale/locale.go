@@ -0,0 +1,315 @@
+// Package locale provides access to the UI locale catalog, including
+// user-supplied translation overrides, and negotiation of the language used
+// for server-generated content.
+package locale
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/logger"
+	"golang.org/x/text/language"
+)
+
+// DefaultLanguage is the language used for messages missing from the chosen
+// language. This matches the UI.
+const DefaultLanguage = "en-GB"
+
+const localeExt = ".json"
+
+var ErrUnknownLanguage = errors.New("unknown language")
+
+type Config interface {
+	// GetLocalesPath returns the directory containing the translation
+	// overrides.
+	GetLocalesPath() string
+}
+
+// Messages is a nested map of message keys to messages.
+type Messages map[string]interface{}
+
+// Catalog provides the messages of the UI locales. The messages of each
+// language are merged with the default language and any overrides in the
+// locales directory. Overrides are read on each access so that changes are
+// applied without restarting.
+type Catalog struct {
+	base   fs.FS
+	config Config
+
+	mutex sync.Mutex
+	// parsed base messages by language
+	cache map[string]Messages
+}
+
+// NewCatalog returns a new Catalog using the locale files in base.
+func NewCatalog(base fs.FS, config Config) *Catalog {
+	return &Catalog{
+		base:   base,
+		config: config,
+		cache:  make(map[string]Messages),
+	}
+}
+
+func languageFromFilename(fn string) (string, bool) {
+	if filepath.Ext(fn) != localeExt {
+		return "", false
+	}
+
+	lang := strings.TrimSuffix(fn, localeExt)
+	if _, err := language.Parse(lang); err != nil {
+		return "", false
+	}
+
+	return lang, true
+}
+
+// Languages returns the available languages, including languages which only
+// exist in the locales directory.
+func (c *Catalog) Languages() []string {
+	found := make(map[string]bool)
+
+	if entries, err := fs.ReadDir(c.base, "."); err == nil {
+		for _, e := range entries {
+			if lang, ok := languageFromFilename(e.Name()); ok {
+				found[lang] = true
+			}
+		}
+	}
+
+	if dir := c.config.GetLocalesPath(); dir != "" {
+		if entries, err := os.ReadDir(dir); err == nil {
+			for _, e := range entries {
+				if lang, ok := languageFromFilename(e.Name()); ok && !e.IsDir() {
+					found[lang] = true
+				}
+			}
+		}
+	}
+
+	ret := make([]string, 0, len(found))
+	for lang := range found {
+		ret = append(ret, lang)
+	}
+	sort.Strings(ret)
+
+	return ret
+}
+
+func (c *Catalog) hasLanguage(lang string) bool {
+	for _, l := range c.Languages() {
+		if l == lang {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *Catalog) baseMessages(lang string) (Messages, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if ret, found := c.cache[lang]; found {
+		return ret, nil
+	}
+
+	data, err := fs.ReadFile(c.base, lang+localeExt)
+	if errors.Is(err, fs.ErrNotExist) {
+		// language only exists in the overrides
+		return Messages{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var ret Messages
+	if err := json.Unmarshal(data, &ret); err != nil {
+		return nil, fmt.Errorf("parsing %s messages: %w", lang, err)
+	}
+
+	c.cache[lang] = ret
+	return ret, nil
+}
+
+func (c *Catalog) overrideMessages(lang string) Messages {
+	dir := c.config.GetLocalesPath()
+	if dir == "" {
+		return nil
+	}
+
+	fn := filepath.Join(dir, lang+localeExt)
+	if exists, _ := fsutil.FileExists(fn); !exists {
+		return nil
+	}
+
+	data, err := os.ReadFile(fn)
+	if err != nil {
+		logger.Warnf("error reading locale overrides %s: %v", fn, err)
+		return nil
+	}
+
+	var ret Messages
+	if err := json.Unmarshal(data, &ret); err != nil {
+		logger.Warnf("error parsing locale overrides %s: %v", fn, err)
+		return nil
+	}
+
+	return ret
+}
+
+// Messages returns the messages of the provided language, merged with the
+// messages of the default language and the overrides of both languages.
+// Returns ErrUnknownLanguage if the language is not available.
+func (c *Catalog) Messages(lang string) (Messages, error) {
+	if !c.hasLanguage(lang) {
+		return nil, ErrUnknownLanguage
+	}
+
+	langs := []string{DefaultLanguage}
+	if lang != DefaultLanguage {
+		langs = append(langs, lang)
+	}
+
+	ret := Messages{}
+	for _, l := range langs {
+		base, err := c.baseMessages(l)
+		if err != nil {
+			return nil, err
+		}
+
+		mergeMessages(ret, base)
+		mergeMessages(ret, c.overrideMessages(l))
+	}
+
+	return ret, nil
+}
+
+// mergeMessages deeply merges src into dest, ignoring empty messages. Nested
+// maps of src are copied rather than referenced.
+func mergeMessages(dest Messages, src map[string]interface{}) {
+	for k, v := range src {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			d, ok := dest[k].(map[string]interface{})
+			if !ok {
+				d = make(map[string]interface{})
+				dest[k] = d
+			}
+			mergeMessages(d, v)
+		case string:
+			if v != "" {
+				dest[k] = v
+			}
+		default:
+			dest[k] = v
+		}
+	}
+}
+
+// Negotiate returns the available language best matching the provided
+// Accept-Language header value. If the header is empty or does not match any
+// language, the best match of fallback is returned instead.
+func (c *Catalog) Negotiate(acceptLanguage string, fallback string) string {
+	langs := c.Languages()
+	if len(langs) == 0 {
+		return DefaultLanguage
+	}
+
+	tags := make([]language.Tag, 0, len(langs)+1)
+	// the first tag is used when nothing matches
+	tags = append(tags, language.Make(DefaultLanguage))
+	for _, l := range langs {
+		tags = append(tags, language.Make(l))
+	}
+	matcher := language.NewMatcher(tags)
+
+	match := func(s string) (string, bool) {
+		desired, _, err := language.ParseAcceptLanguage(s)
+		if err != nil || len(desired) == 0 {
+			return "", false
+		}
+
+		_, index, confidence := matcher.Match(desired...)
+		if confidence == language.No {
+			return "", false
+		}
+
+		if index == 0 {
+			return DefaultLanguage, true
+		}
+		return langs[index-1], true
+	}
+
+	if ret, ok := match(acceptLanguage); ok {
+		return ret
+	}
+
+	if ret, ok := match(fallback); ok {
+		return ret
+	}
+
+	return DefaultLanguage
+}
+
+// Translate returns the message with the provided dotted key in the provided
+// language, with {name} placeholders replaced by args. Returns the key if
+// the message is not found.
+func (c *Catalog) Translate(lang string, key string, args map[string]string) string {
+	messages, err := c.Messages(lang)
+	if err != nil {
+		messages, err = c.Messages(DefaultLanguage)
+		if err != nil {
+			return key
+		}
+	}
+
+	var v interface{} = map[string]interface{}(messages)
+	for _, part := range strings.Split(key, ".") {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return key
+		}
+		v = m[part]
+	}
+
+	ret, ok := v.(string)
+	if !ok {
+		return key
+	}
+
+	if len(args) > 0 {
+		pairs := make([]string, 0, len(args)*2)
+		for k, v := range args {
+			pairs = append(pairs, "{"+k+"}", v)
+		}
+		ret = strings.NewReplacer(pairs...).Replace(ret)
+	}
+
+	return ret
+}
+
+type contextKey struct{}
+
+// WithAcceptLanguage returns a context with the Accept-Language header value
+// of the request, used to negotiate the language of server-generated content.
+func WithAcceptLanguage(ctx context.Context, acceptLanguage string) context.Context {
+	return context.WithValue(ctx, contextKey{}, acceptLanguage)
+}
+
+// ContextLanguage returns the language negotiated from the Accept-Language
+// header value in the context. Contexts without a header value, such as
+// those of background tasks, use the best match of fallback, which should be
+// the configured language.
+func (c *Catalog) ContextLanguage(ctx context.Context, fallback string) string {
+	acceptLanguage, _ := ctx.Value(contextKey{}).(string)
+	return c.Negotiate(acceptLanguage, fallback)
+}
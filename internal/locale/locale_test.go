@@ -0,0 +1,130 @@
+package locale
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testConfig string
+
+func (c testConfig) GetLocalesPath() string {
+	return string(c)
+}
+
+func newTestCatalog(t *testing.T) *Catalog {
+	base := fstest.MapFS{
+		"en-GB.json": {Data: []byte(`{"actions":{"add":"Add","add_entity":"Add {entityType}","customise":"Customise"},"favourite":"Favourite"}`)},
+		"en-US.json": {Data: []byte(`{"actions":{"customise":"Customize"},"favourite":"Favorite"}`)},
+		"de-DE.json": {Data: []byte(`{"actions":{"add":"Hinzufügen","add_entity":""}}`)},
+		"index.ts":   {Data: []byte(``)},
+	}
+
+	dir := t.TempDir()
+	write := func(name string, data string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("de-DE.json", `{"favourite":"Favorit"}`)
+	write("en-GB.json", `{"actions":{"add":"Create"}}`)
+	write("ja-JP.json", `{"favourite":"お気に入り"}`)
+	write("invalid.json", `{}`)
+
+	return NewCatalog(base, testConfig(dir))
+}
+
+func TestCatalogLanguages(t *testing.T) {
+	c := newTestCatalog(t)
+	assert.Equal(t, []string{"de-DE", "en-GB", "en-US", "ja-JP"}, c.Languages())
+}
+
+func TestCatalogMessages(t *testing.T) {
+	c := newTestCatalog(t)
+
+	got, err := c.Messages("de-DE")
+	if err != nil {
+		t.Fatalf("Messages() error = %v", err)
+	}
+
+	// empty messages fall back to the default language
+	assert.Equal(t, Messages{
+		"actions": map[string]interface{}{
+			"add":        "Hinzufügen",
+			"add_entity": "Add {entityType}",
+			"customise":  "Customise",
+		},
+		"favourite": "Favorit",
+	}, got)
+
+	// base messages must not be modified by merging
+	got, err = c.Messages("en-US")
+	if err != nil {
+		t.Fatalf("Messages() error = %v", err)
+	}
+	assert.Equal(t, "Create", got["actions"].(map[string]interface{})["add"])
+	assert.Equal(t, "Favorite", got["favourite"])
+
+	_, err = c.Messages("xx-XX")
+	assert.ErrorIs(t, err, ErrUnknownLanguage)
+}
+
+func TestCatalogNegotiate(t *testing.T) {
+	c := newTestCatalog(t)
+
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		fallback       string
+		want           string
+	}{
+		{"exact", "de-DE", "en-US", "de-DE"},
+		{"base language", "de", "en-US", "de-DE"},
+		{"quality", "fr;q=0.9, ja;q=0.8, en;q=0.1", "en-US", "ja-JP"},
+		{"regional", "en-US,en;q=0.9", "de-DE", "en-US"},
+		{"no match", "fr-FR", "de-DE", "de-DE"},
+		{"empty", "", "en-US", "en-US"},
+		{"invalid", "!!", "", DefaultLanguage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, c.Negotiate(tt.acceptLanguage, tt.fallback))
+		})
+	}
+}
+
+func TestCatalogContextLanguage(t *testing.T) {
+	c := newTestCatalog(t)
+
+	assert.Equal(t, "en-US", c.ContextLanguage(context.Background(), "en-US"))
+	assert.Equal(t, "de-DE", c.ContextLanguage(WithAcceptLanguage(context.Background(), "de"), "en-US"))
+}
+
+func TestCatalogTranslate(t *testing.T) {
+	c := newTestCatalog(t)
+
+	tests := []struct {
+		lang string
+		key  string
+		args map[string]string
+		want string
+	}{
+		{"de-DE", "actions.add", nil, "Hinzufügen"},
+		{"de-DE", "actions.add_entity", map[string]string{"entityType": "Tag"}, "Add Tag"},
+		{"xx-XX", "favourite", nil, "Favourite"},
+		{"en-GB", "actions", nil, "actions"},
+		{"en-GB", "missing.key", nil, "missing.key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			assert.Equal(t, tt.want, c.Translate(tt.lang, tt.key, tt.args))
+		})
+	}
+}
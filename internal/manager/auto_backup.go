@@ -0,0 +1,153 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const (
+	autoBackupDatabaseContentType = "application/vnd.sqlite3"
+	autoBackupRowsContentType     = "application/json"
+)
+
+// AutoBackupRows returns the rows affected by a destructive operation. It is
+// called within a read transaction.
+type AutoBackupRows func(ctx context.Context) (interface{}, error)
+
+// autoBackupRows is the content of a backup of the affected rows.
+type autoBackupRows struct {
+	Operation string      `json:"operation"`
+	CreatedAt time.Time   `json:"created_at"`
+	Rows      interface{} `json:"rows"`
+}
+
+// AutoBackup backs up the data affected by a destructive operation before it
+// is run, storing the backup as a job artifact so that it is listed with the
+// job history. The database is backed up in full if it is no larger than the
+// configured maximum size, or if rows is nil. Otherwise, only the rows
+// returned by rows are backed up, as JSON.
+//
+// Returns nil if automatic backups are disabled.
+func (s *Manager) AutoBackup(ctx context.Context, operation string, rows AutoBackupRows) (*models.JobArtifact, error) {
+	if !s.Config.GetAutoBackup() {
+		return nil, nil
+	}
+
+	// operations run outside of jobs are listed with job id 0
+	info, _ := job.GetInfo(ctx)
+	info.Description = "Backup before " + operation
+
+	name := autoBackupName(operation, time.Now())
+
+	full, err := s.autoBackupFull(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret *models.JobArtifact
+	if full {
+		ret, err = s.addArtifact(ctx, info, name+".sqlite", autoBackupDatabaseContentType, s.writeDatabaseBackup)
+	} else {
+		var data autoBackupRows
+		data, err = s.getAutoBackupRows(ctx, operation, rows)
+		if err != nil {
+			return nil, err
+		}
+
+		ret, err = s.addArtifact(ctx, info, name+".json", autoBackupRowsContentType, func(w io.Writer) error {
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			return enc.Encode(data)
+		})
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("backing up before %s: %w", operation, err)
+	}
+
+	logger.Infof("Backed up before %s to job artifact %s", operation, ret.Name)
+	return ret, nil
+}
+
+func autoBackupName(operation string, t time.Time) string {
+	operation = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '-'
+	}, strings.ToLower(operation))
+
+	return "backup-" + operation + "-" + t.Format("20060102_150405")
+}
+
+// autoBackupFull returns true if the full database should be backed up.
+func (s *Manager) autoBackupFull(rows AutoBackupRows) (bool, error) {
+	if rows == nil {
+		return true, nil
+	}
+
+	stat, err := os.Stat(s.Database.DatabasePath())
+	if err != nil {
+		return false, fmt.Errorf("getting database size: %w", err)
+	}
+
+	maxSize := int64(s.Config.GetAutoBackupMaxDatabaseSize()) * 1024 * 1024
+	return stat.Size() <= maxSize, nil
+}
+
+func (s *Manager) getAutoBackupRows(ctx context.Context, operation string, rows AutoBackupRows) (autoBackupRows, error) {
+	ret := autoBackupRows{
+		Operation: operation,
+		CreatedAt: time.Now(),
+	}
+
+	if err := s.Repository.WithReadTxn(ctx, func(ctx context.Context) error {
+		var err error
+		ret.Rows, err = rows(ctx)
+		return err
+	}); err != nil {
+		return ret, fmt.Errorf("getting rows affected by %s: %w", operation, err)
+	}
+
+	return ret, nil
+}
+
+// writeDatabaseBackup backs up the database to a temporary file, then copies
+// it to w.
+func (s *Manager) writeDatabaseBackup(w io.Writer) error {
+	f, err := os.CreateTemp(s.Paths.Generated.Artifacts, "backup*.sqlite")
+	if err != nil {
+		return err
+	}
+	tmpPath := f.Name()
+	f.Close()
+
+	// the backup cannot be written to an existing file
+	if err := os.Remove(tmpPath); err != nil {
+		return err
+	}
+	defer os.Remove(tmpPath)
+
+	if err := s.Database.Backup(tmpPath); err != nil {
+		return err
+	}
+
+	backup, err := os.Open(tmpPath)
+	if err != nil {
+		return err
+	}
+	defer backup.Close()
+
+	_, err = io.Copy(w, backup)
+	return err
+}
@@ -0,0 +1,27 @@
+package manager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAutoBackupName(t *testing.T) {
+	now := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		operation string
+		want      string
+	}{
+		{"import", "backup-import-20220102_030405"},
+		{"destroying scenes", "backup-destroying-scenes-20220102_030405"},
+		{"Merging Tags/../x", "backup-merging-tags----x-20220102_030405"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.operation, func(t *testing.T) {
+			assert.Equal(t, tt.want, autoBackupName(tt.operation, now))
+		})
+	}
+}
@@ -1,12 +1,14 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
 	"runtime"
 	"strings"
+	"time"
 
 	"sync"
 	// "github.com/sasha-s/go-deadlock" // if you have deadlock issues
@@ -16,11 +18,15 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/stashapp/stash/internal/identify"
+	"github.com/stashapp/stash/pkg/ffmpeg"
 	"github.com/stashapp/stash/pkg/fsutil"
 	"github.com/stashapp/stash/pkg/hash"
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/models/paths"
+	"github.com/stashapp/stash/pkg/scraper"
+	"github.com/stashapp/stash/pkg/session"
+	"github.com/stashapp/stash/pkg/webhook"
 )
 
 var officialBuild string
@@ -37,6 +43,12 @@ const (
 	Password            = "password"
 	MaxSessionAge       = "max_session_age"
 
+	// OpenID Connect providers users can log in with
+	OIDCProviders = "oidc_providers"
+
+	// Webhooks called on library events
+	Webhooks = "webhooks"
+
 	DefaultMaxSessionAge = 60 * 60 * 1 // 1 hours
 
 	Database = "database"
@@ -60,6 +72,17 @@ const (
 	MaxTranscodeSize          = "max_transcode_size"
 	MaxStreamingTranscodeSize = "max_streaming_transcode_size"
 
+	// TranscodeProfiles is the config key for the named transcode profiles.
+	// StreamTranscodeProfiles and PreviewTranscodeProfile select the profiles
+	// used for live transcoding and preview generation.
+	TranscodeProfiles       = "transcode_profiles"
+	StreamTranscodeProfiles = "stream_transcode_profiles"
+	PreviewTranscodeProfile = "preview_transcode_profile"
+
+	// HLSVariants is the config key for the bitrate ladder of adaptive
+	// bitrate HLS streams.
+	HLSVariants = "hls_variants"
+
 	ParallelTasks        = "parallel_tasks"
 	parallelTasksDefault = 1
 
@@ -103,6 +126,8 @@ const (
 	ScraperCertCheck          = "scraper_cert_check"
 	ScraperCDPPath            = "scraper_cdp_path"
 	ScraperExcludeTagPatterns = "scraper_exclude_tag_patterns"
+	ScraperRateLimits         = "scraper_rate_limits"
+	ScraperCacheDays          = "scraper_cache_days"
 
 	// stash-box options
 	StashBoxes = "stash_boxes"
@@ -112,9 +137,16 @@ const (
 	// plugin options
 	PluginsPath = "plugins_path"
 
+	// theme options
+	ThemesPath  = "themes_path"
+	ActiveTheme = "active_theme"
+
 	// i18n
 	Language = "language"
 
+	// directory containing translation overrides
+	LocalesPath = "locales_path"
+
 	// served directories
 	// this should be manually configured only
 	CustomServedFolders = "custom_served_folders"
@@ -212,6 +244,117 @@ const (
 
 	// File upload options
 	MaxUploadSize = "max_upload_size"
+
+	// Library watch options
+	WatchLibraryEnabled         = "watch_library.enabled"
+	WatchLibraryDebounce        = "watch_library.debounce"
+	watchLibraryDebounceDefault = 10
+
+	// Scheduled task options
+	ScheduledTasks = "scheduled_tasks"
+
+	ScrapeRefreshRules = "scrape_refresh_rules"
+
+	// Resume generate jobs interrupted by a restart
+	ResumeInterruptedJobs = "resume_interrupted_jobs"
+
+	// Download client hook options
+	DownloadHookEnabled = "download_hook.enabled"
+	DownloadHookAutoTag = "download_hook.auto_tag"
+
+	// Directory that files uploaded over HTTP are moved to
+	UploadInboxPath = "upload.inbox_path"
+
+	// Add scanned scenes to the inbox for review
+	InboxEnabled = "inbox.enabled"
+
+	// Session lock options. The timeout is in minutes of inactivity.
+	SessionLockPin     = "session_lock.pin"
+	SessionLockTimeout = "session_lock.timeout"
+
+	// Tags of the content shown while quick hide is enabled
+	QuickHideSafeTags = "quick_hide.safe_tags"
+
+	// Activity log options
+	ActivityLogEnabled       = "activity_log.enabled"
+	ActivityLogRetentionDays = "activity_log.retention_days"
+
+	// Job artifact options
+	JobArtifactRetentionDays        = "job_artifacts.retention_days"
+	jobArtifactRetentionDaysDefault = 14
+
+	// Trash options
+	TrashPath                 = "trash.path"
+	TrashRetentionDays        = "trash.retention_days"
+	trashRetentionDaysDefault = 30
+
+	// Holding directory options for deleted files
+	DeletedFilesPath              = "deleted_files.path"
+	DeletedFilesPreserveStructure = "deleted_files.preserve_structure"
+	DeletedFilesMaxSize           = "deleted_files.max_size"
+
+	// Number of days to cache the results of stash-box fingerprint queries
+	StashBoxFingerprintCacheDays        = "stash_box_fingerprint_cache_days"
+	stashBoxFingerprintCacheDaysDefault = 7
+
+	// Template of the paths of renamed files, relative to their library path
+	RenameTemplate        = "rename_template"
+	renameTemplateDefault = "{studio}/{date} - {title} [{resolution}]"
+
+	// Media access options
+	MediaAllowedSubnets = "media_access.allowed_subnets"
+	MediaAccessToken    = "media_access.token"
+
+	// Render a band for each secondary axis of multi-axis funscripts in
+	// interactive heatmaps
+	InteractiveHeatmapRenderAxes = "interactive_heatmap_render_axes"
+
+	// Render the interactive heatmap below each thumbnail of the scrubber
+	// sprite of interactive scenes
+	InteractiveHeatmapSprite = "interactive_heatmap_sprite"
+
+	// Interactive heatmap appearance options
+	InteractiveHeatmapWidth                  = "interactive_heatmap_width"
+	interactiveHeatmapWidthDefault           = 320
+	InteractiveHeatmapHeight                 = "interactive_heatmap_height"
+	interactiveHeatmapHeightDefault          = 15
+	InteractiveHeatmapSegments               = "interactive_heatmap_segments"
+	interactiveHeatmapSegmentsDefault        = 150
+	InteractiveHeatmapColormap               = "interactive_heatmap_colormap"
+	interactiveHeatmapColormapDefault        = "default"
+	InteractiveHeatmapColormapStops          = "interactive_heatmap_colormap_stops"
+	InteractiveHeatmapBackgroundColor        = "interactive_heatmap_background_color"
+	interactiveHeatmapBackgroundColorDefault = "#30404d"
+
+	// Number of minutes that destructive bulk operations may be undone for
+	BulkUndoWindow        = "bulk_undo_window"
+	bulkUndoWindowDefault = 10
+
+	// Back up the data affected by destructive bulk operations before they
+	// are run
+	AutoBackup        = "auto_backup"
+	autoBackupDefault = true
+
+	// Maximum size in MiB of a database which is backed up in full before
+	// destructive bulk operations
+	AutoBackupMaxDatabaseSize        = "auto_backup_max_database_size"
+	autoBackupMaxDatabaseSizeDefault = 256
+
+	// Constrain memory use for devices with little RAM
+	LowMemoryMode        = "low_memory_mode"
+	lowMemoryModeDefault = false
+
+	// Primary tag of markers generated from high intensity sections of
+	// interactive scenes
+	InteractiveMarkerTag        = "interactive_marker_tag"
+	interactiveMarkerTagDefault = "High Intensity"
+
+	// Import the chapters embedded in video files as scene markers
+	ImportChapters = "import_chapters"
+
+	// Primary tag of markers imported from chapters
+	ChapterMarkerTag        = "chapter_marker_tag"
+	chapterMarkerTagDefault = "Chapter"
 )
 
 // slice default values
@@ -343,6 +486,15 @@ func (i *Instance) SetPassword(value string) {
 	}
 }
 
+func (i *Instance) SetSessionLockPin(value string) {
+	// if blank, don't bother hashing; we want it to be blank
+	if value == "" {
+		i.Set(SessionLockPin, "")
+	} else {
+		i.Set(SessionLockPin, hashPassword(value))
+	}
+}
+
 func (i *Instance) Write() error {
 	i.Lock()
 	defer i.Unlock()
@@ -478,16 +630,113 @@ func (i *Instance) getStringMapString(key string) map[string]string {
 }
 
 type StashConfig struct {
-	Path         string `json:"path"`
-	ExcludeVideo bool   `json:"excludeVideo"`
-	ExcludeImage bool   `json:"excludeImage"`
+	Path         string                `json:"path"`
+	ExcludeVideo bool                  `json:"excludeVideo"`
+	ExcludeImage bool                  `json:"excludeImage"`
+	Retention    *StashRetentionConfig `json:"retention"`
+	Patterns     []*StashPathPattern   `json:"patterns"`
 }
 
 // Stash configuration details
 type StashConfigInput struct {
-	Path         string `json:"path"`
-	ExcludeVideo bool   `json:"excludeVideo"`
-	ExcludeImage bool   `json:"excludeImage"`
+	Path         string                `json:"path"`
+	ExcludeVideo bool                  `json:"excludeVideo"`
+	ExcludeImage bool                  `json:"excludeImage"`
+	Retention    *StashRetentionConfig `json:"retention"`
+	Patterns     []*StashPathPattern   `json:"patterns"`
+}
+
+// StashPathPattern is a scan include or exclude pattern for a stash library
+// path. Patterns are applied in addition to the global exclusion patterns.
+type StashPathPattern struct {
+	// Glob matched against the path relative to the stash path, or a regular
+	// expression matched against the full path.
+	Pattern string `json:"pattern"`
+	// Pattern is a regular expression rather than a glob.
+	Regex bool `json:"regex"`
+	// Only files matching an include pattern are scanned. Matching files are
+	// excluded otherwise.
+	Include bool `json:"include"`
+	// Pattern applies to video files.
+	Video bool `json:"video"`
+	// Pattern applies to image and gallery files.
+	Image bool `json:"image"`
+}
+
+// Compile returns the regular expression for the pattern. Patterns are
+// matched case-insensitively.
+func (p *StashPathPattern) Compile() (*regexp.Regexp, error) {
+	pattern := p.Pattern
+	if !p.Regex {
+		pattern = globToRegexp(pattern)
+	}
+
+	if !strings.HasPrefix(pattern, "(?i)") {
+		pattern = "(?i)" + pattern
+	}
+
+	return regexp.Compile(pattern)
+}
+
+// globToRegexp converts a glob to a regular expression. * matches within a
+// path component, ** matches across components and ? matches a single
+// character. Globs without a slash match the name of any path component.
+func globToRegexp(glob string) string {
+	var sb strings.Builder
+
+	if strings.Contains(glob, "/") {
+		sb.WriteString("^")
+	} else {
+		sb.WriteString("(^|/)")
+	}
+
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch c {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				sb.WriteString(".*")
+				i++
+			} else {
+				sb.WriteString("[^/]*")
+			}
+		case '?':
+			sb.WriteString("[^/]")
+		case '[':
+			if end := strings.IndexByte(glob[i+1:], ']'); end > 0 {
+				class := glob[i+1 : i+1+end]
+				if strings.HasPrefix(class, "!") {
+					class = "^" + class[1:]
+				}
+				sb.WriteString("[" + class + "]")
+				i += end + 1
+			} else {
+				sb.WriteString(regexp.QuoteMeta(string(c)))
+			}
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	sb.WriteString("(/|$)")
+	return sb.String()
+}
+
+// StashRetentionConfig contains the retention rules for a stash library path.
+// Scenes in the path which violate the rules are deleted by the retention task.
+type StashRetentionConfig struct {
+	// Maximum total size of the scene files in the path, in bytes. Zero for no limit.
+	MaxSize int64 `json:"maxSize"`
+	// Delete watched scenes that were last played more than this many days ago.
+	// Zero to disable.
+	WatchedOlderThanDays int `json:"watchedOlderThanDays"`
+	// If set, only scenes with this tag are eligible for deletion.
+	TagID *string `json:"tagId"`
+}
+
+// IsSet returns true if any retention rule is configured.
+func (c *StashRetentionConfig) IsSet() bool {
+	return c != nil && (c.MaxSize > 0 || c.WatchedOlderThanDays > 0)
 }
 
 // GetStathPaths returns the configured stash library paths.
@@ -653,6 +902,23 @@ func (i *Instance) GetScraperExcludeTagPatterns() []string {
 	return i.getStringSlice(ScraperExcludeTagPatterns)
 }
 
+// GetScraperRateLimits returns the per-domain limits of the requests made by
+// scrapers.
+func (i *Instance) GetScraperRateLimits() []*scraper.RateLimit {
+	var ret []*scraper.RateLimit
+	if err := i.unmarshalKey(ScraperRateLimits, &ret); err != nil {
+		logger.Warnf("error in unmarshalkey: %v", err)
+	}
+
+	return ret
+}
+
+// GetScraperCacheDays returns the number of days that the results of
+// scrapes are cached for. Results are not cached if zero.
+func (i *Instance) GetScraperCacheDays() int {
+	return i.getInt(ScraperCacheDays)
+}
+
 func (i *Instance) GetStashBoxes() []*models.StashBox {
 	var boxes []*models.StashBox
 	if err := i.unmarshalKey(StashBoxes, &boxes); err != nil {
@@ -673,6 +939,24 @@ func (i *Instance) GetPluginsPath() string {
 	return i.getString(PluginsPath)
 }
 
+func (i *Instance) GetDefaultThemesPath() string {
+	// default to the same directory as the config file
+	fn := filepath.Join(i.GetConfigPath(), "themes")
+
+	return fn
+}
+
+// GetThemesPath returns the directory containing the theme packs.
+func (i *Instance) GetThemesPath() string {
+	return i.getString(ThemesPath)
+}
+
+// GetActiveTheme returns the id of the active theme pack. Returns an empty
+// string if no theme is active.
+func (i *Instance) GetActiveTheme() string {
+	return i.getString(ActiveTheme)
+}
+
 func (i *Instance) GetPythonPath() string {
 	return i.getString(PythonPath)
 }
@@ -715,7 +999,14 @@ func (i *Instance) GetParallelTasks() int {
 	return i.getInt(ParallelTasks)
 }
 
+// GetParallelTasksWithAutoDetection returns the number of parallel tasks,
+// detected from the number of CPUs if not set. Tasks are not run in parallel
+// in low memory mode.
 func (i *Instance) GetParallelTasksWithAutoDetection() int {
+	if i.GetLowMemoryMode() {
+		return 1
+	}
+
 	parallelTasks := i.getInt(ParallelTasks)
 	if parallelTasks <= 0 {
 		parallelTasks = (runtime.NumCPU() / 4) + 1
@@ -786,6 +1077,128 @@ func (i *Instance) GetMaxStreamingTranscodeSize() models.StreamingResolutionEnum
 	return models.StreamingResolutionEnum(ret)
 }
 
+func (i *Instance) GetTranscodeProfiles() []*ffmpeg.TranscodeProfile {
+	var ret []*ffmpeg.TranscodeProfile
+	if err := i.unmarshalKey(TranscodeProfiles, &ret); err != nil {
+		logger.Warnf("error in unmarshalkey: %v", err)
+	}
+
+	return ret
+}
+
+// GetTranscodeProfile returns the transcode profile with the given name.
+// Returns nil if name is empty or no such profile exists.
+func (i *Instance) GetTranscodeProfile(name string) *ffmpeg.TranscodeProfile {
+	if name == "" {
+		return nil
+	}
+
+	for _, p := range i.GetTranscodeProfiles() {
+		if p.Name == name {
+			return p
+		}
+	}
+
+	return nil
+}
+
+func (i *Instance) GetStreamTranscodeProfiles() []*StreamTranscodeProfile {
+	var ret []*StreamTranscodeProfile
+	if err := i.unmarshalKey(StreamTranscodeProfiles, &ret); err != nil {
+		logger.Warnf("error in unmarshalkey: %v", err)
+	}
+
+	return ret
+}
+
+// GetStreamTranscodeProfile returns the transcode profile selected for live
+// transcoding to the given resolution and container. Returns nil if no
+// profile is selected, in which case the built-in stream format is used.
+func (i *Instance) GetStreamTranscodeProfile(resolution models.StreamingResolutionEnum, container ffmpeg.TranscodeContainer) *ffmpeg.TranscodeProfile {
+	if container == "" {
+		return nil
+	}
+
+	for _, s := range i.GetStreamTranscodeProfiles() {
+		if s.Resolution != resolution {
+			continue
+		}
+
+		if p := i.GetTranscodeProfile(s.Profile); p != nil && p.Container == container {
+			return p
+		}
+	}
+
+	return nil
+}
+
+// GetPreviewTranscodeProfile returns the name of the transcode profile used
+// to generate preview videos. An empty string means the built-in libx264
+// arguments are used.
+func (i *Instance) GetPreviewTranscodeProfile() string {
+	return i.getString(PreviewTranscodeProfile)
+}
+
+// HLSVariant is a variant of the adaptive bitrate HLS bitrate ladder.
+type HLSVariant struct {
+	Resolution models.StreamingResolutionEnum `json:"resolution"`
+	// Video bitrate in kilobits per second
+	Bitrate int `json:"bitrate"`
+}
+
+var defaultHLSVariants = []*HLSVariant{
+	{Resolution: models.StreamingResolutionEnumLow, Bitrate: 400},
+	{Resolution: models.StreamingResolutionEnumStandard, Bitrate: 1000},
+	{Resolution: models.StreamingResolutionEnumStandardHd, Bitrate: 2500},
+	{Resolution: models.StreamingResolutionEnumFullHd, Bitrate: 5000},
+}
+
+// GetHLSVariants returns the bitrate ladder of adaptive bitrate HLS
+// streams. Defaults to 240p, 480p, 720p and 1080p variants.
+func (i *Instance) GetHLSVariants() []*HLSVariant {
+	i.RLock()
+	defer i.RUnlock()
+
+	var ret []*HLSVariant
+	v := i.viper(HLSVariants)
+	if !v.IsSet(HLSVariants) {
+		return defaultHLSVariants
+	}
+
+	if err := v.UnmarshalKey(HLSVariants, &ret); err != nil {
+		logger.Warnf("error in unmarshalkey: %v", err)
+		return defaultHLSVariants
+	}
+
+	return ret
+}
+
+// ValidateHLSVariants returns an error if the bitrate ladder is empty, or
+// has an invalid or duplicate resolution or a non-positive bitrate.
+func (i *Instance) ValidateHLSVariants(variants []*HLSVariant) error {
+	if len(variants) == 0 {
+		return errors.New("at least one HLS variant is required")
+	}
+
+	seen := make(map[models.StreamingResolutionEnum]bool)
+	for _, v := range variants {
+		if !v.Resolution.IsValid() {
+			return fmt.Errorf("invalid HLS variant resolution %q", v.Resolution)
+		}
+
+		if seen[v.Resolution] {
+			return fmt.Errorf("duplicate HLS variant resolution %s", v.Resolution)
+		}
+		seen[v.Resolution] = true
+
+		if v.Bitrate <= 0 {
+			return fmt.Errorf("HLS variant %s bitrate must be positive", v.Resolution)
+		}
+	}
+
+	return nil
+}
+
 // IsWriteImageThumbnails returns true if image thumbnails should be written
 // to disk after generating on the fly.
 func (i *Instance) IsWriteImageThumbnails() bool {
@@ -844,6 +1257,9 @@ type StashBoxInput struct {
 	Endpoint string `json:"endpoint"`
 	APIKey   string `json:"api_key"`
 	Name     string `json:"name"`
+	// Maximum number of requests made to the endpoint per minute. 0 for no
+	// limit
+	MaxRequestsPerMinute int `json:"max_requests_per_minute"`
 }
 
 func (i *Instance) ValidateStashBoxes(boxes []*StashBoxInput) error {
@@ -866,6 +1282,58 @@ func (i *Instance) ValidateStashBoxes(boxes []*StashBoxInput) error {
 		if isMulti && box.Name == "" {
 			return &StashBoxError{msg: "name cannot be blank"}
 		}
+
+		if box.MaxRequestsPerMinute < 0 {
+			return &StashBoxError{msg: "max requests per minute must not be negative"}
+		}
+	}
+
+	return nil
+}
+
+// StreamTranscodeProfile selects the transcode profile used when live
+// transcoding to a streaming resolution.
+type StreamTranscodeProfile struct {
+	Resolution models.StreamingResolutionEnum `json:"resolution"`
+	Profile    string                         `json:"profile"`
+}
+
+// ValidateTranscodeProfiles returns an error if a profile is invalid or
+// has a duplicate name, or if a selected profile does not exist or cannot be
+// used where it is selected.
+func (i *Instance) ValidateTranscodeProfiles(profiles []*ffmpeg.TranscodeProfile, streamProfiles []*StreamTranscodeProfile, previewProfile string) error {
+	byName := make(map[string]*ffmpeg.TranscodeProfile)
+	for _, p := range profiles {
+		if err := p.Validate(); err != nil {
+			return fmt.Errorf("invalid transcode profile: %w", err)
+		}
+
+		if byName[p.Name] != nil {
+			return fmt.Errorf("duplicate transcode profile name %q", p.Name)
+		}
+		byName[p.Name] = p
+	}
+
+	for _, s := range streamProfiles {
+		if !s.Resolution.IsValid() {
+			return fmt.Errorf("invalid streaming resolution %q", s.Resolution)
+		}
+
+		if byName[s.Profile] == nil {
+			return fmt.Errorf("transcode profile %q not found", s.Profile)
+		}
+	}
+
+	if previewProfile != "" {
+		p := byName[previewProfile]
+		if p == nil {
+			return fmt.Errorf("transcode profile %q not found", previewProfile)
+		}
+
+		// previews are always generated as mp4
+		if p.Container != ffmpeg.TranscodeContainerMP4 {
+			return fmt.Errorf("preview transcode profile %q must use the %s container", previewProfile, ffmpeg.TranscodeContainerMP4)
+		}
 	}
 
 	return nil
@@ -886,6 +1354,27 @@ func (i *Instance) GetMaxSessionAge() int {
 	return ret
 }
 
+// GetOIDCProviders returns the OpenID Connect providers users can log in
+// with.
+func (i *Instance) GetOIDCProviders() []*session.OIDCConfig {
+	var ret []*session.OIDCConfig
+	if err := i.unmarshalKey(OIDCProviders, &ret); err != nil {
+		logger.Warnf("error in unmarshalkey: %v", err)
+	}
+
+	return ret
+}
+
+// GetWebhooks returns the webhooks called on library events.
+func (i *Instance) GetWebhooks() []*webhook.Config {
+	var ret []*webhook.Config
+	if err := i.unmarshalKey(Webhooks, &ret); err != nil {
+		logger.Warnf("error in unmarshalkey: %v", err)
+	}
+
+	return ret
+}
+
 // GetCustomServedFolders gets the map of custom paths to their applicable
 // filesystem locations
 func (i *Instance) GetCustomServedFolders() URLMap {
@@ -1168,6 +1657,20 @@ func (i *Instance) SetCustomLocales(customLocales string) {
 	}
 }
 
+func (i *Instance) GetDefaultLocalesPath() string {
+	// default to the same directory as the config file
+	fn := filepath.Join(i.GetConfigPath(), "locales")
+
+	return fn
+}
+
+// GetLocalesPath returns the directory containing translation overrides.
+// Files in the directory are named after the language, for example
+// en-GB.json.
+func (i *Instance) GetLocalesPath() string {
+	return i.getString(LocalesPath)
+}
+
 func (i *Instance) GetCustomLocalesEnabled() bool {
 	return i.getBool(CustomLocalesEnabled)
 }
@@ -1343,6 +1846,291 @@ func (i *Instance) GetMaxUploadSize() int64 {
 	return ret << 20
 }
 
+// GetWatchLibraryEnabled returns true if the stash paths are watched for
+// changes, which are scanned as they happen.
+func (i *Instance) GetWatchLibraryEnabled() bool {
+	return i.getBool(WatchLibraryEnabled)
+}
+
+// GetWatchLibraryDebounce returns the number of seconds without further
+// changes to wait before scanning changed files.
+func (i *Instance) GetWatchLibraryDebounce() int {
+	return i.getInt(WatchLibraryDebounce)
+}
+
+// GetScheduledTasks returns the tasks which are queued automatically
+// according to their schedule.
+func (i *Instance) GetScheduledTasks() []*ScheduledTask {
+	var ret []*ScheduledTask
+	if err := i.unmarshalKey(ScheduledTasks, &ret); err != nil {
+		logger.Warnf("error in unmarshalkey: %v", err)
+	}
+
+	return ret
+}
+
+// GetScrapeRefreshRules returns the rules which re-scrape performers and
+// studios according to their schedule.
+func (i *Instance) GetScrapeRefreshRules() []*ScrapeRefreshRule {
+	var ret []*ScrapeRefreshRule
+	if err := i.unmarshalKey(ScrapeRefreshRules, &ret); err != nil {
+		logger.Warnf("error in unmarshalkey: %v", err)
+	}
+
+	return ret
+}
+
+// GetScrapeRefreshRule returns the scrape refresh rule with the name, or nil
+// if there is none.
+func (i *Instance) GetScrapeRefreshRule(name string) *ScrapeRefreshRule {
+	for _, r := range i.GetScrapeRefreshRules() {
+		if r.Name == name {
+			return r
+		}
+	}
+
+	return nil
+}
+
+// GetResumeInterruptedJobs returns true if generate jobs which were
+// interrupted by a restart are resumed from their checkpoint at startup.
+func (i *Instance) GetResumeInterruptedJobs() bool {
+	return i.getBool(ResumeInterruptedJobs)
+}
+
+// GetDownloadHookEnabled returns true if the completed download hook endpoint
+// accepts requests.
+func (i *Instance) GetDownloadHookEnabled() bool {
+	return i.getBool(DownloadHookEnabled)
+}
+
+// GetDownloadHookAutoTag returns true if completed downloads should be
+// auto-tagged after they have been scanned.
+func (i *Instance) GetDownloadHookAutoTag() bool {
+	return i.getBool(DownloadHookAutoTag)
+}
+
+// GetUploadInboxPath returns the directory that files uploaded to the upload
+// endpoint are moved to once complete. Uploads are disabled if empty.
+func (i *Instance) GetUploadInboxPath() string {
+	return i.getString(UploadInboxPath)
+}
+
+// GetInboxEnabled returns true if new scenes added by a scan are placed in
+// the inbox, to be approved or rejected.
+func (i *Instance) GetInboxEnabled() bool {
+	return i.getBool(InboxEnabled)
+}
+
+func (i *Instance) GetSessionLockPinHash() string {
+	return i.getString(SessionLockPin)
+}
+
+// IsSessionLockEnabled returns true if sessions can be locked. Locked
+// sessions are unlocked with the session lock PIN or, if no PIN is set, the
+// password, so one of these must be set.
+func (i *Instance) IsSessionLockEnabled() bool {
+	return i.GetSessionLockPinHash() != "" || i.HasCredentials()
+}
+
+// GetSessionLockTimeout returns the period of inactivity after which sessions
+// are locked. Sessions are not locked automatically if zero.
+func (i *Instance) GetSessionLockTimeout() time.Duration {
+	return time.Duration(i.getInt(SessionLockTimeout)) * time.Minute
+}
+
+// ValidateSessionLockPin returns true if the provided value unlocks a locked
+// session. This is the session lock PIN if set, otherwise the password.
+func (i *Instance) ValidateSessionLockPin(pin string) bool {
+	if !i.IsSessionLockEnabled() {
+		return false
+	}
+
+	pinHash := i.GetSessionLockPinHash()
+	if pinHash == "" {
+		return i.ValidateCredentials(i.GetUsername(), pin)
+	}
+
+	return bcrypt.CompareHashAndPassword([]byte(pinHash), []byte(pin)) == nil
+}
+
+// GetQuickHideSafeTags returns the ids of the tags of the content that
+// remains visible while quick hide is enabled.
+func (i *Instance) GetQuickHideSafeTags() []int {
+	i.RLock()
+	defer i.RUnlock()
+
+	return i.viper(QuickHideSafeTags).GetIntSlice(QuickHideSafeTags)
+}
+
+// GetActivityLogEnabled returns true if mutations and streams should be
+// recorded in the activity log.
+func (i *Instance) GetActivityLogEnabled() bool {
+	return i.getBool(ActivityLogEnabled)
+}
+
+// GetActivityLogRetentionDays returns the number of days that activity log
+// entries are kept for. Zero means that entries are kept forever.
+func (i *Instance) GetActivityLogRetentionDays() int {
+	return i.getInt(ActivityLogRetentionDays)
+}
+
+// GetJobArtifactRetentionDays returns the number of days that job artifacts
+// are kept for. Zero means that artifacts are kept until deleted.
+func (i *Instance) GetJobArtifactRetentionDays() int {
+	return i.getInt(JobArtifactRetentionDays)
+}
+
+// GetTrashPath returns the directory that the files of deleted scenes are
+// moved to. Files are deleted permanently if empty.
+func (i *Instance) GetTrashPath() string {
+	return i.getString(TrashPath)
+}
+
+// GetTrashRetentionDays returns the number of days that deleted scenes are
+// kept in the trash for. Zero means that they are kept until purged. Expired
+// scenes are deleted by the purge trash scheduled task.
+func (i *Instance) GetTrashRetentionDays() int {
+	return i.getInt(TrashRetentionDays)
+}
+
+// GetDeletedFilesPath returns the holding directory that files deleted from
+// the library are moved to. Files are deleted permanently if empty.
+func (i *Instance) GetDeletedFilesPath() string {
+	return i.getString(DeletedFilesPath)
+}
+
+// GetDeletedFilesPreserveStructure returns true if deleted files are moved to
+// their original path relative to the holding directory.
+func (i *Instance) GetDeletedFilesPreserveStructure() bool {
+	return i.getBool(DeletedFilesPreserveStructure)
+}
+
+// GetDeletedFilesMaxSize returns the maximum total size in MiB of the files
+// in the holding directory. Zero means that the size is unlimited.
+func (i *Instance) GetDeletedFilesMaxSize() int {
+	return i.getInt(DeletedFilesMaxSize)
+}
+
+// GetStashBoxFingerprintCacheDays returns the number of days that the results
+// of stash-box fingerprint queries are cached for. Zero means that results
+// are not cached.
+func (i *Instance) GetStashBoxFingerprintCacheDays() int {
+	return i.getInt(StashBoxFingerprintCacheDays)
+}
+
+// GetRenameTemplate returns the template used to rename files, relative to
+// the library path of each file and without the extension.
+func (i *Instance) GetRenameTemplate() string {
+	return i.getString(RenameTemplate)
+}
+
+// GetMediaAllowedSubnets returns the subnets that may access streaming and
+// download endpoints without providing the media access token.
+func (i *Instance) GetMediaAllowedSubnets() []string {
+	return i.getStringSlice(MediaAllowedSubnets)
+}
+
+// GetMediaAccessToken returns the token required to access streaming and
+// download endpoints from outside the allowed subnets.
+func (i *Instance) GetMediaAccessToken() string {
+	return i.getString(MediaAccessToken)
+}
+
+// GetInteractiveHeatmapRenderAxes returns true if interactive heatmaps should
+// include a band for each secondary axis of multi-axis funscripts.
+func (i *Instance) GetInteractiveHeatmapRenderAxes() bool {
+	return i.getBool(InteractiveHeatmapRenderAxes)
+}
+
+// GetInteractiveHeatmapSprite returns true if the scrubber sprites of
+// interactive scenes should include the heatmap of each thumbnail.
+func (i *Instance) GetInteractiveHeatmapSprite() bool {
+	return i.getBool(InteractiveHeatmapSprite)
+}
+
+// GetInteractiveHeatmapWidth returns the width in pixels of generated
+// interactive heatmaps.
+func (i *Instance) GetInteractiveHeatmapWidth() int {
+	return i.getInt(InteractiveHeatmapWidth)
+}
+
+// GetInteractiveHeatmapHeight returns the height in pixels of the main band of
+// generated interactive heatmaps.
+func (i *Instance) GetInteractiveHeatmapHeight() int {
+	return i.getInt(InteractiveHeatmapHeight)
+}
+
+// GetInteractiveHeatmapSegments returns the number of segments that the
+// funscript is divided into when generating interactive heatmaps.
+func (i *Instance) GetInteractiveHeatmapSegments() int {
+	return i.getInt(InteractiveHeatmapSegments)
+}
+
+// GetInteractiveHeatmapColormap returns the name of the colormap used for
+// interactive heatmaps.
+func (i *Instance) GetInteractiveHeatmapColormap() string {
+	return i.getString(InteractiveHeatmapColormap)
+}
+
+// GetInteractiveHeatmapColormapStops returns the hex colors of the custom
+// interactive heatmap colormap.
+func (i *Instance) GetInteractiveHeatmapColormapStops() []string {
+	return i.getStringSlice(InteractiveHeatmapColormapStops)
+}
+
+// GetInteractiveHeatmapBackgroundColor returns the hex color of interactive
+// heatmap segments without actions.
+func (i *Instance) GetInteractiveHeatmapBackgroundColor() string {
+	return i.getString(InteractiveHeatmapBackgroundColor)
+}
+
+// GetBulkUndoWindow returns the number of minutes that destructive bulk
+// operations may be undone for. Zero means that bulk operations are not
+// staged and cannot be undone.
+func (i *Instance) GetBulkUndoWindow() int {
+	return i.getInt(BulkUndoWindow)
+}
+
+// GetAutoBackup returns true if the data affected by destructive bulk
+// operations is backed up before the operations are run.
+func (i *Instance) GetAutoBackup() bool {
+	return i.getBool(AutoBackup)
+}
+
+// GetAutoBackupMaxDatabaseSize returns the maximum size in MiB of a database
+// which is backed up in full before destructive bulk operations. Only the
+// affected rows of larger databases are backed up.
+func (i *Instance) GetAutoBackupMaxDatabaseSize() int {
+	return i.getInt(AutoBackupMaxDatabaseSize)
+}
+
+// GetLowMemoryMode returns true if stash should constrain its memory use, for
+// devices with little RAM. Caches are made smaller, ffmpeg and ffprobe
+// processes are run one at a time, exports load scenes and images in batches
+// and the library watcher is disabled.
+func (i *Instance) GetLowMemoryMode() bool {
+	return i.getBool(LowMemoryMode)
+}
+
+// GetInteractiveMarkerTag returns the name of the primary tag of markers
+// generated from high intensity sections of interactive scenes.
+func (i *Instance) GetInteractiveMarkerTag() string {
+	return i.getString(InteractiveMarkerTag)
+}
+
+// GetImportChapters returns true if the chapters embedded in video files are
+// imported as scene markers when the files are scanned or probed.
+func (i *Instance) GetImportChapters() bool {
+	return i.getBool(ImportChapters)
+}
+
+// GetChapterMarkerTag returns the name of the primary tag of markers
+// imported from chapters.
+func (i *Instance) GetChapterMarkerTag() string {
+	return i.getString(ChapterMarkerTag)
+}
+
 // ActivatePublicAccessTripwire sets the security_tripwire_accessed_from_public_internet
 // config field to the provided IP address to indicate that stash has been accessed
 // from this public IP without authentication.
@@ -1381,6 +2169,8 @@ func (i *Instance) setDefaultValues(write bool) error {
 	defaultDatabaseFilePath := i.GetDefaultDatabaseFilePath()
 	defaultScrapersPath := i.GetDefaultScrapersPath()
 	defaultPluginsPath := i.GetDefaultPluginsPath()
+	defaultThemesPath := i.GetDefaultThemesPath()
+	defaultLocalesPath := i.GetDefaultLocalesPath()
 
 	i.Lock()
 	defer i.Unlock()
@@ -1400,6 +2190,24 @@ func (i *Instance) setDefaultValues(write bool) error {
 
 	i.main.SetDefault(ThemeColor, DefaultThemeColor)
 
+	i.main.SetDefault(InteractiveHeatmapWidth, interactiveHeatmapWidthDefault)
+	i.main.SetDefault(InteractiveHeatmapHeight, interactiveHeatmapHeightDefault)
+	i.main.SetDefault(InteractiveHeatmapSegments, interactiveHeatmapSegmentsDefault)
+	i.main.SetDefault(InteractiveHeatmapColormap, interactiveHeatmapColormapDefault)
+	i.main.SetDefault(InteractiveHeatmapBackgroundColor, interactiveHeatmapBackgroundColorDefault)
+
+	i.main.SetDefault(BulkUndoWindow, bulkUndoWindowDefault)
+	i.main.SetDefault(AutoBackup, autoBackupDefault)
+	i.main.SetDefault(AutoBackupMaxDatabaseSize, autoBackupMaxDatabaseSizeDefault)
+	i.main.SetDefault(LowMemoryMode, lowMemoryModeDefault)
+	i.main.SetDefault(WatchLibraryDebounce, watchLibraryDebounceDefault)
+	i.main.SetDefault(JobArtifactRetentionDays, jobArtifactRetentionDaysDefault)
+	i.main.SetDefault(TrashRetentionDays, trashRetentionDaysDefault)
+	i.main.SetDefault(StashBoxFingerprintCacheDays, stashBoxFingerprintCacheDaysDefault)
+	i.main.SetDefault(RenameTemplate, renameTemplateDefault)
+	i.main.SetDefault(InteractiveMarkerTag, interactiveMarkerTagDefault)
+	i.main.SetDefault(ChapterMarkerTag, chapterMarkerTagDefault)
+
 	i.main.SetDefault(WriteImageThumbnails, writeImageThumbnailsDefault)
 
 	i.main.SetDefault(Database, defaultDatabaseFilePath)
@@ -1417,6 +2225,8 @@ func (i *Instance) setDefaultValues(write bool) error {
 	// Set default scrapers and plugins paths
 	i.main.SetDefault(ScrapersPath, defaultScrapersPath)
 	i.main.SetDefault(PluginsPath, defaultPluginsPath)
+	i.main.SetDefault(ThemesPath, defaultThemesPath)
+	i.main.SetDefault(LocalesPath, defaultLocalesPath)
 
 	if write {
 		return i.main.WriteConfig()
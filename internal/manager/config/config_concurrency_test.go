@@ -51,9 +51,15 @@ func TestConcurrentConfigAccess(t *testing.T) {
 				i.Set(ScraperCDPPath, i.GetScraperCDPPath())
 				i.Set(ScraperCertCheck, i.GetScraperCertCheck())
 				i.Set(ScraperExcludeTagPatterns, i.GetScraperExcludeTagPatterns())
+				i.Set(ScraperCacheDays, i.GetScraperCacheDays())
 				i.Set(StashBoxes, i.GetStashBoxes())
 				i.GetDefaultPluginsPath()
 				i.Set(PluginsPath, i.GetPluginsPath())
+				i.GetDefaultThemesPath()
+				i.Set(ThemesPath, i.GetThemesPath())
+				i.Set(ActiveTheme, i.GetActiveTheme())
+				i.GetDefaultLocalesPath()
+				i.Set(LocalesPath, i.GetLocalesPath())
 				i.Set(Host, i.GetHost())
 				i.Set(Port, i.GetPort())
 				i.Set(ExternalHost, i.GetExternalHost())
@@ -67,11 +73,18 @@ func TestConcurrentConfigAccess(t *testing.T) {
 				i.Set(PreviewPreset, i.GetPreviewPreset())
 				i.Set(MaxTranscodeSize, i.GetMaxTranscodeSize())
 				i.Set(MaxStreamingTranscodeSize, i.GetMaxStreamingTranscodeSize())
+				i.Set(TranscodeProfiles, i.GetTranscodeProfiles())
+				i.Set(StreamTranscodeProfiles, i.GetStreamTranscodeProfiles())
+				i.Set(PreviewTranscodeProfile, i.GetPreviewTranscodeProfile())
+				i.Set(HLSVariants, i.GetHLSVariants())
 				i.Set(ApiKey, i.GetAPIKey())
 				i.Set(Username, i.GetUsername())
 				i.Set(Password, i.GetPasswordHash())
 				i.GetCredentials()
 				i.Set(MaxSessionAge, i.GetMaxSessionAge())
+				i.Set(SessionLockPin, i.GetSessionLockPinHash())
+				i.Set(SessionLockTimeout, int(i.GetSessionLockTimeout().Minutes()))
+				i.Set(QuickHideSafeTags, i.GetQuickHideSafeTags())
 				i.Set(CustomServedFolders, i.GetCustomServedFolders())
 				i.Set(CustomUILocation, i.GetCustomUILocation())
 				i.Set(MenuItems, i.GetMenuItems())
@@ -114,6 +127,35 @@ func TestConcurrentConfigAccess(t *testing.T) {
 				i.Set(AutostartVideoOnPlaySelected, i.GetAutostartVideoOnPlaySelected())
 				i.Set(ContinuePlaylistDefault, i.GetContinuePlaylistDefault())
 				i.Set(PythonPath, i.GetPythonPath())
+				i.Set(DownloadHookEnabled, i.GetDownloadHookEnabled())
+				i.Set(DownloadHookAutoTag, i.GetDownloadHookAutoTag())
+				i.Set(UploadInboxPath, i.GetUploadInboxPath())
+				i.Set(InboxEnabled, i.GetInboxEnabled())
+				i.Set(ActivityLogEnabled, i.GetActivityLogEnabled())
+				i.Set(ActivityLogRetentionDays, i.GetActivityLogRetentionDays())
+				i.Set(MediaAllowedSubnets, i.GetMediaAllowedSubnets())
+				i.Set(MediaAccessToken, i.GetMediaAccessToken())
+				i.Set(InteractiveHeatmapRenderAxes, i.GetInteractiveHeatmapRenderAxes())
+				i.Set(InteractiveHeatmapSprite, i.GetInteractiveHeatmapSprite())
+				i.Set(InteractiveHeatmapWidth, i.GetInteractiveHeatmapWidth())
+				i.Set(InteractiveHeatmapHeight, i.GetInteractiveHeatmapHeight())
+				i.Set(InteractiveHeatmapSegments, i.GetInteractiveHeatmapSegments())
+				i.Set(InteractiveHeatmapColormap, i.GetInteractiveHeatmapColormap())
+				i.Set(InteractiveHeatmapColormapStops, i.GetInteractiveHeatmapColormapStops())
+				i.Set(InteractiveHeatmapBackgroundColor, i.GetInteractiveHeatmapBackgroundColor())
+				i.Set(BulkUndoWindow, i.GetBulkUndoWindow())
+				i.Set(AutoBackup, i.GetAutoBackup())
+				i.Set(AutoBackupMaxDatabaseSize, i.GetAutoBackupMaxDatabaseSize())
+				i.Set(LowMemoryMode, i.GetLowMemoryMode())
+				i.Set(InteractiveMarkerTag, i.GetInteractiveMarkerTag())
+				i.Set(ImportChapters, i.GetImportChapters())
+				i.Set(ChapterMarkerTag, i.GetChapterMarkerTag())
+				i.Set(TrashPath, i.GetTrashPath())
+				i.Set(TrashRetentionDays, i.GetTrashRetentionDays())
+				i.Set(DeletedFilesPath, i.GetDeletedFilesPath())
+				i.Set(DeletedFilesPreserveStructure, i.GetDeletedFilesPreserveStructure())
+				i.Set(DeletedFilesMaxSize, i.GetDeletedFilesMaxSize())
+				i.Set(StashBoxFingerprintCacheDays, i.GetStashBoxFingerprintCacheDays())
 			}
 			wg.Done()
 		}(k)
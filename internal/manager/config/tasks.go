@@ -1,8 +1,16 @@
 package config
 
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/stashapp/stash/internal/identify"
+)
+
 type ScanMetadataOptions struct {
-	// Set name, date, details from metadata (if present)
-	// Deprecated: not implemented
+	// Set title, date, details, performers, studio and cover of new scenes
+	// from the metadata embedded in their file (if present)
 	UseFileMetadata bool `json:"useFileMetadata"`
 	// Strip file extension from title
 	// Deprecated: not implemented
@@ -27,3 +35,130 @@ type AutoTagMetadataOptions struct {
 	// IDs of tags to tag files with, or "*" for all
 	Tags []string `json:"tags"`
 }
+
+// ScheduledTask is a task which is queued automatically according to a
+// cron-style schedule.
+type ScheduledTask struct {
+	Name string `json:"name"`
+	// Cron expression of five fields: minute, hour, day of month, month and
+	// day of week. Interpreted in the server's local time zone.
+	Schedule string            `json:"schedule"`
+	Task     ScheduledTaskType `json:"task"`
+	Enabled  bool              `json:"enabled"`
+}
+
+type ScheduledTaskType string
+
+const (
+	ScheduledTaskTypeScan     ScheduledTaskType = "SCAN"
+	ScheduledTaskTypeAutoTag  ScheduledTaskType = "AUTO_TAG"
+	ScheduledTaskTypeGenerate ScheduledTaskType = "GENERATE"
+	ScheduledTaskTypeClean    ScheduledTaskType = "CLEAN"
+	ScheduledTaskTypeBackup   ScheduledTaskType = "BACKUP"
+	// Submits fingerprints to and finds matches from the configured stash-boxes
+	ScheduledTaskTypeStashBoxSync ScheduledTaskType = "STASH_BOX_SYNC"
+	// Applies the retention rules of the stash paths
+	ScheduledTaskTypeRetention ScheduledTaskType = "RETENTION"
+	// Permanently deletes the trashed scenes older than the trash retention
+	// period
+	ScheduledTaskTypePurgeTrash ScheduledTaskType = "PURGE_TRASH"
+)
+
+var AllScheduledTaskType = []ScheduledTaskType{
+	ScheduledTaskTypeScan,
+	ScheduledTaskTypeAutoTag,
+	ScheduledTaskTypeGenerate,
+	ScheduledTaskTypeClean,
+	ScheduledTaskTypeBackup,
+	ScheduledTaskTypeStashBoxSync,
+	ScheduledTaskTypeRetention,
+	ScheduledTaskTypePurgeTrash,
+}
+
+func (e ScheduledTaskType) IsValid() bool {
+	switch e {
+	case ScheduledTaskTypeScan, ScheduledTaskTypeAutoTag, ScheduledTaskTypeGenerate, ScheduledTaskTypeClean, ScheduledTaskTypeBackup, ScheduledTaskTypeStashBoxSync, ScheduledTaskTypeRetention, ScheduledTaskTypePurgeTrash:
+		return true
+	}
+	return false
+}
+
+func (e ScheduledTaskType) String() string {
+	return string(e)
+}
+
+func (e *ScheduledTaskType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ScheduledTaskType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ScheduledTaskType", str)
+	}
+	return nil
+}
+
+func (e ScheduledTaskType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// ScrapeRefreshRule periodically re-scrapes performers or studios, to pick up
+// changes to their images, aliases and details.
+type ScrapeRefreshRule struct {
+	Name string `json:"name"`
+	// Cron expression of five fields, as for scheduled tasks
+	Schedule   string                  `json:"schedule"`
+	Enabled    bool                    `json:"enabled"`
+	EntityType ScrapeRefreshEntityType `json:"entityType"`
+	// IDs of the performers or studios to refresh. If empty, all performers
+	// or studios with a source to refresh from are refreshed.
+	IDs []string `json:"ids"`
+	// Endpoint of the stash-box to refresh from. If empty, performers are
+	// scraped from their URL. Studios can only be refreshed from a stash-box.
+	StashBoxEndpoint string `json:"stashBoxEndpoint"`
+	// Fields without options default to MERGE
+	FieldOptions []*identify.FieldOptions `json:"fieldOptions"`
+}
+
+type ScrapeRefreshEntityType string
+
+const (
+	ScrapeRefreshEntityTypePerformer ScrapeRefreshEntityType = "PERFORMER"
+	ScrapeRefreshEntityTypeStudio    ScrapeRefreshEntityType = "STUDIO"
+)
+
+var AllScrapeRefreshEntityType = []ScrapeRefreshEntityType{
+	ScrapeRefreshEntityTypePerformer,
+	ScrapeRefreshEntityTypeStudio,
+}
+
+func (e ScrapeRefreshEntityType) IsValid() bool {
+	switch e {
+	case ScrapeRefreshEntityTypePerformer, ScrapeRefreshEntityTypeStudio:
+		return true
+	}
+	return false
+}
+
+func (e ScrapeRefreshEntityType) String() string {
+	return string(e)
+}
+
+func (e *ScrapeRefreshEntityType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ScrapeRefreshEntityType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ScrapeRefreshEntityType", str)
+	}
+	return nil
+}
+
+func (e ScrapeRefreshEntityType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
@@ -0,0 +1,71 @@
+package manager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCronSchedule_Invalid(t *testing.T) {
+	tests := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"1,,2 * * * *",
+		"a * * * *",
+	}
+
+	for _, expr := range tests {
+		_, err := parseCronSchedule(expr)
+		assert.NotNil(t, err, expr)
+	}
+}
+
+func TestCronSchedule_Next(t *testing.T) {
+	// Wednesday
+	from := time.Date(2023, time.March, 15, 10, 30, 20, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		expr string
+		want time.Time
+	}{
+		{"every minute", "* * * * *", time.Date(2023, time.March, 15, 10, 31, 0, 0, time.UTC)},
+		{"daily", "0 3 * * *", time.Date(2023, time.March, 16, 3, 0, 0, 0, time.UTC)},
+		{"later today", "45 10 * * *", time.Date(2023, time.March, 15, 10, 45, 0, 0, time.UTC)},
+		{"step", "*/20 * * * *", time.Date(2023, time.March, 15, 10, 40, 0, 0, time.UTC)},
+		{"range with step", "0 1-5/2 * * *", time.Date(2023, time.March, 16, 1, 0, 0, 0, time.UTC)},
+		{"list", "0 8,22 * * *", time.Date(2023, time.March, 15, 22, 0, 0, 0, time.UTC)},
+		{"day of week", "0 2 * * sat", time.Date(2023, time.March, 18, 2, 0, 0, 0, time.UTC)},
+		{"sunday as 7", "0 2 * * 7", time.Date(2023, time.March, 19, 2, 0, 0, 0, time.UTC)},
+		{"month name", "0 0 1 jun *", time.Date(2023, time.June, 1, 0, 0, 0, 0, time.UTC)},
+		{"next year", "0 0 1 1 *", time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"day of month or week", "0 0 20 * mon", time.Date(2023, time.March, 20, 0, 0, 0, 0, time.UTC)},
+		{"leap day", "0 0 29 2 *", time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"macro", "@weekly", time.Date(2023, time.March, 19, 0, 0, 0, 0, time.UTC)},
+		{"never", "0 0 31 2 *", time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := parseCronSchedule(tt.expr)
+			if !assert.Nil(t, err) {
+				return
+			}
+
+			got := s.next(from)
+			assert.True(t, tt.want.Equal(got), "next() = %v, want %v", got, tt.want)
+			if !got.IsZero() {
+				assert.True(t, s.matches(got))
+			}
+		})
+	}
+}
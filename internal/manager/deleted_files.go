@@ -0,0 +1,29 @@
+package manager
+
+import (
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/file"
+)
+
+// deletedFilesHolding returns the holding directory that deleted library
+// files are moved to, or nil if it is not configured.
+func deletedFilesHolding(c *config.Instance) *file.HoldingDir {
+	path := c.GetDeletedFilesPath()
+	if path == "" {
+		return nil
+	}
+
+	return &file.HoldingDir{
+		Path:              path,
+		PreserveStructure: c.GetDeletedFilesPreserveStructure(),
+		MaxSize:           int64(c.GetDeletedFilesMaxSize()) * 1024 * 1024,
+	}
+}
+
+// NewFileDeleter returns a file deleter which moves deleted library files to
+// the configured holding directory.
+func NewFileDeleter() *file.Deleter {
+	ret := file.NewDeleter()
+	ret.Holding = deletedFilesHolding(config.GetInstance())
+	return ret
+}
@@ -0,0 +1,155 @@
+package manager
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+)
+
+// time allowed for the transcoder to report its version
+const transcoderCheckTimeout = 5 * time.Second
+
+type TranscoderDiagnostics struct {
+	FFMpegPath  string `json:"ffmpeg_path"`
+	FFProbePath string `json:"ffprobe_path"`
+	// Available is true if both ffmpeg and ffprobe can be run
+	Available bool `json:"available"`
+	// Version is the first line of the ffmpeg version output
+	Version *string `json:"version"`
+	Error   *string `json:"error"`
+}
+
+type StreamCacheDiagnostics struct {
+	Path     string `json:"path"`
+	Writable bool   `json:"writable"`
+	// Sessions is the number of active adaptive HLS sessions
+	Sessions int `json:"sessions"`
+	// Size is the total size in bytes of the cached segments
+	Size int64 `json:"size"`
+}
+
+// Diagnostics reports the state of the streaming subsystem, to help debug
+// streaming to remote clients.
+type Diagnostics struct {
+	Transcoder  *TranscoderDiagnostics  `json:"transcoder"`
+	StreamCache *StreamCacheDiagnostics `json:"stream_cache"`
+	Clients     []*StreamClient         `json:"clients"`
+}
+
+// GetDiagnostics returns the current diagnostics.
+func (s *Manager) GetDiagnostics(ctx context.Context) *Diagnostics {
+	return &Diagnostics{
+		Transcoder:  getTranscoderDiagnostics(ctx, string(s.FFMPEG), string(s.FFProbe)),
+		StreamCache: getStreamCacheDiagnostics(s.HLSStreams),
+		Clients:     s.StreamClients.List(time.Now()),
+	}
+}
+
+// runVersion runs the binary with the -version flag, returning the first
+// line of its output.
+func runVersion(ctx context.Context, path string) (string, error) {
+	if path == "" {
+		return "", errors.New("not found")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, transcoderCheckTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, path, "-version").Output()
+	if err != nil {
+		return "", err
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	scanner.Scan()
+	return scanner.Text(), nil
+}
+
+func getTranscoderDiagnostics(ctx context.Context, ffmpegPath string, ffprobePath string) *TranscoderDiagnostics {
+	ret := &TranscoderDiagnostics{
+		FFMpegPath:  ffmpegPath,
+		FFProbePath: ffprobePath,
+	}
+
+	setError := func(name string, err error) {
+		msg := name + ": " + err.Error()
+		ret.Error = &msg
+	}
+
+	version, err := runVersion(ctx, ffmpegPath)
+	if err != nil {
+		setError("ffmpeg", err)
+		return ret
+	}
+	ret.Version = &version
+
+	if _, err := runVersion(ctx, ffprobePath); err != nil {
+		setError("ffprobe", err)
+		return ret
+	}
+
+	ret.Available = true
+	return ret
+}
+
+// isWritable returns true if a file can be created in the directory. If the
+// directory does not exist, its closest existing parent is checked instead,
+// since the directory is created when first needed.
+func isWritable(dir string) bool {
+	for {
+		if _, err := os.Stat(dir); err == nil {
+			break
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return false
+		}
+		dir = parent
+	}
+
+	f, err := os.CreateTemp(dir, ".write-test-*")
+	if err != nil {
+		return false
+	}
+
+	f.Close()
+	_ = os.Remove(f.Name())
+	return true
+}
+
+func dirSize(dir string) int64 {
+	var ret int64
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// ignore files removed while walking
+			return nil
+		}
+
+		if !d.IsDir() {
+			if info, err := d.Info(); err == nil {
+				ret += info.Size()
+			}
+		}
+		return nil
+	})
+
+	return ret
+}
+
+func getStreamCacheDiagnostics(m *HLSStreamManager) *StreamCacheDiagnostics {
+	dir := m.CacheDir()
+
+	return &StreamCacheDiagnostics{
+		Path:     dir,
+		Writable: isWritable(dir),
+		Sessions: m.SessionCount(),
+		Size:     dirSize(dir),
+	}
+}
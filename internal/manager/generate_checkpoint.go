@@ -0,0 +1,268 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// generateCheckpointJob is the job name of the generate checkpoint. Only
+// generate jobs for the whole library are checkpointed.
+const generateCheckpointJob = "generate"
+
+// generateCheckpointInterval is how often the generate checkpoint is saved
+const generateCheckpointInterval = 30 * time.Second
+
+// generator types, used as the checkpoint task names
+const (
+	generatorSprites                   = "sprites"
+	generatorHoverStrips               = "hover_strips"
+	generatorPreviews                  = "previews"
+	generatorMarkers                   = "markers"
+	generatorTranscodes                = "transcodes"
+	generatorPhashes                   = "phashes"
+	generatorInteractiveHeatmapsSpeeds = "interactive_heatmaps_speeds"
+	generatorInteractiveMarkers        = "interactive_markers"
+	generatorChapterThumbnails         = "chapter_thumbnails"
+)
+
+// generateCheckpoint tracks the last scene processed by each generator.
+// Scenes are queued in ascending ID order, but tasks complete out of order,
+// so the checkpoint of a generator is the scene before its earliest
+// incomplete task.
+type generateCheckpoint struct {
+	mutex sync.Mutex
+
+	// last processed scene ID of each generator when the job was resumed
+	resumeFrom map[string]int
+	// number of incomplete tasks of each scene ID, by generator
+	pending map[string]map[int]int
+	// ID of the last scene that has had all of its tasks queued
+	lastQueued int
+}
+
+func newGenerateCheckpoint(resumeFrom map[string]int) *generateCheckpoint {
+	if resumeFrom == nil {
+		resumeFrom = make(map[string]int)
+	}
+
+	return &generateCheckpoint{
+		resumeFrom: resumeFrom,
+		pending:    make(map[string]map[int]int),
+	}
+}
+
+// skip returns true if the generator has already processed the scene.
+func (c *generateCheckpoint) skip(generator string, sceneID int) bool {
+	return c != nil && sceneID <= c.resumeFrom[generator]
+}
+
+func (c *generateCheckpoint) taskQueued(generator string, sceneID int) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	p := c.pending[generator]
+	if p == nil {
+		p = make(map[int]int)
+		c.pending[generator] = p
+	}
+	p[sceneID]++
+}
+
+func (c *generateCheckpoint) taskDone(generator string, sceneID int) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	p := c.pending[generator]
+	p[sceneID]--
+	if p[sceneID] <= 0 {
+		delete(p, sceneID)
+	}
+}
+
+// sceneQueued must be called once all tasks of the scene have been queued.
+func (c *generateCheckpoint) sceneQueued(sceneID int) {
+	if c == nil {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.lastQueued = sceneID
+}
+
+// progress returns the last scene ID processed by each of the generators.
+func (c *generateCheckpoint) progress(generators []string) map[string]int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	ret := make(map[string]int)
+	for _, g := range generators {
+		last := c.lastQueued
+		for id := range c.pending[g] {
+			if id-1 < last {
+				last = id - 1
+			}
+		}
+
+		if resumed := c.resumeFrom[g]; resumed > last {
+			last = resumed
+		}
+
+		ret[g] = last
+	}
+
+	return ret
+}
+
+// checkpointTask marks the task as done in the checkpoint once it has run.
+type checkpointTask struct {
+	Task
+	done func()
+}
+
+func (t *checkpointTask) Start(ctx context.Context) {
+	t.Task.Start(ctx)
+	t.done()
+}
+
+// generators returns the generator types enabled by the input.
+func (j *GenerateJob) generators() []string {
+	var ret []string
+	add := func(enabled *bool, g string) {
+		if enabled != nil && *enabled {
+			ret = append(ret, g)
+		}
+	}
+
+	add(j.input.Sprites, generatorSprites)
+	add(j.input.HoverStrips, generatorHoverStrips)
+	add(j.input.Previews, generatorPreviews)
+	add(j.input.Markers, generatorMarkers)
+	add(j.input.Transcodes, generatorTranscodes)
+	add(j.input.Phashes, generatorPhashes)
+	add(j.input.InteractiveHeatmapsSpeeds, generatorInteractiveHeatmapsSpeeds)
+	add(j.input.InteractiveMarkers, generatorInteractiveMarkers)
+	add(j.input.ChapterThumbnails, generatorChapterThumbnails)
+
+	return ret
+}
+
+// startCheckpoint persists a new checkpoint for the job input, unless the
+// job is being resumed from an existing checkpoint.
+func (j *GenerateJob) startCheckpoint(ctx context.Context) error {
+	j.checkpoint = newGenerateCheckpoint(j.resumeFrom)
+	if j.resumeFrom != nil {
+		return nil
+	}
+
+	input, err := json.Marshal(j.input)
+	if err != nil {
+		return err
+	}
+
+	return j.txnManager.WithTxn(ctx, func(ctx context.Context) error {
+		return j.txnManager.JobCheckpoint.Start(ctx, generateCheckpointJob, string(input))
+	})
+}
+
+func (j *GenerateJob) saveCheckpoint(ctx context.Context) {
+	progress := j.checkpoint.progress(j.generators())
+
+	if err := j.txnManager.WithTxn(ctx, func(ctx context.Context) error {
+		qb := j.txnManager.JobCheckpoint
+		for g, lastID := range progress {
+			if err := qb.SetProgress(ctx, generateCheckpointJob, g, lastID); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		logger.Warnf("error saving generate checkpoint: %v", err)
+	}
+}
+
+// saveCheckpointPeriodically saves the checkpoint until the returned
+// function is called.
+func (j *GenerateJob) saveCheckpointPeriodically(ctx context.Context) (stop func()) {
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(generateCheckpointInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				j.saveCheckpoint(ctx)
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		wg.Wait()
+	}
+}
+
+func (j *GenerateJob) clearCheckpoint(ctx context.Context) {
+	if err := j.txnManager.WithTxn(ctx, func(ctx context.Context) error {
+		return j.txnManager.JobCheckpoint.Destroy(ctx, generateCheckpointJob)
+	}); err != nil {
+		logger.Warnf("error clearing generate checkpoint: %v", err)
+	}
+}
+
+// resumeInterruptedJobs queues the generate job which was interrupted by
+// the last shutdown, continuing from its checkpoint.
+func (s *Manager) resumeInterruptedJobs(ctx context.Context) error {
+	if s.Database.Ready() != nil {
+		return nil
+	}
+
+	var checkpoint *models.JobCheckpoint
+	if err := s.Repository.WithReadTxn(ctx, func(ctx context.Context) error {
+		var err error
+		checkpoint, err = s.Repository.JobCheckpoint.Find(ctx, generateCheckpointJob)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	if checkpoint == nil {
+		return nil
+	}
+
+	var input GenerateMetadataInput
+	if err := json.Unmarshal([]byte(checkpoint.Input), &input); err != nil {
+		return fmt.Errorf("invalid generate checkpoint input: %w", err)
+	}
+
+	if err := s.validateFFMPEG(); err != nil {
+		return err
+	}
+
+	logger.Infof("Resuming generate job interrupted at %v", checkpoint.UpdatedAt)
+
+	j := &GenerateJob{
+		txnManager: s.Repository,
+		input:      input,
+		resumeFrom: checkpoint.Progress,
+	}
+
+	s.JobManager.AddClass(ctx, "Resuming generate...", j, job.ClassCPU)
+	return nil
+}
@@ -0,0 +1,49 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateCheckpoint_Progress(t *testing.T) {
+	generators := []string{generatorSprites, generatorPhashes}
+
+	c := newGenerateCheckpoint(nil)
+	assert.Equal(t, map[string]int{generatorSprites: 0, generatorPhashes: 0}, c.progress(generators))
+
+	c.taskQueued(generatorSprites, 1)
+	c.taskQueued(generatorPhashes, 1)
+	c.taskQueued(generatorPhashes, 1)
+	c.sceneQueued(1)
+	c.sceneQueued(2)
+	c.taskQueued(generatorSprites, 3)
+	c.sceneQueued(3)
+
+	assert.Equal(t, map[string]int{generatorSprites: 0, generatorPhashes: 0}, c.progress(generators))
+
+	// tasks complete out of order
+	c.taskDone(generatorSprites, 3)
+	c.taskDone(generatorPhashes, 1)
+	assert.Equal(t, map[string]int{generatorSprites: 0, generatorPhashes: 0}, c.progress(generators))
+
+	c.taskDone(generatorSprites, 1)
+	c.taskDone(generatorPhashes, 1)
+	assert.Equal(t, map[string]int{generatorSprites: 3, generatorPhashes: 3}, c.progress(generators))
+}
+
+func TestGenerateCheckpoint_Resume(t *testing.T) {
+	c := newGenerateCheckpoint(map[string]int{generatorSprites: 10})
+
+	assert.True(t, c.skip(generatorSprites, 10))
+	assert.False(t, c.skip(generatorSprites, 11))
+	assert.False(t, c.skip(generatorPreviews, 1))
+
+	// progress does not go back before the resumed position
+	c.taskQueued(generatorPreviews, 1)
+	c.sceneQueued(1)
+	assert.Equal(t, map[string]int{generatorSprites: 10, generatorPreviews: 0}, c.progress([]string{generatorSprites, generatorPreviews}))
+
+	var nilCheckpoint *generateCheckpoint
+	assert.False(t, nilCheckpoint.skip(generatorSprites, 1))
+}
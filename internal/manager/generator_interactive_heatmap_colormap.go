@@ -0,0 +1,116 @@
+package manager
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+const (
+	HeatmapColormapDefault = "default"
+	HeatmapColormapViridis = "viridis"
+	HeatmapColormapPlasma  = "plasma"
+	// HeatmapColormapCustom uses user provided color stops
+	HeatmapColormapCustom = "custom"
+)
+
+// DefaultHeatmapBackgroundColor is the color of heatmap segments without
+// actions. Same as the GridCard background.
+const DefaultHeatmapBackgroundColor = "#30404d"
+
+// heatmapStepSize is the intensity range covered by each color of the default
+// colormap.
+const heatmapStepSize = 60.0
+
+// maxHeatmapIntensity is the intensity mapped to the last color stop.
+const maxHeatmapIntensity = 9 * heatmapStepSize
+
+// HeatmapColormap returns the color of a heatmap segment with the provided
+// average intensity.
+type HeatmapColormap func(intensity float64) colorful.Color
+
+var (
+	viridisStops = []string{"#440154", "#482878", "#3e4989", "#31688e", "#26828e", "#1f9e89", "#35b779", "#6ece58", "#b5de2b", "#fde725"}
+	plasmaStops  = []string{"#0d0887", "#46039f", "#7201a8", "#9c179e", "#bd3786", "#d8576b", "#ed7953", "#fb9f3a", "#fdca26", "#f0f921"}
+)
+
+// ParseHeatmapColormap returns the colormap with the provided name. stops are
+// the hex colors used by the custom colormap, and are ignored otherwise.
+func ParseHeatmapColormap(name string, stops []string) (HeatmapColormap, error) {
+	switch strings.ToLower(name) {
+	case "", HeatmapColormapDefault:
+		return defaultHeatmapColormap, nil
+	case HeatmapColormapViridis:
+		return newStopsColormap(viridisStops)
+	case HeatmapColormapPlasma:
+		return newStopsColormap(plasmaStops)
+	case HeatmapColormapCustom:
+		if len(stops) < 2 {
+			return nil, fmt.Errorf("custom colormap requires at least two color stops")
+		}
+		return newStopsColormap(stops)
+	default:
+		return nil, fmt.Errorf("unknown colormap %q", name)
+	}
+}
+
+// newStopsColormap returns a colormap which blends evenly between the
+// provided hex colors up to maxHeatmapIntensity.
+func newStopsColormap(stops []string) (HeatmapColormap, error) {
+	colors := make([]colorful.Color, len(stops))
+	for i, s := range stops {
+		c, err := colorful.Hex(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid color stop %q: %w", s, err)
+		}
+		colors[i] = c
+	}
+
+	return func(intensity float64) colorful.Color {
+		f := math.Min(math.Max(intensity/maxHeatmapIntensity, 0), 1) * float64(len(colors)-1)
+		i := int(f)
+		if i >= len(colors)-1 {
+			return colors[len(colors)-1]
+		}
+
+		return colors[i].BlendLab(colors[i+1], f-float64(i))
+	}, nil
+}
+
+// defaultHeatmapColormap blends from DodgerBlue through to Purple, and then
+// to black for very intense segments.
+func defaultHeatmapColormap(intensity float64) colorful.Color {
+	colorBlue, _ := colorful.Hex("#1e90ff")   // DodgerBlue
+	colorGreen, _ := colorful.Hex("#228b22")  // ForestGreen
+	colorYellow, _ := colorful.Hex("#ffd700") // Gold
+	colorRed, _ := colorful.Hex("#dc143c")    // Crimson
+	colorPurple, _ := colorful.Hex("#800080") // Purple
+	colorBlack, _ := colorful.Hex("#0f001e")
+
+	var stepSize = heatmapStepSize
+	var f float64
+	var c colorful.Color
+
+	switch {
+	case intensity <= 1*stepSize:
+		f = (intensity - 0*stepSize) / stepSize
+		c = colorBlue.BlendLab(colorGreen, f)
+	case intensity <= 2*stepSize:
+		f = (intensity - 1*stepSize) / stepSize
+		c = colorGreen.BlendLab(colorYellow, f)
+	case intensity <= 3*stepSize:
+		f = (intensity - 2*stepSize) / stepSize
+		c = colorYellow.BlendLab(colorRed, f)
+	case intensity <= 4*stepSize:
+		f = (intensity - 3*stepSize) / stepSize
+		c = colorRed.BlendRgb(colorPurple, f)
+	default:
+		f = (intensity - 4*stepSize) / (5 * stepSize)
+		f = math.Min(f, 1.0)
+		c = colorPurple.BlendLab(colorBlack, f)
+	}
+
+	return c
+}
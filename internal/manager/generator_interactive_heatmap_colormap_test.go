@@ -0,0 +1,60 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/lucasb-eyer/go-colorful"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseHeatmapColormap(t *testing.T) {
+	hex := func(s string) colorful.Color {
+		c, _ := colorful.Hex(s)
+		return c
+	}
+
+	tests := []struct {
+		name    string
+		stops   []string
+		wantErr bool
+		// expected colors at zero and maximum intensity
+		wantMin colorful.Color
+		wantMax colorful.Color
+	}{
+		{"", nil, false, hex("#1e90ff"), hex("#0f001e")},
+		{"default", nil, false, hex("#1e90ff"), hex("#0f001e")},
+		{"Viridis", nil, false, hex("#440154"), hex("#fde725")},
+		{"plasma", nil, false, hex("#0d0887"), hex("#f0f921")},
+		{"custom", []string{"#000000", "#ffffff"}, false, hex("#000000"), hex("#ffffff")},
+		{"custom", []string{"#000000"}, true, colorful.Color{}, colorful.Color{}},
+		{"custom", []string{"#000000", "white"}, true, colorful.Color{}, colorful.Color{}},
+		{"rainbow", nil, true, colorful.Color{}, colorful.Color{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseHeatmapColormap(tt.name, tt.stops)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseHeatmapColormap() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			assert.Equal(t, tt.wantMin.Hex(), got(0).Hex())
+			assert.Equal(t, tt.wantMax.Hex(), got(maxHeatmapIntensity).Hex())
+			// intensities beyond the maximum are clamped
+			assert.Equal(t, tt.wantMax.Hex(), got(2*maxHeatmapIntensity).Hex())
+		})
+	}
+}
+
+func TestInteractiveHeatmapSpeedGeneratorSegmentColor(t *testing.T) {
+	g := NewInteractiveHeatmapSpeedGenerator("", "", 0)
+	g.BackgroundColor, _ = colorful.Hex("#ffffff")
+	g.Colormap, _ = ParseHeatmapColormap("custom", []string{"#ff0000", "#ff0000"})
+
+	assert.Equal(t, "#ffffff", g.segmentColor(0).Hex())
+	assert.Equal(t, "#ff0000", g.segmentColor(100).Hex())
+}
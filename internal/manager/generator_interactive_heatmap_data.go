@@ -0,0 +1,71 @@
+package manager
+
+import (
+	"fmt"
+)
+
+// maxHeatmapDataSegments is the maximum number of segments that heatmap data
+// may be requested with.
+const maxHeatmapDataSegments = 2000
+
+// InteractiveHeatmapData is the per-segment data used to render the
+// interactive heatmap, for clients which render the heatmap themselves.
+type InteractiveHeatmapData struct {
+	// Time in milliseconds covered by the segments
+	Duration int `json:"duration"`
+	// Average intensity of the actions in each segment
+	Intensity []float64 `json:"intensity"`
+	// Average speed of the actions in each segment, in positions per second
+	Speed []float64 `json:"speed"`
+	// Lowest and highest position of each segment. Empty for segments
+	// without actions
+	YRange [][]int `json:"y_range"`
+}
+
+// HeatmapData returns the heatmap data of the script divided into
+// numSegments segments. The script must have its intensity and speed
+// updated first.
+func (funscript Script) HeatmapData(numSegments int) InteractiveHeatmapData {
+	maxts := funscript.Actions[len(funscript.Actions)-1].At
+	segments := funscript.getSegmentsUntil(numSegments, maxts)
+
+	ret := InteractiveHeatmapData{
+		Duration:  int(maxts + 1),
+		Intensity: make([]float64, numSegments),
+		Speed:     make([]float64, numSegments),
+		YRange:    make([][]int, numSegments),
+	}
+
+	for i, s := range segments {
+		ret.Intensity[i] = s.averageIntensity()
+		ret.Speed[i] = s.averageSpeed()
+		ret.YRange[i] = []int{}
+		if s.count > 0 {
+			ret.YRange[i] = []int{s.minPos, s.maxPos}
+		}
+	}
+
+	return ret
+}
+
+// LoadInteractiveHeatmapData loads the funscript and returns its heatmap data
+// divided into numSegments segments.
+func LoadInteractiveHeatmapData(funscriptPath string, sceneDuration float64, numSegments int) (*InteractiveHeatmapData, error) {
+	if numSegments < 2 || numSegments > maxHeatmapDataSegments {
+		return nil, fmt.Errorf("number of segments must be between 2 and %d", maxHeatmapDataSegments)
+	}
+
+	g := NewInteractiveHeatmapSpeedGenerator(funscriptPath, "", sceneDuration)
+	funscript, err := g.LoadFunscriptData(funscriptPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(funscript.Actions) == 0 {
+		return nil, fmt.Errorf("no valid actions in funscript")
+	}
+
+	funscript.UpdateIntensityAndSpeed()
+	ret := funscript.HeatmapData(numSegments)
+	return &ret, nil
+}
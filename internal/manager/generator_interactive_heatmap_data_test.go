@@ -0,0 +1,65 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScriptHeatmapData(t *testing.T) {
+	script := Script{
+		Actions: []Action{
+			{At: 0, Pos: 0},
+			{At: 500, Pos: 100},
+			{At: 1000, Pos: 20},
+			{At: 3999, Pos: 80},
+		},
+	}
+	script.UpdateIntensityAndSpeed()
+
+	got := script.HeatmapData(4)
+
+	assert.Equal(t, 4000, got.Duration)
+	assert.Equal(t, [][]int{{0, 100}, {20, 20}, {}, {80, 80}}, got.YRange)
+	// the first action has no speed or intensity
+	assert.InDeltaSlice(t, []float64{100, 160, 0, 20}, got.Speed, 0.01)
+	assert.Equal(t, []float64{50, 80, 0, 10}, got.Intensity)
+}
+
+func TestLoadInteractiveHeatmapData(t *testing.T) {
+	dir := t.TempDir()
+	funscriptPath := filepath.Join(dir, "scene.funscript")
+	if err := os.WriteFile(funscriptPath, []byte(testFunscript), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		path        string
+		numSegments int
+		wantErr     bool
+	}{
+		{"valid", funscriptPath, 10, false},
+		{"too few segments", funscriptPath, 1, true},
+		{"too many segments", funscriptPath, maxHeatmapDataSegments + 1, true},
+		{"missing file", filepath.Join(dir, "missing.funscript"), 10, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LoadInteractiveHeatmapData(tt.path, 2, tt.numSegments)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			if assert.NoError(t, err) {
+				assert.Len(t, got.Intensity, tt.numSegments)
+				assert.Len(t, got.Speed, tt.numSegments)
+				assert.Len(t, got.YRange, tt.numSegments)
+			}
+		})
+	}
+}
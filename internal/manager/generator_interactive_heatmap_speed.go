@@ -1,28 +1,50 @@
 package manager
 
 import (
-	"encoding/json"
 	"fmt"
 	"image"
 	"image/draw"
 	"image/png"
+	"io"
 	"math"
 	"os"
 	"sort"
 
 	"github.com/lucasb-eyer/go-colorful"
+	"github.com/stashapp/stash/pkg/file/video"
+	"github.com/stashapp/stash/pkg/fsutil"
 	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
 )
 
 type InteractiveHeatmapSpeedGenerator struct {
 	sceneDurationMilli int64
 	InteractiveSpeed   int
+	Stats              models.FunscriptStats
 	Funscript          Script
 	FunscriptPath      string
 	HeatmapPath        string
 	Width              int
 	Height             int
 	NumSegments        int
+
+	// Colormap of segments with actions
+	Colormap HeatmapColormap
+	// Color of segments without actions
+	BackgroundColor colorful.Color
+
+	// Secondary axis scripts of multi-axis funscripts
+	Axes []AxisScript
+	// Render a band for each secondary axis below the main heatmap
+	RenderAxes bool
+	AxisHeight int
+}
+
+// AxisScript is a secondary axis script of a multi-axis funscript.
+type AxisScript struct {
+	Axis             string
+	Script           Script
+	InteractiveSpeed int
 }
 
 type Script struct {
@@ -55,6 +77,8 @@ type GradientTable []struct {
 }
 
 func NewInteractiveHeatmapSpeedGenerator(funscriptPath string, heatmapPath string, sceneDuration float64) *InteractiveHeatmapSpeedGenerator {
+	background, _ := colorful.Hex(DefaultHeatmapBackgroundColor)
+
 	return &InteractiveHeatmapSpeedGenerator{
 		sceneDurationMilli: int64(sceneDuration * 1000),
 		FunscriptPath:      funscriptPath,
@@ -62,50 +86,142 @@ func NewInteractiveHeatmapSpeedGenerator(funscriptPath string, heatmapPath strin
 		Width:              320,
 		Height:             15,
 		NumSegments:        150,
+		AxisHeight:         5,
+		Colormap:           defaultHeatmapColormap,
+		BackgroundColor:    background,
 	}
 }
 
+// Generate loads the funscript at FunscriptPath along with its secondary axis
+// scripts, and renders the heatmap to HeatmapPath.
 func (g *InteractiveHeatmapSpeedGenerator) Generate() error {
+	if err := g.validate(); err != nil {
+		return err
+	}
+
 	funscript, err := g.LoadFunscriptData(g.FunscriptPath)
+	if err != nil {
+		return err
+	}
+
+	g.loadAxes()
+
+	if err := g.Process(funscript); err != nil {
+		return err
+	}
+
+	return g.RenderHeatmap()
+}
 
+// GenerateFrom parses the script read from r and renders the heatmap to w,
+// without reading or writing any files. Secondary axis scripts are not
+// loaded, but Axes may be set beforehand for them to be rendered.
+func (g *InteractiveHeatmapSpeedGenerator) GenerateFrom(r io.Reader, isCSV bool, w io.Writer) error {
+	if err := g.validate(); err != nil {
+		return err
+	}
+
+	funscript, err := g.ParseFunscriptData(r, isCSV)
 	if err != nil {
 		return err
 	}
 
+	if err := g.Process(funscript); err != nil {
+		return err
+	}
+
+	return g.RenderHeatmapTo(w)
+}
+
+func (g *InteractiveHeatmapSpeedGenerator) validate() error {
+	if g.Width <= 0 || g.Height <= 0 {
+		return fmt.Errorf("invalid heatmap dimensions %dx%d", g.Width, g.Height)
+	}
+
+	// gradient positions are divided by the number of segments minus one
+	if g.NumSegments < 2 {
+		return fmt.Errorf("invalid number of heatmap segments %d", g.NumSegments)
+	}
+
+	return nil
+}
+
+// Process sets the funscript to render, and calculates the intensity and
+// speed of its actions and of the secondary axis scripts, along with the
+// stats and interactive speeds.
+func (g *InteractiveHeatmapSpeedGenerator) Process(funscript Script) error {
 	if len(funscript.Actions) == 0 {
 		return fmt.Errorf("no valid actions in funscript")
 	}
 
 	g.Funscript = funscript
 	g.Funscript.UpdateIntensityAndSpeed()
+	g.Stats = g.Funscript.CalculateStats(g.sceneDurationMilli)
+	g.InteractiveSpeed = g.Funscript.medianSpeed()
 
-	err = g.RenderHeatmap()
-
-	if err != nil {
-		return err
+	for i := range g.Axes {
+		g.Axes[i].Script.UpdateIntensityAndSpeed()
+		g.Axes[i].InteractiveSpeed = g.Axes[i].Script.medianSpeed()
 	}
 
-	g.InteractiveSpeed = g.Funscript.CalculateMedian()
-
 	return nil
 }
 
+// loadAxes loads the secondary axis scripts stored next to the funscript.
+// Invalid axis scripts are logged and ignored.
+func (g *InteractiveHeatmapSpeedGenerator) loadAxes() {
+	g.Axes = nil
+
+	for _, axis := range video.FunscriptAxes {
+		axisPath := video.GetFunscriptAxisPath(g.FunscriptPath, axis)
+		if exists, _ := fsutil.FileExists(axisPath); !exists {
+			continue
+		}
+
+		script, err := g.LoadFunscriptData(axisPath)
+		if err != nil {
+			logger.Warnf("error loading %s axis script %s: %v", axis, axisPath, err)
+			continue
+		}
+
+		if len(script.Actions) == 0 {
+			logger.Warnf("no valid actions in %s axis script %s", axis, axisPath)
+			continue
+		}
+
+		g.Axes = append(g.Axes, AxisScript{
+			Axis:   axis,
+			Script: script,
+		})
+	}
+}
+
+// LoadFunscriptData reads the script at path. See ParseFunscriptData.
 func (g *InteractiveHeatmapSpeedGenerator) LoadFunscriptData(path string) (Script, error) {
-	data, err := os.ReadFile(path)
+	funscript, err := ReadInteractiveScript(path)
 	if err != nil {
 		return Script{}, err
 	}
 
-	var funscript Script
-	err = json.Unmarshal(data, &funscript)
+	g.trimActions(&funscript, path)
+	return funscript, nil
+}
+
+// ParseFunscriptData parses the script read from r, sorting its actions by
+// time and removing actions outside of the scene duration.
+func (g *InteractiveHeatmapSpeedGenerator) ParseFunscriptData(r io.Reader, isCSV bool) (Script, error) {
+	funscript, err := ParseInteractiveScript(r, isCSV)
 	if err != nil {
 		return Script{}, err
 	}
 
-	if funscript.Actions == nil {
-		return Script{}, fmt.Errorf("actions list missing in %s", path)
-	}
+	g.trimActions(&funscript, "funscript")
+	return funscript, nil
+}
 
+// trimActions sorts the actions of the script by time and removes the actions
+// outside of the scene duration. name identifies the script in log messages.
+func (g *InteractiveHeatmapSpeedGenerator) trimActions(funscript *Script, name string) {
 	sort.SliceStable(funscript.Actions, func(i, j int) bool { return funscript.Actions[i].At < funscript.Actions[j].At })
 
 	// trim actions with negative timestamps to avoid index range errors when generating heatmap
@@ -122,13 +238,11 @@ func (g *InteractiveHeatmapSpeedGenerator) LoadFunscriptData(path string) (Scrip
 			i++
 		} else if !loggedBadTimestamp {
 			loggedBadTimestamp = true
-			logger.Warnf("Invalid timestamp %d in %s: subsequent invalid timestamps will not be logged", x.At, path)
+			logger.Warnf("Invalid timestamp %d in %s: subsequent invalid timestamps will not be logged", x.At, name)
 		}
 	}
 
 	funscript.Actions = funscript.Actions[:i]
-
-	return funscript, nil
 }
 
 func (funscript *Script) UpdateIntensityAndSpeed() {
@@ -156,19 +270,49 @@ func (funscript *Script) UpdateIntensityAndSpeed() {
 	}
 }
 
-// funscript needs to have intensity updated first
+// RenderHeatmap renders the heatmap to HeatmapPath. The funscript must be
+// processed first.
 func (g *InteractiveHeatmapSpeedGenerator) RenderHeatmap() error {
+	outpng, err := os.Create(g.HeatmapPath)
+	if err != nil {
+		return err
+	}
+	defer outpng.Close()
 
-	gradient := g.Funscript.getGradientTable(g.NumSegments)
+	return g.RenderHeatmapTo(outpng)
+}
+
+// RenderHeatmapTo renders the heatmap as a PNG image to w. The funscript must
+// be processed first.
+func (g *InteractiveHeatmapSpeedGenerator) RenderHeatmapTo(w io.Writer) error {
+
+	maxts := g.Funscript.Actions[len(g.Funscript.Actions)-1].At
+	gradient := g.Funscript.getGradientTable(g.NumSegments, g.segmentColor)
 
-	img := image.NewRGBA(image.Rect(0, 0, g.Width, g.Height))
-	for x := 0; x < g.Width; x++ {
-		c := gradient.GetInterpolatedColorFor(float64(x) / float64(g.Width))
-		draw.Draw(img, image.Rect(x, 0, x+1, g.Height), &image.Uniform{c}, image.Point{}, draw.Src)
+	height := g.Height
+	if g.RenderAxes {
+		height += len(g.Axes) * g.AxisHeight
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, g.Width, height))
+	drawBand := func(gradient GradientTable, y0 int, y1 int) {
+		for x := 0; x < g.Width; x++ {
+			c := gradient.GetInterpolatedColorFor(float64(x) / float64(g.Width))
+			draw.Draw(img, image.Rect(x, y0, x+1, y1), &image.Uniform{c}, image.Point{}, draw.Src)
+		}
+	}
+
+	drawBand(gradient, 0, g.Height)
+
+	if g.RenderAxes {
+		// axis bands use the timescale of the main script so that they line up
+		for i, axis := range g.Axes {
+			y0 := g.Height + i*g.AxisHeight
+			drawBand(axis.Script.getGradientTableUntil(g.NumSegments, maxts, g.segmentColor), y0, y0+g.AxisHeight)
+		}
 	}
 
 	// add 10 minute marks
-	maxts := g.Funscript.Actions[len(g.Funscript.Actions)-1].At
 	const tick = 600000
 	var ts int64 = tick
 	c, _ := colorful.Hex("#000000")
@@ -178,14 +322,7 @@ func (g *InteractiveHeatmapSpeedGenerator) RenderHeatmap() error {
 		ts += tick
 	}
 
-	outpng, err := os.Create(g.HeatmapPath)
-	if err != nil {
-		return err
-	}
-	defer outpng.Close()
-
-	err = png.Encode(outpng, img)
-	return err
+	return png.Encode(w, img)
 }
 
 func (funscript *Script) CalculateMedian() int {
@@ -202,6 +339,80 @@ func (funscript *Script) CalculateMedian() int {
 	return int((funscript.Actions[mNumber-1].Speed + funscript.Actions[mNumber].Speed) / 2)
 }
 
+// medianSpeed returns the median speed of the actions without reordering the
+// actions of the script.
+func (funscript Script) medianSpeed() int {
+	if len(funscript.Actions) == 0 {
+		return 0
+	}
+
+	sorted := Script{
+		Actions: append([]Action(nil), funscript.Actions...),
+	}
+	return sorted.CalculateMedian()
+}
+
+// maxCoverageGap is the maximum interval in milliseconds between two actions
+// for the interval to count as scripted movement.
+const maxCoverageGap = 5000
+
+// CalculateStats returns statistics of the script. Actions must be sorted by
+// time and have their speed updated first.
+func (funscript *Script) CalculateStats(durationMilli int64) models.FunscriptStats {
+	ret := models.FunscriptStats{
+		ActionCount: len(funscript.Actions),
+	}
+
+	if len(funscript.Actions) < 2 {
+		return ret
+	}
+
+	speeds := make([]float64, 0, len(funscript.Actions)-1)
+	var strokeTotal float64
+	var strokes int
+	var covered int64
+	for i := 1; i < len(funscript.Actions); i++ {
+		prev := funscript.Actions[i-1]
+		a := funscript.Actions[i]
+
+		speeds = append(speeds, a.Speed)
+
+		if length := math.Abs(float64(a.Pos - prev.Pos)); length > 0 {
+			strokeTotal += length
+			strokes++
+
+			if gap := a.At - prev.At; gap <= maxCoverageGap {
+				covered += gap
+			}
+		}
+	}
+
+	sort.Float64s(speeds)
+	percentile := func(p float64) int {
+		// nearest-rank method
+		rank := int(math.Ceil(p/100*float64(len(speeds)))) - 1
+		if rank < 0 {
+			rank = 0
+		}
+		return int(speeds[rank])
+	}
+
+	ret.SpeedP5 = percentile(5)
+	ret.SpeedP50 = percentile(50)
+	ret.SpeedP95 = percentile(95)
+	ret.SpeedMax = int(speeds[len(speeds)-1])
+
+	if strokes > 0 {
+		ret.AverageStrokeLength = strokeTotal / float64(strokes)
+	}
+
+	if durationMilli > 0 {
+		ret.Coverage = math.Min(float64(covered)/float64(durationMilli), 1)
+	}
+
+	return ret
+}
+
 func (gt GradientTable) GetInterpolatedColorFor(t float64) colorful.Color {
 	for i := 0; i < len(gt)-1; i++ {
 		c1 := gt[i]
@@ -217,70 +428,88 @@ func (gt GradientTable) GetInterpolatedColorFor(t float64) colorful.Color {
 	return gt[len(gt)-1].Col
 }
 
-func (funscript Script) getGradientTable(numSegments int) GradientTable {
-	segments := make([]struct {
-		count     int
-		intensity int
-	}, numSegments)
-	gradient := make(GradientTable, numSegments)
-
+func (funscript Script) getGradientTable(numSegments int, segmentColor HeatmapColormap) GradientTable {
 	maxts := funscript.Actions[len(funscript.Actions)-1].At
+	return funscript.getGradientTableUntil(numSegments, maxts, segmentColor)
+}
+
+// heatmapSegment holds the totals of the actions within a segment of the
+// heatmap.
+type heatmapSegment struct {
+	count     int
+	intensity int
+	speed     float64
+	minPos    int
+	maxPos    int
+}
+
+func (s heatmapSegment) averageIntensity() float64 {
+	if s.count == 0 {
+		return 0
+	}
+	return float64(s.intensity) / float64(s.count)
+}
+
+func (s heatmapSegment) averageSpeed() float64 {
+	if s.count == 0 {
+		return 0
+	}
+	return s.speed / float64(s.count)
+}
+
+// getSegmentsUntil divides the actions of the script into numSegments
+// segments, scaled so that the last segment ends at maxts.
+func (funscript Script) getSegmentsUntil(numSegments int, maxts int64) []heatmapSegment {
+	segments := make([]heatmapSegment, numSegments)
 
 	for _, a := range funscript.Actions {
 		segment := int(float64(a.At) / float64(maxts+1) * float64(numSegments))
 		// #3181 - sanity check. Clamp segment to numSegments-1
+		// axis actions may also occur after the end of the main script
 		if segment >= numSegments {
 			segment = numSegments - 1
 		}
-		segments[segment].count++
-		segments[segment].intensity += int(a.Intensity)
+
+		s := &segments[segment]
+		if s.count == 0 || a.Pos < s.minPos {
+			s.minPos = a.Pos
+		}
+		if s.count == 0 || a.Pos > s.maxPos {
+			s.maxPos = a.Pos
+		}
+		s.count++
+		s.intensity += int(a.Intensity)
+		s.speed += a.Speed
 	}
 
-	for i := 0; i < numSegments; i++ {
+	return segments
+}
+
+// getGradientTableUntil returns the gradient table for the actions of the
+// script, scaled so that the table ends at maxts.
+func (funscript Script) getGradientTableUntil(numSegments int, maxts int64, segmentColor HeatmapColormap) GradientTable {
+	segments := funscript.getSegmentsUntil(numSegments, maxts)
+	gradient := make(GradientTable, numSegments)
+
+	for i, s := range segments {
 		gradient[i].Pos = float64(i) / float64(numSegments-1)
-		if segments[i].count > 0 {
-			gradient[i].Col = getSegmentColor(float64(segments[i].intensity) / float64(segments[i].count))
-		} else {
-			gradient[i].Col = getSegmentColor(0.0)
-		}
+		gradient[i].Col = segmentColor(s.averageIntensity())
 	}
 
 	return gradient
 }
 
-func getSegmentColor(intensity float64) colorful.Color {
-	colorBlue, _ := colorful.Hex("#1e90ff")   // DodgerBlue
-	colorGreen, _ := colorful.Hex("#228b22")  // ForestGreen
-	colorYellow, _ := colorful.Hex("#ffd700") // Gold
-	colorRed, _ := colorful.Hex("#dc143c")    // Crimson
-	colorPurple, _ := colorful.Hex("#800080") // Purple
-	colorBlack, _ := colorful.Hex("#0f001e")
-	colorBackground, _ := colorful.Hex("#30404d") // Same as GridCard bg
-
-	var stepSize = 60.0
-	var f float64
-	var c colorful.Color
-
-	switch {
-	case intensity <= 0.001:
-		c = colorBackground
-	case intensity <= 1*stepSize:
-		f = (intensity - 0*stepSize) / stepSize
-		c = colorBlue.BlendLab(colorGreen, f)
-	case intensity <= 2*stepSize:
-		f = (intensity - 1*stepSize) / stepSize
-		c = colorGreen.BlendLab(colorYellow, f)
-	case intensity <= 3*stepSize:
-		f = (intensity - 2*stepSize) / stepSize
-		c = colorYellow.BlendLab(colorRed, f)
-	case intensity <= 4*stepSize:
-		f = (intensity - 3*stepSize) / stepSize
-		c = colorRed.BlendRgb(colorPurple, f)
-	default:
-		f = (intensity - 4*stepSize) / (5 * stepSize)
-		f = math.Min(f, 1.0)
-		c = colorPurple.BlendLab(colorBlack, f)
-	}
-
-	return c
+// segmentColor returns the color of a segment with the provided average
+// intensity.
+func (g *InteractiveHeatmapSpeedGenerator) segmentColor(intensity float64) colorful.Color {
+	if intensity <= 0.001 {
+		return g.BackgroundColor
+	}
+
+	colormap := g.Colormap
+	if colormap == nil {
+		colormap = defaultHeatmapColormap
+	}
+
+	return colormap(intensity)
 }
@@ -0,0 +1,123 @@
+package manager
+
+import (
+	"bytes"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testFunscript = `{"version":"1.0","actions":[{"at":0,"pos":0},{"at":500,"pos":100},{"at":1000,"pos":0},{"at":1500,"pos":100}]}`
+
+func TestInteractiveHeatmapSpeedGeneratorAxes(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, data string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("scene.funscript", testFunscript)
+	write("scene.roll.funscript", testFunscript)
+	write("scene.twist.funscript", `{"actions":[]}`)
+	write("scene.sway.funscript", `not json`)
+
+	heatmapPath := filepath.Join(dir, "heatmap.png")
+	g := NewInteractiveHeatmapSpeedGenerator(filepath.Join(dir, "scene.funscript"), heatmapPath, 2)
+	g.RenderAxes = true
+
+	if err := g.Generate(); err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	// invalid and empty axis scripts are ignored
+	if assert.Len(t, g.Axes, 1) {
+		assert.Equal(t, "roll", g.Axes[0].Axis)
+		assert.Equal(t, g.InteractiveSpeed, g.Axes[0].InteractiveSpeed)
+	}
+
+	f, err := os.Open(heatmapPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	img, err := png.Decode(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, g.Height+g.AxisHeight, img.Bounds().Dy())
+}
+
+func TestInteractiveHeatmapSpeedGeneratorGenerateFrom(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  string
+		isCSV bool
+	}{
+		{"funscript", testFunscript, false},
+		{"csv", "0,0\n500,100\n1000,0\n1500,100\n", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewInteractiveHeatmapSpeedGenerator("", "", 2)
+
+			var b bytes.Buffer
+			if err := g.GenerateFrom(strings.NewReader(tt.data), tt.isCSV, &b); err != nil {
+				t.Fatalf("GenerateFrom() error = %v", err)
+			}
+
+			img, err := png.Decode(&b)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, g.Width, img.Bounds().Dx())
+			assert.Equal(t, g.Height, img.Bounds().Dy())
+			assert.Equal(t, 200, g.InteractiveSpeed)
+			assert.Equal(t, 4, g.Stats.ActionCount)
+
+			// calculating the speeds does not reorder the actions
+			assert.Equal(t, int64(1500), g.Funscript.Actions[len(g.Funscript.Actions)-1].At)
+		})
+	}
+
+	g := NewInteractiveHeatmapSpeedGenerator("", "", 2)
+	assert.Error(t, g.GenerateFrom(strings.NewReader(`{"actions":[]}`), false, io.Discard))
+	assert.Error(t, g.GenerateFrom(strings.NewReader(`{}`), false, io.Discard))
+}
+
+func TestScriptCalculateStats(t *testing.T) {
+	script := Script{
+		Actions: []Action{
+			{At: 0, Pos: 0},
+			{At: 500, Pos: 100},
+			{At: 1000, Pos: 50},
+			{At: 1250, Pos: 100},
+			// pause
+			{At: 10000, Pos: 100},
+			{At: 11000, Pos: 0},
+		},
+	}
+	script.UpdateIntensityAndSpeed()
+
+	got := script.CalculateStats(20000)
+
+	assert.Equal(t, 6, got.ActionCount)
+	assert.Equal(t, 0, got.SpeedP5)
+	assert.Equal(t, 100, got.SpeedP50)
+	assert.Equal(t, 200, got.SpeedP95)
+	assert.Equal(t, 200, got.SpeedMax)
+	assert.InDelta(t, 75, got.AverageStrokeLength, 0.001)
+	assert.InDelta(t, 0.1125, got.Coverage, 0.0001)
+
+	empty := Script{}
+	assert.Equal(t, 0, empty.CalculateStats(20000).ActionCount)
+}
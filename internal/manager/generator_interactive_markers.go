@@ -0,0 +1,111 @@
+package manager
+
+// Default options for detecting high intensity sections of funscripts.
+const (
+	// 5 seconds
+	DefaultIntensityPeakWindow = 5000
+	// same as the start of the red band of the default heatmap colormap
+	DefaultIntensityPeakMinIntensity = 3 * heatmapStepSize
+	// 30 seconds
+	DefaultIntensityPeakMinDuration = 30000
+	// 10 seconds
+	DefaultIntensityPeakMaxGap = 10000
+)
+
+// IntensityPeakOptions are the options used to detect sustained high
+// intensity sections of a funscript. Durations are in milliseconds.
+type IntensityPeakOptions struct {
+	// Length of the windows that the intensity is averaged over
+	Window int64
+	// Minimum average intensity of a high intensity window
+	MinIntensity float64
+	// Minimum duration of a high intensity section
+	MinDuration int64
+	// High intensity windows separated by less than this gap are merged
+	// into a single section
+	MaxGap int64
+}
+
+func DefaultIntensityPeakOptions() IntensityPeakOptions {
+	return IntensityPeakOptions{
+		Window:       DefaultIntensityPeakWindow,
+		MinIntensity: DefaultIntensityPeakMinIntensity,
+		MinDuration:  DefaultIntensityPeakMinDuration,
+		MaxGap:       DefaultIntensityPeakMaxGap,
+	}
+}
+
+// IntensityPeak is a sustained high intensity section of a funscript.
+type IntensityPeak struct {
+	// Start time in milliseconds
+	Start int64
+	// End time in milliseconds
+	End int64
+	// Average intensity of the high intensity windows of the section
+	Intensity float64
+}
+
+// FindIntensityPeaks returns the sustained high intensity sections of the
+// script, in order. The script must have its intensity and speed updated
+// first.
+func (funscript Script) FindIntensityPeaks(options IntensityPeakOptions) []IntensityPeak {
+	if len(funscript.Actions) == 0 || options.Window <= 0 {
+		return nil
+	}
+
+	maxts := funscript.Actions[len(funscript.Actions)-1].At
+	numWindows := int(maxts/options.Window) + 1
+	windows := make([]heatmapSegment, numWindows)
+
+	for _, a := range funscript.Actions {
+		w := &windows[a.At/options.Window]
+		w.count++
+		w.intensity += int(a.Intensity)
+	}
+
+	var ret []IntensityPeak
+	var current *IntensityPeak
+	var count, intensity int
+
+	finish := func() {
+		if current == nil {
+			return
+		}
+
+		if current.End > maxts {
+			current.End = maxts
+		}
+		if current.End-current.Start >= options.MinDuration {
+			current.Intensity = float64(intensity) / float64(count)
+			ret = append(ret, *current)
+		}
+		current = nil
+	}
+
+	for i, w := range windows {
+		if w.count == 0 || w.averageIntensity() < options.MinIntensity {
+			continue
+		}
+
+		start := int64(i) * options.Window
+		end := start + options.Window
+
+		if current != nil && start-current.End > options.MaxGap {
+			finish()
+		}
+
+		if current == nil {
+			current = &IntensityPeak{Start: start}
+			count = 0
+			intensity = 0
+		}
+
+		current.End = end
+		count += w.count
+		intensity += w.intensity
+	}
+
+	finish()
+
+	return ret
+}
@@ -0,0 +1,105 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// strokes appends full strokes every interval milliseconds, until the
+// provided time.
+func strokes(actions []Action, until int64, interval int64) []Action {
+	at := int64(0)
+	pos := 0
+	if len(actions) > 0 {
+		last := actions[len(actions)-1]
+		at = last.At + interval
+		pos = 100 - last.Pos
+	}
+
+	for ; at < until; at += interval {
+		actions = append(actions, Action{At: at, Pos: pos})
+		pos = 100 - pos
+	}
+
+	return actions
+}
+
+func TestScriptFindIntensityPeaks(t *testing.T) {
+	const (
+		// intensity 200
+		fast = 250
+		// intensity 50
+		slow = 1000
+	)
+
+	tests := []struct {
+		name    string
+		actions func() []Action
+		want    []IntensityPeak
+	}{
+		{
+			"single peak",
+			func() []Action {
+				a := strokes(nil, 60000, fast)
+				return strokes(a, 120000, slow)
+			},
+			[]IntensityPeak{{Start: 0, End: 60000}},
+		},
+		{
+			"too short",
+			func() []Action {
+				a := strokes(nil, 60000, slow)
+				a = strokes(a, 80000, fast)
+				return strokes(a, 120000, slow)
+			},
+			nil,
+		},
+		{
+			"short gap merged",
+			func() []Action {
+				a := strokes(nil, 20000, fast)
+				a = strokes(a, 25000, slow)
+				a = strokes(a, 45000, fast)
+				return strokes(a, 120000, slow)
+			},
+			[]IntensityPeak{{Start: 0, End: 45000}},
+		},
+		{
+			"long gap split",
+			func() []Action {
+				a := strokes(nil, 40000, fast)
+				a = strokes(a, 80000, slow)
+				a = strokes(a, 120000, fast)
+				return strokes(a, 180000, slow)
+			},
+			[]IntensityPeak{{Start: 0, End: 40000}, {Start: 80000, End: 120000}},
+		},
+		{
+			"ends at last action",
+			func() []Action {
+				a := strokes(nil, 60000, slow)
+				return strokes(a, 100000, fast)
+			},
+			[]IntensityPeak{{Start: 60000, End: 99750}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			script := Script{Actions: tt.actions()}
+			script.UpdateIntensityAndSpeed()
+
+			got := script.FindIntensityPeaks(DefaultIntensityPeakOptions())
+
+			if !assert.Len(t, got, len(tt.want)) {
+				return
+			}
+			for i, p := range got {
+				assert.Equal(t, tt.want[i].Start, p.Start)
+				assert.Equal(t, tt.want[i].End, p.End)
+				assert.GreaterOrEqual(t, p.Intensity, DefaultIntensityPeakMinIntensity)
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package manager
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/file/video"
+	funscriptpkg "github.com/stashapp/stash/pkg/funscript"
+)
+
+const (
+	// vorzeTimeUnit is the duration in milliseconds of the time unit of
+	// Vorze scripts
+	vorzeTimeUnit = 100
+	// vorzeFastestStroke is the duration in milliseconds of a full stroke at
+	// the maximum Vorze speed
+	vorzeFastestStroke = 200
+	vorzeMaxSpeed      = 100
+)
+
+// ReadInteractiveScript reads the interactive script at path. Funscripts and
+// CSV scripts are supported, and are distinguished by the extension of the
+// file.
+func ReadInteractiveScript(path string) (Script, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Script{}, err
+	}
+	defer f.Close()
+
+	funscript, err := ParseInteractiveScript(f, IsCSVScriptPath(path))
+	if err != nil {
+		return Script{}, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	return funscript, nil
+}
+
+// IsCSVScriptPath returns true if the interactive script at path is a CSV
+// script.
+func IsCSVScriptPath(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), video.CSVScriptExtension)
+}
+
+// ParseInteractiveScript parses the interactive script read from r. If isCSV is
+// true the script is parsed as a CSV script, otherwise as a funscript.
+func ParseInteractiveScript(r io.Reader, isCSV bool) (Script, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return Script{}, err
+	}
+
+	if isCSV {
+		return ParseCSVScript(data)
+	}
+
+	var funscript Script
+	if err := json.Unmarshal(data, &funscript); err != nil {
+		return Script{}, err
+	}
+
+	if funscript.Actions == nil {
+		return Script{}, errors.New("actions list missing")
+	}
+
+	return funscript, nil
+}
+
+// ParseCSVScript parses a CSV interactive script. Rows of two columns are
+// time in milliseconds and position from 0 to 100. Rows of three columns are
+// Vorze rows of time in tenths of a second, rotation direction and speed
+// from 0 to 100. A header row is ignored.
+//
+// Vorze scripts drive rotating devices, so they are converted to strokes at
+// a rate proportional to the speed. The rotation direction is not
+// represented.
+func ParseCSVScript(data []byte) (Script, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	r.Comment = '#'
+
+	var rows [][]int64
+	columns := 0
+	for line := 1; ; line++ {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return Script{}, err
+		}
+
+		row, err := parseCSVScriptRow(record)
+		if err != nil {
+			// allow a header row
+			if len(rows) == 0 && line == 1 {
+				continue
+			}
+			return Script{}, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		if columns == 0 {
+			columns = len(row)
+		} else if len(row) != columns {
+			return Script{}, fmt.Errorf("line %d: expected %d columns, found %d", line, columns, len(row))
+		}
+
+		rows = append(rows, row)
+	}
+
+	if columns == 3 {
+		return vorzeToScript(rows), nil
+	}
+
+	ret := Script{
+		Actions: make([]Action, len(rows)),
+	}
+	for i, row := range rows {
+		if row[1] < 0 || row[1] > 100 {
+			return Script{}, fmt.Errorf("position %d out of range at %dms", row[1], row[0])
+		}
+		ret.Actions[i] = Action{At: row[0], Pos: int(row[1])}
+	}
+
+	return ret, nil
+}
+
+func parseCSVScriptRow(record []string) ([]int64, error) {
+	if len(record) != 2 && len(record) != 3 {
+		return nil, fmt.Errorf("expected 2 or 3 columns, found %d", len(record))
+	}
+
+	ret := make([]int64, len(record))
+	for i, v := range record {
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value %q", v)
+		}
+		ret[i] = int64(f)
+	}
+
+	return ret, nil
+}
+
+// vorzeToScript converts Vorze rows into strokes. Each row sets the speed
+// until the next row. The script ends at the last row.
+func vorzeToScript(rows [][]int64) Script {
+	ret := Script{
+		Actions: []Action{},
+	}
+
+	pos := 0
+	addAction := func(at int64) {
+		ret.Actions = append(ret.Actions, Action{At: at, Pos: pos})
+	}
+
+	for i, row := range rows {
+		start := row[0] * vorzeTimeUnit
+		speed := row[2]
+		if speed > vorzeMaxSpeed {
+			speed = vorzeMaxSpeed
+		}
+
+		if i == len(rows)-1 || speed <= 0 {
+			// hold the current position
+			addAction(start)
+			continue
+		}
+
+		end := rows[i+1][0] * vorzeTimeUnit
+		interval := vorzeFastestStroke * vorzeMaxSpeed / speed
+		for at := start; at < end; at += interval {
+			addAction(at)
+			pos = 100 - pos
+		}
+	}
+
+	return ret
+}
+
+// MarshalFunscript returns the script in the funscript format, for devices
+// and players which only support funscripts. Values calculated from the
+// actions are not included.
+func (funscript Script) MarshalFunscript() ([]byte, error) {
+	out := funscriptpkg.Script{
+		Version:  funscript.Version,
+		Inverted: funscript.Inverted,
+		Range:    funscript.Range,
+		Actions:  make([]funscriptpkg.Action, len(funscript.Actions)),
+	}
+
+	if out.Version == "" {
+		out.Version = "1.0"
+	}
+	if out.Range == 0 {
+		out.Range = 100
+	}
+
+	for i, a := range funscript.Actions {
+		out.Actions[i] = funscriptpkg.Action{At: a.At, Pos: a.Pos}
+	}
+
+	return json.Marshal(out)
+}
@@ -0,0 +1,89 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCSVScript(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    []Action
+		wantErr bool
+	}{
+		{
+			"positions",
+			"0,0\n500,100\n1000,50\n",
+			[]Action{{At: 0, Pos: 0}, {At: 500, Pos: 100}, {At: 1000, Pos: 50}},
+			false,
+		},
+		{
+			"header and comments",
+			"time,position\n# comment\n0, 10\n250, 90\n",
+			[]Action{{At: 0, Pos: 10}, {At: 250, Pos: 90}},
+			false,
+		},
+		{
+			"vorze",
+			// full speed for 0.5 seconds, stopped for a second, then half
+			// speed until the end at 2.5 seconds
+			"0,0,100\n5,1,0\n15,0,50\n25,0,0\n",
+			[]Action{
+				{At: 0, Pos: 0},
+				{At: 200, Pos: 100},
+				{At: 400, Pos: 0},
+				{At: 500, Pos: 100},
+				{At: 1500, Pos: 100},
+				{At: 1900, Pos: 0},
+				{At: 2300, Pos: 100},
+				{At: 2500, Pos: 0},
+			},
+			false,
+		},
+		{
+			"position out of range",
+			"0,0\n500,101\n",
+			nil,
+			true,
+		},
+		{
+			"invalid value",
+			"0,0\n500,a\n",
+			nil,
+			true,
+		},
+		{
+			"mixed columns",
+			"0,0\n5,1,50\n",
+			nil,
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCSVScript([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseCSVScript() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !tt.wantErr {
+				assert.Equal(t, tt.want, got.Actions)
+			}
+		})
+	}
+}
+
+func TestScriptMarshalFunscript(t *testing.T) {
+	script := Script{
+		Actions: []Action{{At: 0, Pos: 0, Intensity: 10}, {At: 500, Pos: 100, Speed: 200}},
+	}
+
+	got, err := script.MarshalFunscript()
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{"version":"1.0","inverted":false,"range":100,"actions":[{"at":0,"pos":0},{"at":500,"pos":100}]}`, string(got))
+	}
+}
@@ -27,6 +27,9 @@ type SpriteGenerator struct {
 
 	Overwrite bool
 
+	// Heatmap renders a band below each thumbnail if set
+	Heatmap *InteractiveHeatmapSpeedGenerator
+
 	g *generate.Generator
 }
 
@@ -138,6 +141,16 @@ func (g *SpriteGenerator) generateSpriteImage() error {
 		return fmt.Errorf("images slice is empty, failed to generate sprite images for %s", g.Info.VideoFile.Path)
 	}
 
+	if g.Heatmap != nil {
+		// the sprite is still useful without the heatmap
+		bands, err := g.Heatmap.SpriteBands(len(images), images[0].Bounds().Dx(), spriteHeatmapHeight)
+		if err != nil {
+			logger.Warnf("[generator] not rendering heatmap in sprite for %s: %v", g.Info.VideoFile.Path, err)
+		} else {
+			images = appendSpriteBands(images, bands)
+		}
+	}
+
 	return imaging.Save(g.g.CombineSpriteImages(images), g.ImageOutputPath)
 }
 
@@ -0,0 +1,78 @@
+package manager
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+
+	"github.com/disintegration/imaging"
+)
+
+// spriteHeatmapHeight is the height in pixels of the heatmap band rendered
+// below each thumbnail of the scrubber sprite.
+const spriteHeatmapHeight = 8
+
+// SpriteBands renders the heatmap of each of count equal parts of the scene,
+// to be rendered below the thumbnails of the scrubber sprite.
+func (g *InteractiveHeatmapSpeedGenerator) SpriteBands(count int, width int, height int) ([]image.Image, error) {
+	if g.sceneDurationMilli <= 0 {
+		return nil, fmt.Errorf("invalid scene duration")
+	}
+
+	funscript, err := g.LoadFunscriptData(g.FunscriptPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(funscript.Actions) == 0 {
+		return nil, fmt.Errorf("no valid actions in funscript")
+	}
+
+	funscript.UpdateIntensityAndSpeed()
+
+	numSegments := g.NumSegments
+	if numSegments < 2 {
+		numSegments = 2
+	}
+
+	return funscript.spriteBands(count, width, height, g.sceneDurationMilli, numSegments, g.segmentColor), nil
+}
+
+// spriteBands divides the scene into count equal parts and renders the
+// heatmap of each part. The script must have its intensity updated first.
+func (funscript Script) spriteBands(count int, width int, height int, durationMilli int64, numSegments int, segmentColor HeatmapColormap) []image.Image {
+	// the gradient is scaled to the scene duration rather than the last
+	// action so that the bands line up with the thumbnails
+	gradient := funscript.getGradientTableUntil(numSegments, durationMilli, segmentColor)
+
+	ret := make([]image.Image, count)
+	for i := range ret {
+		img := image.NewRGBA(image.Rect(0, 0, width, height))
+		for x := 0; x < width; x++ {
+			t := (float64(i) + float64(x)/float64(width)) / float64(count)
+			c := gradient.GetInterpolatedColorFor(t)
+			draw.Draw(img, image.Rect(x, 0, x+1, height), &image.Uniform{c}, image.Point{}, draw.Src)
+		}
+		ret[i] = img
+	}
+
+	return ret
+}
+
+// appendSpriteBands returns the thumbnails with the band of the same index
+// rendered below each thumbnail.
+func appendSpriteBands(images []image.Image, bands []image.Image) []image.Image {
+	ret := make([]image.Image, len(images))
+	for i, img := range images {
+		size := img.Bounds().Size()
+		band := bands[i]
+
+		combined := imaging.New(size.X, size.Y+band.Bounds().Dy(), color.NRGBA{})
+		combined = imaging.Paste(combined, img, image.Pt(0, 0))
+		combined = imaging.Paste(combined, band, image.Pt(0, size.Y))
+		ret[i] = combined
+	}
+
+	return ret
+}
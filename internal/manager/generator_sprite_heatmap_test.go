@@ -0,0 +1,70 @@
+package manager
+
+import (
+	"image"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInteractiveHeatmapSpeedGeneratorSpriteBands(t *testing.T) {
+	const (
+		count  = 2
+		width  = 10
+		height = 3
+	)
+
+	funscriptPath := filepath.Join(t.TempDir(), "scene.funscript")
+	if err := os.WriteFile(funscriptPath, []byte(testFunscript), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// the script only has actions in the first half of the scene
+	g := NewInteractiveHeatmapSpeedGenerator(funscriptPath, "", 4)
+	bands, err := g.SpriteBands(count, width, height)
+	if err != nil {
+		t.Fatalf("SpriteBands() error = %v", err)
+	}
+
+	if !assert.Len(t, bands, count) {
+		return
+	}
+
+	for _, b := range bands {
+		assert.Equal(t, image.Rect(0, 0, width, height), b.Bounds())
+	}
+
+	// the second half is the background color
+	background := bands[1].At(0, 0)
+	for x := 0; x < width; x++ {
+		assert.Equal(t, background, bands[1].At(x, height-1))
+	}
+
+	hasActions := false
+	for x := 0; x < width; x++ {
+		if bands[0].At(x, 0) != background {
+			hasActions = true
+		}
+	}
+	assert.True(t, hasActions)
+}
+
+func TestAppendSpriteBands(t *testing.T) {
+	images := []image.Image{
+		image.NewRGBA(image.Rect(0, 0, 16, 9)),
+		image.NewRGBA(image.Rect(0, 0, 16, 9)),
+	}
+	bands := []image.Image{
+		image.NewRGBA(image.Rect(0, 0, 16, 2)),
+		image.NewRGBA(image.Rect(0, 0, 16, 2)),
+	}
+
+	got := appendSpriteBands(images, bands)
+	if assert.Len(t, got, 2) {
+		for _, img := range got {
+			assert.Equal(t, image.Pt(16, 11), img.Bounds().Size())
+		}
+	}
+}
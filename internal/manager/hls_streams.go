@@ -0,0 +1,230 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/hash"
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+const (
+	// sessions that are not accessed for this long are removed, along with
+	// their cached segments
+	hlsSessionTimeout = 5 * time.Minute
+
+	hlsCleanupInterval = time.Minute
+
+	hlsSessionIDLength = 16
+)
+
+var ErrHLSSessionNotFound = errors.New("HLS session not found")
+
+// HLSVariants returns the adaptive bitrate HLS variants of the configured
+// bitrate ladder.
+func HLSVariants(c *config.Instance) []ffmpeg.HLSVariant {
+	var ret []ffmpeg.HLSVariant
+	for _, v := range c.GetHLSVariants() {
+		ret = append(ret, ffmpeg.HLSVariant{
+			Name:          v.Resolution.String(),
+			MaxResolution: v.Resolution.GetMaxResolution(),
+			Bitrate:       v.Bitrate,
+		})
+	}
+
+	return ret
+}
+
+type hlsSession struct {
+	sceneID    int
+	dir        string
+	lastAccess time.Time
+
+	// segments being transcoded, keyed by path. The channel is closed when
+	// the transcode finishes.
+	pending map[string]chan struct{}
+}
+
+// HLSStreamManager manages the playback sessions of adaptive bitrate HLS
+// streams. Segments are transcoded when first requested and cached on disk
+// for the lifetime of the session, so that clients switching between
+// variants or seeking back are served from the cache.
+type HLSStreamManager struct {
+	// cacheDir returns the directory containing the session directories
+	cacheDir func() string
+	generate func(ctx context.Context, options ffmpeg.HLSSegmentOptions) error
+
+	mutex    sync.Mutex
+	sessions map[string]*hlsSession
+}
+
+func newHLSStreamManager(cacheDir func() string, generate func(ctx context.Context, options ffmpeg.HLSSegmentOptions) error) *HLSStreamManager {
+	return &HLSStreamManager{
+		cacheDir: cacheDir,
+		generate: generate,
+		sessions: make(map[string]*hlsSession),
+	}
+}
+
+// NewSession starts a playback session of the scene, returning its ID.
+func (m *HLSStreamManager) NewSession(sceneID int) (string, error) {
+	id, err := hash.GenerateRandomKey(hlsSessionIDLength)
+	if err != nil {
+		return "", err
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.sessions[id] = &hlsSession{
+		sceneID:    sceneID,
+		dir:        filepath.Join(m.cacheDir(), id),
+		lastAccess: time.Now(),
+		pending:    make(map[string]chan struct{}),
+	}
+
+	return id, nil
+}
+
+// SessionCount returns the number of active sessions.
+func (m *HLSStreamManager) SessionCount() int {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return len(m.sessions)
+}
+
+// CacheDir returns the directory containing the cached segments.
+func (m *HLSStreamManager) CacheDir() string {
+	return m.cacheDir()
+}
+
+// getSession returns the session with the given ID, updating its last
+// access time. Assumes the lock is held.
+func (m *HLSStreamManager) getSession(id string, sceneID int) (*hlsSession, error) {
+	s := m.sessions[id]
+	if s == nil || s.sceneID != sceneID {
+		return nil, ErrHLSSessionNotFound
+	}
+
+	s.lastAccess = time.Now()
+	return s, nil
+}
+
+// Touch keeps the session of the scene alive. Returns
+// ErrHLSSessionNotFound if the session does not exist or has expired.
+func (m *HLSStreamManager) Touch(id string, sceneID int) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	_, err := m.getSession(id, sceneID)
+	return err
+}
+
+// Segment returns the path of the cached segment of the session, transcoding
+// it first if it is not yet cached. The output path of the options is set by
+// this method.
+func (m *HLSStreamManager) Segment(ctx context.Context, id string, sceneID int, options ffmpeg.HLSSegmentOptions) (string, error) {
+	m.mutex.Lock()
+
+	s, err := m.getSession(id, sceneID)
+	if err != nil {
+		m.mutex.Unlock()
+		return "", err
+	}
+
+	fn := filepath.Join(s.dir, options.Variant.Name, fmt.Sprintf("%d.ts", options.Index))
+
+	// wait for a concurrent transcode of the same segment
+	for {
+		pending := s.pending[fn]
+		if pending == nil {
+			break
+		}
+
+		m.mutex.Unlock()
+		select {
+		case <-pending:
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+		m.mutex.Lock()
+	}
+
+	if exists, _ := fsutil.FileExists(fn); exists {
+		m.mutex.Unlock()
+		return fn, nil
+	}
+
+	done := make(chan struct{})
+	s.pending[fn] = done
+	m.mutex.Unlock()
+
+	err = m.transcodeSegment(ctx, fn, options)
+
+	m.mutex.Lock()
+	delete(s.pending, fn)
+	close(done)
+	m.mutex.Unlock()
+
+	if err != nil {
+		return "", err
+	}
+
+	return fn, nil
+}
+
+func (m *HLSStreamManager) transcodeSegment(ctx context.Context, fn string, options ffmpeg.HLSSegmentOptions) error {
+	if err := fsutil.EnsureDirAll(filepath.Dir(fn)); err != nil {
+		return err
+	}
+
+	// transcode to a temporary file so that partial segments are never served
+	tmpFn := fn + ".tmp"
+	options.OutputPath = tmpFn
+	if err := m.generate(ctx, options); err != nil {
+		_ = os.Remove(tmpFn)
+		return err
+	}
+
+	return os.Rename(tmpFn, fn)
+}
+
+// Cleanup removes the sessions that have not been accessed since the session
+// timeout, deleting their cached segments. Sessions with segments being
+// transcoded are kept.
+func (m *HLSStreamManager) Cleanup(now time.Time) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for id, s := range m.sessions {
+		if len(s.pending) > 0 || now.Sub(s.lastAccess) < hlsSessionTimeout {
+			continue
+		}
+
+		delete(m.sessions, id)
+
+		if err := os.RemoveAll(s.dir); err != nil {
+			logger.Warnf("[stream] error removing HLS session directory %s: %v", s.dir, err)
+		}
+	}
+}
+
+func (m *HLSStreamManager) runCleanup(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-time.After(hlsCleanupInterval):
+			m.Cleanup(now)
+		}
+	}
+}
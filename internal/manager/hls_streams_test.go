@@ -0,0 +1,62 @@
+package manager
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHLSStreamManager(t *testing.T) {
+	const (
+		sceneID      = 1
+		otherSceneID = 2
+	)
+
+	cacheDir := t.TempDir()
+
+	generated := 0
+	m := newHLSStreamManager(func() string {
+		return cacheDir
+	}, func(ctx context.Context, options ffmpeg.HLSSegmentOptions) error {
+		generated++
+		return os.WriteFile(options.OutputPath, []byte("segment"), 0644)
+	})
+
+	ctx := context.Background()
+	options := ffmpeg.HLSSegmentOptions{
+		Index:   3,
+		Variant: ffmpeg.HLSVariant{Name: "STANDARD", MaxResolution: 480, Bitrate: 1000},
+	}
+
+	id, err := m.NewSession(sceneID)
+	assert.Nil(t, err)
+
+	fn, err := m.Segment(ctx, id, sceneID, options)
+	assert.Nil(t, err)
+	assert.FileExists(t, fn)
+
+	// second request is served from the cache
+	cached, err := m.Segment(ctx, id, sceneID, options)
+	assert.Nil(t, err)
+	assert.Equal(t, fn, cached)
+	assert.Equal(t, 1, generated)
+
+	// sessions are specific to the scene
+	_, err = m.Segment(ctx, id, otherSceneID, options)
+	assert.ErrorIs(t, err, ErrHLSSessionNotFound)
+	assert.ErrorIs(t, m.Touch("unknown", sceneID), ErrHLSSessionNotFound)
+
+	// active sessions are kept
+	m.Cleanup(time.Now())
+	assert.Nil(t, m.Touch(id, sceneID))
+	assert.FileExists(t, fn)
+
+	// expired sessions are removed with their segments
+	m.Cleanup(time.Now().Add(hlsSessionTimeout))
+	assert.ErrorIs(t, m.Touch(id, sceneID), ErrHLSSessionNotFound)
+	assert.NoFileExists(t, fn)
+}
@@ -0,0 +1,85 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/stashapp/stash/internal/identify"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/scraper"
+)
+
+// IdentifySourceResult is the result of scraping a scene with an identify
+// source.
+type IdentifySourceResult struct {
+	Source *scraper.Source `json:"source"`
+	// Name of the source
+	Name string `json:"name"`
+	// Scene is nil if the source did not find a match
+	Scene *scraper.ScrapedScene `json:"scene"`
+	// Error scraping the source, if any
+	Error *string `json:"error"`
+}
+
+// CompareIdentifySources scrapes the scene with all of the sources
+// concurrently, without applying the results. The sources of the default
+// identify settings are used if sources is empty. Results are returned in the
+// order of the sources.
+func (s *Manager) CompareIdentifySources(ctx context.Context, sceneID int, sources []*scraper.Source) ([]*IdentifySourceResult, error) {
+	var identifySources []*identify.Source
+	if len(sources) > 0 {
+		for _, src := range sources {
+			identifySources = append(identifySources, &identify.Source{Source: src})
+		}
+	} else if defaults := s.Config.GetDefaultIdentifySettings(); defaults != nil {
+		identifySources = defaults.Sources
+	}
+
+	if len(identifySources) == 0 {
+		return nil, errors.New("no identify sources configured")
+	}
+
+	scraperSources, err := getIdentifySources(identifySources, s.Config.GetStashBoxes())
+	if err != nil {
+		return nil, err
+	}
+
+	ret := scrapeIdentifySources(ctx, sceneID, scraperSources)
+	for i, result := range ret {
+		result.Source = identifySources[i].Source
+	}
+
+	return ret, nil
+}
+
+// scrapeIdentifySources scrapes the scene with the sources concurrently.
+func scrapeIdentifySources(ctx context.Context, sceneID int, sources []identify.ScraperSource) []*IdentifySourceResult {
+	ret := make([]*IdentifySourceResult, len(sources))
+
+	var wg sync.WaitGroup
+	for i, src := range sources {
+		ret[i] = &IdentifySourceResult{
+			Name: src.Name,
+		}
+
+		wg.Add(1)
+		go func(result *IdentifySourceResult, src identify.ScraperSource) {
+			defer wg.Done()
+
+			scene, err := src.Scraper.ScrapeScene(ctx, sceneID)
+			if err != nil {
+				logger.Debugf("Error scraping scene %d with %s: %v", sceneID, src.Name, err)
+				errStr := err.Error()
+				result.Error = &errStr
+				return
+			}
+
+			result.Scene = scene
+		}(ret[i], src)
+	}
+
+	wg.Wait()
+
+	return ret
+}
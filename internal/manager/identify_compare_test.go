@@ -0,0 +1,46 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stashapp/stash/internal/identify"
+	"github.com/stashapp/stash/pkg/scraper"
+)
+
+type testSceneScraper struct {
+	delay time.Duration
+	scene *scraper.ScrapedScene
+	err   error
+}
+
+func (s testSceneScraper) ScrapeScene(ctx context.Context, sceneID int) (*scraper.ScrapedScene, error) {
+	time.Sleep(s.delay)
+	return s.scene, s.err
+}
+
+func TestScrapeIdentifySources(t *testing.T) {
+	title := "title"
+	scene := &scraper.ScrapedScene{Title: &title}
+	scrapeErr := errors.New("scrape error")
+
+	sources := []identify.ScraperSource{
+		// the slowest source is first, to check that the results are ordered
+		{Name: "slow", Scraper: testSceneScraper{delay: 50 * time.Millisecond, scene: scene}},
+		{Name: "no match", Scraper: testSceneScraper{}},
+		{Name: "error", Scraper: testSceneScraper{err: scrapeErr}},
+	}
+
+	got := scrapeIdentifySources(context.Background(), 1, sources)
+
+	errStr := scrapeErr.Error()
+	assert.Equal(t, []*IdentifySourceResult{
+		{Name: "slow", Scene: scene},
+		{Name: "no match"},
+		{Name: "error", Error: &errStr},
+	}, got)
+}
@@ -0,0 +1,95 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/stashapp/stash/internal/autotag"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/match"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+var ErrNotInInbox = errors.New("scene is not in the inbox")
+
+// FindInboxScenes returns the scenes with the provided ids. An error is
+// returned if any scene does not exist or is not in the inbox.
+func FindInboxScenes(ctx context.Context, r models.SceneReader, ids []int) ([]*models.Scene, error) {
+	var ret []*models.Scene
+	for _, id := range ids {
+		s, err := r.Find(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if s == nil {
+			return nil, fmt.Errorf("%w: scene with id %d", models.ErrNotFound, id)
+		}
+
+		if !s.Inbox {
+			return nil, fmt.Errorf("%w: %s", ErrNotInInbox, s.DisplayName())
+		}
+
+		ret = append(ret, s)
+	}
+
+	return ret, nil
+}
+
+// ApproveInboxScenes removes the scenes from the inbox, applying the partial
+// update to each scene. If autoTag is true, the scenes are then auto-tagged
+// with the performers, studio and tags matching their paths. A studio set by
+// the partial update is not replaced. No scenes are approved if any scene is
+// not in the inbox.
+func (s *Manager) ApproveInboxScenes(ctx context.Context, ids []int, partial models.ScenePartial, autoTag bool) ([]*models.Scene, error) {
+	partial.Inbox = models.NewOptionalBool(false)
+
+	var ret []*models.Scene
+	r := s.Repository
+	if err := r.WithTxn(ctx, func(ctx context.Context) error {
+		scenes, err := FindInboxScenes(ctx, r.Scene, ids)
+		if err != nil {
+			return err
+		}
+
+		ret = nil
+		var cache match.Cache
+		for _, existing := range scenes {
+			updated, err := r.Scene.UpdatePartial(ctx, existing.ID, partial)
+			if err != nil {
+				return fmt.Errorf("updating scene %s: %w", existing.DisplayName(), err)
+			}
+
+			if autoTag && updated.Path != "" {
+				if err := autoTagScene(ctx, r, updated, &cache); err != nil {
+					return err
+				}
+			}
+
+			ret = append(ret, updated)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	logger.Infof("Approved %d scenes from the inbox", len(ret))
+
+	return ret, nil
+}
+
+func autoTagScene(ctx context.Context, r Repository, s *models.Scene, cache *match.Cache) error {
+	if err := autotag.ScenePerformers(ctx, s, r.Scene, r.Performer, cache); err != nil {
+		return fmt.Errorf("tagging scene performers for %s: %w", s.DisplayName(), err)
+	}
+	if err := autotag.SceneStudios(ctx, s, r.Scene, r.Studio, cache); err != nil {
+		return fmt.Errorf("tagging scene studio for %s: %w", s.DisplayName(), err)
+	}
+	if err := autotag.SceneTags(ctx, s, r.Scene, r.Tag, cache); err != nil {
+		return fmt.Errorf("tagging scene tags for %s: %w", s.DisplayName(), err)
+	}
+
+	return nil
+}
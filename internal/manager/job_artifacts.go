@@ -0,0 +1,190 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// AddJobArtifact stores the output written by write as an artifact of the
+// job running with the context, under the artifacts directory.
+func (s *Manager) AddJobArtifact(ctx context.Context, name string, contentType string, write func(w io.Writer) error) (*models.JobArtifact, error) {
+	info, _ := job.GetInfo(ctx)
+	return s.addArtifact(ctx, info, name, contentType, write)
+}
+
+func (s *Manager) addArtifact(ctx context.Context, info job.Info, name string, contentType string, write func(w io.Writer) error) (*models.JobArtifact, error) {
+	artifactsDir := s.Paths.Generated.Artifacts
+	if err := fsutil.EnsureDir(artifactsDir); err != nil {
+		return nil, fmt.Errorf("creating artifacts directory: %w", err)
+	}
+
+	// each artifact has its own directory, so that names don't collide
+	dir, err := os.MkdirTemp(artifactsDir, time.Now().Format("20060102-150405")+"-*")
+	if err != nil {
+		return nil, err
+	}
+
+	fn := filepath.Join(dir, filepath.Base(name))
+	size, err := writeArtifactFile(fn, write)
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, fmt.Errorf("writing artifact %s: %w", name, err)
+	}
+
+	relPath, err := filepath.Rel(artifactsDir, fn)
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, err
+	}
+
+	newArtifact := models.JobArtifact{
+		JobID:          info.ID,
+		JobDescription: info.Description,
+		Name:           filepath.Base(name),
+		Path:           relPath,
+		ContentType:    contentType,
+		Size:           size,
+		CreatedAt:      time.Now(),
+	}
+
+	var ret *models.JobArtifact
+	if err := s.Repository.WithTxn(ctx, func(ctx context.Context) error {
+		var err error
+		ret, err = s.Repository.JobArtifact.Create(ctx, newArtifact)
+		return err
+	}); err != nil {
+		_ = os.RemoveAll(dir)
+		return nil, err
+	}
+
+	logger.Infof("Stored job artifact %s", fn)
+	return ret, nil
+}
+
+func writeArtifactFile(fn string, write func(w io.Writer) error) (int64, error) {
+	f, err := os.Create(fn)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := write(f); err != nil {
+		f.Close()
+		return 0, err
+	}
+
+	if err := f.Close(); err != nil {
+		return 0, err
+	}
+
+	stat, err := os.Stat(fn)
+	if err != nil {
+		return 0, err
+	}
+
+	return stat.Size(), nil
+}
+
+// JobArtifactPath returns the absolute path of the artifact file.
+func (s *Manager) JobArtifactPath(a *models.JobArtifact) string {
+	return filepath.Join(s.Paths.Generated.Artifacts, a.Path)
+}
+
+// DestroyJobArtifact deletes the artifact and its file.
+func (s *Manager) DestroyJobArtifact(ctx context.Context, id int) error {
+	var a *models.JobArtifact
+	if err := s.Repository.WithTxn(ctx, func(ctx context.Context) error {
+		qb := s.Repository.JobArtifact
+		var err error
+		a, err = qb.Find(ctx, id)
+		if err != nil {
+			return err
+		}
+		if a == nil {
+			return fmt.Errorf("job artifact with id %d not found", id)
+		}
+
+		return qb.Destroy(ctx, id)
+	}); err != nil {
+		return err
+	}
+
+	s.removeJobArtifactFile(a)
+	return nil
+}
+
+func (s *Manager) removeJobArtifactFile(a *models.JobArtifact) {
+	// remove the directory of the artifact
+	dir := filepath.Dir(s.JobArtifactPath(a))
+	if err := os.RemoveAll(dir); err != nil {
+		logger.Warnf("error removing job artifact %s: %v", dir, err)
+	}
+}
+
+// pruneJobArtifacts deletes the artifacts older than the configured
+// retention period.
+func (s *Manager) pruneJobArtifacts(ctx context.Context) error {
+	days := s.Config.GetJobArtifactRetentionDays()
+	if days <= 0 {
+		return nil
+	}
+
+	before := time.Now().AddDate(0, 0, -days)
+	var artifacts []*models.JobArtifact
+	if err := s.Repository.WithTxn(ctx, func(ctx context.Context) error {
+		qb := s.Repository.JobArtifact
+		var err error
+		artifacts, err = qb.FindCreatedBefore(ctx, before)
+		if err != nil {
+			return err
+		}
+
+		for _, a := range artifacts {
+			if err := qb.Destroy(ctx, a.ID); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	for _, a := range artifacts {
+		s.removeJobArtifactFile(a)
+	}
+
+	if len(artifacts) > 0 {
+		logger.Infof("Deleted %d expired job artifacts", len(artifacts))
+	}
+
+	return nil
+}
+
+// runJobArtifactPruner periodically deletes expired job artifacts. It
+// returns when the context is cancelled.
+func (s *Manager) runJobArtifactPruner(ctx context.Context) {
+	const interval = time.Hour
+
+	for {
+		if !s.Config.IsNewSystem() && s.Database.Ready() == nil {
+			if err := s.pruneJobArtifacts(ctx); err != nil {
+				logger.Errorf("Error deleting expired job artifacts: %v", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
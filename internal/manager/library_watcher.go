@@ -0,0 +1,486 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+const (
+	watchJournalFilename = "watch_journal.json"
+
+	// maximum number of changed paths processed by a single scan and clean
+	watchBatchSize = 500
+)
+
+type watchChangeOp string
+
+const (
+	watchChangeModified watchChangeOp = "modified"
+	watchChangeRemoved  watchChangeOp = "removed"
+)
+
+type watchChange struct {
+	Op watchChangeOp `json:"op"`
+	// Seq is incremented each time the path changes, so that changes made
+	// while a batch is being processed are not discarded with it
+	Seq int64 `json:"seq"`
+}
+
+// changeJournal records the paths changed since they were last processed.
+// The journal is persisted before changes are processed, so that changes are
+// not lost if stash is stopped before processing completes.
+type changeJournal struct {
+	path string
+
+	mutex   sync.Mutex
+	seq     int64
+	changes map[string]watchChange
+}
+
+func newChangeJournal(path string) *changeJournal {
+	return &changeJournal{
+		path:    path,
+		changes: make(map[string]watchChange),
+	}
+}
+
+// loadChangeJournal reads the journal from path. A missing or invalid journal
+// is treated as empty.
+func loadChangeJournal(path string) *changeJournal {
+	ret := newChangeJournal(path)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			logger.Warnf("Could not read library watch journal: %v", err)
+		}
+		return ret
+	}
+
+	if err := json.Unmarshal(data, &ret.changes); err != nil {
+		logger.Warnf("Could not parse library watch journal: %v", err)
+		ret.changes = make(map[string]watchChange)
+	}
+
+	for _, c := range ret.changes {
+		if c.Seq > ret.seq {
+			ret.seq = c.Seq
+		}
+	}
+
+	return ret
+}
+
+// add records a change to path, replacing any earlier change to it.
+func (j *changeJournal) add(path string, op watchChangeOp) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	j.seq++
+	j.changes[path] = watchChange{Op: op, Seq: j.seq}
+}
+
+func (j *changeJournal) len() int {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	return len(j.changes)
+}
+
+// next returns up to n pending changes, ordered by path.
+func (j *changeJournal) next(n int) map[string]watchChange {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	paths := make([]string, 0, len(j.changes))
+	for p := range j.changes {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	if len(paths) > n {
+		paths = paths[:n]
+	}
+
+	ret := make(map[string]watchChange, len(paths))
+	for _, p := range paths {
+		ret[p] = j.changes[p]
+	}
+
+	return ret
+}
+
+// commit removes the processed changes from the journal. Paths that changed
+// again since they were returned by next are kept.
+func (j *changeJournal) commit(processed map[string]watchChange) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	for p, c := range processed {
+		if j.changes[p].Seq == c.Seq {
+			delete(j.changes, p)
+		}
+	}
+}
+
+func (j *changeJournal) save() error {
+	j.mutex.Lock()
+	data, err := json.Marshal(j.changes)
+	j.mutex.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(j.path, data, 0600)
+}
+
+// watchScanPaths returns the paths to scan for the modified paths, removing
+// paths within other modified directories.
+func watchScanPaths(modified []string) []string {
+	sorted := append([]string(nil), modified...)
+	sort.Strings(sorted)
+
+	var ret []string
+	for _, p := range sorted {
+		covered := false
+		for _, r := range ret {
+			if fsutil.IsPathInDir(r, p) {
+				covered = true
+				break
+			}
+		}
+
+		if !covered {
+			ret = append(ret, p)
+		}
+	}
+
+	return ret
+}
+
+// watchCleanPaths returns the folders to clean for the removed paths. The
+// parent folder is cleaned, since the removed path may have been a file.
+func watchCleanPaths(removed []string) []string {
+	parents := make([]string, len(removed))
+	for i, p := range removed {
+		parents[i] = filepath.Dir(p)
+	}
+
+	return watchScanPaths(parents)
+}
+
+// LibraryWatcher watches the stash paths for changes, and scans only the
+// changed files rather than walking every stash path. Changes are recorded
+// in a persistent journal, and processed once no further changes have been
+// made for the configured debounce period.
+type LibraryWatcher struct {
+	manager *Manager
+	journal *changeJournal
+
+	mutex      sync.Mutex
+	cancel     context.CancelFunc
+	stashPaths []string
+	debounce   time.Duration
+	processing bool
+}
+
+func newLibraryWatcher(m *Manager) *LibraryWatcher {
+	return &LibraryWatcher{
+		manager: m,
+	}
+}
+
+// Refresh starts or stops watching the stash paths according to the
+// configuration. Call this when the configuration changes.
+func (w *LibraryWatcher) Refresh() {
+	c := w.manager.Config
+
+	var stashPaths []string
+	// watching every folder of the library uses too much memory on devices
+	// with little RAM
+	if c.GetWatchLibraryEnabled() && !c.GetLowMemoryMode() {
+		for _, s := range c.GetStashPaths() {
+			stashPaths = append(stashPaths, s.Path)
+		}
+	}
+	debounce := time.Duration(c.GetWatchLibraryDebounce()) * time.Second
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.cancel != nil && debounce == w.debounce && stringSlicesEqual(stashPaths, w.stashPaths) {
+		return
+	}
+
+	if w.cancel != nil {
+		w.cancel()
+		w.cancel = nil
+		logger.Info("Stopped watching library for changes")
+	}
+
+	w.stashPaths = stashPaths
+	w.debounce = debounce
+
+	if len(stashPaths) == 0 {
+		return
+	}
+
+	// the config path is not known until the configuration is loaded
+	if w.journal == nil {
+		w.journal = loadChangeJournal(filepath.Join(c.GetConfigPath(), watchJournalFilename))
+	}
+
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		logger.Errorf("Could not watch library for changes: %v", err)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+	go w.run(ctx, fsw, stashPaths, debounce)
+
+	logger.Infof("Watching %d stash paths for changes", len(stashPaths))
+}
+
+func stringSlicesEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// watchRecursive watches the directory and all directories within it, since
+// fsnotify does not watch subdirectories.
+func (w *LibraryWatcher) watchRecursive(fsw *fsnotify.Watcher, path string) {
+	err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			logger.Warnf("Could not watch %s: %v", p, err)
+			return nil
+		}
+
+		if !d.IsDir() {
+			return nil
+		}
+
+		if err := fsw.Add(p); err != nil {
+			// most likely the watch limit has been reached
+			logger.Warnf("Could not watch %s: %v", p, err)
+			return filepath.SkipDir
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		logger.Warnf("Could not watch %s: %v", path, err)
+	}
+}
+
+func (w *LibraryWatcher) run(ctx context.Context, fsw *fsnotify.Watcher, stashPaths []string, debounce time.Duration) {
+	defer fsw.Close()
+
+	// walking large libraries takes a while, so watch in the background
+	for _, p := range stashPaths {
+		w.watchRecursive(fsw, p)
+	}
+
+	timer := time.NewTimer(debounce)
+	// process changes left from a previous run
+	if w.journal.len() == 0 && !timer.Stop() {
+		<-timer.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case event, ok := <-fsw.Events:
+			if !ok {
+				return
+			}
+			if w.handleEvent(fsw, event) {
+				timer.Reset(debounce)
+			}
+		case err, ok := <-fsw.Errors:
+			if !ok {
+				return
+			}
+			logger.Warnf("Error watching library: %v", err)
+		case <-timer.C:
+			if !w.process() {
+				// try again later
+				timer.Reset(debounce)
+			}
+		}
+	}
+}
+
+// handleEvent records the change of the event in the journal. Returns false
+// if the event is not a change of interest.
+func (w *LibraryWatcher) handleEvent(fsw *fsnotify.Watcher, event fsnotify.Event) bool {
+	switch {
+	case event.Op&fsnotify.Create != 0:
+		if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+			w.watchRecursive(fsw, event.Name)
+		}
+		w.journal.add(event.Name, watchChangeModified)
+	case event.Op&fsnotify.Write != 0:
+		w.journal.add(event.Name, watchChangeModified)
+	case event.Op&(fsnotify.Remove|fsnotify.Rename) != 0:
+		// renames are also reported as a create event for the new path
+		w.journal.add(event.Name, watchChangeRemoved)
+	default:
+		return false
+	}
+
+	return true
+}
+
+// process queues a job processing the journalled changes. Returns false if
+// the changes cannot be processed yet.
+func (w *LibraryWatcher) process() bool {
+	if w.journal.len() == 0 {
+		return true
+	}
+
+	if err := w.journal.save(); err != nil {
+		logger.Warnf("Could not save library watch journal: %v", err)
+	}
+
+	m := w.manager
+	if m.Config.IsNewSystem() || m.Database.Ready() != nil {
+		return false
+	}
+
+	if err := m.validateFFMPEG(); err != nil {
+		logger.Warnf("Could not scan library changes: %v", err)
+		return false
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	// the running job processes changes made while it runs, but may be
+	// finishing, so check again later
+	if w.processing {
+		return false
+	}
+	w.processing = true
+
+	// not cancelled with the watcher, so that processing always completes
+	m.JobManager.Add(context.Background(), "Scanning library changes...", job.MakeJobExec(func(ctx context.Context, progress *job.Progress) {
+		defer func() {
+			w.mutex.Lock()
+			w.processing = false
+			w.mutex.Unlock()
+		}()
+
+		w.processChanges(ctx, progress)
+	}))
+
+	return true
+}
+
+// processChanges scans the modified paths and cleans the removed paths of
+// the journal, in batches. Each batch is removed from the journal once it
+// has been processed.
+func (w *LibraryWatcher) processChanges(ctx context.Context, progress *job.Progress) {
+	m := w.manager
+	start := time.Now()
+	count := 0
+
+	for {
+		batch := w.journal.next(watchBatchSize)
+		if len(batch) == 0 {
+			break
+		}
+
+		var modified, removed []string
+		for p, c := range batch {
+			if c.Op == watchChangeRemoved {
+				removed = append(removed, p)
+			} else {
+				modified = append(modified, p)
+			}
+		}
+
+		// scan before cleaning, so that moved files are detected as moves
+		if len(modified) > 0 {
+			var paths []string
+			for _, p := range watchScanPaths(modified) {
+				if stash := getStashFromDirPath(m.Config.GetStashPaths(), p); stash != nil {
+					root, err := m.scanPathRoot(ctx, stash.Path, p)
+					if err != nil {
+						logger.Errorf("Error finding scan root for %s: %v", p, err)
+						return
+					}
+					paths = append(paths, root)
+				}
+			}
+
+			scanInput := ScanMetadataInput{
+				Paths: watchScanPaths(paths),
+			}
+			if opts := m.Config.GetDefaultScanSettings(); opts != nil {
+				scanInput.ScanMetadataOptions = *opts
+			}
+
+			if len(scanInput.Paths) > 0 {
+				scanJob := &ScanJob{
+					scanner:       m.Scanner,
+					input:         scanInput,
+					subscriptions: m.scanSubs,
+				}
+				scanJob.Execute(ctx, progress)
+			}
+		}
+
+		if len(removed) > 0 && !job.IsCancelled(ctx) {
+			j := &cleanJob{
+				cleaner:      m.Cleaner,
+				txnManager:   m.Repository,
+				sceneService: m.SceneService,
+				imageService: m.ImageService,
+				input: CleanMetadataInput{
+					Paths: watchCleanPaths(removed),
+				},
+				scanSubs: m.scanSubs,
+			}
+			j.Execute(ctx, progress)
+		}
+
+		if job.IsCancelled(ctx) {
+			logger.Info("Stopping due to user request")
+			return
+		}
+
+		w.journal.commit(batch)
+		if err := w.journal.save(); err != nil {
+			logger.Warnf("Could not save library watch journal: %v", err)
+		}
+		count += len(batch)
+	}
+
+	logger.Infof("Processed %d library changes (%s)", count, time.Since(start))
+}
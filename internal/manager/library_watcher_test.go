@@ -0,0 +1,70 @@
+package manager
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChangeJournal(t *testing.T) {
+	journalPath := filepath.Join(t.TempDir(), watchJournalFilename)
+	j := loadChangeJournal(journalPath)
+
+	a := filepath.Join("stash", "a.mp4")
+	b := filepath.Join("stash", "b.mp4")
+	c := filepath.Join("stash", "c.mp4")
+
+	j.add(a, watchChangeModified)
+	j.add(b, watchChangeModified)
+	j.add(b, watchChangeRemoved)
+	j.add(c, watchChangeModified)
+
+	batch := j.next(2)
+	assert.Len(t, batch, 2)
+	assert.Equal(t, watchChangeModified, batch[a].Op)
+	assert.Equal(t, watchChangeRemoved, batch[b].Op)
+
+	// changes made while the batch is processed are kept
+	j.add(a, watchChangeRemoved)
+	j.commit(batch)
+
+	assert.Nil(t, j.save())
+
+	loaded := loadChangeJournal(journalPath)
+	remaining := loaded.next(watchBatchSize)
+	assert.Len(t, remaining, 2)
+	assert.Equal(t, watchChangeRemoved, remaining[a].Op)
+	assert.Equal(t, watchChangeModified, remaining[c].Op)
+
+	// sequence numbers continue from the loaded journal
+	loaded.add(c, watchChangeRemoved)
+	assert.Greater(t, loaded.next(watchBatchSize)[c].Seq, remaining[a].Seq)
+}
+
+func TestWatchScanPaths(t *testing.T) {
+	dir := filepath.Join("stash", "dir")
+	modified := []string{
+		filepath.Join(dir, "a.mp4"),
+		dir,
+		filepath.Join("stash", "b.mp4"),
+		filepath.Join("stash", "dir2", "c.mp4"),
+	}
+
+	assert.Equal(t, []string{
+		filepath.Join("stash", "b.mp4"),
+		dir,
+		filepath.Join("stash", "dir2", "c.mp4"),
+	}, watchScanPaths(modified))
+
+	removed := []string{
+		filepath.Join(dir, "a.mp4"),
+		filepath.Join(dir, "sub", "b.mp4"),
+		filepath.Join("stash", "dir2", "c.mp4"),
+	}
+
+	assert.Equal(t, []string{
+		dir,
+		filepath.Join("stash", "dir2"),
+	}, watchCleanPaths(removed))
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"runtime/pprof"
@@ -15,6 +16,7 @@ import (
 
 	"github.com/stashapp/stash/internal/desktop"
 	"github.com/stashapp/stash/internal/dlna"
+	"github.com/stashapp/stash/internal/locale"
 	"github.com/stashapp/stash/internal/log"
 	"github.com/stashapp/stash/internal/manager/config"
 	"github.com/stashapp/stash/pkg/ffmpeg"
@@ -34,7 +36,9 @@ import (
 	"github.com/stashapp/stash/pkg/scraper"
 	"github.com/stashapp/stash/pkg/session"
 	"github.com/stashapp/stash/pkg/sqlite"
+	"github.com/stashapp/stash/pkg/upload"
 	"github.com/stashapp/stash/pkg/utils"
+	"github.com/stashapp/stash/pkg/webhook"
 	"github.com/stashapp/stash/ui"
 
 	// register custom migrations
@@ -42,11 +46,12 @@ import (
 )
 
 type SystemStatus struct {
-	DatabaseSchema *int             `json:"databaseSchema"`
-	DatabasePath   *string          `json:"databasePath"`
-	ConfigPath     *string          `json:"configPath"`
-	AppSchema      int              `json:"appSchema"`
-	Status         SystemStatusEnum `json:"status"`
+	DatabaseSchema    *int             `json:"databaseSchema"`
+	DatabasePath      *string          `json:"databasePath"`
+	ConfigPath        *string          `json:"configPath"`
+	AppSchema         int              `json:"appSchema"`
+	Status            SystemStatusEnum `json:"status"`
+	OfflineStashPaths []string         `json:"offlineStashPaths"`
 }
 
 type SystemStatusEnum string
@@ -113,6 +118,9 @@ type Manager struct {
 
 	ReadLockManager *fsutil.ReadLockManager
 
+	HLSStreams    *HLSStreamManager
+	StreamClients *StreamClientTracker
+
 	SessionStore *session.Store
 
 	JobManager *job.Manager
@@ -120,6 +128,8 @@ type Manager struct {
 	PluginCache  *plugin.Cache
 	ScraperCache *scraper.Cache
 
+	Locales *locale.Catalog
+
 	DownloadStore *DownloadStore
 
 	DLNAService *dlna.Service
@@ -134,12 +144,30 @@ type Manager struct {
 	Scanner *file.Scanner
 	Cleaner *file.Cleaner
 
+	StashPathMonitor *StashPathMonitor
+	LibraryWatcher   *LibraryWatcher
+
+	Webhooks *webhook.Dispatcher
+
 	scanSubs *subscriptionManager
+
+	uploadMutex sync.Mutex
+	uploadStore *upload.Store
 }
 
 var instance *Manager
 var once sync.Once
 
+// localesFS returns the directory of the embedded UI locale files.
+func localesFS() fs.FS {
+	ret, err := fs.Sub(ui.LocalesBox, ui.LocalesDir)
+	if err != nil {
+		panic(err)
+	}
+
+	return ret
+}
+
 func GetInstance() *Manager {
 	if _, err := Initialize(); err != nil {
 		panic(err)
@@ -178,11 +206,14 @@ func initialize() error {
 		ReadLockManager: fsutil.NewReadLockManager(),
 		DownloadStore:   NewDownloadStore(),
 		PluginCache:     plugin.NewCache(cfg),
+		Locales:         locale.NewCatalog(localesFS(), cfg),
 
 		Database:   db,
 		Repository: sqliteRepository(db),
 		Paths:      &emptyPaths,
 
+		StashPathMonitor: newStashPathMonitor(cfg.GetStashPaths),
+
 		scanSubs: &subscriptionManager{},
 	}
 
@@ -209,6 +240,29 @@ func initialize() error {
 	}
 
 	instance.JobManager = initJobManager()
+	instance.initWebhooks()
+
+	// HLS segments are transcoded one at a time in low memory mode
+	hlsLowMemoryLock := make(chan struct{}, 1)
+	instance.HLSStreams = newHLSStreamManager(func() string {
+		return filepath.Join(instance.Paths.Generated.Tmp, "hls")
+	}, func(ctx context.Context, options ffmpeg.HLSSegmentOptions) error {
+		if instance.Config.GetLowMemoryMode() {
+			select {
+			case hlsLowMemoryLock <- struct{}{}:
+				defer func() { <-hlsLowMemoryLock }()
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		lockCtx := instance.ReadLockManager.ReadLock(ctx, options.Input)
+		defer lockCtx.Cancel()
+
+		return instance.FFMPEG.GenerateHLSSegment(lockCtx, options)
+	})
+
+	instance.StreamClients = NewStreamClientTracker()
 
 	sceneServer := SceneServer{
 		TxnManager:       instance.Repository,
@@ -264,6 +318,17 @@ func initialize() error {
 
 	instance.Scanner = makeScanner(db, instance.PluginCache)
 	instance.Cleaner = makeCleaner(db, instance.PluginCache)
+	instance.LibraryWatcher = newLibraryWatcher(instance)
+
+	go instance.runTaskScheduler(context.Background())
+	go instance.runJobArtifactPruner(context.Background())
+	go instance.HLSStreams.runCleanup(context.Background())
+
+	if !cfg.IsNewSystem() && cfg.GetResumeInterruptedJobs() {
+		if err := instance.resumeInterruptedJobs(ctx); err != nil {
+			logger.Warnf("could not resume interrupted jobs: %v", err)
+		}
+	}
 
 	// if DLNA is enabled, start it now
 	if instance.Config.GetDLNADefaultEnabled() {
@@ -322,6 +387,8 @@ func makeScanner(db *sqlite.Database, pluginCache *plugin.Cache) *file.Scanner {
 		},
 		FingerprintCalculator: &fingerprintCalculator{instance.Config},
 		FS:                    &file.OsFS{},
+		OfflineDetector:       instance.StashPathMonitor,
+		CaseSensitivity:       instance.StashPathMonitor,
 	}
 }
 
@@ -337,6 +404,7 @@ func makeCleaner(db *sqlite.Database, pluginCache *plugin.Cache) *file.Cleaner {
 		Handlers: []file.CleanHandler{
 			&cleanHandler{},
 		},
+		OfflineDetector: instance.StashPathMonitor,
 	}
 }
 
@@ -454,6 +522,10 @@ func (s *Manager) PostInit(ctx context.Context) error {
 	*s.Paths = paths.NewPaths(s.Config.GetGeneratedPath())
 	s.RefreshConfig()
 	s.SessionStore = session.NewStore(s.Config)
+	s.SessionStore.SetUserFinder(&userFinder{repository: s.Repository})
+	s.SessionStore.SetRestrictionProfileFinder(&restrictionProfileFinder{repository: s.Repository})
+	s.SessionStore.SetExternalUserStore(&userFinder{repository: s.Repository})
+	s.SessionStore.SetAuthProviders(s.authProviders())
 	s.PluginCache.RegisterSessionStore(s.SessionStore)
 
 	if err := s.PluginCache.LoadPlugins(); err != nil {
@@ -515,6 +587,7 @@ func (s *Manager) initScraperCache() *scraper.Cache {
 		PerformerFinder: s.Repository.Performer,
 		MovieFinder:     s.Repository.Movie,
 		StudioFinder:    s.Repository.Studio,
+		ResultCache:     s.Repository.ScraperCache,
 	})
 
 	if err != nil {
@@ -547,6 +620,10 @@ func (s *Manager) RefreshConfig() {
 			logger.Warnf("could not create directory for Interactive Heatmaps: %v", err)
 		}
 	}
+
+	if s.LibraryWatcher != nil {
+		s.LibraryWatcher.Refresh()
+	}
 }
 
 // RefreshScraperCache refreshes the scraper cache. Call this when scraper
@@ -711,6 +788,8 @@ func (s *Manager) GetSystemStatus() *SystemStatus {
 		AppSchema:      appSchema,
 		Status:         status,
 		ConfigPath:     &configFile,
+
+		OfflineStashPaths: s.StashPathMonitor.OfflinePaths(),
 	}
 }
 
@@ -157,7 +157,7 @@ func (s *Manager) Generate(ctx context.Context, input GenerateMetadataInput) (in
 		input:      input,
 	}
 
-	return s.JobManager.Add(ctx, "Generating...", j), nil
+	return s.JobManager.AddClass(ctx, "Generating...", j, job.ClassCPU), nil
 }
 
 func (s *Manager) GenerateDefaultScreenshot(ctx context.Context, sceneId string) int {
@@ -206,7 +206,7 @@ func (s *Manager) generateScreenshot(ctx context.Context, sceneId string, at *fl
 		logger.Infof("Generate screenshot finished")
 	})
 
-	return s.JobManager.Add(ctx, fmt.Sprintf("Generating screenshot for scene id %s", sceneId), j)
+	return s.JobManager.AddClass(ctx, fmt.Sprintf("Generating screenshot for scene id %s", sceneId), j, job.ClassCPU)
 }
 
 type AutoTagMetadataInput struct {
@@ -416,5 +416,5 @@ func (s *Manager) StashBoxBatchPerformerTag(ctx context.Context, input StashBoxB
 		}
 	})
 
-	return s.JobManager.Add(ctx, "Batch stash-box performer tag...", j)
+	return s.JobManager.AddClass(ctx, "Batch stash-box performer tag...", j, job.ClassMetadata)
 }
@@ -0,0 +1,255 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+var ErrNoCompatibleStream = errors.New("no stream is compatible with the client capabilities")
+
+type ClientCapabilitiesInput struct {
+	// Video codecs the client can decode. For example h264, hevc, vp9, av1
+	VideoCodecs []string `json:"videoCodecs"`
+	// Audio codecs the client can decode. For example aac, mp3, opus
+	AudioCodecs []string `json:"audioCodecs"`
+	// Containers the client can play. For example mp4, webm, mkv
+	Containers []string `json:"containers"`
+	// Client can play HLS streams
+	Hls *bool `json:"hls"`
+	// Maximum resolution the client wants to receive
+	MaxResolution *models.StreamingResolutionEnum `json:"maxResolution"`
+	// Maximum bitrate in bits per second the client wants to receive
+	MaxBitRate *int `json:"maxBitRate"`
+}
+
+type PlaybackDecision struct {
+	// True if the client can play the file without transcoding
+	DirectPlay bool   `json:"direct_play"`
+	URL        string `json:"url"`
+	MimeType   string `json:"mime_type"`
+	// Resolution of the transcode. Null when playing directly
+	Resolution *models.StreamingResolutionEnum `json:"resolution"`
+	// Reasons why the file cannot be played directly
+	Reasons []string `json:"reasons"`
+}
+
+var codecAliases = map[string]string{
+	"avc":      "h264",
+	"avc1":     "h264",
+	"h265":     "hevc",
+	"hvc1":     "hevc",
+	"mkv":      string(ffmpeg.Matroska),
+	"ts":       string(ffmpeg.Mpegts),
+	"m4v":      string(ffmpeg.Mp4),
+	"mp4a":     "aac",
+	"libopus":  "opus",
+	"matroska": string(ffmpeg.Matroska),
+}
+
+func normaliseCodec(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if alias, ok := codecAliases[s]; ok {
+		return alias
+	}
+	return s
+}
+
+func (c ClientCapabilitiesInput) supports(values []string, v string) bool {
+	v = normaliseCodec(v)
+	for _, vv := range values {
+		if normaliseCodec(vv) == v {
+			return true
+		}
+	}
+	return false
+}
+
+func (c ClientCapabilitiesInput) supportsVideo(codec string) bool {
+	return c.supports(c.VideoCodecs, codec)
+}
+
+func (c ClientCapabilitiesInput) supportsAudio(codec string) bool {
+	return c.supports(c.AudioCodecs, codec)
+}
+
+func (c ClientCapabilitiesInput) supportsContainer(container ffmpeg.Container) bool {
+	return c.supports(c.Containers, string(container))
+}
+
+func (c ClientCapabilitiesInput) maxResolution() int {
+	if c.MaxResolution == nil || *c.MaxResolution == models.StreamingResolutionEnumOriginal {
+		return 0
+	}
+
+	return streamingResolutionMin(*c.MaxResolution)
+}
+
+func streamingResolutionMin(res models.StreamingResolutionEnum) int {
+	r := models.ResolutionEnum(res)
+	return r.GetMinResolution()
+}
+
+// directPlayReasons returns the reasons why the client cannot play the
+// provided file directly. Returns nil if the file can be played directly.
+func directPlayReasons(f *file.VideoFile, container ffmpeg.Container, caps ClientCapabilitiesInput) []string {
+	var ret []string
+
+	if !caps.supportsContainer(container) {
+		ret = append(ret, fmt.Sprintf("container %s is not supported", container))
+	}
+
+	if !caps.supportsVideo(f.VideoCodec) {
+		ret = append(ret, fmt.Sprintf("video codec %s is not supported", f.VideoCodec))
+	}
+
+	if f.AudioCodec != "" && !caps.supportsAudio(f.AudioCodec) {
+		ret = append(ret, fmt.Sprintf("audio codec %s is not supported", f.AudioCodec))
+	}
+
+	if max := caps.maxResolution(); max > 0 && f.GetMinResolution() > max {
+		ret = append(ret, fmt.Sprintf("resolution %dp exceeds the maximum of %dp", f.GetMinResolution(), max))
+	}
+
+	if caps.MaxBitRate != nil && *caps.MaxBitRate > 0 && f.BitRate > int64(*caps.MaxBitRate) {
+		ret = append(ret, fmt.Sprintf("bitrate %d exceeds the maximum of %d", f.BitRate, *caps.MaxBitRate))
+	}
+
+	return ret
+}
+
+// transcodeVideoFile returns the properties of the transcode served in place
+// of the original file by the direct stream endpoint.
+func transcodeVideoFile(f *file.VideoFile) *file.VideoFile {
+	ret := *f
+	ret.VideoCodec = "h264"
+	ret.AudioCodec = "aac"
+	ret.Format = string(ffmpeg.Mp4)
+	// the transcode bitrate is unknown
+	ret.BitRate = 0
+	return &ret
+}
+
+var transcodeResolutions = []models.StreamingResolutionEnum{
+	models.StreamingResolutionEnumFourK,
+	models.StreamingResolutionEnumFullHd,
+	models.StreamingResolutionEnumStandardHd,
+	models.StreamingResolutionEnumStandard,
+	models.StreamingResolutionEnumLow,
+}
+
+// transcodeResolution returns the highest streaming resolution permitted by
+// the file, the server and the client.
+func transcodeResolution(f *file.VideoFile, caps ClientCapabilitiesInput, maxStreamingTranscodeSize models.StreamingResolutionEnum) models.StreamingResolutionEnum {
+	clientMax := caps.maxResolution()
+	for _, res := range transcodeResolutions {
+		if clientMax > 0 && streamingResolutionMin(res) > clientMax {
+			continue
+		}
+
+		if includeSceneStreamPath(f, res, maxStreamingTranscodeSize) {
+			return res
+		}
+	}
+
+	// the file is smaller than the lowest streaming resolution
+	return models.StreamingResolutionEnumOriginal
+}
+
+func decidePlayback(f *file.VideoFile, container ffmpeg.Container, hasTranscode bool, caps ClientCapabilitiesInput, streamURL *url.URL, maxStreamingTranscodeSize models.StreamingResolutionEnum) (*PlaybackDecision, error) {
+	direct := f
+	directContainer := container
+	if hasTranscode {
+		direct = transcodeVideoFile(f)
+		directContainer = ffmpeg.Mp4
+	}
+
+	reasons := directPlayReasons(direct, directContainer, caps)
+	if len(reasons) == 0 {
+		mimeType := ffmpeg.MimeMp4
+		switch directContainer {
+		case ffmpeg.Webm:
+			mimeType = ffmpeg.MimeWebm
+		case ffmpeg.Matroska:
+			mimeType = ffmpeg.MimeMkv
+		}
+
+		return &PlaybackDecision{
+			DirectPlay: true,
+			URL:        streamURL.String(),
+			MimeType:   mimeType,
+			Reasons:    []string{},
+		}, nil
+	}
+
+	withSuffix := func(suffix string) *url.URL {
+		urlCopy := *streamURL
+		urlCopy.Path += suffix
+		return &urlCopy
+	}
+
+	supportsAudio := func(codec string) bool {
+		return f.AudioCodec == "" || caps.supportsAudio(codec)
+	}
+
+	// only the audio needs transcoding for matroska files which are
+	// otherwise playable
+	videoOnly := *f
+	videoOnly.AudioCodec = ""
+	if container == ffmpeg.Matroska && supportsAudio("opus") && len(directPlayReasons(&videoOnly, container, caps)) == 0 {
+		return &PlaybackDecision{
+			URL:      withSuffix(".mkv").String(),
+			MimeType: ffmpeg.MimeMkv,
+			Reasons:  reasons,
+		}, nil
+	}
+
+	res := transcodeResolution(f, caps, maxStreamingTranscodeSize)
+
+	var endpoint *SceneStreamEndpoint
+	switch {
+	case caps.supportsContainer(ffmpeg.Mp4) && caps.supportsVideo("h264") && supportsAudio("aac"):
+		endpoint = makeStreamEndpoint(withSuffix(".mp4"), res, ffmpeg.MimeMp4, "")
+	case caps.supportsContainer(ffmpeg.Webm) && caps.supportsVideo("vp9") && supportsAudio("opus"):
+		endpoint = makeStreamEndpoint(withSuffix(".webm"), res, ffmpeg.MimeWebm, "")
+	case caps.Hls != nil && *caps.Hls && caps.supportsVideo("h264") && supportsAudio("aac"):
+		endpoint = makeStreamEndpoint(withSuffix(".m3u8"), res, ffmpeg.MimeHLS, "")
+	default:
+		return nil, ErrNoCompatibleStream
+	}
+
+	return &PlaybackDecision{
+		URL:        endpoint.URL,
+		MimeType:   *endpoint.MimeType,
+		Resolution: &res,
+		Reasons:    reasons,
+	}, nil
+}
+
+// DecidePlayback returns how the client with the provided capabilities should
+// play the primary file of the scene: either directly, or using the most
+// suitable transcode stream.
+func DecidePlayback(scene *models.Scene, directStreamURL *url.URL, caps ClientCapabilitiesInput) (*PlaybackDecision, error) {
+	if scene == nil {
+		return nil, fmt.Errorf("nil scene")
+	}
+
+	pf := scene.Files.Primary()
+	if pf == nil {
+		return nil, fmt.Errorf("scene %d has no files", scene.ID)
+	}
+
+	// don't care if we can't get the container
+	container, _ := GetVideoFileContainer(pf)
+
+	c := config.GetInstance()
+	hasTranscode := HasTranscode(scene, c.GetVideoFileNamingAlgorithm())
+
+	return decidePlayback(pf, container, hasTranscode, caps, directStreamURL, c.GetMaxStreamingTranscodeSize())
+}
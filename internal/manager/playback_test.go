@@ -0,0 +1,97 @@
+package manager
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecidePlayback(t *testing.T) {
+	streamURL, _ := url.Parse("http://localhost:9999/scene/1/stream")
+
+	hevcMkv := &file.VideoFile{
+		BaseFile:   &file.BaseFile{Path: "/stash/a.mkv"},
+		Format:     "matroska",
+		VideoCodec: "hevc",
+		AudioCodec: "dts",
+		Width:      1920,
+		Height:     1080,
+		BitRate:    8000000,
+	}
+
+	h264Mp4 := &file.VideoFile{
+		BaseFile:   &file.BaseFile{Path: "/stash/a.mp4"},
+		Format:     "mp4",
+		VideoCodec: "h264",
+		AudioCodec: "aac",
+		Width:      1920,
+		Height:     1080,
+		BitRate:    8000000,
+	}
+
+	browser := ClientCapabilitiesInput{
+		VideoCodecs: []string{"h264", "vp9"},
+		AudioCodecs: []string{"aac", "opus"},
+		Containers:  []string{"mp4", "webm"},
+	}
+
+	hls := true
+	hlsOnly := ClientCapabilitiesInput{
+		VideoCodecs: []string{"avc"},
+		AudioCodecs: []string{"aac"},
+		Hls:         &hls,
+	}
+
+	tv := ClientCapabilitiesInput{
+		VideoCodecs: []string{"h264", "h265"},
+		AudioCodecs: []string{"aac", "opus"},
+		Containers:  []string{"mkv", "mp4"},
+	}
+
+	standardHD := models.StreamingResolutionEnumStandardHd
+	lowBitRate := 4000000
+	limited := browser
+	limited.MaxResolution = &standardHD
+	limited.MaxBitRate = &lowBitRate
+
+	tests := []struct {
+		name         string
+		f            *file.VideoFile
+		hasTranscode bool
+		caps         ClientCapabilitiesInput
+		wantDirect   bool
+		wantURL      string
+		wantErr      bool
+	}{
+		{"direct play", h264Mp4, false, browser, true, "http://localhost:9999/scene/1/stream", false},
+		{"transcode mp4", hevcMkv, false, browser, false, "http://localhost:9999/scene/1/stream.mp4?resolution=FULL_HD", false},
+		{"existing transcode", hevcMkv, true, browser, true, "http://localhost:9999/scene/1/stream", false},
+		{"hls", hevcMkv, false, hlsOnly, false, "http://localhost:9999/scene/1/stream.m3u8?resolution=FULL_HD", false},
+		{"mkv audio only", hevcMkv, false, tv, false, "http://localhost:9999/scene/1/stream.mkv", false},
+		{"client limits", h264Mp4, false, limited, false, "http://localhost:9999/scene/1/stream.mp4?resolution=STANDARD_HD", false},
+		{"no compatible stream", hevcMkv, false, ClientCapabilitiesInput{VideoCodecs: []string{"av1"}}, false, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			container := ffmpeg.Container(tt.f.Format)
+			got, err := decidePlayback(tt.f, container, tt.hasTranscode, tt.caps, streamURL, models.StreamingResolutionEnumOriginal)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrNoCompatibleStream)
+				return
+			}
+
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			assert.Equal(t, tt.wantDirect, got.DirectPlay)
+			assert.Equal(t, tt.wantURL, got.URL)
+			assert.Equal(t, tt.wantDirect, len(got.Reasons) == 0)
+		})
+	}
+}
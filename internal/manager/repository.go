@@ -38,6 +38,12 @@ type FileReaderWriter interface {
 	file.Finder
 	Query(ctx context.Context, options models.FileQueryOptions) (*models.FileQueryResult, error)
 	GetCaptions(ctx context.Context, fileID file.ID) ([]*models.VideoCaption, error)
+	GetChapters(ctx context.Context, fileID file.ID) ([]file.VideoChapter, error)
+	GetSubtitleTracks(ctx context.Context, fileID file.ID) ([]file.VideoSubtitleTrack, error)
+	GetFunscriptAxes(ctx context.Context, fileID file.ID) ([]*models.FunscriptAxis, error)
+	UpdateFunscriptAxes(ctx context.Context, fileID file.ID, axes []*models.FunscriptAxis) error
+	GetFunscriptStats(ctx context.Context, fileID file.ID) (*models.FunscriptStats, error)
+	UpdateFunscriptStats(ctx context.Context, fileID file.ID, stats *models.FunscriptStats) error
 	IsPrimary(ctx context.Context, fileID file.ID) (bool, error)
 }
 
@@ -49,18 +55,41 @@ type FolderReaderWriter interface {
 type Repository struct {
 	models.TxnManager
 
-	File        FileReaderWriter
-	Folder      FolderReaderWriter
-	Gallery     GalleryReaderWriter
-	Image       ImageReaderWriter
-	Movie       models.MovieReaderWriter
-	Performer   models.PerformerReaderWriter
-	Scene       SceneReaderWriter
-	SceneMarker models.SceneMarkerReaderWriter
-	ScrapedItem models.ScrapedItemReaderWriter
-	Studio      models.StudioReaderWriter
-	Tag         models.TagReaderWriter
-	SavedFilter models.SavedFilterReaderWriter
+	File               FileReaderWriter
+	Folder             FolderReaderWriter
+	Gallery            GalleryReaderWriter
+	Image              ImageReaderWriter
+	Movie              models.MovieReaderWriter
+	Performer          models.PerformerReaderWriter
+	Scene              SceneReaderWriter
+	SceneMarker        models.SceneMarkerReaderWriter
+	ScrapedItem        models.ScrapedItemReaderWriter
+	Studio             models.StudioReaderWriter
+	Tag                models.TagReaderWriter
+	SavedFilter        models.SavedFilterReaderWriter
+	WantedScene        models.WantedSceneReaderWriter
+	ActivityLog        models.ActivityLogReaderWriter
+	PlaybackEvent      models.PlaybackEventReaderWriter
+	SceneFlag          models.SceneFlagReaderWriter
+	SceneVersion       models.SceneVersionReaderWriter
+	BulkOperation      models.BulkOperationReaderWriter
+	TagSuggestion      models.TagSuggestionReaderWriter
+	PlayQueue          models.PlayQueueReaderWriter
+	JobCheckpoint      models.JobCheckpointReaderWriter
+	JobArtifact        models.JobArtifactReaderWriter
+	TrashedScene       models.TrashedSceneReaderWriter
+	CustomField        models.CustomFieldReaderWriter
+	EditHistory        models.EditHistoryReaderWriter
+	User               models.UserReaderWriter
+	RestrictionProfile models.RestrictionProfileReaderWriter
+	Search             models.SearchReader
+	Consistency        models.ConsistencyChecker
+
+	StashBoxFingerprintCache models.StashBoxFingerprintCacheReaderWriter
+	ScraperCache             models.ScraperCacheReaderWriter
+	StashBoxDraft            models.StashBoxDraftReaderWriter
+	StashBoxSync             models.StashBoxSyncReaderWriter
+	OperationJournal         models.OperationJournalReaderWriter
 }
 
 func (r *Repository) WithTxn(ctx context.Context, fn txn.TxnFunc) error {
@@ -79,19 +108,42 @@ func sqliteRepository(d *sqlite.Database) Repository {
 	txnRepo := d.TxnRepository()
 
 	return Repository{
-		TxnManager:  txnRepo,
-		File:        d.File,
-		Folder:      d.Folder,
-		Gallery:     d.Gallery,
-		Image:       d.Image,
-		Movie:       txnRepo.Movie,
-		Performer:   txnRepo.Performer,
-		Scene:       d.Scene,
-		SceneMarker: txnRepo.SceneMarker,
-		ScrapedItem: txnRepo.ScrapedItem,
-		Studio:      txnRepo.Studio,
-		Tag:         txnRepo.Tag,
-		SavedFilter: txnRepo.SavedFilter,
+		TxnManager:         txnRepo,
+		File:               d.File,
+		Folder:             d.Folder,
+		Gallery:            d.Gallery,
+		Image:              d.Image,
+		Movie:              txnRepo.Movie,
+		Performer:          txnRepo.Performer,
+		Scene:              d.Scene,
+		SceneMarker:        txnRepo.SceneMarker,
+		ScrapedItem:        txnRepo.ScrapedItem,
+		Studio:             txnRepo.Studio,
+		Tag:                txnRepo.Tag,
+		SavedFilter:        txnRepo.SavedFilter,
+		WantedScene:        txnRepo.WantedScene,
+		ActivityLog:        txnRepo.ActivityLog,
+		PlaybackEvent:      txnRepo.PlaybackEvent,
+		SceneFlag:          txnRepo.SceneFlag,
+		SceneVersion:       txnRepo.SceneVersion,
+		BulkOperation:      txnRepo.BulkOperation,
+		TagSuggestion:      txnRepo.TagSuggestion,
+		PlayQueue:          txnRepo.PlayQueue,
+		JobCheckpoint:      txnRepo.JobCheckpoint,
+		JobArtifact:        txnRepo.JobArtifact,
+		TrashedScene:       txnRepo.TrashedScene,
+		CustomField:        txnRepo.CustomField,
+		EditHistory:        txnRepo.EditHistory,
+		User:               txnRepo.User,
+		RestrictionProfile: txnRepo.RestrictionProfile,
+		Search:             txnRepo.Search,
+		Consistency:        txnRepo.Consistency,
+
+		StashBoxFingerprintCache: txnRepo.StashBoxFingerprintCache,
+		ScraperCache:             txnRepo.ScraperCache,
+		StashBoxDraft:            txnRepo.StashBoxDraft,
+		StashBoxSync:             txnRepo.StashBoxSync,
+		OperationJournal:         txnRepo.OperationJournal,
 	}
 }
 
@@ -100,6 +152,8 @@ type SceneService interface {
 	AssignFile(ctx context.Context, sceneID int, fileID file.ID) error
 	Merge(ctx context.Context, sourceIDs []int, destinationID int, values models.ScenePartial) error
 	Destroy(ctx context.Context, scene *models.Scene, fileDeleter *scene.FileDeleter, deleteGenerated, deleteFile bool) error
+	Trash(ctx context.Context, scene *models.Scene, fileDeleter *scene.FileDeleter, mover *file.Mover, trashDir string, store scene.TrashCreator, deleteGenerated bool) (*models.TrashedScene, error)
+	RestoreTrashed(ctx context.Context, t *models.TrashedScene, mover *file.Mover) (*models.Scene, error)
 }
 
 type ImageService interface {
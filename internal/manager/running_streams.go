@@ -100,7 +100,7 @@ func (s *SceneServer) StreamSceneDirect(scene *models.Scene, w http.ResponseWrit
 	// We trust that the request context will be closed, so we don't need to call Cancel on the
 	// returned context here.
 	_ = GetInstance().ReadLockManager.ReadLock(streamRequestCtx, filepath)
-	http.ServeFile(w, r, filepath)
+	http.ServeFile(w, r, fsutil.LongPath(filepath))
 }
 
 func (s *SceneServer) ServeScreenshot(scene *models.Scene, w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,146 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/txn"
+)
+
+// ScanDiff summarises the changes made to the library by a scan.
+type ScanDiff struct {
+	NewScenes    []int `json:"new_scenes"`
+	NewFiles     []int `json:"new_files"`
+	UpdatedFiles []int `json:"updated_files"`
+	// MissingFiles are the files in the scanned paths which no longer exist.
+	// They are removed by the clean task.
+	MissingFiles []int `json:"missing_files"`
+}
+
+func (d ScanDiff) String() string {
+	return fmt.Sprintf("%d new scenes, %d new files, %d updated files, %d missing files", len(d.NewScenes), len(d.NewFiles), len(d.UpdatedFiles), len(d.MissingFiles))
+}
+
+// scanDiffHandler is a file handler recording the files created and updated
+// by a scan.
+type scanDiffHandler struct {
+	start time.Time
+
+	mutex        sync.Mutex
+	handled      map[int]struct{}
+	newFiles     map[int]struct{}
+	updatedFiles map[int]struct{}
+}
+
+func newScanDiffHandler() *scanDiffHandler {
+	return &scanDiffHandler{
+		start:        time.Now(),
+		handled:      make(map[int]struct{}),
+		newFiles:     make(map[int]struct{}),
+		updatedFiles: make(map[int]struct{}),
+	}
+}
+
+func (h *scanDiffHandler) Handle(ctx context.Context, f file.File, oldFile file.File) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	id := int(f.Base().ID)
+	h.handled[id] = struct{}{}
+
+	// unchanged files are also handled if their handlers need to be run
+	switch {
+	case oldFile != nil:
+		h.updatedFiles[id] = struct{}{}
+	case !f.Base().CreatedAt.Before(h.start):
+		h.newFiles[id] = struct{}{}
+	}
+
+	return nil
+}
+
+func sortedIDs(m map[int]struct{}) []int {
+	ret := make([]int, 0, len(m))
+	for id := range m {
+		ret = append(ret, id)
+	}
+	sort.Ints(ret)
+	return ret
+}
+
+// diff returns the changes made by the scan of paths, finding the scenes
+// created for the handled files and the files which are missing.
+func (h *scanDiffHandler) diff(ctx context.Context, repo Repository, cleaner *file.Cleaner, paths []string) (*ScanDiff, error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	ret := &ScanDiff{
+		NewFiles:     sortedIDs(h.newFiles),
+		UpdatedFiles: sortedIDs(h.updatedFiles),
+		MissingFiles: []int{},
+	}
+
+	newScenes := make(map[int]struct{})
+	if err := txn.WithReadTxn(ctx, repo, func(ctx context.Context) error {
+		for id := range h.handled {
+			scenes, err := repo.Scene.FindByFileID(ctx, file.ID(id))
+			if err != nil {
+				return fmt.Errorf("finding scenes for file %d: %w", id, err)
+			}
+
+			for _, s := range scenes {
+				if !s.CreatedAt.Before(h.start) {
+					newScenes[s.ID] = struct{}{}
+				}
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	ret.NewScenes = sortedIDs(newScenes)
+
+	missing, err := cleaner.FindMissing(ctx, paths)
+	if err != nil {
+		return nil, fmt.Errorf("finding missing files: %w", err)
+	}
+
+	for _, f := range missing {
+		ret.MissingFiles = append(ret.MissingFiles, int(f.Base().ID))
+	}
+
+	return ret, nil
+}
+
+// reportScanDiff computes the changes made by the scan, storing them as a job
+// artifact and posting them to the scan finished webhooks.
+func (s *Manager) reportScanDiff(ctx context.Context, h *scanDiffHandler, paths []string, elapsed time.Duration) {
+	diff, err := h.diff(ctx, s.Repository, s.Cleaner, paths)
+	if err != nil {
+		logger.Errorf("Error computing scan changes: %v", err)
+		s.dispatchScanFinishedWebhook(paths, elapsed, nil)
+		return
+	}
+
+	logger.Infof("Scan changes: %s", diff)
+
+	name := "scan-" + time.Now().Format("20060102-150405") + ".json"
+	if _, err := s.AddJobArtifact(ctx, name, "application/json", func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(diff)
+	}); err != nil {
+		logger.Errorf("Error storing scan changes: %v", err)
+	}
+
+	s.dispatchScanFinishedWebhook(paths, elapsed, diff)
+}
@@ -0,0 +1,51 @@
+package manager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stashapp/stash/pkg/file"
+)
+
+func TestScanDiffHandler(t *testing.T) {
+	h := newScanDiffHandler()
+	before := h.start.Add(-time.Hour)
+	after := h.start.Add(time.Second)
+
+	newFile := func(id int, createdAt time.Time) file.File {
+		return &file.BaseFile{ID: file.ID(id), CreatedAt: createdAt}
+	}
+
+	tests := []struct {
+		name    string
+		f       file.File
+		oldFile file.File
+	}{
+		{"new", newFile(3, after), nil},
+		{"updated", newFile(2, before), newFile(2, before)},
+		{"unchanged", newFile(1, before), nil},
+		{"new handled twice", newFile(3, after), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.NoError(t, h.Handle(context.Background(), tt.f, tt.oldFile))
+		})
+	}
+
+	assert.Equal(t, []int{3}, sortedIDs(h.newFiles))
+	assert.Equal(t, []int{2}, sortedIDs(h.updatedFiles))
+	assert.Equal(t, []int{1, 2, 3}, sortedIDs(h.handled))
+}
+
+func TestScanDiffString(t *testing.T) {
+	d := ScanDiff{
+		NewScenes:    []int{1, 2},
+		NewFiles:     []int{3, 4, 5},
+		UpdatedFiles: []int{6},
+	}
+
+	assert.Equal(t, "2 new scenes, 3 new files, 1 updated files, 0 missing files", d.String())
+}
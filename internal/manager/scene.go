@@ -191,6 +191,14 @@ func GetSceneStreamPaths(scene *models.Scene, directStreamURL *url.URL, maxStrea
 	}
 	ret = append(ret, &hls)
 
+	labelHLSAdaptive := "HLS (adaptive)"
+	hlsAdaptive := SceneStreamEndpoint{
+		URL:      replaceSuffix("_abr.m3u8").String(),
+		MimeType: &mimeHLS,
+		Label:    &labelHLSAdaptive,
+	}
+	ret = append(ret, &hlsAdaptive)
+
 	return ret, nil
 }
 
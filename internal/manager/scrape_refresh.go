@@ -0,0 +1,411 @@
+package manager
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stashapp/stash/internal/identify"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
+)
+
+// ScrapeRefreshReport is the change report of a refreshed performer or
+// studio.
+type ScrapeRefreshReport struct {
+	EntityType string `json:"entity_type"`
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	// Source is the stash-box endpoint or URL that was scraped
+	Source string                     `json:"source"`
+	Fields []ScrapeRefreshFieldChange `json:"fields"`
+}
+
+// ScrapeRefreshFieldChange is a changed field. Old and New are not set for
+// images.
+type ScrapeRefreshFieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old,omitempty"`
+	New   string `json:"new,omitempty"`
+}
+
+const (
+	refreshFieldAliases = "aliases"
+	refreshFieldImage   = "image"
+	refreshFieldURL     = "url"
+)
+
+type refreshFieldStrategies map[string]*identify.FieldOptions
+
+func newRefreshFieldStrategies(options []*identify.FieldOptions) refreshFieldStrategies {
+	ret := refreshFieldStrategies{}
+	for _, o := range options {
+		ret[o.Field] = o
+	}
+	return ret
+}
+
+// strategy returns the strategy of the field, defaulting to MERGE.
+func (s refreshFieldStrategies) strategy(field string) identify.FieldStrategy {
+	if o := s[field]; o != nil && o.Strategy.IsValid() {
+		return o.Strategy
+	}
+
+	return identify.FieldStrategyMerge
+}
+
+// shouldSet returns true if a single value field should be set to the
+// scraped value.
+func (s refreshFieldStrategies) shouldSet(field string, hasExisting bool) bool {
+	switch s.strategy(field) {
+	case identify.FieldStrategyIgnore:
+		return false
+	case identify.FieldStrategyOverwrite:
+		return true
+	}
+
+	return !hasExisting
+}
+
+type performerRefreshField struct {
+	name     string
+	existing func(p *models.Performer) string
+	// scraped returns nil if the scraped value is missing or invalid
+	scraped func(s *models.ScrapedPerformer) *string
+	set     func(partial *models.PerformerPartial, v string)
+}
+
+func refreshString(v *string) *string {
+	if v == nil {
+		return nil
+	}
+
+	trimmed := strings.TrimSpace(*v)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
+
+func refreshInt(v *string) *string {
+	v = refreshString(v)
+	if v == nil {
+		return nil
+	}
+
+	if _, err := strconv.Atoi(*v); err != nil {
+		return nil
+	}
+	return v
+}
+
+func refreshDate(v *string) *string {
+	v = refreshString(v)
+	if v == nil {
+		return nil
+	}
+
+	if _, err := time.Parse("2006-01-02", *v); err != nil {
+		return nil
+	}
+	return v
+}
+
+func intString(v *int) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.Itoa(*v)
+}
+
+func dateString(v *models.Date) string {
+	if v == nil {
+		return ""
+	}
+	return v.String()
+}
+
+func stringField(name string, existing func(p *models.Performer) string, scraped func(s *models.ScrapedPerformer) *string, set func(partial *models.PerformerPartial, v models.OptionalString)) performerRefreshField {
+	return performerRefreshField{
+		name:     name,
+		existing: existing,
+		scraped: func(s *models.ScrapedPerformer) *string {
+			return refreshString(scraped(s))
+		},
+		set: func(partial *models.PerformerPartial, v string) {
+			set(partial, models.NewOptionalString(v))
+		},
+	}
+}
+
+func intField(name string, existing func(p *models.Performer) *int, scraped func(s *models.ScrapedPerformer) *string, set func(partial *models.PerformerPartial, v models.OptionalInt)) performerRefreshField {
+	return performerRefreshField{
+		name: name,
+		existing: func(p *models.Performer) string {
+			return intString(existing(p))
+		},
+		scraped: func(s *models.ScrapedPerformer) *string {
+			return refreshInt(scraped(s))
+		},
+		set: func(partial *models.PerformerPartial, v string) {
+			i, _ := strconv.Atoi(v)
+			set(partial, models.NewOptionalInt(i))
+		},
+	}
+}
+
+func dateField(name string, existing func(p *models.Performer) *models.Date, scraped func(s *models.ScrapedPerformer) *string, set func(partial *models.PerformerPartial, v models.OptionalDate)) performerRefreshField {
+	return performerRefreshField{
+		name: name,
+		existing: func(p *models.Performer) string {
+			return dateString(existing(p))
+		},
+		scraped: func(s *models.ScrapedPerformer) *string {
+			return refreshDate(scraped(s))
+		},
+		set: func(partial *models.PerformerPartial, v string) {
+			set(partial, models.NewOptionalDate(models.NewDate(v)))
+		},
+	}
+}
+
+// performerRefreshFields are the single value fields of performers which
+// are refreshed, in the order they are reported.
+var performerRefreshFields = []performerRefreshField{
+	stringField("disambiguation",
+		func(p *models.Performer) string { return p.Disambiguation },
+		func(s *models.ScrapedPerformer) *string { return s.Disambiguation },
+		func(partial *models.PerformerPartial, v models.OptionalString) { partial.Disambiguation = v }),
+	{
+		name:     "gender",
+		existing: func(p *models.Performer) string { return p.Gender.String() },
+		scraped: func(s *models.ScrapedPerformer) *string {
+			v := refreshString(s.Gender)
+			if v == nil {
+				return nil
+			}
+			gender := strings.ToUpper(*v)
+			if !models.GenderEnum(gender).IsValid() {
+				return nil
+			}
+			return &gender
+		},
+		set: func(partial *models.PerformerPartial, v string) { partial.Gender = models.NewOptionalString(v) },
+	},
+	stringField(refreshFieldURL,
+		func(p *models.Performer) string { return p.URL },
+		func(s *models.ScrapedPerformer) *string { return s.URL },
+		func(partial *models.PerformerPartial, v models.OptionalString) { partial.URL = v }),
+	stringField("twitter",
+		func(p *models.Performer) string { return p.Twitter },
+		func(s *models.ScrapedPerformer) *string { return s.Twitter },
+		func(partial *models.PerformerPartial, v models.OptionalString) { partial.Twitter = v }),
+	stringField("instagram",
+		func(p *models.Performer) string { return p.Instagram },
+		func(s *models.ScrapedPerformer) *string { return s.Instagram },
+		func(partial *models.PerformerPartial, v models.OptionalString) { partial.Instagram = v }),
+	dateField("birthdate",
+		func(p *models.Performer) *models.Date { return p.Birthdate },
+		func(s *models.ScrapedPerformer) *string { return s.Birthdate },
+		func(partial *models.PerformerPartial, v models.OptionalDate) { partial.Birthdate = v }),
+	dateField("death_date",
+		func(p *models.Performer) *models.Date { return p.DeathDate },
+		func(s *models.ScrapedPerformer) *string { return s.DeathDate },
+		func(partial *models.PerformerPartial, v models.OptionalDate) { partial.DeathDate = v }),
+	stringField("ethnicity",
+		func(p *models.Performer) string { return p.Ethnicity },
+		func(s *models.ScrapedPerformer) *string { return s.Ethnicity },
+		func(partial *models.PerformerPartial, v models.OptionalString) { partial.Ethnicity = v }),
+	stringField("country",
+		func(p *models.Performer) string { return p.Country },
+		func(s *models.ScrapedPerformer) *string { return s.Country },
+		func(partial *models.PerformerPartial, v models.OptionalString) { partial.Country = v }),
+	stringField("eye_color",
+		func(p *models.Performer) string { return p.EyeColor },
+		func(s *models.ScrapedPerformer) *string { return s.EyeColor },
+		func(partial *models.PerformerPartial, v models.OptionalString) { partial.EyeColor = v }),
+	stringField("hair_color",
+		func(p *models.Performer) string { return p.HairColor },
+		func(s *models.ScrapedPerformer) *string { return s.HairColor },
+		func(partial *models.PerformerPartial, v models.OptionalString) { partial.HairColor = v }),
+	intField("height",
+		func(p *models.Performer) *int { return p.Height },
+		func(s *models.ScrapedPerformer) *string { return s.Height },
+		func(partial *models.PerformerPartial, v models.OptionalInt) { partial.Height = v }),
+	intField("weight",
+		func(p *models.Performer) *int { return p.Weight },
+		func(s *models.ScrapedPerformer) *string { return s.Weight },
+		func(partial *models.PerformerPartial, v models.OptionalInt) { partial.Weight = v }),
+	stringField("measurements",
+		func(p *models.Performer) string { return p.Measurements },
+		func(s *models.ScrapedPerformer) *string { return s.Measurements },
+		func(partial *models.PerformerPartial, v models.OptionalString) { partial.Measurements = v }),
+	stringField("fake_tits",
+		func(p *models.Performer) string { return p.FakeTits },
+		func(s *models.ScrapedPerformer) *string { return s.FakeTits },
+		func(partial *models.PerformerPartial, v models.OptionalString) { partial.FakeTits = v }),
+	stringField("career_length",
+		func(p *models.Performer) string { return p.CareerLength },
+		func(s *models.ScrapedPerformer) *string { return s.CareerLength },
+		func(partial *models.PerformerPartial, v models.OptionalString) { partial.CareerLength = v }),
+	stringField("tattoos",
+		func(p *models.Performer) string { return p.Tattoos },
+		func(s *models.ScrapedPerformer) *string { return s.Tattoos },
+		func(partial *models.PerformerPartial, v models.OptionalString) { partial.Tattoos = v }),
+	stringField("piercings",
+		func(p *models.Performer) string { return p.Piercings },
+		func(s *models.ScrapedPerformer) *string { return s.Piercings },
+		func(partial *models.PerformerPartial, v models.OptionalString) { partial.Piercings = v }),
+	stringField("details",
+		func(p *models.Performer) string { return p.Details },
+		func(s *models.ScrapedPerformer) *string { return s.Details },
+		func(partial *models.PerformerPartial, v models.OptionalString) { partial.Details = v }),
+}
+
+// ScrapeRefreshPerformerFields returns the names of the performer fields
+// which can be refreshed.
+func ScrapeRefreshPerformerFields() []string {
+	ret := []string{refreshFieldAliases, refreshFieldImage}
+	for _, f := range performerRefreshFields {
+		ret = append(ret, f.name)
+	}
+	return ret
+}
+
+// ScrapeRefreshStudioFields returns the names of the studio fields which can
+// be refreshed.
+func ScrapeRefreshStudioFields() []string {
+	return []string{refreshFieldURL, refreshFieldImage}
+}
+
+// performerRefresh returns the changes to make to performer p from the
+// scraped performer. The aliases of p must be loaded. The returned image is
+// the scraped image if it should be set.
+func performerRefresh(p *models.Performer, hasImage bool, scraped *models.ScrapedPerformer, strategies refreshFieldStrategies) (models.PerformerPartial, *string, []ScrapeRefreshFieldChange) {
+	partial := models.NewPerformerPartial()
+	var changes []ScrapeRefreshFieldChange
+
+	for _, f := range performerRefreshFields {
+		v := f.scraped(scraped)
+		if v == nil {
+			continue
+		}
+
+		existing := f.existing(p)
+		if existing == *v || !strategies.shouldSet(f.name, existing != "") {
+			continue
+		}
+
+		f.set(&partial, *v)
+		changes = append(changes, ScrapeRefreshFieldChange{
+			Field: f.name,
+			Old:   existing,
+			New:   *v,
+		})
+	}
+
+	if aliases := refreshAliases(p, scraped, strategies); aliases != nil {
+		partial.Aliases = &models.UpdateStrings{
+			Values: aliases,
+			Mode:   models.RelationshipUpdateModeSet,
+		}
+		changes = append(changes, ScrapeRefreshFieldChange{
+			Field: refreshFieldAliases,
+			Old:   strings.Join(p.Aliases.List(), ", "),
+			New:   strings.Join(aliases, ", "),
+		})
+	}
+
+	var image *string
+	if scraped.Image != nil && strategies.shouldSet(refreshFieldImage, hasImage) {
+		image = scraped.Image
+		changes = append(changes, ScrapeRefreshFieldChange{Field: refreshFieldImage})
+	}
+
+	return partial, image, changes
+}
+
+// refreshAliases returns the new aliases of p, or nil if they are unchanged.
+// Aliases equal to the name of the performer are ignored.
+func refreshAliases(p *models.Performer, scraped *models.ScrapedPerformer, strategies refreshFieldStrategies) []string {
+	if scraped.Aliases == nil {
+		return nil
+	}
+
+	existing := p.Aliases.List()
+	var scrapedAliases []string
+	for _, a := range stringslice.FromString(*scraped.Aliases, ",") {
+		if a != "" && !strings.EqualFold(a, p.Name) {
+			scrapedAliases = append(scrapedAliases, a)
+		}
+	}
+
+	if len(scrapedAliases) == 0 {
+		return nil
+	}
+
+	var ret []string
+	switch strategies.strategy(refreshFieldAliases) {
+	case identify.FieldStrategyIgnore:
+		return nil
+	case identify.FieldStrategySkipIfSet:
+		if len(existing) > 0 {
+			return nil
+		}
+		ret = scrapedAliases
+	case identify.FieldStrategyOverwrite:
+		ret = scrapedAliases
+	default:
+		ret = stringslice.StrAppendUniques(append([]string{}, existing...), scrapedAliases)
+	}
+
+	if stringsEqual(existing, ret) {
+		return nil
+	}
+
+	return ret
+}
+
+func stringsEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// studioRefresh returns the changes to make to studio s from the scraped
+// studio. The returned image is the scraped image if it should be set.
+func studioRefresh(s *models.Studio, hasImage bool, scraped *models.ScrapedStudio, strategies refreshFieldStrategies) (*models.StudioPartial, *string, []ScrapeRefreshFieldChange) {
+	partial := &models.StudioPartial{
+		ID: s.ID,
+	}
+	var changes []ScrapeRefreshFieldChange
+
+	if url := refreshString(scraped.URL); url != nil {
+		existing := s.URL.String
+		if existing != *url && strategies.shouldSet(refreshFieldURL, existing != "") {
+			partial.URL = &sql.NullString{String: *url, Valid: true}
+			changes = append(changes, ScrapeRefreshFieldChange{
+				Field: refreshFieldURL,
+				Old:   existing,
+				New:   *url,
+			})
+		}
+	}
+
+	var image *string
+	if scraped.Image != nil && strategies.shouldSet(refreshFieldImage, hasImage) {
+		image = scraped.Image
+		changes = append(changes, ScrapeRefreshFieldChange{Field: refreshFieldImage})
+	}
+
+	return partial, image, changes
+}
@@ -0,0 +1,163 @@
+package manager
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stashapp/stash/internal/identify"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func refreshStrategies(strategies map[string]identify.FieldStrategy) refreshFieldStrategies {
+	var options []*identify.FieldOptions
+	for field, strategy := range strategies {
+		options = append(options, &identify.FieldOptions{
+			Field:    field,
+			Strategy: strategy,
+		})
+	}
+	return newRefreshFieldStrategies(options)
+}
+
+func TestPerformerRefresh(t *testing.T) {
+	height := 170
+	existing := &models.Performer{
+		Name:    "Name",
+		Gender:  models.GenderEnumFemale,
+		Country: "Country",
+		Height:  &height,
+		Aliases: models.NewRelatedStrings([]string{"Alias"}),
+	}
+
+	newCountry := "New Country"
+	newHeight := "175"
+	newGender := "female"
+	url := "https://example.com"
+	aliases := "Other, name"
+	image := "https://example.com/image.jpg"
+	scraped := &models.ScrapedPerformer{
+		Gender:  &newGender,
+		URL:     &url,
+		Country: &newCountry,
+		Height:  &newHeight,
+		Aliases: &aliases,
+		Image:   &image,
+	}
+
+	tests := []struct {
+		name        string
+		hasImage    bool
+		strategies  map[string]identify.FieldStrategy
+		wantFields  []string
+		wantAliases []string
+		wantImage   bool
+	}{
+		{
+			"merge",
+			true,
+			nil,
+			[]string{"url", "aliases"},
+			[]string{"Alias", "Other"},
+			false,
+		},
+		{
+			"merge without image",
+			false,
+			nil,
+			[]string{"url", "aliases", "image"},
+			[]string{"Alias", "Other"},
+			true,
+		},
+		{
+			"overwrite",
+			true,
+			map[string]identify.FieldStrategy{
+				"country": identify.FieldStrategyOverwrite,
+				"height":  identify.FieldStrategyOverwrite,
+				"aliases": identify.FieldStrategyOverwrite,
+				"image":   identify.FieldStrategyOverwrite,
+			},
+			[]string{"url", "country", "height", "aliases", "image"},
+			[]string{"Other"},
+			true,
+		},
+		{
+			"ignore",
+			false,
+			map[string]identify.FieldStrategy{
+				"url":     identify.FieldStrategyIgnore,
+				"aliases": identify.FieldStrategySkipIfSet,
+				"image":   identify.FieldStrategyIgnore,
+			},
+			nil,
+			nil,
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			partial, gotImage, changes := performerRefresh(existing, tt.hasImage, scraped, refreshStrategies(tt.strategies))
+
+			var fields []string
+			for _, c := range changes {
+				fields = append(fields, c.Field)
+			}
+			assert.Equal(t, tt.wantFields, fields)
+
+			if tt.wantAliases == nil {
+				assert.Nil(t, partial.Aliases)
+			} else if assert.NotNil(t, partial.Aliases) {
+				assert.Equal(t, tt.wantAliases, partial.Aliases.Values)
+			}
+
+			assert.Equal(t, tt.wantImage, gotImage != nil)
+		})
+	}
+}
+
+func TestPerformerRefreshInvalidValues(t *testing.T) {
+	gender := "unknown"
+	height := "tall"
+	scraped := &models.ScrapedPerformer{
+		Gender: &gender,
+		Height: &height,
+	}
+
+	_, _, changes := performerRefresh(&models.Performer{}, false, scraped, nil)
+	assert.Empty(t, changes)
+}
+
+func TestStudioRefresh(t *testing.T) {
+	url := "https://example.com"
+	image := "https://example.com/image.jpg"
+	scraped := &models.ScrapedStudio{
+		URL:   &url,
+		Image: &image,
+	}
+
+	existing := &models.Studio{
+		ID:  1,
+		URL: sql.NullString{String: "https://old.example.com", Valid: true},
+	}
+
+	partial, gotImage, changes := studioRefresh(existing, true, scraped, nil)
+	assert.Nil(t, partial.URL)
+	assert.Nil(t, gotImage)
+	assert.Empty(t, changes)
+
+	partial, gotImage, changes = studioRefresh(existing, true, scraped, refreshStrategies(map[string]identify.FieldStrategy{
+		"url":   identify.FieldStrategyOverwrite,
+		"image": identify.FieldStrategyOverwrite,
+	}))
+	assert.Equal(t, 1, partial.ID)
+	if assert.NotNil(t, partial.URL) {
+		assert.Equal(t, url, partial.URL.String)
+	}
+	assert.Equal(t, &image, gotImage)
+	assert.Equal(t, []ScrapeRefreshFieldChange{
+		{Field: "url", Old: "https://old.example.com", New: url},
+		{Field: "image"},
+	}, changes)
+}
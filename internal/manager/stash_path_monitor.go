@@ -0,0 +1,235 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+const (
+	// stash path status is cached for this long, so that checking many files
+	// on the same stash path does not hit the filesystem for each file
+	stashPathStatusTTL = 5 * time.Second
+
+	// stat calls on a disconnected network share can block for a long time
+	stashPathCheckTimeout = 10 * time.Second
+
+	stashPathPollInterval = 10 * time.Second
+)
+
+var errStashPathCheckTimeout = errors.New("timed out accessing stash path")
+
+type stashPathStatus struct {
+	offline   bool
+	checkedAt time.Time
+}
+
+// StashPathMonitor tracks the availability and case sensitivity of the
+// configured stash paths.
+//
+// A stash path is offline if its root directory cannot be accessed, or if it
+// is empty, which is the case for the mount point of an unmounted share.
+// Files on an offline stash path are not treated as missing: scans are
+// paused until the path is available again, cleaning skips them and streams
+// fail with a temporary error.
+//
+// Stash paths may be on filesystems with different case sensitivity, such as
+// SMB shares alongside local ext4 disks. Paths on a case-insensitive stash
+// path that differ only by case refer to the same file.
+type StashPathMonitor struct {
+	stashPaths func() []*config.StashConfig
+	// check returns an error if the stash path root is not available
+	check func(path string) error
+	// detectCaseSensitive returns true if the stash path is case sensitive
+	detectCaseSensitive func(path string) (bool, error)
+
+	mutex         sync.Mutex
+	status        map[string]stashPathStatus
+	caseSensitive map[string]bool
+}
+
+func newStashPathMonitor(stashPaths func() []*config.StashConfig) *StashPathMonitor {
+	return &StashPathMonitor{
+		stashPaths:          stashPaths,
+		check:               checkStashPathRoot,
+		detectCaseSensitive: detectStashPathCaseSensitive,
+		status:              make(map[string]stashPathStatus),
+		caseSensitive:       make(map[string]bool),
+	}
+}
+
+func checkStashPathRoot(path string) error {
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- checkStashPathRootSync(path)
+	}()
+
+	select {
+	case err := <-errChan:
+		return err
+	case <-time.After(stashPathCheckTimeout):
+		return errStashPathCheckTimeout
+	}
+}
+
+func checkStashPathRootSync(path string) error {
+	f, err := os.Open(fsutil.LongPath(path))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := f.Readdirnames(1); err != nil {
+		if errors.Is(err, io.EOF) {
+			return errors.New("stash path is empty")
+		}
+		return err
+	}
+
+	return nil
+}
+
+// IsOffline returns true if the stash path containing path is offline. Paths
+// outside of the stash paths are never offline.
+func (m *StashPathMonitor) IsOffline(path string) bool {
+	stash := getStashFromDirPath(m.stashPaths(), path)
+	if stash == nil {
+		return false
+	}
+
+	return m.isStashPathOffline(stash.Path, time.Now())
+}
+
+func (m *StashPathMonitor) isStashPathOffline(stashPath string, now time.Time) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	status, found := m.status[stashPath]
+	if found && now.Sub(status.checkedAt) < stashPathStatusTTL {
+		return status.offline
+	}
+
+	err := m.check(stashPath)
+	offline := err != nil
+
+	switch {
+	case offline && !status.offline:
+		logger.Warnf("Stash path %s is offline: %v", stashPath, err)
+	case !offline && status.offline:
+		logger.Infof("Stash path %s is back online", stashPath)
+	}
+
+	m.status[stashPath] = stashPathStatus{
+		offline:   offline,
+		checkedAt: now,
+	}
+
+	return offline
+}
+
+// WaitOnline blocks until the stash path containing path is online, or until
+// the context is done.
+func (m *StashPathMonitor) WaitOnline(ctx context.Context, path string) error {
+	for m.IsOffline(path) {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(stashPathPollInterval):
+		}
+	}
+
+	return nil
+}
+
+// OfflinePaths returns the configured stash paths that are offline.
+func (m *StashPathMonitor) OfflinePaths() []string {
+	ret := []string{}
+	now := time.Now()
+	for _, s := range m.stashPaths() {
+		if m.isStashPathOffline(s.Path, now) {
+			ret = append(ret, s.Path)
+		}
+	}
+
+	return ret
+}
+
+// detectStashPathCaseSensitive detects the case sensitivity of the
+// filesystem of the stash path. If it cannot be determined from the stash
+// path itself, such as when its name has no letters, its entries are used.
+func detectStashPathCaseSensitive(path string) (bool, error) {
+	ret, err := fsutil.IsFsPathCaseSensitive(path)
+	if err == nil {
+		return ret, nil
+	}
+
+	f, openErr := os.Open(fsutil.LongPath(path))
+	if openErr != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	const maxEntries = 10
+	names, _ := f.Readdirnames(maxEntries)
+	for _, name := range names {
+		if ret, entryErr := fsutil.IsFsPathCaseSensitive(filepath.Join(path, name)); entryErr == nil {
+			return ret, nil
+		}
+	}
+
+	return false, err
+}
+
+// IsCaseSensitive returns true if path is on a case sensitive filesystem.
+// Case sensitivity is detected once for each stash path. Paths outside of
+// the stash paths, and paths where it cannot be determined, are treated as
+// case sensitive.
+func (m *StashPathMonitor) IsCaseSensitive(path string) bool {
+	stash := getStashFromDirPath(m.stashPaths(), path)
+	if stash == nil {
+		return true
+	}
+
+	return m.isStashPathCaseSensitive(stash.Path)
+}
+
+func (m *StashPathMonitor) isStashPathCaseSensitive(stashPath string) bool {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if ret, found := m.caseSensitive[stashPath]; found {
+		return ret
+	}
+
+	ret, err := m.detectCaseSensitive(stashPath)
+	if err != nil {
+		logger.Debugf("Could not determine case sensitivity of stash path %s: %v", stashPath, err)
+
+		// detect again later if the stash path is not available
+		if _, statErr := os.Stat(fsutil.LongPath(stashPath)); statErr == nil {
+			m.caseSensitive[stashPath] = true
+		}
+		return true
+	}
+
+	if !ret {
+		logger.Infof("Stash path %s is on a case-insensitive filesystem", stashPath)
+	}
+
+	m.caseSensitive[stashPath] = ret
+	return ret
+}
+
+// isStashPathCaseInsensitive returns true if the stash path is known to be
+// on a case-insensitive filesystem.
+func isStashPathCaseInsensitive(stashPath string) bool {
+	return instance != nil && instance.StashPathMonitor != nil && !instance.StashPathMonitor.isStashPathCaseSensitive(stashPath)
+}
@@ -0,0 +1,104 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStashPathMonitor(t *testing.T) {
+	onlinePath := filepath.Join("stash", "online")
+	offlinePath := filepath.Join("stash", "offline")
+
+	checks := 0
+	offline := map[string]bool{offlinePath: true}
+
+	m := newStashPathMonitor(func() []*config.StashConfig {
+		return []*config.StashConfig{
+			{Path: onlinePath},
+			{Path: offlinePath},
+		}
+	})
+	m.check = func(path string) error {
+		checks++
+		if offline[path] {
+			return errors.New("offline")
+		}
+		return nil
+	}
+
+	assert.False(t, m.IsOffline(filepath.Join(onlinePath, "file.mp4")))
+	assert.True(t, m.IsOffline(filepath.Join(offlinePath, "dir", "file.mp4")))
+	assert.False(t, m.IsOffline(filepath.Join("other", "file.mp4")))
+	assert.Equal(t, []string{offlinePath}, m.OfflinePaths())
+
+	// status is cached
+	assert.Equal(t, 2, checks)
+
+	// status is rechecked once the cache expires
+	offline[offlinePath] = false
+	assert.True(t, m.isStashPathOffline(offlinePath, time.Now()))
+	assert.False(t, m.isStashPathOffline(offlinePath, time.Now().Add(stashPathStatusTTL)))
+	assert.Nil(t, m.WaitOnline(context.Background(), offlinePath))
+	assert.Empty(t, m.OfflinePaths())
+}
+
+func TestStashPathMonitor_WaitOnlineCancelled(t *testing.T) {
+	m := newStashPathMonitor(func() []*config.StashConfig {
+		return []*config.StashConfig{{Path: "stash"}}
+	})
+	m.check = func(path string) error {
+		return errors.New("offline")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.ErrorIs(t, m.WaitOnline(ctx, filepath.Join("stash", "file.mp4")), context.Canceled)
+}
+
+func TestStashPathMonitor_IsCaseSensitive(t *testing.T) {
+	sensitivePath := filepath.Join("stash", "ext4")
+	insensitivePath := filepath.Join("stash", "smb")
+
+	detected := 0
+	m := newStashPathMonitor(func() []*config.StashConfig {
+		return []*config.StashConfig{
+			{Path: sensitivePath},
+			{Path: insensitivePath},
+		}
+	})
+	m.detectCaseSensitive = func(path string) (bool, error) {
+		detected++
+		return path != insensitivePath, nil
+	}
+
+	assert.True(t, m.IsCaseSensitive(filepath.Join(sensitivePath, "file.mp4")))
+	assert.False(t, m.IsCaseSensitive(filepath.Join(insensitivePath, "file.mp4")))
+	assert.False(t, m.IsCaseSensitive(filepath.Join(insensitivePath, "dir", "file.mp4")))
+	assert.True(t, m.IsCaseSensitive(filepath.Join("other", "file.mp4")))
+
+	// detected once per stash path
+	assert.Equal(t, 2, detected)
+}
+
+func TestCheckStashPathRoot(t *testing.T) {
+	dir := t.TempDir()
+
+	// missing
+	assert.NotNil(t, checkStashPathRoot(filepath.Join(dir, "missing")))
+
+	// empty mount point
+	assert.NotNil(t, checkStashPathRoot(dir))
+
+	if err := os.WriteFile(filepath.Join(dir, "file.mp4"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	assert.Nil(t, checkStashPathRoot(dir))
+}
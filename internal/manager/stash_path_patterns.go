@@ -0,0 +1,155 @@
+package manager
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+type stashPathRegexp struct {
+	re *regexp.Regexp
+	// globs are matched against the path relative to the stash path
+	relative bool
+}
+
+func (r stashPathRegexp) match(path, relPath string) bool {
+	if r.relative {
+		return r.re.MatchString(relPath)
+	}
+	return r.re.MatchString(path)
+}
+
+type stashPathRegexps []stashPathRegexp
+
+func (l stashPathRegexps) match(path, relPath string) bool {
+	for _, r := range l {
+		if r.match(path, relPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// stashPathPatterns are the compiled include and exclude patterns of a stash
+// library path.
+type stashPathPatterns struct {
+	stashPath string
+
+	videoInclude stashPathRegexps
+	videoExclude stashPathRegexps
+	imageInclude stashPathRegexps
+	imageExclude stashPathRegexps
+}
+
+func newStashPathPatterns(s *config.StashConfig) *stashPathPatterns {
+	ret := &stashPathPatterns{
+		stashPath: s.Path,
+	}
+
+	for _, p := range s.Patterns {
+		re, err := p.Compile()
+		if err != nil {
+			logger.Errorf("Invalid pattern %q for stash path %s: %v", p.Pattern, s.Path, err)
+			continue
+		}
+
+		r := stashPathRegexp{re: re, relative: !p.Regex}
+		switch {
+		case p.Include && p.Video:
+			ret.videoInclude = append(ret.videoInclude, r)
+		case p.Video:
+			ret.videoExclude = append(ret.videoExclude, r)
+		}
+		switch {
+		case p.Include && p.Image:
+			ret.imageInclude = append(ret.imageInclude, r)
+		case p.Image:
+			ret.imageExclude = append(ret.imageExclude, r)
+		}
+	}
+
+	return ret
+}
+
+// compileStashPathPatterns returns the compiled patterns of each stash path,
+// keyed by stash path.
+func compileStashPathPatterns(stashPaths []*config.StashConfig) map[string]*stashPathPatterns {
+	ret := make(map[string]*stashPathPatterns)
+	for _, s := range stashPaths {
+		if len(s.Patterns) > 0 {
+			ret[s.Path] = newStashPathPatterns(s)
+		}
+	}
+	return ret
+}
+
+func (p *stashPathPatterns) relativePath(path string) string {
+	rel, err := filepath.Rel(p.stashPath, path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// excludesFolder returns true if the folder matches both video and image
+// exclude patterns. Include patterns are not applied to folders, since files
+// within them may match.
+func (p *stashPathPatterns) excludesFolder(path string) bool {
+	if p == nil || len(p.videoExclude) == 0 || len(p.imageExclude) == 0 {
+		return false
+	}
+
+	// add a trailing separator so that it correctly matches against patterns like path/.*
+	pathTest := path + string(filepath.Separator)
+	relTest := p.relativePath(path) + "/"
+	return p.videoExclude.match(pathTest, relTest) && p.imageExclude.match(pathTest, relTest)
+}
+
+func excludesFile(path, relPath string, include, exclude stashPathRegexps) bool {
+	if len(include) > 0 && !include.match(path, relPath) {
+		return true
+	}
+
+	return exclude.match(path, relPath)
+}
+
+// excludesVideo returns true if the video file is not included by the
+// include patterns, or matches an exclude pattern.
+func (p *stashPathPatterns) excludesVideo(path string) bool {
+	if p == nil {
+		return false
+	}
+
+	return excludesFile(path, p.relativePath(path), p.videoInclude, p.videoExclude)
+}
+
+// excludesImage returns true if the image or gallery file is not included by
+// the include patterns, or matches an exclude pattern.
+func (p *stashPathPatterns) excludesImage(path string) bool {
+	if p == nil {
+		return false
+	}
+
+	return excludesFile(path, p.relativePath(path), p.imageInclude, p.imageExclude)
+}
+
+// ValidateStashPathPatterns returns an error if any pattern of the stash
+// paths is empty or invalid.
+func ValidateStashPathPatterns(stashes []*config.StashConfigInput) error {
+	for _, s := range stashes {
+		for _, p := range s.Patterns {
+			if strings.TrimSpace(p.Pattern) == "" {
+				return fmt.Errorf("empty pattern for stash path %s", s.Path)
+			}
+			if _, err := p.Compile(); err != nil {
+				return fmt.Errorf("invalid pattern %q for stash path %s: %w", p.Pattern, s.Path, err)
+			}
+		}
+	}
+
+	return nil
+}
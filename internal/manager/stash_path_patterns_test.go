@@ -0,0 +1,82 @@
+package manager
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStashPathPatterns(t *testing.T) {
+	stashPath := filepath.Join("stash", "mixed")
+	p := newStashPathPatterns(&config.StashConfig{
+		Path: stashPath,
+		Patterns: []*config.StashPathPattern{
+			// only scan videos in the movies folder
+			{Pattern: "movies/**", Include: true, Video: true},
+			{Pattern: "*.sample.*", Video: true, Image: true},
+			{Pattern: "thumbs", Video: true, Image: true},
+			{Pattern: `\.tmp\.`, Regex: true, Image: true},
+			// invalid patterns are ignored
+			{Pattern: "[", Regex: true, Video: true, Image: true},
+		},
+	})
+
+	tests := []struct {
+		name         string
+		path         string
+		wantVideo    bool
+		wantImage    bool
+		wantExcluded bool
+	}{
+		{"included video", "movies/a.mp4", false, false, false},
+		{"nested included video", "movies/x/a.mp4", false, false, false},
+		{"video outside include", "photos/a.mp4", true, false, false},
+		{"case insensitive", "MOVIES/a.mp4", false, false, false},
+		{"excluded by name glob", "movies/a.SAMPLE.mp4", true, true, true},
+		{"excluded folder name", "photos/thumbs/a.jpg", true, true, true},
+		{"excluded by regex", "photos/a.tmp.jpg", true, true, false},
+		{"image", "photos/a.jpg", true, false, false},
+		{"folder", "photos", true, false, false},
+		{"excluded folder", "photos/thumbs", true, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(stashPath, filepath.FromSlash(tt.path))
+			assert.Equal(t, tt.wantVideo, p.excludesVideo(path))
+			assert.Equal(t, tt.wantImage, p.excludesImage(path))
+			assert.Equal(t, tt.wantExcluded, p.excludesFolder(path))
+		})
+	}
+}
+
+func TestStashPathPatterns_Nil(t *testing.T) {
+	patterns := compileStashPathPatterns([]*config.StashConfig{{Path: "stash"}})
+	p := patterns["stash"]
+
+	assert.False(t, p.excludesVideo(filepath.Join("stash", "a.mp4")))
+	assert.False(t, p.excludesImage(filepath.Join("stash", "a.jpg")))
+	assert.False(t, p.excludesFolder("stash"))
+}
+
+func TestValidateStashPathPatterns(t *testing.T) {
+	valid := []*config.StashConfigInput{{
+		Path:     "stash",
+		Patterns: []*config.StashPathPattern{{Pattern: "**/*.mp4"}, {Pattern: `\.mp4$`, Regex: true}},
+	}}
+	assert.Nil(t, ValidateStashPathPatterns(valid))
+
+	invalid := []*config.StashConfigInput{{
+		Path:     "stash",
+		Patterns: []*config.StashPathPattern{{Pattern: "[", Regex: true}},
+	}}
+	assert.NotNil(t, ValidateStashPathPatterns(invalid))
+
+	empty := []*config.StashConfigInput{{
+		Path:     "stash",
+		Patterns: []*config.StashPathPattern{{Pattern: " "}},
+	}}
+	assert.NotNil(t, ValidateStashPathPatterns(empty))
+}
@@ -0,0 +1,294 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/txn"
+)
+
+var (
+	ErrStashPathExists     = errors.New("stash path already exists")
+	ErrStashPathOverridden = errors.New("stash paths are overridden and cannot be changed")
+)
+
+// stashPathsMutex serialises changes to the stash paths, so that concurrent
+// changes are not lost.
+var stashPathsMutex sync.Mutex
+
+type StashPathAddInput struct {
+	Stash config.StashConfigInput `json:"stash"`
+	// Position to insert the stash path at. Appended if nil
+	Index *int `json:"index"`
+	// Scan the stash path once added. Defaults to true
+	Scan *bool `json:"scan"`
+}
+
+type StashPathRemoveInput struct {
+	Path string `json:"path"`
+	// Remove the files of the stash path from the database once removed.
+	// Files are never deleted from disk.
+	Clean bool `json:"clean"`
+	// Count the files that would be removed without removing the stash path
+	DryRun bool `json:"dryRun"`
+}
+
+type StashPathRemoveResult struct {
+	// Number of files in the stash path that are not in another stash path
+	FileCount int
+	// ID of the clean job, if started
+	CleanJobID *int
+}
+
+func findStashPath(stashes []*config.StashConfig, path string) int {
+	path = filepath.Clean(path)
+	for i, s := range stashes {
+		if s != nil && filepath.Clean(s.Path) == path {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func stashConfigFromInput(input config.StashConfigInput) *config.StashConfig {
+	return &config.StashConfig{
+		Path:         filepath.Clean(input.Path),
+		ExcludeVideo: input.ExcludeVideo,
+		ExcludeImage: input.ExcludeImage,
+		Retention:    input.Retention,
+		Patterns:     input.Patterns,
+	}
+}
+
+func validateStashPathInput(input config.StashConfigInput) error {
+	return ValidateStashPathPatterns([]*config.StashConfigInput{&input})
+}
+
+// updateStashPaths applies fn to the configured stash paths and writes the
+// result to the configuration.
+func (s *Manager) updateStashPaths(fn func(stashes []*config.StashConfig) ([]*config.StashConfig, error)) error {
+	c := s.Config
+	if c.HasOverride(config.Stash) {
+		return ErrStashPathOverridden
+	}
+
+	stashPathsMutex.Lock()
+	defer stashPathsMutex.Unlock()
+
+	stashes, err := fn(c.GetStashPaths())
+	if err != nil {
+		return err
+	}
+
+	c.Set(config.Stash, stashes)
+	if err := c.Write(); err != nil {
+		return err
+	}
+
+	s.RefreshConfig()
+	return nil
+}
+
+// AddStashPath adds a stash path, and starts a scan of it unless disabled.
+// Returns the ID of the scan job, or nil if the stash path was not scanned.
+func (s *Manager) AddStashPath(ctx context.Context, input StashPathAddInput) (*int, error) {
+	if err := validateStashPathInput(input.Stash); err != nil {
+		return nil, err
+	}
+
+	stash := stashConfigFromInput(input.Stash)
+	if exists, err := fsutil.DirExists(stash.Path); !exists {
+		return nil, err
+	}
+
+	if err := s.updateStashPaths(func(stashes []*config.StashConfig) ([]*config.StashConfig, error) {
+		if findStashPath(stashes, stash.Path) != -1 {
+			return nil, fmt.Errorf("%w: %s", ErrStashPathExists, stash.Path)
+		}
+
+		index := len(stashes)
+		if input.Index != nil {
+			index = *input.Index
+			if index < 0 || index > len(stashes) {
+				return nil, fmt.Errorf("%w: index %d out of range", ErrInput, index)
+			}
+		}
+
+		ret := make([]*config.StashConfig, 0, len(stashes)+1)
+		ret = append(ret, stashes[:index]...)
+		ret = append(ret, stash)
+		return append(ret, stashes[index:]...), nil
+	}); err != nil {
+		return nil, err
+	}
+
+	logger.Infof("Added stash path %s", stash.Path)
+
+	if input.Scan != nil && !*input.Scan {
+		return nil, nil
+	}
+
+	scanInput := ScanMetadataInput{
+		Paths: []string{stash.Path},
+	}
+	if opts := s.Config.GetDefaultScanSettings(); opts != nil {
+		scanInput.ScanMetadataOptions = *opts
+	}
+
+	jobID, err := s.Scan(ctx, scanInput)
+	if err != nil {
+		return nil, fmt.Errorf("scanning added stash path: %w", err)
+	}
+
+	return &jobID, nil
+}
+
+// UpdateStashPath replaces the configuration of the stash path with the same
+// path as input.
+func (s *Manager) UpdateStashPath(ctx context.Context, input config.StashConfigInput) error {
+	if err := validateStashPathInput(input); err != nil {
+		return err
+	}
+
+	stash := stashConfigFromInput(input)
+	return s.updateStashPaths(func(stashes []*config.StashConfig) ([]*config.StashConfig, error) {
+		i := findStashPath(stashes, stash.Path)
+		if i == -1 {
+			return nil, fmt.Errorf("%w: stash path %s", models.ErrNotFound, stash.Path)
+		}
+
+		stashes[i] = stash
+		return stashes, nil
+	})
+}
+
+// ReorderStashPaths changes the order of the stash paths. paths must contain
+// every stash path exactly once. Files in nested stash paths use the
+// configuration of the first stash path that contains them.
+func (s *Manager) ReorderStashPaths(ctx context.Context, paths []string) error {
+	return s.updateStashPaths(func(stashes []*config.StashConfig) ([]*config.StashConfig, error) {
+		if len(paths) != len(stashes) {
+			return nil, fmt.Errorf("%w: expected %d stash paths, got %d", ErrInput, len(stashes), len(paths))
+		}
+
+		ret := make([]*config.StashConfig, len(paths))
+		for i, p := range paths {
+			// stash paths already included are set to nil
+			j := findStashPath(stashes, p)
+			if j == -1 {
+				return nil, fmt.Errorf("%w: stash path %s is not configured or included more than once", ErrInput, p)
+			}
+
+			ret[i] = stashes[j]
+			stashes[j] = nil
+		}
+
+		return ret, nil
+	})
+}
+
+// detachedPaths returns the paths containing the files that are no longer in
+// a stash path once the stash path at path is removed from stashes, and the
+// paths within them that remain in a stash path.
+func detachedPaths(stashes []*config.StashConfig, path string) (include []string, exclude []string) {
+	for _, s := range stashes {
+		if filepath.Clean(s.Path) == path {
+			continue
+		}
+
+		// the files remain in the containing stash path
+		if fsutil.IsPathInDir(s.Path, path) {
+			return nil, nil
+		}
+
+		if fsutil.IsPathInDir(path, s.Path) {
+			exclude = append(exclude, s.Path)
+		}
+	}
+
+	return []string{path}, exclude
+}
+
+func (s *Manager) countDetachedFiles(ctx context.Context, stashes []*config.StashConfig, path string) (int, error) {
+	include, exclude := detachedPaths(stashes, path)
+	if len(include) == 0 {
+		return 0, nil
+	}
+
+	var ret int
+	if err := txn.WithReadTxn(ctx, s.Repository, func(ctx context.Context) error {
+		n, err := s.Repository.File.CountAllInPaths(ctx, include)
+		if err != nil {
+			return err
+		}
+
+		var excluded int
+		if len(exclude) > 0 {
+			excluded, err = s.Repository.File.CountAllInPaths(ctx, exclude)
+			if err != nil {
+				return err
+			}
+		}
+
+		ret = n - excluded
+		return nil
+	}); err != nil {
+		return 0, fmt.Errorf("counting files in stash path: %w", err)
+	}
+
+	return ret, nil
+}
+
+// RemoveStashPath removes a stash path. The files of the stash path remain
+// in the database until they are cleaned, which is started immediately if
+// input.Clean is true. Files are never deleted from disk.
+func (s *Manager) RemoveStashPath(ctx context.Context, input StashPathRemoveInput) (*StashPathRemoveResult, error) {
+	path := filepath.Clean(input.Path)
+	stashes := s.Config.GetStashPaths()
+	if findStashPath(stashes, path) == -1 {
+		return nil, fmt.Errorf("%w: stash path %s", models.ErrNotFound, path)
+	}
+
+	count, err := s.countDetachedFiles(ctx, stashes, path)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := &StashPathRemoveResult{
+		FileCount: count,
+	}
+
+	if input.DryRun {
+		return ret, nil
+	}
+
+	if err := s.updateStashPaths(func(stashes []*config.StashConfig) ([]*config.StashConfig, error) {
+		i := findStashPath(stashes, path)
+		if i == -1 {
+			return nil, fmt.Errorf("%w: stash path %s", models.ErrNotFound, path)
+		}
+
+		return append(stashes[:i], stashes[i+1:]...), nil
+	}); err != nil {
+		return nil, err
+	}
+
+	logger.Infof("Removed stash path %s", path)
+
+	if input.Clean && count > 0 {
+		jobID := s.Clean(ctx, CleanMetadataInput{
+			Paths: []string{path},
+		})
+		ret.CleanJobID = &jobID
+	}
+
+	return ret, nil
+}
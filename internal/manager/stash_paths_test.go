@@ -0,0 +1,53 @@
+package manager
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stashapp/stash/internal/manager/config"
+)
+
+func TestDetachedPaths(t *testing.T) {
+	root := filepath.Join(string(filepath.Separator), "library")
+	nested := filepath.Join(root, "nested")
+	other := filepath.Join(string(filepath.Separator), "other")
+
+	stashes := func(paths ...string) []*config.StashConfig {
+		var ret []*config.StashConfig
+		for _, p := range paths {
+			ret = append(ret, &config.StashConfig{Path: p})
+		}
+		return ret
+	}
+
+	tests := []struct {
+		name        string
+		stashes     []*config.StashConfig
+		path        string
+		wantInclude []string
+		wantExclude []string
+	}{
+		{"single", stashes(root, other), root, []string{root}, nil},
+		{"contains other stash path", stashes(root, nested, other), root, []string{root}, []string{nested}},
+		{"within other stash path", stashes(root, nested), nested, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			include, exclude := detachedPaths(tt.stashes, tt.path)
+			assert.Equal(t, tt.wantInclude, include)
+			assert.Equal(t, tt.wantExclude, exclude)
+		})
+	}
+}
+
+func TestFindStashPath(t *testing.T) {
+	stashes := []*config.StashConfig{
+		nil,
+		{Path: filepath.Join("a", "b")},
+	}
+
+	assert.Equal(t, 1, findStashPath(stashes, filepath.Join("a", "b")+string(filepath.Separator)))
+	assert.Equal(t, -1, findStashPath(stashes, "a"))
+}
@@ -0,0 +1,114 @@
+package manager
+
+import (
+	"context"
+	"time"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+// StatsGrowthDays is the number of days over which the growth rate of stash
+// paths is calculated.
+const StatsGrowthDays = 30
+
+// PathStats are the statistics of a stash path.
+type PathStats struct {
+	file.PathStats
+	Path string
+	// GrowthRate is the average size of the files added per day over the
+	// last StatsGrowthDays days.
+	GrowthRate float64
+	// Volume is nil if the volume information of the path is not available.
+	Volume *fsutil.VolumeInfo
+}
+
+// VolumeStats are the statistics of the stash paths on a volume.
+type VolumeStats struct {
+	file.PathStats
+	Volume     string
+	Paths      []string
+	GrowthRate float64
+	FreeSpace  float64
+	TotalSpace float64
+}
+
+// GetPathStats returns the statistics of each of the stash paths.
+func GetPathStats(ctx context.Context, r file.Counter, paths []string, now time.Time) ([]*PathStats, error) {
+	since := now.AddDate(0, 0, -StatsGrowthDays)
+
+	ret := make([]*PathStats, len(paths))
+	for i, p := range paths {
+		s, err := r.PathStats(ctx, p, since)
+		if err != nil {
+			return nil, err
+		}
+
+		ret[i] = &PathStats{
+			PathStats:  *s,
+			Path:       p,
+			GrowthRate: s.RecentSize / StatsGrowthDays,
+		}
+
+		volume, err := fsutil.GetVolumeInfo(p)
+		if err != nil {
+			logger.Warnf("Could not get volume of stash path %s: %v", p, err)
+			continue
+		}
+		ret[i].Volume = volume
+	}
+
+	return ret, nil
+}
+
+// GetVolumeStats groups the path statistics by volume. Paths without volume
+// information are ignored. Paths within another of the paths are not counted
+// again.
+func GetVolumeStats(paths []*PathStats) []*VolumeStats {
+	ret := []*VolumeStats{}
+	byID := make(map[string]*VolumeStats)
+
+	for _, p := range paths {
+		if p.Volume == nil {
+			continue
+		}
+
+		v := byID[p.Volume.ID]
+		if v == nil {
+			v = &VolumeStats{
+				Volume:     p.Volume.ID,
+				FreeSpace:  float64(p.Volume.FreeSpace),
+				TotalSpace: float64(p.Volume.TotalSpace),
+			}
+			byID[p.Volume.ID] = v
+			ret = append(ret, v)
+		}
+
+		v.Paths = append(v.Paths, p.Path)
+		if isNestedPath(paths, p) {
+			continue
+		}
+
+		v.SceneCount += p.SceneCount
+		v.ScenesSize += p.ScenesSize
+		v.ScenesDuration += p.ScenesDuration
+		v.ImageCount += p.ImageCount
+		v.ImagesSize += p.ImagesSize
+		v.RecentSize += p.RecentSize
+		v.GrowthRate += p.GrowthRate
+	}
+
+	return ret
+}
+
+// isNestedPath returns true if p is within another of the paths.
+func isNestedPath(paths []*PathStats, p *PathStats) bool {
+	for _, other := range paths {
+		if other != p && other.Path != p.Path && fsutil.IsPathInDir(other.Path, p.Path) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,59 @@
+package manager
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetVolumeStats(t *testing.T) {
+	volume1 := &fsutil.VolumeInfo{ID: "1", TotalSpace: 1000, FreeSpace: 100}
+	volume2 := &fsutil.VolumeInfo{ID: "2", TotalSpace: 2000, FreeSpace: 200}
+
+	path1 := filepath.Join("stash", "a")
+	path2 := filepath.Join("stash", "a", "b")
+	path3 := filepath.Join("stash", "c")
+	path4 := filepath.Join("other")
+
+	stats := func(sceneCount int, size float64, growthRate float64) file.PathStats {
+		return file.PathStats{
+			SceneCount: sceneCount,
+			ScenesSize: size,
+			RecentSize: growthRate * StatsGrowthDays,
+		}
+	}
+
+	paths := []*PathStats{
+		{PathStats: stats(2, 20, 1), Path: path1, GrowthRate: 1, Volume: volume1},
+		// nested within path1 and already counted
+		{PathStats: stats(1, 10, 1), Path: path2, GrowthRate: 1, Volume: volume1},
+		{PathStats: stats(3, 30, 2), Path: path3, GrowthRate: 2, Volume: volume1},
+		{PathStats: stats(4, 40, 0), Path: path4, GrowthRate: 0, Volume: volume2},
+		// inaccessible
+		{PathStats: stats(5, 50, 5), Path: "missing"},
+	}
+
+	want := []*VolumeStats{
+		{
+			PathStats:  file.PathStats{SceneCount: 5, ScenesSize: 50, RecentSize: 3 * StatsGrowthDays},
+			Volume:     "1",
+			Paths:      []string{path1, path2, path3},
+			GrowthRate: 3,
+			FreeSpace:  100,
+			TotalSpace: 1000,
+		},
+		{
+			PathStats:  file.PathStats{SceneCount: 4, ScenesSize: 40},
+			Volume:     "2",
+			Paths:      []string{path4},
+			FreeSpace:  200,
+			TotalSpace: 2000,
+		},
+	}
+
+	assert.Equal(t, want, GetVolumeStats(paths))
+	assert.Equal(t, []*VolumeStats{}, GetVolumeStats(nil))
+}
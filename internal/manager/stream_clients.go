@@ -0,0 +1,93 @@
+package manager
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// clients which have not requested a stream for this long are no longer
+// reported
+const streamClientTimeout = 30 * time.Minute
+
+type SceneStreamType string
+
+const (
+	SceneStreamTypeDirect      SceneStreamType = "DIRECT"
+	SceneStreamTypeMkv         SceneStreamType = "MKV"
+	SceneStreamTypeWebm        SceneStreamType = "WEBM"
+	SceneStreamTypeMp4         SceneStreamType = "MP4"
+	SceneStreamTypeHLS         SceneStreamType = "HLS"
+	SceneStreamTypeHLSAdaptive SceneStreamType = "HLS_ADAPTIVE"
+)
+
+// StreamClient is a client which has recently streamed a scene.
+type StreamClient struct {
+	RemoteAddr string `json:"remote_addr"`
+	UserAgent  string `json:"user_agent"`
+	// SceneID is the scene of the last stream request
+	SceneID int `json:"scene_id"`
+	// StreamType is the type of stream last requested by the client
+	StreamType SceneStreamType `json:"stream_type"`
+	// Resolution requested for transcoded streams. Nil for direct streams.
+	Resolution  *string   `json:"resolution"`
+	Requests    int       `json:"requests"`
+	LastRequest time.Time `json:"last_request"`
+}
+
+// StreamClientTracker tracks the streams requested by each client, so that
+// the stream type negotiated by remote clients can be diagnosed.
+type StreamClientTracker struct {
+	mutex   sync.Mutex
+	clients map[string]*StreamClient
+}
+
+func NewStreamClientTracker() *StreamClientTracker {
+	return &StreamClientTracker{
+		clients: make(map[string]*StreamClient),
+	}
+}
+
+func streamClientKey(remoteAddr, userAgent string) string {
+	return strings.Join([]string{remoteAddr, userAgent}, "|")
+}
+
+// Record records a stream request. Clients are identified by their remote
+// address and user agent.
+func (t *StreamClientTracker) Record(c StreamClient) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	key := streamClientKey(c.RemoteAddr, c.UserAgent)
+	if existing := t.clients[key]; existing != nil {
+		c.Requests += existing.Requests
+	}
+	c.Requests++
+
+	t.clients[key] = &c
+}
+
+// List returns the clients which requested a stream within the timeout of
+// now, most recent first.
+func (t *StreamClientTracker) List(now time.Time) []*StreamClient {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	var ret []*StreamClient
+	for k, c := range t.clients {
+		if now.Sub(c.LastRequest) >= streamClientTimeout {
+			delete(t.clients, k)
+			continue
+		}
+
+		cc := *c
+		ret = append(ret, &cc)
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].LastRequest.After(ret[j].LastRequest)
+	})
+
+	return ret
+}
@@ -0,0 +1,58 @@
+package manager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamClientTracker(t *testing.T) {
+	tr := NewStreamClientTracker()
+	now := time.Now()
+	resolution := "STANDARD"
+
+	tr.Record(StreamClient{
+		RemoteAddr:  "192.168.1.2",
+		UserAgent:   "tv",
+		SceneID:     1,
+		StreamType:  SceneStreamTypeDirect,
+		LastRequest: now.Add(-time.Minute),
+	})
+	tr.Record(StreamClient{
+		RemoteAddr:  "10.0.0.1",
+		UserAgent:   "browser",
+		SceneID:     1,
+		StreamType:  SceneStreamTypeDirect,
+		LastRequest: now.Add(-streamClientTimeout),
+	})
+	tr.Record(StreamClient{
+		RemoteAddr:  "192.168.1.2",
+		UserAgent:   "tv",
+		SceneID:     2,
+		StreamType:  SceneStreamTypeWebm,
+		Resolution:  &resolution,
+		LastRequest: now,
+	})
+	tr.Record(StreamClient{
+		RemoteAddr:  "192.168.1.2",
+		UserAgent:   "phone",
+		SceneID:     3,
+		StreamType:  SceneStreamTypeHLS,
+		LastRequest: now.Add(-2 * time.Minute),
+	})
+
+	got := tr.List(now)
+
+	// expired clients are not returned
+	if assert.Len(t, got, 2) {
+		assert.Equal(t, "tv", got[0].UserAgent)
+		assert.Equal(t, 2, got[0].SceneID)
+		assert.Equal(t, SceneStreamTypeWebm, got[0].StreamType)
+		assert.Equal(t, &resolution, got[0].Resolution)
+		assert.Equal(t, 2, got[0].Requests)
+
+		assert.Equal(t, "phone", got[1].UserAgent)
+		assert.Equal(t, 1, got[1].Requests)
+	}
+}
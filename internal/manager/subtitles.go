@@ -0,0 +1,85 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/file/video"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+type SubtitleFinder interface {
+	GetCaptions(ctx context.Context, fileID file.ID) ([]*models.VideoCaption, error)
+	GetSubtitleTracks(ctx context.Context, fileID file.ID) ([]file.VideoSubtitleTrack, error)
+}
+
+// Subtitle is a subtitle file next to a video file, or a subtitle track
+// embedded in it. Exactly one of Caption and Track is set.
+type Subtitle struct {
+	Caption *models.VideoCaption
+	Track   *file.VideoSubtitleTrack
+}
+
+// GetSubtitles returns the subtitle files of the video file followed by its
+// embedded subtitle tracks. The index of a subtitle in the returned slice
+// identifies it in subtitle URLs and stream requests.
+func GetSubtitles(ctx context.Context, r SubtitleFinder, fileID file.ID) ([]Subtitle, error) {
+	captions, err := r.GetCaptions(ctx, fileID)
+	if err != nil {
+		return nil, fmt.Errorf("getting captions: %w", err)
+	}
+
+	tracks, err := r.GetSubtitleTracks(ctx, fileID)
+	if err != nil {
+		return nil, fmt.Errorf("getting subtitle tracks: %w", err)
+	}
+
+	ret := []Subtitle{}
+	for _, c := range captions {
+		ret = append(ret, Subtitle{Caption: c})
+	}
+	for i := range tracks {
+		ret = append(ret, Subtitle{Track: &tracks[i]})
+	}
+
+	return ret, nil
+}
+
+// BurnIn returns the options to burn the subtitle into a transcode of the
+// video file at videoPath.
+func (s Subtitle) BurnIn(videoPath string) ffmpeg.SubtitleBurnIn {
+	if s.Caption != nil {
+		return ffmpeg.SubtitleBurnIn{
+			Path: s.Caption.Path(videoPath),
+		}
+	}
+
+	return ffmpeg.SubtitleBurnIn{
+		Path:        videoPath,
+		StreamIndex: s.Track.StreamIndex,
+	}
+}
+
+// WriteSubtitleVTT writes the subtitle of the video file at videoPath,
+// converted to WebVTT.
+func WriteSubtitleVTT(ctx context.Context, encoder ffmpeg.FFMpeg, videoPath string, s Subtitle, w io.Writer) error {
+	if s.Caption != nil {
+		sub, err := video.ReadSubs(s.Caption.Path(videoPath))
+		if err != nil {
+			return fmt.Errorf("reading subtitle file: %w", err)
+		}
+
+		return sub.WriteToWebVTT(w)
+	}
+
+	data, err := encoder.GetSubtitleVTT(ctx, videoPath, s.Track.StreamIndex)
+	if err != nil {
+		return fmt.Errorf("extracting subtitle track: %w", err)
+	}
+
+	_, err = w.Write(data)
+	return err
+}
@@ -0,0 +1,61 @@
+package manager
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type testSubtitleFinder struct {
+	captions []*models.VideoCaption
+	tracks   []file.VideoSubtitleTrack
+}
+
+func (f testSubtitleFinder) GetCaptions(ctx context.Context, fileID file.ID) ([]*models.VideoCaption, error) {
+	return f.captions, nil
+}
+
+func (f testSubtitleFinder) GetSubtitleTracks(ctx context.Context, fileID file.ID) ([]file.VideoSubtitleTrack, error) {
+	return f.tracks, nil
+}
+
+func TestGetSubtitles(t *testing.T) {
+	videoPath := filepath.Join("videos", "video.mkv")
+
+	finder := testSubtitleFinder{
+		captions: []*models.VideoCaption{
+			{LanguageCode: "en", Filename: "video.en.srt", CaptionType: "srt"},
+		},
+		tracks: []file.VideoSubtitleTrack{
+			{StreamIndex: 0, Language: "de", Codec: "subrip"},
+			{StreamIndex: 2, Language: "fr", Codec: "ass"},
+		},
+	}
+
+	got, err := GetSubtitles(context.Background(), finder, 1)
+	if err != nil {
+		t.Fatalf("GetSubtitles() error = %v", err)
+	}
+
+	if !assert.Len(t, got, 3) {
+		return
+	}
+
+	assert.Equal(t, finder.captions[0], got[0].Caption)
+	assert.Nil(t, got[0].Track)
+	assert.Equal(t, "de", got[1].Track.Language)
+	assert.Equal(t, "fr", got[2].Track.Language)
+
+	assert.Equal(t, ffmpeg.SubtitleBurnIn{
+		Path: filepath.Join("videos", "video.en.srt"),
+	}, got[0].BurnIn(videoPath))
+	assert.Equal(t, ffmpeg.SubtitleBurnIn{
+		Path:        videoPath,
+		StreamIndex: 2,
+	}, got[2].BurnIn(videoPath))
+}
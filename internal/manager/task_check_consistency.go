@@ -0,0 +1,108 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/txn"
+)
+
+type CheckConsistencyInput struct {
+	// Types of the issues to repair after checking
+	Repair []models.ConsistencyIssueType `json:"repair"`
+}
+
+// CheckConsistency queues a job to check the invariants of the database,
+// repairing the issues of the requested types. The issues found are stored
+// in a job artifact.
+func (s *Manager) CheckConsistency(ctx context.Context, input CheckConsistencyInput) (int, error) {
+	for _, t := range input.Repair {
+		if !t.IsValid() {
+			return 0, fmt.Errorf("invalid issue type %q", t)
+		}
+	}
+
+	j := job.MakeJobExec(func(ctx context.Context, progress *job.Progress) {
+		s.checkConsistency(ctx, input.Repair)
+	})
+
+	return s.JobManager.Add(ctx, "Checking library consistency...", j), nil
+}
+
+// consistencyIssue is an issue in the consistency report.
+type consistencyIssue struct {
+	*models.ConsistencyIssue
+	// RepairAction describes how the issue is repaired. Empty if the issue
+	// must be resolved manually.
+	RepairAction string `json:"repair_action,omitempty"`
+	Repaired     bool   `json:"repaired"`
+}
+
+func (s *Manager) checkConsistency(ctx context.Context, repairTypes []models.ConsistencyIssueType) {
+	r := s.Repository
+
+	var issues []*models.ConsistencyIssue
+	if err := txn.WithReadTxn(ctx, r, func(ctx context.Context) error {
+		var err error
+		issues, err = r.Consistency.CheckConsistency(ctx)
+		return err
+	}); err != nil {
+		logger.Errorf("Error checking library consistency: %v", err)
+		return
+	}
+
+	logger.Infof("Found %d library consistency issues", len(issues))
+
+	if len(issues) == 0 {
+		return
+	}
+
+	repaired := false
+	if len(repairTypes) > 0 {
+		var n int
+		if err := txn.WithTxn(ctx, r, func(ctx context.Context) error {
+			var err error
+			n, err = r.Consistency.RepairConsistency(ctx, repairTypes)
+			return err
+		}); err != nil {
+			logger.Errorf("Error repairing library consistency issues: %v", err)
+		} else {
+			logger.Infof("Repaired %d library consistency issues", n)
+			repaired = true
+		}
+	}
+
+	report := make([]consistencyIssue, len(issues))
+	for i, issue := range issues {
+		action := issue.Type.RepairAction()
+		report[i] = consistencyIssue{
+			ConsistencyIssue: issue,
+			RepairAction:     action,
+			Repaired:         repaired && action != "" && issueTypesContain(repairTypes, issue.Type),
+		}
+	}
+
+	name := "consistency-" + time.Now().Format("20060102-150405") + ".json"
+	if _, err := s.AddJobArtifact(ctx, name, "application/json", func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}); err != nil {
+		logger.Errorf("Error storing consistency report: %v", err)
+	}
+}
+
+func issueTypesContain(types []models.ConsistencyIssueType, t models.ConsistencyIssueType) bool {
+	for _, tt := range types {
+		if tt == t {
+			return true
+		}
+	}
+	return false
+}
@@ -52,11 +52,39 @@ func (j *cleanJob) Execute(ctx context.Context, progress *job.Progress) {
 
 	j.cleanEmptyGalleries(ctx)
 
+	if !j.input.DryRun {
+		j.compactOperationJournal(ctx)
+	}
+
 	j.scanSubs.notify()
 	elapsed := time.Since(start)
 	logger.Info(fmt.Sprintf("Finished Cleaning (%s)", elapsed))
 }
 
+// compactOperationJournal deletes the operation journal entries superseded
+// by a later entry for the same object.
+func (j *cleanJob) compactOperationJournal(ctx context.Context) {
+	r := j.txnManager
+	if err := txn.WithTxn(ctx, r, func(ctx context.Context) error {
+		cursor, err := r.OperationJournal.Latest(ctx)
+		if err != nil {
+			return err
+		}
+
+		n, err := r.OperationJournal.Compact(ctx, cursor)
+		if err != nil {
+			return err
+		}
+
+		if n > 0 {
+			logger.Infof("Removed %d superseded operation journal entries", n)
+		}
+		return nil
+	}); err != nil {
+		logger.Errorf("Error compacting operation journal: %v", err)
+	}
+}
+
 func (j *cleanJob) cleanEmptyGalleries(ctx context.Context) {
 	const batchSize = 1000
 	var toClean []int
@@ -144,13 +172,15 @@ type cleanFilter struct {
 }
 
 func newCleanFilter(c *config.Instance) *cleanFilter {
+	stashPaths := c.GetStashPaths()
 	return &cleanFilter{
 		scanFilter: scanFilter{
 			extensionConfig:   newExtensionConfig(c),
-			stashPaths:        c.GetStashPaths(),
+			stashPaths:        stashPaths,
 			generatedPath:     c.GetGeneratedPath(),
 			videoExcludeRegex: generateRegexps(c.GetExcludes()),
 			imageExcludeRegex: generateRegexps(c.GetImageExcludes()),
+			stashPatterns:     compileStashPathPatterns(stashPaths),
 		},
 	}
 }
@@ -194,6 +224,11 @@ func (f *cleanFilter) shouldCleanFolder(path string, s *config.StashConfig) bool
 		return true
 	}
 
+	if f.stashPatterns[s.Path].excludesFolder(path) {
+		logger.Infof("Folder matched stash path exclusion patterns. Marking to clean: \"%s\"", path)
+		return true
+	}
+
 	return false
 }
 
@@ -222,6 +257,11 @@ func (f *cleanFilter) shouldCleanVideoFile(path string, stash *config.StashConfi
 		return true
 	}
 
+	if f.stashPatterns[stash.Path].excludesVideo(path) {
+		logger.Infof("File matched stash path patterns. Marking to clean: \"%s\"", path)
+		return true
+	}
+
 	return false
 }
 
@@ -236,6 +276,11 @@ func (f *cleanFilter) shouldCleanGallery(path string, stash *config.StashConfig)
 		return true
 	}
 
+	if f.stashPatterns[stash.Path].excludesImage(path) {
+		logger.Infof("File matched stash path patterns. Marking to clean: \"%s\"", path)
+		return true
+	}
+
 	return false
 }
 
@@ -250,6 +295,11 @@ func (f *cleanFilter) shouldCleanImage(path string, stash *config.StashConfig) b
 		return true
 	}
 
+	if f.stashPatterns[stash.Path].excludesImage(path) {
+		logger.Infof("File matched stash path patterns. Marking to clean: \"%s\"", path)
+		return true
+	}
+
 	return false
 }
 
@@ -451,19 +501,22 @@ func (h *cleanHandler) handleRelatedImages(ctx context.Context, fileDeleter *fil
 }
 
 func getStashFromPath(stashes []*config.StashConfig, pathToCheck string) *config.StashConfig {
+	return getStashFromDirPath(stashes, filepath.Dir(pathToCheck))
+}
+
+func getStashFromDirPath(stashes []*config.StashConfig, pathToCheck string) *config.StashConfig {
 	for _, f := range stashes {
-		if fsutil.IsPathInDir(f.Path, filepath.Dir(pathToCheck)) {
+		if fsutil.IsPathInDir(f.Path, pathToCheck) {
 			return f
 		}
 	}
-	return nil
-}
 
-func getStashFromDirPath(stashes []*config.StashConfig, pathToCheck string) *config.StashConfig {
+	// paths on case-insensitive stash paths may differ by case
 	for _, f := range stashes {
-		if fsutil.IsPathInDir(f.Path, pathToCheck) {
+		if fsutil.IsPathInDirFold(f.Path, pathToCheck) && isStashPathCaseInsensitive(f.Path) {
 			return f
 		}
 	}
+
 	return nil
 }
@@ -0,0 +1,92 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+var ErrDownloadHookDisabled = errors.New("download hook is disabled")
+
+// DownloadComplete queues a scan of a completed download path, optionally
+// followed by an auto-tag of the same path. The path may be a file or a
+// directory, and must be within a configured stash path. Returns the IDs of
+// the queued jobs.
+func (s *Manager) DownloadComplete(ctx context.Context, p string) ([]int, error) {
+	if !s.Config.GetDownloadHookEnabled() {
+		return nil, ErrDownloadHookDisabled
+	}
+
+	dir, err := downloadHookDir(p)
+	if err != nil {
+		return nil, err
+	}
+
+	if getStashFromDirPath(s.Config.GetStashPaths(), dir) == nil {
+		return nil, fmt.Errorf("%s is not in the configured stash paths", p)
+	}
+
+	logger.Infof("Download completed: %s", p)
+
+	scanInput := ScanMetadataInput{
+		Paths: []string{dir},
+	}
+	if opts := s.Config.GetDefaultScanSettings(); opts != nil {
+		scanInput.ScanMetadataOptions = *opts
+	}
+
+	scanID, err := s.Scan(ctx, scanInput)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := []int{scanID}
+
+	if s.Config.GetDownloadHookAutoTag() {
+		// jobs are executed in order, so the new files will be in the
+		// database by the time the auto-tag job runs
+		autoTagInput := AutoTagMetadataInput{
+			Paths:      []string{dir},
+			Performers: []string{"*"},
+			Studios:    []string{"*"},
+			Tags:       []string{"*"},
+		}
+		if opts := s.Config.GetDefaultAutoTagSettings(); opts != nil {
+			autoTagInput.Performers = opts.Performers
+			autoTagInput.Studios = opts.Studios
+			autoTagInput.Tags = opts.Tags
+		}
+
+		ret = append(ret, s.AutoTag(ctx, autoTagInput))
+	}
+
+	return ret, nil
+}
+
+// downloadHookDir returns the directory to scan for the provided download
+// path. Single-file downloads are scanned using their parent directory.
+func downloadHookDir(p string) (string, error) {
+	if p == "" {
+		return "", errors.New("path is required")
+	}
+
+	p, err := filepath.Abs(p)
+	if err != nil {
+		return "", err
+	}
+
+	info, err := os.Stat(p)
+	if err != nil {
+		return "", err
+	}
+
+	if !info.IsDir() {
+		return filepath.Dir(p), nil
+	}
+
+	return p, nil
+}
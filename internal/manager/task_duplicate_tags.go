@@ -0,0 +1,100 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/tag"
+	"github.com/stashapp/stash/pkg/txn"
+)
+
+type FindDuplicateTagsInput struct {
+	// Minimum similarity of the names of duplicate tags, between 0 and 1
+	MinSimilarity *float64 `json:"min_similarity"`
+}
+
+// FindDuplicateTags queues a job to find tags with colliding or similar names
+// and aliases. The duplicates are stored in a job artifact.
+func (s *Manager) FindDuplicateTags(ctx context.Context, input FindDuplicateTagsInput) (int, error) {
+	minSimilarity := tag.DefaultDuplicateMinSimilarity
+	if input.MinSimilarity != nil {
+		if *input.MinSimilarity <= 0 || *input.MinSimilarity > 1 {
+			return 0, fmt.Errorf("min_similarity must be greater than 0 and at most 1")
+		}
+		minSimilarity = *input.MinSimilarity
+	}
+
+	j := job.MakeJobExec(func(ctx context.Context, progress *job.Progress) {
+		s.findDuplicateTags(ctx, minSimilarity)
+	})
+
+	return s.JobManager.Add(ctx, "Finding duplicate tags...", j), nil
+}
+
+// duplicateTag is a tag in the duplicate tags artifact.
+type duplicateTag struct {
+	ID      int      `json:"id"`
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases"`
+}
+
+func (s *Manager) findDuplicateTags(ctx context.Context, minSimilarity float64) {
+	r := s.Repository
+
+	var candidates []tag.DuplicateCandidate
+	if err := txn.WithReadTxn(ctx, r, func(ctx context.Context) error {
+		tags, err := r.Tag.All(ctx)
+		if err != nil {
+			return err
+		}
+
+		candidates = make([]tag.DuplicateCandidate, len(tags))
+		for i, t := range tags {
+			aliases, err := r.Tag.GetAliases(ctx, t.ID)
+			if err != nil {
+				return fmt.Errorf("getting aliases of tag %s: %w", t.Name, err)
+			}
+
+			candidates[i] = tag.DuplicateCandidate{Tag: t, Aliases: aliases}
+		}
+
+		return nil
+	}); err != nil {
+		logger.Errorf("Error finding duplicate tags: %v", err)
+		return
+	}
+
+	aliases := make(map[int][]string, len(candidates))
+	for _, c := range candidates {
+		aliases[c.Tag.ID] = c.Aliases
+	}
+
+	groups := tag.FindDuplicates(candidates, minSimilarity)
+	logger.Infof("Found %d groups of duplicate tags", len(groups))
+
+	if len(groups) == 0 {
+		return
+	}
+
+	report := make([][]duplicateTag, len(groups))
+	for i, g := range groups {
+		report[i] = make([]duplicateTag, len(g))
+		for j, t := range g {
+			report[i][j] = duplicateTag{ID: t.ID, Name: t.Name, Aliases: aliases[t.ID]}
+		}
+	}
+
+	name := "duplicate-tags-" + time.Now().Format("20060102-150405") + ".json"
+	if _, err := s.AddJobArtifact(ctx, name, "application/json", func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}); err != nil {
+		logger.Errorf("Error storing duplicate tags: %v", err)
+	}
+}
@@ -51,9 +51,17 @@ type ExportTask struct {
 
 	includeDependencies bool
 
+	// lowMemory loads all scenes and images in batches, and exports with a
+	// single worker
+	lowMemory bool
+
 	DownloadHash string
 }
 
+// exportBatchSize is the number of scenes or images loaded at a time when
+// exporting all of them in low memory mode.
+const exportBatchSize = 1000
+
 type ExportObjectTypeInput struct {
 	Ids []string `json:"ids"`
 	All *bool    `json:"all"`
@@ -118,6 +126,11 @@ func (t *ExportTask) Start(ctx context.Context, wg *sync.WaitGroup) {
 	// @manager.total = Scene.count + Gallery.count + Performer.count + Studio.count + Movie.count
 	workerCount := runtime.GOMAXPROCS(0) // set worker count to number of cpus available
 
+	t.lowMemory = config.GetInstance().GetLowMemoryMode()
+	if t.lowMemory {
+		workerCount = 1
+	}
+
 	startTime := time.Now()
 
 	if t.full {
@@ -356,7 +369,10 @@ func (t *ExportTask) ExportScenes(ctx context.Context, workers int, repo Reposit
 	var scenes []*models.Scene
 	var err error
 	all := t.full || (t.scenes != nil && t.scenes.all)
-	if all {
+	batched := all && t.lowMemory
+	if batched {
+		// scenes are loaded when they are fed to the workers
+	} else if all {
 		scenes, err = sceneReader.All(ctx)
 	} else if t.scenes != nil && len(t.scenes.IDs) > 0 {
 		scenes, err = sceneReader.FindMany(ctx, t.scenes.IDs)
@@ -376,6 +392,12 @@ func (t *ExportTask) ExportScenes(ctx context.Context, workers int, repo Reposit
 		go exportScene(ctx, &scenesWg, jobCh, repo, t)
 	}
 
+	if batched {
+		if err := feedSceneBatches(ctx, sceneReader, jobCh); err != nil {
+			logger.Errorf("[scenes] failed to fetch scenes: %s", err.Error())
+		}
+	}
+
 	for i, scene := range scenes {
 		index := i + 1
 
@@ -391,6 +413,40 @@ func (t *ExportTask) ExportScenes(ctx context.Context, workers int, repo Reposit
 	logger.Infof("[scenes] export complete in %s. %d workers used.", time.Since(startTime), workers)
 }
 
+// feedSceneBatches feeds all scenes to the workers, loading exportBatchSize
+// scenes at a time.
+func feedSceneBatches(ctx context.Context, r SceneReaderWriter, jobCh chan<- *models.Scene) error {
+	total, err := r.Count(ctx)
+	if err != nil {
+		return err
+	}
+
+	sort := "id"
+	findFilter := models.BatchFindFilter(exportBatchSize)
+	findFilter.Sort = &sort
+
+	index := 0
+	for {
+		scenes, err := scene.Query(ctx, r, nil, findFilter)
+		if err != nil {
+			return err
+		}
+
+		for _, s := range scenes {
+			if (index % 100) == 0 { // make progress easier to read
+				logger.Progressf("[scenes] %d of %d", index+1, total)
+			}
+			index++
+			jobCh <- s // feed workers
+		}
+
+		if len(scenes) != exportBatchSize {
+			return nil
+		}
+		*findFilter.Page++
+	}
+}
+
 func exportFile(f file.File, t *ExportTask) {
 	newFileJSON := fileToJSON(f)
 
@@ -603,7 +659,10 @@ func (t *ExportTask) ExportImages(ctx context.Context, workers int, repo Reposit
 	var images []*models.Image
 	var err error
 	all := t.full || (t.images != nil && t.images.all)
-	if all {
+	batched := all && t.lowMemory
+	if batched {
+		// images are loaded when they are fed to the workers
+	} else if all {
 		images, err = imageReader.All(ctx)
 	} else if t.images != nil && len(t.images.IDs) > 0 {
 		images, err = imageReader.FindMany(ctx, t.images.IDs)
@@ -623,6 +682,12 @@ func (t *ExportTask) ExportImages(ctx context.Context, workers int, repo Reposit
 		go exportImage(ctx, &imagesWg, jobCh, repo, t)
 	}
 
+	if batched {
+		if err := feedImageBatches(ctx, imageReader, jobCh); err != nil {
+			logger.Errorf("[images] failed to fetch images: %s", err.Error())
+		}
+	}
+
 	for i, image := range images {
 		index := i + 1
 
@@ -638,6 +703,40 @@ func (t *ExportTask) ExportImages(ctx context.Context, workers int, repo Reposit
 	logger.Infof("[images] export complete in %s. %d workers used.", time.Since(startTime), workers)
 }
 
+// feedImageBatches feeds all images to the workers, loading exportBatchSize
+// images at a time.
+func feedImageBatches(ctx context.Context, r ImageReaderWriter, jobCh chan<- *models.Image) error {
+	total, err := r.Count(ctx)
+	if err != nil {
+		return err
+	}
+
+	sort := "id"
+	findFilter := models.BatchFindFilter(exportBatchSize)
+	findFilter.Sort = &sort
+
+	index := 0
+	for {
+		images, err := image.Query(ctx, r, nil, findFilter)
+		if err != nil {
+			return err
+		}
+
+		for _, img := range images {
+			if (index % 100) == 0 { // make progress easier to read
+				logger.Progressf("[images] %d of %d", index+1, total)
+			}
+			index++
+			jobCh <- img // feed workers
+		}
+
+		if len(images) != exportBatchSize {
+			return nil
+		}
+		*findFilter.Page++
+	}
+}
+
 func exportImage(ctx context.Context, wg *sync.WaitGroup, jobChan <-chan *models.Image, repo Repository, t *ExportTask) {
 	defer wg.Done()
 	studioReader := repo.Studio
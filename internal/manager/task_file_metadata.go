@@ -0,0 +1,446 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene"
+	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
+	"github.com/stashapp/stash/pkg/txn"
+)
+
+// ImportEmbeddedCoverTask sets the cover of a scene to the cover art embedded
+// in its video file, if the scene does not already have a cover.
+type ImportEmbeddedCoverTask struct {
+	Scene      *models.Scene
+	File       *file.VideoFile
+	TxnManager Repository
+}
+
+func (t *ImportEmbeddedCoverTask) GetDescription() string {
+	return fmt.Sprintf("Importing cover art for %s", t.File.Path)
+}
+
+func (t *ImportEmbeddedCoverTask) Start(ctx context.Context) {
+	if t.File.Metadata == nil || t.File.Metadata.CoverStreamIndex < 0 {
+		return
+	}
+
+	r := t.TxnManager
+	var hasCover bool
+	if err := txn.WithReadTxn(ctx, r, func(ctx context.Context) error {
+		cover, err := r.Scene.GetCover(ctx, t.Scene.ID)
+		hasCover = len(cover) > 0
+		return err
+	}); err != nil {
+		logger.Errorf("error getting cover of %s: %v", t.File.Path, err)
+		return
+	}
+
+	if hasCover {
+		return
+	}
+
+	cover, err := instance.FFMPEG.GenerateOutput(ctx, extractCoverArgs(t.File.Path, t.File.Metadata.CoverStreamIndex), nil)
+	if err != nil {
+		logger.Errorf("error extracting cover art from %s: %v", t.File.Path, err)
+		return
+	}
+
+	if len(cover) == 0 {
+		return
+	}
+
+	if err := txn.WithTxn(ctx, r, func(ctx context.Context) error {
+		return r.Scene.UpdateCover(ctx, t.Scene.ID, cover)
+	}); err != nil {
+		logger.Errorf("error setting cover of %s: %v", t.File.Path, err)
+		return
+	}
+
+	logger.Infof("Set cover of %s from embedded cover art", t.File.Path)
+}
+
+func extractCoverArgs(input string, streamIndex int) ffmpeg.Args {
+	var args ffmpeg.Args
+	args = args.LogLevel(ffmpeg.LogLevelError)
+	args = args.Input(input)
+	args = append(args, "-map", fmt.Sprintf("0:%d", streamIndex))
+	args = args.VideoCodec(ffmpeg.VideoCodecCopy)
+	args = args.VideoFrames(1)
+	args = args.Format("image2pipe")
+	args = args.Output("-")
+
+	return args
+}
+
+type EmbedMetadataInput struct {
+	// IDs of scenes to embed metadata into, null for all organized scenes
+	SceneIDs []string `json:"sceneIds"`
+	// Embed the scene cover as cover art
+	Cover bool `json:"cover"`
+	// Do a dry run. Don't modify any files
+	DryRun bool `json:"dryRun"`
+}
+
+// EmbedMetadata queues a job which writes the metadata of scenes into the
+// container tags of their primary file, so that it is available to other
+// applications. Only mp4, m4v, mov and mkv files are supported.
+func (s *Manager) EmbedMetadata(ctx context.Context, input EmbedMetadataInput) (int, error) {
+	if err := s.validateFFMPEG(); err != nil {
+		return 0, err
+	}
+
+	j := embedMetadataJob{
+		txnManager: s.Repository,
+		input:      input,
+	}
+
+	return s.JobManager.AddClass(ctx, "Embedding metadata...", &j, job.ClassCPU), nil
+}
+
+// embedFormat returns the ffmpeg format used to write the file with the
+// metadata embedded. Returns false if embedding is not supported for the
+// container of the file.
+func embedFormat(path string) (ffmpeg.Format, bool) {
+	ext := strings.TrimPrefix(filepath.Ext(path), ".")
+	format, ok := containerFormat(ext)
+	if !ok || format == ffmpeg.FormatWebm {
+		return "", false
+	}
+
+	return format, true
+}
+
+// embeddedMetadata is the scene metadata written to the container tags.
+type embeddedMetadata struct {
+	Title       string
+	Date        string
+	Details     string
+	Artist      string
+	AlbumArtist string
+}
+
+// tags returns the ffmpeg metadata keys and values. The performers and studio
+// are written to the artist and album artist tags, which are read when
+// scanning with useFileMetadata.
+func (m embeddedMetadata) tags() [][2]string {
+	return [][2]string{
+		{"title", m.Title},
+		{"date", m.Date},
+		{"description", m.Details},
+		{"comment", m.Details},
+		{"artist", m.Artist},
+		{"album_artist", m.AlbumArtist},
+	}
+}
+
+// embedMetadataArgs returns the arguments to copy the streams of input to
+// output, setting the container tags to the metadata. If cover is set, then
+// the image at that path replaces the existing cover art. Data streams are
+// not copied, since they cannot always be written to the output container.
+func embedMetadataArgs(input string, cover string, output string, format ffmpeg.Format, m embeddedMetadata, probe *ffmpeg.VideoFile) ffmpeg.Args {
+	var args ffmpeg.Args
+	args = args.LogLevel(ffmpeg.LogLevelError).Overwrite()
+	args = args.Input(input)
+	if cover != "" {
+		args = args.Input(cover)
+	}
+
+	// 0:V excludes cover art
+	args = append(args, "-map", "0:V", "-map", "0:a?", "-map", "0:s?", "-map", "0:t?")
+
+	switch {
+	case cover != "":
+		videoStreams := 0
+		for _, s := range probe.JSON.Streams {
+			if s.CodecType == "video" && s.Disposition.AttachedPic == 0 {
+				videoStreams++
+			}
+		}
+
+		coverStream := fmt.Sprintf("v:%d", videoStreams)
+		args = append(args, "-map", "1", "-disposition:"+coverStream, "attached_pic")
+		if format == ffmpeg.FormatMatroska {
+			// cover art is stored as an attachment in matroska
+			args = append(args,
+				"-metadata:s:"+coverStream, "mimetype=image/jpeg",
+				"-metadata:s:"+coverStream, "filename=cover.jpg",
+			)
+		}
+	case probe.CoverStream != nil:
+		args = append(args, "-map", fmt.Sprintf("0:%d", probe.CoverStream.Index))
+	}
+
+	args = append(args, "-c", "copy")
+
+	for _, t := range m.tags() {
+		if t[1] != "" {
+			args = append(args, "-metadata", t[0]+"="+t[1])
+		}
+	}
+
+	if format == ffmpeg.FormatMP4 {
+		args = append(args, "-movflags", "+faststart")
+	}
+
+	args = args.Format(format)
+	args = args.Output(output)
+
+	return args
+}
+
+type embedMetadataJob struct {
+	txnManager Repository
+	input      EmbedMetadataInput
+}
+
+func (j *embedMetadataJob) Execute(ctx context.Context, progress *job.Progress) {
+	logger.Infof("Starting embed metadata task")
+	if j.input.DryRun {
+		logger.Infof("Running in Dry Mode")
+	}
+
+	scenes, err := j.findScenes(ctx)
+	if err != nil {
+		logger.Errorf("Error finding scenes to embed metadata into: %v", err)
+		return
+	}
+
+	progress.SetTotal(len(scenes))
+
+	var dirs []string
+	var embedded int
+	for _, s := range scenes {
+		if job.IsCancelled(ctx) {
+			logger.Info("Stopping due to user request")
+			break
+		}
+
+		f := s.Files.Primary()
+		progress.ExecuteTask(fmt.Sprintf("Embedding metadata into %s", f.Path), func() {
+			if err := j.embed(ctx, s, f); err != nil {
+				logger.Errorf("Error embedding metadata into %s: %v", f.Path, err)
+				return
+			}
+
+			embedded++
+			dirs = stringslice.StrAppendUnique(dirs, filepath.Dir(f.Path))
+		})
+
+		progress.Increment()
+	}
+
+	if j.input.DryRun {
+		logger.Infof("Finished embed metadata task. Would embed metadata into %d files", embedded)
+		return
+	}
+
+	logger.Infof("Finished embed metadata task. Embedded metadata into %d files", embedded)
+
+	if len(dirs) > 0 {
+		// rescan to update the fingerprints of the modified files
+		if _, err := instance.Scan(ctx, ScanMetadataInput{Paths: dirs}); err != nil {
+			logger.Errorf("Error queueing scan of modified files: %v", err)
+		}
+	}
+}
+
+func (j *embedMetadataJob) findScenes(ctx context.Context) ([]*models.Scene, error) {
+	var ret []*models.Scene
+	r := j.txnManager
+
+	add := func(ctx context.Context, s *models.Scene) error {
+		if err := s.LoadPrimaryFile(ctx, r.File); err != nil {
+			return err
+		}
+
+		f := s.Files.Primary()
+		if f == nil {
+			return nil
+		}
+
+		if _, ok := embedFormat(f.Path); !ok {
+			logger.Debugf("Skipping %s: embedding metadata is not supported for the container", f.Path)
+			return nil
+		}
+
+		ret = append(ret, s)
+		return nil
+	}
+
+	if err := txn.WithReadTxn(ctx, r, func(ctx context.Context) error {
+		if len(j.input.SceneIDs) == 0 {
+			organized := true
+			filter := &models.SceneFilterType{
+				Organized: &organized,
+			}
+			return scene.BatchProcess(ctx, r.Scene, filter, nil, func(s *models.Scene) error {
+				return add(ctx, s)
+			})
+		}
+
+		ids, err := stringslice.StringSliceToIntSlice(j.input.SceneIDs)
+		if err != nil {
+			return err
+		}
+
+		scenes, err := r.Scene.FindMany(ctx, ids)
+		if err != nil {
+			return err
+		}
+
+		for _, s := range scenes {
+			if err := add(ctx, s); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// sceneMetadata returns the metadata of the scene to embed, and its cover if
+// cover is true.
+func (j *embedMetadataJob) sceneMetadata(ctx context.Context, s *models.Scene, cover bool) (embeddedMetadata, []byte, error) {
+	ret := embeddedMetadata{
+		Title:   s.Title,
+		Details: s.Details,
+	}
+	if s.Date != nil {
+		ret.Date = s.Date.String()
+	}
+
+	var coverImage []byte
+	r := j.txnManager
+	if err := txn.WithReadTxn(ctx, r, func(ctx context.Context) error {
+		if err := s.LoadPerformerIDs(ctx, r.Scene); err != nil {
+			return err
+		}
+
+		performers, err := r.Performer.FindMany(ctx, s.PerformerIDs.List())
+		if err != nil {
+			return err
+		}
+
+		var names []string
+		for _, p := range performers {
+			names = append(names, p.Name)
+		}
+		ret.Artist = strings.Join(names, ", ")
+
+		if s.StudioID != nil {
+			studio, err := r.Studio.Find(ctx, *s.StudioID)
+			if err != nil {
+				return err
+			}
+			if studio != nil {
+				ret.AlbumArtist = studio.Name.String
+			}
+		}
+
+		if cover {
+			coverImage, err = r.Scene.GetCover(ctx, s.ID)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return ret, nil, err
+	}
+
+	return ret, coverImage, nil
+}
+
+func (j *embedMetadataJob) embed(ctx context.Context, s *models.Scene, f *file.VideoFile) error {
+	format, _ := embedFormat(f.Path)
+
+	m, coverImage, err := j.sceneMetadata(ctx, s, j.input.Cover)
+	if err != nil {
+		return err
+	}
+
+	if j.input.DryRun {
+		logger.Infof("[dry run] Would embed metadata into %s", f.Path)
+		return nil
+	}
+
+	original, err := instance.FFProbe.NewVideoFile(f.Path)
+	if err != nil {
+		return fmt.Errorf("probing original: %w", err)
+	}
+
+	var coverPath string
+	if len(coverImage) > 0 {
+		if err := instance.Paths.Generated.EnsureTmpDir(); err != nil {
+			return err
+		}
+
+		tmp, err := instance.Paths.Generated.TempFile("cover-*.jpg")
+		if err != nil {
+			return err
+		}
+		coverPath = tmp.Name()
+		defer os.Remove(coverPath)
+
+		_, err = tmp.Write(coverImage)
+		if closeErr := tmp.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("writing cover: %w", err)
+		}
+	}
+
+	tempPath := f.Path + replacementTempSuffix
+	removeTemp := func() {
+		if err := os.Remove(tempPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			logger.Warnf("error removing %s: %v", tempPath, err)
+		}
+	}
+
+	logger.Infof("Embedding metadata into %s", f.Path)
+	start := time.Now()
+
+	if err := instance.FFMPEG.Generate(ctx, embedMetadataArgs(f.Path, coverPath, tempPath, format, m, original)); err != nil {
+		removeTemp()
+		return err
+	}
+
+	probed, err := instance.FFProbe.NewVideoFile(tempPath)
+	if err != nil {
+		removeTemp()
+		return fmt.Errorf("probing output: %w", err)
+	}
+
+	if err := verifyReplacement(f, probed, original.VideoCodec); err != nil {
+		removeTemp()
+		return fmt.Errorf("verifying output: %w", err)
+	}
+
+	KillRunningStreams(s, instance.Config.GetVideoFileNamingAlgorithm())
+
+	if err := replaceVideoFile(ctx, j.txnManager, f, tempPath, f.Path); err != nil {
+		removeTemp()
+		return err
+	}
+
+	logger.Infof("Embedded metadata into %s in %s", f.Path, time.Since(start).Round(time.Second))
+	return nil
+}
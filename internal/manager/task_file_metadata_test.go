@@ -0,0 +1,84 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmbedFormat(t *testing.T) {
+	tests := []struct {
+		path   string
+		want   ffmpeg.Format
+		wantOK bool
+	}{
+		{"a.mp4", ffmpeg.FormatMP4, true},
+		{"a.M4V", ffmpeg.FormatMP4, true},
+		{"a.mkv", ffmpeg.FormatMatroska, true},
+		{"a.webm", "", false},
+		{"a.avi", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := embedFormat(tt.path)
+		assert.Equal(t, tt.want, got, tt.path)
+		assert.Equal(t, tt.wantOK, ok, tt.path)
+	}
+}
+
+func TestEmbedMetadataArgs(t *testing.T) {
+	m := embeddedMetadata{
+		Title:       "Title",
+		Artist:      "A, B",
+		AlbumArtist: "Studio",
+	}
+
+	coverStream := ffmpeg.FFProbeStream{Index: 2, CodecType: "video"}
+	coverStream.Disposition.AttachedPic = 1
+
+	probe := &ffmpeg.VideoFile{CoverStream: &coverStream}
+	probe.JSON.Streams = []ffmpeg.FFProbeStream{
+		{Index: 0, CodecType: "video"},
+		{Index: 1, CodecType: "audio"},
+		coverStream,
+	}
+
+	maps := []string{"-map", "0:V", "-map", "0:a?", "-map", "0:s?", "-map", "0:t?"}
+	tags := []string{"-metadata", "title=Title", "-metadata", "artist=A, B", "-metadata", "album_artist=Studio"}
+
+	join := func(parts ...[]string) ffmpeg.Args {
+		var ret ffmpeg.Args
+		for _, p := range parts {
+			ret = append(ret, p...)
+		}
+		return ret
+	}
+
+	t.Run("keep cover", func(t *testing.T) {
+		got := embedMetadataArgs("in.mp4", "", "out", ffmpeg.FormatMP4, m, probe)
+		assert.Equal(t, join(
+			[]string{"-v", "error", "-y", "-i", "in.mp4"},
+			maps,
+			[]string{"-map", "0:2", "-c", "copy"},
+			tags,
+			[]string{"-movflags", "+faststart", "-f", "mp4", "out"},
+		), got)
+	})
+
+	t.Run("replace cover", func(t *testing.T) {
+		got := embedMetadataArgs("in.mkv", "cover.jpg", "out", ffmpeg.FormatMatroska, m, probe)
+		assert.Equal(t, join(
+			[]string{"-v", "error", "-y", "-i", "in.mkv", "-i", "cover.jpg"},
+			maps,
+			[]string{
+				"-map", "1", "-disposition:v:1", "attached_pic",
+				"-metadata:s:v:1", "mimetype=image/jpeg",
+				"-metadata:s:v:1", "filename=cover.jpg",
+				"-c", "copy",
+			},
+			tags,
+			[]string{"-f", "matroska", "out"},
+		), got)
+	})
+}
@@ -18,6 +18,7 @@ import (
 
 type GenerateMetadataInput struct {
 	Sprites             *bool                        `json:"sprites"`
+	HoverStrips         *bool                        `json:"hoverStrips"`
 	Previews            *bool                        `json:"previews"`
 	ImagePreviews       *bool                        `json:"imagePreviews"`
 	PreviewOptions      *GeneratePreviewOptionsInput `json:"previewOptions"`
@@ -29,6 +30,10 @@ type GenerateMetadataInput struct {
 	ForceTranscodes           *bool `json:"forceTranscodes"`
 	Phashes                   *bool `json:"phashes"`
 	InteractiveHeatmapsSpeeds *bool `json:"interactiveHeatmapsSpeeds"`
+	// Generate markers for high intensity sections of interactive scenes
+	InteractiveMarkers *bool `json:"interactiveMarkers"`
+	// Generate thumbnails for the chapters of scenes
+	ChapterThumbnails *bool `json:"chapterThumbnails"`
 	// scene ids to generate for
 	SceneIDs []string `json:"sceneIDs"`
 	// marker ids to generate for
@@ -50,24 +55,30 @@ type GeneratePreviewOptionsInput struct {
 	PreviewPreset *models.PreviewPreset `json:"previewPreset"`
 }
 
-const generateQueueSize = 200000
-
 type GenerateJob struct {
 	txnManager Repository
 	input      GenerateMetadataInput
 
 	overwrite      bool
 	fileNamingAlgo models.HashAlgorithm
+
+	// last processed scene ID of each generator, if resuming an
+	// interrupted job
+	resumeFrom map[string]int
+	checkpoint *generateCheckpoint
 }
 
 type totalsGenerate struct {
 	sprites                  int64
+	hoverStrips              int64
 	previews                 int64
 	imagePreviews            int64
 	markers                  int64
 	transcodes               int64
 	phashes                  int64
 	interactiveHeatmapSpeeds int64
+	interactiveMarkers       int64
+	chapterThumbnails        int64
 
 	tasks int
 }
@@ -87,9 +98,28 @@ func (j *GenerateJob) Execute(ctx context.Context, progress *job.Progress) {
 
 	logger.Infof("Generate started with %d parallel tasks", parallelTasks)
 
-	queue := make(chan Task, generateQueueSize)
+	// only generating for the whole library is checkpointed
+	if len(j.input.SceneIDs) == 0 && len(j.input.MarkerIDs) == 0 {
+		if err := j.startCheckpoint(ctx); err != nil {
+			logger.Warnf("could not create generate checkpoint: %v", err)
+			j.checkpoint = nil
+		}
+	}
+
+	if j.checkpoint != nil {
+		stopSaving := j.saveCheckpointPeriodically(ctx)
+
+		// the checkpoint is only kept if stash is stopped while generating
+		defer func() {
+			stopSaving()
+			j.clearCheckpoint(context.Background())
+		}()
+	}
+
+	// the queued tasks may be skipped and reprioritised while generating
+	queue := progress.Items()
 	go func() {
-		defer close(queue)
+		defer queue.Close()
 
 		var totals totalsGenerate
 		sceneIDs, err := stringslice.StringSliceToIntSlice(j.input.SceneIDs)
@@ -142,7 +172,7 @@ func (j *GenerateJob) Execute(ctx context.Context, progress *job.Progress) {
 			return
 		}
 
-		logger.Infof("Generating %d sprites %d previews %d image previews %d markers %d transcodes %d phashes %d heatmaps & speeds", totals.sprites, totals.previews, totals.imagePreviews, totals.markers, totals.transcodes, totals.phashes, totals.interactiveHeatmapSpeeds)
+		logger.Infof("Generating %d sprites %d hover strips %d previews %d image previews %d markers %d transcodes %d phashes %d heatmaps & speeds %d interactive markers %d chapter thumbnails", totals.sprites, totals.hoverStrips, totals.previews, totals.imagePreviews, totals.markers, totals.transcodes, totals.phashes, totals.interactiveHeatmapSpeeds, totals.interactiveMarkers, totals.chapterThumbnails)
 
 		progress.SetTotal(int(totals.tasks))
 	}()
@@ -161,17 +191,31 @@ func (j *GenerateJob) Execute(ctx context.Context, progress *job.Progress) {
 		}
 	}()
 
-	for f := range queue {
-		if job.IsCancelled(ctx) {
+	for !job.IsCancelled(ctx) {
+		// wait for a free slot before taking the next task, so that the
+		// task of the highest priority is started
+		wg.Add()
+		item, ok := queue.Pop(ctx)
+		if !ok {
+			wg.Done()
 			break
 		}
 
-		wg.Add()
-		// #1879 - need to make a copy of f - otherwise there is a race condition
-		// where f is changed when the goroutine runs
-		localTask := f
+		localTask := item.Value().(Task)
+		taskCtx, started := queue.Start(ctx, item)
+		if !started {
+			// skipped while queued
+			if t, ok := localTask.(*checkpointTask); ok {
+				t.done()
+			}
+			wg.Done()
+			progress.Increment()
+			continue
+		}
+
 		go progress.ExecuteTask(localTask.GetDescription(), func() {
-			localTask.Start(ctx)
+			localTask.Start(taskCtx)
+			queue.Finish(item, nil)
 			wg.Done()
 			progress.Increment()
 		})
@@ -186,14 +230,20 @@ func (j *GenerateJob) Execute(ctx context.Context, progress *job.Progress) {
 
 	elapsed := time.Since(start)
 	logger.Info(fmt.Sprintf("Generate finished (%s)", elapsed))
+	instance.dispatchGenerateFinishedWebhook(elapsed)
 }
 
-func (j *GenerateJob) queueTasks(ctx context.Context, g *generate.Generator, queue chan<- Task) totalsGenerate {
+func (j *GenerateJob) queueTasks(ctx context.Context, g *generate.Generator, queue *job.ItemQueue) totalsGenerate {
 	var totals totalsGenerate
 
 	const batchSize = 1000
 
 	findFilter := models.BatchFindFilter(batchSize)
+	// scenes must be queued in ID order for checkpoints
+	sort := "id"
+	direction := models.SortDirectionEnumAsc
+	findFilter.Sort = &sort
+	findFilter.Direction = &direction
 
 	for more := true; more; {
 		if job.IsCancelled(ctx) {
@@ -217,6 +267,7 @@ func (j *GenerateJob) queueTasks(ctx context.Context, g *generate.Generator, que
 			}
 
 			j.queueSceneJobs(ctx, g, ss, queue, &totals)
+			j.checkpoint.sceneQueued(ss.ID)
 		}
 
 		if len(scenes) != batchSize {
@@ -238,6 +289,7 @@ func getGeneratePreviewOptions(optionsInput GeneratePreviewOptionsInput) generat
 		ExcludeStart:    config.GetPreviewExcludeStart(),
 		ExcludeEnd:      config.GetPreviewExcludeEnd(),
 		Preset:          config.GetPreviewPreset().String(),
+		Profile:         config.GetTranscodeProfile(config.GetPreviewTranscodeProfile()),
 		Audio:           config.GetPreviewAudio(),
 	}
 
@@ -264,8 +316,13 @@ func getGeneratePreviewOptions(optionsInput GeneratePreviewOptionsInput) generat
 	return ret
 }
 
-func (j *GenerateJob) queueSceneJobs(ctx context.Context, g *generate.Generator, scene *models.Scene, queue chan<- Task, totals *totalsGenerate) {
-	if utils.IsTrue(j.input.Sprites) {
+func (j *GenerateJob) queueSceneJobs(ctx context.Context, g *generate.Generator, scene *models.Scene, queue *job.ItemQueue, totals *totalsGenerate) {
+	// linked-only scenes have no files to generate from
+	if scene.Files.Primary() == nil {
+		return
+	}
+
+	if utils.IsTrue(j.input.Sprites) && !j.checkpoint.skip(generatorSprites, scene.ID) {
 		task := &GenerateSpriteTask{
 			Scene:               *scene,
 			Overwrite:           j.overwrite,
@@ -275,7 +332,7 @@ func (j *GenerateJob) queueSceneJobs(ctx context.Context, g *generate.Generator,
 		if j.overwrite || task.required() {
 			totals.sprites++
 			totals.tasks++
-			queue <- task
+			j.queueTask(queue, generatorSprites, scene.ID, task)
 		}
 	}
 
@@ -285,7 +342,29 @@ func (j *GenerateJob) queueSceneJobs(ctx context.Context, g *generate.Generator,
 	}
 	options := getGeneratePreviewOptions(*generatePreviewOptions)
 
-	if utils.IsTrue(j.input.Previews) {
+	if utils.IsTrue(j.input.HoverStrips) && !j.checkpoint.skip(generatorHoverStrips, scene.ID) {
+		task := &GenerateHoverStripTask{
+			Scene: *scene,
+			Options: generate.HoverStripOptions{
+				Segments:        generate.DefaultHoverStripSegments,
+				SegmentDuration: generate.DefaultHoverStripSegmentDuration,
+				ExcludeStart:    options.ExcludeStart,
+				ExcludeEnd:      options.ExcludeEnd,
+				Preset:          options.Preset,
+			},
+			Overwrite:           j.overwrite,
+			fileNamingAlgorithm: j.fileNamingAlgo,
+			generator:           g,
+		}
+
+		if j.overwrite || task.required() {
+			totals.hoverStrips++
+			totals.tasks++
+			j.queueTask(queue, generatorHoverStrips, scene.ID, task)
+		}
+	}
+
+	if utils.IsTrue(j.input.Previews) && !j.checkpoint.skip(generatorPreviews, scene.ID) {
 		task := &GeneratePreviewTask{
 			Scene:               *scene,
 			ImagePreview:        utils.IsTrue(j.input.ImagePreviews),
@@ -309,12 +388,12 @@ func (j *GenerateJob) queueSceneJobs(ctx context.Context, g *generate.Generator,
 
 			if addTask {
 				totals.tasks++
-				queue <- task
+				j.queueTask(queue, generatorPreviews, scene.ID, task)
 			}
 		}
 	}
 
-	if utils.IsTrue(j.input.Markers) {
+	if utils.IsTrue(j.input.Markers) && !j.checkpoint.skip(generatorMarkers, scene.ID) {
 		task := &GenerateMarkersTask{
 			TxnManager:          j.txnManager,
 			Scene:               scene,
@@ -331,11 +410,11 @@ func (j *GenerateJob) queueSceneJobs(ctx context.Context, g *generate.Generator,
 			totals.markers += int64(markers)
 			totals.tasks++
 
-			queue <- task
+			j.queueTask(queue, generatorMarkers, scene.ID, task)
 		}
 	}
 
-	if utils.IsTrue(j.input.Transcodes) {
+	if utils.IsTrue(j.input.Transcodes) && !j.checkpoint.skip(generatorTranscodes, scene.ID) {
 		forceTranscode := utils.IsTrue(j.input.ForceTranscodes)
 		task := &GenerateTranscodeTask{
 			Scene:               *scene,
@@ -347,11 +426,11 @@ func (j *GenerateJob) queueSceneJobs(ctx context.Context, g *generate.Generator,
 		if task.isTranscodeNeeded() {
 			totals.transcodes++
 			totals.tasks++
-			queue <- task
+			j.queueTask(queue, generatorTranscodes, scene.ID, task)
 		}
 	}
 
-	if utils.IsTrue(j.input.Phashes) {
+	if utils.IsTrue(j.input.Phashes) && !j.checkpoint.skip(generatorPhashes, scene.ID) {
 		// generate for all files in scene
 		for _, f := range scene.Files.List() {
 			task := &GeneratePhashTask{
@@ -365,12 +444,12 @@ func (j *GenerateJob) queueSceneJobs(ctx context.Context, g *generate.Generator,
 			if task.shouldGenerate() {
 				totals.phashes++
 				totals.tasks++
-				queue <- task
+				j.queueTask(queue, generatorPhashes, scene.ID, task)
 			}
 		}
 	}
 
-	if utils.IsTrue(j.input.InteractiveHeatmapsSpeeds) {
+	if utils.IsTrue(j.input.InteractiveHeatmapsSpeeds) && !j.checkpoint.skip(generatorInteractiveHeatmapsSpeeds, scene.ID) {
 		task := &GenerateInteractiveHeatmapSpeedTask{
 			Scene:               *scene,
 			Overwrite:           j.overwrite,
@@ -381,12 +460,67 @@ func (j *GenerateJob) queueSceneJobs(ctx context.Context, g *generate.Generator,
 		if task.shouldGenerate() {
 			totals.interactiveHeatmapSpeeds++
 			totals.tasks++
-			queue <- task
+			j.queueTask(queue, generatorInteractiveHeatmapsSpeeds, scene.ID, task)
+		}
+	}
+
+	if utils.IsTrue(j.input.InteractiveMarkers) && !j.checkpoint.skip(generatorInteractiveMarkers, scene.ID) {
+		task := &GenerateInteractiveMarkersTask{
+			Scene:               *scene,
+			Overwrite:           j.overwrite,
+			fileNamingAlgorithm: j.fileNamingAlgo,
+			TxnManager:          j.txnManager,
+			TagName:             instance.Config.GetInteractiveMarkerTag(),
+		}
+
+		if task.shouldGenerate() {
+			totals.interactiveMarkers++
+			totals.tasks++
+			j.queueTask(queue, generatorInteractiveMarkers, scene.ID, task)
 		}
 	}
+
+	if utils.IsTrue(j.input.ChapterThumbnails) && !j.checkpoint.skip(generatorChapterThumbnails, scene.ID) {
+		chapters, err := j.txnManager.File.GetChapters(ctx, scene.Files.Primary().ID)
+		if err != nil {
+			logger.Errorf("error getting chapters for %s: %v", scene.DisplayName(), err)
+			return
+		}
+
+		task := &GenerateChapterThumbnailsTask{
+			Scene:               *scene,
+			Chapters:            chapters,
+			Overwrite:           j.overwrite,
+			fileNamingAlgorithm: j.fileNamingAlgo,
+
+			generator: g,
+		}
+
+		thumbnails := task.thumbnailsNeeded()
+		if thumbnails > 0 {
+			totals.chapterThumbnails += int64(thumbnails)
+			totals.tasks++
+
+			j.queueTask(queue, generatorChapterThumbnails, scene.ID, task)
+		}
+	}
+}
+
+// queueTask adds the task to the queue, tracking it in the checkpoint if
+// there is one.
+func (j *GenerateJob) queueTask(queue *job.ItemQueue, generator string, sceneID int, task Task) {
+	if j.checkpoint != nil {
+		j.checkpoint.taskQueued(generator, sceneID)
+		task = &checkpointTask{
+			Task: task,
+			done: func() { j.checkpoint.taskDone(generator, sceneID) },
+		}
+	}
+
+	queue.Push(task.GetDescription(), task)
 }
 
-func (j *GenerateJob) queueMarkerJob(g *generate.Generator, marker *models.SceneMarker, queue chan<- Task, totals *totalsGenerate) {
+func (j *GenerateJob) queueMarkerJob(g *generate.Generator, marker *models.SceneMarker, queue *job.ItemQueue, totals *totalsGenerate) {
 	task := &GenerateMarkersTask{
 		TxnManager:          j.txnManager,
 		Marker:              marker,
@@ -396,5 +530,5 @@ func (j *GenerateJob) queueMarkerJob(g *generate.Generator, marker *models.Scene
 	}
 	totals.markers++
 	totals.tasks++
-	queue <- task
+	queue.Push(task.GetDescription(), task)
 }
@@ -0,0 +1,78 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene/generate"
+)
+
+// GenerateChapterThumbnailsTask generates a thumbnail at the start of each
+// chapter of the primary file of a scene.
+type GenerateChapterThumbnailsTask struct {
+	Scene               models.Scene
+	Chapters            []file.VideoChapter
+	Overwrite           bool
+	fileNamingAlgorithm models.HashAlgorithm
+
+	generator *generate.Generator
+}
+
+func (t *GenerateChapterThumbnailsTask) GetDescription() string {
+	return fmt.Sprintf("Generating chapter thumbnails for %s", t.Scene.Path)
+}
+
+func (t *GenerateChapterThumbnailsTask) Start(ctx context.Context) {
+	videoFile := t.Scene.Files.Primary()
+	if len(t.Chapters) == 0 || videoFile == nil {
+		return
+	}
+
+	sceneHash := t.Scene.GetHash(t.fileNamingAlgorithm)
+
+	chaptersFolder := instance.Paths.Scene.GetChapterThumbnailDir(sceneHash)
+	if err := fsutil.EnsureDir(chaptersFolder); err != nil {
+		logger.Warnf("could not create the chapters folder (%v): %v", chaptersFolder, err)
+	}
+
+	for i, chapter := range t.Chapters {
+		logger.Progressf("[generator] <%s> chapter thumbnail %d of %d", sceneHash, i+1, len(t.Chapters))
+
+		if err := t.generator.ChapterThumbnail(ctx, videoFile.Path, sceneHash, chapter.Start); err != nil {
+			logger.Errorf("[generator] failed to generate chapter thumbnail: %v", err)
+			logErrorOutput(err)
+		}
+	}
+}
+
+// thumbnailsNeeded returns the number of chapter thumbnails that will be
+// generated.
+func (t *GenerateChapterThumbnailsTask) thumbnailsNeeded() int {
+	if t.Scene.Files.Primary() == nil {
+		return 0
+	}
+
+	sceneHash := t.Scene.GetHash(t.fileNamingAlgorithm)
+	if sceneHash == "" {
+		return 0
+	}
+
+	needed := 0
+	for _, chapter := range t.Chapters {
+		if t.Overwrite {
+			needed++
+			continue
+		}
+
+		path := instance.Paths.Scene.GetChapterThumbnailPath(sceneHash, chapter.Start)
+		if exists, _ := fsutil.FileExists(path); !exists {
+			needed++
+		}
+	}
+
+	return needed
+}
@@ -0,0 +1,63 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene/generate"
+)
+
+type GenerateHoverStripTask struct {
+	Scene   models.Scene
+	Options generate.HoverStripOptions
+
+	Overwrite           bool
+	fileNamingAlgorithm models.HashAlgorithm
+
+	generator *generate.Generator
+}
+
+func (t *GenerateHoverStripTask) GetDescription() string {
+	return fmt.Sprintf("Generating hover strip for %s", t.Scene.Path)
+}
+
+func (t *GenerateHoverStripTask) Start(ctx context.Context) {
+	if !t.Overwrite && !t.required() {
+		return
+	}
+
+	ffprobe := instance.FFProbe
+	videoFile, err := ffprobe.NewVideoFile(t.Scene.Path)
+	if err != nil {
+		logger.Errorf("error reading video file: %v", err)
+		return
+	}
+
+	sceneHash := t.Scene.GetHash(t.fileNamingAlgorithm)
+	if err := t.generator.HoverStrip(ctx, t.Scene.Path, videoFile.VideoStreamDuration, sceneHash, t.Options, false); err != nil {
+		logger.Warnf("[generator] failed generating hover strip, trying fallback")
+		if err := t.generator.HoverStrip(ctx, t.Scene.Path, videoFile.VideoStreamDuration, sceneHash, t.Options, true); err != nil {
+			logger.Errorf("error generating hover strip: %v", err)
+			logErrorOutput(err)
+		}
+	}
+}
+
+// required returns true if the hover strip needs to be generated
+func (t GenerateHoverStripTask) required() bool {
+	if t.Scene.Path == "" {
+		return false
+	}
+
+	sceneHash := t.Scene.GetHash(t.fileNamingAlgorithm)
+	if sceneHash == "" {
+		return false
+	}
+
+	videoExists, _ := fsutil.FileExists(instance.Paths.Scene.GetHoverStripPath(sceneHash))
+	vttExists, _ := fsutil.FileExists(instance.Paths.Scene.GetHoverStripVttPath(sceneHash))
+	return !videoExists || !vttExists
+}
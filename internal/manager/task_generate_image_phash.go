@@ -0,0 +1,156 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/hash/imagephash"
+	"github.com/stashapp/stash/pkg/image"
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
+	"github.com/stashapp/stash/pkg/txn"
+)
+
+type GenerateImagePhashesInput struct {
+	// IDs of galleries whose images are hashed, or all images if empty
+	GalleryIDs []string `json:"galleryIds"`
+	// Regenerate existing phashes
+	Overwrite bool `json:"overwrite"`
+}
+
+// GenerateImagePhashes queues a job which generates perceptual hashes of the
+// primary files of images, used to detect bursts of near-identical images.
+func (s *Manager) GenerateImagePhashes(ctx context.Context, input GenerateImagePhashesInput) (int, error) {
+	galleryIDs, err := stringslice.StringSliceToIntSlice(input.GalleryIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	j := generateImagePhashesJob{
+		txnManager: s.Repository,
+		galleryIDs: galleryIDs,
+		overwrite:  input.Overwrite,
+	}
+
+	return s.JobManager.AddClass(ctx, "Generating image phashes...", &j, job.ClassCPU), nil
+}
+
+type generateImagePhashesJob struct {
+	txnManager Repository
+	galleryIDs []int
+	overwrite  bool
+}
+
+func (j *generateImagePhashesJob) Execute(ctx context.Context, progress *job.Progress) {
+	files, err := j.findFiles(ctx)
+	if err != nil {
+		logger.Errorf("Error finding images to generate phashes for: %v", err)
+		return
+	}
+
+	logger.Infof("Generating %d image phashes", len(files))
+	progress.SetTotal(len(files))
+
+	for _, f := range files {
+		if job.IsCancelled(ctx) {
+			logger.Info("Stopping due to user request")
+			return
+		}
+
+		progress.ExecuteTask(fmt.Sprintf("Generating phash for %s", f.Path), func() {
+			if err := j.generate(ctx, f); err != nil && ctx.Err() == nil {
+				logger.Errorf("Error generating phash for %s: %v", f.Path, err)
+			}
+		})
+
+		progress.Increment()
+	}
+
+	logger.Info("Finished generating image phashes")
+}
+
+// findFiles returns the primary files of the images which need a phash.
+func (j *generateImagePhashesJob) findFiles(ctx context.Context) ([]*file.ImageFile, error) {
+	var ret []*file.ImageFile
+	r := j.txnManager
+
+	add := func(ctx context.Context, images []*models.Image) error {
+		for _, i := range images {
+			if err := i.LoadPrimaryFile(ctx, r.File); err != nil {
+				return err
+			}
+
+			f := i.Files.Primary()
+			if f == nil {
+				continue
+			}
+
+			if j.overwrite || f.Fingerprints.For(file.FingerprintTypePhash) == nil {
+				ret = append(ret, f)
+			}
+		}
+
+		return nil
+	}
+
+	if err := txn.WithReadTxn(ctx, r, func(ctx context.Context) error {
+		if len(j.galleryIDs) > 0 {
+			for _, id := range j.galleryIDs {
+				images, err := r.Image.FindByGalleryID(ctx, id)
+				if err != nil {
+					return err
+				}
+
+				if err := add(ctx, images); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}
+
+		const batchSize = 1000
+		findFilter := models.BatchFindFilter(batchSize)
+		for more := true; more; {
+			images, err := image.Query(ctx, r.Image, nil, findFilter)
+			if err != nil {
+				return err
+			}
+
+			if err := add(ctx, images); err != nil {
+				return err
+			}
+
+			if len(images) != batchSize {
+				more = false
+			} else {
+				*findFilter.Page++
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (j *generateImagePhashesJob) generate(ctx context.Context, f *file.ImageFile) error {
+	hash, err := imagephash.Generate(f)
+	if err != nil {
+		return err
+	}
+
+	return txn.WithTxn(ctx, j.txnManager, func(ctx context.Context) error {
+		f.Fingerprints = f.Fingerprints.AppendUnique(file.Fingerprint{
+			Type:        file.FingerprintTypePhash,
+			Fingerprint: int64(*hash),
+		})
+
+		return j.txnManager.File.Update(ctx, f)
+	})
+}
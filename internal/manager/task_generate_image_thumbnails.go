@@ -0,0 +1,202 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/image"
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
+	"github.com/stashapp/stash/pkg/txn"
+)
+
+// number of images resized by a single encoder process
+const imageThumbnailBatchSize = 64
+
+type GenerateImageThumbnailsInput struct {
+	// IDs of galleries whose images are thumbnailed, or all images if empty
+	GalleryIDs []string `json:"galleryIds"`
+	// Regenerate existing thumbnails
+	Overwrite bool `json:"overwrite"`
+}
+
+// GenerateImageThumbnails queues a job which generates the thumbnails of
+// images in batches.
+func (s *Manager) GenerateImageThumbnails(ctx context.Context, input GenerateImageThumbnailsInput) (int, error) {
+	galleryIDs, err := stringslice.StringSliceToIntSlice(input.GalleryIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	j := generateImageThumbnailsJob{
+		txnManager: s.Repository,
+		galleryIDs: galleryIDs,
+		overwrite:  input.Overwrite,
+	}
+
+	return s.JobManager.AddClass(ctx, "Generating image thumbnails...", &j, job.ClassCPU), nil
+}
+
+type generateImageThumbnailsJob struct {
+	txnManager Repository
+	galleryIDs []int
+	overwrite  bool
+}
+
+func (j *generateImageThumbnailsJob) Execute(ctx context.Context, progress *job.Progress) {
+	items, err := j.findItems(ctx)
+	if err != nil {
+		logger.Errorf("Error finding images to generate thumbnails for: %v", err)
+		return
+	}
+
+	parallelTasks := config.GetInstance().GetParallelTasksWithAutoDetection()
+	logger.Infof("Generating %d image thumbnails with %d parallel tasks", len(items), parallelTasks)
+	progress.SetTotal(len(items))
+
+	encoder := image.NewThumbnailEncoder(instance.FFMPEG)
+	batches := make(chan []image.ThumbnailBatchItem)
+
+	var wg sync.WaitGroup
+	for w := 0; w < parallelTasks; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range batches {
+				j.generate(ctx, &encoder, batch, progress)
+			}
+		}()
+	}
+
+	for start := 0; start < len(items); start += imageThumbnailBatchSize {
+		if job.IsCancelled(ctx) {
+			break
+		}
+
+		end := start + imageThumbnailBatchSize
+		if end > len(items) {
+			end = len(items)
+		}
+		batches <- items[start:end]
+	}
+
+	close(batches)
+	wg.Wait()
+
+	if job.IsCancelled(ctx) {
+		logger.Info("Stopping due to user request")
+		return
+	}
+
+	logger.Info("Finished generating image thumbnails")
+}
+
+func (j *generateImageThumbnailsJob) generate(ctx context.Context, encoder *image.ThumbnailEncoder, batch []image.ThumbnailBatchItem, progress *job.Progress) {
+	if job.IsCancelled(ctx) {
+		return
+	}
+
+	progress.ExecuteTask(fmt.Sprintf("Generating %d image thumbnails", len(batch)), func() {
+		errs := encoder.GenerateThumbnails(ctx, batch, models.DefaultGthumbWidth)
+		if ctx.Err() != nil {
+			return
+		}
+
+		for i, err := range errs {
+			// don't log for animated images
+			if !errors.Is(err, image.ErrNotSupportedForThumbnail) {
+				logger.Errorf("Error generating thumbnail for %s: %v", batch[i].File.Path, err)
+			}
+		}
+	})
+
+	progress.AddProcessed(len(batch))
+}
+
+// findItems returns the primary files of the images which need a thumbnail,
+// along with the path of the thumbnail.
+func (j *generateImageThumbnailsJob) findItems(ctx context.Context) ([]image.ThumbnailBatchItem, error) {
+	var ret []image.ThumbnailBatchItem
+	r := j.txnManager
+	generated := instance.Paths.Generated
+
+	add := func(ctx context.Context, images []*models.Image) error {
+		for _, i := range images {
+			if err := i.LoadPrimaryFile(ctx, r.File); err != nil {
+				return err
+			}
+
+			f := i.Files.Primary()
+			if f == nil {
+				continue
+			}
+
+			// images smaller than the thumbnail size are served as is
+			if f.Height <= models.DefaultGthumbWidth && f.Width <= models.DefaultGthumbWidth {
+				continue
+			}
+
+			thumbPath := generated.GetThumbnailPath(i.Checksum, models.DefaultGthumbWidth)
+			if !j.overwrite {
+				if exists, _ := fsutil.FileExists(thumbPath); exists {
+					continue
+				}
+			}
+
+			ret = append(ret, image.ThumbnailBatchItem{
+				File:       f,
+				OutputPath: thumbPath,
+			})
+		}
+
+		return nil
+	}
+
+	if err := txn.WithReadTxn(ctx, r, func(ctx context.Context) error {
+		if len(j.galleryIDs) > 0 {
+			for _, id := range j.galleryIDs {
+				images, err := r.Image.FindByGalleryID(ctx, id)
+				if err != nil {
+					return err
+				}
+
+				if err := add(ctx, images); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}
+
+		const batchSize = 1000
+		findFilter := models.BatchFindFilter(batchSize)
+		for more := true; more; {
+			images, err := image.Query(ctx, r.Image, nil, findFilter)
+			if err != nil {
+				return err
+			}
+
+			if err := add(ctx, images); err != nil {
+				return err
+			}
+
+			if len(images) != batchSize {
+				more = false
+			} else {
+				*findFilter.Page++
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
@@ -3,7 +3,10 @@ package manager
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
 
+	"github.com/lucasb-eyer/go-colorful"
 	"github.com/stashapp/stash/pkg/file/video"
 	"github.com/stashapp/stash/pkg/fsutil"
 	"github.com/stashapp/stash/pkg/logger"
@@ -27,10 +30,11 @@ func (t *GenerateInteractiveHeatmapSpeedTask) Start(ctx context.Context) {
 	}
 
 	videoChecksum := t.Scene.GetHash(t.fileNamingAlgorithm)
-	funscriptPath := video.GetFunscriptPath(t.Scene.Path)
+	funscriptPath := video.FindInteractiveScriptPath(t.Scene.Path)
 	heatmapPath := instance.Paths.Scene.GetInteractiveHeatmapPath(videoChecksum)
 
 	generator := NewInteractiveHeatmapSpeedGenerator(funscriptPath, heatmapPath, t.Scene.Files.Primary().Duration)
+	configureHeatmapGenerator(generator)
 
 	err := generator.Generate()
 
@@ -41,16 +45,92 @@ func (t *GenerateInteractiveHeatmapSpeedTask) Start(ctx context.Context) {
 
 	median := generator.InteractiveSpeed
 
+	var axes []*models.FunscriptAxis
+	for _, axis := range generator.Axes {
+		speed := axis.InteractiveSpeed
+		axes = append(axes, &models.FunscriptAxis{
+			Axis:             axis.Axis,
+			InteractiveSpeed: &speed,
+		})
+	}
+
 	if err := t.TxnManager.WithTxn(ctx, func(ctx context.Context) error {
 		primaryFile := t.Scene.Files.Primary()
 		primaryFile.InteractiveSpeed = &median
 		qb := t.TxnManager.File
-		return qb.Update(ctx, primaryFile)
+		if err := qb.Update(ctx, primaryFile); err != nil {
+			return err
+		}
+
+		if err := qb.UpdateFunscriptAxes(ctx, primaryFile.ID, axes); err != nil {
+			return err
+		}
+
+		return qb.UpdateFunscriptStats(ctx, primaryFile.ID, &generator.Stats)
 	}); err != nil && ctx.Err() == nil {
 		logger.Error(err.Error())
 	}
 }
 
+// WriteInteractiveHeatmap renders the heatmap of the interactive script of
+// the scene to w using the configured heatmap settings, without writing to
+// the generated files directory.
+func WriteInteractiveHeatmap(scene *models.Scene, w io.Writer) error {
+	primaryFile := scene.Files.Primary()
+	if primaryFile == nil || !primaryFile.Interactive {
+		return fmt.Errorf("scene %d is not interactive", scene.ID)
+	}
+
+	funscriptPath := video.FindInteractiveScriptPath(scene.Path)
+	f, err := os.Open(funscriptPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	generator := NewInteractiveHeatmapSpeedGenerator(funscriptPath, "", primaryFile.Duration)
+	configureHeatmapGenerator(generator)
+	if generator.RenderAxes {
+		generator.loadAxes()
+	}
+
+	return generator.GenerateFrom(f, IsCSVScriptPath(funscriptPath), w)
+}
+
+// configureHeatmapGenerator applies the heatmap settings of the configuration
+// to the generator. Invalid colors are logged and the generator defaults are
+// used.
+func configureHeatmapGenerator(g *InteractiveHeatmapSpeedGenerator) {
+	c := instance.Config
+	g.RenderAxes = c.GetInteractiveHeatmapRenderAxes()
+
+	if v := c.GetInteractiveHeatmapWidth(); v > 0 {
+		g.Width = v
+	}
+	if v := c.GetInteractiveHeatmapHeight(); v > 0 {
+		g.Height = v
+	}
+	if v := c.GetInteractiveHeatmapSegments(); v >= 2 {
+		g.NumSegments = v
+	}
+
+	colormap, err := ParseHeatmapColormap(c.GetInteractiveHeatmapColormap(), c.GetInteractiveHeatmapColormapStops())
+	if err != nil {
+		logger.Warnf("invalid interactive heatmap colormap: %v", err)
+	} else {
+		g.Colormap = colormap
+	}
+
+	if v := c.GetInteractiveHeatmapBackgroundColor(); v != "" {
+		background, err := colorful.Hex(v)
+		if err != nil {
+			logger.Warnf("invalid interactive heatmap background color %q: %v", v, err)
+		} else {
+			g.BackgroundColor = background
+		}
+	}
+}
+
 func (t *GenerateInteractiveHeatmapSpeedTask) shouldGenerate() bool {
 	primaryFile := t.Scene.Files.Primary()
 	if primaryFile == nil || !primaryFile.Interactive {
@@ -0,0 +1,152 @@
+package manager
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/file/video"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene"
+)
+
+// GenerateInteractiveMarkersTask creates scene markers for the sustained high
+// intensity sections of the funscript of an interactive scene.
+type GenerateInteractiveMarkersTask struct {
+	Scene               models.Scene
+	Overwrite           bool
+	fileNamingAlgorithm models.HashAlgorithm
+	TxnManager          Repository
+	// Name of the primary tag of the generated markers. The tag is created
+	// if it does not exist.
+	TagName string
+}
+
+func (t *GenerateInteractiveMarkersTask) GetDescription() string {
+	return fmt.Sprintf("Generating interactive markers for %s", t.Scene.Path)
+}
+
+func (t *GenerateInteractiveMarkersTask) Start(ctx context.Context) {
+	if !t.shouldGenerate() {
+		return
+	}
+
+	funscriptPath := video.FindInteractiveScriptPath(t.Scene.Path)
+	generator := NewInteractiveHeatmapSpeedGenerator(funscriptPath, "", t.Scene.Files.Primary().Duration)
+	funscript, err := generator.LoadFunscriptData(funscriptPath)
+	if err != nil {
+		logger.Errorf("error generating interactive markers: %s", err.Error())
+		return
+	}
+
+	funscript.UpdateIntensityAndSpeed()
+	peaks := funscript.FindIntensityPeaks(DefaultIntensityPeakOptions())
+
+	fileDeleter := &scene.FileDeleter{
+		Deleter:        file.NewDeleter(),
+		FileNamingAlgo: t.fileNamingAlgorithm,
+		Paths:          instance.Paths,
+	}
+
+	created := 0
+	if err := t.TxnManager.WithTxn(ctx, func(ctx context.Context) error {
+		tagID, err := getOrCreateTag(ctx, t.TxnManager.Tag, t.TagName)
+		if err != nil {
+			return fmt.Errorf("getting interactive marker tag: %w", err)
+		}
+
+		existing, err := t.findGeneratedMarkers(ctx, tagID)
+		if err != nil {
+			return err
+		}
+
+		if len(existing) > 0 {
+			if !t.Overwrite {
+				return nil
+			}
+
+			for _, m := range existing {
+				if err := scene.DestroyMarker(ctx, &t.Scene, m, t.TxnManager.SceneMarker, fileDeleter); err != nil {
+					return fmt.Errorf("destroying existing interactive marker: %w", err)
+				}
+			}
+		}
+
+		now := time.Now()
+		for _, p := range peaks {
+			marker := models.SceneMarker{
+				Title:        t.TagName,
+				Seconds:      float64(p.Start) / 1000,
+				PrimaryTagID: tagID,
+				SceneID:      sql.NullInt64{Int64: int64(t.Scene.ID), Valid: true},
+				CreatedAt:    models.SQLiteTimestamp{Timestamp: now},
+				UpdatedAt:    models.SQLiteTimestamp{Timestamp: now},
+			}
+
+			if _, err := t.TxnManager.SceneMarker.Create(ctx, marker); err != nil {
+				return fmt.Errorf("creating interactive marker: %w", err)
+			}
+		}
+
+		created = len(peaks)
+		return nil
+	}); err != nil {
+		fileDeleter.Rollback()
+		if ctx.Err() == nil {
+			logger.Errorf("error generating interactive markers for %s: %v", t.Scene.Path, err)
+		}
+		return
+	}
+
+	fileDeleter.Commit()
+
+	if created > 0 {
+		logger.Debugf("Created %d interactive markers for %s", created, t.Scene.Path)
+	}
+}
+
+// getOrCreateTag returns the ID of the tag with the name, creating the tag if
+// it does not exist.
+func getOrCreateTag(ctx context.Context, qb models.TagReaderWriter, name string) (int, error) {
+	existing, err := qb.FindByName(ctx, name, true)
+	if err != nil {
+		return 0, err
+	}
+
+	if existing != nil {
+		return existing.ID, nil
+	}
+
+	created, err := qb.Create(ctx, *models.NewTag(name))
+	if err != nil {
+		return 0, err
+	}
+
+	return created.ID, nil
+}
+
+// findGeneratedMarkers returns the markers of the scene with the interactive
+// marker tag as their primary tag.
+func (t *GenerateInteractiveMarkersTask) findGeneratedMarkers(ctx context.Context, tagID int) ([]*models.SceneMarker, error) {
+	markers, err := t.TxnManager.SceneMarker.FindBySceneID(ctx, t.Scene.ID)
+	if err != nil {
+		return nil, fmt.Errorf("finding scene markers: %w", err)
+	}
+
+	var ret []*models.SceneMarker
+	for _, m := range markers {
+		if m.PrimaryTagID == tagID {
+			ret = append(ret, m)
+		}
+	}
+
+	return ret, nil
+}
+
+func (t *GenerateInteractiveMarkersTask) shouldGenerate() bool {
+	primaryFile := t.Scene.Files.Primary()
+	return primaryFile != nil && primaryFile.Interactive && t.TagName != ""
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/stashapp/stash/pkg/file/video"
 	"github.com/stashapp/stash/pkg/fsutil"
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/models"
@@ -42,6 +43,10 @@ func (t *GenerateSpriteTask) Start(ctx context.Context) {
 	}
 	generator.Overwrite = t.Overwrite
 
+	if instance.Config.GetInteractiveHeatmapSprite() {
+		generator.Heatmap = t.heatmapGenerator()
+	}
+
 	if err := generator.Generate(); err != nil {
 		logger.Errorf("error generating sprite: %s", err.Error())
 		logErrorOutput(err)
@@ -49,6 +54,20 @@ func (t *GenerateSpriteTask) Start(ctx context.Context) {
 	}
 }
 
+// heatmapGenerator returns the generator of the heatmap bands of the sprite,
+// or nil if the scene is not interactive.
+func (t *GenerateSpriteTask) heatmapGenerator() *InteractiveHeatmapSpeedGenerator {
+	primaryFile := t.Scene.Files.Primary()
+	if primaryFile == nil || !primaryFile.Interactive {
+		return nil
+	}
+
+	funscriptPath := video.FindInteractiveScriptPath(t.Scene.Path)
+	g := NewInteractiveHeatmapSpeedGenerator(funscriptPath, "", primaryFile.Duration)
+	configureHeatmapGenerator(g)
+	return g
+}
+
 // required returns true if the sprite needs to be generated
 func (t GenerateSpriteTask) required() bool {
 	if t.Scene.Path == "" {
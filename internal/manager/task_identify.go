@@ -2,9 +2,12 @@ package manager
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"strings"
+	"time"
 
 	"github.com/stashapp/stash/internal/identify"
 	"github.com/stashapp/stash/pkg/job"
@@ -15,16 +18,24 @@ import (
 	"github.com/stashapp/stash/pkg/scraper/stashbox"
 	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
 	"github.com/stashapp/stash/pkg/txn"
+	"github.com/stashapp/stash/pkg/utils"
 )
 
 var ErrInput = errors.New("invalid request input")
 
+// identifyBatchSize is the number of scenes which sources query ahead of
+// identifying them.
+const identifyBatchSize = 200
+
 type IdentifyJob struct {
 	postHookExecutor identify.SceneUpdatePostHookExecutor
 	input            identify.Options
 
 	stashBoxes []*models.StashBox
 	progress   *job.Progress
+
+	// reports are the changes made to each identified scene
+	reports []*identify.SceneReport
 }
 
 func CreateIdentifyJob(input identify.Options) *IdentifyJob {
@@ -49,6 +60,9 @@ func (j *IdentifyJob) Execute(ctx context.Context, progress *job.Progress) {
 		return
 	}
 
+	j.pruneFingerprintCache(ctx)
+	j.pruneScraperCache(ctx)
+
 	// if scene ids provided, use those
 	// otherwise, batch query for all scenes - ordering by path
 	// don't use a transaction to query scenes
@@ -63,6 +77,7 @@ func (j *IdentifyJob) Execute(ctx context.Context, progress *job.Progress) {
 		}
 
 		progress.SetTotal(len(sceneIDs))
+		var batch []*models.Scene
 		for _, id := range sceneIDs {
 			if job.IsCancelled(ctx) {
 				break
@@ -79,20 +94,103 @@ func (j *IdentifyJob) Execute(ctx context.Context, progress *job.Progress) {
 				return fmt.Errorf("%w: scene with id %d", models.ErrNotFound, id)
 			}
 
-			j.identifyScene(ctx, scene, sources)
+			batch = append(batch, scene)
+			if len(batch) == identifyBatchSize {
+				j.identifyScenes(ctx, batch, sources)
+				batch = nil
+			}
 		}
 
+		j.identifyScenes(ctx, batch, sources)
 		return nil
 	}); err != nil {
 		logger.Errorf("Error encountered while identifying scenes: %v", err)
 	}
+
+	j.storeReport(ctx)
+}
+
+// storeReport stores the changes made to the identified scenes as a job
+// artifact.
+func (j *IdentifyJob) storeReport(ctx context.Context) {
+	fields := 0
+	for _, r := range j.reports {
+		fields += len(r.Fields)
+	}
+
+	logger.Infof("Identify changed %d fields of %d scenes", fields, len(j.reports))
+
+	if len(j.reports) == 0 {
+		return
+	}
+
+	name := "identify-" + time.Now().Format("20060102-150405") + ".json"
+	if _, err := instance.AddJobArtifact(ctx, name, "application/json", func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(j.reports)
+	}); err != nil {
+		logger.Errorf("Error storing identify changes: %v", err)
+	}
+}
+
+// pruneFingerprintCache removes the expired stash-box fingerprint query
+// results.
+func (j *IdentifyJob) pruneFingerprintCache(ctx context.Context) {
+	days := instance.Config.GetStashBoxFingerprintCacheDays()
+	if days <= 0 {
+		return
+	}
+
+	before := time.Now().AddDate(0, 0, -days)
+	var n int
+	if err := txn.WithTxn(ctx, instance.Repository, func(ctx context.Context) error {
+		var err error
+		n, err = instance.Repository.StashBoxFingerprintCache.DestroyCreatedBefore(ctx, before)
+		return err
+	}); err != nil {
+		logger.Warnf("Error removing expired stash-box fingerprint results: %v", err)
+		return
+	}
+
+	if n > 0 {
+		logger.Debugf("Removed %d expired stash-box fingerprint results", n)
+	}
+}
+
+// pruneScraperCache removes the expired scrape results.
+func (j *IdentifyJob) pruneScraperCache(ctx context.Context) {
+	days := instance.Config.GetScraperCacheDays()
+	if days <= 0 {
+		return
+	}
+
+	before := time.Now().AddDate(0, 0, -days)
+	var n int
+	if err := txn.WithTxn(ctx, instance.Repository, func(ctx context.Context) error {
+		var err error
+		n, err = instance.Repository.ScraperCache.DestroyCreatedBefore(ctx, before)
+		return err
+	}); err != nil {
+		logger.Warnf("Error removing expired scrape results: %v", err)
+		return
+	}
+
+	if n > 0 {
+		logger.Debugf("Removed %d expired scrape results", n)
+	}
 }
 
 func (j *IdentifyJob) identifyAllScenes(ctx context.Context, sources []identify.ScraperSource) error {
-	// exclude organised
-	organised := false
-	sceneFilter := scene.FilterFromPaths(j.input.Paths)
-	sceneFilter.Organized = &organised
+	var sceneFilter *models.SceneFilterType
+	if len(j.input.Paths) == 0 && j.input.SceneFilter != nil {
+		sceneFilter = j.input.SceneFilter
+	} else {
+		// exclude organised
+		organised := false
+		sceneFilter = scene.FilterFromPaths(j.input.Paths)
+		sceneFilter.Organized = &organised
+	}
 
 	sort := "path"
 	findFilter := &models.FindFilterType{
@@ -115,14 +213,53 @@ func (j *IdentifyJob) identifyAllScenes(ctx context.Context, sources []identify.
 
 	j.progress.SetTotal(countResult.Count)
 
-	return scene.BatchProcess(ctx, instance.Repository.Scene, sceneFilter, findFilter, func(scene *models.Scene) error {
+	var batch []*models.Scene
+	if err := scene.BatchProcess(ctx, instance.Repository.Scene, sceneFilter, findFilter, func(scene *models.Scene) error {
 		if job.IsCancelled(ctx) {
 			return nil
 		}
 
-		j.identifyScene(ctx, scene, sources)
+		batch = append(batch, scene)
+		if len(batch) == identifyBatchSize {
+			j.identifyScenes(ctx, batch, sources)
+			batch = nil
+		}
 		return nil
-	})
+	}); err != nil {
+		return err
+	}
+
+	j.identifyScenes(ctx, batch, sources)
+	return nil
+}
+
+// scenePrefetcher is implemented by sources which can query a batch of scenes
+// at once, ahead of the scenes being identified one at a time.
+type scenePrefetcher interface {
+	PrefetchScenes(ctx context.Context, sceneIDs []int) error
+}
+
+func (j *IdentifyJob) identifyScenes(ctx context.Context, scenes []*models.Scene, sources []identify.ScraperSource) {
+	if len(scenes) == 0 || job.IsCancelled(ctx) {
+		return
+	}
+
+	ids := make([]int, len(scenes))
+	for i, s := range scenes {
+		ids[i] = s.ID
+	}
+
+	for _, src := range sources {
+		if p, ok := src.Scraper.(scenePrefetcher); ok {
+			if err := p.PrefetchScenes(ctx, ids); err != nil && ctx.Err() == nil {
+				logger.Warnf("Error querying %s for %d scenes: %v", src.Name, len(ids), err)
+			}
+		}
+	}
+
+	for _, s := range scenes {
+		j.identifyScene(ctx, s, sources)
+	}
 }
 
 func (j *IdentifyJob) identifyScene(ctx context.Context, s *models.Scene, sources []identify.ScraperSource) {
@@ -145,9 +282,14 @@ func (j *IdentifyJob) identifyScene(ctx context.Context, s *models.Scene, source
 				FileNamingAlgorithm: instance.Config.GetVideoFileNamingAlgorithm(),
 			},
 			SceneUpdatePostHookExecutor: j.postHookExecutor,
+			CombineSources:              utils.IsTrue(j.input.CombineSources),
 		}
 
-		taskError = task.Identify(ctx, instance.Repository, s)
+		var report *identify.SceneReport
+		report, taskError = task.Identify(ctx, instance.Repository, s)
+		if report != nil && len(report.Fields) > 0 {
+			j.reports = append(j.reports, report)
+		}
 	})
 
 	if taskError != nil {
@@ -158,25 +300,35 @@ func (j *IdentifyJob) identifyScene(ctx context.Context, s *models.Scene, source
 }
 
 func (j *IdentifyJob) getSources() ([]identify.ScraperSource, error) {
+	return getIdentifySources(j.input.Sources, j.stashBoxes)
+}
+
+// getIdentifySources returns the scraper sources of the identify sources.
+func getIdentifySources(sources []*identify.Source, stashBoxes []*models.StashBox) ([]identify.ScraperSource, error) {
 	var ret []identify.ScraperSource
-	for _, source := range j.input.Sources {
+	for _, source := range sources {
 		// get scraper source
-		stashBox, err := j.getStashBox(source.Source)
+		stashBox, err := getStashBox(stashBoxes, source.Source)
 		if err != nil {
 			return nil, err
 		}
 
 		var src identify.ScraperSource
 		if stashBox != nil {
+			client := stashbox.NewClient(*stashBox, instance.Repository, stashbox.Repository{
+				Scene:     instance.Repository.Scene,
+				Performer: instance.Repository.Performer,
+				Tag:       instance.Repository.Tag,
+				Studio:    instance.Repository.Studio,
+			})
+			if days := instance.Config.GetStashBoxFingerprintCacheDays(); days > 0 {
+				client.SetFingerprintCache(instance.Repository.StashBoxFingerprintCache, time.Duration(days)*24*time.Hour)
+			}
+
 			src = identify.ScraperSource{
 				Name: "stash-box: " + stashBox.Endpoint,
 				Scraper: stashboxSource{
-					stashbox.NewClient(*stashBox, instance.Repository, stashbox.Repository{
-						Scene:     instance.Repository.Scene,
-						Performer: instance.Repository.Performer,
-						Tag:       instance.Repository.Tag,
-						Studio:    instance.Repository.Studio,
-					}),
+					client,
 					stashBox.Endpoint,
 				},
 				RemoteSite: stashBox.Endpoint,
@@ -193,6 +345,7 @@ func (j *IdentifyJob) getSources() ([]identify.ScraperSource, error) {
 					cache:     instance.ScraperCache,
 					scraperID: scraperID,
 				},
+				ScraperID: scraperID,
 			}
 		}
 
@@ -203,7 +356,7 @@ func (j *IdentifyJob) getSources() ([]identify.ScraperSource, error) {
 	return ret, nil
 }
 
-func (j *IdentifyJob) getStashBox(src *scraper.Source) (*models.StashBox, error) {
+func getStashBox(stashBoxes []*models.StashBox, src *scraper.Source) (*models.StashBox, error) {
 	if src.ScraperID != nil {
 		return nil, nil
 	}
@@ -213,7 +366,7 @@ func (j *IdentifyJob) getStashBox(src *scraper.Source) (*models.StashBox, error)
 		return nil, fmt.Errorf("%w: stash_box_index or stash_box_endpoint or scraper_id must be set", ErrInput)
 	}
 
-	return resolveStashBox(j.stashBoxes, *src)
+	return resolveStashBox(stashBoxes, *src)
 }
 
 func resolveStashBox(sb []*models.StashBox, source scraper.Source) (*models.StashBox, error) {
@@ -265,6 +418,12 @@ func (s stashboxSource) ScrapeScene(ctx context.Context, sceneID int) (*scraper.
 	return nil, nil
 }
 
+// PrefetchScenes queries stash-box for the scenes in batches, caching the
+// results for ScrapeScene.
+func (s stashboxSource) PrefetchScenes(ctx context.Context, sceneIDs []int) error {
+	return s.CacheScenesByFingerprints(ctx, sceneIDs)
+}
+
 func (s stashboxSource) String() string {
 	return fmt.Sprintf("stash-box %s", s.endpoint)
 }
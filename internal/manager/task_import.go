@@ -117,6 +117,14 @@ func (t *ImportTask) Start(ctx context.Context) {
 	}
 	t.scraped = scraped
 
+	// resetting or overwriting affects the whole library
+	if t.Reset || t.DuplicateBehaviour == ImportDuplicateEnumOverwrite {
+		if _, err := GetInstance().AutoBackup(ctx, "import", nil); err != nil {
+			logger.Errorf("Not importing: %v", err)
+			return
+		}
+	}
+
 	if t.Reset {
 		err := t.txnManager.Reset()
 
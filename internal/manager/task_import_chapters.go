@@ -0,0 +1,108 @@
+package manager
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// ImportChaptersTask creates scene markers for the chapters embedded in the
+// video file of a scene. Chapters which already have a marker with the
+// chapter tag are skipped, so importing the same chapters again does not
+// create duplicate markers.
+type ImportChaptersTask struct {
+	Scene      *models.Scene
+	Chapters   []file.VideoChapter
+	TxnManager Repository
+	// Name of the primary tag of the imported markers. The tag is created if
+	// it does not exist.
+	TagName string
+}
+
+func (t *ImportChaptersTask) GetDescription() string {
+	return fmt.Sprintf("Importing chapters for %s", t.Scene.DisplayName())
+}
+
+func (t *ImportChaptersTask) Start(ctx context.Context) {
+	// a single chapter spanning the whole file is not useful as a marker
+	if len(t.Chapters) < 2 || t.TagName == "" {
+		return
+	}
+
+	created := 0
+	if err := t.TxnManager.WithTxn(ctx, func(ctx context.Context) error {
+		tagID, err := getOrCreateTag(ctx, t.TxnManager.Tag, t.TagName)
+		if err != nil {
+			return fmt.Errorf("getting chapter marker tag: %w", err)
+		}
+
+		existing, err := t.TxnManager.SceneMarker.FindBySceneID(ctx, t.Scene.ID)
+		if err != nil {
+			return fmt.Errorf("finding scene markers: %w", err)
+		}
+
+		now := time.Now()
+		for _, c := range newChapterMarkers(t.Chapters, existing, tagID) {
+			marker := models.SceneMarker{
+				Title:        c.Title,
+				Seconds:      c.Start,
+				PrimaryTagID: tagID,
+				SceneID:      sql.NullInt64{Int64: int64(t.Scene.ID), Valid: true},
+				CreatedAt:    models.SQLiteTimestamp{Timestamp: now},
+				UpdatedAt:    models.SQLiteTimestamp{Timestamp: now},
+			}
+
+			if _, err := t.TxnManager.SceneMarker.Create(ctx, marker); err != nil {
+				return fmt.Errorf("creating chapter marker: %w", err)
+			}
+			created++
+		}
+
+		return nil
+	}); err != nil {
+		if ctx.Err() == nil {
+			logger.Errorf("error importing chapters for %s: %v", t.Scene.DisplayName(), err)
+		}
+		return
+	}
+
+	if created > 0 {
+		logger.Infof("Imported %d chapters for %s", created, t.Scene.DisplayName())
+	}
+}
+
+// newChapterMarkers returns the chapters which do not have an existing marker
+// with the tag at the same time. Chapters without a title are named by their
+// position.
+func newChapterMarkers(chapters []file.VideoChapter, existing []*models.SceneMarker, tagID int) []file.VideoChapter {
+	// chapter times may be rounded differently by the container
+	const tolerance = 0.5
+
+	var ret []file.VideoChapter
+	for i, c := range chapters {
+		found := false
+		for _, m := range existing {
+			if m.PrimaryTagID == tagID && math.Abs(m.Seconds-c.Start) < tolerance {
+				found = true
+				break
+			}
+		}
+
+		if found {
+			continue
+		}
+
+		if c.Title == "" {
+			c.Title = fmt.Sprintf("Chapter %d", i+1)
+		}
+		ret = append(ret, c)
+	}
+
+	return ret
+}
@@ -0,0 +1,36 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewChapterMarkers(t *testing.T) {
+	const (
+		chapterTagID = 1
+		otherTagID   = 2
+	)
+
+	chapters := []file.VideoChapter{
+		{Title: "Intro", Start: 0},
+		{Title: "", Start: 120.5},
+		{Title: "Existing", Start: 300},
+		{Title: "Other tag", Start: 400},
+	}
+
+	existing := []*models.SceneMarker{
+		{PrimaryTagID: chapterTagID, Seconds: 300.2},
+		{PrimaryTagID: otherTagID, Seconds: 400},
+	}
+
+	got := newChapterMarkers(chapters, existing, chapterTagID)
+
+	assert.Equal(t, []file.VideoChapter{
+		{Title: "Intro", Start: 0},
+		{Title: "Chapter 2", Start: 120.5},
+		{Title: "Other tag", Start: 400},
+	}, got)
+}
@@ -15,12 +15,14 @@ func (s *Manager) RunPluginTask(ctx context.Context, pluginID string, taskName s
 		task, err := s.PluginCache.CreateTask(ctx, pluginID, taskName, args, pluginProgress)
 		if err != nil {
 			logger.Errorf("Error creating plugin task: %s", err.Error())
+			s.dispatchPluginTaskFailedWebhook(pluginID, taskName, err.Error())
 			return
 		}
 
 		err = task.Start()
 		if err != nil {
 			logger.Errorf("Error running plugin task: %s", err.Error())
+			s.dispatchPluginTaskFailedWebhook(pluginID, taskName, err.Error())
 			return
 		}
 
@@ -35,6 +37,7 @@ func (s *Manager) RunPluginTask(ctx context.Context, pluginID string, taskName s
 			} else {
 				if output.Error != nil {
 					logger.Errorf("Plugin returned error: %s", *output.Error)
+					s.dispatchPluginTaskFailedWebhook(pluginID, taskName, *output.Error)
 				} else if output.Output != nil {
 					logger.Debugf("Plugin returned: %v", output.Output)
 				}
@@ -0,0 +1,208 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/file/video"
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene"
+	"github.com/stashapp/stash/pkg/txn"
+)
+
+type ProbeMetadataInput struct {
+	// Paths to probe, null for all files
+	Paths []string `json:"paths"`
+	// Only probe files with missing technical metadata
+	MissingOnly bool `json:"missingOnly"`
+}
+
+func (s *Manager) Probe(ctx context.Context, input ProbeMetadataInput) (int, error) {
+	if err := s.validateFFMPEG(); err != nil {
+		return 0, err
+	}
+
+	j := probeJob{
+		txnManager: s.Repository,
+		decorator: &video.Decorator{
+			FFProbe: s.FFProbe,
+		},
+		input:          input,
+		importChapters: s.Config.GetImportChapters(),
+		chapterTag:     s.Config.GetChapterMarkerTag(),
+	}
+
+	return s.JobManager.Add(ctx, "Refreshing video metadata...", &j), nil
+}
+
+func pathInAny(dirs []string, p string) bool {
+	for _, d := range dirs {
+		if fsutil.IsPathInDir(d, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// videoMetadataEqual returns true if the technical metadata of the provided
+// video files is equal.
+func videoMetadataEqual(a *file.VideoFile, b *file.VideoFile) bool {
+	return a.Format == b.Format &&
+		a.Width == b.Width &&
+		a.Height == b.Height &&
+		a.Duration == b.Duration &&
+		a.VideoCodec == b.VideoCodec &&
+		a.AudioCodec == b.AudioCodec &&
+		a.FrameRate == b.FrameRate &&
+		a.BitRate == b.BitRate &&
+		a.Interactive == b.Interactive
+}
+
+type probeJob struct {
+	txnManager Repository
+	decorator  *video.Decorator
+	input      ProbeMetadataInput
+
+	importChapters bool
+	chapterTag     string
+}
+
+func (j *probeJob) Execute(ctx context.Context, progress *job.Progress) {
+	logger.Infof("Starting video metadata refresh")
+
+	files, err := j.findFiles(ctx)
+	if err != nil {
+		logger.Errorf("Error finding files to probe: %v", err)
+		return
+	}
+
+	progress.SetTotal(len(files))
+
+	var updated int
+	for _, f := range files {
+		if job.IsCancelled(ctx) {
+			logger.Info("Stopping due to user request")
+			return
+		}
+
+		progress.ExecuteTask(fmt.Sprintf("Probing %s", f.Path), func() {
+			changed, err := j.probe(ctx, f)
+			if err != nil {
+				logger.Errorf("Error refreshing video metadata for %s: %v", f.Path, err)
+				return
+			}
+
+			if changed {
+				updated++
+			}
+		})
+
+		progress.Increment()
+	}
+
+	logger.Infof("Finished video metadata refresh. Updated %d of %d files", updated, len(files))
+}
+
+func (j *probeJob) findFiles(ctx context.Context) ([]*file.VideoFile, error) {
+	var ret []*file.VideoFile
+	seen := make(map[file.ID]bool)
+
+	r := j.txnManager
+	if err := txn.WithReadTxn(ctx, r, func(ctx context.Context) error {
+		var filter *models.SceneFilterType
+		if len(j.input.Paths) > 0 {
+			filter = scene.FilterFromPaths(j.input.Paths)
+		}
+
+		return scene.BatchProcess(ctx, r.Scene, filter, nil, func(s *models.Scene) error {
+			if err := s.LoadFiles(ctx, r.Scene); err != nil {
+				return err
+			}
+
+			for _, f := range s.Files.List() {
+				if seen[f.ID] {
+					continue
+				}
+				seen[f.ID] = true
+
+				if len(j.input.Paths) > 0 && !pathInAny(j.input.Paths, f.Path) {
+					continue
+				}
+
+				if j.input.MissingOnly && !j.decorator.IsMissingMetadata(ctx, &file.OsFS{}, f) {
+					continue
+				}
+
+				ret = append(ret, f)
+			}
+
+			return nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// probe re-probes the provided file, updating its stored technical metadata
+// if it has changed. Returns true if the file was updated.
+func (j *probeJob) probe(ctx context.Context, f *file.VideoFile) (bool, error) {
+	decorated, err := j.decorator.Decorate(ctx, &file.OsFS{}, f)
+	if err != nil {
+		return false, err
+	}
+
+	vf, ok := decorated.(*file.VideoFile)
+	if !ok {
+		return false, fmt.Errorf("unexpected file type %T", decorated)
+	}
+
+	if j.importChapters && len(vf.Chapters) > 0 {
+		j.importFileChapters(ctx, vf)
+	}
+
+	if videoMetadataEqual(f, vf) {
+		return false, nil
+	}
+
+	// preserve fields not set by probing
+	vf.InteractiveSpeed = f.InteractiveSpeed
+
+	if err := txn.WithTxn(ctx, j.txnManager, func(ctx context.Context) error {
+		return j.txnManager.File.Update(ctx, vf)
+	}); err != nil {
+		return false, err
+	}
+
+	logger.Infof("Updated video metadata for %s", f.Path)
+	return true, nil
+}
+
+// importFileChapters imports the chapters of the file as markers of the
+// scenes of the file.
+func (j *probeJob) importFileChapters(ctx context.Context, f *file.VideoFile) {
+	var scenes []*models.Scene
+	if err := txn.WithReadTxn(ctx, j.txnManager, func(ctx context.Context) error {
+		var err error
+		scenes, err = j.txnManager.Scene.FindByFileID(ctx, f.ID)
+		return err
+	}); err != nil {
+		logger.Errorf("Error finding scenes of %s: %v", f.Path, err)
+		return
+	}
+
+	for _, s := range scenes {
+		task := ImportChaptersTask{
+			Scene:      s,
+			Chapters:   f.Chapters,
+			TxnManager: j.txnManager,
+			TagName:    j.chapterTag,
+		}
+		task.Start(ctx)
+	}
+}
@@ -0,0 +1,52 @@
+package manager
+
+import (
+	"context"
+	"errors"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+type PurgeDeletedFilesInput struct {
+	// Delete all held files, instead of only the oldest files exceeding the
+	// maximum size
+	All bool `json:"all"`
+}
+
+// PurgeDeletedFiles permanently deletes files from the deleted files holding
+// directory.
+func (s *Manager) PurgeDeletedFiles(ctx context.Context, input PurgeDeletedFilesInput) (int, error) {
+	holding := deletedFilesHolding(s.Config)
+	if holding == nil {
+		return 0, errors.New("deleted files path is not set")
+	}
+
+	if !input.All && holding.MaxSize == 0 {
+		return 0, errors.New("deleted files max size is not set")
+	}
+
+	maxSize := holding.MaxSize
+	if input.All {
+		maxSize = 0
+	}
+
+	j := job.MakeJobExec(func(ctx context.Context, progress *job.Progress) {
+		purgeDeletedFiles(holding, maxSize)
+	})
+
+	return s.JobManager.Add(ctx, "Purging deleted files...", j), nil
+}
+
+func purgeDeletedFiles(holding *file.HoldingDir, maxSize int64) {
+	logger.Infof("Purging deleted files from %s", holding.Path)
+
+	deleted, freed, err := holding.Purge(maxSize)
+	if err != nil {
+		logger.Errorf("Error purging deleted files: %v", err)
+	}
+
+	logger.Infof("Purged %d deleted files, freeing %s", deleted, utils.FormatBytes(freed))
+}
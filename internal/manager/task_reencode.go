@@ -0,0 +1,355 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene"
+	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
+	"github.com/stashapp/stash/pkg/txn"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+type ReencodeProfileInput struct {
+	// ffmpeg video encoder to use. For example libsvtav1, libx265
+	VideoEncoder string `json:"videoEncoder"`
+	// Constant rate factor passed to the encoder
+	Crf *int `json:"crf"`
+	// Encoder preset
+	Preset *string `json:"preset"`
+	// ffmpeg audio encoder to use. Defaults to copy
+	AudioEncoder *string `json:"audioEncoder"`
+	// Output container extension. For example mp4, mkv. Defaults to the
+	// extension of the original file if supported, otherwise mp4
+	Container *string `json:"container"`
+	// Maximum number of threads used by ffmpeg. 0 for automatic
+	Threads *int `json:"threads"`
+}
+
+type ReencodeMetadataInput struct {
+	// IDs of scenes to re-encode
+	SceneIDs []string `json:"sceneIds"`
+	// Filter of scenes to re-encode. Ignored if sceneIds is set. One of
+	// sceneIds or sceneFilter is required
+	SceneFilter *models.SceneFilterType `json:"sceneFilter"`
+	Profile     ReencodeProfileInput    `json:"profile"`
+	// Seconds to wait between encodes
+	Throttle *int `json:"throttle"`
+	// Maximum number of scenes to encode in this run
+	Limit *int `json:"limit"`
+	// Do a dry run. Don't encode any scenes
+	DryRun bool `json:"dryRun"`
+}
+
+func (s *Manager) Reencode(ctx context.Context, input ReencodeMetadataInput) (int, error) {
+	if err := s.validateFFMPEG(); err != nil {
+		return 0, err
+	}
+
+	if err := input.validate(); err != nil {
+		return 0, err
+	}
+
+	j := reencodeJob{
+		txnManager: s.Repository,
+		input:      input,
+	}
+
+	return s.JobManager.AddClass(ctx, "Re-encoding scenes...", &j, job.ClassCPU), nil
+}
+
+// validate returns an error if the input is invalid. Since re-encoding
+// replaces the original files, the scenes must be selected explicitly: an
+// empty filter is required to re-encode all scenes.
+func (input ReencodeMetadataInput) validate() error {
+	if len(input.SceneIDs) == 0 && input.SceneFilter == nil {
+		return errors.New("sceneIds or sceneFilter is required")
+	}
+
+	if strings.TrimSpace(input.Profile.VideoEncoder) == "" {
+		return errors.New("video encoder is required")
+	}
+
+	if input.Profile.Container != nil {
+		if _, ok := containerFormat(*input.Profile.Container); !ok {
+			return fmt.Errorf("unsupported container %q", *input.Profile.Container)
+		}
+	}
+
+	return nil
+}
+
+// encoderCodecs maps ffmpeg encoders to the codec name reported by ffprobe.
+var encoderCodecs = map[string]string{
+	"libx264":    "h264",
+	"h264_nvenc": "h264",
+	"h264_qsv":   "h264",
+	"h264_vaapi": "h264",
+	"libx265":    "hevc",
+	"hevc_nvenc": "hevc",
+	"hevc_qsv":   "hevc",
+	"hevc_vaapi": "hevc",
+	"libsvtav1":  "av1",
+	"libaom-av1": "av1",
+	"librav1e":   "av1",
+	"av1_nvenc":  "av1",
+	"av1_qsv":    "av1",
+	"libvpx-vp9": "vp9",
+	"libvpx":     "vp8",
+}
+
+// encoderCodec returns the codec name produced by the provided encoder, or
+// an empty string if it is not known.
+func encoderCodec(encoder string) string {
+	return encoderCodecs[strings.ToLower(encoder)]
+}
+
+// outputContainer returns the container extension to use for the provided
+// file when re-encoding with profile.
+func (p ReencodeProfileInput) outputContainer(f *file.VideoFile) string {
+	if p.Container != nil && *p.Container != "" {
+		return strings.ToLower(*p.Container)
+	}
+
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(f.Path), "."))
+	if _, ok := containerFormat(ext); ok {
+		return ext
+	}
+
+	return "mp4"
+}
+
+func (p ReencodeProfileInput) args(input string, output string, format ffmpeg.Format) ffmpeg.Args {
+	var args ffmpeg.Args
+	args = args.LogLevel(ffmpeg.LogLevelError).Overwrite()
+	args = args.Input(input)
+
+	// keep the first video stream and all audio streams
+	args = append(args, "-map", "0:v:0", "-map", "0:a?")
+	args = args.MaxMuxingQueueSize(1024)
+
+	args = args.VideoCodec(ffmpeg.VideoCodec(p.VideoEncoder))
+	if p.Crf != nil {
+		args = append(args, "-crf", strconv.Itoa(*p.Crf))
+	}
+	if p.Preset != nil && *p.Preset != "" {
+		args = append(args, "-preset", *p.Preset)
+	}
+	if p.Threads != nil && *p.Threads > 0 {
+		args = append(args, "-threads", strconv.Itoa(*p.Threads))
+	}
+
+	audioCodec := ffmpeg.AudioCodecCopy
+	if p.AudioEncoder != nil && *p.AudioEncoder != "" {
+		audioCodec = ffmpeg.AudioCodec(*p.AudioEncoder)
+	}
+	args = args.AudioCodec(audioCodec)
+
+	if format == ffmpeg.FormatMP4 {
+		args = append(args, "-movflags", "+faststart")
+	}
+
+	args = args.Format(format)
+	args = args.Output(output)
+
+	return args
+}
+
+type reencodeJob struct {
+	txnManager Repository
+	input      ReencodeMetadataInput
+}
+
+func (j *reencodeJob) Execute(ctx context.Context, progress *job.Progress) {
+	logger.Infof("Starting re-encode task")
+	if j.input.DryRun {
+		logger.Infof("Running in Dry Mode")
+	}
+
+	scenes, err := j.findScenes(ctx)
+	if err != nil {
+		logger.Errorf("Error finding scenes to re-encode: %v", err)
+		return
+	}
+
+	if j.input.Limit != nil && *j.input.Limit > 0 && len(scenes) > *j.input.Limit {
+		scenes = scenes[:*j.input.Limit]
+	}
+
+	progress.SetTotal(len(scenes))
+
+	var dirs []string
+	var encoded int
+	for i, s := range scenes {
+		if job.IsCancelled(ctx) {
+			break
+		}
+
+		if i > 0 && !j.input.DryRun && !j.throttle(ctx) {
+			break
+		}
+
+		f := s.Files.Primary()
+		progress.ExecuteTask(fmt.Sprintf("Re-encoding %s", f.Path), func() {
+			if err := j.reencode(ctx, s, f); err != nil {
+				logger.Errorf("Error re-encoding %s: %v", f.Path, err)
+				return
+			}
+
+			encoded++
+			dirs = stringslice.StrAppendUnique(dirs, filepath.Dir(f.Path))
+		})
+
+		progress.Increment()
+	}
+
+	if job.IsCancelled(ctx) {
+		logger.Info("Stopping due to user request")
+	}
+
+	if j.input.DryRun {
+		logger.Infof("Finished re-encode task. Would re-encode %d scenes", encoded)
+		return
+	}
+
+	logger.Infof("Finished re-encode task. Re-encoded %d scenes", encoded)
+
+	if len(dirs) > 0 {
+		// rescan to update the fingerprints and metadata of the new files
+		if _, err := instance.Scan(ctx, ScanMetadataInput{Paths: dirs}); err != nil {
+			logger.Errorf("Error queueing scan of re-encoded files: %v", err)
+		}
+	}
+}
+
+// throttle waits for the configured throttle duration. Returns false if the
+// job was cancelled while waiting.
+func (j *reencodeJob) throttle(ctx context.Context) bool {
+	if j.input.Throttle == nil || *j.input.Throttle <= 0 {
+		return true
+	}
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(time.Duration(*j.input.Throttle) * time.Second):
+		return true
+	}
+}
+
+// findScenes returns the scenes to re-encode. Scenes which are already
+// encoded with the target codec are excluded, so that an interrupted job can
+// be resumed by running it again.
+func (j *reencodeJob) findScenes(ctx context.Context) ([]*models.Scene, error) {
+	targetCodec := encoderCodec(j.input.Profile.VideoEncoder)
+
+	var ret []*models.Scene
+	r := j.txnManager
+	if err := txn.WithReadTxn(ctx, r, func(ctx context.Context) error {
+		var scenes []*models.Scene
+		if len(j.input.SceneIDs) > 0 {
+			ids, err := stringslice.StringSliceToIntSlice(j.input.SceneIDs)
+			if err != nil {
+				return err
+			}
+
+			scenes, err = r.Scene.FindMany(ctx, ids)
+			if err != nil {
+				return err
+			}
+		} else {
+			allPages := -1
+			var err error
+			scenes, err = scene.Query(ctx, r.Scene, j.input.SceneFilter, &models.FindFilterType{
+				PerPage: &allPages,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, s := range scenes {
+			if err := s.LoadPrimaryFile(ctx, r.File); err != nil {
+				return err
+			}
+
+			f := s.Files.Primary()
+			if f == nil {
+				continue
+			}
+
+			if targetCodec != "" && strings.EqualFold(f.VideoCodec, targetCodec) {
+				logger.Debugf("Skipping %s: already encoded with %s", f.Path, targetCodec)
+				continue
+			}
+
+			ret = append(ret, s)
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (j *reencodeJob) reencode(ctx context.Context, s *models.Scene, f *file.VideoFile) error {
+	profile := j.input.Profile
+	container := profile.outputContainer(f)
+	format, _ := containerFormat(container)
+	newPath := replacementPath(f.Path, container)
+
+	if j.input.DryRun {
+		logger.Infof("[dry run] Would re-encode %s to %s", f.Path, newPath)
+		return nil
+	}
+
+	tempPath := f.Path + replacementTempSuffix
+	removeTemp := func() {
+		if err := os.Remove(tempPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			logger.Warnf("error removing %s: %v", tempPath, err)
+		}
+	}
+
+	logger.Infof("Re-encoding %s with %s", f.Path, profile.VideoEncoder)
+	start := time.Now()
+
+	if err := instance.FFMPEG.Generate(ctx, profile.args(f.Path, tempPath, format)); err != nil {
+		removeTemp()
+		return err
+	}
+
+	probed, err := instance.FFProbe.NewVideoFile(tempPath)
+	if err != nil {
+		removeTemp()
+		return fmt.Errorf("probing output: %w", err)
+	}
+
+	if err := verifyReplacement(f, probed, encoderCodec(profile.VideoEncoder)); err != nil {
+		removeTemp()
+		return fmt.Errorf("verifying output: %w", err)
+	}
+
+	// kill any running streams of the original file
+	KillRunningStreams(s, instance.Config.GetVideoFileNamingAlgorithm())
+
+	if err := replaceVideoFile(ctx, j.txnManager, f, tempPath, newPath); err != nil {
+		removeTemp()
+		return err
+	}
+
+	logger.Infof("Re-encoded %s in %s (%s -> %s)", newPath, time.Since(start).Round(time.Second), utils.FormatBytes(f.Size), utils.FormatBytes(probed.Size))
+	return nil
+}
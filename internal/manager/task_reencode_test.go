@@ -0,0 +1,139 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReencodeProfileArgs(t *testing.T) {
+	crf := 30
+	preset := "8"
+	audio := "libopus"
+	threads := 4
+
+	tests := []struct {
+		name    string
+		profile ReencodeProfileInput
+		format  ffmpeg.Format
+		want    []string
+	}{
+		{
+			"minimal",
+			ReencodeProfileInput{VideoEncoder: "libx265"},
+			ffmpeg.FormatMatroska,
+			[]string{"-v", "error", "-y", "-i", "in.mkv", "-map", "0:v:0", "-map", "0:a?", "-max_muxing_queue_size", "1024", "-c:v", "libx265", "-c:a", "copy", "-f", "matroska", "out.tmp"},
+		},
+		{
+			"full mp4",
+			ReencodeProfileInput{
+				VideoEncoder: "libsvtav1",
+				Crf:          &crf,
+				Preset:       &preset,
+				AudioEncoder: &audio,
+				Threads:      &threads,
+			},
+			ffmpeg.FormatMP4,
+			[]string{"-v", "error", "-y", "-i", "in.mkv", "-map", "0:v:0", "-map", "0:a?", "-max_muxing_queue_size", "1024", "-c:v", "libsvtav1", "-crf", "30", "-preset", "8", "-threads", "4", "-c:a", "libopus", "-movflags", "+faststart", "-f", "mp4", "out.tmp"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.profile.args("in.mkv", "out.tmp", tt.format)
+			assert.Equal(t, tt.want, []string(got))
+		})
+	}
+}
+
+func TestReencodeMetadataInputValidate(t *testing.T) {
+	profile := ReencodeProfileInput{VideoEncoder: "libx265"}
+	unsupported := "avi"
+
+	tests := []struct {
+		name    string
+		input   ReencodeMetadataInput
+		wantErr bool
+	}{
+		{"no scenes", ReencodeMetadataInput{Profile: profile}, true},
+		{"scene ids", ReencodeMetadataInput{SceneIDs: []string{"1"}, Profile: profile}, false},
+		{"empty filter", ReencodeMetadataInput{SceneFilter: &models.SceneFilterType{}, Profile: profile}, false},
+		{"no encoder", ReencodeMetadataInput{SceneIDs: []string{"1"}}, true},
+		{"unsupported container", ReencodeMetadataInput{
+			SceneIDs: []string{"1"},
+			Profile:  ReencodeProfileInput{VideoEncoder: "libx265", Container: &unsupported},
+		}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.input.validate()
+			assert.Equal(t, tt.wantErr, err != nil, "validate() error = %v", err)
+		})
+	}
+}
+
+func TestReencodeProfileOutputContainer(t *testing.T) {
+	mkv := "MKV"
+
+	tests := []struct {
+		name      string
+		container *string
+		path      string
+		want      string
+	}{
+		{"keep supported", nil, "/stash/a.webm", "webm"},
+		{"unsupported defaults to mp4", nil, "/stash/a.wmv", "mp4"},
+		{"explicit", &mkv, "/stash/a.mp4", "mkv"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := ReencodeProfileInput{Container: tt.container}
+			f := &file.VideoFile{BaseFile: &file.BaseFile{Path: tt.path}}
+			assert.Equal(t, tt.want, p.outputContainer(f))
+		})
+	}
+}
+
+func TestReplacementPath(t *testing.T) {
+	assert.Equal(t, "/stash/a.mp4", replacementPath("/stash/a.mp4", "mp4"))
+	assert.Equal(t, "/stash/a.MP4", replacementPath("/stash/a.MP4", "mp4"))
+	assert.Equal(t, "/stash/a.mkv", replacementPath("/stash/a.wmv", "mkv"))
+}
+
+func TestVerifyReplacement(t *testing.T) {
+	original := &file.VideoFile{
+		BaseFile:   &file.BaseFile{Path: "/stash/a.mp4"},
+		Duration:   100,
+		AudioCodec: "aac",
+	}
+
+	stream := &ffmpeg.FFProbeStream{}
+
+	tests := []struct {
+		name        string
+		replacement *ffmpeg.VideoFile
+		codec       string
+		wantErr     bool
+	}{
+		{"valid", &ffmpeg.VideoFile{VideoStream: stream, AudioStream: stream, VideoCodec: "av1", FileDuration: 100.5}, "av1", false},
+		{"unknown codec not checked", &ffmpeg.VideoFile{VideoStream: stream, AudioStream: stream, VideoCodec: "av1", FileDuration: 100}, "", false},
+		{"no video", &ffmpeg.VideoFile{AudioStream: stream, FileDuration: 100}, "av1", true},
+		{"no audio", &ffmpeg.VideoFile{VideoStream: stream, VideoCodec: "av1", FileDuration: 100}, "av1", true},
+		{"wrong codec", &ffmpeg.VideoFile{VideoStream: stream, AudioStream: stream, VideoCodec: "h264", FileDuration: 100}, "av1", true},
+		{"truncated", &ffmpeg.VideoFile{VideoStream: stream, AudioStream: stream, VideoCodec: "av1", FileDuration: 90}, "av1", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifyReplacement(original, tt.replacement, tt.codec)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("verifyReplacement() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/remeh/sizedwaitgroup"
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene"
+	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
+	"github.com/stashapp/stash/pkg/txn"
+)
+
+type RegenerateHeatmapsInput struct {
+	// IDs of scenes to regenerate, null for all interactive scenes
+	SceneIDs []string `json:"sceneIds"`
+	// Number of heatmaps to generate concurrently. Defaults to the parallel
+	// tasks setting
+	Workers *int `json:"workers"`
+}
+
+// RegenerateHeatmaps regenerates the interactive heatmaps and speeds of
+// interactive scenes, overwriting existing heatmaps. Used after changing the
+// heatmap settings.
+func (s *Manager) RegenerateHeatmaps(ctx context.Context, input RegenerateHeatmapsInput) (int, error) {
+	if input.Workers != nil && *input.Workers < 1 {
+		return 0, fmt.Errorf("workers must be at least 1")
+	}
+
+	sceneIDs, err := stringslice.StringSliceToIntSlice(input.SceneIDs)
+	if err != nil {
+		return 0, fmt.Errorf("converting scene ids: %w", err)
+	}
+
+	j := &regenerateHeatmapsJob{
+		txnManager: s.Repository,
+		sceneIDs:   sceneIDs,
+		workers:    input.Workers,
+	}
+
+	return s.JobManager.AddClass(ctx, "Regenerating interactive heatmaps...", j, job.ClassCPU), nil
+}
+
+type regenerateHeatmapsJob struct {
+	txnManager Repository
+	sceneIDs   []int
+	workers    *int
+}
+
+func (j *regenerateHeatmapsJob) Execute(ctx context.Context, progress *job.Progress) {
+	c := config.GetInstance()
+	workers := c.GetParallelTasksWithAutoDetection()
+	if j.workers != nil {
+		workers = *j.workers
+	}
+
+	scenes, err := j.findScenes(ctx)
+	if err != nil {
+		logger.Errorf("Error finding interactive scenes: %v", err)
+		return
+	}
+
+	logger.Infof("Regenerating %d interactive heatmaps with %d workers", len(scenes), workers)
+	progress.SetTotal(len(scenes))
+
+	start := time.Now()
+	fileNamingAlgorithm := c.GetVideoFileNamingAlgorithm()
+	wg := sizedwaitgroup.New(workers)
+
+	for _, s := range scenes {
+		if job.IsCancelled(ctx) {
+			break
+		}
+
+		t := &GenerateInteractiveHeatmapSpeedTask{
+			Scene:               *s,
+			Overwrite:           true,
+			fileNamingAlgorithm: fileNamingAlgorithm,
+			TxnManager:          j.txnManager,
+		}
+
+		wg.Add()
+		go progress.ExecuteTask(t.GetDescription(), func() {
+			defer wg.Done()
+			t.Start(ctx)
+			progress.Increment()
+		})
+	}
+
+	wg.Wait()
+
+	if job.IsCancelled(ctx) {
+		logger.Info("Stopping due to user request")
+		return
+	}
+
+	logger.Infof("Finished regenerating interactive heatmaps (%s)", time.Since(start))
+}
+
+// findScenes returns the scenes with an interactive primary file.
+func (j *regenerateHeatmapsJob) findScenes(ctx context.Context) ([]*models.Scene, error) {
+	var ret []*models.Scene
+
+	r := j.txnManager
+	if err := txn.WithReadTxn(ctx, r, func(ctx context.Context) error {
+		add := func(s *models.Scene) error {
+			if err := s.LoadFiles(ctx, r.Scene); err != nil {
+				return err
+			}
+
+			if f := s.Files.Primary(); f != nil && f.Interactive {
+				ret = append(ret, s)
+			}
+
+			return nil
+		}
+
+		if len(j.sceneIDs) > 0 {
+			scenes, err := r.Scene.FindMany(ctx, j.sceneIDs)
+			if err != nil {
+				return err
+			}
+
+			for _, s := range scenes {
+				if err := add(s); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}
+
+		interactive := true
+		return scene.BatchProcess(ctx, r.Scene, &models.SceneFilterType{
+			Interactive: &interactive,
+		}, nil, add)
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,247 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene"
+	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
+	"github.com/stashapp/stash/pkg/txn"
+)
+
+type RemuxMetadataInput struct {
+	// IDs of scenes to remux, null for all scenes that can be remuxed
+	SceneIDs []string `json:"sceneIds"`
+	// Do a dry run. Don't remux any scenes
+	DryRun bool `json:"dryRun"`
+}
+
+func (s *Manager) Remux(ctx context.Context, input RemuxMetadataInput) (int, error) {
+	if err := s.validateFFMPEG(); err != nil {
+		return 0, err
+	}
+
+	j := remuxJob{
+		txnManager: s.Repository,
+		input:      input,
+	}
+
+	return s.JobManager.AddClass(ctx, "Remuxing scenes...", &j, job.ClassCPU), nil
+}
+
+// canRemux returns true if the file cannot be direct-played by browsers in
+// its current container, but could be after being remuxed to mp4 without
+// re-encoding.
+func canRemux(f *file.VideoFile) bool {
+	audioCodec := ffmpeg.MissingUnsupported
+	if f.AudioCodec != "" {
+		audioCodec = ffmpeg.ProbeAudioCodec(f.AudioCodec)
+	}
+
+	container := ffmpeg.Container(f.Format)
+	if container != "" && ffmpeg.IsStreamable(f.VideoCodec, audioCodec, container) == nil {
+		// already streamable
+		return false
+	}
+
+	return ffmpeg.IsStreamable(f.VideoCodec, audioCodec, ffmpeg.Mp4) == nil
+}
+
+func remuxArgs(input string, output string) ffmpeg.Args {
+	var args ffmpeg.Args
+	args = args.LogLevel(ffmpeg.LogLevelError).Overwrite()
+	args = args.Input(input)
+
+	// keep the first video stream and all audio streams
+	args = append(args, "-map", "0:v:0", "-map", "0:a?")
+	args = args.MaxMuxingQueueSize(1024)
+	args = args.VideoCodec(ffmpeg.VideoCodecCopy)
+	args = args.AudioCodec(ffmpeg.AudioCodecCopy)
+	args = append(args, "-movflags", "+faststart")
+	args = args.Format(ffmpeg.FormatMP4)
+	args = args.Output(output)
+
+	return args
+}
+
+type remuxJob struct {
+	txnManager Repository
+	input      RemuxMetadataInput
+}
+
+func (j *remuxJob) Execute(ctx context.Context, progress *job.Progress) {
+	logger.Infof("Starting remux task")
+	if j.input.DryRun {
+		logger.Infof("Running in Dry Mode")
+	}
+
+	scenes, err := j.findScenes(ctx)
+	if err != nil {
+		logger.Errorf("Error finding scenes to remux: %v", err)
+		return
+	}
+
+	progress.SetTotal(len(scenes))
+
+	var dirs []string
+	var remuxed int
+	for _, s := range scenes {
+		if job.IsCancelled(ctx) {
+			logger.Info("Stopping due to user request")
+			break
+		}
+
+		f := s.Files.Primary()
+		progress.ExecuteTask(fmt.Sprintf("Remuxing %s", f.Path), func() {
+			if err := j.remux(ctx, s, f); err != nil {
+				logger.Errorf("Error remuxing %s: %v", f.Path, err)
+				return
+			}
+
+			remuxed++
+			dirs = stringslice.StrAppendUnique(dirs, filepath.Dir(f.Path))
+		})
+
+		progress.Increment()
+	}
+
+	if j.input.DryRun {
+		logger.Infof("Finished remux task. Would remux %d scenes", remuxed)
+		return
+	}
+
+	logger.Infof("Finished remux task. Remuxed %d scenes", remuxed)
+
+	if len(dirs) > 0 {
+		// rescan to update the fingerprints and metadata of the new files.
+		// Generated content is migrated to the new file hashes by the scan.
+		if _, err := instance.Scan(ctx, ScanMetadataInput{Paths: dirs}); err != nil {
+			logger.Errorf("Error queueing scan of remuxed files: %v", err)
+		}
+	}
+}
+
+func (j *remuxJob) findScenes(ctx context.Context) ([]*models.Scene, error) {
+	var ret []*models.Scene
+	r := j.txnManager
+
+	add := func(ctx context.Context, s *models.Scene) error {
+		if err := s.LoadPrimaryFile(ctx, r.File); err != nil {
+			return err
+		}
+
+		if f := s.Files.Primary(); f != nil && canRemux(f) {
+			ret = append(ret, s)
+		}
+
+		return nil
+	}
+
+	if err := txn.WithReadTxn(ctx, r, func(ctx context.Context) error {
+		if len(j.input.SceneIDs) == 0 {
+			return scene.BatchProcess(ctx, r.Scene, nil, nil, func(s *models.Scene) error {
+				return add(ctx, s)
+			})
+		}
+
+		ids, err := stringslice.StringSliceToIntSlice(j.input.SceneIDs)
+		if err != nil {
+			return err
+		}
+
+		scenes, err := r.Scene.FindMany(ctx, ids)
+		if err != nil {
+			return err
+		}
+
+		for _, s := range scenes {
+			if err := add(ctx, s); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (j *remuxJob) remux(ctx context.Context, s *models.Scene, f *file.VideoFile) error {
+	newPath := replacementPath(f.Path, "mp4")
+
+	original, err := instance.FFProbe.NewVideoFile(f.Path)
+	if err != nil {
+		return fmt.Errorf("probing original: %w", err)
+	}
+
+	// subtitle streams cannot always be stored in mp4. Leave these files
+	// alone rather than dropping the subtitles.
+	for _, stream := range original.JSON.Streams {
+		if stream.CodecType == "subtitle" {
+			logger.Infof("Skipping %s: file contains subtitle streams", f.Path)
+			return nil
+		}
+	}
+
+	if j.input.DryRun {
+		logger.Infof("[dry run] Would remux %s to %s", f.Path, newPath)
+		return nil
+	}
+
+	tempPath := f.Path + replacementTempSuffix
+	removeTemp := func() {
+		if err := os.Remove(tempPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			logger.Warnf("error removing %s: %v", tempPath, err)
+		}
+	}
+
+	logger.Infof("Remuxing %s", f.Path)
+	start := time.Now()
+
+	if err := instance.FFMPEG.Generate(ctx, remuxArgs(f.Path, tempPath)); err != nil {
+		removeTemp()
+		return err
+	}
+
+	probed, err := instance.FFProbe.NewVideoFile(tempPath)
+	if err != nil {
+		removeTemp()
+		return fmt.Errorf("probing output: %w", err)
+	}
+
+	if err := verifyReplacement(f, probed, original.VideoCodec); err != nil {
+		removeTemp()
+		return fmt.Errorf("verifying output: %w", err)
+	}
+
+	fileNamingAlgo := instance.Config.GetVideoFileNamingAlgorithm()
+	KillRunningStreams(s, fileNamingAlgo)
+
+	if err := replaceVideoFile(ctx, j.txnManager, f, tempPath, newPath); err != nil {
+		removeTemp()
+		return err
+	}
+
+	// the transcode is no longer needed now that the file can be played
+	// directly. Other generated content is unaffected by the container.
+	if hash := s.GetHash(fileNamingAlgo); hash != "" {
+		transcodePath := instance.Paths.Scene.GetTranscodePath(hash)
+		if err := os.Remove(transcodePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			logger.Warnf("error removing transcode %s: %v", transcodePath, err)
+		}
+	}
+
+	logger.Infof("Remuxed %s in %s", newPath, time.Since(start).Round(time.Second))
+	return nil
+}
@@ -0,0 +1,42 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanRemux(t *testing.T) {
+	tests := []struct {
+		name       string
+		format     string
+		videoCodec string
+		audioCodec string
+		want       bool
+	}{
+		{"mp4 h264 aac", "mp4", "h264", "aac", false},
+		{"mkv h264 aac", "matroska", "h264", "aac", true},
+		{"mkv h264 no audio", "matroska", "h264", "", true},
+		{"avi h264 mp3", "avi", "h264", "mp3", true},
+		{"mkv h264 opus", "matroska", "h264", "opus", false},
+		{"avi mpeg4", "avi", "mpeg4", "mp3", false},
+		{"wmv", "asf", "wmv3", "wmav2", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &file.VideoFile{
+				Format:     tt.format,
+				VideoCodec: tt.videoCodec,
+				AudioCodec: tt.audioCodec,
+			}
+			assert.Equal(t, tt.want, canRemux(f))
+		})
+	}
+}
+
+func TestRemuxArgs(t *testing.T) {
+	want := []string{"-v", "error", "-y", "-i", "in.mkv", "-map", "0:v:0", "-map", "0:a?", "-max_muxing_queue_size", "1024", "-c:v", "copy", "-c:a", "copy", "-movflags", "+faststart", "-f", "mp4", "out.tmp"}
+	assert.Equal(t, want, []string(remuxArgs("in.mkv", "out.tmp")))
+}
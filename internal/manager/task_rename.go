@@ -0,0 +1,495 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
+	"github.com/stashapp/stash/pkg/txn"
+)
+
+type RenameFilesInput struct {
+	// IDs of scenes whose files are renamed
+	SceneIDs []string `json:"sceneIds"`
+	// IDs of images whose files are renamed
+	ImageIDs []string `json:"imageIds"`
+	// IDs of galleries whose zip files are renamed
+	GalleryIDs []string `json:"galleryIds"`
+	// Template of the new paths. Defaults to the configured rename template
+	Template *string `json:"template"`
+}
+
+// FileRename is the new path of a renamed file.
+type FileRename struct {
+	FileID  string `json:"fileId"`
+	OldPath string `json:"oldPath"`
+	// empty if the new path could not be determined
+	NewPath string `json:"newPath"`
+	// reason that the file cannot be renamed
+	Error *string `json:"error,omitempty"`
+}
+
+func (r *FileRename) setError(format string, args ...interface{}) {
+	err := fmt.Sprintf(format, args...)
+	r.Error = &err
+}
+
+// renameJournalPrefix is the name prefix of the artifacts storing the files
+// renamed by a rename job, which are used to undo the renames.
+const renameJournalPrefix = "rename-"
+
+// renameTokens are the tokens supported by rename templates.
+var renameTokens = []string{"title", "date", "year", "studio", "resolution", "basename"}
+
+// ValidateRenameTemplate returns an error if the template is empty or
+// contains unsupported tokens.
+func ValidateRenameTemplate(template string) error {
+	values := make(map[string]string)
+	for _, t := range renameTokens {
+		values[t] = t
+	}
+
+	_, err := file.ExpandRenamePattern(template, values)
+	return err
+}
+
+// renameTemplate validates the input, returning the template to use.
+func (s *Manager) renameTemplate(input RenameFilesInput) (string, error) {
+	if len(input.SceneIDs) == 0 && len(input.ImageIDs) == 0 && len(input.GalleryIDs) == 0 {
+		return "", errors.New("no scenes, images or galleries to rename")
+	}
+
+	template := s.Config.GetRenameTemplate()
+	if input.Template != nil {
+		template = strings.TrimSpace(*input.Template)
+	}
+
+	if err := ValidateRenameTemplate(template); err != nil {
+		return "", fmt.Errorf("invalid rename template: %w", err)
+	}
+
+	return template, nil
+}
+
+// PreviewRenames returns the new paths of the files that would be renamed by
+// the rename task, without renaming any files.
+func (s *Manager) PreviewRenames(ctx context.Context, input RenameFilesInput) ([]*FileRename, error) {
+	template, err := s.renameTemplate(input)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.planRenames(ctx, input, template)
+}
+
+// Rename queues a job to rename the files of the scenes, images and galleries
+// using the rename template. Files which cannot be renamed are skipped. The
+// renamed files are stored in a journal artifact, which can be used to undo
+// the renames.
+func (s *Manager) Rename(ctx context.Context, input RenameFilesInput) (int, error) {
+	template, err := s.renameTemplate(input)
+	if err != nil {
+		return 0, err
+	}
+
+	j := job.MakeJobExec(func(ctx context.Context, progress *job.Progress) {
+		renames, err := s.planRenames(ctx, input, template)
+		if err != nil {
+			logger.Errorf("Error determining new file paths: %v", err)
+			return
+		}
+
+		renamed := s.executeRenames(ctx, progress, renames)
+		if len(renamed) == 0 {
+			return
+		}
+
+		name := renameJournalPrefix + time.Now().Format("20060102-150405") + ".json"
+		a, err := s.AddJobArtifact(ctx, name, "application/json", func(w io.Writer) error {
+			return json.NewEncoder(w).Encode(renamed)
+		})
+		if err != nil {
+			logger.Errorf("Error storing rename journal: %v", err)
+			return
+		}
+
+		logger.Infof("Renamed %d files. The renames can be undone using journal %d", len(renamed), a.ID)
+	})
+
+	return s.JobManager.Add(ctx, "Renaming files...", j), nil
+}
+
+// UndoRename queues a job to move the files renamed by a rename job back to
+// their original paths, using the journal artifact of the job. Files which
+// have been moved since they were renamed are skipped.
+func (s *Manager) UndoRename(ctx context.Context, journalID int) (int, error) {
+	var a *models.JobArtifact
+	if err := txn.WithReadTxn(ctx, s.Repository, func(ctx context.Context) error {
+		var err error
+		a, err = s.Repository.JobArtifact.Find(ctx, journalID)
+		return err
+	}); err != nil {
+		return 0, err
+	}
+
+	if a == nil || !strings.HasPrefix(a.Name, renameJournalPrefix) {
+		return 0, fmt.Errorf("rename journal with id %d not found", journalID)
+	}
+
+	data, err := os.ReadFile(s.JobArtifactPath(a))
+	if err != nil {
+		return 0, fmt.Errorf("reading rename journal: %w", err)
+	}
+
+	var renamed []*FileRename
+	if err := json.Unmarshal(data, &renamed); err != nil {
+		return 0, fmt.Errorf("invalid rename journal: %w", err)
+	}
+
+	// undo in reverse order
+	undo := make([]*FileRename, len(renamed))
+	for i, r := range renamed {
+		undo[len(renamed)-1-i] = &FileRename{
+			FileID:  r.FileID,
+			OldPath: r.NewPath,
+			NewPath: r.OldPath,
+		}
+	}
+
+	j := job.MakeJobExec(func(ctx context.Context, progress *job.Progress) {
+		restored := s.executeRenames(ctx, progress, undo)
+		logger.Infof("Restored %d of %d renamed files", len(restored), len(undo))
+	})
+
+	return s.JobManager.Add(ctx, "Undoing file renames...", j), nil
+}
+
+// executeRenames renames the files without errors, returning the files that
+// were renamed.
+func (s *Manager) executeRenames(ctx context.Context, progress *job.Progress, renames []*FileRename) []*FileRename {
+	progress.SetTotal(len(renames))
+
+	var ret []*FileRename
+	for _, r := range renames {
+		if job.IsCancelled(ctx) {
+			logger.Info("Stopping due to user request")
+			break
+		}
+
+		switch {
+		case r.Error != nil:
+			logger.Warnf("Not renaming %q: %s", r.OldPath, *r.Error)
+		case r.NewPath != r.OldPath:
+			progress.ExecuteTask(fmt.Sprintf("Renaming %s", r.OldPath), func() {
+				if err := s.renameFile(ctx, r); err != nil {
+					logger.Errorf("Error renaming %q: %v", r.OldPath, err)
+					return
+				}
+				ret = append(ret, r)
+			})
+		}
+
+		progress.Increment()
+	}
+
+	return ret
+}
+
+func (s *Manager) renameFile(ctx context.Context, r *FileRename) error {
+	fileID, err := strconv.Atoi(r.FileID)
+	if err != nil {
+		return err
+	}
+
+	mover := file.NewMover(s.Repository.File, s.Repository.Folder)
+
+	if err := txn.WithTxn(ctx, s.Repository, func(ctx context.Context) error {
+		files, err := s.Repository.File.Find(ctx, file.ID(fileID))
+		if err != nil {
+			return err
+		}
+		if len(files) == 0 {
+			return fmt.Errorf("file with id %d not found", fileID)
+		}
+
+		if p := files[0].Base().Path; p != r.OldPath {
+			return fmt.Errorf("file has been moved to %q", p)
+		}
+
+		return mover.Move(ctx, files[0], r.NewPath)
+	}); err != nil {
+		mover.Rollback()
+		return err
+	}
+
+	mover.Commit()
+	return nil
+}
+
+type renameCandidate struct {
+	file   file.File
+	values map[string]string
+}
+
+func (s *Manager) planRenames(ctx context.Context, input RenameFilesInput, template string) ([]*FileRename, error) {
+	var libraryPaths []string
+	for _, sp := range s.Config.GetStashPaths() {
+		libraryPaths = append(libraryPaths, sp.Path)
+	}
+
+	var ret []*FileRename
+	r := s.Repository
+	if err := txn.WithReadTxn(ctx, r, func(ctx context.Context) error {
+		candidates, err := s.findRenameCandidates(ctx, input)
+		if err != nil {
+			return err
+		}
+
+		var existsErr error
+		exists := func(path string) bool {
+			if _, err := os.Stat(path); err == nil {
+				return true
+			}
+
+			f, err := r.File.FindByPath(ctx, path)
+			if err != nil {
+				existsErr = err
+			}
+			return f != nil
+		}
+
+		ret = planRenames(candidates, template, libraryPaths, exists)
+		return existsErr
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (s *Manager) findRenameCandidates(ctx context.Context, input RenameFilesInput) ([]renameCandidate, error) {
+	sceneIDs, err := stringslice.StringSliceToIntSlice(input.SceneIDs)
+	if err != nil {
+		return nil, err
+	}
+	imageIDs, err := stringslice.StringSliceToIntSlice(input.ImageIDs)
+	if err != nil {
+		return nil, err
+	}
+	galleryIDs, err := stringslice.StringSliceToIntSlice(input.GalleryIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	r := s.Repository
+	var ret []renameCandidate
+	seen := make(map[file.ID]bool)
+	add := func(f file.File, values map[string]string, width, height int) {
+		base := f.Base()
+		if seen[base.ID] {
+			return
+		}
+		seen[base.ID] = true
+
+		v := make(map[string]string, len(values)+2)
+		for k, val := range values {
+			v[k] = val
+		}
+		v["basename"] = strings.TrimSuffix(base.Basename, filepath.Ext(base.Basename))
+		if v["title"] == "" {
+			v["title"] = v["basename"]
+		}
+		v["resolution"] = resolutionLabel(width, height)
+
+		ret = append(ret, renameCandidate{file: f, values: v})
+	}
+
+	scenes, err := r.Scene.FindMany(ctx, sceneIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, scene := range scenes {
+		if err := scene.LoadFiles(ctx, r.Scene); err != nil {
+			return nil, err
+		}
+
+		values, err := s.renameValues(ctx, scene.Title, scene.Date, scene.StudioID)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range scene.Files.List() {
+			add(f, values, f.Width, f.Height)
+		}
+	}
+
+	images, err := r.Image.FindMany(ctx, imageIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, image := range images {
+		if err := image.LoadFiles(ctx, r.Image); err != nil {
+			return nil, err
+		}
+
+		values, err := s.renameValues(ctx, image.Title, image.Date, image.StudioID)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range image.Files.List() {
+			add(f, values, f.Width, f.Height)
+		}
+	}
+
+	galleries, err := r.Gallery.FindMany(ctx, galleryIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, gallery := range galleries {
+		if err := gallery.LoadFiles(ctx, r.Gallery); err != nil {
+			return nil, err
+		}
+
+		values, err := s.renameValues(ctx, gallery.Title, gallery.Date, gallery.StudioID)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range gallery.Files.List() {
+			add(f, values, 0, 0)
+		}
+	}
+
+	return ret, nil
+}
+
+func (s *Manager) renameValues(ctx context.Context, title string, date *models.Date, studioID *int) (map[string]string, error) {
+	ret := map[string]string{
+		"title":  title,
+		"date":   "",
+		"year":   "",
+		"studio": "",
+	}
+
+	if date != nil {
+		ret["date"] = date.String()
+		ret["year"] = strconv.Itoa(date.Year())
+	}
+
+	if studioID != nil {
+		studio, err := s.Repository.Studio.Find(ctx, *studioID)
+		if err != nil {
+			return nil, err
+		}
+		if studio != nil {
+			ret["studio"] = studio.Name.String
+		}
+	}
+
+	return ret, nil
+}
+
+// resolutionLabel returns the name of the standard resolution of the
+// dimensions, such as 1080p. Returns an empty string if the dimensions are
+// unknown.
+func resolutionLabel(width, height int) string {
+	size := height
+	if width < height {
+		size = width
+	}
+
+	if size <= 0 {
+		return ""
+	}
+
+	for i := len(models.AllResolutionEnum) - 1; i >= 0; i-- {
+		r := models.AllResolutionEnum[i]
+		if min := r.GetMinResolution(); size >= min {
+			return strconv.Itoa(min) + "p"
+		}
+	}
+
+	return strconv.Itoa(size) + "p"
+}
+
+// libraryPathOf returns the deepest library path containing the path, or an
+// empty string if it is not in a library path.
+func libraryPathOf(libraryPaths []string, p string) string {
+	var ret string
+	for _, l := range libraryPaths {
+		if fsutil.IsPathInDir(l, p) && len(l) > len(ret) {
+			ret = l
+		}
+	}
+
+	return ret
+}
+
+// planRenames returns the new paths of the candidate files, relative to their
+// library path. Files that are inside zip files, that are not in a library
+// path, or whose new path already exists or is the new path of another file
+// have their error set. exists returns true if a file exists at the path.
+func planRenames(candidates []renameCandidate, template string, libraryPaths []string, exists func(path string) bool) []*FileRename {
+	var ret []*FileRename
+	newPaths := make(map[string]*FileRename)
+
+	for _, c := range candidates {
+		base := c.file.Base()
+		r := &FileRename{
+			FileID:  base.ID.String(),
+			OldPath: base.Path,
+		}
+		ret = append(ret, r)
+
+		if base.ZipFileID != nil {
+			r.setError("file is inside a zip file")
+			continue
+		}
+
+		libraryPath := libraryPathOf(libraryPaths, base.Path)
+		if libraryPath == "" {
+			r.setError("file is not in a library path")
+			continue
+		}
+
+		rel, err := file.ExpandRenamePattern(template, c.values)
+		if err != nil {
+			r.setError("%v", err)
+			continue
+		}
+
+		r.NewPath = filepath.Join(libraryPath, rel) + filepath.Ext(base.Basename)
+		if r.NewPath == r.OldPath {
+			continue
+		}
+
+		// compare case-insensitively, since the file system may be
+		// case-insensitive
+		key := strings.ToLower(r.NewPath)
+		if other, found := newPaths[key]; found {
+			r.setError("new path is the same as the new path of %q", other.OldPath)
+			if other.Error == nil {
+				other.setError("new path is the same as the new path of %q", r.OldPath)
+			}
+			continue
+		}
+		newPaths[key] = r
+
+		if exists(r.NewPath) {
+			r.setError("%q already exists", r.NewPath)
+		}
+	}
+
+	return ret
+}
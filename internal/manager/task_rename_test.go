@@ -0,0 +1,82 @@
+package manager
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolutionLabel(t *testing.T) {
+	tests := []struct {
+		width  int
+		height int
+		want   string
+	}{
+		{1920, 1080, "1080p"},
+		{1080, 1920, "1080p"},
+		{3840, 2160, "2160p"},
+		{1280, 718, "540p"},
+		{100, 100, "100p"},
+		{0, 0, ""},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, resolutionLabel(tt.width, tt.height), "%dx%d", tt.width, tt.height)
+	}
+}
+
+func TestPlanRenames(t *testing.T) {
+	library := filepath.Join(string(filepath.Separator), "library")
+	existing := filepath.Join(library, "Studio", "Existing.mp4")
+	zipFileID := file.ID(100)
+
+	candidate := func(id file.ID, path string, title string) renameCandidate {
+		return renameCandidate{
+			file: &file.BaseFile{
+				ID:       id,
+				Path:     path,
+				Basename: filepath.Base(path),
+			},
+			values: map[string]string{
+				"studio": "Studio",
+				"title":  title,
+			},
+		}
+	}
+
+	inZip := candidate(6, filepath.Join(library, "a.zip", "b.jpg"), "Zipped")
+	inZip.file.Base().ZipFileID = &zipFileID
+
+	candidates := []renameCandidate{
+		candidate(1, filepath.Join(library, "a.mp4"), "Renamed"),
+		candidate(2, filepath.Join(library, "Studio", "Unchanged.mp4"), "Unchanged"),
+		candidate(3, filepath.Join(library, "b.mp4"), "Existing"),
+		candidate(4, filepath.Join(library, "c.mp4"), "Same"),
+		candidate(5, filepath.Join(library, "d.mp4"), "same"),
+		inZip,
+		candidate(7, filepath.Join(string(filepath.Separator), "other", "e.mp4"), "Other"),
+	}
+
+	exists := func(path string) bool {
+		return path == existing
+	}
+
+	got := planRenames(candidates, "{studio}/{title}", []string{library}, exists)
+	if !assert.Len(t, got, len(candidates)) {
+		return
+	}
+
+	assert.Equal(t, "1", got[0].FileID)
+	assert.Equal(t, filepath.Join(library, "Studio", "Renamed.mp4"), got[0].NewPath)
+	assert.Nil(t, got[0].Error)
+
+	assert.Equal(t, got[1].OldPath, got[1].NewPath)
+	assert.Nil(t, got[1].Error)
+
+	// collisions
+	for _, i := range []int{2, 3, 4, 5, 6} {
+		assert.NotNil(t, got[i].Error, got[i].OldPath)
+	}
+}
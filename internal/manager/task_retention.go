@@ -0,0 +1,328 @@
+package manager
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/plugin"
+	"github.com/stashapp/stash/pkg/scene"
+	"github.com/stashapp/stash/pkg/sliceutil/intslice"
+	"github.com/stashapp/stash/pkg/txn"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+type RetentionMetadataInput struct {
+	// Paths to apply retention rules to, null for all stash paths with rules
+	Paths []string `json:"paths"`
+	// Do a dry run. Don't delete any scenes
+	DryRun bool `json:"dryRun"`
+}
+
+func (s *Manager) Retention(ctx context.Context, input RetentionMetadataInput) int {
+	j := retentionJob{
+		txnManager:   s.Repository,
+		sceneService: s.SceneService,
+		input:        input,
+	}
+
+	return s.JobManager.Add(ctx, "Applying retention rules...", &j)
+}
+
+type retentionCandidate struct {
+	scene  *models.Scene
+	size   int64
+	reason string
+}
+
+type retentionJob struct {
+	txnManager   Repository
+	sceneService SceneService
+	input        RetentionMetadataInput
+}
+
+func (j *retentionJob) Execute(ctx context.Context, progress *job.Progress) {
+	logger.Infof("Starting retention task")
+	if j.input.DryRun {
+		logger.Infof("Running in Dry Mode")
+	}
+
+	var stashes []*config.StashConfig
+	for _, s := range getScanPaths(j.input.Paths) {
+		if s.Retention.IsSet() {
+			stashes = append(stashes, s)
+		}
+	}
+
+	if len(stashes) == 0 {
+		logger.Info("No stash paths with retention rules configured")
+		return
+	}
+
+	progress.SetTotal(len(stashes))
+
+	var deleted int
+	var freed int64
+	var report []retentionReportRow
+	for _, s := range stashes {
+		if job.IsCancelled(ctx) {
+			logger.Info("Stopping due to user request")
+			return
+		}
+
+		progress.ExecuteTask(fmt.Sprintf("Applying retention rules to %s", s.Path), func() {
+			candidates, err := j.findCandidates(ctx, s)
+			if err != nil {
+				logger.Errorf("Error applying retention rules to %s: %v", s.Path, err)
+				return
+			}
+
+			for _, c := range candidates {
+				if j.input.DryRun {
+					logger.Infof("[dry run] Scene %q would be deleted (%s, %s)", c.scene.Path, c.reason, utils.FormatBytes(c.size))
+					report = append(report, retentionReportRow{c, "would delete"})
+					deleted++
+					freed += c.size
+					continue
+				}
+
+				if job.IsCancelled(ctx) {
+					return
+				}
+
+				logger.Infof("Deleting scene %q (%s, %s)", c.scene.Path, c.reason, utils.FormatBytes(c.size))
+				if err := j.deleteScene(ctx, c.scene.ID); err != nil {
+					logger.Errorf("Error deleting scene %q: %v", c.scene.Path, err)
+					report = append(report, retentionReportRow{c, "error: " + err.Error()})
+					continue
+				}
+
+				report = append(report, retentionReportRow{c, "deleted"})
+				deleted++
+				freed += c.size
+			}
+		})
+
+		progress.Increment()
+	}
+
+	verb := "Deleted"
+	if j.input.DryRun {
+		verb = "Would delete"
+	}
+	logger.Infof("Finished retention task. %s %d scenes, freeing %s", verb, deleted, utils.FormatBytes(freed))
+
+	if len(report) > 0 {
+		name := "retention-" + time.Now().Format("20060102-150405") + ".csv"
+		if _, err := instance.AddJobArtifact(ctx, name, "text/csv", func(w io.Writer) error {
+			return writeRetentionReport(w, report)
+		}); err != nil {
+			logger.Errorf("Error storing retention report: %v", err)
+		}
+	}
+}
+
+type retentionReportRow struct {
+	retentionCandidate
+	result string
+}
+
+// writeRetentionReport writes the scenes deleted by the retention task as
+// CSV.
+func writeRetentionReport(w io.Writer, rows []retentionReportRow) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"scene_id", "path", "reason", "size", "result"}); err != nil {
+		return err
+	}
+
+	for _, r := range rows {
+		if err := cw.Write([]string{
+			strconv.Itoa(r.scene.ID),
+			r.scene.Path,
+			r.reason,
+			strconv.FormatInt(r.size, 10),
+			r.result,
+		}); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func (j *retentionJob) findCandidates(ctx context.Context, s *config.StashConfig) ([]retentionCandidate, error) {
+	var tagID int
+	if s.Retention.TagID != nil && *s.Retention.TagID != "" {
+		var err error
+		tagID, err = strconv.Atoi(*s.Retention.TagID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid retention tag id %q: %w", *s.Retention.TagID, err)
+		}
+	}
+
+	var scenes []*models.Scene
+	var eligible []bool
+	r := j.txnManager
+	if err := txn.WithReadTxn(ctx, r, func(ctx context.Context) error {
+		return scene.BatchProcess(ctx, r.Scene, scene.FilterFromPaths([]string{s.Path}), nil, func(sc *models.Scene) error {
+			if err := sc.LoadFiles(ctx, r.Scene); err != nil {
+				return err
+			}
+
+			// ignore scenes that have files outside of this stash path
+			for _, f := range sc.Files.List() {
+				if !fsutil.IsPathInDir(s.Path, f.Path) {
+					return nil
+				}
+			}
+
+			isEligible := true
+			if tagID != 0 {
+				if err := sc.LoadTagIDs(ctx, r.Scene); err != nil {
+					return err
+				}
+				isEligible = intslice.IntInclude(sc.TagIDs.List(), tagID)
+			}
+
+			scenes = append(scenes, sc)
+			eligible = append(eligible, isEligible)
+			return nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+
+	return selectRetentionCandidates(scenes, eligible, s.Retention, time.Now()), nil
+}
+
+func sceneFilesSize(s *models.Scene) int64 {
+	var ret int64
+	for _, f := range s.Files.List() {
+		ret += f.Size
+	}
+	return ret
+}
+
+// selectRetentionCandidates returns the scenes that must be deleted to satisfy
+// the provided retention rules. eligible indicates which of the provided scenes
+// may be deleted. Watched scenes are deleted before unwatched scenes when
+// reducing the total size, oldest first.
+func selectRetentionCandidates(scenes []*models.Scene, eligible []bool, rules *config.StashRetentionConfig, now time.Time) []retentionCandidate {
+	var ret []retentionCandidate
+	var remaining []retentionCandidate
+	var total int64
+
+	for i, s := range scenes {
+		size := sceneFilesSize(s)
+		total += size
+
+		if !eligible[i] {
+			continue
+		}
+
+		c := retentionCandidate{
+			scene: s,
+			size:  size,
+		}
+
+		if rules.WatchedOlderThanDays > 0 && s.LastPlayedAt != nil {
+			cutoff := now.AddDate(0, 0, -rules.WatchedOlderThanDays)
+			if s.LastPlayedAt.Before(cutoff) {
+				c.reason = fmt.Sprintf("watched more than %d days ago", rules.WatchedOlderThanDays)
+				ret = append(ret, c)
+				total -= size
+				continue
+			}
+		}
+
+		remaining = append(remaining, c)
+	}
+
+	if rules.MaxSize <= 0 || total <= rules.MaxSize {
+		return ret
+	}
+
+	sort.SliceStable(remaining, func(i, j int) bool {
+		a := remaining[i].scene
+		b := remaining[j].scene
+
+		// watched scenes first, least recently played first
+		switch {
+		case a.LastPlayedAt != nil && b.LastPlayedAt != nil:
+			return a.LastPlayedAt.Before(*b.LastPlayedAt)
+		case a.LastPlayedAt != nil:
+			return true
+		case b.LastPlayedAt != nil:
+			return false
+		}
+
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+
+	for _, c := range remaining {
+		if total <= rules.MaxSize {
+			break
+		}
+
+		c.reason = fmt.Sprintf("path exceeds maximum size of %s", utils.FormatBytes(rules.MaxSize))
+		ret = append(ret, c)
+		total -= c.size
+	}
+
+	return ret
+}
+
+func (j *retentionJob) deleteScene(ctx context.Context, id int) error {
+	mgr := GetInstance()
+	fileNamingAlgo := mgr.Config.GetVideoFileNamingAlgorithm()
+
+	fileDeleter := &scene.FileDeleter{
+		Deleter:        NewFileDeleter(),
+		FileNamingAlgo: fileNamingAlgo,
+		Paths:          mgr.Paths,
+	}
+
+	if err := txn.WithTxn(ctx, j.txnManager, func(ctx context.Context) error {
+		s, err := j.txnManager.Scene.Find(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if s == nil {
+			return fmt.Errorf("scene not found: %d", id)
+		}
+
+		// kill any running encoders
+		KillRunningStreams(s, fileNamingAlgo)
+
+		const deleteGenerated = true
+		const deleteFile = true
+		if err := j.sceneService.Destroy(ctx, s, fileDeleter, deleteGenerated, deleteFile); err != nil {
+			return err
+		}
+
+		mgr.PluginCache.RegisterPostHooks(ctx, s.ID, plugin.SceneDestroyPost, plugin.SceneDestroyInput{
+			Checksum: s.Checksum,
+			OSHash:   s.OSHash,
+			Path:     s.Path,
+		}, nil)
+
+		return nil
+	}); err != nil {
+		fileDeleter.Rollback()
+		return err
+	}
+
+	fileDeleter.Commit()
+	return nil
+}
@@ -0,0 +1,124 @@
+package manager
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func makeRetentionTestScene(id int, size int64, created time.Time, lastPlayed *time.Time) *models.Scene {
+	return &models.Scene{
+		ID:           id,
+		CreatedAt:    created,
+		LastPlayedAt: lastPlayed,
+		Files: models.NewRelatedVideoFiles([]*file.VideoFile{
+			{
+				BaseFile: &file.BaseFile{
+					Size: size,
+				},
+			},
+		}),
+	}
+}
+
+func TestSelectRetentionCandidates(t *testing.T) {
+	now := time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)
+	daysAgo := func(d int) *time.Time {
+		ret := now.AddDate(0, 0, -d)
+		return &ret
+	}
+
+	const (
+		watchedOld = iota + 1
+		watchedRecent
+		unwatchedOld
+		unwatchedNew
+		ineligible
+	)
+
+	scenes := []*models.Scene{
+		makeRetentionTestScene(watchedOld, 100, *daysAgo(100), daysAgo(40)),
+		makeRetentionTestScene(watchedRecent, 100, *daysAgo(100), daysAgo(5)),
+		makeRetentionTestScene(unwatchedOld, 100, *daysAgo(50), nil),
+		makeRetentionTestScene(unwatchedNew, 100, *daysAgo(1), nil),
+		makeRetentionTestScene(ineligible, 100, *daysAgo(200), daysAgo(100)),
+	}
+	eligible := []bool{true, true, true, true, false}
+
+	tests := []struct {
+		name  string
+		rules config.StashRetentionConfig
+		want  []int
+	}{
+		{
+			"no rules",
+			config.StashRetentionConfig{},
+			nil,
+		},
+		{
+			"watched older than",
+			config.StashRetentionConfig{WatchedOlderThanDays: 30},
+			[]int{watchedOld},
+		},
+		{
+			"under max size",
+			config.StashRetentionConfig{MaxSize: 500},
+			nil,
+		},
+		{
+			"over max size",
+			config.StashRetentionConfig{MaxSize: 300},
+			[]int{watchedOld, watchedRecent},
+		},
+		{
+			"over max size unwatched",
+			config.StashRetentionConfig{MaxSize: 200},
+			[]int{watchedOld, watchedRecent, unwatchedOld},
+		},
+		{
+			"watched and max size",
+			config.StashRetentionConfig{WatchedOlderThanDays: 30, MaxSize: 300},
+			[]int{watchedOld, watchedRecent},
+		},
+		{
+			"max size below ineligible",
+			config.StashRetentionConfig{MaxSize: 50},
+			[]int{watchedOld, watchedRecent, unwatchedOld, unwatchedNew},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := selectRetentionCandidates(scenes, eligible, &tt.rules, now)
+
+			var gotIDs []int
+			for _, c := range got {
+				gotIDs = append(gotIDs, c.scene.ID)
+			}
+
+			assert.Equal(t, tt.want, gotIDs)
+		})
+	}
+}
+
+func TestWriteRetentionReport(t *testing.T) {
+	rows := []retentionReportRow{
+		{retentionCandidate{scene: &models.Scene{ID: 1, Path: "/a/b.mp4"}, size: 100, reason: "older than 30 days"}, "deleted"},
+		{retentionCandidate{scene: &models.Scene{ID: 2, Path: "/a/c, d.mp4"}, size: 200, reason: "exceeds size limit"}, "would delete"},
+	}
+
+	var buf bytes.Buffer
+	if err := writeRetentionReport(&buf, rows); err != nil {
+		t.Fatalf("writeRetentionReport() error = %v", err)
+	}
+
+	want := "scene_id,path,reason,size,result\n" +
+		"1,/a/b.mp4,older than 30 days,100,deleted\n" +
+		"2,\"/a/c, d.mp4\",exceeds size limit,200,would delete\n"
+	assert.Equal(t, want, buf.String())
+}
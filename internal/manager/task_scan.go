@@ -58,7 +58,10 @@ func (j *ScanJob) Execute(ctx context.Context, progress *job.Progress) {
 		minModTime = *j.input.Filter.MinModTime
 	}
 
-	j.scanner.Scan(ctx, getScanHandlers(j.input, taskQueue, progress), file.ScanOptions{
+	diffHandler := newScanDiffHandler()
+	handlers := append(getScanHandlers(j.input, taskQueue, progress), diffHandler)
+
+	j.scanner.Scan(ctx, handlers, file.ScanOptions{
 		Paths:             paths,
 		ScanFilters:       []file.PathFilter{newScanFilter(instance.Config, minModTime)},
 		ZipFileExtensions: instance.Config.GetGalleryExtensions(),
@@ -66,6 +69,7 @@ func (j *ScanJob) Execute(ctx context.Context, progress *job.Progress) {
 		HandlerRequiredFilters: []file.Filter{
 			newHandlerRequiredFilter(instance.Config),
 		},
+		Items: progress.Items(),
 	}, progress)
 
 	taskQueue.Close()
@@ -77,6 +81,9 @@ func (j *ScanJob) Execute(ctx context.Context, progress *job.Progress) {
 
 	elapsed := time.Since(start)
 	logger.Info(fmt.Sprintf("Scan finished (%s)", elapsed))
+	instance.reportScanDiff(ctx, diffHandler, paths, elapsed)
+
+	matchWantedScenes(ctx, instance.Repository)
 
 	j.subscriptions.notify()
 }
@@ -107,6 +114,7 @@ type galleryFinder interface {
 type sceneFinder interface {
 	fileCounter
 	FindByPrimaryFileID(ctx context.Context, fileID file.ID) ([]*models.Scene, error)
+	CountByTrailerFileID(ctx context.Context, fileID file.ID) (int, error)
 }
 
 // handlerRequiredFilter returns true if a File's handler needs to be executed despite the file not being updated.
@@ -169,6 +177,12 @@ func (f *handlerRequiredFilter) Accept(ctx context.Context, ff file.File) bool {
 
 	// execute handler if there are no related objects
 	if n == 0 {
+		// trailers attached to a scene don't have related objects
+		if isVideoFile {
+			trailers, err := f.SceneFinder.CountByTrailerFileID(ctx, ff.Base().ID)
+			return err == nil && trailers == 0
+		}
+
 		return true
 	}
 
@@ -230,16 +244,19 @@ type scanFilter struct {
 	generatedPath     string
 	videoExcludeRegex []*regexp.Regexp
 	imageExcludeRegex []*regexp.Regexp
+	stashPatterns     map[string]*stashPathPatterns
 	minModTime        time.Time
 }
 
 func newScanFilter(c *config.Instance, minModTime time.Time) *scanFilter {
+	stashPaths := c.GetStashPaths()
 	return &scanFilter{
 		extensionConfig:   newExtensionConfig(c),
-		stashPaths:        c.GetStashPaths(),
+		stashPaths:        stashPaths,
 		generatedPath:     c.GetGeneratedPath(),
 		videoExcludeRegex: generateRegexps(c.GetExcludes()),
 		imageExcludeRegex: generateRegexps(c.GetImageExcludes()),
+		stashPatterns:     compileStashPathPatterns(stashPaths),
 		minModTime:        minModTime,
 	}
 }
@@ -285,6 +302,8 @@ func (f *scanFilter) Accept(ctx context.Context, path string, info fs.FileInfo)
 		return false
 	}
 
+	patterns := f.stashPatterns[s.Path]
+
 	// shortcut: skip the directory entirely if it matches both exclusion patterns
 	// add a trailing separator so that it correctly matches against patterns like path/.*
 	pathExcludeTest := path + string(filepath.Separator)
@@ -293,10 +312,15 @@ func (f *scanFilter) Accept(ctx context.Context, path string, info fs.FileInfo)
 		return false
 	}
 
-	if isVideoFile && (s.ExcludeVideo || matchFileRegex(path, f.videoExcludeRegex)) {
+	if info.IsDir() && patterns.excludesFolder(path) {
+		logger.Debugf("Skipping directory %s as it matches stash path exclusion patterns", path)
+		return false
+	}
+
+	if isVideoFile && (s.ExcludeVideo || matchFileRegex(path, f.videoExcludeRegex) || patterns.excludesVideo(path)) {
 		logger.Debugf("Skipping %s as it matches video exclusion patterns", path)
 		return false
-	} else if (isImageFile || isZipFile) && (s.ExcludeImage || matchFileRegex(path, f.imageExcludeRegex)) {
+	} else if (isImageFile || isZipFile) && (s.ExcludeImage || matchFileRegex(path, f.imageExcludeRegex) || patterns.excludesImage(path)) {
 		logger.Debugf("Skipping %s as it matches image exclusion patterns", path)
 		return false
 	}
@@ -357,6 +381,10 @@ func getScanHandlers(options ScanMetadataInput, taskQueue *job.TaskQueue, progre
 				},
 				FileNamingAlgorithm: instance.Config.GetVideoFileNamingAlgorithm(),
 				Paths:               instance.Paths,
+				UseFileMetadata:     options.UseFileMetadata,
+				PerformerFinder:     instance.Repository.Performer,
+				StudioFinder:        instance.Repository.Studio,
+				Inbox:               instance.Config.GetInboxEnabled(),
 			},
 		},
 	}
@@ -465,5 +493,32 @@ func (g *sceneGenerators) Generate(ctx context.Context, s *models.Scene, f *file
 		})
 	}
 
+	if config.GetImportChapters() && len(f.Chapters) > 0 {
+		progress.AddTotal(1)
+		g.taskQueue.Add(fmt.Sprintf("Importing chapters for %s", path), func(ctx context.Context) {
+			task := ImportChaptersTask{
+				Scene:      s,
+				Chapters:   f.Chapters,
+				TxnManager: instance.Repository,
+				TagName:    config.GetChapterMarkerTag(),
+			}
+			task.Start(ctx)
+			progress.Increment()
+		})
+	}
+
+	if t.UseFileMetadata && f.Metadata != nil && f.Metadata.CoverStreamIndex >= 0 {
+		progress.AddTotal(1)
+		g.taskQueue.Add(fmt.Sprintf("Importing cover art for %s", path), func(ctx context.Context) {
+			task := ImportEmbeddedCoverTask{
+				Scene:      s,
+				File:       f,
+				TxnManager: instance.Repository,
+			}
+			task.Start(ctx)
+			progress.Increment()
+		})
+	}
+
 	return nil
 }
@@ -0,0 +1,132 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/txn"
+)
+
+type ScanPathInput struct {
+	// File or directory to scan. Must be within a configured stash path
+	Path string `json:"path"`
+	// Maximum duration of the scan in seconds. The scan is stopped if it
+	// takes longer. Nil or zero for no limit
+	Timeout *int `json:"timeout"`
+}
+
+// ScanPath queues a scan of a single file or directory ahead of the other
+// queued jobs. The scan uses the default scan settings, so the same
+// generation and hooks apply as for a library scan, and the exclusions of
+// the stash path containing it. Returns the ID of the queued job.
+func (s *Manager) ScanPath(ctx context.Context, input ScanPathInput) (int, error) {
+	if err := s.validateFFMPEG(); err != nil {
+		return 0, err
+	}
+
+	if input.Path == "" {
+		return 0, errors.New("path is required")
+	}
+
+	var timeout time.Duration
+	if input.Timeout != nil {
+		if *input.Timeout < 0 {
+			return 0, errors.New("timeout must not be negative")
+		}
+		timeout = time.Duration(*input.Timeout) * time.Second
+	}
+
+	p, err := filepath.Abs(input.Path)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := os.Stat(p); err != nil {
+		return 0, err
+	}
+
+	stash := getStashFromDirPath(s.Config.GetStashPaths(), p)
+	if stash == nil {
+		return 0, fmt.Errorf("%s is not in the configured stash paths", p)
+	}
+
+	root, err := s.scanPathRoot(ctx, stash.Path, p)
+	if err != nil {
+		return 0, fmt.Errorf("finding scan root for %s: %w", p, err)
+	}
+
+	if root != p {
+		logger.Infof("Parent folder of %s is not yet scanned. Scanning %s instead", p, root)
+	}
+
+	scanInput := ScanMetadataInput{
+		Paths: []string{root},
+	}
+	if opts := s.Config.GetDefaultScanSettings(); opts != nil {
+		scanInput.ScanMetadataOptions = *opts
+	}
+
+	scanJob := &ScanJob{
+		scanner:       s.Scanner,
+		input:         scanInput,
+		subscriptions: s.scanSubs,
+	}
+
+	var e job.JobExec = scanJob
+	if timeout > 0 {
+		e = job.MakeJobExec(func(ctx context.Context, progress *job.Progress) {
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			scanJob.Execute(ctx, progress)
+
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				logger.Warnf("Scan of %s stopped after exceeding the timeout of %v", root, timeout)
+			}
+		})
+	}
+
+	return s.JobManager.AddPriority(ctx, fmt.Sprintf("Scanning %s...", root), e), nil
+}
+
+// scanPathRoot returns the path to scan so that p is added under its
+// existing parent folder. Scanning a path whose parent folder is not yet in
+// the database would create it as a top-level folder, so the scan is widened
+// to the nearest ancestor whose parent folder exists, up to the stash path.
+func (s *Manager) scanPathRoot(ctx context.Context, stashPath string, p string) (string, error) {
+	stashPath = filepath.Clean(stashPath)
+	ret := p
+
+	if err := txn.WithReadTxn(ctx, s.Repository, func(ctx context.Context) error {
+		for ret != stashPath {
+			parent := filepath.Dir(ret)
+			if parent == ret || !fsutil.IsPathInDir(stashPath, parent) {
+				return nil
+			}
+
+			f, err := s.Repository.Folder.FindByPath(ctx, parent)
+			if err != nil {
+				return err
+			}
+
+			if f != nil {
+				return nil
+			}
+
+			ret = parent
+		}
+
+		return nil
+	}); err != nil {
+		return "", err
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,236 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+// ValidateScheduledTasks returns an error if any scheduled task has no name,
+// an invalid task type or an invalid schedule.
+func ValidateScheduledTasks(tasks []*config.ScheduledTask) error {
+	for _, t := range tasks {
+		if strings.TrimSpace(t.Name) == "" {
+			return errors.New("scheduled task name must not be empty")
+		}
+		if !t.Task.IsValid() {
+			return fmt.Errorf("invalid task %q for scheduled task %s", t.Task, t.Name)
+		}
+		if _, err := parseCronSchedule(t.Schedule); err != nil {
+			return fmt.Errorf("invalid schedule %q for scheduled task %s: %w", t.Schedule, t.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// NextScheduledRun returns the next time after t that the task will be
+// queued. Returns nil if the task is disabled or will never run.
+func NextScheduledRun(task *config.ScheduledTask, t time.Time) *time.Time {
+	if !task.Enabled {
+		return nil
+	}
+
+	return nextCronRun(task.Schedule, t)
+}
+
+// NextScrapeRefreshRun returns the next time after t that the scrape refresh
+// rule will be queued. Returns nil if the rule is disabled or will never run.
+func NextScrapeRefreshRun(rule *config.ScrapeRefreshRule, t time.Time) *time.Time {
+	if !rule.Enabled {
+		return nil
+	}
+
+	return nextCronRun(rule.Schedule, t)
+}
+
+func nextCronRun(expr string, t time.Time) *time.Time {
+	schedule, err := parseCronSchedule(expr)
+	if err != nil {
+		return nil
+	}
+
+	ret := schedule.next(t)
+	if ret.IsZero() {
+		return nil
+	}
+
+	return &ret
+}
+
+// runTaskScheduler queues the enabled scheduled tasks and scrape refresh rules
+// when their schedule matches the current minute. It returns when the context is cancelled.
+func (s *Manager) runTaskScheduler(ctx context.Context) {
+	for {
+		now := time.Now()
+		wait := now.Truncate(time.Minute).Add(time.Minute).Sub(now)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+
+		if s.Config.IsNewSystem() || s.Database.Ready() != nil {
+			continue
+		}
+
+		// round in case the timer fired slightly early
+		minute := time.Now().Round(time.Minute)
+		for _, t := range s.Config.GetScheduledTasks() {
+			if !t.Enabled {
+				continue
+			}
+
+			schedule, err := parseCronSchedule(t.Schedule)
+			if err != nil {
+				logger.Warnf("Invalid schedule %q for scheduled task %s: %v", t.Schedule, t.Name, err)
+				continue
+			}
+
+			if schedule.matches(minute) {
+				logger.Infof("Queueing scheduled task %s", t.Name)
+				if err := s.runScheduledTask(ctx, t.Task); err != nil {
+					logger.Errorf("Error queueing scheduled task %s: %v", t.Name, err)
+				}
+			}
+		}
+
+		for _, r := range s.Config.GetScrapeRefreshRules() {
+			if !r.Enabled {
+				continue
+			}
+
+			schedule, err := parseCronSchedule(r.Schedule)
+			if err != nil {
+				logger.Warnf("Invalid schedule %q for scrape refresh rule %s: %v", r.Schedule, r.Name, err)
+				continue
+			}
+
+			if schedule.matches(minute) {
+				logger.Infof("Queueing scrape refresh rule %s", r.Name)
+				if _, err := s.ScrapeRefresh(ctx, *r); err != nil {
+					logger.Errorf("Error queueing scrape refresh rule %s: %v", r.Name, err)
+				}
+			}
+		}
+	}
+}
+
+// runScheduledTask queues the task using the default task settings.
+func (s *Manager) runScheduledTask(ctx context.Context, task config.ScheduledTaskType) error {
+	switch task {
+	case config.ScheduledTaskTypeScan:
+		input := ScanMetadataInput{}
+		if opts := s.Config.GetDefaultScanSettings(); opts != nil {
+			input.ScanMetadataOptions = *opts
+		}
+		_, err := s.Scan(ctx, input)
+		return err
+	case config.ScheduledTaskTypeAutoTag:
+		input := AutoTagMetadataInput{
+			Performers: []string{"*"},
+			Studios:    []string{"*"},
+			Tags:       []string{"*"},
+		}
+		if opts := s.Config.GetDefaultAutoTagSettings(); opts != nil {
+			input.Performers = opts.Performers
+			input.Studios = opts.Studios
+			input.Tags = opts.Tags
+		}
+		s.AutoTag(ctx, input)
+	case config.ScheduledTaskTypeGenerate:
+		_, err := s.Generate(ctx, s.defaultGenerateInput())
+		return err
+	case config.ScheduledTaskTypeClean:
+		s.Clean(ctx, CleanMetadataInput{})
+	case config.ScheduledTaskTypeBackup:
+		s.JobManager.Add(ctx, "Backing up database...", job.MakeJobExec(func(ctx context.Context, progress *job.Progress) {
+			if _, err := s.BackupDatabase(); err != nil {
+				logger.Errorf("Error backing up database: %v", err)
+			}
+		}))
+	case config.ScheduledTaskTypeStashBoxSync:
+		_, err := s.StashBoxSync(ctx, StashBoxSyncInput{})
+		return err
+	case config.ScheduledTaskTypeRetention:
+		s.Retention(ctx, RetentionMetadataInput{})
+	case config.ScheduledTaskTypePurgeTrash:
+		s.JobManager.Add(ctx, "Purging expired trash...", job.MakeJobExec(func(ctx context.Context, progress *job.Progress) {
+			if err := s.pruneTrash(ctx); err != nil {
+				logger.Errorf("Error purging expired trashed scenes: %v", err)
+			}
+		}))
+	default:
+		return fmt.Errorf("unsupported task %q", task)
+	}
+
+	return nil
+}
+
+// defaultGenerateInput returns the generate input for the default generate
+// settings. Sprites, previews and phashes are generated if no defaults are
+// set.
+func (s *Manager) defaultGenerateInput() GenerateMetadataInput {
+	opts := s.Config.GetDefaultGenerateSettings()
+	if opts == nil {
+		enabled := true
+		return GenerateMetadataInput{
+			Sprites:  &enabled,
+			Previews: &enabled,
+			Phashes:  &enabled,
+		}
+	}
+
+	ret := GenerateMetadataInput{
+		Sprites:                   opts.Sprites,
+		HoverStrips:               opts.HoverStrips,
+		Previews:                  opts.Previews,
+		ImagePreviews:             opts.ImagePreviews,
+		Markers:                   opts.Markers,
+		MarkerImagePreviews:       opts.MarkerImagePreviews,
+		MarkerScreenshots:         opts.MarkerScreenshots,
+		Transcodes:                opts.Transcodes,
+		Phashes:                   opts.Phashes,
+		InteractiveHeatmapsSpeeds: opts.InteractiveHeatmapsSpeeds,
+		InteractiveMarkers:        opts.InteractiveMarkers,
+		ChapterThumbnails:         opts.ChapterThumbnails,
+	}
+	if p := opts.PreviewOptions; p != nil {
+		ret.PreviewOptions = &GeneratePreviewOptionsInput{
+			PreviewSegments:        p.PreviewSegments,
+			PreviewSegmentDuration: p.PreviewSegmentDuration,
+			PreviewExcludeStart:    p.PreviewExcludeStart,
+			PreviewExcludeEnd:      p.PreviewExcludeEnd,
+			PreviewPreset:          p.PreviewPreset,
+		}
+	}
+
+	return ret
+}
+
+// BackupDatabase backs up the database to the backup directory, returning
+// the path of the backup.
+func (s *Manager) BackupDatabase() (string, error) {
+	backupDirectoryPath := s.Config.GetBackupDirectoryPathOrDefault()
+	if backupDirectoryPath != "" {
+		if err := fsutil.EnsureDir(backupDirectoryPath); err != nil {
+			return "", fmt.Errorf("could not create backup directory %v: %w", backupDirectoryPath, err)
+		}
+	}
+
+	backupPath := s.Database.DatabaseBackupPath(backupDirectoryPath)
+	if err := s.Database.Backup(backupPath); err != nil {
+		return "", err
+	}
+
+	logger.Infof("Successfully backed up database to: %s", backupPath)
+	return backupPath, nil
+}
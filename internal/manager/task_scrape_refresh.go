@@ -0,0 +1,395 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scraper"
+	"github.com/stashapp/stash/pkg/scraper/stashbox"
+	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
+	"github.com/stashapp/stash/pkg/txn"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+// ValidateScrapeRefreshRules returns an error if any scrape refresh rule has
+// no name or a duplicate name, an invalid schedule, entity type or field, or
+// a stash-box endpoint which is not configured.
+func ValidateScrapeRefreshRules(rules []*config.ScrapeRefreshRule, stashBoxes []*models.StashBox) error {
+	names := make(map[string]bool)
+	for _, r := range rules {
+		if strings.TrimSpace(r.Name) == "" {
+			return errors.New("scrape refresh rule name must not be empty")
+		}
+		if names[r.Name] {
+			return fmt.Errorf("duplicate scrape refresh rule %s", r.Name)
+		}
+		names[r.Name] = true
+
+		if _, err := parseCronSchedule(r.Schedule); err != nil {
+			return fmt.Errorf("invalid schedule %q for scrape refresh rule %s: %w", r.Schedule, r.Name, err)
+		}
+		if !r.EntityType.IsValid() {
+			return fmt.Errorf("invalid entity type %q for scrape refresh rule %s", r.EntityType, r.Name)
+		}
+		if _, err := stringslice.StringSliceToIntSlice(r.IDs); err != nil {
+			return fmt.Errorf("invalid ids for scrape refresh rule %s: %w", r.Name, err)
+		}
+
+		if r.StashBoxEndpoint == "" {
+			if r.EntityType == config.ScrapeRefreshEntityTypeStudio {
+				return fmt.Errorf("scrape refresh rule %s: studios can only be refreshed from a stash-box", r.Name)
+			}
+		} else if findStashBox(stashBoxes, r.StashBoxEndpoint) == nil {
+			return fmt.Errorf("scrape refresh rule %s: stash-box with endpoint %s is not configured", r.Name, r.StashBoxEndpoint)
+		}
+
+		fields := ScrapeRefreshPerformerFields()
+		if r.EntityType == config.ScrapeRefreshEntityTypeStudio {
+			fields = ScrapeRefreshStudioFields()
+		}
+		for _, o := range r.FieldOptions {
+			if !stringslice.StrInclude(fields, o.Field) {
+				return fmt.Errorf("scrape refresh rule %s: invalid field %s", r.Name, o.Field)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ScrapeRefresh queues a job which re-scrapes the performers or studios of the
+// rule, storing the changes as a job artifact.
+func (s *Manager) ScrapeRefresh(ctx context.Context, rule config.ScrapeRefreshRule) (int, error) {
+	var box *models.StashBox
+	if rule.StashBoxEndpoint != "" {
+		box = findStashBox(s.Config.GetStashBoxes(), rule.StashBoxEndpoint)
+		if box == nil {
+			return 0, fmt.Errorf("%w: stash-box with endpoint %s", models.ErrNotFound, rule.StashBoxEndpoint)
+		}
+	} else if rule.EntityType == config.ScrapeRefreshEntityTypeStudio {
+		return 0, errors.New("studios can only be refreshed from a stash-box")
+	}
+
+	ids, err := stringslice.StringSliceToIntSlice(rule.IDs)
+	if err != nil {
+		return 0, fmt.Errorf("invalid ids: %w", err)
+	}
+
+	j := &scrapeRefreshJob{
+		repository:   s.Repository,
+		scraperCache: s.ScraperCache,
+		rule:         rule,
+		ids:          ids,
+		box:          box,
+		strategies:   newRefreshFieldStrategies(rule.FieldOptions),
+	}
+
+	return s.JobManager.Add(ctx, fmt.Sprintf("Refreshing %s...", rule.Name), j), nil
+}
+
+type scrapeRefreshJob struct {
+	repository   Repository
+	scraperCache *scraper.Cache
+	rule         config.ScrapeRefreshRule
+	ids          []int
+	// box is nil if refreshing from URLs
+	box        *models.StashBox
+	client     *stashbox.Client
+	strategies refreshFieldStrategies
+
+	reports []*ScrapeRefreshReport
+}
+
+func (j *scrapeRefreshJob) Execute(ctx context.Context, progress *job.Progress) {
+	if j.box != nil {
+		j.client = newStashBoxClient(*j.box, j.repository, 0)
+	}
+
+	var err error
+	if j.rule.EntityType == config.ScrapeRefreshEntityTypeStudio {
+		err = j.refreshStudios(ctx, progress)
+	} else {
+		err = j.refreshPerformers(ctx, progress)
+	}
+
+	if err != nil && !job.IsCancelled(ctx) {
+		logger.Errorf("Error refreshing %s: %v", j.rule.Name, err)
+	}
+
+	j.storeReport(ctx)
+}
+
+func (j *scrapeRefreshJob) refreshPerformers(ctx context.Context, progress *job.Progress) error {
+	var performers []*models.Performer
+	if err := txn.WithReadTxn(ctx, j.repository, func(ctx context.Context) error {
+		var err error
+		if len(j.ids) > 0 {
+			performers, err = j.repository.Performer.FindMany(ctx, j.ids)
+		} else {
+			performers, err = j.repository.Performer.All(ctx)
+		}
+		return err
+	}); err != nil {
+		return fmt.Errorf("finding performers: %w", err)
+	}
+
+	progress.SetTotal(len(performers))
+	for _, p := range performers {
+		if job.IsCancelled(ctx) {
+			return nil
+		}
+
+		progress.ExecuteTask(fmt.Sprintf("Refreshing performer %s", p.Name), func() {
+			if err := j.refreshPerformer(ctx, p); err != nil {
+				logger.Errorf("Error refreshing performer %s: %v", p.Name, err)
+			}
+		})
+		progress.Increment()
+	}
+
+	return nil
+}
+
+func (j *scrapeRefreshJob) refreshPerformer(ctx context.Context, p *models.Performer) error {
+	var hasImage bool
+	if err := txn.WithReadTxn(ctx, j.repository, func(ctx context.Context) error {
+		qb := j.repository.Performer
+		if err := p.LoadAliases(ctx, qb); err != nil {
+			return err
+		}
+		if err := p.LoadStashIDs(ctx, qb); err != nil {
+			return err
+		}
+
+		image, err := qb.GetImage(ctx, p.ID)
+		hasImage = len(image) > 0
+		return err
+	}); err != nil {
+		return err
+	}
+
+	scraped, source, err := j.scrapePerformer(ctx, p)
+	if err != nil {
+		return err
+	}
+	if scraped == nil {
+		return nil
+	}
+
+	partial, image, changes := performerRefresh(p, hasImage, scraped, j.strategies)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	var imageData []byte
+	if image != nil {
+		imageData, err = utils.ProcessImageInput(ctx, *image)
+		if err != nil {
+			return fmt.Errorf("reading image: %w", err)
+		}
+	}
+
+	if err := txn.WithTxn(ctx, j.repository, func(ctx context.Context) error {
+		qb := j.repository.Performer
+		if _, err := qb.UpdatePartial(ctx, p.ID, partial); err != nil {
+			return err
+		}
+
+		if imageData != nil {
+			return qb.UpdateImage(ctx, p.ID, imageData)
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	logger.Infof("Refreshed %d fields of performer %s from %s", len(changes), p.Name, source)
+	j.reports = append(j.reports, &ScrapeRefreshReport{
+		EntityType: config.ScrapeRefreshEntityTypePerformer.String(),
+		ID:         p.ID,
+		Name:       p.Name,
+		Source:     source,
+		Fields:     changes,
+	})
+
+	return nil
+}
+
+// scrapePerformer scrapes the performer from the stash-box or its URL.
+// Returns nil if the performer has no stash ID for the stash-box or no URL.
+func (j *scrapeRefreshJob) scrapePerformer(ctx context.Context, p *models.Performer) (*models.ScrapedPerformer, string, error) {
+	if j.box == nil {
+		if p.URL == "" {
+			return nil, "", nil
+		}
+
+		content, err := j.scraperCache.ScrapeURL(ctx, p.URL, scraper.ScrapeContentTypePerformer)
+		if err != nil {
+			return nil, "", fmt.Errorf("scraping %s: %w", p.URL, err)
+		}
+
+		switch scraped := content.(type) {
+		case *models.ScrapedPerformer:
+			return scraped, p.URL, nil
+		case models.ScrapedPerformer:
+			return &scraped, p.URL, nil
+		}
+
+		return nil, "", nil
+	}
+
+	stashID := findStashID(p.StashIDs.List(), j.box.Endpoint)
+	if stashID == "" {
+		return nil, "", nil
+	}
+
+	scraped, err := j.client.FindStashBoxPerformerByID(ctx, stashID)
+	if err != nil {
+		return nil, "", fmt.Errorf("querying %s: %w", j.box.Endpoint, err)
+	}
+
+	return scraped, j.box.Endpoint, nil
+}
+
+func (j *scrapeRefreshJob) refreshStudios(ctx context.Context, progress *job.Progress) error {
+	var studios []*models.Studio
+	if err := txn.WithReadTxn(ctx, j.repository, func(ctx context.Context) error {
+		var err error
+		if len(j.ids) > 0 {
+			studios, err = j.repository.Studio.FindMany(ctx, j.ids)
+		} else {
+			studios, err = j.repository.Studio.All(ctx)
+		}
+		return err
+	}); err != nil {
+		return fmt.Errorf("finding studios: %w", err)
+	}
+
+	progress.SetTotal(len(studios))
+	for _, s := range studios {
+		if job.IsCancelled(ctx) {
+			return nil
+		}
+
+		name := s.Name.String
+		progress.ExecuteTask(fmt.Sprintf("Refreshing studio %s", name), func() {
+			if err := j.refreshStudio(ctx, s); err != nil {
+				logger.Errorf("Error refreshing studio %s: %v", name, err)
+			}
+		})
+		progress.Increment()
+	}
+
+	return nil
+}
+
+func (j *scrapeRefreshJob) refreshStudio(ctx context.Context, s *models.Studio) error {
+	var stashIDs []models.StashID
+	var hasImage bool
+	if err := txn.WithReadTxn(ctx, j.repository, func(ctx context.Context) error {
+		qb := j.repository.Studio
+		var err error
+		stashIDs, err = qb.GetStashIDs(ctx, s.ID)
+		if err != nil {
+			return err
+		}
+
+		hasImage, err = qb.HasImage(ctx, s.ID)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	stashID := findStashID(stashIDs, j.box.Endpoint)
+	if stashID == "" {
+		return nil
+	}
+
+	scraped, err := j.client.FindStashBoxStudioByID(ctx, stashID)
+	if err != nil {
+		return fmt.Errorf("querying %s: %w", j.box.Endpoint, err)
+	}
+	if scraped == nil {
+		return nil
+	}
+
+	partial, image, changes := studioRefresh(s, hasImage, scraped, j.strategies)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	var imageData []byte
+	if image != nil {
+		imageData, err = utils.ProcessImageInput(ctx, *image)
+		if err != nil {
+			return fmt.Errorf("reading image: %w", err)
+		}
+	}
+
+	partial.UpdatedAt = &models.SQLiteTimestamp{Timestamp: time.Now()}
+	if err := txn.WithTxn(ctx, j.repository, func(ctx context.Context) error {
+		qb := j.repository.Studio
+		if _, err := qb.Update(ctx, *partial); err != nil {
+			return err
+		}
+
+		if imageData != nil {
+			return qb.UpdateImage(ctx, s.ID, imageData)
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	logger.Infof("Refreshed %d fields of studio %s from %s", len(changes), s.Name.String, j.box.Endpoint)
+	j.reports = append(j.reports, &ScrapeRefreshReport{
+		EntityType: config.ScrapeRefreshEntityTypeStudio.String(),
+		ID:         s.ID,
+		Name:       s.Name.String,
+		Source:     j.box.Endpoint,
+		Fields:     changes,
+	})
+
+	return nil
+}
+
+func findStashID(stashIDs []models.StashID, endpoint string) string {
+	for _, id := range stashIDs {
+		if id.Endpoint == endpoint {
+			return id.StashID
+		}
+	}
+
+	return ""
+}
+
+// storeReport stores the changes made to the refreshed performers or studios
+// as a job artifact.
+func (j *scrapeRefreshJob) storeReport(ctx context.Context) {
+	logger.Infof("%s changed %d %s", j.rule.Name, len(j.reports), strings.ToLower(j.rule.EntityType.String())+"s")
+
+	if len(j.reports) == 0 {
+		return
+	}
+
+	name := "scrape-refresh-" + strconv.Itoa(int(time.Now().Unix())) + ".json"
+	if _, err := instance.AddJobArtifact(ctx, name, "application/json", func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(j.reports)
+	}); err != nil {
+		logger.Errorf("Error storing scrape refresh changes: %v", err)
+	}
+}
@@ -0,0 +1,407 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/stashapp/stash/internal/identify"
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene"
+	"github.com/stashapp/stash/pkg/scraper"
+	"github.com/stashapp/stash/pkg/scraper/stashbox"
+	"github.com/stashapp/stash/pkg/txn"
+)
+
+const (
+	// stashBoxSyncBatchSize is the number of scenes submitted or queried in a
+	// single stash-box request.
+	stashBoxSyncBatchSize = 40
+
+	// stashBoxMatchRecheckInterval is how long until scenes without a match
+	// are checked again.
+	stashBoxMatchRecheckInterval = 7 * 24 * time.Hour
+)
+
+type StashBoxSyncInput struct {
+	// Endpoints of the stash-boxes to sync with. Defaults to all configured
+	// stash-boxes.
+	Endpoints []string `json:"endpoints"`
+	// Submit the fingerprints of scenes with a stash ID. Defaults to true.
+	SubmitFingerprints *bool `json:"submit_fingerprints"`
+	// Query the fingerprints of scenes without a stash ID, queuing the
+	// matches as suggestions. Defaults to true.
+	FindMatches *bool `json:"find_matches"`
+}
+
+// StashBoxSync queues a job which submits fingerprints to and finds matches
+// from the stash-boxes. Matches are not applied, but stored as suggestions to
+// be accepted or rejected.
+func (s *Manager) StashBoxSync(ctx context.Context, input StashBoxSyncInput) (int, error) {
+	boxes, err := s.syncStashBoxes(input.Endpoints)
+	if err != nil {
+		return 0, err
+	}
+
+	j := &stashBoxSyncJob{
+		repository: s.Repository,
+		boxes:      boxes,
+		submit:     input.SubmitFingerprints == nil || *input.SubmitFingerprints,
+		match:      input.FindMatches == nil || *input.FindMatches,
+	}
+
+	return s.JobManager.Add(ctx, "Syncing with stash-box...", j), nil
+}
+
+func (s *Manager) syncStashBoxes(endpoints []string) ([]*models.StashBox, error) {
+	boxes := s.Config.GetStashBoxes()
+	if len(endpoints) == 0 {
+		return boxes, nil
+	}
+
+	var ret []*models.StashBox
+	for _, e := range endpoints {
+		box := findStashBox(boxes, e)
+		if box == nil {
+			return nil, fmt.Errorf("%w: stash-box with endpoint %s", models.ErrNotFound, e)
+		}
+		ret = append(ret, box)
+	}
+
+	return ret, nil
+}
+
+func findStashBox(boxes []*models.StashBox, endpoint string) *models.StashBox {
+	for _, b := range boxes {
+		if b.Endpoint == endpoint {
+			return b
+		}
+	}
+
+	return nil
+}
+
+func newStashBoxClient(box models.StashBox, repo Repository, fingerprintCacheDays int) *stashbox.Client {
+	client := stashbox.NewClient(box, repo, stashbox.Repository{
+		Scene:     repo.Scene,
+		Performer: repo.Performer,
+		Tag:       repo.Tag,
+		Studio:    repo.Studio,
+	})
+	if fingerprintCacheDays > 0 {
+		client.SetFingerprintCache(repo.StashBoxFingerprintCache, time.Duration(fingerprintCacheDays)*24*time.Hour)
+	}
+
+	return client
+}
+
+type stashBoxSyncJob struct {
+	repository Repository
+	boxes      []*models.StashBox
+	submit     bool
+	match      bool
+}
+
+type stashBoxSyncScenes struct {
+	box      *models.StashBox
+	client   *stashbox.Client
+	toSubmit []int
+	toMatch  []int
+}
+
+func (j *stashBoxSyncJob) Execute(ctx context.Context, progress *job.Progress) {
+	var syncs []stashBoxSyncScenes
+	total := 0
+	for _, box := range j.boxes {
+		sync, err := j.findScenes(ctx, box)
+		if err != nil {
+			logger.Errorf("Error finding scenes to sync with %s: %v", box.Endpoint, err)
+			continue
+		}
+
+		syncs = append(syncs, sync)
+		total += len(sync.toSubmit) + len(sync.toMatch)
+	}
+
+	progress.SetTotal(total)
+
+	submitted := 0
+	suggested := 0
+	for _, sync := range syncs {
+		submitted += j.submitFingerprints(ctx, progress, sync)
+		suggested += j.findMatches(ctx, progress, sync)
+	}
+
+	if job.IsCancelled(ctx) {
+		logger.Info("Stopping due to user request")
+		return
+	}
+
+	logger.Infof("Submitted fingerprints of %d scenes to stash-box, found %d matches", submitted, suggested)
+}
+
+func (j *stashBoxSyncJob) findScenes(ctx context.Context, box *models.StashBox) (stashBoxSyncScenes, error) {
+	ret := stashBoxSyncScenes{
+		box:    box,
+		client: newStashBoxClient(*box, j.repository, instance.Config.GetStashBoxFingerprintCacheDays()),
+	}
+
+	checkedBefore := time.Now().Add(-stashBoxMatchRecheckInterval)
+	err := txn.WithReadTxn(ctx, j.repository, func(ctx context.Context) error {
+		qb := j.repository.StashBoxSync
+
+		var err error
+		if j.submit {
+			ret.toSubmit, err = qb.FindScenesToSubmit(ctx, box.Endpoint)
+			if err != nil {
+				return err
+			}
+		}
+
+		if j.match {
+			ret.toMatch, err = qb.FindScenesToMatch(ctx, box.Endpoint, checkedBefore)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return ret, err
+}
+
+func (j *stashBoxSyncJob) submitFingerprints(ctx context.Context, progress *job.Progress, sync stashBoxSyncScenes) int {
+	endpoint := sync.box.Endpoint
+	ret := 0
+
+	for _, batch := range batchIDs(sync.toSubmit, stashBoxSyncBatchSize) {
+		if job.IsCancelled(ctx) {
+			break
+		}
+
+		progress.ExecuteTask(fmt.Sprintf("Submitting fingerprints to %s", endpoint), func() {
+			ids := make([]string, len(batch))
+			for i, id := range batch {
+				ids[i] = strconv.Itoa(id)
+			}
+
+			if _, err := sync.client.SubmitStashBoxFingerprints(ctx, ids, endpoint); err != nil {
+				logger.Errorf("Error submitting fingerprints to %s: %v", endpoint, err)
+				return
+			}
+
+			if err := txn.WithTxn(ctx, j.repository, func(ctx context.Context) error {
+				return j.repository.StashBoxSync.SetFingerprintsSubmitted(ctx, batch, endpoint, time.Now())
+			}); err != nil {
+				logger.Errorf("Error recording submitted fingerprints: %v", err)
+				return
+			}
+
+			ret += len(batch)
+		})
+
+		progress.AddProcessed(len(batch))
+	}
+
+	return ret
+}
+
+func (j *stashBoxSyncJob) findMatches(ctx context.Context, progress *job.Progress, sync stashBoxSyncScenes) int {
+	endpoint := sync.box.Endpoint
+	ret := 0
+
+	for _, batch := range batchIDs(sync.toMatch, stashBoxSyncBatchSize) {
+		if job.IsCancelled(ctx) {
+			break
+		}
+
+		progress.ExecuteTask(fmt.Sprintf("Finding matches from %s", endpoint), func() {
+			results, err := sync.client.FindStashBoxScenesByFingerprints(ctx, batch)
+			if err != nil {
+				logger.Errorf("Error querying %s for matches: %v", endpoint, err)
+				return
+			}
+
+			if err := txn.WithTxn(ctx, j.repository, func(ctx context.Context) error {
+				qb := j.repository.StashBoxSync
+				for i, matches := range results {
+					for _, m := range matches {
+						if m.RemoteSiteID == nil {
+							continue
+						}
+
+						data, err := json.Marshal(m)
+						if err != nil {
+							return fmt.Errorf("encoding match: %w", err)
+						}
+
+						if err := qb.CreateSuggestion(ctx, models.StashBoxMatchSuggestion{
+							SceneID:   batch[i],
+							Endpoint:  endpoint,
+							StashID:   *m.RemoteSiteID,
+							Data:      data,
+							CreatedAt: time.Now(),
+						}); err != nil {
+							return err
+						}
+						ret++
+					}
+				}
+
+				return qb.SetMatchesChecked(ctx, batch, endpoint, time.Now())
+			}); err != nil {
+				logger.Errorf("Error storing matches from %s: %v", endpoint, err)
+			}
+		})
+
+		progress.AddProcessed(len(batch))
+	}
+
+	return ret
+}
+
+func batchIDs(ids []int, size int) [][]int {
+	var ret [][]int
+	for len(ids) > 0 {
+		n := size
+		if len(ids) < n {
+			n = len(ids)
+		}
+		ret = append(ret, ids[:n])
+		ids = ids[n:]
+	}
+
+	return ret
+}
+
+// storedSceneSource is an identify source returning a previously scraped
+// scene.
+type storedSceneSource struct {
+	scene *scraper.ScrapedScene
+}
+
+func (s storedSceneSource) ScrapeScene(ctx context.Context, sceneID int) (*scraper.ScrapedScene, error) {
+	return s.scene, nil
+}
+
+// AcceptStashBoxMatchSuggestion applies the suggested stash-box scene to the
+// scene using the default identify settings, and removes the other pending
+// suggestions of the scene for the stash-box.
+func (s *Manager) AcceptStashBoxMatchSuggestion(ctx context.Context, id int) error {
+	var suggestion *models.StashBoxMatchSuggestion
+	var sc *models.Scene
+	if err := txn.WithReadTxn(ctx, s.Repository, func(ctx context.Context) error {
+		var err error
+		suggestion, err = s.Repository.StashBoxSync.FindSuggestion(ctx, id)
+		if err != nil {
+			return err
+		}
+		if suggestion == nil {
+			return fmt.Errorf("%w: stash-box match suggestion %d", models.ErrNotFound, id)
+		}
+
+		sc, err = s.Repository.Scene.Find(ctx, suggestion.SceneID)
+		if err != nil {
+			return err
+		}
+		if sc == nil {
+			return fmt.Errorf("%w: scene %d", models.ErrNotFound, suggestion.SceneID)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	var match scraper.ScrapedScene
+	if err := json.Unmarshal(suggestion.Data, &match); err != nil {
+		return fmt.Errorf("decoding stash-box match suggestion %d: %w", id, err)
+	}
+
+	var defaultOptions, sourceOptions *identify.MetadataOptions
+	if defaults := s.Config.GetDefaultIdentifySettings(); defaults != nil {
+		defaultOptions = defaults.Options
+		sourceOptions = stashBoxSourceOptions(defaults.Sources, s.Config.GetStashBoxes(), suggestion.Endpoint)
+	}
+
+	task := identify.SceneIdentifier{
+		SceneReaderUpdater: s.Repository.Scene,
+		StudioCreator:      s.Repository.Studio,
+		PerformerCreator:   s.Repository.Performer,
+		TagCreator:         s.Repository.Tag,
+
+		DefaultOptions: defaultOptions,
+		Sources: []identify.ScraperSource{{
+			Name:       "stash-box: " + suggestion.Endpoint,
+			Scraper:    storedSceneSource{scene: &match},
+			RemoteSite: suggestion.Endpoint,
+			Options:    sourceOptions,
+		}},
+		ScreenshotSetter: &scene.PathsCoverSetter{
+			Paths:               s.Paths,
+			FileNamingAlgorithm: s.Config.GetVideoFileNamingAlgorithm(),
+		},
+		SceneUpdatePostHookExecutor: s.PluginCache,
+	}
+
+	return txn.WithDatabase(ctx, s.Repository, func(ctx context.Context) error {
+		if _, err := task.Identify(ctx, s.Repository, sc); err != nil {
+			return err
+		}
+
+		return txn.WithTxn(ctx, s.Repository, func(ctx context.Context) error {
+			// set the stash ID even if the identify settings ignore stash IDs,
+			// so that the scene is not matched again
+			if err := addSceneStashID(ctx, s.Repository.Scene, sc.ID, models.StashID{
+				Endpoint: suggestion.Endpoint,
+				StashID:  suggestion.StashID,
+			}); err != nil {
+				return err
+			}
+
+			return s.Repository.StashBoxSync.DestroySuggestions(ctx, sc.ID, suggestion.Endpoint)
+		})
+	})
+}
+
+// stashBoxSourceOptions returns the options of the default identify source
+// for the stash-box endpoint, if any.
+func stashBoxSourceOptions(sources []*identify.Source, boxes []*models.StashBox, endpoint string) *identify.MetadataOptions {
+	for _, src := range sources {
+		if src.Source == nil || src.Source.ScraperID != nil {
+			continue
+		}
+
+		box, err := resolveStashBox(boxes, *src.Source)
+		if err == nil && box != nil && box.Endpoint == endpoint {
+			return src.Options
+		}
+	}
+
+	return nil
+}
+
+func addSceneStashID(ctx context.Context, qb SceneReaderWriter, sceneID int, stashID models.StashID) error {
+	ids, err := qb.GetStashIDs(ctx, sceneID)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if id.Endpoint == stashID.Endpoint {
+			return nil
+		}
+	}
+
+	partial := models.NewScenePartial()
+	partial.StashIDs = &models.UpdateStashIDs{
+		StashIDs: []models.StashID{stashID},
+		Mode:     models.RelationshipUpdateModeAdd,
+	}
+	_, err = qb.UpdatePartial(ctx, sceneID, partial)
+	return err
+}
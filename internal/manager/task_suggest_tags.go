@@ -0,0 +1,84 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/tag"
+	"github.com/stashapp/stash/pkg/txn"
+)
+
+type SuggestTagsInput struct {
+	// Minimum number of scenes with a performer, studio or tag for its
+	// correlations to be used
+	MinSupport *int `json:"min_support"`
+	// Minimum proportion of correlated scenes that must have a tag for it to
+	// be suggested, between 0 and 1
+	MinConfidence *float64 `json:"min_confidence"`
+	// Maximum number of suggestions per scene
+	MaxPerScene *int `json:"max_per_scene"`
+}
+
+func (i SuggestTagsInput) options() (tag.SuggestOptions, error) {
+	ret := tag.DefaultSuggestOptions()
+
+	if i.MinSupport != nil {
+		if *i.MinSupport < 1 {
+			return ret, fmt.Errorf("min_support must be at least 1")
+		}
+		ret.MinSupport = *i.MinSupport
+	}
+
+	if i.MinConfidence != nil {
+		if *i.MinConfidence <= 0 || *i.MinConfidence > 1 {
+			return ret, fmt.Errorf("min_confidence must be greater than 0 and at most 1")
+		}
+		ret.MinConfidence = *i.MinConfidence
+	}
+
+	if i.MaxPerScene != nil {
+		if *i.MaxPerScene < 0 {
+			return ret, fmt.Errorf("max_per_scene must not be negative")
+		}
+		ret.MaxPerScene = *i.MaxPerScene
+	}
+
+	return ret, nil
+}
+
+// SuggestTags queues a job to replace the pending tag suggestions with
+// suggestions mined from the correlations between the performers, studios
+// and tags of the library.
+func (s *Manager) SuggestTags(ctx context.Context, input SuggestTagsInput) (int, error) {
+	options, err := input.options()
+	if err != nil {
+		return 0, err
+	}
+
+	j := job.MakeJobExec(func(ctx context.Context, progress *job.Progress) {
+		suggestTags(ctx, s.Repository, options)
+	})
+
+	return s.JobManager.Add(ctx, "Suggesting tags...", j), nil
+}
+
+func suggestTags(ctx context.Context, r Repository, options tag.SuggestOptions) {
+	if err := txn.WithTxn(ctx, r, func(ctx context.Context) error {
+		scenes, err := r.TagSuggestion.FindSceneRelationshipIDs(ctx)
+		if err != nil {
+			return err
+		}
+
+		suggestions := tag.Suggest(scenes, options)
+		if err := r.TagSuggestion.Replace(ctx, suggestions); err != nil {
+			return err
+		}
+
+		logger.Infof("Suggested %d tags for %d scenes", len(suggestions), len(scenes))
+		return nil
+	}); err != nil {
+		logger.Errorf("Error suggesting tags: %v", err)
+	}
+}
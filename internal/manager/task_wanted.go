@@ -0,0 +1,149 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/stashapp/stash/pkg/job"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/plugin"
+	"github.com/stashapp/stash/pkg/scraper"
+	"github.com/stashapp/stash/pkg/txn"
+	"github.com/stashapp/stash/pkg/utils"
+	"github.com/stashapp/stash/pkg/wanted"
+)
+
+// MatchWanted queues a job to match the wanted list against the library.
+func (s *Manager) MatchWanted(ctx context.Context) int {
+	j := job.MakeJobExec(func(ctx context.Context, progress *job.Progress) {
+		matchWantedScenes(ctx, s.Repository)
+	})
+
+	return s.JobManager.Add(ctx, "Matching wanted scenes...", j)
+}
+
+// matchWantedScenes marks wanted scenes that are now in the library as
+// fulfilled, logging and triggering plugin hooks for each one.
+func matchWantedScenes(ctx context.Context, r Repository) {
+	m := wanted.Matcher{
+		SceneFinder: r.Scene,
+		Wanted:      r.WantedScene,
+	}
+
+	if err := txn.WithTxn(ctx, r, func(ctx context.Context) error {
+		fulfilled, err := m.Match(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, f := range fulfilled {
+			logger.Infof("Wanted scene %q fulfilled by %q (matched by %s)", f.Wanted.DisplayName(), f.Scene.DisplayName(), f.Reason)
+			instance.PluginCache.RegisterPostHooks(ctx, f.Wanted.ID, plugin.WantedSceneFulfilledPost, f.Wanted, nil)
+		}
+
+		return nil
+	}); err != nil {
+		logger.Errorf("Error matching wanted scenes: %v", err)
+	}
+}
+
+type sceneURLScraper interface {
+	ScrapeURL(ctx context.Context, url string, ty scraper.ScrapeContentType) (scraper.ScrapedContent, error)
+}
+
+// ScrapeWantedScenes queues a job that scrapes the URLs of pending wanted
+// scenes, then matches the wanted list against the library.
+func (s *Manager) ScrapeWantedScenes(ctx context.Context) int {
+	j := job.MakeJobExec(func(ctx context.Context, progress *job.Progress) {
+		scrapeWantedScenes(ctx, s.Repository, s.ScraperCache, progress)
+		if job.IsCancelled(ctx) {
+			logger.Info("Stopping due to user request")
+			return
+		}
+
+		matchWantedScenes(ctx, s.Repository)
+	})
+
+	return s.JobManager.AddClass(ctx, "Scraping wanted scene URLs...", j, job.ClassMetadata)
+}
+
+func scrapeWantedScenes(ctx context.Context, r Repository, sc sceneURLScraper, progress *job.Progress) {
+	var pending []*models.WantedScene
+	if err := txn.WithReadTxn(ctx, r, func(ctx context.Context) error {
+		var err error
+		pending, err = r.WantedScene.FindByScrapeStatus(ctx, models.WantedSceneScrapeStatusPending)
+		return err
+	}); err != nil {
+		logger.Errorf("Error finding pending wanted scenes: %v", err)
+		return
+	}
+
+	progress.SetTotal(len(pending))
+
+	for _, w := range pending {
+		if job.IsCancelled(ctx) {
+			return
+		}
+
+		progress.ExecuteTask("Scraping "+w.DisplayName(), func() {
+			scrapeWantedScene(ctx, r, sc, w)
+		})
+		progress.Increment()
+	}
+}
+
+// scrapeWantedScene scrapes the URL of the wanted scene, recording the
+// outcome in the wanted scene.
+func scrapeWantedScene(ctx context.Context, r Repository, sc sceneURLScraper, w *models.WantedScene) {
+	updated := *w
+	var cover []byte
+
+	scraped, err := scrapeSceneURL(ctx, sc, *w.URL)
+	if err != nil {
+		logger.Warnf("Error scraping wanted scene %s: %v", w.DisplayName(), err)
+		wanted.SetScrapeFailed(&updated, err)
+	} else {
+		wanted.ApplyScrapedScene(&updated, scraped)
+
+		if scraped.Image != nil && *scraped.Image != "" {
+			cover, err = utils.ProcessImageInput(ctx, *scraped.Image)
+			if err != nil {
+				logger.Warnf("Error getting cover image for wanted scene %s: %v", w.DisplayName(), err)
+			}
+		}
+	}
+
+	updated.UpdatedAt = time.Now()
+
+	if err := txn.WithTxn(ctx, r, func(ctx context.Context) error {
+		if _, err := r.WantedScene.Update(ctx, updated); err != nil {
+			return err
+		}
+
+		if len(cover) > 0 {
+			return r.WantedScene.UpdateCover(ctx, w.ID, cover)
+		}
+
+		return nil
+	}); err != nil {
+		logger.Errorf("Error updating wanted scene %s: %v", w.DisplayName(), err)
+	}
+}
+
+func scrapeSceneURL(ctx context.Context, sc sceneURLScraper, url string) (*scraper.ScrapedScene, error) {
+	content, err := sc.ScrapeURL(ctx, url, scraper.ScrapeContentTypeScene)
+	if err != nil {
+		return nil, err
+	}
+
+	switch s := content.(type) {
+	case *scraper.ScrapedScene:
+		return s, nil
+	case scraper.ScrapedScene:
+		return &s, nil
+	}
+
+	return nil, fmt.Errorf("no scene scraped from %s", url)
+}
@@ -0,0 +1,169 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene"
+)
+
+// RestoreTrashedScenes moves the files of the trashed scenes back to their
+// original paths and recreates the scenes. No scenes are restored if any
+// scene cannot be restored.
+func (s *Manager) RestoreTrashedScenes(ctx context.Context, ids []int) ([]*models.Scene, error) {
+	r := s.Repository
+	mover := file.NewMover(r.File, r.Folder)
+
+	var ret []*models.Scene
+	var dirs []string
+	if err := r.WithTxn(ctx, func(ctx context.Context) error {
+		qb := r.TrashedScene
+		for _, id := range ids {
+			t, err := qb.Find(ctx, id)
+			if err != nil {
+				return err
+			}
+			if t == nil {
+				return fmt.Errorf("trashed scene with id %d not found", id)
+			}
+
+			data, err := scene.DecodeTrashData(t)
+			if err != nil {
+				return err
+			}
+
+			restored, err := s.SceneService.RestoreTrashed(ctx, t, mover)
+			if err != nil {
+				return fmt.Errorf("restoring %s: %w", t.Path, err)
+			}
+
+			if err := qb.Destroy(ctx, t.ID); err != nil {
+				return err
+			}
+
+			ret = append(ret, restored)
+			dirs = append(dirs, data.Dir)
+		}
+
+		return nil
+	}); err != nil {
+		mover.Rollback()
+		return nil, err
+	}
+
+	mover.Commit()
+
+	for _, dir := range dirs {
+		// only remove the directory if it is empty
+		if err := os.Remove(dir); err != nil && !os.IsNotExist(err) {
+			logger.Warnf("error removing trash directory %s: %v", dir, err)
+		}
+	}
+
+	for _, restored := range ret {
+		logger.Infof("Restored scene %s from the trash", restored.DisplayName())
+	}
+
+	return ret, nil
+}
+
+// PurgeTrashedScenes permanently deletes the files of the trashed scenes, or
+// of all trashed scenes if ids is nil. Returns the number of purged scenes.
+func (s *Manager) PurgeTrashedScenes(ctx context.Context, ids []int) (int, error) {
+	var trashed []*models.TrashedScene
+	if err := s.Repository.WithTxn(ctx, func(ctx context.Context) error {
+		qb := s.Repository.TrashedScene
+		if ids == nil {
+			var err error
+			trashed, err = qb.All(ctx)
+			if err != nil {
+				return err
+			}
+		} else {
+			for _, id := range ids {
+				t, err := qb.Find(ctx, id)
+				if err != nil {
+					return err
+				}
+				if t == nil {
+					return fmt.Errorf("trashed scene with id %d not found", id)
+				}
+				trashed = append(trashed, t)
+			}
+		}
+
+		for _, t := range trashed {
+			if err := qb.Destroy(ctx, t.ID); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+
+	s.removeTrashedFiles(trashed)
+	return len(trashed), nil
+}
+
+func (s *Manager) removeTrashedFiles(trashed []*models.TrashedScene) {
+	for _, t := range trashed {
+		data, err := scene.DecodeTrashData(t)
+		if err != nil {
+			logger.Warnf("error removing files of trashed scene %s: %v", t.Path, err)
+			continue
+		}
+
+		if data.Dir == "" {
+			continue
+		}
+
+		if err := os.RemoveAll(data.Dir); err != nil {
+			logger.Warnf("error removing trash directory %s: %v", data.Dir, err)
+		}
+	}
+}
+
+// pruneTrash permanently deletes the trashed scenes older than the
+// configured retention period.
+func (s *Manager) pruneTrash(ctx context.Context) error {
+	days := s.Config.GetTrashRetentionDays()
+	if days <= 0 {
+		return nil
+	}
+
+	before := time.Now().AddDate(0, 0, -days)
+	var trashed []*models.TrashedScene
+	if err := s.Repository.WithTxn(ctx, func(ctx context.Context) error {
+		qb := s.Repository.TrashedScene
+		var err error
+		trashed, err = qb.FindCreatedBefore(ctx, before)
+		if err != nil {
+			return err
+		}
+
+		for _, t := range trashed {
+			if err := qb.Destroy(ctx, t.ID); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	s.removeTrashedFiles(trashed)
+
+	if len(trashed) > 0 {
+		logger.Infof("Purged %d expired scenes from the trash", len(trashed))
+	}
+
+	return nil
+}
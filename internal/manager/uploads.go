@@ -0,0 +1,100 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/upload"
+)
+
+var ErrUploadDisabled = errors.New("uploads are disabled")
+
+// uploadExpiry is the time after which incomplete uploads are considered
+// abandoned and removed.
+const uploadExpiry = 7 * 24 * time.Hour
+
+// UploadStore returns the store of in-progress uploads. Uploads are stored
+// in the cache directory until complete.
+func (s *Manager) UploadStore() (*upload.Store, error) {
+	if s.Config.GetUploadInboxPath() == "" {
+		return nil, ErrUploadDisabled
+	}
+
+	cachePath := s.Config.GetCachePath()
+	if cachePath == "" {
+		return nil, errors.New("cache path is not set")
+	}
+
+	dir := filepath.Join(cachePath, "uploads")
+
+	s.uploadMutex.Lock()
+	defer s.uploadMutex.Unlock()
+
+	if s.uploadStore == nil || s.uploadStore.Dir() != dir {
+		s.uploadStore = upload.NewStore(dir)
+	}
+
+	return s.uploadStore, nil
+}
+
+// CreateUpload creates a new upload of a file with the provided name and
+// length, removing abandoned uploads.
+func (s *Manager) CreateUpload(filename string, length int64) (*upload.Info, error) {
+	store, err := s.UploadStore()
+	if err != nil {
+		return nil, err
+	}
+
+	if n, err := store.DeleteCreatedBefore(time.Now().Add(-uploadExpiry)); err != nil {
+		logger.Warnf("Error removing abandoned uploads: %v", err)
+	} else if n > 0 {
+		logger.Infof("Removed %d abandoned uploads", n)
+	}
+
+	return store.Create(filename, length)
+}
+
+// CompleteUpload moves the file of the completed upload to the upload inbox,
+// and queues a scan of the inbox. Returns the path of the file and the ID of
+// the scan job.
+func (s *Manager) CompleteUpload(ctx context.Context, id string) (string, int, error) {
+	store, err := s.UploadStore()
+	if err != nil {
+		return "", 0, err
+	}
+
+	inbox := s.Config.GetUploadInboxPath()
+	if getStashFromDirPath(s.Config.GetStashPaths(), inbox) == nil {
+		return "", 0, fmt.Errorf("upload inbox %s is not in the configured stash paths", inbox)
+	}
+
+	if err := fsutil.EnsureDirAll(inbox); err != nil {
+		return "", 0, err
+	}
+
+	p, err := store.Move(id, inbox)
+	if err != nil {
+		return "", 0, err
+	}
+
+	logger.Infof("Upload completed: %s", p)
+
+	scanInput := ScanMetadataInput{
+		Paths: []string{inbox},
+	}
+	if opts := s.Config.GetDefaultScanSettings(); opts != nil {
+		scanInput.ScanMetadataOptions = *opts
+	}
+
+	jobID, err := s.Scan(ctx, scanInput)
+	if err != nil {
+		return p, 0, err
+	}
+
+	return p, jobID, nil
+}
@@ -0,0 +1,151 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/stashapp/stash/internal/manager/config"
+	"github.com/stashapp/stash/pkg/hash"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/session"
+	"github.com/stashapp/stash/pkg/txn"
+)
+
+// userFinder finds the users used to authenticate requests.
+type userFinder struct {
+	repository Repository
+}
+
+func (f *userFinder) FindUserByUsername(ctx context.Context, username string) (ret *models.User, err error) {
+	err = txn.WithReadTxn(ctx, f.repository, func(ctx context.Context) error {
+		ret, err = f.repository.User.FindByUsername(ctx, username)
+		return err
+	})
+	return
+}
+
+func (f *userFinder) FindUserByAPIKeyHash(ctx context.Context, hash string) (ret *models.User, err error) {
+	err = txn.WithReadTxn(ctx, f.repository, func(ctx context.Context) error {
+		ret, err = f.repository.User.FindByAPIKeyHash(ctx, hash)
+		return err
+	})
+	return
+}
+
+// restrictionProfileFinder finds the restriction profiles used to hide
+// content from users and sessions.
+type restrictionProfileFinder struct {
+	repository Repository
+}
+
+func (f *restrictionProfileFinder) AllRestrictionProfiles(ctx context.Context) (ret []*models.RestrictionProfile, err error) {
+	err = txn.WithReadTxn(ctx, f.repository, func(ctx context.Context) error {
+		ret, err = f.repository.RestrictionProfile.All(ctx)
+		return err
+	})
+	return
+}
+
+func (f *restrictionProfileFinder) FindContentRestriction(ctx context.Context, profileID int) (ret *models.ContentRestriction, err error) {
+	err = txn.WithReadTxn(ctx, f.repository, func(ctx context.Context) error {
+		qb := f.repository.RestrictionProfile
+		p, err := qb.Find(ctx, profileID)
+		if err != nil || p == nil {
+			return err
+		}
+
+		ret = &models.ContentRestriction{}
+		if ret.TagIDs, err = qb.GetTagIDs(ctx, profileID); err != nil {
+			return err
+		}
+		ret.StudioIDs, err = qb.GetStudioIDs(ctx, profileID)
+		return err
+	})
+	return
+}
+
+func (f *userFinder) FindUserByExternalIdentity(ctx context.Context, provider, subject string) (ret *models.User, err error) {
+	err = txn.WithReadTxn(ctx, f.repository, func(ctx context.Context) error {
+		ret, err = f.repository.User.FindByExternalIdentity(ctx, provider, subject)
+		return err
+	})
+	return
+}
+
+// RegisterExternalUser creates a user for an unknown external user. The
+// user cannot log in with a password until an administrator sets one.
+func (f *userFinder) RegisterExternalUser(ctx context.Context, provider string, identity session.ExternalIdentity, role models.UserRole) (ret *models.User, err error) {
+	username := identity.Username
+	if username == "" {
+		username = identity.Email
+	}
+	if username == "" {
+		return nil, errors.New("the external user has no username or email")
+	}
+
+	if strings.EqualFold(username, config.GetInstance().GetUsername()) {
+		return nil, fmt.Errorf("username %s is used by the administrator", username)
+	}
+
+	// the password is random, and never revealed
+	password, err := hash.GenerateRandomKey(32)
+	if err != nil {
+		return nil, err
+	}
+	passwordHash, err := session.HashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	err = txn.WithTxn(ctx, f.repository, func(ctx context.Context) error {
+		qb := f.repository.User
+
+		// don't take over existing users - they must be linked by an
+		// administrator
+		existing, err := qb.FindByUsername(ctx, username)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return fmt.Errorf("username %s is already in use", username)
+		}
+
+		now := time.Now()
+		ret, err = qb.Create(ctx, models.User{
+			Username:     username,
+			PasswordHash: passwordHash,
+			Role:         role,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		})
+		if err != nil {
+			return err
+		}
+
+		return qb.LinkExternalIdentity(ctx, ret.ID, models.UserExternalIdentity{
+			Provider: provider,
+			Subject:  identity.Subject,
+		})
+	})
+	return
+}
+
+// authProviders returns the external authentication providers configured
+// for logging in, ignoring invalid configurations.
+func (s *Manager) authProviders() []session.AuthProvider {
+	var ret []session.AuthProvider
+	for _, c := range s.Config.GetOIDCProviders() {
+		p, err := session.NewOIDCProvider(*c)
+		if err != nil {
+			logger.Errorf("Invalid OpenID Connect provider %q: %v", c.ID, err)
+			continue
+		}
+		ret = append(ret, p)
+	}
+
+	return ret
+}
@@ -0,0 +1,121 @@
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/ffmpeg"
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/txn"
+)
+
+// replacementTempSuffix is appended to the path of a video file to produce
+// the path of its replacement while it is being written. The suffix is not
+// a video extension, so incomplete replacements are ignored by the scanner.
+const replacementTempSuffix = ".stash.tmp"
+
+// maxDurationDifference is the maximum difference in seconds between the
+// duration of a video file and its replacement.
+const maxDurationDifference = 1.0
+
+var containerFormats = map[string]ffmpeg.Format{
+	"mp4":  ffmpeg.FormatMP4,
+	"m4v":  ffmpeg.FormatMP4,
+	"mov":  "mov",
+	"mkv":  ffmpeg.FormatMatroska,
+	"webm": ffmpeg.FormatWebm,
+}
+
+// containerFormat returns the ffmpeg format for the provided file extension,
+// without the leading period. Returns false if the extension is not supported
+// as an output container.
+func containerFormat(ext string) (ffmpeg.Format, bool) {
+	ret, ok := containerFormats[strings.ToLower(ext)]
+	return ret, ok
+}
+
+// replacementPath returns the path of the file that will replace the file at
+// p, using the provided extension, without the leading period.
+func replacementPath(p string, ext string) string {
+	currentExt := filepath.Ext(p)
+	if strings.EqualFold(strings.TrimPrefix(currentExt, "."), ext) {
+		return p
+	}
+
+	return strings.TrimSuffix(p, currentExt) + "." + ext
+}
+
+// verifyReplacement checks that the probed replacement video is a valid
+// substitute for the original file.
+func verifyReplacement(original *file.VideoFile, replacement *ffmpeg.VideoFile, videoCodec string) error {
+	if replacement.VideoStream == nil {
+		return errors.New("output has no video stream")
+	}
+
+	if original.AudioCodec != "" && replacement.AudioStream == nil {
+		return errors.New("output has no audio stream")
+	}
+
+	if videoCodec != "" && !strings.EqualFold(replacement.VideoCodec, videoCodec) {
+		return fmt.Errorf("output video codec %q does not match %q", replacement.VideoCodec, videoCodec)
+	}
+
+	if diff := math.Abs(replacement.FileDuration - original.Duration); diff > maxDurationDifference {
+		return fmt.Errorf("output duration %.2fs differs from original duration %.2fs", replacement.FileDuration, original.Duration)
+	}
+
+	return nil
+}
+
+// replaceVideoFile replaces the video file f with the file at tempPath,
+// moving it to newPath. If newPath differs from the path of f, then the file
+// is renamed in the database and the original file is deleted. The file must
+// be rescanned afterwards to update its fingerprints and metadata.
+func replaceVideoFile(ctx context.Context, r Repository, f *file.VideoFile, tempPath string, newPath string) error {
+	if newPath == f.Path {
+		// rename is atomic when on the same filesystem
+		if err := os.Rename(tempPath, f.Path); err != nil {
+			return fmt.Errorf("replacing %s: %w", f.Path, err)
+		}
+		return nil
+	}
+
+	exists, err := fsutil.FileExists(newPath)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("%s already exists", newPath)
+	}
+
+	if err := os.Rename(tempPath, newPath); err != nil {
+		return fmt.Errorf("moving %s to %s: %w", tempPath, newPath, err)
+	}
+
+	oldPath := f.Path
+	if err := txn.WithTxn(ctx, r, func(ctx context.Context) error {
+		f.Basename = filepath.Base(newPath)
+		f.Path = newPath
+		return r.File.Update(ctx, f)
+	}); err != nil {
+		f.Basename = filepath.Base(oldPath)
+		f.Path = oldPath
+		if removeErr := os.Remove(newPath); removeErr != nil {
+			logger.Warnf("error removing %s: %v", newPath, removeErr)
+		}
+		return fmt.Errorf("updating file %s: %w", oldPath, err)
+	}
+
+	if err := os.Remove(oldPath); err != nil {
+		logger.Warnf("error removing original file %s: %v", oldPath, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/stashapp/stash/pkg/plugin"
+	"github.com/stashapp/stash/pkg/webhook"
+)
+
+// initWebhooks creates the webhook dispatcher, posting created scenes using
+// the plugin post hooks.
+func (s *Manager) initWebhooks() {
+	s.Webhooks = webhook.NewDispatcher(s.Config.GetWebhooks)
+	s.PluginCache.RegisterHookListener(s.dispatchHookWebhooks)
+}
+
+func (s *Manager) dispatchHookWebhooks(ctx context.Context, id int, hookType plugin.HookTriggerEnum) {
+	if hookType == plugin.SceneCreatePost {
+		s.Webhooks.Dispatch(webhook.EventSceneCreated, fmt.Sprintf("Scene %d created", id), map[string]interface{}{
+			"id": id,
+		})
+	}
+}
+
+// dispatchScanFinishedWebhook posts the changes made by the scan. diff is
+// nil if they could not be determined.
+func (s *Manager) dispatchScanFinishedWebhook(paths []string, elapsed time.Duration, diff *ScanDiff) {
+	message := fmt.Sprintf("Scan finished (%s)", elapsed.Round(time.Second))
+	data := map[string]interface{}{
+		"paths":           paths,
+		"elapsed_seconds": elapsed.Seconds(),
+	}
+
+	if diff != nil {
+		message += ": " + diff.String()
+		data["new_scenes"] = diff.NewScenes
+		data["new_files"] = diff.NewFiles
+		data["updated_files"] = diff.UpdatedFiles
+		data["missing_files"] = diff.MissingFiles
+	}
+
+	s.Webhooks.Dispatch(webhook.EventScanFinished, message, data)
+}
+
+func (s *Manager) dispatchGenerateFinishedWebhook(elapsed time.Duration) {
+	s.Webhooks.Dispatch(webhook.EventGenerateFinished, fmt.Sprintf("Generate finished (%s)", elapsed.Round(time.Second)), map[string]interface{}{
+		"elapsed_seconds": elapsed.Seconds(),
+	})
+}
+
+func (s *Manager) dispatchPluginTaskFailedWebhook(pluginID, taskName string, err string) {
+	s.Webhooks.Dispatch(webhook.EventPluginTaskFailed, fmt.Sprintf("Plugin task %s failed: %s", taskName, err), map[string]interface{}{
+		"plugin_id": pluginID,
+		"task":      taskName,
+		"error":     err,
+	})
+}
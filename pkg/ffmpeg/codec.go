@@ -19,6 +19,16 @@ var (
 	VideoCodecVPX     VideoCodec = "libvpx"
 	VideoCodecLibX265 VideoCodec = "libx265"
 	VideoCodecCopy    VideoCodec = "copy"
+
+	// hardware encoders
+	VideoCodecH264VAAPI VideoCodec = "h264_vaapi"
+	VideoCodecH264NVENC VideoCodec = "h264_nvenc"
+	VideoCodecH264QSV   VideoCodec = "h264_qsv"
+	VideoCodecHEVCVAAPI VideoCodec = "hevc_vaapi"
+	VideoCodecHEVCNVENC VideoCodec = "hevc_nvenc"
+	VideoCodecHEVCQSV   VideoCodec = "hevc_qsv"
+	VideoCodecVP9VAAPI  VideoCodec = "vp9_vaapi"
+	VideoCodecVP9QSV    VideoCodec = "vp9_qsv"
 )
 
 type AudioCodec string
@@ -19,10 +19,14 @@ type VideoFile struct {
 	AudioStream *FFProbeStream
 	VideoStream *FFProbeStream
 
-	Path      string
-	Title     string
-	Comment   string
-	Container string
+	Path        string
+	Title       string
+	Comment     string
+	Description string
+	Artist      string
+	AlbumArtist string
+	Date        string
+	Container   string
 	// FileDuration is the declared (meta-data) duration of the *file*.
 	// In most cases (sprites, previews, etc.) we actually care about the duration of the video stream specifically,
 	// because those two can differ slightly (e.g. audio stream longer than the video stream, making the whole file
@@ -43,6 +47,32 @@ type VideoFile struct {
 	FrameCount   int64
 
 	AudioCodec string
+
+	// CoverStream is the embedded cover art, if present
+	CoverStream *FFProbeStream
+
+	Chapters []Chapter
+
+	// SubtitleStreams are the text subtitle streams of the file
+	SubtitleStreams []SubtitleStream
+}
+
+// Chapter is a chapter of a video file, with times in seconds.
+type Chapter struct {
+	Title string
+	Start float64
+	End   float64
+}
+
+// SubtitleStream is a text subtitle stream of a video file.
+type SubtitleStream struct {
+	// Index of the stream among the subtitle streams of the file
+	Index    int
+	Codec    string
+	Language string
+	Title    string
+	Default  bool
+	Forced   bool
 }
 
 // TranscodeScale calculates the dimension scaling for a transcode, where maxSize is the maximum size of the longest dimension of the input video.
@@ -77,7 +107,7 @@ type FFProbe string
 
 // NewVideoFile runs ffprobe on the given path and returns a VideoFile.
 func (f *FFProbe) NewVideoFile(videoPath string) (*VideoFile, error) {
-	args := []string{"-v", "quiet", "-print_format", "json", "-show_format", "-show_streams", "-show_error", videoPath}
+	args := []string{"-v", "quiet", "-print_format", "json", "-show_format", "-show_streams", "-show_chapters", "-show_error", videoPath}
 	cmd := exec.Command(string(*f), args...)
 	out, err := cmd.Output()
 
@@ -128,6 +158,10 @@ func parse(filePath string, probeJSON *FFProbeJSON) (*VideoFile, error) {
 	result.Title = probeJSON.Format.Tags.Title
 
 	result.Comment = probeJSON.Format.Tags.Comment
+	result.Description = probeJSON.Format.Tags.Description
+	result.Artist = probeJSON.Format.Tags.Artist
+	result.AlbumArtist = probeJSON.Format.Tags.AlbumArtist
+	result.Date = probeJSON.Format.Tags.Date
 	result.Bitrate, _ = strconv.ParseInt(probeJSON.Format.BitRate, 10, 64)
 
 	result.Container = probeJSON.Format.FormatName
@@ -190,6 +224,48 @@ func parse(filePath string, probeJSON *FFProbeJSON) (*VideoFile, error) {
 		}
 	}
 
+	for i, stream := range result.JSON.Streams {
+		if stream.CodecType == "video" && stream.Disposition.AttachedPic == 1 {
+			result.CoverStream = &result.JSON.Streams[i]
+			break
+		}
+	}
+
+	subtitleIndex := 0
+	for _, stream := range result.JSON.Streams {
+		if stream.CodecType != "subtitle" {
+			continue
+		}
+
+		// image based subtitles cannot be converted to text or burnt in
+		// using the subtitles filter
+		if IsTextSubtitleCodec(stream.CodecName) {
+			result.SubtitleStreams = append(result.SubtitleStreams, SubtitleStream{
+				Index:    subtitleIndex,
+				Codec:    stream.CodecName,
+				Language: stream.Tags.Language,
+				Title:    strings.TrimSpace(stream.Tags.Title),
+				Default:  stream.Disposition.Default == 1,
+				Forced:   stream.Disposition.Forced == 1,
+			})
+		}
+		subtitleIndex++
+	}
+
+	for _, c := range probeJSON.Chapters {
+		start, err := strconv.ParseFloat(c.StartTime, 64)
+		if err != nil {
+			continue
+		}
+		end, _ := strconv.ParseFloat(c.EndTime, 64)
+
+		result.Chapters = append(result.Chapters, Chapter{
+			Title: strings.TrimSpace(c.Tags.Title),
+			Start: start,
+			End:   end,
+		})
+	}
+
 	return result, nil
 }
 
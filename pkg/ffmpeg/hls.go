@@ -1,15 +1,32 @@
 package ffmpeg
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"math"
+	"sort"
 	"strings"
 )
 
-const hlsSegmentLength = 10.0
+const (
+	hlsSegmentLength = 10.0
+
+	// audio bitrate of adaptive bitrate variant streams, in kilobits per second
+	hlsVariantAudioBitrate = 128
+)
 
 // WriteHLSPlaylist writes a HLS playlist to w using baseUrl as the base URL for TS streams.
 func WriteHLSPlaylist(duration float64, baseUrl string, w io.Writer) {
+	i := strings.LastIndex(baseUrl, ".m3u8")
+	tsURL := baseUrl[0:i] + ".ts"
+
+	writeHLSMediaPlaylist(w, duration, func(index int, start float64) string {
+		return fmt.Sprintf("%s?start=%f", tsURL, start)
+	})
+}
+
+func writeHLSMediaPlaylist(w io.Writer, duration float64, segmentURL func(index int, start float64) string) {
 	fmt.Fprint(w, "#EXTM3U\n")
 	fmt.Fprint(w, "#EXT-X-VERSION:3\n")
 	fmt.Fprint(w, "#EXT-X-MEDIA-SEQUENCE:0\n")
@@ -20,17 +37,14 @@ func WriteHLSPlaylist(duration float64, baseUrl string, w io.Writer) {
 	leftover := duration
 	upTo := 0.0
 
-	i := strings.LastIndex(baseUrl, ".m3u8")
-	tsURL := baseUrl[0:i] + ".ts"
-
-	for leftover > 0 {
+	for index := 0; leftover > 0; index++ {
 		thisLength := hlsSegmentLength
 		if leftover < thisLength {
 			thisLength = leftover
 		}
 
 		fmt.Fprintf(w, "#EXTINF: %f,\n", thisLength)
-		fmt.Fprintf(w, "%s?start=%f\n", tsURL, upTo)
+		fmt.Fprintf(w, "%s\n", segmentURL(index, upTo))
 
 		leftover -= thisLength
 		upTo += thisLength
@@ -38,3 +52,177 @@ func WriteHLSPlaylist(duration float64, baseUrl string, w io.Writer) {
 
 	fmt.Fprint(w, "#EXT-X-ENDLIST\n")
 }
+
+// HLSSegmentCount returns the number of segments of a HLS stream of a video
+// with the given duration.
+func HLSSegmentCount(duration float64) int {
+	return int(math.Ceil(duration / hlsSegmentLength))
+}
+
+// HLSVariant is a variant stream of an adaptive bitrate HLS stream.
+type HLSVariant struct {
+	// Name identifies the variant in the stream URLs.
+	Name string
+	// MaxResolution is the maximum output resolution - the smaller of the
+	// width and height. Zero to keep the resolution of the source.
+	MaxResolution int
+	// Bitrate is the video bitrate in kilobits per second.
+	Bitrate int
+}
+
+// Dimensions returns the output width and height of the variant for a video
+// of the given dimensions.
+func (v HLSVariant) Dimensions(width, height int) (int, int) {
+	smaller := height
+	if width < smaller {
+		smaller = width
+	}
+
+	if v.MaxResolution == 0 || smaller == 0 || v.MaxResolution >= smaller {
+		return width, height
+	}
+
+	scale := func(d int) int {
+		// scaled to a multiple of 2, matching ScaleMax
+		return int(math.Round(float64(d)*float64(v.MaxResolution)/float64(smaller)/2)) * 2
+	}
+
+	if width > height {
+		return scale(width), v.MaxResolution
+	}
+
+	return v.MaxResolution, scale(height)
+}
+
+// bandwidth returns the peak bandwidth of the variant in bits per second.
+func (v HLSVariant) bandwidth() int {
+	return (v.Bitrate + hlsVariantAudioBitrate) * 1000
+}
+
+// HLSVariantsForVideo returns the variants that do not upscale a video of the
+// given dimensions, ordered by ascending bitrate. The variant with the
+// smallest max resolution is returned if all of the variants would upscale.
+func HLSVariantsForVideo(variants []HLSVariant, width, height int) []HLSVariant {
+	smaller := height
+	if width < smaller {
+		smaller = width
+	}
+
+	var ret []HLSVariant
+	var smallest *HLSVariant
+	for i, v := range variants {
+		if v.MaxResolution == 0 || v.MaxResolution <= smaller {
+			ret = append(ret, v)
+		} else if smallest == nil || v.MaxResolution < smallest.MaxResolution {
+			smallest = &variants[i]
+		}
+	}
+
+	if len(ret) == 0 && smallest != nil {
+		ret = append(ret, *smallest)
+	}
+
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].Bitrate < ret[j].Bitrate
+	})
+
+	return ret
+}
+
+// WriteHLSMasterPlaylist writes an adaptive bitrate HLS master playlist
+// listing the provided variants to w. variantURL returns the URL of the
+// playlist of a variant.
+func WriteHLSMasterPlaylist(w io.Writer, variants []HLSVariant, width, height int, variantURL func(v HLSVariant) string) {
+	fmt.Fprint(w, "#EXTM3U\n")
+	fmt.Fprint(w, "#EXT-X-VERSION:3\n")
+
+	for _, v := range variants {
+		vw, vh := v.Dimensions(width, height)
+		fmt.Fprintf(w, "#EXT-X-STREAM-INF:BANDWIDTH=%d,RESOLUTION=%dx%d\n", v.bandwidth(), vw, vh)
+		fmt.Fprintf(w, "%s\n", variantURL(v))
+	}
+}
+
+// WriteHLSVariantPlaylist writes the playlist of an adaptive bitrate variant
+// stream to w. segmentURL returns the URL of the segment with the given
+// index.
+func WriteHLSVariantPlaylist(w io.Writer, duration float64, segmentURL func(index int) string) {
+	writeHLSMediaPlaylist(w, duration, func(index int, start float64) string {
+		return segmentURL(index)
+	})
+}
+
+// HLSSegmentOptions represents options for transcoding a segment of an
+// adaptive bitrate HLS variant stream.
+type HLSSegmentOptions struct {
+	Input   string
+	Index   int
+	Variant HLSVariant
+
+	// original video dimensions
+	VideoWidth  int
+	VideoHeight int
+
+	// remove the audio, if the audio codec is not supported by ffmpeg
+	VideoOnly bool
+
+	OutputPath string
+}
+
+func (o HLSSegmentOptions) args() Args {
+	start := float64(o.Index) * hlsSegmentLength
+
+	var args Args
+	args = append(args, "-hide_banner")
+	args = args.LogLevel(LogLevelError).Overwrite()
+
+	if start != 0 {
+		args = args.Seek(start)
+	}
+
+	args = args.Duration(hlsSegmentLength)
+	args = args.Input(o.Input)
+
+	if o.VideoOnly {
+		args = args.SkipAudio()
+	}
+
+	args = args.VideoCodec(VideoCodecLibX264)
+
+	var videoFilter VideoFilter
+	videoFilter = videoFilter.ScaleMax(o.VideoWidth, o.VideoHeight, o.Variant.MaxResolution)
+	args = args.VideoFilter(videoFilter)
+
+	bitrate := fmt.Sprintf("%dk", o.Variant.Bitrate)
+	args = append(args,
+		"-pix_fmt", "yuv420p",
+		"-preset", "veryfast",
+		"-b:v", bitrate,
+		"-maxrate", bitrate,
+		"-bufsize", fmt.Sprintf("%dk", o.Variant.Bitrate*2),
+	)
+
+	if !o.VideoOnly {
+		args = args.AudioCodec(AudioCodecAAC)
+		args = args.AudioBitrate(fmt.Sprintf("%dk", hlsVariantAudioBitrate))
+		args = append(args,
+			// this is needed for 5-channel ac3 files
+			"-ac", "2",
+		)
+	}
+
+	// keep the timestamps of the variants aligned, so that clients can
+	// switch between them
+	args = append(args, "-output_ts_offset", fmt.Sprint(start))
+
+	args = args.Format(FormatMpegTS)
+	args = args.Output(o.OutputPath)
+
+	return args
+}
+
+// GenerateHLSSegment transcodes a segment of an adaptive bitrate HLS variant
+// stream to the output path.
+func (f FFMpeg) GenerateHLSSegment(ctx context.Context, options HLSSegmentOptions) error {
+	return f.Generate(ctx, options.args())
+}
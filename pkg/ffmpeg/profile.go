@@ -0,0 +1,396 @@
+package ffmpeg
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+)
+
+const (
+	defaultVAAPIDevice = "/dev/dri/renderD128"
+	defaultPreset      = "veryfast"
+)
+
+type TranscodeVideoCodec string
+
+const (
+	TranscodeVideoCodecH264 TranscodeVideoCodec = "H264"
+	TranscodeVideoCodecHEVC TranscodeVideoCodec = "HEVC"
+	TranscodeVideoCodecVP9  TranscodeVideoCodec = "VP9"
+)
+
+var AllTranscodeVideoCodec = []TranscodeVideoCodec{
+	TranscodeVideoCodecH264,
+	TranscodeVideoCodecHEVC,
+	TranscodeVideoCodecVP9,
+}
+
+func (e TranscodeVideoCodec) IsValid() bool {
+	switch e {
+	case TranscodeVideoCodecH264, TranscodeVideoCodecHEVC, TranscodeVideoCodecVP9:
+		return true
+	}
+	return false
+}
+
+func (e TranscodeVideoCodec) String() string {
+	return string(e)
+}
+
+func (e *TranscodeVideoCodec) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = TranscodeVideoCodec(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid TranscodeVideoCodec", str)
+	}
+	return nil
+}
+
+func (e TranscodeVideoCodec) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// TranscodeHWAccel is the hardware acceleration backend used to encode.
+type TranscodeHWAccel string
+
+const (
+	TranscodeHWAccelNone  TranscodeHWAccel = "NONE"
+	TranscodeHWAccelVAAPI TranscodeHWAccel = "VAAPI"
+	TranscodeHWAccelNVENC TranscodeHWAccel = "NVENC"
+	TranscodeHWAccelQSV   TranscodeHWAccel = "QSV"
+)
+
+var AllTranscodeHWAccel = []TranscodeHWAccel{
+	TranscodeHWAccelNone,
+	TranscodeHWAccelVAAPI,
+	TranscodeHWAccelNVENC,
+	TranscodeHWAccelQSV,
+}
+
+func (e TranscodeHWAccel) IsValid() bool {
+	switch e {
+	case TranscodeHWAccelNone, TranscodeHWAccelVAAPI, TranscodeHWAccelNVENC, TranscodeHWAccelQSV:
+		return true
+	}
+	return false
+}
+
+func (e TranscodeHWAccel) String() string {
+	return string(e)
+}
+
+func (e *TranscodeHWAccel) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = TranscodeHWAccel(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid TranscodeHWAccel", str)
+	}
+	return nil
+}
+
+func (e TranscodeHWAccel) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type TranscodeContainer string
+
+const (
+	TranscodeContainerMP4    TranscodeContainer = "MP4"
+	TranscodeContainerWebm   TranscodeContainer = "WEBM"
+	TranscodeContainerMpegTS TranscodeContainer = "MPEGTS"
+)
+
+var AllTranscodeContainer = []TranscodeContainer{
+	TranscodeContainerMP4,
+	TranscodeContainerWebm,
+	TranscodeContainerMpegTS,
+}
+
+func (e TranscodeContainer) IsValid() bool {
+	switch e {
+	case TranscodeContainerMP4, TranscodeContainerWebm, TranscodeContainerMpegTS:
+		return true
+	}
+	return false
+}
+
+func (e TranscodeContainer) String() string {
+	return string(e)
+}
+
+func (e *TranscodeContainer) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = TranscodeContainer(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid TranscodeContainer", str)
+	}
+	return nil
+}
+
+func (e TranscodeContainer) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+var transcodeEncoders = map[TranscodeVideoCodec]map[TranscodeHWAccel]VideoCodec{
+	TranscodeVideoCodecH264: {
+		TranscodeHWAccelNone:  VideoCodecLibX264,
+		TranscodeHWAccelVAAPI: VideoCodecH264VAAPI,
+		TranscodeHWAccelNVENC: VideoCodecH264NVENC,
+		TranscodeHWAccelQSV:   VideoCodecH264QSV,
+	},
+	TranscodeVideoCodecHEVC: {
+		TranscodeHWAccelNone:  VideoCodecLibX265,
+		TranscodeHWAccelVAAPI: VideoCodecHEVCVAAPI,
+		TranscodeHWAccelNVENC: VideoCodecHEVCNVENC,
+		TranscodeHWAccelQSV:   VideoCodecHEVCQSV,
+	},
+	TranscodeVideoCodecVP9: {
+		TranscodeHWAccelNone:  VideoCodecVP9,
+		TranscodeHWAccelVAAPI: VideoCodecVP9VAAPI,
+		TranscodeHWAccelQSV:   VideoCodecVP9QSV,
+	},
+}
+
+// default quality values, matching the built-in stream formats
+var transcodeDefaultQuality = map[TranscodeVideoCodec]int{
+	TranscodeVideoCodecH264: 25,
+	TranscodeVideoCodecHEVC: 30,
+	TranscodeVideoCodecVP9:  30,
+}
+
+// TranscodeBitrate is an entry of the bitrate ladder of a transcode profile.
+type TranscodeBitrate struct {
+	// MaxResolution is the largest output resolution - the smaller of the
+	// width and height - that the bitrate is used for.
+	MaxResolution int `json:"max_resolution"`
+	// Bitrate is the target video bitrate, in ffmpeg notation. For example: 4M
+	Bitrate string `json:"bitrate"`
+}
+
+// TranscodeProfile is a named set of encoding options used in place of the
+// built-in ffmpeg arguments when live transcoding or generating previews.
+type TranscodeProfile struct {
+	Name       string              `json:"name"`
+	VideoCodec TranscodeVideoCodec `json:"video_codec"`
+	HWAccel    TranscodeHWAccel    `json:"hw_accel"`
+	// Device is the render device used with VAAPI. Defaults to /dev/dri/renderD128.
+	Device    string             `json:"device"`
+	Container TranscodeContainer `json:"container"`
+	// Quality is the constant quality value used when no bitrate applies.
+	// The encoder default is used if zero.
+	Quality int `json:"quality"`
+	// Preset is the encoder preset. Ignored by VAAPI and libvpx.
+	Preset string `json:"preset"`
+	// Bitrates is the bitrate ladder. Constant quality is used if empty.
+	Bitrates  []*TranscodeBitrate `json:"bitrates"`
+	ExtraArgs []string            `json:"extra_args"`
+}
+
+// Validate returns an error if the profile has a missing or unsupported
+// combination of options.
+func (p TranscodeProfile) Validate() error {
+	if p.Name == "" {
+		return errors.New("name must be set")
+	}
+
+	if !p.VideoCodec.IsValid() {
+		return fmt.Errorf("%s: invalid video codec %q", p.Name, p.VideoCodec)
+	}
+
+	if !p.hwAccel().IsValid() {
+		return fmt.Errorf("%s: invalid hardware acceleration %q", p.Name, p.HWAccel)
+	}
+
+	if p.Encoder() == "" {
+		return fmt.Errorf("%s: %s encoding is not supported with %s", p.Name, p.VideoCodec, p.hwAccel())
+	}
+
+	switch p.Container {
+	case TranscodeContainerMP4, TranscodeContainerMpegTS:
+		if p.VideoCodec == TranscodeVideoCodecVP9 {
+			return fmt.Errorf("%s: %s cannot be used with %s", p.Name, p.VideoCodec, p.Container)
+		}
+	case TranscodeContainerWebm:
+		if p.VideoCodec != TranscodeVideoCodecVP9 {
+			return fmt.Errorf("%s: %s cannot be used with %s", p.Name, p.VideoCodec, p.Container)
+		}
+	default:
+		return fmt.Errorf("%s: invalid container %q", p.Name, p.Container)
+	}
+
+	if p.Quality < 0 {
+		return fmt.Errorf("%s: quality must not be negative", p.Name)
+	}
+
+	for _, b := range p.Bitrates {
+		if b.MaxResolution <= 0 || b.Bitrate == "" {
+			return fmt.Errorf("%s: bitrates must have a positive max resolution and a bitrate", p.Name)
+		}
+	}
+
+	return nil
+}
+
+func (p TranscodeProfile) hwAccel() TranscodeHWAccel {
+	if p.HWAccel == "" {
+		return TranscodeHWAccelNone
+	}
+
+	return p.HWAccel
+}
+
+// Encoder returns the ffmpeg encoder for the video codec and hardware
+// acceleration of the profile. Returns an empty codec if the combination is
+// not supported.
+func (p TranscodeProfile) Encoder() VideoCodec {
+	return transcodeEncoders[p.VideoCodec][p.hwAccel()]
+}
+
+// InputArgs returns the arguments that must precede the input to initialise
+// the hardware device.
+func (p TranscodeProfile) InputArgs() Args {
+	switch p.hwAccel() {
+	case TranscodeHWAccelVAAPI:
+		device := p.Device
+		if device == "" {
+			device = defaultVAAPIDevice
+		}
+		return Args{"-vaapi_device", device}
+	case TranscodeHWAccelQSV:
+		return Args{"-init_hw_device", "qsv=hw", "-filter_hw_device", "hw"}
+	}
+
+	return nil
+}
+
+// HWUpload returns f with the filters uploading the frames to the hardware
+// device appended, if required by the hardware acceleration of the profile.
+func (p TranscodeProfile) HWUpload(f VideoFilter) VideoFilter {
+	switch p.hwAccel() {
+	case TranscodeHWAccelVAAPI:
+		return f.Append("format=nv12,hwupload")
+	case TranscodeHWAccelQSV:
+		return f.Append("format=nv12,hwupload=extra_hw_frames=64")
+	}
+
+	return f
+}
+
+// Bitrate returns the bitrate of the ladder for the given output resolution.
+// The bitrate with the smallest max resolution that fits is used, otherwise
+// the bitrate with the largest. Returns an empty string if the profile has
+// no bitrates.
+func (p TranscodeProfile) Bitrate(resolution int) string {
+	if len(p.Bitrates) == 0 {
+		return ""
+	}
+
+	ladder := make([]*TranscodeBitrate, len(p.Bitrates))
+	copy(ladder, p.Bitrates)
+	sort.Slice(ladder, func(i, j int) bool {
+		return ladder[i].MaxResolution < ladder[j].MaxResolution
+	})
+
+	for _, b := range ladder {
+		if resolution <= b.MaxResolution {
+			return b.Bitrate
+		}
+	}
+
+	return ladder[len(ladder)-1].Bitrate
+}
+
+// VideoArgs returns the encoder arguments for the given output resolution,
+// which is the smaller of the output width and height.
+func (p TranscodeProfile) VideoArgs(resolution int) Args {
+	var args Args
+	hwAccel := p.hwAccel()
+	software := hwAccel == TranscodeHWAccelNone
+
+	// hardware uploaded frames are already in the encoder format
+	if software || hwAccel == TranscodeHWAccelNVENC {
+		args = append(args, "-pix_fmt", "yuv420p")
+	}
+
+	if bitrate := p.Bitrate(resolution); bitrate != "" {
+		args = append(args, "-b:v", bitrate)
+	} else {
+		quality := p.Quality
+		if quality == 0 {
+			quality = transcodeDefaultQuality[p.VideoCodec]
+		}
+		q := strconv.Itoa(quality)
+
+		switch hwAccel {
+		case TranscodeHWAccelVAAPI:
+			args = append(args, "-qp", q)
+		case TranscodeHWAccelNVENC:
+			args = append(args, "-cq", q)
+		case TranscodeHWAccelQSV:
+			args = append(args, "-global_quality", q)
+		default:
+			args = append(args, "-crf", q)
+			if p.VideoCodec == TranscodeVideoCodecVP9 {
+				args = append(args, "-b:v", "0")
+			}
+		}
+	}
+
+	switch {
+	case software && p.VideoCodec == TranscodeVideoCodecVP9:
+		args = append(args,
+			"-deadline", "realtime",
+			"-cpu-used", "5",
+			"-row-mt", "1",
+		)
+	case software:
+		preset := p.Preset
+		if preset == "" {
+			preset = defaultPreset
+		}
+		args = append(args, "-preset", preset)
+	case hwAccel != TranscodeHWAccelVAAPI && p.Preset != "":
+		args = append(args, "-preset", p.Preset)
+	}
+
+	return append(args, p.ExtraArgs...)
+}
+
+// StreamFormat returns the live transcoding stream format of the profile.
+func (p TranscodeProfile) StreamFormat() StreamFormat {
+	ret := StreamFormat{
+		codec:   p.Encoder(),
+		profile: &p,
+	}
+
+	switch p.Container {
+	case TranscodeContainerWebm:
+		ret.MimeType = MimeWebm
+		ret.format = FormatWebm
+	case TranscodeContainerMpegTS:
+		ret.MimeType = MimeMpegts
+		ret.format = FormatMpegTS
+		ret.extraArgs = []string{"-acodec", "aac"}
+		ret.hls = true
+	default:
+		ret.MimeType = MimeMp4
+		ret.format = FormatMP4
+		ret.extraArgs = []string{"-movflags", "frag_keyframe+empty_moov"}
+	}
+
+	return ret
+}
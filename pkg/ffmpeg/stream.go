@@ -49,6 +49,29 @@ type StreamFormat struct {
 	format    Format
 	extraArgs []string
 	hls       bool
+
+	// profile is set if the stream format was created from a transcode profile
+	profile *TranscodeProfile
+}
+
+// Container returns the transcode profile container that can be used in
+// place of the stream format. Returns an empty container if the stream
+// format cannot be replaced by a transcode profile.
+func (f StreamFormat) Container() TranscodeContainer {
+	if f.codec == VideoCodecCopy {
+		return ""
+	}
+
+	switch f.format {
+	case FormatMP4:
+		return TranscodeContainerMP4
+	case FormatWebm:
+		return TranscodeContainerWebm
+	case FormatMpegTS:
+		return TranscodeContainerMpegTS
+	}
+
+	return ""
 }
 
 var (
@@ -144,6 +167,24 @@ type TranscodeStreamOptions struct {
 	// in some videos where the audio codec is not supported by ffmpeg
 	// ffmpeg fails if you try to transcode the audio
 	VideoOnly bool
+
+	// Subtitles is burnt into the video if set. Ignored when copying the
+	// video stream.
+	Subtitles *SubtitleBurnIn
+}
+
+// outputResolution returns the smaller of the output width and height.
+func (o TranscodeStreamOptions) outputResolution() int {
+	ret := o.VideoHeight
+	if o.VideoWidth < ret {
+		ret = o.VideoWidth
+	}
+
+	if o.MaxTranscodeSize != 0 && o.MaxTranscodeSize < ret {
+		ret = o.MaxTranscodeSize
+	}
+
+	return ret
 }
 
 func (o TranscodeStreamOptions) getStreamArgs() Args {
@@ -160,6 +201,10 @@ func (o TranscodeStreamOptions) getStreamArgs() Args {
 		args = args.Duration(hlsSegmentLength)
 	}
 
+	if o.Codec.profile != nil {
+		args = args.AppendArgs(o.Codec.profile.InputArgs())
+	}
+
 	args = args.Input(o.Input)
 
 	if o.VideoOnly {
@@ -172,6 +217,12 @@ func (o TranscodeStreamOptions) getStreamArgs() Args {
 	if o.Codec.codec != VideoCodecCopy {
 		var videoFilter VideoFilter
 		videoFilter = videoFilter.ScaleMax(o.VideoWidth, o.VideoHeight, o.MaxTranscodeSize)
+		if o.Subtitles != nil {
+			videoFilter = videoFilter.Subtitles(*o.Subtitles, o.StartTime)
+		}
+		if o.Codec.profile != nil {
+			videoFilter = o.Codec.profile.HWUpload(videoFilter)
+		}
 		args = args.VideoFilter(videoFilter)
 	}
 
@@ -179,6 +230,10 @@ func (o TranscodeStreamOptions) getStreamArgs() Args {
 		args = append(args, o.Codec.extraArgs...)
 	}
 
+	if o.Codec.profile != nil {
+		args = args.AppendArgs(o.Codec.profile.VideoArgs(o.outputResolution()))
+	}
+
 	args = append(args,
 		// this is needed for 5-channel ac3 files
 		"-ac", "2",
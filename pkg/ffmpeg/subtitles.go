@@ -0,0 +1,84 @@
+package ffmpeg
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+var FormatWebVTT Format = "webvtt"
+
+// textSubtitleCodecs are the subtitle codecs that can be converted to WebVTT
+// and burnt in using the subtitles filter.
+var textSubtitleCodecs = []string{"subrip", "srt", "ass", "ssa", "webvtt", "mov_text", "text"}
+
+// IsTextSubtitleCodec returns true if the subtitle codec is text based.
+func IsTextSubtitleCodec(codec string) bool {
+	for _, c := range textSubtitleCodecs {
+		if c == codec {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SubtitleBurnIn is a subtitle stream to burn into a transcoded video.
+type SubtitleBurnIn struct {
+	// Path is the path of the subtitle file, or of the video file for
+	// embedded subtitles
+	Path string
+	// StreamIndex is the index of the stream among the subtitle streams of
+	// the file at Path
+	StreamIndex int
+}
+
+// Subtitles returns a VideoFilter rendering the subtitles onto the video.
+// startTime is the time the input was seeked to, so that the subtitles are
+// rendered at the times of the original video.
+func (f VideoFilter) Subtitles(s SubtitleBurnIn, startTime float64) VideoFilter {
+	subtitles := fmt.Sprintf("subtitles=filename=%s:si=%d", escapeFilterValue(s.Path), s.StreamIndex)
+
+	if startTime == 0 {
+		return f.Append(subtitles)
+	}
+
+	// seeking the input resets the timestamps to 0
+	return f.Append(fmt.Sprintf("setpts=PTS+%f/TB", startTime)).
+		Append(subtitles).
+		Append("setpts=PTS-STARTPTS")
+}
+
+// escapeFilterValue escapes a filter option value, first for the option and
+// then for the filtergraph.
+func escapeFilterValue(v string) string {
+	v = escapeChars(v, `\':`)
+	return escapeChars(v, `\'[],;`)
+}
+
+func escapeChars(v string, chars string) string {
+	var b strings.Builder
+	for _, c := range v {
+		if strings.ContainsRune(chars, c) {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(c)
+	}
+
+	return b.String()
+}
+
+// GetSubtitleVTT converts a text subtitle stream of the input file to
+// WebVTT. streamIndex is the index of the stream among the subtitle streams
+// of the file.
+func (f FFMpeg) GetSubtitleVTT(ctx context.Context, input string, streamIndex int) ([]byte, error) {
+	var args Args
+	args = append(args, "-hide_banner")
+	args = args.LogLevel(LogLevelError)
+	args = args.Input(input)
+	args = append(args, "-map", fmt.Sprintf("0:s:%d", streamIndex))
+	args = args.Format(FormatWebVTT)
+	args = args.Output("pipe:")
+
+	return f.GenerateOutput(ctx, args, nil)
+}
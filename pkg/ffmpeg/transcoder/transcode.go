@@ -6,6 +6,9 @@ type TranscodeOptions struct {
 	OutputPath string
 	Format     ffmpeg.Format
 
+	// InputArgs are added before the input, such as hardware device initialisation
+	InputArgs ffmpeg.Args
+
 	VideoCodec ffmpeg.VideoCodec
 	VideoArgs  ffmpeg.Args
 
@@ -64,6 +67,8 @@ func Transcode(input string, options TranscodeOptions) ffmpeg.Args {
 		args = args.XError()
 	}
 
+	args = args.AppendArgs(options.InputArgs)
+
 	if fastSeek > 0 {
 		args = args.Seek(fastSeek)
 	}
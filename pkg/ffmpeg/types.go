@@ -25,15 +25,33 @@ type FFProbeJSON struct {
 			MinorVersion     string        `json:"minor_version"`
 			Title            string        `json:"title"`
 			Comment          string        `json:"comment"`
+			Description      string        `json:"description"`
+			Artist           string        `json:"artist"`
+			AlbumArtist      string        `json:"album_artist"`
+			Date             string        `json:"date"`
 		} `json:"tags"`
 	} `json:"format"`
-	Streams []FFProbeStream `json:"streams"`
-	Error   struct {
+	Streams  []FFProbeStream  `json:"streams"`
+	Chapters []FFProbeChapter `json:"chapters"`
+	Error    struct {
 		Code   int    `json:"code"`
 		String string `json:"string"`
 	} `json:"error"`
 }
 
+// FFProbeChapter is a JSON representation of a chapter of a container.
+type FFProbeChapter struct {
+	ID        int64  `json:"id"`
+	TimeBase  string `json:"time_base"`
+	Start     int64  `json:"start"`
+	StartTime string `json:"start_time"`
+	End       int64  `json:"end"`
+	EndTime   string `json:"end_time"`
+	Tags      struct {
+		Title string `json:"title"`
+	} `json:"tags"`
+}
+
 // FFProbeStream is a JSON representation of an ffmpeg stream.
 type FFProbeStream struct {
 	AvgFrameRate       string `json:"avg_frame_rate"`
@@ -85,6 +103,7 @@ type FFProbeStream struct {
 		HandlerName  string        `json:"handler_name"`
 		Language     string        `json:"language"`
 		Rotate       string        `json:"rotate"`
+		Title        string        `json:"title"`
 	} `json:"tags"`
 	TimeBase      string `json:"time_base"`
 	Width         int    `json:"width,omitempty"`
@@ -17,6 +17,10 @@ type Cleaner struct {
 	Repository Repository
 
 	Handlers []CleanHandler
+
+	// OfflineDetector is used to avoid cleaning files on storage that is
+	// temporarily unavailable. May be nil.
+	OfflineDetector OfflineDetector
 }
 
 type cleanJob struct {
@@ -312,6 +316,45 @@ func (j *cleanJob) flagFolderForDelete(ctx context.Context, toDelete *deleteSet,
 	return nil
 }
 
+// FindMissing returns the files in paths which no longer exist on disk.
+// Files in zip files and files on offline storage are not returned.
+func (s *Cleaner) FindMissing(ctx context.Context, paths []string) ([]File, error) {
+	const batchSize = 1000
+
+	var ret []File
+	if err := txn.WithReadTxn(ctx, s.Repository, func(ctx context.Context) error {
+		for offset := 0; ; offset += batchSize {
+			if job.IsCancelled(ctx) {
+				return nil
+			}
+
+			files, err := s.Repository.FindAllInPaths(ctx, paths, batchSize, offset)
+			if err != nil {
+				return fmt.Errorf("error querying for files: %w", err)
+			}
+
+			for _, f := range files {
+				path := f.Base().Path
+				if f.Base().ZipFileID != nil {
+					continue
+				}
+
+				if _, err := s.FS.Lstat(path); errors.Is(err, fs.ErrNotExist) && !isOffline(s.OfflineDetector, path) {
+					ret = append(ret, f)
+				}
+			}
+
+			if len(files) != batchSize {
+				return nil
+			}
+		}
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
 func (j *cleanJob) shouldClean(ctx context.Context, f File) bool {
 	path := f.Base().Path
 
@@ -322,6 +365,11 @@ func (j *cleanJob) shouldClean(ctx context.Context, f File) bool {
 	}
 
 	if info == nil {
+		if isOffline(j.OfflineDetector, path) {
+			logger.Warnf("File not found but its path is offline, not cleaning: %q", path)
+			return false
+		}
+
 		// info is nil - file not exist
 		logger.Infof("File not found. Marking to clean: \"%s\"", path)
 		return true
@@ -346,6 +394,11 @@ func (j *cleanJob) shouldCleanFolder(ctx context.Context, f *Folder) bool {
 	}
 
 	if info == nil {
+		if isOffline(j.OfflineDetector, path) {
+			logger.Warnf("Folder not found but its path is offline, not cleaning: %q", path)
+			return false
+		}
+
 		// info is nil - file not exist
 		logger.Infof("Folder not found. Marking to clean: \"%s\"", path)
 		return true
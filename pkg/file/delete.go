@@ -7,6 +7,7 @@ import (
 	"io/fs"
 	"os"
 
+	"github.com/stashapp/stash/pkg/fsutil"
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/txn"
 )
@@ -51,19 +52,32 @@ func (r renamerRemoverImpl) Stat(path string) (fs.FileInfo, error) {
 // be restored to their original state with the Abort method. If the
 // transaction is committed, the marked files are then deleted from the
 // filesystem using the Complete method.
+//
+// Library files marked with the LibraryFiles method are moved to the Holding
+// directory on commit instead of being deleted, if it is set.
 type Deleter struct {
 	RenamerRemover RenamerRemover
+	Holding        *HoldingDir
 	files          []string
+	libraryFiles   []string
 	dirs           []string
 }
 
 func NewDeleter() *Deleter {
 	return &Deleter{
 		RenamerRemover: renamerRemoverImpl{
-			RenameFn:    os.Rename,
-			RemoveFn:    os.Remove,
-			RemoveAllFn: os.RemoveAll,
-			StatFn:      os.Stat,
+			RenameFn: func(oldpath, newpath string) error {
+				return os.Rename(fsutil.LongPath(oldpath), fsutil.LongPath(newpath))
+			},
+			RemoveFn: func(name string) error {
+				return os.Remove(fsutil.LongPath(name))
+			},
+			RemoveAllFn: func(path string) error {
+				return os.RemoveAll(fsutil.LongPath(path))
+			},
+			StatFn: func(path string) (fs.FileInfo, error) {
+				return os.Stat(fsutil.LongPath(path))
+			},
 		},
 	}
 }
@@ -87,6 +101,17 @@ func (d *Deleter) RegisterHooks(ctx context.Context, mgr txn.Manager) {
 // Abort should be called to restore marked files if this function returns an
 // error.
 func (d *Deleter) Files(paths []string) error {
+	return d.markFiles(paths, &d.files)
+}
+
+// LibraryFiles designates files of the library to be deleted. They are
+// marked in the same way as Files, but are moved to the holding directory on
+// commit if it is set.
+func (d *Deleter) LibraryFiles(paths []string) error {
+	return d.markFiles(paths, &d.libraryFiles)
+}
+
+func (d *Deleter) markFiles(paths []string, marked *[]string) error {
 	for _, p := range paths {
 		// fail silently if the file does not exist
 		if _, err := d.RenamerRemover.Stat(p); err != nil {
@@ -101,7 +126,7 @@ func (d *Deleter) Files(paths []string) error {
 		if err := d.renameForDelete(p); err != nil {
 			return fmt.Errorf("marking file %q for deletion: %w", p, err)
 		}
-		d.files = append(d.files, p)
+		*marked = append(*marked, p)
 	}
 
 	return nil
@@ -137,13 +162,19 @@ func (d *Deleter) Dirs(paths []string) error {
 // original names and clears the marked list. Any errors encountered are
 // logged. All files will be attempted regardless of any errors occurred.
 func (d *Deleter) Rollback() {
-	for _, f := range append(d.files, d.dirs...) {
+	var marked []string
+	marked = append(marked, d.files...)
+	marked = append(marked, d.libraryFiles...)
+	marked = append(marked, d.dirs...)
+
+	for _, f := range marked {
 		if err := d.renameForRestore(f); err != nil {
 			logger.Warnf("Error restoring %q: %v", f, err)
 		}
 	}
 
 	d.files = nil
+	d.libraryFiles = nil
 	d.dirs = nil
 }
 
@@ -163,10 +194,49 @@ func (d *Deleter) Commit() {
 		}
 	}
 
+	d.commitLibraryFiles()
+
 	d.files = nil
+	d.libraryFiles = nil
 	d.dirs = nil
 }
 
+// commitLibraryFiles moves the marked library files to the holding
+// directory, or deletes them if it is not set. Files which cannot be moved
+// are left in place with the deletion suffix.
+func (d *Deleter) commitLibraryFiles() {
+	if d.Holding == nil {
+		for _, f := range d.libraryFiles {
+			if err := d.RenamerRemover.Remove(f + deleteFileSuffix); err != nil {
+				logger.Warnf("Error deleting file %q: %v", f+deleteFileSuffix, err)
+			}
+		}
+		return
+	}
+
+	if len(d.libraryFiles) == 0 {
+		return
+	}
+
+	for _, f := range d.libraryFiles {
+		dest, err := d.Holding.hold(f+deleteFileSuffix, f)
+		if err != nil {
+			logger.Warnf("Error moving file %q to holding directory: %v", f+deleteFileSuffix, err)
+			continue
+		}
+		logger.Infof("Moved deleted file %q to %q", f, dest)
+	}
+
+	if d.Holding.MaxSize > 0 {
+		deleted, _, err := d.Holding.Purge(d.Holding.MaxSize)
+		if err != nil {
+			logger.Warnf("Error purging holding directory: %v", err)
+		} else if deleted > 0 {
+			logger.Infof("Purged %d files from holding directory to stay within its maximum size", deleted)
+		}
+	}
+}
+
 func (d *Deleter) renameForDelete(path string) error {
 	return d.RenamerRemover.Rename(path, path+deleteFileSuffix)
 }
@@ -182,7 +252,7 @@ func Destroy(ctx context.Context, destroyer Destroyer, f File, fileDeleter *Dele
 
 	// don't delete files in zip files
 	if deleteFile && f.Base().ZipFileID == nil {
-		if err := fileDeleter.Files([]string{f.Base().Path}); err != nil {
+		if err := fileDeleter.LibraryFiles([]string{f.Base().Path}); err != nil {
 			return err
 		}
 	}
@@ -225,7 +295,7 @@ func (d *ZipDestroyer) DestroyZip(ctx context.Context, f File, fileDeleter *Dele
 	}
 
 	if deleteFile {
-		if err := fileDeleter.Files([]string{f.Base().Path}); err != nil {
+		if err := fileDeleter.LibraryFiles([]string{f.Base().Path}); err != nil {
 			return err
 		}
 	}
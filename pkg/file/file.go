@@ -156,13 +156,31 @@ type Finder interface {
 type Getter interface {
 	Finder
 	FindByPath(ctx context.Context, path string) (File, error)
+	// FindByPathCaseInsensitive returns the file with the given path,
+	// ignoring case.
+	FindByPathCaseInsensitive(ctx context.Context, path string) (File, error)
 	FindByFingerprint(ctx context.Context, fp Fingerprint) ([]File, error)
 	FindByZipFileID(ctx context.Context, zipFileID ID) ([]File, error)
 	FindAllInPaths(ctx context.Context, p []string, limit, offset int) ([]File, error)
 }
 
+// PathStats are the statistics of the files within a path.
+type PathStats struct {
+	SceneCount     int
+	ScenesSize     float64
+	ScenesDuration float64
+	ImageCount     int
+	ImagesSize     float64
+	// RecentSize is the size of the files created since a given time.
+	RecentSize float64
+}
+
 type Counter interface {
 	CountAllInPaths(ctx context.Context, p []string) (int, error)
+	// PathStats returns the statistics of the scene and image files within
+	// the path. RecentSize is the size of all files within the path created
+	// since the provided time.
+	PathStats(ctx context.Context, p string, since time.Time) (*PathStats, error)
 }
 
 // Creator provides methods to create Files.
@@ -33,6 +33,9 @@ func (f *Folder) Info(fs FS) (fs.FileInfo, error) {
 // FolderGetter provides methods to find Folders.
 type FolderGetter interface {
 	FindByPath(ctx context.Context, path string) (*Folder, error)
+	// FindByPathCaseInsensitive returns the folder with the given path,
+	// ignoring case.
+	FindByPathCaseInsensitive(ctx context.Context, path string) (*Folder, error)
 	FindByZipFileID(ctx context.Context, zipFileID ID) ([]*Folder, error)
 	FindAllInPaths(ctx context.Context, p []string, limit, offset int) ([]*Folder, error)
 	FindByParentFolderID(ctx context.Context, parentFolderID FolderID) ([]*Folder, error)
@@ -34,16 +34,20 @@ type FS interface {
 // OsFS is a file system backed by the OS.
 type OsFS struct{}
 
+// The OsFS methods access files using fsutil.LongPath, so that files with
+// long paths, reserved names or trailing dots or spaces can be scanned on
+// Windows.
+
 func (f *OsFS) Stat(name string) (fs.FileInfo, error) {
-	return os.Stat(name)
+	return os.Stat(fsutil.LongPath(name))
 }
 
 func (f *OsFS) Lstat(name string) (fs.FileInfo, error) {
-	return os.Lstat(name)
+	return os.Lstat(fsutil.LongPath(name))
 }
 
 func (f *OsFS) Open(name string) (fs.ReadDirFile, error) {
-	return os.Open(name)
+	return os.Open(fsutil.LongPath(name))
 }
 
 func (f *OsFS) OpenZip(name string) (*ZipFS, error) {
@@ -0,0 +1,154 @@
+package file
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+// HoldingDir is a directory that files deleted from the library are moved to
+// instead of being deleted, so that they can be recovered until purged.
+type HoldingDir struct {
+	Path string
+	// PreserveStructure moves files to their original path relative to Path,
+	// instead of directly into Path.
+	PreserveStructure bool
+	// MaxSize is the maximum total size of the held files in bytes. The oldest
+	// files are purged when it is exceeded. Unlimited if zero.
+	MaxSize int64
+}
+
+// destination returns the path in the holding directory of the file with
+// the provided original path.
+func (h HoldingDir) destination(path string) string {
+	if !h.PreserveStructure {
+		return filepath.Join(h.Path, filepath.Base(path))
+	}
+
+	// the volume name is kept as a directory, so that files from different
+	// drives or shares do not clash
+	vol := filepath.VolumeName(path)
+	rel := strings.TrimPrefix(path, vol)
+	vol = strings.Trim(strings.ReplaceAll(vol, ":", ""), `\/`)
+
+	return filepath.Join(h.Path, vol, rel)
+}
+
+// hold moves src to the holding directory, using the original path of the
+// file to determine its destination. A number is appended to the name if a
+// file with the same name is already held.
+func (h HoldingDir) hold(src, path string) (string, error) {
+	dest := h.destination(path)
+	if err := fsutil.EnsureDirAll(filepath.Dir(dest)); err != nil {
+		return "", err
+	}
+
+	ext := filepath.Ext(dest)
+	base := strings.TrimSuffix(dest, ext)
+	for i := 1; ; i++ {
+		if _, err := os.Stat(dest); err != nil {
+			if !errors.Is(err, fs.ErrNotExist) {
+				return "", err
+			}
+			break
+		}
+		dest = fmt.Sprintf("%s_%d%s", base, i, ext)
+	}
+
+	if err := fsutil.SafeMove(src, dest); err != nil {
+		return "", err
+	}
+
+	// files are purged in the order that they were deleted
+	now := time.Now()
+	if err := os.Chtimes(dest, now, now); err != nil {
+		logger.Warnf("Error setting modification time of %q: %v", dest, err)
+	}
+
+	return dest, nil
+}
+
+type heldFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// Purge permanently deletes the oldest held files until the total size of
+// the held files is at most maxSize. All files are deleted if maxSize is
+// zero. Returns the number of deleted files and the number of bytes freed.
+func (h HoldingDir) Purge(maxSize int64) (int, int64, error) {
+	var files []heldFile
+	var dirs []string
+	var total int64
+
+	err := filepath.WalkDir(h.Path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if path != h.Path {
+				dirs = append(dirs, path)
+			}
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		files = append(files, heldFile{
+			path:    path,
+			size:    info.Size(),
+			modTime: info.ModTime(),
+		})
+		total += info.Size()
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return 0, 0, nil
+		}
+		return 0, 0, err
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.Before(files[j].modTime)
+	})
+
+	var deleted int
+	var freed int64
+	for _, f := range files {
+		if total-freed <= maxSize && maxSize > 0 {
+			break
+		}
+
+		if err := os.Remove(f.path); err != nil {
+			return deleted, freed, fmt.Errorf("deleting %q: %w", f.path, err)
+		}
+
+		logger.Debugf("Purged held file %q", f.path)
+		deleted++
+		freed += f.size
+	}
+
+	// remove the directories left empty, deepest first
+	for i := len(dirs) - 1; i >= 0; i-- {
+		entries, err := os.ReadDir(dirs[i])
+		if err == nil && len(entries) == 0 {
+			_ = os.Remove(dirs[i])
+		}
+	}
+
+	return deleted, freed, nil
+}
@@ -0,0 +1,140 @@
+package file
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeHoldingTestFile(t *testing.T, path string, size int, modTime time.Time) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, make([]byte, size), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDeleterLibraryFiles(t *testing.T) {
+	tests := []struct {
+		name              string
+		preserveStructure bool
+		existing          bool
+		// wantName is the name of the held file, if the structure is not
+		// preserved
+		wantName string
+	}{
+		{"flat", false, false, "a.mp4"},
+		{"preserve structure", true, false, ""},
+		{"name clash", false, true, "a_1.mp4"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lib := filepath.Join(t.TempDir(), "library")
+			holding := filepath.Join(t.TempDir(), "holding")
+			path := filepath.Join(lib, "sub", "a.mp4")
+			writeHoldingTestFile(t, path, 1, time.Now())
+			if tt.existing {
+				writeHoldingTestFile(t, filepath.Join(holding, "a.mp4"), 1, time.Now())
+			}
+
+			d := NewDeleter()
+			d.Holding = &HoldingDir{Path: holding, PreserveStructure: tt.preserveStructure}
+			if err := d.LibraryFiles([]string{path}); err != nil {
+				t.Fatalf("LibraryFiles() error = %v", err)
+			}
+			d.Commit()
+
+			if _, err := os.Stat(path); !os.IsNotExist(err) {
+				t.Errorf("file still exists at %q", path)
+			}
+
+			dest := filepath.Join(holding, tt.wantName)
+			if tt.preserveStructure {
+				dest = filepath.Join(holding, strings.TrimPrefix(path, filepath.VolumeName(path)))
+			}
+			if _, err := os.Stat(dest); err != nil {
+				t.Errorf("file not held at %q: %v", dest, err)
+			}
+		})
+	}
+}
+
+func TestDeleterLibraryFilesRollback(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.mp4")
+	holding := t.TempDir()
+	writeHoldingTestFile(t, path, 1, time.Now())
+
+	d := NewDeleter()
+	d.Holding = &HoldingDir{Path: holding}
+	if err := d.LibraryFiles([]string{path}); err != nil {
+		t.Fatalf("LibraryFiles() error = %v", err)
+	}
+	d.Rollback()
+
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("file not restored: %v", err)
+	}
+}
+
+func TestHoldingDirPurge(t *testing.T) {
+	now := time.Now()
+	files := []struct {
+		name string
+		age  time.Duration
+	}{
+		{"old.mp4", 3 * time.Hour},
+		{filepath.Join("sub", "middle.mp4"), 2 * time.Hour},
+		{"new.mp4", time.Hour},
+	}
+
+	tests := []struct {
+		name        string
+		maxSize     int64
+		wantDeleted int
+		wantKept    []string
+	}{
+		{"all", 0, 3, nil},
+		{"within size", 30, 0, []string{"old.mp4", filepath.Join("sub", "middle.mp4"), "new.mp4"}},
+		{"oldest first", 15, 2, []string{"new.mp4"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := HoldingDir{Path: t.TempDir()}
+			for _, f := range files {
+				writeHoldingTestFile(t, filepath.Join(h.Path, f.name), 10, now.Add(-f.age))
+			}
+
+			deleted, freed, err := h.Purge(tt.maxSize)
+			if err != nil {
+				t.Fatalf("Purge() error = %v", err)
+			}
+			if deleted != tt.wantDeleted {
+				t.Errorf("Purge() deleted = %d, want %d", deleted, tt.wantDeleted)
+			}
+			if freed != int64(tt.wantDeleted*10) {
+				t.Errorf("Purge() freed = %d, want %d", freed, tt.wantDeleted*10)
+			}
+
+			for _, f := range tt.wantKept {
+				if _, err := os.Stat(filepath.Join(h.Path, f)); err != nil {
+					t.Errorf("file %q was purged", f)
+				}
+			}
+
+			// empty directories are removed
+			if len(tt.wantKept) < len(files) {
+				if _, err := os.Stat(filepath.Join(h.Path, "sub")); !os.IsNotExist(err) {
+					t.Errorf("empty directory was not removed")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,417 @@
+package file
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+// MoverFS provides the filesystem functions used to move files.
+type MoverFS interface {
+	Move(oldpath, newpath string) error
+	Mkdir(name string, perm fs.FileMode) error
+	Remove(name string) error
+	Stat(name string) (fs.FileInfo, error)
+}
+
+type moverFSImpl struct{}
+
+func (moverFSImpl) Move(oldpath, newpath string) error {
+	return fsutil.SafeMove(oldpath, newpath)
+}
+
+func (moverFSImpl) Mkdir(name string, perm fs.FileMode) error {
+	return os.Mkdir(fsutil.LongPath(name), perm)
+}
+
+func (moverFSImpl) Remove(name string) error {
+	return os.Remove(fsutil.LongPath(name))
+}
+
+func (moverFSImpl) Stat(name string) (fs.FileInfo, error) {
+	return os.Stat(fsutil.LongPath(name))
+}
+
+// MoverFileStore provides the methods used to find, create and update moved
+// files.
+type MoverFileStore interface {
+	FindByPath(ctx context.Context, path string) (File, error)
+	Creator
+	Updater
+}
+
+// MoverFolderStore provides the methods used to find and create the
+// folders that files are moved to, and to update the folders of moved zip
+// files.
+type MoverFolderStore interface {
+	FindByPath(ctx context.Context, path string) (*Folder, error)
+	FindByZipFileID(ctx context.Context, zipFileID ID) ([]*Folder, error)
+	FolderCreator
+	FolderUpdater
+}
+
+type movedFile struct {
+	from string
+	to   string
+}
+
+// Mover is used to move files on the filesystem, updating their folder and
+// basename. During a transaction, files are moved using the Move method,
+// creating destination directories as needed. If the transaction is rolled
+// back, then the files can be moved back and the created directories
+// removed with the Rollback method. If the transaction is committed, Commit
+// clears the moved list.
+type Mover struct {
+	FS      MoverFS
+	Files   MoverFileStore
+	Folders MoverFolderStore
+
+	moved       []movedFile
+	createdDirs []string
+}
+
+func NewMover(fileStore MoverFileStore, folderStore MoverFolderStore) *Mover {
+	return &Mover{
+		FS:      moverFSImpl{},
+		Files:   fileStore,
+		Folders: folderStore,
+	}
+}
+
+// Move moves the file to the destination path and updates the file to be
+// in the destination folder, which is created if it does not exist. An
+// error is returned if the destination already exists. Files inside zip
+// files cannot be moved. The folders of the contents of moved zip files are
+// updated to be in the new zip path.
+func (m *Mover) Move(ctx context.Context, f File, dest string) error {
+	base := f.Base()
+	dest = filepath.Clean(dest)
+
+	if dest == base.Path {
+		return nil
+	}
+
+	if base.ZipFileID != nil {
+		return fmt.Errorf("cannot move %q: file is inside a zip file", base.Path)
+	}
+
+	if err := m.checkDestination(ctx, dest); err != nil {
+		return err
+	}
+
+	folder, err := m.ensureFolder(ctx, filepath.Dir(dest))
+	if err != nil {
+		return err
+	}
+
+	if err := m.FS.Move(base.Path, dest); err != nil {
+		return fmt.Errorf("moving %q to %q: %w", base.Path, dest, err)
+	}
+	m.moved = append(m.moved, movedFile{from: base.Path, to: dest})
+
+	logger.Infof("Moved %q to %q", base.Path, dest)
+
+	if err := m.moveZipFolders(ctx, base.ID, base.Path, dest, folder.ID); err != nil {
+		return err
+	}
+
+	base.ParentFolderID = folder.ID
+	base.Basename = filepath.Base(dest)
+	base.Path = dest
+	base.UpdatedAt = time.Now()
+
+	if err := m.Files.Update(ctx, f); err != nil {
+		return fmt.Errorf("updating file %q: %w", dest, err)
+	}
+
+	return nil
+}
+
+// MoveFile moves the file at src to dest on the filesystem without updating
+// the database, creating the destination directory if it does not exist. It
+// is used to move files out of the library, such as the files of deleted
+// scenes. An error is returned if the destination already exists.
+func (m *Mover) MoveFile(src string, dest string) error {
+	dest = filepath.Clean(dest)
+
+	if _, err := m.FS.Stat(dest); err == nil {
+		return fmt.Errorf("destination %q already exists", dest)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("checking destination %q: %w", dest, err)
+	}
+
+	if err := m.ensureDir(filepath.Dir(dest)); err != nil {
+		return err
+	}
+
+	if err := m.FS.Move(src, dest); err != nil {
+		return fmt.Errorf("moving %q to %q: %w", src, dest, err)
+	}
+	m.moved = append(m.moved, movedFile{from: src, to: dest})
+
+	logger.Infof("Moved %q to %q", src, dest)
+	return nil
+}
+
+// Create moves the file at src, which is not in the database, to the path
+// of f and creates f in the destination folder, which is created if it does
+// not exist. It is used to move files moved out of the library with MoveFile
+// back into it. An error is returned if the destination already exists.
+func (m *Mover) Create(ctx context.Context, f File, src string) error {
+	base := f.Base()
+	base.Path = filepath.Clean(base.Path)
+
+	if err := m.checkDestination(ctx, base.Path); err != nil {
+		return err
+	}
+
+	folder, err := m.ensureFolder(ctx, filepath.Dir(base.Path))
+	if err != nil {
+		return err
+	}
+
+	if err := m.FS.Move(src, base.Path); err != nil {
+		return fmt.Errorf("moving %q to %q: %w", src, base.Path, err)
+	}
+	m.moved = append(m.moved, movedFile{from: src, to: base.Path})
+
+	logger.Infof("Moved %q to %q", src, base.Path)
+
+	base.ParentFolderID = folder.ID
+	base.Basename = filepath.Base(base.Path)
+	base.UpdatedAt = time.Now()
+
+	if err := m.Files.Create(ctx, f); err != nil {
+		return fmt.Errorf("creating file %q: %w", base.Path, err)
+	}
+
+	return nil
+}
+
+// moveZipFolders updates the paths of the folders inside the zip file to be
+// in the new zip path. The folder of the zip file root is moved to the new
+// parent folder.
+func (m *Mover) moveZipFolders(ctx context.Context, zipFileID ID, oldPath string, newPath string, parentFolderID FolderID) error {
+	folders, err := m.Folders.FindByZipFileID(ctx, zipFileID)
+	if err != nil {
+		return fmt.Errorf("finding zip file folders for %q: %w", oldPath, err)
+	}
+
+	for _, f := range folders {
+		rel, err := filepath.Rel(oldPath, f.Path)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			return fmt.Errorf("zip file folder %q is not in %q", f.Path, oldPath)
+		}
+
+		if rel == "." {
+			f.ParentFolderID = &parentFolderID
+		}
+		f.Path = filepath.Join(newPath, rel)
+		f.UpdatedAt = time.Now()
+
+		if err := m.Folders.Update(ctx, f); err != nil {
+			return fmt.Errorf("updating folder %q: %w", f.Path, err)
+		}
+	}
+
+	return nil
+}
+
+func (m *Mover) checkDestination(ctx context.Context, dest string) error {
+	if _, err := m.FS.Stat(dest); err == nil {
+		return fmt.Errorf("destination %q already exists", dest)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("checking destination %q: %w", dest, err)
+	}
+
+	existing, err := m.Files.FindByPath(ctx, dest)
+	if err != nil {
+		return fmt.Errorf("finding file %q: %w", dest, err)
+	}
+	if existing != nil {
+		return fmt.Errorf("destination %q already exists", dest)
+	}
+
+	return nil
+}
+
+// ensureFolder returns the folder with the path, creating the directory
+// and folder if they do not exist.
+func (m *Mover) ensureFolder(ctx context.Context, dir string) (*Folder, error) {
+	existing, err := m.Folders.FindByPath(ctx, dir)
+	if err != nil {
+		return nil, fmt.Errorf("finding folder %q: %w", dir, err)
+	}
+	if existing != nil {
+		return existing, nil
+	}
+
+	info, err := m.FS.Stat(dir)
+	create := errors.Is(err, fs.ErrNotExist)
+	switch {
+	case create:
+	case err != nil:
+		return nil, fmt.Errorf("checking directory %q: %w", dir, err)
+	case !info.IsDir():
+		return nil, fmt.Errorf("%q is not a directory", dir)
+	}
+
+	var parentFolderID *FolderID
+	if parentDir := filepath.Dir(dir); parentDir != dir {
+		parent, err := m.Folders.FindByPath(ctx, parentDir)
+		if err != nil {
+			return nil, fmt.Errorf("finding folder %q: %w", parentDir, err)
+		}
+
+		// as when scanning, assume that an existing directory without a
+		// parent folder is a top-level folder
+		if parent == nil && create {
+			parent, err = m.ensureFolder(ctx, parentDir)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if parent != nil {
+			parentFolderID = &parent.ID
+		}
+	}
+
+	if create {
+		if err := m.FS.Mkdir(dir, 0755); err != nil {
+			return nil, fmt.Errorf("creating directory %q: %w", dir, err)
+		}
+		m.createdDirs = append(m.createdDirs, dir)
+
+		info, err = m.FS.Stat(dir)
+		if err != nil {
+			return nil, fmt.Errorf("checking directory %q: %w", dir, err)
+		}
+	}
+
+	now := time.Now()
+	ret := &Folder{
+		DirEntry: DirEntry{
+			ModTime: info.ModTime(),
+		},
+		Path:           dir,
+		ParentFolderID: parentFolderID,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	if err := m.Folders.Create(ctx, ret); err != nil {
+		return nil, fmt.Errorf("creating folder %q: %w", dir, err)
+	}
+
+	return ret, nil
+}
+
+// ensureDir creates the directory and its missing parents on the
+// filesystem, without creating folders in the database.
+func (m *Mover) ensureDir(dir string) error {
+	info, err := m.FS.Stat(dir)
+	switch {
+	case err == nil:
+		if !info.IsDir() {
+			return fmt.Errorf("%q is not a directory", dir)
+		}
+		return nil
+	case !errors.Is(err, fs.ErrNotExist):
+		return fmt.Errorf("checking directory %q: %w", dir, err)
+	}
+
+	if parentDir := filepath.Dir(dir); parentDir != dir {
+		if err := m.ensureDir(parentDir); err != nil {
+			return err
+		}
+	}
+
+	if err := m.FS.Mkdir(dir, 0755); err != nil {
+		return fmt.Errorf("creating directory %q: %w", dir, err)
+	}
+	m.createdDirs = append(m.createdDirs, dir)
+
+	return nil
+}
+
+// Rollback tries to move all moved files back to their original paths and
+// to remove the created directories, then clears the moved list. Any errors
+// encountered are logged. All files will be attempted regardless of any
+// errors occurred.
+func (m *Mover) Rollback() {
+	for i := len(m.moved) - 1; i >= 0; i-- {
+		mf := m.moved[i]
+		if err := m.FS.Move(mf.to, mf.from); err != nil {
+			logger.Warnf("Error moving %q back to %q: %v", mf.to, mf.from, err)
+		}
+	}
+
+	// remove the deepest directories first
+	for i := len(m.createdDirs) - 1; i >= 0; i-- {
+		if err := m.FS.Remove(m.createdDirs[i]); err != nil {
+			logger.Warnf("Error removing directory %q: %v", m.createdDirs[i], err)
+		}
+	}
+
+	m.moved = nil
+	m.createdDirs = nil
+}
+
+// Commit clears the moved list.
+func (m *Mover) Commit() {
+	m.moved = nil
+	m.createdDirs = nil
+}
+
+var renamePatternTokenRE = regexp.MustCompile(`\{([a-z_]+)\}`)
+
+// replaced in token values, since they are not valid in file names
+var invalidPathCharsRE = regexp.MustCompile(`[\\/:*?"<>|]`)
+
+// ExpandRenamePattern returns the relative path produced by replacing the
+// {token} placeholders of the pattern with the values. Slashes in the
+// pattern separate directories, and characters that are not valid in file
+// names are replaced in the values. Empty directory names are removed.
+// Returns an error if the pattern contains an unknown token or produces an
+// empty file name.
+func ExpandRenamePattern(pattern string, values map[string]string) (string, error) {
+	var tokenErr error
+	expanded := renamePatternTokenRE.ReplaceAllStringFunc(pattern, func(s string) string {
+		token := s[1 : len(s)-1]
+		v, ok := values[token]
+		if !ok {
+			tokenErr = fmt.Errorf("unknown token %q", s)
+			return ""
+		}
+		return invalidPathCharsRE.ReplaceAllString(v, "-")
+	})
+	if tokenErr != nil {
+		return "", tokenErr
+	}
+
+	var parts []string
+	for _, p := range strings.Split(filepath.ToSlash(expanded), "/") {
+		// trailing dots and spaces are stripped by Windows
+		p = strings.TrimRight(strings.TrimSpace(p), ". ")
+		if p == "" {
+			continue
+		}
+		parts = append(parts, p)
+	}
+
+	if len(parts) == 0 {
+		return "", fmt.Errorf("pattern %q produces an empty file name", pattern)
+	}
+
+	return filepath.Join(parts...), nil
+}
@@ -0,0 +1,291 @@
+package file
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type testMoverFileStore struct {
+	byPath  map[string]File
+	failing string
+}
+
+func (s *testMoverFileStore) FindByPath(ctx context.Context, path string) (File, error) {
+	return s.byPath[path], nil
+}
+
+func (s *testMoverFileStore) Create(ctx context.Context, f File) error {
+	if s.byPath == nil {
+		s.byPath = make(map[string]File)
+	}
+	s.byPath[f.Base().Path] = f
+	return nil
+}
+
+func (s *testMoverFileStore) Update(ctx context.Context, f File) error {
+	if f.Base().Basename == s.failing {
+		return errors.New("update failed")
+	}
+	return nil
+}
+
+type testMoverFolderStore struct {
+	byPath map[string]*Folder
+	lastID FolderID
+}
+
+func (s *testMoverFolderStore) FindByPath(ctx context.Context, path string) (*Folder, error) {
+	return s.byPath[path], nil
+}
+
+func (s *testMoverFolderStore) FindByZipFileID(ctx context.Context, zipFileID ID) ([]*Folder, error) {
+	var ret []*Folder
+	for _, f := range s.byPath {
+		if f.ZipFileID != nil && *f.ZipFileID == zipFileID {
+			ret = append(ret, f)
+		}
+	}
+	return ret, nil
+}
+
+func (s *testMoverFolderStore) Update(ctx context.Context, f *Folder) error {
+	return nil
+}
+
+func (s *testMoverFolderStore) Create(ctx context.Context, f *Folder) error {
+	s.lastID++
+	f.ID = s.lastID
+	s.byPath[f.Path] = f
+	return nil
+}
+
+func writeTestFile(t *testing.T, path string) *BaseFile {
+	t.Helper()
+	if err := os.WriteFile(path, []byte("test"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return &BaseFile{
+		ID:       1,
+		Path:     path,
+		Basename: filepath.Base(path),
+	}
+}
+
+func TestMover_Move(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+
+	folders := &testMoverFolderStore{byPath: map[string]*Folder{
+		root: {ID: 1, Path: root},
+	}}
+	folders.lastID = 1
+
+	f := writeTestFile(t, filepath.Join(root, "a.mp4"))
+	m := NewMover(&testMoverFileStore{}, folders)
+
+	dest := filepath.Join(root, "studio", "2023", "b.mp4")
+	if err := m.Move(ctx, f, dest); err != nil {
+		t.Fatalf("Move() error = %v", err)
+	}
+	m.Commit()
+
+	if _, err := os.Stat(dest); err != nil {
+		t.Errorf("destination not moved: %v", err)
+	}
+
+	if f.Path != dest || f.Basename != "b.mp4" {
+		t.Errorf("file path = %q, basename = %q", f.Path, f.Basename)
+	}
+
+	studio := folders.byPath[filepath.Join(root, "studio")]
+	year := folders.byPath[filepath.Join(root, "studio", "2023")]
+	if studio == nil || year == nil {
+		t.Fatalf("destination folders not created")
+	}
+	if studio.ParentFolderID == nil || *studio.ParentFolderID != 1 {
+		t.Errorf("studio folder parent = %v, want 1", studio.ParentFolderID)
+	}
+	if year.ParentFolderID == nil || *year.ParentFolderID != studio.ID {
+		t.Errorf("year folder parent = %v, want %v", year.ParentFolderID, studio.ID)
+	}
+	if f.ParentFolderID != year.ID {
+		t.Errorf("file parent folder = %v, want %v", f.ParentFolderID, year.ID)
+	}
+}
+
+func TestMover_MoveZip(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+
+	zipPath := filepath.Join(root, "a.zip")
+	f := writeTestFile(t, zipPath)
+	zipRoot := &Folder{ID: 2, Path: zipPath, DirEntry: DirEntry{ZipFileID: &f.ID}}
+	zipSub := &Folder{ID: 3, Path: filepath.Join(zipPath, "sub"), DirEntry: DirEntry{ZipFileID: &f.ID}}
+
+	folders := &testMoverFolderStore{byPath: map[string]*Folder{
+		root:         {ID: 1, Path: root},
+		zipRoot.Path: zipRoot,
+		zipSub.Path:  zipSub,
+	}}
+	folders.lastID = 3
+
+	m := NewMover(&testMoverFileStore{}, folders)
+
+	dest := filepath.Join(root, "new", "b.zip")
+	if err := m.Move(ctx, f, dest); err != nil {
+		t.Fatalf("Move() error = %v", err)
+	}
+
+	if zipRoot.Path != dest {
+		t.Errorf("zip root folder path = %q, want %q", zipRoot.Path, dest)
+	}
+	if zipRoot.ParentFolderID == nil || *zipRoot.ParentFolderID != f.ParentFolderID {
+		t.Errorf("zip root folder parent = %v, want %v", zipRoot.ParentFolderID, f.ParentFolderID)
+	}
+	if want := filepath.Join(dest, "sub"); zipSub.Path != want {
+		t.Errorf("zip sub folder path = %q, want %q", zipSub.Path, want)
+	}
+}
+
+func TestMover_MoveExisting(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+
+	folders := &testMoverFolderStore{byPath: map[string]*Folder{
+		root: {ID: 1, Path: root},
+	}}
+
+	f := writeTestFile(t, filepath.Join(root, "a.mp4"))
+	existing := writeTestFile(t, filepath.Join(root, "b.mp4"))
+	m := NewMover(&testMoverFileStore{}, folders)
+
+	if err := m.Move(ctx, f, existing.Path); err == nil {
+		t.Error("Move() expected error for existing destination")
+	}
+}
+
+func TestMover_Rollback(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+
+	folders := &testMoverFolderStore{byPath: map[string]*Folder{
+		root: {ID: 1, Path: root},
+	}}
+	folders.lastID = 1
+
+	f1 := writeTestFile(t, filepath.Join(root, "a.mp4"))
+	f2 := writeTestFile(t, filepath.Join(root, "b.mp4"))
+	m := NewMover(&testMoverFileStore{failing: "d.mp4"}, folders)
+
+	newDir := filepath.Join(root, "new")
+	if err := m.Move(ctx, f1, filepath.Join(newDir, "c.mp4")); err != nil {
+		t.Fatalf("Move() error = %v", err)
+	}
+	if err := m.Move(ctx, f2, filepath.Join(newDir, "d.mp4")); err == nil {
+		t.Fatal("Move() expected error")
+	}
+
+	m.Rollback()
+
+	for _, p := range []string{"a.mp4", "b.mp4"} {
+		if _, err := os.Stat(filepath.Join(root, p)); err != nil {
+			t.Errorf("%s not moved back: %v", p, err)
+		}
+	}
+
+	if _, err := os.Stat(newDir); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("created directory not removed: %v", err)
+	}
+}
+
+func TestMover_MoveFileCreate(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	library := filepath.Join(root, "library")
+	trash := filepath.Join(root, "trash", "1")
+
+	folders := &testMoverFolderStore{byPath: map[string]*Folder{}}
+	files := &testMoverFileStore{}
+
+	if err := os.Mkdir(library, 0755); err != nil {
+		t.Fatal(err)
+	}
+	f := writeTestFile(t, filepath.Join(library, "a.mp4"))
+	original := f.Path
+	trashPath := filepath.Join(trash, "a.mp4")
+
+	m := NewMover(files, folders)
+	if err := m.MoveFile(original, trashPath); err != nil {
+		t.Fatalf("MoveFile() error = %v", err)
+	}
+	m.Commit()
+
+	if _, err := os.Stat(trashPath); err != nil {
+		t.Errorf("file not moved to trash: %v", err)
+	}
+	if len(folders.byPath) > 0 {
+		t.Errorf("folders created for trash directory: %v", folders.byPath)
+	}
+
+	if err := m.Create(ctx, f, trashPath); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	m.Commit()
+
+	if _, err := os.Stat(original); err != nil {
+		t.Errorf("file not moved back: %v", err)
+	}
+	if files.byPath[original] != f {
+		t.Errorf("file not created")
+	}
+	if folder := folders.byPath[library]; folder == nil || f.ParentFolderID != folder.ID {
+		t.Errorf("file parent folder = %v, want %v", f.ParentFolderID, folder)
+	}
+
+	// the file cannot be restored over an existing file
+	f2 := writeTestFile(t, filepath.Join(trash, "b.mp4"))
+	f2.Path = original
+	if err := m.Create(ctx, f2, filepath.Join(trash, "b.mp4")); err == nil {
+		t.Error("Create() expected error")
+	}
+}
+
+func TestExpandRenamePattern(t *testing.T) {
+	values := map[string]string{
+		"title":  "Title: Part 1/2",
+		"studio": "Studio",
+		"date":   "2023-03-15",
+		"empty":  "",
+	}
+
+	tests := []struct {
+		name    string
+		pattern string
+		want    string
+		wantErr bool
+	}{
+		{"tokens", "{studio}/{date} {title}", filepath.Join("Studio", "2023-03-15 Title- Part 1-2"), false},
+		{"empty folder", "{empty}/{title}", "Title- Part 1-2", false},
+		{"parent directory", "../{studio}", "Studio", false},
+		{"trailing dots", "{studio}...", "Studio", false},
+		{"unknown token", "{unknown}", "", true},
+		{"empty", "{empty}", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpandRenamePattern(tt.pattern, values)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ExpandRenamePattern() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ExpandRenamePattern() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
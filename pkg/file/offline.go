@@ -0,0 +1,25 @@
+package file
+
+import "context"
+
+// OfflineDetector detects paths on storage that is temporarily unavailable,
+// such as a network share that has been disconnected.
+type OfflineDetector interface {
+	// IsOffline returns true if the storage containing path is unavailable.
+	IsOffline(path string) bool
+	// WaitOnline blocks until the storage containing path is available, or
+	// until the context is done.
+	WaitOnline(ctx context.Context, path string) error
+}
+
+func isOffline(d OfflineDetector, path string) bool {
+	return d != nil && d.IsOffline(path)
+}
+
+func waitOnline(ctx context.Context, d OfflineDetector, path string) error {
+	if !isOffline(d, path) {
+		return nil
+	}
+
+	return d.WaitOnline(ctx, path)
+}
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/remeh/sizedwaitgroup"
+	"github.com/stashapp/stash/pkg/job"
 	"github.com/stashapp/stash/pkg/logger"
 	"github.com/stashapp/stash/pkg/txn"
 )
@@ -64,6 +65,20 @@ type Scanner struct {
 
 	// FileDecorators are applied to files as they are scanned.
 	FileDecorators []Decorator
+
+	// OfflineDetector is used to pause the scan while the storage of the
+	// scanned files is unavailable. May be nil.
+	OfflineDetector OfflineDetector
+
+	// CaseSensitivity determines the case sensitivity of scanned paths. If
+	// nil, paths are treated as case sensitive.
+	CaseSensitivity CaseSensitivityChecker
+}
+
+// CaseSensitivityChecker reports whether paths are on a case sensitive
+// filesystem.
+type CaseSensitivityChecker interface {
+	IsCaseSensitive(path string) bool
 }
 
 // ProgressReporter is used to report progress of the scan.
@@ -109,6 +124,10 @@ type ScanOptions struct {
 	HandlerRequiredFilters []Filter
 
 	ParallelTasks int
+
+	// Items tracks the queued files, so that they can be skipped while
+	// scanning. May be nil.
+	Items *job.ItemQueue
 }
 
 // Scan starts the scanning process.
@@ -131,6 +150,9 @@ type scanFile struct {
 	*BaseFile
 	fs   FS
 	info fs.FileInfo
+
+	// item is the tracked item of a queued file
+	item *job.Item
 }
 
 func (s *scanJob) withTxn(ctx context.Context, fn func(ctx context.Context) error) error {
@@ -200,6 +222,10 @@ func (s *scanJob) queueFiles(ctx context.Context, paths []string) error {
 func (s *scanJob) queueFileFunc(ctx context.Context, f FS, zipFile *scanFile) fs.WalkDirFunc {
 	return func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
+			if zipFile == nil && isOffline(s.OfflineDetector, path) {
+				return s.rewalkWhenOnline(ctx, path)
+			}
+
 			// don't let errors prevent scanning
 			logger.Errorf("error scanning %s: %v", path, err)
 			return nil
@@ -285,6 +311,10 @@ func (s *scanJob) queueFileFunc(ctx context.Context, f FS, zipFile *scanFile) fs
 			return nil
 		}
 
+		if s.options.Items != nil {
+			ff.item = s.options.Items.Track(path)
+		}
+
 		s.fileQueue <- ff
 
 		s.count++
@@ -293,6 +323,24 @@ func (s *scanJob) queueFileFunc(ctx context.Context, f FS, zipFile *scanFile) fs
 	}
 }
 
+// rewalkWhenOnline waits for the storage containing path to come back online
+// and then walks path again, since the entries of path could not be read
+// while it was offline.
+func (s *scanJob) rewalkWhenOnline(ctx context.Context, path string) error {
+	logger.Warnf("%s is offline. Pausing scan until it is available", path)
+	if err := s.OfflineDetector.WaitOnline(ctx, path); err != nil {
+		return err
+	}
+
+	logger.Infof("%s is available. Resuming scan", path)
+
+	err := symWalk(s.FS, path, s.queueFileFunc(ctx, s.FS, nil))
+	if errors.Is(err, fs.SkipDir) {
+		return nil
+	}
+	return err
+}
+
 func (s *scanJob) acceptEntry(ctx context.Context, path string, info fs.FileInfo) bool {
 	// always accept if there's no filters
 	accept := len(s.options.ScanFilters) == 0
@@ -388,12 +436,40 @@ func (s *scanJob) incrementProgress(f scanFile) {
 }
 
 func (s *scanJob) processQueueItem(ctx context.Context, f scanFile) {
+	var err error
+	if f.item != nil {
+		items := s.options.Items
+		itemCtx, started := items.Start(ctx, f.item)
+		if !started {
+			logger.Infof("Skipping %s", f.Path)
+			s.incrementProgress(f)
+			return
+		}
+
+		ctx = itemCtx
+		defer func() {
+			items.Finish(f.item, err)
+		}()
+	}
+
 	s.ProgressReports.ExecuteTask("Scanning "+f.Path, func() {
-		var err error
-		if f.info.IsDir() {
-			err = s.handleFolder(ctx, f)
-		} else {
-			err = s.handleFile(ctx, f)
+		handle := func() error {
+			if err := waitOnline(ctx, s.OfflineDetector, f.Path); err != nil {
+				return err
+			}
+
+			if f.info.IsDir() {
+				return s.handleFolder(ctx, f)
+			}
+			return s.handleFile(ctx, f)
+		}
+
+		err = handle()
+
+		// retry if the storage went offline while handling the file
+		if err != nil && isOffline(s.OfflineDetector, f.Path) {
+			logger.Warnf("%s is offline. Pausing until it is available", f.Path)
+			err = handle()
 		}
 
 		if err != nil && !errors.Is(err, context.Canceled) {
@@ -414,6 +490,13 @@ func (s *scanJob) getFolderID(ctx context.Context, path string) (*FolderID, erro
 		return nil, err
 	}
 
+	if ret == nil && s.isCaseInsensitive(path) {
+		ret, err = s.Repository.FolderStore.FindByPathCaseInsensitive(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if ret == nil {
 		return nil, nil
 	}
@@ -459,7 +542,7 @@ func (s *scanJob) handleFolder(ctx context.Context, file scanFile) error {
 		defer s.incrementProgress(file)
 
 		// determine if folder already exists in data store (by path)
-		f, err := s.Repository.FolderStore.FindByPath(ctx, path)
+		f, err := s.findExistingFolder(ctx, file)
 		if err != nil {
 			return fmt.Errorf("checking for existing folder %q: %w", path, err)
 		}
@@ -483,6 +566,96 @@ func (s *scanJob) handleFolder(ctx context.Context, file scanFile) error {
 	})
 }
 
+// isCaseInsensitive returns true if path is known to be on a case-insensitive
+// filesystem. Paths within zip files are case sensitive.
+func (s *scanJob) isCaseInsensitive(path string) bool {
+	return s.CaseSensitivity != nil && !s.CaseSensitivity.IsCaseSensitive(path)
+}
+
+// isPathCaseSensitive returns true if path is on a case sensitive filesystem.
+func (s *scanJob) isPathCaseSensitive(f FS, path string) (bool, error) {
+	if f == s.FS && s.CaseSensitivity != nil {
+		return s.CaseSensitivity.IsCaseSensitive(path), nil
+	}
+
+	return f.IsPathCaseSensitive(path)
+}
+
+// findExistingFolder returns the existing folder with the path of f. On a
+// case-insensitive filesystem, a folder with a path that differs only by case
+// is the same folder, so its path is updated to match the filesystem rather
+// than creating a duplicate folder. Assumes a transaction is active.
+func (s *scanJob) findExistingFolder(ctx context.Context, f scanFile) (*Folder, error) {
+	ret, err := s.Repository.FolderStore.FindByPath(ctx, f.Path)
+	if err != nil || ret != nil || f.ZipFile != nil || !s.isCaseInsensitive(f.Path) {
+		return ret, err
+	}
+
+	ret, err = s.Repository.FolderStore.FindByPathCaseInsensitive(ctx, f.Path)
+	if err != nil || ret == nil {
+		return ret, err
+	}
+
+	oldPath := ret.Path
+	ret.Path = f.Path
+	ret.UpdatedAt = time.Now()
+	if err := s.Repository.FolderStore.Update(ctx, ret); err != nil {
+		return nil, fmt.Errorf("updating folder path case %q: %w", f.Path, err)
+	}
+
+	txn.AddPostCommitHook(ctx, func(ctx context.Context) error {
+		logger.Infof("Case of folder %s changed to %s. Updating path...", oldPath, f.Path)
+		return nil
+	})
+
+	return ret, nil
+}
+
+// findExistingFile returns the existing file with the path of f. On a
+// case-insensitive filesystem, a file with a path that differs only by case
+// is the same file, so its path is updated to match the filesystem rather
+// than creating a duplicate file.
+func (s *scanJob) findExistingFile(ctx context.Context, f scanFile) (File, error) {
+	ret, err := s.Repository.FindByPath(ctx, f.Path)
+	if err != nil || ret != nil || f.ZipFile != nil || !s.isCaseInsensitive(f.Path) {
+		return ret, err
+	}
+
+	ret, err = s.Repository.FindByPathCaseInsensitive(ctx, f.Path)
+	if err != nil || ret == nil {
+		return ret, err
+	}
+
+	base := ret.Base()
+	oldBase := *base
+
+	parentFolderID, err := s.getFolderID(ctx, filepath.Dir(f.Path))
+	if err != nil {
+		return nil, fmt.Errorf("getting parent folder for %q: %w", f.Path, err)
+	}
+
+	if parentFolderID != nil {
+		base.ParentFolderID = *parentFolderID
+	}
+
+	logger.Infof("Case of %s changed to %s. Updating path...", oldBase.Path, f.Path)
+	base.Path = f.Path
+	base.Basename = f.Basename
+	base.UpdatedAt = time.Now()
+
+	if err := s.withTxn(ctx, func(ctx context.Context) error {
+		if err := s.Repository.Update(ctx, ret); err != nil {
+			return fmt.Errorf("updating file path case %q: %w", f.Path, err)
+		}
+
+		return s.fireHandlers(ctx, ret, &oldBase)
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
 func (s *scanJob) onNewFolder(ctx context.Context, file scanFile) (*Folder, error) {
 	now := time.Now()
 
@@ -552,7 +725,7 @@ func (s *scanJob) handleFile(ctx context.Context, f scanFile) error {
 	if err := s.withDB(ctx, func(ctx context.Context) error {
 		// determine if file already exists in data store
 		var err error
-		ff, err = s.Repository.FindByPath(ctx, f.Path)
+		ff, err = s.findExistingFile(ctx, f)
 		if err != nil {
 			return fmt.Errorf("checking for existing file %q: %w", f.Path, err)
 		}
@@ -762,12 +935,16 @@ func (s *scanJob) handleRename(ctx context.Context, f File, fp []Fingerprint) (F
 		}
 
 		if _, err := fs.Lstat(other.Base().Path); err != nil {
+			// files on offline storage are not missing
+			if isOffline(s.OfflineDetector, other.Base().Path) {
+				continue
+			}
 			missing = append(missing, other)
 		} else if strings.EqualFold(f.Base().Path, other.Base().Path) {
 			// #1426 - if file exists but is a case-insensitive match for the
 			// original filename, and the filesystem is case-insensitive
 			// then treat it as a move
-			if caseSensitive, _ := fs.IsPathCaseSensitive(other.Base().Path); !caseSensitive {
+			if caseSensitive, _ := s.isPathCaseSensitive(fs, other.Base().Path); !caseSensitive {
 				// treat as a move
 				missing = append(missing, other)
 			}
@@ -831,6 +1008,7 @@ func (s *scanJob) isHandlerRequired(ctx context.Context, f File) bool {
 // - file size
 // - image format, width or height
 // - video codec, audio codec, format, width, height, framerate or bitrate
+// - video chapters, if a chapter file has changed since the last update
 func (s *scanJob) isMissingMetadata(ctx context.Context, f scanFile, existing File) bool {
 	for _, h := range s.FileDecorators {
 		if h.IsMissingMetadata(ctx, f.fs, existing) {
@@ -846,6 +1024,7 @@ func (s *scanJob) setMissingMetadata(ctx context.Context, f scanFile, existing F
 	logger.Infof("Updating metadata for %s", path)
 
 	existing.Base().Size = f.Size
+	existing.Base().UpdatedAt = time.Now()
 
 	var err error
 	existing, err = s.fireDecorators(ctx, f.fs, existing)
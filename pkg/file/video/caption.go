@@ -16,7 +16,7 @@ import (
 	"golang.org/x/text/language"
 )
 
-var CaptionExts = []string{"vtt", "srt"} // in a case where vtt and srt files are both provided prioritize vtt file due to native support
+var CaptionExts = []string{"vtt", "srt", "ass", "ssa"} // in a case where vtt and srt files are both provided prioritize vtt file due to native support
 
 // to be used for captions without a language code in the filename
 // ISO 639-1 uses 2 or 3 a-z chars for codes so 00 is a safe non valid choise
@@ -0,0 +1,241 @@
+package video
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/file"
+)
+
+const (
+	// ChaptersJSONExtension is the extension of JSON chapter files.
+	ChaptersJSONExtension = ".chapters.json"
+	// FFMetadataExtension is the extension of ffmpeg metadata chapter files.
+	FFMetadataExtension = ".ffmetadata"
+
+	ffmetadataHeader = ";FFMETADATA1"
+)
+
+// GetChaptersPaths returns the paths of the chapter files that may accompany
+// a file, in order of preference.
+func GetChaptersPaths(path string) []string {
+	ext := filepath.Ext(path)
+	fn := strings.TrimSuffix(path, ext)
+	return []string{fn + ChaptersJSONExtension, fn + FFMetadataExtension}
+}
+
+// ReadChaptersFile reads the first chapter file that accompanies the file at
+// path. Returns nil if there is no chapter file.
+func ReadChaptersFile(fs file.FS, path string) ([]file.VideoChapter, error) {
+	for _, p := range GetChaptersPaths(path) {
+		if _, err := fs.Lstat(p); err != nil {
+			continue
+		}
+
+		chapters, err := readChaptersFile(fs, p)
+		if err != nil {
+			return nil, fmt.Errorf("reading chapters from %q: %w", p, err)
+		}
+
+		return chapters, nil
+	}
+
+	return nil, nil
+}
+
+func readChaptersFile(fs file.FS, path string) ([]file.VideoChapter, error) {
+	f, err := fs.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if strings.HasSuffix(path, ChaptersJSONExtension) {
+		return ParseChaptersJSON(f)
+	}
+
+	return ParseFFMetadata(f)
+}
+
+type chaptersJSON struct {
+	Chapters []struct {
+		Title string  `json:"title"`
+		Start float64 `json:"start"`
+	} `json:"chapters"`
+}
+
+// ParseChaptersJSON parses a JSON chapter file of the form
+// {"chapters": [{"title": "Intro", "start": 0}, ...]}, where start is in
+// seconds. The returned chapters are ordered by start time.
+func ParseChaptersJSON(r io.Reader) ([]file.VideoChapter, error) {
+	var data chaptersJSON
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return nil, err
+	}
+
+	ret := []file.VideoChapter{}
+	for _, c := range data.Chapters {
+		if c.Start < 0 {
+			return nil, fmt.Errorf("chapter %q has a negative start time", c.Title)
+		}
+
+		ret = append(ret, file.VideoChapter{
+			Title: c.Title,
+			Start: c.Start,
+		})
+	}
+
+	sortChapters(ret)
+	return ret, nil
+}
+
+// ParseFFMetadata parses the chapters of an ffmpeg metadata file, as written
+// by ffmpeg -f ffmetadata. The returned chapters are ordered by start time.
+func ParseFFMetadata(r io.Reader) ([]file.VideoChapter, error) {
+	scanner := bufio.NewScanner(r)
+
+	if !scanner.Scan() || strings.TrimSpace(scanner.Text()) != ffmetadataHeader {
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("missing %s header", ffmetadataHeader)
+	}
+
+	ret := []file.VideoChapter{}
+
+	type chapter struct {
+		title string
+		start int64
+		// timebase numerator and denominator. ffmpeg defaults to nanoseconds.
+		num, den int64
+	}
+
+	var current *chapter
+	finish := func() {
+		if current != nil {
+			ret = append(ret, file.VideoChapter{
+				Title: current.title,
+				Start: float64(current.start) * float64(current.num) / float64(current.den),
+			})
+		}
+		current = nil
+	}
+
+	line := ""
+	for scanner.Scan() {
+		text := scanner.Text()
+
+		// a trailing backslash escapes the newline
+		if strings.HasSuffix(text, `\`) && !strings.HasSuffix(text, `\\`) {
+			line += strings.TrimSuffix(text, `\`) + "\n"
+			continue
+		}
+		line += text
+		l := line
+		line = ""
+
+		trimmed := strings.TrimSpace(l)
+		if trimmed == "" || strings.HasPrefix(trimmed, ";") || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "[") {
+			finish()
+			if strings.EqualFold(trimmed, "[CHAPTER]") {
+				current = &chapter{num: 1, den: 1000000000}
+			}
+			continue
+		}
+
+		// global and stream metadata is ignored
+		if current == nil {
+			continue
+		}
+
+		key, value, ok := splitFFMetadataLine(l)
+		if !ok {
+			continue
+		}
+
+		var err error
+		switch strings.ToLower(key) {
+		case "timebase":
+			current.num, current.den, err = parseTimebase(value)
+		case "start":
+			current.start, err = strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		case "title":
+			current.title = value
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", key, err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	finish()
+
+	sortChapters(ret)
+	return ret, nil
+}
+
+// splitFFMetadataLine splits a key=value line, unescaping the special
+// characters of the key and value.
+func splitFFMetadataLine(l string) (key string, value string, ok bool) {
+	var b strings.Builder
+	escaped := false
+	for _, c := range l {
+		switch {
+		case escaped:
+			b.WriteRune(c)
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case c == '=' && !ok:
+			key = b.String()
+			b.Reset()
+			ok = true
+		default:
+			b.WriteRune(c)
+		}
+	}
+
+	value = b.String()
+	return
+}
+
+func parseTimebase(v string) (int64, int64, error) {
+	n, d, found := strings.Cut(strings.TrimSpace(v), "/")
+	if !found {
+		return 0, 0, fmt.Errorf("invalid timebase %q", v)
+	}
+
+	num, err := strconv.ParseInt(n, 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	den, err := strconv.ParseInt(d, 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	if num <= 0 || den <= 0 {
+		return 0, 0, fmt.Errorf("invalid timebase %q", v)
+	}
+
+	return num, den, nil
+}
+
+func sortChapters(chapters []file.VideoChapter) {
+	sort.SliceStable(chapters, func(i, j int) bool {
+		return chapters[i].Start < chapters[j].Start
+	})
+}
@@ -0,0 +1,154 @@
+package video
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetChaptersPaths(t *testing.T) {
+	assert.Equal(t, []string{
+		"/stash/video.chapters.json",
+		"/stash/video.ffmetadata",
+	}, GetChaptersPaths("/stash/video.mkv"))
+}
+
+func TestParseChaptersJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []file.VideoChapter
+		wantErr bool
+	}{
+		{
+			"unordered",
+			`{"chapters": [{"title": "Second", "start": 90.5}, {"title": "First", "start": 0}]}`,
+			[]file.VideoChapter{
+				{Title: "First", Start: 0},
+				{Title: "Second", Start: 90.5},
+			},
+			false,
+		},
+		{
+			"empty",
+			`{"chapters": []}`,
+			[]file.VideoChapter{},
+			false,
+		},
+		{
+			"negative start",
+			`{"chapters": [{"title": "First", "start": -1}]}`,
+			nil,
+			true,
+		},
+		{
+			"invalid",
+			`[`,
+			nil,
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseChaptersJSON(strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseChaptersJSON() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseFFMetadata(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []file.VideoChapter
+		wantErr bool
+	}{
+		{
+			"chapters",
+			`;FFMETADATA1
+title=Scene title
+
+[CHAPTER]
+TIMEBASE=1/1000
+START=0
+END=60000
+title=Intro
+
+[CHAPTER]
+TIMEBASE=1/10
+START=605
+END=1200
+title=Part \= one\; \#2
+`,
+			[]file.VideoChapter{
+				{Title: "Intro", Start: 0},
+				{Title: "Part = one; #2", Start: 60.5},
+			},
+			false,
+		},
+		{
+			"default timebase",
+			`;FFMETADATA1
+[CHAPTER]
+START=1500000000
+END=2000000000
+title=Late
+`,
+			[]file.VideoChapter{
+				{Title: "Late", Start: 1.5},
+			},
+			false,
+		},
+		{
+			"stream metadata",
+			`;FFMETADATA1
+[CHAPTER]
+TIMEBASE=1/1
+START=10
+title=Only
+[STREAM]
+title=Video
+`,
+			[]file.VideoChapter{
+				{Title: "Only", Start: 10},
+			},
+			false,
+		},
+		{
+			"missing header",
+			`[CHAPTER]
+START=0
+`,
+			nil,
+			true,
+		},
+		{
+			"invalid timebase",
+			`;FFMETADATA1
+[CHAPTER]
+TIMEBASE=1000
+START=0
+`,
+			nil,
+			true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFFMetadata(strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseFFMetadata() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -3,8 +3,15 @@ package video
 import (
 	"path/filepath"
 	"strings"
+
+	"github.com/stashapp/stash/pkg/fsutil"
 )
 
+// FunscriptAxes are the names of the secondary axes of multi-axis scripts.
+// Secondary axis scripts are stored next to the main funscript with the axis
+// name before the extension. For example scene.roll.funscript.
+var FunscriptAxes = []string{"surge", "sway", "twist", "roll", "pitch"}
+
 // GetFunscriptPath returns the path of a file
 // with the extension changed to .funscript
 func GetFunscriptPath(path string) string {
@@ -12,3 +19,56 @@ func GetFunscriptPath(path string) string {
 	fn := strings.TrimSuffix(path, ext)
 	return fn + ".funscript"
 }
+
+// CSVScriptExtension is the extension of CSV interactive scripts. Both
+// time,position scripts and Vorze time,direction,speed scripts are supported.
+const CSVScriptExtension = ".csv"
+
+// GetCSVScriptPath returns the path of a file
+// with the extension changed to .csv
+func GetCSVScriptPath(path string) string {
+	ext := filepath.Ext(path)
+	fn := strings.TrimSuffix(path, ext)
+	return fn + CSVScriptExtension
+}
+
+// GetInteractiveScriptPaths returns the paths of the interactive scripts that
+// may accompany a file, in order of preference.
+func GetInteractiveScriptPaths(path string) []string {
+	return []string{GetFunscriptPath(path), GetCSVScriptPath(path)}
+}
+
+// FindInteractiveScriptPath returns the path of the preferred interactive
+// script of a file that exists. The funscript path is returned if no script
+// exists.
+func FindInteractiveScriptPath(path string) string {
+	for _, p := range GetInteractiveScriptPaths(path) {
+		if exists, _ := fsutil.FileExists(p); exists {
+			return p
+		}
+	}
+
+	return GetFunscriptPath(path)
+}
+
+// GetRepairedFunscriptPath returns the path of the repaired copy of the
+// funscript of a file, with the extension changed to .repaired.funscript
+func GetRepairedFunscriptPath(path string) string {
+	ext := filepath.Ext(path)
+	fn := strings.TrimSuffix(path, ext)
+	return fn + ".repaired.funscript"
+}
+
+// GetFunscriptBackupPath returns the path of the backup of the funscript of
+// a file, made before the funscript is edited
+func GetFunscriptBackupPath(path string) string {
+	return GetFunscriptPath(path) + ".bak"
+}
+
+// GetFunscriptAxisPath returns the path of a file
+// with the extension changed to .<axis>.funscript
+func GetFunscriptAxisPath(path string, axis string) string {
+	ext := filepath.Ext(path)
+	fn := strings.TrimSuffix(path, ext)
+	return fn + "." + axis + ".funscript"
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/stashapp/stash/pkg/ffmpeg"
 	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/logger"
 )
 
 // Decorator adds video specific fields to a File.
@@ -36,23 +37,67 @@ func (d *Decorator) Decorate(ctx context.Context, fs file.FS, f file.File) (file
 		return f, fmt.Errorf("matching container for %q: %w", base.Path, err)
 	}
 
-	// check if there is a funscript file
-	interactive := false
-	if _, err := fs.Lstat(GetFunscriptPath(base.Path)); err == nil {
-		interactive = true
+	// check if there is an interactive script
+	interactive := hasInteractiveScript(fs, base.Path)
+
+	// chapter files take precedence over the chapters embedded in the
+	// container. Chapters is non-nil to mark the file as probed.
+	chapters, err := ReadChaptersFile(fs, base.Path)
+	if err != nil {
+		logger.Warnf("ignoring chapter file: %v", err)
+	}
+	if chapters == nil {
+		chapters = []file.VideoChapter{}
+		for _, c := range videoFile.Chapters {
+			chapters = append(chapters, file.VideoChapter{
+				Title: c.Title,
+				Start: c.Start,
+			})
+		}
+	}
+
+	// SubtitleTracks is non-nil to mark the file as probed
+	subtitleTracks := []file.VideoSubtitleTrack{}
+	for _, s := range videoFile.SubtitleStreams {
+		subtitleTracks = append(subtitleTracks, file.VideoSubtitleTrack{
+			StreamIndex: s.Index,
+			Language:    s.Language,
+			Title:       s.Title,
+			Codec:       s.Codec,
+			Default:     s.Default,
+			Forced:      s.Forced,
+		})
+	}
+
+	metadata := &file.VideoMetadata{
+		Title:            videoFile.Title,
+		Details:          videoFile.Description,
+		Artist:           videoFile.Artist,
+		AlbumArtist:      videoFile.AlbumArtist,
+		Date:             videoFile.Date,
+		CoverStreamIndex: -1,
+	}
+	if metadata.Details == "" {
+		metadata.Details = videoFile.Comment
+	}
+	if videoFile.CoverStream != nil {
+		metadata.CoverStreamIndex = videoFile.CoverStream.Index
 	}
 
 	return &file.VideoFile{
-		BaseFile:    base,
-		Format:      string(container),
-		VideoCodec:  videoFile.VideoCodec,
-		AudioCodec:  videoFile.AudioCodec,
-		Width:       videoFile.Width,
-		Height:      videoFile.Height,
-		Duration:    videoFile.FileDuration,
-		FrameRate:   videoFile.FrameRate,
-		BitRate:     videoFile.Bitrate,
-		Interactive: interactive,
+		BaseFile:       base,
+		Format:         string(container),
+		VideoCodec:     videoFile.VideoCodec,
+		AudioCodec:     videoFile.AudioCodec,
+		Width:          videoFile.Width,
+		Height:         videoFile.Height,
+		Duration:       videoFile.FileDuration,
+		FrameRate:      videoFile.FrameRate,
+		BitRate:        videoFile.Bitrate,
+		Interactive:    interactive,
+		Chapters:       chapters,
+		SubtitleTracks: subtitleTracks,
+		Metadata:       metadata,
 	}, nil
 }
 
@@ -67,14 +112,34 @@ func (d *Decorator) IsMissingMetadata(ctx context.Context, fs file.FS, f file.Fi
 		return true
 	}
 
-	interactive := false
-	if _, err := fs.Lstat(GetFunscriptPath(vf.Base().Path)); err == nil {
-		interactive = true
-	}
+	interactive := hasInteractiveScript(fs, vf.Base().Path)
 
 	return vf.VideoCodec == unsetString || vf.AudioCodec == unsetString ||
 		vf.Format == unsetString || vf.Width == unsetNumber ||
 		vf.Height == unsetNumber || vf.FrameRate == unsetNumber ||
 		vf.Duration == unsetNumber ||
-		vf.BitRate == unsetNumber || interactive != vf.Interactive
+		vf.BitRate == unsetNumber || interactive != vf.Interactive ||
+		chaptersFileUpdated(fs, vf)
+}
+
+// chaptersFileUpdated returns true if a chapter file of the video file was
+// modified after the file was last updated.
+func chaptersFileUpdated(fs file.FS, vf *file.VideoFile) bool {
+	for _, p := range GetChaptersPaths(vf.Path) {
+		if info, err := fs.Lstat(p); err == nil && info.ModTime().After(vf.UpdatedAt) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasInteractiveScript(fs file.FS, path string) bool {
+	for _, p := range GetInteractiveScriptPaths(path) {
+		if _, err := fs.Lstat(p); err == nil {
+			return true
+		}
+	}
+
+	return false
 }
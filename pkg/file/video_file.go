@@ -14,6 +14,36 @@ type VideoFile struct {
 
 	Interactive      bool `json:"interactive"`
 	InteractiveSpeed *int `json:"interactive_speed"`
+
+	// only set when the file has been probed
+	// Chapters replace the stored chapters of the file when non-nil, but
+	// are not loaded with the file
+	Chapters []VideoChapter `json:"-"`
+	// SubtitleTracks replace the stored subtitle tracks of the file when
+	// non-nil, but are not loaded with the file
+	SubtitleTracks []VideoSubtitleTrack `json:"-"`
+	// transient - not persisted
+	Metadata *VideoMetadata `json:"-"`
+}
+
+// VideoMetadata is the metadata stored in the container tags of a video
+// file.
+type VideoMetadata struct {
+	Title       string
+	Details     string
+	Artist      string
+	AlbumArtist string
+	Date        string
+	// Index of the stream containing the cover art, -1 if there is none
+	CoverStreamIndex int
+}
+
+// VideoChapter is a chapter of a video file, either embedded in the
+// container or read from a chapter file next to it.
+type VideoChapter struct {
+	Title string
+	// Start time in seconds
+	Start float64
 }
 
 func (f VideoFile) GetMinResolution() int {
@@ -26,3 +56,17 @@ func (f VideoFile) GetMinResolution() int {
 
 	return h
 }
+
+// VideoSubtitleTrack is a text subtitle stream embedded in the container of
+// a video file.
+type VideoSubtitleTrack struct {
+	// StreamIndex is the index of the stream among the subtitle streams of
+	// the file
+	StreamIndex int
+	// Language is the language tag of the stream, usually an ISO 639-2 code
+	Language string
+	Title    string
+	Codec    string
+	Default  bool
+	Forced   bool
+}
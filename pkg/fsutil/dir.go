@@ -34,6 +34,12 @@ func IsPathInDir(dir, pathToCheck string) bool {
 	return false
 }
 
+// IsPathInDirFold returns true if pathToCheck is within dir, ignoring case.
+// It should be used for directories on case-insensitive filesystems.
+func IsPathInDirFold(dir, pathToCheck string) bool {
+	return IsPathInDir(strings.ToLower(dir), strings.ToLower(pathToCheck))
+}
+
 // IsPathInDirs returns true if pathToCheck is within anys of the paths in dirs.
 func IsPathInDirs(dirs []string, pathToCheck string) bool {
 	for _, dir := range dirs {
@@ -43,6 +43,26 @@ func TestIsPathInDir(t *testing.T) {
 	}
 }
 
+func TestIsPathInDirFold(t *testing.T) {
+	parentDir := filepath.Join("Stash", "Videos")
+
+	tests := []struct {
+		name        string
+		pathToCheck string
+		expected    bool
+	}{
+		{"same case", filepath.Join("Stash", "Videos", "file.mp4"), true},
+		{"different case", filepath.Join("stash", "VIDEOS", "file.mp4"), true},
+		{"outside", filepath.Join("stash", "images", "file.jpg"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IsPathInDirFold(parentDir, tt.pathToCheck))
+		})
+	}
+}
+
 func TestDirExists(t *testing.T) {
 	type test struct {
 		dir      string
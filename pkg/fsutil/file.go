@@ -11,6 +11,9 @@ import (
 
 // SafeMove attempts to move the file with path src to dest using os.Rename. If this fails, then it copies src to dest, then deletes src.
 func SafeMove(src, dst string) error {
+	src = LongPath(src)
+	dst = LongPath(dst)
+
 	err := os.Rename(src, dst)
 
 	if err != nil {
@@ -133,5 +136,18 @@ func SanitiseBasename(v string) string {
 	// remove multiple hyphens
 	v = multiHyphenRE.ReplaceAllString(v, "-")
 
-	return strings.TrimSpace(v)
+	// trailing dots are stripped by Windows
+	v = strings.TrimRight(strings.TrimSpace(v), ".")
+
+	// reserved names are matched on the part before the first dot, so the
+	// suffix is added there
+	if IsReservedName(v) {
+		i := strings.Index(v, ".")
+		if i < 0 {
+			i = len(v)
+		}
+		v = v[:i] + "-" + v[i:]
+	}
+
+	return v
 }
@@ -15,12 +15,19 @@ func TestSanitiseBasename(t *testing.T) {
 		{"multi-hyphen", `hyphened--name`, "hyphened-name"},
 		{"replaced characters", `a&b=c\d/:e*"f?_ g`, "a-b-c-d-e-f-g"},
 		{"removed characters", `foo!!bar@@and, more`, "foobarand-more"},
+		{"trailing dots", `name...`, "name"},
+		{"reserved name", `con`, "con-"},
+		{"reserved name with extension", `NUL.tar`, "NUL-.tar"},
+		{"reserved name with extensions", `com1.tar.gz`, "com1-.tar.gz"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			if got := SanitiseBasename(tt.v); got != tt.want {
 				t.Errorf("SanitiseBasename() = %v, want %v", got, tt.want)
 			}
+			if got := SanitiseBasename(tt.v); IsReservedName(got) {
+				t.Errorf("SanitiseBasename() = %v, which is a reserved name", got)
+			}
 		})
 	}
 }
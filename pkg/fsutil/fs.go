@@ -16,7 +16,7 @@ func IsFsPathCaseSensitive(path string) (bool, error) {
 	// If the resulting flipped path exists then the fs should not be case sensitive
 	// ( we check the file mod time to avoid matching an existing path )
 
-	fi, err := os.Stat(path)
+	fi, err := os.Stat(LongPath(path))
 	if err != nil { // path cannot be stat'd
 		return false, err
 	}
@@ -37,7 +37,7 @@ func IsFsPathCaseSensitive(path string) (bool, error) {
 		i++
 	}
 
-	fiCase, err := os.Stat(string(flipped))
+	fiCase, err := os.Stat(LongPath(string(flipped)))
 	if err != nil { // cannot stat the case flipped path
 		return true, nil // fs of path should be case sensitive
 	}
@@ -0,0 +1,89 @@
+package fsutil
+
+import (
+	"strings"
+)
+
+const (
+	windowsLongPathPrefix    = `\\?\`
+	windowsLongUNCPathPrefix = `\\?\UNC\`
+
+	// paths of this length or longer need the long path prefix. This is
+	// MAX_PATH less the space needed for an 8.3 file name, which is the
+	// limit when creating directories.
+	windowsMaxShortPathLength = 248
+)
+
+var windowsReservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true,
+	"COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true,
+	"LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// IsReservedName returns true if the file name is a Windows reserved device
+// name, such as NUL or COM1. Reserved names are matched case-insensitively
+// and regardless of extension, so nul.txt is also reserved.
+func IsReservedName(name string) bool {
+	if i := strings.Index(name, "."); i >= 0 {
+		name = name[:i]
+	}
+
+	return windowsReservedNames[strings.ToUpper(strings.TrimRight(name, " "))]
+}
+
+// HasTrailingDotOrSpace returns true if the file name ends in a dot or a
+// space, which Windows strips when normalising paths.
+func HasTrailingDotOrSpace(name string) bool {
+	return name != "." && name != ".." && (strings.HasSuffix(name, ".") || strings.HasSuffix(name, " "))
+}
+
+// windowsLongPath returns the Windows path p with the \\?\ prefix, if it is
+// needed to access p. The prefix disables Win32 path normalisation, so it is
+// needed for paths longer than MAX_PATH and for paths with a component that
+// is a reserved name or that has a trailing dot or space. p must be a clean
+// absolute path. Relative and already prefixed paths are returned unchanged.
+func windowsLongPath(p string) string {
+	if strings.HasPrefix(p, windowsLongPathPrefix) {
+		return p
+	}
+
+	// slashes are not converted in prefixed paths
+	p = strings.ReplaceAll(p, "/", `\`)
+
+	isUNC := strings.HasPrefix(p, `\\`)
+	isDrive := len(p) >= 3 && p[1] == ':' && p[2] == '\\'
+	if !isUNC && !isDrive {
+		return p
+	}
+
+	if len(p) < windowsMaxShortPathLength && !hasUnsafeWindowsComponent(p) {
+		return p
+	}
+
+	if isUNC {
+		return windowsLongUNCPathPrefix + p[2:]
+	}
+
+	return windowsLongPathPrefix + p
+}
+
+func hasUnsafeWindowsComponent(p string) bool {
+	for _, c := range strings.Split(p, `\`) {
+		if IsReservedName(c) || HasTrailingDotOrSpace(c) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// StripLongPathPrefix returns p without the Windows \\?\ long path prefix.
+func StripLongPathPrefix(p string) string {
+	if strings.HasPrefix(p, windowsLongUNCPathPrefix) {
+		return `\\` + p[len(windowsLongUNCPathPrefix):]
+	}
+
+	return strings.TrimPrefix(p, windowsLongPathPrefix)
+}
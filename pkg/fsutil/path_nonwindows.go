@@ -0,0 +1,9 @@
+//go:build linux || darwin || !windows
+// +build linux darwin !windows
+
+package fsutil
+
+// LongPath returns the path unchanged on non-Windows platforms.
+func LongPath(path string) string {
+	return path
+}
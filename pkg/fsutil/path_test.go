@@ -0,0 +1,92 @@
+package fsutil
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsReservedName(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"CON", true},
+		{"con", true},
+		{"nul.txt", true},
+		{"COM1", true},
+		{"LPT9.tar.gz", true},
+		{"COM0", false},
+		{"console", false},
+		{"CONx", false},
+		{"file.nul", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsReservedName(tt.name))
+		})
+	}
+}
+
+func TestHasTrailingDotOrSpace(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"file.", true},
+		{"file ", true},
+		{"file.mp4", false},
+		{".", false},
+		{"..", false},
+		{".hidden", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, HasTrailingDotOrSpace(tt.name))
+		})
+	}
+}
+
+func TestWindowsLongPath(t *testing.T) {
+	longDir := strings.Repeat("a", windowsMaxShortPathLength)
+
+	tests := []struct {
+		name string
+		p    string
+		want string
+	}{
+		{"short", `C:\videos\file.mp4`, `C:\videos\file.mp4`},
+		{"forward slashes", `C:/videos/file.mp4`, `C:\videos\file.mp4`},
+		{"relative", `videos\file.mp4`, `videos\file.mp4`},
+		{"long", `C:\` + longDir, `\\?\C:\` + longDir},
+		{"long UNC", `\\server\share\` + longDir, `\\?\UNC\server\share\` + longDir},
+		{"reserved name", `C:\videos\nul.mp4`, `\\?\C:\videos\nul.mp4`},
+		{"trailing dot", `C:\videos.\file.mp4`, `\\?\C:\videos.\file.mp4`},
+		{"trailing space", `\\server\share\file.mp4 `, `\\?\UNC\server\share\file.mp4 `},
+		{"already prefixed", `\\?\C:\videos\nul`, `\\?\C:\videos\nul`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, windowsLongPath(tt.p))
+		})
+	}
+}
+
+func TestStripLongPathPrefix(t *testing.T) {
+	tests := []struct {
+		name string
+		p    string
+		want string
+	}{
+		{"drive", `\\?\C:\videos\nul.mp4`, `C:\videos\nul.mp4`},
+		{"UNC", `\\?\UNC\server\share\file.mp4`, `\\server\share\file.mp4`},
+		{"unprefixed", `C:\videos\file.mp4`, `C:\videos\file.mp4`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, StripLongPathPrefix(tt.p))
+		})
+	}
+}
@@ -0,0 +1,28 @@
+//go:build windows
+// +build windows
+
+package fsutil
+
+import "path/filepath"
+
+// LongPath returns the path with the \\?\ prefix if it is needed to access
+// the file on Windows. The os package only adds the prefix to long paths, but
+// it is also needed for files with reserved names or trailing dots or spaces,
+// which are common in libraries migrated from other platforms. Paths passed
+// to the filesystem should use LongPath, while paths that are stored or
+// displayed should not.
+func LongPath(path string) string {
+	if path == "" {
+		return path
+	}
+
+	if !filepath.IsAbs(path) {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return path
+		}
+		path = abs
+	}
+
+	return windowsLongPath(filepath.Clean(path))
+}
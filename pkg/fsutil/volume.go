@@ -0,0 +1,15 @@
+package fsutil
+
+// VolumeInfo describes the filesystem volume containing a path.
+type VolumeInfo struct {
+	// ID identifies the volume. Paths on the same volume have the same ID.
+	ID         string
+	TotalSpace uint64
+	// FreeSpace is the space available to the current user.
+	FreeSpace uint64
+}
+
+// GetVolumeInfo returns the information of the volume containing the path.
+func GetVolumeInfo(path string) (*VolumeInfo, error) {
+	return getVolumeInfo(path)
+}
@@ -0,0 +1,37 @@
+//go:build linux || darwin || !windows
+// +build linux darwin !windows
+
+package fsutil
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+func getVolumeInfo(path string) (*VolumeInfo, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil, fmt.Errorf("getting device of %s: unsupported platform", path)
+	}
+
+	var fs unix.Statfs_t
+	if err := unix.Statfs(path, &fs); err != nil {
+		return nil, fmt.Errorf("getting filesystem stats of %s: %w", path, err)
+	}
+
+	blockSize := uint64(fs.Bsize)
+	return &VolumeInfo{
+		ID:         strconv.FormatUint(uint64(st.Dev), 10),
+		TotalSpace: uint64(fs.Blocks) * blockSize,
+		FreeSpace:  uint64(fs.Bavail) * blockSize,
+	}, nil
+}
@@ -0,0 +1,35 @@
+//go:build windows
+// +build windows
+
+package fsutil
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/sys/windows"
+)
+
+func getVolumeInfo(path string) (*VolumeInfo, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := windows.UTF16PtrFromString(LongPath(abs))
+	if err != nil {
+		return nil, err
+	}
+
+	var free, total, totalFree uint64
+	if err := windows.GetDiskFreeSpaceEx(p, &free, &total, &totalFree); err != nil {
+		return nil, fmt.Errorf("getting free space of %s: %w", path, err)
+	}
+
+	return &VolumeInfo{
+		ID:         strings.ToUpper(filepath.VolumeName(abs)),
+		TotalSpace: total,
+		FreeSpace:  free,
+	}, nil
+}
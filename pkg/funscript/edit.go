@@ -0,0 +1,68 @@
+package funscript
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// edit returns the provided funscript data with the actions replaced by the
+// result of fn. Fields other than the actions are preserved.
+func edit(data []byte, fn func(actions []Action) []Action) ([]byte, error) {
+	s, err := parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	actions, err := json.Marshal(fn(s.actions))
+	if err != nil {
+		return nil, err
+	}
+	s.fields["actions"] = actions
+
+	return json.Marshal(s.fields)
+}
+
+// Shift returns the provided funscript data with offsetMilli milliseconds
+// added to the time of every action. Actions which would occur before the
+// start of the scene are removed.
+func Shift(data []byte, offsetMilli int64) ([]byte, error) {
+	return edit(data, func(actions []Action) []Action {
+		ret := make([]Action, 0, len(actions))
+		for _, a := range actions {
+			a.At += offsetMilli
+			if a.At < 0 {
+				continue
+			}
+			ret = append(ret, a)
+		}
+		return ret
+	})
+}
+
+// Scale returns the provided funscript data with the positions of the
+// actions scaled from the full range to the range min to max. Positions
+// outside of the full range are clamped first.
+func Scale(data []byte, min, max int) ([]byte, error) {
+	if min < MinPosition || max > MaxPosition || min >= max {
+		return nil, fmt.Errorf("invalid position range %d-%d: must be within %d-%d", min, max, MinPosition, MaxPosition)
+	}
+
+	return edit(data, func(actions []Action) []Action {
+		const fullRange = MaxPosition - MinPosition
+
+		ret := make([]Action, len(actions))
+		for i, a := range actions {
+			pos := a.Pos
+			if pos < MinPosition {
+				pos = MinPosition
+			} else if pos > MaxPosition {
+				pos = MaxPosition
+			}
+
+			// round to the nearest position
+			a.Pos = min + ((pos-MinPosition)*(max-min)+fullRange/2)/fullRange
+			ret[i] = a
+		}
+		return ret
+	})
+}
@@ -0,0 +1,101 @@
+package funscript
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testEditScript = `{"version":"1.0","inverted":false,"actions":[` +
+	`{"at":0,"pos":0},` +
+	`{"at":500,"pos":100},` +
+	`{"at":1000,"pos":50},` +
+	`{"at":1500,"pos":110}` +
+	`]}`
+
+func unmarshalEdited(t *testing.T, data []byte) (string, []Action) {
+	t.Helper()
+
+	var got struct {
+		Version string   `json:"version"`
+		Actions []Action `json:"actions"`
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	return got.Version, got.Actions
+}
+
+func TestShift(t *testing.T) {
+	tests := []struct {
+		name   string
+		offset int64
+		want   []Action
+	}{
+		{
+			"later",
+			250,
+			[]Action{{At: 250, Pos: 0}, {At: 750, Pos: 100}, {At: 1250, Pos: 50}, {At: 1750, Pos: 110}},
+		},
+		{
+			"earlier",
+			-500,
+			[]Action{{At: 0, Pos: 100}, {At: 500, Pos: 50}, {At: 1000, Pos: 110}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := Shift([]byte(testEditScript), tt.offset)
+			if err != nil {
+				t.Fatalf("Shift() error = %v", err)
+			}
+
+			version, actions := unmarshalEdited(t, data)
+			assert.Equal(t, "1.0", version)
+			assert.Equal(t, tt.want, actions)
+		})
+	}
+}
+
+func TestScale(t *testing.T) {
+	tests := []struct {
+		name     string
+		min, max int
+		want     []Action
+		wantErr  bool
+	}{
+		{
+			"narrow",
+			20, 80,
+			[]Action{{At: 0, Pos: 20}, {At: 500, Pos: 80}, {At: 1000, Pos: 50}, {At: 1500, Pos: 80}},
+			false,
+		},
+		{
+			"full",
+			0, 100,
+			[]Action{{At: 0, Pos: 0}, {At: 500, Pos: 100}, {At: 1000, Pos: 50}, {At: 1500, Pos: 100}},
+			false,
+		},
+		{"inverted range", 80, 20, nil, true},
+		{"out of range", -10, 80, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := Scale([]byte(testEditScript), tt.min, tt.max)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Scale() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			version, actions := unmarshalEdited(t, data)
+			assert.Equal(t, "1.0", version)
+			assert.Equal(t, tt.want, actions)
+		})
+	}
+}
@@ -0,0 +1,222 @@
+// Package funscript provides validation and repair of funscript files.
+package funscript
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+const (
+	MinPosition = 0
+	MaxPosition = 100
+
+	// maximum number of issues included in a report
+	maxReportIssues = 100
+)
+
+// Action is a move at a specific time.
+type Action struct {
+	// At time in milliseconds the action should fire.
+	At int64 `json:"at"`
+	// Pos is the place in percent to move to.
+	Pos int `json:"pos"`
+}
+
+// Script is a funscript file.
+type Script struct {
+	// Version of Launchscript
+	Version string `json:"version"`
+	// Inverted causes up and down movement to be flipped.
+	Inverted bool `json:"inverted"`
+	// Range is the percentage of a full stroke to use.
+	Range int `json:"range"`
+	// Actions are the timed moves.
+	Actions []Action `json:"actions"`
+}
+
+// Report describes the problems found in a script.
+type Report struct {
+	ActionCount int `json:"action_count"`
+	// Number of issues of each type found
+	IssueCounts []*IssueCount `json:"issue_counts"`
+	// The first issues found, in order of action
+	Issues []*Issue `json:"issues"`
+}
+
+// Valid returns true if no issues were found.
+func (r Report) Valid() bool {
+	return len(r.IssueCounts) == 0
+}
+
+type script struct {
+	// all fields are retained so that they are preserved when repairing
+	fields  map[string]json.RawMessage
+	actions []Action
+}
+
+func parse(data []byte) (*script, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("parsing funscript: %w", err)
+	}
+
+	raw, found := fields["actions"]
+	if !found {
+		return nil, fmt.Errorf("actions list missing")
+	}
+
+	var actions []Action
+	if err := json.Unmarshal(raw, &actions); err != nil {
+		return nil, fmt.Errorf("parsing funscript actions: %w", err)
+	}
+
+	return &script{
+		fields:  fields,
+		actions: actions,
+	}, nil
+}
+
+// validate returns the issues of the actions. Actions past durationMilli are
+// only reported if durationMilli is positive.
+func validate(actions []Action, durationMilli int64) []*Issue {
+	var ret []*Issue
+	add := func(t IssueType, i int, a Action) {
+		ret = append(ret, &Issue{
+			Type:  t,
+			Index: i,
+			At:    a.At,
+			Pos:   a.Pos,
+		})
+	}
+
+	seen := make(map[int64]bool)
+	var maxAt int64
+	for i, a := range actions {
+		switch {
+		case a.At < 0:
+			add(IssueTypeNegativeTimestamp, i, a)
+		case durationMilli > 0 && a.At > durationMilli:
+			add(IssueTypePastDuration, i, a)
+		}
+
+		if a.Pos < MinPosition || a.Pos > MaxPosition {
+			add(IssueTypePositionOutOfRange, i, a)
+		}
+
+		if seen[a.At] {
+			add(IssueTypeDuplicateTimestamp, i, a)
+		} else if i > 0 && a.At < maxAt {
+			add(IssueTypeOutOfOrder, i, a)
+		}
+
+		seen[a.At] = true
+		if i == 0 || a.At > maxAt {
+			maxAt = a.At
+		}
+	}
+
+	return ret
+}
+
+func makeReport(actions []Action, issues []*Issue) *Report {
+	counts := make(map[IssueType]int)
+	for _, i := range issues {
+		counts[i.Type]++
+	}
+
+	ret := &Report{
+		ActionCount: len(actions),
+		IssueCounts: []*IssueCount{},
+		Issues:      issues,
+	}
+
+	for _, t := range AllIssueType {
+		if counts[t] > 0 {
+			ret.IssueCounts = append(ret.IssueCounts, &IssueCount{
+				Type:  t,
+				Count: counts[t],
+			})
+		}
+	}
+
+	if len(ret.Issues) > maxReportIssues {
+		ret.Issues = ret.Issues[:maxReportIssues]
+	}
+	if ret.Issues == nil {
+		ret.Issues = []*Issue{}
+	}
+
+	return ret
+}
+
+// Validate returns a report of the problems in the provided funscript data.
+// durationMilli is the duration of the scene in milliseconds. Actions past
+// the duration are not reported if it is zero.
+func Validate(data []byte, durationMilli int64) (*Report, error) {
+	s, err := parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return makeReport(s.actions, validate(s.actions, durationMilli)), nil
+}
+
+// repair returns a copy of the actions with the problems fixed. Actions with
+// negative timestamps or past the duration are removed, positions are
+// clamped, actions are sorted by time, and only the last action of duplicate
+// timestamps is kept.
+func repair(actions []Action, durationMilli int64) []Action {
+	ret := make([]Action, 0, len(actions))
+	for _, a := range actions {
+		if a.At < 0 || (durationMilli > 0 && a.At > durationMilli) {
+			continue
+		}
+
+		if a.Pos < MinPosition {
+			a.Pos = MinPosition
+		} else if a.Pos > MaxPosition {
+			a.Pos = MaxPosition
+		}
+
+		ret = append(ret, a)
+	}
+
+	sort.SliceStable(ret, func(i, j int) bool { return ret[i].At < ret[j].At })
+
+	// keep the last of each duplicate timestamp
+	deduped := ret[:0]
+	for i, a := range ret {
+		if i+1 < len(ret) && ret[i+1].At == a.At {
+			continue
+		}
+		deduped = append(deduped, a)
+	}
+
+	return deduped
+}
+
+// Repair returns the provided funscript data with the problems fixed, and a
+// report of the problems in the original data. Fields other than the actions
+// are preserved.
+func Repair(data []byte, durationMilli int64) ([]byte, *Report, error) {
+	s, err := parse(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	report := makeReport(s.actions, validate(s.actions, durationMilli))
+
+	actions, err := json.Marshal(repair(s.actions, durationMilli))
+	if err != nil {
+		return nil, nil, err
+	}
+	s.fields["actions"] = actions
+
+	ret, err := json.Marshal(s.fields)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return ret, report, nil
+}
@@ -0,0 +1,105 @@
+package funscript
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testScript = `{"version":"1.0","inverted":false,"actions":[` +
+	`{"at":0,"pos":0},` +
+	`{"at":500,"pos":110},` +
+	`{"at":500,"pos":50},` +
+	`{"at":250,"pos":-5},` +
+	`{"at":-100,"pos":20},` +
+	`{"at":3000,"pos":100}` +
+	`]}`
+
+func TestValidate(t *testing.T) {
+	got, err := Validate([]byte(testScript), 2000)
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	assert.False(t, got.Valid())
+	assert.Equal(t, 6, got.ActionCount)
+	assert.Equal(t, []*Issue{
+		{Type: IssueTypePositionOutOfRange, Index: 1, At: 500, Pos: 110},
+		{Type: IssueTypeDuplicateTimestamp, Index: 2, At: 500, Pos: 50},
+		{Type: IssueTypePositionOutOfRange, Index: 3, At: 250, Pos: -5},
+		{Type: IssueTypeOutOfOrder, Index: 3, At: 250, Pos: -5},
+		{Type: IssueTypeNegativeTimestamp, Index: 4, At: -100, Pos: 20},
+		{Type: IssueTypeOutOfOrder, Index: 4, At: -100, Pos: 20},
+		{Type: IssueTypePastDuration, Index: 5, At: 3000, Pos: 100},
+	}, got.Issues)
+	assert.Equal(t, []*IssueCount{
+		{Type: IssueTypeDuplicateTimestamp, Count: 1},
+		{Type: IssueTypeOutOfOrder, Count: 2},
+		{Type: IssueTypePositionOutOfRange, Count: 2},
+		{Type: IssueTypeNegativeTimestamp, Count: 1},
+		{Type: IssueTypePastDuration, Count: 1},
+	}, got.IssueCounts)
+}
+
+func TestValidateNoDuration(t *testing.T) {
+	got, err := Validate([]byte(`{"actions":[{"at":0,"pos":0},{"at":3000,"pos":100}]}`), 0)
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	assert.True(t, got.Valid())
+	assert.Empty(t, got.Issues)
+}
+
+func TestValidateInvalid(t *testing.T) {
+	for _, data := range []string{`not json`, `{"version":"1.0"}`, `{"actions":{}}`} {
+		_, err := Validate([]byte(data), 0)
+		assert.Error(t, err, data)
+	}
+}
+
+func TestRepair(t *testing.T) {
+	data, report, err := Repair([]byte(testScript), 2000)
+	if err != nil {
+		t.Fatalf("Repair() error = %v", err)
+	}
+
+	assert.False(t, report.Valid())
+
+	var got struct {
+		Version  string   `json:"version"`
+		Inverted *bool    `json:"inverted"`
+		Actions  []Action `json:"actions"`
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	// other fields are preserved
+	assert.Equal(t, "1.0", got.Version)
+	assert.NotNil(t, got.Inverted)
+	assert.Equal(t, []Action{
+		{At: 0, Pos: 0},
+		{At: 250, Pos: 0},
+		{At: 500, Pos: 50},
+	}, got.Actions)
+
+	// the repaired script is valid
+	repaired, err := Validate(data, 2000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, repaired.Valid())
+}
+
+func TestReportIssuesCapped(t *testing.T) {
+	actions := make([]Action, maxReportIssues+10)
+	for i := range actions {
+		actions[i] = Action{At: int64(i), Pos: 200}
+	}
+
+	got := makeReport(actions, validate(actions, 0))
+	assert.Len(t, got.Issues, maxReportIssues)
+	assert.Equal(t, []*IssueCount{{Type: IssueTypePositionOutOfRange, Count: len(actions)}}, got.IssueCounts)
+}
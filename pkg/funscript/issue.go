@@ -0,0 +1,68 @@
+package funscript
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+type IssueType string
+
+const (
+	IssueTypeDuplicateTimestamp IssueType = "DUPLICATE_TIMESTAMP"
+	IssueTypeOutOfOrder         IssueType = "OUT_OF_ORDER"
+	IssueTypePositionOutOfRange IssueType = "POSITION_OUT_OF_RANGE"
+	IssueTypeNegativeTimestamp  IssueType = "NEGATIVE_TIMESTAMP"
+	IssueTypePastDuration       IssueType = "PAST_DURATION"
+)
+
+var AllIssueType = []IssueType{
+	IssueTypeDuplicateTimestamp,
+	IssueTypeOutOfOrder,
+	IssueTypePositionOutOfRange,
+	IssueTypeNegativeTimestamp,
+	IssueTypePastDuration,
+}
+
+func (e IssueType) IsValid() bool {
+	switch e {
+	case IssueTypeDuplicateTimestamp, IssueTypeOutOfOrder, IssueTypePositionOutOfRange, IssueTypeNegativeTimestamp, IssueTypePastDuration:
+		return true
+	}
+	return false
+}
+
+func (e IssueType) String() string {
+	return string(e)
+}
+
+func (e *IssueType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = IssueType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid FunscriptIssueType", str)
+	}
+	return nil
+}
+
+func (e IssueType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// Issue is a problem with a single action of a script.
+type Issue struct {
+	Type IssueType `json:"type"`
+	// Index of the action in the script
+	Index int   `json:"index"`
+	At    int64 `json:"at"`
+	Pos   int   `json:"pos"`
+}
+
+type IssueCount struct {
+	Type  IssueType `json:"type"`
+	Count int       `json:"count"`
+}
@@ -16,6 +16,9 @@ func ToBasicJSON(gallery *models.Gallery) (*jsonschema.Gallery, error) {
 		Title:     gallery.Title,
 		URL:       gallery.URL,
 		Details:   gallery.Details,
+		Location:  gallery.Location,
+		Latitude:  gallery.Latitude,
+		Longitude: gallery.Longitude,
 		CreatedAt: json.JSONTime{Time: gallery.CreatedAt},
 		UpdatedAt: json.JSONTime{Time: gallery.UpdatedAt},
 	}
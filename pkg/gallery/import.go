@@ -69,6 +69,11 @@ func (i *Importer) galleryJSONToGallery(galleryJSON jsonschema.Gallery) models.G
 	if galleryJSON.URL != "" {
 		newGallery.URL = galleryJSON.URL
 	}
+	if galleryJSON.Location != "" {
+		newGallery.Location = galleryJSON.Location
+	}
+	newGallery.Latitude = galleryJSON.Latitude
+	newGallery.Longitude = galleryJSON.Longitude
 	if galleryJSON.Date != "" {
 		d := models.NewDate(galleryJSON.Date)
 		newGallery.Date = &d
@@ -0,0 +1,37 @@
+package imagephash
+
+import (
+	"fmt"
+	"image"
+
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+
+	"github.com/corona10/goimagehash"
+	_ "golang.org/x/image/webp"
+
+	"github.com/stashapp/stash/pkg/file"
+)
+
+// Generate returns the perceptual hash of the image file. Animated images
+// are hashed from their first frame.
+func Generate(f *file.ImageFile) (*uint64, error) {
+	r, err := f.Open(&file.OsFS{})
+	if err != nil {
+		return nil, fmt.Errorf("opening %q: %w", f.Path, err)
+	}
+	defer r.Close()
+
+	img, _, err := image.Decode(r)
+	if err != nil {
+		return nil, fmt.Errorf("decoding %q: %w", f.Path, err)
+	}
+
+	hash, err := goimagehash.PerceptionHash(img)
+	if err != nil {
+		return nil, fmt.Errorf("computing phash of %q: %w", f.Path, err)
+	}
+	hashValue := hash.GetHash()
+	return &hashValue, nil
+}
@@ -0,0 +1,111 @@
+package image
+
+import (
+	"sort"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+// Burst is a run of visually near-identical images, such as frames dumped
+// from a video or photos taken in burst mode.
+type Burst struct {
+	// Keep is the image of the burst recommended to keep: the highest
+	// resolution, then the largest file, then the first by path.
+	Keep *models.Image
+	// Redundant are the other images of the burst, ordered by path.
+	Redundant []*models.Image
+	// Distance is the largest phash distance between Keep and an image of
+	// Redundant.
+	Distance int
+}
+
+type burstImage struct {
+	image *models.Image
+	file  *file.ImageFile
+	phash int64
+}
+
+// FindBursts groups the images into bursts of consecutive near-identical
+// images. The images are ordered by path, and an image joins the current
+// burst if the distance between its phash and the phash of the first image
+// of the burst is at most maxDistance. Comparing with the first image rather
+// than the previous one keeps a slow pan from collapsing into a single burst.
+// Images without a phash end the current burst. Only bursts of two or more
+// images are returned. The primary files of the images must be loaded.
+func FindBursts(images []*models.Image, maxDistance int) []Burst {
+	sorted := make([]*models.Image, len(images))
+	copy(sorted, images)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Path < sorted[j].Path
+	})
+
+	var ret []Burst
+	var current []burstImage
+	flush := func() {
+		if len(current) > 1 {
+			ret = append(ret, newBurst(current))
+		}
+		current = nil
+	}
+
+	for _, i := range sorted {
+		f := i.Files.Primary()
+		if f == nil || f.Fingerprints.For(file.FingerprintTypePhash) == nil {
+			flush()
+			continue
+		}
+
+		bi := burstImage{
+			image: i,
+			file:  f,
+			phash: f.Fingerprints.GetInt64(file.FingerprintTypePhash),
+		}
+
+		if len(current) > 0 && utils.PhashDistance(current[0].phash, bi.phash) > maxDistance {
+			flush()
+		}
+
+		current = append(current, bi)
+	}
+
+	flush()
+
+	return ret
+}
+
+func newBurst(images []burstImage) Burst {
+	keep := 0
+	for i := range images {
+		if betterBurstImage(images[i].file, images[keep].file) {
+			keep = i
+		}
+	}
+
+	ret := Burst{
+		Keep: images[keep].image,
+	}
+
+	for i, bi := range images {
+		if i == keep {
+			continue
+		}
+
+		ret.Redundant = append(ret.Redundant, bi.image)
+		if d := utils.PhashDistance(images[keep].phash, bi.phash); d > ret.Distance {
+			ret.Distance = d
+		}
+	}
+
+	return ret
+}
+
+// betterBurstImage returns true if f is a better image to keep than other.
+func betterBurstImage(f, other *file.ImageFile) bool {
+	if r, otherR := f.Width*f.Height, other.Width*other.Height; r != otherR {
+		return r > otherR
+	}
+
+	return f.Size > other.Size
+}
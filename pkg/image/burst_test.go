@@ -0,0 +1,126 @@
+package image
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func makeBurstImage(id int, path string, phash *int64, width int, size int64) *models.Image {
+	f := &file.ImageFile{
+		BaseFile: &file.BaseFile{
+			Path: path,
+			Size: size,
+		},
+		Width:  width,
+		Height: width,
+	}
+	if phash != nil {
+		f.Fingerprints = file.Fingerprints{
+			{Type: file.FingerprintTypePhash, Fingerprint: *phash},
+		}
+	}
+
+	return &models.Image{
+		ID:    id,
+		Path:  path,
+		Files: models.NewRelatedImageFiles([]*file.ImageFile{f}),
+	}
+}
+
+func imageIDs(images []*models.Image) []int {
+	var ret []int
+	for _, i := range images {
+		ret = append(ret, i.ID)
+	}
+	return ret
+}
+
+func TestFindBursts(t *testing.T) {
+	hash := func(v int64) *int64 { return &v }
+
+	const maxDistance = 2
+
+	tests := []struct {
+		name   string
+		images []*models.Image
+		// keep and redundant image ids of each burst
+		want [][]int
+	}{
+		{
+			"single burst keeps highest resolution",
+			[]*models.Image{
+				makeBurstImage(1, "/a/001.jpg", hash(0), 100, 10),
+				makeBurstImage(2, "/a/002.jpg", hash(1), 200, 10),
+				makeBurstImage(3, "/a/003.jpg", hash(3), 100, 10),
+			},
+			[][]int{{2, 1, 3}},
+		},
+		{
+			"ties keep largest file",
+			[]*models.Image{
+				makeBurstImage(1, "/a/001.jpg", hash(0), 100, 10),
+				makeBurstImage(2, "/a/002.jpg", hash(0), 100, 20),
+			},
+			[][]int{{2, 1}},
+		},
+		{
+			"ordered by path",
+			[]*models.Image{
+				makeBurstImage(1, "/a/003.jpg", hash(0), 100, 10),
+				makeBurstImage(2, "/a/001.jpg", hash(0), 100, 10),
+				makeBurstImage(3, "/a/002.jpg", hash(0xff), 100, 10),
+			},
+			nil,
+		},
+		{
+			"no drift from first image",
+			[]*models.Image{
+				makeBurstImage(1, "/a/001.jpg", hash(0), 100, 10),
+				makeBurstImage(2, "/a/002.jpg", hash(0x3), 100, 10),
+				makeBurstImage(3, "/a/003.jpg", hash(0xf), 100, 10),
+				makeBurstImage(4, "/a/004.jpg", hash(0x1f), 100, 10),
+			},
+			[][]int{{1, 2}, {3, 4}},
+		},
+		{
+			"missing phash ends burst",
+			[]*models.Image{
+				makeBurstImage(1, "/a/001.jpg", hash(0), 100, 10),
+				makeBurstImage(2, "/a/002.jpg", nil, 100, 10),
+				makeBurstImage(3, "/a/003.jpg", hash(0), 100, 10),
+			},
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got [][]int
+			for _, b := range FindBursts(tt.images, maxDistance) {
+				got = append(got, append([]int{b.Keep.ID}, imageIDs(b.Redundant)...))
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFindBursts_Distance(t *testing.T) {
+	images := []*models.Image{
+		makeBurstImage(1, "/a/001.jpg", nil, 100, 10),
+		makeBurstImage(2, "/a/002.jpg", nil, 200, 10),
+		makeBurstImage(3, "/a/003.jpg", nil, 100, 10),
+	}
+	for i, h := range []int64{0x1, 0x0, 0x3} {
+		images[i].Files.Primary().Fingerprints = file.Fingerprints{
+			{Type: file.FingerprintTypePhash, Fingerprint: h},
+		}
+	}
+
+	got := FindBursts(images, 2)
+	if assert.Len(t, got, 1) {
+		assert.Equal(t, 2, got[0].Distance)
+	}
+}
@@ -0,0 +1,280 @@
+package image
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// ErrNoGPS is returned when an image has no EXIF GPS coordinates.
+var ErrNoGPS = errors.New("no GPS coordinates")
+
+const (
+	exifTagGPSIFD    = 0x8825
+	exifTagGPSLatRef = 0x0001
+	exifTagGPSLat    = 0x0002
+	exifTagGPSLonRef = 0x0003
+	exifTagGPSLon    = 0x0004
+	exifTypeASCII    = 2
+	exifTypeRational = 5
+	exifIFDEntrySize = 12
+
+	// exif chunks in png files are not limited in size, but GPS data is
+	// expected near the start
+	pngMaxExifBytes = 1 << 20
+)
+
+var (
+	jpegSOI       = []byte{0xFF, 0xD8}
+	exifHeader    = []byte("Exif\x00\x00")
+	pngSignature  = []byte("\x89PNG\r\n\x1a\n")
+	errInvalidTag = errors.New("invalid EXIF data")
+)
+
+// ReadGPSCoordinates returns the latitude and longitude from the EXIF GPS
+// data of a JPEG or PNG image. Returns ErrNoGPS if the image has no GPS data.
+func ReadGPSCoordinates(r io.Reader) (latitude float64, longitude float64, err error) {
+	br := bufio.NewReader(r)
+	header, err := br.Peek(len(pngSignature))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return 0, 0, err
+	}
+
+	var tiff []byte
+	switch {
+	case bytes.HasPrefix(header, jpegSOI):
+		tiff, err = readJPEGExif(br)
+	case bytes.HasPrefix(header, pngSignature):
+		tiff, err = readPNGExif(br)
+	default:
+		return 0, 0, ErrNoGPS
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+
+	latitude, longitude, err = parseExifGPS(tiff)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if err := models.ValidateCoordinates(&latitude, &longitude); err != nil {
+		return 0, 0, err
+	}
+
+	return latitude, longitude, nil
+}
+
+// readJPEGExif returns the TIFF structure of the EXIF APP1 segment.
+func readJPEGExif(r io.Reader) ([]byte, error) {
+	if _, err := io.CopyN(io.Discard, r, int64(len(jpegSOI))); err != nil {
+		return nil, err
+	}
+
+	var marker [2]byte
+	for {
+		if _, err := io.ReadFull(r, marker[:]); err != nil {
+			return nil, ErrNoGPS
+		}
+		if marker[0] != 0xFF {
+			return nil, fmt.Errorf("invalid JPEG marker %x", marker)
+		}
+
+		// start of scan or end of image - no more metadata
+		if marker[1] == 0xDA || marker[1] == 0xD9 {
+			return nil, ErrNoGPS
+		}
+
+		var length uint16
+		if err := binary.Read(r, binary.BigEndian, &length); err != nil {
+			return nil, ErrNoGPS
+		}
+		if length < 2 {
+			return nil, fmt.Errorf("invalid JPEG segment length %d", length)
+		}
+
+		size := int64(length) - 2
+		if marker[1] != 0xE1 {
+			if _, err := io.CopyN(io.Discard, r, size); err != nil {
+				return nil, ErrNoGPS
+			}
+			continue
+		}
+
+		data := make([]byte, size)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, ErrNoGPS
+		}
+
+		// APP1 is also used for XMP
+		if bytes.HasPrefix(data, exifHeader) {
+			return data[len(exifHeader):], nil
+		}
+	}
+}
+
+// readPNGExif returns the TIFF structure of the eXIf chunk.
+func readPNGExif(r io.Reader) ([]byte, error) {
+	if _, err := io.CopyN(io.Discard, r, int64(len(pngSignature))); err != nil {
+		return nil, err
+	}
+
+	var chunk struct {
+		Length uint32
+		Type   [4]byte
+	}
+	for {
+		if err := binary.Read(r, binary.BigEndian, &chunk); err != nil {
+			return nil, ErrNoGPS
+		}
+
+		switch string(chunk.Type[:]) {
+		case "IDAT", "IEND":
+			// exif must precede the image data
+			return nil, ErrNoGPS
+		case "eXIf":
+			if chunk.Length > pngMaxExifBytes {
+				return nil, ErrNoGPS
+			}
+			data := make([]byte, chunk.Length)
+			if _, err := io.ReadFull(r, data); err != nil {
+				return nil, ErrNoGPS
+			}
+			return data, nil
+		}
+
+		// skip data and crc
+		if _, err := io.CopyN(io.Discard, r, int64(chunk.Length)+4); err != nil {
+			return nil, ErrNoGPS
+		}
+	}
+}
+
+type exifIFDEntry struct {
+	typ    uint16
+	count  uint32
+	offset uint32
+	// raw value field, for values of four bytes or fewer
+	value []byte
+}
+
+type exifReader struct {
+	data  []byte
+	order binary.ByteOrder
+}
+
+func (r *exifReader) readIFD(offset uint32) (map[uint16]exifIFDEntry, error) {
+	if int64(offset)+2 > int64(len(r.data)) {
+		return nil, errInvalidTag
+	}
+
+	n := int(r.order.Uint16(r.data[offset:]))
+	start := int64(offset) + 2
+	if start+int64(n)*exifIFDEntrySize > int64(len(r.data)) {
+		return nil, errInvalidTag
+	}
+
+	ret := make(map[uint16]exifIFDEntry, n)
+	for i := 0; i < n; i++ {
+		e := r.data[start+int64(i)*exifIFDEntrySize:]
+		ret[r.order.Uint16(e)] = exifIFDEntry{
+			typ:    r.order.Uint16(e[2:]),
+			count:  r.order.Uint32(e[4:]),
+			offset: r.order.Uint32(e[8:]),
+			value:  e[8:12],
+		}
+	}
+
+	return ret, nil
+}
+
+// degrees returns the decimal degrees of a GPS coordinate, which is stored
+// as three rationals of degrees, minutes and seconds.
+func (r *exifReader) degrees(e exifIFDEntry) (float64, error) {
+	const rationalSize = 8
+	if e.typ != exifTypeRational || e.count != 3 || int64(e.offset)+3*rationalSize > int64(len(r.data)) {
+		return 0, errInvalidTag
+	}
+
+	var ret float64
+	divisor := 1.0
+	for i := uint32(0); i < 3; i++ {
+		b := r.data[e.offset+i*rationalSize:]
+		num := r.order.Uint32(b)
+		den := r.order.Uint32(b[4:])
+		if den == 0 {
+			return 0, errInvalidTag
+		}
+		ret += float64(num) / float64(den) / divisor
+		divisor *= 60
+	}
+
+	return ret, nil
+}
+
+func gpsRef(e exifIFDEntry) byte {
+	if e.typ != exifTypeASCII || e.count == 0 {
+		return 0
+	}
+	return e.value[0]
+}
+
+func parseExifGPS(data []byte) (float64, float64, error) {
+	if len(data) < 8 {
+		return 0, 0, errInvalidTag
+	}
+
+	r := &exifReader{data: data}
+	switch string(data[:4]) {
+	case "II*\x00":
+		r.order = binary.LittleEndian
+	case "MM\x00*":
+		r.order = binary.BigEndian
+	default:
+		return 0, 0, errInvalidTag
+	}
+
+	ifd0, err := r.readIFD(r.order.Uint32(data[4:]))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	gpsEntry, found := ifd0[exifTagGPSIFD]
+	if !found {
+		return 0, 0, ErrNoGPS
+	}
+
+	gps, err := r.readIFD(gpsEntry.offset)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	latEntry, latFound := gps[exifTagGPSLat]
+	lonEntry, lonFound := gps[exifTagGPSLon]
+	if !latFound || !lonFound {
+		return 0, 0, ErrNoGPS
+	}
+
+	lat, err := r.degrees(latEntry)
+	if err != nil {
+		return 0, 0, err
+	}
+	lon, err := r.degrees(lonEntry)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if gpsRef(gps[exifTagGPSLatRef]) == 'S' {
+		lat = -lat
+	}
+	if gpsRef(gps[exifTagGPSLonRef]) == 'W' {
+		lon = -lon
+	}
+
+	return lat, lon, nil
+}
@@ -0,0 +1,155 @@
+package image
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"testing"
+)
+
+type testRational [2]uint32
+
+// makeTestGPSTiff returns a TIFF structure with a GPS IFD containing the
+// given coordinates, expressed as degrees, minutes and seconds.
+func makeTestGPSTiff(order binary.ByteOrder, latRef byte, lat [3]testRational, lonRef byte, lon [3]testRational) []byte {
+	var buf bytes.Buffer
+	write := func(v interface{}) {
+		_ = binary.Write(&buf, order, v)
+	}
+
+	if order == binary.LittleEndian {
+		buf.WriteString("II*\x00")
+	} else {
+		buf.WriteString("MM\x00*")
+	}
+
+	const (
+		ifd0Offset = 8
+		// one entry
+		gpsIFDOffset = ifd0Offset + 2 + exifIFDEntrySize + 4
+		// four entries
+		latOffset = gpsIFDOffset + 2 + 4*exifIFDEntrySize + 4
+		lonOffset = latOffset + 3*8
+	)
+
+	write(uint32(ifd0Offset))
+
+	// IFD0
+	write(uint16(1))
+	write([]uint16{exifTagGPSIFD, 4})
+	write([]uint32{1, gpsIFDOffset})
+	write(uint32(0))
+
+	// GPS IFD
+	write(uint16(4))
+	write([]uint16{exifTagGPSLatRef, exifTypeASCII})
+	write(uint32(2))
+	buf.Write([]byte{latRef, 0, 0, 0})
+	write([]uint16{exifTagGPSLat, exifTypeRational})
+	write([]uint32{3, latOffset})
+	write([]uint16{exifTagGPSLonRef, exifTypeASCII})
+	write(uint32(2))
+	buf.Write([]byte{lonRef, 0, 0, 0})
+	write([]uint16{exifTagGPSLon, exifTypeRational})
+	write([]uint32{3, lonOffset})
+	write(uint32(0))
+
+	for _, r := range lat {
+		write(r[:])
+	}
+	for _, r := range lon {
+		write(r[:])
+	}
+
+	return buf.Bytes()
+}
+
+func makeTestJPEG(tiff []byte) []byte {
+	var buf bytes.Buffer
+	buf.Write(jpegSOI)
+
+	// APP0 segment before exif
+	buf.Write([]byte{0xFF, 0xE0, 0x00, 0x04, 0x00, 0x00})
+
+	if tiff != nil {
+		buf.Write([]byte{0xFF, 0xE1})
+		_ = binary.Write(&buf, binary.BigEndian, uint16(2+len(exifHeader)+len(tiff)))
+		buf.Write(exifHeader)
+		buf.Write(tiff)
+	}
+
+	// start of scan
+	buf.Write([]byte{0xFF, 0xDA})
+	return buf.Bytes()
+}
+
+func makeTestPNG(tiff []byte) []byte {
+	var buf bytes.Buffer
+	buf.Write(pngSignature)
+
+	writeChunk := func(typ string, data []byte) {
+		_ = binary.Write(&buf, binary.BigEndian, uint32(len(data)))
+		buf.WriteString(typ)
+		buf.Write(data)
+		// crc is not checked
+		buf.Write([]byte{0, 0, 0, 0})
+	}
+
+	writeChunk("IHDR", make([]byte, 13))
+	if tiff != nil {
+		writeChunk("eXIf", tiff)
+	}
+	writeChunk("IDAT", nil)
+	return buf.Bytes()
+}
+
+func TestReadGPSCoordinates(t *testing.T) {
+	// 51° 30' 26.4" N, 0° 7' 39.6" W
+	london := makeTestGPSTiff(binary.BigEndian, 'N',
+		[3]testRational{{51, 1}, {30, 1}, {264, 10}},
+		'W',
+		[3]testRational{{0, 1}, {7, 1}, {396, 10}},
+	)
+	// 33° 51' 0" S, 151° 12' 36" E
+	sydney := makeTestGPSTiff(binary.LittleEndian, 'S',
+		[3]testRational{{33, 1}, {51, 1}, {0, 1}},
+		'E',
+		[3]testRational{{151, 1}, {12, 1}, {36, 1}},
+	)
+	invalid := makeTestGPSTiff(binary.LittleEndian, 'N',
+		[3]testRational{{33, 0}, {51, 1}, {0, 1}},
+		'E',
+		[3]testRational{{151, 1}, {12, 1}, {36, 1}},
+	)
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantLat float64
+		wantLon float64
+		wantErr error
+	}{
+		{"jpeg big endian", makeTestJPEG(london), 51.5073, -0.1277, nil},
+		{"jpeg little endian", makeTestJPEG(sydney), -33.85, 151.21, nil},
+		{"png", makeTestPNG(sydney), -33.85, 151.21, nil},
+		{"jpeg without exif", makeTestJPEG(nil), 0, 0, ErrNoGPS},
+		{"png without exif", makeTestPNG(nil), 0, 0, ErrNoGPS},
+		{"gif", []byte("GIF89a"), 0, 0, ErrNoGPS},
+		{"zero denominator", makeTestJPEG(invalid), 0, 0, errInvalidTag},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lat, lon, err := ReadGPSCoordinates(bytes.NewReader(tt.data))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ReadGPSCoordinates() error = %v, want %v", err, tt.wantErr)
+			}
+
+			const tolerance = 0.0001
+			if math.Abs(lat-tt.wantLat) > tolerance || math.Abs(lon-tt.wantLon) > tolerance {
+				t.Errorf("ReadGPSCoordinates() = %v, %v, want %v, %v", lat, lon, tt.wantLat, tt.wantLon)
+			}
+		})
+	}
+}
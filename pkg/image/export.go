@@ -16,6 +16,9 @@ func ToBasicJSON(image *models.Image) *jsonschema.Image {
 	newImageJSON := jsonschema.Image{
 		Title:     image.Title,
 		URL:       image.URL,
+		Location:  image.Location,
+		Latitude:  image.Latitude,
+		Longitude: image.Longitude,
 		CreatedAt: json.JSONTime{Time: image.CreatedAt},
 		UpdatedAt: json.JSONTime{Time: image.UpdatedAt},
 	}
@@ -29,6 +32,7 @@ func ToBasicJSON(image *models.Image) *jsonschema.Image {
 	}
 
 	newImageJSON.Organized = image.Organized
+	newImageJSON.Hidden = image.Hidden
 	newImageJSON.OCounter = image.OCounter
 
 	for _, f := range image.Files.List() {
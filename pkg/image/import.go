@@ -74,6 +74,7 @@ func (i *Importer) imageJSONToImage(imageJSON jsonschema.Image) models.Image {
 
 		Title:     imageJSON.Title,
 		Organized: imageJSON.Organized,
+		Hidden:    imageJSON.Hidden,
 		OCounter:  imageJSON.OCounter,
 		CreatedAt: imageJSON.CreatedAt.GetTime(),
 		UpdatedAt: imageJSON.UpdatedAt.GetTime(),
@@ -88,6 +89,11 @@ func (i *Importer) imageJSONToImage(imageJSON jsonschema.Image) models.Image {
 	if imageJSON.URL != "" {
 		newImage.URL = imageJSON.URL
 	}
+	if imageJSON.Location != "" {
+		newImage.Location = imageJSON.Location
+	}
+	newImage.Latitude = imageJSON.Latitude
+	newImage.Longitude = imageJSON.Longitude
 	if imageJSON.Date != "" {
 		d := models.NewDate(imageJSON.Date)
 		newImage.Date = &d
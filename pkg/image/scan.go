@@ -114,6 +114,8 @@ func (h *ScanHandler) Handle(ctx context.Context, f file.File, oldFile file.File
 
 		logger.Infof("%s doesn't exist. Creating new image...", f.Base().Path)
 
+		setCoordinatesFromExif(newImage, imageFile)
+
 		if _, err := h.associateGallery(ctx, newImage, imageFile); err != nil {
 			return err
 		}
@@ -335,3 +337,29 @@ func (h *ScanHandler) associateGallery(ctx context.Context, newImage *models.Ima
 
 	return ret, nil
 }
+
+// setCoordinatesFromExif sets the coordinates of a new image from the EXIF GPS
+// data of its file, if present.
+func setCoordinatesFromExif(i *models.Image, f *file.ImageFile) {
+	if f.Format != "jpeg" && f.Format != "png" {
+		return
+	}
+
+	r, err := f.Open(&file.OsFS{})
+	if err != nil {
+		logger.Debugf("Error reading EXIF data of %s: %v", f.Path, err)
+		return
+	}
+	defer r.Close()
+
+	latitude, longitude, err := ReadGPSCoordinates(r)
+	if err != nil {
+		if !errors.Is(err, ErrNoGPS) {
+			logger.Debugf("Error reading EXIF GPS data of %s: %v", f.Path, err)
+		}
+		return
+	}
+
+	i.Latitude = &latitude
+	i.Longitude = &longitude
+}
@@ -20,6 +20,9 @@ const ffmpegImageQuality = 5
 var vipsPath string
 var once sync.Once
 
+var vipsThumbnailPath string
+var vipsThumbnailOnce sync.Once
+
 var (
 	ErrUnsupportedImageFormat = errors.New("unsupported image format")
 
@@ -32,8 +35,9 @@ type ThumbnailGenerator interface {
 }
 
 type ThumbnailEncoder struct {
-	ffmpeg ffmpeg.FFMpeg
-	vips   *vipsEncoder
+	ffmpeg        ffmpeg.FFMpeg
+	vips          *vipsEncoder
+	vipsThumbnail *vipsThumbnailEncoder
 }
 
 func GetVipsPath() string {
@@ -43,6 +47,15 @@ func GetVipsPath() string {
 	return vipsPath
 }
 
+// GetVipsThumbnailPath returns the path of the vipsthumbnail tool, which is
+// installed alongside vips.
+func GetVipsThumbnailPath() string {
+	vipsThumbnailOnce.Do(func() {
+		vipsThumbnailPath, _ = exec.LookPath("vipsthumbnail")
+	})
+	return vipsThumbnailPath
+}
+
 func NewThumbnailEncoder(ffmpegEncoder ffmpeg.FFMpeg) ThumbnailEncoder {
 	ret := ThumbnailEncoder{
 		ffmpeg: ffmpegEncoder,
@@ -54,6 +67,11 @@ func NewThumbnailEncoder(ffmpegEncoder ffmpeg.FFMpeg) ThumbnailEncoder {
 		ret.vips = &vipsEncoder
 	}
 
+	if p := GetVipsThumbnailPath(); p != "" {
+		vipsThumbnailEncoder := vipsThumbnailEncoder(p)
+		ret.vipsThumbnail = &vipsThumbnailEncoder
+	}
+
 	return ret
 }
 
@@ -0,0 +1,172 @@
+package image
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/exec"
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+// ThumbnailBatchItem is an image file and the path its thumbnail is written
+// to.
+type ThumbnailBatchItem struct {
+	File       *file.ImageFile
+	OutputPath string
+}
+
+// GenerateThumbnails writes the thumbnails of the items, resized to the
+// provided max size, to their output paths. JPEG and PNG files outside of zip
+// files are resized by a single vipsthumbnail process when it is available,
+// which avoids starting a process per image and uses the shrink-on-load and
+// vectorised resize of libvips. Other files are encoded individually using
+// GetThumbnail.
+//
+// It returns the errors of the items which failed, keyed by their index.
+func (e *ThumbnailEncoder) GenerateThumbnails(ctx context.Context, items []ThumbnailBatchItem, maxSize int) map[int]error {
+	ret := make(map[int]error)
+
+	var batch []int
+	for i, item := range items {
+		if e.canBatch(item.File) {
+			batch = append(batch, i)
+			continue
+		}
+
+		if err := e.writeThumbnail(item, maxSize); err != nil {
+			ret[i] = err
+		}
+	}
+
+	if len(batch) == 0 {
+		return ret
+	}
+
+	inputs := make([]string, len(batch))
+	outputs := make([]string, len(batch))
+	for i, index := range batch {
+		inputs[i] = items[index].File.Path
+		outputs[i] = items[index].OutputPath
+	}
+
+	for i, err := range e.vipsThumbnail.BatchThumbnail(ctx, inputs, outputs, maxSize) {
+		if err != nil {
+			ret[batch[i]] = err
+		}
+	}
+
+	return ret
+}
+
+func (e *ThumbnailEncoder) canBatch(f *file.ImageFile) bool {
+	// symlinks are used to name the batch inputs, which are not reliably
+	// available on Windows
+	if e.vipsThumbnail == nil || runtime.GOOS == "windows" || f.ZipFileID != nil {
+		return false
+	}
+
+	return f.Format == "jpeg" || f.Format == "png"
+}
+
+func (e *ThumbnailEncoder) writeThumbnail(item ThumbnailBatchItem, maxSize int) error {
+	data, err := e.GetThumbnail(item.File, maxSize)
+	if err != nil {
+		return err
+	}
+
+	return fsutil.WriteFile(item.OutputPath, data)
+}
+
+type vipsThumbnailEncoder string
+
+// BatchThumbnail resizes the input files to JPEG thumbnails written to the
+// corresponding output paths using a single process. The inputs are linked
+// into a temporary directory under unique names, since vipsthumbnail names
+// its outputs after the basename of the inputs.
+//
+// It returns an error for each input, which is nil if its thumbnail was
+// written.
+func (e *vipsThumbnailEncoder) BatchThumbnail(ctx context.Context, inputs []string, outputs []string, maxSize int) []error {
+	errs := make([]error, len(inputs))
+	setAll := func(err error) []error {
+		for i := range errs {
+			if errs[i] == nil {
+				errs[i] = err
+			}
+		}
+		return errs
+	}
+
+	dir, err := os.MkdirTemp("", "stash-thumbnails-")
+	if err != nil {
+		return setAll(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// the outputs are written to a separate directory, so that they can never
+	// be written through the links to the inputs
+	inDir := filepath.Join(dir, "in")
+	outDir := filepath.Join(dir, "out")
+	for _, d := range []string{inDir, outDir} {
+		if err := os.Mkdir(d, 0755); err != nil {
+			return setAll(err)
+		}
+	}
+
+	args := []string{
+		"--size", fmt.Sprintf("%dx%d>", maxSize, maxSize),
+		"-o", filepath.Join(outDir, "%s.jpg[Q=70,strip]"),
+	}
+
+	for i, input := range inputs {
+		link := filepath.Join(inDir, strconv.Itoa(i)+filepath.Ext(input))
+		if err := os.Symlink(input, link); err != nil {
+			errs[i] = err
+			continue
+		}
+		args = append(args, link)
+	}
+
+	cmd := exec.CommandContext(ctx, string(*e), args...)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	// vipsthumbnail continues after failing to resize an input, so the
+	// outputs which were written are used even if it returns an error
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return setAll(ctx.Err())
+		}
+		logger.Errorf("image encoder error when running command <%s>: %s", strings.Join(cmd.Args[:5], " "), stderr.String())
+	}
+
+	for i, output := range outputs {
+		if errs[i] != nil {
+			continue
+		}
+
+		thumb := filepath.Join(outDir, strconv.Itoa(i)+".jpg")
+		if _, err := os.Stat(thumb); err != nil {
+			errs[i] = fmt.Errorf("vipsthumbnail did not create a thumbnail of %s", inputs[i])
+			continue
+		}
+
+		if err := fsutil.EnsureDirAll(filepath.Dir(output)); err != nil {
+			errs[i] = err
+			continue
+		}
+
+		errs[i] = fsutil.SafeMove(thumb, output)
+	}
+
+	return errs
+}
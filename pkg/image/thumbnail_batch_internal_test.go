@@ -0,0 +1,106 @@
+package image
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThumbnailEncoder_canBatch(t *testing.T) {
+	vipsThumbnail := vipsThumbnailEncoder("vipsthumbnail")
+	zipID := file.ID(1)
+
+	tests := []struct {
+		name    string
+		encoder ThumbnailEncoder
+		f       *file.ImageFile
+		want    bool
+	}{
+		{"jpeg", ThumbnailEncoder{vipsThumbnail: &vipsThumbnail}, &file.ImageFile{BaseFile: &file.BaseFile{}, Format: "jpeg"}, true},
+		{"png", ThumbnailEncoder{vipsThumbnail: &vipsThumbnail}, &file.ImageFile{BaseFile: &file.BaseFile{}, Format: "png"}, true},
+		{"webp", ThumbnailEncoder{vipsThumbnail: &vipsThumbnail}, &file.ImageFile{BaseFile: &file.BaseFile{}, Format: formatWebP}, false},
+		{"zip", ThumbnailEncoder{vipsThumbnail: &vipsThumbnail}, &file.ImageFile{BaseFile: &file.BaseFile{DirEntry: file.DirEntry{ZipFileID: &zipID}}, Format: "jpeg"}, false},
+		{"no vipsthumbnail", ThumbnailEncoder{}, &file.ImageFile{BaseFile: &file.BaseFile{}, Format: "jpeg"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := tt.want && runtime.GOOS != "windows"
+			assert.Equal(t, want, tt.encoder.canBatch(tt.f))
+		})
+	}
+}
+
+// fakeVipsThumbnail copies each input to the output format, skipping inputs
+// named fail.
+const fakeVipsThumbnail = `#!/bin/sh
+shift 2
+out="$2"
+shift 2
+status=0
+for f in "$@"; do
+  if [ "$(cat "$f")" = "fail" ]; then
+    status=1
+    continue
+  fi
+  name=$(basename "$f")
+  cp "$f" "$(echo "${out%%\[*}" | sed "s|%s|${name%.*}|")"
+done
+exit $status
+`
+
+func TestVipsThumbnailEncoder_BatchThumbnail(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("batch thumbnails are not supported on Windows")
+	}
+
+	dir := t.TempDir()
+	script := filepath.Join(dir, "vipsthumbnail")
+	if err := os.WriteFile(script, []byte(fakeVipsThumbnail), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	contents := []string{"a", "fail", "b"}
+	var inputs, outputs []string
+	for i, c := range contents {
+		// inputs share a basename in different directories
+		input := filepath.Join(dir, "in", string(rune('a'+i)), "image.jpg")
+		if err := os.MkdirAll(filepath.Dir(input), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(input, []byte(c), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		inputs = append(inputs, input)
+		outputs = append(outputs, filepath.Join(dir, "out", c+".jpg"))
+	}
+
+	e := vipsThumbnailEncoder(script)
+	errs := e.BatchThumbnail(context.Background(), inputs, outputs, 640)
+
+	assert.Len(t, errs, 3)
+	assert.Nil(t, errs[0])
+	assert.NotNil(t, errs[1])
+	assert.Nil(t, errs[2])
+
+	// the inputs must not be modified
+	for i, input := range inputs {
+		data, err := os.ReadFile(input)
+		if assert.Nil(t, err) {
+			assert.Equal(t, contents[i], string(data))
+		}
+	}
+
+	for _, i := range []int{0, 2} {
+		data, err := os.ReadFile(outputs[i])
+		if assert.Nil(t, err) {
+			assert.Equal(t, contents[i], string(data))
+		}
+	}
+}
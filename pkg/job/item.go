@@ -0,0 +1,327 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ItemStatus is the status of a work item of a job.
+type ItemStatus string
+
+const (
+	// ItemStatusPending means that the item has not yet been processed.
+	ItemStatusPending ItemStatus = "PENDING"
+	// ItemStatusProcessing means that the item is being processed.
+	ItemStatusProcessing ItemStatus = "PROCESSING"
+	// ItemStatusDone means that the item was processed.
+	ItemStatusDone ItemStatus = "DONE"
+	// ItemStatusFailed means that processing the item failed.
+	ItemStatusFailed ItemStatus = "FAILED"
+	// ItemStatusSkipped means that the item was skipped. Items skipped while
+	// processing have their processing cancelled.
+	ItemStatusSkipped ItemStatus = "SKIPPED"
+)
+
+// ErrItemNotQueued is returned when changing the priority of an item which is
+// not waiting in the queue.
+var ErrItemNotQueued = errors.New("item is not queued")
+
+// Item is a work item of a job, such as a file to scan or generate.
+type Item struct {
+	ID          int
+	Description string
+	Status      ItemStatus
+	// Priority determines the order in which queued items are processed.
+	// Items of higher priority are processed first, and items of the same
+	// priority in the order they were added.
+	Priority  int
+	Error     string
+	StartTime *time.Time
+	EndTime   *time.Time
+
+	value  interface{}
+	queued bool
+	cancel context.CancelFunc
+}
+
+// Value returns the value pushed with the item.
+func (i *Item) Value() interface{} {
+	return i.value
+}
+
+// ItemQueue tracks the work items of a job, so that they can be listed and
+// skipped while the job is running. Items added with Push are processed in
+// order of priority, which may be changed while they are queued.
+type ItemQueue struct {
+	mutex  sync.Mutex
+	items  []*Item
+	byID   map[int]*Item
+	lastID int
+
+	// queued items, in the order they are popped
+	queue  []*Item
+	closed bool
+	// signalled when an item is queued or the queue is closed
+	notify chan struct{}
+}
+
+func NewItemQueue() *ItemQueue {
+	return &ItemQueue{
+		byID:   make(map[int]*Item),
+		notify: make(chan struct{}, 1),
+	}
+}
+
+func (q *ItemQueue) add(description string, value interface{}) *Item {
+	// assumes lock held
+	q.lastID++
+	ret := &Item{
+		ID:          q.lastID,
+		Description: description,
+		Status:      ItemStatusPending,
+		value:       value,
+	}
+
+	q.items = append(q.items, ret)
+	q.byID[ret.ID] = ret
+	return ret
+}
+
+// Track adds a pending item which is processed outside of the queue, in an
+// order determined by the job. Track is used by jobs which cannot reorder
+// their items; the priority of tracked items cannot be changed.
+func (q *ItemQueue) Track(description string) *Item {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	return q.add(description, nil)
+}
+
+// Push adds a pending item to the queue.
+func (q *ItemQueue) Push(description string, value interface{}) *Item {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	ret := q.add(description, value)
+	ret.queued = true
+	q.insert(ret)
+	q.signal()
+
+	return ret
+}
+
+func (q *ItemQueue) insert(item *Item) {
+	// assumes lock held
+	index := sort.Search(len(q.queue), func(i int) bool {
+		qi := q.queue[i]
+		return qi.Priority < item.Priority || (qi.Priority == item.Priority && qi.ID > item.ID)
+	})
+
+	q.queue = append(q.queue, nil)
+	copy(q.queue[index+1:], q.queue[index:])
+	q.queue[index] = item
+}
+
+func (q *ItemQueue) remove(item *Item) {
+	// assumes lock held
+	for i, qi := range q.queue {
+		if qi == item {
+			q.queue = append(q.queue[:i], q.queue[i+1:]...)
+			return
+		}
+	}
+}
+
+func (q *ItemQueue) signal() {
+	// assumes lock held
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+}
+
+// Close notifies that no more items will be pushed.
+func (q *ItemQueue) Close() {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.closed = true
+	q.signal()
+}
+
+// Pop removes the queued item of the highest priority from the queue,
+// waiting until an item is pushed if the queue is empty. Returns false if
+// the queue is closed and empty, or the context is cancelled.
+func (q *ItemQueue) Pop(ctx context.Context) (*Item, bool) {
+	for {
+		q.mutex.Lock()
+		if len(q.queue) > 0 {
+			ret := q.queue[0]
+			q.queue = q.queue[1:]
+			ret.queued = false
+
+			// wake other waiting consumers
+			if len(q.queue) > 0 {
+				q.signal()
+			}
+			q.mutex.Unlock()
+			return ret, true
+		}
+
+		if q.closed {
+			// wake other waiting consumers
+			q.signal()
+			q.mutex.Unlock()
+			return nil, false
+		}
+		q.mutex.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, false
+		case <-q.notify:
+		}
+	}
+}
+
+// Start marks the item as processing. The returned context is cancelled if
+// the item is skipped while it is processed. Returns false if the item was
+// skipped.
+func (q *ItemQueue) Start(ctx context.Context, item *Item) (context.Context, bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if item.Status == ItemStatusSkipped {
+		return ctx, false
+	}
+
+	// items may be processed again, such as when they are retried
+	ctx, item.cancel = context.WithCancel(ctx)
+
+	t := time.Now()
+	item.Status = ItemStatusProcessing
+	item.StartTime = &t
+	item.EndTime = nil
+	item.Error = ""
+
+	return ctx, true
+}
+
+// Finish marks the item as done, or failed if err is not nil. Skipped items
+// remain skipped.
+func (q *ItemQueue) Finish(item *Item, err error) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if item.cancel != nil {
+		item.cancel()
+		item.cancel = nil
+	}
+
+	if item.Status == ItemStatusSkipped {
+		return
+	}
+
+	t := time.Now()
+	item.EndTime = &t
+	item.Status = ItemStatusDone
+	if err != nil {
+		item.Status = ItemStatusFailed
+		item.Error = err.Error()
+	}
+}
+
+// Skip skips the pending or processing item with the id. Processing of the
+// item is cancelled.
+func (q *ItemQueue) Skip(id int) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	item := q.byID[id]
+	if item == nil {
+		return fmt.Errorf("item %d not found", id)
+	}
+
+	switch item.Status {
+	case ItemStatusPending:
+		if item.queued {
+			q.remove(item)
+			item.queued = false
+		}
+	case ItemStatusProcessing:
+		if item.cancel != nil {
+			item.cancel()
+			item.cancel = nil
+		}
+	default:
+		return fmt.Errorf("item %d is %s", id, item.Status)
+	}
+
+	t := time.Now()
+	item.Status = ItemStatusSkipped
+	item.EndTime = &t
+
+	return nil
+}
+
+// SetPriority changes the priority of the queued item with the id.
+func (q *ItemQueue) SetPriority(id int, priority int) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	item := q.byID[id]
+	if item == nil {
+		return fmt.Errorf("item %d not found", id)
+	}
+
+	if !item.queued {
+		return fmt.Errorf("item %d: %w", id, ErrItemNotQueued)
+	}
+
+	q.remove(item)
+	item.Priority = priority
+	q.insert(item)
+
+	return nil
+}
+
+// List returns copies of the items with the statuses, or of all items if no
+// statuses are provided. Queued items are returned first, in the order they
+// will be processed, followed by the other items in the order they were
+// added.
+func (q *ItemQueue) List(statuses ...ItemStatus) []Item {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	include := func(i *Item) bool {
+		if len(statuses) == 0 {
+			return true
+		}
+
+		for _, s := range statuses {
+			if i.Status == s {
+				return true
+			}
+		}
+		return false
+	}
+
+	ret := []Item{}
+	for _, i := range q.queue {
+		if include(i) {
+			ret = append(ret, *i)
+		}
+	}
+
+	for _, i := range q.items {
+		if !i.queued && include(i) {
+			ret = append(ret, *i)
+		}
+	}
+
+	return ret
+}
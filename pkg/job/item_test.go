@@ -0,0 +1,163 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func itemDescriptions(items []Item) []string {
+	var ret []string
+	for _, i := range items {
+		ret = append(ret, i.Description)
+	}
+	return ret
+}
+
+func TestItemQueuePriority(t *testing.T) {
+	ctx := context.Background()
+	q := NewItemQueue()
+
+	a := q.Push("a", 1)
+	q.Push("b", 2)
+	c := q.Push("c", 3)
+
+	assert := assert.New(t)
+	assert.NoError(q.SetPriority(c.ID, 1))
+	assert.Equal([]string{"c", "a", "b"}, itemDescriptions(q.List()))
+
+	item, ok := q.Pop(ctx)
+	assert.True(ok)
+	assert.Equal(3, item.Value())
+
+	// popped items are no longer queued
+	assert.ErrorIs(q.SetPriority(c.ID, 2), ErrItemNotQueued)
+
+	assert.NoError(q.SetPriority(a.ID, -1))
+	item, _ = q.Pop(ctx)
+	assert.Equal("b", item.Description)
+
+	q.Close()
+	item, _ = q.Pop(ctx)
+	assert.Equal("a", item.Description)
+
+	_, ok = q.Pop(ctx)
+	assert.False(ok)
+}
+
+func TestItemQueuePopWaits(t *testing.T) {
+	q := NewItemQueue()
+
+	popped := make(chan *Item)
+	go func() {
+		item, _ := q.Pop(context.Background())
+		popped <- item
+	}()
+
+	time.Sleep(sleepTime)
+	q.Push("a", nil)
+
+	select {
+	case item := <-popped:
+		assert.Equal(t, "a", item.Description)
+	case <-time.After(time.Second):
+		t.Error("item was not popped")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, ok := q.Pop(ctx)
+	assert.False(t, ok)
+}
+
+func TestItemQueueSkip(t *testing.T) {
+	ctx := context.Background()
+	q := NewItemQueue()
+
+	a := q.Push("a", nil)
+	b := q.Push("b", nil)
+	c := q.Push("c", nil)
+
+	assert := assert.New(t)
+
+	// skip while processing
+	item, _ := q.Pop(ctx)
+	itemCtx, started := q.Start(ctx, item)
+	assert.True(started)
+	assert.NoError(q.Skip(a.ID))
+	assert.Error(itemCtx.Err())
+	q.Finish(item, itemCtx.Err())
+
+	// skip while queued
+	assert.NoError(q.Skip(b.ID))
+
+	item, _ = q.Pop(ctx)
+	assert.Equal(c.ID, item.ID)
+	_, started = q.Start(ctx, item)
+	assert.True(started)
+	q.Finish(item, errors.New("failed"))
+
+	items := q.List()
+	assert.Equal([]ItemStatus{ItemStatusSkipped, ItemStatusSkipped, ItemStatusFailed}, []ItemStatus{items[0].Status, items[1].Status, items[2].Status})
+	assert.Equal("failed", items[2].Error)
+
+	// finished items cannot be skipped
+	assert.Error(q.Skip(c.ID))
+	assert.Error(q.Skip(100))
+}
+
+func TestItemQueueTrack(t *testing.T) {
+	ctx := context.Background()
+	q := NewItemQueue()
+
+	a := q.Track("a")
+	b := q.Track("b")
+
+	assert := assert.New(t)
+	assert.ErrorIs(q.SetPriority(a.ID, 1), ErrItemNotQueued)
+
+	assert.NoError(q.Skip(b.ID))
+	_, started := q.Start(ctx, b)
+	assert.False(started)
+
+	_, started = q.Start(ctx, a)
+	assert.True(started)
+
+	assert.Equal([]string{"a"}, itemDescriptions(q.List(ItemStatusProcessing)))
+	assert.Equal([]string{"b"}, itemDescriptions(q.List(ItemStatusSkipped, ItemStatusDone)))
+
+	q.Finish(a, nil)
+	assert.Equal([]string{"a", "b"}, itemDescriptions(q.List(ItemStatusSkipped, ItemStatusDone)))
+}
+
+func TestManagerJobItems(t *testing.T) {
+	m := NewManager()
+
+	exec := newTestExec(make(chan struct{}))
+	jobID := m.Add(context.Background(), "test", exec)
+	<-exec.started
+
+	assert := assert.New(t)
+
+	// job does not track items
+	_, err := m.GetJobItems(jobID)
+	assert.Error(err)
+
+	item := exec.progress.Items().Push("a", nil)
+	exec.progress.Items().Push("b", nil)
+
+	assert.NoError(m.SetJobItemPriority(jobID, item.ID+1, 1))
+	assert.NoError(m.SkipJobItem(jobID, item.ID))
+
+	items, err := m.GetJobItems(jobID, ItemStatusPending)
+	assert.NoError(err)
+	assert.Equal([]string{"b"}, itemDescriptions(items))
+
+	_, err = m.GetJobItems(jobID + 1)
+	assert.Error(err)
+
+	close(exec.finish)
+}
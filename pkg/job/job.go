@@ -42,6 +42,35 @@ const (
 	StatusCancelled Status = "CANCELLED"
 	// StatusFailed means that the job failed.
 	StatusFailed Status = "FAILED"
+	// StatusPaused means that the job is not yet started, and will not be
+	// started until it is resumed.
+	StatusPaused Status = "PAUSED"
+)
+
+// Priority determines the order in which queued jobs are started. Jobs of
+// higher priority are started before jobs of lower priority. Jobs of the
+// same priority are started in the order they were added.
+type Priority int
+
+const (
+	PriorityLow    Priority = -1
+	PriorityNormal Priority = 0
+	PriorityHigh   Priority = 1
+)
+
+// Class is the concurrency class of a Job. At most one job of each class
+// runs at a time, and jobs of different classes run concurrently, except for
+// exclusive jobs, which only run when no other job is running.
+type Class string
+
+const (
+	// ClassExclusive jobs, such as scanning and importing, run alone.
+	ClassExclusive Class = "EXCLUSIVE"
+	// ClassCPU jobs are CPU-heavy, such as generating and transcoding.
+	ClassCPU Class = "CPU"
+	// ClassMetadata jobs are IO-light metadata jobs, such as identifying
+	// and scraping.
+	ClassMetadata Class = "METADATA"
 )
 
 // Job represents the status of a queued or running job.
@@ -52,14 +81,31 @@ type Job struct {
 	Details     []string
 	Description string
 	// Progress in terms of 0 - 1.
-	Progress  float64
+	Progress float64
+	// Number of work units processed, such as files scanned or generated,
+	// out of Total. Total is 0 if the number of work units is not known.
+	Processed int
+	Total     int
 	StartTime *time.Time
 	EndTime   *time.Time
 	AddTime   time.Time
+	Priority  Priority
+	Class     Class
 
 	outerCtx   context.Context
 	exec       JobExec
 	cancelFunc context.CancelFunc
+
+	// work items of the job, if it tracks them
+	items *ItemQueue
+
+	// started immediately with Manager.Start, regardless of class
+	unqueued bool
+}
+
+// queued returns true if the job has not yet started.
+func (j *Job) queued() bool {
+	return j.Status == StatusReady || j.Status == StatusPaused
 }
 
 // TimeElapsed returns the total time elapsed for the job.
@@ -75,8 +121,21 @@ func (j *Job) TimeElapsed() time.Duration {
 	return end.Sub(*j.StartTime)
 }
 
+// EstimatedEndTime returns the time at which the running job is expected to
+// finish, extrapolated from its progress so far. Returns nil if the job is
+// not running or its progress is unknown.
+func (j *Job) EstimatedEndTime(now time.Time) *time.Time {
+	if j.Status != StatusRunning || j.StartTime == nil || j.Progress <= 0 || j.Progress > 1 {
+		return nil
+	}
+
+	elapsed := now.Sub(*j.StartTime)
+	ret := j.StartTime.Add(time.Duration(float64(elapsed) / j.Progress))
+	return &ret
+}
+
 func (j *Job) cancel() {
-	if j.Status == StatusReady {
+	if j.queued() {
 		j.Status = StatusCancelled
 	} else if j.Status == StatusRunning {
 		j.Status = StatusStopping
@@ -87,6 +146,21 @@ func (j *Job) cancel() {
 	}
 }
 
+type infoKey struct{}
+
+// Info identifies a running job.
+type Info struct {
+	ID          int
+	Description string
+}
+
+// GetInfo returns the job running with the context. Returns false if the
+// context is not that of a job.
+func GetInfo(ctx context.Context) (Info, bool) {
+	ret, ok := ctx.Value(infoKey{}).(Info)
+	return ret, ok
+}
+
 // IsCancelled returns true if cancel has been called on the context.
 func IsCancelled(ctx context.Context) bool {
 	select {
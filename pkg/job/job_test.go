@@ -0,0 +1,39 @@
+package job
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobEstimatedEndTime(t *testing.T) {
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start.Add(time.Minute)
+	want := start.Add(4 * time.Minute)
+
+	tests := []struct {
+		name      string
+		status    Status
+		startTime *time.Time
+		progress  float64
+		want      *time.Time
+	}{
+		{"running", StatusRunning, &start, 0.25, &want},
+		{"not started", StatusReady, nil, 0, nil},
+		{"indefinite", StatusRunning, &start, ProgressIndefinite, nil},
+		{"no progress", StatusRunning, &start, 0, nil},
+		{"finished", StatusFinished, &start, 1, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j := &Job{
+				Status:    tt.status,
+				StartTime: tt.startTime,
+				Progress:  tt.progress,
+			}
+			assert.Equal(t, tt.want, j.EstimatedEndTime(now))
+		})
+	}
+}
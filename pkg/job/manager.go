@@ -2,6 +2,7 @@ package job
 
 import (
 	"context"
+	"fmt"
 	"runtime/debug"
 	"sync"
 	"time"
@@ -12,14 +13,18 @@ import (
 const maxGraveyardSize = 10
 const defaultThrottleLimit = 100 * time.Millisecond
 
-// Manager maintains a queue of jobs. Jobs are executed one at a time.
+// Manager maintains a queue of jobs. Jobs are started in order of priority,
+// and then in the order they were added. At most one job of each concurrency
+// class runs at a time.
 type Manager struct {
 	queue     []*Job
 	graveyard []*Job
 
-	mutex    sync.Mutex
-	notEmpty *sync.Cond
-	stop     chan struct{}
+	mutex   sync.Mutex
+	changed *sync.Cond
+	// dispatcher should check for jobs to start
+	pending bool
+	stopped bool
 
 	lastID int
 
@@ -30,11 +35,10 @@ type Manager struct {
 // NewManager initialises and returns a new Manager.
 func NewManager() *Manager {
 	ret := &Manager{
-		stop:                make(chan struct{}),
 		updateThrottleLimit: defaultThrottleLimit,
 	}
 
-	ret.notEmpty = sync.NewCond(&ret.mutex)
+	ret.changed = sync.NewCond(&ret.mutex)
 
 	go ret.dispatcher()
 
@@ -45,11 +49,31 @@ func NewManager() *Manager {
 // more Jobs will be processed.
 func (m *Manager) Stop() {
 	m.CancelAll()
-	close(m.stop)
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.stopped = true
+	m.changed.Broadcast()
 }
 
-// Add queues a job.
+// Add queues an exclusive job of normal priority.
 func (m *Manager) Add(ctx context.Context, description string, e JobExec) int {
+	return m.add(ctx, description, e, PriorityNormal, ClassExclusive)
+}
+
+// AddPriority queues an exclusive job of high priority, ahead of all ready
+// jobs of lower priority. Running jobs are not interrupted.
+func (m *Manager) AddPriority(ctx context.Context, description string, e JobExec) int {
+	return m.add(ctx, description, e, PriorityHigh, ClassExclusive)
+}
+
+// AddClass queues a job of normal priority in the provided concurrency
+// class.
+func (m *Manager) AddClass(ctx context.Context, description string, e JobExec, class Class) int {
+	return m.add(ctx, description, e, PriorityNormal, class)
+}
+
+func (m *Manager) add(ctx context.Context, description string, e JobExec, priority Priority, class Class) int {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
@@ -60,22 +84,36 @@ func (m *Manager) Add(ctx context.Context, description string, e JobExec) int {
 		Status:      StatusReady,
 		Description: description,
 		AddTime:     t,
+		Priority:    priority,
+		Class:       class,
 		exec:        e,
 		outerCtx:    ctx,
 	}
 
-	m.queue = append(m.queue, &j)
-
-	if len(m.queue) == 1 {
-		// notify that there is now a job in the queue
-		m.notEmpty.Broadcast()
-	}
-
+	m.enqueue(&j)
 	m.notifyNewJob(&j)
+	m.notifyChanged()
 
 	return j.ID
 }
 
+// enqueue inserts the job after the running jobs and the queued jobs of
+// equal or higher priority.
+func (m *Manager) enqueue(j *Job) {
+	// assumes lock held
+	index := len(m.queue)
+	for i, qj := range m.queue {
+		if qj.queued() && qj.Priority < j.Priority {
+			index = i
+			break
+		}
+	}
+
+	m.queue = append(m.queue, nil)
+	copy(m.queue[index+1:], m.queue[index:])
+	m.queue[index] = j
+}
+
 // Start adds a job and starts it immediately, concurrently with any other
 // jobs.
 func (m *Manager) Start(ctx context.Context, description string, e JobExec) int {
@@ -89,8 +127,10 @@ func (m *Manager) Start(ctx context.Context, description string, e JobExec) int
 		Status:      StatusReady,
 		Description: description,
 		AddTime:     t,
+		Priority:    PriorityNormal,
 		exec:        e,
 		outerCtx:    ctx,
+		unqueued:    true,
 	}
 
 	m.queue = append(m.queue, &j)
@@ -116,49 +156,68 @@ func (m *Manager) nextID() int {
 	return m.lastID
 }
 
-func (m *Manager) getReadyJob() *Job {
+// notifyChanged notifies the dispatcher that jobs may be ready to start.
+func (m *Manager) notifyChanged() {
 	// assumes lock held
-	for _, j := range m.queue {
-		if j.Status == StatusReady {
-			return j
-		}
-	}
-
-	return nil
+	m.pending = true
+	m.changed.Broadcast()
 }
 
 func (m *Manager) dispatcher() {
 	m.mutex.Lock()
+	defer m.mutex.Unlock()
 
 	for {
-		// wait until we have something to process
-		j := m.getReadyJob()
-
-		for j == nil {
-			m.notEmpty.Wait()
-
-			// it's possible that we have been stopped - check here
-			select {
-			case <-m.stop:
-				m.mutex.Unlock()
-				return
-			default:
-				// keep going
-				j = m.getReadyJob()
-			}
+		for !m.pending && !m.stopped {
+			m.changed.Wait()
 		}
 
-		done := m.dispatch(j.outerCtx, j)
+		if m.stopped {
+			return
+		}
 
-		// unlock the mutex and wait for the job to finish
-		m.mutex.Unlock()
-		<-done
-		m.mutex.Lock()
+		m.pending = false
+		m.dispatchReady()
+	}
+}
+
+// dispatchReady starts the ready jobs which may run alongside the running
+// jobs, in queue order. A waiting exclusive job blocks the jobs queued after
+// it, so that it is not starved by jobs of other classes.
+func (m *Manager) dispatchReady() {
+	// assumes lock held
+	running := make(map[Class]bool)
+	anyRunning := false
+	for _, j := range m.queue {
+		if !j.queued() && !j.unqueued {
+			running[j.Class] = true
+			anyRunning = true
+		}
+	}
+
+	for _, j := range m.queue {
+		if j.Status != StatusReady {
+			continue
+		}
+
+		if running[ClassExclusive] {
+			return
+		}
 
-		// remove the job from the queue
-		m.removeJob(j)
+		if j.Class == ClassExclusive {
+			if !anyRunning {
+				m.dispatch(j.outerCtx, j)
+			}
+			return
+		}
 
-		// process next job
+		if running[j.Class] {
+			continue
+		}
+
+		m.dispatch(j.outerCtx, j)
+		running[j.Class] = true
+		anyRunning = true
 	}
 }
 
@@ -172,25 +231,25 @@ func (m *Manager) newProgress(j *Job) *Progress {
 	}
 }
 
-func (m *Manager) dispatch(ctx context.Context, j *Job) (done chan struct{}) {
+func (m *Manager) dispatch(ctx context.Context, j *Job) {
 	// assumes lock held
 	t := time.Now()
 	j.StartTime = &t
 	j.Status = StatusRunning
 
-	ctx, cancelFunc := context.WithCancel(valueOnlyContext{ctx})
+	ctx = context.WithValue(valueOnlyContext{ctx}, infoKey{}, Info{
+		ID:          j.ID,
+		Description: j.Description,
+	})
+	ctx, cancelFunc := context.WithCancel(ctx)
 	j.cancelFunc = cancelFunc
 
-	done = make(chan struct{})
-	go m.executeJob(ctx, j, done)
+	go m.executeJob(ctx, j)
 
 	m.notifyJobUpdate(j)
-
-	return
 }
 
-func (m *Manager) executeJob(ctx context.Context, j *Job, done chan struct{}) {
-	defer close(done)
+func (m *Manager) executeJob(ctx context.Context, j *Job) {
 	defer m.onJobFinish(j)
 	defer func() {
 		if p := recover(); p != nil {
@@ -219,6 +278,10 @@ func (m *Manager) onJobFinish(job *Job) {
 	}
 	t := time.Now()
 	job.EndTime = &t
+
+	// remove the job from the queue and process the next jobs
+	m.removeJob(job)
+	m.notifyChanged()
 }
 
 func (m *Manager) removeJob(job *Job) {
@@ -276,18 +339,79 @@ func (m *Manager) CancelJob(id int) {
 		if j.Status == StatusCancelled {
 			// remove from the queue
 			m.removeJob(j)
+			m.notifyChanged()
 		}
 	}
 }
 
+// SetPriority changes the priority of a queued job, moving it in the queue
+// accordingly. Returns an error if the job is not queued.
+func (m *Manager) SetPriority(id int, priority Priority) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	index, j := m.getJob(m.queue, id)
+	if j == nil || !j.queued() {
+		return fmt.Errorf("job %d is not queued", id)
+	}
+
+	m.queue = append(m.queue[:index], m.queue[index+1:]...)
+	j.Priority = priority
+	m.enqueue(j)
+
+	m.notifyJobUpdate(j)
+	m.notifyChanged()
+
+	return nil
+}
+
+// PauseJob prevents a queued job from starting until it is resumed. Returns
+// an error if the job is not queued.
+func (m *Manager) PauseJob(id int) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	_, j := m.getJob(m.queue, id)
+	if j == nil || !j.queued() {
+		return fmt.Errorf("job %d is not queued", id)
+	}
+
+	j.Status = StatusPaused
+	m.notifyJobUpdate(j)
+
+	// jobs blocked by the paused job may now start
+	m.notifyChanged()
+
+	return nil
+}
+
+// ResumeJob allows a paused job to start. Returns an error if the job is not
+// paused.
+func (m *Manager) ResumeJob(id int) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	_, j := m.getJob(m.queue, id)
+	if j == nil || j.Status != StatusPaused {
+		return fmt.Errorf("job %d is not paused", id)
+	}
+
+	j.Status = StatusReady
+	m.notifyJobUpdate(j)
+	m.notifyChanged()
+
+	return nil
+}
+
 // CancelAll cancels all of the jobs in the queue. This is the same as
 // calling CancelJob on all jobs in the queue.
 func (m *Manager) CancelAll() {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
-	// call cancel on all
-	for _, j := range m.queue {
+	// call cancel on all - removing jobs modifies the queue
+	queue := append([]*Job(nil), m.queue...)
+	for _, j := range queue {
 		j.cancel()
 
 		if j.Status == StatusCancelled {
@@ -314,6 +438,53 @@ func (m *Manager) GetJob(id int) *Job {
 	return nil
 }
 
+func (m *Manager) getItems(id int) (*ItemQueue, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	_, j := m.getJob(append(m.queue, m.graveyard...), id)
+	if j == nil {
+		return nil, fmt.Errorf("job %d not found", id)
+	}
+
+	if j.items == nil {
+		return nil, fmt.Errorf("job %d does not track its items", id)
+	}
+
+	return j.items, nil
+}
+
+// GetJobItems returns copies of the work items of the job with the statuses,
+// or of all items if no statuses are provided.
+func (m *Manager) GetJobItems(id int, statuses ...ItemStatus) ([]Item, error) {
+	items, err := m.getItems(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return items.List(statuses...), nil
+}
+
+// SkipJobItem skips a pending or processing work item of the job.
+func (m *Manager) SkipJobItem(id int, itemID int) error {
+	items, err := m.getItems(id)
+	if err != nil {
+		return err
+	}
+
+	return items.Skip(itemID)
+}
+
+// SetJobItemPriority changes the priority of a queued work item of the job.
+func (m *Manager) SetJobItemPriority(id int, itemID int, priority int) error {
+	items, err := m.getItems(id)
+	if err != nil {
+		return err
+	}
+
+	return items.SetPriority(itemID, priority)
+}
+
 // GetQueue returns a copy of the current job queue.
 func (m *Manager) GetQueue() []Job {
 	m.mutex.Lock()
@@ -388,11 +559,20 @@ func (u *updater) notifyUpdate() {
 	u.updateTimer = nil
 }
 
-func (u *updater) updateProgress(progress float64, details []string) {
+func (u *updater) setItems(items *ItemQueue) {
+	u.m.mutex.Lock()
+	defer u.m.mutex.Unlock()
+
+	u.job.items = items
+}
+
+func (u *updater) updateProgress(progress float64, processed int, total int, details []string) {
 	u.m.mutex.Lock()
 	defer u.m.mutex.Unlock()
 
 	u.job.Progress = progress
+	u.job.Processed = processed
+	u.job.Total = total
 	u.job.Details = details
 
 	if time.Since(u.lastUpdate) < u.m.updateThrottleLimit {
@@ -124,6 +124,170 @@ func TestAdd(t *testing.T) {
 	assert.NotNil(j2.StartTime)
 }
 
+func TestGetInfo(t *testing.T) {
+	m := NewManager()
+
+	_, ok := GetInfo(context.Background())
+	assert.False(t, ok)
+
+	infoCh := make(chan Info, 1)
+	const jobName = "test job"
+	jobID := m.Add(context.Background(), jobName, MakeJobExec(func(ctx context.Context, progress *Progress) {
+		info, _ := GetInfo(ctx)
+		infoCh <- info
+	}))
+
+	select {
+	case info := <-infoCh:
+		assert.Equal(t, Info{ID: jobID, Description: jobName}, info)
+	case <-time.After(time.Second):
+		t.Error("exec was not started")
+	}
+}
+
+func TestAddPriority(t *testing.T) {
+	m := NewManager()
+
+	finish := make(chan struct{})
+	defer close(finish)
+
+	runningID := m.Add(context.Background(), "running", newTestExec(finish))
+
+	// wait a tiny bit
+	time.Sleep(sleepTime)
+
+	readyID := m.Add(context.Background(), "ready", newTestExec(finish))
+	priorityID := m.AddPriority(context.Background(), "priority", newTestExec(finish))
+	secondPriorityID := m.AddPriority(context.Background(), "second priority", newTestExec(finish))
+
+	var got []int
+	for _, j := range m.GetQueue() {
+		got = append(got, j.ID)
+	}
+
+	// priority jobs are queued after the running job, in the order added
+	assert.Equal(t, []int{runningID, priorityID, secondPriorityID, readyID}, got)
+	assert.Equal(t, StatusRunning, m.GetJob(runningID).Status)
+}
+
+func isStarted(e *testExec) bool {
+	select {
+	case <-e.started:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestAddClass(t *testing.T) {
+	m := NewManager()
+
+	cpuFinish := make(chan struct{})
+	metadataFinish := make(chan struct{})
+	defer close(metadataFinish)
+
+	cpu1 := newTestExec(cpuFinish)
+	cpu2 := newTestExec(cpuFinish)
+	metadata := newTestExec(metadataFinish)
+	exclusive := newTestExec(nil)
+
+	m.AddClass(context.Background(), "cpu 1", cpu1, ClassCPU)
+	m.AddClass(context.Background(), "cpu 2", cpu2, ClassCPU)
+	m.AddClass(context.Background(), "metadata", metadata, ClassMetadata)
+	m.Add(context.Background(), "exclusive", exclusive)
+
+	// wait a tiny bit
+	time.Sleep(sleepTime)
+
+	assert := assert.New(t)
+
+	// jobs of different classes run concurrently
+	assert.True(isStarted(cpu1))
+	assert.True(isStarted(metadata))
+	assert.False(isStarted(cpu2))
+	assert.False(isStarted(exclusive))
+
+	close(cpuFinish)
+
+	// wait a tiny bit
+	time.Sleep(sleepTime)
+
+	// exclusive job waits for the metadata job
+	assert.True(isStarted(cpu2))
+	assert.False(isStarted(exclusive))
+}
+
+func TestSetPriority(t *testing.T) {
+	m := NewManager()
+
+	finish := make(chan struct{})
+	defer close(finish)
+
+	runningID := m.Add(context.Background(), "running", newTestExec(finish))
+
+	// wait a tiny bit
+	time.Sleep(sleepTime)
+
+	firstID := m.Add(context.Background(), "first", newTestExec(finish))
+	secondID := m.Add(context.Background(), "second", newTestExec(finish))
+	lowID := m.Add(context.Background(), "low", newTestExec(finish))
+
+	assert := assert.New(t)
+	assert.Nil(m.SetPriority(lowID, PriorityLow))
+	assert.Nil(m.SetPriority(secondID, PriorityHigh))
+
+	// running jobs cannot be reprioritised
+	assert.NotNil(m.SetPriority(runningID, PriorityHigh))
+
+	var got []int
+	for _, j := range m.GetQueue() {
+		got = append(got, j.ID)
+	}
+
+	assert.Equal([]int{runningID, secondID, firstID, lowID}, got)
+	assert.Equal(PriorityHigh, m.GetJob(secondID).Priority)
+}
+
+func TestPauseJob(t *testing.T) {
+	m := NewManager()
+
+	finish := make(chan struct{})
+	running := newTestExec(finish)
+	paused := newTestExec(nil)
+	next := newTestExec(nil)
+
+	runningID := m.Add(context.Background(), "running", running)
+
+	// wait a tiny bit
+	time.Sleep(sleepTime)
+
+	pausedID := m.Add(context.Background(), "paused", paused)
+	nextID := m.Add(context.Background(), "next", next)
+
+	assert := assert.New(t)
+	assert.Nil(m.PauseJob(pausedID))
+	assert.NotNil(m.PauseJob(runningID))
+	assert.Equal(StatusPaused, m.GetJob(pausedID).Status)
+
+	close(finish)
+
+	// wait a tiny bit
+	time.Sleep(sleepTime)
+
+	// paused job is skipped
+	assert.False(isStarted(paused))
+	assert.True(isStarted(next))
+	assert.Equal(StatusFinished, m.GetJob(nextID).Status)
+
+	assert.Nil(m.ResumeJob(pausedID))
+	assert.NotNil(m.ResumeJob(pausedID))
+
+	// wait a tiny bit
+	time.Sleep(sleepTime)
+
+	assert.True(isStarted(paused))
+}
+
 func TestCancel(t *testing.T) {
 	m := NewManager()
 
@@ -14,6 +14,7 @@ type Progress struct {
 	total        int
 	percent      float64
 	currentTasks []*task
+	items        *ItemQueue
 
 	mutex   sync.Mutex
 	updater *updater
@@ -29,7 +30,12 @@ func (p *Progress) updated() {
 		details = append(details, t.description)
 	}
 
-	p.updater.updateProgress(p.percent, details)
+	total := p.total
+	if !p.defined || total < 0 {
+		total = 0
+	}
+
+	p.updater.updateProgress(p.percent, p.processed, total, details)
 }
 
 // Indefinite sets the progress to an indefinite amount.
@@ -174,3 +180,19 @@ func (p *Progress) ExecuteTask(description string, fn func()) {
 	defer p.removeTask(t)
 	fn()
 }
+
+// Items returns the queue of the work items of the job, creating it if
+// necessary. The items may be listed and skipped while the job is running.
+func (p *Progress) Items() *ItemQueue {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.items == nil {
+		p.items = NewItemQueue()
+		if p.updater != nil {
+			p.updater.setItems(p.items)
+		}
+	}
+
+	return p.items
+}
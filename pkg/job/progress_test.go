@@ -116,6 +116,27 @@ func TestProgressIncrement(t *testing.T) {
 	assert.Equal(float64(1), j.Progress)
 }
 
+func TestProgressCounts(t *testing.T) {
+	m := NewManager()
+	j := &Job{}
+
+	p := createProgress(m, j)
+
+	p.SetProcessed(30)
+
+	assert := assert.New(t)
+
+	// ensure job counts were updated
+	assert.Equal(30, j.Processed)
+	assert.Equal(100, j.Total)
+
+	// the total is unknown when indefinite
+	p.Indefinite()
+	p.Increment()
+	assert.Equal(31, j.Processed)
+	assert.Equal(0, j.Total)
+}
+
 func TestExecuteTask(t *testing.T) {
 	m := NewManager()
 	j := &Job{}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"context"
+	"time"
+)
+
+type ActivityLogReader interface {
+	// Query returns the entries matching the filter, newest first, along with
+	// the total number of matching entries.
+	Query(ctx context.Context, filter *ActivityLogFilterType, findFilter *FindFilterType) ([]*ActivityLogEntry, int, error)
+}
+
+type ActivityLogWriter interface {
+	Create(ctx context.Context, newObject ActivityLogEntry) error
+	// DestroyOlderThan deletes the entries created before t.
+	DestroyOlderThan(ctx context.Context, t time.Time) error
+}
+
+type ActivityLogReaderWriter interface {
+	ActivityLogReader
+	ActivityLogWriter
+}
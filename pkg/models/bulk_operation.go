@@ -0,0 +1,24 @@
+package models
+
+import (
+	"context"
+	"time"
+)
+
+type BulkOperationReader interface {
+	// FindLatest returns the most recent operation created at or after t.
+	// Returns nil if there is no such operation.
+	FindLatest(ctx context.Context, t time.Time) (*BulkOperation, error)
+}
+
+type BulkOperationWriter interface {
+	Create(ctx context.Context, newObject BulkOperation) (*BulkOperation, error)
+	Destroy(ctx context.Context, id int) error
+	// DestroyOlderThan deletes the operations created before t.
+	DestroyOlderThan(ctx context.Context, t time.Time) error
+}
+
+type BulkOperationReaderWriter interface {
+	BulkOperationReader
+	BulkOperationWriter
+}
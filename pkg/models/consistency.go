@@ -0,0 +1,99 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+type ConsistencyIssueType string
+
+const (
+	// ConsistencyIssueTypeFileWithoutObject is a video or image file not
+	// linked to a scene or image.
+	ConsistencyIssueTypeFileWithoutObject ConsistencyIssueType = "FILE_WITHOUT_OBJECT"
+	// ConsistencyIssueTypeSceneWithoutFile is a scene without any files.
+	ConsistencyIssueTypeSceneWithoutFile ConsistencyIssueType = "SCENE_WITHOUT_FILE"
+	// ConsistencyIssueTypeOrphanedRow is a row referencing a row that does
+	// not exist, such as a tag of a deleted scene.
+	ConsistencyIssueTypeOrphanedRow ConsistencyIssueType = "ORPHANED_ROW"
+	// ConsistencyIssueTypeFingerprintMismatch is a file with conflicting
+	// fingerprints of the same type.
+	ConsistencyIssueTypeFingerprintMismatch ConsistencyIssueType = "FINGERPRINT_MISMATCH"
+	// ConsistencyIssueTypeGalleryMissingZip is a gallery referencing a zip
+	// file that does not exist.
+	ConsistencyIssueTypeGalleryMissingZip ConsistencyIssueType = "GALLERY_MISSING_ZIP"
+)
+
+var AllConsistencyIssueType = []ConsistencyIssueType{
+	ConsistencyIssueTypeFileWithoutObject,
+	ConsistencyIssueTypeSceneWithoutFile,
+	ConsistencyIssueTypeOrphanedRow,
+	ConsistencyIssueTypeFingerprintMismatch,
+	ConsistencyIssueTypeGalleryMissingZip,
+}
+
+func (e ConsistencyIssueType) IsValid() bool {
+	switch e {
+	case ConsistencyIssueTypeFileWithoutObject, ConsistencyIssueTypeSceneWithoutFile, ConsistencyIssueTypeOrphanedRow, ConsistencyIssueTypeFingerprintMismatch, ConsistencyIssueTypeGalleryMissingZip:
+		return true
+	}
+	return false
+}
+
+// RepairAction describes how issues of the type are repaired. Returns an
+// empty string if the issues must be resolved manually.
+func (e ConsistencyIssueType) RepairAction() string {
+	switch e {
+	case ConsistencyIssueTypeFileWithoutObject:
+		return "Delete the file entry. The file is added again by the next scan."
+	case ConsistencyIssueTypeOrphanedRow:
+		return "Delete the row."
+	case ConsistencyIssueTypeFingerprintMismatch:
+		return "Delete the fingerprints. They are calculated again by the next scan."
+	case ConsistencyIssueTypeGalleryMissingZip:
+		return "Remove the zip file from the gallery."
+	}
+	return ""
+}
+
+func (e ConsistencyIssueType) String() string {
+	return string(e)
+}
+
+func (e *ConsistencyIssueType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ConsistencyIssueType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ConsistencyIssueType", str)
+	}
+	return nil
+}
+
+func (e ConsistencyIssueType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// ConsistencyIssue is a violation of an invariant of the database.
+type ConsistencyIssue struct {
+	Type ConsistencyIssueType `json:"type"`
+	// Table is the table of the inconsistent row.
+	Table string `json:"table"`
+	// ID is the id of the inconsistent object, or the rowid of orphaned rows.
+	ID          int    `json:"id"`
+	Description string `json:"description"`
+}
+
+type ConsistencyChecker interface {
+	// CheckConsistency returns the violations of the database invariants.
+	CheckConsistency(ctx context.Context) ([]*ConsistencyIssue, error)
+	// RepairConsistency repairs the current issues of the types, returning
+	// the number of repaired issues. Issues that cannot be repaired
+	// automatically are ignored.
+	RepairConsistency(ctx context.Context, types []ConsistencyIssueType) (int, error)
+}
@@ -0,0 +1,40 @@
+package models
+
+import "context"
+
+type contentRestrictionKey struct{}
+
+// ContentRestriction is the content hidden by the restriction profiles that
+// apply to a request.
+type ContentRestriction struct {
+	// Content tagged with these tags, or their descendants, is hidden.
+	TagIDs []int
+	// Content of these studios, or their child studios, is hidden.
+	StudioIDs []int
+}
+
+// Empty returns true if the restriction does not hide any content.
+func (r ContentRestriction) Empty() bool {
+	return len(r.TagIDs) == 0 && len(r.StudioIDs) == 0
+}
+
+// RestrictContent returns a context in which queries exclude the content
+// hidden by the provided restriction, in addition to any restriction already
+// set in the context.
+func RestrictContent(ctx context.Context, r ContentRestriction) context.Context {
+	if existing, ok := GetContentRestriction(ctx); ok {
+		r = ContentRestriction{
+			TagIDs:    append(append([]int{}, existing.TagIDs...), r.TagIDs...),
+			StudioIDs: append(append([]int{}, existing.StudioIDs...), r.StudioIDs...),
+		}
+	}
+
+	return context.WithValue(ctx, contentRestrictionKey{}, r)
+}
+
+// GetContentRestriction returns the content restriction and true if content
+// is restricted in the provided context.
+func GetContentRestriction(ctx context.Context) (ContentRestriction, bool) {
+	ret, ok := ctx.Value(contentRestrictionKey{}).(ContentRestriction)
+	return ret, ok
+}
@@ -0,0 +1,18 @@
+package models
+
+import "context"
+
+type contentWarningsKey struct{}
+
+// HideContentWarnings returns a context in which content tagged with content
+// warning tags is excluded from queries.
+func HideContentWarnings(ctx context.Context) context.Context {
+	return context.WithValue(ctx, contentWarningsKey{}, true)
+}
+
+// ContentWarningsHidden returns true if content tagged with content warning
+// tags should be excluded from queries made with the provided context.
+func ContentWarningsHidden(ctx context.Context) bool {
+	hidden, _ := ctx.Value(contentWarningsKey{}).(bool)
+	return hidden
+}
@@ -0,0 +1,36 @@
+package models
+
+import "context"
+
+type CustomFieldCriterionInput struct {
+	FieldID  string            `json:"field_id"`
+	Value    interface{}       `json:"value"`
+	Value2   interface{}       `json:"value2"`
+	Modifier CriterionModifier `json:"modifier"`
+}
+
+type CustomFieldReader interface {
+	Find(ctx context.Context, id int) (*CustomField, error)
+	FindByName(ctx context.Context, objectType CustomFieldObjectType, name string) (*CustomField, error)
+	// FindByObjectType returns the fields of the object type, ordered by name.
+	FindByObjectType(ctx context.Context, objectType CustomFieldObjectType) ([]*CustomField, error)
+	All(ctx context.Context) ([]*CustomField, error)
+	// GetValues returns the custom field values of the object, ordered by
+	// field name.
+	GetValues(ctx context.Context, objectType CustomFieldObjectType, objectID int) ([]CustomFieldValue, error)
+}
+
+type CustomFieldWriter interface {
+	Create(ctx context.Context, newObject CustomField) (*CustomField, error)
+	Update(ctx context.Context, updatedObject CustomField) (*CustomField, error)
+	Destroy(ctx context.Context, id int) error
+	// SetValues sets the custom field values of the object. Values which are
+	// nil are removed. The values must already be parsed with
+	// CustomField.ParseValue.
+	SetValues(ctx context.Context, objectType CustomFieldObjectType, objectID int, values []CustomFieldValue) error
+}
+
+type CustomFieldReaderWriter interface {
+	CustomFieldReader
+	CustomFieldWriter
+}
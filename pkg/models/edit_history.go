@@ -0,0 +1,21 @@
+package models
+
+import "context"
+
+type EditHistoryReader interface {
+	Find(ctx context.Context, id int) (*EditHistoryEntry, error)
+	// FindByObject returns the entries of the object, newest first.
+	FindByObject(ctx context.Context, objectType EditHistoryObjectType, objectID int) ([]*EditHistoryEntry, error)
+	// Query returns the entries matching the filter, newest first, along with
+	// the total number of matching entries.
+	Query(ctx context.Context, filter *EditHistoryFilterType, findFilter *FindFilterType) ([]*EditHistoryEntry, int, error)
+}
+
+type EditHistoryWriter interface {
+	Create(ctx context.Context, newObject EditHistoryEntry) (*EditHistoryEntry, error)
+}
+
+type EditHistoryReaderWriter interface {
+	EditHistoryReader
+	EditHistoryWriter
+}
@@ -0,0 +1,41 @@
+package models
+
+import "context"
+
+// ExternalID is the identifier of an object in an external source, such as a
+// scraper. An object has at most one identifier per source.
+type ExternalID struct {
+	Source string `db:"source" json:"source"`
+	ID     string `db:"external_id" json:"id"`
+}
+
+type ExternalIDLoader interface {
+	GetExternalIDs(ctx context.Context, relatedID int) ([]ExternalID, error)
+}
+
+type ExternalIDUpdater interface {
+	UpdateExternalIDs(ctx context.Context, relatedID int, externalIDs []ExternalID) error
+}
+
+// MergeExternalIDs returns the existing identifiers with the identifiers of
+// the sources in toSet replaced or added.
+func MergeExternalIDs(existing []ExternalID, toSet []ExternalID) []ExternalID {
+	ret := append([]ExternalID{}, existing...)
+
+	for _, v := range toSet {
+		found := false
+		for i := range ret {
+			if ret[i].Source == v.Source {
+				ret[i].ID = v.ID
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			ret = append(ret, v)
+		}
+	}
+
+	return ret
+}
@@ -109,6 +109,15 @@ func (i IntCriterionInput) ValidModifier() bool {
 	return false
 }
 
+// ProximityCriterionInput matches objects with coordinates within Distance
+// kilometres of the given latitude and longitude.
+type ProximityCriterionInput struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	// Distance in kilometres
+	Distance float64 `json:"distance"`
+}
+
 type ResolutionCriterionInput struct {
 	Value    ResolutionEnum    `json:"value"`
 	Modifier CriterionModifier `json:"modifier"`
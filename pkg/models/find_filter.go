@@ -58,6 +58,9 @@ type FindFilterType struct {
 	PerPage   *int               `json:"per_page"`
 	Sort      *string            `json:"sort"`
 	Direction *SortDirectionEnum `json:"direction"`
+	// Sort favorites before other results. Only applies to performers,
+	// tags and studios.
+	FavoritesFirst *bool `json:"favorites_first"`
 }
 
 func (ff FindFilterType) GetSort(defaultSort string) string {
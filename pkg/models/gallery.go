@@ -51,6 +51,10 @@ type GalleryFilterType struct {
 	ImageCount *IntCriterionInput `json:"image_count"`
 	// Filter by url
 	URL *StringCriterionInput `json:"url"`
+	// Filter by location
+	Location *StringCriterionInput `json:"location"`
+	// Filter by distance from coordinates
+	Coordinates *ProximityCriterionInput `json:"coordinates"`
 	// Filter by date
 	Date *DateCriterionInput `json:"date"`
 	// Filter by created at
@@ -71,6 +75,9 @@ type GalleryUpdateInput struct {
 	Organized        *bool    `json:"organized"`
 	SceneIds         []string `json:"scene_ids"`
 	StudioID         *string  `json:"studio_id"`
+	Location         *string  `json:"location"`
+	Latitude         *float64 `json:"latitude"`
+	Longitude        *float64 `json:"longitude"`
 	TagIds           []string `json:"tag_ids"`
 	PerformerIds     []string `json:"performer_ids"`
 	PrimaryFileID    *string  `json:"primary_file_id"`
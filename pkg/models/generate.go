@@ -8,6 +8,7 @@ import (
 
 type GenerateMetadataOptions struct {
 	Sprites                   *bool                   `json:"sprites"`
+	HoverStrips               *bool                   `json:"hoverStrips"`
 	Previews                  *bool                   `json:"previews"`
 	ImagePreviews             *bool                   `json:"imagePreviews"`
 	PreviewOptions            *GeneratePreviewOptions `json:"previewOptions"`
@@ -17,6 +18,8 @@ type GenerateMetadataOptions struct {
 	Transcodes                *bool                   `json:"transcodes"`
 	Phashes                   *bool                   `json:"phashes"`
 	InteractiveHeatmapsSpeeds *bool                   `json:"interactiveHeatmapsSpeeds"`
+	InteractiveMarkers        *bool                   `json:"interactiveMarkers"`
+	ChapterThumbnails         *bool                   `json:"chapterThumbnails"`
 }
 
 type GeneratePreviewOptions struct {
@@ -22,8 +22,14 @@ type ImageFilterType struct {
 	Date *DateCriterionInput `json:"date"`
 	// Filter by url
 	URL *StringCriterionInput `json:"url"`
+	// Filter by location
+	Location *StringCriterionInput `json:"location"`
+	// Filter by distance from coordinates
+	Coordinates *ProximityCriterionInput `json:"coordinates"`
 	// Filter by organized
 	Organized *bool `json:"organized"`
+	// Filter by hidden
+	Hidden *bool `json:"hidden"`
 	// Filter by o-counter
 	OCounter *IntCriterionInput `json:"o_counter"`
 	// Filter by resolution
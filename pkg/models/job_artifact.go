@@ -0,0 +1,24 @@
+package models
+
+import (
+	"context"
+	"time"
+)
+
+type JobArtifactReader interface {
+	Find(ctx context.Context, id int) (*JobArtifact, error)
+	// All returns all artifacts, newest first.
+	All(ctx context.Context) ([]*JobArtifact, error)
+	// FindCreatedBefore returns the artifacts created before t.
+	FindCreatedBefore(ctx context.Context, t time.Time) ([]*JobArtifact, error)
+}
+
+type JobArtifactWriter interface {
+	Create(ctx context.Context, newObject JobArtifact) (*JobArtifact, error)
+	Destroy(ctx context.Context, id int) error
+}
+
+type JobArtifactReaderWriter interface {
+	JobArtifactReader
+	JobArtifactWriter
+}
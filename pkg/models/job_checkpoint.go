@@ -0,0 +1,22 @@
+package models
+
+import "context"
+
+type JobCheckpointReader interface {
+	// Find returns the checkpoint of the job, or nil if there is none.
+	Find(ctx context.Context, job string) (*JobCheckpoint, error)
+}
+
+type JobCheckpointWriter interface {
+	// Start replaces the checkpoint of the job with one for the input, with
+	// no progress.
+	Start(ctx context.Context, job string, input string) error
+	// SetProgress sets the last processed ID of the task of the job.
+	SetProgress(ctx context.Context, job string, task string, lastID int) error
+	Destroy(ctx context.Context, job string) error
+}
+
+type JobCheckpointReaderWriter interface {
+	JobCheckpointReader
+	JobCheckpointWriter
+}
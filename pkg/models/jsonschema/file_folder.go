@@ -1,7 +1,6 @@
 package jsonschema
 
 import (
-	"bytes"
 	"errors"
 	"fmt"
 	"io"
@@ -104,20 +103,19 @@ func LoadFileFile(filePath string) (DirEntry, error) {
 	}
 	defer r.Close()
 
-	data, err := io.ReadAll(r)
-	if err != nil {
-		return nil, err
-	}
-
 	var json = jsoniter.ConfigCompatibleWithStandardLibrary
-	jsonParser := json.NewDecoder(bytes.NewReader(data))
+	jsonParser := json.NewDecoder(r)
 
 	var bf BaseDirEntry
 	if err := jsonParser.Decode(&bf); err != nil {
 		return nil, err
 	}
 
-	jsonParser = json.NewDecoder(bytes.NewReader(data))
+	// decode the file again as its type, rather than holding it in memory
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	jsonParser = json.NewDecoder(r)
 
 	switch bf.Type {
 	case DirEntryTypeFolder:
@@ -20,6 +20,9 @@ type Gallery struct {
 	Rating     int           `json:"rating,omitempty"`
 	Organized  bool          `json:"organized,omitempty"`
 	Studio     string        `json:"studio,omitempty"`
+	Location   string        `json:"location,omitempty"`
+	Latitude   *float64      `json:"latitude,omitempty"`
+	Longitude  *float64      `json:"longitude,omitempty"`
 	Performers []string      `json:"performers,omitempty"`
 	Tags       []string      `json:"tags,omitempty"`
 	CreatedAt  json.JSONTime `json:"created_at,omitempty"`
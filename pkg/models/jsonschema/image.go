@@ -16,7 +16,11 @@ type Image struct {
 	URL        string        `json:"url,omitempty"`
 	Date       string        `json:"date,omitempty"`
 	Organized  bool          `json:"organized,omitempty"`
+	Hidden     bool          `json:"hidden,omitempty"`
 	OCounter   int           `json:"o_counter,omitempty"`
+	Location   string        `json:"location,omitempty"`
+	Latitude   *float64      `json:"latitude,omitempty"`
+	Longitude  *float64      `json:"longitude,omitempty"`
 	Galleries  []GalleryRef  `json:"galleries,omitempty"`
 	Performers []string      `json:"performers,omitempty"`
 	Tags       []string      `json:"tags,omitempty"`
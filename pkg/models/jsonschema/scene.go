@@ -49,6 +49,9 @@ type Scene struct {
 	OCounter     int              `json:"o_counter,omitempty"`
 	Details      string           `json:"details,omitempty"`
 	Director     string           `json:"director,omitempty"`
+	Location     string           `json:"location,omitempty"`
+	Latitude     *float64         `json:"latitude,omitempty"`
+	Longitude    *float64         `json:"longitude,omitempty"`
 	Galleries    []GalleryRef     `json:"galleries,omitempty"`
 	Performers   []string         `json:"performers,omitempty"`
 	Movies       []SceneMovie     `json:"movies,omitempty"`
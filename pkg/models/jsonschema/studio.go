@@ -22,6 +22,7 @@ type Studio struct {
 	Aliases       []string         `json:"aliases,omitempty"`
 	StashIDs      []models.StashID `json:"stash_ids,omitempty"`
 	IgnoreAutoTag bool             `json:"ignore_auto_tag,omitempty"`
+	Favorite      bool             `json:"favorite,omitempty"`
 }
 
 func (s Studio) Filename() string {
@@ -10,14 +10,16 @@ import (
 )
 
 type Tag struct {
-	Name          string        `json:"name,omitempty"`
-	Description   string        `json:"description,omitempty"`
-	Aliases       []string      `json:"aliases,omitempty"`
-	Image         string        `json:"image,omitempty"`
-	Parents       []string      `json:"parents,omitempty"`
-	IgnoreAutoTag bool          `json:"ignore_auto_tag,omitempty"`
-	CreatedAt     json.JSONTime `json:"created_at,omitempty"`
-	UpdatedAt     json.JSONTime `json:"updated_at,omitempty"`
+	Name           string        `json:"name,omitempty"`
+	Description    string        `json:"description,omitempty"`
+	Aliases        []string      `json:"aliases,omitempty"`
+	Image          string        `json:"image,omitempty"`
+	Parents        []string      `json:"parents,omitempty"`
+	IgnoreAutoTag  bool          `json:"ignore_auto_tag,omitempty"`
+	ContentWarning bool          `json:"content_warning,omitempty"`
+	Favorite       bool          `json:"favorite,omitempty"`
+	CreatedAt      json.JSONTime `json:"created_at,omitempty"`
+	UpdatedAt      json.JSONTime `json:"updated_at,omitempty"`
 }
 
 func (s Tag) Filename() string {
@@ -1,7 +1,9 @@
 package jsonschema
 
 import (
+	"bufio"
 	"bytes"
+	"io"
 	"os"
 
 	jsoniter "github.com/json-iterator/go"
@@ -13,23 +15,52 @@ func CompareJSON(a interface{}, b interface{}) bool {
 	return bytes.Equal(aBuf, bBuf)
 }
 
+// marshalToFile encodes j directly to the file, so that large documents are
+// not held in memory.
 func marshalToFile(filePath string, j interface{}) error {
-	data, err := encode(j)
+	f, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(filePath, data, 0644)
+
+	if err := encodeToFile(f, j); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+func encodeToFile(f *os.File, j interface{}) error {
+	w := bufio.NewWriter(f)
+	if err := newEncoder(w).Encode(j); err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	// Strip the newline at the end of the file
+	n, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	return f.Truncate(n - 1)
 }
 
 func encode(j interface{}) ([]byte, error) {
 	buffer := &bytes.Buffer{}
-	var json = jsoniter.ConfigCompatibleWithStandardLibrary
-	encoder := json.NewEncoder(buffer)
-	encoder.SetEscapeHTML(false)
-	encoder.SetIndent("", "  ")
-	if err := encoder.Encode(j); err != nil {
+	if err := newEncoder(buffer).Encode(j); err != nil {
 		return nil, err
 	}
 	// Strip the newline at the end of the file
 	return bytes.TrimRight(buffer.Bytes(), "\n"), nil
 }
+
+func newEncoder(w io.Writer) *jsoniter.Encoder {
+	var json = jsoniter.ConfigCompatibleWithStandardLibrary
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder
+}
@@ -0,0 +1,40 @@
+package jsonschema
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_marshalToFile(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "studio.json")
+
+	studio := &Studio{
+		Name:    "<Studio & Co>",
+		Aliases: []string{"alias"},
+	}
+
+	// write twice to check that existing files are truncated
+	if err := marshalToFile(fn, &Studio{Name: "a much longer studio name than the final one"}); err != nil {
+		t.Fatalf("marshalToFile() error = %v", err)
+	}
+	if err := marshalToFile(fn, studio); err != nil {
+		t.Fatalf("marshalToFile() error = %v", err)
+	}
+
+	got, err := os.ReadFile(fn)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+
+	want, _ := encode(studio)
+	assert.Equal(t, string(want), string(got))
+
+	loaded, err := LoadStudioFile(fn)
+	if err != nil {
+		t.Fatalf("LoadStudioFile() error = %v", err)
+	}
+	assert.Equal(t, studio, loaded)
+}
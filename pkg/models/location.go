@@ -0,0 +1,16 @@
+package models
+
+import "fmt"
+
+// ValidateCoordinates returns an error if the latitude or longitude is out of
+// range. Nil values are valid.
+func ValidateCoordinates(latitude, longitude *float64) error {
+	if latitude != nil && (*latitude < -90 || *latitude > 90) {
+		return fmt.Errorf("latitude %v out of range", *latitude)
+	}
+	if longitude != nil && (*longitude < -180 || *longitude > 180) {
+		return fmt.Errorf("longitude %v out of range", *longitude)
+	}
+
+	return nil
+}
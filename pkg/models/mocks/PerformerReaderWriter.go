@@ -144,6 +144,29 @@ func (_m *PerformerReaderWriter) Find(ctx context.Context, id int) (*models.Perf
 	return r0, r1
 }
 
+// FindByExternalID provides a mock function with given fields: ctx, source, externalID
+func (_m *PerformerReaderWriter) FindByExternalID(ctx context.Context, source string, externalID string) ([]*models.Performer, error) {
+	ret := _m.Called(ctx, source, externalID)
+
+	var r0 []*models.Performer
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []*models.Performer); ok {
+		r0 = rf(ctx, source, externalID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Performer)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, source, externalID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindByGalleryID provides a mock function with given fields: ctx, galleryID
 func (_m *PerformerReaderWriter) FindByGalleryID(ctx context.Context, galleryID int) ([]*models.Performer, error) {
 	ret := _m.Called(ctx, galleryID)
@@ -328,6 +351,52 @@ func (_m *PerformerReaderWriter) GetAliases(ctx context.Context, relatedID int)
 	return r0, r1
 }
 
+// GetExternalIDs provides a mock function with given fields: ctx, relatedID
+func (_m *PerformerReaderWriter) GetExternalIDs(ctx context.Context, relatedID int) ([]models.ExternalID, error) {
+	ret := _m.Called(ctx, relatedID)
+
+	var r0 []models.ExternalID
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.ExternalID); ok {
+		r0 = rf(ctx, relatedID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.ExternalID)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, relatedID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetGalleryIDs provides a mock function with given fields: ctx, performerID
+func (_m *PerformerReaderWriter) GetGalleryIDs(ctx context.Context, performerID int) ([]int, error) {
+	ret := _m.Called(ctx, performerID)
+
+	var r0 []int
+	if rf, ok := ret.Get(0).(func(context.Context, int) []int); ok {
+		r0 = rf(ctx, performerID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, performerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetImage provides a mock function with given fields: ctx, performerID
 func (_m *PerformerReaderWriter) GetImage(ctx context.Context, performerID int) ([]byte, error) {
 	ret := _m.Called(ctx, performerID)
@@ -351,6 +420,52 @@ func (_m *PerformerReaderWriter) GetImage(ctx context.Context, performerID int)
 	return r0, r1
 }
 
+// GetImageIDs provides a mock function with given fields: ctx, performerID
+func (_m *PerformerReaderWriter) GetImageIDs(ctx context.Context, performerID int) ([]int, error) {
+	ret := _m.Called(ctx, performerID)
+
+	var r0 []int
+	if rf, ok := ret.Get(0).(func(context.Context, int) []int); ok {
+		r0 = rf(ctx, performerID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, performerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSceneIDs provides a mock function with given fields: ctx, performerID
+func (_m *PerformerReaderWriter) GetSceneIDs(ctx context.Context, performerID int) ([]int, error) {
+	ret := _m.Called(ctx, performerID)
+
+	var r0 []int
+	if rf, ok := ret.Get(0).(func(context.Context, int) []int); ok {
+		r0 = rf(ctx, performerID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, performerID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetStashIDs provides a mock function with given fields: ctx, relatedID
 func (_m *PerformerReaderWriter) GetStashIDs(ctx context.Context, relatedID int) ([]models.StashID, error) {
 	ret := _m.Called(ctx, relatedID)
@@ -397,6 +512,20 @@ func (_m *PerformerReaderWriter) GetTagIDs(ctx context.Context, relatedID int) (
 	return r0, r1
 }
 
+// Merge provides a mock function with given fields: ctx, source, destination
+func (_m *PerformerReaderWriter) Merge(ctx context.Context, source []int, destination int) error {
+	ret := _m.Called(ctx, source, destination)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int, int) error); ok {
+		r0 = rf(ctx, source, destination)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Query provides a mock function with given fields: ctx, performerFilter, findFilter
 func (_m *PerformerReaderWriter) Query(ctx context.Context, performerFilter *models.PerformerFilterType, findFilter *models.FindFilterType) ([]*models.Performer, int, error) {
 	ret := _m.Called(ctx, performerFilter, findFilter)
@@ -464,6 +593,20 @@ func (_m *PerformerReaderWriter) Update(ctx context.Context, updatedPerformer *m
 	return r0
 }
 
+// UpdateExternalIDs provides a mock function with given fields: ctx, relatedID, externalIDs
+func (_m *PerformerReaderWriter) UpdateExternalIDs(ctx context.Context, relatedID int, externalIDs []models.ExternalID) error {
+	ret := _m.Called(ctx, relatedID, externalIDs)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, []models.ExternalID) error); ok {
+		r0 = rf(ctx, relatedID, externalIDs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateImage provides a mock function with given fields: ctx, performerID, image
 func (_m *PerformerReaderWriter) UpdateImage(ctx context.Context, performerID int, image []byte) error {
 	ret := _m.Called(ctx, performerID, image)
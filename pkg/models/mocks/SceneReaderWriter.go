@@ -9,6 +9,8 @@ import (
 	mock "github.com/stretchr/testify/mock"
 
 	models "github.com/stashapp/stash/pkg/models"
+
+	utils "github.com/stashapp/stash/pkg/utils"
 )
 
 // SceneReaderWriter is an autogenerated mock type for the SceneReaderWriter type
@@ -144,6 +146,27 @@ func (_m *SceneReaderWriter) CountByTagID(ctx context.Context, tagID int) (int,
 	return r0, r1
 }
 
+// CountByTrailerFileID provides a mock function with given fields: ctx, fileID
+func (_m *SceneReaderWriter) CountByTrailerFileID(ctx context.Context, fileID file.ID) (int, error) {
+	ret := _m.Called(ctx, fileID)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, file.ID) int); ok {
+		r0 = rf(ctx, fileID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, file.ID) error); ok {
+		r1 = rf(ctx, fileID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // CountMissingChecksum provides a mock function with given fields: ctx
 func (_m *SceneReaderWriter) CountMissingChecksum(ctx context.Context) (int, error) {
 	ret := _m.Called(ctx)
@@ -316,6 +339,29 @@ func (_m *SceneReaderWriter) FindByChecksum(ctx context.Context, checksum string
 	return r0, r1
 }
 
+// FindByExternalID provides a mock function with given fields: ctx, source, externalID
+func (_m *SceneReaderWriter) FindByExternalID(ctx context.Context, source string, externalID string) ([]*models.Scene, error) {
+	ret := _m.Called(ctx, source, externalID)
+
+	var r0 []*models.Scene
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []*models.Scene); ok {
+		r0 = rf(ctx, source, externalID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Scene)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, source, externalID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindByGalleryID provides a mock function with given fields: ctx, performerID
 func (_m *SceneReaderWriter) FindByGalleryID(ctx context.Context, performerID int) ([]*models.Scene, error) {
 	ret := _m.Called(ctx, performerID)
@@ -477,6 +523,29 @@ func (_m *SceneReaderWriter) FindMany(ctx context.Context, ids []int) ([]*models
 	return r0, r1
 }
 
+// FindPrimaryPhashes provides a mock function with given fields: ctx
+func (_m *SceneReaderWriter) FindPrimaryPhashes(ctx context.Context) ([]*utils.Phash, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []*utils.Phash
+	if rf, ok := ret.Get(0).(func(context.Context) []*utils.Phash); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*utils.Phash)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetCover provides a mock function with given fields: ctx, sceneID
 func (_m *SceneReaderWriter) GetCover(ctx context.Context, sceneID int) ([]byte, error) {
 	ret := _m.Called(ctx, sceneID)
@@ -500,6 +569,29 @@ func (_m *SceneReaderWriter) GetCover(ctx context.Context, sceneID int) ([]byte,
 	return r0, r1
 }
 
+// GetExternalIDs provides a mock function with given fields: ctx, relatedID
+func (_m *SceneReaderWriter) GetExternalIDs(ctx context.Context, relatedID int) ([]models.ExternalID, error) {
+	ret := _m.Called(ctx, relatedID)
+
+	var r0 []models.ExternalID
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.ExternalID); ok {
+		r0 = rf(ctx, relatedID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.ExternalID)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, relatedID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetFiles provides a mock function with given fields: ctx, relatedID
 func (_m *SceneReaderWriter) GetFiles(ctx context.Context, relatedID int) ([]*file.VideoFile, error) {
 	ret := _m.Called(ctx, relatedID)
@@ -638,20 +730,22 @@ func (_m *SceneReaderWriter) GetTagIDs(ctx context.Context, relatedID int) ([]in
 	return r0, r1
 }
 
-// SaveActivity provides a mock function with given fields: ctx, id, resumeTime, playDuration
-func (_m *SceneReaderWriter) SaveActivity(ctx context.Context, id int, resumeTime *float64, playDuration *float64) (bool, error) {
-	ret := _m.Called(ctx, id, resumeTime, playDuration)
+// GetTrailerFileID provides a mock function with given fields: ctx, sceneID
+func (_m *SceneReaderWriter) GetTrailerFileID(ctx context.Context, sceneID int) (*file.ID, error) {
+	ret := _m.Called(ctx, sceneID)
 
-	var r0 bool
-	if rf, ok := ret.Get(0).(func(context.Context, int, *float64, *float64) bool); ok {
-		r0 = rf(ctx, id, resumeTime, playDuration)
+	var r0 *file.ID
+	if rf, ok := ret.Get(0).(func(context.Context, int) *file.ID); ok {
+		r0 = rf(ctx, sceneID)
 	} else {
-		r0 = ret.Get(0).(bool)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*file.ID)
+		}
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, int, *float64, *float64) error); ok {
-		r1 = rf(ctx, id, resumeTime, playDuration)
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, sceneID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -659,8 +753,8 @@ func (_m *SceneReaderWriter) SaveActivity(ctx context.Context, id int, resumeTim
 	return r0, r1
 }
 
-// IncrementWatchCount provides a mock function with given fields: ctx, id
-func (_m *SceneReaderWriter) IncrementWatchCount(ctx context.Context, id int) (int, error) {
+// IncrementOCounter provides a mock function with given fields: ctx, id
+func (_m *SceneReaderWriter) IncrementOCounter(ctx context.Context, id int) (int, error) {
 	ret := _m.Called(ctx, id)
 
 	var r0 int
@@ -680,8 +774,8 @@ func (_m *SceneReaderWriter) IncrementWatchCount(ctx context.Context, id int) (i
 	return r0, r1
 }
 
-// IncrementOCounter provides a mock function with given fields: ctx, id
-func (_m *SceneReaderWriter) IncrementOCounter(ctx context.Context, id int) (int, error) {
+// IncrementWatchCount provides a mock function with given fields: ctx, id
+func (_m *SceneReaderWriter) IncrementWatchCount(ctx context.Context, id int) (int, error) {
 	ret := _m.Called(ctx, id)
 
 	var r0 int
@@ -745,6 +839,27 @@ func (_m *SceneReaderWriter) ResetOCounter(ctx context.Context, id int) (int, er
 	return r0, r1
 }
 
+// SaveActivity provides a mock function with given fields: ctx, id, resumeTime, playDuration
+func (_m *SceneReaderWriter) SaveActivity(ctx context.Context, id int, resumeTime *float64, playDuration *float64) (bool, error) {
+	ret := _m.Called(ctx, id, resumeTime, playDuration)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, int, *float64, *float64) bool); ok {
+		r0 = rf(ctx, id, resumeTime, playDuration)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int, *float64, *float64) error); ok {
+		r1 = rf(ctx, id, resumeTime, playDuration)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Size provides a mock function with given fields: ctx
 func (_m *SceneReaderWriter) Size(ctx context.Context) (float64, error) {
 	ret := _m.Called(ctx)
@@ -794,6 +909,20 @@ func (_m *SceneReaderWriter) UpdateCover(ctx context.Context, sceneID int, cover
 	return r0
 }
 
+// UpdateExternalIDs provides a mock function with given fields: ctx, relatedID, externalIDs
+func (_m *SceneReaderWriter) UpdateExternalIDs(ctx context.Context, relatedID int, externalIDs []models.ExternalID) error {
+	ret := _m.Called(ctx, relatedID, externalIDs)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, []models.ExternalID) error); ok {
+		r0 = rf(ctx, relatedID, externalIDs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdatePartial provides a mock function with given fields: ctx, id, updatedScene
 func (_m *SceneReaderWriter) UpdatePartial(ctx context.Context, id int, updatedScene models.ScenePartial) (*models.Scene, error) {
 	ret := _m.Called(ctx, id, updatedScene)
@@ -817,6 +946,20 @@ func (_m *SceneReaderWriter) UpdatePartial(ctx context.Context, id int, updatedS
 	return r0, r1
 }
 
+// UpdateTrailerFile provides a mock function with given fields: ctx, sceneID, fileID
+func (_m *SceneReaderWriter) UpdateTrailerFile(ctx context.Context, sceneID int, fileID *file.ID) error {
+	ret := _m.Called(ctx, sceneID, fileID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, *file.ID) error); ok {
+		r0 = rf(ctx, sceneID, fileID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Wall provides a mock function with given fields: ctx, q
 func (_m *SceneReaderWriter) Wall(ctx context.Context, q *string) ([]*models.Scene, error) {
 	ret := _m.Called(ctx, q)
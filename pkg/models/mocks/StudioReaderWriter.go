@@ -132,6 +132,29 @@ func (_m *StudioReaderWriter) Find(ctx context.Context, id int) (*models.Studio,
 	return r0, r1
 }
 
+// FindByExternalID provides a mock function with given fields: ctx, source, externalID
+func (_m *StudioReaderWriter) FindByExternalID(ctx context.Context, source string, externalID string) ([]*models.Studio, error) {
+	ret := _m.Called(ctx, source, externalID)
+
+	var r0 []*models.Studio
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []*models.Studio); ok {
+		r0 = rf(ctx, source, externalID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Studio)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, source, externalID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindByName provides a mock function with given fields: ctx, name, nocase
 func (_m *StudioReaderWriter) FindByName(ctx context.Context, name string, nocase bool) (*models.Studio, error) {
 	ret := _m.Called(ctx, name, nocase)
@@ -247,6 +270,29 @@ func (_m *StudioReaderWriter) GetAliases(ctx context.Context, studioID int) ([]s
 	return r0, r1
 }
 
+// GetExternalIDs provides a mock function with given fields: ctx, relatedID
+func (_m *StudioReaderWriter) GetExternalIDs(ctx context.Context, relatedID int) ([]models.ExternalID, error) {
+	ret := _m.Called(ctx, relatedID)
+
+	var r0 []models.ExternalID
+	if rf, ok := ret.Get(0).(func(context.Context, int) []models.ExternalID); ok {
+		r0 = rf(ctx, relatedID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.ExternalID)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, relatedID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetImage provides a mock function with given fields: ctx, studioID
 func (_m *StudioReaderWriter) GetImage(ctx context.Context, studioID int) ([]byte, error) {
 	ret := _m.Called(ctx, studioID)
@@ -404,6 +450,20 @@ func (_m *StudioReaderWriter) UpdateAliases(ctx context.Context, studioID int, a
 	return r0
 }
 
+// UpdateExternalIDs provides a mock function with given fields: ctx, relatedID, externalIDs
+func (_m *StudioReaderWriter) UpdateExternalIDs(ctx context.Context, relatedID int, externalIDs []models.ExternalID) error {
+	ret := _m.Called(ctx, relatedID, externalIDs)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, []models.ExternalID) error); ok {
+		r0 = rf(ctx, relatedID, externalIDs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateFull provides a mock function with given fields: ctx, updatedStudio
 func (_m *StudioReaderWriter) UpdateFull(ctx context.Context, updatedStudio models.Studio) (*models.Studio, error) {
 	ret := _m.Called(ctx, updatedStudio)
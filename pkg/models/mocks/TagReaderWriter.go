@@ -385,6 +385,29 @@ func (_m *TagReaderWriter) FindBySceneMarkerID(ctx context.Context, sceneMarkerI
 	return r0, r1
 }
 
+// FindInheritedBySceneID provides a mock function with given fields: ctx, sceneID, depth
+func (_m *TagReaderWriter) FindInheritedBySceneID(ctx context.Context, sceneID int, depth int) ([]*models.Tag, error) {
+	ret := _m.Called(ctx, sceneID, depth)
+
+	var r0 []*models.Tag
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []*models.Tag); ok {
+		r0 = rf(ctx, sceneID, depth)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*models.Tag)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, sceneID, depth)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindMany provides a mock function with given fields: ctx, ids
 func (_m *TagReaderWriter) FindMany(ctx context.Context, ids []int) ([]*models.Tag, error) {
 	ret := _m.Called(ctx, ids)
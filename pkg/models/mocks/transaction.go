@@ -44,16 +44,38 @@ func (*TxnManager) Reset() error {
 
 func NewTxnRepository() models.Repository {
 	return models.Repository{
-		TxnManager:  &TxnManager{},
-		Gallery:     &GalleryReaderWriter{},
-		Image:       &ImageReaderWriter{},
-		Movie:       &MovieReaderWriter{},
-		Performer:   &PerformerReaderWriter{},
-		Scene:       &SceneReaderWriter{},
-		SceneMarker: &SceneMarkerReaderWriter{},
-		ScrapedItem: &ScrapedItemReaderWriter{},
-		Studio:      &StudioReaderWriter{},
-		Tag:         &TagReaderWriter{},
-		SavedFilter: &SavedFilterReaderWriter{},
+		TxnManager:         &TxnManager{},
+		Gallery:            &GalleryReaderWriter{},
+		Image:              &ImageReaderWriter{},
+		Movie:              &MovieReaderWriter{},
+		Performer:          &PerformerReaderWriter{},
+		Scene:              &SceneReaderWriter{},
+		SceneMarker:        &SceneMarkerReaderWriter{},
+		ScrapedItem:        &ScrapedItemReaderWriter{},
+		Studio:             &StudioReaderWriter{},
+		Tag:                &TagReaderWriter{},
+		SavedFilter:        &SavedFilterReaderWriter{},
+		WantedScene:        &WantedSceneReaderWriter{},
+		ActivityLog:        &ActivityLogReaderWriter{},
+		PlaybackEvent:      &PlaybackEventReaderWriter{},
+		SceneFlag:          &SceneFlagReaderWriter{},
+		SceneVersion:       &SceneVersionReaderWriter{},
+		BulkOperation:      &BulkOperationReaderWriter{},
+		TagSuggestion:      &TagSuggestionReaderWriter{},
+		PlayQueue:          &PlayQueueReaderWriter{},
+		JobCheckpoint:      &JobCheckpointReaderWriter{},
+		JobArtifact:        &JobArtifactReaderWriter{},
+		TrashedScene:       &TrashedSceneReaderWriter{},
+		CustomField:        &CustomFieldReaderWriter{},
+		EditHistory:        &EditHistoryReaderWriter{},
+		User:               &UserReaderWriter{},
+		RestrictionProfile: &RestrictionProfileReaderWriter{},
+		Search:             &SearchReader{},
+
+		StashBoxFingerprintCache: &StashBoxFingerprintCacheReaderWriter{},
+		ScraperCache:             &ScraperCacheReaderWriter{},
+		StashBoxDraft:            &StashBoxDraftReaderWriter{},
+		StashBoxSync:             &StashBoxSyncReaderWriter{},
+		OperationJournal:         &OperationJournalReaderWriter{},
 	}
 }
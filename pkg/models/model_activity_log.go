@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// ActivityLogEntry records an action performed by a client of the server.
+type ActivityLogEntry struct {
+	ID        int       `db:"id" json:"id"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	// Method used to authenticate the request. One of api_key, session or none
+	AuthMethod string `db:"auth_method" json:"auth_method"`
+	// Username for sessions, or a fingerprint of the key for API keys
+	Credential string  `db:"credential" json:"credential"`
+	RemoteAddr string  `db:"remote_addr" json:"remote_addr"`
+	Action     string  `db:"action" json:"action"`
+	TargetType *string `db:"target_type" json:"target_type"`
+	TargetID   *string `db:"target_id" json:"target_id"`
+	// JSON-encoded details of the action
+	Details *string `db:"details" json:"details"`
+}
+
+type ActivityLogEntries []*ActivityLogEntry
+
+func (m *ActivityLogEntries) Append(o interface{}) {
+	*m = append(*m, o.(*ActivityLogEntry))
+}
+
+func (m *ActivityLogEntries) New() interface{} {
+	return &ActivityLogEntry{}
+}
+
+type ActivityLogFilterType struct {
+	AuthMethod *string    `json:"auth_method"`
+	Credential *string    `json:"credential"`
+	RemoteAddr *string    `json:"remote_addr"`
+	Action     *string    `json:"action"`
+	TargetType *string    `json:"target_type"`
+	TargetID   *string    `json:"target_id"`
+	Since      *time.Time `json:"since"`
+	Until      *time.Time `json:"until"`
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// BulkOperation is a staged bulk operation which may be undone.
+type BulkOperation struct {
+	ID int `db:"id" json:"id"`
+	// Name of the mutation which performed the operation
+	Operation string `db:"operation" json:"operation"`
+	// JSON-encoded operation which reverses the bulk operation
+	Reverse   []byte    `db:"reverse" json:"reverse"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+type BulkOperations []*BulkOperation
+
+func (m *BulkOperations) Append(o interface{}) {
+	*m = append(*m, o.(*BulkOperation))
+}
+
+func (m *BulkOperations) New() interface{} {
+	return &BulkOperation{}
+}
@@ -0,0 +1,244 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type CustomFieldObjectType string
+
+const (
+	CustomFieldObjectTypeScene     CustomFieldObjectType = "SCENE"
+	CustomFieldObjectTypePerformer CustomFieldObjectType = "PERFORMER"
+	CustomFieldObjectTypeStudio    CustomFieldObjectType = "STUDIO"
+)
+
+var AllCustomFieldObjectType = []CustomFieldObjectType{
+	CustomFieldObjectTypeScene,
+	CustomFieldObjectTypePerformer,
+	CustomFieldObjectTypeStudio,
+}
+
+func (e CustomFieldObjectType) IsValid() bool {
+	switch e {
+	case CustomFieldObjectTypeScene, CustomFieldObjectTypePerformer, CustomFieldObjectTypeStudio:
+		return true
+	}
+	return false
+}
+
+func (e CustomFieldObjectType) String() string {
+	return string(e)
+}
+
+func (e *CustomFieldObjectType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = CustomFieldObjectType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid CustomFieldObjectType", str)
+	}
+	return nil
+}
+
+func (e CustomFieldObjectType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type CustomFieldType string
+
+const (
+	CustomFieldTypeText    CustomFieldType = "TEXT"
+	CustomFieldTypeNumber  CustomFieldType = "NUMBER"
+	CustomFieldTypeDate    CustomFieldType = "DATE"
+	CustomFieldTypeBoolean CustomFieldType = "BOOLEAN"
+	CustomFieldTypeEnum    CustomFieldType = "ENUM"
+)
+
+var AllCustomFieldType = []CustomFieldType{
+	CustomFieldTypeText,
+	CustomFieldTypeNumber,
+	CustomFieldTypeDate,
+	CustomFieldTypeBoolean,
+	CustomFieldTypeEnum,
+}
+
+func (e CustomFieldType) IsValid() bool {
+	switch e {
+	case CustomFieldTypeText, CustomFieldTypeNumber, CustomFieldTypeDate, CustomFieldTypeBoolean, CustomFieldTypeEnum:
+		return true
+	}
+	return false
+}
+
+func (e CustomFieldType) String() string {
+	return string(e)
+}
+
+func (e *CustomFieldType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = CustomFieldType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid CustomFieldType", str)
+	}
+	return nil
+}
+
+func (e CustomFieldType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// CustomField is a user-defined field of scenes, performers or studios.
+type CustomField struct {
+	ID         int                   `json:"id"`
+	ObjectType CustomFieldObjectType `json:"object_type"`
+	Name       string                `json:"name"`
+	Type       CustomFieldType       `json:"type"`
+	// Options are the allowed values of enum fields.
+	Options   []string  `json:"options"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CustomFieldValue is the value of a custom field of an object. Values are
+// strings for text, date and enum fields, float64 for number fields and bool
+// for boolean fields. Dates are formatted as YYYY-MM-DD.
+type CustomFieldValue struct {
+	FieldID int         `json:"field_id"`
+	Value   interface{} `json:"value"`
+}
+
+var ErrInvalidCustomFieldValue = errors.New("invalid custom field value")
+
+// ParseValue converts v to the value type of the field. v may be a string
+// representation of the value, or a value decoded from JSON.
+func (f *CustomField) ParseValue(v interface{}) (interface{}, error) {
+	ret, err := f.parseValue(v)
+	if err != nil {
+		return nil, fmt.Errorf("%w for %s field %q: %v", ErrInvalidCustomFieldValue, strings.ToLower(f.Type.String()), f.Name, err)
+	}
+
+	return ret, nil
+}
+
+func (f *CustomField) parseValue(v interface{}) (interface{}, error) {
+	switch f.Type {
+	case CustomFieldTypeText:
+		return customFieldString(v)
+	case CustomFieldTypeNumber:
+		return customFieldNumber(v)
+	case CustomFieldTypeDate:
+		s, err := customFieldString(v)
+		if err != nil {
+			return nil, err
+		}
+
+		t, err := time.Parse(dateFormat, strings.TrimSpace(s))
+		if err != nil {
+			return nil, fmt.Errorf("dates must be formatted as YYYY-MM-DD")
+		}
+		return t.Format(dateFormat), nil
+	case CustomFieldTypeBoolean:
+		return customFieldBool(v)
+	case CustomFieldTypeEnum:
+		s, err := customFieldString(v)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, o := range f.Options {
+			if o == s {
+				return s, nil
+			}
+		}
+		return nil, fmt.Errorf("%q is not one of %s", s, strings.Join(f.Options, ", "))
+	}
+
+	return nil, fmt.Errorf("unsupported type")
+}
+
+// ValueFromDB converts a value stored in the database to the value type of
+// the field.
+func (f *CustomField) ValueFromDB(v interface{}) interface{} {
+	switch f.Type {
+	case CustomFieldTypeBoolean:
+		switch vv := v.(type) {
+		case int64:
+			return vv != 0
+		case bool:
+			return vv
+		}
+	case CustomFieldTypeNumber:
+		switch vv := v.(type) {
+		case int64:
+			return float64(vv)
+		case float64:
+			return vv
+		}
+	default:
+		switch vv := v.(type) {
+		case string:
+			return vv
+		case []byte:
+			return string(vv)
+		}
+	}
+
+	// the type of the field cannot be changed, so this should not happen
+	ret, err := f.parseValue(v)
+	if err != nil {
+		return nil
+	}
+	return ret
+}
+
+func customFieldString(v interface{}) (string, error) {
+	switch vv := v.(type) {
+	case string:
+		return vv, nil
+	case json.Number:
+		return vv.String(), nil
+	}
+
+	return "", fmt.Errorf("expected a string")
+}
+
+func customFieldNumber(v interface{}) (float64, error) {
+	switch vv := v.(type) {
+	case float64:
+		return vv, nil
+	case int:
+		return float64(vv), nil
+	case int64:
+		return float64(vv), nil
+	case json.Number:
+		return vv.Float64()
+	case string:
+		return strconv.ParseFloat(strings.TrimSpace(vv), 64)
+	}
+
+	return 0, fmt.Errorf("expected a number")
+}
+
+func customFieldBool(v interface{}) (bool, error) {
+	switch vv := v.(type) {
+	case bool:
+		return vv, nil
+	case string:
+		return strconv.ParseBool(strings.TrimSpace(vv))
+	}
+
+	return false, fmt.Errorf("expected a boolean")
+}
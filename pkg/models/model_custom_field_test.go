@@ -0,0 +1,75 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCustomFieldParseValue(t *testing.T) {
+	tests := []struct {
+		name      string
+		fieldType CustomFieldType
+		v         interface{}
+		want      interface{}
+		wantErr   bool
+	}{
+		{"text", CustomFieldTypeText, "value", "value", false},
+		{"text number", CustomFieldTypeText, 1.5, nil, true},
+		{"number", CustomFieldTypeNumber, 1.5, 1.5, false},
+		{"number int", CustomFieldTypeNumber, int64(2), 2.0, false},
+		{"number json", CustomFieldTypeNumber, json.Number("3"), 3.0, false},
+		{"number string", CustomFieldTypeNumber, " 4.5 ", 4.5, false},
+		{"number invalid", CustomFieldTypeNumber, "four", nil, true},
+		{"date", CustomFieldTypeDate, "2023-01-02", "2023-01-02", false},
+		{"date invalid", CustomFieldTypeDate, "02/01/2023", nil, true},
+		{"boolean", CustomFieldTypeBoolean, true, true, false},
+		{"boolean string", CustomFieldTypeBoolean, "false", false, false},
+		{"boolean invalid", CustomFieldTypeBoolean, 1.0, nil, true},
+		{"enum", CustomFieldTypeEnum, "b", "b", false},
+		{"enum invalid", CustomFieldTypeEnum, "c", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &CustomField{
+				Name:    tt.name,
+				Type:    tt.fieldType,
+				Options: []string{"a", "b"},
+			}
+
+			got, err := f.ParseValue(tt.v)
+			if tt.wantErr {
+				assert.True(t, errors.Is(err, ErrInvalidCustomFieldValue), "error = %v", err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCustomFieldValueFromDB(t *testing.T) {
+	tests := []struct {
+		name      string
+		fieldType CustomFieldType
+		v         interface{}
+		want      interface{}
+	}{
+		{"boolean", CustomFieldTypeBoolean, int64(1), true},
+		{"number int", CustomFieldTypeNumber, int64(3), 3.0},
+		{"number", CustomFieldTypeNumber, 3.5, 3.5},
+		{"text bytes", CustomFieldTypeText, []byte("value"), "value"},
+		{"date", CustomFieldTypeDate, "2023-01-02", "2023-01-02"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &CustomField{Type: tt.fieldType}
+			assert.Equal(t, tt.want, f.ValueFromDB(tt.v))
+		})
+	}
+}
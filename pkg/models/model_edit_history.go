@@ -0,0 +1,193 @@
+package models
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+type EditHistoryObjectType string
+
+const (
+	EditHistoryObjectTypeScene     EditHistoryObjectType = "SCENE"
+	EditHistoryObjectTypePerformer EditHistoryObjectType = "PERFORMER"
+	EditHistoryObjectTypeTag       EditHistoryObjectType = "TAG"
+	EditHistoryObjectTypeStudio    EditHistoryObjectType = "STUDIO"
+)
+
+var AllEditHistoryObjectType = []EditHistoryObjectType{
+	EditHistoryObjectTypeScene,
+	EditHistoryObjectTypePerformer,
+	EditHistoryObjectTypeTag,
+	EditHistoryObjectTypeStudio,
+}
+
+func (e EditHistoryObjectType) IsValid() bool {
+	switch e {
+	case EditHistoryObjectTypeScene, EditHistoryObjectTypePerformer, EditHistoryObjectTypeTag, EditHistoryObjectTypeStudio:
+		return true
+	}
+	return false
+}
+
+func (e EditHistoryObjectType) String() string {
+	return string(e)
+}
+
+func (e *EditHistoryObjectType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = EditHistoryObjectType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid EditHistoryObjectType", str)
+	}
+	return nil
+}
+
+func (e EditHistoryObjectType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// FieldChange is the JSON-encoded old and new value of a single field.
+type FieldChange struct {
+	Field    string          `json:"field"`
+	OldValue json.RawMessage `json:"old_value"`
+	NewValue json.RawMessage `json:"new_value"`
+}
+
+// FieldChanges is stored as a JSON array in the database.
+type FieldChanges []FieldChange
+
+func (c FieldChanges) Value() (driver.Value, error) {
+	if c == nil {
+		c = FieldChanges{}
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (c *FieldChanges) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case string:
+		return json.Unmarshal([]byte(src), c)
+	case []byte:
+		return json.Unmarshal(src, c)
+	case nil:
+		*c = nil
+		return nil
+	}
+	return fmt.Errorf("cannot scan %T into FieldChanges", src)
+}
+
+// EditHistoryEntry records the fields changed on an object by a single
+// mutation.
+type EditHistoryEntry struct {
+	ID         int                   `db:"id" json:"id"`
+	CreatedAt  time.Time             `db:"created_at" json:"created_at"`
+	ObjectType EditHistoryObjectType `db:"object_type" json:"object_type"`
+	ObjectID   int                   `db:"object_id" json:"object_id"`
+	// Name of the mutation that made the changes
+	Action     string       `db:"action" json:"action"`
+	AuthMethod string       `db:"auth_method" json:"auth_method"`
+	Credential string       `db:"credential" json:"credential"`
+	Changes    FieldChanges `db:"changes" json:"changes"`
+}
+
+type EditHistoryEntries []*EditHistoryEntry
+
+func (m *EditHistoryEntries) Append(o interface{}) {
+	*m = append(*m, o.(*EditHistoryEntry))
+}
+
+func (m *EditHistoryEntries) New() interface{} {
+	return &EditHistoryEntry{}
+}
+
+type EditHistoryFilterType struct {
+	ObjectType *EditHistoryObjectType `json:"object_type"`
+	ObjectID   *string                `json:"object_id"`
+	Action     *string                `json:"action"`
+	Credential *string                `json:"credential"`
+	Since      *time.Time             `json:"since"`
+	Until      *time.Time             `json:"until"`
+}
+
+func toJSONFields(v interface{}) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret map[string]json.RawMessage
+	if err := json.Unmarshal(data, &ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// DiffFields returns the JSON fields that differ between before and after,
+// sorted by field name. Both values must encode to JSON objects.
+func DiffFields(before, after interface{}) (FieldChanges, error) {
+	b, err := toJSONFields(before)
+	if err != nil {
+		return nil, err
+	}
+	a, err := toJSONFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret FieldChanges
+	for k, av := range a {
+		if bv, found := b[k]; !found || !bytes.Equal(bv, av) {
+			ret = append(ret, FieldChange{Field: k, OldValue: bv, NewValue: av})
+		}
+	}
+	for k, bv := range b {
+		if _, found := a[k]; !found {
+			ret = append(ret, FieldChange{Field: k, OldValue: bv})
+		}
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Field < ret[j].Field
+	})
+
+	return ret, nil
+}
+
+// RevertFields sets the fields of v, which must be a pointer to a struct, to
+// the old values of the provided changes.
+func RevertFields(v interface{}, changes FieldChanges) error {
+	fields, err := toJSONFields(v)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range changes {
+		if c.OldValue == nil {
+			delete(fields, c.Field)
+			continue
+		}
+		fields[c.Field] = c.OldValue
+	}
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type historyTestState struct {
+	Title  string `json:"title"`
+	Rating *int   `json:"rating"`
+	TagIDs []int  `json:"tag_ids"`
+}
+
+func TestDiffFields(t *testing.T) {
+	rating := 80
+
+	tests := []struct {
+		name   string
+		before historyTestState
+		after  historyTestState
+		want   FieldChanges
+	}{
+		{
+			"unchanged",
+			historyTestState{Title: "a", TagIDs: []int{1}},
+			historyTestState{Title: "a", TagIDs: []int{1}},
+			nil,
+		},
+		{
+			"changed",
+			historyTestState{Title: "a", TagIDs: []int{1}},
+			historyTestState{Title: "b", Rating: &rating, TagIDs: []int{1, 2}},
+			FieldChanges{
+				{Field: "rating", OldValue: json.RawMessage("null"), NewValue: json.RawMessage("80")},
+				{Field: "tag_ids", OldValue: json.RawMessage("[1]"), NewValue: json.RawMessage("[1,2]")},
+				{Field: "title", OldValue: json.RawMessage(`"a"`), NewValue: json.RawMessage(`"b"`)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DiffFields(tt.before, tt.after)
+			if err != nil {
+				t.Errorf("DiffFields() error = %v", err)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRevertFields(t *testing.T) {
+	rating := 80
+	before := historyTestState{Title: "a", TagIDs: []int{1}}
+	after := historyTestState{Title: "b", Rating: &rating, TagIDs: []int{1, 2}}
+
+	changes, err := DiffFields(before, after)
+	if err != nil {
+		t.Errorf("DiffFields() error = %v", err)
+		return
+	}
+
+	// fields changed since the edit are kept unless the edit changed them
+	got := after
+	got.TagIDs = []int{3}
+	got.Title = "c"
+	changes = changes[:2]
+
+	if err := RevertFields(&got, changes); err != nil {
+		t.Errorf("RevertFields() error = %v", err)
+		return
+	}
+
+	assert.Equal(t, historyTestState{Title: "c", TagIDs: []int{1}}, got)
+}
+
+func TestFieldChangesScan(t *testing.T) {
+	want := FieldChanges{{Field: "title", OldValue: json.RawMessage(`"a"`), NewValue: json.RawMessage(`"b"`)}}
+
+	v, err := want.Value()
+	if err != nil {
+		t.Errorf("Value() error = %v", err)
+		return
+	}
+
+	var got FieldChanges
+	if err := got.Scan(v); err != nil {
+		t.Errorf("Scan() error = %v", err)
+		return
+	}
+
+	assert.Equal(t, want, got)
+}
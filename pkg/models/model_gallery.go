@@ -21,6 +21,11 @@ type Gallery struct {
 	Organized bool `json:"organized"`
 	StudioID  *int `json:"studio_id"`
 
+	// Location is a free text description of where the gallery was shot
+	Location  string   `json:"location"`
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+
 	// transient - not persisted
 	Files RelatedFiles
 	// transient - not persisted
@@ -113,6 +118,9 @@ type GalleryPartial struct {
 	Rating    OptionalInt
 	Organized OptionalBool
 	StudioID  OptionalInt
+	Location  OptionalString
+	Latitude  OptionalFloat64
+	Longitude OptionalFloat64
 	// FileModTime OptionalTime
 	CreatedAt OptionalTime
 	UpdatedAt OptionalTime
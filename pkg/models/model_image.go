@@ -16,12 +16,20 @@ type Image struct {
 
 	Title string `json:"title"`
 	// Rating expressed in 1-100 scale
-	Rating    *int   `json:"rating"`
-	Organized bool   `json:"organized"`
-	OCounter  int    `json:"o_counter"`
-	StudioID  *int   `json:"studio_id"`
-	URL       string `json:"url"`
-	Date      *Date  `json:"date"`
+	Rating    *int `json:"rating"`
+	Organized bool `json:"organized"`
+	// Hidden images are redundant copies, such as near-identical images
+	// of a burst, which clients may choose not to show
+	Hidden   bool   `json:"hidden"`
+	OCounter int    `json:"o_counter"`
+	StudioID *int   `json:"studio_id"`
+	URL      string `json:"url"`
+	Date     *Date  `json:"date"`
+
+	// Location is a free text description of where the image was shot
+	Location  string   `json:"location"`
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
 
 	// transient - not persisted
 	Files         RelatedImageFiles
@@ -122,8 +130,12 @@ type ImagePartial struct {
 	URL       OptionalString
 	Date      OptionalDate
 	Organized OptionalBool
+	Hidden    OptionalBool
 	OCounter  OptionalInt
 	StudioID  OptionalInt
+	Location  OptionalString
+	Latitude  OptionalFloat64
+	Longitude OptionalFloat64
 	CreatedAt OptionalTime
 	UpdatedAt OptionalTime
 
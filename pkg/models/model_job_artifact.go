@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// JobArtifact is an output file attached to a job, such as an export or a
+// report.
+type JobArtifact struct {
+	ID int `db:"id" json:"id"`
+	// JobID is the ID of the job when it ran. Job IDs are not unique across
+	// restarts.
+	JobID          int    `db:"job_id" json:"job_id"`
+	JobDescription string `db:"job_description" json:"job_description"`
+	Name           string `db:"name" json:"name"`
+	// Path is relative to the artifacts directory
+	Path        string    `db:"path" json:"path"`
+	ContentType string    `db:"content_type" json:"content_type"`
+	Size        int64     `db:"size" json:"size"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+}
+
+type JobArtifacts []*JobArtifact
+
+func (m *JobArtifacts) Append(o interface{}) {
+	*m = append(*m, o.(*JobArtifact))
+}
+
+func (m *JobArtifacts) New() interface{} {
+	return &JobArtifact{}
+}
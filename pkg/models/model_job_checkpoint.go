@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// JobCheckpoint is the persisted progress of a job, used to resume the job
+// if it is interrupted.
+type JobCheckpoint struct {
+	Job string `db:"job" json:"job"`
+	// Input is the job input, encoded as JSON
+	Input     string    `db:"input" json:"input"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+
+	// Progress is the last processed ID of each task of the job
+	Progress map[string]int `db:"-" json:"progress"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// DefaultPlayQueue is the name of the play queue used when no queue is
+// specified. It is shared by all clients, so that it syncs across devices.
+const DefaultPlayQueue = "default"
+
+// PlayQueueItem is a scene in a play queue.
+type PlayQueueItem struct {
+	ID      int    `db:"id" json:"id"`
+	Queue   string `db:"queue" json:"queue"`
+	SceneID int    `db:"scene_id" json:"scene_id"`
+	// Items are played in ascending position order
+	Position  int       `db:"position" json:"position"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+type PlayQueueItems []*PlayQueueItem
+
+func (m *PlayQueueItems) Append(o interface{}) {
+	*m = append(*m, o.(*PlayQueueItem))
+}
+
+func (m *PlayQueueItems) New() interface{} {
+	return &PlayQueueItem{}
+}
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+type PlaybackEventType string
+
+const (
+	PlaybackEventTypeStart         PlaybackEventType = "START"
+	PlaybackEventTypePause         PlaybackEventType = "PAUSE"
+	PlaybackEventTypeSeek          PlaybackEventType = "SEEK"
+	PlaybackEventTypeQualitySwitch PlaybackEventType = "QUALITY_SWITCH"
+	PlaybackEventTypeError         PlaybackEventType = "ERROR"
+	PlaybackEventTypeComplete      PlaybackEventType = "COMPLETE"
+)
+
+var AllPlaybackEventType = []PlaybackEventType{
+	PlaybackEventTypeStart,
+	PlaybackEventTypePause,
+	PlaybackEventTypeSeek,
+	PlaybackEventTypeQualitySwitch,
+	PlaybackEventTypeError,
+	PlaybackEventTypeComplete,
+}
+
+func (e PlaybackEventType) IsValid() bool {
+	switch e {
+	case PlaybackEventTypeStart, PlaybackEventTypePause, PlaybackEventTypeSeek, PlaybackEventTypeQualitySwitch, PlaybackEventTypeError, PlaybackEventTypeComplete:
+		return true
+	}
+	return false
+}
+
+func (e PlaybackEventType) String() string {
+	return string(e)
+}
+
+func (e *PlaybackEventType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = PlaybackEventType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid PlaybackEventType", str)
+	}
+	return nil
+}
+
+func (e PlaybackEventType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// PlaybackEvent is a player event reported by a client during playback of a
+// scene.
+type PlaybackEvent struct {
+	ID      int `db:"id" json:"id"`
+	SceneID int `db:"scene_id" json:"scene_id"`
+	// Client-generated identifier of the playback session
+	SessionID string            `db:"session_id" json:"session_id"`
+	Type      PlaybackEventType `db:"event_type" json:"event_type"`
+	// Playback position in seconds
+	Position *float64 `db:"position" json:"position"`
+	// Event specific details, such as the new quality or the error message
+	Details   *string   `db:"details" json:"details"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// PlaybackDropOff is the number of incomplete playback sessions which ended
+// within a range of a scene.
+type PlaybackDropOff struct {
+	// Start of the range in seconds
+	Start float64 `json:"start"`
+	// End of the range in seconds
+	End   float64 `json:"end"`
+	Count int     `json:"count"`
+}
+
+// PlaybackDropOffs groups the end positions of incomplete playback sessions
+// into the provided number of equal ranges of the duration.
+func PlaybackDropOffs(positions []float64, duration float64, ranges int) []*PlaybackDropOff {
+	if duration <= 0 || ranges <= 0 {
+		return []*PlaybackDropOff{}
+	}
+
+	width := duration / float64(ranges)
+	ret := make([]*PlaybackDropOff, ranges)
+	for i := range ret {
+		ret[i] = &PlaybackDropOff{
+			Start: float64(i) * width,
+			End:   float64(i+1) * width,
+		}
+	}
+
+	for _, p := range positions {
+		i := int(p / width)
+		if i < 0 {
+			i = 0
+		} else if i >= ranges {
+			i = ranges - 1
+		}
+		ret[i].Count++
+	}
+
+	return ret
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlaybackDropOffs(t *testing.T) {
+	tests := []struct {
+		name      string
+		positions []float64
+		duration  float64
+		ranges    int
+		want      []int
+	}{
+		{"no duration", []float64{1}, 0, 4, nil},
+		{"no ranges", []float64{1}, 100, 0, nil},
+		{"empty", nil, 100, 4, []int{0, 0, 0, 0}},
+		{"grouped", []float64{0, 10, 24.9, 25, 60, 99}, 100, 4, []int{3, 1, 1, 1}},
+		{"out of range", []float64{-5, 150, 100}, 100, 4, []int{1, 0, 0, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := PlaybackDropOffs(tt.positions, tt.duration, tt.ranges)
+			if !assert.Len(t, got, len(tt.want)) {
+				return
+			}
+
+			width := tt.duration / float64(tt.ranges)
+			for i, d := range got {
+				assert.Equal(t, float64(i)*width, d.Start)
+				assert.Equal(t, float64(i+1)*width, d.End)
+				assert.Equal(t, tt.want[i], d.Count)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// RestrictionProfile hides the content tagged with any of its tags, or
+// belonging to any of its studios, from the users assigned to the profile
+// and from sessions unlocked with the PIN of the profile.
+type RestrictionProfile struct {
+	ID   int    `db:"id" json:"id"`
+	Name string `db:"name" json:"name"`
+	// bcrypt hash of the PIN unlocking sessions with the profile, if set
+	PinHash   *string   `db:"pin_hash" json:"-"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+type RestrictionProfiles []*RestrictionProfile
+
+func (m *RestrictionProfiles) Append(o interface{}) {
+	*m = append(*m, o.(*RestrictionProfile))
+}
+
+func (m *RestrictionProfiles) New() interface{} {
+	return &RestrictionProfile{}
+}
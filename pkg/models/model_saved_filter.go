@@ -1,6 +1,9 @@
 package models
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"strconv"
@@ -59,12 +62,119 @@ func (e FilterMode) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
+type SavedFilterDisplayMode string
+
+const (
+	SavedFilterDisplayModeGrid   SavedFilterDisplayMode = "GRID"
+	SavedFilterDisplayModeList   SavedFilterDisplayMode = "LIST"
+	SavedFilterDisplayModeWall   SavedFilterDisplayMode = "WALL"
+	SavedFilterDisplayModeTagger SavedFilterDisplayMode = "TAGGER"
+)
+
+var AllSavedFilterDisplayMode = []SavedFilterDisplayMode{
+	SavedFilterDisplayModeGrid,
+	SavedFilterDisplayModeList,
+	SavedFilterDisplayModeWall,
+	SavedFilterDisplayModeTagger,
+}
+
+func (e SavedFilterDisplayMode) IsValid() bool {
+	switch e {
+	case SavedFilterDisplayModeGrid, SavedFilterDisplayModeList, SavedFilterDisplayModeWall, SavedFilterDisplayModeTagger:
+		return true
+	}
+	return false
+}
+
+func (e SavedFilterDisplayMode) String() string {
+	return string(e)
+}
+
+func (e *SavedFilterDisplayMode) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SavedFilterDisplayMode(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SavedFilterDisplayMode", str)
+	}
+	return nil
+}
+
+func (e SavedFilterDisplayMode) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// SavedFilterDisplayOptions are the display preferences applied by clients
+// when showing the results of a saved filter. Unset options use the
+// preferences of the client.
+type SavedFilterDisplayOptions struct {
+	DisplayMode *SavedFilterDisplayMode `json:"display_mode,omitempty"`
+	// Index of the card size, where 0 is the smallest
+	ZoomIndex *int               `json:"zoom_index,omitempty"`
+	PerPage   *int               `json:"per_page,omitempty"`
+	Sort      *string            `json:"sort,omitempty"`
+	Direction *SortDirectionEnum `json:"direction,omitempty"`
+	// Columns shown in the list display mode, in order
+	Columns []string `json:"columns,omitempty"`
+}
+
+// Validate returns an error if any of the options are invalid.
+func (o SavedFilterDisplayOptions) Validate() error {
+	switch {
+	case o.DisplayMode != nil && !o.DisplayMode.IsValid():
+		return fmt.Errorf("invalid display mode %q", *o.DisplayMode)
+	case o.ZoomIndex != nil && *o.ZoomIndex < 0:
+		return errors.New("zoom index must not be negative")
+	case o.PerPage != nil && *o.PerPage < 1 && *o.PerPage != PerPageAll:
+		return fmt.Errorf("per page must be positive or %d", PerPageAll)
+	case o.Direction != nil && !o.Direction.IsValid():
+		return fmt.Errorf("invalid sort direction %q", *o.Direction)
+	}
+
+	seen := make(map[string]bool)
+	for _, c := range o.Columns {
+		if c == "" {
+			return errors.New("column must not be empty")
+		}
+		if seen[c] {
+			return fmt.Errorf("duplicate column %q", c)
+		}
+		seen[c] = true
+	}
+
+	return nil
+}
+
+// SavedFilterDisplayOptions is stored as a JSON object in the database.
+func (o SavedFilterDisplayOptions) Value() (driver.Value, error) {
+	data, err := json.Marshal(o)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (o *SavedFilterDisplayOptions) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case string:
+		return json.Unmarshal([]byte(src), o)
+	case []byte:
+		return json.Unmarshal(src, o)
+	}
+	return fmt.Errorf("cannot scan %T into SavedFilterDisplayOptions", src)
+}
+
 type SavedFilter struct {
 	ID   int        `db:"id" json:"id"`
 	Mode FilterMode `db:"mode" json:"mode"`
 	Name string     `db:"name" json:"name"`
 	// JSON-encoded filter string
 	Filter string `db:"filter" json:"filter"`
+	// Display preferences of the filter, if any
+	DisplayOptions *SavedFilterDisplayOptions `db:"display_options" json:"display_options"`
 }
 
 type SavedFilters []*SavedFilter
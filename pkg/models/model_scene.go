@@ -22,8 +22,15 @@ type Scene struct {
 	// Rating expressed in 1-100 scale
 	Rating    *int `json:"rating"`
 	Organized bool `json:"organized"`
-	OCounter  int  `json:"o_counter"`
-	StudioID  *int `json:"studio_id"`
+	// Inbox scenes were added by a scan and have not yet been reviewed
+	Inbox    bool `json:"inbox"`
+	OCounter int  `json:"o_counter"`
+	StudioID *int `json:"studio_id"`
+
+	// Location is a free text description of where the scene was shot
+	Location  string   `json:"location"`
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
 
 	// transient - not persisted
 	Files         RelatedVideoFiles
@@ -149,8 +156,12 @@ type ScenePartial struct {
 	// Rating expressed in 1-100 scale
 	Rating       OptionalInt
 	Organized    OptionalBool
+	Inbox        OptionalBool
 	OCounter     OptionalInt
 	StudioID     OptionalInt
+	Location     OptionalString
+	Latitude     OptionalFloat64
+	Longitude    OptionalFloat64
 	CreatedAt    OptionalTime
 	UpdatedAt    OptionalTime
 	ResumeTime   OptionalFloat64
@@ -194,17 +205,21 @@ type SceneUpdateInput struct {
 	OCounter     *int               `json:"o_counter"`
 	Organized    *bool              `json:"organized"`
 	StudioID     *string            `json:"studio_id"`
+	Location     *string            `json:"location"`
+	Latitude     *float64           `json:"latitude"`
+	Longitude    *float64           `json:"longitude"`
 	GalleryIds   []string           `json:"gallery_ids"`
 	PerformerIds []string           `json:"performer_ids"`
 	Movies       []*SceneMovieInput `json:"movies"`
 	TagIds       []string           `json:"tag_ids"`
 	// This should be a URL or a base64 encoded data URL
-	CoverImage    *string   `json:"cover_image"`
-	StashIds      []StashID `json:"stash_ids"`
-	ResumeTime    *float64  `json:"resume_time"`
-	PlayDuration  *float64  `json:"play_duration"`
-	PlayCount     *int      `json:"play_count"`
-	PrimaryFileID *string   `json:"primary_file_id"`
+	CoverImage    *string      `json:"cover_image"`
+	StashIds      []StashID    `json:"stash_ids"`
+	ExternalIds   []ExternalID `json:"external_ids"`
+	ResumeTime    *float64     `json:"resume_time"`
+	PlayDuration  *float64     `json:"play_duration"`
+	PlayCount     *int         `json:"play_count"`
+	PrimaryFileID *string      `json:"primary_file_id"`
 }
 
 // UpdateInput constructs a SceneUpdateInput using the populated fields in the ScenePartial object.
@@ -232,6 +247,9 @@ func (s ScenePartial) UpdateInput(id int) SceneUpdateInput {
 		Rating100:    s.Rating.Ptr(),
 		Organized:    s.Organized.Ptr(),
 		StudioID:     s.StudioID.StringPtr(),
+		Location:     s.Location.Ptr(),
+		Latitude:     s.Latitude.Ptr(),
+		Longitude:    s.Longitude.Ptr(),
 		GalleryIds:   s.GalleryIDs.IDStrings(),
 		PerformerIds: s.PerformerIDs.IDStrings(),
 		Movies:       s.MovieIDs.SceneMovieInputs(),
@@ -312,3 +330,26 @@ type VideoCaption struct {
 func (c VideoCaption) Path(filePath string) string {
 	return filepath.Join(filepath.Dir(filePath), c.Filename)
 }
+
+// FunscriptAxis holds the metrics of a secondary axis script of a
+// multi-axis funscript.
+type FunscriptAxis struct {
+	Axis             string `json:"axis"`
+	InteractiveSpeed *int   `json:"interactive_speed"`
+}
+
+// FunscriptStats holds statistics of the main script of an interactive
+// video file.
+type FunscriptStats struct {
+	// Speed percentiles in position units per second
+	SpeedP5  int `db:"speed_p5" json:"speed_p5"`
+	SpeedP50 int `db:"speed_p50" json:"speed_p50"`
+	SpeedP95 int `db:"speed_p95" json:"speed_p95"`
+	SpeedMax int `db:"speed_max" json:"speed_max"`
+	// Number of actions within the duration of the video
+	ActionCount int `db:"action_count" json:"action_count"`
+	// Average distance between consecutive positions, from 0 to 100
+	AverageStrokeLength float64 `db:"average_stroke_length" json:"average_stroke_length"`
+	// Fraction of the video duration covered by scripted movement, from 0 to 1
+	Coverage float64 `db:"coverage" json:"coverage"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// SceneFlag is a user-defined list of scenes, such as "Watch later". Flags
+// track curation state and are kept separate from tags.
+type SceneFlag struct {
+	ID        int       `db:"id" json:"id"`
+	Name      string    `db:"name" json:"name"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+type SceneFlags []*SceneFlag
+
+func (m *SceneFlags) Append(o interface{}) {
+	*m = append(*m, o.(*SceneFlag))
+}
+
+func (m *SceneFlags) New() interface{} {
+	return &SceneFlag{}
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// ScraperCacheEntry is the cached result of a scrape.
+type ScraperCacheEntry struct {
+	ScraperID string `db:"scraper_id" json:"scraper_id"`
+	// Key identifies the scrape operation and its input
+	Key string `db:"key" json:"key"`
+	// Data is the JSON-encoded scraped content
+	Data      []byte    `db:"data" json:"data"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+type ScraperCacheEntries []*ScraperCacheEntry
+
+func (m *ScraperCacheEntries) Append(o interface{}) {
+	*m = append(*m, o.(*ScraperCacheEntry))
+}
+
+func (m *ScraperCacheEntries) New() interface{} {
+	return &ScraperCacheEntry{}
+}
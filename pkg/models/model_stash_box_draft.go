@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// StashBoxDraft is a draft of a scene or performer submitted to a stash-box
+// instance.
+type StashBoxDraft struct {
+	ID       int    `db:"id" json:"id"`
+	Endpoint string `db:"endpoint" json:"endpoint"`
+	// DraftID is the ID of the draft on the stash-box instance
+	DraftID     string    `db:"draft_id" json:"draft_id"`
+	SceneID     *int      `db:"scene_id" json:"scene_id"`
+	PerformerID *int      `db:"performer_id" json:"performer_id"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+}
+
+type StashBoxDrafts []*StashBoxDraft
+
+func (m *StashBoxDrafts) Append(o interface{}) {
+	*m = append(*m, o.(*StashBoxDraft))
+}
+
+func (m *StashBoxDrafts) New() interface{} {
+	return &StashBoxDraft{}
+}
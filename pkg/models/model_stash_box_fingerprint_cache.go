@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// StashBoxFingerprintCacheEntry is the cached result of querying a stash-box
+// instance for the scenes matching a set of fingerprints.
+type StashBoxFingerprintCacheEntry struct {
+	Endpoint string `db:"endpoint" json:"endpoint"`
+	// Key identifies the set of fingerprints which was queried
+	Key string `db:"key" json:"key"`
+	// Data is the JSON-encoded list of matching stash-box scenes
+	Data      []byte    `db:"data" json:"data"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+type StashBoxFingerprintCacheEntries []*StashBoxFingerprintCacheEntry
+
+func (m *StashBoxFingerprintCacheEntries) Append(o interface{}) {
+	*m = append(*m, o.(*StashBoxFingerprintCacheEntry))
+}
+
+func (m *StashBoxFingerprintCacheEntries) New() interface{} {
+	return &StashBoxFingerprintCacheEntry{}
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// StashBoxMatchSuggestion is a stash-box scene matching the fingerprints of a
+// scene without a stash ID for the stash-box, awaiting review.
+type StashBoxMatchSuggestion struct {
+	ID       int    `db:"id" json:"id"`
+	SceneID  int    `db:"scene_id" json:"scene_id"`
+	Endpoint string `db:"endpoint" json:"endpoint"`
+	StashID  string `db:"stash_id" json:"stash_id"`
+	// Data is the JSON-encoded scraped scene
+	Data []byte `db:"data" json:"data"`
+	// Rejected suggestions are kept so that they are not suggested again
+	Rejected  bool      `db:"rejected" json:"rejected"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+type StashBoxMatchSuggestions []*StashBoxMatchSuggestion
+
+func (m *StashBoxMatchSuggestions) Append(o interface{}) {
+	*m = append(*m, o.(*StashBoxMatchSuggestion))
+}
+
+func (m *StashBoxMatchSuggestions) New() interface{} {
+	return &StashBoxMatchSuggestion{}
+}
@@ -19,6 +19,7 @@ type Studio struct {
 	Rating        sql.NullInt64  `db:"rating" json:"rating"`
 	Details       sql.NullString `db:"details" json:"details"`
 	IgnoreAutoTag bool           `db:"ignore_auto_tag" json:"ignore_auto_tag"`
+	Favorite      bool           `db:"favorite" json:"favorite"`
 }
 
 type StudioPartial struct {
@@ -33,6 +34,7 @@ type StudioPartial struct {
 	Rating        *sql.NullInt64  `db:"rating" json:"rating"`
 	Details       *sql.NullString `db:"details" json:"details"`
 	IgnoreAutoTag *bool           `db:"ignore_auto_tag" json:"ignore_auto_tag"`
+	Favorite      *bool           `db:"favorite" json:"favorite"`
 }
 
 var DefaultStudioImage = "data:image/png;base64,iVBORw0KGgoAAAANSUhEUgAAAGQAAABkCAYAAABw4pVUAAAABmJLR0QA/wD/AP+gvaeTAAAACXBIWXMAAA3XAAAN1wFCKJt4AAAAB3RJTUUH4wgVBQsJl1CMZAAAASJJREFUeNrt3N0JwyAYhlEj3cj9R3Cm5rbkqtAP+qrnGaCYHPwJpLlaa++mmLpbAERAgAgIEAEBIiBABERAgAgIEAEBIiBABERAgAgIEAHZuVflj40x4i94zhk9vqsVvEq6AsQqMP1EjORx20OACAgQRRx7T+zzcFBxcjNDfoB4ntQqTm5Awo7MlqywZxcgYQ+RlqywJ3ozJAQCSBiEJSsQA0gYBpDAgAARECACAkRAgAgIEAERECACAmSjUv6eAOSB8m8YIGGzBUjYbAESBgMkbBkDEjZbgITBAClcxiqQvEoatreYIWEBASIgJ4Gkf11ntXH3nS9uxfGWfJ5J9hAgAgJEQAQEiIAAERAgAgJEQAQEiIAAERAgAgJEQAQEiL7qBuc6RKLHxr0CAAAAAElFTkSuQmCC"
@@ -6,21 +6,27 @@ import (
 )
 
 type Tag struct {
-	ID            int             `db:"id" json:"id"`
-	Name          string          `db:"name" json:"name"` // TODO make schema not null
-	Description   sql.NullString  `db:"description" json:"description"`
-	IgnoreAutoTag bool            `db:"ignore_auto_tag" json:"ignore_auto_tag"`
-	CreatedAt     SQLiteTimestamp `db:"created_at" json:"created_at"`
-	UpdatedAt     SQLiteTimestamp `db:"updated_at" json:"updated_at"`
+	ID            int            `db:"id" json:"id"`
+	Name          string         `db:"name" json:"name"` // TODO make schema not null
+	Description   sql.NullString `db:"description" json:"description"`
+	IgnoreAutoTag bool           `db:"ignore_auto_tag" json:"ignore_auto_tag"`
+	// ContentWarning tags, and their child tags, hide tagged content unless
+	// content warnings are unlocked
+	ContentWarning bool            `db:"content_warning" json:"content_warning"`
+	Favorite       bool            `db:"favorite" json:"favorite"`
+	CreatedAt      SQLiteTimestamp `db:"created_at" json:"created_at"`
+	UpdatedAt      SQLiteTimestamp `db:"updated_at" json:"updated_at"`
 }
 
 type TagPartial struct {
-	ID            int              `db:"id" json:"id"`
-	Name          *string          `db:"name" json:"name"` // TODO make schema not null
-	Description   *sql.NullString  `db:"description" json:"description"`
-	IgnoreAutoTag *bool            `db:"ignore_auto_tag" json:"ignore_auto_tag"`
-	CreatedAt     *SQLiteTimestamp `db:"created_at" json:"created_at"`
-	UpdatedAt     *SQLiteTimestamp `db:"updated_at" json:"updated_at"`
+	ID             int              `db:"id" json:"id"`
+	Name           *string          `db:"name" json:"name"` // TODO make schema not null
+	Description    *sql.NullString  `db:"description" json:"description"`
+	IgnoreAutoTag  *bool            `db:"ignore_auto_tag" json:"ignore_auto_tag"`
+	ContentWarning *bool            `db:"content_warning" json:"content_warning"`
+	Favorite       *bool            `db:"favorite" json:"favorite"`
+	CreatedAt      *SQLiteTimestamp `db:"created_at" json:"created_at"`
+	UpdatedAt      *SQLiteTimestamp `db:"updated_at" json:"updated_at"`
 }
 
 type TagPath struct {
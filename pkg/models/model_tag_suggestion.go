@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// TagSuggestion is a tag suggested for a scene, awaiting review.
+type TagSuggestion struct {
+	SceneID int `db:"scene_id" json:"scene_id"`
+	TagID   int `db:"tag_id" json:"tag_id"`
+	// Proportion of the correlated scenes that have the tag, between 0 and 1
+	Confidence float64 `db:"confidence" json:"confidence"`
+	// Rejected suggestions are kept so that they are not suggested again
+	Rejected  bool      `db:"rejected" json:"rejected"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+type TagSuggestions []*TagSuggestion
+
+func (m *TagSuggestions) Append(o interface{}) {
+	*m = append(*m, o.(*TagSuggestion))
+}
+
+func (m *TagSuggestions) New() interface{} {
+	return &TagSuggestion{}
+}
+
+// SceneRelationshipIDs is the IDs of the studio, performers and tags of a
+// scene.
+type SceneRelationshipIDs struct {
+	SceneID      int
+	StudioID     *int
+	PerformerIDs []int
+	TagIDs       []int
+}
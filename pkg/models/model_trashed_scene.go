@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// TrashedScene is a deleted scene whose files were moved to the trash
+// directory, so that the scene can be restored.
+type TrashedScene struct {
+	ID int `db:"id" json:"id"`
+	// SceneID is the ID of the scene before it was deleted. Restored scenes
+	// are assigned a new ID.
+	SceneID int    `db:"scene_id" json:"scene_id"`
+	Title   string `db:"title" json:"title"`
+	// Path is the original path of the primary file of the scene
+	Path string `db:"path" json:"path"`
+	// JSON-encoded state of the scene and its trashed files
+	Data      []byte    `db:"data" json:"data"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+type TrashedScenes []*TrashedScene
+
+func (m *TrashedScenes) Append(o interface{}) {
+	*m = append(*m, o.(*TrashedScene))
+}
+
+func (m *TrashedScenes) New() interface{} {
+	return &TrashedScene{}
+}
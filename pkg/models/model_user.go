@@ -0,0 +1,113 @@
+package models
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+type UserRole string
+
+const (
+	// UserRoleAdmin can do anything, including managing users, changing the
+	// configuration and running system tasks.
+	UserRoleAdmin UserRole = "ADMIN"
+	// UserRoleEditor can edit the library, but cannot run the operations
+	// reserved for administrators.
+	UserRoleEditor UserRole = "EDITOR"
+	// UserRoleReadOnly can browse and play the library, but cannot edit it.
+	UserRoleReadOnly UserRole = "READ_ONLY"
+)
+
+var AllUserRole = []UserRole{
+	UserRoleAdmin,
+	UserRoleEditor,
+	UserRoleReadOnly,
+}
+
+func (e UserRole) IsValid() bool {
+	switch e {
+	case UserRoleAdmin, UserRoleEditor, UserRoleReadOnly:
+		return true
+	}
+	return false
+}
+
+func (e UserRole) String() string {
+	return string(e)
+}
+
+func (e *UserRole) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = UserRole(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid UserRole", str)
+	}
+	return nil
+}
+
+func (e UserRole) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e UserRole) level() int {
+	switch e {
+	case UserRoleAdmin:
+		return 2
+	case UserRoleEditor:
+		return 1
+	}
+	return 0
+}
+
+// Includes returns true if the role grants the permissions of the other role.
+func (e UserRole) Includes(other UserRole) bool {
+	return e.level() >= other.level()
+}
+
+// User is an account stored in the database. The administrator configured
+// with the username and password in the configuration is not stored.
+type User struct {
+	ID           int      `db:"id" json:"id"`
+	Username     string   `db:"username" json:"username"`
+	PasswordHash string   `db:"password_hash" json:"-"`
+	Role         UserRole `db:"role" json:"role"`
+	// SHA-256 hash of the API key of the user, if generated
+	APIKeyHash *string `db:"api_key_hash" json:"-"`
+	// Restriction profile hiding content from the user, if any
+	RestrictionProfileID *int      `db:"restriction_profile_id" json:"restriction_profile_id"`
+	CreatedAt            time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt            time.Time `db:"updated_at" json:"updated_at"`
+}
+
+type Users []*User
+
+func (m *Users) Append(o interface{}) {
+	*m = append(*m, o.(*User))
+}
+
+func (m *Users) New() interface{} {
+	return &User{}
+}
+
+// UserExternalIdentity links a user to the subject identifying it in an
+// external authentication provider.
+type UserExternalIdentity struct {
+	Provider string `db:"provider" json:"provider"`
+	Subject  string `db:"subject" json:"subject"`
+}
+
+type UserExternalIdentities []UserExternalIdentity
+
+func (m *UserExternalIdentities) Append(o interface{}) {
+	*m = append(*m, *o.(*UserExternalIdentity))
+}
+
+func (m *UserExternalIdentities) New() interface{} {
+	return &UserExternalIdentity{}
+}
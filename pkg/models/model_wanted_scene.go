@@ -0,0 +1,108 @@
+package models
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+type WantedSceneScrapeStatus string
+
+const (
+	// the URL has not yet been scraped
+	WantedSceneScrapeStatusPending WantedSceneScrapeStatus = "PENDING"
+	// the URL was scraped successfully
+	WantedSceneScrapeStatusComplete WantedSceneScrapeStatus = "COMPLETE"
+	// scraping the URL failed
+	WantedSceneScrapeStatusFailed WantedSceneScrapeStatus = "FAILED"
+)
+
+var AllWantedSceneScrapeStatus = []WantedSceneScrapeStatus{
+	WantedSceneScrapeStatusPending,
+	WantedSceneScrapeStatusComplete,
+	WantedSceneScrapeStatusFailed,
+}
+
+func (e WantedSceneScrapeStatus) IsValid() bool {
+	switch e {
+	case WantedSceneScrapeStatusPending, WantedSceneScrapeStatusComplete, WantedSceneScrapeStatusFailed:
+		return true
+	}
+	return false
+}
+
+func (e WantedSceneScrapeStatus) String() string {
+	return string(e)
+}
+
+func (e *WantedSceneScrapeStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = WantedSceneScrapeStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid WantedSceneScrapeStatus", str)
+	}
+	return nil
+}
+
+func (e WantedSceneScrapeStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// WantedScene is a scene that the user wants to add to their library. It is
+// fulfilled when a scene matching its stash ID, URL, fingerprints or title is
+// found.
+type WantedScene struct {
+	ID      int     `db:"id" json:"id"`
+	Title   *string `db:"title" json:"title"`
+	URL     *string `db:"url" json:"url"`
+	Details *string `db:"details" json:"details"`
+	// Date in YYYY-MM-DD format
+	Date *string `db:"date" json:"date"`
+	// ScrapeStatus is the status of scraping the URL. Nil for wanted scenes
+	// that were not created from a URL
+	ScrapeStatus     *WantedSceneScrapeStatus `db:"scrape_status" json:"scrape_status"`
+	ScrapeError      *string                  `db:"scrape_error" json:"scrape_error"`
+	StashBoxEndpoint *string                  `db:"stash_box_endpoint" json:"stash_box_endpoint"`
+	StashID          *string                  `db:"stash_id" json:"stash_id"`
+	Checksum         *string                  `db:"checksum" json:"checksum"`
+	OSHash           *string                  `db:"oshash" json:"oshash"`
+	Phash            *int64                   `db:"phash" json:"phash"`
+	// ID of the scene that fulfilled this item
+	SceneID     *int       `db:"scene_id" json:"scene_id"`
+	FulfilledAt *time.Time `db:"fulfilled_at" json:"fulfilled_at"`
+	CreatedAt   time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+func (w WantedScene) IsFulfilled() bool {
+	return w.SceneID != nil
+}
+
+// DisplayName returns a display name for the wanted scene for logging purposes.
+func (w WantedScene) DisplayName() string {
+	switch {
+	case w.Title != nil && *w.Title != "":
+		return *w.Title
+	case w.StashID != nil && *w.StashID != "":
+		return *w.StashID
+	case w.URL != nil && *w.URL != "":
+		return *w.URL
+	}
+
+	return "#" + strconv.Itoa(w.ID)
+}
+
+type WantedScenes []*WantedScene
+
+func (m *WantedScenes) Append(o interface{}) {
+	*m = append(*m, o.(*WantedScene))
+}
+
+func (m *WantedScenes) New() interface{} {
+	return &WantedScene{}
+}
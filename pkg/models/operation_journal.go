@@ -0,0 +1,149 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+type OperationJournalEntityType string
+
+const (
+	OperationJournalEntityTypeScene       OperationJournalEntityType = "SCENE"
+	OperationJournalEntityTypeImage       OperationJournalEntityType = "IMAGE"
+	OperationJournalEntityTypeGallery     OperationJournalEntityType = "GALLERY"
+	OperationJournalEntityTypePerformer   OperationJournalEntityType = "PERFORMER"
+	OperationJournalEntityTypeStudio      OperationJournalEntityType = "STUDIO"
+	OperationJournalEntityTypeTag         OperationJournalEntityType = "TAG"
+	OperationJournalEntityTypeMovie       OperationJournalEntityType = "MOVIE"
+	OperationJournalEntityTypeSceneMarker OperationJournalEntityType = "SCENE_MARKER"
+)
+
+var AllOperationJournalEntityType = []OperationJournalEntityType{
+	OperationJournalEntityTypeScene,
+	OperationJournalEntityTypeImage,
+	OperationJournalEntityTypeGallery,
+	OperationJournalEntityTypePerformer,
+	OperationJournalEntityTypeStudio,
+	OperationJournalEntityTypeTag,
+	OperationJournalEntityTypeMovie,
+	OperationJournalEntityTypeSceneMarker,
+}
+
+func (e OperationJournalEntityType) IsValid() bool {
+	switch e {
+	case OperationJournalEntityTypeScene, OperationJournalEntityTypeImage, OperationJournalEntityTypeGallery, OperationJournalEntityTypePerformer, OperationJournalEntityTypeStudio, OperationJournalEntityTypeTag, OperationJournalEntityTypeMovie, OperationJournalEntityTypeSceneMarker:
+		return true
+	}
+	return false
+}
+
+func (e OperationJournalEntityType) String() string {
+	return string(e)
+}
+
+func (e *OperationJournalEntityType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = OperationJournalEntityType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid OperationJournalEntityType", str)
+	}
+	return nil
+}
+
+func (e OperationJournalEntityType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type OperationJournalOperation string
+
+const (
+	OperationJournalOperationCreate  OperationJournalOperation = "CREATE"
+	OperationJournalOperationUpdate  OperationJournalOperation = "UPDATE"
+	OperationJournalOperationDestroy OperationJournalOperation = "DESTROY"
+)
+
+var AllOperationJournalOperation = []OperationJournalOperation{
+	OperationJournalOperationCreate,
+	OperationJournalOperationUpdate,
+	OperationJournalOperationDestroy,
+}
+
+func (e OperationJournalOperation) IsValid() bool {
+	switch e {
+	case OperationJournalOperationCreate, OperationJournalOperationUpdate, OperationJournalOperationDestroy:
+		return true
+	}
+	return false
+}
+
+func (e OperationJournalOperation) String() string {
+	return string(e)
+}
+
+func (e *OperationJournalOperation) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = OperationJournalOperation(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid OperationJournalOperation", str)
+	}
+	return nil
+}
+
+func (e OperationJournalOperation) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// OperationJournalEntry is a change to an object, recorded in the same
+// transaction as the change.
+type OperationJournalEntry struct {
+	// ID increases with each entry, and is used as the journal cursor
+	ID         int                        `db:"id" json:"id"`
+	EntityType OperationJournalEntityType `db:"entity_type" json:"entity_type"`
+	EntityID   int                        `db:"entity_id" json:"entity_id"`
+	Operation  OperationJournalOperation  `db:"operation" json:"operation"`
+	// Revision is the number of operations on the object, including this one
+	Revision  int       `db:"revision" json:"revision"`
+	Timestamp time.Time `db:"created_at" json:"timestamp"`
+}
+
+type OperationJournalEntries []*OperationJournalEntry
+
+func (m *OperationJournalEntries) Append(o interface{}) {
+	*m = append(*m, o.(*OperationJournalEntry))
+}
+
+func (m *OperationJournalEntries) New() interface{} {
+	return &OperationJournalEntry{}
+}
+
+type OperationJournalReader interface {
+	// FindAfter returns up to limit entries after the cursor in journal
+	// order, optionally only of the entity types.
+	FindAfter(ctx context.Context, cursor int, entityTypes []OperationJournalEntityType, limit int) ([]*OperationJournalEntry, error)
+	// Latest returns the cursor of the newest entry, or 0 if the journal is
+	// empty.
+	Latest(ctx context.Context) (int, error)
+}
+
+type OperationJournalWriter interface {
+	// Compact deletes the entries up to and including the cursor that are
+	// superseded by a later entry for the same object, returning the number
+	// of entries deleted.
+	Compact(ctx context.Context, cursor int) (int64, error)
+}
+
+type OperationJournalReaderWriter interface {
+	OperationJournalReader
+	OperationJournalWriter
+}
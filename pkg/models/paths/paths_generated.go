@@ -20,6 +20,7 @@ type generatedPaths struct {
 	Transcodes         string
 	Downloads          string
 	Tmp                string
+	Artifacts          string
 	InteractiveHeatmap string
 }
 
@@ -32,6 +33,7 @@ func newGeneratedPaths(path string) *generatedPaths {
 	gp.Transcodes = filepath.Join(path, "transcodes")
 	gp.Downloads = filepath.Join(path, "download_stage")
 	gp.Tmp = filepath.Join(path, "tmp")
+	gp.Artifacts = filepath.Join(path, "artifacts")
 	gp.InteractiveHeatmap = filepath.Join(path, "interactive_heatmaps")
 	return &gp
 }
@@ -2,6 +2,7 @@ package paths
 
 import (
 	"path/filepath"
+	"strconv"
 
 	"github.com/stashapp/stash/pkg/fsutil"
 )
@@ -54,6 +55,28 @@ func (sp *scenePaths) GetSpriteVttFilePath(checksum string) string {
 	return filepath.Join(sp.Vtt, checksum+"_thumbs.vtt")
 }
 
+func (sp *scenePaths) GetHoverStripPath(checksum string) string {
+	return filepath.Join(sp.Vtt, checksum+"_hover.mp4")
+}
+
+func (sp *scenePaths) GetHoverStripVttPath(checksum string) string {
+	return filepath.Join(sp.Vtt, checksum+"_hover.vtt")
+}
+
 func (sp *scenePaths) GetInteractiveHeatmapPath(checksum string) string {
 	return filepath.Join(sp.InteractiveHeatmap, checksum+".png")
 }
+
+// GetChapterThumbnailDir returns the directory containing the chapter
+// thumbnails of a scene.
+func (sp *scenePaths) GetChapterThumbnailDir(checksum string) string {
+	return filepath.Join(sp.Screenshots, checksum+"_chapters")
+}
+
+// GetChapterThumbnailPath returns the path of the thumbnail of the chapter
+// starting at start seconds. Thumbnails are keyed by start time in
+// milliseconds, so that moved chapters get new thumbnails.
+func (sp *scenePaths) GetChapterThumbnailPath(checksum string, start float64) string {
+	millis := int64(start * 1000)
+	return filepath.Join(sp.GetChapterThumbnailDir(checksum), strconv.FormatInt(millis, 10)+".jpg")
+}
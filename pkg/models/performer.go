@@ -138,6 +138,8 @@ type PerformerFilterType struct {
 	CreatedAt *TimestampCriterionInput `json:"created_at"`
 	// Filter by updated at
 	UpdatedAt *TimestampCriterionInput `json:"updated_at"`
+	// Filter by custom field values
+	CustomFields []*CustomFieldCriterionInput `json:"custom_fields"`
 }
 
 type PerformerFinder interface {
@@ -153,6 +155,7 @@ type PerformerReader interface {
 	FindByNames(ctx context.Context, names []string, nocase bool) ([]*Performer, error)
 	FindByStashID(ctx context.Context, stashID StashID) ([]*Performer, error)
 	FindByStashIDStatus(ctx context.Context, hasStashID bool, stashboxEndpoint string) ([]*Performer, error)
+	FindByExternalID(ctx context.Context, source string, externalID string) ([]*Performer, error)
 	CountByTagID(ctx context.Context, tagID int) (int, error)
 	Count(ctx context.Context) (int, error)
 	All(ctx context.Context) ([]*Performer, error)
@@ -162,7 +165,11 @@ type PerformerReader interface {
 	Query(ctx context.Context, performerFilter *PerformerFilterType, findFilter *FindFilterType) ([]*Performer, int, error)
 	AliasLoader
 	GetImage(ctx context.Context, performerID int) ([]byte, error)
+	GetSceneIDs(ctx context.Context, performerID int) ([]int, error)
+	GetImageIDs(ctx context.Context, performerID int) ([]int, error)
+	GetGalleryIDs(ctx context.Context, performerID int) ([]int, error)
 	StashIDLoader
+	ExternalIDLoader
 	TagIDLoader
 }
 
@@ -171,8 +178,10 @@ type PerformerWriter interface {
 	UpdatePartial(ctx context.Context, id int, updatedPerformer PerformerPartial) (*Performer, error)
 	Update(ctx context.Context, updatedPerformer *Performer) error
 	Destroy(ctx context.Context, id int) error
+	Merge(ctx context.Context, source []int, destination int) error
 	UpdateImage(ctx context.Context, performerID int, image []byte) error
 	DestroyImage(ctx context.Context, performerID int) error
+	ExternalIDUpdater
 }
 
 type PerformerReaderWriter interface {
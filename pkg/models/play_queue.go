@@ -0,0 +1,24 @@
+package models
+
+import "context"
+
+type PlayQueueReader interface {
+	// FindByQueue returns the items of the queue in play order.
+	FindByQueue(ctx context.Context, queue string) ([]*PlayQueueItem, error)
+}
+
+type PlayQueueWriter interface {
+	// Append adds the scenes to the end of the queue.
+	Append(ctx context.Context, queue string, sceneIDs []int) error
+	// UpdatePositions sets the position of each item of the queue to its
+	// index in itemIDs.
+	UpdatePositions(ctx context.Context, queue string, itemIDs []int) error
+	Destroy(ctx context.Context, queue string, itemIDs []int) error
+	// Clear removes all items from the queue.
+	Clear(ctx context.Context, queue string) error
+}
+
+type PlayQueueReaderWriter interface {
+	PlayQueueReader
+	PlayQueueWriter
+}
@@ -0,0 +1,24 @@
+package models
+
+import "context"
+
+type PlaybackEventReader interface {
+	// CountSessions returns the number of playback sessions of the scene, or
+	// of all scenes if sceneID is nil.
+	CountSessions(ctx context.Context, sceneID *int) (int, error)
+	// CountCompletedSessions returns the number of playback sessions with a
+	// completion event of the scene, or of all scenes if sceneID is nil.
+	CountCompletedSessions(ctx context.Context, sceneID *int) (int, error)
+	// IncompleteSessionPositions returns the last reported position of each
+	// playback session of the scene without a completion event.
+	IncompleteSessionPositions(ctx context.Context, sceneID int) ([]float64, error)
+}
+
+type PlaybackEventWriter interface {
+	Create(ctx context.Context, newObject PlaybackEvent) error
+}
+
+type PlaybackEventReaderWriter interface {
+	PlaybackEventReader
+	PlaybackEventWriter
+}
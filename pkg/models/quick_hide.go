@@ -0,0 +1,21 @@
+package models
+
+import "context"
+
+type quickHideKey struct{}
+
+// QuickHide returns a context in which queries only return content tagged
+// with one of the provided safe tags, or their descendants.
+func QuickHide(ctx context.Context, safeTagIDs []int) context.Context {
+	if safeTagIDs == nil {
+		safeTagIDs = []int{}
+	}
+	return context.WithValue(ctx, quickHideKey{}, safeTagIDs)
+}
+
+// QuickHideSafeTags returns the ids of the safe tags and true if quick hide
+// is enabled in the provided context.
+func QuickHideSafeTags(ctx context.Context) ([]int, bool) {
+	ret, ok := ctx.Value(quickHideKey{}).([]int)
+	return ret, ok
+}
@@ -14,16 +14,39 @@ type TxnManager interface {
 type Repository struct {
 	TxnManager
 
-	File        file.Store
-	Folder      file.FolderStore
-	Gallery     GalleryReaderWriter
-	Image       ImageReaderWriter
-	Movie       MovieReaderWriter
-	Performer   PerformerReaderWriter
-	Scene       SceneReaderWriter
-	SceneMarker SceneMarkerReaderWriter
-	ScrapedItem ScrapedItemReaderWriter
-	Studio      StudioReaderWriter
-	Tag         TagReaderWriter
-	SavedFilter SavedFilterReaderWriter
+	File               file.Store
+	Folder             file.FolderStore
+	Gallery            GalleryReaderWriter
+	Image              ImageReaderWriter
+	Movie              MovieReaderWriter
+	Performer          PerformerReaderWriter
+	Scene              SceneReaderWriter
+	SceneMarker        SceneMarkerReaderWriter
+	ScrapedItem        ScrapedItemReaderWriter
+	Studio             StudioReaderWriter
+	Tag                TagReaderWriter
+	SavedFilter        SavedFilterReaderWriter
+	WantedScene        WantedSceneReaderWriter
+	ActivityLog        ActivityLogReaderWriter
+	PlaybackEvent      PlaybackEventReaderWriter
+	SceneFlag          SceneFlagReaderWriter
+	SceneVersion       SceneVersionReaderWriter
+	BulkOperation      BulkOperationReaderWriter
+	TagSuggestion      TagSuggestionReaderWriter
+	PlayQueue          PlayQueueReaderWriter
+	JobCheckpoint      JobCheckpointReaderWriter
+	JobArtifact        JobArtifactReaderWriter
+	TrashedScene       TrashedSceneReaderWriter
+	CustomField        CustomFieldReaderWriter
+	EditHistory        EditHistoryReaderWriter
+	User               UserReaderWriter
+	RestrictionProfile RestrictionProfileReaderWriter
+	Search             SearchReader
+	Consistency        ConsistencyChecker
+
+	StashBoxFingerprintCache StashBoxFingerprintCacheReaderWriter
+	ScraperCache             ScraperCacheReaderWriter
+	StashBoxDraft            StashBoxDraftReaderWriter
+	StashBoxSync             StashBoxSyncReaderWriter
+	OperationJournal         OperationJournalReaderWriter
 }
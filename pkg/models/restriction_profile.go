@@ -0,0 +1,23 @@
+package models
+
+import "context"
+
+type RestrictionProfileReader interface {
+	Find(ctx context.Context, id int) (*RestrictionProfile, error)
+	All(ctx context.Context) ([]*RestrictionProfile, error)
+	GetTagIDs(ctx context.Context, id int) ([]int, error)
+	GetStudioIDs(ctx context.Context, id int) ([]int, error)
+}
+
+type RestrictionProfileWriter interface {
+	Create(ctx context.Context, newObject RestrictionProfile) (*RestrictionProfile, error)
+	Update(ctx context.Context, updatedObject RestrictionProfile) (*RestrictionProfile, error)
+	UpdateTags(ctx context.Context, id int, tagIDs []int) error
+	UpdateStudios(ctx context.Context, id int, studioIDs []int) error
+	Destroy(ctx context.Context, id int) error
+}
+
+type RestrictionProfileReaderWriter interface {
+	RestrictionProfileReader
+	RestrictionProfileWriter
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/utils"
 )
 
 type PHashDuplicationCriterionInput struct {
@@ -31,12 +32,18 @@ type SceneFilterType struct {
 	Path *StringCriterionInput `json:"path"`
 	// Filter by file count
 	FileCount *IntCriterionInput `json:"file_count"`
+	// Filter by scenes without local files
+	LinkedOnly *bool `json:"linked_only"`
+	// Filter by scenes stacked as versions of another scene
+	IsVersion *bool `json:"is_version"`
 	// Filter by rating expressed as 1-5
 	Rating *IntCriterionInput `json:"rating"`
 	// Filter by rating expressed as 1-100
 	Rating100 *IntCriterionInput `json:"rating100"`
 	// Filter by organized
 	Organized *bool `json:"organized"`
+	// Filter by inbox
+	Inbox *bool `json:"inbox"`
 	// Filter by o-counter
 	OCounter *IntCriterionInput `json:"o_counter"`
 	// Filter Scenes that have an exact phash match available
@@ -57,6 +64,9 @@ type SceneFilterType struct {
 	Tags *HierarchicalMultiCriterionInput `json:"tags"`
 	// Filter by tag count
 	TagCount *IntCriterionInput `json:"tag_count"`
+	// Include the primary and secondary tags of the scene markers in the tags
+	// and tag count filters
+	IncludeMarkerTags *bool `json:"include_marker_tags"`
 	// Filter to only include scenes with performers with these tags
 	PerformerTags *HierarchicalMultiCriterionInput `json:"performer_tags"`
 	// Filter scenes that have performers that have been favorited
@@ -67,12 +77,18 @@ type SceneFilterType struct {
 	Performers *MultiCriterionInput `json:"performers"`
 	// Filter by performer count
 	PerformerCount *IntCriterionInput `json:"performer_count"`
+	// Filter to only include scenes with these flags
+	Flags *MultiCriterionInput `json:"flags"`
 	// Filter by StashID
 	StashID *StringCriterionInput `json:"stash_id"`
 	// Filter by StashID Endpoint
 	StashIDEndpoint *StashIDCriterionInput `json:"stash_id_endpoint"`
 	// Filter by url
 	URL *StringCriterionInput `json:"url"`
+	// Filter by location
+	Location *StringCriterionInput `json:"location"`
+	// Filter by distance from coordinates
+	Coordinates *ProximityCriterionInput `json:"coordinates"`
 	// Filter by interactive
 	Interactive *bool `json:"interactive"`
 	// Filter by InteractiveSpeed
@@ -91,6 +107,8 @@ type SceneFilterType struct {
 	CreatedAt *TimestampCriterionInput `json:"created_at"`
 	// Filter by updated at
 	UpdatedAt *TimestampCriterionInput `json:"updated_at"`
+	// Filter by custom field values
+	CustomFields []*CustomFieldCriterionInput `json:"custom_fields"`
 }
 
 type SceneQueryOptions struct {
@@ -152,12 +170,16 @@ type SceneReader interface {
 	FindByPerformerID(ctx context.Context, performerID int) ([]*Scene, error)
 	FindByGalleryID(ctx context.Context, performerID int) ([]*Scene, error)
 	FindDuplicates(ctx context.Context, distance int) ([][]*Scene, error)
+	// FindPrimaryPhashes returns the phash and duration of the primary file
+	// of each scene with a phash.
+	FindPrimaryPhashes(ctx context.Context) ([]*utils.Phash, error)
 
 	GalleryIDLoader
 	PerformerIDLoader
 	TagIDLoader
 	SceneMovieLoader
 	StashIDLoader
+	ExternalIDLoader
 	VideoFileLoader
 
 	CountByPerformerID(ctx context.Context, performerID int) (int, error)
@@ -176,6 +198,9 @@ type SceneReader interface {
 	All(ctx context.Context) ([]*Scene, error)
 	Query(ctx context.Context, options SceneQueryOptions) (*SceneQueryResult, error)
 	GetCover(ctx context.Context, sceneID int) ([]byte, error)
+	GetTrailerFileID(ctx context.Context, sceneID int) (*file.ID, error)
+	CountByTrailerFileID(ctx context.Context, fileID file.ID) (int, error)
+	FindByExternalID(ctx context.Context, source string, externalID string) ([]*Scene, error)
 }
 
 type SceneWriter interface {
@@ -190,6 +215,10 @@ type SceneWriter interface {
 	Destroy(ctx context.Context, id int) error
 	UpdateCover(ctx context.Context, sceneID int, cover []byte) error
 	DestroyCover(ctx context.Context, sceneID int) error
+	// UpdateTrailerFile sets the trailer file of the scene. A nil fileID
+	// removes the trailer.
+	UpdateTrailerFile(ctx context.Context, sceneID int, fileID *file.ID) error
+	ExternalIDUpdater
 }
 
 type SceneReaderWriter interface {
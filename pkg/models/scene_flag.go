@@ -0,0 +1,25 @@
+package models
+
+import "context"
+
+type SceneFlagReader interface {
+	Find(ctx context.Context, id int) (*SceneFlag, error)
+	FindMany(ctx context.Context, ids []int) ([]*SceneFlag, error)
+	FindByName(ctx context.Context, name string) (*SceneFlag, error)
+	FindBySceneID(ctx context.Context, sceneID int) ([]*SceneFlag, error)
+	All(ctx context.Context) ([]*SceneFlag, error)
+	CountScenes(ctx context.Context, id int) (int, error)
+}
+
+type SceneFlagWriter interface {
+	Create(ctx context.Context, newObject SceneFlag) (*SceneFlag, error)
+	Update(ctx context.Context, updatedObject SceneFlag) (*SceneFlag, error)
+	Destroy(ctx context.Context, id int) error
+	AddScenes(ctx context.Context, id int, sceneIDs []int) error
+	RemoveScenes(ctx context.Context, id int, sceneIDs []int) error
+}
+
+type SceneFlagReaderWriter interface {
+	SceneFlagReader
+	SceneFlagWriter
+}
@@ -0,0 +1,27 @@
+package models
+
+import "context"
+
+type SceneVersionReader interface {
+	// FindPrimaryID returns the ID of the primary scene that the scene is
+	// stacked under, or nil if the scene is not a version of another scene.
+	FindPrimaryID(ctx context.Context, sceneID int) (*int, error)
+	// FindVersionIDs returns the IDs of the scenes stacked under the primary
+	// scene, in ID order.
+	FindVersionIDs(ctx context.Context, primaryID int) ([]int, error)
+}
+
+type SceneVersionWriter interface {
+	// Stack stacks the scenes under the primary scene, replacing the
+	// primary scene of scenes which are already stacked.
+	Stack(ctx context.Context, primaryID int, sceneIDs []int) error
+	// Unstack removes the scenes from the stacks they are versions in.
+	Unstack(ctx context.Context, sceneIDs []int) error
+	// DestroyStack removes all of the scenes stacked under the primary scene.
+	DestroyStack(ctx context.Context, primaryID int) error
+}
+
+type SceneVersionReaderWriter interface {
+	SceneVersionReader
+	SceneVersionWriter
+}
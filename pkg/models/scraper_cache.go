@@ -0,0 +1,29 @@
+package models
+
+import (
+	"context"
+	"time"
+)
+
+type ScraperCacheReader interface {
+	// Find returns the entry of the scraper with the key if it was created
+	// after t, or nil if there is none.
+	Find(ctx context.Context, scraperID string, key string, t time.Time) (*ScraperCacheEntry, error)
+}
+
+type ScraperCacheWriter interface {
+	// Set creates the entry, replacing any existing entry of the scraper with
+	// the same key.
+	Set(ctx context.Context, entry ScraperCacheEntry) error
+	// DestroyCreatedBefore destroys the entries created before t, returning
+	// the number of destroyed entries.
+	DestroyCreatedBefore(ctx context.Context, t time.Time) (int, error)
+	// DestroyByScraperID destroys the entries of the scraper, or of all
+	// scrapers if scraperID is nil. Returns the number of destroyed entries.
+	DestroyByScraperID(ctx context.Context, scraperID *string) (int, error)
+}
+
+type ScraperCacheReaderWriter interface {
+	ScraperCacheReader
+	ScraperCacheWriter
+}
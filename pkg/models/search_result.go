@@ -0,0 +1,125 @@
+package models
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+type SearchResultType string
+
+const (
+	SearchResultTypeScene       SearchResultType = "SCENE"
+	SearchResultTypePerformer   SearchResultType = "PERFORMER"
+	SearchResultTypeTag         SearchResultType = "TAG"
+	SearchResultTypeSceneMarker SearchResultType = "SCENE_MARKER"
+)
+
+var AllSearchResultType = []SearchResultType{
+	SearchResultTypeScene,
+	SearchResultTypePerformer,
+	SearchResultTypeTag,
+	SearchResultTypeSceneMarker,
+}
+
+func (e SearchResultType) IsValid() bool {
+	switch e {
+	case SearchResultTypeScene, SearchResultTypePerformer, SearchResultTypeTag, SearchResultTypeSceneMarker:
+		return true
+	}
+	return false
+}
+
+func (e SearchResultType) String() string {
+	return string(e)
+}
+
+func (e *SearchResultType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SearchResultType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SearchResultType", str)
+	}
+	return nil
+}
+
+func (e SearchResultType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type SearchField string
+
+const (
+	// SearchFieldTitle is the title of a scene or marker, or the name of a
+	// performer or tag.
+	SearchFieldTitle SearchField = "TITLE"
+	// SearchFieldAliases are the aliases of a performer or tag.
+	SearchFieldAliases SearchField = "ALIASES"
+	// SearchFieldPath are the paths of the files of a scene.
+	SearchFieldPath SearchField = "PATH"
+	// SearchFieldDetails are the details of a scene or performer, or the
+	// description of a tag.
+	SearchFieldDetails SearchField = "DETAILS"
+)
+
+var AllSearchField = []SearchField{
+	SearchFieldTitle,
+	SearchFieldAliases,
+	SearchFieldPath,
+	SearchFieldDetails,
+}
+
+func (e SearchField) IsValid() bool {
+	switch e {
+	case SearchFieldTitle, SearchFieldAliases, SearchFieldPath, SearchFieldDetails:
+		return true
+	}
+	return false
+}
+
+func (e SearchField) String() string {
+	return string(e)
+}
+
+func (e *SearchField) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SearchField(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SearchField", str)
+	}
+	return nil
+}
+
+func (e SearchField) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+// SearchResult is an object matching a full-text search.
+type SearchResult struct {
+	Type SearchResultType `json:"type"`
+	ID   int              `json:"id"`
+	// Title is the title of a scene or marker, or the name of a performer
+	// or tag
+	Title string `json:"title"`
+	// Score is the relevance of the object to the search. Higher is more
+	// relevant.
+	Score float64 `json:"score"`
+	// MatchedFields are the fields containing the search term, in the order
+	// of their weight.
+	MatchedFields []SearchField `json:"matched_fields"`
+}
+
+type SearchReader interface {
+	// Search returns the objects of the types matching the term, most
+	// relevant first. All types are searched if types is empty.
+	Search(ctx context.Context, term string, types []SearchResultType, limit int) ([]*SearchResult, error)
+}
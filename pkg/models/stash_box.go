@@ -10,4 +10,7 @@ type StashBox struct {
 	Endpoint string `json:"endpoint"`
 	APIKey   string `json:"api_key"`
 	Name     string `json:"name"`
+	// Maximum number of requests made to the endpoint per minute. 0 for no
+	// limit
+	MaxRequestsPerMinute int `json:"max_requests_per_minute"`
 }
@@ -0,0 +1,20 @@
+package models
+
+import "context"
+
+type StashBoxDraftReader interface {
+	// FindBySceneID returns the drafts submitted for the scene, newest first.
+	FindBySceneID(ctx context.Context, sceneID int) ([]*StashBoxDraft, error)
+	// FindByPerformerID returns the drafts submitted for the performer,
+	// newest first.
+	FindByPerformerID(ctx context.Context, performerID int) ([]*StashBoxDraft, error)
+}
+
+type StashBoxDraftWriter interface {
+	Create(ctx context.Context, newObject StashBoxDraft) (*StashBoxDraft, error)
+}
+
+type StashBoxDraftReaderWriter interface {
+	StashBoxDraftReader
+	StashBoxDraftWriter
+}
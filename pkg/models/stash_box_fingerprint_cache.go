@@ -0,0 +1,26 @@
+package models
+
+import (
+	"context"
+	"time"
+)
+
+type StashBoxFingerprintCacheReader interface {
+	// FindMany returns the entries of the endpoint with the keys which were
+	// created after t.
+	FindMany(ctx context.Context, endpoint string, keys []string, t time.Time) ([]*StashBoxFingerprintCacheEntry, error)
+}
+
+type StashBoxFingerprintCacheWriter interface {
+	// Set creates the entry, replacing any existing entry of the endpoint
+	// with the same key.
+	Set(ctx context.Context, entry StashBoxFingerprintCacheEntry) error
+	// DestroyCreatedBefore destroys the entries created before t, returning
+	// the number of destroyed entries.
+	DestroyCreatedBefore(ctx context.Context, t time.Time) (int, error)
+}
+
+type StashBoxFingerprintCacheReaderWriter interface {
+	StashBoxFingerprintCacheReader
+	StashBoxFingerprintCacheWriter
+}
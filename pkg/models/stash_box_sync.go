@@ -0,0 +1,40 @@
+package models
+
+import (
+	"context"
+	"time"
+)
+
+type StashBoxSyncReader interface {
+	// FindScenesToSubmit returns the IDs of the scenes with a stash ID for
+	// the endpoint whose fingerprints have not been submitted since the
+	// scene was last updated.
+	FindScenesToSubmit(ctx context.Context, endpoint string) ([]int, error)
+	// FindScenesToMatch returns the IDs of the scenes without a stash ID for
+	// the endpoint or a pending suggestion, which have not been checked for
+	// matches since checkedBefore.
+	FindScenesToMatch(ctx context.Context, endpoint string, checkedBefore time.Time) ([]int, error)
+
+	FindSuggestion(ctx context.Context, id int) (*StashBoxMatchSuggestion, error)
+	// QuerySuggestions returns the pending suggestions, optionally of a
+	// single scene, newest first, along with the total number of suggestions.
+	QuerySuggestions(ctx context.Context, sceneID *int, findFilter *FindFilterType) ([]*StashBoxMatchSuggestion, int, error)
+}
+
+type StashBoxSyncWriter interface {
+	SetFingerprintsSubmitted(ctx context.Context, sceneIDs []int, endpoint string, t time.Time) error
+	SetMatchesChecked(ctx context.Context, sceneIDs []int, endpoint string, t time.Time) error
+
+	// CreateSuggestion adds a pending suggestion. Suggestions that already
+	// exist, including rejected suggestions, are not added.
+	CreateSuggestion(ctx context.Context, newObject StashBoxMatchSuggestion) error
+	// DestroySuggestions removes the pending suggestions of the scene for the
+	// endpoint.
+	DestroySuggestions(ctx context.Context, sceneID int, endpoint string) error
+	RejectSuggestion(ctx context.Context, id int) error
+}
+
+type StashBoxSyncReaderWriter interface {
+	StashBoxSyncReader
+	StashBoxSyncWriter
+}
@@ -32,10 +32,14 @@ type StudioFilterType struct {
 	Aliases *StringCriterionInput `json:"aliases"`
 	// Filter by autotag ignore value
 	IgnoreAutoTag *bool `json:"ignore_auto_tag"`
+	// Filter by favorite
+	Favorite *bool `json:"favorite"`
 	// Filter by created at
 	CreatedAt *TimestampCriterionInput `json:"created_at"`
 	// Filter by updated at
 	UpdatedAt *TimestampCriterionInput `json:"updated_at"`
+	// Filter by custom field values
+	CustomFields []*CustomFieldCriterionInput `json:"custom_fields"`
 }
 
 type StudioFinder interface {
@@ -48,6 +52,7 @@ type StudioReader interface {
 	FindChildren(ctx context.Context, id int) ([]*Studio, error)
 	FindByName(ctx context.Context, name string, nocase bool) (*Studio, error)
 	FindByStashID(ctx context.Context, stashID StashID) ([]*Studio, error)
+	FindByExternalID(ctx context.Context, source string, externalID string) ([]*Studio, error)
 	Count(ctx context.Context) (int, error)
 	All(ctx context.Context) ([]*Studio, error)
 	// TODO - this interface is temporary until the filter schema can fully
@@ -57,6 +62,7 @@ type StudioReader interface {
 	GetImage(ctx context.Context, studioID int) ([]byte, error)
 	HasImage(ctx context.Context, studioID int) (bool, error)
 	StashIDLoader
+	ExternalIDLoader
 	GetAliases(ctx context.Context, studioID int) ([]string, error)
 }
 
@@ -69,6 +75,7 @@ type StudioWriter interface {
 	DestroyImage(ctx context.Context, studioID int) error
 	UpdateStashIDs(ctx context.Context, studioID int, stashIDs []StashID) error
 	UpdateAliases(ctx context.Context, studioID int, aliases []string) error
+	ExternalIDUpdater
 }
 
 type StudioReaderWriter interface {
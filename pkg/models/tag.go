@@ -34,6 +34,10 @@ type TagFilterType struct {
 	ChildCount *IntCriterionInput `json:"child_count"`
 	// Filter by autotag ignore value
 	IgnoreAutoTag *bool `json:"ignore_auto_tag"`
+	// Filter by content warning value
+	ContentWarning *bool `json:"content_warning"`
+	// Filter by favorite
+	Favorite *bool `json:"favorite"`
 	// Filter by created at
 	CreatedAt *TimestampCriterionInput `json:"created_at"`
 	// Filter by updated at
@@ -48,6 +52,7 @@ type TagReader interface {
 	Find(ctx context.Context, id int) (*Tag, error)
 	TagFinder
 	FindBySceneID(ctx context.Context, sceneID int) ([]*Tag, error)
+	FindInheritedBySceneID(ctx context.Context, sceneID int, depth int) ([]*Tag, error)
 	FindByPerformerID(ctx context.Context, performerID int) ([]*Tag, error)
 	FindBySceneMarkerID(ctx context.Context, sceneMarkerID int) ([]*Tag, error)
 	FindByImageID(ctx context.Context, imageID int) ([]*Tag, error)
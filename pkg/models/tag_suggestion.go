@@ -0,0 +1,25 @@
+package models
+
+import "context"
+
+type TagSuggestionReader interface {
+	// Query returns the pending suggestions, optionally of a single scene,
+	// highest confidence first, along with the total number of suggestions.
+	Query(ctx context.Context, sceneID *int, findFilter *FindFilterType) ([]*TagSuggestion, int, error)
+	// FindSceneRelationshipIDs returns the studio, performer and tag IDs of
+	// all scenes.
+	FindSceneRelationshipIDs(ctx context.Context) ([]*SceneRelationshipIDs, error)
+}
+
+type TagSuggestionWriter interface {
+	// Replace replaces the pending suggestions with the provided suggestions.
+	// Suggestions that were previously rejected are not added.
+	Replace(ctx context.Context, suggestions []TagSuggestion) error
+	Destroy(ctx context.Context, sceneID int, tagID int) error
+	Reject(ctx context.Context, sceneID int, tagID int) error
+}
+
+type TagSuggestionReaderWriter interface {
+	TagSuggestionReader
+	TagSuggestionWriter
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"context"
+	"time"
+)
+
+type TrashedSceneReader interface {
+	Find(ctx context.Context, id int) (*TrashedScene, error)
+	// All returns all trashed scenes, most recently deleted first.
+	All(ctx context.Context) ([]*TrashedScene, error)
+	// FindCreatedBefore returns the scenes deleted before t.
+	FindCreatedBefore(ctx context.Context, t time.Time) ([]*TrashedScene, error)
+}
+
+type TrashedSceneWriter interface {
+	Create(ctx context.Context, newObject TrashedScene) (*TrashedScene, error)
+	Destroy(ctx context.Context, id int) error
+}
+
+type TrashedSceneReaderWriter interface {
+	TrashedSceneReader
+	TrashedSceneWriter
+}
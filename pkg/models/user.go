@@ -0,0 +1,30 @@
+package models
+
+import "context"
+
+type UserReader interface {
+	Find(ctx context.Context, id int) (*User, error)
+	// FindByUsername returns the user with the username, ignoring case.
+	FindByUsername(ctx context.Context, username string) (*User, error)
+	FindByAPIKeyHash(ctx context.Context, hash string) (*User, error)
+	All(ctx context.Context) ([]*User, error)
+	// FindByExternalIdentity returns the user linked to the subject of the
+	// external authentication provider.
+	FindByExternalIdentity(ctx context.Context, provider, subject string) (*User, error)
+	GetExternalIdentities(ctx context.Context, userID int) ([]UserExternalIdentity, error)
+}
+
+type UserWriter interface {
+	Create(ctx context.Context, newObject User) (*User, error)
+	Update(ctx context.Context, updatedObject User) (*User, error)
+	Destroy(ctx context.Context, id int) error
+	// LinkExternalIdentity links the user to the identity, replacing any
+	// identity of the user from the same provider.
+	LinkExternalIdentity(ctx context.Context, userID int, identity UserExternalIdentity) error
+	UnlinkExternalIdentity(ctx context.Context, userID int, provider string) error
+}
+
+type UserReaderWriter interface {
+	UserReader
+	UserWriter
+}
@@ -0,0 +1,24 @@
+package models
+
+import "context"
+
+type WantedSceneReader interface {
+	All(ctx context.Context) ([]*WantedScene, error)
+	Find(ctx context.Context, id int) (*WantedScene, error)
+	FindUnfulfilled(ctx context.Context) ([]*WantedScene, error)
+	FindByURL(ctx context.Context, url string) ([]*WantedScene, error)
+	FindByScrapeStatus(ctx context.Context, status WantedSceneScrapeStatus) ([]*WantedScene, error)
+	GetCover(ctx context.Context, wantedSceneID int) ([]byte, error)
+}
+
+type WantedSceneWriter interface {
+	Create(ctx context.Context, obj WantedScene) (*WantedScene, error)
+	Update(ctx context.Context, obj WantedScene) (*WantedScene, error)
+	Destroy(ctx context.Context, id int) error
+	UpdateCover(ctx context.Context, wantedSceneID int, cover []byte) error
+}
+
+type WantedSceneReaderWriter interface {
+	WantedSceneReader
+	WantedSceneWriter
+}
@@ -0,0 +1,137 @@
+package performer
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+// DefaultDuplicateMinSimilarity is the default minimum similarity of the names
+// of duplicate performers.
+const DefaultDuplicateMinSimilarity = 0.9
+
+// DuplicateCandidate is a performer with its aliases and stash ids, checked
+// for duplicates.
+type DuplicateCandidate struct {
+	Performer *models.Performer
+	Aliases   []string
+	StashIDs  []models.StashID
+}
+
+func normaliseName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// conflicts returns true if the performers have different birthdates or
+// disambiguations, which means they are not duplicates despite matching
+// names.
+func (c DuplicateCandidate) conflicts(other DuplicateCandidate) bool {
+	a, b := c.Performer, other.Performer
+	if a.Birthdate != nil && b.Birthdate != nil && a.Birthdate.String() != b.Birthdate.String() {
+		return true
+	}
+
+	da, db := normaliseName(a.Disambiguation), normaliseName(b.Disambiguation)
+	return da != "" && db != "" && da != db
+}
+
+// FindDuplicates groups the performers that share a stash id, or whose names
+// or aliases match. Names and aliases match if they are equal, ignoring case,
+// or if the similarity of the names is at least minSimilarity. Names are only
+// compared by similarity if minSimilarity is less than 1. Performers with
+// matching names are not duplicates if their birthdates or disambiguations
+// differ.
+//
+// Each group contains at least two performers, ordered by id. Groups are
+// ordered by the id of their first performer.
+func FindDuplicates(candidates []DuplicateCandidate, minSimilarity float64) [][]*models.Performer {
+	parent := make([]int, len(candidates))
+	for i := range parent {
+		parent[i] = i
+	}
+
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	union := func(i, j int) {
+		pi, pj := find(i), find(j)
+		if pi != pj {
+			parent[pj] = pi
+		}
+	}
+
+	byStashID := make(map[models.StashID]int)
+	byName := make(map[string][]int)
+	names := make([]string, len(candidates))
+	for i, c := range candidates {
+		for _, s := range c.StashIDs {
+			if other, found := byStashID[s]; found {
+				union(other, i)
+			} else {
+				byStashID[s] = i
+			}
+		}
+
+		names[i] = normaliseName(c.Performer.Name)
+
+		seen := make(map[string]bool)
+		for _, n := range append([]string{c.Performer.Name}, c.Aliases...) {
+			n = normaliseName(n)
+			if n == "" || seen[n] {
+				continue
+			}
+			seen[n] = true
+
+			for _, other := range byName[n] {
+				if !c.conflicts(candidates[other]) {
+					union(other, i)
+				}
+			}
+			byName[n] = append(byName[n], i)
+		}
+	}
+
+	if minSimilarity < 1 {
+		for i := range candidates {
+			for j := i + 1; j < len(candidates); j++ {
+				if find(i) == find(j) || candidates[i].conflicts(candidates[j]) {
+					continue
+				}
+
+				if utils.StrSimilarity(names[i], names[j], minSimilarity) >= minSimilarity {
+					union(i, j)
+				}
+			}
+		}
+	}
+
+	groups := make(map[int][]*models.Performer)
+	for i, c := range candidates {
+		root := find(i)
+		groups[root] = append(groups[root], c.Performer)
+	}
+
+	var ret [][]*models.Performer
+	for _, g := range groups {
+		if len(g) < 2 {
+			continue
+		}
+
+		sort.Slice(g, func(i, j int) bool {
+			return g[i].ID < g[j].ID
+		})
+		ret = append(ret, g)
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i][0].ID < ret[j][0].ID
+	})
+
+	return ret
+}
@@ -0,0 +1,68 @@
+package performer
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindDuplicates(t *testing.T) {
+	date1 := models.NewDate("1990-01-01")
+	date2 := models.NewDate("1991-01-01")
+
+	performers := []*models.Performer{
+		{ID: 1, Name: "Jane Doe"},
+		{ID: 2, Name: "jane doe "},
+		{ID: 3, Name: "Janie"},
+		{ID: 4, Name: "Anna Smith", Birthdate: &date1},
+		{ID: 5, Name: "Anna Smith", Birthdate: &date2},
+		{ID: 6, Name: "Anna Smyth", Birthdate: &date1},
+		{ID: 7, Name: "Mary", Disambiguation: "a"},
+		{ID: 8, Name: "Mary", Disambiguation: "b"},
+		{ID: 9, Name: "Someone"},
+		{ID: 10, Name: "Somebody Else"},
+	}
+	aliases := map[int][]string{
+		3: {"JANE DOE"},
+	}
+	stashIDs := map[int][]models.StashID{
+		9:  {{Endpoint: "e", StashID: "1"}},
+		10: {{Endpoint: "e", StashID: "1"}},
+	}
+
+	candidates := func() []DuplicateCandidate {
+		ret := make([]DuplicateCandidate, len(performers))
+		for i, p := range performers {
+			ret[i] = DuplicateCandidate{Performer: p, Aliases: aliases[p.ID], StashIDs: stashIDs[p.ID]}
+		}
+		return ret
+	}
+
+	ids := func(groups [][]*models.Performer) [][]int {
+		var ret [][]int
+		for _, g := range groups {
+			var group []int
+			for _, p := range g {
+				group = append(group, p.ID)
+			}
+			ret = append(ret, group)
+		}
+		return ret
+	}
+
+	tests := []struct {
+		name          string
+		minSimilarity float64
+		want          [][]int
+	}{
+		{"exact", 1, [][]int{{1, 2, 3}, {9, 10}}},
+		{"similar", 0.85, [][]int{{1, 2, 3}, {4, 6}, {9, 10}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ids(FindDuplicates(candidates(), tt.minSimilarity)))
+		})
+	}
+}
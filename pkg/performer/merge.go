@@ -0,0 +1,195 @@
+package performer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sliceutil/intslice"
+)
+
+// MergeResult is the state of the performers before a merge.
+type MergeResult struct {
+	Destination *Snapshot   `json:"destination"`
+	Sources     []*Snapshot `json:"sources"`
+}
+
+// Merge merges the source performers into the destination performer. The
+// scenes, images and galleries of the source performers are moved to the
+// destination, and the source performers are destroyed.
+//
+// The names and aliases of the source performers become aliases of the
+// destination, and their tags, stash ids and external ids are added to the
+// destination. Fields of the destination that are not set are set from the
+// first source performer with a value.
+func Merge(ctx context.Context, r models.PerformerReaderWriter, sources []int, destination int) (*MergeResult, error) {
+	dest, err := r.Find(ctx, destination)
+	if err != nil {
+		return nil, err
+	}
+	if dest == nil {
+		return nil, fmt.Errorf("%w: performer with id %d", models.ErrNotFound, destination)
+	}
+
+	ret := &MergeResult{}
+	if ret.Destination, err = TakeSnapshot(ctx, r, dest); err != nil {
+		return nil, err
+	}
+
+	partial := models.NewPerformerPartial()
+	aliases := dest.Aliases.List()
+	tagIDs := dest.TagIDs.List()
+	stashIDs := dest.StashIDs.List()
+	externalIDs := ret.Destination.ExternalIDs
+
+	for _, id := range sources {
+		if id == destination {
+			return nil, errors.New("cannot merge a performer into itself")
+		}
+
+		src, err := r.Find(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if src == nil {
+			return nil, fmt.Errorf("%w: performer with id %d", models.ErrNotFound, id)
+		}
+
+		snapshot, err := TakeSnapshot(ctx, r, src)
+		if err != nil {
+			return nil, err
+		}
+		ret.Sources = append(ret.Sources, snapshot)
+
+		mergeFields(&partial, dest, src)
+
+		aliases = append(aliases, src.Name)
+		aliases = append(aliases, snapshot.Aliases...)
+		tagIDs = intslice.IntAppendUniques(tagIDs, snapshot.TagIDs)
+		stashIDs = appendStashIDs(stashIDs, snapshot.StashIDs)
+		externalIDs = appendExternalIDs(externalIDs, snapshot.ExternalIDs)
+	}
+
+	partial.Aliases = &models.UpdateStrings{
+		Values: mergeAliases(dest.Name, aliases),
+		Mode:   models.RelationshipUpdateModeSet,
+	}
+	partial.TagIDs = &models.UpdateIDs{
+		IDs:  tagIDs,
+		Mode: models.RelationshipUpdateModeSet,
+	}
+	partial.StashIDs = &models.UpdateStashIDs{
+		StashIDs: stashIDs,
+		Mode:     models.RelationshipUpdateModeSet,
+	}
+
+	// set the image from the first source with an image
+	if len(ret.Destination.Image) == 0 {
+		for _, s := range ret.Sources {
+			if len(s.Image) > 0 {
+				if err := r.UpdateImage(ctx, destination, s.Image); err != nil {
+					return nil, err
+				}
+				break
+			}
+		}
+	}
+
+	if err := r.Merge(ctx, sources, destination); err != nil {
+		return nil, err
+	}
+
+	if _, err := r.UpdatePartial(ctx, destination, partial); err != nil {
+		return nil, err
+	}
+
+	if err := r.UpdateExternalIDs(ctx, destination, externalIDs); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// mergeFields sets the fields of the partial that are not set on the
+// destination from the source.
+func mergeFields(partial *models.PerformerPartial, dest *models.Performer, src *models.Performer) {
+	partial.Disambiguation.Merge(dest.Disambiguation, src.Disambiguation)
+	partial.Gender.Merge(dest.Gender.String(), src.Gender.String())
+	partial.URL.Merge(dest.URL, src.URL)
+	partial.Twitter.Merge(dest.Twitter, src.Twitter)
+	partial.Instagram.Merge(dest.Instagram, src.Instagram)
+	partial.Birthdate.MergePtr(dest.Birthdate, src.Birthdate)
+	partial.Ethnicity.Merge(dest.Ethnicity, src.Ethnicity)
+	partial.Country.Merge(dest.Country, src.Country)
+	partial.EyeColor.Merge(dest.EyeColor, src.EyeColor)
+	partial.Height.MergePtr(dest.Height, src.Height)
+	partial.Measurements.Merge(dest.Measurements, src.Measurements)
+	partial.FakeTits.Merge(dest.FakeTits, src.FakeTits)
+	partial.CareerLength.Merge(dest.CareerLength, src.CareerLength)
+	partial.Tattoos.Merge(dest.Tattoos, src.Tattoos)
+	partial.Piercings.Merge(dest.Piercings, src.Piercings)
+	partial.Favorite.Merge(dest.Favorite, src.Favorite)
+	partial.Rating.MergePtr(dest.Rating, src.Rating)
+	partial.Details.Merge(dest.Details, src.Details)
+	partial.DeathDate.MergePtr(dest.DeathDate, src.DeathDate)
+	partial.HairColor.Merge(dest.HairColor, src.HairColor)
+	partial.Weight.MergePtr(dest.Weight, src.Weight)
+}
+
+// mergeAliases returns the aliases with duplicates and the name of the
+// performer removed, ignoring case.
+func mergeAliases(name string, aliases []string) []string {
+	seen := map[string]bool{
+		normaliseName(name): true,
+	}
+
+	ret := []string{}
+	for _, a := range aliases {
+		n := normaliseName(a)
+		if n == "" || seen[n] {
+			continue
+		}
+
+		seen[n] = true
+		ret = append(ret, a)
+	}
+
+	return ret
+}
+
+func appendStashIDs(existing []models.StashID, toAdd []models.StashID) []models.StashID {
+	for _, s := range toAdd {
+		found := false
+		for _, e := range existing {
+			if e.Endpoint == s.Endpoint && e.StashID == s.StashID {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			existing = append(existing, s)
+		}
+	}
+
+	return existing
+}
+
+func appendExternalIDs(existing []models.ExternalID, toAdd []models.ExternalID) []models.ExternalID {
+	for _, s := range toAdd {
+		found := false
+		for _, e := range existing {
+			if e == s {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			existing = append(existing, s)
+		}
+	}
+
+	return existing
+}
@@ -0,0 +1,47 @@
+package performer
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeAliases(t *testing.T) {
+	assert.Equal(t, []string{"b", "C"}, mergeAliases("A", []string{"a", "b", "C", "B", "c", ""}))
+	assert.Equal(t, []string{}, mergeAliases("A", nil))
+}
+
+func TestMergeFields(t *testing.T) {
+	height := 170
+	dest := &models.Performer{
+		Name:    "dest",
+		Country: "AU",
+	}
+	src := &models.Performer{
+		Name:    "src",
+		Country: "US",
+		Details: "details",
+		Height:  &height,
+	}
+
+	partial := models.NewPerformerPartial()
+	mergeFields(&partial, dest, src)
+
+	assert.False(t, partial.Country.Set)
+	assert.Equal(t, models.NewOptionalString("details"), partial.Details)
+	assert.Equal(t, models.NewOptionalInt(170), partial.Height)
+}
+
+func TestAppendStashIDs(t *testing.T) {
+	existing := []models.StashID{{Endpoint: "a", StashID: "1"}}
+	got := appendStashIDs(existing, []models.StashID{
+		{Endpoint: "a", StashID: "1"},
+		{Endpoint: "b", StashID: "1"},
+	})
+
+	assert.Equal(t, []models.StashID{
+		{Endpoint: "a", StashID: "1"},
+		{Endpoint: "b", StashID: "1"},
+	}, got)
+}
@@ -0,0 +1,109 @@
+package performer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// Snapshot is the state of a performer, including its relationships, image,
+// and the scenes, images and galleries it is linked to.
+type Snapshot struct {
+	Performer   models.Performer    `json:"performer"`
+	Aliases     []string            `json:"aliases"`
+	TagIDs      []int               `json:"tag_ids"`
+	StashIDs    []models.StashID    `json:"stash_ids"`
+	ExternalIDs []models.ExternalID `json:"external_ids"`
+	Image       []byte              `json:"image,omitempty"`
+	SceneIDs    []int               `json:"scene_ids"`
+	ImageIDs    []int               `json:"image_ids"`
+	GalleryIDs  []int               `json:"gallery_ids"`
+}
+
+type SnapshotWriter interface {
+	Create(ctx context.Context, newPerformer *models.Performer) error
+	Update(ctx context.Context, updatedPerformer *models.Performer) error
+	UpdateImage(ctx context.Context, performerID int, image []byte) error
+	DestroyImage(ctx context.Context, performerID int) error
+	models.ExternalIDUpdater
+}
+
+// TakeSnapshot returns a snapshot of the performer.
+func TakeSnapshot(ctx context.Context, r models.PerformerReader, p *models.Performer) (*Snapshot, error) {
+	if err := p.LoadRelationships(ctx, r); err != nil {
+		return nil, fmt.Errorf("loading performer relationships: %w", err)
+	}
+
+	ret := &Snapshot{
+		Performer: *p,
+		Aliases:   p.Aliases.List(),
+		TagIDs:    p.TagIDs.List(),
+		StashIDs:  p.StashIDs.List(),
+	}
+
+	var err error
+	if ret.ExternalIDs, err = r.GetExternalIDs(ctx, p.ID); err != nil {
+		return nil, fmt.Errorf("getting performer external ids: %w", err)
+	}
+	if ret.Image, err = r.GetImage(ctx, p.ID); err != nil {
+		return nil, fmt.Errorf("getting performer image: %w", err)
+	}
+	if ret.SceneIDs, err = r.GetSceneIDs(ctx, p.ID); err != nil {
+		return nil, fmt.Errorf("getting performer scenes: %w", err)
+	}
+	if ret.ImageIDs, err = r.GetImageIDs(ctx, p.ID); err != nil {
+		return nil, fmt.Errorf("getting performer images: %w", err)
+	}
+	if ret.GalleryIDs, err = r.GetGalleryIDs(ctx, p.ID); err != nil {
+		return nil, fmt.Errorf("getting performer galleries: %w", err)
+	}
+
+	return ret, nil
+}
+
+func (s *Snapshot) performer() models.Performer {
+	ret := s.Performer
+	ret.Aliases = models.NewRelatedStrings(s.Aliases)
+	ret.TagIDs = models.NewRelatedIDs(s.TagIDs)
+	ret.StashIDs = models.NewRelatedStashIDs(s.StashIDs)
+	return ret
+}
+
+func (s *Snapshot) writeDetails(ctx context.Context, w SnapshotWriter, id int) error {
+	if err := w.UpdateExternalIDs(ctx, id, s.ExternalIDs); err != nil {
+		return err
+	}
+
+	if len(s.Image) > 0 {
+		return w.UpdateImage(ctx, id, s.Image)
+	}
+	return w.DestroyImage(ctx, id)
+}
+
+// Revert reverts the existing performer to the state of the snapshot. The
+// scenes, images and galleries of the performer are not changed.
+func (s *Snapshot) Revert(ctx context.Context, w SnapshotWriter) error {
+	p := s.performer()
+	if err := w.Update(ctx, &p); err != nil {
+		return err
+	}
+
+	return s.writeDetails(ctx, w, p.ID)
+}
+
+// Restore creates a new performer from the snapshot. The scenes, images and
+// galleries of the performer are not linked.
+func (s *Snapshot) Restore(ctx context.Context, w SnapshotWriter) (*models.Performer, error) {
+	p := s.performer()
+	p.ID = 0
+	if err := w.Create(ctx, &p); err != nil {
+		return nil, err
+	}
+
+	if err := s.writeDetails(ctx, w, p.ID); err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
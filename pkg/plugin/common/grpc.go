@@ -0,0 +1,69 @@
+package common
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Environment variables set for plugins using the grpc interface.
+const (
+	// GRPCMagicCookieKey is set to GRPCMagicCookieValue, so that plugins can
+	// detect that they were started by stash.
+	GRPCMagicCookieKey   = "STASH_PLUGIN_MAGIC_COOKIE"
+	GRPCMagicCookieValue = "b3d6c0cf7e8a4b1b9b5e1f2a6c4d8e07"
+
+	// GRPCProtocolVersionsKey is set to the comma separated protocol versions
+	// supported by stash.
+	GRPCProtocolVersionsKey = "STASH_PLUGIN_PROTOCOL_VERSIONS"
+
+	// GRPCHostAddrKey is set to the address of the Host service.
+	GRPCHostAddrKey = "STASH_PLUGIN_HOST_ADDR"
+)
+
+// GRPCProtocolVersion is the current version of the grpc plugin protocol.
+const GRPCProtocolVersion = 1
+
+// GRPCHandshake is the line written to stdout by grpc plugins once they are
+// serving the Plugin service.
+type GRPCHandshake struct {
+	ProtocolVersion int
+	// Network is tcp or unix
+	Network string
+	Address string
+}
+
+// String returns the handshake line, in the form
+// <protocol version>|<network>|<address>
+func (h GRPCHandshake) String() string {
+	return fmt.Sprintf("%d|%s|%s", h.ProtocolVersion, h.Network, h.Address)
+}
+
+// ParseGRPCHandshake parses the handshake line written by a grpc plugin,
+// returning an error if the protocol version is not supported.
+func ParseGRPCHandshake(line string) (*GRPCHandshake, error) {
+	parts := strings.Split(strings.TrimSpace(line), "|")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("invalid handshake %q", line)
+	}
+
+	version, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid protocol version in handshake %q", line)
+	}
+
+	if version != GRPCProtocolVersion {
+		return nil, fmt.Errorf("unsupported protocol version %d, expected %d", version, GRPCProtocolVersion)
+	}
+
+	network := parts[1]
+	if network != "tcp" && network != "unix" {
+		return nil, fmt.Errorf("unsupported network %q in handshake", network)
+	}
+
+	return &GRPCHandshake{
+		ProtocolVersion: version,
+		Network:         network,
+		Address:         parts[2],
+	}, nil
+}
@@ -17,6 +17,10 @@ type StashServerConnection struct {
 	// Cookie for authentication purposes
 	SessionCookie *http.Cookie
 
+	// APIKey authenticates requests when sent in the ApiKey header. It has
+	// the same value and permissions as SessionCookie.
+	APIKey string
+
 	// Dir specifies the directory containing the stash server's configuration
 	// file.
 	Dir string
@@ -102,6 +106,17 @@ func (o *PluginOutput) SetError(err error) {
 	o.Error = &errStr
 }
 
+// PreHookOutput is the output expected from pre hooks, such as
+// Scene.Update.Pre. Pre hooks are passed the input of the mutation in the
+// hook context.
+type PreHookOutput struct {
+	// Input replaces the input of the mutation if set. Fields missing from
+	// the input are not changed by update mutations.
+	Input map[string]interface{} `json:"input,omitempty"`
+	// Veto rejects the mutation with the reason if set.
+	Veto *string `json:"veto,omitempty"`
+}
+
 // HookContext is passed as a PluginArgValue and indicates what hook triggered
 // this plugin task.
 type HookContext struct {
@@ -9,6 +9,8 @@ import (
 	"strings"
 
 	"gopkg.in/yaml.v2"
+
+	"github.com/stashapp/stash/pkg/session"
 )
 
 // Config describes the configuration for a single plugin.
@@ -59,6 +61,10 @@ type Config struct {
 
 	// Javascript files that will be injected into the stash UI.
 	UI UIConfig `yaml:"ui"`
+
+	// The permissions of the token issued to the plugin when it is run.
+	// Plugins can always run queries. Valid values are write and admin.
+	Permissions []session.PluginPermission `yaml:"permissions"`
 }
 
 type UIConfig struct {
@@ -150,6 +156,7 @@ func (c Config) toPlugin() *Plugin {
 		Version:     c.Version,
 		Tasks:       c.getPluginTasks(false),
 		Hooks:       c.getPluginHooks(false),
+		Permissions: c.getPermissions(),
 		UI: PluginUI{
 			Javascript: c.UI.getJavascriptFiles(c),
 			CSS:        c.UI.getCSSFiles(c),
@@ -157,6 +164,14 @@ func (c Config) toPlugin() *Plugin {
 	}
 }
 
+func (c Config) getPermissions() []string {
+	ret := make([]string, len(c.Permissions))
+	for i, p := range c.Permissions {
+		ret[i] = string(p)
+	}
+	return ret
+}
+
 func (c Config) getTask(name string) *OperationConfig {
 	for _, o := range c.Tasks {
 		if o.Name == name {
@@ -226,10 +241,14 @@ const (
 	InterfaceEnumRaw interfaceEnum = "raw"
 
 	InterfaceEnumJS interfaceEnum = "js"
+
+	// InterfaceEnumGRPC indicates that the plugin serves the Plugin service
+	// declared in proto/plugin.proto. Go plugins should use the sdk package.
+	InterfaceEnumGRPC interfaceEnum = "grpc"
 )
 
 func (i interfaceEnum) Valid() bool {
-	return i == InterfaceEnumRPC || i == InterfaceEnumRaw || i == InterfaceEnumJS
+	return i == InterfaceEnumRPC || i == InterfaceEnumRaw || i == InterfaceEnumJS || i == InterfaceEnumGRPC
 }
 
 func (i *interfaceEnum) getTaskBuilder() taskBuilder {
@@ -245,6 +264,10 @@ func (i *interfaceEnum) getTaskBuilder() taskBuilder {
 		return &jsTaskBuilder{}
 	}
 
+	if *i == InterfaceEnumGRPC {
+		return &grpcTaskBuilder{}
+	}
+
 	// shouldn't happen
 	return nil
 }
@@ -296,6 +319,12 @@ func loadPluginFromYAML(reader io.Reader) (*Config, error) {
 		return nil, fmt.Errorf("invalid interface type %s", ret.Interface)
 	}
 
+	for _, p := range ret.Permissions {
+		if !p.IsValid() {
+			return nil, fmt.Errorf("invalid permission %s", p)
+		}
+	}
+
 	return ret, nil
 }
 
@@ -0,0 +1,75 @@
+//go:build plugin_example
+// +build plugin_example
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	exampleCommon "github.com/stashapp/stash/pkg/plugin/examples/common"
+
+	"github.com/stashapp/stash/pkg/plugin/sdk"
+)
+
+func main() {
+	// serves the plugin, providing an object that satisfies the
+	// sdk.Plugin interface
+	if err := sdk.Serve(&api{}); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+type api struct{}
+
+// Run is the main work function of the plugin. It interprets the input and
+// acts accordingly.
+func (a *api) Run(ctx context.Context, req *sdk.Request) (interface{}, error) {
+	var err error
+	switch req.Args.String("mode") {
+	case "", "add":
+		err = exampleCommon.AddTag(ctx, req.Client)
+	case "remove":
+		err = exampleCommon.RemoveTag(ctx, req.Client)
+	case "long":
+		err = a.doLongTask(ctx, req)
+	case "indef":
+		err = a.doIndefiniteTask(ctx, req)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return "ok", nil
+}
+
+func (a *api) doLongTask(ctx context.Context, req *sdk.Request) error {
+	const total = 100
+
+	req.Infof("Doing long task")
+	for upTo := 0; upTo < total; upTo++ {
+		select {
+		case <-ctx.Done():
+			req.Infof("Stopping...")
+			return nil
+		case <-time.After(time.Second):
+		}
+
+		if err := req.Progress(float64(upTo) / float64(total)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (a *api) doIndefiniteTask(ctx context.Context, req *sdk.Request) error {
+	req.Warnf("Sleeping indefinitely")
+	<-ctx.Done()
+	req.Infof("Stopping...")
+	return nil
+}
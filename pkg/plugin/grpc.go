@@ -0,0 +1,343 @@
+package plugin
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"strconv"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	stashExec "github.com/stashapp/stash/pkg/exec"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/plugin/common"
+	"github.com/stashapp/stash/pkg/plugin/proto"
+)
+
+const (
+	// grpcHandshakeTimeout is how long to wait for the plugin to write the
+	// handshake line.
+	grpcHandshakeTimeout = 30 * time.Second
+
+	// grpcExitTimeout is how long to wait for the plugin process to exit after
+	// the operation is complete, before killing it.
+	grpcExitTimeout = 5 * time.Second
+
+	grpcStopTimeout = 10 * time.Second
+)
+
+type grpcTaskBuilder struct{}
+
+func (*grpcTaskBuilder) build(task pluginTask) Task {
+	return &grpcPluginTask{
+		pluginTask: task,
+	}
+}
+
+type grpcPluginTask struct {
+	pluginTask
+
+	started   bool
+	waitGroup sync.WaitGroup
+	cmd       *exec.Cmd
+	conn      *grpc.ClientConn
+	client    proto.PluginClient
+
+	hostServer *grpc.Server
+}
+
+func (t *grpcPluginTask) Start() error {
+	if t.started {
+		return errors.New("task already started")
+	}
+
+	command := t.plugin.getExecCommand(t.operation)
+	if len(command) == 0 {
+		return fmt.Errorf("empty exec value in operation %s", t.operation.Name)
+	}
+
+	hostAddr, err := t.serveHost()
+	if err != nil {
+		return fmt.Errorf("error serving plugin host: %w", err)
+	}
+
+	cmd := stashExec.Command(command[0], command[1:]...)
+	cmd.Env = append(os.Environ(),
+		common.GRPCMagicCookieKey+"="+common.GRPCMagicCookieValue,
+		common.GRPCProtocolVersionsKey+"="+strconv.Itoa(common.GRPCProtocolVersion),
+		common.GRPCHostAddrKey+"="+hostAddr,
+	)
+
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		t.hostServer.Stop()
+		return fmt.Errorf("plugin stderr not available: %w", err)
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.hostServer.Stop()
+		return fmt.Errorf("plugin stdout not available: %w", err)
+	}
+
+	if err := cmd.Start(); err != nil {
+		t.hostServer.Stop()
+		return fmt.Errorf("error running plugin: %w", err)
+	}
+
+	t.cmd = cmd
+	go t.handlePluginStderr(t.plugin.Name, stderr)
+
+	stream, err := t.connect(stdout)
+	if err != nil {
+		t.stopProcess()
+		return err
+	}
+
+	t.waitGroup.Add(1)
+	go func() {
+		defer t.waitGroup.Done()
+		defer t.stopProcess()
+
+		t.result = t.receive(stream)
+	}()
+
+	t.started = true
+	return nil
+}
+
+// serveHost serves the Host service for the plugin, returning its address.
+func (t *grpcPluginTask) serveHost() (string, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+
+	t.hostServer = grpc.NewServer()
+	proto.RegisterHostServer(t.hostServer, &grpcHost{
+		gqlHandler: t.gqlHandler,
+		cookie:     t.input.ServerConnection.SessionCookie,
+	})
+
+	go func() {
+		if err := t.hostServer.Serve(l); err != nil {
+			logger.Errorf("[Plugin / %s] error serving plugin host: %v", t.plugin.Name, err)
+		}
+	}()
+
+	return l.Addr().String(), nil
+}
+
+// connect reads the handshake of the plugin, then connects to it and runs
+// the operation.
+func (t *grpcPluginTask) connect(stdout io.ReadCloser) (proto.Plugin_RunClient, error) {
+	handshake, err := t.readHandshake(stdout)
+	if err != nil {
+		return nil, err
+	}
+
+	target := handshake.Address
+	if handshake.Network == "unix" {
+		target = "unix:" + target
+	}
+
+	t.conn, err = grpc.Dial(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to plugin: %w", err)
+	}
+
+	t.client = proto.NewPluginClient(t.conn)
+
+	req, err := t.runRequest()
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := t.client.Run(context.Background(), req)
+	if err != nil {
+		return nil, fmt.Errorf("error running plugin operation: %w", err)
+	}
+
+	return stream, nil
+}
+
+// readHandshake reads the handshake line from the plugin stdout. The rest of
+// stdout is logged.
+func (t *grpcPluginTask) readHandshake(stdout io.ReadCloser) (*common.GRPCHandshake, error) {
+	lines := make(chan string, 1)
+	go func() {
+		r := bufio.NewReader(stdout)
+		line, err := r.ReadString('\n')
+		if err != nil && line == "" {
+			close(lines)
+			return
+		}
+		lines <- line
+
+		// the plugin may still write to stdout
+		go t.handlePluginStderr(t.plugin.Name, io.NopCloser(r))
+	}()
+
+	select {
+	case line, ok := <-lines:
+		if !ok {
+			return nil, errors.New("plugin exited before handshake")
+		}
+		return common.ParseGRPCHandshake(line)
+	case <-time.After(grpcHandshakeTimeout):
+		return nil, errors.New("timed out waiting for plugin handshake")
+	}
+}
+
+func (t *grpcPluginTask) runRequest() (*proto.RunRequest, error) {
+	argsJSON, err := json.Marshal(t.input.Args)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding plugin args: %w", err)
+	}
+
+	args := &structpb.Struct{}
+	if err := protojson.Unmarshal(argsJSON, args); err != nil {
+		return nil, fmt.Errorf("error encoding plugin args: %w", err)
+	}
+
+	return &proto.RunRequest{
+		Args:      args,
+		ServerDir: t.input.ServerConnection.Dir,
+		PluginDir: t.input.ServerConnection.PluginDir,
+	}, nil
+}
+
+// receive handles the messages of the Run stream, returning the output of
+// the operation.
+func (t *grpcPluginTask) receive(stream proto.Plugin_RunClient) *common.PluginOutput {
+	prefix := fmt.Sprintf("[Plugin / %s] ", t.plugin.Name)
+
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				err = errors.New("plugin did not return an output")
+			}
+			errStr := err.Error()
+			return &common.PluginOutput{Error: &errStr}
+		}
+
+		switch m := resp.Message.(type) {
+		case *proto.RunResponse_Progress:
+			// don't block on this
+			select {
+			case t.progress <- m.Progress:
+			default:
+			}
+		case *proto.RunResponse_Log:
+			logGRPCMessage(prefix, m.Log)
+		case *proto.RunResponse_Output:
+			ret := &common.PluginOutput{
+				Output: m.Output.GetOutput().AsInterface(),
+			}
+			if m.Output.GetError() != "" {
+				errStr := m.Output.GetError()
+				ret.Error = &errStr
+			}
+			return ret
+		}
+	}
+}
+
+func logGRPCMessage(prefix string, m *proto.LogMessage) {
+	switch m.GetLevel() {
+	case proto.LogLevel_LOG_LEVEL_TRACE:
+		logger.Trace(prefix, m.GetMessage())
+	case proto.LogLevel_LOG_LEVEL_DEBUG:
+		logger.Debug(prefix, m.GetMessage())
+	case proto.LogLevel_LOG_LEVEL_WARNING:
+		logger.Warn(prefix, m.GetMessage())
+	case proto.LogLevel_LOG_LEVEL_ERROR:
+		logger.Error(prefix, m.GetMessage())
+	default:
+		logger.Info(prefix, m.GetMessage())
+	}
+}
+
+// stopProcess closes the connections and waits for the plugin process to
+// exit, killing it if it does not.
+func (t *grpcPluginTask) stopProcess() {
+	if t.conn != nil {
+		t.conn.Close()
+	}
+	t.hostServer.Stop()
+
+	exited := make(chan struct{})
+	go func() {
+		_ = t.cmd.Wait()
+		close(exited)
+	}()
+
+	select {
+	case <-exited:
+	case <-time.After(grpcExitTimeout):
+		logger.Warnf("[Plugin / %s] killing plugin process which did not exit", t.plugin.Name)
+		_ = t.cmd.Process.Kill()
+	}
+}
+
+func (t *grpcPluginTask) Wait() {
+	t.waitGroup.Wait()
+}
+
+func (t *grpcPluginTask) Stop() error {
+	if t.client == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), grpcStopTimeout)
+	defer cancel()
+
+	_, err := t.client.Stop(ctx, &proto.StopRequest{})
+	return err
+}
+
+// grpcHost implements the Host service for a plugin task.
+type grpcHost struct {
+	proto.UnimplementedHostServer
+
+	gqlHandler http.Handler
+	cookie     *http.Cookie
+}
+
+func (h *grpcHost) GraphQL(ctx context.Context, req *proto.GraphQLRequest) (*proto.GraphQLResponse, error) {
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, "/graphql", bytes.NewReader(req.GetBody()))
+	if err != nil {
+		return nil, err
+	}
+	r.Header.Set("Content-Type", "application/json")
+
+	if h.cookie != nil {
+		r.AddCookie(h.cookie)
+	}
+
+	w := httptest.NewRecorder()
+	h.gqlHandler.ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		return nil, fmt.Errorf("graphQL request failed: %d - %s", w.Code, w.Body.String())
+	}
+
+	return &proto.GraphQLResponse{
+		Body: w.Body.Bytes(),
+	}, nil
+}
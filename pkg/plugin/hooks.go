@@ -1,6 +1,9 @@
 package plugin
 
 import (
+	"encoding/json"
+	"fmt"
+
 	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/plugin/common"
 )
@@ -17,6 +20,19 @@ type HookTriggerEnum string
 // Scan-related hooks are current disabled until post-hook execution is
 // integrated.
 
+// Pre hooks are run synchronously before the mutation, and may replace its
+// input or veto it. See common.PreHookOutput.
+const (
+	SceneCreatePre     HookTriggerEnum = "Scene.Create.Pre"
+	SceneUpdatePre     HookTriggerEnum = "Scene.Update.Pre"
+	PerformerCreatePre HookTriggerEnum = "Performer.Create.Pre"
+	PerformerUpdatePre HookTriggerEnum = "Performer.Update.Pre"
+	StudioCreatePre    HookTriggerEnum = "Studio.Create.Pre"
+	StudioUpdatePre    HookTriggerEnum = "Studio.Update.Pre"
+	TagCreatePre       HookTriggerEnum = "Tag.Create.Pre"
+	TagUpdatePre       HookTriggerEnum = "Tag.Update.Pre"
+)
+
 const (
 	SceneMarkerCreatePost  HookTriggerEnum = "SceneMarker.Create.Post"
 	SceneMarkerUpdatePost  HookTriggerEnum = "SceneMarker.Update.Post"
@@ -40,6 +56,7 @@ const (
 
 	PerformerCreatePost  HookTriggerEnum = "Performer.Create.Post"
 	PerformerUpdatePost  HookTriggerEnum = "Performer.Update.Post"
+	PerformerMergePost   HookTriggerEnum = "Performer.Merge.Post"
 	PerformerDestroyPost HookTriggerEnum = "Performer.Destroy.Post"
 
 	StudioCreatePost  HookTriggerEnum = "Studio.Create.Post"
@@ -50,9 +67,20 @@ const (
 	TagUpdatePost  HookTriggerEnum = "Tag.Update.Post"
 	TagMergePost   HookTriggerEnum = "Tag.Merge.Post"
 	TagDestroyPost HookTriggerEnum = "Tag.Destroy.Post"
+
+	WantedSceneFulfilledPost HookTriggerEnum = "WantedScene.Fulfilled.Post"
 )
 
 var AllHookTriggerEnum = []HookTriggerEnum{
+	SceneCreatePre,
+	SceneUpdatePre,
+	PerformerCreatePre,
+	PerformerUpdatePre,
+	StudioCreatePre,
+	StudioUpdatePre,
+	TagCreatePre,
+	TagUpdatePre,
+
 	SceneMarkerCreatePost,
 	SceneMarkerUpdatePost,
 	SceneMarkerDestroyPost,
@@ -75,6 +103,7 @@ var AllHookTriggerEnum = []HookTriggerEnum{
 
 	PerformerCreatePost,
 	PerformerUpdatePost,
+	PerformerMergePost,
 	PerformerDestroyPost,
 
 	StudioCreatePost,
@@ -85,12 +114,23 @@ var AllHookTriggerEnum = []HookTriggerEnum{
 	TagUpdatePost,
 	TagMergePost,
 	TagDestroyPost,
+
+	WantedSceneFulfilledPost,
 }
 
 func (e HookTriggerEnum) IsValid() bool {
 
 	switch e {
-	case SceneMarkerCreatePost,
+	case SceneCreatePre,
+		SceneUpdatePre,
+		PerformerCreatePre,
+		PerformerUpdatePre,
+		StudioCreatePre,
+		StudioUpdatePre,
+		TagCreatePre,
+		TagUpdatePre,
+
+		SceneMarkerCreatePost,
 		SceneMarkerUpdatePost,
 		SceneMarkerDestroyPost,
 
@@ -112,6 +152,7 @@ func (e HookTriggerEnum) IsValid() bool {
 
 		PerformerCreatePost,
 		PerformerUpdatePost,
+		PerformerMergePost,
 		PerformerDestroyPost,
 
 		StudioCreatePost,
@@ -120,7 +161,9 @@ func (e HookTriggerEnum) IsValid() bool {
 
 		TagCreatePost,
 		TagUpdatePost,
-		TagDestroyPost:
+		TagDestroyPost,
+
+		WantedSceneFulfilledPost:
 		return true
 	}
 	return false
@@ -134,6 +177,31 @@ func addHookContext(argsMap common.ArgsMap, hookContext common.HookContext) {
 	argsMap[common.HookContextKey] = hookContext
 }
 
+// PreHookVetoError is returned when a pre hook vetoes a mutation.
+type PreHookVetoError struct {
+	Plugin string
+	Reason string
+}
+
+func (e *PreHookVetoError) Error() string {
+	return fmt.Sprintf("rejected by plugin %s: %s", e.Plugin, e.Reason)
+}
+
+// convertPreHookOutput converts the output of a pre hook, which is decoded
+// from JSON by raw plugins, to a PreHookOutput.
+func convertPreHookOutput(output interface{}, dest *common.PreHookOutput) error {
+	if output == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(output)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, dest)
+}
+
 // types for destroy hooks, to provide a little more information
 type SceneDestroyInput struct {
 	models.SceneDestroyInput
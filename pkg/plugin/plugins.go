@@ -32,6 +32,7 @@ type Plugin struct {
 	Tasks       []*PluginTask `json:"tasks"`
 	Hooks       []*PluginHook `json:"hooks"`
 	UI          PluginUI      `json:"ui"`
+	Permissions []string      `json:"permissions"`
 }
 
 type PluginUI struct {
@@ -51,12 +52,17 @@ type ServerConfig interface {
 	GetPythonPath() string
 }
 
+// HookListener is called when post hooks are executed, regardless of
+// whether any plugin handles the hook.
+type HookListener func(ctx context.Context, id int, hookType HookTriggerEnum)
+
 // Cache stores plugin details.
 type Cache struct {
-	config       ServerConfig
-	plugins      []Config
-	sessionStore *session.Store
-	gqlHandler   http.Handler
+	config        ServerConfig
+	plugins       []Config
+	sessionStore  *session.Store
+	gqlHandler    http.Handler
+	hookListeners []HookListener
 }
 
 // NewCache returns a new Cache.
@@ -76,6 +82,11 @@ func (c *Cache) RegisterGQLHandler(handler http.Handler) {
 	c.gqlHandler = handler
 }
 
+// RegisterHookListener adds a listener called when post hooks are executed.
+func (c *Cache) RegisterHookListener(l HookListener) {
+	c.hookListeners = append(c.hookListeners, l)
+}
+
 func (c *Cache) RegisterSessionStore(sessionStore *session.Store) {
 	c.sessionStore = sessionStore
 }
@@ -151,8 +162,10 @@ func buildPluginInput(plugin *Config, operation *OperationConfig, serverConnecti
 	}
 }
 
-func (c Cache) makeServerConnection(ctx context.Context) common.StashServerConnection {
-	cookie := c.sessionStore.MakePluginCookie(ctx)
+// makeServerConnection returns the connection details for the plugin,
+// including a token scoped to the permissions of the plugin.
+func (c Cache) makeServerConnection(ctx context.Context, plugin *Config) common.StashServerConnection {
+	cookie := c.sessionStore.MakePluginCookie(ctx, plugin.id, plugin.Permissions)
 
 	serverConnection := common.StashServerConnection{
 		Scheme:        "http",
@@ -162,6 +175,10 @@ func (c Cache) makeServerConnection(ctx context.Context) common.StashServerConne
 		Dir:           c.config.GetConfigPath(),
 	}
 
+	if cookie != nil {
+		serverConnection.APIKey = cookie.Value
+	}
+
 	if c.config.HasTLSConfig() {
 		serverConnection.Scheme = "https"
 	}
@@ -173,8 +190,6 @@ func (c Cache) makeServerConnection(ctx context.Context) common.StashServerConne
 // name provided. Returns an error if the plugin or the operation could not be
 // resolved.
 func (c Cache) CreateTask(ctx context.Context, pluginID string, operationName string, args []*PluginArgInput, progress chan float64) (Task, error) {
-	serverConnection := c.makeServerConnection(ctx)
-
 	// find the plugin and operation
 	plugin := c.getPlugin(pluginID)
 
@@ -182,6 +197,8 @@ func (c Cache) CreateTask(ctx context.Context, pluginID string, operationName st
 		return nil, fmt.Errorf("no plugin with ID %s", pluginID)
 	}
 
+	serverConnection := c.makeServerConnection(ctx, plugin)
+
 	operation := plugin.getTask(operationName)
 	if operation == nil {
 		return nil, fmt.Errorf("no task with name %s in plugin %s", operationName, plugin.getName())
@@ -199,6 +216,10 @@ func (c Cache) CreateTask(ctx context.Context, pluginID string, operationName st
 }
 
 func (c Cache) ExecutePostHooks(ctx context.Context, id int, hookType HookTriggerEnum, input interface{}, inputFields []string) {
+	for _, l := range c.hookListeners {
+		l(ctx, id, hookType)
+	}
+
 	if err := c.executePostHooks(ctx, hookType, common.HookContext{
 		ID:          id,
 		Type:        hookType.String(),
@@ -238,43 +259,11 @@ func (c Cache) executePostHooks(ctx context.Context, hookType HookTriggerEnum, h
 		}
 
 		for _, h := range hooks {
-			newCtx := session.AddVisitedPlugin(ctx, p.id)
-			serverConnection := c.makeServerConnection(newCtx)
-
-			pluginInput := buildPluginInput(&p, &h.OperationConfig, serverConnection, nil)
-			addHookContext(pluginInput.Args, hookContext)
-
-			pt := pluginTask{
-				plugin:       &p,
-				operation:    &h.OperationConfig,
-				input:        pluginInput,
-				gqlHandler:   c.gqlHandler,
-				serverConfig: c.config,
-			}
-
-			task := pt.createTask()
-			if err := task.Start(); err != nil {
+			output, err := c.runHook(ctx, p, h, hookContext)
+			if err != nil {
 				return err
 			}
 
-			// handle cancel from context
-			c := make(chan struct{})
-			go func() {
-				task.Wait()
-				close(c)
-			}()
-
-			select {
-			case <-ctx.Done():
-				if err := task.Stop(); err != nil {
-					logger.Warnf("could not stop task: %v", err)
-				}
-				return fmt.Errorf("operation cancelled")
-			case <-c:
-				// task finished normally
-			}
-
-			output := task.GetResult()
 			if output == nil {
 				logger.Debugf("%s [%s]: returned no result", hookType.String(), p.Name)
 			} else {
@@ -290,6 +279,106 @@ func (c Cache) executePostHooks(ctx context.Context, hookType HookTriggerEnum, h
 	return nil
 }
 
+// ExecutePreHooks runs the pre hooks of the mutation synchronously, in plugin
+// order. Each hook receives the input returned by the previous hook, and may
+// replace the input or veto the mutation. Returns the replaced input, or nil
+// if no hook replaced it. Returns a *PreHookVetoError if a hook vetoed the
+// mutation.
+func (c Cache) ExecutePreHooks(ctx context.Context, id int, hookType HookTriggerEnum, input interface{}) (map[string]interface{}, error) {
+	visitedPlugins := session.GetVisitedPlugins(ctx)
+
+	var ret map[string]interface{}
+	var current interface{} = input
+	for _, p := range c.plugins {
+		hooks := p.getHooks(hookType)
+		// mutations made by a hooked plugin are not hooked again
+		if len(hooks) > 0 && stringslice.StrInclude(visitedPlugins, p.id) {
+			logger.Debugf("plugin ID '%s' already triggered, not re-triggering", p.id)
+			continue
+		}
+
+		for _, h := range hooks {
+			output, err := c.runHook(ctx, p, h, common.HookContext{
+				ID:    id,
+				Type:  hookType.String(),
+				Input: current,
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			if output == nil {
+				continue
+			}
+
+			if output.Error != nil {
+				// a failing plugin does not prevent the mutation
+				logger.Errorf("%s [%s]: returned error: %s", hookType.String(), p.Name, *output.Error)
+				continue
+			}
+
+			var result common.PreHookOutput
+			if err := convertPreHookOutput(output.Output, &result); err != nil {
+				logger.Errorf("%s [%s]: invalid output: %v", hookType.String(), p.Name, err)
+				continue
+			}
+
+			if result.Veto != nil {
+				return nil, &PreHookVetoError{Plugin: p.getName(), Reason: *result.Veto}
+			}
+
+			if result.Input != nil {
+				logger.Debugf("%s [%s]: replaced input: %v", hookType.String(), p.Name, result.Input)
+				ret = result.Input
+				current = ret
+			}
+		}
+	}
+
+	return ret, nil
+}
+
+// runHook runs the hook of the plugin, waiting for it to complete.
+func (c Cache) runHook(ctx context.Context, p Config, h *HookConfig, hookContext common.HookContext) (*common.PluginOutput, error) {
+	newCtx := session.AddVisitedPlugin(ctx, p.id)
+	serverConnection := c.makeServerConnection(newCtx, &p)
+
+	pluginInput := buildPluginInput(&p, &h.OperationConfig, serverConnection, nil)
+	addHookContext(pluginInput.Args, hookContext)
+
+	pt := pluginTask{
+		plugin:       &p,
+		operation:    &h.OperationConfig,
+		input:        pluginInput,
+		gqlHandler:   c.gqlHandler,
+		serverConfig: c.config,
+	}
+
+	task := pt.createTask()
+	if err := task.Start(); err != nil {
+		return nil, err
+	}
+
+	// handle cancel from context
+	done := make(chan struct{})
+	go func() {
+		task.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-ctx.Done():
+		if err := task.Stop(); err != nil {
+			logger.Warnf("could not stop task: %v", err)
+		}
+		return nil, fmt.Errorf("operation cancelled")
+	case <-done:
+		// task finished normally
+	}
+
+	return task.GetResult(), nil
+}
+
 func (c Cache) getPlugin(pluginID string) *Config {
 	for _, s := range c.plugins {
 		if s.id == pluginID {
@@ -0,0 +1,5 @@
+// Package proto contains the protocol buffer definitions of plugins using the
+// grpc interface. Plugins written in Go should use the sdk package instead.
+package proto
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative plugin.proto
@@ -0,0 +1,52 @@
+package sdk
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"github.com/shurcooL/graphql"
+
+	"github.com/stashapp/stash/pkg/plugin/proto"
+)
+
+// hostTransport sends the GraphQL requests of the client through the Host
+// service, so that plugins do not need the address or credentials of the
+// stash server.
+type hostTransport struct {
+	host proto.HostClient
+}
+
+func (t hostTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := t.host.GraphQL(req.Context(), &proto.GraphQLRequest{Body: body})
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader(resp.GetBody())),
+		Request:    req,
+	}, nil
+}
+
+func newGraphQLClient(host proto.HostClient) *graphql.Client {
+	httpClient := &http.Client{
+		Transport: hostTransport{host: host},
+	}
+
+	// the url is not used by the transport
+	return graphql.NewClient("http://stash/graphql", httpClient)
+}
@@ -0,0 +1,262 @@
+// Package sdk implements plugins using the grpc interface. It is not intended
+// for the main stash code to access.
+//
+// A plugin implements the Plugin interface and serves it from its main
+// function:
+//
+//	func main() {
+//		if err := sdk.Serve(&myPlugin{}); err != nil {
+//			log.Fatal(err)
+//		}
+//	}
+package sdk
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/shurcooL/graphql"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/stashapp/stash/pkg/plugin/common"
+	"github.com/stashapp/stash/pkg/plugin/proto"
+)
+
+// Plugin is the interface that grpc plugins are expected to fulfil.
+type Plugin interface {
+	// Run performs the operation. ctx is cancelled when stash requests the
+	// operation to stop. The output is encoded as JSON.
+	Run(ctx context.Context, req *Request) (interface{}, error)
+}
+
+// Request is an operation requested by stash.
+type Request struct {
+	Args common.ArgsMap
+
+	// ServerDir is the directory containing the stash server's configuration
+	// file.
+	ServerDir string
+	// PluginDir is the directory containing the plugin configuration file.
+	PluginDir string
+
+	// Client executes GraphQL requests against the stash server with the
+	// permissions of the plugin. Queries and mutations are declared as Go
+	// structs, see github.com/shurcooL/graphql.
+	Client *graphql.Client
+
+	mutex  sync.Mutex
+	stream proto.Plugin_RunServer
+}
+
+func (r *Request) send(resp *proto.RunResponse) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.stream.Send(resp)
+}
+
+// Progress reports the progress of the operation, from 0 to 1.
+func (r *Request) Progress(progress float64) error {
+	return r.send(&proto.RunResponse{
+		Message: &proto.RunResponse_Progress{Progress: progress},
+	})
+}
+
+func (r *Request) log(level proto.LogLevel, format string, args ...interface{}) {
+	// logging is best effort
+	_ = r.send(&proto.RunResponse{
+		Message: &proto.RunResponse_Log{Log: &proto.LogMessage{
+			Level:   level,
+			Message: fmt.Sprintf(format, args...),
+		}},
+	})
+}
+
+// Tracef sends a trace log message to stash.
+func (r *Request) Tracef(format string, args ...interface{}) {
+	r.log(proto.LogLevel_LOG_LEVEL_TRACE, format, args...)
+}
+
+// Debugf sends a debug log message to stash.
+func (r *Request) Debugf(format string, args ...interface{}) {
+	r.log(proto.LogLevel_LOG_LEVEL_DEBUG, format, args...)
+}
+
+// Infof sends an info log message to stash.
+func (r *Request) Infof(format string, args ...interface{}) {
+	r.log(proto.LogLevel_LOG_LEVEL_INFO, format, args...)
+}
+
+// Warnf sends a warning log message to stash.
+func (r *Request) Warnf(format string, args ...interface{}) {
+	r.log(proto.LogLevel_LOG_LEVEL_WARNING, format, args...)
+}
+
+// Errorf sends an error log message to stash.
+func (r *Request) Errorf(format string, args ...interface{}) {
+	r.log(proto.LogLevel_LOG_LEVEL_ERROR, format, args...)
+}
+
+// ErrNotStartedByStash is returned by Serve if the plugin was not started by
+// stash.
+var ErrNotStartedByStash = errors.New("this executable is a stash plugin and must be run by stash")
+
+// Serve serves the plugin until the operation requested by stash is complete.
+func Serve(p Plugin) error {
+	if os.Getenv(common.GRPCMagicCookieKey) != common.GRPCMagicCookieValue {
+		return ErrNotStartedByStash
+	}
+
+	if !supportsVersion(os.Getenv(common.GRPCProtocolVersionsKey)) {
+		return fmt.Errorf("stash does not support protocol version %d", common.GRPCProtocolVersion)
+	}
+
+	hostConn, err := grpc.Dial(os.Getenv(common.GRPCHostAddrKey), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return fmt.Errorf("connecting to stash: %w", err)
+	}
+	defer hostConn.Close()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+
+	s := &server{
+		plugin: p,
+		client: newGraphQLClient(proto.NewHostClient(hostConn)),
+		done:   make(chan struct{}),
+	}
+
+	grpcServer := grpc.NewServer()
+	proto.RegisterPluginServer(grpcServer, s)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- grpcServer.Serve(l)
+	}()
+
+	if err := writeHandshake(l.Addr().String()); err != nil {
+		grpcServer.Stop()
+		return err
+	}
+
+	select {
+	case <-s.done:
+		grpcServer.GracefulStop()
+		return nil
+	case err := <-serveErr:
+		return err
+	}
+}
+
+func supportsVersion(versions string) bool {
+	for _, v := range strings.Split(versions, ",") {
+		if n, err := strconv.Atoi(strings.TrimSpace(v)); err == nil && n == common.GRPCProtocolVersion {
+			return true
+		}
+	}
+	return false
+}
+
+func writeHandshake(addr string) error {
+	w := bufio.NewWriter(os.Stdout)
+	h := common.GRPCHandshake{
+		ProtocolVersion: common.GRPCProtocolVersion,
+		Network:         "tcp",
+		Address:         addr,
+	}
+	if _, err := fmt.Fprintln(w, h.String()); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// server implements the Plugin service.
+type server struct {
+	proto.UnimplementedPluginServer
+
+	plugin Plugin
+	client *graphql.Client
+
+	mutex  sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func (s *server) Run(req *proto.RunRequest, stream proto.Plugin_RunServer) error {
+	defer close(s.done)
+
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	s.mutex.Lock()
+	s.cancel = cancel
+	s.mutex.Unlock()
+
+	args := common.ArgsMap{}
+	for k, v := range req.GetArgs().AsMap() {
+		args[k] = v
+	}
+
+	r := &Request{
+		Args:      args,
+		ServerDir: req.GetServerDir(),
+		PluginDir: req.GetPluginDir(),
+		Client:    s.client,
+		stream:    stream,
+	}
+
+	output := &proto.Output{}
+
+	out, err := s.plugin.Run(ctx, r)
+	if err != nil {
+		output.Error = err.Error()
+	}
+
+	if out != nil {
+		output.Output, err = toValue(out)
+		if err != nil {
+			output.Error = fmt.Sprintf("encoding output: %v", err)
+		}
+	}
+
+	return r.send(&proto.RunResponse{
+		Message: &proto.RunResponse_Output{Output: output},
+	})
+}
+
+func (s *server) Stop(ctx context.Context, req *proto.StopRequest) (*proto.StopResponse, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.cancel != nil {
+		s.cancel()
+	}
+
+	return &proto.StopResponse{}, nil
+}
+
+func toValue(v interface{}) (*structpb.Value, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := &structpb.Value{}
+	if err := protojson.Unmarshal(b, ret); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,328 @@
+// Package savedfilter provides the sharing of saved filters between stash
+// instances.
+package savedfilter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/studio"
+	"github.com/stashapp/stash/pkg/tag"
+)
+
+// DocumentVersion is the version of the format of exported saved filters.
+// Documents of a later version cannot be imported.
+const DocumentVersion = 1
+
+// Document is a set of exported saved filters. Tags and studios referenced
+// by the filters are stored by name, since IDs differ between instances.
+type Document struct {
+	Version int              `json:"version"`
+	Filters []DocumentFilter `json:"filters"`
+}
+
+type DocumentFilter struct {
+	Mode models.FilterMode `json:"mode"`
+	Name string            `json:"name"`
+	// JSON-encoded filter string, with referenced objects identified by name
+	Filter         string                            `json:"filter"`
+	DisplayOptions *models.SavedFilterDisplayOptions `json:"display_options,omitempty"`
+}
+
+// ParseDocument decodes an exported document of saved filters.
+func ParseDocument(data []byte) (*Document, error) {
+	var ret Document
+	if err := json.Unmarshal(data, &ret); err != nil {
+		return nil, fmt.Errorf("decoding saved filters: %w", err)
+	}
+
+	if ret.Version < 1 {
+		return nil, errors.New("saved filters document has no version")
+	}
+	if ret.Version > DocumentVersion {
+		return nil, fmt.Errorf("saved filters document version %d is not supported", ret.Version)
+	}
+
+	for _, f := range ret.Filters {
+		if !f.Mode.IsValid() {
+			return nil, fmt.Errorf("saved filter %q has invalid mode %q", f.Name, f.Mode)
+		}
+		if f.DisplayOptions != nil {
+			if err := f.DisplayOptions.Validate(); err != nil {
+				return nil, fmt.Errorf("saved filter %q has invalid display options: %w", f.Name, err)
+			}
+		}
+	}
+
+	return &ret, nil
+}
+
+type referenceKind string
+
+const (
+	referenceTag    referenceKind = "tag"
+	referenceStudio referenceKind = "studio"
+)
+
+// criterionReferences are the types of the criteria which reference tags or
+// studios.
+var criterionReferences = map[string]referenceKind{
+	"tags":           referenceTag,
+	"sceneTags":      referenceTag,
+	"performerTags":  referenceTag,
+	"parentTags":     referenceTag,
+	"childTags":      referenceTag,
+	"studios":        referenceStudio,
+	"parent_studios": referenceStudio,
+}
+
+type TagFinder interface {
+	tag.Finder
+	tag.Queryer
+}
+
+type StudioFinder interface {
+	studio.Finder
+	studio.Queryer
+}
+
+// Resolver converts the tag and studio references of saved filters between
+// IDs and names.
+type Resolver struct {
+	TagFinder    TagFinder
+	StudioFinder StudioFinder
+}
+
+// Export returns the document of the saved filters. References to objects
+// which no longer exist are exported using their last known name.
+func (r *Resolver) Export(ctx context.Context, filters []*models.SavedFilter) (*Document, error) {
+	ret := &Document{
+		Version: DocumentVersion,
+		Filters: []DocumentFilter{},
+	}
+
+	for _, f := range filters {
+		filter, err := rewriteReferences(f.Filter, func(kind referenceKind, item map[string]interface{}) (map[string]interface{}, error) {
+			label, _ := item["label"].(string)
+			id, _ := item["id"].(string)
+			name, err := r.name(ctx, kind, id)
+			if err != nil {
+				return nil, err
+			}
+			if name == "" {
+				name = label
+			}
+
+			return map[string]interface{}{"name": name}, nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("exporting saved filter %q: %w", f.Name, err)
+		}
+
+		ret.Filters = append(ret.Filters, DocumentFilter{
+			Mode:           f.Mode,
+			Name:           f.Name,
+			Filter:         filter,
+			DisplayOptions: f.DisplayOptions,
+		})
+	}
+
+	return ret, nil
+}
+
+func (r *Resolver) name(ctx context.Context, kind referenceKind, idStr string) (string, error) {
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		return "", nil
+	}
+
+	switch kind {
+	case referenceTag:
+		t, err := r.TagFinder.Find(ctx, id)
+		if err != nil || t == nil {
+			return "", err
+		}
+		return t.Name, nil
+	case referenceStudio:
+		s, err := r.StudioFinder.Find(ctx, id)
+		if err != nil || s == nil {
+			return "", err
+		}
+		return s.Name.String, nil
+	}
+
+	return "", nil
+}
+
+// Import returns the saved filters of the document, with referenced tags and
+// studios resolved by name or alias. References which cannot be resolved are
+// removed from the filters and returned in missing.
+func (r *Resolver) Import(ctx context.Context, doc *Document) (ret []models.SavedFilter, missing []string, err error) {
+	for _, f := range doc.Filters {
+		filter, err := rewriteReferences(f.Filter, func(kind referenceKind, item map[string]interface{}) (map[string]interface{}, error) {
+			name, _ := item["name"].(string)
+			id, resolved, err := r.find(ctx, kind, name)
+			if err != nil {
+				return nil, err
+			}
+			if id == 0 {
+				missing = append(missing, fmt.Sprintf("%s %q", kind, name))
+				return nil, nil
+			}
+
+			return map[string]interface{}{
+				"id":    strconv.Itoa(id),
+				"label": resolved,
+			}, nil
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("importing saved filter %q: %w", f.Name, err)
+		}
+
+		ret = append(ret, models.SavedFilter{
+			Mode:           f.Mode,
+			Name:           f.Name,
+			Filter:         filter,
+			DisplayOptions: f.DisplayOptions,
+		})
+	}
+
+	return ret, missing, nil
+}
+
+// find returns the ID and name of the object with the name or alias, or 0 if
+// not found.
+func (r *Resolver) find(ctx context.Context, kind referenceKind, name string) (int, string, error) {
+	if name == "" {
+		return 0, "", nil
+	}
+
+	switch kind {
+	case referenceTag:
+		t, err := tag.ByName(ctx, r.TagFinder, name)
+		if err == nil && t == nil {
+			t, err = tag.ByAlias(ctx, r.TagFinder, name)
+		}
+		if err != nil || t == nil {
+			return 0, "", err
+		}
+		return t.ID, t.Name, nil
+	case referenceStudio:
+		s, err := studio.ByName(ctx, r.StudioFinder, name)
+		if err == nil && s == nil {
+			s, err = studio.ByAlias(ctx, r.StudioFinder, name)
+		}
+		if err != nil || s == nil {
+			return 0, "", err
+		}
+		return s.ID, s.Name.String, nil
+	}
+
+	return 0, "", nil
+}
+
+type rewriteFunc func(kind referenceKind, item map[string]interface{}) (map[string]interface{}, error)
+
+// rewriteReferences replaces the items of the criteria of the JSON-encoded
+// filter which reference tags or studios with the result of fn. Items are
+// removed if fn returns nil.
+func rewriteReferences(filter string, fn rewriteFunc) (string, error) {
+	var f map[string]interface{}
+	if err := json.Unmarshal([]byte(filter), &f); err != nil {
+		return "", fmt.Errorf("decoding filter: %w", err)
+	}
+
+	criteria, ok := f["c"].([]interface{})
+	if !ok {
+		return filter, nil
+	}
+
+	for i, c := range criteria {
+		// criteria are stored as JSON-encoded strings
+		encoded, ok := c.(string)
+		if !ok {
+			continue
+		}
+
+		var criterion map[string]interface{}
+		if err := json.Unmarshal([]byte(encoded), &criterion); err != nil {
+			return "", fmt.Errorf("decoding criterion: %w", err)
+		}
+
+		criterionType, _ := criterion["type"].(string)
+		kind, ok := criterionReferences[criterionType]
+		if !ok {
+			continue
+		}
+
+		value, err := rewriteValue(criterion["value"], func(item map[string]interface{}) (map[string]interface{}, error) {
+			return fn(kind, item)
+		})
+		if err != nil {
+			return "", err
+		}
+		criterion["value"] = value
+
+		b, err := json.Marshal(criterion)
+		if err != nil {
+			return "", err
+		}
+		criteria[i] = string(b)
+	}
+
+	b, err := json.Marshal(f)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// rewriteValue rewrites a criterion value, which is either a list of items or
+// a hierarchical value with lists of included and excluded items.
+func rewriteValue(v interface{}, fn func(item map[string]interface{}) (map[string]interface{}, error)) (interface{}, error) {
+	switch v := v.(type) {
+	case []interface{}:
+		return rewriteItems(v, fn)
+	case map[string]interface{}:
+		for _, key := range []string{"items", "excluded"} {
+			items, ok := v[key].([]interface{})
+			if !ok {
+				continue
+			}
+
+			rewritten, err := rewriteItems(items, fn)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = rewritten
+		}
+		return v, nil
+	}
+
+	return v, nil
+}
+
+func rewriteItems(items []interface{}, fn func(item map[string]interface{}) (map[string]interface{}, error)) ([]interface{}, error) {
+	ret := []interface{}{}
+	for _, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		rewritten, err := fn(m)
+		if err != nil {
+			return nil, err
+		}
+		if rewritten != nil {
+			ret = append(ret, rewritten)
+		}
+	}
+
+	return ret, nil
+}
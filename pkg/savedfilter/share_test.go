@@ -0,0 +1,225 @@
+package savedfilter
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type testTagFinder struct {
+	tags []*models.Tag
+	// aliases maps aliases to tag IDs
+	aliases map[string]int
+}
+
+func (f *testTagFinder) Find(ctx context.Context, id int) (*models.Tag, error) {
+	for _, t := range f.tags {
+		if t.ID == id {
+			return t, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *testTagFinder) Query(ctx context.Context, tagFilter *models.TagFilterType, findFilter *models.FindFilterType) ([]*models.Tag, int, error) {
+	for _, t := range f.tags {
+		if (tagFilter.Name != nil && t.Name == tagFilter.Name.Value) ||
+			(tagFilter.Aliases != nil && f.aliases[tagFilter.Aliases.Value] == t.ID) {
+			return []*models.Tag{t}, 1, nil
+		}
+	}
+	return nil, 0, nil
+}
+
+type testStudioFinder struct {
+	studios []*models.Studio
+}
+
+func (f *testStudioFinder) Find(ctx context.Context, id int) (*models.Studio, error) {
+	for _, s := range f.studios {
+		if s.ID == id {
+			return s, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *testStudioFinder) Query(ctx context.Context, studioFilter *models.StudioFilterType, findFilter *models.FindFilterType) ([]*models.Studio, int, error) {
+	for _, s := range f.studios {
+		if studioFilter.Name != nil && s.Name.String == studioFilter.Name.Value {
+			return []*models.Studio{s}, 1, nil
+		}
+	}
+	return nil, 0, nil
+}
+
+// makeFilter returns a filter string with the criteria encoded as the UI
+// does.
+func makeFilter(t *testing.T, criteria ...interface{}) string {
+	t.Helper()
+
+	var c []string
+	for _, criterion := range criteria {
+		b, err := json.Marshal(criterion)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c = append(c, string(b))
+	}
+
+	b, err := json.Marshal(map[string]interface{}{
+		"sortby": "date",
+		"c":      c,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func item(id, label string) map[string]interface{} {
+	return map[string]interface{}{"id": id, "label": label}
+}
+
+func TestResolver_ExportImport(t *testing.T) {
+	ctx := context.Background()
+
+	source := &Resolver{
+		TagFinder: &testTagFinder{tags: []*models.Tag{
+			{ID: 1, Name: "Outdoor"},
+			{ID: 2, Name: "Renamed"},
+		}},
+		StudioFinder: &testStudioFinder{studios: []*models.Studio{
+			{ID: 3, Name: sql.NullString{String: "Studio", Valid: true}},
+		}},
+	}
+
+	filter := makeFilter(t,
+		map[string]interface{}{
+			"type":     "tags",
+			"modifier": "INCLUDES_ALL",
+			"value": map[string]interface{}{
+				"items": []interface{}{item("1", "Outdoor"), item("2", "Old Name"), item("9", "Deleted")},
+				"depth": 0,
+			},
+		},
+		map[string]interface{}{
+			"type":     "parent_studios",
+			"modifier": "INCLUDES",
+			"value":    []interface{}{item("3", "Studio")},
+		},
+		map[string]interface{}{
+			"type":     "rating100",
+			"modifier": "GREATER_THAN",
+			"value":    map[string]interface{}{"value": 60},
+		},
+	)
+
+	doc, err := source.Export(ctx, []*models.SavedFilter{
+		{ID: 5, Mode: models.FilterModeScenes, Name: "Favourites", Filter: filter},
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	wantExported := makeFilter(t,
+		map[string]interface{}{
+			"type":     "tags",
+			"modifier": "INCLUDES_ALL",
+			"value": map[string]interface{}{
+				"items": []interface{}{
+					map[string]interface{}{"name": "Outdoor"},
+					map[string]interface{}{"name": "Renamed"},
+					map[string]interface{}{"name": "Deleted"},
+				},
+				"depth": 0,
+			},
+		},
+		map[string]interface{}{
+			"type":     "parent_studios",
+			"modifier": "INCLUDES",
+			"value":    []interface{}{map[string]interface{}{"name": "Studio"}},
+		},
+		map[string]interface{}{
+			"type":     "rating100",
+			"modifier": "GREATER_THAN",
+			"value":    map[string]interface{}{"value": 60},
+		},
+	)
+	assert.Equal(t, DocumentVersion, doc.Version)
+	assert.Equal(t, []DocumentFilter{
+		{Mode: models.FilterModeScenes, Name: "Favourites", Filter: wantExported},
+	}, doc.Filters)
+
+	destination := &Resolver{
+		TagFinder: &testTagFinder{
+			tags: []*models.Tag{
+				{ID: 10, Name: "Outside"},
+				{ID: 11, Name: "Renamed"},
+			},
+			aliases: map[string]int{"Outdoor": 10},
+		},
+		StudioFinder: &testStudioFinder{studios: []*models.Studio{
+			{ID: 12, Name: sql.NullString{String: "Studio", Valid: true}},
+		}},
+	}
+
+	imported, missing, err := destination.Import(ctx, doc)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	wantImported := makeFilter(t,
+		map[string]interface{}{
+			"type":     "tags",
+			"modifier": "INCLUDES_ALL",
+			"value": map[string]interface{}{
+				"items": []interface{}{item("10", "Outside"), item("11", "Renamed")},
+				"depth": 0,
+			},
+		},
+		map[string]interface{}{
+			"type":     "parent_studios",
+			"modifier": "INCLUDES",
+			"value":    []interface{}{item("12", "Studio")},
+		},
+		map[string]interface{}{
+			"type":     "rating100",
+			"modifier": "GREATER_THAN",
+			"value":    map[string]interface{}{"value": 60},
+		},
+	)
+	assert.Equal(t, []models.SavedFilter{
+		{Mode: models.FilterModeScenes, Name: "Favourites", Filter: wantImported},
+	}, imported)
+	assert.Equal(t, []string{`tag "Deleted"`}, missing)
+}
+
+func TestParseDocument(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{"valid", `{"version":1,"filters":[{"mode":"SCENES","name":"a","filter":"{}"}]}`, false},
+		{"no version", `{"filters":[]}`, true},
+		{"later version", `{"version":2,"filters":[]}`, true},
+		{"invalid mode", `{"version":1,"filters":[{"mode":"INVALID","name":"a","filter":"{}"}]}`, true},
+		{"display options", `{"version":1,"filters":[{"mode":"SCENES","name":"a","filter":"{}","display_options":{"display_mode":"WALL","per_page":-1}}]}`, false},
+		{"invalid display options", `{"version":1,"filters":[{"mode":"SCENES","name":"a","filter":"{}","display_options":{"zoom_index":-1}}]}`, true},
+		{"invalid json", `{`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseDocument([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseDocument() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -36,6 +36,14 @@ func (d *FileDeleter) MarkGeneratedFiles(scene *models.Scene) error {
 		}
 	}
 
+	chaptersFolder := d.Paths.Scene.GetChapterThumbnailDir(sceneHash)
+	exists, _ = fsutil.FileExists(chaptersFolder)
+	if exists {
+		if err := d.Dirs([]string{chaptersFolder}); err != nil {
+			return err
+		}
+	}
+
 	var files []string
 
 	thumbPath := d.Paths.Scene.GetThumbnailScreenshotPath(sceneHash)
@@ -80,6 +88,18 @@ func (d *FileDeleter) MarkGeneratedFiles(scene *models.Scene) error {
 		files = append(files, vttPath)
 	}
 
+	hoverStripPath := d.Paths.Scene.GetHoverStripPath(sceneHash)
+	exists, _ = fsutil.FileExists(hoverStripPath)
+	if exists {
+		files = append(files, hoverStripPath)
+	}
+
+	hoverStripVttPath := d.Paths.Scene.GetHoverStripVttPath(sceneHash)
+	exists, _ = fsutil.FileExists(hoverStripVttPath)
+	if exists {
+		files = append(files, hoverStripVttPath)
+	}
+
 	heatmapPath := d.Paths.Scene.GetInteractiveHeatmapPath(sceneHash)
 	exists, _ = fsutil.FileExists(heatmapPath)
 	if exists {
@@ -184,11 +204,12 @@ func (s *Service) deleteFiles(ctx context.Context, scene *models.Scene, fileDele
 
 		// don't delete files in zip archives
 		if f.ZipFileID == nil {
-			funscriptPath := video.GetFunscriptPath(f.Path)
-			funscriptExists, _ := fsutil.FileExists(funscriptPath)
-			if funscriptExists {
-				if err := fileDeleter.Files([]string{funscriptPath}); err != nil {
-					return err
+			for _, funscriptPath := range funscriptPaths(f.Path) {
+				funscriptExists, _ := fsutil.FileExists(funscriptPath)
+				if funscriptExists {
+					if err := fileDeleter.LibraryFiles([]string{funscriptPath}); err != nil {
+						return err
+					}
 				}
 			}
 		}
@@ -197,6 +218,17 @@ func (s *Service) deleteFiles(ctx context.Context, scene *models.Scene, fileDele
 	return nil
 }
 
+// funscriptPaths returns the possible paths of the funscripts of the video
+// file, including the backup and multi-axis scripts.
+func funscriptPaths(path string) []string {
+	ret := append(video.GetInteractiveScriptPaths(path), video.GetFunscriptBackupPath(path))
+	for _, axis := range video.FunscriptAxes {
+		ret = append(ret, video.GetFunscriptAxisPath(path, axis))
+	}
+
+	return ret
+}
+
 // DestroyMarker deletes the scene marker from the database and returns a
 // function that removes the generated files, to be executed after the
 // transaction is successfully committed.
@@ -0,0 +1,93 @@
+package scene
+
+import (
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// DuplicateComparison describes how the primary file of a scene compares to
+// the primary files of the other scenes in its group of duplicates. Ties are
+// true for all of the tied scenes.
+type DuplicateComparison struct {
+	SceneID           int
+	HighestResolution bool
+	HighestBitrate    bool
+	LargestSize       bool
+	LongestDuration   bool
+	// Recommended is true for the single scene of the group recommended to
+	// keep: the highest resolution, then the highest bitrate, then the
+	// largest file.
+	Recommended bool
+}
+
+// CompareDuplicates compares the primary files of a group of duplicate
+// scenes. The primary files of the scenes must be loaded. Scenes without a
+// primary file are never the best of the group.
+func CompareDuplicates(scenes []*models.Scene) []DuplicateComparison {
+	files := make([]*file.VideoFile, len(scenes))
+	for i, s := range scenes {
+		files[i] = s.Files.Primary()
+	}
+
+	var (
+		maxResolution int
+		maxBitrate    int64
+		maxSize       int64
+		maxDuration   float64
+		recommended   *file.VideoFile
+	)
+
+	for _, f := range files {
+		if f == nil {
+			continue
+		}
+
+		if r := f.GetMinResolution(); r > maxResolution {
+			maxResolution = r
+		}
+		if f.BitRate > maxBitrate {
+			maxBitrate = f.BitRate
+		}
+		if f.Size > maxSize {
+			maxSize = f.Size
+		}
+		if f.Duration > maxDuration {
+			maxDuration = f.Duration
+		}
+
+		if recommended == nil || betterDuplicate(f, recommended) {
+			recommended = f
+		}
+	}
+
+	ret := make([]DuplicateComparison, len(scenes))
+	for i, s := range scenes {
+		ret[i].SceneID = s.ID
+
+		f := files[i]
+		if f == nil {
+			continue
+		}
+
+		ret[i].HighestResolution = f.GetMinResolution() == maxResolution
+		ret[i].HighestBitrate = f.BitRate == maxBitrate
+		ret[i].LargestSize = f.Size == maxSize
+		ret[i].LongestDuration = f.Duration == maxDuration
+		ret[i].Recommended = f == recommended
+	}
+
+	return ret
+}
+
+// betterDuplicate returns true if f is a better copy to keep than other.
+func betterDuplicate(f, other *file.VideoFile) bool {
+	if r, otherR := f.GetMinResolution(), other.GetMinResolution(); r != otherR {
+		return r > otherR
+	}
+
+	if f.BitRate != other.BitRate {
+		return f.BitRate > other.BitRate
+	}
+
+	return f.Size > other.Size
+}
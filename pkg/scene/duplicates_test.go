@@ -0,0 +1,89 @@
+package scene
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareDuplicates(t *testing.T) {
+	makeScene := func(id int, f *file.VideoFile) *models.Scene {
+		var files []*file.VideoFile
+		if f != nil {
+			files = append(files, f)
+		}
+
+		return &models.Scene{
+			ID:    id,
+			Files: models.NewRelatedVideoFiles(files),
+		}
+	}
+
+	makeFile := func(height int, bitrate int64, size int64, duration float64) *file.VideoFile {
+		return &file.VideoFile{
+			BaseFile: &file.BaseFile{Size: size},
+			Width:    height * 16 / 9,
+			Height:   height,
+			BitRate:  bitrate,
+			Duration: duration,
+		}
+	}
+
+	tests := []struct {
+		name   string
+		scenes []*models.Scene
+		want   []DuplicateComparison
+	}{
+		{
+			"highest resolution recommended",
+			[]*models.Scene{
+				makeScene(1, makeFile(720, 8000, 2000, 600)),
+				makeScene(2, makeFile(1080, 4000, 1000, 590)),
+			},
+			[]DuplicateComparison{
+				{SceneID: 1, HighestBitrate: true, LargestSize: true, LongestDuration: true},
+				{SceneID: 2, HighestResolution: true, Recommended: true},
+			},
+		},
+		{
+			"bitrate breaks resolution tie",
+			[]*models.Scene{
+				makeScene(1, makeFile(1080, 4000, 2000, 600)),
+				makeScene(2, makeFile(1080, 6000, 1000, 600)),
+			},
+			[]DuplicateComparison{
+				{SceneID: 1, HighestResolution: true, LargestSize: true, LongestDuration: true},
+				{SceneID: 2, HighestResolution: true, HighestBitrate: true, LongestDuration: true, Recommended: true},
+			},
+		},
+		{
+			"first of equal files recommended",
+			[]*models.Scene{
+				makeScene(1, makeFile(1080, 4000, 1000, 600)),
+				makeScene(2, makeFile(1080, 4000, 1000, 600)),
+			},
+			[]DuplicateComparison{
+				{SceneID: 1, HighestResolution: true, HighestBitrate: true, LargestSize: true, LongestDuration: true, Recommended: true},
+				{SceneID: 2, HighestResolution: true, HighestBitrate: true, LargestSize: true, LongestDuration: true},
+			},
+		},
+		{
+			"scene without file",
+			[]*models.Scene{
+				makeScene(1, nil),
+				makeScene(2, makeFile(480, 1000, 500, 600)),
+			},
+			[]DuplicateComparison{
+				{SceneID: 1},
+				{SceneID: 2, HighestResolution: true, HighestBitrate: true, LargestSize: true, LongestDuration: true, Recommended: true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, CompareDuplicates(tt.scenes))
+		})
+	}
+}
@@ -43,6 +43,9 @@ func ToBasicJSON(ctx context.Context, reader CoverGetter, scene *models.Scene) (
 		URL:       scene.URL,
 		Details:   scene.Details,
 		Director:  scene.Director,
+		Location:  scene.Location,
+		Latitude:  scene.Latitude,
+		Longitude: scene.Longitude,
 		CreatedAt: json.JSONTime{Time: scene.CreatedAt},
 		UpdatedAt: json.JSONTime{Time: scene.UpdatedAt},
 	}
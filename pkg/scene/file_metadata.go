@@ -0,0 +1,88 @@
+package scene
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+type FileMetadataPerformerFinder interface {
+	FindByNames(ctx context.Context, names []string, nocase bool) ([]*models.Performer, error)
+}
+
+type FileMetadataStudioFinder interface {
+	FindByName(ctx context.Context, name string, nocase bool) (*models.Studio, error)
+}
+
+// separates the names of multiple performers in the artist tag
+var artistSeparatorRE = regexp.MustCompile(`\s*(?:[,;/]|\s&\s)\s*`)
+
+// splitArtists returns the names in the artist tag of a file.
+func splitArtists(artist string) []string {
+	var ret []string
+	for _, name := range artistSeparatorRE.Split(artist, -1) {
+		if name = strings.TrimSpace(name); name != "" {
+			ret = append(ret, name)
+		}
+	}
+
+	return ret
+}
+
+// parseFileMetadataDate returns the date of the date tag of a file, which
+// may be a full timestamp. Returns nil if the date is not a full date.
+func parseFileMetadataDate(s string) *models.Date {
+	const dateLen = len("2006-01-02")
+
+	s = strings.TrimSpace(s)
+	if len(s) < dateLen {
+		return nil
+	}
+
+	t, err := time.Parse("2006-01-02", s[:dateLen])
+	if err != nil {
+		return nil
+	}
+
+	return &models.Date{Time: t}
+}
+
+// applyFileMetadata sets the title, details and date of the new scene from
+// the metadata embedded in its file. The performers in the artist tag and the
+// studio in the album artist tag are set if they exist.
+func (h *ScanHandler) applyFileMetadata(ctx context.Context, s *models.Scene, m *file.VideoMetadata) error {
+	s.Title = strings.TrimSpace(m.Title)
+	s.Details = strings.TrimSpace(m.Details)
+	s.Date = parseFileMetadataDate(m.Date)
+
+	if names := splitArtists(m.Artist); len(names) > 0 {
+		performers, err := h.PerformerFinder.FindByNames(ctx, names, true)
+		if err != nil {
+			return fmt.Errorf("finding performers: %w", err)
+		}
+
+		var ids []int
+		for _, p := range performers {
+			ids = append(ids, p.ID)
+		}
+		s.PerformerIDs = models.NewRelatedIDs(ids)
+	}
+
+	if name := strings.TrimSpace(m.AlbumArtist); name != "" {
+		studio, err := h.StudioFinder.FindByName(ctx, name, true)
+		if err != nil {
+			return fmt.Errorf("finding studio: %w", err)
+		}
+
+		if studio != nil {
+			s.StudioID = &studio.ID
+		}
+	}
+
+	return nil
+}
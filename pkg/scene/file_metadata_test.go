@@ -0,0 +1,47 @@
+package scene
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitArtists(t *testing.T) {
+	tests := []struct {
+		artist string
+		want   []string
+	}{
+		{"", nil},
+		{"Performer", []string{"Performer"}},
+		{"A, B; C / D", []string{"A", "B", "C", "D"}},
+		{"A & B", []string{"A", "B"}},
+		{"AT&T", []string{"AT&T"}},
+		{" A ,, B ", []string{"A", "B"}},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, splitArtists(tt.artist), tt.artist)
+	}
+}
+
+func TestParseFileMetadataDate(t *testing.T) {
+	date := &models.Date{Time: time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC)}
+
+	tests := []struct {
+		s    string
+		want *models.Date
+	}{
+		{"2021-03-04", date},
+		{"2021-03-04T10:11:12Z", date},
+		{" 2021-03-04 ", date},
+		{"2021", nil},
+		{"04/03/2021", nil},
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, parseFileMetadataDate(tt.s), tt.s)
+	}
+}
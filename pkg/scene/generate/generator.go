@@ -44,7 +44,12 @@ type ScenePaths interface {
 	GetSpriteImageFilePath(checksum string) string
 	GetSpriteVttFilePath(checksum string) string
 
+	GetHoverStripPath(checksum string) string
+	GetHoverStripVttPath(checksum string) string
+
 	GetTranscodePath(checksum string) string
+
+	GetChapterThumbnailPath(checksum string, start float64) string
 }
 
 type Generator struct {
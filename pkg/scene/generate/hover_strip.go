@@ -0,0 +1,200 @@
+package generate
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+const (
+	hoverStripWidth = 240
+
+	// DefaultHoverStripSegments is the default number of micro-clips in a
+	// hover strip.
+	DefaultHoverStripSegments = 24
+	// DefaultHoverStripSegmentDuration is the default duration of each
+	// micro-clip of a hover strip, in seconds.
+	DefaultHoverStripSegmentDuration = 1.0
+)
+
+// HoverStripOptions are the options used to generate hover strips.
+type HoverStripOptions struct {
+	Segments        int
+	SegmentDuration float64
+	ExcludeStart    string
+	ExcludeEnd      string
+
+	Preset string
+}
+
+// hoverStripSegment is a micro-clip of the hover strip.
+type hoverStripSegment struct {
+	// Time of the clip in the scene
+	SceneStart float64
+	// Time of the clip in the hover strip
+	StripStart float64
+	Duration   float64
+}
+
+// getSegments returns the micro-clips sampled evenly from the scene. Fewer
+// segments are returned if the scene is too short for the requested number
+// of clips.
+func (o HoverStripOptions) getSegments(videoDuration float64) []hoverStripSegment {
+	segmentDuration := o.SegmentDuration
+	if segmentDuration < minSegmentDuration {
+		segmentDuration = minSegmentDuration
+	}
+
+	segments := o.Segments
+	if maxSegments := int(math.Floor(videoDuration / segmentDuration)); segments > maxSegments {
+		segments = maxSegments
+	}
+
+	// a single clip of the whole scene
+	if segments <= 1 {
+		return []hoverStripSegment{{Duration: videoDuration}}
+	}
+
+	previewOptions := PreviewOptions{
+		Segments:     segments,
+		ExcludeStart: o.ExcludeStart,
+		ExcludeEnd:   o.ExcludeEnd,
+	}
+	stepSize, offset := previewOptions.getStepSizeAndOffset(videoDuration)
+
+	// clips must not overlap
+	if segmentDuration > stepSize {
+		segmentDuration = stepSize
+	}
+
+	ret := make([]hoverStripSegment, segments)
+	for i := range ret {
+		ret[i] = hoverStripSegment{
+			SceneStart: offset + float64(i)*stepSize,
+			StripStart: float64(i) * segmentDuration,
+			Duration:   segmentDuration,
+		}
+	}
+
+	return ret
+}
+
+// HoverStrip generates a short low resolution video of micro-clips sampled
+// evenly from the scene, and a vtt file mapping the scene times to the clips
+// of the hover strip.
+func (g Generator) HoverStrip(ctx context.Context, input string, videoDuration float64, hash string, options HoverStripOptions, fallback bool) error {
+	lockCtx := g.LockManager.ReadLock(ctx, input)
+	defer lockCtx.Cancel()
+
+	output := g.ScenePaths.GetHoverStripPath(hash)
+	vttOutput := g.ScenePaths.GetHoverStripVttPath(hash)
+	if !g.Overwrite {
+		videoExists, _ := fsutil.FileExists(output)
+		vttExists, _ := fsutil.FileExists(vttOutput)
+		if videoExists && vttExists {
+			return nil
+		}
+	}
+
+	logger.Infof("[generator] generating hover strip for %s", input)
+
+	segments := options.getSegments(videoDuration)
+
+	if err := g.generateFile(lockCtx, g.ScenePaths, mp4Pattern, output, g.hoverStrip(input, segments, options.Preset, fallback)); err != nil {
+		return err
+	}
+
+	if err := g.generateFile(lockCtx, g.ScenePaths, vttPattern, vttOutput, g.hoverStripVTT(filepath.Base(output), videoDuration, segments)); err != nil {
+		return err
+	}
+
+	logger.Debug("created hover strip: ", output)
+
+	return nil
+}
+
+func (g Generator) hoverStrip(input string, segments []hoverStripSegment, preset string, fallback bool) generateFn {
+	return func(lockCtx *fsutil.LockContext, tmpFn string) error {
+		if len(segments) == 1 {
+			return g.previewVideoChunk(lockCtx, input, previewChunkOptions{
+				StartTime:  segments[0].SceneStart,
+				Duration:   segments[0].Duration,
+				OutputPath: tmpFn,
+				Width:      hoverStripWidth,
+				Preset:     preset,
+			}, fallback)
+		}
+
+		// a list of tmp files used during the hover strip generation
+		var tmpFiles []string
+
+		// remove tmpFiles when done
+		defer func() { removeFiles(tmpFiles) }()
+
+		for _, s := range segments {
+			chunkFile, err := g.tempFile(g.ScenePaths, mp4Pattern)
+			if err != nil {
+				return fmt.Errorf("generating hover strip chunk file: %w", err)
+			}
+
+			tmpFiles = append(tmpFiles, chunkFile.Name())
+
+			chunkOptions := previewChunkOptions{
+				StartTime:  s.SceneStart,
+				Duration:   s.Duration,
+				OutputPath: chunkFile.Name(),
+				Width:      hoverStripWidth,
+				Preset:     preset,
+			}
+
+			if err := g.previewVideoChunk(lockCtx, input, chunkOptions, fallback); err != nil {
+				return err
+			}
+		}
+
+		concatFilePath, err := g.generateConcatFile(tmpFiles)
+		if concatFilePath != "" {
+			tmpFiles = append(tmpFiles, concatFilePath)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		return g.previewVideoChunkCombine(lockCtx, concatFilePath, tmpFn)
+	}
+}
+
+// hoverStripVTT writes a vtt file with a cue for each micro-clip. Each cue
+// covers the scene times closest to its clip, and its payload is the media
+// fragment of the clip in the hover strip.
+func (g Generator) hoverStripVTT(stripName string, videoDuration float64, segments []hoverStripSegment) generateFn {
+	return func(lockCtx *fsutil.LockContext, tmpFn string) error {
+		vttLines := []string{"WEBVTT", ""}
+		for i, s := range segments {
+			start := 0.0
+			if i > 0 {
+				start = s.SceneStart
+			}
+
+			end := videoDuration
+			if i < len(segments)-1 {
+				end = segments[i+1].SceneStart
+			}
+
+			vttLines = append(vttLines, utils.GetVTTTime(start)+" --> "+utils.GetVTTTime(end))
+			vttLines = append(vttLines, fmt.Sprintf("%s#t=%.3f,%.3f", stripName, s.StripStart, s.StripStart+s.Duration))
+			vttLines = append(vttLines, "")
+		}
+		vtt := strings.Join(vttLines, "\n")
+
+		return os.WriteFile(tmpFn, []byte(vtt), 0644)
+	}
+}
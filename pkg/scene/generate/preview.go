@@ -32,6 +32,10 @@ type PreviewOptions struct {
 
 	Preset string
 
+	// Profile is the transcode profile used to encode the preview. The
+	// built-in libx264 arguments are used if nil.
+	Profile *ffmpeg.TranscodeProfile
+
 	Audio bool
 }
 
@@ -129,6 +133,7 @@ func (g *Generator) previewVideo(input string, videoDuration float64, options Pr
 				OutputPath: chunkFile.Name(),
 				Audio:      options.Audio,
 				Preset:     options.Preset,
+				Profile:    options.Profile,
 			}
 
 			if err := g.previewVideoChunk(lockCtx, input, chunkOptions, fallback); err != nil {
@@ -158,6 +163,7 @@ func (g *Generator) previewVideoSingle(input string, videoDuration float64, opti
 			OutputPath: tmpFn,
 			Audio:      options.Audio,
 			Preset:     options.Preset,
+			Profile:    options.Profile,
 		}
 
 		return g.previewVideoChunk(lockCtx, input, chunkOptions, fallback)
@@ -168,26 +174,21 @@ type previewChunkOptions struct {
 	StartTime  float64
 	Duration   float64
 	OutputPath string
-	Audio      bool
-	Preset     string
+	// Width of the chunk. scenePreviewWidth is used if zero.
+	Width   int
+	Audio   bool
+	Preset  string
+	Profile *ffmpeg.TranscodeProfile
 }
 
 func (g Generator) previewVideoChunk(lockCtx *fsutil.LockContext, fn string, options previewChunkOptions, fallback bool) error {
-	var videoFilter ffmpeg.VideoFilter
-	videoFilter = videoFilter.ScaleWidth(scenePreviewWidth)
-
-	var videoArgs ffmpeg.Args
-	videoArgs = videoArgs.VideoFilter(videoFilter)
+	width := options.Width
+	if width == 0 {
+		width = scenePreviewWidth
+	}
 
-	videoArgs = append(videoArgs,
-		"-pix_fmt", "yuv420p",
-		"-profile:v", "high",
-		"-level", "4.2",
-		"-preset", options.Preset,
-		"-crf", "21",
-		"-threads", "4",
-		"-strict", "-2",
-	)
+	var videoFilter ffmpeg.VideoFilter
+	videoFilter = videoFilter.ScaleWidth(width)
 
 	trimOptions := transcoder.TranscodeOptions{
 		OutputPath: options.OutputPath,
@@ -196,9 +197,29 @@ func (g Generator) previewVideoChunk(lockCtx *fsutil.LockContext, fn string, opt
 
 		XError:   !fallback,
 		SlowSeek: fallback,
+	}
+
+	if options.Profile != nil {
+		trimOptions.InputArgs = options.Profile.InputArgs()
+		trimOptions.VideoCodec = options.Profile.Encoder()
+		trimOptions.VideoArgs = trimOptions.VideoArgs.VideoFilter(options.Profile.HWUpload(videoFilter))
+		trimOptions.VideoArgs = trimOptions.VideoArgs.AppendArgs(options.Profile.VideoArgs(width))
+	} else {
+		var videoArgs ffmpeg.Args
+		videoArgs = videoArgs.VideoFilter(videoFilter)
+
+		videoArgs = append(videoArgs,
+			"-pix_fmt", "yuv420p",
+			"-profile:v", "high",
+			"-level", "4.2",
+			"-preset", options.Preset,
+			"-crf", "21",
+			"-threads", "4",
+			"-strict", "-2",
+		)
 
-		VideoCodec: ffmpeg.VideoCodecLibX264,
-		VideoArgs:  videoArgs,
+		trimOptions.VideoCodec = ffmpeg.VideoCodecLibX264
+		trimOptions.VideoArgs = videoArgs
 	}
 
 	if options.Audio {
@@ -101,3 +101,29 @@ func (g Generator) screenshot(input string, options screenshotOptions) generateF
 		return g.generate(lockCtx, args)
 	}
 }
+
+// ChapterThumbnail generates the thumbnail of the chapter starting at start
+// seconds.
+func (g Generator) ChapterThumbnail(ctx context.Context, input string, hash string, start float64) error {
+	lockCtx := g.LockManager.ReadLock(ctx, input)
+	defer lockCtx.Cancel()
+
+	output := g.ScenePaths.GetChapterThumbnailPath(hash, start)
+	if !g.Overwrite {
+		if exists, _ := fsutil.FileExists(output); exists {
+			return nil
+		}
+	}
+
+	if err := g.generateFile(lockCtx, g.ScenePaths, jpgPattern, output, g.screenshot(input, screenshotOptions{
+		Time:    start,
+		Quality: thumbnailQuality,
+		Width:   thumbnailWidth,
+	})); err != nil {
+		return err
+	}
+
+	logger.Debug("created chapter thumbnail: ", output)
+
+	return nil
+}
@@ -86,6 +86,9 @@ func (i *Importer) sceneJSONToScene(sceneJSON jsonschema.Scene) models.Scene {
 		Details:      sceneJSON.Details,
 		Director:     sceneJSON.Director,
 		URL:          sceneJSON.URL,
+		Location:     sceneJSON.Location,
+		Latitude:     sceneJSON.Latitude,
+		Longitude:    sceneJSON.Longitude,
 		PerformerIDs: models.NewRelatedIDs([]int{}),
 		TagIDs:       models.NewRelatedIDs([]int{}),
 		GalleryIDs:   models.NewRelatedIDs([]int{}),
@@ -45,9 +45,25 @@ func MigrateHash(p *paths.Paths, oldHash string, newHash string) {
 	migrateSceneFiles(oldPath, newPath)
 	migrateVttFile(newVttPath, oldPath, newPath)
 
+	oldPath = scenePaths.GetHoverStripPath(oldHash)
+	newPath = scenePaths.GetHoverStripPath(newHash)
+	migrateSceneFiles(oldPath, newPath)
+
+	oldVttPath = scenePaths.GetHoverStripVttPath(oldHash)
+	newVttPath = scenePaths.GetHoverStripVttPath(newHash)
+	migrateSceneFiles(oldVttPath, newVttPath)
+	// hover strips are optional
+	if exists, _ := fsutil.FileExists(newVttPath); exists {
+		migrateVttFile(newVttPath, oldPath, newPath)
+	}
+
 	oldPath = scenePaths.GetInteractiveHeatmapPath(oldHash)
 	newPath = scenePaths.GetInteractiveHeatmapPath(newHash)
 	migrateSceneFiles(oldPath, newPath)
+
+	oldPath = scenePaths.GetChapterThumbnailDir(oldHash)
+	newPath = scenePaths.GetChapterThumbnailDir(newHash)
+	migrateSceneFiles(oldPath, newPath)
 }
 
 func migrateSceneFiles(oldName, newName string) {
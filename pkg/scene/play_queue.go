@@ -0,0 +1,40 @@
+package scene
+
+import (
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// ReorderPlayQueue returns the IDs of the play queue items in their new
+// order. The items in itemIDs are moved to the front of the queue in the
+// given order, followed by the remaining items in their existing order.
+// Returns an error if an item ID is not in the queue or is repeated.
+func ReorderPlayQueue(items []*models.PlayQueueItem, itemIDs []int) ([]int, error) {
+	inQueue := make(map[int]bool, len(items))
+	for _, item := range items {
+		inQueue[item.ID] = true
+	}
+
+	moved := make(map[int]bool, len(itemIDs))
+	ret := make([]int, 0, len(items))
+	for _, id := range itemIDs {
+		if !inQueue[id] {
+			return nil, fmt.Errorf("play queue item %d not found", id)
+		}
+		if moved[id] {
+			return nil, fmt.Errorf("play queue item %d is repeated", id)
+		}
+
+		moved[id] = true
+		ret = append(ret, id)
+	}
+
+	for _, item := range items {
+		if !moved[item.ID] {
+			ret = append(ret, item.ID)
+		}
+	}
+
+	return ret, nil
+}
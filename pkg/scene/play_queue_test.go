@@ -0,0 +1,41 @@
+package scene
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReorderPlayQueue(t *testing.T) {
+	items := []*models.PlayQueueItem{
+		{ID: 1},
+		{ID: 2},
+		{ID: 3},
+		{ID: 4},
+	}
+
+	tests := []struct {
+		name    string
+		itemIDs []int
+		want    []int
+		wantErr bool
+	}{
+		{"full order", []int{4, 3, 2, 1}, []int{4, 3, 2, 1}, false},
+		{"partial order", []int{3, 1}, []int{3, 1, 2, 4}, false},
+		{"empty", nil, []int{1, 2, 3, 4}, false},
+		{"unknown item", []int{5}, nil, true},
+		{"repeated item", []int{2, 2}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReorderPlayQueue(items, tt.itemIDs)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ReorderPlayQueue() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
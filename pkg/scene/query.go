@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/stashapp/stash/pkg/job"
@@ -62,6 +63,37 @@ func Query(ctx context.Context, qb Queryer, sceneFilter *models.SceneFilterType,
 	return scenes, nil
 }
 
+// CountInbox returns the number of scenes in the inbox.
+func CountInbox(ctx context.Context, qb Queryer) (int, error) {
+	inbox := true
+	result, err := qb.Query(ctx, QueryOptions(&models.SceneFilterType{
+		Inbox: &inbox,
+	}, nil, true))
+	if err != nil {
+		return 0, err
+	}
+
+	return result.Count, nil
+}
+
+// CountByTagID returns the number of scenes tagged with the tag. If
+// includeMarkerTags is true, scenes with markers tagged with the tag are
+// included.
+func CountByTagID(ctx context.Context, qb Queryer, tagID int, includeMarkerTags bool) (int, error) {
+	result, err := qb.Query(ctx, QueryOptions(&models.SceneFilterType{
+		Tags: &models.HierarchicalMultiCriterionInput{
+			Value:    []string{strconv.Itoa(tagID)},
+			Modifier: models.CriterionModifierIncludes,
+		},
+		IncludeMarkerTags: &includeMarkerTags,
+	}, nil, true))
+	if err != nil {
+		return 0, err
+	}
+
+	return result.Count, nil
+}
+
 func BatchProcess(ctx context.Context, reader Queryer, sceneFilter *models.SceneFilterType, findFilter *models.FindFilterType, fn func(scene *models.Scene) error) error {
 	const batchSize = 1000
 
@@ -25,6 +25,7 @@ type CreatorUpdater interface {
 	Creator
 	UpdatePartial(ctx context.Context, id int, updatedScene models.ScenePartial) (*models.Scene, error)
 	AddFileID(ctx context.Context, id int, fileID file.ID) error
+	CountByTrailerFileID(ctx context.Context, fileID file.ID) (int, error)
 	models.VideoFileLoader
 }
 
@@ -42,6 +43,17 @@ type ScanHandler struct {
 
 	FileNamingAlgorithm models.HashAlgorithm
 	Paths               *paths.Paths
+
+	// UseFileMetadata sets the fields of new scenes from the metadata
+	// embedded in their file. PerformerFinder and StudioFinder are required
+	// if set.
+	UseFileMetadata bool
+	PerformerFinder FileMetadataPerformerFinder
+	StudioFinder    FileMetadataStudioFinder
+
+	// Inbox adds new scenes to the inbox, to be reviewed before they are
+	// approved into the library.
+	Inbox bool
 }
 
 func (h *ScanHandler) validate() error {
@@ -63,6 +75,9 @@ func (h *ScanHandler) validate() error {
 	if h.Paths == nil {
 		return errors.New("Paths is required")
 	}
+	if h.UseFileMetadata && (h.PerformerFinder == nil || h.StudioFinder == nil) {
+		return errors.New("PerformerFinder and StudioFinder are required to use file metadata")
+	}
 
 	return nil
 }
@@ -103,13 +118,31 @@ func (h *ScanHandler) Handle(ctx context.Context, f file.File, oldFile file.File
 			return err
 		}
 	} else {
+		// don't create scenes for trailers attached to other scenes
+		trailers, err := h.CreatorUpdater.CountByTrailerFileID(ctx, f.Base().ID)
+		if err != nil {
+			return fmt.Errorf("counting scenes with trailer: %w", err)
+		}
+
+		if trailers > 0 {
+			logger.Debugf("%s is the trailer of a scene. Not creating a scene", f.Base().Path)
+			return nil
+		}
+
 		// create a new scene
 		now := time.Now()
 		newScene := &models.Scene{
+			Inbox:     h.Inbox,
 			CreatedAt: now,
 			UpdatedAt: now,
 		}
 
+		if h.UseFileMetadata && videoFile.Metadata != nil {
+			if err := h.applyFileMetadata(ctx, newScene, videoFile.Metadata); err != nil {
+				return fmt.Errorf("setting scene metadata from file: %w", err)
+			}
+		}
+
 		logger.Infof("%s doesn't exist. Creating new scene...", f.Base().Path)
 
 		if err := h.CreatorUpdater.Create(ctx, newScene, []file.ID{videoFile.ID}); err != nil {
@@ -42,8 +42,9 @@ type Repository interface {
 }
 
 type MarkerRepository interface {
-	MarkerFinder
+	MarkerSnapshotFinder
 	MarkerDestroyer
+	MarkerCreator
 
 	Update(ctx context.Context, updatedObject models.SceneMarker) (*models.SceneMarker, error)
 }
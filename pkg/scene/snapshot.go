@@ -0,0 +1,171 @@
+package scene
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// Snapshot is the state of a scene and its relationships. It is used to
+// revert changes made to the scene, or to recreate the scene after it has
+// been destroyed.
+type Snapshot struct {
+	Scene        models.Scene          `json:"scene"`
+	GalleryIDs   []int                 `json:"gallery_ids"`
+	TagIDs       []int                 `json:"tag_ids"`
+	PerformerIDs []int                 `json:"performer_ids"`
+	Movies       []models.MoviesScenes `json:"movies"`
+	StashIDs     []models.StashID      `json:"stash_ids"`
+	// Files of the scene, primary file first
+	FileIDs []file.ID `json:"file_ids"`
+	Cover   []byte    `json:"cover,omitempty"`
+	// Only populated by LoadMarkers
+	Markers []MarkerSnapshot `json:"markers,omitempty"`
+}
+
+// MarkerSnapshot is the state of a scene marker and its tags.
+type MarkerSnapshot struct {
+	Marker models.SceneMarker `json:"marker"`
+	TagIDs []int              `json:"tag_ids"`
+}
+
+type MarkerSnapshotFinder interface {
+	MarkerFinder
+	GetTagIDs(ctx context.Context, markerID int) ([]int, error)
+}
+
+type MarkerCreator interface {
+	Create(ctx context.Context, newSceneMarker models.SceneMarker) (*models.SceneMarker, error)
+	UpdateTags(ctx context.Context, markerID int, tagIDs []int) error
+}
+
+type SnapshotRestorer interface {
+	Creator
+	CoverUpdater
+}
+
+// TakeSnapshot returns a snapshot of the scene, including its relationships
+// and cover image.
+func TakeSnapshot(ctx context.Context, r models.SceneReader, s *models.Scene) (*Snapshot, error) {
+	if err := s.LoadRelationships(ctx, r); err != nil {
+		return nil, fmt.Errorf("loading scene relationships: %w", err)
+	}
+
+	cover, err := r.GetCover(ctx, s.ID)
+	if err != nil {
+		return nil, fmt.Errorf("getting scene cover: %w", err)
+	}
+
+	ret := &Snapshot{
+		Scene:        *s,
+		GalleryIDs:   s.GalleryIDs.List(),
+		TagIDs:       s.TagIDs.List(),
+		PerformerIDs: s.PerformerIDs.List(),
+		Movies:       s.Movies.List(),
+		StashIDs:     s.StashIDs.List(),
+		Cover:        cover,
+	}
+
+	for _, f := range s.Files.List() {
+		ret.FileIDs = append(ret.FileIDs, f.ID)
+	}
+
+	return ret, nil
+}
+
+// LoadMarkers adds the markers of the scene to the snapshot. Markers are
+// only required when the scene is to be recreated.
+func (s *Snapshot) LoadMarkers(ctx context.Context, r MarkerSnapshotFinder) error {
+	markers, err := r.FindBySceneID(ctx, s.Scene.ID)
+	if err != nil {
+		return fmt.Errorf("finding scene markers: %w", err)
+	}
+
+	s.Markers = nil
+	for _, m := range markers {
+		tagIDs, err := r.GetTagIDs(ctx, m.ID)
+		if err != nil {
+			return fmt.Errorf("getting scene marker tags: %w", err)
+		}
+
+		s.Markers = append(s.Markers, MarkerSnapshot{
+			Marker: *m,
+			TagIDs: tagIDs,
+		})
+	}
+
+	return nil
+}
+
+// Partial returns the partial which reverts the scene to the state of the
+// snapshot. Play history and the o-counter are not reverted.
+func (s *Snapshot) Partial() models.ScenePartial {
+	scene := s.Scene
+	ret := models.NewScenePartial()
+
+	ret.Title = models.NewOptionalString(scene.Title)
+	ret.Code = models.NewOptionalString(scene.Code)
+	ret.Details = models.NewOptionalString(scene.Details)
+	ret.Director = models.NewOptionalString(scene.Director)
+	ret.URL = models.NewOptionalString(scene.URL)
+	ret.Organized = models.NewOptionalBool(scene.Organized)
+
+	ret.Date = models.NewOptionalDatePtr(scene.Date)
+	ret.Rating = models.NewOptionalIntPtr(scene.Rating)
+	ret.StudioID = models.NewOptionalIntPtr(scene.StudioID)
+
+	ret.GalleryIDs = &models.UpdateIDs{IDs: s.GalleryIDs, Mode: models.RelationshipUpdateModeSet}
+	ret.TagIDs = &models.UpdateIDs{IDs: s.TagIDs, Mode: models.RelationshipUpdateModeSet}
+	ret.PerformerIDs = &models.UpdateIDs{IDs: s.PerformerIDs, Mode: models.RelationshipUpdateModeSet}
+	ret.MovieIDs = &models.UpdateMovieIDs{Movies: s.Movies, Mode: models.RelationshipUpdateModeSet}
+	ret.StashIDs = &models.UpdateStashIDs{StashIDs: s.StashIDs, Mode: models.RelationshipUpdateModeSet}
+
+	return ret
+}
+
+// Revert reverts the existing scene to the state of the snapshot.
+func (s *Snapshot) Revert(ctx context.Context, r PartialUpdater) (*models.Scene, error) {
+	return r.UpdatePartial(ctx, s.Scene.ID, s.Partial())
+}
+
+// Restore recreates the scene of the snapshot, along with its cover and
+// markers. The recreated scene is assigned a new ID.
+func (s *Snapshot) Restore(ctx context.Context, r SnapshotRestorer, mr MarkerCreator) (*models.Scene, error) {
+	newScene := s.Scene
+	newScene.ID = 0
+	newScene.GalleryIDs = models.NewRelatedIDs(s.GalleryIDs)
+	newScene.TagIDs = models.NewRelatedIDs(s.TagIDs)
+	newScene.PerformerIDs = models.NewRelatedIDs(s.PerformerIDs)
+	newScene.Movies = models.NewRelatedMovies(s.Movies)
+	newScene.StashIDs = models.NewRelatedStashIDs(s.StashIDs)
+
+	if err := r.Create(ctx, &newScene, s.FileIDs); err != nil {
+		return nil, fmt.Errorf("creating scene: %w", err)
+	}
+
+	if len(s.Cover) > 0 {
+		if err := r.UpdateCover(ctx, newScene.ID, s.Cover); err != nil {
+			return nil, fmt.Errorf("setting scene cover: %w", err)
+		}
+	}
+
+	for _, m := range s.Markers {
+		marker := m.Marker
+		marker.ID = 0
+		marker.SceneID = sql.NullInt64{Int64: int64(newScene.ID), Valid: true}
+
+		created, err := mr.Create(ctx, marker)
+		if err != nil {
+			return nil, fmt.Errorf("creating scene marker: %w", err)
+		}
+
+		if err := mr.UpdateTags(ctx, created.ID, m.TagIDs); err != nil {
+			return nil, fmt.Errorf("setting scene marker tags: %w", err)
+		}
+	}
+
+	return &newScene, nil
+}
@@ -0,0 +1,92 @@
+package scene
+
+import (
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSnapshot_Partial(t *testing.T) {
+	rating := 60
+	snapshot := Snapshot{
+		Scene: models.Scene{
+			ID:     1,
+			Title:  "title",
+			Rating: &rating,
+		},
+		TagIDs: []int{2, 3},
+	}
+
+	got := snapshot.Partial()
+
+	assert.Equal(t, models.NewOptionalString("title"), got.Title)
+	assert.Equal(t, models.NewOptionalInt(rating), got.Rating)
+	// unset values are cleared
+	assert.Equal(t, models.OptionalInt{Set: true, Null: true}, got.StudioID)
+	assert.Equal(t, models.OptionalDate{Set: true, Null: true}, got.Date)
+	assert.Equal(t, &models.UpdateIDs{IDs: []int{2, 3}, Mode: models.RelationshipUpdateModeSet}, got.TagIDs)
+	assert.Equal(t, &models.UpdateIDs{Mode: models.RelationshipUpdateModeSet}, got.PerformerIDs)
+	// play history is not reverted
+	assert.False(t, got.PlayCount.Set)
+	assert.False(t, got.OCounter.Set)
+}
+
+func TestSnapshot_Restore(t *testing.T) {
+	const (
+		oldID    = 1
+		newID    = 2
+		markerID = 3
+	)
+
+	snapshot := &Snapshot{
+		Scene:   models.Scene{ID: oldID, Title: "title"},
+		TagIDs:  []int{4},
+		FileIDs: []file.ID{5},
+		Cover:   []byte{1},
+		Markers: []MarkerSnapshot{
+			{
+				Marker: models.SceneMarker{ID: 6, Title: "marker", SceneID: sql.NullInt64{Int64: oldID, Valid: true}},
+				TagIDs: []int{7},
+			},
+		},
+	}
+
+	// the snapshot must survive being staged as JSON
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshot = &Snapshot{}
+	if err := json.Unmarshal(data, snapshot); err != nil {
+		t.Fatal(err)
+	}
+
+	sceneRW := &mocks.SceneReaderWriter{}
+	markerRW := &mocks.SceneMarkerReaderWriter{}
+
+	sceneRW.On("Create", testCtx, mock.MatchedBy(func(s *models.Scene) bool {
+		return s.ID == 0 && s.Title == "title" && assert.ObjectsAreEqual([]int{4}, s.TagIDs.List())
+	}), []file.ID{5}).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.Scene).ID = newID
+	}).Return(nil).Once()
+	sceneRW.On("UpdateCover", testCtx, newID, []byte{1}).Return(nil).Once()
+	markerRW.On("Create", testCtx, models.SceneMarker{
+		Title:   "marker",
+		SceneID: sql.NullInt64{Int64: newID, Valid: true},
+	}).Return(&models.SceneMarker{ID: markerID}, nil).Once()
+	markerRW.On("UpdateTags", testCtx, markerID, []int{7}).Return(nil).Once()
+
+	got, err := snapshot.Restore(testCtx, sceneRW, markerRW)
+	if assert.NoError(t, err) {
+		assert.Equal(t, newID, got.ID)
+	}
+
+	sceneRW.AssertExpectations(t)
+	markerRW.AssertExpectations(t)
+}
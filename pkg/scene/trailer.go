@@ -0,0 +1,84 @@
+package scene
+
+import (
+	"sort"
+
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+const (
+	DefaultMaxTrailerDuration   = 300
+	DefaultTrailerDurationRatio = 3
+	DefaultTrailerDistance      = 8
+)
+
+type TrailerMatchOptions struct {
+	// Maximum duration in seconds of a trailer
+	MaxTrailerDuration float64
+	// Minimum ratio of the duration of the full scene to the duration of
+	// the trailer
+	MinDurationRatio float64
+	// Maximum phash distance between the trailer and the full scene
+	Distance int
+}
+
+func DefaultTrailerMatchOptions() TrailerMatchOptions {
+	return TrailerMatchOptions{
+		MaxTrailerDuration: DefaultMaxTrailerDuration,
+		MinDurationRatio:   DefaultTrailerDurationRatio,
+		Distance:           DefaultTrailerDistance,
+	}
+}
+
+// TrailerMatch is a short scene which is likely to be a trailer of a full
+// scene.
+type TrailerMatch struct {
+	TrailerID int
+	SceneID   int
+	Distance  int
+}
+
+// MatchTrailers matches short scenes to the full scenes they were cut from.
+//
+// The phash of a scene is generated from frames spread across the whole
+// video, so a trailer made of excerpts of a scene has a phash close to the
+// phash of the scene. Trailers are matched to the full scene with the closest
+// phash, preferring the longest scene when distances are equal. Each trailer
+// is matched to at most one scene.
+func MatchTrailers(hashes []*utils.Phash, options TrailerMatchOptions) []TrailerMatch {
+	var ret []TrailerMatch
+
+	for _, trailer := range hashes {
+		if trailer.Duration <= 0 || trailer.Duration > options.MaxTrailerDuration {
+			continue
+		}
+
+		var best *utils.Phash
+		bestDistance := options.Distance + 1
+		for _, candidate := range hashes {
+			if candidate.SceneID == trailer.SceneID || candidate.Duration < trailer.Duration*options.MinDurationRatio {
+				continue
+			}
+
+			distance := utils.PhashDistance(trailer.Hash, candidate.Hash)
+			if distance < bestDistance || (distance == bestDistance && best != nil && candidate.Duration > best.Duration) {
+				best = candidate
+				bestDistance = distance
+			}
+		}
+
+		if best != nil {
+			ret = append(ret, TrailerMatch{
+				TrailerID: trailer.SceneID,
+				SceneID:   best.SceneID,
+				Distance:  bestDistance,
+			})
+		}
+	}
+
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].Distance < ret[j].Distance
+	})
+
+	return ret
+}
@@ -0,0 +1,74 @@
+package scene
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchTrailers(t *testing.T) {
+	const (
+		fullHash = int64(0x0f0f0f0f0f0f0f0f)
+		// differs from fullHash by 2 bits
+		closeHash = int64(0x0f0f0f0f0f0f0f0c)
+		// differs from fullHash in every bit
+		otherHash = ^fullHash
+	)
+
+	tests := []struct {
+		name   string
+		hashes []*utils.Phash
+		want   []TrailerMatch
+	}{
+		{
+			"match",
+			[]*utils.Phash{
+				{SceneID: 1, Hash: fullHash, Duration: 1800},
+				{SceneID: 2, Hash: closeHash, Duration: 60},
+			},
+			[]TrailerMatch{{TrailerID: 2, SceneID: 1, Distance: 2}},
+		},
+		{
+			"trailer too long",
+			[]*utils.Phash{
+				{SceneID: 1, Hash: fullHash, Duration: 3600},
+				{SceneID: 2, Hash: closeHash, Duration: 600},
+			},
+			nil,
+		},
+		{
+			"scene too short",
+			[]*utils.Phash{
+				{SceneID: 1, Hash: fullHash, Duration: 120},
+				{SceneID: 2, Hash: closeHash, Duration: 60},
+			},
+			nil,
+		},
+		{
+			"distance too large",
+			[]*utils.Phash{
+				{SceneID: 1, Hash: otherHash, Duration: 1800},
+				{SceneID: 2, Hash: fullHash, Duration: 60},
+			},
+			nil,
+		},
+		{
+			"closest scene",
+			[]*utils.Phash{
+				{SceneID: 1, Hash: closeHash, Duration: 1800},
+				{SceneID: 2, Hash: fullHash, Duration: 1200},
+				{SceneID: 3, Hash: fullHash, Duration: 2400},
+				{SceneID: 4, Hash: fullHash, Duration: 60},
+			},
+			[]TrailerMatch{{TrailerID: 4, SceneID: 3, Distance: 0}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MatchTrailers(tt.hashes, DefaultTrailerMatchOptions())
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package scene
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// TrashedFile is a file of a deleted scene that was moved to the trash
+// directory.
+type TrashedFile struct {
+	// File is nil for files which are not in the database, such as
+	// funscripts
+	File         *file.VideoFile `json:"file,omitempty"`
+	OriginalPath string          `json:"original_path"`
+	TrashPath    string          `json:"trash_path"`
+}
+
+// TrashData is the state of a deleted scene, stored in its trashed scene.
+type TrashData struct {
+	Snapshot *Snapshot `json:"snapshot"`
+	// Dir is the directory containing the trashed files of the scene
+	Dir   string        `json:"dir"`
+	Files []TrashedFile `json:"files"`
+	// KeptFileIDs are the files of the scene which were not trashed because
+	// they belong to other scenes
+	KeptFileIDs []file.ID `json:"kept_file_ids,omitempty"`
+}
+
+// DecodeTrashData returns the state of the deleted scene of the trashed
+// scene.
+func DecodeTrashData(t *models.TrashedScene) (*TrashData, error) {
+	var ret TrashData
+	if err := json.Unmarshal(t.Data, &ret); err != nil {
+		return nil, fmt.Errorf("decoding trashed scene %d: %w", t.ID, err)
+	}
+
+	if ret.Snapshot == nil {
+		return nil, fmt.Errorf("trashed scene %d has no snapshot", t.ID)
+	}
+
+	return &ret, nil
+}
+
+type TrashCreator interface {
+	Create(ctx context.Context, newObject models.TrashedScene) (*models.TrashedScene, error)
+}
+
+// Trash destroys the scene, moving its files to a new directory in trashDir
+// instead of deleting them, and creates a trashed scene from which the scene
+// can be restored with RestoreTrashed. As with Destroy, files which belong to
+// other scenes are kept. Files inside zip files are not trashed, and are not
+// restored.
+func (s *Service) Trash(ctx context.Context, scene *models.Scene, fileDeleter *FileDeleter, mover *file.Mover, trashDir string, store TrashCreator, deleteGenerated bool) (*models.TrashedScene, error) {
+	snapshot, err := TakeSnapshot(ctx, s.Repository, scene)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := snapshot.LoadMarkers(ctx, s.MarkerRepository); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	data := TrashData{
+		Snapshot: snapshot,
+		Dir:      filepath.Join(trashDir, fmt.Sprintf("%s-%d", now.Format("20060102-150405"), scene.ID)),
+	}
+
+	if err := s.trashFiles(ctx, scene, mover, &data); err != nil {
+		return nil, err
+	}
+
+	// the files have already been destroyed
+	const deleteFile = false
+	if err := s.Destroy(ctx, scene, fileDeleter, deleteGenerated, deleteFile); err != nil {
+		return nil, err
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("encoding trashed scene: %w", err)
+	}
+
+	return store.Create(ctx, models.TrashedScene{
+		SceneID:   scene.ID,
+		Title:     scene.GetTitle(),
+		Path:      scene.Path,
+		Data:      encoded,
+		CreatedAt: now,
+	})
+}
+
+// trashFiles destroys the files of the scene which do not belong to other
+// scenes, and moves them and their funscripts to the trash directory of the
+// scene.
+func (s *Service) trashFiles(ctx context.Context, scene *models.Scene, mover *file.Mover, data *TrashData) error {
+	used := make(map[string]bool)
+	trash := func(f *file.VideoFile, path string) error {
+		if exists, _ := fsutil.FileExists(path); !exists {
+			logger.Warnf("File %q does not exist and therefore cannot be moved to the trash. Ignoring.", path)
+			return nil
+		}
+
+		dest := filepath.Join(data.Dir, filepath.Base(path))
+		for i := 1; used[dest]; i++ {
+			dest = filepath.Join(data.Dir, fmt.Sprintf("%d-%s", i, filepath.Base(path)))
+		}
+
+		if err := mover.MoveFile(path, dest); err != nil {
+			return fmt.Errorf("moving %q to the trash: %w", path, err)
+		}
+		used[dest] = true
+
+		data.Files = append(data.Files, TrashedFile{
+			File:         f,
+			OriginalPath: path,
+			TrashPath:    dest,
+		})
+		return nil
+	}
+
+	for _, f := range scene.Files.List() {
+		otherScenes, err := s.Repository.FindByFileID(ctx, f.ID)
+		if err != nil {
+			return err
+		}
+
+		if len(otherScenes) > 1 {
+			data.KeptFileIDs = append(data.KeptFileIDs, f.ID)
+			continue
+		}
+
+		const deleteFile = false
+		if err := file.Destroy(ctx, s.File, f, nil, deleteFile); err != nil {
+			return err
+		}
+
+		if f.ZipFileID != nil {
+			continue
+		}
+
+		if err := trash(f, f.Path); err != nil {
+			return err
+		}
+
+		for _, p := range funscriptPaths(f.Path) {
+			if exists, _ := fsutil.FileExists(p); exists {
+				if err := trash(nil, p); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// RestoreTrashed moves the files of the trashed scene back to their original
+// paths and recreates the scene, along with its cover and markers. The
+// recreated scene is assigned a new ID. The trashed scene is not destroyed.
+// An error is returned if a file has since been created at an original path.
+func (s *Service) RestoreTrashed(ctx context.Context, t *models.TrashedScene, mover *file.Mover) (*models.Scene, error) {
+	data, err := DecodeTrashData(t)
+	if err != nil {
+		return nil, err
+	}
+
+	// restored files are assigned new IDs
+	fileIDs := make(map[file.ID]file.ID)
+	for _, id := range data.KeptFileIDs {
+		fileIDs[id] = id
+	}
+
+	for _, tf := range data.Files {
+		if tf.File == nil {
+			if err := mover.MoveFile(tf.TrashPath, tf.OriginalPath); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		oldID := tf.File.ID
+		tf.File.Path = tf.OriginalPath
+		if err := mover.Create(ctx, tf.File, tf.TrashPath); err != nil {
+			return nil, err
+		}
+		fileIDs[oldID] = tf.File.ID
+	}
+
+	snapshot := *data.Snapshot
+	snapshot.FileIDs = nil
+	for _, id := range data.Snapshot.FileIDs {
+		if newID, ok := fileIDs[id]; ok {
+			snapshot.FileIDs = append(snapshot.FileIDs, newID)
+		}
+	}
+
+	return snapshot.Restore(ctx, s.Repository, s.MarkerRepository)
+}
@@ -0,0 +1,165 @@
+package scene
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// StackVersions stacks the scenes as versions under the primary scene. Scenes
+// which are the primary scene of another stack are stacked along with their
+// versions.
+func StackVersions(ctx context.Context, qb models.SceneVersionReaderWriter, primaryID int, sceneIDs []int) error {
+	p, err := qb.FindPrimaryID(ctx, primaryID)
+	if err != nil {
+		return err
+	}
+	if p != nil {
+		return fmt.Errorf("scene %d is a version of scene %d", primaryID, *p)
+	}
+
+	var ids []int
+	for _, id := range sceneIDs {
+		if id == primaryID {
+			continue
+		}
+
+		versions, err := qb.FindVersionIDs(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		ids = append(ids, id)
+		ids = append(ids, versions...)
+	}
+
+	return qb.Stack(ctx, primaryID, ids)
+}
+
+// UnstackVersions removes the scenes from their stacks. Removing the primary
+// scene of a stack removes the whole stack.
+func UnstackVersions(ctx context.Context, qb models.SceneVersionWriter, sceneIDs []int) error {
+	for _, id := range sceneIDs {
+		if err := qb.DestroyStack(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	return qb.Unstack(ctx, sceneIDs)
+}
+
+// SetPrimaryVersion makes the scene the primary scene of its stack. Scenes
+// which are not stacked are left unchanged.
+func SetPrimaryVersion(ctx context.Context, qb models.SceneVersionReaderWriter, sceneID int) error {
+	p, err := qb.FindPrimaryID(ctx, sceneID)
+	if err != nil || p == nil {
+		return err
+	}
+
+	versions, err := qb.FindVersionIDs(ctx, *p)
+	if err != nil {
+		return err
+	}
+
+	ids := []int{*p}
+	for _, id := range versions {
+		if id != sceneID {
+			ids = append(ids, id)
+		}
+	}
+
+	if err := qb.Unstack(ctx, []int{sceneID}); err != nil {
+		return err
+	}
+
+	return qb.Stack(ctx, sceneID, ids)
+}
+
+// StackIDs returns the IDs of the scenes in the stack of the scene, primary
+// scene first. Returns nil if the scene is not stacked.
+func StackIDs(ctx context.Context, qb models.SceneVersionReader, sceneID int) ([]int, error) {
+	primaryID := sceneID
+	p, err := qb.FindPrimaryID(ctx, sceneID)
+	if err != nil {
+		return nil, err
+	}
+	if p != nil {
+		primaryID = *p
+	}
+
+	versions, err := qb.FindVersionIDs(ctx, primaryID)
+	if err != nil || len(versions) == 0 {
+		return nil, err
+	}
+
+	return append([]int{primaryID}, versions...), nil
+}
+
+// BestVersion returns the version of a stack to play by default: the scene
+// recommended to keep when comparing the scenes as duplicates. The primary
+// files of the scenes must be loaded. Returns the first scene if none of the
+// scenes have a primary file.
+func BestVersion(scenes []*models.Scene) *models.Scene {
+	if len(scenes) == 0 {
+		return nil
+	}
+
+	for i, c := range CompareDuplicates(scenes) {
+		if c.Recommended {
+			return scenes[i]
+		}
+	}
+
+	return scenes[0]
+}
+
+// StackDuplicates stacks each group of duplicate scenes under the scene
+// recommended to keep, or under the primary scene of its stack if the scene
+// is already a version. The primary files of the scenes must be loaded.
+// Returns the number of groups which were stacked, excluding groups which
+// were already stacked together.
+func StackDuplicates(ctx context.Context, qb models.SceneVersionReaderWriter, groups [][]*models.Scene) (int, error) {
+	ret := 0
+	for _, group := range groups {
+		best := BestVersion(group)
+		if best == nil {
+			continue
+		}
+
+		primaryID := best.ID
+		p, err := qb.FindPrimaryID(ctx, best.ID)
+		if err != nil {
+			return ret, err
+		}
+		if p != nil {
+			primaryID = *p
+		}
+
+		var ids []int
+		for _, s := range group {
+			if s.ID == primaryID {
+				continue
+			}
+
+			p, err := qb.FindPrimaryID(ctx, s.ID)
+			if err != nil {
+				return ret, err
+			}
+			if p == nil || *p != primaryID {
+				ids = append(ids, s.ID)
+			}
+		}
+
+		if len(ids) == 0 {
+			continue
+		}
+
+		if err := StackVersions(ctx, qb, primaryID, ids); err != nil {
+			return ret, err
+		}
+		ret++
+	}
+
+	return ret, nil
+}
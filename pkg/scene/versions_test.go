@@ -0,0 +1,45 @@
+package scene
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBestVersion(t *testing.T) {
+	makeScene := func(id int, height int) *models.Scene {
+		var files []*file.VideoFile
+		if height > 0 {
+			files = append(files, &file.VideoFile{
+				BaseFile: &file.BaseFile{},
+				Width:    height * 16 / 9,
+				Height:   height,
+			})
+		}
+
+		return &models.Scene{
+			ID:    id,
+			Files: models.NewRelatedVideoFiles(files),
+		}
+	}
+
+	tests := []struct {
+		name   string
+		scenes []*models.Scene
+		want   int
+	}{
+		{"highest resolution", []*models.Scene{makeScene(1, 1080), makeScene(2, 2160), makeScene(3, 720)}, 2},
+		{"without files", []*models.Scene{makeScene(1, 0), makeScene(2, 0)}, 1},
+		{"ignores scenes without files", []*models.Scene{makeScene(1, 0), makeScene(2, 480)}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, BestVersion(tt.scenes).ID)
+		})
+	}
+
+	assert.Nil(t, BestVersion(nil))
+}
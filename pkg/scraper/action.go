@@ -34,6 +34,8 @@ type scraperActionImpl interface {
 }
 
 func (c config) getScraper(scraper scraperTypeConfig, client *http.Client, globalConfig GlobalConfig) scraperActionImpl {
+	client = c.rateLimitedClient(client)
+
 	switch scraper.Action {
 	case scraperActionScript:
 		return newScriptScraper(scraper, c, globalConfig)
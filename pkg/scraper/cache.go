@@ -41,6 +41,9 @@ type GlobalConfig interface {
 	GetScraperCDPPath() string
 	GetScraperCertCheck() bool
 	GetPythonPath() string
+	GetScraperRateLimits() []*RateLimit
+	GetScraperCacheDays() int
+	GetCachePath() string
 }
 
 func isCDPPathHTTP(c GlobalConfig) bool {
@@ -78,6 +81,8 @@ type Repository struct {
 	PerformerFinder PerformerFinder
 	MovieFinder     match.MovieNamesFinder
 	StudioFinder    StudioFinder
+	// ResultCache caches the results of scrapes. Results are not cached if nil.
+	ResultCache ResultCache
 }
 
 // Cache stores the database of scrapers
@@ -93,10 +98,10 @@ type Cache struct {
 // newClient creates a scraper-local http client we use throughout the scraper subsystem.
 func newClient(gc GlobalConfig) *http.Client {
 	client := &http.Client{
-		Transport: &http.Transport{ // ignore insecure certificates
+		Transport: newRateLimitTransport(&http.Transport{ // ignore insecure certificates
 			TLSClientConfig:     &tls.Config{InsecureSkipVerify: !gc.GetScraperCertCheck()},
 			MaxIdleConnsPerHost: maxIdleConnsPerHost,
-		},
+		}, gc),
 		Timeout: scrapeGetTimeout,
 		// defaultCheckRedirect code with max changed from 10 to maxRedirects
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
@@ -240,7 +245,9 @@ func (c Cache) ScrapeName(ctx context.Context, id, query string, ty ScrapeConten
 		return nil, fmt.Errorf("%w: cannot use scraper %s to scrape by name", ErrNotSupported, id)
 	}
 
-	return ns.viaName(ctx, c.client, query, ty)
+	return c.cachedList(ctx, id, "name", ty, query, func() ([]ScrapedContent, error) {
+		return ns.viaName(ctx, c.client, query, ty)
+	})
 }
 
 // ScrapeFragment uses the given fragment input to scrape
@@ -255,7 +262,10 @@ func (c Cache) ScrapeFragment(ctx context.Context, id string, input Input) (Scra
 		return nil, fmt.Errorf("%w: cannot use scraper %s as a fragment scraper", ErrNotSupported, id)
 	}
 
-	content, err := fs.viaFragment(ctx, c.client, input)
+	ty := fragmentContentType(input)
+	content, err := c.cachedContent(ctx, id, "fragment", ty, input, func() (ScrapedContent, error) {
+		return fs.viaFragment(ctx, c.client, input)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("error while fragment scraping with scraper %s: %w", id, err)
 	}
@@ -273,7 +283,9 @@ func (c Cache) ScrapeURL(ctx context.Context, url string, ty ScrapeContentType)
 			if !ok {
 				return nil, fmt.Errorf("%w: cannot use scraper %s as an url scraper", ErrNotSupported, s.spec().ID)
 			}
-			ret, err := ul.viaURL(ctx, c.client, url, ty)
+			ret, err := c.cachedContent(ctx, s.spec().ID, "url", ty, url, func() (ScrapedContent, error) {
+				return ul.viaURL(ctx, c.client, url, ty)
+			})
 			if err != nil {
 				return nil, err
 			}
@@ -312,16 +324,18 @@ func (c Cache) ScrapeID(ctx context.Context, scraperID string, id int, ty Scrape
 			return nil, fmt.Errorf("scraper %s: unable to load scene id %v: %w", scraperID, id, err)
 		}
 
-		// don't assign nil concrete pointer to ret interface, otherwise nil
-		// detection is harder
-		scraped, err := ss.viaScene(ctx, c.client, scene)
+		ret, err = c.cachedContent(ctx, scraperID, "scene", ty, sceneCacheInput(scene), func() (ScrapedContent, error) {
+			// don't assign nil concrete pointer to ret interface, otherwise nil
+			// detection is harder
+			scraped, err := ss.viaScene(ctx, c.client, scene)
+			if scraped == nil || err != nil {
+				return nil, err
+			}
+			return scraped, nil
+		})
 		if err != nil {
 			return nil, fmt.Errorf("scraper %s: %w", scraperID, err)
 		}
-
-		if scraped != nil {
-			ret = scraped
-		}
 	case ScrapeContentTypeGallery:
 		gs, ok := s.(galleryScraper)
 		if !ok {
@@ -333,16 +347,18 @@ func (c Cache) ScrapeID(ctx context.Context, scraperID string, id int, ty Scrape
 			return nil, fmt.Errorf("scraper %s: unable to load gallery id %v: %w", scraperID, id, err)
 		}
 
-		// don't assign nil concrete pointer to ret interface, otherwise nil
-		// detection is harder
-		scraped, err := gs.viaGallery(ctx, c.client, gallery)
+		ret, err = c.cachedContent(ctx, scraperID, "gallery", ty, galleryCacheInput(gallery), func() (ScrapedContent, error) {
+			// don't assign nil concrete pointer to ret interface, otherwise nil
+			// detection is harder
+			scraped, err := gs.viaGallery(ctx, c.client, gallery)
+			if scraped == nil || err != nil {
+				return nil, err
+			}
+			return scraped, nil
+		})
 		if err != nil {
 			return nil, fmt.Errorf("scraper %s: %w", scraperID, err)
 		}
-
-		if scraped != nil {
-			ret = scraped
-		}
 	}
 
 	return c.postScrape(ctx, ret)
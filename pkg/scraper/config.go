@@ -62,6 +62,10 @@ type config struct {
 
 	// Scraping driver options
 	DriverOptions *scraperDriverOptions `yaml:"driver"`
+
+	// Rate limits of the requests made by the scraper. These take precedence
+	// over the global rate limits.
+	RateLimits []*RateLimit `yaml:"rateLimits"`
 }
 
 func (c config) validate() error {
@@ -105,6 +109,15 @@ func (c config) validate() error {
 		}
 	}
 
+	for _, l := range c.RateLimits {
+		if l == nil {
+			continue
+		}
+		if err := l.validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -190,6 +203,12 @@ type scraperDriverOptions struct {
 	Clicks  []*clickOptions  `yaml:"clicks"`
 	Cookies []*cookieOptions `yaml:"cookies"`
 	Headers []*header        `yaml:"headers"`
+	// UserAgent overrides the global scraper user agent
+	UserAgent string `yaml:"userAgent"`
+	// PersistCookies keeps the cookies set by scraped sites between scrapes
+	PersistCookies bool `yaml:"persistCookies"`
+	// ScreenshotOnFailure saves a screenshot of the page if a CDP scrape fails
+	ScreenshotOnFailure bool `yaml:"screenshotOnFailure"`
 }
 
 func loadConfigFromYAML(id string, reader io.Reader) (*config, error) {
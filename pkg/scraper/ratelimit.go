@@ -0,0 +1,350 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+const (
+	// defaultRateLimitRetries is the number of times rate limited requests are
+	// retried for domains without a configured rate limit.
+	defaultRateLimitRetries = 3
+
+	// rateLimitBackoff is the initial backoff of rate limited requests. It is
+	// doubled after each retry, up to maxRateLimitBackoff.
+	rateLimitBackoff = time.Second
+
+	// maxRateLimitBackoff is the maximum time to wait before retrying a rate
+	// limited request. It is lower than scrapeGetTimeout, which includes the
+	// time spent waiting.
+	maxRateLimitBackoff = 30 * time.Second
+)
+
+// RateLimit limits the requests made to a domain.
+type RateLimit struct {
+	// Domain the limit applies to, including its subdomains. If empty, the
+	// limit applies to all domains without a more specific limit.
+	Domain string `yaml:"domain" json:"domain" mapstructure:"domain"`
+	// Maximum number of requests per second. Unlimited if zero.
+	RequestsPerSecond float64 `yaml:"requestsPerSecond" json:"requests_per_second" mapstructure:"requests_per_second"`
+	// Maximum number of concurrent requests. Unlimited if zero.
+	MaxConcurrent int `yaml:"maxConcurrent" json:"max_concurrent" mapstructure:"max_concurrent"`
+	// Number of times a rate limited request is retried, with exponential
+	// backoff. Negative values disable retries. Defaults to 3 if zero.
+	MaxRetries int `yaml:"maxRetries" json:"max_retries" mapstructure:"max_retries"`
+}
+
+func (l RateLimit) validate() error {
+	if l.RequestsPerSecond < 0 {
+		return errors.New("rate limit requestsPerSecond must not be negative")
+	}
+	if l.MaxConcurrent < 0 {
+		return errors.New("rate limit maxConcurrent must not be negative")
+	}
+	return nil
+}
+
+func (l RateLimit) retries() int {
+	switch {
+	case l.MaxRetries < 0:
+		return 0
+	case l.MaxRetries == 0:
+		return defaultRateLimitRetries
+	}
+	return l.MaxRetries
+}
+
+// matches returns true if the limit applies to host.
+func (l RateLimit) matches(host string) bool {
+	domain := strings.ToLower(strings.TrimPrefix(l.Domain, "."))
+	if domain == "" {
+		return true
+	}
+
+	host = strings.ToLower(host)
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+// findRateLimit returns the most specific limit matching host, or nil if
+// there is none.
+func findRateLimit(limits []*RateLimit, host string) *RateLimit {
+	var ret *RateLimit
+	for _, l := range limits {
+		if l != nil && l.matches(host) && (ret == nil || len(l.Domain) > len(ret.Domain)) {
+			ret = l
+		}
+	}
+
+	return ret
+}
+
+type scraperRateLimitsKey struct{}
+
+// withScraperRateLimits returns a context containing the rate limits of a
+// scraper, which take precedence over the global ones.
+func withScraperRateLimits(ctx context.Context, limits []*RateLimit) context.Context {
+	return context.WithValue(ctx, scraperRateLimitsKey{}, limits)
+}
+
+func scraperRateLimits(ctx context.Context) []*RateLimit {
+	ret, _ := ctx.Value(scraperRateLimitsKey{}).([]*RateLimit)
+	return ret
+}
+
+// domainLimiter enforces a rate limit for the requests to a domain.
+type domainLimiter struct {
+	interval time.Duration
+	// sem is nil if the number of concurrent requests is unlimited
+	sem chan struct{}
+
+	mutex sync.Mutex
+	// next is the earliest time the next request may start
+	next time.Time
+}
+
+func newDomainLimiter(l RateLimit) *domainLimiter {
+	ret := &domainLimiter{}
+	if l.RequestsPerSecond > 0 {
+		ret.interval = time.Duration(float64(time.Second) / l.RequestsPerSecond)
+	}
+	if l.MaxConcurrent > 0 {
+		ret.sem = make(chan struct{}, l.MaxConcurrent)
+	}
+
+	return ret
+}
+
+// acquire waits until a request may be made. release must be called once
+// the request is complete if acquire does not return an error.
+func (l *domainLimiter) acquire(ctx context.Context) error {
+	if l.sem != nil {
+		select {
+		case l.sem <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	l.mutex.Lock()
+	now := time.Now()
+	start := l.next
+	if start.Before(now) {
+		start = now
+	}
+	l.next = start.Add(l.interval)
+	l.mutex.Unlock()
+
+	if wait := time.Until(start); wait > 0 {
+		t := time.NewTimer(wait)
+		defer t.Stop()
+
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			l.release()
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+func (l *domainLimiter) release() {
+	if l.sem != nil {
+		<-l.sem
+	}
+}
+
+// backoff delays all subsequent requests to the domain by d.
+func (l *domainLimiter) backoff(d time.Duration) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if until := time.Now().Add(d); until.After(l.next) {
+		l.next = until
+	}
+}
+
+// rateLimitTransport is a http.RoundTripper that limits the rate and
+// concurrency of requests per domain, and retries rate limited requests with
+// exponential backoff. It is shared by all scrapers so that the limits apply
+// across them.
+type rateLimitTransport struct {
+	base         http.RoundTripper
+	globalConfig GlobalConfig
+
+	mutex    sync.Mutex
+	limiters map[string]*domainLimiter
+}
+
+func newRateLimitTransport(base http.RoundTripper, gc GlobalConfig) *rateLimitTransport {
+	return &rateLimitTransport{
+		base:         base,
+		globalConfig: gc,
+		limiters:     make(map[string]*domainLimiter),
+	}
+}
+
+// getLimit returns the limit for the request, and the key of the limiter
+// enforcing it.
+func (t *rateLimitTransport) getLimit(req *http.Request) (RateLimit, string) {
+	host := req.URL.Hostname()
+
+	limit := findRateLimit(scraperRateLimits(req.Context()), host)
+	if limit == nil {
+		limit = findRateLimit(t.globalConfig.GetScraperRateLimits(), host)
+	}
+
+	if limit == nil {
+		return RateLimit{}, host
+	}
+
+	// subdomains share the budget of the domain they are limited by. Limits
+	// without a domain apply to each host separately.
+	domain := strings.ToLower(strings.TrimPrefix(limit.Domain, "."))
+	if domain == "" {
+		domain = host
+	}
+
+	// the limiter is recreated if the limit changes
+	key := fmt.Sprintf("%s|%v|%d", domain, limit.RequestsPerSecond, limit.MaxConcurrent)
+	return *limit, key
+}
+
+func (t *rateLimitTransport) limiter(key string, l RateLimit) *domainLimiter {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	ret := t.limiters[key]
+	if ret == nil {
+		ret = newDomainLimiter(l)
+		t.limiters[key] = ret
+	}
+
+	return ret
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	limit, key := t.getLimit(req)
+	l := t.limiter(key, limit)
+	retries := limit.retries()
+
+	for attempt := 0; ; attempt++ {
+		if err := l.acquire(req.Context()); err != nil {
+			return nil, err
+		}
+
+		resp, err := t.base.RoundTrip(req)
+		l.release()
+
+		if err != nil || !isRateLimited(resp) || attempt >= retries {
+			return resp, err
+		}
+
+		retryReq, err := rewindRequest(req)
+		if err != nil {
+			// the request cannot be retried
+			return resp, nil
+		}
+
+		delay, ok := retryAfter(resp)
+		if !ok {
+			delay = rateLimitBackoff << attempt
+		}
+		if delay > maxRateLimitBackoff {
+			delay = maxRateLimitBackoff
+		}
+
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		logger.Debugf("[scraper] request to %s was rate limited (%s), retrying in %v", req.URL.Hostname(), resp.Status, delay)
+		l.backoff(delay)
+		req = retryReq
+	}
+}
+
+func isRateLimited(resp *http.Response) bool {
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable
+}
+
+// retryAfter returns the delay requested by the Retry-After header of resp.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+
+	return 0, false
+}
+
+// rewindRequest returns a copy of req which can be sent again.
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	ret := req.Clone(req.Context())
+	if req.Body == nil || req.Body == http.NoBody {
+		return ret, nil
+	}
+
+	if req.GetBody == nil {
+		return nil, errors.New("request body cannot be rewound")
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	ret.Body = body
+	return ret, nil
+}
+
+// scraperRateLimitTransport adds the rate limits of a scraper to the
+// requests it makes.
+type scraperRateLimitTransport struct {
+	base   http.RoundTripper
+	limits []*RateLimit
+}
+
+func (t scraperRateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.base.RoundTrip(req.WithContext(withScraperRateLimits(req.Context(), t.limits)))
+}
+
+// rateLimitedClient returns a copy of client which applies the rate limits
+// of the scraper configuration. client is returned if there are none.
+func (c config) rateLimitedClient(client *http.Client) *http.Client {
+	if len(c.RateLimits) == 0 || client == nil {
+		return client
+	}
+
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	ret := *client
+	ret.Transport = scraperRateLimitTransport{
+		base:   base,
+		limits: c.RateLimits,
+	}
+	return &ret
+}
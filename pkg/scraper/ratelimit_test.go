@@ -0,0 +1,140 @@
+package scraper
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type rateLimitGlobalConfig struct {
+	mockGlobalConfig
+	limits []*RateLimit
+}
+
+func (c rateLimitGlobalConfig) GetScraperRateLimits() []*RateLimit {
+	return c.limits
+}
+
+func TestFindRateLimit(t *testing.T) {
+	all := &RateLimit{}
+	domain := &RateLimit{Domain: "example.com"}
+	sub := &RateLimit{Domain: "api.example.com"}
+	limits := []*RateLimit{all, domain, sub}
+
+	tests := []struct {
+		name   string
+		limits []*RateLimit
+		host   string
+		want   *RateLimit
+	}{
+		{"no limits", nil, "example.com", nil},
+		{"exact domain", limits, "example.com", domain},
+		{"subdomain", limits, "www.example.com", domain},
+		{"more specific subdomain", limits, "api.example.com", sub},
+		{"case insensitive", limits, "WWW.Example.com", domain},
+		{"suffix of other domain", limits, "notexample.com", all},
+		{"no matching domain", []*RateLimit{domain}, "example.org", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Same(t, tt.want, findRateLimit(tt.limits, tt.host))
+		})
+	}
+}
+
+func TestRateLimitTransportRetry(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) <= 2 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name         string
+		maxRetries   int
+		wantStatus   int
+		wantRequests int32
+	}{
+		{"default retries", 0, http.StatusOK, 3},
+		{"single retry", 1, http.StatusTooManyRequests, 2},
+		{"retries disabled", -1, http.StatusTooManyRequests, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			atomic.StoreInt32(&requests, 0)
+
+			gc := rateLimitGlobalConfig{limits: []*RateLimit{{MaxRetries: tt.maxRetries}}}
+			client := &http.Client{Transport: newRateLimitTransport(http.DefaultTransport, gc)}
+
+			resp, err := client.Get(server.URL)
+			if !assert.NoError(t, err) {
+				return
+			}
+			resp.Body.Close()
+
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			assert.Equal(t, tt.wantRequests, atomic.LoadInt32(&requests))
+		})
+	}
+}
+
+func TestRateLimitTransportLimits(t *testing.T) {
+	const nRequests = 6
+	const maxConcurrent = 2
+
+	var mutex sync.Mutex
+	var current, max int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		current++
+		if current > max {
+			max = current
+		}
+		mutex.Unlock()
+
+		time.Sleep(50 * time.Millisecond)
+
+		mutex.Lock()
+		current--
+		mutex.Unlock()
+	}))
+	defer server.Close()
+
+	// the scraper limit takes precedence over the global one
+	gc := rateLimitGlobalConfig{limits: []*RateLimit{{MaxConcurrent: 1}}}
+	c := config{RateLimits: []*RateLimit{{
+		Domain:            "127.0.0.1",
+		RequestsPerSecond: 100,
+		MaxConcurrent:     maxConcurrent,
+	}}}
+	client := c.rateLimitedClient(&http.Client{Transport: newRateLimitTransport(http.DefaultTransport, gc)})
+
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	for i := 0; i < nRequests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.Get(server.URL)
+			if assert.NoError(t, err) {
+				resp.Body.Close()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, maxConcurrent, max)
+	// requests must be at least 10ms apart
+	assert.GreaterOrEqual(t, time.Since(start), (nRequests-1)*10*time.Millisecond)
+}
@@ -0,0 +1,266 @@
+package scraper
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/txn"
+)
+
+// ResultCache stores the results of scrapes.
+type ResultCache interface {
+	Find(ctx context.Context, scraperID string, key string, t time.Time) (*models.ScraperCacheEntry, error)
+	Set(ctx context.Context, entry models.ScraperCacheEntry) error
+}
+
+// resultCacheTTL returns how long scrape results are cached for, or zero if
+// results are not cached.
+func (c Cache) resultCacheTTL() time.Duration {
+	if c.repository.ResultCache == nil {
+		return 0
+	}
+
+	days := c.globalConfig.GetScraperCacheDays()
+	if days <= 0 {
+		return 0
+	}
+
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// resultCacheKey returns the key of the result of a scrape operation with
+// the input.
+func resultCacheKey(op string, ty ScrapeContentType, input interface{}) (string, error) {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00", op, ty)
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// cachedScrape returns the cached result of the scrape operation with the
+// input if there is one, otherwise it scrapes using fn, caching the result.
+// out must be a pointer to the result type, and is set to the result.
+// Results of the builtin scrapers, which do not make remote requests, are
+// not cached.
+func (c Cache) cachedScrape(ctx context.Context, scraperID string, op string, ty ScrapeContentType, input interface{}, out interface{}, fn func() error) error {
+	ttl := c.resultCacheTTL()
+	if ttl == 0 || scraperID == autoTagScraperID {
+		return fn()
+	}
+
+	key, err := resultCacheKey(op, ty, input)
+	if err != nil {
+		logger.Debugf("[scraper] not caching result of %s: %v", scraperID, err)
+		return fn()
+	}
+
+	var entry *models.ScraperCacheEntry
+	if err := txn.WithReadTxn(ctx, c.txnManager, func(ctx context.Context) error {
+		var err error
+		entry, err = c.repository.ResultCache.Find(ctx, scraperID, key, time.Now().Add(-ttl))
+		return err
+	}); err != nil {
+		logger.Warnf("[scraper] error reading cached result of %s: %v", scraperID, err)
+	}
+
+	if entry != nil {
+		if err := json.Unmarshal(entry.Data, out); err == nil {
+			logger.Debugf("[scraper] using cached result of %s", scraperID)
+			return nil
+		}
+		logger.Debugf("[scraper] ignoring invalid cached result of %s: %v", scraperID, err)
+	}
+
+	if err := fn(); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(out)
+	if err != nil {
+		logger.Debugf("[scraper] not caching result of %s: %v", scraperID, err)
+		return nil
+	}
+
+	if err := txn.WithTxn(ctx, c.txnManager, func(ctx context.Context) error {
+		return c.repository.ResultCache.Set(ctx, models.ScraperCacheEntry{
+			ScraperID: scraperID,
+			Key:       key,
+			Data:      data,
+			CreatedAt: time.Now(),
+		})
+	}); err != nil {
+		logger.Warnf("[scraper] error caching result of %s: %v", scraperID, err)
+	}
+
+	return nil
+}
+
+// cachedContent scrapes a single content of type ty using fn, using the
+// result cache.
+func (c Cache) cachedContent(ctx context.Context, scraperID string, op string, ty ScrapeContentType, input interface{}, fn func() (ScrapedContent, error)) (ScrapedContent, error) {
+	out := newScrapedContent(ty)
+	if out == nil {
+		return fn()
+	}
+
+	var found bool
+	if err := c.cachedScrape(ctx, scraperID, op, ty, input, &out, func() error {
+		ret, err := fn()
+		if err != nil {
+			return err
+		}
+
+		found = ret != nil
+		out = toPointerContent(ret)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	// cached results without content decode to nil pointers
+	if !found && isNilContent(out) {
+		return nil, nil
+	}
+
+	return out, nil
+}
+
+// cachedList scrapes a list of content of type ty using fn, using the result
+// cache.
+func (c Cache) cachedList(ctx context.Context, scraperID string, op string, ty ScrapeContentType, input interface{}, fn func() ([]ScrapedContent, error)) ([]ScrapedContent, error) {
+	if newScrapedContent(ty) == nil {
+		return fn()
+	}
+
+	var ret []ScrapedContent
+	var scraped bool
+	var out []json.RawMessage
+	if err := c.cachedScrape(ctx, scraperID, op, ty, input, &out, func() error {
+		var err error
+		ret, err = fn()
+		if err != nil {
+			return err
+		}
+
+		scraped = true
+		out = make([]json.RawMessage, len(ret))
+		for i, content := range ret {
+			if out[i], err = json.Marshal(content); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if scraped {
+		return ret, nil
+	}
+
+	ret = make([]ScrapedContent, len(out))
+	for i, data := range out {
+		content := newScrapedContent(ty)
+		if err := json.Unmarshal(data, content); err != nil {
+			return nil, fmt.Errorf("decoding cached result of %s: %w", scraperID, err)
+		}
+		ret[i] = content
+	}
+
+	return ret, nil
+}
+
+// fragmentContentType returns the type of content scraped using input.
+func fragmentContentType(input Input) ScrapeContentType {
+	switch {
+	case input.Performer != nil:
+		return ScrapeContentTypePerformer
+	case input.Gallery != nil:
+		return ScrapeContentTypeGallery
+	}
+
+	return ScrapeContentTypeScene
+}
+
+// sceneCacheInput returns the fields of the scene that identify the result of
+// scraping it. Fields that are set by scraping the scene are excluded, so
+// that its results remain cached after they are applied.
+func sceneCacheInput(s *models.Scene) interface{} {
+	return struct {
+		ID       int
+		Path     string
+		OSHash   string
+		Checksum string
+	}{s.ID, s.Path, s.OSHash, s.Checksum}
+}
+
+// galleryCacheInput returns the fields of the gallery that identify the
+// result of scraping it.
+func galleryCacheInput(g *models.Gallery) interface{} {
+	return struct {
+		ID   int
+		Path string
+	}{g.ID, g.Path}
+}
+
+// newScrapedContent returns a pointer to an empty content of type ty, for
+// decoding cached results into.
+func newScrapedContent(ty ScrapeContentType) ScrapedContent {
+	switch ty {
+	case ScrapeContentTypeScene:
+		return &ScrapedScene{}
+	case ScrapeContentTypeGallery:
+		return &ScrapedGallery{}
+	case ScrapeContentTypePerformer:
+		return &models.ScrapedPerformer{}
+	case ScrapeContentTypeMovie:
+		return &models.ScrapedMovie{}
+	}
+
+	return nil
+}
+
+// toPointerContent returns content as a pointer, so that all results of a
+// content type have the same type.
+func toPointerContent(content ScrapedContent) ScrapedContent {
+	switch v := content.(type) {
+	case ScrapedScene:
+		return &v
+	case ScrapedGallery:
+		return &v
+	case models.ScrapedPerformer:
+		return &v
+	case models.ScrapedMovie:
+		return &v
+	}
+
+	return content
+}
+
+func isNilContent(content ScrapedContent) bool {
+	switch v := content.(type) {
+	case nil:
+		return true
+	case *ScrapedScene:
+		return v == nil
+	case *ScrapedGallery:
+		return v == nil
+	case *models.ScrapedPerformer:
+		return v == nil
+	case *models.ScrapedMovie:
+		return v == nil
+	}
+
+	return false
+}
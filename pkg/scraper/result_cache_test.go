@@ -0,0 +1,131 @@
+package scraper
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+)
+
+type testResultCache struct {
+	entries map[string]models.ScraperCacheEntry
+}
+
+func (c *testResultCache) Find(ctx context.Context, scraperID string, key string, t time.Time) (*models.ScraperCacheEntry, error) {
+	if e, ok := c.entries[scraperID+key]; ok && e.CreatedAt.After(t) {
+		return &e, nil
+	}
+	return nil, nil
+}
+
+func (c *testResultCache) Set(ctx context.Context, entry models.ScraperCacheEntry) error {
+	c.entries[entry.ScraperID+entry.Key] = entry
+	return nil
+}
+
+type resultCacheGlobalConfig struct {
+	mockGlobalConfig
+	days int
+}
+
+func (c resultCacheGlobalConfig) GetScraperCacheDays() int {
+	return c.days
+}
+
+func newResultCacheTestCache(days int) Cache {
+	return Cache{
+		globalConfig: resultCacheGlobalConfig{days: days},
+		txnManager:   &mocks.TxnManager{},
+		repository: Repository{
+			ResultCache: &testResultCache{entries: make(map[string]models.ScraperCacheEntry)},
+		},
+	}
+}
+
+func TestCacheCachedContent(t *testing.T) {
+	title := "title"
+	scene := ScrapedScene{Title: &title}
+
+	tests := []struct {
+		name      string
+		days      int
+		scraperID string
+		result    ScrapedContent
+		wantCalls int
+		want      ScrapedContent
+	}{
+		{"cached", 1, "scraper", scene, 1, &scene},
+		{"cached pointer", 1, "scraper", &scene, 1, &scene},
+		{"cached nil", 1, "scraper", nil, 1, nil},
+		{"disabled", 0, "scraper", &scene, 2, &scene},
+		{"builtin", 1, autoTagScraperID, &scene, 2, &scene},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newResultCacheTestCache(tt.days)
+
+			calls := 0
+			fn := func() (ScrapedContent, error) {
+				calls++
+				return tt.result, nil
+			}
+
+			for i := 0; i < 2; i++ {
+				got, err := c.cachedContent(context.Background(), tt.scraperID, "url", ScrapeContentTypeScene, "http://example.com", fn)
+				if !assert.NoError(t, err) {
+					return
+				}
+				assert.Equal(t, tt.want, got)
+			}
+
+			assert.Equal(t, tt.wantCalls, calls)
+		})
+	}
+}
+
+func TestCacheCachedList(t *testing.T) {
+	name := "name"
+	performers := []ScrapedContent{
+		&models.ScrapedPerformer{Name: &name},
+		&models.ScrapedPerformer{},
+	}
+
+	c := newResultCacheTestCache(1)
+
+	calls := 0
+	fn := func() ([]ScrapedContent, error) {
+		calls++
+		return performers, nil
+	}
+
+	for _, query := range []string{"a", "a", "b"} {
+		got, err := c.cachedList(context.Background(), "scraper", "name", ScrapeContentTypePerformer, query, fn)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, performers, got)
+	}
+
+	// only the differing query is scraped again
+	assert.Equal(t, 2, calls)
+}
+
+func TestResultCacheKey(t *testing.T) {
+	key := func(op string, ty ScrapeContentType, input interface{}) string {
+		ret, err := resultCacheKey(op, ty, input)
+		if err != nil {
+			t.Fatalf("resultCacheKey() error = %v", err)
+		}
+		return ret
+	}
+
+	base := key("url", ScrapeContentTypeScene, "http://example.com")
+	assert.Equal(t, base, key("url", ScrapeContentTypeScene, "http://example.com"))
+	assert.NotEqual(t, base, key("name", ScrapeContentTypeScene, "http://example.com"))
+	assert.NotEqual(t, base, key("url", ScrapeContentTypeMovie, "http://example.com"))
+	assert.NotEqual(t, base, key("url", ScrapeContentTypeScene, "http://example.org"))
+}
@@ -0,0 +1,308 @@
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+// sessionCookie is a cookie set by a scraped site, which is persisted between
+// scrapes.
+type sessionCookie struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Domain string `json:"domain"`
+	Path   string `json:"path"`
+	// Expires is nil for session cookies, which are kept until replaced.
+	Expires  *time.Time `json:"expires,omitempty"`
+	Secure   bool       `json:"secure"`
+	HTTPOnly bool       `json:"httpOnly"`
+}
+
+func (c sessionCookie) expired(now time.Time) bool {
+	return c.Expires != nil && !c.Expires.After(now)
+}
+
+func (c sessionCookie) key() string {
+	return c.Name + "\x00" + strings.TrimPrefix(c.Domain, ".") + "\x00" + c.Path
+}
+
+// sessionMutex serialises access to the session files.
+var sessionMutex sync.Mutex
+
+// sessionStore persists the cookies set by the sites that a scraper scrapes,
+// so that sessions such as logins are kept between scrapes and restarts.
+type sessionStore struct {
+	path string
+}
+
+// newSessionStore returns the session store of the scraper, or nil if the
+// scraper does not persist cookies or there is no cache directory.
+func newSessionStore(c config, gc GlobalConfig) *sessionStore {
+	if c.DriverOptions == nil || !c.DriverOptions.PersistCookies {
+		return nil
+	}
+
+	cachePath := gc.GetCachePath()
+	if cachePath == "" {
+		logger.Warnf("[scraper] %s: not persisting cookies as the cache path is not set", c.ID)
+		return nil
+	}
+
+	return &sessionStore{
+		path: filepath.Join(cachePath, "scrapers", "sessions", c.ID+".json"),
+	}
+}
+
+// load returns the cookies that have not expired.
+func (s *sessionStore) load() ([]sessionCookie, error) {
+	sessionMutex.Lock()
+	defer sessionMutex.Unlock()
+
+	return s.read()
+}
+
+func (s *sessionStore) read() ([]sessionCookie, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var cookies []sessionCookie
+	if err := json.Unmarshal(data, &cookies); err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", s.path, err)
+	}
+
+	now := time.Now()
+	var ret []sessionCookie
+	for _, c := range cookies {
+		if !c.expired(now) {
+			ret = append(ret, c)
+		}
+	}
+
+	return ret, nil
+}
+
+// save merges cookies into the persisted cookies. Expired cookies are
+// removed.
+func (s *sessionStore) save(cookies []sessionCookie) error {
+	if len(cookies) == 0 {
+		return nil
+	}
+
+	sessionMutex.Lock()
+	defer sessionMutex.Unlock()
+
+	existing, err := s.read()
+	if err != nil {
+		logger.Warnf("[scraper] replacing invalid session file: %v", err)
+	}
+
+	now := time.Now()
+	index := make(map[string]int)
+	var merged []sessionCookie
+	for _, c := range append(existing, cookies...) {
+		if i, found := index[c.key()]; found {
+			merged[i] = c
+			continue
+		}
+
+		index[c.key()] = len(merged)
+		merged = append(merged, c)
+	}
+
+	var ret []sessionCookie
+	for _, c := range merged {
+		if !c.expired(now) {
+			ret = append(ret, c)
+		}
+	}
+
+	data, err := json.MarshalIndent(ret, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := fsutil.EnsureDirAll(filepath.Dir(s.path)); err != nil {
+		return err
+	}
+
+	// cookies may include credentials
+	return os.WriteFile(s.path, data, 0600)
+}
+
+// setJarCookies adds the persisted cookies to jar.
+func (s *sessionStore) setJarCookies(jar *cookiejar.Jar) error {
+	cookies, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	for _, c := range cookies {
+		scheme := "http"
+		if c.Secure {
+			scheme = "https"
+		}
+
+		u := &url.URL{
+			Scheme: scheme,
+			Host:   strings.TrimPrefix(c.Domain, "."),
+			Path:   c.Path,
+		}
+
+		hc := &http.Cookie{
+			Name:     c.Name,
+			Value:    c.Value,
+			Domain:   c.Domain,
+			Path:     c.Path,
+			Secure:   c.Secure,
+			HttpOnly: c.HTTPOnly,
+		}
+		if c.Expires != nil {
+			hc.Expires = *c.Expires
+		}
+
+		jar.SetCookies(u, []*http.Cookie{hc})
+	}
+
+	return nil
+}
+
+// sessionCookiesFromHTTP converts the cookies set by a response to the
+// request for u. Cookies without a domain are applied to the host of u.
+func sessionCookiesFromHTTP(u *url.URL, cookies []*http.Cookie) []sessionCookie {
+	now := time.Now()
+
+	var ret []sessionCookie
+	for _, c := range cookies {
+		sc := sessionCookie{
+			Name:     c.Name,
+			Value:    c.Value,
+			Domain:   c.Domain,
+			Path:     c.Path,
+			Secure:   c.Secure,
+			HTTPOnly: c.HttpOnly,
+		}
+
+		if sc.Domain == "" {
+			sc.Domain = u.Hostname()
+		}
+		if sc.Path == "" {
+			sc.Path = "/"
+		}
+
+		switch {
+		case c.MaxAge < 0:
+			sc.Expires = &now
+		case c.MaxAge > 0:
+			t := now.Add(time.Duration(c.MaxAge) * time.Second)
+			sc.Expires = &t
+		case !c.Expires.IsZero():
+			t := c.Expires
+			sc.Expires = &t
+		}
+
+		ret = append(ret, sc)
+	}
+
+	return ret
+}
+
+// setCDPSessionCookies sets the persisted cookies in the browser.
+func setCDPSessionCookies(s *sessionStore) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		if s == nil {
+			return nil
+		}
+
+		cookies, err := s.load()
+		if err != nil {
+			logger.Warnf("[scraper] could not load session cookies: %v", err)
+			return nil
+		}
+
+		for _, c := range cookies {
+			p := network.SetCookie(c.Name, c.Value).
+				WithDomain(c.Domain).
+				WithPath(c.Path).
+				WithSecure(c.Secure).
+				WithHTTPOnly(c.HTTPOnly)
+			if c.Expires != nil {
+				expires := cdp.TimeSinceEpoch(*c.Expires)
+				p = p.WithExpires(&expires)
+			}
+
+			if err := p.Do(ctx); err != nil {
+				return fmt.Errorf("could not set chrome session cookie %s: %w", c.Name, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// saveCDPSessionCookies persists the browser cookies for the loaded url. Only
+// the cookies of the page are saved, as a remote browser may hold cookies of
+// unrelated sites.
+func saveCDPSessionCookies(s *sessionStore, loadURL string) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		if s == nil {
+			return nil
+		}
+
+		urls := []string{loadURL}
+		var location string
+		if err := chromedp.Location(&location).Do(ctx); err == nil && location != loadURL {
+			urls = append(urls, location)
+		}
+
+		cookies, err := network.GetCookies().WithUrls(urls).Do(ctx)
+		if err != nil {
+			return err
+		}
+
+		var ret []sessionCookie
+		for _, c := range cookies {
+			sc := sessionCookie{
+				Name:     c.Name,
+				Value:    c.Value,
+				Domain:   c.Domain,
+				Path:     c.Path,
+				Secure:   c.Secure,
+				HTTPOnly: c.HTTPOnly,
+			}
+			if !c.Session {
+				t := time.Unix(int64(c.Expires), 0)
+				sc.Expires = &t
+			}
+			ret = append(ret, sc)
+		}
+
+		if err := s.save(ret); err != nil {
+			logger.Warnf("[scraper] could not save session cookies: %v", err)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,100 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type sessionGlobalConfig struct {
+	mockGlobalConfig
+	cachePath string
+}
+
+func (c sessionGlobalConfig) GetCachePath() string {
+	return c.cachePath
+}
+
+func TestSessionStoreSave(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	s := &sessionStore{path: filepath.Join(t.TempDir(), "sessions", "test.json")}
+
+	assert.NoError(t, s.save([]sessionCookie{
+		{Name: "session", Value: "a", Domain: "example.com", Path: "/"},
+		{Name: "remember", Value: "b", Domain: "example.com", Path: "/", Expires: &future},
+		{Name: "other", Value: "c", Domain: "example.org", Path: "/"},
+	}))
+
+	// replaces cookies with the same name, domain and path, and removes
+	// expired cookies
+	assert.NoError(t, s.save([]sessionCookie{
+		{Name: "session", Value: "d", Domain: ".example.com", Path: "/"},
+		{Name: "other", Value: "", Domain: "example.org", Path: "/", Expires: &past},
+	}))
+
+	got, err := s.load()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	var values []string
+	for _, c := range got {
+		values = append(values, c.Name+"="+c.Value)
+	}
+	assert.Equal(t, []string{"session=d", "remember=b"}, values)
+}
+
+func TestLoadURLSession(t *testing.T) {
+	var gotCookies []string
+	var gotUserAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUserAgent = r.UserAgent()
+		gotCookies = nil
+		for _, c := range r.Cookies() {
+			gotCookies = append(gotCookies, c.Name+"="+c.Value)
+		}
+
+		if _, err := r.Cookie("session"); err != nil {
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc", MaxAge: 3600})
+		}
+		_, _ = w.Write([]byte("<html></html>"))
+	}))
+	defer server.Close()
+
+	gc := sessionGlobalConfig{cachePath: t.TempDir()}
+	tests := []struct {
+		name           string
+		persistCookies bool
+		wantCookies    []string
+	}{
+		{"persisted", true, []string{"session=abc"}},
+		{"not persisted", false, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := config{
+				ID: tt.name,
+				DriverOptions: &scraperDriverOptions{
+					UserAgent:      "scraper-agent",
+					PersistCookies: tt.persistCookies,
+				},
+			}
+
+			for i := 0; i < 2; i++ {
+				if _, err := loadURL(context.Background(), server.URL, server.Client(), c, gc); err != nil {
+					t.Fatalf("loadURL() error = %v", err)
+				}
+			}
+
+			assert.Equal(t, tt.wantCookies, gotCookies)
+			assert.Equal(t, "scraper-agent", gotUserAgent)
+		})
+	}
+}
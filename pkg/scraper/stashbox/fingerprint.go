@@ -0,0 +1,171 @@
+package stashbox
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/stashapp/stash/pkg/hash/md5"
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scraper/stashbox/graphql"
+	"github.com/stashapp/stash/pkg/txn"
+)
+
+// fingerprintBatchSize is the maximum number of scenes queried by stash-box
+// in a single fingerprint query.
+const fingerprintBatchSize = 40
+
+// fingerprintKey returns the cache key of a set of fingerprints, which does
+// not depend on the order of the fingerprints.
+func fingerprintKey(fingerprints []*graphql.FingerprintQueryInput) string {
+	parts := make([]string, len(fingerprints))
+	for i, fp := range fingerprints {
+		parts[i] = fp.Algorithm.String() + ":" + fp.Hash
+	}
+	sort.Strings(parts)
+
+	return md5.FromString(strings.Join(parts, ","))
+}
+
+func (c Client) fingerprintCacheEnabled() bool {
+	return c.fingerprintCache != nil && c.fingerprintCacheTTL > 0
+}
+
+// findScenesByFingerprints returns the stash-box scenes matching each set of
+// fingerprints, in the same order as the input. Sets which are cached are
+// not queried, and identical sets are only queried once. Empty sets are not
+// queried and have no results.
+func (c Client) findScenesByFingerprints(ctx context.Context, sets [][]*graphql.FingerprintQueryInput) ([][]*graphql.SceneFragment, error) {
+	keys := make([]string, len(sets))
+	var uniqueKeys []string
+	seen := make(map[string]bool)
+	for i, fps := range sets {
+		if len(fps) == 0 {
+			continue
+		}
+
+		keys[i] = fingerprintKey(fps)
+		if !seen[keys[i]] {
+			seen[keys[i]] = true
+			uniqueKeys = append(uniqueKeys, keys[i])
+		}
+	}
+
+	results, err := c.cachedFingerprintResults(ctx, uniqueKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	var query [][]*graphql.FingerprintQueryInput
+	var queryKeys []string
+	for i, key := range keys {
+		if key == "" {
+			continue
+		}
+
+		if _, found := results[key]; found {
+			continue
+		}
+
+		// prevent querying the same set twice
+		results[key] = nil
+		query = append(query, sets[i])
+		queryKeys = append(queryKeys, key)
+	}
+
+	if len(uniqueKeys) > 0 {
+		logger.Debugf("[stash-box] %d of %d fingerprint sets cached", len(uniqueKeys)-len(queryKeys), len(uniqueKeys))
+	}
+
+	for i := 0; i < len(query); i += fingerprintBatchSize {
+		end := i + fingerprintBatchSize
+		if end > len(query) {
+			end = len(query)
+		}
+
+		res, err := c.client.FindScenesBySceneFingerprints(ctx, query[i:end])
+		if err != nil {
+			return nil, err
+		}
+
+		batchKeys := queryKeys[i:end]
+		for j, fragments := range res.FindScenesBySceneFingerprints {
+			if j < len(batchKeys) {
+				results[batchKeys[j]] = fragments
+			}
+		}
+
+		// cache each batch so that the results are kept if interrupted
+		if err := c.cacheFingerprintResults(ctx, batchKeys, results); err != nil {
+			return nil, err
+		}
+	}
+
+	ret := make([][]*graphql.SceneFragment, len(sets))
+	for i, key := range keys {
+		if key != "" {
+			ret[i] = results[key]
+		}
+	}
+
+	return ret, nil
+}
+
+// cachedFingerprintResults returns the unexpired cached results of the keys.
+// Keys which are not cached are not present in the returned map.
+func (c Client) cachedFingerprintResults(ctx context.Context, keys []string) (map[string][]*graphql.SceneFragment, error) {
+	ret := make(map[string][]*graphql.SceneFragment)
+	if !c.fingerprintCacheEnabled() || len(keys) == 0 {
+		return ret, nil
+	}
+
+	var entries []*models.StashBoxFingerprintCacheEntry
+	if err := txn.WithReadTxn(ctx, c.txnManager, func(ctx context.Context) error {
+		var err error
+		entries, err = c.fingerprintCache.FindMany(ctx, c.box.Endpoint, keys, time.Now().Add(-c.fingerprintCacheTTL))
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	for _, e := range entries {
+		var fragments []*graphql.SceneFragment
+		if err := json.Unmarshal(e.Data, &fragments); err != nil {
+			logger.Debugf("[stash-box] ignoring invalid cached fingerprint result: %v", err)
+			continue
+		}
+		ret[e.Key] = fragments
+	}
+
+	return ret, nil
+}
+
+func (c Client) cacheFingerprintResults(ctx context.Context, keys []string, results map[string][]*graphql.SceneFragment) error {
+	if !c.fingerprintCacheEnabled() {
+		return nil
+	}
+
+	now := time.Now()
+	return txn.WithTxn(ctx, c.txnManager, func(ctx context.Context) error {
+		for _, key := range keys {
+			data, err := json.Marshal(results[key])
+			if err != nil {
+				return err
+			}
+
+			if err := c.fingerprintCache.Set(ctx, models.StashBoxFingerprintCacheEntry{
+				Endpoint:  c.box.Endpoint,
+				Key:       key,
+				Data:      data,
+				CreatedAt: now,
+			}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
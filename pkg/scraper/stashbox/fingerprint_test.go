@@ -0,0 +1,155 @@
+package stashbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+	"github.com/stashapp/stash/pkg/scraper/stashbox/graphql"
+	"github.com/stretchr/testify/assert"
+)
+
+type testFingerprintCache struct {
+	entries map[string]models.StashBoxFingerprintCacheEntry
+}
+
+func (c *testFingerprintCache) FindMany(ctx context.Context, endpoint string, keys []string, t time.Time) ([]*models.StashBoxFingerprintCacheEntry, error) {
+	var ret []*models.StashBoxFingerprintCacheEntry
+	for _, key := range keys {
+		if e, ok := c.entries[endpoint+key]; ok && e.CreatedAt.After(t) {
+			ret = append(ret, &e)
+		}
+	}
+	return ret, nil
+}
+
+func (c *testFingerprintCache) Set(ctx context.Context, entry models.StashBoxFingerprintCacheEntry) error {
+	c.entries[entry.Endpoint+entry.Key] = entry
+	return nil
+}
+
+// newFingerprintServer returns a stash-box server which returns a scene with
+// the hash of the first fingerprint of each set as its ID, and the number of
+// sets of each received query.
+func newFingerprintServer(t *testing.T) (*httptest.Server, *[]int) {
+	var queries []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables struct {
+				Fingerprints [][]*graphql.FingerprintQueryInput `json:"fingerprints"`
+			} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decoding request: %v", err)
+		}
+
+		sets := req.Variables.Fingerprints
+		queries = append(queries, len(sets))
+
+		results := make([][]map[string]interface{}, len(sets))
+		for i, fps := range sets {
+			results[i] = []map[string]interface{}{{"id": fps[0].Hash}}
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"findScenesBySceneFingerprints": results,
+			},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &queries
+}
+
+func makeFingerprintSets(n int) [][]*graphql.FingerprintQueryInput {
+	ret := make([][]*graphql.FingerprintQueryInput, n)
+	for i := range ret {
+		ret[i] = []*graphql.FingerprintQueryInput{
+			{Hash: fmt.Sprintf("hash%d", i), Algorithm: graphql.FingerprintAlgorithmOshash},
+		}
+	}
+	return ret
+}
+
+func TestClient_findScenesByFingerprints(t *testing.T) {
+	ctx := context.Background()
+	server, queries := newFingerprintServer(t)
+
+	c := NewClient(models.StashBox{Endpoint: server.URL}, &mocks.TxnManager{}, Repository{})
+	cache := &testFingerprintCache{entries: make(map[string]models.StashBoxFingerprintCacheEntry)}
+	c.SetFingerprintCache(cache, time.Hour)
+
+	sets := makeFingerprintSets(45)
+	// empty and duplicate sets are not queried
+	sets = append(sets, nil, sets[0])
+
+	got, err := c.findScenesByFingerprints(ctx, sets)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, []int{40, 5}, *queries)
+	assert.Len(t, got, len(sets))
+	assert.Equal(t, "hash44", got[44][0].ID)
+	assert.Nil(t, got[45])
+	assert.Equal(t, "hash0", got[46][0].ID)
+	assert.Len(t, cache.entries, 45)
+
+	// cached sets are not queried again
+	sets = makeFingerprintSets(50)
+	got, err = c.findScenesByFingerprints(ctx, sets)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, []int{40, 5, 5}, *queries)
+	assert.Equal(t, "hash0", got[0][0].ID)
+	assert.Equal(t, "hash49", got[49][0].ID)
+}
+
+func TestClient_findScenesByFingerprints_expired(t *testing.T) {
+	ctx := context.Background()
+	server, queries := newFingerprintServer(t)
+
+	c := NewClient(models.StashBox{Endpoint: server.URL}, &mocks.TxnManager{}, Repository{})
+	cache := &testFingerprintCache{entries: make(map[string]models.StashBoxFingerprintCacheEntry)}
+	c.SetFingerprintCache(cache, time.Hour)
+
+	sets := makeFingerprintSets(1)
+	cache.entries[server.URL+fingerprintKey(sets[0])] = models.StashBoxFingerprintCacheEntry{
+		Endpoint:  server.URL,
+		Key:       fingerprintKey(sets[0]),
+		Data:      []byte("[]"),
+		CreatedAt: time.Now().Add(-2 * time.Hour),
+	}
+
+	got, err := c.findScenesByFingerprints(ctx, sets)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, []int{1}, *queries)
+	assert.Equal(t, "hash0", got[0][0].ID)
+}
+
+func TestFingerprintKey(t *testing.T) {
+	a := []*graphql.FingerprintQueryInput{
+		{Hash: "a", Algorithm: graphql.FingerprintAlgorithmMd5},
+		{Hash: "b", Algorithm: graphql.FingerprintAlgorithmOshash},
+	}
+	b := []*graphql.FingerprintQueryInput{a[1], a[0]}
+	c := []*graphql.FingerprintQueryInput{
+		{Hash: "a", Algorithm: graphql.FingerprintAlgorithmOshash},
+		{Hash: "b", Algorithm: graphql.FingerprintAlgorithmMd5},
+	}
+
+	assert.Equal(t, fingerprintKey(a), fingerprintKey(b))
+	assert.NotEqual(t, fingerprintKey(a), fingerprintKey(c))
+}
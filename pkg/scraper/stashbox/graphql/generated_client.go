@@ -16,6 +16,7 @@ type StashBoxGraphQLClient interface {
 	SearchScene(ctx context.Context, term string, httpRequestOptions ...client.HTTPRequestOption) (*SearchScene, error)
 	SearchPerformer(ctx context.Context, term string, httpRequestOptions ...client.HTTPRequestOption) (*SearchPerformer, error)
 	FindPerformerByID(ctx context.Context, id string, httpRequestOptions ...client.HTTPRequestOption) (*FindPerformerByID, error)
+	FindStudioByID(ctx context.Context, id string, httpRequestOptions ...client.HTTPRequestOption) (*FindStudioByID, error)
 	FindSceneByID(ctx context.Context, id string, httpRequestOptions ...client.HTTPRequestOption) (*FindSceneByID, error)
 	SubmitFingerprint(ctx context.Context, input FingerprintSubmission, httpRequestOptions ...client.HTTPRequestOption) (*SubmitFingerprint, error)
 	Me(ctx context.Context, httpRequestOptions ...client.HTTPRequestOption) (*Me, error)
@@ -212,6 +213,9 @@ type SearchPerformer struct {
 type FindPerformerByID struct {
 	FindPerformer *PerformerFragment "json:\"findPerformer\" graphql:\"findPerformer\""
 }
+type FindStudioByID struct {
+	FindStudio *StudioFragment "json:\"findStudio\" graphql:\"findStudio\""
+}
 type FindSceneByID struct {
 	FindScene *SceneFragment "json:\"findScene\" graphql:\"findScene\""
 }
@@ -910,6 +914,46 @@ func (c *Client) FindPerformerByID(ctx context.Context, id string, httpRequestOp
 	return &res, nil
 }
 
+const FindStudioByIDDocument = `query FindStudioByID ($id: ID!) {
+	findStudio(id: $id) {
+		... StudioFragment
+	}
+}
+fragment StudioFragment on Studio {
+	name
+	id
+	urls {
+		... URLFragment
+	}
+	images {
+		... ImageFragment
+	}
+}
+fragment URLFragment on URL {
+	url
+	type
+}
+fragment ImageFragment on Image {
+	id
+	url
+	width
+	height
+}
+`
+
+func (c *Client) FindStudioByID(ctx context.Context, id string, httpRequestOptions ...client.HTTPRequestOption) (*FindStudioByID, error) {
+	vars := map[string]interface{}{
+		"id": id,
+	}
+
+	var res FindStudioByID
+	if err := c.Client.Post(ctx, "FindStudioByID", FindStudioByIDDocument, &res, vars, httpRequestOptions...); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
 const FindSceneByIDDocument = `query FindSceneByID ($id: ID!) {
 	findScene(id: $id) {
 		... SceneFragment
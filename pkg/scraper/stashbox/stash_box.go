@@ -13,6 +13,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Yamashou/gqlgenc/client"
 	"golang.org/x/text/cases"
@@ -37,6 +38,7 @@ type SceneReader interface {
 	Find(ctx context.Context, id int) (*models.Scene, error)
 	models.StashIDLoader
 	models.VideoFileLoader
+	GetCover(ctx context.Context, sceneID int) ([]byte, error)
 }
 
 type PerformerReader interface {
@@ -65,22 +67,42 @@ type Repository struct {
 	Studio    StudioReader
 }
 
+// FingerprintCache stores the results of fingerprint queries.
+type FingerprintCache interface {
+	FindMany(ctx context.Context, endpoint string, keys []string, t time.Time) ([]*models.StashBoxFingerprintCacheEntry, error)
+	Set(ctx context.Context, entry models.StashBoxFingerprintCacheEntry) error
+}
+
 // Client represents the client interface to a stash-box server instance.
 type Client struct {
 	client     *graphql.Client
 	txnManager txn.Manager
 	repository Repository
 	box        models.StashBox
+
+	fingerprintCache    FingerprintCache
+	fingerprintCacheTTL time.Duration
 }
 
-// NewClient returns a new instance of a stash-box client.
+// NewClient returns a new instance of a stash-box client. Requests are
+// throttled if the stash-box has a maximum number of requests per minute.
 func NewClient(box models.StashBox, txnManager txn.Manager, repo Repository) *Client {
 	authHeader := func(req *http.Request) {
 		req.Header.Set("ApiKey", box.APIKey)
 	}
 
+	httpClient := http.DefaultClient
+	if box.MaxRequestsPerMinute > 0 {
+		httpClient = &http.Client{
+			Transport: throttledTransport{
+				throttle: endpointThrottle(box.Endpoint, box.MaxRequestsPerMinute),
+				base:     http.DefaultTransport,
+			},
+		}
+	}
+
 	client := &graphql.Client{
-		Client: client.NewClient(http.DefaultClient, box.Endpoint, authHeader),
+		Client: client.NewClient(httpClient, box.Endpoint, authHeader),
 	}
 
 	return &Client{
@@ -126,10 +148,60 @@ func (c Client) FindStashBoxSceneByFingerprints(ctx context.Context, sceneID int
 	return nil, err
 }
 
+// SetFingerprintCache enables the caching of the results of fingerprint
+// queries for the duration of ttl.
+func (c *Client) SetFingerprintCache(cache FingerprintCache, ttl time.Duration) {
+	c.fingerprintCache = cache
+	c.fingerprintCacheTTL = ttl
+}
+
 // FindStashBoxScenesByFingerprints queries stash-box for scenes using every
 // scene's MD5/OSHASH checksum, or PHash, and returns results in the same order
 // as the input slice.
 func (c Client) FindStashBoxScenesByFingerprints(ctx context.Context, ids []int) ([][]*scraper.ScrapedScene, error) {
+	fingerprints, err := c.sceneFingerprints(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	fragments, err := c.findScenesByFingerprints(ctx, fingerprints)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([][]*scraper.ScrapedScene, len(fragments))
+	for i, sceneFragments := range fragments {
+		for _, scene := range sceneFragments {
+			ss, err := c.sceneFragmentToScrapedScene(ctx, scene)
+			if err != nil {
+				return nil, err
+			}
+			ret[i] = append(ret[i], ss)
+		}
+	}
+
+	return ret, nil
+}
+
+// CacheScenesByFingerprints queries stash-box for the scenes whose results
+// are not already cached, so that later queries for the scenes are answered
+// from the cache. Does nothing if the fingerprint cache is not enabled.
+func (c Client) CacheScenesByFingerprints(ctx context.Context, ids []int) error {
+	if !c.fingerprintCacheEnabled() {
+		return nil
+	}
+
+	fingerprints, err := c.sceneFingerprints(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.findScenesByFingerprints(ctx, fingerprints)
+	return err
+}
+
+// sceneFingerprints returns the fingerprints of the files of each scene.
+func (c Client) sceneFingerprints(ctx context.Context, ids []int) ([][]*graphql.FingerprintQueryInput, error) {
 	var fingerprints [][]*graphql.FingerprintQueryInput
 
 	if err := txn.WithReadTxn(ctx, c.txnManager, func(ctx context.Context) error {
@@ -186,56 +258,7 @@ func (c Client) FindStashBoxScenesByFingerprints(ctx context.Context, ids []int)
 		return nil, err
 	}
 
-	return c.findStashBoxScenesByFingerprints(ctx, fingerprints)
-}
-
-func (c Client) findStashBoxScenesByFingerprints(ctx context.Context, scenes [][]*graphql.FingerprintQueryInput) ([][]*scraper.ScrapedScene, error) {
-	var results [][]*scraper.ScrapedScene
-
-	// filter out nils
-	var validScenes [][]*graphql.FingerprintQueryInput
-	for _, s := range scenes {
-		if len(s) > 0 {
-			validScenes = append(validScenes, s)
-		}
-	}
-
-	for i := 0; i < len(validScenes); i += 40 {
-		end := i + 40
-		if end > len(validScenes) {
-			end = len(validScenes)
-		}
-		scenes, err := c.client.FindScenesBySceneFingerprints(ctx, validScenes[i:end])
-
-		if err != nil {
-			return nil, err
-		}
-
-		for _, sceneFragments := range scenes.FindScenesBySceneFingerprints {
-			var sceneResults []*scraper.ScrapedScene
-			for _, scene := range sceneFragments {
-				ss, err := c.sceneFragmentToScrapedScene(ctx, scene)
-				if err != nil {
-					return nil, err
-				}
-				sceneResults = append(sceneResults, ss)
-			}
-			results = append(results, sceneResults)
-		}
-	}
-
-	// repopulate the results to be the same order as the input
-	ret := make([][]*scraper.ScrapedScene, len(scenes))
-	upTo := 0
-
-	for i, v := range scenes {
-		if len(v) > 0 {
-			ret[i] = results[upTo]
-			upTo++
-		}
-	}
-
-	return results, nil
+	return fingerprints, nil
 }
 
 func (c Client) SubmitStashBoxFingerprints(ctx context.Context, sceneIDs []string, endpoint string) (bool, error) {
@@ -763,10 +786,42 @@ func (c Client) FindStashBoxPerformerByID(ctx context.Context, id string) (*mode
 		return nil, err
 	}
 
+	if performer.FindPerformer == nil {
+		return nil, nil
+	}
+
 	ret := performerFragmentToScrapedScenePerformer(*performer.FindPerformer)
 	return ret, nil
 }
 
+// FindStashBoxStudioByID returns the studio with the stash-box id, or nil if
+// it does not exist. The image of the returned studio is the URL of the
+// first studio image.
+func (c Client) FindStashBoxStudioByID(ctx context.Context, id string) (*models.ScrapedStudio, error) {
+	studio, err := c.client.FindStudioByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if studio.FindStudio == nil {
+		return nil, nil
+	}
+
+	s := studio.FindStudio
+	studioID := s.ID
+	ret := &models.ScrapedStudio{
+		Name:         s.Name,
+		URL:          findURL(s.Urls, "HOME"),
+		RemoteSiteID: &studioID,
+	}
+
+	if len(s.Images) > 0 {
+		ret.Image = &s.Images[0].URL
+	}
+
+	return ret, nil
+}
+
 func (c Client) FindStashBoxPerformerByName(ctx context.Context, name string) (*models.ScrapedPerformer, error) {
 	performers, err := c.client.SearchPerformer(ctx, name)
 	if err != nil {
@@ -921,11 +976,17 @@ func (c Client) SubmitSceneDraft(ctx context.Context, scene *models.Scene, endpo
 	}
 	draft.Tags = tags
 
-	if imagePath != "" {
+	// prefer the cover set on the scene, falling back to the generated
+	// screenshot
+	cover, _ := r.Scene.GetCover(ctx, scene.ID)
+	if len(cover) > 0 {
+		image = bytes.NewReader(cover)
+	} else if imagePath != "" {
 		exists, _ := fsutil.FileExists(imagePath)
 		if exists {
 			file, err := os.Open(imagePath)
 			if err == nil {
+				defer file.Close()
 				image = file
 			}
 		}
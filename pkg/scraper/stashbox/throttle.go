@@ -0,0 +1,87 @@
+package stashbox
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// throttle spaces out requests so that no more than a maximum number of
+// requests per minute are made.
+type throttle struct {
+	mutex    sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func newThrottle(requestsPerMinute int) *throttle {
+	return &throttle{
+		interval: time.Minute / time.Duration(requestsPerMinute),
+	}
+}
+
+// reserve reserves the next request slot at or after now, returning how long
+// to wait before making the request.
+func (t *throttle) reserve(now time.Time) time.Duration {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	at := t.next
+	if at.Before(now) {
+		at = now
+	}
+	t.next = at.Add(t.interval)
+
+	return at.Sub(now)
+}
+
+// wait blocks until a request can be made, or the context is cancelled.
+func (t *throttle) wait(ctx context.Context) error {
+	d := t.reserve(time.Now())
+	if d <= 0 {
+		return nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
+
+var (
+	throttlesMutex sync.Mutex
+	// throttles are shared by all clients of the same endpoint
+	throttles = make(map[string]*throttle)
+)
+
+// endpointThrottle returns the throttle of the endpoint, replacing it if the
+// limit has changed.
+func endpointThrottle(endpoint string, requestsPerMinute int) *throttle {
+	throttlesMutex.Lock()
+	defer throttlesMutex.Unlock()
+
+	t := throttles[endpoint]
+	if t == nil || t.interval != time.Minute/time.Duration(requestsPerMinute) {
+		t = newThrottle(requestsPerMinute)
+		throttles[endpoint] = t
+	}
+
+	return t
+}
+
+// throttledTransport is a http.RoundTripper which throttles requests.
+type throttledTransport struct {
+	throttle *throttle
+	base     http.RoundTripper
+}
+
+func (t throttledTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.throttle.wait(req.Context()); err != nil {
+		return nil, err
+	}
+
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,40 @@
+package stashbox
+
+import (
+	"testing"
+	"time"
+)
+
+func TestThrottle_reserve(t *testing.T) {
+	th := newThrottle(60)
+	start := time.Now()
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want time.Duration
+	}{
+		{"first request", start, 0},
+		{"immediate second request", start, time.Second},
+		{"third request", start.Add(500 * time.Millisecond), 1500 * time.Millisecond},
+		{"after idle", start.Add(10 * time.Second), 0},
+	}
+
+	for _, tt := range tests {
+		if got := th.reserve(tt.now); got != tt.want {
+			t.Errorf("%s: reserve() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEndpointThrottle(t *testing.T) {
+	const endpoint = "https://stashbox.test/graphql"
+
+	a := endpointThrottle(endpoint, 60)
+	if b := endpointThrottle(endpoint, 60); a != b {
+		t.Error("endpointThrottle() returned a new throttle for the same limit")
+	}
+	if c := endpointThrottle(endpoint, 120); a == c {
+		t.Error("endpointThrottle() did not replace the throttle for a new limit")
+	}
+}
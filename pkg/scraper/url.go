@@ -9,25 +9,31 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/emulation"
 	"github.com/chromedp/cdproto/network"
 	"github.com/chromedp/chromedp"
 	jsoniter "github.com/json-iterator/go"
 	"golang.org/x/net/html/charset"
 
+	"github.com/stashapp/stash/pkg/fsutil"
 	"github.com/stashapp/stash/pkg/logger"
 )
 
-const scrapeDefaultSleep = time.Second * 2
+const (
+	scrapeDefaultSleep      = time.Second * 2
+	scrapeScreenshotTimeout = time.Second * 10
+)
 
 func loadURL(ctx context.Context, loadURL string, client *http.Client, scraperConfig config, globalConfig GlobalConfig) (io.Reader, error) {
 	driverOptions := scraperConfig.DriverOptions
 	if driverOptions != nil && driverOptions.UseCDP {
 		// get the page using chrome dp
-		return urlFromCDP(ctx, loadURL, *driverOptions, globalConfig)
+		return urlFromCDP(ctx, loadURL, scraperConfig, globalConfig)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, loadURL, nil)
@@ -45,13 +51,21 @@ func loadURL(ctx context.Context, loadURL string, client *http.Client, scraperCo
 		return nil, fmt.Errorf("error parsing url %s: %w", loadURL, err)
 	}
 
+	sessions := newSessionStore(scraperConfig, globalConfig)
+	if sessions != nil {
+		// cookies set by the site replace the configured cookies
+		if err := sessions.setJarCookies(jar); err != nil {
+			logger.Warnf("[scraper] could not load session cookies: %v", err)
+		}
+	}
+
 	// Fetch relevant cookies from the jar for url u and add them to the request
 	cookies := jar.Cookies(u)
 	for _, cookie := range cookies {
 		req.AddCookie(cookie)
 	}
 
-	userAgent := globalConfig.GetScraperUserAgent()
+	userAgent := scraperConfig.userAgent(globalConfig)
 	if userAgent != "" {
 		req.Header.Set("User-Agent", userAgent)
 	}
@@ -69,6 +83,13 @@ func loadURL(ctx context.Context, loadURL string, client *http.Client, scraperCo
 	if err != nil {
 		return nil, err
 	}
+
+	if sessions != nil {
+		if err := sessions.save(sessionCookiesFromHTTP(resp.Request.URL, resp.Cookies())); err != nil {
+			logger.Warnf("[scraper] could not save session cookies: %v", err)
+		}
+	}
+
 	if resp.StatusCode >= 400 {
 		return nil, fmt.Errorf("http error %d:%s", resp.StatusCode, http.StatusText(resp.StatusCode))
 	}
@@ -88,8 +109,8 @@ func loadURL(ctx context.Context, loadURL string, client *http.Client, scraperCo
 // func urlFromCDP uses chrome cdp and DOM to load and process the url
 // if remote is set as true in the scraperConfig  it will try to use localhost:9222
 // else it will look for google-chrome in path
-func urlFromCDP(ctx context.Context, urlCDP string, driverOptions scraperDriverOptions, globalConfig GlobalConfig) (io.Reader, error) {
-
+func urlFromCDP(ctx context.Context, urlCDP string, scraperConfig config, globalConfig GlobalConfig) (io.Reader, error) {
+	driverOptions := *scraperConfig.DriverOptions
 	if !driverOptions.UseCDP {
 		return nil, fmt.Errorf("url shouldn't be fetched through CDP")
 	}
@@ -163,19 +184,28 @@ func urlFromCDP(ctx context.Context, urlCDP string, driverOptions scraperDriverO
 		defer cancelAct()
 	}
 
-	ctx, cancel := chromedp.NewContext(ctx)
+	tabCtx, cancel := chromedp.NewContext(ctx)
 	defer cancel()
 
+	// start the browser without a timeout, as a timeout on the first run
+	// stops the browser, which prevents screenshots of timed out scrapes
+	if err := chromedp.Run(tabCtx); err != nil {
+		return nil, err
+	}
+
 	// add a fixed timeout for the http request
-	ctx, cancel = context.WithTimeout(ctx, scrapeGetTimeout)
+	ctx, cancel = context.WithTimeout(tabCtx, scrapeGetTimeout)
 	defer cancel()
 
 	var res string
 	headers := cdpHeaders(driverOptions)
+	sessions := newSessionStore(scraperConfig, globalConfig)
 
 	err := chromedp.Run(ctx,
 		network.Enable(),
+		setCDPUserAgent(scraperConfig.userAgent(globalConfig)),
 		setCDPCookies(driverOptions),
+		setCDPSessionCookies(sessions),
 		printCDPCookies(driverOptions, "Cookies found"),
 		network.SetExtraHTTPHeaders(network.Headers(headers)),
 		chromedp.Navigate(urlCDP),
@@ -183,15 +213,73 @@ func urlFromCDP(ctx context.Context, urlCDP string, driverOptions scraperDriverO
 		setCDPClicks(driverOptions),
 		chromedp.OuterHTML("html", &res, chromedp.ByQuery),
 		printCDPCookies(driverOptions, "Cookies set"),
+		saveCDPSessionCookies(sessions, urlCDP),
 	)
 
 	if err != nil {
+		if driverOptions.ScreenshotOnFailure {
+			saveCDPScreenshot(tabCtx, scraperConfig.ID, globalConfig)
+		}
 		return nil, err
 	}
 
 	return strings.NewReader(res), nil
 }
 
+// userAgent returns the user agent of the requests made by the scraper.
+func (c config) userAgent(globalConfig GlobalConfig) string {
+	if c.DriverOptions != nil && c.DriverOptions.UserAgent != "" {
+		return c.DriverOptions.UserAgent
+	}
+
+	return globalConfig.GetScraperUserAgent()
+}
+
+// setCDPUserAgent overrides the user agent of the browser, if set.
+func setCDPUserAgent(userAgent string) chromedp.Action {
+	return chromedp.ActionFunc(func(ctx context.Context) error {
+		if userAgent == "" {
+			return nil
+		}
+
+		return emulation.SetUserAgentOverride(userAgent).Do(ctx)
+	})
+}
+
+// saveCDPScreenshot saves a screenshot of the page in tabCtx to the cache
+// directory, for debugging failed scrapes. tabCtx must not be the context
+// that timed out.
+func saveCDPScreenshot(tabCtx context.Context, scraperID string, globalConfig GlobalConfig) {
+	cachePath := globalConfig.GetCachePath()
+	if cachePath == "" {
+		logger.Warnf("[scraper] %s: not saving screenshot as the cache path is not set", scraperID)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(tabCtx, scrapeScreenshotTimeout)
+	defer cancel()
+
+	var buf []byte
+	if err := chromedp.Run(ctx, chromedp.FullScreenshot(&buf, 100)); err != nil {
+		logger.Warnf("[scraper] %s: could not take screenshot of failed scrape: %v", scraperID, err)
+		return
+	}
+
+	dir := filepath.Join(cachePath, "scrapers", "screenshots")
+	if err := fsutil.EnsureDirAll(dir); err != nil {
+		logger.Warnf("[scraper] %s: could not create screenshot directory: %v", scraperID, err)
+		return
+	}
+
+	fn := filepath.Join(dir, fmt.Sprintf("%s-%s.png", scraperID, time.Now().Format("20060102-150405")))
+	if err := os.WriteFile(fn, buf, 0644); err != nil {
+		logger.Warnf("[scraper] %s: could not write screenshot: %v", scraperID, err)
+		return
+	}
+
+	logger.Infof("[scraper] %s: saved screenshot of failed scrape to %s", scraperID, fn)
+}
+
 // click all xpaths listed in the scraper config
 func setCDPClicks(driverOptions scraperDriverOptions) chromedp.Tasks {
 	var tasks chromedp.Tasks
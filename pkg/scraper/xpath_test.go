@@ -834,6 +834,18 @@ func (mockGlobalConfig) GetPythonPath() string {
 	return ""
 }
 
+func (mockGlobalConfig) GetScraperRateLimits() []*RateLimit {
+	return nil
+}
+
+func (mockGlobalConfig) GetScraperCacheDays() int {
+	return 0
+}
+
+func (mockGlobalConfig) GetCachePath() string {
+	return ""
+}
+
 func TestSubScrape(t *testing.T) {
 	retHTML := `
 	<div>
@@ -0,0 +1,256 @@
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const (
+	externalLoginProviderKey = "externalLoginProvider"
+	externalLoginStateKey    = "externalLoginState"
+	externalLoginNonceKey    = "externalLoginNonce"
+	externalLoginVerifierKey = "externalLoginVerifier"
+	externalLoginReturnKey   = "externalLoginReturnURL"
+)
+
+var (
+	ErrUnknownAuthProvider   = errors.New("unknown authentication provider")
+	ErrInvalidExternalLogin  = errors.New("invalid or expired login attempt")
+	ErrExternalUserNotLinked = errors.New("no user is linked to the external account")
+)
+
+// ExternalIdentity is the identity of a user authenticated by an
+// authentication provider.
+type ExternalIdentity struct {
+	// Subject uniquely identifies the user within the provider.
+	Subject  string
+	Username string
+	Email    string
+}
+
+// AuthProvider authenticates users using an external identity provider,
+// using the authorization code flow.
+type AuthProvider interface {
+	// ID returns the identifier of the provider used in URLs and stored
+	// with the linked identities.
+	ID() string
+	// Name returns the name of the provider shown on the login page.
+	Name() string
+	// AutoRegisterRole returns the role of the users created when an
+	// unknown external user logs in. Unknown users are refused if empty.
+	AutoRegisterRole() models.UserRole
+	// AuthCodeURL returns the URL the user is redirected to to log in.
+	AuthCodeURL(ctx context.Context, redirectURL, state, nonce, codeVerifier string) (string, error)
+	// Exchange exchanges the authorization code returned to the redirect
+	// URL for the identity of the user.
+	Exchange(ctx context.Context, redirectURL, code, nonce, codeVerifier string) (*ExternalIdentity, error)
+}
+
+// ExternalUserStore maps external identities to local users.
+type ExternalUserStore interface {
+	FindUserByExternalIdentity(ctx context.Context, provider, subject string) (*models.User, error)
+	// RegisterExternalUser creates a user with the provided role and links
+	// the external identity to it.
+	RegisterExternalUser(ctx context.Context, provider string, identity ExternalIdentity, role models.UserRole) (*models.User, error)
+}
+
+// SetAuthProviders sets the external authentication providers users can
+// log in with.
+func (s *Store) SetAuthProviders(providers []AuthProvider) {
+	s.authProviders = providers
+}
+
+// AuthProviders returns the external authentication providers users can
+// log in with.
+func (s *Store) AuthProviders() []AuthProvider {
+	return s.authProviders
+}
+
+// SetExternalUserStore sets the store used to map external identities to
+// users. Users cannot log in with external providers if it is not set.
+func (s *Store) SetExternalUserStore(st ExternalUserStore) {
+	s.externalUsers = st
+}
+
+func (s *Store) getAuthProvider(id string) AuthProvider {
+	for _, p := range s.authProviders {
+		if p.ID() == id {
+			return p
+		}
+	}
+
+	return nil
+}
+
+// randomToken returns a random URL-safe token.
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// pkceChallenge returns the S256 PKCE code challenge of the verifier.
+func pkceChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// isLocalURL returns true if u is a path on this server, so that it is safe
+// to redirect to after logging in.
+func isLocalURL(u string) bool {
+	return strings.HasPrefix(u, "/") && !strings.HasPrefix(u, "//") && !strings.HasPrefix(u, "/\\")
+}
+
+// BeginExternalLogin starts logging in with the provider, returning the URL
+// of the provider the user must be redirected to. The provider redirects
+// back to redirectURL, which must call CompleteExternalLogin.
+func (s *Store) BeginExternalLogin(w http.ResponseWriter, r *http.Request, providerID, redirectURL, returnURL string) (string, error) {
+	p := s.getAuthProvider(providerID)
+	if p == nil {
+		return "", ErrUnknownAuthProvider
+	}
+
+	var tokens [3]string
+	for i := range tokens {
+		t, err := randomToken()
+		if err != nil {
+			return "", err
+		}
+		tokens[i] = t
+	}
+	state, nonce, verifier := tokens[0], tokens[1], tokens[2]
+
+	u, err := p.AuthCodeURL(r.Context(), redirectURL, state, nonce, verifier)
+	if err != nil {
+		return "", err
+	}
+
+	if !isLocalURL(returnURL) {
+		returnURL = ""
+	}
+
+	// ignore error - we want a new session regardless
+	session, _ := s.sessionStore.Get(r, cookieName)
+	session.Values[externalLoginProviderKey] = providerID
+	session.Values[externalLoginStateKey] = state
+	session.Values[externalLoginNonceKey] = nonce
+	session.Values[externalLoginVerifierKey] = verifier
+	session.Values[externalLoginReturnKey] = returnURL
+
+	if err := session.Save(r, w); err != nil {
+		return "", err
+	}
+
+	return u, nil
+}
+
+// CompleteExternalLogin handles the redirect back from the provider, logging
+// in the user linked to the external identity. Returns the URL the user
+// requested when starting to log in, which may be empty.
+func (s *Store) CompleteExternalLogin(w http.ResponseWriter, r *http.Request, providerID, redirectURL string) (string, error) {
+	p := s.getAuthProvider(providerID)
+	if p == nil {
+		return "", ErrUnknownAuthProvider
+	}
+
+	session, err := s.sessionStore.Get(r, cookieName)
+	if err != nil {
+		return "", ErrInvalidExternalLogin
+	}
+
+	wantProvider, _ := session.Values[externalLoginProviderKey].(string)
+	wantState, _ := session.Values[externalLoginStateKey].(string)
+	nonce, _ := session.Values[externalLoginNonceKey].(string)
+	verifier, _ := session.Values[externalLoginVerifierKey].(string)
+	returnURL, _ := session.Values[externalLoginReturnKey].(string)
+
+	// the login attempt can only be completed once
+	delete(session.Values, externalLoginProviderKey)
+	delete(session.Values, externalLoginStateKey)
+	delete(session.Values, externalLoginNonceKey)
+	delete(session.Values, externalLoginVerifierKey)
+	delete(session.Values, externalLoginReturnKey)
+
+	u, err := s.authenticateExternalLogin(r, p, wantProvider, wantState, nonce, verifier, redirectURL)
+	if err == nil {
+		session.Values[userIDKey] = u.Username
+
+		// logging in again unlocks a locked session
+		unlockSession(session)
+	}
+
+	if saveErr := session.Save(r, w); saveErr != nil && err == nil {
+		err = saveErr
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return returnURL, nil
+}
+
+// authenticateExternalLogin validates the redirect back from the provider
+// against the login attempt stored in the session, returning the user linked
+// to the external identity.
+func (s *Store) authenticateExternalLogin(r *http.Request, p AuthProvider, wantProvider, wantState, nonce, verifier, redirectURL string) (*models.User, error) {
+	q := r.URL.Query()
+	state := q.Get("state")
+	if wantState == "" || wantProvider != p.ID() || subtle.ConstantTimeCompare([]byte(state), []byte(wantState)) != 1 {
+		return nil, ErrInvalidExternalLogin
+	}
+
+	if e := q.Get("error"); e != "" {
+		return nil, fmt.Errorf("%s: %s %s", p.Name(), e, q.Get("error_description"))
+	}
+
+	identity, err := p.Exchange(r.Context(), redirectURL, q.Get("code"), nonce, verifier)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.findExternalUser(r.Context(), p, *identity)
+}
+
+// findExternalUser returns the user linked to the external identity,
+// registering a new user if the provider allows it.
+func (s *Store) findExternalUser(ctx context.Context, p AuthProvider, identity ExternalIdentity) (*models.User, error) {
+	if s.externalUsers == nil {
+		return nil, ErrExternalUserNotLinked
+	}
+
+	u, err := s.externalUsers.FindUserByExternalIdentity(ctx, p.ID(), identity.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	if u != nil {
+		return u, nil
+	}
+
+	role := p.AutoRegisterRole()
+	if role == "" {
+		logger.Infof("%s user %q (%s) is not linked to a user", p.Name(), identity.Username, identity.Subject)
+		return nil, ErrExternalUserNotLinked
+	}
+
+	u, err = s.externalUsers.RegisterExternalUser(ctx, p.ID(), identity, role)
+	if err != nil {
+		return nil, fmt.Errorf("registering %s user %q: %w", p.Name(), identity.Username, err)
+	}
+
+	logger.Infof("Registered user %s for %s user %s", u.Username, p.Name(), identity.Subject)
+	return u, nil
+}
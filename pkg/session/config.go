@@ -1,5 +1,7 @@
 package session
 
+import "time"
+
 type ExternalAccessConfig interface {
 	HasCredentials() bool
 	GetDangerousAllowPublicWithoutAuth() bool
@@ -14,4 +16,14 @@ type SessionConfig interface {
 	GetSessionStoreKey() []byte
 	GetMaxSessionAge() int
 	ValidateCredentials(username string, password string) bool
+
+	IsSessionLockEnabled() bool
+	GetSessionLockTimeout() time.Duration
+	ValidateSessionLockPin(pin string) bool
+	GetQuickHideSafeTags() []int
+}
+
+type MediaAccessConfig interface {
+	GetMediaAllowedSubnets() []string
+	GetMediaAccessToken() string
 }
@@ -0,0 +1,59 @@
+package session
+
+import (
+	"net/http"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const contentWarningsUnlockedKey = "contentWarningsUnlocked"
+
+// UnlockContentWarnings validates the password provided in the request and,
+// if valid, unlocks content warning tagged content for the session.
+func (s *Store) UnlockContentWarnings(w http.ResponseWriter, r *http.Request) error {
+	// ignore error - we want a new session regardless
+	session, _ := s.sessionStore.Get(r, cookieName)
+
+	password := r.FormValue(passwordFormKey)
+	if !s.config.ValidateCredentials(s.config.GetUsername(), password) {
+		return ErrInvalidCredentials
+	}
+
+	session.Values[contentWarningsUnlockedKey] = true
+
+	return session.Save(r, w)
+}
+
+// LockContentWarnings hides content warning tagged content for the session.
+func (s *Store) LockContentWarnings(w http.ResponseWriter, r *http.Request) error {
+	session, err := s.sessionStore.Get(r, cookieName)
+	if err != nil {
+		return err
+	}
+
+	delete(session.Values, contentWarningsUnlockedKey)
+
+	return session.Save(r, w)
+}
+
+// ContentWarningsHandler hides content warning tagged content from the request
+// context, unless it has been unlocked for the session.
+func (s *Store) ContentWarningsHandler() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			unlocked := false
+
+			// treat errors as locked
+			session, err := s.getSession(r)
+			if err == nil {
+				unlocked, _ = session.Values[contentWarningsUnlockedKey].(bool)
+			}
+
+			if !unlocked {
+				r = r.WithContext(models.HideContentWarnings(r.Context()))
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package session
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+const (
+	MediaTokenHeader    = "X-Media-Token"
+	MediaTokenParameter = "media_token"
+)
+
+var ErrMediaAccessDenied = errors.New("media access denied")
+
+// ParseSubnets parses the provided subnets in CIDR notation. Single IP
+// addresses are treated as subnets containing only that address.
+func ParseSubnets(subnets []string) ([]*net.IPNet, error) {
+	var ret []*net.IPNet
+	for _, s := range subnets {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+
+		if !strings.Contains(s, "/") {
+			ip := net.ParseIP(s)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid IP address %q", s)
+			}
+
+			bits := 8 * net.IPv4len
+			if ip.To4() == nil {
+				bits = 8 * net.IPv6len
+			}
+			s = fmt.Sprintf("%s/%d", s, bits)
+		}
+
+		_, subnet, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid subnet %q: %w", s, err)
+		}
+
+		ret = append(ret, subnet)
+	}
+
+	return ret, nil
+}
+
+// clientIPs returns the IP addresses of the client making the request. The
+// X-Forwarded-For header is only trusted when the request was made by a
+// proxy on the local network, in which case every address in the proxy chain
+// is returned. Addresses that cannot be parsed are returned as nil.
+func clientIPs(r *http.Request) ([]net.IP, error) {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing remote host (%s): %w", r.RemoteAddr, err)
+	}
+
+	// presence of scope ID in IPv6 addresses prevents parsing. Remove if present
+	if i := strings.Index(host, "%"); i != -1 {
+		host = host[0:i]
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return nil, fmt.Errorf("unable to parse remote host (%s)", host)
+	}
+
+	forwarded := r.Header.Get("X-FORWARDED-FOR")
+	if forwarded == "" || !isLocalIP(ip) {
+		return []net.IP{ip}, nil
+	}
+
+	// the client controls the leading entries, so every hop must be checked
+	var ret []net.IP
+	for _, hop := range strings.Split(forwarded, ",") {
+		ret = append(ret, net.ParseIP(strings.TrimSpace(hop)))
+	}
+
+	return ret, nil
+}
+
+func ipAllowed(ip net.IP, subnets []*net.IPNet) bool {
+	if ip == nil {
+		return false
+	}
+
+	if len(subnets) == 0 {
+		return isLocalIP(ip)
+	}
+
+	for _, subnet := range subnets {
+		if subnet.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func requestMediaToken(r *http.Request) string {
+	token := r.Header.Get(MediaTokenHeader)
+	if token == "" {
+		token = r.URL.Query().Get(MediaTokenParameter)
+	}
+
+	return token
+}
+
+// CheckMediaAccess returns ErrMediaAccessDenied if the request is not
+// permitted to access media files. Requests from the allowed subnets are
+// always permitted. If no subnets are configured, then requests from the
+// local network are permitted. Proxied requests are only permitted if every
+// address in the proxy chain is permitted. Other requests must provide the media access
+// token, if one is configured. If neither subnets nor a token are configured,
+// then all requests are permitted.
+func CheckMediaAccess(c MediaAccessConfig, r *http.Request) error {
+	token := c.GetMediaAccessToken()
+	subnets, err := ParseSubnets(c.GetMediaAllowedSubnets())
+	if err != nil {
+		return err
+	}
+
+	if token == "" && len(subnets) == 0 {
+		return nil
+	}
+
+	ips, err := clientIPs(r)
+	if err != nil {
+		return err
+	}
+
+	allowed := true
+	for _, ip := range ips {
+		if !ipAllowed(ip, subnets) {
+			allowed = false
+			break
+		}
+	}
+
+	if allowed {
+		return nil
+	}
+
+	if token != "" && subtle.ConstantTimeCompare([]byte(requestMediaToken(r)), []byte(token)) == 1 {
+		return nil
+	}
+
+	return ErrMediaAccessDenied
+}
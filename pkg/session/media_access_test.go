@@ -0,0 +1,91 @@
+package session
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+type mediaConfig struct {
+	subnets []string
+	token   string
+}
+
+func (c *mediaConfig) GetMediaAllowedSubnets() []string {
+	return c.subnets
+}
+
+func (c *mediaConfig) GetMediaAccessToken() string {
+	return c.token
+}
+
+func TestCheckMediaAccess(t *testing.T) {
+	const (
+		local  = "192.168.1.2:8080"
+		public = "193.168.1.2:8080"
+		token  = "secret"
+	)
+
+	testCases := []struct {
+		name      string
+		config    mediaConfig
+		address   string
+		forwarded string
+		token     string
+		err       error
+	}{
+		{"unrestricted", mediaConfig{}, public, "", "", nil},
+		{"token local", mediaConfig{token: token}, local, "", "", nil},
+		{"token public missing", mediaConfig{token: token}, public, "", "", ErrMediaAccessDenied},
+		{"token public wrong", mediaConfig{token: token}, public, "", "wrong", ErrMediaAccessDenied},
+		{"token public", mediaConfig{token: token}, public, "", token, nil},
+		{"subnet allowed", mediaConfig{subnets: []string{"10.0.0.0/8"}}, "10.1.2.3:80", "", "", nil},
+		{"single ip allowed", mediaConfig{subnets: []string{"10.1.2.3"}}, "10.1.2.3:80", "", "", nil},
+		{"subnet excludes local", mediaConfig{subnets: []string{"10.0.0.0/8"}}, local, "", "", ErrMediaAccessDenied},
+		{"subnet with token", mediaConfig{subnets: []string{"10.0.0.0/8"}, token: token}, public, "", token, nil},
+		{"local proxy", mediaConfig{token: token}, local, "193.168.1.2, 192.168.1.1", "", ErrMediaAccessDenied},
+		{"local proxy chain", mediaConfig{token: token}, local, "192.168.1.5, 192.168.1.1", "", nil},
+		{"spoofed forwarded local", mediaConfig{token: token}, local, "192.168.1.5, 193.168.1.2", "", ErrMediaAccessDenied},
+		{"spoofed forwarded subnet", mediaConfig{subnets: []string{"10.0.0.0/8"}}, local, "10.1.2.3, 192.168.1.7", "", ErrMediaAccessDenied},
+		{"spoofed forwarded invalid", mediaConfig{token: token}, local, "192.168.1.5, garbage", "", ErrMediaAccessDenied},
+		{"spoofed forwarded with token", mediaConfig{token: token}, local, "192.168.1.5, 193.168.1.2", token, nil},
+		{"forwarded subnet", mediaConfig{subnets: []string{"10.0.0.0/8"}}, local, "10.1.2.3", "", nil},
+		{"public proxy ignored", mediaConfig{subnets: []string{"10.0.0.0/8"}}, public, "10.1.2.3", "", ErrMediaAccessDenied},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &http.Request{
+				RemoteAddr: tc.address,
+				Header:     make(http.Header),
+				URL:        &url.URL{},
+			}
+			if tc.forwarded != "" {
+				r.Header.Set("X-FORWARDED-FOR", tc.forwarded)
+			}
+			if tc.token != "" {
+				r.URL.RawQuery = url.Values{MediaTokenParameter: []string{tc.token}}.Encode()
+			}
+
+			err := CheckMediaAccess(&tc.config, r)
+			if !errors.Is(err, tc.err) {
+				t.Errorf("expected %v, got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestParseSubnets(t *testing.T) {
+	if _, err := ParseSubnets([]string{"10.0.0.0/8", "::1", " "}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if _, err := ParseSubnets([]string{"10.0.0.0/33"}); err == nil {
+		t.Error("expected error for invalid subnet")
+	}
+
+	if _, err := ParseSubnets([]string{"not an ip"}); err == nil {
+		t.Error("expected error for invalid address")
+	}
+}
@@ -0,0 +1,370 @@
+package session
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const oidcRequestTimeout = 30 * time.Second
+
+var defaultOIDCScopes = []string{"openid", "profile", "email"}
+
+// OIDCConfig is the configuration of an OpenID Connect provider, such as
+// Authentik, Keycloak or Google.
+type OIDCConfig struct {
+	ID           string   `json:"id" mapstructure:"id"`
+	Name         string   `json:"name" mapstructure:"name"`
+	Issuer       string   `json:"issuer" mapstructure:"issuer"`
+	ClientID     string   `json:"client_id" mapstructure:"client_id"`
+	ClientSecret string   `json:"client_secret" mapstructure:"client_secret"`
+	Scopes       []string `json:"scopes" mapstructure:"scopes"`
+	// AutoRegisterRole is the role of the users created for unknown
+	// external users. Unknown users are refused if empty.
+	AutoRegisterRole models.UserRole `json:"auto_register_role" mapstructure:"auto_register_role"`
+}
+
+// Validate returns an error if the configuration is incomplete.
+func (c OIDCConfig) Validate() error {
+	switch {
+	case c.ID == "":
+		return errors.New("id is required")
+	case c.Issuer == "":
+		return errors.New("issuer is required")
+	case c.ClientID == "":
+		return errors.New("client_id is required")
+	case c.AutoRegisterRole != "" && !c.AutoRegisterRole.IsValid():
+		return fmt.Errorf("invalid auto_register_role %q", c.AutoRegisterRole)
+	}
+
+	return nil
+}
+
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// OIDCProvider is an AuthProvider authenticating users with an OpenID
+// Connect provider. The provider metadata is discovered from the issuer
+// when first needed.
+type OIDCProvider struct {
+	config OIDCConfig
+	client *http.Client
+
+	mutex     sync.Mutex
+	discovery *oidcDiscovery
+	keys      map[string]interface{}
+}
+
+// NewOIDCProvider returns a provider using the configuration.
+func NewOIDCProvider(c OIDCConfig) (*OIDCProvider, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	if c.Name == "" {
+		c.Name = c.ID
+	}
+	if len(c.Scopes) == 0 {
+		c.Scopes = defaultOIDCScopes
+	}
+	c.Issuer = strings.TrimSuffix(c.Issuer, "/")
+
+	return &OIDCProvider{
+		config: c,
+		client: &http.Client{Timeout: oidcRequestTimeout},
+	}, nil
+}
+
+func (p *OIDCProvider) ID() string {
+	return p.config.ID
+}
+
+func (p *OIDCProvider) Name() string {
+	return p.config.Name
+}
+
+func (p *OIDCProvider) AutoRegisterRole() models.UserRole {
+	return p.config.AutoRegisterRole
+}
+
+func (p *OIDCProvider) getJSON(ctx context.Context, u string, dest interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+
+	return p.doJSON(req, dest)
+}
+
+func (p *OIDCProvider) doJSON(req *http.Request, dest interface{}) error {
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s %s: %s: %s", req.Method, req.URL.String(), resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	return json.NewDecoder(resp.Body).Decode(dest)
+}
+
+func (p *OIDCProvider) getDiscovery(ctx context.Context) (*oidcDiscovery, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	var d oidcDiscovery
+	if err := p.getJSON(ctx, p.config.Issuer+"/.well-known/openid-configuration", &d); err != nil {
+		return nil, fmt.Errorf("discovering %s: %w", p.config.Name, err)
+	}
+
+	if strings.TrimSuffix(d.Issuer, "/") != p.config.Issuer {
+		return nil, fmt.Errorf("discovering %s: issuer %q does not match configured issuer", p.config.Name, d.Issuer)
+	}
+	if d.AuthorizationEndpoint == "" || d.TokenEndpoint == "" || d.JWKSURI == "" {
+		return nil, fmt.Errorf("discovering %s: incomplete provider metadata", p.config.Name)
+	}
+
+	p.discovery = &d
+	return p.discovery, nil
+}
+
+func (p *OIDCProvider) AuthCodeURL(ctx context.Context, redirectURL, state, nonce, codeVerifier string) (string, error) {
+	d, err := p.getDiscovery(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	u, err := url.Parse(d.AuthorizationEndpoint)
+	if err != nil {
+		return "", err
+	}
+
+	q := u.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", p.config.ClientID)
+	q.Set("redirect_uri", redirectURL)
+	q.Set("scope", strings.Join(p.config.Scopes, " "))
+	q.Set("state", state)
+	q.Set("nonce", nonce)
+	q.Set("code_challenge", pkceChallenge(codeVerifier))
+	q.Set("code_challenge_method", "S256")
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
+func (p *OIDCProvider) Exchange(ctx context.Context, redirectURL, code, nonce, codeVerifier string) (*ExternalIdentity, error) {
+	if code == "" {
+		return nil, ErrInvalidExternalLogin
+	}
+
+	d, err := p.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {redirectURL},
+		"code_verifier": {codeVerifier},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+
+	var token struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := p.doJSON(req, &token); err != nil {
+		return nil, fmt.Errorf("exchanging %s code: %w", p.config.Name, err)
+	}
+
+	if token.IDToken == "" {
+		return nil, fmt.Errorf("exchanging %s code: no id token returned", p.config.Name)
+	}
+
+	return p.verifyIDToken(ctx, token.IDToken, nonce)
+}
+
+// verifyIDToken verifies the signature and claims of the ID token, returning
+// the identity of the user.
+func (p *OIDCProvider) verifyIDToken(ctx context.Context, idToken, nonce string) (*ExternalIdentity, error) {
+	parser := &jwt.Parser{
+		ValidMethods: []string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"},
+	}
+
+	claims := jwt.MapClaims{}
+	if _, err := parser.ParseWithClaims(idToken, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return p.getKey(ctx, kid)
+	}); err != nil {
+		return nil, fmt.Errorf("invalid %s id token: %w", p.config.Name, err)
+	}
+
+	switch {
+	case !claims.VerifyIssuer(p.config.Issuer, true) && !claims.VerifyIssuer(p.config.Issuer+"/", true):
+		return nil, fmt.Errorf("invalid %s id token: unexpected issuer", p.config.Name)
+	case !claims.VerifyAudience(p.config.ClientID, true):
+		return nil, fmt.Errorf("invalid %s id token: unexpected audience", p.config.Name)
+	case !claims.VerifyExpiresAt(time.Now().Unix(), true):
+		return nil, fmt.Errorf("invalid %s id token: token is expired", p.config.Name)
+	}
+
+	if got, _ := claims["nonce"].(string); got != nonce {
+		return nil, fmt.Errorf("invalid %s id token: unexpected nonce", p.config.Name)
+	}
+
+	ret := &ExternalIdentity{}
+	ret.Subject, _ = claims["sub"].(string)
+	ret.Username, _ = claims["preferred_username"].(string)
+	ret.Email, _ = claims["email"].(string)
+
+	if ret.Subject == "" {
+		return nil, fmt.Errorf("invalid %s id token: no subject", p.config.Name)
+	}
+
+	return ret, nil
+}
+
+// getKey returns the public key with the key id, fetching the keys of the
+// provider if the key is not known, so that rotated keys are picked up.
+func (p *OIDCProvider) getKey(ctx context.Context, kid string) (interface{}, error) {
+	p.mutex.Lock()
+	key, ok := p.keys[kid]
+	p.mutex.Unlock()
+
+	if ok {
+		return key, nil
+	}
+
+	d, err := p.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := p.getJSON(ctx, d.JWKSURI, &jwks); err != nil {
+		return nil, fmt.Errorf("fetching %s keys: %w", p.config.Name, err)
+	}
+
+	keys := make(map[string]interface{})
+	for _, k := range jwks.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		// ignore unsupported keys
+		if pub, err := k.publicKey(); err == nil {
+			keys[k.Kid] = pub
+		}
+	}
+
+	p.mutex.Lock()
+	p.keys = keys
+	p.mutex.Unlock()
+
+	key, ok = keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown %s key %q", p.config.Name, kid)
+	}
+
+	return key, nil
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+func (k jsonWebKey) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() {
+			return nil, errors.New("invalid exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("point is not on curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
@@ -0,0 +1,223 @@
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const (
+	testClientID    = "stash"
+	testRedirectURL = "http://stash.local/login/oidc/idp/callback"
+)
+
+// fakeIDP is an OpenID Connect provider issuing ID tokens for a single
+// authorization code.
+type fakeIDP struct {
+	*httptest.Server
+	key *rsa.PrivateKey
+
+	subject  string
+	audience string
+
+	// set when the user is redirected to the provider
+	nonce     string
+	challenge string
+}
+
+func newFakeIDP(t *testing.T) *fakeIDP {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idp := &fakeIDP{key: key, audience: testClientID}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 idp.URL,
+			"authorization_endpoint": idp.URL + "/authorize",
+			"token_endpoint":         idp.URL + "/token",
+			"jwks_uri":               idp.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		e := big.NewInt(int64(key.E)).Bytes()
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kid": "key",
+				"kty": "RSA",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(e),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		clientID, _, _ := r.BasicAuth()
+		if clientID != testClientID || r.FormValue("code") != "code" || r.FormValue("redirect_uri") != testRedirectURL ||
+			pkceChallenge(r.FormValue("code_verifier")) != idp.challenge {
+			http.Error(w, "invalid_grant", http.StatusBadRequest)
+			return
+		}
+
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+			"iss":                idp.URL,
+			"aud":                idp.audience,
+			"sub":                idp.subject,
+			"exp":                time.Now().Add(time.Minute).Unix(),
+			"nonce":              idp.nonce,
+			"preferred_username": "external",
+		})
+		token.Header["kid"] = "key"
+
+		signed, err := token.SignedString(key)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]string{"id_token": signed})
+	})
+
+	idp.Server = httptest.NewServer(mux)
+	t.Cleanup(idp.Close)
+
+	return idp
+}
+
+// externalUserStore links the subjects to users.
+type externalUserStore map[string]*models.User
+
+func (s externalUserStore) FindUserByExternalIdentity(ctx context.Context, provider, subject string) (*models.User, error) {
+	return s[provider+"/"+subject], nil
+}
+
+func (s externalUserStore) RegisterExternalUser(ctx context.Context, provider string, identity ExternalIdentity, role models.UserRole) (*models.User, error) {
+	u := &models.User{Username: identity.Username, Role: role}
+	s[provider+"/"+identity.Subject] = u
+	return u, nil
+}
+
+func TestExternalLogin(t *testing.T) {
+	testCases := []struct {
+		name         string
+		subject      string
+		autoRegister models.UserRole
+		audience     string
+		state        string
+		userID       string
+		err          error
+	}{
+		{"linked user", "linked", "", testClientID, "", "viewer", nil},
+		{"unknown user", "unknown", "", testClientID, "", "", ErrExternalUserNotLinked},
+		{"registered user", "unknown", models.UserRoleReadOnly, testClientID, "", "external", nil},
+		{"wrong state", "linked", "", testClientID, "wrong", "", ErrInvalidExternalLogin},
+		{"wrong audience", "linked", "", "other", "", "", nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			idp := newFakeIDP(t)
+			idp.subject = tc.subject
+			idp.audience = tc.audience
+
+			p, err := NewOIDCProvider(OIDCConfig{
+				ID:               "idp",
+				Issuer:           idp.URL,
+				ClientID:         testClientID,
+				AutoRegisterRole: tc.autoRegister,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			store := NewStore(&sessionConfig{})
+			store.SetAuthProviders([]AuthProvider{p})
+			store.SetExternalUserStore(externalUserStore{
+				"idp/linked": {Username: "viewer", Role: models.UserRoleReadOnly},
+			})
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/login/oidc/idp", nil)
+			u, err := store.BeginExternalLogin(w, r, "idp", testRedirectURL, "/scenes")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			authURL, err := url.Parse(u)
+			if err != nil {
+				t.Fatal(err)
+			}
+			q := authURL.Query()
+			assert.Equal(t, testRedirectURL, q.Get("redirect_uri"))
+			idp.nonce = q.Get("nonce")
+			idp.challenge = q.Get("code_challenge")
+
+			state := q.Get("state")
+			if tc.state != "" {
+				state = tc.state
+			}
+
+			callback := url.Values{"state": {state}, "code": {"code"}}
+			r = httptest.NewRequest(http.MethodGet, "/login/oidc/idp/callback?"+callback.Encode(), nil)
+			for _, c := range w.Result().Cookies() {
+				r.AddCookie(c)
+			}
+
+			w = httptest.NewRecorder()
+			returnURL, err := store.CompleteExternalLogin(w, r, "idp", testRedirectURL)
+			if tc.userID == "" {
+				assert.NotNil(t, err)
+				if tc.err != nil {
+					assert.ErrorIs(t, err, tc.err)
+				}
+				return
+			}
+
+			if !assert.Nil(t, err) {
+				return
+			}
+			assert.Equal(t, "/scenes", returnURL)
+
+			r = httptest.NewRequest(http.MethodGet, "/graphql", nil)
+			for _, c := range w.Result().Cookies() {
+				r.AddCookie(c)
+			}
+			userID, err := store.Authenticate(httptest.NewRecorder(), r)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.userID, userID)
+		})
+	}
+}
+
+func TestIsLocalURL(t *testing.T) {
+	testCases := []struct {
+		url  string
+		want bool
+	}{
+		{"/scenes?q=1", true},
+		{"", false},
+		{"//evil.com", false},
+		{"/\\evil.com", false},
+		{"https://evil.com/", false},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.want, isLocalURL(tc.url), tc.url)
+	}
+}
@@ -0,0 +1,115 @@
+package session
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// maxPinFailures is the number of consecutive invalid PINs after which a
+	// client is locked out.
+	maxPinFailures = 5
+	// pinLockoutDuration is the time a client is locked out for after too many
+	// invalid PINs.
+	pinLockoutDuration = 5 * time.Minute
+	// pinFailureWindow is the time after which failed attempts are forgotten.
+	pinFailureWindow = 15 * time.Minute
+)
+
+var ErrTooManyPinAttempts = errors.New("too many invalid PIN attempts")
+
+type pinAttempt struct {
+	failures    int
+	lastFailure time.Time
+	lockedUntil time.Time
+}
+
+// pinThrottle limits the number of PINs a client may try, so that short PINs
+// cannot be brute-forced. Clients are identified by IP address rather than by
+// session, since a client can discard its session cookie.
+type pinThrottle struct {
+	mutex    sync.Mutex
+	attempts map[string]*pinAttempt
+	now      func() time.Time
+}
+
+func newPinThrottle() *pinThrottle {
+	return &pinThrottle{
+		attempts: make(map[string]*pinAttempt),
+		now:      time.Now,
+	}
+}
+
+// pinClientKey returns the key identifying the client making the request.
+// For requests proxied from the local network, the address appended by the
+// proxy is used, since earlier entries are provided by the client.
+func pinClientKey(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	if forwarded := r.Header.Get("X-FORWARDED-FOR"); forwarded != "" {
+		if ip := net.ParseIP(host); ip != nil && isLocalIP(ip) {
+			hops := strings.Split(forwarded, ",")
+			return host + "/" + strings.TrimSpace(hops[len(hops)-1])
+		}
+	}
+
+	return host
+}
+
+// check returns ErrTooManyPinAttempts if the client is locked out.
+func (t *pinThrottle) check(key string) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if a := t.attempts[key]; a != nil && t.now().Before(a.lockedUntil) {
+		return ErrTooManyPinAttempts
+	}
+
+	return nil
+}
+
+// fail records an invalid PIN for the client, locking it out if it has
+// reached the maximum number of failures.
+func (t *pinThrottle) fail(key string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	now := t.now()
+	t.prune(now)
+
+	a := t.attempts[key]
+	if a == nil {
+		a = &pinAttempt{}
+		t.attempts[key] = a
+	}
+
+	a.failures++
+	a.lastFailure = now
+	if a.failures >= maxPinFailures {
+		a.failures = 0
+		a.lockedUntil = now.Add(pinLockoutDuration)
+	}
+}
+
+// succeed forgets the failed attempts of the client.
+func (t *pinThrottle) succeed(key string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	delete(t.attempts, key)
+}
+
+func (t *pinThrottle) prune(now time.Time) {
+	for k, a := range t.attempts {
+		if now.After(a.lockedUntil) && now.Sub(a.lastFailure) > pinFailureWindow {
+			delete(t.attempts, k)
+		}
+	}
+}
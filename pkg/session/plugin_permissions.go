@@ -0,0 +1,128 @@
+package session
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/securecookie"
+	"github.com/gorilla/sessions"
+)
+
+// PluginPermission is a permission that a plugin may declare in its
+// configuration. Plugins can always run queries. Requests made using the
+// token issued to a plugin are limited to the permissions it declares.
+type PluginPermission string
+
+const (
+	// PluginPermissionWrite allows running mutations, other than those which
+	// require the admin permission.
+	PluginPermissionWrite PluginPermission = "write"
+	// PluginPermissionAdmin allows changing the configuration, running
+	// system tasks and reading secrets such as the API key.
+	PluginPermissionAdmin PluginPermission = "admin"
+)
+
+func (p PluginPermission) IsValid() bool {
+	switch p {
+	case PluginPermissionWrite, PluginPermissionAdmin:
+		return true
+	}
+	return false
+}
+
+const (
+	pluginIDKey          = "pluginID"
+	pluginPermissionsKey = "pluginPermissions"
+)
+
+type pluginScope struct {
+	pluginID    string
+	permissions []string
+}
+
+// getSession returns the session of the plugin token provided in the
+// request, or the session of the request cookie otherwise.
+func (s *Store) getSession(r *http.Request) (*sessions.Session, error) {
+	if apiKey := GetRequestAPIKey(r); apiKey != "" && apiKey != s.config.GetAPIKey() {
+		return s.decodePluginToken(apiKey)
+	}
+
+	return s.sessionStore.Get(r, cookieName)
+}
+
+func (s *Store) decodePluginToken(token string) (*sessions.Session, error) {
+	session := sessions.NewSession(s.sessionStore, cookieName)
+	if err := securecookie.DecodeMulti(cookieName, token, &session.Values, s.sessionStore.Codecs...); err != nil {
+		return nil, err
+	}
+
+	// only plugin sessions are accepted as tokens
+	if _, ok := session.Values[pluginIDKey].(string); !ok {
+		return nil, ErrUnauthorized
+	}
+
+	return session, nil
+}
+
+// PluginPermissionsHandler limits the request context to the permissions of
+// the plugin, if the request was made using a plugin token or cookie.
+func (s *Store) PluginPermissionsHandler() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// ignore errors
+			session, err := s.getSession(r)
+			if err == nil {
+				if pluginID, ok := session.Values[pluginIDKey].(string); ok {
+					permissions, _ := session.Values[pluginPermissionsKey].([]string)
+					ctx := context.WithValue(r.Context(), contextPluginScope, pluginScope{
+						pluginID:    pluginID,
+						permissions: permissions,
+					})
+					r = r.WithContext(ctx)
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// PluginRequestHandler applies the session state carried by plugin cookies
+// to requests made by plugins in-process: the visited plugins, content
+// warning, quick hide and content restriction state, and the plugin
+// permissions.
+func (s *Store) PluginRequestHandler(next http.Handler) http.Handler {
+	return s.VisitedPluginHandler()(
+		s.ContentWarningsHandler()(
+			s.QuickHideHandler()(
+				s.ContentRestrictionHandler()(
+					s.PluginPermissionsHandler()(next),
+				),
+			),
+		),
+	)
+}
+
+// GetPluginID returns the ID of the plugin that made the request, or an
+// empty string if the request was not made by a plugin.
+func GetPluginID(ctx context.Context) string {
+	scope, _ := ctx.Value(contextPluginScope).(pluginScope)
+	return scope.pluginID
+}
+
+// HasPluginPermission returns true if the request was not made by a plugin,
+// or if the plugin declared the permission.
+func HasPluginPermission(ctx context.Context, p PluginPermission) bool {
+	scope, ok := ctx.Value(contextPluginScope).(pluginScope)
+	if !ok {
+		return true
+	}
+
+	for _, v := range scope.permissions {
+		if v == string(p) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,155 @@
+package session
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+type sessionConfig struct {
+	apiKey      string
+	pin         string
+	lockTimeout time.Duration
+	safeTags    []int
+}
+
+func (c *sessionConfig) GetUsername() string {
+	return "user"
+}
+
+func (c *sessionConfig) GetAPIKey() string {
+	return c.apiKey
+}
+
+func (c *sessionConfig) GetSessionStoreKey() []byte {
+	return []byte("0123456789abcdef0123456789abcdef")
+}
+
+func (c *sessionConfig) GetMaxSessionAge() int {
+	return 3600
+}
+
+func (c *sessionConfig) ValidateCredentials(username string, password string) bool {
+	return false
+}
+
+func (c *sessionConfig) IsSessionLockEnabled() bool {
+	return c.pin != ""
+}
+
+func (c *sessionConfig) GetSessionLockTimeout() time.Duration {
+	return c.lockTimeout
+}
+
+func (c *sessionConfig) ValidateSessionLockPin(pin string) bool {
+	return c.pin != "" && pin == c.pin
+}
+
+func (c *sessionConfig) GetQuickHideSafeTags() []int {
+	return c.safeTags
+}
+
+func TestPluginPermissionsHandler(t *testing.T) {
+	store := NewStore(&sessionConfig{apiKey: "apikey"})
+
+	ctx := context.WithValue(context.Background(), contextUser, "user")
+	writeToken := store.MakePluginCookie(ctx, "writer", []PluginPermission{PluginPermissionWrite}).Value
+	readToken := store.MakePluginCookie(ctx, "reader", nil).Value
+
+	testCases := []struct {
+		name     string
+		apiKey   string
+		pluginID string
+		write    bool
+		admin    bool
+	}{
+		{"no key", "", "", true, true},
+		{"config key", "apikey", "", true, true},
+		{"write plugin", writeToken, "writer", true, false},
+		{"read plugin", readToken, "reader", false, false},
+		{"invalid token", "invalid", "", true, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/graphql", nil)
+			if tc.apiKey != "" {
+				r.Header.Set(ApiKeyHeader, tc.apiKey)
+			}
+
+			var got context.Context
+			h := store.PluginPermissionsHandler()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.Context()
+			}))
+			h.ServeHTTP(httptest.NewRecorder(), r)
+
+			assert.Equal(t, tc.pluginID, GetPluginID(got))
+			assert.Equal(t, tc.write, HasPluginPermission(got, PluginPermissionWrite))
+			assert.Equal(t, tc.admin, HasPluginPermission(got, PluginPermissionAdmin))
+		})
+	}
+}
+
+func TestAuthenticatePluginToken(t *testing.T) {
+	store := NewStore(&sessionConfig{apiKey: "apikey"})
+
+	ctx := context.WithValue(context.Background(), contextUser, "user")
+	token := store.MakePluginCookie(ctx, "plugin", nil).Value
+
+	testCases := []struct {
+		name   string
+		apiKey string
+		userID string
+		err    error
+	}{
+		{"config key", "apikey", "user", nil},
+		{"plugin token", token, "user", nil},
+		{"invalid token", "invalid", "", ErrUnauthorized},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/graphql", nil)
+			r.Header.Set(ApiKeyHeader, tc.apiKey)
+
+			userID, err := store.Authenticate(httptest.NewRecorder(), r)
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.userID, userID)
+		})
+	}
+}
+
+func TestPluginRequestHandlerRestriction(t *testing.T) {
+	store := NewStore(&sessionConfig{apiKey: "apikey"})
+	store.SetRestrictionProfileFinder(restrictionProfileFinder{
+		{ID: 2, Name: "session"}: {StudioIDs: []int{2}},
+	})
+
+	var restriction *models.ContentRestriction
+	h := store.PluginRequestHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		restriction = nil
+		if got, ok := models.GetContentRestriction(r.Context()); ok {
+			restriction = &got
+		}
+	}))
+
+	request := func(ctx context.Context) *models.ContentRestriction {
+		r := httptest.NewRequest(http.MethodGet, "/graphql", nil)
+		r.AddCookie(store.MakePluginCookie(ctx, "plugin", nil))
+		h.ServeHTTP(httptest.NewRecorder(), r)
+		return restriction
+	}
+
+	ctx := context.Background()
+	assert.Nil(t, request(ctx))
+
+	// plugins run by a restricted session are restricted
+	ctx = context.WithValue(ctx, contextRestrictionProfile, 2)
+	assert.Equal(t, &models.ContentRestriction{StudioIDs: []int{2}}, request(ctx))
+}
@@ -0,0 +1,67 @@
+package session
+
+import (
+	"net/http"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const quickHideKey = "quickHide"
+
+// EnableQuickHide limits the content visible in the session to content tagged
+// with the quick hide safe tags.
+func (s *Store) EnableQuickHide(w http.ResponseWriter, r *http.Request) error {
+	// ignore error - we want a new session regardless
+	session, _ := s.sessionStore.Get(r, cookieName)
+
+	session.Values[quickHideKey] = true
+
+	return session.Save(r, w)
+}
+
+// DisableQuickHide validates the PIN provided in the request and, if valid,
+// disables quick hide for the session. No PIN is required if session locking
+// is disabled. Invalid PINs are throttled as in UnlockSession.
+func (s *Store) DisableQuickHide(w http.ResponseWriter, r *http.Request) error {
+	session, err := s.sessionStore.Get(r, cookieName)
+	if err != nil {
+		return err
+	}
+
+	if s.config.IsSessionLockEnabled() {
+		client := pinClientKey(r)
+		if err := s.pinAttempts.check(client); err != nil {
+			return err
+		}
+
+		if !s.config.ValidateSessionLockPin(r.FormValue(pinFormKey)) {
+			s.pinAttempts.fail(client)
+			return ErrInvalidPin
+		}
+
+		s.pinAttempts.succeed(client)
+	}
+
+	delete(session.Values, quickHideKey)
+
+	return session.Save(r, w)
+}
+
+// QuickHideHandler limits the content visible in the request context to
+// content tagged with the quick hide safe tags, if quick hide is enabled for
+// the session.
+func (s *Store) QuickHideHandler() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// ignore errors
+			session, err := s.getSession(r)
+			if err == nil {
+				if enabled, _ := session.Values[quickHideKey].(bool); enabled {
+					r = r.WithContext(models.QuickHide(r.Context(), s.config.GetQuickHideSafeTags()))
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
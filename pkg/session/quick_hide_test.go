@@ -0,0 +1,47 @@
+package session
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuickHide(t *testing.T) {
+	store := NewStore(&sessionConfig{pin: "1234", safeTags: []int{1, 2}})
+
+	var enabled bool
+	var safeTags []int
+	handler := store.QuickHideHandler()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		safeTags, enabled = models.QuickHideSafeTags(r.Context())
+	}))
+
+	c := &sessionClient{}
+	c.do(handler.ServeHTTP, httptest.NewRequest(http.MethodGet, "/graphql", nil))
+	assert.False(t, enabled)
+
+	c.do(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, store.EnableQuickHide(w, r))
+	}, httptest.NewRequest(http.MethodPost, "/quickHide/enable", nil))
+
+	c.do(handler.ServeHTTP, httptest.NewRequest(http.MethodGet, "/graphql", nil))
+	assert.True(t, enabled)
+	assert.Equal(t, []int{1, 2}, safeTags)
+
+	var disableErr error
+	disable := func(w http.ResponseWriter, r *http.Request) {
+		disableErr = store.DisableQuickHide(w, r)
+	}
+
+	c.do(disable, pinRequest("/quickHide/disable", "0000"))
+	assert.ErrorIs(t, disableErr, ErrInvalidPin)
+	c.do(handler.ServeHTTP, httptest.NewRequest(http.MethodGet, "/graphql", nil))
+	assert.True(t, enabled)
+
+	c.do(disable, pinRequest("/quickHide/disable", "1234"))
+	assert.NoError(t, disableErr)
+	c.do(handler.ServeHTTP, httptest.NewRequest(http.MethodGet, "/graphql", nil))
+	assert.False(t, enabled)
+}
@@ -0,0 +1,115 @@
+package session
+
+import (
+	"context"
+	"net/http"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const restrictionProfileKey = "restrictionProfile"
+
+// RestrictionProfileFinder returns the restriction profiles used to hide
+// content from users and sessions.
+type RestrictionProfileFinder interface {
+	AllRestrictionProfiles(ctx context.Context) ([]*models.RestrictionProfile, error)
+	FindContentRestriction(ctx context.Context, profileID int) (*models.ContentRestriction, error)
+}
+
+// SetRestrictionProfileFinder sets the source of the restriction profiles.
+// Content is not restricted if it is not set.
+func (s *Store) SetRestrictionProfileFinder(f RestrictionProfileFinder) {
+	s.restrictionProfiles = f
+}
+
+// findRestrictionProfileByPin returns the restriction profile unlocked by
+// the provided PIN, or nil if there is none.
+func (s *Store) findRestrictionProfileByPin(ctx context.Context, pin string) *models.RestrictionProfile {
+	if s.restrictionProfiles == nil || pin == "" {
+		return nil
+	}
+
+	profiles, err := s.restrictionProfiles.AllRestrictionProfiles(ctx)
+	if err != nil {
+		logger.Errorf("Error finding restriction profiles: %v", err)
+		return nil
+	}
+
+	for _, p := range profiles {
+		if p.PinHash != nil && bcrypt.CompareHashAndPassword([]byte(*p.PinHash), []byte(pin)) == nil {
+			return p
+		}
+	}
+
+	return nil
+}
+
+// restrictionProfileIDs returns the ids of the restriction profiles applying
+// to the request: the profile of the current user, and the profile the
+// session was unlocked with.
+func (s *Store) restrictionProfileIDs(r *http.Request) []int {
+	var ret []int
+
+	if userID := GetCurrentUserID(r.Context()); userID != nil && *userID != "" && *userID != s.config.GetUsername() && s.users != nil {
+		u, err := s.users.FindUserByUsername(r.Context(), *userID)
+		if err != nil {
+			logger.Errorf("Error finding user %s: %v", *userID, err)
+		} else if u != nil && u.RestrictionProfileID != nil {
+			ret = append(ret, *u.RestrictionProfileID)
+		}
+	}
+
+	if id, ok := GetSessionRestrictionProfile(r.Context()); ok {
+		ret = append(ret, id)
+	}
+
+	return ret
+}
+
+// ContentRestrictionHandler hides the content restricted by the restriction
+// profiles of the current user and session from the request context.
+func (s *Store) ContentRestrictionHandler() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if s.restrictionProfiles == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// ignore errors
+			session, err := s.getSession(r)
+			if err == nil {
+				if id, ok := session.Values[restrictionProfileKey].(int); ok {
+					r = r.WithContext(context.WithValue(r.Context(), contextRestrictionProfile, id))
+				}
+			}
+
+			for _, id := range s.restrictionProfileIDs(r) {
+				restriction, err := s.restrictionProfiles.FindContentRestriction(r.Context(), id)
+				if err != nil {
+					// fail closed - the restricted content cannot be determined
+					logger.Errorf("Error finding restriction profile %d: %v", id, err)
+					http.Error(w, "error finding restriction profile", http.StatusInternalServerError)
+					return
+				}
+
+				// the profile may have been deleted
+				if restriction != nil {
+					r = r.WithContext(models.RestrictContent(r.Context(), *restriction))
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// GetSessionRestrictionProfile returns the id of the restriction profile the
+// session of the request was unlocked with, and true if there is one.
+func GetSessionRestrictionProfile(ctx context.Context) (int, bool) {
+	id, ok := ctx.Value(contextRestrictionProfile).(int)
+	return id, ok
+}
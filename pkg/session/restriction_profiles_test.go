@@ -0,0 +1,85 @@
+package session
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+type restrictionProfileFinder map[*models.RestrictionProfile]models.ContentRestriction
+
+func (f restrictionProfileFinder) AllRestrictionProfiles(ctx context.Context) ([]*models.RestrictionProfile, error) {
+	var ret []*models.RestrictionProfile
+	for p := range f {
+		ret = append(ret, p)
+	}
+	return ret, nil
+}
+
+func (f restrictionProfileFinder) FindContentRestriction(ctx context.Context, profileID int) (*models.ContentRestriction, error) {
+	for p, r := range f {
+		if p.ID == profileID {
+			r := r
+			return &r, nil
+		}
+	}
+	return nil, nil
+}
+
+func TestContentRestrictionHandler(t *testing.T) {
+	pinHash, err := HashPassword("5678")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := NewStore(&sessionConfig{pin: "1234"})
+	profileID := 1
+	store.SetUserFinder(userFinder{
+		{ID: 1, Username: "kid", RestrictionProfileID: &profileID},
+		{ID: 2, Username: "viewer"},
+	})
+	store.SetRestrictionProfileFinder(restrictionProfileFinder{
+		{ID: 1, Name: "user"}:                       {TagIDs: []int{1}},
+		{ID: 2, Name: "session", PinHash: &pinHash}: {StudioIDs: []int{2}},
+	})
+
+	var restriction *models.ContentRestriction
+	handler := store.ContentRestrictionHandler()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		restriction = nil
+		if got, ok := models.GetContentRestriction(r.Context()); ok {
+			restriction = &got
+		}
+	}))
+
+	request := func(c *sessionClient, userID string) *models.ContentRestriction {
+		r := httptest.NewRequest(http.MethodGet, "/graphql", nil)
+		r = r.WithContext(SetCurrentUserID(r.Context(), userID))
+		c.do(handler.ServeHTTP, r)
+		return restriction
+	}
+
+	c := &sessionClient{}
+	unlock := func(w http.ResponseWriter, r *http.Request) {
+		_ = store.UnlockSession(w, r)
+	}
+
+	assert.Nil(t, request(c, "viewer"))
+	assert.Equal(t, &models.ContentRestriction{TagIDs: []int{1}}, request(c, "kid"))
+
+	c.do(unlock, pinRequest("/sessionLock/unlock", "0000"))
+	assert.Nil(t, request(c, "viewer"))
+
+	// unlocking with the PIN of a profile restricts the session
+	c.do(unlock, pinRequest("/sessionLock/unlock", "5678"))
+	assert.Equal(t, &models.ContentRestriction{StudioIDs: []int{2}}, request(c, "viewer"))
+	assert.Equal(t, &models.ContentRestriction{TagIDs: []int{1}, StudioIDs: []int{2}}, request(c, "kid"))
+
+	// unlocking with the session lock PIN removes the session restriction
+	c.do(unlock, pinRequest("/sessionLock/unlock", "1234"))
+	assert.Nil(t, request(c, "viewer"))
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/gorilla/securecookie"
 	"github.com/gorilla/sessions"
 	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
 	"github.com/stashapp/stash/pkg/sliceutil/stringslice"
 )
 
@@ -16,6 +17,9 @@ type key int
 const (
 	contextUser key = iota
 	contextVisitedPlugins
+	contextPluginScope
+	contextUserRole
+	contextRestrictionProfile
 )
 
 const (
@@ -40,12 +44,21 @@ var ErrUnauthorized = errors.New("unauthorized")
 type Store struct {
 	sessionStore *sessions.CookieStore
 	config       SessionConfig
+	users        UserFinder
+
+	restrictionProfiles RestrictionProfileFinder
+
+	authProviders []AuthProvider
+	externalUsers ExternalUserStore
+
+	pinAttempts *pinThrottle
 }
 
 func NewStore(c SessionConfig) *Store {
 	ret := &Store{
 		sessionStore: sessions.NewCookieStore(c.GetSessionStoreKey()),
 		config:       c,
+		pinAttempts:  newPinThrottle(),
 	}
 
 	ret.sessionStore.MaxAge(c.GetMaxSessionAge())
@@ -62,12 +75,17 @@ func (s *Store) Login(w http.ResponseWriter, r *http.Request) error {
 	password := r.FormValue(passwordFormKey)
 
 	// authenticate the user
-	if !s.config.ValidateCredentials(username, password) {
+	if u := s.ValidateUserCredentials(r.Context(), username, password); u != nil {
+		username = u.Username
+	} else if !s.config.ValidateCredentials(username, password) {
 		return ErrInvalidCredentials
 	}
 
 	newSession.Values[userIDKey] = username
 
+	// logging in again unlocks a locked session
+	unlockSession(newSession)
+
 	err := newSession.Save(r, w)
 	if err != nil {
 		return err
@@ -137,7 +155,7 @@ func (s *Store) VisitedPluginHandler() func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			// get the visited plugins from the cookie and set in the context
-			session, err := s.sessionStore.Get(r, cookieName)
+			session, err := s.getSession(r)
 
 			// ignore errors
 			if err == nil {
@@ -173,7 +191,10 @@ func setVisitedPlugins(ctx context.Context, visitedPlugins []string) context.Con
 	return context.WithValue(ctx, contextVisitedPlugins, visitedPlugins)
 }
 
-func (s *Store) MakePluginCookie(ctx context.Context) *http.Cookie {
+// MakePluginCookie returns the session cookie issued to a plugin. Requests
+// made using the cookie, or its value as an API key, are limited to the
+// provided permissions.
+func (s *Store) MakePluginCookie(ctx context.Context, pluginID string, permissions []PluginPermission) *http.Cookie {
 	currentUser := GetCurrentUserID(ctx)
 	visitedPlugins := GetVisitedPlugins(ctx)
 
@@ -184,6 +205,24 @@ func (s *Store) MakePluginCookie(ctx context.Context) *http.Cookie {
 
 	session.Values[visitedPluginsKey] = visitedPlugins
 
+	perms := make([]string, len(permissions))
+	for i, p := range permissions {
+		perms[i] = string(p)
+	}
+	session.Values[pluginIDKey] = pluginID
+	session.Values[pluginPermissionsKey] = perms
+
+	// plugins share the content warning state of the calling session
+	if !models.ContentWarningsHidden(ctx) {
+		session.Values[contentWarningsUnlockedKey] = true
+	}
+	if _, quickHide := models.QuickHideSafeTags(ctx); quickHide {
+		session.Values[quickHideKey] = true
+	}
+	if profileID, ok := GetSessionRestrictionProfile(ctx); ok {
+		session.Values[restrictionProfileKey] = profileID
+	}
+
 	encoded, err := securecookie.EncodeMulti(session.Name(), session.Values,
 		s.sessionStore.Codecs...)
 	if err != nil {
@@ -194,10 +233,9 @@ func (s *Store) MakePluginCookie(ctx context.Context) *http.Cookie {
 	return sessions.NewCookie(session.Name(), encoded, session.Options)
 }
 
-func (s *Store) Authenticate(w http.ResponseWriter, r *http.Request) (userID string, err error) {
-	c := s.config
-
-	// translate api key into current user, if present
+// GetRequestAPIKey returns the API key provided in the request header or
+// query parameters, or an empty string if none was provided.
+func GetRequestAPIKey(r *http.Request) string {
 	apiKey := r.Header.Get(ApiKeyHeader)
 
 	// try getting the api key as a query parameter
@@ -205,15 +243,36 @@ func (s *Store) Authenticate(w http.ResponseWriter, r *http.Request) (userID str
 		apiKey = r.URL.Query().Get(ApiKeyParameter)
 	}
 
+	return apiKey
+}
+
+func (s *Store) Authenticate(w http.ResponseWriter, r *http.Request) (userID string, err error) {
+	c := s.config
+
+	// translate api key into current user, if present
+	apiKey := GetRequestAPIKey(r)
+
 	if apiKey != "" {
 		// match against configured API and set userID to the
 		// configured username. In future, we'll want to
 		// get the username from the key.
-		if c.GetAPIKey() != apiKey {
+		if c.GetAPIKey() == apiKey {
+			userID = c.GetUsername()
+			return
+		}
+
+		if u := s.findUserByAPIKey(r.Context(), apiKey); u != nil {
+			userID = u.Username
+			return
+		}
+
+		// otherwise, it must be a plugin token
+		session, err := s.decodePluginToken(apiKey)
+		if err != nil {
 			return "", ErrUnauthorized
 		}
 
-		userID = c.GetUsername()
+		userID, _ = session.Values[userIDKey].(string)
 	} else {
 		// handle session
 		userID, err = s.GetSessionUserID(w, r)
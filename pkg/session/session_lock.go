@@ -0,0 +1,135 @@
+package session
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/sessions"
+)
+
+const (
+	lastActivityKey  = "lastActivity"
+	sessionLockedKey = "sessionLocked"
+	pinFormKey       = "pin"
+)
+
+// activityInterval is the minimum interval between updates of the last
+// activity time of a session, so that the cookie is not rewritten on every
+// request.
+const activityInterval = time.Minute
+
+var (
+	ErrSessionLocked       = errors.New("session is locked")
+	ErrSessionLockDisabled = errors.New("a session lock PIN or password must be set to lock sessions")
+	ErrInvalidPin          = errors.New("invalid PIN")
+)
+
+// lockableSession returns the cookie session of the request. Returns nil if
+// the request was made with an API key or by a plugin, since these are never
+// locked.
+func (s *Store) lockableSession(r *http.Request) *sessions.Session {
+	if GetRequestAPIKey(r) != "" {
+		return nil
+	}
+
+	// ignore error - an invalid cookie is replaced with a new session
+	session, _ := s.sessionStore.Get(r, cookieName)
+	if _, ok := session.Values[pluginIDKey]; ok {
+		return nil
+	}
+
+	return session
+}
+
+// CheckSessionLock returns ErrSessionLocked if the session of the request is
+// locked. Sessions that have been inactive for longer than the session lock
+// timeout are locked first. Otherwise, the last activity time of the session
+// is updated.
+func (s *Store) CheckSessionLock(w http.ResponseWriter, r *http.Request) error {
+	if !s.config.IsSessionLockEnabled() {
+		return nil
+	}
+
+	session := s.lockableSession(r)
+	if session == nil {
+		return nil
+	}
+
+	if locked, _ := session.Values[sessionLockedKey].(bool); locked {
+		return ErrSessionLocked
+	}
+
+	now := time.Now()
+	lastActivity, _ := session.Values[lastActivityKey].(int64)
+	inactive := now.Sub(time.Unix(lastActivity, 0))
+
+	if timeout := s.config.GetSessionLockTimeout(); lastActivity != 0 && timeout > 0 && inactive > timeout {
+		session.Values[sessionLockedKey] = true
+		if err := session.Save(r, w); err != nil {
+			return err
+		}
+
+		return ErrSessionLocked
+	}
+
+	if inactive >= activityInterval {
+		session.Values[lastActivityKey] = now.Unix()
+		return session.Save(r, w)
+	}
+
+	return nil
+}
+
+// LockSession locks the session of the request until it is unlocked with
+// UnlockSession or the user logs in again.
+func (s *Store) LockSession(w http.ResponseWriter, r *http.Request) error {
+	if !s.config.IsSessionLockEnabled() {
+		return ErrSessionLockDisabled
+	}
+
+	session := s.lockableSession(r)
+	if session == nil {
+		return ErrUnauthorized
+	}
+
+	session.Values[sessionLockedKey] = true
+
+	return session.Save(r, w)
+}
+
+// UnlockSession validates the PIN provided in the request and, if valid,
+// unlocks the session. If the PIN is that of a restriction profile, the
+// content hidden by the profile remains hidden until the session is unlocked
+// with the session lock PIN, or the user logs in again. Returns
+// ErrTooManyPinAttempts if the client has tried too many invalid PINs.
+func (s *Store) UnlockSession(w http.ResponseWriter, r *http.Request) error {
+	client := pinClientKey(r)
+	if err := s.pinAttempts.check(client); err != nil {
+		return err
+	}
+
+	// ignore error - we want a new session regardless
+	session, _ := s.sessionStore.Get(r, cookieName)
+
+	pin := r.FormValue(pinFormKey)
+	if s.config.ValidateSessionLockPin(pin) {
+		unlockSession(session)
+	} else if p := s.findRestrictionProfileByPin(r.Context(), pin); p != nil {
+		unlockSession(session)
+		session.Values[restrictionProfileKey] = p.ID
+	} else {
+		s.pinAttempts.fail(client)
+		return ErrInvalidPin
+	}
+
+	s.pinAttempts.succeed(client)
+
+	return session.Save(r, w)
+}
+
+func unlockSession(session *sessions.Session) {
+	delete(session.Values, sessionLockedKey)
+	delete(session.Values, restrictionProfileKey)
+	session.Values[lastActivityKey] = time.Now().Unix()
+}
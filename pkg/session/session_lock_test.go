@@ -0,0 +1,159 @@
+package session
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// sessionClient sends requests to a handler, keeping the cookies set by the
+// responses.
+type sessionClient struct {
+	cookies []*http.Cookie
+}
+
+func (c *sessionClient) do(h http.HandlerFunc, r *http.Request) *httptest.ResponseRecorder {
+	for _, cookie := range c.cookies {
+		r.AddCookie(cookie)
+	}
+
+	w := httptest.NewRecorder()
+	h(w, r)
+
+	if cookies := w.Result().Cookies(); len(cookies) > 0 {
+		c.cookies = cookies
+	}
+
+	return w
+}
+
+func pinRequest(path string, pin string) *http.Request {
+	form := url.Values{pinFormKey: {pin}}
+	r := httptest.NewRequest(http.MethodPost, path, strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return r
+}
+
+func TestCheckSessionLock(t *testing.T) {
+	store := NewStore(&sessionConfig{apiKey: "apikey", pin: "1234", lockTimeout: time.Minute})
+
+	var checkErr error
+	check := func(w http.ResponseWriter, r *http.Request) {
+		checkErr = store.CheckSessionLock(w, r)
+	}
+
+	c := &sessionClient{}
+	c.do(check, httptest.NewRequest(http.MethodGet, "/graphql", nil))
+	assert.NoError(t, checkErr)
+
+	// activity within the timeout
+	c.do(check, httptest.NewRequest(http.MethodGet, "/graphql", nil))
+	assert.NoError(t, checkErr)
+
+	// simulate inactivity longer than the timeout
+	c.do(func(w http.ResponseWriter, r *http.Request) {
+		session, _ := store.sessionStore.Get(r, cookieName)
+		session.Values[lastActivityKey] = time.Now().Add(-2 * time.Minute).Unix()
+		assert.NoError(t, session.Save(r, w))
+	}, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	c.do(check, httptest.NewRequest(http.MethodGet, "/graphql", nil))
+	assert.ErrorIs(t, checkErr, ErrSessionLocked)
+
+	// API key requests are never locked
+	r := httptest.NewRequest(http.MethodGet, "/graphql", nil)
+	r.Header.Set(ApiKeyHeader, "apikey")
+	c.do(check, r)
+	assert.NoError(t, checkErr)
+
+	var unlockErr error
+	unlock := func(w http.ResponseWriter, r *http.Request) {
+		unlockErr = store.UnlockSession(w, r)
+	}
+
+	c.do(unlock, pinRequest("/sessionLock/unlock", "0000"))
+	assert.ErrorIs(t, unlockErr, ErrInvalidPin)
+	c.do(check, httptest.NewRequest(http.MethodGet, "/graphql", nil))
+	assert.ErrorIs(t, checkErr, ErrSessionLocked)
+
+	c.do(unlock, pinRequest("/sessionLock/unlock", "1234"))
+	assert.NoError(t, unlockErr)
+	c.do(check, httptest.NewRequest(http.MethodGet, "/graphql", nil))
+	assert.NoError(t, checkErr)
+
+	// manual lock
+	c.do(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, store.LockSession(w, r))
+	}, httptest.NewRequest(http.MethodPost, "/sessionLock/lock", nil))
+	c.do(check, httptest.NewRequest(http.MethodGet, "/graphql", nil))
+	assert.ErrorIs(t, checkErr, ErrSessionLocked)
+}
+
+func TestLockSessionDisabled(t *testing.T) {
+	store := NewStore(&sessionConfig{})
+
+	r := httptest.NewRequest(http.MethodPost, "/sessionLock/lock", nil)
+	assert.ErrorIs(t, store.LockSession(httptest.NewRecorder(), r), ErrSessionLockDisabled)
+	assert.NoError(t, store.CheckSessionLock(httptest.NewRecorder(), r))
+}
+
+func TestUnlockSessionThrottle(t *testing.T) {
+	store := NewStore(&sessionConfig{pin: "1234"})
+	now := time.Now()
+	store.pinAttempts.now = func() time.Time { return now }
+
+	unlock := func(pin string, remoteAddr string) error {
+		r := pinRequest("/sessionLock/unlock", pin)
+		r.RemoteAddr = remoteAddr
+		return store.UnlockSession(httptest.NewRecorder(), r)
+	}
+
+	const (
+		client = "192.0.2.1:1234"
+		other  = "192.0.2.2:1234"
+	)
+
+	// a valid PIN resets the failure count
+	for i := 0; i < maxPinFailures-1; i++ {
+		assert.ErrorIs(t, unlock("0000", client), ErrInvalidPin)
+	}
+	assert.NoError(t, unlock("1234", client))
+
+	for i := 0; i < maxPinFailures; i++ {
+		assert.ErrorIs(t, unlock("0000", client), ErrInvalidPin)
+	}
+
+	// locked out, even with the valid PIN
+	assert.ErrorIs(t, unlock("1234", client), ErrTooManyPinAttempts)
+
+	// other clients are not affected
+	assert.NoError(t, unlock("1234", other))
+
+	// quick hide shares the lockout
+	r := pinRequest("/quickHide/disable", "1234")
+	r.RemoteAddr = client
+	assert.ErrorIs(t, store.DisableQuickHide(httptest.NewRecorder(), r), ErrTooManyPinAttempts)
+
+	now = now.Add(pinLockoutDuration + time.Second)
+	assert.NoError(t, unlock("1234", client))
+}
+
+func TestPinClientKey(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/sessionLock/unlock", nil)
+	r.RemoteAddr = "192.168.1.1:1234"
+	r.Header.Set("X-FORWARDED-FOR", "10.0.0.1, 193.168.1.2")
+
+	// the client cannot change its key by prepending addresses
+	key := pinClientKey(r)
+	r.Header.Set("X-FORWARDED-FOR", "10.0.0.2, 193.168.1.2")
+	assert.Equal(t, key, pinClientKey(r))
+
+	// forwarded addresses are ignored from public proxies
+	r.RemoteAddr = "193.168.1.1:1234"
+	assert.Equal(t, "193.168.1.1", pinClientKey(r))
+}
@@ -0,0 +1,122 @@
+package session
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/stashapp/stash/pkg/logger"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// UserFinder returns the users stored in the database. The administrator
+// configured with the username and password in the configuration is not
+// stored in the database.
+type UserFinder interface {
+	FindUserByUsername(ctx context.Context, username string) (*models.User, error)
+	FindUserByAPIKeyHash(ctx context.Context, hash string) (*models.User, error)
+}
+
+// SetUserFinder sets the source of the users other than the configured
+// administrator. Only the configured administrator can log in if it is not
+// set.
+func (s *Store) SetUserFinder(f UserFinder) {
+	s.users = f
+}
+
+// HashPassword returns the bcrypt hash of the password of a user.
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// HashAPIKey returns the hash of a user API key. Only the hash of the key
+// is stored.
+func HashAPIKey(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:])
+}
+
+// ValidateUserCredentials returns the user with the username if the password
+// is correct, or nil otherwise.
+func (s *Store) ValidateUserCredentials(ctx context.Context, username, password string) *models.User {
+	if s.users == nil || username == "" {
+		return nil
+	}
+
+	u, err := s.users.FindUserByUsername(ctx, username)
+	if err != nil {
+		logger.Errorf("Error finding user %s: %v", username, err)
+		return nil
+	}
+
+	if u == nil || bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password)) != nil {
+		return nil
+	}
+
+	return u
+}
+
+func (s *Store) findUserByAPIKey(ctx context.Context, apiKey string) *models.User {
+	if s.users == nil {
+		return nil
+	}
+
+	u, err := s.users.FindUserByAPIKeyHash(ctx, HashAPIKey(apiKey))
+	if err != nil {
+		logger.Errorf("Error finding user by API key: %v", err)
+		return nil
+	}
+
+	return u
+}
+
+// GetUserRole returns the role of the user with the provided ID. The
+// configured administrator, and requests made when authentication is not
+// configured, have the admin role. Returns ErrUnauthorized if the user no
+// longer exists.
+func (s *Store) GetUserRole(ctx context.Context, userID string) (models.UserRole, error) {
+	if userID == "" || userID == s.config.GetUsername() {
+		return models.UserRoleAdmin, nil
+	}
+
+	if s.users == nil {
+		return "", ErrUnauthorized
+	}
+
+	u, err := s.users.FindUserByUsername(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	if u == nil {
+		return "", ErrUnauthorized
+	}
+
+	return u.Role, nil
+}
+
+func SetCurrentUserRole(ctx context.Context, role models.UserRole) context.Context {
+	return context.WithValue(ctx, contextUserRole, role)
+}
+
+// GetCurrentUserRole returns the role of the current user. Contexts without
+// a user, such as those of tasks, have the admin role.
+func GetCurrentUserRole(ctx context.Context) models.UserRole {
+	if role, ok := ctx.Value(contextUserRole).(models.UserRole); ok {
+		return role
+	}
+
+	return models.UserRoleAdmin
+}
+
+// HasUserRole returns true if the role of the current user grants the
+// permissions of the provided role.
+func HasUserRole(ctx context.Context, role models.UserRole) bool {
+	return GetCurrentUserRole(ctx).Includes(role)
+}
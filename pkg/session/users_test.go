@@ -0,0 +1,153 @@
+package session
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+type userFinder []*models.User
+
+func (f userFinder) FindUserByUsername(ctx context.Context, username string) (*models.User, error) {
+	for _, u := range f {
+		if strings.EqualFold(u.Username, username) {
+			return u, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f userFinder) FindUserByAPIKeyHash(ctx context.Context, hash string) (*models.User, error) {
+	for _, u := range f {
+		if u.APIKeyHash != nil && *u.APIKeyHash == hash {
+			return u, nil
+		}
+	}
+	return nil, nil
+}
+
+func newUserStore(t *testing.T) *Store {
+	passwordHash, err := HashPassword("password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	apiKeyHash := HashAPIKey("editorkey")
+
+	store := NewStore(&sessionConfig{apiKey: "apikey"})
+	store.SetUserFinder(userFinder{
+		{ID: 1, Username: "editor", PasswordHash: passwordHash, Role: models.UserRoleEditor, APIKeyHash: &apiKeyHash},
+		{ID: 2, Username: "viewer", PasswordHash: passwordHash, Role: models.UserRoleReadOnly},
+	})
+
+	return store
+}
+
+func TestAuthenticateUserAPIKey(t *testing.T) {
+	store := newUserStore(t)
+
+	testCases := []struct {
+		name   string
+		apiKey string
+		userID string
+		err    error
+	}{
+		{"config key", "apikey", "user", nil},
+		{"user key", "editorkey", "editor", nil},
+		{"invalid key", "invalid", "", ErrUnauthorized},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/graphql", nil)
+			r.Header.Set(ApiKeyHeader, tc.apiKey)
+
+			userID, err := store.Authenticate(httptest.NewRecorder(), r)
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.userID, userID)
+		})
+	}
+}
+
+func TestLoginUser(t *testing.T) {
+	store := newUserStore(t)
+
+	testCases := []struct {
+		name     string
+		username string
+		password string
+		err      error
+	}{
+		{"valid", "Viewer", "password", nil},
+		{"wrong password", "viewer", "wrong", ErrInvalidCredentials},
+		{"unknown user", "unknown", "password", ErrInvalidCredentials},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			form := url.Values{}
+			form.Set(usernameFormKey, tc.username)
+			form.Set(passwordFormKey, tc.password)
+			r := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(form.Encode()))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+			w := httptest.NewRecorder()
+			err := store.Login(w, r)
+			assert.Equal(t, tc.err, err)
+			if err != nil {
+				return
+			}
+
+			// the session is for the stored username
+			r = httptest.NewRequest(http.MethodGet, "/graphql", nil)
+			for _, c := range w.Result().Cookies() {
+				r.AddCookie(c)
+			}
+			userID, err := store.Authenticate(httptest.NewRecorder(), r)
+			assert.Nil(t, err)
+			assert.Equal(t, "viewer", userID)
+		})
+	}
+}
+
+func TestGetUserRole(t *testing.T) {
+	store := newUserStore(t)
+
+	testCases := []struct {
+		name   string
+		userID string
+		role   models.UserRole
+		err    error
+	}{
+		{"no authentication", "", models.UserRoleAdmin, nil},
+		{"configured administrator", "user", models.UserRoleAdmin, nil},
+		{"editor", "editor", models.UserRoleEditor, nil},
+		{"read only", "viewer", models.UserRoleReadOnly, nil},
+		{"deleted user", "deleted", "", ErrUnauthorized},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			role, err := store.GetUserRole(context.Background(), tc.userID)
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.role, role)
+		})
+	}
+}
+
+func TestHasUserRole(t *testing.T) {
+	ctx := SetCurrentUserRole(context.Background(), models.UserRoleEditor)
+
+	assert.True(t, HasUserRole(ctx, models.UserRoleReadOnly))
+	assert.True(t, HasUserRole(ctx, models.UserRoleEditor))
+	assert.False(t, HasUserRole(ctx, models.UserRoleAdmin))
+
+	// contexts without a user are not restricted
+	assert.True(t, HasUserRole(context.Background(), models.UserRoleAdmin))
+}
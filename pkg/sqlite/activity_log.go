@@ -0,0 +1,91 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const activityLogTable = "activity_log"
+
+type activityLogQueryBuilder struct {
+	repository
+}
+
+var ActivityLogReaderWriter = &activityLogQueryBuilder{
+	repository{
+		tableName: activityLogTable,
+		idColumn:  idColumn,
+	},
+}
+
+func (qb *activityLogQueryBuilder) Create(ctx context.Context, newObject models.ActivityLogEntry) error {
+	_, err := qb.insert(ctx, newObject)
+	return err
+}
+
+func (qb *activityLogQueryBuilder) DestroyOlderThan(ctx context.Context, t time.Time) error {
+	_, err := qb.tx.Exec(ctx, fmt.Sprintf("DELETE FROM %s WHERE created_at < ?", activityLogTable), t)
+	return err
+}
+
+func (qb *activityLogQueryBuilder) Query(ctx context.Context, filter *models.ActivityLogFilterType, findFilter *models.FindFilterType) ([]*models.ActivityLogEntry, int, error) {
+	if findFilter == nil {
+		findFilter = &models.FindFilterType{}
+	}
+
+	var whereClauses []string
+	var args []interface{}
+
+	if filter != nil {
+		addEqual := func(column string, v *string) {
+			if v != nil {
+				whereClauses = append(whereClauses, column+" = ?")
+				args = append(args, *v)
+			}
+		}
+
+		addEqual("auth_method", filter.AuthMethod)
+		addEqual("credential", filter.Credential)
+		addEqual("remote_addr", filter.RemoteAddr)
+		addEqual("action", filter.Action)
+		addEqual("target_type", filter.TargetType)
+		addEqual("target_id", filter.TargetID)
+
+		if filter.Since != nil {
+			whereClauses = append(whereClauses, "created_at >= ?")
+			args = append(args, *filter.Since)
+		}
+		if filter.Until != nil {
+			whereClauses = append(whereClauses, "created_at < ?")
+			args = append(args, *filter.Until)
+		}
+	}
+
+	body := selectAll(activityLogTable)
+	if len(whereClauses) > 0 {
+		body += " WHERE " + strings.Join(whereClauses, " AND ")
+	}
+
+	count, err := qb.runCountQuery(ctx, qb.buildCountQuery(body), args)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	direction := "DESC"
+	if findFilter.Direction != nil && *findFilter.Direction == models.SortDirectionEnumAsc {
+		direction = "ASC"
+	}
+
+	query := body + fmt.Sprintf(" ORDER BY created_at %[1]s, id %[1]s", direction) + getPagination(findFilter)
+
+	var ret models.ActivityLogEntries
+	if err := qb.query(ctx, query, args, &ret); err != nil {
+		return nil, 0, err
+	}
+
+	return []*models.ActivityLogEntry(ret), count, nil
+}
@@ -0,0 +1,73 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sqlite"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActivityLogQuery(t *testing.T) {
+	qb := sqlite.ActivityLogReaderWriter
+	now := time.Now()
+	stream := "stream"
+	sceneType := "scene"
+	sceneID := "1"
+
+	entries := []models.ActivityLogEntry{
+		{CreatedAt: now.Add(-48 * time.Hour), AuthMethod: "session", Credential: "admin", Action: "sceneUpdate"},
+		{CreatedAt: now.Add(-time.Hour), AuthMethod: "api_key", Credential: "abcd1234", Action: stream, TargetType: &sceneType, TargetID: &sceneID},
+		{CreatedAt: now, AuthMethod: "session", Credential: "admin", Action: stream, TargetType: &sceneType, TargetID: &sceneID},
+	}
+
+	withRollbackTxn(func(ctx context.Context) error {
+		for _, e := range entries {
+			if err := qb.Create(ctx, e); err != nil {
+				t.Errorf("Error creating activity log entry: %s", err.Error())
+				return nil
+			}
+		}
+
+		got, count, err := qb.Query(ctx, nil, nil)
+		if err != nil {
+			t.Errorf("Error querying activity log: %s", err.Error())
+			return nil
+		}
+		assert.Equal(t, 3, count)
+		assert.Equal(t, "admin", got[0].Credential)
+		assert.Equal(t, stream, got[0].Action)
+
+		perPage := 1
+		since := now.Add(-2 * time.Hour)
+		got, count, err = qb.Query(ctx, &models.ActivityLogFilterType{
+			Action: &stream,
+			Since:  &since,
+		}, &models.FindFilterType{PerPage: &perPage})
+		if err != nil {
+			t.Errorf("Error querying activity log: %s", err.Error())
+			return nil
+		}
+		assert.Equal(t, 2, count)
+		assert.Len(t, got, 1)
+
+		if err := qb.DestroyOlderThan(ctx, now.Add(-24*time.Hour)); err != nil {
+			t.Errorf("Error pruning activity log: %s", err.Error())
+			return nil
+		}
+
+		_, count, err = qb.Query(ctx, nil, nil)
+		if err != nil {
+			t.Errorf("Error querying activity log: %s", err.Error())
+			return nil
+		}
+		assert.Equal(t, 2, count)
+
+		return nil
+	})
+}
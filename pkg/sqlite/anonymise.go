@@ -89,6 +89,8 @@ func (db *Anonymiser) deleteStashIDs() error {
 		func() error { return db.truncateTable("scene_stash_ids") },
 		func() error { return db.truncateTable("studio_stash_ids") },
 		func() error { return db.truncateTable("performer_stash_ids") },
+		// suggestions contain stash IDs and the scraped metadata
+		func() error { return db.truncateTable("stash_box_match_suggestions") },
 	})
 }
 
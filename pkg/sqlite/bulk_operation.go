@@ -0,0 +1,55 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const bulkOperationTable = "bulk_operations"
+
+type bulkOperationQueryBuilder struct {
+	repository
+}
+
+var BulkOperationReaderWriter = &bulkOperationQueryBuilder{
+	repository{
+		tableName: bulkOperationTable,
+		idColumn:  idColumn,
+	},
+}
+
+func (qb *bulkOperationQueryBuilder) Create(ctx context.Context, newObject models.BulkOperation) (*models.BulkOperation, error) {
+	var ret models.BulkOperation
+	if err := qb.insertObject(ctx, newObject, &ret); err != nil {
+		return nil, err
+	}
+
+	return &ret, nil
+}
+
+func (qb *bulkOperationQueryBuilder) Destroy(ctx context.Context, id int) error {
+	return qb.destroyExisting(ctx, []int{id})
+}
+
+func (qb *bulkOperationQueryBuilder) DestroyOlderThan(ctx context.Context, t time.Time) error {
+	_, err := qb.tx.Exec(ctx, fmt.Sprintf("DELETE FROM %s WHERE created_at < ?", bulkOperationTable), t)
+	return err
+}
+
+func (qb *bulkOperationQueryBuilder) FindLatest(ctx context.Context, t time.Time) (*models.BulkOperation, error) {
+	query := fmt.Sprintf("SELECT * FROM %s WHERE created_at >= ? ORDER BY created_at DESC, id DESC LIMIT 1", bulkOperationTable)
+
+	var ret models.BulkOperations
+	if err := qb.query(ctx, query, []interface{}{t}, &ret); err != nil {
+		return nil, err
+	}
+
+	if len(ret) == 0 {
+		return nil, nil
+	}
+
+	return ret[0], nil
+}
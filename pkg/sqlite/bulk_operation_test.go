@@ -0,0 +1,140 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene"
+	"github.com/stashapp/stash/pkg/sqlite"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBulkOperationFindLatest(t *testing.T) {
+	qb := sqlite.BulkOperationReaderWriter
+	now := time.Now()
+
+	withRollbackTxn(func(ctx context.Context) error {
+		create := func(operation string, createdAt time.Time) *models.BulkOperation {
+			ret, err := qb.Create(ctx, models.BulkOperation{
+				Operation: operation,
+				Reverse:   []byte("{}"),
+				CreatedAt: createdAt,
+			})
+			if err != nil {
+				t.Fatalf("Error creating bulk operation: %s", err.Error())
+			}
+			return ret
+		}
+
+		create("old", now.Add(-time.Hour))
+		latest := create("latest", now)
+
+		got, err := qb.FindLatest(ctx, now.Add(-time.Minute))
+		if err != nil {
+			t.Errorf("Error finding latest bulk operation: %s", err.Error())
+			return nil
+		}
+		if assert.NotNil(t, got) {
+			assert.Equal(t, latest.ID, got.ID)
+			assert.Equal(t, []byte("{}"), got.Reverse)
+		}
+
+		if err := qb.Destroy(ctx, latest.ID); err != nil {
+			t.Errorf("Error destroying bulk operation: %s", err.Error())
+			return nil
+		}
+
+		// expired operations are not returned
+		got, err = qb.FindLatest(ctx, now.Add(-time.Minute))
+		if err != nil {
+			t.Errorf("Error finding latest bulk operation: %s", err.Error())
+			return nil
+		}
+		assert.Nil(t, got)
+
+		if err := qb.DestroyOlderThan(ctx, now); err != nil {
+			t.Errorf("Error destroying old bulk operations: %s", err.Error())
+			return nil
+		}
+
+		got, err = qb.FindLatest(ctx, now.Add(-2*time.Hour))
+		if err != nil {
+			t.Errorf("Error finding latest bulk operation: %s", err.Error())
+			return nil
+		}
+		assert.Nil(t, got)
+
+		return nil
+	})
+}
+
+func TestBulkOperationSceneSnapshotRestore(t *testing.T) {
+	withRollbackTxn(func(ctx context.Context) error {
+		qb := db.Scene
+		mqb := sqlite.SceneMarkerReaderWriter
+
+		s, err := qb.Find(ctx, sceneIDs[sceneIdxWithMarkerAndTag])
+		if err != nil {
+			t.Errorf("Error finding scene: %s", err.Error())
+			return nil
+		}
+
+		snapshot, err := scene.TakeSnapshot(ctx, qb, s)
+		if err != nil {
+			t.Errorf("Error taking snapshot: %s", err.Error())
+			return nil
+		}
+		if err := snapshot.LoadMarkers(ctx, mqb); err != nil {
+			t.Errorf("Error loading markers: %s", err.Error())
+			return nil
+		}
+
+		for _, m := range snapshot.Markers {
+			if err := mqb.Destroy(ctx, m.Marker.ID); err != nil {
+				t.Errorf("Error destroying marker: %s", err.Error())
+				return nil
+			}
+		}
+		if err := qb.Destroy(ctx, s.ID); err != nil {
+			t.Errorf("Error destroying scene: %s", err.Error())
+			return nil
+		}
+
+		restored, err := snapshot.Restore(ctx, qb, mqb)
+		if err != nil {
+			t.Errorf("Error restoring scene: %s", err.Error())
+			return nil
+		}
+
+		assert.NotEqual(t, s.ID, restored.ID)
+		assert.Equal(t, s.Title, restored.Title)
+
+		if err := restored.LoadRelationships(ctx, qb); err != nil {
+			t.Errorf("Error loading relationships: %s", err.Error())
+			return nil
+		}
+		assert.Equal(t, snapshot.TagIDs, restored.TagIDs.List())
+		assert.Len(t, restored.Files.List(), len(snapshot.FileIDs))
+
+		markers, err := mqb.FindBySceneID(ctx, restored.ID)
+		if err != nil {
+			t.Errorf("Error finding markers: %s", err.Error())
+			return nil
+		}
+		if assert.Len(t, markers, len(snapshot.Markers)) {
+			tagIDs, err := mqb.GetTagIDs(ctx, markers[0].ID)
+			if err != nil {
+				t.Errorf("Error getting marker tags: %s", err.Error())
+				return nil
+			}
+			assert.ElementsMatch(t, snapshot.Markers[0].TagIDs, tagIDs)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,273 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// consistencyCheck finds and repairs the issues of a single type.
+type consistencyCheck struct {
+	issueType models.ConsistencyIssueType
+	find      func(ctx context.Context) ([]*models.ConsistencyIssue, error)
+	// repair repairs the issues returned by find. Nil if the issues cannot
+	// be repaired automatically.
+	repair func(ctx context.Context, issues []*models.ConsistencyIssue) error
+}
+
+type consistencyChecker struct {
+	tx dbWrapper
+}
+
+var ConsistencyChecker = &consistencyChecker{}
+
+func (c *consistencyChecker) checks() []consistencyCheck {
+	return []consistencyCheck{
+		{models.ConsistencyIssueTypeOrphanedRow, c.findOrphanedRows, c.deleteOrphanedRows},
+		{models.ConsistencyIssueTypeGalleryMissingZip, c.findGalleriesMissingZip, c.removeMissingZips},
+		{models.ConsistencyIssueTypeFileWithoutObject, c.findFilesWithoutObject, c.deleteFilesWithoutObject},
+		{models.ConsistencyIssueTypeSceneWithoutFile, c.findScenesWithoutFile, nil},
+		{models.ConsistencyIssueTypeFingerprintMismatch, c.findFingerprintMismatches, c.deleteMismatchedFingerprints},
+	}
+}
+
+func (c *consistencyChecker) CheckConsistency(ctx context.Context) ([]*models.ConsistencyIssue, error) {
+	var ret []*models.ConsistencyIssue
+	for _, check := range c.checks() {
+		issues, err := check.find(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("checking %s: %w", check.issueType, err)
+		}
+		ret = append(ret, issues...)
+	}
+
+	return ret, nil
+}
+
+func (c *consistencyChecker) RepairConsistency(ctx context.Context, types []models.ConsistencyIssueType) (int, error) {
+	repaired := 0
+	for _, check := range c.checks() {
+		if check.repair == nil || !issueTypesInclude(types, check.issueType) {
+			continue
+		}
+
+		issues, err := check.find(ctx)
+		if err != nil {
+			return repaired, fmt.Errorf("checking %s: %w", check.issueType, err)
+		}
+
+		if len(issues) == 0 {
+			continue
+		}
+
+		if err := check.repair(ctx, issues); err != nil {
+			return repaired, fmt.Errorf("repairing %s: %w", check.issueType, err)
+		}
+		repaired += len(issues)
+	}
+
+	return repaired, nil
+}
+
+func issueTypesInclude(types []models.ConsistencyIssueType, t models.ConsistencyIssueType) bool {
+	for _, tt := range types {
+		if tt == t {
+			return true
+		}
+	}
+	return false
+}
+
+type foreignKeyViolation struct {
+	Table string `db:"table"`
+	// nil for tables without rowid
+	RowID  *int   `db:"rowid"`
+	Parent string `db:"parent"`
+	FKID   int    `db:"fkid"`
+}
+
+// isGalleryMissingZip returns true if the violation is a gallery referencing
+// a missing zip file, which is reported separately.
+func (v foreignKeyViolation) isGalleryMissingZip() bool {
+	return v.Table == galleriesFilesTable && v.Parent == fileTable
+}
+
+func (c *consistencyChecker) findOrphanedRows(ctx context.Context) ([]*models.ConsistencyIssue, error) {
+	var violations []foreignKeyViolation
+	if err := c.tx.Select(ctx, &violations, "PRAGMA foreign_key_check"); err != nil {
+		return nil, err
+	}
+
+	var ret []*models.ConsistencyIssue
+	for _, v := range violations {
+		// rows without rowid cannot be identified
+		if v.RowID == nil || v.isGalleryMissingZip() {
+			continue
+		}
+
+		ret = append(ret, &models.ConsistencyIssue{
+			Type:        models.ConsistencyIssueTypeOrphanedRow,
+			Table:       v.Table,
+			ID:          *v.RowID,
+			Description: fmt.Sprintf("%s row %d references a missing %s row", v.Table, *v.RowID, v.Parent),
+		})
+	}
+
+	return ret, nil
+}
+
+func (c *consistencyChecker) deleteOrphanedRows(ctx context.Context, issues []*models.ConsistencyIssue) error {
+	for _, i := range issues {
+		// table names come from the foreign key check, not user input
+		if _, err := c.tx.Exec(ctx, "DELETE FROM `"+i.Table+"` WHERE rowid = ?", i.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+const galleriesMissingZipWhere = "file_id NOT IN (SELECT id FROM " + fileTable + ")"
+
+func (c *consistencyChecker) findGalleriesMissingZip(ctx context.Context) ([]*models.ConsistencyIssue, error) {
+	var rows []struct {
+		GalleryID int `db:"gallery_id"`
+		FileID    int `db:"file_id"`
+	}
+	query := "SELECT gallery_id, file_id FROM " + galleriesFilesTable + " WHERE " + galleriesMissingZipWhere + " ORDER BY gallery_id, file_id"
+	if err := c.tx.Select(ctx, &rows, query); err != nil {
+		return nil, err
+	}
+
+	ret := make([]*models.ConsistencyIssue, len(rows))
+	for i, r := range rows {
+		ret[i] = &models.ConsistencyIssue{
+			Type:        models.ConsistencyIssueTypeGalleryMissingZip,
+			Table:       galleryTable,
+			ID:          r.GalleryID,
+			Description: fmt.Sprintf("Gallery %d references missing zip file %d", r.GalleryID, r.FileID),
+		}
+	}
+
+	return ret, nil
+}
+
+func (c *consistencyChecker) removeMissingZips(ctx context.Context, issues []*models.ConsistencyIssue) error {
+	_, err := c.tx.Exec(ctx, "DELETE FROM "+galleriesFilesTable+" WHERE "+galleriesMissingZipWhere)
+	return err
+}
+
+// filesWithoutObjectWhere matches video files without scenes and image
+// files without images.
+const filesWithoutObjectWhere = `(files.id IN (SELECT file_id FROM ` + videoFileTable + `) AND files.id NOT IN (SELECT file_id FROM ` + scenesFilesTable + `))
+OR (files.id IN (SELECT file_id FROM ` + imageFileTable + `) AND files.id NOT IN (SELECT file_id FROM ` + imagesFilesTable + `))`
+
+func (c *consistencyChecker) findFilesWithoutObject(ctx context.Context) ([]*models.ConsistencyIssue, error) {
+	var rows []struct {
+		ID       int    `db:"id"`
+		Folder   string `db:"folder"`
+		Basename string `db:"basename"`
+		Video    bool   `db:"video"`
+	}
+	query := `SELECT files.id, COALESCE(folders.path, '') AS folder, files.basename,
+files.id IN (SELECT file_id FROM ` + videoFileTable + `) AS video
+FROM files
+LEFT JOIN folders ON folders.id = files.parent_folder_id
+WHERE ` + filesWithoutObjectWhere + `
+ORDER BY files.id`
+	if err := c.tx.Select(ctx, &rows, query); err != nil {
+		return nil, err
+	}
+
+	ret := make([]*models.ConsistencyIssue, len(rows))
+	for i, r := range rows {
+		desc := fmt.Sprintf("Image file %s is not linked to an image", filepath.Join(r.Folder, r.Basename))
+		if r.Video {
+			desc = fmt.Sprintf("Video file %s is not linked to a scene", filepath.Join(r.Folder, r.Basename))
+		}
+
+		ret[i] = &models.ConsistencyIssue{
+			Type:        models.ConsistencyIssueTypeFileWithoutObject,
+			Table:       fileTable,
+			ID:          r.ID,
+			Description: desc,
+		}
+	}
+
+	return ret, nil
+}
+
+func (c *consistencyChecker) deleteFilesWithoutObject(ctx context.Context, issues []*models.ConsistencyIssue) error {
+	_, err := c.tx.Exec(ctx, "DELETE FROM files WHERE "+filesWithoutObjectWhere)
+	return err
+}
+
+func (c *consistencyChecker) findScenesWithoutFile(ctx context.Context) ([]*models.ConsistencyIssue, error) {
+	var rows []struct {
+		ID    int    `db:"id"`
+		Title string `db:"title"`
+	}
+	query := "SELECT id, COALESCE(title, '') AS title FROM " + sceneTable + " WHERE id NOT IN (SELECT scene_id FROM " + scenesFilesTable + ") ORDER BY id"
+	if err := c.tx.Select(ctx, &rows, query); err != nil {
+		return nil, err
+	}
+
+	ret := make([]*models.ConsistencyIssue, len(rows))
+	for i, r := range rows {
+		desc := fmt.Sprintf("Scene %d has no files", r.ID)
+		if r.Title != "" {
+			desc = fmt.Sprintf("Scene %d (%s) has no files", r.ID, r.Title)
+		}
+
+		ret[i] = &models.ConsistencyIssue{
+			Type:        models.ConsistencyIssueTypeSceneWithoutFile,
+			Table:       sceneTable,
+			ID:          r.ID,
+			Description: desc,
+		}
+	}
+
+	return ret, nil
+}
+
+// mismatchedFingerprintsQuery selects the files and fingerprint types with
+// more than one fingerprint.
+const mismatchedFingerprintsQuery = "SELECT file_id, type FROM " + fingerprintTable + " GROUP BY file_id, type HAVING COUNT(*) > 1"
+
+func (c *consistencyChecker) findFingerprintMismatches(ctx context.Context) ([]*models.ConsistencyIssue, error) {
+	var rows []struct {
+		FileID int    `db:"file_id"`
+		Type   string `db:"type"`
+	}
+	if err := c.tx.Select(ctx, &rows, mismatchedFingerprintsQuery+" ORDER BY file_id, type"); err != nil {
+		return nil, err
+	}
+
+	// group the types by file
+	var ret []*models.ConsistencyIssue
+	var types []string
+	for i, r := range rows {
+		types = append(types, r.Type)
+		if i+1 < len(rows) && rows[i+1].FileID == r.FileID {
+			continue
+		}
+
+		ret = append(ret, &models.ConsistencyIssue{
+			Type:        models.ConsistencyIssueTypeFingerprintMismatch,
+			Table:       fileTable,
+			ID:          r.FileID,
+			Description: fmt.Sprintf("File %d has conflicting %s fingerprints", r.FileID, strings.Join(types, ", ")),
+		})
+		types = nil
+	}
+
+	return ret, nil
+}
+
+func (c *consistencyChecker) deleteMismatchedFingerprints(ctx context.Context, issues []*models.ConsistencyIssue) error {
+	_, err := c.tx.Exec(ctx, "DELETE FROM "+fingerprintTable+" WHERE (file_id, type) IN ("+mismatchedFingerprintsQuery+")")
+	return err
+}
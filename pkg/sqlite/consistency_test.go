@@ -0,0 +1,101 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sqlite"
+	"github.com/stretchr/testify/assert"
+)
+
+func findConsistencyIssue(issues []*models.ConsistencyIssue, t models.ConsistencyIssueType, id int) *models.ConsistencyIssue {
+	for _, i := range issues {
+		if i.Type == t && i.ID == id {
+			return i
+		}
+	}
+	return nil
+}
+
+func TestConsistencyChecker(t *testing.T) {
+	qb := sqlite.ConsistencyChecker
+
+	withRollbackTxn(func(ctx context.Context) error {
+		// video file without a scene, with conflicting md5 fingerprints
+		f := &file.VideoFile{
+			BaseFile: &file.BaseFile{
+				Basename:       "orphan.mp4",
+				ParentFolderID: folderIDs[folderIdxWithFiles],
+				DirEntry: file.DirEntry{
+					ModTime: time.Now(),
+				},
+				Fingerprints: []file.Fingerprint{
+					{Type: file.FingerprintTypeMD5, Fingerprint: "md5a"},
+					{Type: file.FingerprintTypeMD5, Fingerprint: "md5b"},
+				},
+			},
+			Format:     "mp4",
+			VideoCodec: "h264",
+			AudioCodec: "aac",
+		}
+		if err := db.File.Create(ctx, f); err != nil {
+			t.Errorf("Error creating file: %s", err.Error())
+			return nil
+		}
+		fileID := int(f.ID)
+
+		s := &models.Scene{Title: "no files"}
+		if err := db.Scene.Create(ctx, s, nil); err != nil {
+			t.Errorf("Error creating scene: %s", err.Error())
+			return nil
+		}
+
+		issues, err := qb.CheckConsistency(ctx)
+		if err != nil {
+			t.Errorf("Error checking consistency: %s", err.Error())
+			return nil
+		}
+
+		assert.NotNil(t, findConsistencyIssue(issues, models.ConsistencyIssueTypeFileWithoutObject, fileID))
+		assert.NotNil(t, findConsistencyIssue(issues, models.ConsistencyIssueTypeFingerprintMismatch, fileID))
+		assert.NotNil(t, findConsistencyIssue(issues, models.ConsistencyIssueTypeSceneWithoutFile, s.ID))
+
+		// scenes without files cannot be repaired
+		repaired, err := qb.RepairConsistency(ctx, []models.ConsistencyIssueType{
+			models.ConsistencyIssueTypeFingerprintMismatch,
+			models.ConsistencyIssueTypeSceneWithoutFile,
+		})
+		if err != nil {
+			t.Errorf("Error repairing consistency: %s", err.Error())
+			return nil
+		}
+		assert.GreaterOrEqual(t, repaired, 1)
+
+		issues, err = qb.CheckConsistency(ctx)
+		if err != nil {
+			t.Errorf("Error checking consistency: %s", err.Error())
+			return nil
+		}
+
+		assert.Nil(t, findConsistencyIssue(issues, models.ConsistencyIssueTypeFingerprintMismatch, fileID))
+		assert.NotNil(t, findConsistencyIssue(issues, models.ConsistencyIssueTypeFileWithoutObject, fileID))
+		assert.NotNil(t, findConsistencyIssue(issues, models.ConsistencyIssueTypeSceneWithoutFile, s.ID))
+
+		if _, err := qb.RepairConsistency(ctx, []models.ConsistencyIssueType{models.ConsistencyIssueTypeFileWithoutObject}); err != nil {
+			t.Errorf("Error repairing consistency: %s", err.Error())
+			return nil
+		}
+
+		// the file entry is deleted
+		_, err = db.File.Find(ctx, f.ID)
+		assert.NotNil(t, err)
+
+		return nil
+	})
+}
@@ -0,0 +1,86 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/doug-martin/goqu/v9"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sliceutil/intslice"
+)
+
+// restrictedTagsQuery selects the ids of the provided restricted tags and all
+// of their descendants.
+func restrictedTagsQuery(tagIDs []int) string {
+	return fmt.Sprintf(`WITH RECURSIVE restricted_tags(id) AS (
+	SELECT id FROM tags WHERE id IN (%s)
+	UNION SELECT tags_relations.child_id FROM tags_relations
+	INNER JOIN restricted_tags ON restricted_tags.id = tags_relations.parent_id
+) SELECT id FROM restricted_tags`, strings.Join(intslice.IntSliceToStringSlice(tagIDs), ","))
+}
+
+// restrictedStudiosQuery selects the ids of the provided restricted studios
+// and all of their child studios.
+func restrictedStudiosQuery(studioIDs []int) string {
+	return fmt.Sprintf(`WITH RECURSIVE restricted_studios(id) AS (
+	SELECT id FROM studios WHERE id IN (%s)
+	UNION SELECT studios.id FROM studios
+	INNER JOIN restricted_studios ON restricted_studios.id = studios.parent_id
+) SELECT id FROM restricted_studios`, strings.Join(intslice.IntSliceToStringSlice(studioIDs), ","))
+}
+
+// contentRestrictionClauses returns the where clauses excluding rows of table
+// that are tagged with a restricted tag in joinTable, or that belong to a
+// restricted studio.
+func contentRestrictionClauses(r models.ContentRestriction, table, joinTable, fkColumn string) []string {
+	var ret []string
+	if len(r.TagIDs) > 0 {
+		ret = append(ret, fmt.Sprintf("%s.id NOT IN (SELECT %s.%s FROM %s WHERE %s.tag_id IN (%s))", table, joinTable, fkColumn, joinTable, joinTable, restrictedTagsQuery(r.TagIDs)))
+	}
+	if len(r.StudioIDs) > 0 {
+		ret = append(ret, fmt.Sprintf("(%s.studio_id IS NULL OR %[1]s.studio_id NOT IN (%s))", table, restrictedStudiosQuery(r.StudioIDs)))
+	}
+	return ret
+}
+
+// restrictContent adds where clauses to the query excluding objects of table
+// that are hidden by the content restriction of the context, if any.
+func restrictContent(ctx context.Context, query *queryBuilder, table, joinTable, fkColumn string) {
+	if r, ok := models.GetContentRestriction(ctx); ok {
+		query.addWhere(contentRestrictionClauses(r, table, joinTable, fkColumn)...)
+	}
+}
+
+// restrictContentDataset is the goqu equivalent of restrictContent.
+func restrictContentDataset(ctx context.Context, q *goqu.SelectDataset, table, joinTable, fkColumn string) *goqu.SelectDataset {
+	if r, ok := models.GetContentRestriction(ctx); ok {
+		for _, c := range contentRestrictionClauses(r, table, joinTable, fkColumn) {
+			q = q.Where(goqu.L(c))
+		}
+	}
+	return q
+}
+
+// contentRestrictionMarkerClauses returns the where clauses excluding scene
+// markers of hidden scenes, and markers tagged with a restricted tag.
+func contentRestrictionMarkerClauses(r models.ContentRestriction) []string {
+	ret := []string{
+		fmt.Sprintf("%s.scene_id IN (SELECT %s.id FROM %[2]s WHERE %s)", sceneMarkerTable, sceneTable, strings.Join(contentRestrictionClauses(r, sceneTable, scenesTagsTable, sceneIDColumn), " AND ")),
+	}
+	if len(r.TagIDs) > 0 {
+		tagsQuery := restrictedTagsQuery(r.TagIDs)
+		ret = append(ret,
+			fmt.Sprintf("%s.id NOT IN (SELECT scene_markers_tags.scene_marker_id FROM scene_markers_tags WHERE scene_markers_tags.tag_id IN (%s))", sceneMarkerTable, tagsQuery),
+			fmt.Sprintf("%s.primary_tag_id NOT IN (%s)", sceneMarkerTable, tagsQuery),
+		)
+	}
+	return ret
+}
+
+func restrictContentMarkers(ctx context.Context, query *queryBuilder) {
+	if r, ok := models.GetContentRestriction(ctx); ok && !r.Empty() {
+		query.addWhere(contentRestrictionMarkerClauses(r)...)
+	}
+}
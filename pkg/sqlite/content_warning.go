@@ -0,0 +1,69 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/doug-martin/goqu/v9"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+// contentWarningTagsQuery selects the ids of content warning tags and all of
+// their descendants.
+const contentWarningTagsQuery = `WITH RECURSIVE content_warning_tags(id) AS (
+	SELECT id FROM tags WHERE content_warning = 1
+	UNION SELECT tags_relations.child_id FROM tags_relations
+	INNER JOIN content_warning_tags ON content_warning_tags.id = tags_relations.parent_id
+) SELECT id FROM content_warning_tags`
+
+// contentWarningClause returns a where clause excluding rows where column
+// references an object tagged with a content warning tag in joinTable.
+func contentWarningClause(column, joinTable, fkColumn string) string {
+	return fmt.Sprintf("%s NOT IN (SELECT %s.%s FROM %s WHERE %s.tag_id IN (%s))", column, joinTable, fkColumn, joinTable, joinTable, contentWarningTagsQuery)
+}
+
+// hideContentWarnings adds a where clause to the query excluding objects of
+// table that are tagged with a content warning tag, if content warnings are
+// hidden in the context.
+func hideContentWarnings(ctx context.Context, query *queryBuilder, table, joinTable, fkColumn string) {
+	if models.ContentWarningsHidden(ctx) {
+		query.addWhere(contentWarningClause(table+".id", joinTable, fkColumn))
+	}
+}
+
+// hideContentWarningsDataset is the goqu equivalent of hideContentWarnings.
+func hideContentWarningsDataset(ctx context.Context, q *goqu.SelectDataset, table, joinTable, fkColumn string) *goqu.SelectDataset {
+	if models.ContentWarningsHidden(ctx) {
+		q = q.Where(goqu.L(contentWarningClause(table+".id", joinTable, fkColumn)))
+	}
+	return q
+}
+
+// contentWarningMarkerClauses returns the where clauses excluding scene
+// markers of hidden scenes, and markers tagged with a content warning tag.
+func contentWarningMarkerClauses() []string {
+	return []string{
+		contentWarningClause(sceneMarkerTable+".scene_id", scenesTagsTable, sceneIDColumn),
+		contentWarningClause(sceneMarkerTable+".id", "scene_markers_tags", "scene_marker_id"),
+		fmt.Sprintf("%s.primary_tag_id NOT IN (%s)", sceneMarkerTable, contentWarningTagsQuery),
+	}
+}
+
+func hideContentWarningMarkers(ctx context.Context, query *queryBuilder) {
+	if models.ContentWarningsHidden(ctx) {
+		query.addWhere(contentWarningMarkerClauses()...)
+	}
+}
+
+// contentWarningTagClause returns a where clause excluding content warning
+// tags and their descendants.
+func contentWarningTagClause() string {
+	return fmt.Sprintf("%s.id NOT IN (%s)", tagTable, contentWarningTagsQuery)
+}
+
+func hideContentWarningTags(ctx context.Context, query *queryBuilder) {
+	if models.ContentWarningsHidden(ctx) {
+		query.addWhere(contentWarningTagClause())
+	}
+}
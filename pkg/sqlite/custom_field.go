@@ -0,0 +1,449 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const (
+	customFieldTable       = "custom_fields"
+	customFieldIDColumn    = "field_id"
+	customFieldValueColumn = "value"
+
+	customFieldSortPrefix = "custom_field_"
+)
+
+type customFieldRow struct {
+	ID         int            `db:"id" goqu:"skipinsert"`
+	ObjectType string         `db:"object_type"`
+	Name       string         `db:"name"`
+	Type       string         `db:"type"`
+	Options    sql.NullString `db:"options"`
+	CreatedAt  time.Time      `db:"created_at"`
+	UpdatedAt  time.Time      `db:"updated_at"`
+}
+
+func (r *customFieldRow) fromCustomField(o models.CustomField) error {
+	r.ID = o.ID
+	r.ObjectType = o.ObjectType.String()
+	r.Name = o.Name
+	r.Type = o.Type.String()
+	r.Options = sql.NullString{}
+	if len(o.Options) > 0 {
+		data, err := json.Marshal(o.Options)
+		if err != nil {
+			return err
+		}
+		r.Options = sql.NullString{String: string(data), Valid: true}
+	}
+	r.CreatedAt = o.CreatedAt
+	r.UpdatedAt = o.UpdatedAt
+	return nil
+}
+
+func (r *customFieldRow) resolve() (*models.CustomField, error) {
+	ret := &models.CustomField{
+		ID:         r.ID,
+		ObjectType: models.CustomFieldObjectType(r.ObjectType),
+		Name:       r.Name,
+		Type:       models.CustomFieldType(r.Type),
+		CreatedAt:  r.CreatedAt,
+		UpdatedAt:  r.UpdatedAt,
+	}
+
+	if r.Options.Valid {
+		if err := json.Unmarshal([]byte(r.Options.String), &ret.Options); err != nil {
+			return nil, fmt.Errorf("decoding options of custom field %d: %w", r.ID, err)
+		}
+	}
+
+	return ret, nil
+}
+
+type customFieldRows []*customFieldRow
+
+func (m *customFieldRows) Append(o interface{}) {
+	*m = append(*m, o.(*customFieldRow))
+}
+
+func (m *customFieldRows) New() interface{} {
+	return &customFieldRow{}
+}
+
+func (m customFieldRows) resolve() ([]*models.CustomField, error) {
+	ret := make([]*models.CustomField, len(m))
+	for i, r := range m {
+		var err error
+		if ret[i], err = r.resolve(); err != nil {
+			return nil, err
+		}
+	}
+
+	return ret, nil
+}
+
+// customFieldValuesTable returns the table containing the custom field values
+// of the object type, and its column referencing the object.
+func customFieldValuesTable(objectType models.CustomFieldObjectType) (table string, fkColumn string, err error) {
+	switch objectType {
+	case models.CustomFieldObjectTypeScene:
+		return "scenes_custom_fields", sceneIDColumn, nil
+	case models.CustomFieldObjectTypePerformer:
+		return "performers_custom_fields", performerIDColumn, nil
+	case models.CustomFieldObjectTypeStudio:
+		return "studios_custom_fields", studioIDColumn, nil
+	}
+
+	return "", "", fmt.Errorf("unsupported custom field object type %q", objectType)
+}
+
+type customFieldQueryBuilder struct {
+	repository
+}
+
+var CustomFieldReaderWriter = &customFieldQueryBuilder{
+	repository{
+		tableName: customFieldTable,
+		idColumn:  idColumn,
+	},
+}
+
+func (qb *customFieldQueryBuilder) Create(ctx context.Context, newObject models.CustomField) (*models.CustomField, error) {
+	var r customFieldRow
+	if err := r.fromCustomField(newObject); err != nil {
+		return nil, err
+	}
+
+	var ret customFieldRow
+	if err := qb.insertObject(ctx, r, &ret); err != nil {
+		return nil, err
+	}
+
+	return ret.resolve()
+}
+
+func (qb *customFieldQueryBuilder) Update(ctx context.Context, updatedObject models.CustomField) (*models.CustomField, error) {
+	var r customFieldRow
+	if err := r.fromCustomField(updatedObject); err != nil {
+		return nil, err
+	}
+
+	const partial = false
+	if err := qb.update(ctx, updatedObject.ID, r, partial); err != nil {
+		return nil, err
+	}
+
+	return qb.Find(ctx, updatedObject.ID)
+}
+
+func (qb *customFieldQueryBuilder) Destroy(ctx context.Context, id int) error {
+	return qb.destroyExisting(ctx, []int{id})
+}
+
+func (qb *customFieldQueryBuilder) Find(ctx context.Context, id int) (*models.CustomField, error) {
+	var ret customFieldRow
+	if err := qb.getByID(ctx, id, &ret); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return ret.resolve()
+}
+
+func (qb *customFieldQueryBuilder) FindByName(ctx context.Context, objectType models.CustomFieldObjectType, name string) (*models.CustomField, error) {
+	query := fmt.Sprintf("SELECT * FROM %s WHERE object_type = ? AND name = ? COLLATE NOCASE LIMIT 1", customFieldTable)
+
+	var ret customFieldRows
+	if err := qb.query(ctx, query, []interface{}{objectType.String(), name}, &ret); err != nil {
+		return nil, err
+	}
+
+	if len(ret) == 0 {
+		return nil, nil
+	}
+
+	return ret[0].resolve()
+}
+
+func (qb *customFieldQueryBuilder) FindByObjectType(ctx context.Context, objectType models.CustomFieldObjectType) ([]*models.CustomField, error) {
+	query := selectAll(customFieldTable) + " WHERE object_type = ?" + getSort("name", "ASC", customFieldTable)
+
+	var ret customFieldRows
+	if err := qb.query(ctx, query, []interface{}{objectType.String()}, &ret); err != nil {
+		return nil, err
+	}
+
+	return ret.resolve()
+}
+
+func (qb *customFieldQueryBuilder) All(ctx context.Context) ([]*models.CustomField, error) {
+	query := selectAll(customFieldTable) + " ORDER BY object_type ASC, name ASC"
+
+	var ret customFieldRows
+	if err := qb.query(ctx, query, nil, &ret); err != nil {
+		return nil, err
+	}
+
+	return ret.resolve()
+}
+
+func (qb *customFieldQueryBuilder) GetValues(ctx context.Context, objectType models.CustomFieldObjectType, objectID int) ([]models.CustomFieldValue, error) {
+	table, fkColumn, err := customFieldValuesTable(objectType)
+	if err != nil {
+		return nil, err
+	}
+
+	// the field is needed to convert the stored value
+	query := fmt.Sprintf(`SELECT %[2]s.*, %[1]s.value FROM %[1]s
+INNER JOIN %[2]s ON %[2]s.id = %[1]s.field_id
+WHERE %[1]s.%[3]s = ?
+ORDER BY %[2]s.name ASC`, table, customFieldTable, fkColumn)
+
+	type valueRow struct {
+		customFieldRow
+		Value interface{} `db:"value"`
+	}
+
+	var ret []models.CustomFieldValue
+	if err := qb.queryFunc(ctx, query, []interface{}{objectID}, false, func(r *sqlx.Rows) error {
+		var v valueRow
+		if err := r.StructScan(&v); err != nil {
+			return err
+		}
+
+		field, err := v.resolve()
+		if err != nil {
+			return err
+		}
+
+		ret = append(ret, models.CustomFieldValue{
+			FieldID: field.ID,
+			Value:   field.ValueFromDB(v.Value),
+		})
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (qb *customFieldQueryBuilder) SetValues(ctx context.Context, objectType models.CustomFieldObjectType, objectID int, values []models.CustomFieldValue) error {
+	table, fkColumn, err := customFieldValuesTable(objectType)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range values {
+		if v.Value == nil {
+			_, err = qb.tx.Exec(ctx, fmt.Sprintf("DELETE FROM %s WHERE %s = ? AND field_id = ?", table, fkColumn), objectID, v.FieldID)
+		} else {
+			_, err = qb.tx.Exec(ctx, fmt.Sprintf("INSERT OR REPLACE INTO %s (%s, field_id, value) VALUES (?, ?, ?)", table, fkColumn), objectID, v.FieldID, v.Value)
+		}
+
+		if err != nil {
+			return fmt.Errorf("setting custom field %d: %w", v.FieldID, err)
+		}
+	}
+
+	return nil
+}
+
+// customFieldsCriterionHandler filters objects of the object type by their
+// custom field values. Objects without a value for the field match the
+// negated modifiers. INCLUDES and EXCLUDES accept a list of values, and range
+// modifiers are only supported by number and date fields.
+func customFieldsCriterionHandler(objectType models.CustomFieldObjectType, primaryTable string, criteria []*models.CustomFieldCriterionInput) criterionHandlerFunc {
+	return func(ctx context.Context, f *filterBuilder) {
+		for _, c := range criteria {
+			clause, args, err := getCustomFieldWhereClause(ctx, objectType, primaryTable, c)
+			if err != nil {
+				f.setError(err)
+				return
+			}
+
+			f.addWhere(clause, args...)
+		}
+	}
+}
+
+func getCustomFieldWhereClause(ctx context.Context, objectType models.CustomFieldObjectType, primaryTable string, c *models.CustomFieldCriterionInput) (string, []interface{}, error) {
+	fieldID, err := strconv.Atoi(c.FieldID)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid custom field id %q: %w", c.FieldID, err)
+	}
+
+	field, err := CustomFieldReaderWriter.Find(ctx, fieldID)
+	if err != nil {
+		return "", nil, err
+	}
+	if field == nil || field.ObjectType != objectType {
+		return "", nil, fmt.Errorf("custom field %d not found", fieldID)
+	}
+
+	table, fkColumn, err := customFieldValuesTable(objectType)
+	if err != nil {
+		return "", nil, err
+	}
+
+	exists := func(cond string) string {
+		if cond != "" {
+			cond = " AND " + cond
+		}
+		return fmt.Sprintf("EXISTS (SELECT 1 FROM %s cf WHERE cf.%s = %s.id AND cf.field_id = ?%s)", table, fkColumn, primaryTable, cond)
+	}
+	notExists := func(cond string) string {
+		return "NOT " + exists(cond)
+	}
+
+	args := []interface{}{fieldID}
+	value := func() error {
+		v, err := field.ParseValue(c.Value)
+		if err != nil {
+			return err
+		}
+		args = append(args, v)
+		return nil
+	}
+	// the bounds of ranges may be provided in either order
+	between := func() error {
+		v1, err := field.ParseValue(c.Value)
+		if err != nil {
+			return err
+		}
+		v2, err := field.ParseValue(c.Value2)
+		if err != nil {
+			return err
+		}
+
+		switch lower := v1.(type) {
+		case float64:
+			if lower > v2.(float64) {
+				v1, v2 = v2, v1
+			}
+		case string:
+			if lower > v2.(string) {
+				v1, v2 = v2, v1
+			}
+		}
+
+		args = append(args, v1, v2)
+		return nil
+	}
+	ranged := func() error {
+		if field.Type != models.CustomFieldTypeNumber && field.Type != models.CustomFieldTypeDate {
+			return fmt.Errorf("%w: %s modifier requires a number or date field", models.ErrInvalidCustomFieldValue, c.Modifier)
+		}
+		return nil
+	}
+
+	// INCLUDES and EXCLUDES accept a list of values, matching any of them.
+	// Text values match substrings, other values must match exactly.
+	anyOf := func(negate bool) (string, error) {
+		values, isList := c.Value.([]interface{})
+		if !isList {
+			values = []interface{}{c.Value}
+		}
+		if len(values) == 0 {
+			return "", fmt.Errorf("%w: %s modifier requires at least one value", models.ErrInvalidCustomFieldValue, c.Modifier)
+		}
+
+		var conds []string
+		for _, v := range values {
+			if field.Type == models.CustomFieldTypeText {
+				s, ok := v.(string)
+				if !ok {
+					return "", fmt.Errorf("%w: %s modifier requires a string", models.ErrInvalidCustomFieldValue, c.Modifier)
+				}
+				conds = append(conds, "cf.value LIKE ?")
+				args = append(args, "%"+s+"%")
+				continue
+			}
+
+			pv, err := field.ParseValue(v)
+			if err != nil {
+				return "", err
+			}
+			conds = append(conds, "cf.value = ?")
+			args = append(args, pv)
+		}
+
+		cond := "(" + strings.Join(conds, " OR ") + ")"
+		if negate {
+			return notExists(cond), nil
+		}
+		return exists(cond), nil
+	}
+
+	var clause string
+	var valueErr error
+	switch c.Modifier {
+	case models.CriterionModifierIsNull:
+		clause = notExists("")
+	case models.CriterionModifierNotNull:
+		clause = exists("")
+	case models.CriterionModifierEquals:
+		clause, valueErr = exists("cf.value = ?"), value()
+	case models.CriterionModifierNotEquals:
+		clause, valueErr = notExists("cf.value = ?"), value()
+	case models.CriterionModifierGreaterThan, models.CriterionModifierLessThan:
+		op := ">"
+		if c.Modifier == models.CriterionModifierLessThan {
+			op = "<"
+		}
+		if valueErr = ranged(); valueErr == nil {
+			clause, valueErr = exists("cf.value "+op+" ?"), value()
+		}
+	case models.CriterionModifierBetween:
+		if valueErr = ranged(); valueErr == nil {
+			clause, valueErr = exists("cf.value BETWEEN ? AND ?"), between()
+		}
+	case models.CriterionModifierNotBetween:
+		if valueErr = ranged(); valueErr == nil {
+			clause, valueErr = notExists("cf.value BETWEEN ? AND ?"), between()
+		}
+	case models.CriterionModifierIncludes:
+		clause, valueErr = anyOf(false)
+	case models.CriterionModifierExcludes:
+		clause, valueErr = anyOf(true)
+	default:
+		return "", nil, fmt.Errorf("unsupported custom field modifier %s", c.Modifier)
+	}
+
+	if valueErr != nil {
+		return "", nil, valueErr
+	}
+
+	return clause, args, nil
+}
+
+// getCustomFieldSort returns the sort clause if sort is the sort key of a
+// custom field, which is custom_field_ followed by the field id.
+func getCustomFieldSort(objectType models.CustomFieldObjectType, primaryTable string, sort string, direction string) (string, bool) {
+	if !strings.HasPrefix(sort, customFieldSortPrefix) {
+		return "", false
+	}
+
+	fieldID, err := strconv.Atoi(strings.TrimPrefix(sort, customFieldSortPrefix))
+	if err != nil {
+		return "", false
+	}
+
+	table, fkColumn, err := customFieldValuesTable(objectType)
+	if err != nil {
+		return "", false
+	}
+
+	return fmt.Sprintf(" ORDER BY (SELECT value FROM %s WHERE %s = %s.id AND field_id = %d) %s, %[3]s.id %[5]s", table, fkColumn, primaryTable, fieldID, getSortDirection(direction)), true
+}
@@ -0,0 +1,334 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sqlite"
+	"github.com/stretchr/testify/assert"
+)
+
+func createCustomField(ctx context.Context, t *testing.T, objectType models.CustomFieldObjectType, name string, fieldType models.CustomFieldType, options []string) *models.CustomField {
+	t.Helper()
+	now := time.Now()
+	ret, err := sqlite.CustomFieldReaderWriter.Create(ctx, models.CustomField{
+		ObjectType: objectType,
+		Name:       name,
+		Type:       fieldType,
+		Options:    options,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	})
+	if err != nil {
+		t.Fatalf("Error creating custom field: %s", err.Error())
+	}
+
+	return ret
+}
+
+func TestCustomFieldCRUD(t *testing.T) {
+	qb := sqlite.CustomFieldReaderWriter
+
+	withRollbackTxn(func(ctx context.Context) error {
+		field := createCustomField(ctx, t, models.CustomFieldObjectTypeStudio, "Region", models.CustomFieldTypeEnum, []string{"EU", "US"})
+
+		found, err := qb.FindByName(ctx, models.CustomFieldObjectTypeStudio, "region")
+		if err != nil {
+			t.Errorf("Error finding custom field by name: %s", err.Error())
+			return nil
+		}
+		assert.Equal(t, field, found)
+
+		// names are unique per object type only
+		found, err = qb.FindByName(ctx, models.CustomFieldObjectTypeScene, "region")
+		if err != nil {
+			t.Errorf("Error finding custom field by name: %s", err.Error())
+			return nil
+		}
+		assert.Nil(t, found)
+
+		updated := *field
+		updated.Options = []string{"EU", "US", "Asia"}
+		if _, err := qb.Update(ctx, updated); err != nil {
+			t.Errorf("Error updating custom field: %s", err.Error())
+			return nil
+		}
+
+		fields, err := qb.FindByObjectType(ctx, models.CustomFieldObjectTypeStudio)
+		if err != nil {
+			t.Errorf("Error finding custom fields: %s", err.Error())
+			return nil
+		}
+		if assert.Len(t, fields, 1) {
+			assert.Equal(t, updated.Options, fields[0].Options)
+		}
+
+		studioID := studioIDs[studioIdxWithScene]
+		if err := qb.SetValues(ctx, models.CustomFieldObjectTypeStudio, studioID, []models.CustomFieldValue{
+			{FieldID: field.ID, Value: "US"},
+		}); err != nil {
+			t.Errorf("Error setting custom field values: %s", err.Error())
+			return nil
+		}
+
+		// destroying the field removes its values
+		if err := qb.Destroy(ctx, field.ID); err != nil {
+			t.Errorf("Error destroying custom field: %s", err.Error())
+			return nil
+		}
+
+		values, err := qb.GetValues(ctx, models.CustomFieldObjectTypeStudio, studioID)
+		if err != nil {
+			t.Errorf("Error getting custom field values: %s", err.Error())
+			return nil
+		}
+		assert.Len(t, values, 0)
+
+		return nil
+	})
+}
+
+func TestCustomFieldValues(t *testing.T) {
+	qb := sqlite.CustomFieldReaderWriter
+	sceneID := sceneIDs[sceneIdxWithPerformer]
+
+	withRollbackTxn(func(ctx context.Context) error {
+		released := createCustomField(ctx, t, models.CustomFieldObjectTypeScene, "Released", models.CustomFieldTypeDate, nil)
+		remastered := createCustomField(ctx, t, models.CustomFieldObjectTypeScene, "Remastered", models.CustomFieldTypeBoolean, nil)
+		bitDepth := createCustomField(ctx, t, models.CustomFieldObjectTypeScene, "Bit depth", models.CustomFieldTypeNumber, nil)
+
+		if err := qb.SetValues(ctx, models.CustomFieldObjectTypeScene, sceneID, []models.CustomFieldValue{
+			{FieldID: released.ID, Value: "2020-01-02"},
+			{FieldID: remastered.ID, Value: true},
+			{FieldID: bitDepth.ID, Value: 10.0},
+		}); err != nil {
+			t.Errorf("Error setting custom field values: %s", err.Error())
+			return nil
+		}
+
+		// replace one value and remove another
+		if err := qb.SetValues(ctx, models.CustomFieldObjectTypeScene, sceneID, []models.CustomFieldValue{
+			{FieldID: bitDepth.ID, Value: 12.0},
+			{FieldID: remastered.ID, Value: nil},
+		}); err != nil {
+			t.Errorf("Error setting custom field values: %s", err.Error())
+			return nil
+		}
+
+		values, err := qb.GetValues(ctx, models.CustomFieldObjectTypeScene, sceneID)
+		if err != nil {
+			t.Errorf("Error getting custom field values: %s", err.Error())
+			return nil
+		}
+
+		assert.Equal(t, []models.CustomFieldValue{
+			{FieldID: bitDepth.ID, Value: 12.0},
+			{FieldID: released.ID, Value: "2020-01-02"},
+		}, values)
+
+		// unknown object types are rejected rather than panicking
+		_, err = qb.GetValues(ctx, models.CustomFieldObjectType("GALLERY"), sceneID)
+		assert.Error(t, err)
+
+		return nil
+	})
+}
+
+func TestCustomFieldFilterAndSort(t *testing.T) {
+	qb := sqlite.CustomFieldReaderWriter
+	testIDs := []int{
+		performerIDs[performerIdxWithScene],
+		performerIDs[performerIdx1WithScene],
+		performerIDs[performerIdx2WithScene],
+	}
+
+	withRollbackTxn(func(ctx context.Context) error {
+		field := createCustomField(ctx, t, models.CustomFieldObjectTypePerformer, "Shoe size", models.CustomFieldTypeNumber, nil)
+		fieldID := strconv.Itoa(field.ID)
+
+		for i, v := range []float64{40, 42} {
+			if err := qb.SetValues(ctx, models.CustomFieldObjectTypePerformer, testIDs[i], []models.CustomFieldValue{
+				{FieldID: field.ID, Value: v},
+			}); err != nil {
+				t.Errorf("Error setting custom field values: %s", err.Error())
+				return nil
+			}
+		}
+
+		filterIDs := func(c models.CustomFieldCriterionInput) []int {
+			performers := queryPerformers(ctx, t, &models.PerformerFilterType{
+				CustomFields: []*models.CustomFieldCriterionInput{&c},
+			}, nil)
+
+			var ids []int
+			for _, p := range performers {
+				ids = append(ids, p.ID)
+			}
+			return ids
+		}
+
+		assert.Equal(t, []int{testIDs[1]}, filterIDs(models.CustomFieldCriterionInput{
+			FieldID:  fieldID,
+			Value:    "41",
+			Modifier: models.CriterionModifierGreaterThan,
+		}))
+		assert.ElementsMatch(t, testIDs[:2], filterIDs(models.CustomFieldCriterionInput{
+			FieldID:  fieldID,
+			Value:    40.0,
+			Value2:   42.0,
+			Modifier: models.CriterionModifierBetween,
+		}))
+
+		// performers without a value match negated modifiers
+		notEquals := filterIDs(models.CustomFieldCriterionInput{
+			FieldID:  fieldID,
+			Value:    40.0,
+			Modifier: models.CriterionModifierNotEquals,
+		})
+		assert.NotContains(t, notEquals, testIDs[0])
+		assert.Contains(t, notEquals, testIDs[1])
+		assert.Contains(t, notEquals, testIDs[2])
+
+		isNull := filterIDs(models.CustomFieldCriterionInput{
+			FieldID:  fieldID,
+			Modifier: models.CriterionModifierIsNull,
+		})
+		assert.NotContains(t, isNull, testIDs[0])
+		assert.Contains(t, isNull, testIDs[2])
+
+		_, _, err := db.Performer.Query(ctx, &models.PerformerFilterType{
+			CustomFields: []*models.CustomFieldCriterionInput{{
+				FieldID:  fieldID,
+				Value:    "large",
+				Modifier: models.CriterionModifierEquals,
+			}},
+		}, nil)
+		assert.ErrorIs(t, err, models.ErrInvalidCustomFieldValue)
+
+		sort := "custom_field_" + fieldID
+		direction := models.SortDirectionEnumDesc
+		performers := queryPerformers(ctx, t, nil, &models.FindFilterType{
+			Sort:      &sort,
+			Direction: &direction,
+		})
+		if assert.True(t, len(performers) > 2) {
+			assert.Equal(t, testIDs[1], performers[0].ID)
+			assert.Equal(t, testIDs[0], performers[1].ID)
+		}
+
+		return nil
+	})
+}
+
+func TestCustomFieldFilterMultiValue(t *testing.T) {
+	qb := sqlite.CustomFieldReaderWriter
+	testIDs := []int{
+		studioIDs[studioIdxWithScene],
+		studioIDs[studioIdxWithTwoScenes],
+		studioIDs[studioIdxWithImage],
+	}
+
+	withRollbackTxn(func(ctx context.Context) error {
+		region := createCustomField(ctx, t, models.CustomFieldObjectTypeStudio, "Region", models.CustomFieldTypeEnum, []string{"EU", "US", "Asia"})
+		founded := createCustomField(ctx, t, models.CustomFieldObjectTypeStudio, "Founded", models.CustomFieldTypeDate, nil)
+		notes := createCustomField(ctx, t, models.CustomFieldObjectTypeStudio, "Notes", models.CustomFieldTypeText, nil)
+
+		values := []struct {
+			region  string
+			founded string
+			notes   string
+		}{
+			{"EU", "2001-05-01", "studio in berlin"},
+			{"US", "2010-01-01", "studio in la"},
+			{"Asia", "2020-07-15", "studio in tokyo"},
+		}
+
+		for i, v := range values {
+			if err := qb.SetValues(ctx, models.CustomFieldObjectTypeStudio, testIDs[i], []models.CustomFieldValue{
+				{FieldID: region.ID, Value: v.region},
+				{FieldID: founded.ID, Value: v.founded},
+				{FieldID: notes.ID, Value: v.notes},
+			}); err != nil {
+				t.Errorf("Error setting custom field values: %s", err.Error())
+				return nil
+			}
+		}
+
+		query := func(c models.CustomFieldCriterionInput) ([]int, error) {
+			studios, _, err := sqlite.StudioReaderWriter.Query(ctx, &models.StudioFilterType{
+				CustomFields: []*models.CustomFieldCriterionInput{&c},
+			}, nil)
+			if err != nil {
+				return nil, err
+			}
+
+			var ids []int
+			for _, s := range studios {
+				ids = append(ids, s.ID)
+			}
+			return ids, nil
+		}
+		filterIDs := func(c models.CustomFieldCriterionInput) []int {
+			ids, err := query(c)
+			if err != nil {
+				t.Errorf("Error querying studios: %s", err.Error())
+			}
+			return ids
+		}
+
+		regionID := strconv.Itoa(region.ID)
+		assert.ElementsMatch(t, testIDs[:2], filterIDs(models.CustomFieldCriterionInput{
+			FieldID:  regionID,
+			Value:    []interface{}{"EU", "US"},
+			Modifier: models.CriterionModifierIncludes,
+		}))
+
+		excluded := filterIDs(models.CustomFieldCriterionInput{
+			FieldID:  regionID,
+			Value:    []interface{}{"EU", "US"},
+			Modifier: models.CriterionModifierExcludes,
+		})
+		assert.NotContains(t, excluded, testIDs[0])
+		assert.NotContains(t, excluded, testIDs[1])
+		assert.Contains(t, excluded, testIDs[2])
+
+		// enum values must be one of the options
+		_, err := query(models.CustomFieldCriterionInput{
+			FieldID:  regionID,
+			Value:    []interface{}{"EU", "E"},
+			Modifier: models.CriterionModifierIncludes,
+		})
+		assert.ErrorIs(t, err, models.ErrInvalidCustomFieldValue)
+
+		// text values match substrings
+		assert.ElementsMatch(t, []int{testIDs[0], testIDs[2]}, filterIDs(models.CustomFieldCriterionInput{
+			FieldID:  strconv.Itoa(notes.ID),
+			Value:    []interface{}{"berlin", "tokyo"},
+			Modifier: models.CriterionModifierIncludes,
+		}))
+
+		// range bounds may be reversed
+		assert.ElementsMatch(t, testIDs[1:], filterIDs(models.CustomFieldCriterionInput{
+			FieldID:  strconv.Itoa(founded.ID),
+			Value:    "2021-01-01",
+			Value2:   "2005-01-01",
+			Modifier: models.CriterionModifierBetween,
+		}))
+
+		// ranges are not supported by enum fields
+		_, err = query(models.CustomFieldCriterionInput{
+			FieldID:  regionID,
+			Value:    "EU",
+			Modifier: models.CriterionModifierGreaterThan,
+		})
+		assert.ErrorIs(t, err, models.ErrInvalidCustomFieldValue)
+
+		return nil
+	})
+}
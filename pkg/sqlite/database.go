@@ -21,7 +21,7 @@ import (
 	"github.com/stashapp/stash/pkg/logger"
 )
 
-var appSchemaVersion uint = 43
+var appSchemaVersion uint = 80
 
 //go:embed migrations/*.sql
 var migrationsBox embed.FS
@@ -462,6 +462,7 @@ func registerCustomDriver() {
 					"regexp":            regexFn,
 					"durationToTinyInt": durationToTinyIntFn,
 					"basename":          basenameFn,
+					"searchRank":        searchRankFn,
 				}
 
 				for name, fn := range funcs {
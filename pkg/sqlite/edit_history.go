@@ -0,0 +1,116 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const editHistoryTable = "edit_history"
+
+type editHistoryQueryBuilder struct {
+	repository
+}
+
+var EditHistoryReaderWriter = &editHistoryQueryBuilder{
+	repository{
+		tableName: editHistoryTable,
+		idColumn:  idColumn,
+	},
+}
+
+func (qb *editHistoryQueryBuilder) Create(ctx context.Context, newObject models.EditHistoryEntry) (*models.EditHistoryEntry, error) {
+	var ret models.EditHistoryEntry
+	if err := qb.insertObject(ctx, newObject, &ret); err != nil {
+		return nil, err
+	}
+
+	return &ret, nil
+}
+
+func (qb *editHistoryQueryBuilder) Find(ctx context.Context, id int) (*models.EditHistoryEntry, error) {
+	var ret models.EditHistoryEntry
+	if err := qb.getByID(ctx, id, &ret); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &ret, nil
+}
+
+func (qb *editHistoryQueryBuilder) FindByObject(ctx context.Context, objectType models.EditHistoryObjectType, objectID int) ([]*models.EditHistoryEntry, error) {
+	query := selectAll(editHistoryTable) + " WHERE object_type = ? AND object_id = ? ORDER BY created_at DESC, id DESC"
+
+	var ret models.EditHistoryEntries
+	if err := qb.query(ctx, query, []interface{}{objectType, objectID}, &ret); err != nil {
+		return nil, err
+	}
+
+	return []*models.EditHistoryEntry(ret), nil
+}
+
+func (qb *editHistoryQueryBuilder) Query(ctx context.Context, filter *models.EditHistoryFilterType, findFilter *models.FindFilterType) ([]*models.EditHistoryEntry, int, error) {
+	if findFilter == nil {
+		findFilter = &models.FindFilterType{}
+	}
+
+	var whereClauses []string
+	var args []interface{}
+
+	if filter != nil {
+		if filter.ObjectType != nil {
+			whereClauses = append(whereClauses, "object_type = ?")
+			args = append(args, *filter.ObjectType)
+		}
+		if filter.ObjectID != nil {
+			whereClauses = append(whereClauses, "object_id = ?")
+			args = append(args, *filter.ObjectID)
+		}
+		if filter.Action != nil {
+			whereClauses = append(whereClauses, "action = ?")
+			args = append(args, *filter.Action)
+		}
+		if filter.Credential != nil {
+			whereClauses = append(whereClauses, "credential = ?")
+			args = append(args, *filter.Credential)
+		}
+		if filter.Since != nil {
+			whereClauses = append(whereClauses, "created_at >= ?")
+			args = append(args, *filter.Since)
+		}
+		if filter.Until != nil {
+			whereClauses = append(whereClauses, "created_at < ?")
+			args = append(args, *filter.Until)
+		}
+	}
+
+	body := selectAll(editHistoryTable)
+	if len(whereClauses) > 0 {
+		body += " WHERE " + strings.Join(whereClauses, " AND ")
+	}
+
+	count, err := qb.runCountQuery(ctx, qb.buildCountQuery(body), args)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	direction := "DESC"
+	if findFilter.Direction != nil && *findFilter.Direction == models.SortDirectionEnumAsc {
+		direction = "ASC"
+	}
+
+	query := body + fmt.Sprintf(" ORDER BY created_at %[1]s, id %[1]s", direction) + getPagination(findFilter)
+
+	var ret models.EditHistoryEntries
+	if err := qb.query(ctx, query, args, &ret); err != nil {
+		return nil, 0, err
+	}
+
+	return []*models.EditHistoryEntry(ret), count, nil
+}
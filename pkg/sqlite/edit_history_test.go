@@ -0,0 +1,77 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sqlite"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEditHistory(t *testing.T) {
+	qb := sqlite.EditHistoryReaderWriter
+	now := time.Now()
+	changes := models.FieldChanges{
+		{Field: "title", OldValue: json.RawMessage(`"a"`), NewValue: json.RawMessage(`"b"`)},
+	}
+
+	entries := []models.EditHistoryEntry{
+		{CreatedAt: now.Add(-time.Hour), ObjectType: models.EditHistoryObjectTypeScene, ObjectID: 1, Action: "sceneUpdate", AuthMethod: "session", Credential: "admin", Changes: changes},
+		{CreatedAt: now, ObjectType: models.EditHistoryObjectTypeScene, ObjectID: 1, Action: "bulkSceneUpdate", AuthMethod: "api_key", Credential: "abcd1234", Changes: changes},
+		{CreatedAt: now, ObjectType: models.EditHistoryObjectTypeTag, ObjectID: 1, Action: "tagUpdate", AuthMethod: "session", Credential: "admin", Changes: changes},
+	}
+
+	withRollbackTxn(func(ctx context.Context) error {
+		var created []*models.EditHistoryEntry
+		for _, e := range entries {
+			c, err := qb.Create(ctx, e)
+			if err != nil {
+				t.Errorf("Error creating edit history entry: %s", err.Error())
+				return nil
+			}
+			created = append(created, c)
+		}
+
+		found, err := qb.Find(ctx, created[0].ID)
+		if err != nil {
+			t.Errorf("Error finding edit history entry: %s", err.Error())
+			return nil
+		}
+		assert.Equal(t, "sceneUpdate", found.Action)
+		assert.Equal(t, changes, found.Changes)
+
+		got, err := qb.FindByObject(ctx, models.EditHistoryObjectTypeScene, 1)
+		if err != nil {
+			t.Errorf("Error finding edit history: %s", err.Error())
+			return nil
+		}
+		if assert.Len(t, got, 2) {
+			assert.Equal(t, created[1].ID, got[0].ID)
+			assert.Equal(t, created[0].ID, got[1].ID)
+		}
+
+		objectType := models.EditHistoryObjectTypeTag
+		objectID := strconv.Itoa(1)
+		got, count, err := qb.Query(ctx, &models.EditHistoryFilterType{
+			ObjectType: &objectType,
+			ObjectID:   &objectID,
+		}, nil)
+		if err != nil {
+			t.Errorf("Error querying edit history: %s", err.Error())
+			return nil
+		}
+		assert.Equal(t, 1, count)
+		if assert.Len(t, got, 1) {
+			assert.Equal(t, created[2].ID, got[0].ID)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,111 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sqlite"
+	"github.com/stretchr/testify/assert"
+)
+
+type externalIDReaderWriter interface {
+	models.ExternalIDLoader
+	models.ExternalIDUpdater
+}
+
+func TestExternalIDs(t *testing.T) {
+	const (
+		source      = "TestExternalIDs"
+		otherSource = "TestExternalIDs other"
+		externalID  = "externalID"
+	)
+
+	findScenes := func(ctx context.Context, source, id string) ([]int, error) {
+		found, err := db.Scene.FindByExternalID(ctx, source, id)
+		var ret []int
+		for _, s := range found {
+			ret = append(ret, s.ID)
+		}
+		return ret, err
+	}
+	findPerformers := func(ctx context.Context, source, id string) ([]int, error) {
+		found, err := db.Performer.FindByExternalID(ctx, source, id)
+		var ret []int
+		for _, p := range found {
+			ret = append(ret, p.ID)
+		}
+		return ret, err
+	}
+	findStudios := func(ctx context.Context, source, id string) ([]int, error) {
+		found, err := sqlite.StudioReaderWriter.FindByExternalID(ctx, source, id)
+		var ret []int
+		for _, s := range found {
+			ret = append(ret, s.ID)
+		}
+		return ret, err
+	}
+
+	tests := []struct {
+		name string
+		qb   externalIDReaderWriter
+		id   int
+		find func(ctx context.Context, source, id string) ([]int, error)
+	}{
+		{"scene", db.Scene, sceneIDs[sceneIdxWithStudio], findScenes},
+		{"performer", db.Performer, performerIDs[performerIdxWithScene], findPerformers},
+		{"studio", sqlite.StudioReaderWriter, studioIDs[studioIdxWithScene], findStudios},
+	}
+
+	for _, tt := range tests {
+		runWithRollbackTxn(t, tt.name, func(t *testing.T, ctx context.Context) {
+			assert := assert.New(t)
+
+			externalIDs := []models.ExternalID{
+				{Source: source, ID: externalID},
+				{Source: otherSource, ID: externalID},
+			}
+			if err := tt.qb.UpdateExternalIDs(ctx, tt.id, externalIDs); err != nil {
+				t.Errorf("UpdateExternalIDs() error = %v", err)
+				return
+			}
+
+			got, err := tt.qb.GetExternalIDs(ctx, tt.id)
+			if err != nil {
+				t.Errorf("GetExternalIDs() error = %v", err)
+				return
+			}
+			assert.Equal(externalIDs, got)
+
+			found, err := tt.find(ctx, source, externalID)
+			if err != nil {
+				t.Errorf("FindByExternalID() error = %v", err)
+				return
+			}
+			assert.Equal([]int{tt.id}, found)
+
+			found, err = tt.find(ctx, source, "missing")
+			if err != nil {
+				t.Errorf("FindByExternalID() error = %v", err)
+				return
+			}
+			assert.Len(found, 0)
+
+			// replace the existing identifiers
+			if err := tt.qb.UpdateExternalIDs(ctx, tt.id, nil); err != nil {
+				t.Errorf("UpdateExternalIDs() error = %v", err)
+				return
+			}
+
+			got, err = tt.qb.GetExternalIDs(ctx, tt.id)
+			if err != nil {
+				t.Errorf("GetExternalIDs() error = %v", err)
+				return
+			}
+			assert.Len(got, 0)
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/doug-martin/goqu/v9"
 	"github.com/doug-martin/goqu/v9/exp"
@@ -26,6 +27,20 @@ const (
 	captionCodeColumn     = "language_code"
 	captionFilenameColumn = "filename"
 	captionTypeColumn     = "caption_type"
+
+	videoFunscriptAxesTable = "video_funscript_axes"
+	funscriptAxisColumn     = "axis"
+	funscriptSpeedColumn    = "interactive_speed"
+
+	videoFunscriptStatsTable = "video_funscript_stats"
+
+	videoChaptersTable = "video_chapters"
+	chapterIndexColumn = "chapter_index"
+	chapterTitleColumn = "title"
+	chapterStartColumn = "start"
+
+	videoSubtitleTracksTable = "video_subtitle_tracks"
+	subtitleTrackIndexColumn = "track_index"
 )
 
 type basicFileRow struct {
@@ -372,6 +387,18 @@ func (qb *FileStore) createVideoFile(ctx context.Context, id file.ID, f file.Vid
 		return err
 	}
 
+	if f.Chapters != nil {
+		if err := qb.UpdateChapters(ctx, id, f.Chapters); err != nil {
+			return err
+		}
+	}
+
+	if f.SubtitleTracks != nil {
+		if err := qb.UpdateSubtitleTracks(ctx, id, f.SubtitleTracks); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -392,6 +419,19 @@ func (qb *FileStore) updateOrCreateVideoFile(ctx context.Context, id file.ID, f
 		return err
 	}
 
+	// chapters and subtitle tracks are only set if the file has been probed
+	if f.Chapters != nil {
+		if err := qb.UpdateChapters(ctx, id, f.Chapters); err != nil {
+			return err
+		}
+	}
+
+	if f.SubtitleTracks != nil {
+		if err := qb.UpdateSubtitleTracks(ctx, id, f.SubtitleTracks); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -609,6 +649,28 @@ func (qb *FileStore) FindByPath(ctx context.Context, p string) (file.File, error
 	return ret, nil
 }
 
+// FindByPathCaseInsensitive returns the first file that matches the given
+// path, ignoring the case of ASCII characters.
+func (qb *FileStore) FindByPathCaseInsensitive(ctx context.Context, p string) (file.File, error) {
+	basename := filepath.Base(p)
+	dirName := filepath.Dir(p)
+
+	table := qb.table()
+	folderTable := folderTableMgr.table
+
+	q := qb.selectDataset().Prepared(true).Where(
+		goqu.L("? COLLATE NOCASE", folderTable.Col("path")).Eq(dirName),
+		goqu.L("? COLLATE NOCASE", table.Col("basename")).Eq(basename),
+	)
+
+	ret, err := qb.get(ctx, q)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("getting file by case-insensitive path %s: %w", p, err)
+	}
+
+	return ret, nil
+}
+
 func (qb *FileStore) allInPaths(q *goqu.SelectDataset, p []string) *goqu.SelectDataset {
 	folderTable := folderTableMgr.table
 
@@ -661,6 +723,58 @@ func (qb *FileStore) CountAllInPaths(ctx context.Context, p []string) (int, erro
 	return count(ctx, q)
 }
 
+func (qb *FileStore) PathStats(ctx context.Context, p string, since time.Time) (*file.PathStats, error) {
+	table := qb.table()
+	folderTable := folderTableMgr.table
+	videoFileTable := videoFileTableMgr.table
+
+	inPath := func(cols ...interface{}) *goqu.SelectDataset {
+		q := dialect.From(table).Prepared(true).InnerJoin(
+			folderTable,
+			goqu.On(table.Col("parent_folder_id").Eq(folderTable.Col(idColumn))),
+		).Select(cols...)
+		return qb.allInPaths(q, []string{p})
+	}
+
+	sizeCol := goqu.COALESCE(goqu.SUM(table.Col("size")), 0)
+	sceneFiles := func(cols ...interface{}) *goqu.SelectDataset {
+		return inPath(cols...).InnerJoin(
+			scenesFilesJoinTable,
+			goqu.On(scenesFilesJoinTable.Col(fileIDColumn).Eq(table.Col(idColumn))),
+		)
+	}
+	imageFiles := func(cols ...interface{}) *goqu.SelectDataset {
+		return inPath(cols...).InnerJoin(
+			imagesFilesJoinTable,
+			goqu.On(imagesFilesJoinTable.Col(fileIDColumn).Eq(table.Col(idColumn))),
+		)
+	}
+
+	ret := &file.PathStats{}
+	queries := []struct {
+		q   *goqu.SelectDataset
+		out interface{}
+	}{
+		{sceneFiles(goqu.COUNT(goqu.DISTINCT(scenesFilesJoinTable.Col(sceneIDColumn)))), &ret.SceneCount},
+		{sceneFiles(sizeCol), &ret.ScenesSize},
+		{sceneFiles(goqu.COALESCE(goqu.SUM(videoFileTable.Col("duration")), 0)).InnerJoin(
+			videoFileTable,
+			goqu.On(videoFileTable.Col(fileIDColumn).Eq(table.Col(idColumn))),
+		), &ret.ScenesDuration},
+		{imageFiles(goqu.COUNT(goqu.DISTINCT(imagesFilesJoinTable.Col(imageIDColumn)))), &ret.ImageCount},
+		{imageFiles(sizeCol), &ret.ImagesSize},
+		{inPath(sizeCol).Where(table.Col("created_at").Gte(since)), &ret.RecentSize},
+	}
+
+	for _, q := range queries {
+		if err := querySimple(ctx, q.q, q.out); err != nil {
+			return nil, fmt.Errorf("getting stats of path %s: %w", p, err)
+		}
+	}
+
+	return ret, nil
+}
+
 func (qb *FileStore) findBySubquery(ctx context.Context, sq *goqu.SelectDataset) ([]file.File, error) {
 	table := qb.table()
 
@@ -890,3 +1004,116 @@ func (qb *FileStore) GetCaptions(ctx context.Context, fileID file.ID) ([]*models
 func (qb *FileStore) UpdateCaptions(ctx context.Context, fileID file.ID, captions []*models.VideoCaption) error {
 	return qb.captionRepository().replace(ctx, fileID, captions)
 }
+
+func (qb *FileStore) chapterRepository() *chapterRepository {
+	return &chapterRepository{
+		repository: repository{
+			tx:        qb.tx,
+			tableName: videoChaptersTable,
+			idColumn:  fileIDColumn,
+		},
+	}
+}
+
+// GetChapters returns the chapters of the video file, ordered by index.
+func (qb *FileStore) GetChapters(ctx context.Context, fileID file.ID) ([]file.VideoChapter, error) {
+	return qb.chapterRepository().get(ctx, fileID)
+}
+
+func (qb *FileStore) UpdateChapters(ctx context.Context, fileID file.ID, chapters []file.VideoChapter) error {
+	return qb.chapterRepository().replace(ctx, fileID, chapters)
+}
+
+func (qb *FileStore) subtitleTrackRepository() *subtitleTrackRepository {
+	return &subtitleTrackRepository{
+		repository: repository{
+			tx:        qb.tx,
+			tableName: videoSubtitleTracksTable,
+			idColumn:  fileIDColumn,
+		},
+	}
+}
+
+// GetSubtitleTracks returns the embedded subtitle tracks of the video file,
+// in stream order.
+func (qb *FileStore) GetSubtitleTracks(ctx context.Context, fileID file.ID) ([]file.VideoSubtitleTrack, error) {
+	return qb.subtitleTrackRepository().get(ctx, fileID)
+}
+
+func (qb *FileStore) UpdateSubtitleTracks(ctx context.Context, fileID file.ID, tracks []file.VideoSubtitleTrack) error {
+	return qb.subtitleTrackRepository().replace(ctx, fileID, tracks)
+}
+
+func (qb *FileStore) funscriptAxisRepository() *funscriptAxisRepository {
+	return &funscriptAxisRepository{
+		repository: repository{
+			tx:        qb.tx,
+			tableName: videoFunscriptAxesTable,
+			idColumn:  fileIDColumn,
+		},
+	}
+}
+
+func (qb *FileStore) GetFunscriptAxes(ctx context.Context, fileID file.ID) ([]*models.FunscriptAxis, error) {
+	return qb.funscriptAxisRepository().get(ctx, fileID)
+}
+
+func (qb *FileStore) UpdateFunscriptAxes(ctx context.Context, fileID file.ID, axes []*models.FunscriptAxis) error {
+	return qb.funscriptAxisRepository().replace(ctx, fileID, axes)
+}
+
+// GetFunscriptStats returns the funscript statistics of the file, or nil if
+// they have not been generated.
+func (qb *FileStore) GetFunscriptStats(ctx context.Context, fileID file.ID) (*models.FunscriptStats, error) {
+	q := dialect.From(videoFunscriptStatsTable).Select(
+		"speed_p5",
+		"speed_p50",
+		"speed_p95",
+		"speed_max",
+		"action_count",
+		"average_stroke_length",
+		"coverage",
+	).Where(goqu.C(fileIDColumn).Eq(fileID))
+
+	var ret *models.FunscriptStats
+	if err := queryFunc(ctx, q, true, func(rows *sqlx.Rows) error {
+		var stats models.FunscriptStats
+		if err := rows.StructScan(&stats); err != nil {
+			return err
+		}
+
+		ret = &stats
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// UpdateFunscriptStats replaces the funscript statistics of the file. Nil
+// stats removes the existing statistics.
+func (qb *FileStore) UpdateFunscriptStats(ctx context.Context, fileID file.ID, stats *models.FunscriptStats) error {
+	table := goqu.T(videoFunscriptStatsTable)
+	if _, err := exec(ctx, dialect.Delete(table).Where(table.Col(fileIDColumn).Eq(fileID))); err != nil {
+		return err
+	}
+
+	if stats == nil {
+		return nil
+	}
+
+	q := dialect.Insert(table).Rows(goqu.Record{
+		fileIDColumn:            fileID,
+		"speed_p5":              stats.SpeedP5,
+		"speed_p50":             stats.SpeedP50,
+		"speed_p95":             stats.SpeedP95,
+		"speed_max":             stats.SpeedMax,
+		"action_count":          stats.ActionCount,
+		"average_stroke_length": stats.AverageStrokeLength,
+		"coverage":              stats.Coverage,
+	})
+
+	_, err := exec(ctx, q)
+	return err
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/models"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -662,3 +663,218 @@ func TestFileStore_IsPrimary(t *testing.T) {
 		})
 	}
 }
+
+func TestFileStore_FunscriptStats(t *testing.T) {
+	qb := db.File
+	fileID := sceneFileIDs[sceneIdx1WithPerformer]
+
+	runWithRollbackTxn(t, "funscript stats", func(t *testing.T, ctx context.Context) {
+		assert := assert.New(t)
+
+		got, err := qb.GetFunscriptStats(ctx, fileID)
+		if err != nil {
+			t.Errorf("FileStore.GetFunscriptStats() error = %v", err)
+			return
+		}
+		assert.Nil(got)
+
+		stats := &models.FunscriptStats{
+			SpeedP5:             10,
+			SpeedP50:            200,
+			SpeedP95:            400,
+			SpeedMax:            500,
+			ActionCount:         1000,
+			AverageStrokeLength: 62.5,
+			Coverage:            0.75,
+		}
+
+		// updating twice replaces the existing stats
+		for i := 0; i < 2; i++ {
+			if err := qb.UpdateFunscriptStats(ctx, fileID, stats); err != nil {
+				t.Errorf("FileStore.UpdateFunscriptStats() error = %v", err)
+				return
+			}
+		}
+
+		got, err = qb.GetFunscriptStats(ctx, fileID)
+		if err != nil {
+			t.Errorf("FileStore.GetFunscriptStats() error = %v", err)
+			return
+		}
+		assert.Equal(stats, got)
+
+		if err := qb.UpdateFunscriptStats(ctx, fileID, nil); err != nil {
+			t.Errorf("FileStore.UpdateFunscriptStats() error = %v", err)
+			return
+		}
+
+		got, err = qb.GetFunscriptStats(ctx, fileID)
+		if err != nil {
+			t.Errorf("FileStore.GetFunscriptStats() error = %v", err)
+			return
+		}
+		assert.Nil(got)
+	})
+}
+
+func TestFileStore_Chapters(t *testing.T) {
+	qb := db.File
+	fileID := sceneFileIDs[sceneIdx1WithPerformer]
+
+	runWithRollbackTxn(t, "chapters", func(t *testing.T, ctx context.Context) {
+		assert := assert.New(t)
+
+		chapters := []file.VideoChapter{
+			{Title: "Intro", Start: 0},
+			{Title: "Part one", Start: 95.5},
+		}
+
+		if err := qb.UpdateChapters(ctx, fileID, chapters); err != nil {
+			t.Errorf("FileStore.UpdateChapters() error = %v", err)
+			return
+		}
+
+		got, err := qb.GetChapters(ctx, fileID)
+		if err != nil {
+			t.Errorf("FileStore.GetChapters() error = %v", err)
+			return
+		}
+		assert.Equal(chapters, got)
+
+		found, err := qb.Find(ctx, fileID)
+		if err != nil {
+			t.Errorf("FileStore.Find() error = %v", err)
+			return
+		}
+		vf := found[0].(*file.VideoFile)
+
+		// files which have not been probed keep their chapters
+		if err := qb.Update(ctx, vf); err != nil {
+			t.Errorf("FileStore.Update() error = %v", err)
+			return
+		}
+
+		got, err = qb.GetChapters(ctx, fileID)
+		if err != nil {
+			t.Errorf("FileStore.GetChapters() error = %v", err)
+			return
+		}
+		assert.Equal(chapters, got)
+
+		vf.Chapters = []file.VideoChapter{}
+		if err := qb.Update(ctx, vf); err != nil {
+			t.Errorf("FileStore.Update() error = %v", err)
+			return
+		}
+
+		got, err = qb.GetChapters(ctx, fileID)
+		if err != nil {
+			t.Errorf("FileStore.GetChapters() error = %v", err)
+			return
+		}
+		assert.Empty(got)
+	})
+}
+
+func TestFileStore_SubtitleTracks(t *testing.T) {
+	qb := db.File
+	fileID := sceneFileIDs[sceneIdx1WithPerformer]
+
+	runWithRollbackTxn(t, "subtitle tracks", func(t *testing.T, ctx context.Context) {
+		assert := assert.New(t)
+
+		tracks := []file.VideoSubtitleTrack{
+			{StreamIndex: 0, Language: "eng", Title: "English", Codec: "subrip", Default: true},
+			{StreamIndex: 2, Language: "ger", Codec: "ass", Forced: true},
+		}
+
+		if err := qb.UpdateSubtitleTracks(ctx, fileID, tracks); err != nil {
+			t.Errorf("FileStore.UpdateSubtitleTracks() error = %v", err)
+			return
+		}
+
+		got, err := qb.GetSubtitleTracks(ctx, fileID)
+		if err != nil {
+			t.Errorf("FileStore.GetSubtitleTracks() error = %v", err)
+			return
+		}
+		assert.Equal(tracks, got)
+
+		found, err := qb.Find(ctx, fileID)
+		if err != nil {
+			t.Errorf("FileStore.Find() error = %v", err)
+			return
+		}
+		vf := found[0].(*file.VideoFile)
+
+		// files which have not been probed keep their subtitle tracks
+		if err := qb.Update(ctx, vf); err != nil {
+			t.Errorf("FileStore.Update() error = %v", err)
+			return
+		}
+
+		got, err = qb.GetSubtitleTracks(ctx, fileID)
+		if err != nil {
+			t.Errorf("FileStore.GetSubtitleTracks() error = %v", err)
+			return
+		}
+		assert.Equal(tracks, got)
+
+		vf.SubtitleTracks = []file.VideoSubtitleTrack{}
+		if err := qb.Update(ctx, vf); err != nil {
+			t.Errorf("FileStore.Update() error = %v", err)
+			return
+		}
+
+		got, err = qb.GetSubtitleTracks(ctx, fileID)
+		if err != nil {
+			t.Errorf("FileStore.GetSubtitleTracks() error = %v", err)
+			return
+		}
+		assert.Empty(got)
+	})
+}
+
+func TestFileStore_PathStats(t *testing.T) {
+	qb := db.File
+
+	if err := withTxn(func(ctx context.Context) error {
+		assert := assert.New(t)
+
+		scenePath := folderPaths[folderIdxWithSceneFiles]
+
+		got, err := qb.PathStats(ctx, scenePath, time.Time{})
+		if err != nil {
+			t.Errorf("FileStore.PathStats() error = %v", err)
+			return nil
+		}
+
+		// all scene files are in the scene folder
+		size, _ := db.Scene.Size(ctx)
+		duration, _ := db.Scene.Duration(ctx)
+		assert.Greater(got.SceneCount, 0)
+		assert.Equal(size, got.ScenesSize)
+		assert.InDelta(duration, got.ScenesDuration, 0.001)
+		assert.Zero(got.ImageCount)
+		assert.Zero(got.ImagesSize)
+		assert.GreaterOrEqual(got.RecentSize, got.ScenesSize)
+
+		got, err = qb.PathStats(ctx, scenePath, time.Now().Add(time.Hour))
+		if err != nil {
+			t.Errorf("FileStore.PathStats() error = %v", err)
+			return nil
+		}
+		assert.Zero(got.RecentSize)
+
+		got, err = qb.PathStats(ctx, "missing", time.Time{})
+		if err != nil {
+			t.Errorf("FileStore.PathStats() error = %v", err)
+			return nil
+		}
+		assert.Equal(&file.PathStats{}, got)
+
+		return nil
+	}); err != nil {
+		t.Error(err.Error())
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"path/filepath"
 	"regexp"
 	"strconv"
@@ -571,6 +572,48 @@ func dateCriterionHandler(c *models.DateCriterionInput, column string) criterion
 	}
 }
 
+// kilometres per degree of latitude, and of longitude at the equator
+const kmPerDegree = 6371 * math.Pi / 180
+
+// proximityCriterionHandler matches rows of table with latitude and longitude
+// within the criterion distance. Distance is approximated with an
+// equirectangular projection, which is accurate enough for the distances of
+// interest and does not need trigonometric functions in SQL.
+func proximityCriterionHandler(c *models.ProximityCriterionInput, table string) criterionHandlerFunc {
+	return func(ctx context.Context, f *filterBuilder) {
+		if c == nil {
+			return
+		}
+
+		if c.Latitude < -90 || c.Latitude > 90 || c.Longitude < -180 || c.Longitude > 180 {
+			f.setError(fmt.Errorf("invalid coordinates: %v, %v", c.Latitude, c.Longitude))
+			return
+		}
+		if c.Distance < 0 {
+			f.setError(fmt.Errorf("invalid distance: %v", c.Distance))
+			return
+		}
+
+		lat := table + ".latitude"
+		lon := table + ".longitude"
+
+		// longitude difference wraps around the antimeridian
+		dLat := fmt.Sprintf("(%s - ?)", lat)
+		dLon := fmt.Sprintf("(MIN(ABS(%[1]s - ?), 360 - ABS(%[1]s - ?)) * ?)", lon)
+
+		cosLat := math.Cos(c.Latitude * math.Pi / 180)
+		maxDegrees := c.Distance / kmPerDegree
+
+		f.addWhere(
+			fmt.Sprintf("(%s IS NOT NULL AND %s IS NOT NULL AND %s * %[3]s + %[4]s * %[4]s <= ?)", lat, lon, dLat, dLon),
+			c.Latitude, c.Latitude,
+			c.Longitude, c.Longitude, cosLat,
+			c.Longitude, c.Longitude, cosLat,
+			maxDegrees*maxDegrees,
+		)
+	}
+}
+
 func timestampCriterionHandler(c *models.TimestampCriterionInput, column string) criterionHandlerFunc {
 	return func(ctx context.Context, f *filterBuilder) {
 		if c != nil {
@@ -233,6 +233,19 @@ func (qb *FolderStore) FindByPath(ctx context.Context, p string) (*file.Folder,
 	return ret, nil
 }
 
+// FindByPathCaseInsensitive returns the folder that matches the given path,
+// ignoring the case of ASCII characters.
+func (qb *FolderStore) FindByPathCaseInsensitive(ctx context.Context, p string) (*file.Folder, error) {
+	q := qb.selectDataset().Prepared(true).Where(goqu.L("? COLLATE NOCASE", qb.table().Col("path")).Eq(p))
+
+	ret, err := qb.get(ctx, q)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("getting folder by case-insensitive path %s: %w", p, err)
+	}
+
+	return ret, nil
+}
+
 func (qb *FolderStore) FindByParentFolderID(ctx context.Context, parentFolderID file.FolderID) ([]*file.Folder, error) {
 	q := qb.selectDataset().Where(qb.table().Col("parent_folder_id").Eq(int(parentFolderID)))
 
@@ -6,6 +6,7 @@ package sqlite_test
 import (
 	"context"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -239,3 +240,50 @@ func Test_FolderStore_FindByPath(t *testing.T) {
 		})
 	}
 }
+
+func Test_FolderStore_FindByPathCaseInsensitive(t *testing.T) {
+	getPath := func(index int) string {
+		return folderPaths[index]
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		want    *file.Folder
+		wantErr bool
+	}{
+		{
+			"same case",
+			getPath(folderIdxWithFiles),
+			makeFolderWithID(folderIdxWithFiles),
+			false,
+		},
+		{
+			"different case",
+			strings.ToUpper(getPath(folderIdxWithFiles)),
+			makeFolderWithID(folderIdxWithFiles),
+			false,
+		},
+		{
+			"invalid",
+			"invalid path",
+			nil,
+			false,
+		},
+	}
+
+	qb := db.Folder
+
+	for _, tt := range tests {
+		runWithRollbackTxn(t, tt.name, func(t *testing.T, ctx context.Context) {
+			got, err := qb.FindByPathCaseInsensitive(ctx, tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("FolderStore.FindByPathCaseInsensitive() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FolderStore.FindByPathCaseInsensitive() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
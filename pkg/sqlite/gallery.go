@@ -39,6 +39,9 @@ type galleryRow struct {
 	Rating    null.Int               `db:"rating"`
 	Organized bool                   `db:"organized"`
 	StudioID  null.Int               `db:"studio_id,omitempty"`
+	Location  zero.String            `db:"location"`
+	Latitude  null.Float             `db:"latitude"`
+	Longitude null.Float             `db:"longitude"`
 	FolderID  null.Int               `db:"folder_id,omitempty"`
 	CreatedAt models.SQLiteTimestamp `db:"created_at"`
 	UpdatedAt models.SQLiteTimestamp `db:"updated_at"`
@@ -55,6 +58,9 @@ func (r *galleryRow) fromGallery(o models.Gallery) {
 	r.Rating = intFromPtr(o.Rating)
 	r.Organized = o.Organized
 	r.StudioID = intFromPtr(o.StudioID)
+	r.Location = zero.StringFrom(o.Location)
+	r.Latitude = null.FloatFromPtr(o.Latitude)
+	r.Longitude = null.FloatFromPtr(o.Longitude)
 	r.FolderID = nullIntFromFolderIDPtr(o.FolderID)
 	r.CreatedAt = models.SQLiteTimestamp{Timestamp: o.CreatedAt}
 	r.UpdatedAt = models.SQLiteTimestamp{Timestamp: o.UpdatedAt}
@@ -79,6 +85,9 @@ func (r *galleryQueryRow) resolve() *models.Gallery {
 		Rating:        nullIntPtr(r.Rating),
 		Organized:     r.Organized,
 		StudioID:      nullIntPtr(r.StudioID),
+		Location:      r.Location.String,
+		Latitude:      nullFloatPtr(r.Latitude),
+		Longitude:     nullFloatPtr(r.Longitude),
 		FolderID:      nullIntFolderIDPtr(r.FolderID),
 		PrimaryFileID: nullIntFileIDPtr(r.PrimaryFileID),
 		CreatedAt:     r.CreatedAt.Timestamp,
@@ -106,6 +115,9 @@ func (r *galleryRowRecord) fromPartial(o models.GalleryPartial) {
 	r.setNullInt("rating", o.Rating)
 	r.setBool("organized", o.Organized)
 	r.setNullInt("studio_id", o.StudioID)
+	r.setNullString("location", o.Location)
+	r.setNullFloat64("latitude", o.Latitude)
+	r.setNullFloat64("longitude", o.Longitude)
 	r.setSQLiteTimestamp("created_at", o.CreatedAt)
 	r.setSQLiteTimestamp("updated_at", o.UpdatedAt)
 }
@@ -353,6 +365,9 @@ func (qb *GalleryStore) GetManyFileIDs(ctx context.Context, ids []int) ([][]file
 
 func (qb *GalleryStore) Find(ctx context.Context, id int) (*models.Gallery, error) {
 	q := qb.selectDataset().Where(qb.tableMgr.byID(id))
+	q = hideContentWarningsDataset(ctx, q, galleryTable, galleriesTagsTable, galleryIDColumn)
+	q = quickHideDataset(ctx, q, galleryTable, galleriesTagsTable, galleryIDColumn)
+	q = restrictContentDataset(ctx, q, galleryTable, galleriesTagsTable, galleryIDColumn)
 
 	ret, err := qb.get(ctx, q)
 	if err != nil {
@@ -656,6 +671,8 @@ func (qb *GalleryStore) makeFilter(ctx context.Context, galleryFilter *models.Ga
 	// legacy rating handler
 	query.handleCriterion(ctx, rating5CriterionHandler(galleryFilter.Rating, "galleries.rating", nil))
 	query.handleCriterion(ctx, stringCriterionHandler(galleryFilter.URL, "galleries.url"))
+	query.handleCriterion(ctx, stringCriterionHandler(galleryFilter.Location, "galleries.location"))
+	query.handleCriterion(ctx, proximityCriterionHandler(galleryFilter.Coordinates, galleryTable))
 	query.handleCriterion(ctx, boolCriterionHandler(galleryFilter.Organized, "galleries.organized", nil))
 	query.handleCriterion(ctx, galleryIsMissingCriterionHandler(qb, galleryFilter.IsMissing))
 	query.handleCriterion(ctx, galleryTagsCriterionHandler(qb, galleryFilter.Tags))
@@ -737,6 +754,9 @@ func (qb *GalleryStore) makeQuery(ctx context.Context, galleryFilter *models.Gal
 	filter := qb.makeFilter(ctx, galleryFilter)
 
 	query.addFilter(filter)
+	hideContentWarnings(ctx, &query, galleryTable, galleriesTagsTable, galleryIDColumn)
+	quickHide(ctx, &query, galleryTable, galleriesTagsTable, galleryIDColumn)
+	restrictContent(ctx, &query, galleryTable, galleriesTagsTable, galleryIDColumn)
 
 	qb.setGallerySort(&query, findFilter)
 	query.sortAndPagination += getPagination(findFilter)
@@ -34,8 +34,12 @@ type imageRow struct {
 	URL       zero.String            `db:"url"`
 	Date      models.SQLiteDate      `db:"date"`
 	Organized bool                   `db:"organized"`
+	Hidden    bool                   `db:"hidden"`
 	OCounter  int                    `db:"o_counter"`
 	StudioID  null.Int               `db:"studio_id,omitempty"`
+	Location  zero.String            `db:"location"`
+	Latitude  null.Float             `db:"latitude"`
+	Longitude null.Float             `db:"longitude"`
 	CreatedAt models.SQLiteTimestamp `db:"created_at"`
 	UpdatedAt models.SQLiteTimestamp `db:"updated_at"`
 }
@@ -49,8 +53,12 @@ func (r *imageRow) fromImage(i models.Image) {
 		_ = r.Date.Scan(i.Date.Time)
 	}
 	r.Organized = i.Organized
+	r.Hidden = i.Hidden
 	r.OCounter = i.OCounter
 	r.StudioID = intFromPtr(i.StudioID)
+	r.Location = zero.StringFrom(i.Location)
+	r.Latitude = null.FloatFromPtr(i.Latitude)
+	r.Longitude = null.FloatFromPtr(i.Longitude)
 	r.CreatedAt = models.SQLiteTimestamp{Timestamp: i.CreatedAt}
 	r.UpdatedAt = models.SQLiteTimestamp{Timestamp: i.UpdatedAt}
 }
@@ -71,8 +79,12 @@ func (r *imageQueryRow) resolve() *models.Image {
 		URL:       r.URL.String,
 		Date:      r.Date.DatePtr(),
 		Organized: r.Organized,
+		Hidden:    r.Hidden,
 		OCounter:  r.OCounter,
 		StudioID:  nullIntPtr(r.StudioID),
+		Location:  r.Location.String,
+		Latitude:  nullFloatPtr(r.Latitude),
+		Longitude: nullFloatPtr(r.Longitude),
 
 		PrimaryFileID: nullIntFileIDPtr(r.PrimaryFileID),
 		Checksum:      r.PrimaryFileChecksum.String,
@@ -98,8 +110,12 @@ func (r *imageRowRecord) fromPartial(i models.ImagePartial) {
 	r.setNullString("url", i.URL)
 	r.setSQLiteDate("date", i.Date)
 	r.setBool("organized", i.Organized)
+	r.setBool("hidden", i.Hidden)
 	r.setInt("o_counter", i.OCounter)
 	r.setNullInt("studio_id", i.StudioID)
+	r.setNullString("location", i.Location)
+	r.setNullFloat64("latitude", i.Latitude)
+	r.setNullFloat64("longitude", i.Longitude)
 	r.setSQLiteTimestamp("created_at", i.CreatedAt)
 	r.setSQLiteTimestamp("updated_at", i.UpdatedAt)
 }
@@ -385,6 +401,9 @@ func (qb *ImageStore) GetManyFileIDs(ctx context.Context, ids []int) ([][]file.I
 
 func (qb *ImageStore) find(ctx context.Context, id int) (*models.Image, error) {
 	q := qb.selectDataset().Where(qb.tableMgr.byID(id))
+	q = hideContentWarningsDataset(ctx, q, imageTable, imagesTagsTable, imageIDColumn)
+	q = quickHideDataset(ctx, q, imageTable, imagesTagsTable, imageIDColumn)
+	q = restrictContentDataset(ctx, q, imageTable, imagesTagsTable, imageIDColumn)
 
 	ret, err := qb.get(ctx, q)
 	if err != nil {
@@ -648,8 +667,11 @@ func (qb *ImageStore) makeFilter(ctx context.Context, imageFilter *models.ImageF
 	query.handleCriterion(ctx, rating5CriterionHandler(imageFilter.Rating, "images.rating", nil))
 	query.handleCriterion(ctx, intCriterionHandler(imageFilter.OCounter, "images.o_counter", nil))
 	query.handleCriterion(ctx, boolCriterionHandler(imageFilter.Organized, "images.organized", nil))
+	query.handleCriterion(ctx, boolCriterionHandler(imageFilter.Hidden, "images.hidden", nil))
 	query.handleCriterion(ctx, dateCriterionHandler(imageFilter.Date, "images.date"))
 	query.handleCriterion(ctx, stringCriterionHandler(imageFilter.URL, "images.url"))
+	query.handleCriterion(ctx, stringCriterionHandler(imageFilter.Location, "images.location"))
+	query.handleCriterion(ctx, proximityCriterionHandler(imageFilter.Coordinates, imageTable))
 
 	query.handleCriterion(ctx, resolutionCriterionHandler(imageFilter.Resolution, "image_files.height", "image_files.width", qb.addImageFilesTable))
 	query.handleCriterion(ctx, imageIsMissingCriterionHandler(qb, imageFilter.IsMissing))
@@ -729,6 +751,9 @@ func (qb *ImageStore) makeQuery(ctx context.Context, imageFilter *models.ImageFi
 	filter := qb.makeFilter(ctx, imageFilter)
 
 	query.addFilter(filter)
+	hideContentWarnings(ctx, &query, imageTable, imagesTagsTable, imageIDColumn)
+	quickHide(ctx, &query, imageTable, imagesTagsTable, imageIDColumn)
+	restrictContent(ctx, &query, imageTable, imagesTagsTable, imageIDColumn)
 
 	qb.setImageSortAndPagination(&query, findFilter)
 
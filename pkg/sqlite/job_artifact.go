@@ -0,0 +1,69 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const jobArtifactTable = "job_artifacts"
+
+type jobArtifactQueryBuilder struct {
+	repository
+}
+
+var JobArtifactReaderWriter = &jobArtifactQueryBuilder{
+	repository{
+		tableName: jobArtifactTable,
+		idColumn:  idColumn,
+	},
+}
+
+func (qb *jobArtifactQueryBuilder) Create(ctx context.Context, newObject models.JobArtifact) (*models.JobArtifact, error) {
+	var ret models.JobArtifact
+	if err := qb.insertObject(ctx, newObject, &ret); err != nil {
+		return nil, err
+	}
+
+	return &ret, nil
+}
+
+func (qb *jobArtifactQueryBuilder) Destroy(ctx context.Context, id int) error {
+	return qb.destroyExisting(ctx, []int{id})
+}
+
+func (qb *jobArtifactQueryBuilder) Find(ctx context.Context, id int) (*models.JobArtifact, error) {
+	var ret models.JobArtifact
+	if err := qb.getByID(ctx, id, &ret); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &ret, nil
+}
+
+func (qb *jobArtifactQueryBuilder) All(ctx context.Context) ([]*models.JobArtifact, error) {
+	query := selectAll(jobArtifactTable) + "ORDER BY created_at DESC, id DESC"
+
+	var ret models.JobArtifacts
+	if err := qb.query(ctx, query, nil, &ret); err != nil {
+		return nil, err
+	}
+
+	return []*models.JobArtifact(ret), nil
+}
+
+func (qb *jobArtifactQueryBuilder) FindCreatedBefore(ctx context.Context, t time.Time) ([]*models.JobArtifact, error) {
+	query := selectAll(jobArtifactTable) + "WHERE created_at < ?"
+
+	var ret models.JobArtifacts
+	if err := qb.query(ctx, query, []interface{}{t}, &ret); err != nil {
+		return nil, err
+	}
+
+	return []*models.JobArtifact(ret), nil
+}
@@ -0,0 +1,94 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sqlite"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobArtifact(t *testing.T) {
+	qb := sqlite.JobArtifactReaderWriter
+	now := time.Now()
+
+	withRollbackTxn(func(ctx context.Context) error {
+		old, err := qb.Create(ctx, models.JobArtifact{
+			JobID:          1,
+			JobDescription: "Exporting...",
+			Name:           "export.zip",
+			Path:           "1/export.zip",
+			ContentType:    "application/zip",
+			Size:           100,
+			CreatedAt:      now.AddDate(0, 0, -10),
+		})
+		if err != nil {
+			t.Errorf("Error creating job artifact: %s", err.Error())
+			return nil
+		}
+
+		recent, err := qb.Create(ctx, models.JobArtifact{
+			JobID:          2,
+			JobDescription: "Applying retention rules...",
+			Name:           "retention.csv",
+			Path:           "2/retention.csv",
+			ContentType:    "text/csv",
+			Size:           10,
+			CreatedAt:      now,
+		})
+		if err != nil {
+			t.Errorf("Error creating job artifact: %s", err.Error())
+			return nil
+		}
+
+		found, err := qb.Find(ctx, recent.ID)
+		if err != nil {
+			t.Errorf("Error finding job artifact: %s", err.Error())
+			return nil
+		}
+		if assert.NotNil(t, found) {
+			assert.Equal(t, "retention.csv", found.Name)
+			assert.Equal(t, "2/retention.csv", found.Path)
+			assert.Equal(t, int64(10), found.Size)
+		}
+
+		all, err := qb.All(ctx)
+		if err != nil {
+			t.Errorf("Error finding job artifacts: %s", err.Error())
+			return nil
+		}
+		if assert.Len(t, all, 2) {
+			// newest first
+			assert.Equal(t, recent.ID, all[0].ID)
+			assert.Equal(t, old.ID, all[1].ID)
+		}
+
+		expired, err := qb.FindCreatedBefore(ctx, now.AddDate(0, 0, -7))
+		if err != nil {
+			t.Errorf("Error finding expired job artifacts: %s", err.Error())
+			return nil
+		}
+		if assert.Len(t, expired, 1) {
+			assert.Equal(t, old.ID, expired[0].ID)
+		}
+
+		if err := qb.Destroy(ctx, old.ID); err != nil {
+			t.Errorf("Error destroying job artifact: %s", err.Error())
+			return nil
+		}
+
+		found, err = qb.Find(ctx, old.ID)
+		if err != nil {
+			t.Errorf("Error finding job artifact: %s", err.Error())
+			return nil
+		}
+		assert.Nil(t, found)
+
+		return nil
+	})
+}
@@ -0,0 +1,87 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const (
+	jobCheckpointTable         = "job_checkpoints"
+	jobCheckpointProgressTable = "job_checkpoint_progress"
+)
+
+type jobCheckpointQueryBuilder struct {
+	repository
+}
+
+var JobCheckpointReaderWriter = &jobCheckpointQueryBuilder{
+	repository{
+		tableName: jobCheckpointTable,
+		idColumn:  "job",
+	},
+}
+
+func (qb *jobCheckpointQueryBuilder) Find(ctx context.Context, job string) (*models.JobCheckpoint, error) {
+	query := selectAll(jobCheckpointTable) + "WHERE job = ?"
+
+	var ret models.JobCheckpoint
+	found := false
+	if err := qb.queryFunc(ctx, query, []interface{}{job}, true, func(rows *sqlx.Rows) error {
+		found = true
+		return rows.StructScan(&ret)
+	}); err != nil {
+		return nil, err
+	}
+
+	if !found {
+		return nil, nil
+	}
+
+	ret.Progress = make(map[string]int)
+
+	query = fmt.Sprintf("SELECT task, last_id FROM %s WHERE job = ?", jobCheckpointProgressTable)
+	if err := qb.queryFunc(ctx, query, []interface{}{job}, false, func(rows *sqlx.Rows) error {
+		var task string
+		var lastID int
+		if err := rows.Scan(&task, &lastID); err != nil {
+			return err
+		}
+		ret.Progress[task] = lastID
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return &ret, nil
+}
+
+func (qb *jobCheckpointQueryBuilder) Start(ctx context.Context, job string, input string) error {
+	if err := qb.Destroy(ctx, job); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	stmt := fmt.Sprintf("INSERT INTO %s (job, input, created_at, updated_at) VALUES (?, ?, ?, ?)", jobCheckpointTable)
+	_, err := qb.tx.Exec(ctx, stmt, job, input, now, now)
+	return err
+}
+
+func (qb *jobCheckpointQueryBuilder) SetProgress(ctx context.Context, job string, task string, lastID int) error {
+	stmt := fmt.Sprintf("INSERT INTO %s (job, task, last_id) VALUES (?, ?, ?) ON CONFLICT (job, task) DO UPDATE SET last_id = excluded.last_id", jobCheckpointProgressTable)
+	if _, err := qb.tx.Exec(ctx, stmt, job, task, lastID); err != nil {
+		return err
+	}
+
+	stmt = fmt.Sprintf("UPDATE %s SET updated_at = ? WHERE job = ?", jobCheckpointTable)
+	_, err := qb.tx.Exec(ctx, stmt, time.Now(), job)
+	return err
+}
+
+func (qb *jobCheckpointQueryBuilder) Destroy(ctx context.Context, job string) error {
+	_, err := qb.tx.Exec(ctx, fmt.Sprintf("DELETE FROM %s WHERE job = ?", jobCheckpointTable), job)
+	return err
+}
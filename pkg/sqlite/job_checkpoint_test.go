@@ -0,0 +1,83 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/sqlite"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJobCheckpoint(t *testing.T) {
+	qb := sqlite.JobCheckpointReaderWriter
+	const job = "test"
+
+	withRollbackTxn(func(ctx context.Context) error {
+		c, err := qb.Find(ctx, job)
+		if err != nil {
+			t.Errorf("Error finding job checkpoint: %s", err.Error())
+			return nil
+		}
+		assert.Nil(t, c)
+
+		if err := qb.Start(ctx, job, `{"sprites":true}`); err != nil {
+			t.Errorf("Error starting job checkpoint: %s", err.Error())
+			return nil
+		}
+		if err := qb.SetProgress(ctx, job, "sprites", 5); err != nil {
+			t.Errorf("Error setting job checkpoint progress: %s", err.Error())
+			return nil
+		}
+		if err := qb.SetProgress(ctx, job, "sprites", 8); err != nil {
+			t.Errorf("Error setting job checkpoint progress: %s", err.Error())
+			return nil
+		}
+		if err := qb.SetProgress(ctx, job, "phashes", 3); err != nil {
+			t.Errorf("Error setting job checkpoint progress: %s", err.Error())
+			return nil
+		}
+
+		c, err = qb.Find(ctx, job)
+		if err != nil {
+			t.Errorf("Error finding job checkpoint: %s", err.Error())
+			return nil
+		}
+		if assert.NotNil(t, c) {
+			assert.Equal(t, `{"sprites":true}`, c.Input)
+			assert.Equal(t, map[string]int{"sprites": 8, "phashes": 3}, c.Progress)
+		}
+
+		// starting again replaces the progress
+		if err := qb.Start(ctx, job, `{"previews":true}`); err != nil {
+			t.Errorf("Error starting job checkpoint: %s", err.Error())
+			return nil
+		}
+
+		c, err = qb.Find(ctx, job)
+		if err != nil {
+			t.Errorf("Error finding job checkpoint: %s", err.Error())
+			return nil
+		}
+		if assert.NotNil(t, c) {
+			assert.Equal(t, `{"previews":true}`, c.Input)
+			assert.Empty(t, c.Progress)
+		}
+
+		if err := qb.Destroy(ctx, job); err != nil {
+			t.Errorf("Error destroying job checkpoint: %s", err.Error())
+			return nil
+		}
+
+		c, err = qb.Find(ctx, job)
+		if err != nil {
+			t.Errorf("Error finding job checkpoint: %s", err.Error())
+			return nil
+		}
+		assert.Nil(t, c)
+
+		return nil
+	})
+}
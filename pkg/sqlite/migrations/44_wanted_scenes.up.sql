@@ -0,0 +1,16 @@
+CREATE TABLE `wanted_scenes` (
+  `id` integer not null primary key autoincrement,
+  `title` varchar(255),
+  `stash_box_endpoint` varchar(255),
+  `stash_id` varchar(36),
+  `checksum` varchar(255),
+  `oshash` varchar(255),
+  `phash` bigint,
+  `scene_id` integer,
+  `fulfilled_at` datetime,
+  `created_at` datetime not null,
+  `updated_at` datetime not null,
+  foreign key(`scene_id`) references `scenes`(`id`) on delete SET NULL
+);
+
+CREATE INDEX `index_wanted_scenes_on_scene_id` on `wanted_scenes` (`scene_id`);
@@ -0,0 +1,14 @@
+CREATE TABLE `activity_log` (
+  `id` integer not null primary key autoincrement,
+  `created_at` datetime not null,
+  `auth_method` varchar(255) not null,
+  `credential` varchar(255) not null,
+  `remote_addr` varchar(255) not null,
+  `action` varchar(255) not null,
+  `target_type` varchar(255),
+  `target_id` varchar(255),
+  `details` text
+);
+
+CREATE INDEX `index_activity_log_on_created_at` on `activity_log` (`created_at`);
+CREATE INDEX `index_activity_log_on_action` on `activity_log` (`action`);
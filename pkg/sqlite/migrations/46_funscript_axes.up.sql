@@ -0,0 +1,7 @@
+CREATE TABLE `video_funscript_axes` (
+  `file_id` integer NOT NULL,
+  `axis` varchar(255) NOT NULL,
+  `interactive_speed` int,
+  primary key (`file_id`, `axis`),
+  foreign key(`file_id`) references `video_files`(`file_id`) on delete CASCADE
+);
@@ -0,0 +1,11 @@
+CREATE TABLE `video_funscript_stats` (
+  `file_id` integer NOT NULL primary key,
+  `speed_p5` int NOT NULL,
+  `speed_p50` int NOT NULL,
+  `speed_p95` int NOT NULL,
+  `speed_max` int NOT NULL,
+  `action_count` int NOT NULL,
+  `average_stroke_length` float NOT NULL,
+  `coverage` float NOT NULL,
+  foreign key(`file_id`) references `video_files`(`file_id`) on delete CASCADE
+);
@@ -0,0 +1,13 @@
+CREATE TABLE `playback_events` (
+  `id` integer not null primary key autoincrement,
+  `scene_id` integer not null,
+  `session_id` varchar(255) not null,
+  `event_type` varchar(255) not null,
+  `position` float,
+  `details` text,
+  `created_at` datetime not null,
+  foreign key(`scene_id`) references `scenes`(`id`) on delete CASCADE
+);
+
+CREATE INDEX `index_playback_events_on_scene_id_session_id` on `playback_events` (`scene_id`, `session_id`);
+CREATE INDEX `index_playback_events_on_event_type` on `playback_events` (`event_type`);
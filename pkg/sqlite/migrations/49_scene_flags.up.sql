@@ -0,0 +1,23 @@
+CREATE TABLE `scene_flags` (
+  `id` integer not null primary key autoincrement,
+  `name` varchar(255) not null,
+  `created_at` datetime not null,
+  `updated_at` datetime not null
+);
+
+CREATE UNIQUE INDEX `index_scene_flags_on_name` on `scene_flags` (`name`);
+
+CREATE TABLE `scenes_scene_flags` (
+  `scene_id` integer not null,
+  `flag_id` integer not null,
+  foreign key(`scene_id`) references `scenes`(`id`) on delete CASCADE,
+  foreign key(`flag_id`) references `scene_flags`(`id`) on delete CASCADE,
+  PRIMARY KEY(`scene_id`, `flag_id`)
+);
+
+CREATE INDEX `index_scenes_scene_flags_on_flag_id` on `scenes_scene_flags` (`flag_id`);
+
+INSERT INTO `scene_flags` (`name`, `created_at`, `updated_at`) VALUES
+  ('Watch later', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+  ('Needs review', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+  ('To upgrade', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);
@@ -0,0 +1,8 @@
+CREATE TABLE `bulk_operations` (
+  `id` integer not null primary key autoincrement,
+  `operation` varchar(255) not null,
+  `reverse` blob not null,
+  `created_at` datetime not null
+);
+
+CREATE INDEX `index_bulk_operations_on_created_at` on `bulk_operations` (`created_at`);
@@ -0,0 +1,8 @@
+CREATE TABLE `scenes_trailers` (
+  `scene_id` integer not null primary key,
+  `file_id` integer not null,
+  foreign key(`scene_id`) references `scenes`(`id`) on delete CASCADE,
+  foreign key(`file_id`) references `files`(`id`) on delete CASCADE
+);
+
+CREATE UNIQUE INDEX `index_scenes_trailers_on_file_id` on `scenes_trailers` (`file_id`);
@@ -0,0 +1,27 @@
+CREATE TABLE `scene_external_ids` (
+  `scene_id` integer not null,
+  `source` varchar(255) not null,
+  `external_id` varchar(255) not null,
+  foreign key(`scene_id`) references `scenes`(`id`) on delete CASCADE,
+  PRIMARY KEY(`scene_id`, `source`)
+);
+
+CREATE TABLE `performer_external_ids` (
+  `performer_id` integer not null,
+  `source` varchar(255) not null,
+  `external_id` varchar(255) not null,
+  foreign key(`performer_id`) references `performers`(`id`) on delete CASCADE,
+  PRIMARY KEY(`performer_id`, `source`)
+);
+
+CREATE TABLE `studio_external_ids` (
+  `studio_id` integer not null,
+  `source` varchar(255) not null,
+  `external_id` varchar(255) not null,
+  foreign key(`studio_id`) references `studios`(`id`) on delete CASCADE,
+  PRIMARY KEY(`studio_id`, `source`)
+);
+
+CREATE INDEX `index_scene_external_ids_on_source_external_id` ON `scene_external_ids` (`source`, `external_id`);
+CREATE INDEX `index_performer_external_ids_on_source_external_id` ON `performer_external_ids` (`source`, `external_id`);
+CREATE INDEX `index_studio_external_ids_on_source_external_id` ON `studio_external_ids` (`source`, `external_id`);
@@ -0,0 +1,12 @@
+CREATE TABLE `scene_tag_suggestions` (
+  `scene_id` integer not null,
+  `tag_id` integer not null,
+  `confidence` real not null,
+  `rejected` boolean not null default '0',
+  `created_at` datetime not null,
+  foreign key(`scene_id`) references `scenes`(`id`) on delete CASCADE,
+  foreign key(`tag_id`) references `tags`(`id`) on delete CASCADE,
+  PRIMARY KEY(`scene_id`, `tag_id`)
+);
+
+CREATE INDEX `index_scene_tag_suggestions_on_tag_id` on `scene_tag_suggestions` (`tag_id`);
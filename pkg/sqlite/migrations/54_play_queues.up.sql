@@ -0,0 +1,11 @@
+CREATE TABLE `play_queue_items` (
+  `id` integer not null primary key autoincrement,
+  `queue` varchar(255) not null,
+  `scene_id` integer not null,
+  `position` integer not null,
+  `created_at` datetime not null,
+  foreign key(`scene_id`) references `scenes`(`id`) on delete CASCADE
+);
+
+CREATE INDEX `index_play_queue_items_on_queue_position` on `play_queue_items` (`queue`, `position`);
+CREATE INDEX `index_play_queue_items_on_scene_id` on `play_queue_items` (`scene_id`);
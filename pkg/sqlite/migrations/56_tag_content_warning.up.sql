@@ -0,0 +1 @@
+ALTER TABLE `tags` ADD COLUMN `content_warning` boolean not null default '0';
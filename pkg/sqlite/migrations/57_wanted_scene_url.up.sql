@@ -0,0 +1,14 @@
+ALTER TABLE `wanted_scenes` ADD COLUMN `url` varchar(255);
+ALTER TABLE `wanted_scenes` ADD COLUMN `details` text;
+ALTER TABLE `wanted_scenes` ADD COLUMN `date` varchar(10);
+ALTER TABLE `wanted_scenes` ADD COLUMN `scrape_status` varchar(255);
+ALTER TABLE `wanted_scenes` ADD COLUMN `scrape_error` text;
+
+CREATE INDEX `index_wanted_scenes_on_url` on `wanted_scenes` (`url`);
+CREATE INDEX `index_wanted_scenes_on_scrape_status` on `wanted_scenes` (`scrape_status`);
+
+CREATE TABLE `wanted_scenes_cover` (
+  `wanted_scene_id` integer primary key,
+  `cover` blob not null,
+  foreign key(`wanted_scene_id`) references `wanted_scenes`(`id`) on delete CASCADE
+);
@@ -0,0 +1,14 @@
+CREATE TABLE `job_checkpoints` (
+  `job` varchar(255) not null primary key,
+  `input` text not null,
+  `created_at` datetime not null,
+  `updated_at` datetime not null
+);
+
+CREATE TABLE `job_checkpoint_progress` (
+  `job` varchar(255) not null,
+  `task` varchar(255) not null,
+  `last_id` integer not null,
+  foreign key(`job`) references `job_checkpoints`(`job`) on delete CASCADE,
+  PRIMARY KEY(`job`, `task`)
+);
@@ -0,0 +1,12 @@
+CREATE TABLE `job_artifacts` (
+  `id` integer not null primary key autoincrement,
+  `job_id` integer not null,
+  `job_description` varchar(255) not null,
+  `name` varchar(255) not null,
+  `path` varchar(255) not null,
+  `content_type` varchar(255) not null,
+  `size` integer not null,
+  `created_at` datetime not null
+);
+
+CREATE INDEX `index_job_artifacts_on_created_at` on `job_artifacts` (`created_at`);
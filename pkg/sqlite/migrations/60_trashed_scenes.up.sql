@@ -0,0 +1,10 @@
+CREATE TABLE `trashed_scenes` (
+  `id` integer not null primary key autoincrement,
+  `scene_id` integer not null,
+  `title` varchar(255) not null,
+  `path` varchar(255) not null,
+  `data` blob not null,
+  `created_at` datetime not null
+);
+
+CREATE INDEX `index_trashed_scenes_on_created_at` on `trashed_scenes` (`created_at`);
@@ -0,0 +1 @@
+ALTER TABLE `images` ADD COLUMN `hidden` boolean not null default '0';
@@ -0,0 +1,9 @@
+CREATE TABLE `stash_box_fingerprint_cache` (
+  `endpoint` varchar(255) NOT NULL,
+  `key` varchar(255) NOT NULL,
+  `data` blob NOT NULL,
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`endpoint`, `key`)
+);
+
+CREATE INDEX `index_stash_box_fingerprint_cache_created_at` ON `stash_box_fingerprint_cache` (`created_at`);
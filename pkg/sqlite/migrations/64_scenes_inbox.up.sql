@@ -0,0 +1,2 @@
+ALTER TABLE `scenes` ADD COLUMN `inbox` boolean not null default '0';
+CREATE INDEX `index_scenes_on_inbox` ON `scenes` (`inbox`) WHERE `inbox` = 1;
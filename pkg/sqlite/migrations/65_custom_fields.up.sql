@@ -0,0 +1,45 @@
+CREATE TABLE `custom_fields` (
+  `id` integer not null primary key autoincrement,
+  `object_type` varchar(255) not null,
+  `name` varchar(255) not null,
+  `type` varchar(255) not null,
+  `options` text,
+  `created_at` datetime not null,
+  `updated_at` datetime not null
+);
+
+CREATE UNIQUE INDEX `index_custom_fields_on_object_type_name` on `custom_fields` (`object_type`, `name`);
+
+-- values are untyped so that they are compared using the type of the field
+CREATE TABLE `scenes_custom_fields` (
+  `scene_id` integer not null,
+  `field_id` integer not null,
+  `value` not null,
+  foreign key(`scene_id`) references `scenes`(`id`) on delete CASCADE,
+  foreign key(`field_id`) references `custom_fields`(`id`) on delete CASCADE,
+  PRIMARY KEY(`scene_id`, `field_id`)
+);
+
+CREATE INDEX `index_scenes_custom_fields_on_field_id_value` on `scenes_custom_fields` (`field_id`, `value`);
+
+CREATE TABLE `performers_custom_fields` (
+  `performer_id` integer not null,
+  `field_id` integer not null,
+  `value` not null,
+  foreign key(`performer_id`) references `performers`(`id`) on delete CASCADE,
+  foreign key(`field_id`) references `custom_fields`(`id`) on delete CASCADE,
+  PRIMARY KEY(`performer_id`, `field_id`)
+);
+
+CREATE INDEX `index_performers_custom_fields_on_field_id_value` on `performers_custom_fields` (`field_id`, `value`);
+
+CREATE TABLE `studios_custom_fields` (
+  `studio_id` integer not null,
+  `field_id` integer not null,
+  `value` not null,
+  foreign key(`studio_id`) references `studios`(`id`) on delete CASCADE,
+  foreign key(`field_id`) references `custom_fields`(`id`) on delete CASCADE,
+  PRIMARY KEY(`studio_id`, `field_id`)
+);
+
+CREATE INDEX `index_studios_custom_fields_on_field_id_value` on `studios_custom_fields` (`field_id`, `value`);
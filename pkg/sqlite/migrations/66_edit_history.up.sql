@@ -0,0 +1,13 @@
+CREATE TABLE `edit_history` (
+  `id` integer not null primary key autoincrement,
+  `created_at` datetime not null,
+  `object_type` varchar(255) not null,
+  `object_id` integer not null,
+  `action` varchar(255) not null,
+  `auth_method` varchar(255) not null,
+  `credential` varchar(255) not null,
+  `changes` text not null
+);
+
+CREATE INDEX `index_edit_history_on_object` on `edit_history` (`object_type`, `object_id`);
+CREATE INDEX `index_edit_history_on_created_at` on `edit_history` (`created_at`);
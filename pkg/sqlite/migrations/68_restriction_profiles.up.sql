@@ -0,0 +1,31 @@
+CREATE TABLE `restriction_profiles` (
+  `id` integer not null primary key autoincrement,
+  `name` varchar(255) not null,
+  `pin_hash` varchar(255),
+  `created_at` datetime not null,
+  `updated_at` datetime not null
+);
+
+CREATE UNIQUE INDEX `index_restriction_profiles_on_name` on `restriction_profiles` (`name` COLLATE NOCASE);
+
+CREATE TABLE `restriction_profiles_tags` (
+  `restriction_profile_id` integer not null,
+  `tag_id` integer not null,
+  foreign key(`restriction_profile_id`) references `restriction_profiles`(`id`) on delete CASCADE,
+  foreign key(`tag_id`) references `tags`(`id`) on delete CASCADE,
+  PRIMARY KEY(`restriction_profile_id`, `tag_id`)
+);
+
+CREATE INDEX `index_restriction_profiles_tags_on_tag_id` on `restriction_profiles_tags` (`tag_id`);
+
+CREATE TABLE `restriction_profiles_studios` (
+  `restriction_profile_id` integer not null,
+  `studio_id` integer not null,
+  foreign key(`restriction_profile_id`) references `restriction_profiles`(`id`) on delete CASCADE,
+  foreign key(`studio_id`) references `studios`(`id`) on delete CASCADE,
+  PRIMARY KEY(`restriction_profile_id`, `studio_id`)
+);
+
+CREATE INDEX `index_restriction_profiles_studios_on_studio_id` on `restriction_profiles_studios` (`studio_id`);
+
+ALTER TABLE `users` ADD COLUMN `restriction_profile_id` integer REFERENCES `restriction_profiles`(`id`) ON DELETE SET NULL;
@@ -0,0 +1,165 @@
+-- rebuild the full-text index with a path column holding the paths of the
+-- files of scenes, and prefix indexes for short search terms
+DROP TRIGGER `search_index_scenes_insert`;
+DROP TRIGGER `search_index_scenes_update`;
+DROP TRIGGER `search_index_scenes_delete`;
+DROP TRIGGER `search_index_performers_insert`;
+DROP TRIGGER `search_index_performers_update`;
+DROP TRIGGER `search_index_performers_delete`;
+DROP TRIGGER `search_index_performer_aliases_insert`;
+DROP TRIGGER `search_index_performer_aliases_delete`;
+DROP TRIGGER `search_index_tags_insert`;
+DROP TRIGGER `search_index_tags_update`;
+DROP TRIGGER `search_index_tags_delete`;
+DROP TRIGGER `search_index_tag_aliases_insert`;
+DROP TRIGGER `search_index_tag_aliases_delete`;
+DROP TRIGGER `search_index_scene_markers_insert`;
+DROP TRIGGER `search_index_scene_markers_update`;
+DROP TRIGGER `search_index_scene_markers_delete`;
+DROP TABLE `search_index`;
+
+-- The type of an indexed object is encoded in the docid of its row, which is
+-- id * 4 + type, where type is 0 for scenes, 1 for performers, 2 for tags and
+-- 3 for scene markers.
+CREATE VIRTUAL TABLE `search_index` USING fts4(`title`, `aliases`, `path`, `body`, tokenize=unicode61, prefix="2,3");
+
+-- scenes
+CREATE TRIGGER `search_index_scenes_insert` AFTER INSERT ON `scenes` BEGIN
+  INSERT INTO `search_index` (`docid`, `title`, `aliases`, `path`, `body`)
+  VALUES (new.`id` * 4, coalesce(new.`title`, ''), '', '', coalesce(new.`details`, ''));
+END;
+
+CREATE TRIGGER `search_index_scenes_update` AFTER UPDATE OF `title`, `details` ON `scenes` BEGIN
+  UPDATE `search_index` SET `title` = coalesce(new.`title`, ''), `body` = coalesce(new.`details`, '')
+  WHERE `docid` = new.`id` * 4;
+END;
+
+CREATE TRIGGER `search_index_scenes_delete` AFTER DELETE ON `scenes` BEGIN
+  DELETE FROM `search_index` WHERE `docid` = old.`id` * 4;
+END;
+
+CREATE TRIGGER `search_index_scenes_files_insert` AFTER INSERT ON `scenes_files` BEGIN
+  UPDATE `search_index` SET `path` = coalesce((SELECT group_concat(`folders`.`path` || ' ' || `files`.`basename`, ' ') FROM `scenes_files`
+    INNER JOIN `files` ON `files`.`id` = `scenes_files`.`file_id`
+    INNER JOIN `folders` ON `folders`.`id` = `files`.`parent_folder_id`
+    WHERE `scenes_files`.`scene_id` = new.`scene_id`), '')
+  WHERE `docid` = new.`scene_id` * 4;
+END;
+
+CREATE TRIGGER `search_index_scenes_files_delete` AFTER DELETE ON `scenes_files` BEGIN
+  UPDATE `search_index` SET `path` = coalesce((SELECT group_concat(`folders`.`path` || ' ' || `files`.`basename`, ' ') FROM `scenes_files`
+    INNER JOIN `files` ON `files`.`id` = `scenes_files`.`file_id`
+    INNER JOIN `folders` ON `folders`.`id` = `files`.`parent_folder_id`
+    WHERE `scenes_files`.`scene_id` = old.`scene_id`), '')
+  WHERE `docid` = old.`scene_id` * 4;
+END;
+
+CREATE TRIGGER `search_index_files_update` AFTER UPDATE OF `basename`, `parent_folder_id` ON `files` BEGIN
+  UPDATE `search_index` SET `path` = coalesce((SELECT group_concat(`folders`.`path` || ' ' || `files`.`basename`, ' ') FROM `scenes_files`
+    INNER JOIN `files` ON `files`.`id` = `scenes_files`.`file_id`
+    INNER JOIN `folders` ON `folders`.`id` = `files`.`parent_folder_id`
+    WHERE `scenes_files`.`scene_id` = `search_index`.`docid` / 4), '')
+  WHERE `docid` IN (SELECT `scene_id` * 4 FROM `scenes_files` WHERE `file_id` = new.`id`);
+END;
+
+CREATE TRIGGER `search_index_folders_update` AFTER UPDATE OF `path` ON `folders` BEGIN
+  UPDATE `search_index` SET `path` = coalesce((SELECT group_concat(`folders`.`path` || ' ' || `files`.`basename`, ' ') FROM `scenes_files`
+    INNER JOIN `files` ON `files`.`id` = `scenes_files`.`file_id`
+    INNER JOIN `folders` ON `folders`.`id` = `files`.`parent_folder_id`
+    WHERE `scenes_files`.`scene_id` = `search_index`.`docid` / 4), '')
+  WHERE `docid` IN (
+    SELECT `scenes_files`.`scene_id` * 4 FROM `scenes_files`
+    INNER JOIN `files` ON `files`.`id` = `scenes_files`.`file_id`
+    WHERE `files`.`parent_folder_id` = new.`id`
+  );
+END;
+
+-- performers
+CREATE TRIGGER `search_index_performers_insert` AFTER INSERT ON `performers` BEGIN
+  INSERT INTO `search_index` (`docid`, `title`, `aliases`, `path`, `body`)
+  VALUES (new.`id` * 4 + 1, new.`name`, '', '', coalesce(new.`details`, ''));
+END;
+
+CREATE TRIGGER `search_index_performers_update` AFTER UPDATE OF `name`, `details` ON `performers` BEGIN
+  UPDATE `search_index` SET `title` = new.`name`, `body` = coalesce(new.`details`, '')
+  WHERE `docid` = new.`id` * 4 + 1;
+END;
+
+CREATE TRIGGER `search_index_performers_delete` AFTER DELETE ON `performers` BEGIN
+  DELETE FROM `search_index` WHERE `docid` = old.`id` * 4 + 1;
+END;
+
+CREATE TRIGGER `search_index_performer_aliases_insert` AFTER INSERT ON `performer_aliases` BEGIN
+  UPDATE `search_index`
+  SET `aliases` = coalesce((SELECT group_concat(`alias`, ' ') FROM `performer_aliases` WHERE `performer_id` = new.`performer_id`), '')
+  WHERE `docid` = new.`performer_id` * 4 + 1;
+END;
+
+CREATE TRIGGER `search_index_performer_aliases_delete` AFTER DELETE ON `performer_aliases` BEGIN
+  UPDATE `search_index`
+  SET `aliases` = coalesce((SELECT group_concat(`alias`, ' ') FROM `performer_aliases` WHERE `performer_id` = old.`performer_id`), '')
+  WHERE `docid` = old.`performer_id` * 4 + 1;
+END;
+
+-- tags
+CREATE TRIGGER `search_index_tags_insert` AFTER INSERT ON `tags` BEGIN
+  INSERT INTO `search_index` (`docid`, `title`, `aliases`, `path`, `body`)
+  VALUES (new.`id` * 4 + 2, new.`name`, '', '', coalesce(new.`description`, ''));
+END;
+
+CREATE TRIGGER `search_index_tags_update` AFTER UPDATE OF `name`, `description` ON `tags` BEGIN
+  UPDATE `search_index` SET `title` = new.`name`, `body` = coalesce(new.`description`, '')
+  WHERE `docid` = new.`id` * 4 + 2;
+END;
+
+CREATE TRIGGER `search_index_tags_delete` AFTER DELETE ON `tags` BEGIN
+  DELETE FROM `search_index` WHERE `docid` = old.`id` * 4 + 2;
+END;
+
+CREATE TRIGGER `search_index_tag_aliases_insert` AFTER INSERT ON `tag_aliases` BEGIN
+  UPDATE `search_index`
+  SET `aliases` = coalesce((SELECT group_concat(`alias`, ' ') FROM `tag_aliases` WHERE `tag_id` = new.`tag_id`), '')
+  WHERE `docid` = new.`tag_id` * 4 + 2;
+END;
+
+CREATE TRIGGER `search_index_tag_aliases_delete` AFTER DELETE ON `tag_aliases` BEGIN
+  UPDATE `search_index`
+  SET `aliases` = coalesce((SELECT group_concat(`alias`, ' ') FROM `tag_aliases` WHERE `tag_id` = old.`tag_id`), '')
+  WHERE `docid` = old.`tag_id` * 4 + 2;
+END;
+
+-- scene markers
+CREATE TRIGGER `search_index_scene_markers_insert` AFTER INSERT ON `scene_markers` BEGIN
+  INSERT INTO `search_index` (`docid`, `title`, `aliases`, `path`, `body`)
+  VALUES (new.`id` * 4 + 3, new.`title`, '', '', '');
+END;
+
+CREATE TRIGGER `search_index_scene_markers_update` AFTER UPDATE OF `title` ON `scene_markers` BEGIN
+  UPDATE `search_index` SET `title` = new.`title` WHERE `docid` = new.`id` * 4 + 3;
+END;
+
+CREATE TRIGGER `search_index_scene_markers_delete` AFTER DELETE ON `scene_markers` BEGIN
+  DELETE FROM `search_index` WHERE `docid` = old.`id` * 4 + 3;
+END;
+
+-- index the existing objects
+INSERT INTO `search_index` (`docid`, `title`, `aliases`, `path`, `body`)
+SELECT `id` * 4, coalesce(`title`, ''), '', coalesce((SELECT group_concat(`folders`.`path` || ' ' || `files`.`basename`, ' ') FROM `scenes_files`
+    INNER JOIN `files` ON `files`.`id` = `scenes_files`.`file_id`
+    INNER JOIN `folders` ON `folders`.`id` = `files`.`parent_folder_id`
+    WHERE `scenes_files`.`scene_id` = `scenes`.`id`), ''), coalesce(`details`, '') FROM `scenes`;
+
+INSERT INTO `search_index` (`docid`, `title`, `aliases`, `path`, `body`)
+SELECT `id` * 4 + 1, `name`,
+  coalesce((SELECT group_concat(`alias`, ' ') FROM `performer_aliases` WHERE `performer_id` = `performers`.`id`), ''),
+  '', coalesce(`details`, '')
+FROM `performers`;
+
+INSERT INTO `search_index` (`docid`, `title`, `aliases`, `path`, `body`)
+SELECT `id` * 4 + 2, `name`,
+  coalesce((SELECT group_concat(`alias`, ' ') FROM `tag_aliases` WHERE `tag_id` = `tags`.`id`), ''),
+  '', coalesce(`description`, '')
+FROM `tags`;
+
+INSERT INTO `search_index` (`docid`, `title`, `aliases`, `path`, `body`)
+SELECT `id` * 4 + 3, `title`, '', '', '' FROM `scene_markers`;
@@ -0,0 +1,9 @@
+CREATE TABLE `user_identities` (
+  `user_id` integer not null,
+  `provider` varchar(255) not null,
+  `subject` varchar(255) not null,
+  foreign key(`user_id`) references `users`(`id`) on delete CASCADE,
+  PRIMARY KEY(`user_id`, `provider`)
+);
+
+CREATE UNIQUE INDEX `index_user_identities_on_provider_subject` on `user_identities` (`provider`, `subject`);
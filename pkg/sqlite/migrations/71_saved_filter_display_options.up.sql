@@ -0,0 +1 @@
+ALTER TABLE `saved_filters` ADD COLUMN `display_options` text;
@@ -0,0 +1,2 @@
+ALTER TABLE `tags` ADD COLUMN `favorite` boolean not null default '0';
+ALTER TABLE `studios` ADD COLUMN `favorite` boolean not null default '0';
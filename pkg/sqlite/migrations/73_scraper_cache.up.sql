@@ -0,0 +1,9 @@
+CREATE TABLE `scraper_cache` (
+  `scraper_id` varchar(255) NOT NULL,
+  `key` varchar(255) NOT NULL,
+  `data` blob NOT NULL,
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`scraper_id`, `key`)
+);
+
+CREATE INDEX `index_scraper_cache_created_at` ON `scraper_cache` (`created_at`);
@@ -0,0 +1,13 @@
+CREATE TABLE `stash_box_drafts` (
+  `id` integer not null primary key autoincrement,
+  `endpoint` varchar(255) NOT NULL,
+  `draft_id` varchar(255) NOT NULL,
+  `scene_id` integer,
+  `performer_id` integer,
+  `created_at` datetime NOT NULL,
+  foreign key(`scene_id`) references `scenes`(`id`) on delete CASCADE,
+  foreign key(`performer_id`) references `performers`(`id`) on delete CASCADE
+);
+
+CREATE INDEX `index_stash_box_drafts_scene_id` ON `stash_box_drafts` (`scene_id`);
+CREATE INDEX `index_stash_box_drafts_performer_id` ON `stash_box_drafts` (`performer_id`);
@@ -0,0 +1,21 @@
+CREATE TABLE `stash_box_scene_sync` (
+  `scene_id` integer NOT NULL,
+  `endpoint` varchar(255) NOT NULL,
+  `fingerprints_submitted_at` datetime,
+  `matches_checked_at` datetime,
+  foreign key(`scene_id`) references `scenes`(`id`) on delete CASCADE,
+  PRIMARY KEY (`scene_id`, `endpoint`)
+);
+
+CREATE TABLE `stash_box_match_suggestions` (
+  `id` integer not null primary key autoincrement,
+  `scene_id` integer NOT NULL,
+  `endpoint` varchar(255) NOT NULL,
+  `stash_id` varchar(255) NOT NULL,
+  `data` blob NOT NULL,
+  `rejected` boolean not null default '0',
+  `created_at` datetime NOT NULL,
+  foreign key(`scene_id`) references `scenes`(`id`) on delete CASCADE
+);
+
+CREATE UNIQUE INDEX `index_stash_box_match_suggestions_unique` ON `stash_box_match_suggestions` (`scene_id`, `endpoint`, `stash_id`);
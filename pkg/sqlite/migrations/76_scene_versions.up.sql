@@ -0,0 +1,8 @@
+CREATE TABLE `scene_versions` (
+  `scene_id` integer not null primary key,
+  `primary_scene_id` integer not null,
+  foreign key(`scene_id`) references `scenes`(`id`) on delete CASCADE,
+  foreign key(`primary_scene_id`) references `scenes`(`id`) on delete CASCADE
+);
+
+CREATE INDEX `index_scene_versions_primary_scene_id` ON `scene_versions` (`primary_scene_id`);
@@ -0,0 +1,8 @@
+CREATE TABLE `video_chapters` (
+  `file_id` integer NOT NULL,
+  `chapter_index` integer NOT NULL,
+  `title` varchar(255) NOT NULL,
+  `start` real NOT NULL,
+  primary key (`file_id`, `chapter_index`),
+  foreign key(`file_id`) references `video_files`(`file_id`) on delete CASCADE
+);
@@ -0,0 +1,189 @@
+-- append-only journal of the objects created, updated and destroyed, for
+-- external sync tools. Entries are written by triggers so that they are part
+-- of the transaction making the change. The id of an entry is its cursor.
+CREATE TABLE `operation_journal` (
+  `id` integer not null primary key autoincrement,
+  `entity_type` varchar(255) NOT NULL,
+  `entity_id` integer NOT NULL,
+  `operation` varchar(255) NOT NULL,
+  `revision` integer NOT NULL,
+  `created_at` datetime NOT NULL
+);
+
+CREATE INDEX `index_operation_journal_entity` ON `operation_journal` (`entity_type`, `entity_id`, `revision`);
+
+-- scenes
+CREATE TRIGGER `operation_journal_scenes_insert` AFTER INSERT ON `scenes` BEGIN
+  INSERT INTO `operation_journal` (`entity_type`, `entity_id`, `operation`, `revision`, `created_at`)
+  VALUES ('SCENE', new.`id`, 'CREATE',
+    coalesce((SELECT max(`revision`) FROM `operation_journal` WHERE `entity_type` = 'SCENE' AND `entity_id` = new.`id`), 0) + 1,
+    strftime('%Y-%m-%d %H:%M:%f', 'now'));
+END;
+
+CREATE TRIGGER `operation_journal_scenes_update` AFTER UPDATE ON `scenes` BEGIN
+  INSERT INTO `operation_journal` (`entity_type`, `entity_id`, `operation`, `revision`, `created_at`)
+  VALUES ('SCENE', new.`id`, 'UPDATE',
+    coalesce((SELECT max(`revision`) FROM `operation_journal` WHERE `entity_type` = 'SCENE' AND `entity_id` = new.`id`), 0) + 1,
+    strftime('%Y-%m-%d %H:%M:%f', 'now'));
+END;
+
+CREATE TRIGGER `operation_journal_scenes_delete` AFTER DELETE ON `scenes` BEGIN
+  INSERT INTO `operation_journal` (`entity_type`, `entity_id`, `operation`, `revision`, `created_at`)
+  VALUES ('SCENE', old.`id`, 'DESTROY',
+    coalesce((SELECT max(`revision`) FROM `operation_journal` WHERE `entity_type` = 'SCENE' AND `entity_id` = old.`id`), 0) + 1,
+    strftime('%Y-%m-%d %H:%M:%f', 'now'));
+END;
+
+-- images
+CREATE TRIGGER `operation_journal_images_insert` AFTER INSERT ON `images` BEGIN
+  INSERT INTO `operation_journal` (`entity_type`, `entity_id`, `operation`, `revision`, `created_at`)
+  VALUES ('IMAGE', new.`id`, 'CREATE',
+    coalesce((SELECT max(`revision`) FROM `operation_journal` WHERE `entity_type` = 'IMAGE' AND `entity_id` = new.`id`), 0) + 1,
+    strftime('%Y-%m-%d %H:%M:%f', 'now'));
+END;
+
+CREATE TRIGGER `operation_journal_images_update` AFTER UPDATE ON `images` BEGIN
+  INSERT INTO `operation_journal` (`entity_type`, `entity_id`, `operation`, `revision`, `created_at`)
+  VALUES ('IMAGE', new.`id`, 'UPDATE',
+    coalesce((SELECT max(`revision`) FROM `operation_journal` WHERE `entity_type` = 'IMAGE' AND `entity_id` = new.`id`), 0) + 1,
+    strftime('%Y-%m-%d %H:%M:%f', 'now'));
+END;
+
+CREATE TRIGGER `operation_journal_images_delete` AFTER DELETE ON `images` BEGIN
+  INSERT INTO `operation_journal` (`entity_type`, `entity_id`, `operation`, `revision`, `created_at`)
+  VALUES ('IMAGE', old.`id`, 'DESTROY',
+    coalesce((SELECT max(`revision`) FROM `operation_journal` WHERE `entity_type` = 'IMAGE' AND `entity_id` = old.`id`), 0) + 1,
+    strftime('%Y-%m-%d %H:%M:%f', 'now'));
+END;
+
+-- galleries
+CREATE TRIGGER `operation_journal_galleries_insert` AFTER INSERT ON `galleries` BEGIN
+  INSERT INTO `operation_journal` (`entity_type`, `entity_id`, `operation`, `revision`, `created_at`)
+  VALUES ('GALLERY', new.`id`, 'CREATE',
+    coalesce((SELECT max(`revision`) FROM `operation_journal` WHERE `entity_type` = 'GALLERY' AND `entity_id` = new.`id`), 0) + 1,
+    strftime('%Y-%m-%d %H:%M:%f', 'now'));
+END;
+
+CREATE TRIGGER `operation_journal_galleries_update` AFTER UPDATE ON `galleries` BEGIN
+  INSERT INTO `operation_journal` (`entity_type`, `entity_id`, `operation`, `revision`, `created_at`)
+  VALUES ('GALLERY', new.`id`, 'UPDATE',
+    coalesce((SELECT max(`revision`) FROM `operation_journal` WHERE `entity_type` = 'GALLERY' AND `entity_id` = new.`id`), 0) + 1,
+    strftime('%Y-%m-%d %H:%M:%f', 'now'));
+END;
+
+CREATE TRIGGER `operation_journal_galleries_delete` AFTER DELETE ON `galleries` BEGIN
+  INSERT INTO `operation_journal` (`entity_type`, `entity_id`, `operation`, `revision`, `created_at`)
+  VALUES ('GALLERY', old.`id`, 'DESTROY',
+    coalesce((SELECT max(`revision`) FROM `operation_journal` WHERE `entity_type` = 'GALLERY' AND `entity_id` = old.`id`), 0) + 1,
+    strftime('%Y-%m-%d %H:%M:%f', 'now'));
+END;
+
+-- performers
+CREATE TRIGGER `operation_journal_performers_insert` AFTER INSERT ON `performers` BEGIN
+  INSERT INTO `operation_journal` (`entity_type`, `entity_id`, `operation`, `revision`, `created_at`)
+  VALUES ('PERFORMER', new.`id`, 'CREATE',
+    coalesce((SELECT max(`revision`) FROM `operation_journal` WHERE `entity_type` = 'PERFORMER' AND `entity_id` = new.`id`), 0) + 1,
+    strftime('%Y-%m-%d %H:%M:%f', 'now'));
+END;
+
+CREATE TRIGGER `operation_journal_performers_update` AFTER UPDATE ON `performers` BEGIN
+  INSERT INTO `operation_journal` (`entity_type`, `entity_id`, `operation`, `revision`, `created_at`)
+  VALUES ('PERFORMER', new.`id`, 'UPDATE',
+    coalesce((SELECT max(`revision`) FROM `operation_journal` WHERE `entity_type` = 'PERFORMER' AND `entity_id` = new.`id`), 0) + 1,
+    strftime('%Y-%m-%d %H:%M:%f', 'now'));
+END;
+
+CREATE TRIGGER `operation_journal_performers_delete` AFTER DELETE ON `performers` BEGIN
+  INSERT INTO `operation_journal` (`entity_type`, `entity_id`, `operation`, `revision`, `created_at`)
+  VALUES ('PERFORMER', old.`id`, 'DESTROY',
+    coalesce((SELECT max(`revision`) FROM `operation_journal` WHERE `entity_type` = 'PERFORMER' AND `entity_id` = old.`id`), 0) + 1,
+    strftime('%Y-%m-%d %H:%M:%f', 'now'));
+END;
+
+-- studios
+CREATE TRIGGER `operation_journal_studios_insert` AFTER INSERT ON `studios` BEGIN
+  INSERT INTO `operation_journal` (`entity_type`, `entity_id`, `operation`, `revision`, `created_at`)
+  VALUES ('STUDIO', new.`id`, 'CREATE',
+    coalesce((SELECT max(`revision`) FROM `operation_journal` WHERE `entity_type` = 'STUDIO' AND `entity_id` = new.`id`), 0) + 1,
+    strftime('%Y-%m-%d %H:%M:%f', 'now'));
+END;
+
+CREATE TRIGGER `operation_journal_studios_update` AFTER UPDATE ON `studios` BEGIN
+  INSERT INTO `operation_journal` (`entity_type`, `entity_id`, `operation`, `revision`, `created_at`)
+  VALUES ('STUDIO', new.`id`, 'UPDATE',
+    coalesce((SELECT max(`revision`) FROM `operation_journal` WHERE `entity_type` = 'STUDIO' AND `entity_id` = new.`id`), 0) + 1,
+    strftime('%Y-%m-%d %H:%M:%f', 'now'));
+END;
+
+CREATE TRIGGER `operation_journal_studios_delete` AFTER DELETE ON `studios` BEGIN
+  INSERT INTO `operation_journal` (`entity_type`, `entity_id`, `operation`, `revision`, `created_at`)
+  VALUES ('STUDIO', old.`id`, 'DESTROY',
+    coalesce((SELECT max(`revision`) FROM `operation_journal` WHERE `entity_type` = 'STUDIO' AND `entity_id` = old.`id`), 0) + 1,
+    strftime('%Y-%m-%d %H:%M:%f', 'now'));
+END;
+
+-- tags
+CREATE TRIGGER `operation_journal_tags_insert` AFTER INSERT ON `tags` BEGIN
+  INSERT INTO `operation_journal` (`entity_type`, `entity_id`, `operation`, `revision`, `created_at`)
+  VALUES ('TAG', new.`id`, 'CREATE',
+    coalesce((SELECT max(`revision`) FROM `operation_journal` WHERE `entity_type` = 'TAG' AND `entity_id` = new.`id`), 0) + 1,
+    strftime('%Y-%m-%d %H:%M:%f', 'now'));
+END;
+
+CREATE TRIGGER `operation_journal_tags_update` AFTER UPDATE ON `tags` BEGIN
+  INSERT INTO `operation_journal` (`entity_type`, `entity_id`, `operation`, `revision`, `created_at`)
+  VALUES ('TAG', new.`id`, 'UPDATE',
+    coalesce((SELECT max(`revision`) FROM `operation_journal` WHERE `entity_type` = 'TAG' AND `entity_id` = new.`id`), 0) + 1,
+    strftime('%Y-%m-%d %H:%M:%f', 'now'));
+END;
+
+CREATE TRIGGER `operation_journal_tags_delete` AFTER DELETE ON `tags` BEGIN
+  INSERT INTO `operation_journal` (`entity_type`, `entity_id`, `operation`, `revision`, `created_at`)
+  VALUES ('TAG', old.`id`, 'DESTROY',
+    coalesce((SELECT max(`revision`) FROM `operation_journal` WHERE `entity_type` = 'TAG' AND `entity_id` = old.`id`), 0) + 1,
+    strftime('%Y-%m-%d %H:%M:%f', 'now'));
+END;
+
+-- movies
+CREATE TRIGGER `operation_journal_movies_insert` AFTER INSERT ON `movies` BEGIN
+  INSERT INTO `operation_journal` (`entity_type`, `entity_id`, `operation`, `revision`, `created_at`)
+  VALUES ('MOVIE', new.`id`, 'CREATE',
+    coalesce((SELECT max(`revision`) FROM `operation_journal` WHERE `entity_type` = 'MOVIE' AND `entity_id` = new.`id`), 0) + 1,
+    strftime('%Y-%m-%d %H:%M:%f', 'now'));
+END;
+
+CREATE TRIGGER `operation_journal_movies_update` AFTER UPDATE ON `movies` BEGIN
+  INSERT INTO `operation_journal` (`entity_type`, `entity_id`, `operation`, `revision`, `created_at`)
+  VALUES ('MOVIE', new.`id`, 'UPDATE',
+    coalesce((SELECT max(`revision`) FROM `operation_journal` WHERE `entity_type` = 'MOVIE' AND `entity_id` = new.`id`), 0) + 1,
+    strftime('%Y-%m-%d %H:%M:%f', 'now'));
+END;
+
+CREATE TRIGGER `operation_journal_movies_delete` AFTER DELETE ON `movies` BEGIN
+  INSERT INTO `operation_journal` (`entity_type`, `entity_id`, `operation`, `revision`, `created_at`)
+  VALUES ('MOVIE', old.`id`, 'DESTROY',
+    coalesce((SELECT max(`revision`) FROM `operation_journal` WHERE `entity_type` = 'MOVIE' AND `entity_id` = old.`id`), 0) + 1,
+    strftime('%Y-%m-%d %H:%M:%f', 'now'));
+END;
+
+-- scene_markers
+CREATE TRIGGER `operation_journal_scene_markers_insert` AFTER INSERT ON `scene_markers` BEGIN
+  INSERT INTO `operation_journal` (`entity_type`, `entity_id`, `operation`, `revision`, `created_at`)
+  VALUES ('SCENE_MARKER', new.`id`, 'CREATE',
+    coalesce((SELECT max(`revision`) FROM `operation_journal` WHERE `entity_type` = 'SCENE_MARKER' AND `entity_id` = new.`id`), 0) + 1,
+    strftime('%Y-%m-%d %H:%M:%f', 'now'));
+END;
+
+CREATE TRIGGER `operation_journal_scene_markers_update` AFTER UPDATE ON `scene_markers` BEGIN
+  INSERT INTO `operation_journal` (`entity_type`, `entity_id`, `operation`, `revision`, `created_at`)
+  VALUES ('SCENE_MARKER', new.`id`, 'UPDATE',
+    coalesce((SELECT max(`revision`) FROM `operation_journal` WHERE `entity_type` = 'SCENE_MARKER' AND `entity_id` = new.`id`), 0) + 1,
+    strftime('%Y-%m-%d %H:%M:%f', 'now'));
+END;
+
+CREATE TRIGGER `operation_journal_scene_markers_delete` AFTER DELETE ON `scene_markers` BEGIN
+  INSERT INTO `operation_journal` (`entity_type`, `entity_id`, `operation`, `revision`, `created_at`)
+  VALUES ('SCENE_MARKER', old.`id`, 'DESTROY',
+    coalesce((SELECT max(`revision`) FROM `operation_journal` WHERE `entity_type` = 'SCENE_MARKER' AND `entity_id` = old.`id`), 0) + 1,
+    strftime('%Y-%m-%d %H:%M:%f', 'now'));
+END;
@@ -0,0 +1,12 @@
+CREATE TABLE `video_subtitle_tracks` (
+  `file_id` integer NOT NULL,
+  `track_index` integer NOT NULL,
+  `stream_index` integer NOT NULL,
+  `language_code` varchar(255) NOT NULL,
+  `title` varchar(255) NOT NULL,
+  `codec` varchar(255) NOT NULL,
+  `is_default` boolean NOT NULL default '0',
+  `forced` boolean NOT NULL default '0',
+  primary key (`file_id`, `track_index`),
+  foreign key(`file_id`) references `video_files`(`file_id`) on delete CASCADE
+);
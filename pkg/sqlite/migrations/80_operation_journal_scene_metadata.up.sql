@@ -0,0 +1,21 @@
+-- playback activity updates the resume time and play count of scenes, which
+-- should not be journaled. Only journal updates to the metadata columns.
+DROP TRIGGER `operation_journal_scenes_update`;
+
+CREATE TRIGGER `operation_journal_scenes_update` AFTER UPDATE OF
+  `title`, `code`, `details`, `director`, `url`, `date`, `rating`, `organized`,
+  `inbox`, `o_counter`, `studio_id`, `location`, `latitude`, `longitude`, `updated_at`
+ON `scenes` BEGIN
+  INSERT INTO `operation_journal` (`entity_type`, `entity_id`, `operation`, `revision`, `created_at`)
+  VALUES ('SCENE', new.`id`, 'UPDATE',
+    coalesce((SELECT max(`revision`) FROM `operation_journal` WHERE `entity_type` = 'SCENE' AND `entity_id` = new.`id`), 0) + 1,
+    strftime('%Y-%m-%d %H:%M:%f', 'now'));
+END;
+
+-- remove the entries superseded by a later entry for the same object
+DELETE FROM `operation_journal` WHERE EXISTS (
+  SELECT 1 FROM `operation_journal` AS `newer`
+  WHERE `newer`.`entity_type` = `operation_journal`.`entity_type`
+    AND `newer`.`entity_id` = `operation_journal`.`entity_id`
+    AND `newer`.`revision` > `operation_journal`.`revision`
+);
@@ -0,0 +1,72 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const operationJournalTable = "operation_journal"
+
+type operationJournalQueryBuilder struct {
+	repository
+}
+
+var OperationJournalReaderWriter = &operationJournalQueryBuilder{
+	repository{
+		tableName: operationJournalTable,
+		idColumn:  idColumn,
+	},
+}
+
+func (qb *operationJournalQueryBuilder) FindAfter(ctx context.Context, cursor int, entityTypes []models.OperationJournalEntityType, limit int) ([]*models.OperationJournalEntry, error) {
+	query := selectAll(operationJournalTable) + " WHERE id > ?"
+	args := []interface{}{cursor}
+
+	if len(entityTypes) > 0 {
+		query += " AND entity_type IN " + getInBinding(len(entityTypes))
+		for _, t := range entityTypes {
+			args = append(args, t.String())
+		}
+	}
+
+	query += fmt.Sprintf(" ORDER BY id ASC LIMIT %d", limit)
+
+	var ret models.OperationJournalEntries
+	if err := qb.query(ctx, query, args, &ret); err != nil {
+		return nil, err
+	}
+
+	return []*models.OperationJournalEntry(ret), nil
+}
+
+func (qb *operationJournalQueryBuilder) Latest(ctx context.Context) (int, error) {
+	var ret int
+	query := "SELECT coalesce(max(id), 0) FROM " + operationJournalTable
+	if err := qb.tx.Get(ctx, &ret, query); err != nil {
+		return 0, fmt.Errorf("getting latest operation journal entry: %w", err)
+	}
+
+	return ret, nil
+}
+
+// Compact deletes the entries up to and including the cursor that are
+// superseded by a later entry for the same object. The newest entry of each
+// object is kept, so that reading the journal from any cursor still returns
+// every object changed since.
+func (qb *operationJournalQueryBuilder) Compact(ctx context.Context, cursor int) (int64, error) {
+	query := fmt.Sprintf(`DELETE FROM %[1]s WHERE id <= ? AND EXISTS (
+		SELECT 1 FROM %[1]s AS newer
+		WHERE newer.entity_type = %[1]s.entity_type
+			AND newer.entity_id = %[1]s.entity_id
+			AND newer.revision > %[1]s.revision
+	)`, operationJournalTable)
+
+	result, err := qb.tx.Exec(ctx, query, cursor)
+	if err != nil {
+		return 0, fmt.Errorf("compacting operation journal: %w", err)
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,231 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sqlite"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOperationJournal(t *testing.T) {
+	qb := sqlite.OperationJournalReaderWriter
+	tqb := sqlite.TagReaderWriter
+
+	withRollbackTxn(func(ctx context.Context) error {
+		cursor, err := qb.Latest(ctx)
+		if err != nil {
+			t.Errorf("Error getting latest cursor: %s", err.Error())
+			return nil
+		}
+
+		tag, err := tqb.Create(ctx, models.Tag{Name: "TestOperationJournal"})
+		if err != nil {
+			t.Errorf("Error creating tag: %s", err.Error())
+			return nil
+		}
+
+		name := "TestOperationJournal updated"
+		if _, err := tqb.Update(ctx, models.TagPartial{ID: tag.ID, Name: &name}); err != nil {
+			t.Errorf("Error updating tag: %s", err.Error())
+			return nil
+		}
+
+		if err := tqb.Destroy(ctx, tag.ID); err != nil {
+			t.Errorf("Error destroying tag: %s", err.Error())
+			return nil
+		}
+
+		got, err := qb.FindAfter(ctx, cursor, []models.OperationJournalEntityType{models.OperationJournalEntityTypeTag}, 10)
+		if err != nil {
+			t.Errorf("Error finding entries: %s", err.Error())
+			return nil
+		}
+
+		if !assert.Len(t, got, 3) {
+			return nil
+		}
+
+		for i, op := range []models.OperationJournalOperation{
+			models.OperationJournalOperationCreate,
+			models.OperationJournalOperationUpdate,
+			models.OperationJournalOperationDestroy,
+		} {
+			assert.Equal(t, models.OperationJournalEntityTypeTag, got[i].EntityType)
+			assert.Equal(t, tag.ID, got[i].EntityID)
+			assert.Equal(t, op, got[i].Operation)
+			assert.Equal(t, i+1, got[i].Revision)
+			assert.False(t, got[i].Timestamp.IsZero())
+		}
+
+		assert.Less(t, got[0].ID, got[1].ID)
+		assert.Less(t, got[1].ID, got[2].ID)
+
+		latest, err := qb.Latest(ctx)
+		if err != nil {
+			t.Errorf("Error getting latest cursor: %s", err.Error())
+			return nil
+		}
+		assert.Equal(t, got[2].ID, latest)
+
+		// limited, and filtered by entity type
+		got, err = qb.FindAfter(ctx, cursor, []models.OperationJournalEntityType{models.OperationJournalEntityTypeTag}, 1)
+		if err != nil {
+			t.Errorf("Error finding entries: %s", err.Error())
+			return nil
+		}
+		assert.Len(t, got, 1)
+
+		got, err = qb.FindAfter(ctx, cursor, []models.OperationJournalEntityType{models.OperationJournalEntityTypeScene}, 10)
+		if err != nil {
+			t.Errorf("Error finding entries: %s", err.Error())
+			return nil
+		}
+		assert.Len(t, got, 0)
+
+		return nil
+	})
+}
+
+func TestOperationJournalSceneActivity(t *testing.T) {
+	qb := sqlite.OperationJournalReaderWriter
+	sceneTypes := []models.OperationJournalEntityType{models.OperationJournalEntityTypeScene}
+
+	withRollbackTxn(func(ctx context.Context) error {
+		cursor, err := qb.Latest(ctx)
+		if err != nil {
+			t.Errorf("Error getting latest cursor: %s", err.Error())
+			return nil
+		}
+
+		sceneID := sceneIDs[sceneIdxWithGallery]
+		resumeTime := 10.0
+		playDuration := 5.0
+		if _, err := db.Scene.SaveActivity(ctx, sceneID, &resumeTime, &playDuration); err != nil {
+			t.Errorf("Error saving scene activity: %s", err.Error())
+			return nil
+		}
+
+		if _, err := db.Scene.IncrementWatchCount(ctx, sceneID); err != nil {
+			t.Errorf("Error incrementing scene play count: %s", err.Error())
+			return nil
+		}
+
+		got, err := qb.FindAfter(ctx, cursor, sceneTypes, 10)
+		if err != nil {
+			t.Errorf("Error finding entries: %s", err.Error())
+			return nil
+		}
+		assert.Len(t, got, 0)
+
+		if _, err := db.Scene.UpdatePartial(ctx, sceneID, models.ScenePartial{
+			Title: models.NewOptionalString("TestOperationJournalSceneActivity"),
+		}); err != nil {
+			t.Errorf("Error updating scene: %s", err.Error())
+			return nil
+		}
+
+		got, err = qb.FindAfter(ctx, cursor, sceneTypes, 10)
+		if err != nil {
+			t.Errorf("Error finding entries: %s", err.Error())
+			return nil
+		}
+		if assert.Len(t, got, 1) {
+			assert.Equal(t, sceneID, got[0].EntityID)
+			assert.Equal(t, models.OperationJournalOperationUpdate, got[0].Operation)
+		}
+
+		return nil
+	})
+}
+
+func TestOperationJournalCompact(t *testing.T) {
+	qb := sqlite.OperationJournalReaderWriter
+	tqb := sqlite.TagReaderWriter
+	tagTypes := []models.OperationJournalEntityType{models.OperationJournalEntityTypeTag}
+
+	withRollbackTxn(func(ctx context.Context) error {
+		start, err := qb.Latest(ctx)
+		if err != nil {
+			t.Errorf("Error getting latest cursor: %s", err.Error())
+			return nil
+		}
+
+		tag, err := tqb.Create(ctx, models.Tag{Name: "TestOperationJournalCompact"})
+		if err != nil {
+			t.Errorf("Error creating tag: %s", err.Error())
+			return nil
+		}
+
+		for _, name := range []string{"TestOperationJournalCompact 1", "TestOperationJournalCompact 2"} {
+			name := name
+			if _, err := tqb.Update(ctx, models.TagPartial{ID: tag.ID, Name: &name}); err != nil {
+				t.Errorf("Error updating tag: %s", err.Error())
+				return nil
+			}
+		}
+
+		cursor, err := qb.Latest(ctx)
+		if err != nil {
+			t.Errorf("Error getting latest cursor: %s", err.Error())
+			return nil
+		}
+
+		// changed after the compaction cursor
+		other, err := tqb.Create(ctx, models.Tag{Name: "TestOperationJournalCompact other"})
+		if err != nil {
+			t.Errorf("Error creating tag: %s", err.Error())
+			return nil
+		}
+		otherName := "TestOperationJournalCompact other 1"
+		if _, err := tqb.Update(ctx, models.TagPartial{ID: other.ID, Name: &otherName}); err != nil {
+			t.Errorf("Error updating tag: %s", err.Error())
+			return nil
+		}
+
+		if _, err := qb.Compact(ctx, cursor); err != nil {
+			t.Errorf("Error compacting journal: %s", err.Error())
+			return nil
+		}
+
+		got, err := qb.FindAfter(ctx, start, tagTypes, 10)
+		if err != nil {
+			t.Errorf("Error finding entries: %s", err.Error())
+			return nil
+		}
+
+		if !assert.Len(t, got, 3) {
+			return nil
+		}
+
+		// only the newest entry of the compacted tag is kept
+		assert.Equal(t, tag.ID, got[0].EntityID)
+		assert.Equal(t, 3, got[0].Revision)
+		assert.Equal(t, other.ID, got[1].EntityID)
+		assert.Equal(t, 1, got[1].Revision)
+		assert.Equal(t, other.ID, got[2].EntityID)
+		assert.Equal(t, 2, got[2].Revision)
+
+		// revisions continue from the newest entry
+		name := "TestOperationJournalCompact 3"
+		if _, err := tqb.Update(ctx, models.TagPartial{ID: tag.ID, Name: &name}); err != nil {
+			t.Errorf("Error updating tag: %s", err.Error())
+			return nil
+		}
+
+		got, err = qb.FindAfter(ctx, got[2].ID, tagTypes, 10)
+		if err != nil {
+			t.Errorf("Error finding entries: %s", err.Error())
+			return nil
+		}
+		if assert.Len(t, got, 1) {
+			assert.Equal(t, 4, got[0].Revision)
+		}
+
+		return nil
+	})
+}
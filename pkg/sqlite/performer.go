@@ -3,6 +3,7 @@ package sqlite
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -613,6 +614,7 @@ func (qb *PerformerStore) makeFilter(ctx context.Context, filter *models.Perform
 	query.handleCriterion(ctx, dateCriterionHandler(filter.DeathDate, tableName+".death_date"))
 	query.handleCriterion(ctx, timestampCriterionHandler(filter.CreatedAt, tableName+".created_at"))
 	query.handleCriterion(ctx, timestampCriterionHandler(filter.UpdatedAt, tableName+".updated_at"))
+	query.handleCriterion(ctx, customFieldsCriterionHandler(models.CustomFieldObjectTypePerformer, tableName, filter.CustomFields))
 
 	return query
 }
@@ -641,7 +643,7 @@ func (qb *PerformerStore) Query(ctx context.Context, performerFilter *models.Per
 
 	query.addFilter(filter)
 
-	query.sortAndPagination = qb.getPerformerSort(findFilter) + getPagination(findFilter)
+	query.sortAndPagination = getFavoritesFirstSort(findFilter, performerTable, qb.getPerformerSort(findFilter)) + getPagination(findFilter)
 	idsResult, countResult, err := query.executeFind(ctx)
 	if err != nil {
 		return nil, 0, err
@@ -865,6 +867,9 @@ func (qb *PerformerStore) getPerformerSort(findFilter *models.FindFilterType) st
 	if sort == "galleries_count" {
 		return getCountSort(performerTable, performersGalleriesTable, performerIDColumn, direction)
 	}
+	if customSort, ok := getCustomFieldSort(models.CustomFieldObjectTypePerformer, performerTable, sort, direction); ok {
+		return customSort
+	}
 
 	return getSort(sort, direction, "performers")
 }
@@ -884,6 +889,74 @@ func (qb *PerformerStore) GetTagIDs(ctx context.Context, id int) ([]int, error)
 	return qb.tagsRepository().getIDs(ctx, id)
 }
 
+func (qb *PerformerStore) linkRepository(table string, fkColumn string) *joinRepository {
+	return &joinRepository{
+		repository: repository{
+			tx:        qb.tx,
+			tableName: table,
+			idColumn:  performerIDColumn,
+		},
+		fkColumn: fkColumn,
+	}
+}
+
+func (qb *PerformerStore) GetSceneIDs(ctx context.Context, performerID int) ([]int, error) {
+	return qb.linkRepository(performersScenesTable, sceneIDColumn).getIDs(ctx, performerID)
+}
+
+func (qb *PerformerStore) GetImageIDs(ctx context.Context, performerID int) ([]int, error) {
+	return qb.linkRepository(performersImagesTable, imageIDColumn).getIDs(ctx, performerID)
+}
+
+func (qb *PerformerStore) GetGalleryIDs(ctx context.Context, performerID int) ([]int, error) {
+	return qb.linkRepository(performersGalleriesTable, galleryIDColumn).getIDs(ctx, performerID)
+}
+
+// Merge moves the scenes, images and galleries of the source performers to
+// the destination performer, then destroys the source performers.
+func (qb *PerformerStore) Merge(ctx context.Context, source []int, destination int) error {
+	if len(source) == 0 {
+		return nil
+	}
+
+	inBinding := getInBinding(len(source))
+
+	srcArgs := make([]interface{}, len(source))
+	for i, id := range source {
+		if id == destination {
+			return errors.New("cannot merge where source == destination")
+		}
+		srcArgs[i] = id
+	}
+
+	args := append([]interface{}{destination}, srcArgs...)
+	args = append(args, destination)
+
+	linkTables := map[string]string{
+		performersScenesTable:    sceneIDColumn,
+		performersImagesTable:    imageIDColumn,
+		performersGalleriesTable: galleryIDColumn,
+	}
+
+	for table, idColumn := range linkTables {
+		if _, err := qb.tx.Exec(ctx, `UPDATE OR IGNORE `+table+`
+SET performer_id = ?
+WHERE performer_id IN `+inBinding+`
+AND NOT EXISTS(SELECT 1 FROM `+table+` o WHERE o.`+idColumn+` = `+table+`.`+idColumn+` AND o.performer_id = ?)`,
+			args...,
+		); err != nil {
+			return err
+		}
+
+		// delete source performer ids from the table where they couldn't be set
+		if _, err := qb.tx.Exec(ctx, `DELETE FROM `+table+` WHERE performer_id IN `+inBinding, srcArgs...); err != nil {
+			return err
+		}
+	}
+
+	return qb.destroyExisting(ctx, source)
+}
+
 func (qb *PerformerStore) imageRepository() *imageRepository {
 	return &imageRepository{
 		repository: repository{
@@ -917,6 +990,33 @@ func (qb *PerformerStore) stashIDRepository() *stashIDRepository {
 	}
 }
 
+func (qb *PerformerStore) externalIDRepository() *externalIDRepository {
+	return &externalIDRepository{
+		repository{
+			tx:        qb.tx,
+			tableName: "performer_external_ids",
+			idColumn:  performerIDColumn,
+		},
+	}
+}
+
+func (qb *PerformerStore) GetExternalIDs(ctx context.Context, performerID int) ([]models.ExternalID, error) {
+	return qb.externalIDRepository().get(ctx, performerID)
+}
+
+func (qb *PerformerStore) UpdateExternalIDs(ctx context.Context, performerID int, externalIDs []models.ExternalID) error {
+	return qb.externalIDRepository().replace(ctx, performerID, externalIDs)
+}
+
+func (qb *PerformerStore) FindByExternalID(ctx context.Context, source string, externalID string) ([]*models.Performer, error) {
+	ids, err := qb.externalIDRepository().findIDs(ctx, source, externalID)
+	if err != nil {
+		return nil, fmt.Errorf("getting performers for external ID %s: %w", externalID, err)
+	}
+
+	return qb.FindMany(ctx, ids)
+}
+
 func (qb *PerformerStore) GetAliases(ctx context.Context, performerID int) ([]string, error) {
 	return performersAliasesTableMgr.get(ctx, performerID)
 }
@@ -5,6 +5,7 @@ package sqlite_test
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"math"
 	"strconv"
@@ -1092,6 +1093,56 @@ func TestPerformerDestroyPerformerImage(t *testing.T) {
 	}
 }
 
+func TestPerformerMerge(t *testing.T) {
+	if err := withRollbackTxn(func(ctx context.Context) error {
+		qb := db.Performer
+
+		destID := performerIDs[performerIdx1WithScene]
+
+		// try merging into same performer
+		assert.NotNil(t, qb.Merge(ctx, []int{destID}, destID))
+
+		srcIDs := []int{
+			performerIDs[performerIdx2WithScene],
+			performerIDs[performerIdxWithScene],
+			performerIDs[performerIdxWithImage],
+			performerIDs[performerIdxWithGallery],
+		}
+
+		if err := qb.Merge(ctx, srcIDs, destID); err != nil {
+			return err
+		}
+
+		// ensure source performers are deleted
+		for _, id := range srcIDs {
+			_, err := qb.Find(ctx, id)
+			assert.ErrorIs(t, err, sql.ErrNoRows)
+		}
+
+		destSceneIDs, err := qb.GetSceneIDs(ctx, destID)
+		if err != nil {
+			return err
+		}
+		assert.ElementsMatch(t, indexesToIDs(sceneIDs, []int{sceneIdxWithTwoPerformers, sceneIdxWithPerformer}), destSceneIDs)
+
+		destImageIDs, err := qb.GetImageIDs(ctx, destID)
+		if err != nil {
+			return err
+		}
+		assert.ElementsMatch(t, indexesToIDs(imageIDs, []int{imageIdxWithPerformer}), destImageIDs)
+
+		destGalleryIDs, err := qb.GetGalleryIDs(ctx, destID)
+		if err != nil {
+			return err
+		}
+		assert.ElementsMatch(t, indexesToIDs(galleryIDs, []int{galleryIdxWithPerformer}), destGalleryIDs)
+
+		return nil
+	}); err != nil {
+		t.Error(err.Error())
+	}
+}
+
 func TestPerformerQueryAge(t *testing.T) {
 	const age = 19
 	ageCriterion := models.IntCriterionInput{
@@ -0,0 +1,88 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const playQueueItemTable = "play_queue_items"
+
+type playQueueQueryBuilder struct {
+	repository
+}
+
+var PlayQueueReaderWriter = &playQueueQueryBuilder{
+	repository{
+		tableName: playQueueItemTable,
+		idColumn:  idColumn,
+	},
+}
+
+func (qb *playQueueQueryBuilder) FindByQueue(ctx context.Context, queue string) ([]*models.PlayQueueItem, error) {
+	query := selectAll(playQueueItemTable) + "WHERE queue = ? ORDER BY position ASC, id ASC"
+
+	var ret models.PlayQueueItems
+	if err := qb.query(ctx, query, []interface{}{queue}, &ret); err != nil {
+		return nil, err
+	}
+
+	return []*models.PlayQueueItem(ret), nil
+}
+
+func (qb *playQueueQueryBuilder) Append(ctx context.Context, queue string, sceneIDs []int) error {
+	var maxPosition sql.NullInt64
+	query := fmt.Sprintf("SELECT MAX(position) FROM %s WHERE queue = ?", playQueueItemTable)
+	if err := qb.querySimple(ctx, query, []interface{}{queue}, &maxPosition); err != nil {
+		return err
+	}
+
+	position := 0
+	if maxPosition.Valid {
+		position = int(maxPosition.Int64) + 1
+	}
+
+	stmt := fmt.Sprintf("INSERT INTO %s (queue, scene_id, position, created_at) VALUES (?, ?, ?, ?)", playQueueItemTable)
+	now := time.Now()
+	for i, sceneID := range sceneIDs {
+		if _, err := qb.tx.Exec(ctx, stmt, queue, sceneID, position+i, now); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (qb *playQueueQueryBuilder) UpdatePositions(ctx context.Context, queue string, itemIDs []int) error {
+	stmt := fmt.Sprintf("UPDATE %s SET position = ? WHERE id = ? AND queue = ?", playQueueItemTable)
+	for i, id := range itemIDs {
+		if _, err := qb.tx.Exec(ctx, stmt, i, id, queue); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (qb *playQueueQueryBuilder) Destroy(ctx context.Context, queue string, itemIDs []int) error {
+	if len(itemIDs) == 0 {
+		return nil
+	}
+
+	args := []interface{}{queue}
+	for _, id := range itemIDs {
+		args = append(args, id)
+	}
+
+	stmt := fmt.Sprintf("DELETE FROM %s WHERE queue = ? AND id IN %s", playQueueItemTable, getInBinding(len(itemIDs)))
+	_, err := qb.tx.Exec(ctx, stmt, args...)
+	return err
+}
+
+func (qb *playQueueQueryBuilder) Clear(ctx context.Context, queue string) error {
+	_, err := qb.tx.Exec(ctx, fmt.Sprintf("DELETE FROM %s WHERE queue = ?", playQueueItemTable), queue)
+	return err
+}
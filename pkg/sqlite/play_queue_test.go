@@ -0,0 +1,93 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sqlite"
+	"github.com/stretchr/testify/assert"
+)
+
+func playQueueSceneIDs(items []*models.PlayQueueItem) []int {
+	var ret []int
+	for _, item := range items {
+		ret = append(ret, item.SceneID)
+	}
+	return ret
+}
+
+func TestPlayQueue(t *testing.T) {
+	qb := sqlite.PlayQueueReaderWriter
+	const queue = "test"
+	const otherQueue = "other"
+
+	scene1 := sceneIDs[sceneIdxWithMovie]
+	scene2 := sceneIDs[sceneIdxWithGallery]
+	scene3 := sceneIDs[sceneIdx1WithPerformer]
+
+	withRollbackTxn(func(ctx context.Context) error {
+		if err := qb.Append(ctx, queue, []int{scene1, scene2}); err != nil {
+			t.Errorf("Error appending to play queue: %s", err.Error())
+			return nil
+		}
+		if err := qb.Append(ctx, queue, []int{scene3}); err != nil {
+			t.Errorf("Error appending to play queue: %s", err.Error())
+			return nil
+		}
+		if err := qb.Append(ctx, otherQueue, []int{scene3}); err != nil {
+			t.Errorf("Error appending to play queue: %s", err.Error())
+			return nil
+		}
+
+		items, err := qb.FindByQueue(ctx, queue)
+		if err != nil {
+			t.Errorf("Error finding play queue: %s", err.Error())
+			return nil
+		}
+		assert.Equal(t, []int{scene1, scene2, scene3}, playQueueSceneIDs(items))
+
+		// reverse the queue
+		if err := qb.UpdatePositions(ctx, queue, []int{items[2].ID, items[1].ID, items[0].ID}); err != nil {
+			t.Errorf("Error updating play queue positions: %s", err.Error())
+			return nil
+		}
+
+		// items of other queues are not affected
+		if err := qb.Destroy(ctx, queue, []int{items[1].ID}); err != nil {
+			t.Errorf("Error removing play queue items: %s", err.Error())
+			return nil
+		}
+
+		items, err = qb.FindByQueue(ctx, queue)
+		if err != nil {
+			t.Errorf("Error finding play queue: %s", err.Error())
+			return nil
+		}
+		assert.Equal(t, []int{scene3, scene1}, playQueueSceneIDs(items))
+
+		if err := qb.Clear(ctx, queue); err != nil {
+			t.Errorf("Error clearing play queue: %s", err.Error())
+			return nil
+		}
+
+		items, err = qb.FindByQueue(ctx, queue)
+		if err != nil {
+			t.Errorf("Error finding play queue: %s", err.Error())
+			return nil
+		}
+		assert.Empty(t, items)
+
+		items, err = qb.FindByQueue(ctx, otherQueue)
+		if err != nil {
+			t.Errorf("Error finding play queue: %s", err.Error())
+			return nil
+		}
+		assert.Equal(t, []int{scene3}, playQueueSceneIDs(items))
+
+		return nil
+	})
+}
@@ -0,0 +1,71 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const playbackEventTable = "playback_events"
+
+type playbackEventQueryBuilder struct {
+	repository
+}
+
+var PlaybackEventReaderWriter = &playbackEventQueryBuilder{
+	repository{
+		tableName: playbackEventTable,
+		idColumn:  idColumn,
+	},
+}
+
+func (qb *playbackEventQueryBuilder) Create(ctx context.Context, newObject models.PlaybackEvent) error {
+	_, err := qb.insert(ctx, newObject)
+	return err
+}
+
+func (qb *playbackEventQueryBuilder) countSessions(ctx context.Context, sceneID *int, where string, args []interface{}) (int, error) {
+	if sceneID != nil {
+		where += " AND scene_id = ?"
+		args = append(args, *sceneID)
+	}
+
+	query := fmt.Sprintf("SELECT 1 FROM %s WHERE %s GROUP BY scene_id, session_id", playbackEventTable, where)
+	return qb.runCountQuery(ctx, qb.buildCountQuery(query), args)
+}
+
+func (qb *playbackEventQueryBuilder) CountSessions(ctx context.Context, sceneID *int) (int, error) {
+	return qb.countSessions(ctx, sceneID, "1 = 1", nil)
+}
+
+func (qb *playbackEventQueryBuilder) CountCompletedSessions(ctx context.Context, sceneID *int) (int, error) {
+	return qb.countSessions(ctx, sceneID, "event_type = ?", []interface{}{models.PlaybackEventTypeComplete})
+}
+
+func (qb *playbackEventQueryBuilder) IncompleteSessionPositions(ctx context.Context, sceneID int) ([]float64, error) {
+	query := fmt.Sprintf(`SELECT e.position FROM %[1]s e
+WHERE e.scene_id = ? AND e.id = (
+  SELECT MAX(l.id) FROM %[1]s l
+  WHERE l.scene_id = e.scene_id AND l.session_id = e.session_id AND l.position IS NOT NULL
+) AND NOT EXISTS (
+  SELECT 1 FROM %[1]s c
+  WHERE c.scene_id = e.scene_id AND c.session_id = e.session_id AND c.event_type = ?
+)`, playbackEventTable)
+
+	var ret []float64
+	if err := qb.queryFunc(ctx, query, []interface{}{sceneID, models.PlaybackEventTypeComplete}, false, func(rows *sqlx.Rows) error {
+		var position float64
+		if err := rows.Scan(&position); err != nil {
+			return err
+		}
+
+		ret = append(ret, position)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
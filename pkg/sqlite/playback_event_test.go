@@ -0,0 +1,72 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sqlite"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlaybackEventStats(t *testing.T) {
+	qb := sqlite.PlaybackEventReaderWriter
+	now := time.Now()
+	sceneID := sceneIDs[sceneIdx1WithPerformer]
+	otherSceneID := sceneIDs[sceneIdx1WithStudio]
+	position := func(v float64) *float64 { return &v }
+
+	events := []models.PlaybackEvent{
+		{SceneID: sceneID, SessionID: "a", Type: models.PlaybackEventTypeStart, Position: position(0)},
+		{SceneID: sceneID, SessionID: "a", Type: models.PlaybackEventTypeComplete, Position: position(100)},
+		{SceneID: sceneID, SessionID: "b", Type: models.PlaybackEventTypeStart, Position: position(0)},
+		{SceneID: sceneID, SessionID: "b", Type: models.PlaybackEventTypeSeek, Position: position(40)},
+		{SceneID: sceneID, SessionID: "b", Type: models.PlaybackEventTypeError},
+		{SceneID: sceneID, SessionID: "c", Type: models.PlaybackEventTypePause, Position: position(10)},
+		{SceneID: otherSceneID, SessionID: "a", Type: models.PlaybackEventTypeStart, Position: position(0)},
+	}
+
+	withRollbackTxn(func(ctx context.Context) error {
+		for _, e := range events {
+			e.CreatedAt = now
+			if err := qb.Create(ctx, e); err != nil {
+				t.Errorf("Error creating playback event: %s", err.Error())
+				return nil
+			}
+		}
+
+		sessions, err := qb.CountSessions(ctx, &sceneID)
+		if err != nil {
+			t.Errorf("Error counting sessions: %s", err.Error())
+			return nil
+		}
+		assert.Equal(t, 3, sessions)
+
+		sessions, err = qb.CountSessions(ctx, nil)
+		if err != nil {
+			t.Errorf("Error counting sessions: %s", err.Error())
+			return nil
+		}
+		assert.Equal(t, 4, sessions)
+
+		completed, err := qb.CountCompletedSessions(ctx, &sceneID)
+		if err != nil {
+			t.Errorf("Error counting completed sessions: %s", err.Error())
+			return nil
+		}
+		assert.Equal(t, 1, completed)
+
+		positions, err := qb.IncompleteSessionPositions(ctx, sceneID)
+		if err != nil {
+			t.Errorf("Error getting session positions: %s", err.Error())
+			return nil
+		}
+		assert.ElementsMatch(t, []float64{40, 10}, positions)
+
+		return nil
+	})
+}
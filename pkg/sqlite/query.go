@@ -55,6 +55,10 @@ func (qb queryBuilder) toSQL(includeSortPagination bool) string {
 }
 
 func (qb queryBuilder) findIDs(ctx context.Context) ([]int, error) {
+	if qb.err != nil {
+		return nil, qb.err
+	}
+
 	const includeSortPagination = true
 	sql := qb.toSQL(includeSortPagination)
 	return qb.repository.runIdsQuery(ctx, sql, qb.args)
@@ -0,0 +1,56 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/doug-martin/goqu/v9"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sliceutil/intslice"
+)
+
+// safeTagsQuery selects the ids of the provided safe tags and all of their
+// descendants.
+func safeTagsQuery(safeTagIDs []int) string {
+	return fmt.Sprintf(`WITH RECURSIVE safe_tags(id) AS (
+	SELECT id FROM tags WHERE id IN (%s)
+	UNION SELECT tags_relations.child_id FROM tags_relations
+	INNER JOIN safe_tags ON safe_tags.id = tags_relations.parent_id
+) SELECT id FROM safe_tags`, strings.Join(intslice.IntSliceToStringSlice(safeTagIDs), ","))
+}
+
+// quickHideClause returns a where clause only including rows where column
+// references an object tagged with a safe tag in joinTable.
+func quickHideClause(column, joinTable, fkColumn string, safeTagIDs []int) string {
+	return fmt.Sprintf("%s IN (SELECT %s.%s FROM %s WHERE %s.tag_id IN (%s))", column, joinTable, fkColumn, joinTable, joinTable, safeTagsQuery(safeTagIDs))
+}
+
+// quickHide adds a where clause to the query excluding objects of table that
+// are not tagged with a safe tag, if quick hide is enabled in the context.
+func quickHide(ctx context.Context, query *queryBuilder, table, joinTable, fkColumn string) {
+	if safeTagIDs, ok := models.QuickHideSafeTags(ctx); ok {
+		query.addWhere(quickHideClause(table+".id", joinTable, fkColumn, safeTagIDs))
+	}
+}
+
+// quickHideDataset is the goqu equivalent of quickHide.
+func quickHideDataset(ctx context.Context, q *goqu.SelectDataset, table, joinTable, fkColumn string) *goqu.SelectDataset {
+	if safeTagIDs, ok := models.QuickHideSafeTags(ctx); ok {
+		q = q.Where(goqu.L(quickHideClause(table+".id", joinTable, fkColumn, safeTagIDs)))
+	}
+	return q
+}
+
+// quickHideMarkerClause returns a where clause excluding scene markers of
+// scenes that are not tagged with a safe tag.
+func quickHideMarkerClause(safeTagIDs []int) string {
+	return quickHideClause(sceneMarkerTable+".scene_id", scenesTagsTable, sceneIDColumn, safeTagIDs)
+}
+
+func quickHideMarkers(ctx context.Context, query *queryBuilder) {
+	if safeTagIDs, ok := models.QuickHideSafeTags(ctx); ok {
+		query.addWhere(quickHideMarkerClause(safeTagIDs))
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/jmoiron/sqlx"
+	"gopkg.in/guregu/null.v4"
 
 	"github.com/stashapp/stash/pkg/file"
 	"github.com/stashapp/stash/pkg/models"
@@ -456,6 +457,117 @@ func (r *captionRepository) replace(ctx context.Context, id file.ID, captions []
 	return nil
 }
 
+type funscriptAxisRepository struct {
+	repository
+}
+
+func (r *funscriptAxisRepository) get(ctx context.Context, id file.ID) ([]*models.FunscriptAxis, error) {
+	query := fmt.Sprintf("SELECT %s, %s from %s WHERE %s = ? ORDER BY %s", funscriptAxisColumn, funscriptSpeedColumn, r.tableName, r.idColumn, funscriptAxisColumn)
+	var ret []*models.FunscriptAxis
+	err := r.queryFunc(ctx, query, []interface{}{id}, false, func(rows *sqlx.Rows) error {
+		var axis string
+		var speed null.Int
+
+		if err := rows.Scan(&axis, &speed); err != nil {
+			return err
+		}
+
+		ret = append(ret, &models.FunscriptAxis{
+			Axis:             axis,
+			InteractiveSpeed: nullIntPtr(speed),
+		})
+		return nil
+	})
+	return ret, err
+}
+
+func (r *funscriptAxisRepository) insert(ctx context.Context, id file.ID, axis *models.FunscriptAxis) (sql.Result, error) {
+	stmt := fmt.Sprintf("INSERT INTO %s (%s, %s, %s) VALUES (?, ?, ?)", r.tableName, r.idColumn, funscriptAxisColumn, funscriptSpeedColumn)
+	return r.tx.Exec(ctx, stmt, id, axis.Axis, axis.InteractiveSpeed)
+}
+
+func (r *funscriptAxisRepository) replace(ctx context.Context, id file.ID, axes []*models.FunscriptAxis) error {
+	if err := r.destroy(ctx, []int{int(id)}); err != nil {
+		return err
+	}
+
+	for _, axis := range axes {
+		if _, err := r.insert(ctx, id, axis); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type chapterRepository struct {
+	repository
+}
+
+func (r *chapterRepository) get(ctx context.Context, id file.ID) ([]file.VideoChapter, error) {
+	query := fmt.Sprintf("SELECT %s, %s from %s WHERE %s = ? ORDER BY %s", chapterTitleColumn, chapterStartColumn, r.tableName, r.idColumn, chapterIndexColumn)
+	var ret []file.VideoChapter
+	err := r.queryFunc(ctx, query, []interface{}{id}, false, func(rows *sqlx.Rows) error {
+		var chapter file.VideoChapter
+		if err := rows.Scan(&chapter.Title, &chapter.Start); err != nil {
+			return err
+		}
+
+		ret = append(ret, chapter)
+		return nil
+	})
+	return ret, err
+}
+
+func (r *chapterRepository) replace(ctx context.Context, id file.ID, chapters []file.VideoChapter) error {
+	if err := r.destroy(ctx, []int{int(id)}); err != nil {
+		return err
+	}
+
+	stmt := fmt.Sprintf("INSERT INTO %s (%s, %s, %s, %s) VALUES (?, ?, ?, ?)", r.tableName, r.idColumn, chapterIndexColumn, chapterTitleColumn, chapterStartColumn)
+	for i, chapter := range chapters {
+		if _, err := r.tx.Exec(ctx, stmt, id, i, chapter.Title, chapter.Start); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type subtitleTrackRepository struct {
+	repository
+}
+
+func (r *subtitleTrackRepository) get(ctx context.Context, id file.ID) ([]file.VideoSubtitleTrack, error) {
+	query := fmt.Sprintf("SELECT stream_index, language_code, title, codec, is_default, forced from %s WHERE %s = ? ORDER BY %s", r.tableName, r.idColumn, subtitleTrackIndexColumn)
+	var ret []file.VideoSubtitleTrack
+	err := r.queryFunc(ctx, query, []interface{}{id}, false, func(rows *sqlx.Rows) error {
+		var track file.VideoSubtitleTrack
+		if err := rows.Scan(&track.StreamIndex, &track.Language, &track.Title, &track.Codec, &track.Default, &track.Forced); err != nil {
+			return err
+		}
+
+		ret = append(ret, track)
+		return nil
+	})
+	return ret, err
+}
+
+func (r *subtitleTrackRepository) replace(ctx context.Context, id file.ID, tracks []file.VideoSubtitleTrack) error {
+	if err := r.destroy(ctx, []int{int(id)}); err != nil {
+		return err
+	}
+
+	stmt := fmt.Sprintf("INSERT INTO %s (%s, %s, stream_index, language_code, title, codec, is_default, forced) VALUES (?, ?, ?, ?, ?, ?, ?, ?)", r.tableName, r.idColumn, subtitleTrackIndexColumn)
+	for i, track := range tracks {
+		if _, err := r.tx.Exec(ctx, stmt, id, i, track.StreamIndex, track.Language, track.Title, track.Codec, track.Default, track.Forced); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 type stringRepository struct {
 	repository
 	stringColumn string
@@ -531,6 +643,48 @@ func (r *stashIDRepository) replace(ctx context.Context, id int, newIDs []models
 	return nil
 }
 
+type externalIDRepository struct {
+	repository
+}
+
+type externalIDs []models.ExternalID
+
+func (s *externalIDs) Append(o interface{}) {
+	*s = append(*s, *o.(*models.ExternalID))
+}
+
+func (s *externalIDs) New() interface{} {
+	return &models.ExternalID{}
+}
+
+func (r *externalIDRepository) get(ctx context.Context, id int) ([]models.ExternalID, error) {
+	query := fmt.Sprintf("SELECT source, external_id from %s WHERE %s = ? ORDER BY source", r.tableName, r.idColumn)
+	var ret externalIDs
+	err := r.query(ctx, query, []interface{}{id}, &ret)
+	return []models.ExternalID(ret), err
+}
+
+func (r *externalIDRepository) replace(ctx context.Context, id int, newIDs []models.ExternalID) error {
+	if err := r.destroy(ctx, []int{id}); err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (%s, source, external_id) VALUES (?, ?, ?)", r.tableName, r.idColumn)
+	for _, externalID := range newIDs {
+		_, err := r.tx.Exec(ctx, query, id, externalID.Source, externalID.ID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findIDs returns the IDs of the objects with the provided identifier.
+func (r *externalIDRepository) findIDs(ctx context.Context, source string, externalID string) ([]int, error) {
+	query := fmt.Sprintf("SELECT %s as id FROM %s WHERE source = ? AND external_id = ?", r.idColumn, r.tableName)
+	return r.runIdsQuery(ctx, query, []interface{}{source, externalID})
+}
+
 type filesRepository struct {
 	repository
 }
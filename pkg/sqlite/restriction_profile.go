@@ -0,0 +1,112 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const (
+	restrictionProfileTable         = "restriction_profiles"
+	restrictionProfilesTagsTable    = "restriction_profiles_tags"
+	restrictionProfilesStudiosTable = "restriction_profiles_studios"
+	restrictionProfileIDColumn      = "restriction_profile_id"
+)
+
+type restrictionProfileQueryBuilder struct {
+	repository
+}
+
+var RestrictionProfileReaderWriter = &restrictionProfileQueryBuilder{
+	repository{
+		tableName: restrictionProfileTable,
+		idColumn:  idColumn,
+	},
+}
+
+func (qb *restrictionProfileQueryBuilder) Create(ctx context.Context, newObject models.RestrictionProfile) (*models.RestrictionProfile, error) {
+	var ret models.RestrictionProfile
+	if err := qb.insertObject(ctx, newObject, &ret); err != nil {
+		return nil, err
+	}
+
+	return &ret, nil
+}
+
+func (qb *restrictionProfileQueryBuilder) Update(ctx context.Context, updatedObject models.RestrictionProfile) (*models.RestrictionProfile, error) {
+	const partial = false
+	if err := qb.update(ctx, updatedObject.ID, updatedObject, partial); err != nil {
+		return nil, err
+	}
+
+	var ret models.RestrictionProfile
+	if err := qb.getByID(ctx, updatedObject.ID, &ret); err != nil {
+		return nil, err
+	}
+
+	return &ret, nil
+}
+
+func (qb *restrictionProfileQueryBuilder) Destroy(ctx context.Context, id int) error {
+	return qb.destroyExisting(ctx, []int{id})
+}
+
+func (qb *restrictionProfileQueryBuilder) Find(ctx context.Context, id int) (*models.RestrictionProfile, error) {
+	var ret models.RestrictionProfile
+	if err := qb.getByID(ctx, id, &ret); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &ret, nil
+}
+
+func (qb *restrictionProfileQueryBuilder) All(ctx context.Context) ([]*models.RestrictionProfile, error) {
+	var ret models.RestrictionProfiles
+	if err := qb.query(ctx, selectAll(restrictionProfileTable)+" ORDER BY name COLLATE NOCASE ASC", nil, &ret); err != nil {
+		return nil, err
+	}
+
+	return []*models.RestrictionProfile(ret), nil
+}
+
+func (qb *restrictionProfileQueryBuilder) tagsRepository() *joinRepository {
+	return &joinRepository{
+		repository: repository{
+			tx:        qb.tx,
+			tableName: restrictionProfilesTagsTable,
+			idColumn:  restrictionProfileIDColumn,
+		},
+		fkColumn: tagIDColumn,
+	}
+}
+
+func (qb *restrictionProfileQueryBuilder) GetTagIDs(ctx context.Context, id int) ([]int, error) {
+	return qb.tagsRepository().getIDs(ctx, id)
+}
+
+func (qb *restrictionProfileQueryBuilder) UpdateTags(ctx context.Context, id int, tagIDs []int) error {
+	return qb.tagsRepository().replace(ctx, id, tagIDs)
+}
+
+func (qb *restrictionProfileQueryBuilder) studiosRepository() *joinRepository {
+	return &joinRepository{
+		repository: repository{
+			tx:        qb.tx,
+			tableName: restrictionProfilesStudiosTable,
+			idColumn:  restrictionProfileIDColumn,
+		},
+		fkColumn: studioIDColumn,
+	}
+}
+
+func (qb *restrictionProfileQueryBuilder) GetStudioIDs(ctx context.Context, id int) ([]int, error) {
+	return qb.studiosRepository().getIDs(ctx, id)
+}
+
+func (qb *restrictionProfileQueryBuilder) UpdateStudios(ctx context.Context, id int, studioIDs []int) error {
+	return qb.studiosRepository().replace(ctx, id, studioIDs)
+}
@@ -0,0 +1,103 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sqlite"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRestrictionProfileCRUD(t *testing.T) {
+	qb := sqlite.RestrictionProfileReaderWriter
+	now := time.Now()
+
+	withRollbackTxn(func(ctx context.Context) error {
+		pinHash := "pinhash"
+		profile, err := qb.Create(ctx, models.RestrictionProfile{
+			Name:      "Kids",
+			PinHash:   &pinHash,
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+		if err != nil {
+			t.Errorf("Error creating restriction profile: %s", err.Error())
+			return nil
+		}
+
+		if _, err := qb.Create(ctx, models.RestrictionProfile{
+			Name:      "kids",
+			CreatedAt: now,
+			UpdatedAt: now,
+		}); err == nil {
+			t.Errorf("Expected error creating restriction profile with duplicate name")
+		}
+
+		profileTagIDs := []int{tagIDs[tagIdxWithScene]}
+		profileStudioIDs := []int{studioIDs[studioIdxWithScene]}
+		if err := qb.UpdateTags(ctx, profile.ID, profileTagIDs); err != nil {
+			t.Errorf("Error updating tags: %s", err.Error())
+			return nil
+		}
+		if err := qb.UpdateStudios(ctx, profile.ID, profileStudioIDs); err != nil {
+			t.Errorf("Error updating studios: %s", err.Error())
+			return nil
+		}
+
+		gotTagIDs, err := qb.GetTagIDs(ctx, profile.ID)
+		if err != nil {
+			t.Errorf("Error getting tag ids: %s", err.Error())
+			return nil
+		}
+		assert.ElementsMatch(t, profileTagIDs, gotTagIDs)
+
+		gotStudioIDs, err := qb.GetStudioIDs(ctx, profile.ID)
+		if err != nil {
+			t.Errorf("Error getting studio ids: %s", err.Error())
+			return nil
+		}
+		assert.ElementsMatch(t, profileStudioIDs, gotStudioIDs)
+
+		user, err := sqlite.UserReaderWriter.Create(ctx, models.User{
+			Username:             "TestRestrictionProfileCRUD",
+			PasswordHash:         "hash",
+			Role:                 models.UserRoleReadOnly,
+			RestrictionProfileID: &profile.ID,
+			CreatedAt:            now,
+			UpdatedAt:            now,
+		})
+		if err != nil {
+			t.Errorf("Error creating user: %s", err.Error())
+			return nil
+		}
+
+		if err := qb.Destroy(ctx, profile.ID); err != nil {
+			t.Errorf("Error destroying restriction profile: %s", err.Error())
+			return nil
+		}
+
+		// users of destroyed profiles are no longer restricted
+		user, err = sqlite.UserReaderWriter.Find(ctx, user.ID)
+		if err != nil {
+			t.Errorf("Error finding user: %s", err.Error())
+			return nil
+		}
+		if assert.NotNil(t, user) {
+			assert.Nil(t, user.RestrictionProfileID)
+		}
+
+		all, err := qb.All(ctx)
+		if err != nil {
+			t.Errorf("Error finding restriction profiles: %s", err.Error())
+			return nil
+		}
+		assert.Len(t, all, 0)
+
+		return nil
+	})
+}
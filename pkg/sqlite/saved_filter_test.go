@@ -118,6 +118,45 @@ func TestSavedFilterSetDefault(t *testing.T) {
 	})
 }
 
+func TestSavedFilterDisplayOptions(t *testing.T) {
+	zoomIndex := 2
+	mode := models.SavedFilterDisplayModeList
+	direction := models.SortDirectionEnumDesc
+	options := &models.SavedFilterDisplayOptions{
+		DisplayMode: &mode,
+		ZoomIndex:   &zoomIndex,
+		Direction:   &direction,
+		Columns:     []string{"title", "duration"},
+	}
+
+	withRollbackTxn(func(ctx context.Context) error {
+		qb := sqlite.SavedFilterReaderWriter
+
+		created, err := qb.Create(ctx, models.SavedFilter{
+			Mode:           models.FilterModeScenes,
+			Name:           "displayOptions",
+			Filter:         "{}",
+			DisplayOptions: options,
+		})
+		if err != nil {
+			t.Errorf("Error creating saved filter: %s", err.Error())
+			return nil
+		}
+		assert.Equal(t, options, created.DisplayOptions)
+
+		// clearing the options
+		created.DisplayOptions = nil
+		updated, err := qb.Update(ctx, *created)
+		if err != nil {
+			t.Errorf("Error updating saved filter: %s", err.Error())
+			return nil
+		}
+		assert.Nil(t, updated.DisplayOptions)
+
+		return nil
+	})
+}
+
 // TODO Update
 // TODO Destroy
 // TODO Find
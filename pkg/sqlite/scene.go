@@ -30,8 +30,20 @@ const (
 	scenesTagsTable       = "scenes_tags"
 	scenesGalleriesTable  = "scenes_galleries"
 	moviesScenesTable     = "movies_scenes"
+	scenesTrailersTable   = "scenes_trailers"
 )
 
+// sceneAllTagsTable is a join table of the scene tags and the primary and
+// secondary tags of the scene markers.
+var sceneAllTagsTable = `(
+SELECT scene_id, tag_id FROM scenes_tags
+UNION
+SELECT scene_id, primary_tag_id AS tag_id FROM scene_markers
+UNION
+SELECT scene_markers.scene_id, scene_markers_tags.tag_id FROM scene_markers_tags
+INNER JOIN scene_markers ON scene_markers.id = scene_markers_tags.scene_marker_id
+)`
+
 var findExactDuplicateQuery = `
 SELECT GROUP_CONCAT(scenes.id) as ids
 FROM scenes
@@ -52,6 +64,14 @@ INNER JOIN files_fingerprints ON (scenes_files.file_id = files_fingerprints.file
 ORDER BY files.size DESC
 `
 
+var findAllPrimaryPhashesQuery = `
+SELECT scenes.id as id, files_fingerprints.fingerprint as phash, video_files.duration as duration
+FROM scenes
+INNER JOIN scenes_files ON (scenes.id = scenes_files.scene_id AND scenes_files.primary = 1)
+INNER JOIN video_files ON (scenes_files.file_id = video_files.file_id)
+INNER JOIN files_fingerprints ON (scenes_files.file_id = files_fingerprints.file_id AND files_fingerprints.type = 'phash')
+`
+
 type sceneRow struct {
 	ID       int               `db:"id" goqu:"skipinsert"`
 	Title    zero.String       `db:"title"`
@@ -63,8 +83,12 @@ type sceneRow struct {
 	// expressed as 1-100
 	Rating       null.Int                   `db:"rating"`
 	Organized    bool                       `db:"organized"`
+	Inbox        bool                       `db:"inbox"`
 	OCounter     int                        `db:"o_counter"`
 	StudioID     null.Int                   `db:"studio_id,omitempty"`
+	Location     zero.String                `db:"location"`
+	Latitude     null.Float                 `db:"latitude"`
+	Longitude    null.Float                 `db:"longitude"`
 	CreatedAt    models.SQLiteTimestamp     `db:"created_at"`
 	UpdatedAt    models.SQLiteTimestamp     `db:"updated_at"`
 	LastPlayedAt models.NullSQLiteTimestamp `db:"last_played_at"`
@@ -85,8 +109,12 @@ func (r *sceneRow) fromScene(o models.Scene) {
 	}
 	r.Rating = intFromPtr(o.Rating)
 	r.Organized = o.Organized
+	r.Inbox = o.Inbox
 	r.OCounter = o.OCounter
 	r.StudioID = intFromPtr(o.StudioID)
+	r.Location = zero.StringFrom(o.Location)
+	r.Latitude = null.FloatFromPtr(o.Latitude)
+	r.Longitude = null.FloatFromPtr(o.Longitude)
 	r.CreatedAt = models.SQLiteTimestamp{Timestamp: o.CreatedAt}
 	r.UpdatedAt = models.SQLiteTimestamp{Timestamp: o.UpdatedAt}
 	if o.LastPlayedAt != nil {
@@ -120,8 +148,12 @@ func (r *sceneQueryRow) resolve() *models.Scene {
 		Date:      r.Date.DatePtr(),
 		Rating:    nullIntPtr(r.Rating),
 		Organized: r.Organized,
+		Inbox:     r.Inbox,
 		OCounter:  r.OCounter,
 		StudioID:  nullIntPtr(r.StudioID),
+		Location:  r.Location.String,
+		Latitude:  nullFloatPtr(r.Latitude),
+		Longitude: nullFloatPtr(r.Longitude),
 
 		PrimaryFileID: nullIntFileIDPtr(r.PrimaryFileID),
 		OSHash:        r.PrimaryFileOshash.String,
@@ -159,8 +191,12 @@ func (r *sceneRowRecord) fromPartial(o models.ScenePartial) {
 	r.setSQLiteDate("date", o.Date)
 	r.setNullInt("rating", o.Rating)
 	r.setBool("organized", o.Organized)
+	r.setBool("inbox", o.Inbox)
 	r.setInt("o_counter", o.OCounter)
 	r.setNullInt("studio_id", o.StudioID)
+	r.setNullString("location", o.Location)
+	r.setNullFloat64("latitude", o.Latitude)
+	r.setNullFloat64("longitude", o.Longitude)
 	r.setSQLiteTimestamp("created_at", o.CreatedAt)
 	r.setSQLiteTimestamp("updated_at", o.UpdatedAt)
 	r.setSQLiteTimestamp("last_played_at", o.LastPlayedAt)
@@ -496,6 +532,9 @@ func (qb *SceneStore) GetManyFileIDs(ctx context.Context, ids []int) ([][]file.I
 
 func (qb *SceneStore) find(ctx context.Context, id int) (*models.Scene, error) {
 	q := qb.selectDataset().Where(qb.tableMgr.byID(id))
+	q = hideContentWarningsDataset(ctx, q, sceneTable, scenesTagsTable, sceneIDColumn)
+	q = quickHideDataset(ctx, q, sceneTable, scenesTagsTable, sceneIDColumn)
+	q = restrictContentDataset(ctx, q, sceneTable, scenesTagsTable, sceneIDColumn)
 
 	ret, err := qb.get(ctx, q)
 	if err != nil {
@@ -539,6 +578,11 @@ func (qb *SceneStore) CountByFileID(ctx context.Context, fileID file.ID) (int, e
 	return count(ctx, q)
 }
 
+func (qb *SceneStore) CountByTrailerFileID(ctx context.Context, fileID file.ID) (int, error) {
+	query := fmt.Sprintf("SELECT scene_id FROM %s WHERE file_id = ?", scenesTrailersTable)
+	return qb.runCountQuery(ctx, qb.buildCountQuery(query), []interface{}{fileID})
+}
+
 func (qb *SceneStore) FindByFingerprints(ctx context.Context, fp []file.Fingerprint) ([]*models.Scene, error) {
 	fingerprintTable := fingerprintTableMgr.table
 
@@ -770,6 +814,9 @@ func (qb *SceneStore) Wall(ctx context.Context, q *string) ([]*models.Scene, err
 
 	table := qb.table()
 	qq := qb.selectDataset().Prepared(true).Where(table.Col("details").Like("%" + s + "%")).Order(goqu.L("RANDOM()").Asc()).Limit(80)
+	qq = hideContentWarningsDataset(ctx, qq, sceneTable, scenesTagsTable, sceneIDColumn)
+	qq = quickHideDataset(ctx, qq, sceneTable, scenesTagsTable, sceneIDColumn)
+	qq = restrictContentDataset(ctx, qq, sceneTable, scenesTagsTable, sceneIDColumn)
 	return qb.getMany(ctx, qq)
 }
 
@@ -836,6 +883,8 @@ func (qb *SceneStore) makeFilter(ctx context.Context, sceneFilter *models.SceneF
 	query.handleCriterion(ctx, intCriterionHandler(sceneFilter.ID, "scenes.id", nil))
 	query.handleCriterion(ctx, pathCriterionHandler(sceneFilter.Path, "folders.path", "files.basename", qb.addFoldersTable))
 	query.handleCriterion(ctx, sceneFileCountCriterionHandler(qb, sceneFilter.FileCount))
+	query.handleCriterion(ctx, sceneLinkedOnlyCriterionHandler(sceneFilter.LinkedOnly))
+	query.handleCriterion(ctx, sceneIsVersionCriterionHandler(sceneFilter.IsVersion))
 	query.handleCriterion(ctx, stringCriterionHandler(sceneFilter.Title, "scenes.title"))
 	query.handleCriterion(ctx, stringCriterionHandler(sceneFilter.Code, "scenes.code"))
 	query.handleCriterion(ctx, stringCriterionHandler(sceneFilter.Details, "scenes.details"))
@@ -876,6 +925,7 @@ func (qb *SceneStore) makeFilter(ctx context.Context, sceneFilter *models.SceneF
 	query.handleCriterion(ctx, rating5CriterionHandler(sceneFilter.Rating, "scenes.rating", nil))
 	query.handleCriterion(ctx, intCriterionHandler(sceneFilter.OCounter, "scenes.o_counter", nil))
 	query.handleCriterion(ctx, boolCriterionHandler(sceneFilter.Organized, "scenes.organized", nil))
+	query.handleCriterion(ctx, boolCriterionHandler(sceneFilter.Inbox, "scenes.inbox", nil))
 
 	query.handleCriterion(ctx, floatIntCriterionHandler(sceneFilter.Duration, "video_files.duration", qb.addVideoFilesTable))
 	query.handleCriterion(ctx, resolutionCriterionHandler(sceneFilter.Resolution, "video_files.height", "video_files.width", qb.addVideoFilesTable))
@@ -883,6 +933,8 @@ func (qb *SceneStore) makeFilter(ctx context.Context, sceneFilter *models.SceneF
 	query.handleCriterion(ctx, hasMarkersCriterionHandler(sceneFilter.HasMarkers))
 	query.handleCriterion(ctx, sceneIsMissingCriterionHandler(qb, sceneFilter.IsMissing))
 	query.handleCriterion(ctx, stringCriterionHandler(sceneFilter.URL, "scenes.url"))
+	query.handleCriterion(ctx, stringCriterionHandler(sceneFilter.Location, "scenes.location"))
+	query.handleCriterion(ctx, proximityCriterionHandler(sceneFilter.Coordinates, sceneTable))
 
 	query.handleCriterion(ctx, criterionHandlerFunc(func(ctx context.Context, f *filterBuilder) {
 		if sceneFilter.StashID != nil {
@@ -906,10 +958,12 @@ func (qb *SceneStore) makeFilter(ctx context.Context, sceneFilter *models.SceneF
 	query.handleCriterion(ctx, floatIntCriterionHandler(sceneFilter.PlayDuration, "scenes.play_duration", nil))
 	query.handleCriterion(ctx, intCriterionHandler(sceneFilter.PlayCount, "scenes.play_count", nil))
 
-	query.handleCriterion(ctx, sceneTagsCriterionHandler(qb, sceneFilter.Tags))
-	query.handleCriterion(ctx, sceneTagCountCriterionHandler(qb, sceneFilter.TagCount))
+	includeMarkerTags := utils.IsTrue(sceneFilter.IncludeMarkerTags)
+	query.handleCriterion(ctx, sceneTagsCriterionHandler(qb, sceneFilter.Tags, includeMarkerTags))
+	query.handleCriterion(ctx, sceneTagCountCriterionHandler(qb, sceneFilter.TagCount, includeMarkerTags))
 	query.handleCriterion(ctx, scenePerformersCriterionHandler(qb, sceneFilter.Performers))
 	query.handleCriterion(ctx, scenePerformerCountCriterionHandler(qb, sceneFilter.PerformerCount))
+	query.handleCriterion(ctx, sceneFlagsCriterionHandler(qb, sceneFilter.Flags))
 	query.handleCriterion(ctx, sceneStudioCriterionHandler(qb, sceneFilter.Studios))
 	query.handleCriterion(ctx, sceneMoviesCriterionHandler(qb, sceneFilter.Movies))
 	query.handleCriterion(ctx, scenePerformerTagsCriterionHandler(qb, sceneFilter.PerformerTags))
@@ -919,6 +973,7 @@ func (qb *SceneStore) makeFilter(ctx context.Context, sceneFilter *models.SceneF
 	query.handleCriterion(ctx, dateCriterionHandler(sceneFilter.Date, "scenes.date"))
 	query.handleCriterion(ctx, timestampCriterionHandler(sceneFilter.CreatedAt, "scenes.created_at"))
 	query.handleCriterion(ctx, timestampCriterionHandler(sceneFilter.UpdatedAt, "scenes.updated_at"))
+	query.handleCriterion(ctx, customFieldsCriterionHandler(models.CustomFieldObjectTypeScene, sceneTable, sceneFilter.CustomFields))
 
 	return query
 }
@@ -991,6 +1046,9 @@ func (qb *SceneStore) Query(ctx context.Context, options models.SceneQueryOption
 	filter := qb.makeFilter(ctx, sceneFilter)
 
 	query.addFilter(filter)
+	hideContentWarnings(ctx, &query, sceneTable, scenesTagsTable, sceneIDColumn)
+	quickHide(ctx, &query, sceneTable, scenesTagsTable, sceneIDColumn)
+	restrictContent(ctx, &query, sceneTable, scenesTagsTable, sceneIDColumn)
 
 	qb.setSceneSort(&query, findFilter)
 	query.sortAndPagination += getPagination(findFilter)
@@ -1080,6 +1138,34 @@ func sceneFileCountCriterionHandler(qb *SceneStore, fileCount *models.IntCriteri
 	return h.handler(fileCount)
 }
 
+// sceneLinkedOnlyCriterionHandler filters scenes by whether they have no
+// files.
+func sceneLinkedOnlyCriterionHandler(linkedOnly *bool) criterionHandlerFunc {
+	return func(ctx context.Context, f *filterBuilder) {
+		if linkedOnly != nil {
+			clause := "EXISTS (SELECT 1 FROM " + scenesFilesTable + " WHERE " + scenesFilesTable + ".scene_id = scenes.id)"
+			if *linkedOnly {
+				clause = "NOT " + clause
+			}
+			f.addWhere(clause)
+		}
+	}
+}
+
+// sceneIsVersionCriterionHandler filters scenes by whether they are stacked
+// as versions of another scene.
+func sceneIsVersionCriterionHandler(isVersion *bool) criterionHandlerFunc {
+	return func(ctx context.Context, f *filterBuilder) {
+		if isVersion != nil {
+			clause := "EXISTS (SELECT 1 FROM " + sceneVersionTable + " WHERE " + sceneVersionTable + ".scene_id = scenes.id)"
+			if !*isVersion {
+				clause = "NOT " + clause
+			}
+			f.addWhere(clause)
+		}
+	}
+}
+
 func scenePhashDuplicatedCriterionHandler(duplicatedFilter *models.PHashDuplicationCriterionInput, addJoinFn func(f *filterBuilder)) criterionHandlerFunc {
 	return func(ctx context.Context, f *filterBuilder) {
 		// TODO: Wishlist item: Implement Distance matching
@@ -1209,7 +1295,15 @@ func sceneCaptionCriterionHandler(qb *SceneStore, captions *models.StringCriteri
 	return h.handler(captions)
 }
 
-func sceneTagsCriterionHandler(qb *SceneStore, tags *models.HierarchicalMultiCriterionInput) criterionHandlerFunc {
+func sceneTagsJoinTable(includeMarkerTags bool) string {
+	if includeMarkerTags {
+		return sceneAllTagsTable
+	}
+
+	return scenesTagsTable
+}
+
+func sceneTagsCriterionHandler(qb *SceneStore, tags *models.HierarchicalMultiCriterionInput, includeMarkerTags bool) criterionHandlerFunc {
 	h := joinedHierarchicalMultiCriterionHandlerBuilder{
 		tx: qb.tx,
 
@@ -1219,17 +1313,17 @@ func sceneTagsCriterionHandler(qb *SceneStore, tags *models.HierarchicalMultiCri
 
 		relationsTable: "tags_relations",
 		joinAs:         "scene_tag",
-		joinTable:      scenesTagsTable,
+		joinTable:      sceneTagsJoinTable(includeMarkerTags),
 		primaryFK:      sceneIDColumn,
 	}
 
 	return h.handler(tags)
 }
 
-func sceneTagCountCriterionHandler(qb *SceneStore, tagCount *models.IntCriterionInput) criterionHandlerFunc {
+func sceneTagCountCriterionHandler(qb *SceneStore, tagCount *models.IntCriterionInput, includeMarkerTags bool) criterionHandlerFunc {
 	h := countCriterionHandlerBuilder{
 		primaryTable: sceneTable,
-		joinTable:    scenesTagsTable,
+		joinTable:    sceneTagsJoinTable(includeMarkerTags),
 		primaryFK:    sceneIDColumn,
 	}
 
@@ -1252,6 +1346,22 @@ func scenePerformersCriterionHandler(qb *SceneStore, performers *models.MultiCri
 	return h.handler(performers)
 }
 
+func sceneFlagsCriterionHandler(qb *SceneStore, flags *models.MultiCriterionInput) criterionHandlerFunc {
+	h := joinedMultiCriterionHandlerBuilder{
+		primaryTable: sceneTable,
+		joinTable:    scenesFlagsTable,
+		joinAs:       "flags_join",
+		primaryFK:    sceneIDColumn,
+		foreignFK:    sceneFlagIDColumn,
+
+		addJoinTable: func(f *filterBuilder) {
+			qb.flagsRepository().join(f, "flags_join", "scenes.id")
+		},
+	}
+
+	return h.handler(flags)
+}
+
 func scenePerformerCountCriterionHandler(qb *SceneStore, performerCount *models.IntCriterionInput) criterionHandlerFunc {
 	h := countCriterionHandlerBuilder{
 		primaryTable: sceneTable,
@@ -1451,6 +1561,10 @@ func (qb *SceneStore) setSceneSort(query *queryBuilder, findFilter *models.FindF
 		// handle here since getSort has special handling for _count suffix
 		query.sortAndPagination += " ORDER BY scenes.play_count " + direction
 	default:
+		if customSort, ok := getCustomFieldSort(models.CustomFieldObjectTypeScene, sceneTable, sort, direction); ok {
+			query.sortAndPagination += customSort
+			return
+		}
 		query.sortAndPagination += getSort(sort, direction, "scenes")
 	}
 }
@@ -1534,6 +1648,38 @@ func (qb *SceneStore) DestroyCover(ctx context.Context, sceneID int) error {
 	return qb.imageRepository().destroy(ctx, []int{sceneID})
 }
 
+func (qb *SceneStore) GetTrailerFileID(ctx context.Context, sceneID int) (*file.ID, error) {
+	query := fmt.Sprintf("SELECT file_id FROM %s WHERE scene_id = ?", scenesTrailersTable)
+
+	var ret *file.ID
+	if err := qb.queryFunc(ctx, query, []interface{}{sceneID}, true, func(rows *sqlx.Rows) error {
+		var id file.ID
+		if err := rows.Scan(&id); err != nil {
+			return err
+		}
+
+		ret = &id
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (qb *SceneStore) UpdateTrailerFile(ctx context.Context, sceneID int, fileID *file.ID) error {
+	if _, err := qb.tx.Exec(ctx, fmt.Sprintf("DELETE FROM %s WHERE scene_id = ?", scenesTrailersTable), sceneID); err != nil {
+		return err
+	}
+
+	if fileID == nil {
+		return nil
+	}
+
+	_, err := qb.tx.Exec(ctx, fmt.Sprintf("INSERT INTO %s (scene_id, file_id) VALUES (?, ?)", scenesTrailersTable), sceneID, *fileID)
+	return err
+}
+
 func (qb *SceneStore) AssignFiles(ctx context.Context, sceneID int, fileIDs []file.ID) error {
 	// assuming a file can only be assigned to a single scene
 	if err := scenesFilesTableMgr.destroyJoins(ctx, fileIDs); err != nil {
@@ -1606,6 +1752,17 @@ func (qb *SceneStore) GetPerformerIDs(ctx context.Context, id int) ([]int, error
 	return qb.performersRepository().getIDs(ctx, id)
 }
 
+func (qb *SceneStore) flagsRepository() *joinRepository {
+	return &joinRepository{
+		repository: repository{
+			tx:        qb.tx,
+			tableName: scenesFlagsTable,
+			idColumn:  sceneIDColumn,
+		},
+		fkColumn: sceneFlagIDColumn,
+	}
+}
+
 func (qb *SceneStore) tagsRepository() *joinRepository {
 	return &joinRepository{
 		repository: repository{
@@ -1656,6 +1813,52 @@ func (qb *SceneStore) GetStashIDs(ctx context.Context, sceneID int) ([]models.St
 	return qb.stashIDRepository().get(ctx, sceneID)
 }
 
+func (qb *SceneStore) externalIDRepository() *externalIDRepository {
+	return &externalIDRepository{
+		repository{
+			tx:        qb.tx,
+			tableName: "scene_external_ids",
+			idColumn:  sceneIDColumn,
+		},
+	}
+}
+
+func (qb *SceneStore) GetExternalIDs(ctx context.Context, sceneID int) ([]models.ExternalID, error) {
+	return qb.externalIDRepository().get(ctx, sceneID)
+}
+
+func (qb *SceneStore) UpdateExternalIDs(ctx context.Context, sceneID int, externalIDs []models.ExternalID) error {
+	return qb.externalIDRepository().replace(ctx, sceneID, externalIDs)
+}
+
+func (qb *SceneStore) FindByExternalID(ctx context.Context, source string, externalID string) ([]*models.Scene, error) {
+	ids, err := qb.externalIDRepository().findIDs(ctx, source, externalID)
+	if err != nil {
+		return nil, fmt.Errorf("getting scenes for external ID %s: %w", externalID, err)
+	}
+
+	return qb.FindMany(ctx, ids)
+}
+
+func (qb *SceneStore) FindPrimaryPhashes(ctx context.Context) ([]*utils.Phash, error) {
+	var ret []*utils.Phash
+	if err := qb.queryFunc(ctx, findAllPrimaryPhashesQuery, nil, false, func(rows *sqlx.Rows) error {
+		phash := utils.Phash{
+			Bucket: -1,
+		}
+		if err := rows.StructScan(&phash); err != nil {
+			return err
+		}
+
+		ret = append(ret, &phash)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
 func (qb *SceneStore) FindDuplicates(ctx context.Context, distance int) ([][]*models.Scene, error) {
 	var dupeIds [][]int
 	if distance == 0 {
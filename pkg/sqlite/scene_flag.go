@@ -0,0 +1,150 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const (
+	sceneFlagTable      = "scene_flags"
+	scenesFlagsTable    = "scenes_scene_flags"
+	sceneFlagIDColumn   = "flag_id"
+	sceneFlagNameColumn = "name"
+)
+
+type sceneFlagQueryBuilder struct {
+	repository
+}
+
+var SceneFlagReaderWriter = &sceneFlagQueryBuilder{
+	repository{
+		tableName: sceneFlagTable,
+		idColumn:  idColumn,
+	},
+}
+
+func (qb *sceneFlagQueryBuilder) scenesRepository() *joinRepository {
+	return &joinRepository{
+		repository: repository{
+			tx:        qb.tx,
+			tableName: scenesFlagsTable,
+			idColumn:  sceneFlagIDColumn,
+		},
+		fkColumn: sceneIDColumn,
+	}
+}
+
+func (qb *sceneFlagQueryBuilder) Create(ctx context.Context, newObject models.SceneFlag) (*models.SceneFlag, error) {
+	var ret models.SceneFlag
+	if err := qb.insertObject(ctx, newObject, &ret); err != nil {
+		return nil, err
+	}
+
+	return &ret, nil
+}
+
+func (qb *sceneFlagQueryBuilder) Update(ctx context.Context, updatedObject models.SceneFlag) (*models.SceneFlag, error) {
+	const partial = false
+	if err := qb.update(ctx, updatedObject.ID, updatedObject, partial); err != nil {
+		return nil, err
+	}
+
+	var ret models.SceneFlag
+	if err := qb.getByID(ctx, updatedObject.ID, &ret); err != nil {
+		return nil, err
+	}
+
+	return &ret, nil
+}
+
+func (qb *sceneFlagQueryBuilder) Destroy(ctx context.Context, id int) error {
+	return qb.destroyExisting(ctx, []int{id})
+}
+
+func (qb *sceneFlagQueryBuilder) Find(ctx context.Context, id int) (*models.SceneFlag, error) {
+	var ret models.SceneFlag
+	if err := qb.getByID(ctx, id, &ret); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &ret, nil
+}
+
+func (qb *sceneFlagQueryBuilder) FindMany(ctx context.Context, ids []int) ([]*models.SceneFlag, error) {
+	var ret []*models.SceneFlag
+	for _, id := range ids {
+		flag, err := qb.Find(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if flag == nil {
+			return nil, fmt.Errorf("scene flag with id %d not found", id)
+		}
+
+		ret = append(ret, flag)
+	}
+
+	return ret, nil
+}
+
+func (qb *sceneFlagQueryBuilder) FindByName(ctx context.Context, name string) (*models.SceneFlag, error) {
+	query := fmt.Sprintf("SELECT * FROM %s WHERE %s = ? COLLATE NOCASE LIMIT 1", sceneFlagTable, sceneFlagNameColumn)
+
+	var ret models.SceneFlags
+	if err := qb.query(ctx, query, []interface{}{name}, &ret); err != nil {
+		return nil, err
+	}
+
+	if len(ret) == 0 {
+		return nil, nil
+	}
+
+	return ret[0], nil
+}
+
+func (qb *sceneFlagQueryBuilder) FindBySceneID(ctx context.Context, sceneID int) ([]*models.SceneFlag, error) {
+	query := fmt.Sprintf(`SELECT %[1]s.* FROM %[1]s
+INNER JOIN %[2]s ON %[2]s.%[3]s = %[1]s.id
+WHERE %[2]s.scene_id = ?
+ORDER BY %[1]s.name ASC`, sceneFlagTable, scenesFlagsTable, sceneFlagIDColumn)
+
+	var ret models.SceneFlags
+	if err := qb.query(ctx, query, []interface{}{sceneID}, &ret); err != nil {
+		return nil, err
+	}
+
+	return []*models.SceneFlag(ret), nil
+}
+
+func (qb *sceneFlagQueryBuilder) All(ctx context.Context) ([]*models.SceneFlag, error) {
+	var ret models.SceneFlags
+	if err := qb.query(ctx, selectAll(sceneFlagTable)+getSort("name", "ASC", sceneFlagTable), nil, &ret); err != nil {
+		return nil, err
+	}
+
+	return []*models.SceneFlag(ret), nil
+}
+
+func (qb *sceneFlagQueryBuilder) CountScenes(ctx context.Context, id int) (int, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s = ?", sceneIDColumn, scenesFlagsTable, sceneFlagIDColumn)
+	return qb.runCountQuery(ctx, qb.buildCountQuery(query), []interface{}{id})
+}
+
+func (qb *sceneFlagQueryBuilder) AddScenes(ctx context.Context, id int, sceneIDs []int) error {
+	return qb.scenesRepository().insertOrIgnore(ctx, id, sceneIDs...)
+}
+
+func (qb *sceneFlagQueryBuilder) RemoveScenes(ctx context.Context, id int, sceneIDs []int) error {
+	if len(sceneIDs) == 0 {
+		return nil
+	}
+
+	return qb.scenesRepository().destroyJoins(ctx, id, sceneIDs...)
+}
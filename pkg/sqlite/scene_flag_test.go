@@ -0,0 +1,125 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sqlite"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSceneFlagDefaults(t *testing.T) {
+	withTxn(func(ctx context.Context) error {
+		flags, err := sqlite.SceneFlagReaderWriter.All(ctx)
+		if err != nil {
+			t.Errorf("Error getting scene flags: %s", err.Error())
+			return nil
+		}
+
+		var names []string
+		for _, f := range flags {
+			names = append(names, f.Name)
+		}
+
+		assert.Equal(t, []string{"Needs review", "To upgrade", "Watch later"}, names)
+
+		return nil
+	})
+}
+
+func TestSceneFlagScenes(t *testing.T) {
+	qb := sqlite.SceneFlagReaderWriter
+	sceneID := sceneIDs[sceneIdx1WithPerformer]
+	otherSceneID := sceneIDs[sceneIdx1WithStudio]
+	now := time.Now()
+
+	withRollbackTxn(func(ctx context.Context) error {
+		flag, err := qb.Create(ctx, models.SceneFlag{
+			Name:      "Test flag",
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+		if err != nil {
+			t.Errorf("Error creating scene flag: %s", err.Error())
+			return nil
+		}
+
+		found, err := qb.FindByName(ctx, "test FLAG")
+		if err != nil {
+			t.Errorf("Error finding scene flag by name: %s", err.Error())
+			return nil
+		}
+		assert.Equal(t, flag.ID, found.ID)
+
+		// adding a scene twice is ignored
+		if err := qb.AddScenes(ctx, flag.ID, []int{sceneID, otherSceneID}); err != nil {
+			t.Errorf("Error adding scenes: %s", err.Error())
+			return nil
+		}
+		if err := qb.AddScenes(ctx, flag.ID, []int{sceneID}); err != nil {
+			t.Errorf("Error adding scenes: %s", err.Error())
+			return nil
+		}
+
+		count, err := qb.CountScenes(ctx, flag.ID)
+		if err != nil {
+			t.Errorf("Error counting scenes: %s", err.Error())
+			return nil
+		}
+		assert.Equal(t, 2, count)
+
+		sceneFlags, err := qb.FindBySceneID(ctx, sceneID)
+		if err != nil {
+			t.Errorf("Error finding scene flags: %s", err.Error())
+			return nil
+		}
+		assert.Len(t, sceneFlags, 1)
+
+		sceneFilter := &models.SceneFilterType{
+			Flags: &models.MultiCriterionInput{
+				Value:    []string{strconv.Itoa(flag.ID)},
+				Modifier: models.CriterionModifierIncludes,
+			},
+		}
+
+		scenes := queryScene(ctx, t, db.Scene, sceneFilter, nil)
+		var ids []int
+		for _, s := range scenes {
+			ids = append(ids, s.ID)
+		}
+		assert.ElementsMatch(t, []int{sceneID, otherSceneID}, ids)
+
+		if err := qb.RemoveScenes(ctx, flag.ID, []int{otherSceneID}); err != nil {
+			t.Errorf("Error removing scenes: %s", err.Error())
+			return nil
+		}
+
+		sceneFilter.Flags.Modifier = models.CriterionModifierExcludes
+		scenes = queryScene(ctx, t, db.Scene, sceneFilter, nil)
+		for _, s := range scenes {
+			assert.NotEqual(t, sceneID, s.ID)
+		}
+		assert.NotEmpty(t, scenes)
+
+		// destroying the flag removes the joins
+		if err := qb.Destroy(ctx, flag.ID); err != nil {
+			t.Errorf("Error destroying scene flag: %s", err.Error())
+			return nil
+		}
+
+		sceneFlags, err = qb.FindBySceneID(ctx, sceneID)
+		if err != nil {
+			t.Errorf("Error finding scene flags: %s", err.Error())
+			return nil
+		}
+		assert.Len(t, sceneFlags, 0)
+
+		return nil
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/stashapp/stash/pkg/models"
 )
@@ -120,7 +121,17 @@ func (qb *sceneMarkerQueryBuilder) Wall(ctx context.Context, q *string) ([]*mode
 	if q != nil {
 		s = *q
 	}
-	query := "SELECT scene_markers.* FROM scene_markers WHERE scene_markers.title LIKE '%" + s + "%' ORDER BY RANDOM() LIMIT 80"
+	where := []string{"scene_markers.title LIKE '%" + s + "%'"}
+	if models.ContentWarningsHidden(ctx) {
+		where = append(where, contentWarningMarkerClauses()...)
+	}
+	if safeTagIDs, ok := models.QuickHideSafeTags(ctx); ok {
+		where = append(where, quickHideMarkerClause(safeTagIDs))
+	}
+	if r, ok := models.GetContentRestriction(ctx); ok && !r.Empty() {
+		where = append(where, contentRestrictionMarkerClauses(r)...)
+	}
+	query := "SELECT scene_markers.* FROM scene_markers WHERE " + strings.Join(where, " AND ") + " ORDER BY RANDOM() LIMIT 80"
 	return qb.querySceneMarkers(ctx, query, nil)
 }
 
@@ -159,6 +170,9 @@ func (qb *sceneMarkerQueryBuilder) Query(ctx context.Context, sceneMarkerFilter
 	filter := qb.makeFilter(ctx, sceneMarkerFilter)
 
 	query.addFilter(filter)
+	hideContentWarningMarkers(ctx, &query)
+	quickHideMarkers(ctx, &query)
+	restrictContentMarkers(ctx, &query)
 
 	query.sortAndPagination = qb.getSceneMarkerSort(&query, findFilter) + getPagination(findFilter)
 	idsResult, countResult, err := query.executeFind(ctx)
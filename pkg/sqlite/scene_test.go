@@ -17,7 +17,9 @@ import (
 
 	"github.com/stashapp/stash/pkg/file"
 	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene"
 	"github.com/stashapp/stash/pkg/sliceutil/intslice"
+	"github.com/stashapp/stash/pkg/sqlite"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -2401,6 +2403,54 @@ func TestSceneQueryURL(t *testing.T) {
 	verifySceneQuery(t, filter, verifyFn)
 }
 
+func TestSceneQueryCoordinates(t *testing.T) {
+	if err := withRollbackTxn(func(ctx context.Context) error {
+		qb := db.Scene
+
+		createAt := func(lat, lon float64) int {
+			s := &models.Scene{
+				Title:     "TestSceneQueryCoordinates",
+				Latitude:  &lat,
+				Longitude: &lon,
+			}
+			if err := qb.Create(ctx, s, nil); err != nil {
+				t.Fatalf("Error creating scene: %v", err)
+			}
+			return s.ID
+		}
+
+		london := createAt(51.5073, -0.1277)
+		paris := createAt(48.8566, 2.3522)
+		eastOfAntimeridian := createAt(-17, 179.9)
+
+		queryIDs := func(c models.ProximityCriterionInput) []int {
+			var ret []int
+			for _, s := range queryScene(ctx, t, qb, &models.SceneFilterType{Coordinates: &c}, nil) {
+				ret = append(ret, s.ID)
+			}
+			return ret
+		}
+
+		// london and paris are approximately 344km apart
+		assert.ElementsMatch(t, []int{london}, queryIDs(models.ProximityCriterionInput{Latitude: 51.5, Longitude: -0.12, Distance: 100}))
+		assert.ElementsMatch(t, []int{london, paris}, queryIDs(models.ProximityCriterionInput{Latitude: 51.5, Longitude: -0.12, Distance: 400}))
+
+		// distance wraps around the antimeridian
+		assert.ElementsMatch(t, []int{eastOfAntimeridian}, queryIDs(models.ProximityCriterionInput{Latitude: -17, Longitude: -179.9, Distance: 50}))
+
+		_, err := qb.Query(ctx, models.SceneQueryOptions{
+			SceneFilter: &models.SceneFilterType{
+				Coordinates: &models.ProximityCriterionInput{Latitude: 91},
+			},
+		})
+		assert.NotNil(t, err)
+
+		return nil
+	}); err != nil {
+		t.Error(err.Error())
+	}
+}
+
 func TestSceneQueryPathOr(t *testing.T) {
 	const scene1Idx = 1
 	const scene2Idx = 2
@@ -3437,6 +3487,73 @@ func TestSceneQueryTags(t *testing.T) {
 	})
 }
 
+func TestSceneQueryTagsIncludeMarkerTags(t *testing.T) {
+	withTxn(func(ctx context.Context) error {
+		sqb := db.Scene
+		includeMarkerTags := true
+
+		tagsFilter := func(tagIdx int, include bool) *models.SceneFilterType {
+			return &models.SceneFilterType{
+				Tags: &models.HierarchicalMultiCriterionInput{
+					Value:    []string{strconv.Itoa(tagIDs[tagIdx])},
+					Modifier: models.CriterionModifierIncludes,
+				},
+				IncludeMarkerTags: &include,
+			}
+		}
+
+		// secondary marker tag
+		scenes := queryScene(ctx, t, sqb, tagsFilter(tagIdxWithMarkers, false), nil)
+		assert.Len(t, scenes, 0)
+
+		scenes = queryScene(ctx, t, sqb, tagsFilter(tagIdxWithMarkers, true), nil)
+		assert.Len(t, scenes, 1)
+		for _, scene := range scenes {
+			assert.Equal(t, sceneIDs[sceneIdxWithMarkers], scene.ID)
+		}
+
+		// primary marker tag
+		scenes = queryScene(ctx, t, sqb, tagsFilter(tagIdxWithPrimaryMarkers, true), nil)
+		var ids []int
+		for _, scene := range scenes {
+			ids = append(ids, scene.ID)
+		}
+		assert.ElementsMatch(t, []int{sceneIDs[sceneIdxWithMarkers], sceneIDs[sceneIdxWithMarkerAndTag]}, ids)
+
+		// scenes with only marker tags are tagged
+		notNullFilter := models.SceneFilterType{
+			Tags: &models.HierarchicalMultiCriterionInput{
+				Modifier: models.CriterionModifierNotNull,
+			},
+			IncludeMarkerTags: &includeMarkerTags,
+		}
+		q := getSceneStringValue(sceneIdxWithMarkers, titleField)
+		scenes = queryScene(ctx, t, sqb, &notNullFilter, &models.FindFilterType{Q: &q})
+		assert.Len(t, scenes, 1)
+
+		// scene tag and primary marker tag
+		q = getSceneStringValue(sceneIdxWithMarkerAndTag, titleField)
+		findFilter := models.FindFilterType{
+			Q: &q,
+		}
+		tagCountFilter := models.SceneFilterType{
+			TagCount: &models.IntCriterionInput{
+				Value:    2,
+				Modifier: models.CriterionModifierEquals,
+			},
+		}
+
+		scenes = queryScene(ctx, t, sqb, &tagCountFilter, &findFilter)
+		assert.Len(t, scenes, 0)
+
+		tagCountFilter.IncludeMarkerTags = &includeMarkerTags
+		scenes = queryScene(ctx, t, sqb, &tagCountFilter, &findFilter)
+		assert.Len(t, scenes, 1)
+
+		return nil
+	})
+}
+
 func TestSceneQueryPerformerTags(t *testing.T) {
 	withTxn(func(ctx context.Context) error {
 		sqb := db.Scene
@@ -4344,6 +4461,53 @@ func TestSceneStore_AssignFiles(t *testing.T) {
 	}
 }
 
+func TestSceneStore_UpdateTrailerFile(t *testing.T) {
+	qb := db.Scene
+
+	withRollbackTxn(func(ctx context.Context) error {
+		sceneID := sceneIDs[sceneIdx1WithPerformer]
+		fileID := sceneFileIDs[sceneIdx1WithStudio]
+
+		if err := qb.UpdateTrailerFile(ctx, sceneID, &fileID); err != nil {
+			t.Errorf("SceneStore.UpdateTrailerFile() error = %v", err)
+			return nil
+		}
+
+		got, err := qb.GetTrailerFileID(ctx, sceneID)
+		if err != nil {
+			t.Errorf("SceneStore.GetTrailerFileID() error = %v", err)
+			return nil
+		}
+		assert.Equal(t, &fileID, got)
+
+		count, err := qb.CountByTrailerFileID(ctx, fileID)
+		if err != nil {
+			t.Errorf("SceneStore.CountByTrailerFileID() error = %v", err)
+			return nil
+		}
+		assert.Equal(t, 1, count)
+
+		// a file can only be the trailer of a single scene
+		if err := qb.UpdateTrailerFile(ctx, sceneIDs[sceneIdxWithMarkerAndTag], &fileID); err == nil {
+			t.Errorf("SceneStore.UpdateTrailerFile() expected error attaching trailer twice")
+		}
+
+		if err := qb.UpdateTrailerFile(ctx, sceneID, nil); err != nil {
+			t.Errorf("SceneStore.UpdateTrailerFile() error = %v", err)
+			return nil
+		}
+
+		got, err = qb.GetTrailerFileID(ctx, sceneID)
+		if err != nil {
+			t.Errorf("SceneStore.GetTrailerFileID() error = %v", err)
+			return nil
+		}
+		assert.Nil(t, got)
+
+		return nil
+	})
+}
+
 func TestSceneStore_IncrementWatchCount(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -4495,3 +4659,304 @@ func TestSceneStore_SaveActivity(t *testing.T) {
 
 // TODO Count
 // TODO SizeCount
+
+func TestSceneQueryContentWarnings(t *testing.T) {
+	if err := withRollbackTxn(func(ctx context.Context) error {
+		qb := db.Scene
+		tqb := sqlite.TagReaderWriter
+
+		warning, err := tqb.Create(ctx, models.Tag{Name: "TestSceneQueryContentWarnings warning", ContentWarning: true})
+		if err != nil {
+			t.Fatalf("Error creating tag: %v", err)
+		}
+		child, err := tqb.Create(ctx, models.Tag{Name: "TestSceneQueryContentWarnings child"})
+		if err != nil {
+			t.Fatalf("Error creating tag: %v", err)
+		}
+		if err := tqb.UpdateParentTags(ctx, child.ID, []int{warning.ID}); err != nil {
+			t.Fatalf("Error updating parent tags: %v", err)
+		}
+
+		create := func(tagIDs []int) int {
+			s := &models.Scene{
+				Title:  "TestSceneQueryContentWarnings",
+				TagIDs: models.NewRelatedIDs(tagIDs),
+			}
+			if err := qb.Create(ctx, s, nil); err != nil {
+				t.Fatalf("Error creating scene: %v", err)
+			}
+			return s.ID
+		}
+
+		untagged := create([]int{})
+		tagged := create([]int{warning.ID})
+		childTagged := create([]int{child.ID})
+
+		queryIDs := func(ctx context.Context) []int {
+			var ret []int
+			title := &models.StringCriterionInput{
+				Value:    "TestSceneQueryContentWarnings",
+				Modifier: models.CriterionModifierEquals,
+			}
+			for _, s := range queryScene(ctx, t, qb, &models.SceneFilterType{Title: title}, nil) {
+				ret = append(ret, s.ID)
+			}
+			return ret
+		}
+
+		assert.ElementsMatch(t, []int{untagged, tagged, childTagged}, queryIDs(ctx))
+
+		hiddenCtx := models.HideContentWarnings(ctx)
+		assert.ElementsMatch(t, []int{untagged}, queryIDs(hiddenCtx))
+
+		// hidden scenes are treated as not found
+		_, err = qb.Find(hiddenCtx, childTagged)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+
+		tags, _, err := tqb.Query(hiddenCtx, &models.TagFilterType{
+			Name: &models.StringCriterionInput{
+				Value:    "TestSceneQueryContentWarnings",
+				Modifier: models.CriterionModifierIncludes,
+			},
+		}, nil)
+		assert.Nil(t, err)
+		assert.Len(t, tags, 0)
+
+		return nil
+	}); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestSceneQueryQuickHide(t *testing.T) {
+	if err := withRollbackTxn(func(ctx context.Context) error {
+		qb := db.Scene
+		tqb := sqlite.TagReaderWriter
+
+		safe, err := tqb.Create(ctx, models.Tag{Name: "TestSceneQueryQuickHide safe"})
+		if err != nil {
+			t.Fatalf("Error creating tag: %v", err)
+		}
+		child, err := tqb.Create(ctx, models.Tag{Name: "TestSceneQueryQuickHide child"})
+		if err != nil {
+			t.Fatalf("Error creating tag: %v", err)
+		}
+		if err := tqb.UpdateParentTags(ctx, child.ID, []int{safe.ID}); err != nil {
+			t.Fatalf("Error updating parent tags: %v", err)
+		}
+
+		create := func(tagIDs []int) int {
+			s := &models.Scene{
+				Title:  "TestSceneQueryQuickHide",
+				TagIDs: models.NewRelatedIDs(tagIDs),
+			}
+			if err := qb.Create(ctx, s, nil); err != nil {
+				t.Fatalf("Error creating scene: %v", err)
+			}
+			return s.ID
+		}
+
+		untagged := create([]int{})
+		tagged := create([]int{safe.ID})
+		childTagged := create([]int{child.ID})
+
+		queryIDs := func(ctx context.Context) []int {
+			var ret []int
+			title := &models.StringCriterionInput{
+				Value:    "TestSceneQueryQuickHide",
+				Modifier: models.CriterionModifierEquals,
+			}
+			for _, s := range queryScene(ctx, t, qb, &models.SceneFilterType{Title: title}, nil) {
+				ret = append(ret, s.ID)
+			}
+			return ret
+		}
+
+		assert.ElementsMatch(t, []int{untagged, tagged, childTagged}, queryIDs(ctx))
+
+		hiddenCtx := models.QuickHide(ctx, []int{safe.ID})
+		assert.ElementsMatch(t, []int{tagged, childTagged}, queryIDs(hiddenCtx))
+
+		// hidden scenes are treated as not found
+		_, err = qb.Find(hiddenCtx, untagged)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+
+		// everything is hidden without safe tags
+		assert.Empty(t, queryIDs(models.QuickHide(ctx, nil)))
+
+		return nil
+	}); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestSceneQueryContentRestriction(t *testing.T) {
+	if err := withRollbackTxn(func(ctx context.Context) error {
+		qb := db.Scene
+		tqb := sqlite.TagReaderWriter
+		sqb := sqlite.StudioReaderWriter
+
+		restricted, err := tqb.Create(ctx, models.Tag{Name: "TestSceneQueryContentRestriction restricted"})
+		if err != nil {
+			t.Fatalf("Error creating tag: %v", err)
+		}
+		child, err := tqb.Create(ctx, models.Tag{Name: "TestSceneQueryContentRestriction child"})
+		if err != nil {
+			t.Fatalf("Error creating tag: %v", err)
+		}
+		if err := tqb.UpdateParentTags(ctx, child.ID, []int{restricted.ID}); err != nil {
+			t.Fatalf("Error updating parent tags: %v", err)
+		}
+
+		studio, err := createStudio(ctx, sqb, "TestSceneQueryContentRestriction studio", nil)
+		if err != nil {
+			t.Fatalf("Error creating studio: %v", err)
+		}
+		parentID := int64(studio.ID)
+		childStudio, err := createStudio(ctx, sqb, "TestSceneQueryContentRestriction child studio", &parentID)
+		if err != nil {
+			t.Fatalf("Error creating studio: %v", err)
+		}
+
+		create := func(tagIDs []int, studioID *int) int {
+			s := &models.Scene{
+				Title:    "TestSceneQueryContentRestriction",
+				TagIDs:   models.NewRelatedIDs(tagIDs),
+				StudioID: studioID,
+			}
+			if err := qb.Create(ctx, s, nil); err != nil {
+				t.Fatalf("Error creating scene: %v", err)
+			}
+			return s.ID
+		}
+
+		untagged := create([]int{}, nil)
+		tagged := create([]int{restricted.ID}, nil)
+		childTagged := create([]int{child.ID}, nil)
+		ofStudio := create([]int{}, &studio.ID)
+		ofChildStudio := create([]int{}, &childStudio.ID)
+
+		queryIDs := func(ctx context.Context) []int {
+			var ret []int
+			title := &models.StringCriterionInput{
+				Value:    "TestSceneQueryContentRestriction",
+				Modifier: models.CriterionModifierEquals,
+			}
+			for _, s := range queryScene(ctx, t, qb, &models.SceneFilterType{Title: title}, nil) {
+				ret = append(ret, s.ID)
+			}
+			return ret
+		}
+
+		assert.ElementsMatch(t, []int{untagged, tagged, childTagged, ofStudio, ofChildStudio}, queryIDs(ctx))
+
+		tagCtx := models.RestrictContent(ctx, models.ContentRestriction{TagIDs: []int{restricted.ID}})
+		assert.ElementsMatch(t, []int{untagged, ofStudio, ofChildStudio}, queryIDs(tagCtx))
+
+		studioCtx := models.RestrictContent(ctx, models.ContentRestriction{StudioIDs: []int{studio.ID}})
+		assert.ElementsMatch(t, []int{untagged, tagged, childTagged}, queryIDs(studioCtx))
+
+		// restrictions are combined
+		bothCtx := models.RestrictContent(tagCtx, models.ContentRestriction{StudioIDs: []int{studio.ID}})
+		assert.ElementsMatch(t, []int{untagged}, queryIDs(bothCtx))
+
+		// restricted scenes are treated as not found
+		_, err = qb.Find(bothCtx, ofChildStudio)
+		assert.ErrorIs(t, err, sql.ErrNoRows)
+
+		// markers of restricted scenes are hidden
+		mqb := sqlite.SceneMarkerReaderWriter
+		marker, err := mqb.Create(ctx, models.SceneMarker{
+			Title:        "TestSceneQueryContentRestriction",
+			SceneID:      sql.NullInt64{Int64: int64(ofStudio), Valid: true},
+			PrimaryTagID: tagIDs[tagIdxWithMarkers],
+		})
+		if err != nil {
+			t.Fatalf("Error creating scene marker: %v", err)
+		}
+		markerFilter := &models.FindFilterType{Q: &marker.Title}
+		markers, _, err := mqb.Query(ctx, nil, markerFilter)
+		assert.Nil(t, err)
+		assert.Len(t, markers, 1)
+		markers, _, err = mqb.Query(studioCtx, nil, markerFilter)
+		assert.Nil(t, err)
+		assert.Len(t, markers, 0)
+
+		return nil
+	}); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestSceneQueryLinkedOnly(t *testing.T) {
+	if err := withRollbackTxn(func(ctx context.Context) error {
+		qb := db.Scene
+
+		linked := &models.Scene{
+			Title: "TestSceneQueryLinkedOnly",
+			URL:   "https://example.com/scene",
+		}
+		if err := qb.Create(ctx, linked, nil); err != nil {
+			t.Fatalf("Error creating scene: %v", err)
+		}
+
+		linkedOnly := true
+		scenes := queryScene(ctx, t, qb, &models.SceneFilterType{LinkedOnly: &linkedOnly}, nil)
+		ids := make([]int, len(scenes))
+		for i, s := range scenes {
+			assert.Nil(t, s.PrimaryFileID)
+			ids[i] = s.ID
+		}
+		assert.Contains(t, ids, linked.ID)
+
+		linkedOnly = false
+		scenes = queryScene(ctx, t, qb, &models.SceneFilterType{LinkedOnly: &linkedOnly}, nil)
+		assert.NotEmpty(t, scenes)
+		for _, s := range scenes {
+			assert.NotNil(t, s.PrimaryFileID)
+			assert.NotEqual(t, linked.ID, s.ID)
+		}
+
+		return nil
+	}); err != nil {
+		t.Error(err.Error())
+	}
+}
+
+func TestSceneQueryInbox(t *testing.T) {
+	if err := withRollbackTxn(func(ctx context.Context) error {
+		qb := db.Scene
+
+		inboxScene := &models.Scene{
+			Title: "TestSceneQueryInbox",
+			Inbox: true,
+		}
+		if err := qb.Create(ctx, inboxScene, nil); err != nil {
+			t.Fatalf("Error creating scene: %v", err)
+		}
+
+		inbox := true
+		scenes := queryScene(ctx, t, qb, &models.SceneFilterType{Inbox: &inbox}, nil)
+		if assert.Len(t, scenes, 1) {
+			assert.Equal(t, inboxScene.ID, scenes[0].ID)
+			assert.True(t, scenes[0].Inbox)
+		}
+
+		count, err := scene.CountInbox(ctx, qb)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		partial := models.NewScenePartial()
+		partial.Inbox = models.NewOptionalBool(false)
+		if _, err := qb.UpdatePartial(ctx, inboxScene.ID, partial); err != nil {
+			t.Fatalf("Error updating scene: %v", err)
+		}
+
+		scenes = queryScene(ctx, t, qb, &models.SceneFilterType{Inbox: &inbox}, nil)
+		assert.Empty(t, scenes)
+
+		return nil
+	}); err != nil {
+		t.Error(err.Error())
+	}
+}
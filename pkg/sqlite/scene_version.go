@@ -0,0 +1,66 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	sceneVersionTable         = "scene_versions"
+	scenePrimaryVersionColumn = "primary_scene_id"
+)
+
+type sceneVersionQueryBuilder struct {
+	repository
+}
+
+var SceneVersionReaderWriter = &sceneVersionQueryBuilder{
+	repository{
+		tableName: sceneVersionTable,
+		idColumn:  sceneIDColumn,
+	},
+}
+
+func (qb *sceneVersionQueryBuilder) FindPrimaryID(ctx context.Context, sceneID int) (*int, error) {
+	query := fmt.Sprintf("SELECT %s AS id FROM %s WHERE %s = ?", scenePrimaryVersionColumn, sceneVersionTable, sceneIDColumn)
+	ids, err := qb.runIdsQuery(ctx, query, []interface{}{sceneID})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	return &ids[0], nil
+}
+
+func (qb *sceneVersionQueryBuilder) FindVersionIDs(ctx context.Context, primaryID int) ([]int, error) {
+	query := fmt.Sprintf("SELECT %[1]s AS id FROM %[2]s WHERE %[3]s = ? ORDER BY %[1]s", sceneIDColumn, sceneVersionTable, scenePrimaryVersionColumn)
+	return qb.runIdsQuery(ctx, query, []interface{}{primaryID})
+}
+
+func (qb *sceneVersionQueryBuilder) Stack(ctx context.Context, primaryID int, sceneIDs []int) error {
+	stmt := fmt.Sprintf("INSERT INTO %[1]s (%[2]s, %[3]s) VALUES (?, ?) ON CONFLICT (%[2]s) DO UPDATE SET %[3]s = excluded.%[3]s", sceneVersionTable, sceneIDColumn, scenePrimaryVersionColumn)
+	for _, id := range sceneIDs {
+		if id == primaryID {
+			return fmt.Errorf("scene %d cannot be a version of itself", id)
+		}
+
+		if _, err := qb.tx.Exec(ctx, stmt, id, primaryID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (qb *sceneVersionQueryBuilder) Unstack(ctx context.Context, sceneIDs []int) error {
+	return qb.destroy(ctx, sceneIDs)
+}
+
+func (qb *sceneVersionQueryBuilder) DestroyStack(ctx context.Context, primaryID int) error {
+	stmt := fmt.Sprintf("DELETE FROM %s WHERE %s = ?", sceneVersionTable, scenePrimaryVersionColumn)
+	_, err := qb.tx.Exec(ctx, stmt, primaryID)
+	return err
+}
@@ -0,0 +1,85 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene"
+	"github.com/stashapp/stash/pkg/sqlite"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSceneVersions(t *testing.T) {
+	qb := sqlite.SceneVersionReaderWriter
+
+	primaryID := sceneIDs[sceneIdxWithMovie]
+	versionID := sceneIDs[sceneIdxWithGallery]
+	otherPrimaryID := sceneIDs[sceneIdxWithPerformer]
+	otherVersionID := sceneIDs[sceneIdx1WithPerformer]
+
+	withRollbackTxn(func(ctx context.Context) error {
+		stackIDs := func(sceneID int) []int {
+			ids, err := scene.StackIDs(ctx, qb, sceneID)
+			if err != nil {
+				t.Errorf("Error finding stack: %s", err.Error())
+			}
+			return ids
+		}
+
+		if err := scene.StackVersions(ctx, qb, otherPrimaryID, []int{otherVersionID}); err != nil {
+			t.Errorf("Error stacking versions: %s", err.Error())
+			return nil
+		}
+
+		// the versions of the other stack are moved along with its primary
+		if err := scene.StackVersions(ctx, qb, primaryID, []int{versionID, otherPrimaryID}); err != nil {
+			t.Errorf("Error stacking versions: %s", err.Error())
+			return nil
+		}
+
+		want := []int{primaryID, versionID, otherPrimaryID, otherVersionID}
+		assert.ElementsMatch(t, want, stackIDs(primaryID))
+		assert.Equal(t, primaryID, stackIDs(otherVersionID)[0])
+
+		// versions cannot be the primary of another stack
+		assert.Error(t, scene.StackVersions(ctx, qb, versionID, []int{sceneIDs[sceneIdx2WithPerformer]}))
+
+		if err := scene.SetPrimaryVersion(ctx, qb, versionID); err != nil {
+			t.Errorf("Error setting primary version: %s", err.Error())
+			return nil
+		}
+
+		stack := stackIDs(primaryID)
+		if assert.Len(t, stack, 4) {
+			assert.Equal(t, versionID, stack[0])
+		}
+
+		if err := scene.UnstackVersions(ctx, qb, []int{otherVersionID}); err != nil {
+			t.Errorf("Error unstacking versions: %s", err.Error())
+			return nil
+		}
+		assert.Nil(t, stackIDs(otherVersionID))
+		assert.Len(t, stackIDs(versionID), 3)
+
+		isVersion := true
+		scenes := queryScene(ctx, t, db.Scene, &models.SceneFilterType{IsVersion: &isVersion}, nil)
+		var ids []int
+		for _, s := range scenes {
+			ids = append(ids, s.ID)
+		}
+		assert.ElementsMatch(t, []int{primaryID, otherPrimaryID}, ids)
+
+		// unstacking the primary removes the whole stack
+		if err := scene.UnstackVersions(ctx, qb, []int{versionID}); err != nil {
+			t.Errorf("Error unstacking versions: %s", err.Error())
+			return nil
+		}
+		assert.Nil(t, stackIDs(primaryID))
+
+		return nil
+	})
+}
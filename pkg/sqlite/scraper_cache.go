@@ -0,0 +1,65 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const scraperCacheTable = "scraper_cache"
+
+type scraperCacheQueryBuilder struct {
+	repository
+}
+
+var ScraperCacheReaderWriter = &scraperCacheQueryBuilder{
+	repository{
+		tableName: scraperCacheTable,
+		idColumn:  "key",
+	},
+}
+
+func (qb *scraperCacheQueryBuilder) Find(ctx context.Context, scraperID string, key string, t time.Time) (*models.ScraperCacheEntry, error) {
+	query := selectAll(scraperCacheTable) + "WHERE scraper_id = ? AND key = ? AND created_at > ?"
+
+	var entries models.ScraperCacheEntries
+	if err := qb.query(ctx, query, []interface{}{scraperID, key, t}, &entries); err != nil {
+		return nil, err
+	}
+
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	return entries[0], nil
+}
+
+func (qb *scraperCacheQueryBuilder) Set(ctx context.Context, entry models.ScraperCacheEntry) error {
+	stmt := fmt.Sprintf("INSERT INTO %s (scraper_id, key, data, created_at) VALUES (?, ?, ?, ?) ON CONFLICT (scraper_id, key) DO UPDATE SET data = excluded.data, created_at = excluded.created_at", scraperCacheTable)
+	_, err := qb.tx.Exec(ctx, stmt, entry.ScraperID, entry.Key, entry.Data, entry.CreatedAt)
+	return err
+}
+
+func (qb *scraperCacheQueryBuilder) DestroyCreatedBefore(ctx context.Context, t time.Time) (int, error) {
+	return qb.exec(ctx, fmt.Sprintf("DELETE FROM %s WHERE created_at < ?", scraperCacheTable), t)
+}
+
+func (qb *scraperCacheQueryBuilder) DestroyByScraperID(ctx context.Context, scraperID *string) (int, error) {
+	if scraperID == nil {
+		return qb.exec(ctx, fmt.Sprintf("DELETE FROM %s", scraperCacheTable))
+	}
+
+	return qb.exec(ctx, fmt.Sprintf("DELETE FROM %s WHERE scraper_id = ?", scraperCacheTable), *scraperID)
+}
+
+func (qb *scraperCacheQueryBuilder) exec(ctx context.Context, stmt string, args ...interface{}) (int, error) {
+	result, err := qb.tx.Exec(ctx, stmt, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := result.RowsAffected()
+	return int(n), err
+}
@@ -0,0 +1,281 @@
+package sqlite
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const searchIndexTable = "search_index"
+
+// searchTypes are the types of the objects in the search index, in the order
+// of their type number. The docid of an object in the index is
+// id * len(searchTypes) + type.
+var searchTypes = []models.SearchResultType{
+	models.SearchResultTypeScene,
+	models.SearchResultTypePerformer,
+	models.SearchResultTypeTag,
+	models.SearchResultTypeSceneMarker,
+}
+
+// searchColumns are the fields indexed by the columns of the search index,
+// in column order.
+var searchColumns = []models.SearchField{
+	models.SearchFieldTitle,
+	models.SearchFieldAliases,
+	models.SearchFieldPath,
+	models.SearchFieldDetails,
+}
+
+// weights of the columns of the search index when ranking results
+var searchColumnWeights = []float64{4, 2, 1.5, 1}
+
+type searchQueryBuilder struct {
+	repository
+}
+
+var SearchReaderWriter = &searchQueryBuilder{
+	repository{
+		tableName: searchIndexTable,
+		idColumn:  "docid",
+	},
+}
+
+func (qb *searchQueryBuilder) Search(ctx context.Context, term string, types []models.SearchResultType, limit int) ([]*models.SearchResult, error) {
+	match := searchMatchExpression(term)
+	if match == "" {
+		return nil, nil
+	}
+
+	whereClauses := []string{searchIndexTable + " MATCH ?"}
+	args := []interface{}{match}
+
+	if len(types) > 0 {
+		var typeNums []string
+		for _, t := range types {
+			if n := searchTypeNum(t); n >= 0 {
+				typeNums = append(typeNums, fmt.Sprint(n))
+			}
+		}
+		whereClauses = append(whereClauses, fmt.Sprintf("docid %% %d IN (%s)", len(searchTypes), strings.Join(typeNums, ", ")))
+	}
+
+	whereClauses = append(whereClauses, searchHiddenClauses(ctx)...)
+
+	query := fmt.Sprintf(
+		"SELECT docid, title, searchRank(matchinfo(%[1]s, 'pcnalx')) AS score, matchinfo(%[1]s, 'pcx') AS hits FROM %[1]s WHERE %[2]s ORDER BY score DESC, docid ASC LIMIT ?",
+		searchIndexTable, strings.Join(whereClauses, " AND "),
+	)
+	args = append(args, limit)
+
+	var ret []*models.SearchResult
+	if err := qb.queryFunc(ctx, query, args, false, func(rows *sqlx.Rows) error {
+		var (
+			docid int
+			hits  []byte
+			r     models.SearchResult
+		)
+		if err := rows.Scan(&docid, &r.Title, &r.Score, &hits); err != nil {
+			return err
+		}
+
+		var err error
+		r.MatchedFields, err = searchMatchedFields(hits)
+		if err != nil {
+			return err
+		}
+
+		r.Type = searchTypes[docid%len(searchTypes)]
+		r.ID = docid / len(searchTypes)
+		ret = append(ret, &r)
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("searching for %q: %w", term, err)
+	}
+
+	return ret, nil
+}
+
+// searchTypeNum returns the type number of the search result type, or -1 if
+// it is not indexed.
+func searchTypeNum(t models.SearchResultType) int {
+	for i, st := range searchTypes {
+		if t == st {
+			return i
+		}
+	}
+	return -1
+}
+
+// searchTypeClause returns a where clause excluding the objects of the type
+// from the search results, unless they are in the rows of table matching the
+// where clauses.
+func searchTypeClause(t models.SearchResultType, table string, whereClauses []string) string {
+	return fmt.Sprintf("(docid %% %[1]d != %[2]d OR docid / %[1]d IN (SELECT %[3]s.id FROM %[3]s WHERE %[4]s))",
+		len(searchTypes), searchTypeNum(t), table, strings.Join(whereClauses, " AND "))
+}
+
+// searchHiddenClauses returns the where clauses excluding the scenes, tags
+// and scene markers that are hidden in the context from the search results,
+// using the same rules as the queries of these objects.
+func searchHiddenClauses(ctx context.Context) []string {
+	var ret []string
+
+	var scenes queryBuilder
+	hideContentWarnings(ctx, &scenes, sceneTable, scenesTagsTable, sceneIDColumn)
+	quickHide(ctx, &scenes, sceneTable, scenesTagsTable, sceneIDColumn)
+	restrictContent(ctx, &scenes, sceneTable, scenesTagsTable, sceneIDColumn)
+	if len(scenes.whereClauses) > 0 {
+		ret = append(ret, searchTypeClause(models.SearchResultTypeScene, sceneTable, scenes.whereClauses))
+	}
+
+	var tags queryBuilder
+	hideContentWarningTags(ctx, &tags)
+	if len(tags.whereClauses) > 0 {
+		ret = append(ret, searchTypeClause(models.SearchResultTypeTag, tagTable, tags.whereClauses))
+	}
+
+	var markers queryBuilder
+	hideContentWarningMarkers(ctx, &markers)
+	quickHideMarkers(ctx, &markers)
+	restrictContentMarkers(ctx, &markers)
+	if len(markers.whereClauses) > 0 {
+		ret = append(ret, searchTypeClause(models.SearchResultTypeSceneMarker, sceneMarkerTable, markers.whereClauses))
+	}
+
+	return ret
+}
+
+// searchMatchExpression returns the full-text query matching objects which
+// contain each word of the term, or a word starting with it. Punctuation in
+// the term is ignored, so that it cannot be interpreted as a query operator.
+func searchMatchExpression(term string) string {
+	words := strings.FieldsFunc(term, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	for i, w := range words {
+		// quoted so that words such as OR are not interpreted as operators
+		words[i] = `"` + w + `*"`
+	}
+
+	return strings.Join(words, " ")
+}
+
+// searchRankFn returns the Okapi BM25 relevance of a search result, using the
+// output of the matchinfo function with the pcnalx format. Higher is more
+// relevant.
+func searchRankFn(matchinfo []byte) (float64, error) {
+	const (
+		k1 = 1.2
+		b  = 0.75
+	)
+
+	info, err := decodeMatchinfo(matchinfo)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(info) < 3 {
+		return 0, fmt.Errorf("invalid matchinfo length %d", len(matchinfo))
+	}
+
+	phrases := int(info[0])
+	columns := int(info[1])
+	rows := float64(info[2])
+	avgLengths := info[3 : 3+columns]
+	lengths := info[3+columns : 3+2*columns]
+	hits := info[3+2*columns:]
+
+	if len(hits) < 3*phrases*columns {
+		return 0, fmt.Errorf("invalid matchinfo length %d", len(matchinfo))
+	}
+
+	var ret float64
+	for p := 0; p < phrases; p++ {
+		for c := 0; c < columns; c++ {
+			x := 3 * (c + p*columns)
+			tf := float64(hits[x])
+			if tf == 0 {
+				continue
+			}
+
+			docsWithHits := float64(hits[x+2])
+			idf := math.Log((rows - docsWithHits + 0.5) / (docsWithHits + 0.5))
+			if idf <= 0 {
+				// common terms should still contribute to the rank
+				idf = 1e-6
+			}
+
+			lengthRatio := 1.0
+			if avgLengths[c] > 0 {
+				lengthRatio = float64(lengths[c]) / float64(avgLengths[c])
+			}
+
+			weight := 1.0
+			if c < len(searchColumnWeights) {
+				weight = searchColumnWeights[c]
+			}
+
+			ret += weight * idf * (tf * (k1 + 1)) / (tf + k1*(1-b+b*lengthRatio))
+		}
+	}
+
+	return ret, nil
+}
+
+// decodeMatchinfo returns the values of the output of the matchinfo function.
+func decodeMatchinfo(matchinfo []byte) ([]uint32, error) {
+	if len(matchinfo)%4 != 0 {
+		return nil, fmt.Errorf("invalid matchinfo length %d", len(matchinfo))
+	}
+
+	ret := make([]uint32, len(matchinfo)/4)
+	for i := range ret {
+		// matchinfo is in native byte order. All supported platforms are
+		// little-endian.
+		ret[i] = binary.LittleEndian.Uint32(matchinfo[i*4:])
+	}
+
+	return ret, nil
+}
+
+// searchMatchedFields returns the fields of the columns containing any of the
+// phrases of a search, using the output of the matchinfo function with the
+// pcx format.
+func searchMatchedFields(matchinfo []byte) ([]models.SearchField, error) {
+	info, err := decodeMatchinfo(matchinfo)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(info) < 2 {
+		return nil, fmt.Errorf("invalid matchinfo length %d", len(matchinfo))
+	}
+
+	phrases := int(info[0])
+	columns := int(info[1])
+	hits := info[2:]
+
+	if len(hits) < 3*phrases*columns {
+		return nil, fmt.Errorf("invalid matchinfo length %d", len(matchinfo))
+	}
+
+	ret := []models.SearchField{}
+	for c := 0; c < columns && c < len(searchColumns); c++ {
+		for p := 0; p < phrases; p++ {
+			if hits[3*(c+p*columns)] > 0 {
+				ret = append(ret, searchColumns[c])
+				break
+			}
+		}
+	}
+
+	return ret, nil
+}
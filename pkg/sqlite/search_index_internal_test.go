@@ -0,0 +1,108 @@
+package sqlite
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchMatchExpression(t *testing.T) {
+	tests := []struct {
+		term string
+		want string
+	}{
+		{"", ""},
+		{"  ", ""},
+		{"Jane Doe", `"Jane*" "Doe*"`},
+		{"scene_1_title", `"scene*" "1*" "title*"`},
+		{`cats OR "dogs" -NEAR*`, `"cats*" "OR*" "dogs*" "NEAR*"`},
+		{"Café", `"Café*"`},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, searchMatchExpression(tt.term), tt.term)
+	}
+}
+
+func encodeMatchinfo(values ...uint32) []byte {
+	ret := make([]byte, len(values)*4)
+	for i, v := range values {
+		binary.LittleEndian.PutUint32(ret[i*4:], v)
+	}
+	return ret
+}
+
+func TestSearchRankFn(t *testing.T) {
+	// one phrase, four columns, ten rows, average lengths and row lengths
+	// of 2 tokens per column
+	header := []uint32{1, 4, 10, 2, 2, 2, 2, 2, 2, 2, 2}
+	rank := func(hits ...uint32) float64 {
+		ret, err := searchRankFn(encodeMatchinfo(append(header, hits...)...))
+		if err != nil {
+			t.Fatalf("searchRankFn() error = %v", err)
+		}
+		return ret
+	}
+
+	titleHit := rank(1, 1, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0)
+	aliasHit := rank(0, 0, 0, 1, 1, 1, 0, 0, 0, 0, 0, 0)
+	pathHit := rank(0, 0, 0, 0, 0, 0, 1, 1, 1, 0, 0, 0)
+	bodyHit := rank(0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 1, 1)
+	commonBodyHit := rank(0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 9, 9)
+
+	assert.Greater(t, titleHit, aliasHit)
+	assert.Greater(t, aliasHit, pathHit)
+	assert.Greater(t, pathHit, bodyHit)
+	assert.Greater(t, bodyHit, commonBodyHit)
+	assert.Greater(t, commonBodyHit, 0.0)
+	assert.Equal(t, 0.0, rank(0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0))
+
+	_, err := searchRankFn([]byte{1, 2, 3})
+	assert.Error(t, err)
+	_, err = searchRankFn(encodeMatchinfo(header...))
+	assert.Error(t, err)
+}
+
+func TestSearchMatchedFields(t *testing.T) {
+	tests := []struct {
+		name      string
+		matchinfo []uint32
+		want      []models.SearchField
+	}{
+		{
+			"no hits",
+			[]uint32{1, 4, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+			[]models.SearchField{},
+		},
+		{
+			"title and path",
+			[]uint32{1, 4, 1, 1, 1, 0, 0, 0, 2, 3, 3, 0, 0, 0},
+			[]models.SearchField{models.SearchFieldTitle, models.SearchFieldPath},
+		},
+		{
+			// the first phrase matches the aliases, the second the details
+			"two phrases",
+			[]uint32{
+				2, 4,
+				0, 0, 0, 1, 1, 1, 0, 0, 0, 0, 0, 0,
+				0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 1, 1,
+			},
+			[]models.SearchField{models.SearchFieldAliases, models.SearchFieldDetails},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := searchMatchedFields(encodeMatchinfo(tt.matchinfo...))
+			if err != nil {
+				t.Fatalf("searchMatchedFields() error = %v", err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := searchMatchedFields(encodeMatchinfo(1, 4, 0, 0, 0))
+	assert.Error(t, err)
+}
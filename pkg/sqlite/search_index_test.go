@@ -0,0 +1,188 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/file"
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sqlite"
+	"github.com/stretchr/testify/assert"
+)
+
+func searchResultIDs(results []*models.SearchResult, t models.SearchResultType) []int {
+	var ret []int
+	for _, r := range results {
+		if r.Type == t {
+			ret = append(ret, r.ID)
+		}
+	}
+	return ret
+}
+
+func TestSearch(t *testing.T) {
+	withTxn(func(ctx context.Context) error {
+		qb := sqlite.SearchReaderWriter
+
+		sceneIdx := sceneIdxWithMarkers
+		results, err := qb.Search(ctx, getSceneStringValue(sceneIdx, titleField), nil, 100)
+		if err != nil {
+			t.Errorf("Error searching: %s", err.Error())
+			return nil
+		}
+		assert.Contains(t, searchResultIDs(results, models.SearchResultTypeScene), sceneIDs[sceneIdx])
+
+		tagIdx := tagIdxWithScene
+		results, err = qb.Search(ctx, getTagStringValue(tagIdx, "Name"), []models.SearchResultType{models.SearchResultTypeTag}, 100)
+		if err != nil {
+			t.Errorf("Error searching: %s", err.Error())
+			return nil
+		}
+		assert.Contains(t, searchResultIDs(results, models.SearchResultTypeTag), tagIDs[tagIdx])
+		assert.Empty(t, searchResultIDs(results, models.SearchResultTypeScene))
+
+		// punctuation is not interpreted as query syntax
+		results, err = qb.Search(ctx, `" OR -`, nil, 100)
+		if err != nil {
+			t.Errorf("Error searching: %s", err.Error())
+			return nil
+		}
+		assert.Empty(t, results)
+
+		return nil
+	})
+}
+
+func TestSearchRank(t *testing.T) {
+	withRollbackTxn(func(ctx context.Context) error {
+		sqb := db.Scene
+		inDetails := &models.Scene{
+			Title:   "details match",
+			Details: "a zebrafish",
+		}
+		inTitle := &models.Scene{
+			Title: "Zebrafish",
+		}
+
+		for _, s := range []*models.Scene{inDetails, inTitle} {
+			if err := sqb.Create(ctx, s, nil); err != nil {
+				t.Errorf("Error creating scene: %s", err.Error())
+				return nil
+			}
+		}
+
+		results, err := sqlite.SearchReaderWriter.Search(ctx, "zebra", nil, 10)
+		if err != nil {
+			t.Errorf("Error searching: %s", err.Error())
+			return nil
+		}
+		assert.Equal(t, []int{inTitle.ID, inDetails.ID}, searchResultIDs(results, models.SearchResultTypeScene))
+
+		// the index is updated with the scene
+		inTitle.Title = "Giraffe"
+		if err := sqb.Update(ctx, inTitle); err != nil {
+			t.Errorf("Error updating scene: %s", err.Error())
+			return nil
+		}
+		if err := sqb.Destroy(ctx, inDetails.ID); err != nil {
+			t.Errorf("Error destroying scene: %s", err.Error())
+			return nil
+		}
+
+		results, err = sqlite.SearchReaderWriter.Search(ctx, "zebra", nil, 10)
+		if err != nil {
+			t.Errorf("Error searching: %s", err.Error())
+			return nil
+		}
+		assert.Empty(t, searchResultIDs(results, models.SearchResultTypeScene))
+
+		return nil
+	})
+}
+
+func TestSearchPath(t *testing.T) {
+	withRollbackTxn(func(ctx context.Context) error {
+		folder := &file.Folder{
+			Path: "/TestSearchPath/okapi",
+		}
+		if err := db.Folder.Create(ctx, folder); err != nil {
+			t.Errorf("Error creating folder: %s", err.Error())
+			return nil
+		}
+
+		f := &file.BaseFile{
+			Basename:       "lemur.mp4",
+			ParentFolderID: folder.ID,
+		}
+		if err := db.File.Create(ctx, f); err != nil {
+			t.Errorf("Error creating file: %s", err.Error())
+			return nil
+		}
+
+		s := &models.Scene{
+			Title: "TestSearchPath",
+		}
+		if err := db.Scene.Create(ctx, s, []file.ID{f.ID}); err != nil {
+			t.Errorf("Error creating scene: %s", err.Error())
+			return nil
+		}
+
+		search := func(ctx context.Context, term string) []*models.SearchResult {
+			results, err := sqlite.SearchReaderWriter.Search(ctx, term, []models.SearchResultType{models.SearchResultTypeScene}, 10)
+			if err != nil {
+				t.Errorf("Error searching: %s", err.Error())
+			}
+			return results
+		}
+
+		results := search(ctx, "okapi lemur")
+		if assert.Len(t, results, 1) {
+			assert.Equal(t, s.ID, results[0].ID)
+			assert.Equal(t, []models.SearchField{models.SearchFieldPath}, results[0].MatchedFields)
+		}
+
+		results = search(ctx, "TestSearchPath lemur")
+		if assert.Len(t, results, 1) {
+			assert.Equal(t, []models.SearchField{models.SearchFieldTitle, models.SearchFieldPath}, results[0].MatchedFields)
+		}
+
+		// the index is updated with the file
+		f.Basename = "tapir.mp4"
+		if err := db.File.Update(ctx, f); err != nil {
+			t.Errorf("Error updating file: %s", err.Error())
+			return nil
+		}
+
+		assert.Empty(t, search(ctx, "lemur"))
+		assert.Len(t, search(ctx, "tapir"), 1)
+
+		// and with the folder
+		folder.Path = "/TestSearchPath/zebu"
+		if err := db.Folder.Update(ctx, folder); err != nil {
+			t.Errorf("Error updating folder: %s", err.Error())
+			return nil
+		}
+
+		assert.Empty(t, search(ctx, "okapi"))
+		assert.Len(t, search(ctx, "zebu tapir"), 1)
+
+		// hidden scenes are not returned
+		restricted := models.RestrictContent(ctx, models.ContentRestriction{StudioIDs: []int{studioIDs[studioIdxWithScene]}})
+		assert.Len(t, search(restricted, "tapir"), 1)
+
+		if _, err := db.Scene.UpdatePartial(ctx, s.ID, models.ScenePartial{
+			StudioID: models.NewOptionalInt(studioIDs[studioIdxWithScene]),
+		}); err != nil {
+			t.Errorf("Error updating scene: %s", err.Error())
+			return nil
+		}
+
+		assert.Len(t, search(ctx, "tapir"), 1)
+		assert.Empty(t, search(restricted, "tapir"))
+
+		return nil
+	})
+}
@@ -95,6 +95,22 @@ func getSort(sort string, direction string, tableName string) string {
 	}
 }
 
+// getFavoritesFirstSort returns the sort clause with favorites of the table
+// sorted first, if requested by the find filter.
+func getFavoritesFirstSort(findFilter *models.FindFilterType, tableName string, sort string) string {
+	if findFilter == nil || findFilter.FavoritesFirst == nil || !*findFilter.FavoritesFirst {
+		return sort
+	}
+
+	const orderBy = " ORDER BY "
+	favorite := getColumn(tableName, "favorite") + " DESC"
+	if sort == "" {
+		return orderBy + favorite
+	}
+
+	return orderBy + favorite + ", " + strings.TrimPrefix(sort, orderBy)
+}
+
 func getRandomSort(tableName string, direction string, seed float64) string {
 	// https://stackoverflow.com/a/24511461
 	colName := getColumn(tableName, "id")
@@ -0,0 +1,48 @@
+package sqlite
+
+import (
+	"context"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const stashBoxDraftTable = "stash_box_drafts"
+
+type stashBoxDraftQueryBuilder struct {
+	repository
+}
+
+var StashBoxDraftReaderWriter = &stashBoxDraftQueryBuilder{
+	repository{
+		tableName: stashBoxDraftTable,
+		idColumn:  idColumn,
+	},
+}
+
+func (qb *stashBoxDraftQueryBuilder) Create(ctx context.Context, newObject models.StashBoxDraft) (*models.StashBoxDraft, error) {
+	var ret models.StashBoxDraft
+	if err := qb.insertObject(ctx, newObject, &ret); err != nil {
+		return nil, err
+	}
+
+	return &ret, nil
+}
+
+func (qb *stashBoxDraftQueryBuilder) FindBySceneID(ctx context.Context, sceneID int) ([]*models.StashBoxDraft, error) {
+	return qb.findBy(ctx, "scene_id", sceneID)
+}
+
+func (qb *stashBoxDraftQueryBuilder) FindByPerformerID(ctx context.Context, performerID int) ([]*models.StashBoxDraft, error) {
+	return qb.findBy(ctx, "performer_id", performerID)
+}
+
+func (qb *stashBoxDraftQueryBuilder) findBy(ctx context.Context, column string, id int) ([]*models.StashBoxDraft, error) {
+	query := selectAll(stashBoxDraftTable) + "WHERE " + column + " = ? ORDER BY created_at DESC, id DESC"
+
+	var ret models.StashBoxDrafts
+	if err := qb.query(ctx, query, []interface{}{id}, &ret); err != nil {
+		return nil, err
+	}
+
+	return []*models.StashBoxDraft(ret), nil
+}
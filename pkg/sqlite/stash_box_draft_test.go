@@ -0,0 +1,75 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sqlite"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStashBoxDraft(t *testing.T) {
+	qb := sqlite.StashBoxDraftReaderWriter
+	now := time.Now()
+
+	sceneID := sceneIDs[sceneIdxWithPerformer]
+	performerID := performerIDs[performerIdxWithScene]
+
+	withRollbackTxn(func(ctx context.Context) error {
+		create := func(draftID string, sceneID *int, performerID *int, createdAt time.Time) *models.StashBoxDraft {
+			ret, err := qb.Create(ctx, models.StashBoxDraft{
+				Endpoint:    "https://stashdb.org/graphql",
+				DraftID:     draftID,
+				SceneID:     sceneID,
+				PerformerID: performerID,
+				CreatedAt:   createdAt,
+			})
+			if err != nil {
+				t.Errorf("Error creating stash-box draft: %s", err.Error())
+			}
+			return ret
+		}
+
+		old := create("old", &sceneID, nil, now.AddDate(0, 0, -1))
+		recent := create("recent", &sceneID, nil, now)
+		performerDraft := create("performer", nil, &performerID, now)
+		if old == nil || recent == nil || performerDraft == nil {
+			return nil
+		}
+
+		drafts, err := qb.FindBySceneID(ctx, sceneID)
+		if err != nil {
+			t.Errorf("Error finding stash-box drafts: %s", err.Error())
+			return nil
+		}
+		if assert.Len(t, drafts, 2) {
+			assert.Equal(t, "recent", drafts[0].DraftID)
+			assert.Equal(t, "old", drafts[1].DraftID)
+			assert.Nil(t, drafts[0].PerformerID)
+		}
+
+		drafts, err = qb.FindByPerformerID(ctx, performerID)
+		if err != nil {
+			t.Errorf("Error finding stash-box drafts: %s", err.Error())
+			return nil
+		}
+		if assert.Len(t, drafts, 1) {
+			assert.Equal(t, performerDraft.ID, drafts[0].ID)
+			assert.Equal(t, "https://stashdb.org/graphql", drafts[0].Endpoint)
+		}
+
+		drafts, err = qb.FindBySceneID(ctx, sceneIDs[sceneIdxWithStudio])
+		if err != nil {
+			t.Errorf("Error finding stash-box drafts: %s", err.Error())
+			return nil
+		}
+		assert.Len(t, drafts, 0)
+
+		return nil
+	})
+}
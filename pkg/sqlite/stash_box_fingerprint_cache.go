@@ -0,0 +1,65 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const stashBoxFingerprintCacheTable = "stash_box_fingerprint_cache"
+
+type stashBoxFingerprintCacheQueryBuilder struct {
+	repository
+}
+
+var StashBoxFingerprintCacheReaderWriter = &stashBoxFingerprintCacheQueryBuilder{
+	repository{
+		tableName: stashBoxFingerprintCacheTable,
+		idColumn:  "key",
+	},
+}
+
+func (qb *stashBoxFingerprintCacheQueryBuilder) FindMany(ctx context.Context, endpoint string, keys []string, t time.Time) ([]*models.StashBoxFingerprintCacheEntry, error) {
+	var ret []*models.StashBoxFingerprintCacheEntry
+
+	const batchSize = 500
+	for i := 0; i < len(keys); i += batchSize {
+		end := i + batchSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		query := selectAll(stashBoxFingerprintCacheTable) + "WHERE endpoint = ? AND created_at > ? AND key IN " + getInBinding(end-i)
+		args := []interface{}{endpoint, t}
+		for _, key := range keys[i:end] {
+			args = append(args, key)
+		}
+
+		var entries models.StashBoxFingerprintCacheEntries
+		if err := qb.query(ctx, query, args, &entries); err != nil {
+			return nil, err
+		}
+
+		ret = append(ret, entries...)
+	}
+
+	return ret, nil
+}
+
+func (qb *stashBoxFingerprintCacheQueryBuilder) Set(ctx context.Context, entry models.StashBoxFingerprintCacheEntry) error {
+	stmt := fmt.Sprintf("INSERT INTO %s (endpoint, key, data, created_at) VALUES (?, ?, ?, ?) ON CONFLICT (endpoint, key) DO UPDATE SET data = excluded.data, created_at = excluded.created_at", stashBoxFingerprintCacheTable)
+	_, err := qb.tx.Exec(ctx, stmt, entry.Endpoint, entry.Key, entry.Data, entry.CreatedAt)
+	return err
+}
+
+func (qb *stashBoxFingerprintCacheQueryBuilder) DestroyCreatedBefore(ctx context.Context, t time.Time) (int, error) {
+	result, err := qb.tx.Exec(ctx, fmt.Sprintf("DELETE FROM %s WHERE created_at < ?", stashBoxFingerprintCacheTable), t)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := result.RowsAffected()
+	return int(n), err
+}
@@ -0,0 +1,122 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const (
+	stashBoxSceneSyncTable       = "stash_box_scene_sync"
+	stashBoxMatchSuggestionTable = "stash_box_match_suggestions"
+)
+
+type stashBoxSyncQueryBuilder struct {
+	repository
+}
+
+var StashBoxSyncReaderWriter = &stashBoxSyncQueryBuilder{
+	repository{
+		tableName: stashBoxMatchSuggestionTable,
+		idColumn:  idColumn,
+	},
+}
+
+func (qb *stashBoxSyncQueryBuilder) FindScenesToSubmit(ctx context.Context, endpoint string) ([]int, error) {
+	query := fmt.Sprintf(`SELECT scenes.id FROM %[1]s AS scenes
+INNER JOIN scene_stash_ids ON scene_stash_ids.scene_id = scenes.id AND scene_stash_ids.endpoint = ?
+LEFT JOIN %[2]s AS sync ON sync.scene_id = scenes.id AND sync.endpoint = scene_stash_ids.endpoint
+WHERE sync.fingerprints_submitted_at IS NULL OR sync.fingerprints_submitted_at < scenes.updated_at
+ORDER BY scenes.id`, sceneTable, stashBoxSceneSyncTable)
+
+	return qb.runIdsQuery(ctx, query, []interface{}{endpoint})
+}
+
+func (qb *stashBoxSyncQueryBuilder) FindScenesToMatch(ctx context.Context, endpoint string, checkedBefore time.Time) ([]int, error) {
+	query := fmt.Sprintf(`SELECT scenes.id FROM %[1]s AS scenes
+LEFT JOIN %[2]s AS sync ON sync.scene_id = scenes.id AND sync.endpoint = ?
+WHERE (sync.matches_checked_at IS NULL OR sync.matches_checked_at < ?)
+AND NOT EXISTS (SELECT 1 FROM scene_stash_ids WHERE scene_stash_ids.scene_id = scenes.id AND scene_stash_ids.endpoint = ?)
+AND NOT EXISTS (SELECT 1 FROM %[3]s AS s WHERE s.scene_id = scenes.id AND s.endpoint = ? AND s.rejected = 0)
+ORDER BY scenes.id`, sceneTable, stashBoxSceneSyncTable, stashBoxMatchSuggestionTable)
+
+	return qb.runIdsQuery(ctx, query, []interface{}{endpoint, checkedBefore, endpoint, endpoint})
+}
+
+func (qb *stashBoxSyncQueryBuilder) FindSuggestion(ctx context.Context, id int) (*models.StashBoxMatchSuggestion, error) {
+	var ret models.StashBoxMatchSuggestion
+	if err := qb.getByID(ctx, id, &ret); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &ret, nil
+}
+
+func (qb *stashBoxSyncQueryBuilder) QuerySuggestions(ctx context.Context, sceneID *int, findFilter *models.FindFilterType) ([]*models.StashBoxMatchSuggestion, int, error) {
+	if findFilter == nil {
+		findFilter = &models.FindFilterType{}
+	}
+
+	body := selectAll(stashBoxMatchSuggestionTable) + " WHERE rejected = 0"
+	var args []interface{}
+	if sceneID != nil {
+		body += " AND scene_id = ?"
+		args = append(args, *sceneID)
+	}
+
+	count, err := qb.runCountQuery(ctx, qb.buildCountQuery(body), args)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := body + " ORDER BY created_at DESC, id DESC" + getPagination(findFilter)
+
+	var ret models.StashBoxMatchSuggestions
+	if err := qb.query(ctx, query, args, &ret); err != nil {
+		return nil, 0, err
+	}
+
+	return []*models.StashBoxMatchSuggestion(ret), count, nil
+}
+
+func (qb *stashBoxSyncQueryBuilder) setSceneSync(ctx context.Context, column string, sceneIDs []int, endpoint string, t time.Time) error {
+	stmt := fmt.Sprintf("INSERT INTO %[1]s (scene_id, endpoint, %[2]s) VALUES (?, ?, ?) ON CONFLICT (scene_id, endpoint) DO UPDATE SET %[2]s = excluded.%[2]s", stashBoxSceneSyncTable, column)
+	for _, id := range sceneIDs {
+		if _, err := qb.tx.Exec(ctx, stmt, id, endpoint, t); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (qb *stashBoxSyncQueryBuilder) SetFingerprintsSubmitted(ctx context.Context, sceneIDs []int, endpoint string, t time.Time) error {
+	return qb.setSceneSync(ctx, "fingerprints_submitted_at", sceneIDs, endpoint, t)
+}
+
+func (qb *stashBoxSyncQueryBuilder) SetMatchesChecked(ctx context.Context, sceneIDs []int, endpoint string, t time.Time) error {
+	return qb.setSceneSync(ctx, "matches_checked_at", sceneIDs, endpoint, t)
+}
+
+func (qb *stashBoxSyncQueryBuilder) CreateSuggestion(ctx context.Context, newObject models.StashBoxMatchSuggestion) error {
+	// rejected suggestions already exist and are ignored
+	stmt := fmt.Sprintf("INSERT OR IGNORE INTO %s (scene_id, endpoint, stash_id, data, rejected, created_at) VALUES (?, ?, ?, ?, 0, ?)", stashBoxMatchSuggestionTable)
+	_, err := qb.tx.Exec(ctx, stmt, newObject.SceneID, newObject.Endpoint, newObject.StashID, newObject.Data, newObject.CreatedAt)
+	return err
+}
+
+func (qb *stashBoxSyncQueryBuilder) DestroySuggestions(ctx context.Context, sceneID int, endpoint string) error {
+	_, err := qb.tx.Exec(ctx, fmt.Sprintf("DELETE FROM %s WHERE scene_id = ? AND endpoint = ? AND rejected = 0", stashBoxMatchSuggestionTable), sceneID, endpoint)
+	return err
+}
+
+func (qb *stashBoxSyncQueryBuilder) RejectSuggestion(ctx context.Context, id int) error {
+	_, err := qb.tx.Exec(ctx, fmt.Sprintf("UPDATE %s SET rejected = 1 WHERE id = ?", stashBoxMatchSuggestionTable), id)
+	return err
+}
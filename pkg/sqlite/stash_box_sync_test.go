@@ -0,0 +1,178 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sliceutil/intslice"
+	"github.com/stashapp/stash/pkg/sqlite"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStashBoxSyncFindScenesToSubmit(t *testing.T) {
+	qb := sqlite.StashBoxSyncReaderWriter
+	sceneIdx := sceneIdxWithPerformer
+	endpoint := sceneStashID(sceneIdx).Endpoint
+
+	withRollbackTxn(func(ctx context.Context) error {
+		got, err := qb.FindScenesToSubmit(ctx, endpoint)
+		if err != nil {
+			t.Errorf("Error finding scenes to submit: %s", err.Error())
+			return nil
+		}
+
+		assert.Equal(t, []int{sceneIDs[sceneIdx]}, got)
+
+		if err := qb.SetFingerprintsSubmitted(ctx, got, endpoint, time.Now()); err != nil {
+			t.Errorf("Error setting fingerprints submitted: %s", err.Error())
+			return nil
+		}
+
+		// not submitted again until the scene is updated
+		got, err = qb.FindScenesToSubmit(ctx, endpoint)
+		if err != nil {
+			t.Errorf("Error finding scenes to submit: %s", err.Error())
+			return nil
+		}
+
+		assert.Len(t, got, 0)
+
+		return nil
+	})
+}
+
+func TestStashBoxSyncFindScenesToMatch(t *testing.T) {
+	qb := sqlite.StashBoxSyncReaderWriter
+	endpoint := sceneStashID(sceneIdxWithPerformer).Endpoint
+	matchedID := sceneIDs[sceneIdxWithPerformer]
+	suggestedID := sceneIDs[sceneIdxWithStudio]
+	checkedID := sceneIDs[sceneIdxWithTag]
+
+	withRollbackTxn(func(ctx context.Context) error {
+		// other tests may add scenes, so compare against the unchecked scenes
+		unchecked, err := qb.FindScenesToMatch(ctx, endpoint, time.Now())
+		if err != nil {
+			t.Errorf("Error finding scenes to match: %s", err.Error())
+			return nil
+		}
+
+		if err := qb.CreateSuggestion(ctx, models.StashBoxMatchSuggestion{
+			SceneID:   suggestedID,
+			Endpoint:  endpoint,
+			StashID:   "stash-id",
+			Data:      []byte("{}"),
+			CreatedAt: time.Now(),
+		}); err != nil {
+			t.Errorf("Error creating suggestion: %s", err.Error())
+			return nil
+		}
+
+		now := time.Now()
+		if err := qb.SetMatchesChecked(ctx, []int{checkedID}, endpoint, now); err != nil {
+			t.Errorf("Error setting matches checked: %s", err.Error())
+			return nil
+		}
+
+		got, err := qb.FindScenesToMatch(ctx, endpoint, now.Add(-time.Hour))
+		if err != nil {
+			t.Errorf("Error finding scenes to match: %s", err.Error())
+			return nil
+		}
+
+		// excludes scenes with a stash id, a pending suggestion or checked
+		// since the time
+		assert.NotContains(t, got, matchedID)
+		assert.NotContains(t, got, suggestedID)
+		assert.NotContains(t, got, checkedID)
+		assert.NotContains(t, unchecked, matchedID)
+		assert.Len(t, got, len(unchecked)-2)
+
+		got, err = qb.FindScenesToMatch(ctx, endpoint, now.Add(time.Hour))
+		if err != nil {
+			t.Errorf("Error finding scenes to match: %s", err.Error())
+			return nil
+		}
+
+		assert.True(t, intslice.IntInclude(got, checkedID))
+
+		return nil
+	})
+}
+
+func TestStashBoxSyncSuggestions(t *testing.T) {
+	qb := sqlite.StashBoxSyncReaderWriter
+	sceneID := sceneIDs[sceneIdxWithStudio]
+	otherSceneID := sceneIDs[sceneIdxWithTag]
+	const endpoint = "endpoint"
+
+	withRollbackTxn(func(ctx context.Context) error {
+		for i, s := range []models.StashBoxMatchSuggestion{
+			{SceneID: sceneID, StashID: "a"},
+			{SceneID: sceneID, StashID: "b"},
+			{SceneID: otherSceneID, StashID: "a"},
+		} {
+			s.Endpoint = endpoint
+			s.Data = []byte("{}")
+			s.CreatedAt = time.Now().Add(time.Duration(i) * time.Minute)
+			if err := qb.CreateSuggestion(ctx, s); err != nil {
+				t.Errorf("Error creating suggestion: %s", err.Error())
+				return nil
+			}
+		}
+
+		got, count, err := qb.QuerySuggestions(ctx, &sceneID, nil)
+		if err != nil {
+			t.Errorf("Error querying suggestions: %s", err.Error())
+			return nil
+		}
+
+		// newest first
+		if !assert.Equal(t, 2, count) || !assert.Len(t, got, 2) {
+			return nil
+		}
+		assert.Equal(t, "b", got[0].StashID)
+
+		if err := qb.RejectSuggestion(ctx, got[0].ID); err != nil {
+			t.Errorf("Error rejecting suggestion: %s", err.Error())
+			return nil
+		}
+
+		// rejected suggestions are not added again
+		if err := qb.CreateSuggestion(ctx, models.StashBoxMatchSuggestion{
+			SceneID:   sceneID,
+			Endpoint:  endpoint,
+			StashID:   "b",
+			Data:      []byte("{}"),
+			CreatedAt: time.Now(),
+		}); err != nil {
+			t.Errorf("Error creating suggestion: %s", err.Error())
+			return nil
+		}
+
+		if err := qb.DestroySuggestions(ctx, sceneID, endpoint); err != nil {
+			t.Errorf("Error destroying suggestions: %s", err.Error())
+			return nil
+		}
+
+		_, count, err = qb.QuerySuggestions(ctx, &sceneID, nil)
+		if err != nil {
+			t.Errorf("Error querying suggestions: %s", err.Error())
+			return nil
+		}
+		assert.Equal(t, 0, count)
+
+		_, count, err = qb.QuerySuggestions(ctx, nil, nil)
+		if err != nil {
+			t.Errorf("Error querying suggestions: %s", err.Error())
+			return nil
+		}
+		assert.Equal(t, 1, count)
+
+		return nil
+	})
+}
@@ -238,6 +238,7 @@ func (qb *studioQueryBuilder) makeFilter(ctx context.Context, studioFilter *mode
 	// legacy rating handler
 	query.handleCriterion(ctx, rating5CriterionHandler(studioFilter.Rating, studioTable+".rating", nil))
 	query.handleCriterion(ctx, boolCriterionHandler(studioFilter.IgnoreAutoTag, studioTable+".ignore_auto_tag", nil))
+	query.handleCriterion(ctx, boolCriterionHandler(studioFilter.Favorite, studioTable+".favorite", nil))
 
 	query.handleCriterion(ctx, criterionHandlerFunc(func(ctx context.Context, f *filterBuilder) {
 		if studioFilter.StashID != nil {
@@ -260,6 +261,7 @@ func (qb *studioQueryBuilder) makeFilter(ctx context.Context, studioFilter *mode
 	query.handleCriterion(ctx, studioAliasCriterionHandler(qb, studioFilter.Aliases))
 	query.handleCriterion(ctx, timestampCriterionHandler(studioFilter.CreatedAt, "studios.created_at"))
 	query.handleCriterion(ctx, timestampCriterionHandler(studioFilter.UpdatedAt, "studios.updated_at"))
+	query.handleCriterion(ctx, customFieldsCriterionHandler(models.CustomFieldObjectTypeStudio, studioTable, studioFilter.CustomFields))
 
 	return query
 }
@@ -289,7 +291,7 @@ func (qb *studioQueryBuilder) Query(ctx context.Context, studioFilter *models.St
 
 	query.addFilter(filter)
 
-	query.sortAndPagination = qb.getStudioSort(findFilter) + getPagination(findFilter)
+	query.sortAndPagination = getFavoritesFirstSort(findFilter, studioTable, qb.getStudioSort(findFilter)) + getPagination(findFilter)
 	idsResult, countResult, err := query.executeFind(ctx)
 	if err != nil {
 		return nil, 0, err
@@ -399,6 +401,9 @@ func (qb *studioQueryBuilder) getStudioSort(findFilter *models.FindFilterType) s
 	case "galleries_count":
 		return getCountSort(studioTable, galleryTable, studioIDColumn, direction)
 	default:
+		if customSort, ok := getCustomFieldSort(models.CustomFieldObjectTypeStudio, studioTable, sort, direction); ok {
+			return customSort
+		}
 		return getSort(sort, direction, "studios")
 	}
 }
@@ -465,6 +470,33 @@ func (qb *studioQueryBuilder) UpdateStashIDs(ctx context.Context, studioID int,
 	return qb.stashIDRepository().replace(ctx, studioID, stashIDs)
 }
 
+func (qb *studioQueryBuilder) externalIDRepository() *externalIDRepository {
+	return &externalIDRepository{
+		repository{
+			tx:        qb.tx,
+			tableName: "studio_external_ids",
+			idColumn:  studioIDColumn,
+		},
+	}
+}
+
+func (qb *studioQueryBuilder) GetExternalIDs(ctx context.Context, studioID int) ([]models.ExternalID, error) {
+	return qb.externalIDRepository().get(ctx, studioID)
+}
+
+func (qb *studioQueryBuilder) UpdateExternalIDs(ctx context.Context, studioID int, externalIDs []models.ExternalID) error {
+	return qb.externalIDRepository().replace(ctx, studioID, externalIDs)
+}
+
+func (qb *studioQueryBuilder) FindByExternalID(ctx context.Context, source string, externalID string) ([]*models.Studio, error) {
+	ids, err := qb.externalIDRepository().findIDs(ctx, source, externalID)
+	if err != nil {
+		return nil, fmt.Errorf("getting studios for external ID %s: %w", externalID, err)
+	}
+
+	return qb.FindMany(ctx, ids)
+}
+
 func (qb *studioQueryBuilder) aliasRepository() *stringRepository {
 	return &stringRepository{
 		repository: repository{
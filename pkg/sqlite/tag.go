@@ -150,6 +150,36 @@ func (qb *tagQueryBuilder) FindBySceneID(ctx context.Context, sceneID int) ([]*m
 	return qb.queryTags(ctx, query, args)
 }
 
+// FindInheritedBySceneID returns the ancestors of the tags of the scene, up to
+// depth levels above the tags, or all ancestors if depth is -1. Tags of the
+// scene are not returned.
+func (qb *tagQueryBuilder) FindInheritedBySceneID(ctx context.Context, sceneID int, depth int) ([]*models.Tag, error) {
+	if depth == 0 {
+		return nil, nil
+	}
+
+	args := []interface{}{sceneID}
+	var depthCondition string
+	if depth > 0 {
+		depthCondition = "WHERE a.depth < ?"
+		args = append(args, depth)
+	}
+	args = append(args, sceneID)
+
+	query := `WITH RECURSIVE
+ancestors AS (
+	SELECT tag_id AS id, 0 AS depth FROM scenes_tags WHERE scene_id = ?
+	UNION
+	SELECT tr.parent_id, a.depth + 1 FROM tags_relations tr INNER JOIN ancestors a ON a.id = tr.child_id ` + depthCondition + `
+)
+SELECT tags.* FROM tags
+WHERE tags.id IN (SELECT id FROM ancestors)
+AND tags.id NOT IN (SELECT tag_id FROM scenes_tags WHERE scene_id = ?)
+`
+	query += qb.getDefaultTagSort()
+	return qb.queryTags(ctx, query, args)
+}
+
 func (qb *tagQueryBuilder) FindByPerformerID(ctx context.Context, performerID int) ([]*models.Tag, error) {
 	query := `
 		SELECT tags.* FROM tags
@@ -248,7 +278,11 @@ func (qb *tagQueryBuilder) Count(ctx context.Context) (int, error) {
 }
 
 func (qb *tagQueryBuilder) All(ctx context.Context) ([]*models.Tag, error) {
-	return qb.queryTags(ctx, selectAll("tags")+qb.getDefaultTagSort(), nil)
+	query := selectAll("tags")
+	if models.ContentWarningsHidden(ctx) {
+		query += "WHERE " + contentWarningTagClause() + " "
+	}
+	return qb.queryTags(ctx, query+qb.getDefaultTagSort(), nil)
 }
 
 func (qb *tagQueryBuilder) QueryForAutoTag(ctx context.Context, words []string) ([]*models.Tag, error) {
@@ -327,6 +361,8 @@ func (qb *tagQueryBuilder) makeFilter(ctx context.Context, tagFilter *models.Tag
 
 	query.handleCriterion(ctx, stringCriterionHandler(tagFilter.Description, tagTable+".description"))
 	query.handleCriterion(ctx, boolCriterionHandler(tagFilter.IgnoreAutoTag, tagTable+".ignore_auto_tag", nil))
+	query.handleCriterion(ctx, boolCriterionHandler(tagFilter.ContentWarning, tagTable+".content_warning", nil))
+	query.handleCriterion(ctx, boolCriterionHandler(tagFilter.Favorite, tagTable+".favorite", nil))
 
 	query.handleCriterion(ctx, tagIsMissingCriterionHandler(qb, tagFilter.IsMissing))
 	query.handleCriterion(ctx, tagSceneCountCriterionHandler(qb, tagFilter.SceneCount))
@@ -367,8 +403,9 @@ func (qb *tagQueryBuilder) Query(ctx context.Context, tagFilter *models.TagFilte
 	filter := qb.makeFilter(ctx, tagFilter)
 
 	query.addFilter(filter)
+	hideContentWarningTags(ctx, &query)
 
-	query.sortAndPagination = qb.getTagSort(&query, findFilter) + getPagination(findFilter)
+	query.sortAndPagination = getFavoritesFirstSort(findFilter, tagTable, qb.getTagSort(&query, findFilter)) + getPagination(findFilter)
 	idsResult, countResult, err := query.executeFind(ctx)
 	if err != nil {
 		return nil, 0, err
@@ -0,0 +1,136 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const tagSuggestionTable = "scene_tag_suggestions"
+
+type tagSuggestionQueryBuilder struct {
+	repository
+}
+
+var TagSuggestionReaderWriter = &tagSuggestionQueryBuilder{
+	repository{
+		tableName: tagSuggestionTable,
+		idColumn:  sceneIDColumn,
+	},
+}
+
+func (qb *tagSuggestionQueryBuilder) Query(ctx context.Context, sceneID *int, findFilter *models.FindFilterType) ([]*models.TagSuggestion, int, error) {
+	if findFilter == nil {
+		findFilter = &models.FindFilterType{}
+	}
+
+	body := selectAll(tagSuggestionTable) + " WHERE rejected = 0"
+	var args []interface{}
+	if sceneID != nil {
+		body += " AND scene_id = ?"
+		args = append(args, *sceneID)
+	}
+
+	count, err := qb.runCountQuery(ctx, qb.buildCountQuery(body), args)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := body + " ORDER BY confidence DESC, scene_id ASC, tag_id ASC" + getPagination(findFilter)
+
+	var ret models.TagSuggestions
+	if err := qb.query(ctx, query, args, &ret); err != nil {
+		return nil, 0, err
+	}
+
+	return []*models.TagSuggestion(ret), count, nil
+}
+
+func (qb *tagSuggestionQueryBuilder) FindSceneRelationshipIDs(ctx context.Context) ([]*models.SceneRelationshipIDs, error) {
+	var ret []*models.SceneRelationshipIDs
+	index := make(map[int]*models.SceneRelationshipIDs)
+
+	query := fmt.Sprintf("SELECT id, studio_id FROM %s ORDER BY id", sceneTable)
+	if err := qb.queryFunc(ctx, query, nil, false, func(rows *sqlx.Rows) error {
+		var id int
+		var studioID sql.NullInt64
+		if err := rows.Scan(&id, &studioID); err != nil {
+			return err
+		}
+
+		s := &models.SceneRelationshipIDs{SceneID: id}
+		if studioID.Valid {
+			v := int(studioID.Int64)
+			s.StudioID = &v
+		}
+
+		ret = append(ret, s)
+		index[id] = s
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("getting scene studios: %w", err)
+	}
+
+	joins := []struct {
+		table  string
+		column string
+		add    func(s *models.SceneRelationshipIDs, id int)
+	}{
+		{performersScenesTable, performerIDColumn, func(s *models.SceneRelationshipIDs, id int) {
+			s.PerformerIDs = append(s.PerformerIDs, id)
+		}},
+		{scenesTagsTable, tagIDColumn, func(s *models.SceneRelationshipIDs, id int) {
+			s.TagIDs = append(s.TagIDs, id)
+		}},
+	}
+
+	for _, j := range joins {
+		query := fmt.Sprintf("SELECT %s, %s FROM %s", sceneIDColumn, j.column, j.table)
+		if err := qb.queryFunc(ctx, query, nil, false, func(rows *sqlx.Rows) error {
+			var sceneID, id int
+			if err := rows.Scan(&sceneID, &id); err != nil {
+				return err
+			}
+
+			if s := index[sceneID]; s != nil {
+				j.add(s, id)
+			}
+			return nil
+		}); err != nil {
+			return nil, fmt.Errorf("getting scene %s: %w", j.table, err)
+		}
+	}
+
+	return ret, nil
+}
+
+func (qb *tagSuggestionQueryBuilder) Replace(ctx context.Context, suggestions []models.TagSuggestion) error {
+	if _, err := qb.tx.Exec(ctx, fmt.Sprintf("DELETE FROM %s WHERE rejected = 0", tagSuggestionTable)); err != nil {
+		return err
+	}
+
+	// rejected suggestions already exist and are ignored
+	stmt := fmt.Sprintf("INSERT OR IGNORE INTO %s (scene_id, tag_id, confidence, rejected, created_at) VALUES (?, ?, ?, 0, ?)", tagSuggestionTable)
+	now := time.Now()
+	for _, s := range suggestions {
+		if _, err := qb.tx.Exec(ctx, stmt, s.SceneID, s.TagID, s.Confidence, now); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (qb *tagSuggestionQueryBuilder) Destroy(ctx context.Context, sceneID int, tagID int) error {
+	_, err := qb.tx.Exec(ctx, fmt.Sprintf("DELETE FROM %s WHERE scene_id = ? AND tag_id = ?", tagSuggestionTable), sceneID, tagID)
+	return err
+}
+
+func (qb *tagSuggestionQueryBuilder) Reject(ctx context.Context, sceneID int, tagID int) error {
+	_, err := qb.tx.Exec(ctx, fmt.Sprintf("UPDATE %s SET rejected = 1 WHERE scene_id = ? AND tag_id = ?", tagSuggestionTable), sceneID, tagID)
+	return err
+}
@@ -0,0 +1,107 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sqlite"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTagSuggestionFindSceneRelationshipIDs(t *testing.T) {
+	withTxn(func(ctx context.Context) error {
+		got, err := sqlite.TagSuggestionReaderWriter.FindSceneRelationshipIDs(ctx)
+		if err != nil {
+			t.Errorf("Error finding scene relationship ids: %s", err.Error())
+			return nil
+		}
+
+		index := make(map[int]*models.SceneRelationshipIDs)
+		for _, s := range got {
+			index[s.SceneID] = s
+		}
+
+		assert.Equal(t, []int{tagIDs[tagIdxWithScene]}, index[sceneIDs[sceneIdxWithTag]].TagIDs)
+		assert.Equal(t, []int{performerIDs[performerIdxWithScene]}, index[sceneIDs[sceneIdxWithPerformer]].PerformerIDs)
+		assert.Equal(t, &studioIDs[studioIdxWithScene], index[sceneIDs[sceneIdxWithStudio]].StudioID)
+
+		return nil
+	})
+}
+
+func TestTagSuggestionReplace(t *testing.T) {
+	qb := sqlite.TagSuggestionReaderWriter
+	sceneID := sceneIDs[sceneIdx1WithPerformer]
+	otherSceneID := sceneIDs[sceneIdx1WithStudio]
+	tagID := tagIDs[tagIdxWithScene]
+	rejectedTagID := tagIDs[tagIdx1WithScene]
+
+	withRollbackTxn(func(ctx context.Context) error {
+		if err := qb.Replace(ctx, []models.TagSuggestion{
+			{SceneID: sceneID, TagID: tagID, Confidence: 0.8},
+			{SceneID: sceneID, TagID: rejectedTagID, Confidence: 0.9},
+			{SceneID: otherSceneID, TagID: tagID, Confidence: 0.7},
+		}); err != nil {
+			t.Errorf("Error replacing tag suggestions: %s", err.Error())
+			return nil
+		}
+
+		if err := qb.Reject(ctx, sceneID, rejectedTagID); err != nil {
+			t.Errorf("Error rejecting tag suggestion: %s", err.Error())
+			return nil
+		}
+
+		got, count, err := qb.Query(ctx, nil, nil)
+		if err != nil {
+			t.Errorf("Error querying tag suggestions: %s", err.Error())
+			return nil
+		}
+
+		// rejected suggestions are excluded, highest confidence first
+		if assert.Equal(t, 2, count) && assert.Len(t, got, 2) {
+			assert.Equal(t, sceneID, got[0].SceneID)
+			assert.Equal(t, 0.8, got[0].Confidence)
+			assert.Equal(t, otherSceneID, got[1].SceneID)
+		}
+
+		// replacing does not add rejected suggestions again
+		if err := qb.Replace(ctx, []models.TagSuggestion{
+			{SceneID: sceneID, TagID: rejectedTagID, Confidence: 0.9},
+		}); err != nil {
+			t.Errorf("Error replacing tag suggestions: %s", err.Error())
+			return nil
+		}
+
+		_, count, err = qb.Query(ctx, &sceneID, nil)
+		if err != nil {
+			t.Errorf("Error querying tag suggestions: %s", err.Error())
+			return nil
+		}
+		assert.Equal(t, 0, count)
+
+		if err := qb.Replace(ctx, []models.TagSuggestion{
+			{SceneID: otherSceneID, TagID: tagID, Confidence: 0.7},
+		}); err != nil {
+			t.Errorf("Error replacing tag suggestions: %s", err.Error())
+			return nil
+		}
+
+		if err := qb.Destroy(ctx, otherSceneID, tagID); err != nil {
+			t.Errorf("Error destroying tag suggestion: %s", err.Error())
+			return nil
+		}
+
+		_, count, err = qb.Query(ctx, nil, nil)
+		if err != nil {
+			t.Errorf("Error querying tag suggestions: %s", err.Error())
+			return nil
+		}
+		assert.Equal(t, 0, count)
+
+		return nil
+	})
+}
@@ -95,6 +95,39 @@ func TestTagQueryIgnoreAutoTag(t *testing.T) {
 	})
 }
 
+func TestTagQueryFavorite(t *testing.T) {
+	withRollbackTxn(func(ctx context.Context) error {
+		sqb := sqlite.TagReaderWriter
+
+		// the last tag becomes the only favorite
+		sort := "id"
+		direction := models.SortDirectionEnumDesc
+		tags := queryTags(ctx, t, sqb, nil, &models.FindFilterType{Sort: &sort, Direction: &direction})
+		favoriteID := tags[0].ID
+
+		favorite := true
+		if _, err := sqb.Update(ctx, models.TagPartial{ID: favoriteID, Favorite: &favorite}); err != nil {
+			t.Errorf("Error updating tag: %s", err.Error())
+			return nil
+		}
+
+		tags = queryTags(ctx, t, sqb, &models.TagFilterType{Favorite: &favorite}, nil)
+		if assert.Len(t, tags, 1) {
+			assert.Equal(t, favoriteID, tags[0].ID)
+			assert.True(t, tags[0].Favorite)
+		}
+
+		// the remaining tags are sorted as requested
+		direction = models.SortDirectionEnumAsc
+		unsorted := queryTags(ctx, t, sqb, nil, &models.FindFilterType{Sort: &sort, Direction: &direction})
+		tags = queryTags(ctx, t, sqb, nil, &models.FindFilterType{Sort: &sort, Direction: &direction, FavoritesFirst: &favorite})
+		assert.Equal(t, favoriteID, tags[0].ID)
+		assert.Equal(t, unsorted[0].ID, tags[1].ID)
+
+		return nil
+	})
+}
+
 func TestTagQueryForAutoTag(t *testing.T) {
 	withTxn(func(ctx context.Context) error {
 		tqb := sqlite.TagReaderWriter
@@ -1034,3 +1067,71 @@ func TestTagMerge(t *testing.T) {
 // TODO All
 // TODO AllSlim
 // TODO Query
+
+func TestTagFindInheritedBySceneID(t *testing.T) {
+	if err := withRollbackTxn(func(ctx context.Context) error {
+		qb := sqlite.TagReaderWriter
+
+		create := func(name string) int {
+			created, err := qb.Create(ctx, models.Tag{Name: name})
+			if err != nil {
+				t.Fatalf("Error creating tag: %v", err)
+			}
+			return created.ID
+		}
+
+		// grandparent -> parent -> child, with the scene tagged with child
+		// and grandparent
+		grandparentID := create("TestTagFindInheritedBySceneID grandparent")
+		parentID := create("TestTagFindInheritedBySceneID parent")
+		childID := create("TestTagFindInheritedBySceneID child")
+
+		if err := qb.UpdateParentTags(ctx, parentID, []int{grandparentID}); err != nil {
+			t.Fatalf("Error updating parent tags: %v", err)
+		}
+		if err := qb.UpdateParentTags(ctx, childID, []int{parentID}); err != nil {
+			t.Fatalf("Error updating parent tags: %v", err)
+		}
+
+		s := &models.Scene{
+			Title:  "TestTagFindInheritedBySceneID",
+			TagIDs: models.NewRelatedIDs([]int{childID}),
+		}
+		if err := db.Scene.Create(ctx, s, nil); err != nil {
+			t.Fatalf("Error creating scene: %v", err)
+		}
+
+		tagIDs := func(depth int) []int {
+			tags, err := qb.FindInheritedBySceneID(ctx, s.ID, depth)
+			if err != nil {
+				t.Fatalf("Error finding inherited tags: %v", err)
+			}
+
+			var ret []int
+			for _, t := range tags {
+				ret = append(ret, t.ID)
+			}
+			return ret
+		}
+
+		assert.Empty(t, tagIDs(0))
+		assert.Equal(t, []int{parentID}, tagIDs(1))
+		assert.ElementsMatch(t, []int{grandparentID, parentID}, tagIDs(-1))
+
+		// tags of the scene are not inherited
+		if _, err := db.Scene.UpdatePartial(ctx, s.ID, models.ScenePartial{
+			TagIDs: &models.UpdateIDs{
+				IDs:  []int{grandparentID},
+				Mode: models.RelationshipUpdateModeAdd,
+			},
+		}); err != nil {
+			t.Fatalf("Error updating scene: %v", err)
+		}
+
+		assert.Equal(t, []int{parentID}, tagIDs(-1))
+
+		return nil
+	}); err != nil {
+		t.Error(err.Error())
+	}
+}
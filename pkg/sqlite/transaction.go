@@ -125,18 +125,41 @@ func (db *Database) IsLocked(err error) bool {
 
 func (db *Database) TxnRepository() models.Repository {
 	return models.Repository{
-		TxnManager:  db,
-		File:        db.File,
-		Folder:      db.Folder,
-		Gallery:     db.Gallery,
-		Image:       db.Image,
-		Movie:       MovieReaderWriter,
-		Performer:   db.Performer,
-		Scene:       db.Scene,
-		SceneMarker: SceneMarkerReaderWriter,
-		ScrapedItem: ScrapedItemReaderWriter,
-		Studio:      StudioReaderWriter,
-		Tag:         TagReaderWriter,
-		SavedFilter: SavedFilterReaderWriter,
+		TxnManager:         db,
+		File:               db.File,
+		Folder:             db.Folder,
+		Gallery:            db.Gallery,
+		Image:              db.Image,
+		Movie:              MovieReaderWriter,
+		Performer:          db.Performer,
+		Scene:              db.Scene,
+		SceneMarker:        SceneMarkerReaderWriter,
+		ScrapedItem:        ScrapedItemReaderWriter,
+		Studio:             StudioReaderWriter,
+		Tag:                TagReaderWriter,
+		SavedFilter:        SavedFilterReaderWriter,
+		WantedScene:        WantedSceneReaderWriter,
+		ActivityLog:        ActivityLogReaderWriter,
+		PlaybackEvent:      PlaybackEventReaderWriter,
+		SceneFlag:          SceneFlagReaderWriter,
+		SceneVersion:       SceneVersionReaderWriter,
+		BulkOperation:      BulkOperationReaderWriter,
+		TagSuggestion:      TagSuggestionReaderWriter,
+		PlayQueue:          PlayQueueReaderWriter,
+		JobCheckpoint:      JobCheckpointReaderWriter,
+		JobArtifact:        JobArtifactReaderWriter,
+		TrashedScene:       TrashedSceneReaderWriter,
+		CustomField:        CustomFieldReaderWriter,
+		EditHistory:        EditHistoryReaderWriter,
+		User:               UserReaderWriter,
+		RestrictionProfile: RestrictionProfileReaderWriter,
+		Search:             SearchReaderWriter,
+		Consistency:        ConsistencyChecker,
+
+		StashBoxFingerprintCache: StashBoxFingerprintCacheReaderWriter,
+		ScraperCache:             ScraperCacheReaderWriter,
+		StashBoxDraft:            StashBoxDraftReaderWriter,
+		StashBoxSync:             StashBoxSyncReaderWriter,
+		OperationJournal:         OperationJournalReaderWriter,
 	}
 }
@@ -0,0 +1,69 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const trashedSceneTable = "trashed_scenes"
+
+type trashedSceneQueryBuilder struct {
+	repository
+}
+
+var TrashedSceneReaderWriter = &trashedSceneQueryBuilder{
+	repository{
+		tableName: trashedSceneTable,
+		idColumn:  idColumn,
+	},
+}
+
+func (qb *trashedSceneQueryBuilder) Create(ctx context.Context, newObject models.TrashedScene) (*models.TrashedScene, error) {
+	var ret models.TrashedScene
+	if err := qb.insertObject(ctx, newObject, &ret); err != nil {
+		return nil, err
+	}
+
+	return &ret, nil
+}
+
+func (qb *trashedSceneQueryBuilder) Destroy(ctx context.Context, id int) error {
+	return qb.destroyExisting(ctx, []int{id})
+}
+
+func (qb *trashedSceneQueryBuilder) Find(ctx context.Context, id int) (*models.TrashedScene, error) {
+	var ret models.TrashedScene
+	if err := qb.getByID(ctx, id, &ret); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &ret, nil
+}
+
+func (qb *trashedSceneQueryBuilder) All(ctx context.Context) ([]*models.TrashedScene, error) {
+	query := selectAll(trashedSceneTable) + "ORDER BY created_at DESC, id DESC"
+
+	var ret models.TrashedScenes
+	if err := qb.query(ctx, query, nil, &ret); err != nil {
+		return nil, err
+	}
+
+	return []*models.TrashedScene(ret), nil
+}
+
+func (qb *trashedSceneQueryBuilder) FindCreatedBefore(ctx context.Context, t time.Time) ([]*models.TrashedScene, error) {
+	query := selectAll(trashedSceneTable) + "WHERE created_at < ?"
+
+	var ret models.TrashedScenes
+	if err := qb.query(ctx, query, []interface{}{t}, &ret); err != nil {
+		return nil, err
+	}
+
+	return []*models.TrashedScene(ret), nil
+}
@@ -0,0 +1,90 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sqlite"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrashedScene(t *testing.T) {
+	qb := sqlite.TrashedSceneReaderWriter
+	now := time.Now()
+
+	withRollbackTxn(func(ctx context.Context) error {
+		old, err := qb.Create(ctx, models.TrashedScene{
+			SceneID:   1,
+			Title:     "old",
+			Path:      "/library/old.mp4",
+			Data:      []byte("{}"),
+			CreatedAt: now.AddDate(0, 0, -40),
+		})
+		if err != nil {
+			t.Errorf("Error creating trashed scene: %s", err.Error())
+			return nil
+		}
+
+		recent, err := qb.Create(ctx, models.TrashedScene{
+			SceneID:   2,
+			Title:     "recent",
+			Path:      "/library/recent.mp4",
+			Data:      []byte(`{"dir":"/trash/2"}`),
+			CreatedAt: now,
+		})
+		if err != nil {
+			t.Errorf("Error creating trashed scene: %s", err.Error())
+			return nil
+		}
+
+		found, err := qb.Find(ctx, recent.ID)
+		if err != nil {
+			t.Errorf("Error finding trashed scene: %s", err.Error())
+			return nil
+		}
+		if assert.NotNil(t, found) {
+			assert.Equal(t, 2, found.SceneID)
+			assert.Equal(t, "/library/recent.mp4", found.Path)
+			assert.Equal(t, []byte(`{"dir":"/trash/2"}`), found.Data)
+		}
+
+		all, err := qb.All(ctx)
+		if err != nil {
+			t.Errorf("Error finding trashed scenes: %s", err.Error())
+			return nil
+		}
+		if assert.Len(t, all, 2) {
+			// most recently deleted first
+			assert.Equal(t, recent.ID, all[0].ID)
+			assert.Equal(t, old.ID, all[1].ID)
+		}
+
+		expired, err := qb.FindCreatedBefore(ctx, now.AddDate(0, 0, -30))
+		if err != nil {
+			t.Errorf("Error finding expired trashed scenes: %s", err.Error())
+			return nil
+		}
+		if assert.Len(t, expired, 1) {
+			assert.Equal(t, old.ID, expired[0].ID)
+		}
+
+		if err := qb.Destroy(ctx, old.ID); err != nil {
+			t.Errorf("Error destroying trashed scene: %s", err.Error())
+			return nil
+		}
+
+		found, err = qb.Find(ctx, old.ID)
+		if err != nil {
+			t.Errorf("Error finding trashed scene: %s", err.Error())
+			return nil
+		}
+		assert.Nil(t, found)
+
+		return nil
+	})
+}
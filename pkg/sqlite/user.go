@@ -0,0 +1,127 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const (
+	userTable         = "users"
+	userIdentityTable = "user_identities"
+)
+
+type userQueryBuilder struct {
+	repository
+}
+
+var UserReaderWriter = &userQueryBuilder{
+	repository{
+		tableName: userTable,
+		idColumn:  idColumn,
+	},
+}
+
+func (qb *userQueryBuilder) Create(ctx context.Context, newObject models.User) (*models.User, error) {
+	var ret models.User
+	if err := qb.insertObject(ctx, newObject, &ret); err != nil {
+		return nil, err
+	}
+
+	return &ret, nil
+}
+
+func (qb *userQueryBuilder) Update(ctx context.Context, updatedObject models.User) (*models.User, error) {
+	const partial = false
+	if err := qb.update(ctx, updatedObject.ID, updatedObject, partial); err != nil {
+		return nil, err
+	}
+
+	var ret models.User
+	if err := qb.getByID(ctx, updatedObject.ID, &ret); err != nil {
+		return nil, err
+	}
+
+	return &ret, nil
+}
+
+func (qb *userQueryBuilder) Destroy(ctx context.Context, id int) error {
+	return qb.destroyExisting(ctx, []int{id})
+}
+
+func (qb *userQueryBuilder) Find(ctx context.Context, id int) (*models.User, error) {
+	var ret models.User
+	if err := qb.getByID(ctx, id, &ret); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &ret, nil
+}
+
+func (qb *userQueryBuilder) findOne(ctx context.Context, query string, args []interface{}) (*models.User, error) {
+	var ret models.Users
+	if err := qb.query(ctx, query, args, &ret); err != nil {
+		return nil, err
+	}
+
+	if len(ret) == 0 {
+		return nil, nil
+	}
+
+	return ret[0], nil
+}
+
+func (qb *userQueryBuilder) FindByUsername(ctx context.Context, username string) (*models.User, error) {
+	query := fmt.Sprintf("SELECT * FROM %s WHERE username = ? COLLATE NOCASE LIMIT 1", userTable)
+	return qb.findOne(ctx, query, []interface{}{username})
+}
+
+func (qb *userQueryBuilder) FindByAPIKeyHash(ctx context.Context, hash string) (*models.User, error) {
+	query := fmt.Sprintf("SELECT * FROM %s WHERE api_key_hash = ? LIMIT 1", userTable)
+	return qb.findOne(ctx, query, []interface{}{hash})
+}
+
+func (qb *userQueryBuilder) All(ctx context.Context) ([]*models.User, error) {
+	var ret models.Users
+	if err := qb.query(ctx, selectAll(userTable)+" ORDER BY username COLLATE NOCASE ASC", nil, &ret); err != nil {
+		return nil, err
+	}
+
+	return []*models.User(ret), nil
+}
+
+func (qb *userQueryBuilder) FindByExternalIdentity(ctx context.Context, provider, subject string) (*models.User, error) {
+	query := fmt.Sprintf("SELECT %[1]s.* FROM %[1]s INNER JOIN %[2]s ON %[2]s.user_id = %[1]s.id WHERE %[2]s.provider = ? AND %[2]s.subject = ? LIMIT 1", userTable, userIdentityTable)
+	return qb.findOne(ctx, query, []interface{}{provider, subject})
+}
+
+func (qb *userQueryBuilder) GetExternalIdentities(ctx context.Context, userID int) ([]models.UserExternalIdentity, error) {
+	query := fmt.Sprintf("SELECT provider, subject FROM %s WHERE user_id = ? ORDER BY provider", userIdentityTable)
+	var ret models.UserExternalIdentities
+	if err := qb.query(ctx, query, []interface{}{userID}, &ret); err != nil {
+		return nil, err
+	}
+
+	return []models.UserExternalIdentity(ret), nil
+}
+
+func (qb *userQueryBuilder) LinkExternalIdentity(ctx context.Context, userID int, identity models.UserExternalIdentity) error {
+	if err := qb.UnlinkExternalIdentity(ctx, userID, identity.Provider); err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (user_id, provider, subject) VALUES (?, ?, ?)", userIdentityTable)
+	_, err := qb.tx.Exec(ctx, query, userID, identity.Provider, identity.Subject)
+	return err
+}
+
+func (qb *userQueryBuilder) UnlinkExternalIdentity(ctx context.Context, userID int, provider string) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE user_id = ? AND provider = ?", userIdentityTable)
+	_, err := qb.tx.Exec(ctx, query, userID, provider)
+	return err
+}
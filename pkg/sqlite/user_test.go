@@ -0,0 +1,160 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sqlite"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserCRUD(t *testing.T) {
+	qb := sqlite.UserReaderWriter
+	now := time.Now()
+
+	withRollbackTxn(func(ctx context.Context) error {
+		editor, err := qb.Create(ctx, models.User{
+			Username:     "Editor",
+			PasswordHash: "hash",
+			Role:         models.UserRoleEditor,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		})
+		if err != nil {
+			t.Errorf("Error creating user: %s", err.Error())
+			return nil
+		}
+
+		if _, err := qb.Create(ctx, models.User{
+			Username:     "editor",
+			PasswordHash: "hash",
+			Role:         models.UserRoleReadOnly,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		}); err == nil {
+			t.Errorf("Expected error creating user with duplicate username")
+		}
+
+		found, err := qb.FindByUsername(ctx, "EDITOR")
+		if err != nil {
+			t.Errorf("Error finding user: %s", err.Error())
+			return nil
+		}
+		if assert.NotNil(t, found) {
+			assert.Equal(t, editor.ID, found.ID)
+		}
+
+		apiKeyHash := "keyhash"
+		editor.APIKeyHash = &apiKeyHash
+		editor.Role = models.UserRoleAdmin
+		if _, err := qb.Update(ctx, *editor); err != nil {
+			t.Errorf("Error updating user: %s", err.Error())
+			return nil
+		}
+
+		found, err = qb.FindByAPIKeyHash(ctx, apiKeyHash)
+		if err != nil {
+			t.Errorf("Error finding user: %s", err.Error())
+			return nil
+		}
+		if assert.NotNil(t, found) {
+			assert.Equal(t, models.UserRoleAdmin, found.Role)
+		}
+
+		if err := qb.Destroy(ctx, editor.ID); err != nil {
+			t.Errorf("Error destroying user: %s", err.Error())
+			return nil
+		}
+
+		all, err := qb.All(ctx)
+		if err != nil {
+			t.Errorf("Error finding users: %s", err.Error())
+			return nil
+		}
+		assert.Len(t, all, 0)
+
+		return nil
+	})
+}
+
+func TestUserExternalIdentities(t *testing.T) {
+	qb := sqlite.UserReaderWriter
+	now := time.Now()
+
+	withRollbackTxn(func(ctx context.Context) error {
+		var users []*models.User
+		for _, username := range []string{"first", "second"} {
+			u, err := qb.Create(ctx, models.User{
+				Username:     username,
+				PasswordHash: "hash",
+				Role:         models.UserRoleReadOnly,
+				CreatedAt:    now,
+				UpdatedAt:    now,
+			})
+			if err != nil {
+				t.Errorf("Error creating user: %s", err.Error())
+				return nil
+			}
+			users = append(users, u)
+		}
+
+		identity := models.UserExternalIdentity{Provider: "idp", Subject: "subject"}
+		if err := qb.LinkExternalIdentity(ctx, users[0].ID, identity); err != nil {
+			t.Errorf("Error linking identity: %s", err.Error())
+			return nil
+		}
+
+		if err := qb.LinkExternalIdentity(ctx, users[1].ID, identity); err == nil {
+			t.Errorf("Expected error linking identity to a second user")
+		}
+
+		found, err := qb.FindByExternalIdentity(ctx, "idp", "subject")
+		if err != nil {
+			t.Errorf("Error finding user: %s", err.Error())
+			return nil
+		}
+		if assert.NotNil(t, found) {
+			assert.Equal(t, users[0].ID, found.ID)
+		}
+
+		// linking replaces the identity of the same provider
+		replaced := models.UserExternalIdentity{Provider: "idp", Subject: "other"}
+		if err := qb.LinkExternalIdentity(ctx, users[0].ID, replaced); err != nil {
+			t.Errorf("Error linking identity: %s", err.Error())
+			return nil
+		}
+
+		identities, err := qb.GetExternalIdentities(ctx, users[0].ID)
+		if err != nil {
+			t.Errorf("Error getting identities: %s", err.Error())
+			return nil
+		}
+		assert.Equal(t, []models.UserExternalIdentity{replaced}, identities)
+
+		found, err = qb.FindByExternalIdentity(ctx, "idp", "subject")
+		if err != nil {
+			t.Errorf("Error finding user: %s", err.Error())
+			return nil
+		}
+		assert.Nil(t, found)
+
+		if err := qb.UnlinkExternalIdentity(ctx, users[0].ID, "idp"); err != nil {
+			t.Errorf("Error unlinking identity: %s", err.Error())
+			return nil
+		}
+
+		identities, err = qb.GetExternalIdentities(ctx, users[0].ID)
+		if err != nil {
+			t.Errorf("Error getting identities: %s", err.Error())
+			return nil
+		}
+		assert.Len(t, identities, 0)
+
+		return nil
+	})
+}
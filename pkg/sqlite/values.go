@@ -24,6 +24,15 @@ func nullIntPtr(i null.Int) *int {
 	return &v
 }
 
+func nullFloatPtr(f null.Float) *float64 {
+	if !f.Valid {
+		return nil
+	}
+
+	v := f.Float64
+	return &v
+}
+
 func nullIntFolderIDPtr(i null.Int) *file.FolderID {
 	if !i.Valid {
 		return nil
@@ -0,0 +1,122 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const wantedSceneTable = "wanted_scenes"
+
+type wantedSceneQueryBuilder struct {
+	repository
+}
+
+var WantedSceneReaderWriter = &wantedSceneQueryBuilder{
+	repository{
+		tableName: wantedSceneTable,
+		idColumn:  idColumn,
+	},
+}
+
+func (qb *wantedSceneQueryBuilder) Create(ctx context.Context, newObject models.WantedScene) (*models.WantedScene, error) {
+	var ret models.WantedScene
+	if err := qb.insertObject(ctx, newObject, &ret); err != nil {
+		return nil, err
+	}
+
+	return &ret, nil
+}
+
+func (qb *wantedSceneQueryBuilder) Update(ctx context.Context, updatedObject models.WantedScene) (*models.WantedScene, error) {
+	const partial = false
+	if err := qb.update(ctx, updatedObject.ID, updatedObject, partial); err != nil {
+		return nil, err
+	}
+
+	var ret models.WantedScene
+	if err := qb.getByID(ctx, updatedObject.ID, &ret); err != nil {
+		return nil, err
+	}
+
+	return &ret, nil
+}
+
+func (qb *wantedSceneQueryBuilder) Destroy(ctx context.Context, id int) error {
+	return qb.destroyExisting(ctx, []int{id})
+}
+
+func (qb *wantedSceneQueryBuilder) Find(ctx context.Context, id int) (*models.WantedScene, error) {
+	var ret models.WantedScene
+	if err := qb.getByID(ctx, id, &ret); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &ret, nil
+}
+
+func (qb *wantedSceneQueryBuilder) FindUnfulfilled(ctx context.Context) ([]*models.WantedScene, error) {
+	query := fmt.Sprintf(`SELECT * FROM %s WHERE scene_id IS NULL ORDER BY created_at ASC`, wantedSceneTable)
+
+	var ret models.WantedScenes
+	if err := qb.query(ctx, query, nil, &ret); err != nil {
+		return nil, err
+	}
+
+	return []*models.WantedScene(ret), nil
+}
+
+func (qb *wantedSceneQueryBuilder) All(ctx context.Context) ([]*models.WantedScene, error) {
+	var ret models.WantedScenes
+	if err := qb.query(ctx, selectAll(wantedSceneTable)+"ORDER BY created_at ASC", nil, &ret); err != nil {
+		return nil, err
+	}
+
+	return []*models.WantedScene(ret), nil
+}
+
+func (qb *wantedSceneQueryBuilder) FindByURL(ctx context.Context, url string) ([]*models.WantedScene, error) {
+	query := fmt.Sprintf(`SELECT * FROM %s WHERE url = ? ORDER BY created_at ASC`, wantedSceneTable)
+
+	var ret models.WantedScenes
+	if err := qb.query(ctx, query, []interface{}{url}, &ret); err != nil {
+		return nil, err
+	}
+
+	return []*models.WantedScene(ret), nil
+}
+
+func (qb *wantedSceneQueryBuilder) FindByScrapeStatus(ctx context.Context, status models.WantedSceneScrapeStatus) ([]*models.WantedScene, error) {
+	query := fmt.Sprintf(`SELECT * FROM %s WHERE scrape_status = ? ORDER BY created_at ASC`, wantedSceneTable)
+
+	var ret models.WantedScenes
+	if err := qb.query(ctx, query, []interface{}{status}, &ret); err != nil {
+		return nil, err
+	}
+
+	return []*models.WantedScene(ret), nil
+}
+
+func (qb *wantedSceneQueryBuilder) coverRepository() *imageRepository {
+	return &imageRepository{
+		repository: repository{
+			tx:        qb.tx,
+			tableName: "wanted_scenes_cover",
+			idColumn:  "wanted_scene_id",
+		},
+		imageColumn: "cover",
+	}
+}
+
+func (qb *wantedSceneQueryBuilder) GetCover(ctx context.Context, wantedSceneID int) ([]byte, error) {
+	return qb.coverRepository().get(ctx, wantedSceneID)
+}
+
+func (qb *wantedSceneQueryBuilder) UpdateCover(ctx context.Context, wantedSceneID int, cover []byte) error {
+	return qb.coverRepository().replace(ctx, wantedSceneID, cover)
+}
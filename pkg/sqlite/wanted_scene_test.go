@@ -0,0 +1,186 @@
+//go:build integration
+// +build integration
+
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/sqlite"
+	"github.com/stashapp/stash/pkg/wanted"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWantedSceneCreateUpdateDestroy(t *testing.T) {
+	qb := sqlite.WantedSceneReaderWriter
+	title := "wanted scene"
+	now := time.Now()
+
+	withRollbackTxn(func(ctx context.Context) error {
+		created, err := qb.Create(ctx, models.WantedScene{
+			Title:     &title,
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+		if err != nil {
+			t.Errorf("Error creating wanted scene: %s", err.Error())
+			return nil
+		}
+
+		assert.Equal(t, title, *created.Title)
+		assert.Nil(t, created.SceneID)
+
+		unfulfilled, err := qb.FindUnfulfilled(ctx)
+		if err != nil {
+			t.Errorf("Error finding unfulfilled wanted scenes: %s", err.Error())
+		}
+		assert.Len(t, unfulfilled, 1)
+
+		sceneID := sceneIDs[sceneIdxWithGallery]
+		created.SceneID = &sceneID
+		created.FulfilledAt = &now
+		updated, err := qb.Update(ctx, *created)
+		if err != nil {
+			t.Errorf("Error updating wanted scene: %s", err.Error())
+			return nil
+		}
+
+		assert.Equal(t, sceneID, *updated.SceneID)
+		assert.NotNil(t, updated.FulfilledAt)
+
+		unfulfilled, err = qb.FindUnfulfilled(ctx)
+		if err != nil {
+			t.Errorf("Error finding unfulfilled wanted scenes: %s", err.Error())
+		}
+		assert.Len(t, unfulfilled, 0)
+
+		if err := qb.Destroy(ctx, created.ID); err != nil {
+			t.Errorf("Error destroying wanted scene: %s", err.Error())
+		}
+
+		found, err := qb.Find(ctx, created.ID)
+		if err != nil {
+			t.Errorf("Error finding wanted scene: %s", err.Error())
+		}
+		assert.Nil(t, found)
+
+		return nil
+	})
+}
+
+func TestWantedSceneFindByURLAndScrapeStatus(t *testing.T) {
+	qb := sqlite.WantedSceneReaderWriter
+	url := "https://example.com/scene"
+	pending := models.WantedSceneScrapeStatusPending
+	now := time.Now()
+
+	withRollbackTxn(func(ctx context.Context) error {
+		created, err := qb.Create(ctx, models.WantedScene{
+			URL:          &url,
+			ScrapeStatus: &pending,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		})
+		if err != nil {
+			t.Errorf("Error creating wanted scene: %s", err.Error())
+			return nil
+		}
+
+		byURL, err := qb.FindByURL(ctx, url)
+		if err != nil {
+			t.Errorf("Error finding wanted scenes by url: %s", err.Error())
+		}
+		assert.Len(t, byURL, 1)
+
+		byStatus, err := qb.FindByScrapeStatus(ctx, models.WantedSceneScrapeStatusPending)
+		if err != nil {
+			t.Errorf("Error finding wanted scenes by scrape status: %s", err.Error())
+		}
+		assert.Len(t, byStatus, 1)
+		assert.Equal(t, pending, *byStatus[0].ScrapeStatus)
+
+		byStatus, err = qb.FindByScrapeStatus(ctx, models.WantedSceneScrapeStatusFailed)
+		if err != nil {
+			t.Errorf("Error finding wanted scenes by scrape status: %s", err.Error())
+		}
+		assert.Len(t, byStatus, 0)
+
+		cover := []byte("cover")
+		if err := qb.UpdateCover(ctx, created.ID, cover); err != nil {
+			t.Errorf("Error updating wanted scene cover: %s", err.Error())
+		}
+
+		stored, err := qb.GetCover(ctx, created.ID)
+		if err != nil {
+			t.Errorf("Error getting wanted scene cover: %s", err.Error())
+		}
+		assert.Equal(t, cover, stored)
+
+		return nil
+	})
+}
+
+func TestWantedSceneMatchRequiresFiles(t *testing.T) {
+	qb := sqlite.WantedSceneReaderWriter
+	now := time.Now()
+
+	withRollbackTxn(func(ctx context.Context) error {
+		title := "wanted scene without files"
+		url := "https://example.com/wanted-without-files"
+		withoutFiles := &models.Scene{
+			Title: title,
+			URL:   url,
+		}
+		if err := db.Scene.Create(ctx, withoutFiles, nil); err != nil {
+			t.Errorf("Error creating scene: %s", err.Error())
+			return nil
+		}
+
+		if _, err := qb.Create(ctx, models.WantedScene{
+			Title:     &title,
+			URL:       &url,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}); err != nil {
+			t.Errorf("Error creating wanted scene: %s", err.Error())
+			return nil
+		}
+
+		m := wanted.Matcher{
+			SceneFinder: db.Scene,
+			Wanted:      qb,
+		}
+
+		fulfilled, err := m.Match(ctx)
+		if err != nil {
+			t.Errorf("Error matching wanted scenes: %s", err.Error())
+			return nil
+		}
+		assert.Len(t, fulfilled, 0)
+
+		withFilesTitle := getSceneTitle(sceneIdxWithGallery)
+		if _, err := qb.Create(ctx, models.WantedScene{
+			Title:     &withFilesTitle,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}); err != nil {
+			t.Errorf("Error creating wanted scene: %s", err.Error())
+			return nil
+		}
+
+		fulfilled, err = m.Match(ctx)
+		if err != nil {
+			t.Errorf("Error matching wanted scenes: %s", err.Error())
+			return nil
+		}
+		if assert.Len(t, fulfilled, 1) {
+			assert.Equal(t, sceneIDs[sceneIdxWithGallery], fulfilled[0].Scene.ID)
+			assert.Equal(t, "title", fulfilled[0].Reason)
+		}
+
+		return nil
+	})
+}
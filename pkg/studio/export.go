@@ -21,6 +21,7 @@ type FinderImageStashIDGetter interface {
 func ToJSON(ctx context.Context, reader FinderImageStashIDGetter, studio *models.Studio) (*jsonschema.Studio, error) {
 	newStudioJSON := jsonschema.Studio{
 		IgnoreAutoTag: studio.IgnoreAutoTag,
+		Favorite:      studio.Favorite,
 		CreatedAt:     json.JSONTime{Time: studio.CreatedAt.Timestamp},
 		UpdatedAt:     json.JSONTime{Time: studio.UpdatedAt.Timestamp},
 	}
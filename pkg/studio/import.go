@@ -41,6 +41,7 @@ func (i *Importer) PreImport(ctx context.Context) error {
 		URL:           sql.NullString{String: i.Input.URL, Valid: true},
 		Details:       sql.NullString{String: i.Input.Details, Valid: true},
 		IgnoreAutoTag: i.Input.IgnoreAutoTag,
+		Favorite:      i.Input.Favorite,
 		CreatedAt:     models.SQLiteTimestamp{Timestamp: i.Input.CreatedAt.GetTime()},
 		UpdatedAt:     models.SQLiteTimestamp{Timestamp: i.Input.UpdatedAt.GetTime()},
 		Rating:        sql.NullInt64{Int64: int64(i.Input.Rating), Valid: true},
@@ -0,0 +1,108 @@
+package tag
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+// DefaultDuplicateMinSimilarity is the default minimum similarity of the names
+// of duplicate tags.
+const DefaultDuplicateMinSimilarity = 0.9
+
+// DuplicateCandidate is a tag with its aliases, checked for duplicates.
+type DuplicateCandidate struct {
+	Tag     *models.Tag
+	Aliases []string
+}
+
+// normaliseName returns the name used to compare tag names and aliases.
+func normaliseName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// FindDuplicates groups the tags whose names or aliases are equal, ignoring
+// case, or whose names have a similarity of at least minSimilarity. The
+// similarity of two names is one minus their edit distance divided by the
+// length of the longer name. Names are only compared by similarity if
+// minSimilarity is less than 1.
+//
+// Each group contains at least two tags, ordered by id. Groups are ordered by
+// the id of their first tag.
+func FindDuplicates(candidates []DuplicateCandidate, minSimilarity float64) [][]*models.Tag {
+	parent := make([]int, len(candidates))
+	for i := range parent {
+		parent[i] = i
+	}
+
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	union := func(i, j int) {
+		pi, pj := find(i), find(j)
+		if pi != pj {
+			parent[pj] = pi
+		}
+	}
+
+	// names and aliases colliding case-insensitively
+	byName := make(map[string]int)
+	names := make([]string, len(candidates))
+	for i, c := range candidates {
+		name := normaliseName(c.Tag.Name)
+		names[i] = name
+
+		for _, n := range append([]string{name}, c.Aliases...) {
+			n = normaliseName(n)
+			if n == "" {
+				continue
+			}
+
+			if other, found := byName[n]; found {
+				union(other, i)
+			} else {
+				byName[n] = i
+			}
+		}
+	}
+
+	if minSimilarity < 1 {
+		for i := range candidates {
+			for j := i + 1; j < len(candidates); j++ {
+				if find(i) != find(j) && utils.StrSimilarity(names[i], names[j], minSimilarity) >= minSimilarity {
+					union(i, j)
+				}
+			}
+		}
+	}
+
+	groups := make(map[int][]*models.Tag)
+	for i, c := range candidates {
+		root := find(i)
+		groups[root] = append(groups[root], c.Tag)
+	}
+
+	var ret [][]*models.Tag
+	for _, g := range groups {
+		if len(g) < 2 {
+			continue
+		}
+
+		sort.Slice(g, func(i, j int) bool {
+			return g[i].ID < g[j].ID
+		})
+		ret = append(ret, g)
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i][0].ID < ret[j][0].ID
+	})
+
+	return ret
+}
@@ -0,0 +1,59 @@
+package tag
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindDuplicates(t *testing.T) {
+	tags := []*models.Tag{
+		{ID: 1, Name: "Outdoors"},
+		{ID: 2, Name: "outdoors "},
+		{ID: 3, Name: "Outside"},
+		{ID: 4, Name: "Blowjob"},
+		{ID: 5, Name: "Blow job"},
+		{ID: 6, Name: "Kitchen"},
+		{ID: 7, Name: "Cooking"},
+	}
+	aliases := map[int][]string{
+		3: {"OUTDOORS"},
+		7: {"kitchen"},
+	}
+
+	candidates := func() []DuplicateCandidate {
+		ret := make([]DuplicateCandidate, len(tags))
+		for i, t := range tags {
+			ret[i] = DuplicateCandidate{Tag: t, Aliases: aliases[t.ID]}
+		}
+		return ret
+	}
+
+	ids := func(groups [][]*models.Tag) [][]int {
+		var ret [][]int
+		for _, g := range groups {
+			var group []int
+			for _, t := range g {
+				group = append(group, t.ID)
+			}
+			ret = append(ret, group)
+		}
+		return ret
+	}
+
+	tests := []struct {
+		name          string
+		minSimilarity float64
+		want          [][]int
+	}{
+		{"exact", 1, [][]int{{1, 2, 3}, {6, 7}}},
+		{"similar", 0.85, [][]int{{1, 2, 3}, {4, 5}, {6, 7}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ids(FindDuplicates(candidates(), tt.minSimilarity)))
+		})
+	}
+}
@@ -19,11 +19,13 @@ type FinderAliasImageGetter interface {
 // ToJSON converts a Tag object into its JSON equivalent.
 func ToJSON(ctx context.Context, reader FinderAliasImageGetter, tag *models.Tag) (*jsonschema.Tag, error) {
 	newTagJSON := jsonschema.Tag{
-		Name:          tag.Name,
-		Description:   tag.Description.String,
-		IgnoreAutoTag: tag.IgnoreAutoTag,
-		CreatedAt:     json.JSONTime{Time: tag.CreatedAt.Timestamp},
-		UpdatedAt:     json.JSONTime{Time: tag.UpdatedAt.Timestamp},
+		Name:           tag.Name,
+		Description:    tag.Description.String,
+		IgnoreAutoTag:  tag.IgnoreAutoTag,
+		ContentWarning: tag.ContentWarning,
+		Favorite:       tag.Favorite,
+		CreatedAt:      json.JSONTime{Time: tag.CreatedAt.Timestamp},
+		UpdatedAt:      json.JSONTime{Time: tag.UpdatedAt.Timestamp},
 	}
 
 	aliases, err := reader.GetAliases(ctx, tag.ID)
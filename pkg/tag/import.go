@@ -42,11 +42,13 @@ type Importer struct {
 
 func (i *Importer) PreImport(ctx context.Context) error {
 	i.tag = models.Tag{
-		Name:          i.Input.Name,
-		Description:   sql.NullString{String: i.Input.Description, Valid: true},
-		IgnoreAutoTag: i.Input.IgnoreAutoTag,
-		CreatedAt:     models.SQLiteTimestamp{Timestamp: i.Input.CreatedAt.GetTime()},
-		UpdatedAt:     models.SQLiteTimestamp{Timestamp: i.Input.UpdatedAt.GetTime()},
+		Name:           i.Input.Name,
+		Description:    sql.NullString{String: i.Input.Description, Valid: true},
+		IgnoreAutoTag:  i.Input.IgnoreAutoTag,
+		ContentWarning: i.Input.ContentWarning,
+		Favorite:       i.Input.Favorite,
+		CreatedAt:      models.SQLiteTimestamp{Timestamp: i.Input.CreatedAt.GetTime()},
+		UpdatedAt:      models.SQLiteTimestamp{Timestamp: i.Input.UpdatedAt.GetTime()},
 	}
 
 	var err error
@@ -0,0 +1,140 @@
+package tag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+type MergePlanGetter interface {
+	RelationshipGetter
+	Find(ctx context.Context, id int) (*models.Tag, error)
+	GetAliases(ctx context.Context, tagID int) ([]string, error)
+}
+
+// MergePlan describes the result of merging the source tags into the
+// destination tag.
+type MergePlan struct {
+	Destination *models.Tag
+	Sources     []*models.Tag
+	// Aliases of the destination after the merge. These include the names
+	// and aliases of the source tags.
+	Aliases []string
+	// IDs of the parent and child tags of the destination after the merge
+	ParentIDs []int
+	ChildIDs  []int
+}
+
+// PlanMerge returns the plan of merging the source tags into the destination
+// tag, without changing any tags.
+func PlanMerge(ctx context.Context, qb MergePlanGetter, sources []int, destination int) (*MergePlan, error) {
+	ret := &MergePlan{}
+
+	var err error
+	ret.Destination, err = qb.Find(ctx, destination)
+	if err != nil {
+		return nil, err
+	}
+	if ret.Destination == nil {
+		return nil, fmt.Errorf("%w: tag with id %d", models.ErrNotFound, destination)
+	}
+
+	aliases, err := qb.GetAliases(ctx, destination)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range sources {
+		if id == destination {
+			return nil, errors.New("cannot merge a tag into itself")
+		}
+
+		t, err := qb.Find(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if t == nil {
+			return nil, fmt.Errorf("%w: tag with id %d", models.ErrNotFound, id)
+		}
+
+		sourceAliases, err := qb.GetAliases(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		ret.Sources = append(ret.Sources, t)
+		aliases = append(aliases, t.Name)
+		aliases = append(aliases, sourceAliases...)
+	}
+
+	ret.Aliases = mergeAliases(ret.Destination.Name, aliases)
+
+	ret.ParentIDs, ret.ChildIDs, err = MergeHierarchy(ctx, destination, sources, qb)
+	if err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// mergeAliases returns the aliases with duplicates and the name of the tag
+// removed, ignoring case. The first of duplicate aliases is kept.
+func mergeAliases(name string, aliases []string) []string {
+	seen := map[string]bool{
+		normaliseName(name): true,
+	}
+
+	ret := []string{}
+	for _, a := range aliases {
+		n := normaliseName(a)
+		if n == "" || seen[n] {
+			continue
+		}
+
+		seen[n] = true
+		ret = append(ret, a)
+	}
+
+	return ret
+}
+
+type Merger interface {
+	MergePlanGetter
+	Merge(ctx context.Context, source []int, destination int) error
+	UpdateAliases(ctx context.Context, tagID int, aliases []string) error
+	UpdateParentTags(ctx context.Context, tagID int, parentIDs []int) error
+	UpdateChildTags(ctx context.Context, tagID int, childIDs []int) error
+}
+
+// Merge merges the source tags into the destination tag according to the
+// plan returned by PlanMerge, and returns the plan.
+func Merge(ctx context.Context, qb Merger, sources []int, destination int) (*MergePlan, error) {
+	plan, err := PlanMerge(ctx, qb, sources, destination)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := qb.Merge(ctx, sources, destination); err != nil {
+		return nil, err
+	}
+
+	// replace the aliases set by Merge, which may differ only by case
+	if err := qb.UpdateAliases(ctx, destination, plan.Aliases); err != nil {
+		return nil, err
+	}
+
+	if err := qb.UpdateParentTags(ctx, destination, plan.ParentIDs); err != nil {
+		return nil, err
+	}
+	if err := qb.UpdateChildTags(ctx, destination, plan.ChildIDs); err != nil {
+		return nil, err
+	}
+
+	if err := ValidateHierarchy(ctx, plan.Destination, plan.ParentIDs, plan.ChildIDs, qb); err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
@@ -0,0 +1,47 @@
+package tag
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/models/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeAliases(t *testing.T) {
+	assert.Equal(t, []string{"b", "C"}, mergeAliases("A", []string{"a", "b", "C", "B", "c", ""}))
+	assert.Equal(t, []string{}, mergeAliases("A", nil))
+}
+
+func TestPlanMerge(t *testing.T) {
+	ctx := context.Background()
+	db := &mocks.TagReaderWriter{}
+
+	destination := &models.Tag{ID: 1, Name: "Outdoors"}
+	source := &models.Tag{ID: 2, Name: "outdoors"}
+	parent := &models.Tag{ID: 3, Name: "Location"}
+	child := &models.Tag{ID: 4, Name: "Beach"}
+
+	db.On("Find", ctx, 1).Return(destination, nil)
+	db.On("Find", ctx, 2).Return(source, nil)
+	db.On("GetAliases", ctx, 1).Return([]string{"Outside"}, nil)
+	db.On("GetAliases", ctx, 2).Return([]string{"outside", "Open air"}, nil)
+	db.On("FindByChildTagID", ctx, 1).Return(nil, nil)
+	db.On("FindByChildTagID", ctx, 2).Return([]*models.Tag{parent}, nil)
+	db.On("FindByParentTagID", ctx, 1).Return([]*models.Tag{child}, nil)
+	db.On("FindByParentTagID", ctx, 2).Return([]*models.Tag{child, destination}, nil)
+
+	plan, err := PlanMerge(ctx, db, []int{2}, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, &MergePlan{
+		Destination: destination,
+		Sources:     []*models.Tag{source},
+		Aliases:     []string{"Outside", "Open air"},
+		ParentIDs:   []int{3},
+		ChildIDs:    []int{4},
+	}, plan)
+
+	_, err = PlanMerge(ctx, db, []int{1}, 1)
+	assert.Error(t, err)
+}
@@ -0,0 +1,141 @@
+package tag
+
+import (
+	"sort"
+
+	"github.com/stashapp/stash/pkg/models"
+)
+
+const (
+	DefaultSuggestMinSupport    = 5
+	DefaultSuggestMinConfidence = 0.8
+	DefaultSuggestMaxPerScene   = 10
+)
+
+type SuggestOptions struct {
+	// Minimum number of scenes with a performer, studio or tag for its
+	// correlations to be used
+	MinSupport int
+	// Minimum proportion of the scenes with a performer, studio or tag that
+	// must also have the suggested tag
+	MinConfidence float64
+	// Maximum number of suggestions per scene. Zero for no limit
+	MaxPerScene int
+}
+
+func DefaultSuggestOptions() SuggestOptions {
+	return SuggestOptions{
+		MinSupport:    DefaultSuggestMinSupport,
+		MinConfidence: DefaultSuggestMinConfidence,
+		MaxPerScene:   DefaultSuggestMaxPerScene,
+	}
+}
+
+type featureType int
+
+const (
+	featurePerformer featureType = iota
+	featureStudio
+	featureTag
+)
+
+// feature is a performer, studio or tag of a scene.
+type feature struct {
+	Type featureType
+	ID   int
+}
+
+func sceneFeatures(s *models.SceneRelationshipIDs) []feature {
+	var ret []feature
+	for _, id := range s.PerformerIDs {
+		ret = append(ret, feature{featurePerformer, id})
+	}
+	if s.StudioID != nil {
+		ret = append(ret, feature{featureStudio, *s.StudioID})
+	}
+	for _, id := range s.TagIDs {
+		ret = append(ret, feature{featureTag, id})
+	}
+
+	return ret
+}
+
+// Suggest mines the co-occurrence of performers, studios and tags across
+// the provided scenes and suggests the tags that each scene is missing.
+//
+// A tag is suggested for a scene when, for one of the performers, the studio
+// or the tags of the scene, the proportion of the scenes sharing it that also
+// have the tag is at least MinConfidence. The confidence of a suggestion is
+// the highest such proportion. Suggestions are returned ordered by scene,
+// then by descending confidence.
+func Suggest(scenes []*models.SceneRelationshipIDs, options SuggestOptions) []models.TagSuggestion {
+	featureCount := make(map[feature]int)
+	coCount := make(map[feature]map[int]int)
+
+	for _, s := range scenes {
+		for _, f := range sceneFeatures(s) {
+			featureCount[f]++
+
+			co := coCount[f]
+			if co == nil {
+				co = make(map[int]int)
+				coCount[f] = co
+			}
+
+			for _, tagID := range s.TagIDs {
+				if f.Type != featureTag || f.ID != tagID {
+					co[tagID]++
+				}
+			}
+		}
+	}
+
+	var ret []models.TagSuggestion
+	for _, s := range scenes {
+		hasTag := make(map[int]bool)
+		for _, tagID := range s.TagIDs {
+			hasTag[tagID] = true
+		}
+
+		confidence := make(map[int]float64)
+		for _, f := range sceneFeatures(s) {
+			count := featureCount[f]
+			if count < options.MinSupport {
+				continue
+			}
+
+			for tagID, co := range coCount[f] {
+				c := float64(co) / float64(count)
+				if hasTag[tagID] || c < options.MinConfidence || c <= confidence[tagID] {
+					continue
+				}
+
+				confidence[tagID] = c
+			}
+		}
+
+		var suggestions []models.TagSuggestion
+		for tagID, c := range confidence {
+			suggestions = append(suggestions, models.TagSuggestion{
+				SceneID:    s.SceneID,
+				TagID:      tagID,
+				Confidence: c,
+			})
+		}
+
+		sort.Slice(suggestions, func(i, j int) bool {
+			if suggestions[i].Confidence != suggestions[j].Confidence {
+				return suggestions[i].Confidence > suggestions[j].Confidence
+			}
+			return suggestions[i].TagID < suggestions[j].TagID
+		})
+
+		if options.MaxPerScene > 0 && len(suggestions) > options.MaxPerScene {
+			suggestions = suggestions[:options.MaxPerScene]
+		}
+
+		ret = append(ret, suggestions...)
+	}
+
+	return ret
+}
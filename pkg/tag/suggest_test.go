@@ -0,0 +1,88 @@
+package tag
+
+import (
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuggest(t *testing.T) {
+	const (
+		performerID = 1
+		studioID    = 2
+
+		correlatedTagID = 10
+		otherTagID      = 11
+		impliedTagID    = 12
+		parentTagID     = 13
+	)
+
+	studio := studioID
+
+	tests := []struct {
+		name    string
+		scenes  []*models.SceneRelationshipIDs
+		options SuggestOptions
+		want    []models.TagSuggestion
+	}{
+		{
+			"performer correlation",
+			[]*models.SceneRelationshipIDs{
+				{SceneID: 1, PerformerIDs: []int{performerID}, TagIDs: []int{correlatedTagID, otherTagID}},
+				{SceneID: 2, PerformerIDs: []int{performerID}, TagIDs: []int{correlatedTagID}},
+				{SceneID: 3, PerformerIDs: []int{performerID}, TagIDs: []int{correlatedTagID}},
+				{SceneID: 4, PerformerIDs: []int{performerID}},
+			},
+			SuggestOptions{MinSupport: 4, MinConfidence: 0.7},
+			[]models.TagSuggestion{
+				{SceneID: 4, TagID: correlatedTagID, Confidence: 0.75},
+			},
+		},
+		{
+			"insufficient support",
+			[]*models.SceneRelationshipIDs{
+				{SceneID: 1, PerformerIDs: []int{performerID}, TagIDs: []int{correlatedTagID}},
+				{SceneID: 2, PerformerIDs: []int{performerID}, TagIDs: []int{correlatedTagID}},
+				{SceneID: 3, PerformerIDs: []int{performerID}},
+			},
+			SuggestOptions{MinSupport: 4, MinConfidence: 0.5},
+			nil,
+		},
+		{
+			"highest confidence of studio and tag",
+			[]*models.SceneRelationshipIDs{
+				{SceneID: 1, StudioID: &studio, TagIDs: []int{parentTagID, impliedTagID}},
+				{SceneID: 2, StudioID: &studio, TagIDs: []int{parentTagID, impliedTagID}},
+				{SceneID: 3, TagIDs: []int{parentTagID, impliedTagID}},
+				{SceneID: 4, StudioID: &studio},
+				{SceneID: 5, StudioID: &studio, TagIDs: []int{parentTagID}},
+			},
+			SuggestOptions{MinSupport: 4, MinConfidence: 0.5},
+			[]models.TagSuggestion{
+				{SceneID: 4, TagID: parentTagID, Confidence: 0.75},
+				{SceneID: 4, TagID: impliedTagID, Confidence: 0.5},
+				{SceneID: 5, TagID: impliedTagID, Confidence: 0.75},
+			},
+		},
+		{
+			"max per scene",
+			[]*models.SceneRelationshipIDs{
+				{SceneID: 1, PerformerIDs: []int{performerID}, TagIDs: []int{correlatedTagID, otherTagID}},
+				{SceneID: 2, PerformerIDs: []int{performerID}, TagIDs: []int{correlatedTagID, otherTagID}},
+				{SceneID: 3, PerformerIDs: []int{performerID}},
+			},
+			SuggestOptions{MinSupport: 3, MinConfidence: 0.5, MaxPerScene: 1},
+			[]models.TagSuggestion{
+				{SceneID: 3, TagID: correlatedTagID, Confidence: 2.0 / 3},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Suggest(tt.scenes, tt.options)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,284 @@
+// Package theme implements functions and types for loading user-provided
+// theme packs.
+//
+// Theme packs are subdirectories of the configured themes directory
+// containing a theme.yml file. The theme.yml file must follow the Config
+// structure format. The CSS variables, CSS files and assets of the active
+// theme are served to the UI, so that themes do not require rebuilding the
+// UI bundle.
+package theme
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/logger"
+	"gopkg.in/yaml.v2"
+)
+
+// ConfigFilename is the name of the configuration file of a theme pack.
+const ConfigFilename = "theme.yml"
+
+var ErrNotFound = errors.New("theme not found")
+
+var (
+	validID           = regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)
+	validVariableName = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+)
+
+// Config describes the configuration of a single theme pack.
+type Config struct {
+	id string
+
+	// path to the configuration file
+	path string
+
+	// The name of the theme. This will be displayed in the UI.
+	Name string `yaml:"name"`
+
+	// An optional description of the theme.
+	Description *string `yaml:"description"`
+
+	// An optional version string.
+	Version *string `yaml:"version"`
+
+	// CSS files that will be injected into the stash UI, relative to the
+	// theme directory.
+	CSS []string `yaml:"css"`
+
+	// CSS custom properties that will be set on the root element. Names are
+	// provided without the leading dashes.
+	Variables map[string]string `yaml:"variables"`
+
+	// Asset overrides such as logos and card badge icons, keyed by asset
+	// name. Paths are relative to the theme directory.
+	Assets map[string]string `yaml:"assets"`
+}
+
+type Theme struct {
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	Description *string `json:"description"`
+	Version     *string `json:"version"`
+	Active      bool    `json:"active"`
+	// Names of the overridden assets
+	Assets []string `json:"assets"`
+}
+
+func (c Config) ID() string {
+	return c.id
+}
+
+// Path returns the path of the configuration file.
+func (c Config) Path() string {
+	return c.path
+}
+
+func (c Config) getThemePath() string {
+	return filepath.Dir(c.path)
+}
+
+func (c Config) getName() string {
+	if c.Name != "" {
+		return c.Name
+	}
+
+	return c.id
+}
+
+// resolvePath returns the absolute path of a file in the theme directory.
+// Returns an error if the path is outside of the theme directory.
+func (c Config) resolvePath(p string) (string, error) {
+	dir := c.getThemePath()
+	ret := filepath.Join(dir, filepath.FromSlash(p))
+
+	if filepath.IsAbs(p) || !fsutil.IsPathInDir(dir, ret) {
+		return "", fmt.Errorf("path %s is outside of the theme directory", p)
+	}
+
+	return ret, nil
+}
+
+// CSSFiles returns the paths of the CSS files of the theme.
+func (c Config) CSSFiles() []string {
+	ret := make([]string, 0, len(c.CSS))
+	for _, v := range c.CSS {
+		// paths are validated on load
+		p, _ := c.resolvePath(v)
+		ret = append(ret, p)
+	}
+
+	return ret
+}
+
+// CSSVariables returns a CSS rule setting the variables of the theme on the
+// root element. Returns an empty string if the theme has no variables.
+func (c Config) CSSVariables() string {
+	if len(c.Variables) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(c.Variables))
+	for k := range c.Variables {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	sb.WriteString(":root {\n")
+	for _, k := range names {
+		fmt.Fprintf(&sb, "  --%s: %s;\n", k, c.Variables[k])
+	}
+	sb.WriteString("}\n")
+
+	return sb.String()
+}
+
+// AssetPath returns the path of the asset with the provided name. Returns an
+// empty string if the theme does not override the asset.
+func (c Config) AssetPath(name string) string {
+	v, found := c.Assets[name]
+	if !found {
+		return ""
+	}
+
+	// paths are validated on load
+	ret, _ := c.resolvePath(v)
+	return ret
+}
+
+func (c Config) ToTheme(active bool) *Theme {
+	assets := make([]string, 0, len(c.Assets))
+	for k := range c.Assets {
+		assets = append(assets, k)
+	}
+	sort.Strings(assets)
+
+	return &Theme{
+		ID:          c.id,
+		Name:        c.getName(),
+		Description: c.Description,
+		Version:     c.Version,
+		Active:      active,
+		Assets:      assets,
+	}
+}
+
+func (c Config) validate() error {
+	for _, v := range c.CSS {
+		if _, err := c.resolvePath(v); err != nil {
+			return err
+		}
+	}
+
+	for k, v := range c.Assets {
+		if _, err := c.resolvePath(v); err != nil {
+			return fmt.Errorf("asset %s: %w", k, err)
+		}
+	}
+
+	for k, v := range c.Variables {
+		if !validVariableName.MatchString(k) {
+			return fmt.Errorf("invalid variable name %q", k)
+		}
+
+		// prevent values from escaping the declaration
+		if strings.ContainsAny(v, ";{}<>") {
+			return fmt.Errorf("invalid value for variable %s", k)
+		}
+	}
+
+	return nil
+}
+
+func loadThemeFromYAML(reader io.Reader) (*Config, error) {
+	ret := &Config{}
+
+	parser := yaml.NewDecoder(reader)
+	parser.SetStrict(true)
+	// an empty file is a valid configuration
+	if err := parser.Decode(&ret); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func loadThemeFromYAMLFile(path string) (*Config, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	ret, err := loadThemeFromYAML(file)
+	if err != nil {
+		return nil, err
+	}
+
+	// set id to the name of the theme directory
+	ret.id = filepath.Base(filepath.Dir(path))
+	ret.path = path
+
+	if err := ret.validate(); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+// LoadTheme loads the theme with the provided id from the themes directory.
+// Returns ErrNotFound if the theme does not exist.
+func LoadTheme(path string, id string) (*Config, error) {
+	if !validID.MatchString(id) || id == "." || id == ".." {
+		return nil, ErrNotFound
+	}
+
+	fn := filepath.Join(path, id, ConfigFilename)
+	if exists, _ := fsutil.FileExists(fn); !exists {
+		return nil, ErrNotFound
+	}
+
+	return loadThemeFromYAMLFile(fn)
+}
+
+// LoadThemes loads the themes in the themes directory. Invalid themes are
+// logged and ignored. Returns an empty slice if the directory does not exist.
+func LoadThemes(path string) ([]Config, error) {
+	ret := make([]Config, 0)
+
+	if exists, _ := fsutil.DirExists(path); !exists {
+		return ret, nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+
+		t, err := LoadTheme(path, e.Name())
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			logger.Errorf("Error loading theme %s: %v", e.Name(), err)
+			continue
+		}
+
+		ret = append(ret, *t)
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,110 @@
+package theme
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeTheme(t *testing.T, dir string, id string, config string) {
+	t.Helper()
+
+	themeDir := filepath.Join(dir, id)
+	if err := os.MkdirAll(themeDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(themeDir, ConfigFilename), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadThemes(t *testing.T) {
+	dir := t.TempDir()
+
+	writeTheme(t, dir, "dark", `
+name: Dark
+css:
+  - theme.css
+variables:
+  primary-color: "#ff0000"
+  card-radius: 4px
+assets:
+  logo: images/logo.svg
+`)
+	writeTheme(t, dir, "unnamed", ``)
+	writeTheme(t, dir, "escape", `
+assets:
+  logo: ../../logo.svg
+`)
+	writeTheme(t, dir, "injection", `
+variables:
+  primary-color: "red; } body { display: none"
+`)
+	writeTheme(t, dir, "unknown", `
+unknown: true
+`)
+	// directories without a config file are ignored
+	if err := os.MkdirAll(filepath.Join(dir, "empty"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	themes, err := LoadThemes(dir)
+	if err != nil {
+		t.Fatalf("LoadThemes() error = %v", err)
+	}
+
+	if !assert.Len(t, themes, 2) {
+		return
+	}
+
+	dark := themes[0]
+	assert.Equal(t, "dark", dark.ID())
+	assert.Equal(t, []string{filepath.Join(dir, "dark", "theme.css")}, dark.CSSFiles())
+	assert.Equal(t, ":root {\n  --card-radius: 4px;\n  --primary-color: #ff0000;\n}\n", dark.CSSVariables())
+	assert.Equal(t, filepath.Join(dir, "dark", "images", "logo.svg"), dark.AssetPath("logo"))
+	assert.Equal(t, "", dark.AssetPath("favicon"))
+	assert.Equal(t, &Theme{
+		ID:     "dark",
+		Name:   "Dark",
+		Active: true,
+		Assets: []string{"logo"},
+	}, dark.ToTheme(true))
+
+	unnamed := themes[1]
+	assert.Equal(t, "unnamed", unnamed.ToTheme(false).Name)
+	assert.Equal(t, "", unnamed.CSSVariables())
+}
+
+func TestLoadThemesMissingDirectory(t *testing.T) {
+	themes, err := LoadThemes(filepath.Join(t.TempDir(), "missing"))
+	assert.Nil(t, err)
+	assert.Empty(t, themes)
+}
+
+func TestLoadTheme(t *testing.T) {
+	dir := t.TempDir()
+	writeTheme(t, dir, "dark", `name: Dark`)
+
+	tests := []struct {
+		id      string
+		wantErr error
+	}{
+		{"dark", nil},
+		{"light", ErrNotFound},
+		{"..", ErrNotFound},
+		{"../dark", ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			got, err := LoadTheme(dir, tt.id)
+			assert.ErrorIs(t, err, tt.wantErr)
+			if tt.wantErr == nil && assert.NotNil(t, got) {
+				assert.Equal(t, tt.id, got.ID())
+			}
+		})
+	}
+}
@@ -0,0 +1,345 @@
+// Package upload stores resumable uploads, written in chunks over multiple
+// requests.
+package upload
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stashapp/stash/pkg/fsutil"
+	"github.com/stashapp/stash/pkg/hash"
+)
+
+var (
+	ErrNotFound       = errors.New("upload not found")
+	ErrOffsetMismatch = errors.New("offset does not match the upload offset")
+	ErrTooLarge       = errors.New("data exceeds the upload length")
+	ErrIncomplete     = errors.New("upload is incomplete")
+	ErrLocked         = errors.New("upload is being written by another request")
+	ErrInvalidName    = errors.New("invalid filename")
+)
+
+const (
+	infoExt = ".json"
+	partExt = ".part"
+)
+
+var idRE = regexp.MustCompile(`^[0-9a-f]+$`)
+
+// Info is the state of an upload.
+type Info struct {
+	ID       string `json:"id"`
+	Filename string `json:"filename"`
+	// Length is the total size of the upload in bytes
+	Length int64 `json:"length"`
+	// Offset is the number of bytes received
+	Offset    int64     `json:"offset"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Complete returns true if all bytes of the upload have been received.
+func (i Info) Complete() bool {
+	return i.Offset == i.Length
+}
+
+// Store stores in-progress uploads in a directory. Each upload consists of
+// a json file with its state and a part file with the bytes received so far.
+type Store struct {
+	dir string
+
+	mutex sync.Mutex
+	// ids of uploads being written
+	locked map[string]bool
+}
+
+func NewStore(dir string) *Store {
+	return &Store{
+		dir:    dir,
+		locked: make(map[string]bool),
+	}
+}
+
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+func (s *Store) infoPath(id string) string {
+	return filepath.Join(s.dir, id+infoExt)
+}
+
+func (s *Store) partPath(id string) string {
+	return filepath.Join(s.dir, id+partExt)
+}
+
+// ValidateFilename returns an error if the filename cannot be used as the
+// name of an uploaded file.
+func ValidateFilename(filename string) error {
+	if filename == "" || filename == "." || filename == ".." || strings.ContainsAny(filename, `/\`) {
+		return fmt.Errorf("%w: %q", ErrInvalidName, filename)
+	}
+
+	return nil
+}
+
+// Create creates a new empty upload of a file with the provided name and
+// length.
+func (s *Store) Create(filename string, length int64) (*Info, error) {
+	if err := ValidateFilename(filename); err != nil {
+		return nil, err
+	}
+
+	if length < 0 {
+		return nil, fmt.Errorf("invalid upload length %d", length)
+	}
+
+	if err := fsutil.EnsureDirAll(s.dir); err != nil {
+		return nil, err
+	}
+
+	id, err := hash.GenerateRandomKey(16)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Create(s.partPath(id))
+	if err != nil {
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+
+	ret := &Info{
+		ID:        id,
+		Filename:  filename,
+		Length:    length,
+		CreatedAt: time.Now(),
+	}
+
+	if err := s.writeInfo(ret); err != nil {
+		return nil, err
+	}
+
+	return ret, nil
+}
+
+func (s *Store) writeInfo(info *Info) error {
+	data, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(s.infoPath(info.ID), data, 0644)
+}
+
+// Get returns the state of the upload with the provided id.
+func (s *Store) Get(id string) (*Info, error) {
+	if !idRE.MatchString(id) {
+		return nil, ErrNotFound
+	}
+
+	data, err := os.ReadFile(s.infoPath(id))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	var ret Info
+	if err := json.Unmarshal(data, &ret); err != nil {
+		return nil, fmt.Errorf("reading upload %s: %w", id, err)
+	}
+
+	return &ret, nil
+}
+
+func (s *Store) lock(id string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.locked[id] {
+		return ErrLocked
+	}
+
+	s.locked[id] = true
+	return nil
+}
+
+func (s *Store) unlock(id string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.locked, id)
+}
+
+// Write appends the data read from r to the upload, which must have
+// received exactly offset bytes. The bytes read before an error are kept, so
+// that the upload can be resumed from the returned offset. ErrTooLarge is
+// returned if r contains more data than the remaining length of the upload.
+func (s *Store) Write(id string, offset int64, r io.Reader) (*Info, error) {
+	if err := s.lock(id); err != nil {
+		return nil, err
+	}
+	defer s.unlock(id)
+
+	info, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if offset != info.Offset {
+		return info, ErrOffsetMismatch
+	}
+
+	f, err := os.OpenFile(s.partPath(id), os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// discard bytes written after the last recorded offset, such as by a
+	// request interrupted before its offset was saved
+	if err := f.Truncate(info.Offset); err != nil {
+		return nil, err
+	}
+	if _, err := f.Seek(info.Offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	n, copyErr := io.Copy(f, io.LimitReader(r, info.Length-info.Offset))
+	if copyErr == nil && n == info.Length-info.Offset {
+		// ensure there is no more data
+		var b [1]byte
+		if m, _ := r.Read(b[:]); m > 0 {
+			copyErr = ErrTooLarge
+		}
+	}
+
+	if err := f.Close(); err != nil && copyErr == nil {
+		copyErr = err
+	}
+
+	if n > 0 {
+		info.Offset += n
+		if err := s.writeInfo(info); err != nil {
+			return nil, err
+		}
+	}
+
+	return info, copyErr
+}
+
+// Move moves the file of the completed upload to dir and removes the upload.
+// The file is renamed if a file with the same name already exists in dir.
+// Returns the path of the moved file.
+func (s *Store) Move(id string, dir string) (string, error) {
+	if err := s.lock(id); err != nil {
+		return "", err
+	}
+	defer s.unlock(id)
+
+	info, err := s.Get(id)
+	if err != nil {
+		return "", err
+	}
+
+	if !info.Complete() {
+		return "", ErrIncomplete
+	}
+
+	dest, err := availablePath(dir, info.Filename)
+	if err != nil {
+		return "", err
+	}
+
+	if err := fsutil.SafeMove(s.partPath(id), dest); err != nil {
+		return "", err
+	}
+
+	if err := os.Remove(s.infoPath(id)); err != nil {
+		return "", err
+	}
+
+	return dest, nil
+}
+
+// availablePath returns the path of filename in dir, with a numeric suffix
+// added to the name if the path already exists.
+func availablePath(dir string, filename string) (string, error) {
+	ext := filepath.Ext(filename)
+	name := strings.TrimSuffix(filename, ext)
+
+	ret := filepath.Join(dir, filename)
+	for i := 1; ; i++ {
+		if _, err := os.Lstat(ret); err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return ret, nil
+			}
+			return "", err
+		}
+
+		ret = filepath.Join(dir, fmt.Sprintf("%s (%d)%s", name, i, ext))
+	}
+}
+
+// Delete removes the upload and the bytes received.
+func (s *Store) Delete(id string) error {
+	if err := s.lock(id); err != nil {
+		return err
+	}
+	defer s.unlock(id)
+
+	if _, err := s.Get(id); err != nil {
+		return err
+	}
+
+	if err := os.Remove(s.partPath(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return os.Remove(s.infoPath(id))
+}
+
+// DeleteCreatedBefore removes the uploads created before t, which are
+// assumed to have been abandoned. Returns the number of removed uploads.
+func (s *Store) DeleteCreatedBefore(t time.Time) (int, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	ret := 0
+	for _, e := range entries {
+		id := strings.TrimSuffix(e.Name(), infoExt)
+		if id == e.Name() {
+			continue
+		}
+
+		info, err := s.Get(id)
+		if err != nil || !info.CreatedAt.Before(t) {
+			continue
+		}
+
+		if err := s.Delete(id); err != nil {
+			if errors.Is(err, ErrLocked) {
+				continue
+			}
+			return ret, err
+		}
+		ret++
+	}
+
+	return ret, nil
+}
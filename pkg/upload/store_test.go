@@ -0,0 +1,122 @@
+package upload
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore_Resume(t *testing.T) {
+	dir := t.TempDir()
+	s := NewStore(filepath.Join(dir, "uploads"))
+
+	info, err := s.Create("a.mp4", 6)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	info, err = s.Write(info.ID, 0, strings.NewReader("abc"))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), info.Offset)
+	assert.False(t, info.Complete())
+
+	// stale offset
+	info, err = s.Write(info.ID, 0, strings.NewReader("abc"))
+	assert.ErrorIs(t, err, ErrOffsetMismatch)
+	assert.Equal(t, int64(3), info.Offset)
+
+	_, err = s.Move(info.ID, dir)
+	assert.ErrorIs(t, err, ErrIncomplete)
+
+	// resume from a new store, as after a restart
+	s = NewStore(s.Dir())
+	info, err = s.Get(info.ID)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, int64(3), info.Offset)
+
+	info, err = s.Write(info.ID, 3, strings.NewReader("def"))
+	assert.NoError(t, err)
+	assert.True(t, info.Complete())
+
+	p, err := s.Move(info.ID, dir)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, filepath.Join(dir, "a.mp4"), p)
+
+	data, err := os.ReadFile(p)
+	assert.NoError(t, err)
+	assert.Equal(t, "abcdef", string(data))
+
+	_, err = s.Get(info.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestStore_WriteTooLarge(t *testing.T) {
+	s := NewStore(t.TempDir())
+
+	info, err := s.Create("a.mp4", 2)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	info, err = s.Write(info.ID, 0, strings.NewReader("abc"))
+	assert.ErrorIs(t, err, ErrTooLarge)
+	assert.Equal(t, int64(2), info.Offset)
+}
+
+func TestStore_MoveExisting(t *testing.T) {
+	dir := t.TempDir()
+	s := NewStore(filepath.Join(dir, "uploads"))
+
+	if err := os.WriteFile(filepath.Join(dir, "a.mp4"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := s.Create("a.mp4", 0)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	p, err := s.Move(info.ID, dir)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "a (1).mp4"), p)
+}
+
+func TestStore_Create(t *testing.T) {
+	s := NewStore(t.TempDir())
+
+	for _, name := range []string{"", ".", "..", "../a.mp4", `a\b.mp4`} {
+		_, err := s.Create(name, 1)
+		assert.ErrorIs(t, err, ErrInvalidName, name)
+	}
+
+	_, err := s.Create("a.mp4", -1)
+	assert.Error(t, err)
+}
+
+func TestStore_DeleteCreatedBefore(t *testing.T) {
+	s := NewStore(t.TempDir())
+
+	info, err := s.Create("a.mp4", 1)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	n, err := s.DeleteCreatedBefore(info.CreatedAt)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+
+	n, err = s.DeleteCreatedBefore(time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	_, err = s.Get(info.ID)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
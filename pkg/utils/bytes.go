@@ -0,0 +1,20 @@
+package utils
+
+import "fmt"
+
+// FormatBytes returns a human readable representation of a size in bytes,
+// using binary units. For example, 1536 is formatted as "1.5 KiB".
+func FormatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,13 @@
+package utils
+
+import "fmt"
+
+func ExampleFormatBytes() {
+	fmt.Println(FormatBytes(512))
+	fmt.Println(FormatBytes(1536))
+	fmt.Println(FormatBytes(5 << 30))
+	// Output:
+	// 512 B
+	// 1.5 KiB
+	// 5.0 GiB
+}
@@ -8,8 +8,10 @@ import (
 )
 
 type Phash struct {
-	SceneID   int   `db:"id"`
-	Hash      int64 `db:"phash"`
+	SceneID int   `db:"id"`
+	Hash    int64 `db:"phash"`
+	// Duration of the file in seconds
+	Duration  float64 `db:"duration"`
 	Neighbors []int
 	Bucket    int
 }
@@ -56,6 +58,14 @@ func findNeighbors(bucket int, neighbors []int, hashes []*Phash, scenes *[]int)
 	}
 }
 
+// PhashDistance returns the hamming distance between the two phashes.
+func PhashDistance(a int64, b int64) int {
+	hashA := goimagehash.NewImageHash(uint64(a), goimagehash.PHash)
+	hashB := goimagehash.NewImageHash(uint64(b), goimagehash.PHash)
+	distance, _ := hashA.Distance(hashB)
+	return distance
+}
+
 func PhashToString(phash int64) string {
 	return strconv.FormatUint(uint64(phash), 16)
 }
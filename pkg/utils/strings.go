@@ -31,3 +31,62 @@ func StrFormat(format string, m StrFormatMap) string {
 
 	return strings.NewReplacer(args...).Replace(format)
 }
+
+// StrSimilarity returns the similarity of a and b, between 0 and 1. The
+// similarity is one minus the edit distance of the strings divided by the
+// length of the longer string. Returns 0 without calculating the edit
+// distance if the difference in length alone makes the similarity lower than
+// min.
+func StrSimilarity(as, bs string, min float64) float64 {
+	a, b := []rune(as), []rune(bs)
+	longest := len(a)
+	if len(b) > longest {
+		longest = len(b)
+	}
+	if longest == 0 {
+		return 1
+	}
+
+	diff := len(a) - len(b)
+	if diff < 0 {
+		diff = -diff
+	}
+	if 1-float64(diff)/float64(longest) < min {
+		return 0
+	}
+
+	return 1 - float64(editDistance(a, b))/float64(longest)
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			cur[j] = minInt(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+
+	return prev[len(b)]
+}
+
+func minInt(v int, others ...int) int {
+	for _, o := range others {
+		if o < v {
+			v = o
+		}
+	}
+	return v
+}
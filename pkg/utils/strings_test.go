@@ -1,6 +1,11 @@
 package utils
 
-import "fmt"
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
 
 func ExampleStrFormat() {
 	fmt.Println(StrFormat("{foo} bar {baz}", StrFormatMap{
@@ -10,3 +15,25 @@ func ExampleStrFormat() {
 	// Output:
 	// bar bar abc
 }
+
+func TestStrSimilarity(t *testing.T) {
+	tests := []struct {
+		a    string
+		b    string
+		min  float64
+		want float64
+	}{
+		{"", "", 0.5, 1},
+		{"abcd", "abcd", 0.5, 1},
+		{"abcd", "abce", 0.5, 0.75},
+		{"abcd", "ab", 0.5, 0.5},
+		// length difference alone is too large
+		{"abcd", "a", 0.5, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"-"+tt.b, func(t *testing.T) {
+			assert.Equal(t, tt.want, StrSimilarity(tt.a, tt.b, tt.min))
+		})
+	}
+}
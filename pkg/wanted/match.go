@@ -0,0 +1,227 @@
+// Package wanted provides matching of wanted scenes against the library.
+package wanted
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scene"
+	"github.com/stashapp/stash/pkg/utils"
+)
+
+type SceneFinder interface {
+	scene.Queryer
+	FindByChecksum(ctx context.Context, checksum string) ([]*models.Scene, error)
+	FindByOSHash(ctx context.Context, oshash string) ([]*models.Scene, error)
+}
+
+type ReaderWriter interface {
+	FindUnfulfilled(ctx context.Context) ([]*models.WantedScene, error)
+	Update(ctx context.Context, obj models.WantedScene) (*models.WantedScene, error)
+}
+
+// Fulfillment is a wanted scene that was matched to a scene in the library.
+type Fulfillment struct {
+	Wanted *models.WantedScene
+	Scene  *models.Scene
+	// Reason describes how the scene was matched
+	Reason string
+}
+
+type Matcher struct {
+	SceneFinder SceneFinder
+	Wanted      ReaderWriter
+}
+
+// Match matches all unfulfilled wanted scenes against the scenes in the
+// library, marking the matched items as fulfilled. It must be called within
+// a transaction. Returns the items that were fulfilled.
+func (m Matcher) Match(ctx context.Context) ([]Fulfillment, error) {
+	items, err := m.Wanted.FindUnfulfilled(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("finding wanted scenes: %w", err)
+	}
+
+	var ret []Fulfillment
+	for _, w := range items {
+		s, reason, err := m.findMatch(ctx, w)
+		if err != nil {
+			return nil, fmt.Errorf("matching wanted scene %s: %w", w.DisplayName(), err)
+		}
+
+		if s == nil {
+			continue
+		}
+
+		now := time.Now()
+		updated := *w
+		updated.SceneID = &s.ID
+		updated.FulfilledAt = &now
+		updated.UpdatedAt = now
+
+		fulfilled, err := m.Wanted.Update(ctx, updated)
+		if err != nil {
+			return nil, fmt.Errorf("updating wanted scene %s: %w", w.DisplayName(), err)
+		}
+
+		ret = append(ret, Fulfillment{
+			Wanted: fulfilled,
+			Scene:  s,
+			Reason: reason,
+		})
+	}
+
+	return ret, nil
+}
+
+// findMatch returns the first scene matching the wanted scene, in order of
+// match confidence: stash ID, URL, file fingerprints, then title.
+func (m Matcher) findMatch(ctx context.Context, w *models.WantedScene) (*models.Scene, string, error) {
+	if w.StashID != nil && *w.StashID != "" {
+		f := &models.SceneFilterType{
+			StashIDEndpoint: &models.StashIDCriterionInput{
+				Endpoint: w.StashBoxEndpoint,
+				StashID:  w.StashID,
+				Modifier: models.CriterionModifierEquals,
+			},
+		}
+		if s, err := m.queryFirst(ctx, f); s != nil || err != nil {
+			return s, "stash ID", err
+		}
+	}
+
+	if w.URL != nil && *w.URL != "" {
+		f := &models.SceneFilterType{
+			URL: &models.StringCriterionInput{
+				Value:    *w.URL,
+				Modifier: models.CriterionModifierEquals,
+			},
+		}
+		if s, err := m.queryFirst(ctx, f); s != nil || err != nil {
+			return s, "URL", err
+		}
+	}
+
+	if w.Checksum != nil && *w.Checksum != "" {
+		scenes, err := m.SceneFinder.FindByChecksum(ctx, *w.Checksum)
+		if len(scenes) > 0 || err != nil {
+			return first(scenes), "checksum", err
+		}
+	}
+
+	if w.OSHash != nil && *w.OSHash != "" {
+		scenes, err := m.SceneFinder.FindByOSHash(ctx, *w.OSHash)
+		if len(scenes) > 0 || err != nil {
+			return first(scenes), "oshash", err
+		}
+	}
+
+	if w.Phash != nil && *w.Phash != 0 {
+		f := &models.SceneFilterType{
+			Phash: &models.StringCriterionInput{
+				Value:    utils.PhashToString(*w.Phash),
+				Modifier: models.CriterionModifierEquals,
+			},
+		}
+		if s, err := m.queryFirst(ctx, f); s != nil || err != nil {
+			return s, "phash", err
+		}
+	}
+
+	if w.Title != nil && strings.TrimSpace(*w.Title) != "" {
+		return m.findTitleMatch(ctx, *w.Title)
+	}
+
+	return nil, "", nil
+}
+
+func (m Matcher) findTitleMatch(ctx context.Context, title string) (*models.Scene, string, error) {
+	f := &models.SceneFilterType{
+		Title: &models.StringCriterionInput{
+			Value:    title,
+			Modifier: models.CriterionModifierEquals,
+		},
+	}
+	if s, err := m.queryFirst(ctx, f); s != nil || err != nil {
+		return s, "title", err
+	}
+
+	// newly scanned scenes have no title, so fall back to the filename.
+	// Narrow the candidates using the longest word in the title.
+	var longest string
+	for _, w := range strings.Fields(normalizeTitle(title)) {
+		if len(w) > len(longest) {
+			longest = w
+		}
+	}
+
+	f = &models.SceneFilterType{
+		Path: &models.StringCriterionInput{
+			Value:    longest,
+			Modifier: models.CriterionModifierIncludes,
+		},
+	}
+	allPages := -1
+	scenes, err := scene.Query(ctx, m.SceneFinder, f, &models.FindFilterType{
+		PerPage: &allPages,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	for _, s := range scenes {
+		if TitleMatchesPath(title, s.Path) {
+			return s, "filename", nil
+		}
+	}
+
+	return nil, "", nil
+}
+
+// queryFirst returns the first scene matching f. Scenes without files, such
+// as those created manually, are not considered, since they do not fulfill a
+// wanted scene.
+func (m Matcher) queryFirst(ctx context.Context, f *models.SceneFilterType) (*models.Scene, error) {
+	f.FileCount = &models.IntCriterionInput{
+		Value:    0,
+		Modifier: models.CriterionModifierGreaterThan,
+	}
+
+	perPage := 1
+	scenes, err := scene.Query(ctx, m.SceneFinder, f, &models.FindFilterType{
+		PerPage: &perPage,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return first(scenes), nil
+}
+
+func first(scenes []*models.Scene) *models.Scene {
+	if len(scenes) == 0 {
+		return nil
+	}
+	return scenes[0]
+}
+
+var titleSeparatorRE = regexp.MustCompile(`[\s._\-]+`)
+
+func normalizeTitle(s string) string {
+	return strings.TrimSpace(titleSeparatorRE.ReplaceAllString(strings.ToLower(s), " "))
+}
+
+// TitleMatchesPath returns true if the filename of p, without its extension,
+// matches title. Case and separator characters are ignored.
+func TitleMatchesPath(title string, p string) bool {
+	base := filepath.Base(p)
+	base = strings.TrimSuffix(base, filepath.Ext(base))
+
+	t := normalizeTitle(title)
+	return t != "" && normalizeTitle(base) == t
+}
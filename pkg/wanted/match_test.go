@@ -0,0 +1,31 @@
+package wanted
+
+import (
+	"testing"
+)
+
+func TestTitleMatchesPath(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+		path  string
+		want  bool
+	}{
+		{"exact", "Scene Title", "/stash/Scene Title.mp4", true},
+		{"case", "scene title", "/stash/Scene Title.mp4", true},
+		{"dots", "Scene Title", "/stash/scene.title.mkv", true},
+		{"underscores and dashes", "Scene - Title", "/stash/scene_-_title.mkv", true},
+		{"no extension", "Scene Title", "/stash/Scene Title", true},
+		{"partial", "Scene", "/stash/Scene Title.mp4", false},
+		{"directory", "Scene Title", "/Scene Title/other.mp4", false},
+		{"empty title", "", "/stash/.mp4", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TitleMatchesPath(tt.title, tt.path); got != tt.want {
+				t.Errorf("TitleMatchesPath(%q, %q) = %v, want %v", tt.title, tt.path, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package wanted
+
+import (
+	"strings"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scraper"
+)
+
+// ApplyScrapedScene sets the metadata of the wanted scene from the scene
+// scraped from its URL, and marks the scrape as complete. Fields that are
+// already set are not overwritten.
+func ApplyScrapedScene(w *models.WantedScene, s *scraper.ScrapedScene) {
+	setIfEmpty := func(dest **string, v *string) {
+		if *dest != nil || v == nil || strings.TrimSpace(*v) == "" {
+			return
+		}
+		vv := strings.TrimSpace(*v)
+		*dest = &vv
+	}
+
+	setIfEmpty(&w.Title, s.Title)
+	setIfEmpty(&w.Details, s.Details)
+	setIfEmpty(&w.Date, s.Date)
+
+	status := models.WantedSceneScrapeStatusComplete
+	w.ScrapeStatus = &status
+	w.ScrapeError = nil
+}
+
+// SetScrapeFailed marks the scrape of the wanted scene as failed.
+func SetScrapeFailed(w *models.WantedScene, err error) {
+	status := models.WantedSceneScrapeStatusFailed
+	msg := err.Error()
+	w.ScrapeStatus = &status
+	w.ScrapeError = &msg
+}
@@ -0,0 +1,49 @@
+package wanted
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stashapp/stash/pkg/models"
+	"github.com/stashapp/stash/pkg/scraper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyScrapedScene(t *testing.T) {
+	title := "existing title"
+	scrapedTitle := "scraped title"
+	details := " scraped details "
+	date := "2022-01-02"
+	blank := " "
+	scrapeErr := "previous error"
+
+	w := &models.WantedScene{
+		Title:       &title,
+		ScrapeError: &scrapeErr,
+	}
+
+	ApplyScrapedScene(w, &scraper.ScrapedScene{
+		Title:    &scrapedTitle,
+		Details:  &details,
+		Date:     &date,
+		Director: &blank,
+	})
+
+	assert.Equal(t, "existing title", *w.Title)
+	assert.Equal(t, "scraped details", *w.Details)
+	assert.Equal(t, "2022-01-02", *w.Date)
+	assert.Equal(t, models.WantedSceneScrapeStatusComplete, *w.ScrapeStatus)
+	assert.Nil(t, w.ScrapeError)
+
+	empty := &models.WantedScene{}
+	ApplyScrapedScene(empty, &scraper.ScrapedScene{Title: &blank})
+	assert.Nil(t, empty.Title)
+}
+
+func TestSetScrapeFailed(t *testing.T) {
+	w := &models.WantedScene{}
+	SetScrapeFailed(w, errors.New("not found"))
+
+	assert.Equal(t, models.WantedSceneScrapeStatusFailed, *w.ScrapeStatus)
+	assert.Equal(t, "not found", *w.ScrapeError)
+}
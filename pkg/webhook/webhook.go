@@ -0,0 +1,253 @@
+// Package webhook provides a dispatcher posting library events to
+// configured webhook URLs.
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/stashapp/stash/pkg/logger"
+)
+
+type Event string
+
+const (
+	EventSceneCreated     Event = "scene.created"
+	EventScanFinished     Event = "scan.finished"
+	EventGenerateFinished Event = "generate.finished"
+	EventPluginTaskFailed Event = "plugin_task.failed"
+)
+
+var AllEvents = []Event{
+	EventSceneCreated,
+	EventScanFinished,
+	EventGenerateFinished,
+	EventPluginTaskFailed,
+}
+
+func (e Event) IsValid() bool {
+	switch e {
+	case EventSceneCreated, EventScanFinished, EventGenerateFinished, EventPluginTaskFailed:
+		return true
+	}
+	return false
+}
+
+const (
+	// EventHeader is the header containing the event of the payload.
+	EventHeader = "X-Stash-Event"
+	// SignatureHeader is the header containing the signature of the payload
+	// if the webhook has a secret.
+	SignatureHeader = "X-Stash-Signature"
+)
+
+const (
+	requestTimeout     = 30 * time.Second
+	defaultMaxAttempts = 5
+	defaultBackoff     = 2 * time.Second
+)
+
+// Config is the configuration of a webhook.
+type Config struct {
+	URL string `json:"url" mapstructure:"url"`
+	// Events is the events the webhook is called for. The webhook is
+	// called for all events if empty.
+	Events []Event `json:"events" mapstructure:"events"`
+	// Secret is the key used to sign the payloads. Payloads are not signed
+	// if empty.
+	Secret string `json:"secret" mapstructure:"secret"`
+}
+
+// Validate returns an error if the configuration is invalid.
+func (c Config) Validate() error {
+	u, err := url.Parse(c.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid url %q", c.URL)
+	}
+
+	for _, e := range c.Events {
+		if !e.IsValid() {
+			return fmt.Errorf("invalid event %q", e)
+		}
+	}
+
+	return nil
+}
+
+func (c Config) handles(e Event) bool {
+	if len(c.Events) == 0 {
+		return true
+	}
+
+	for _, ee := range c.Events {
+		if ee == e {
+			return true
+		}
+	}
+	return false
+}
+
+// Payload is the JSON body posted to webhooks.
+type Payload struct {
+	Event Event     `json:"event"`
+	Time  time.Time `json:"time"`
+	// Content is a description of the event, which is shown as the message
+	// by Discord webhooks.
+	Content string      `json:"content"`
+	Data    interface{} `json:"data"`
+}
+
+// Sign returns the signature of the body using the secret, which is the hex
+// encoded HMAC-SHA256 of the body prefixed with "sha256=".
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// errPermanent wraps errors that are not resolved by retrying.
+type errPermanent struct {
+	error
+}
+
+// Dispatcher posts events to the configured webhooks in the background,
+// retrying failed requests with exponential backoff.
+type Dispatcher struct {
+	// getHooks returns the current webhooks, so that configuration changes
+	// apply without restarting.
+	getHooks func() []*Config
+	client   *http.Client
+
+	// MaxAttempts is the number of times a payload is posted before giving up.
+	MaxAttempts int
+	// Backoff is the delay before the first retry, which is doubled on each
+	// following retry.
+	Backoff time.Duration
+
+	wg sync.WaitGroup
+}
+
+// NewDispatcher returns a dispatcher posting events to the webhooks returned
+// by getHooks.
+func NewDispatcher(getHooks func() []*Config) *Dispatcher {
+	return &Dispatcher{
+		getHooks:    getHooks,
+		client:      &http.Client{Timeout: requestTimeout},
+		MaxAttempts: defaultMaxAttempts,
+		Backoff:     defaultBackoff,
+	}
+}
+
+// Dispatch posts the event to the webhooks handling it. Returns without
+// waiting for the requests to complete.
+func (d *Dispatcher) Dispatch(e Event, content string, data interface{}) {
+	if d == nil {
+		return
+	}
+
+	var hooks []*Config
+	for _, h := range d.getHooks() {
+		if h == nil || !h.handles(e) {
+			continue
+		}
+
+		if err := h.Validate(); err != nil {
+			logger.Warnf("Ignoring webhook: %v", err)
+			continue
+		}
+
+		hooks = append(hooks, h)
+	}
+
+	if len(hooks) == 0 {
+		return
+	}
+
+	body, err := json.Marshal(Payload{
+		Event:   e,
+		Time:    time.Now(),
+		Content: content,
+		Data:    data,
+	})
+	if err != nil {
+		logger.Errorf("Error encoding %s webhook payload: %v", e, err)
+		return
+	}
+
+	for _, h := range hooks {
+		d.wg.Add(1)
+		go func(h *Config) {
+			defer d.wg.Done()
+
+			if err := d.send(h, e, body); err != nil {
+				logger.Warnf("Error calling webhook %s for %s: %v", h.URL, e, err)
+			}
+		}(h)
+	}
+}
+
+// Wait waits for the dispatched events to be posted.
+func (d *Dispatcher) Wait() {
+	d.wg.Wait()
+}
+
+func (d *Dispatcher) send(h *Config, e Event, body []byte) error {
+	backoff := d.Backoff
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = d.post(h, e, body)
+
+		var permanent errPermanent
+		if err == nil || errors.As(err, &permanent) || attempt >= d.MaxAttempts {
+			return err
+		}
+
+		logger.Debugf("Retrying webhook %s for %s in %s: %v", h.URL, e, backoff, err)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func (d *Dispatcher) post(h *Config, e Event, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, h.URL, bytes.NewReader(body))
+	if err != nil {
+		return errPermanent{err}
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, string(e))
+	if h.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(h.Secret, body))
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1024))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	err = fmt.Errorf("%s returned %s", h.URL, resp.Status)
+
+	// client errors other than rate limiting are not resolved by retrying
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+		return errPermanent{err}
+	}
+
+	return err
+}
@@ -0,0 +1,126 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type received struct {
+	event     string
+	signature string
+	payload   Payload
+}
+
+// testServer records the requests, responding with the statuses in order.
+type testServer struct {
+	*httptest.Server
+	mutex    sync.Mutex
+	statuses []int
+	requests []received
+}
+
+func newTestServer(t *testing.T, statuses ...int) *testServer {
+	s := &testServer{statuses: statuses}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		var p Payload
+		_ = json.Unmarshal(body, &p)
+
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+
+		s.requests = append(s.requests, received{
+			event:     r.Header.Get(EventHeader),
+			signature: r.Header.Get(SignatureHeader),
+			payload:   p,
+		})
+
+		// check the signature of the body
+		if sig := r.Header.Get(SignatureHeader); sig != "" && sig != Sign("secret", body) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		status := http.StatusOK
+		if len(s.statuses) > 0 {
+			status = s.statuses[0]
+			s.statuses = s.statuses[1:]
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func TestDispatcher(t *testing.T) {
+	testCases := []struct {
+		name     string
+		events   []Event
+		secret   string
+		statuses []int
+		// expected number of requests
+		requests int
+	}{
+		{"all events", nil, "", nil, 1},
+		{"filtered event", []Event{EventSceneCreated}, "", nil, 1},
+		{"other event", []Event{EventGenerateFinished}, "", nil, 0},
+		{"signed", nil, "secret", nil, 1},
+		{"retried", nil, "", []int{http.StatusInternalServerError, http.StatusTooManyRequests}, 3},
+		{"gives up", nil, "", []int{500, 500, 500, 500}, 3},
+		{"client error", nil, "", []int{http.StatusNotFound}, 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newTestServer(t, tc.statuses...)
+
+			d := NewDispatcher(func() []*Config {
+				return []*Config{{URL: s.URL, Events: tc.events, Secret: tc.secret}}
+			})
+			d.MaxAttempts = 3
+			d.Backoff = time.Millisecond
+
+			d.Dispatch(EventSceneCreated, "Scene created", map[string]int{"id": 1})
+			d.Wait()
+
+			assert.Len(t, s.requests, tc.requests)
+			if tc.requests == 0 {
+				return
+			}
+
+			r := s.requests[0]
+			assert.Equal(t, string(EventSceneCreated), r.event)
+			assert.Equal(t, EventSceneCreated, r.payload.Event)
+			assert.Equal(t, "Scene created", r.payload.Content)
+			assert.Equal(t, map[string]interface{}{"id": float64(1)}, r.payload.Data)
+			assert.Equal(t, tc.secret != "", r.signature != "")
+		})
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	testCases := []struct {
+		config  Config
+		wantErr bool
+	}{
+		{Config{URL: "https://ntfy.sh/stash"}, false},
+		{Config{URL: "http://localhost:8123/api/webhook/x", Events: []Event{EventScanFinished}}, false},
+		{Config{URL: ""}, true},
+		{Config{URL: "ftp://host/path"}, true},
+		{Config{URL: "https://host", Events: []Event{"scene.deleted"}}, true},
+	}
+
+	for _, tc := range testCases {
+		err := tc.config.Validate()
+		assert.Equal(t, tc.wantErr, err != nil, tc.config.URL)
+	}
+}
@@ -7,3 +7,9 @@ var UIBox embed.FS
 
 //go:embed login
 var LoginUIBox embed.FS
+
+//go:embed v2.5/src/locales/*.json
+var LocalesBox embed.FS
+
+// LocalesDir is the directory of the locale files in LocalesBox.
+const LocalesDir = "v2.5/src/locales"
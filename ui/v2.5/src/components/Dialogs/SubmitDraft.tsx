@@ -14,6 +14,10 @@ interface IProps {
     id: string;
     title?: string | null;
     stash_ids: { stash_id: string; endpoint: string }[];
+    stash_box_drafts?: Pick<
+      GQL.StashBoxDraft,
+      "id" | "endpoint" | "url" | "created_at"
+    >[];
   };
   boxes: Pick<GQL.StashBox, "name" | "endpoint">[];
   query: DocumentNode;
@@ -77,6 +81,10 @@ export const SubmitStashBoxDraft: React.FC<IProps> = ({
     entity.stash_ids.find((id) => id.endpoint === selectedBox.endpoint) !==
     undefined;
 
+  const previousDrafts = (entity.stash_box_drafts ?? []).filter(
+    (d) => d.endpoint === selectedBox.endpoint
+  );
+
   return (
     <Modal
       icon={faPaperPlane}
@@ -123,6 +131,22 @@ export const SubmitStashBoxDraft: React.FC<IProps> = ({
               />{" "}
             </Button>
           </div>
+          {previousDrafts.length > 0 && (
+            <div className="mt-2">
+              <h6>
+                <FormattedMessage id="stashbox.previous_drafts" />
+              </h6>
+              <ul>
+                {previousDrafts.map((d) => (
+                  <li key={d.id}>
+                    <a target="_blank" rel="noreferrer noopener" href={d.url}>
+                      {intl.formatDate(d.created_at)}
+                    </a>
+                  </li>
+                ))}
+              </ul>
+            </div>
+          )}
         </>
       ) : (
         <>
@@ -20,6 +20,7 @@ import Help from "src/docs/en/Manual/Help.md";
 import Deduplication from "src/docs/en/Manual/Deduplication.md";
 import Interactive from "src/docs/en/Manual/Interactive.md";
 import Captions from "src/docs/en/Manual/Captions.md";
+import Chapters from "src/docs/en/Manual/Chapters.md";
 import Identify from "src/docs/en/Manual/Identify.md";
 import Browsing from "src/docs/en/Manual/Browsing.md";
 import { MarkdownPage } from "../Shared/MarkdownPage";
@@ -140,6 +141,11 @@ export const Manual: React.FC<IManualProps> = ({
       title: "Captions",
       content: Captions,
     },
+    {
+      key: "Chapters.md",
+      title: "Chapters",
+      content: Chapters,
+    },
     {
       key: "KeyboardShortcuts.md",
       title: "Keyboard Shortcuts",
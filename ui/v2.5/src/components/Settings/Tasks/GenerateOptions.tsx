@@ -74,6 +74,13 @@ export const GenerateOptions: React.FC<IGenerateOptions> = ({
         tooltipID="dialogs.scene_gen.sprites_tooltip"
         onChange={(v) => setOptions({ sprites: v })}
       />
+      <BooleanSetting
+        id="hover-strip-task"
+        checked={options.hoverStrips ?? false}
+        headingID="dialogs.scene_gen.hover_strips"
+        tooltipID="dialogs.scene_gen.hover_strips_tooltip"
+        onChange={(v) => setOptions({ hoverStrips: v })}
+      />
       <BooleanSetting
         id="marker-task"
         checked={options.markers ?? false}
@@ -136,6 +143,20 @@ export const GenerateOptions: React.FC<IGenerateOptions> = ({
         headingID="dialogs.scene_gen.interactive_heatmap_speed"
         onChange={(v) => setOptions({ interactiveHeatmapsSpeeds: v })}
       />
+      <BooleanSetting
+        id="interactive-markers-task"
+        checked={options.interactiveMarkers ?? false}
+        headingID="dialogs.scene_gen.interactive_markers"
+        tooltipID="dialogs.scene_gen.interactive_markers_tooltip"
+        onChange={(v) => setOptions({ interactiveMarkers: v })}
+      />
+      <BooleanSetting
+        id="chapter-thumbnails-task"
+        checked={options.chapterThumbnails ?? false}
+        headingID="dialogs.scene_gen.chapter_thumbnails"
+        tooltipID="dialogs.scene_gen.chapter_thumbnails_tooltip"
+        onChange={(v) => setOptions({ chapterThumbnails: v })}
+      />
       <BooleanSetting
         id="overwrite"
         checked={options.overwrite ?? false}
@@ -217,6 +217,7 @@ export function makeCriteria(
         )
       );
     case "ignore_auto_tag":
+    case "include_marker_tags":
       return new BooleanCriterion(new BooleanCriterionOption(type, type));
     case "date":
     case "birthdate":
@@ -165,6 +165,7 @@ export type CriterionType =
   | "performer_age"
   | "duplicated"
   | "ignore_auto_tag"
+  | "include_marker_tags"
   | "file_count"
   | "stash_id_endpoint"
   | "date"